@@ -101,6 +101,18 @@ func registerAgentConfig() {
 	// Device flags.
 	registerString(flags, name("slot-type"), defaults.SlotType, "slot type to expose")
 	registerString(flags, name("visible-gpus"), defaults.VisibleGPUs, "GPUs to expose as slots")
+	registerInt(flags, name("reserved-slots"), defaults.ReservedSlots,
+		"Number of slots to withhold from tasks for system daemons")
+	registerInt(flags, name("reserved-cpu-cores"), defaults.ReservedCPUCores,
+		"Number of CPU cores to withhold from tasks for system daemons")
+
+	// Scratch flags.
+	registerString(flags, name("scratch-dir"), defaults.ScratchDir,
+		"Directory on local disk to make the scratch volumes of tasks in")
+	registerInt(flags, name("scratch-capacity-gib"), defaults.ScratchCapacityGiB,
+		"GiB of scratch volumes tasks may reserve, defaulting to the size of the scratch disk")
+	registerString(flags, name("image-disk-path"), defaults.ImageDiskPath,
+		"Path on the partition Docker stores images on, defaulting to the Docker root directory")
 
 	// Security flags.
 	registerBool(flags, name("security", "tls", "enabled"), defaults.Security.TLS.Enabled,
@@ -126,6 +138,8 @@ func registerAgentConfig() {
 
 	// Endpoint flags.
 	registerBool(flags, name("api-enabled"), defaults.APIEnabled, "Enable agent API endpoints")
+	registerBool(flags, name("outbound-only"), defaults.OutboundOnly,
+		"Accept no connections from the master, which proxies to tasks over the agent's connection")
 	registerString(flags, name("bind-ip"), defaults.BindIP,
 		"IP address to listen on for API requests")
 	registerInt(flags, name("bind-port"), defaults.BindPort, "Port to listen on for API requests")
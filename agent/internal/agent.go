@@ -8,10 +8,12 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"runtime"
 	"strings"
 	"time"
 
 	dclient "github.com/docker/docker/client"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -34,6 +36,8 @@ const (
 	wsSecureScheme   = "wss"
 	eventChanSize    = 64 // same size as the websocket outbox
 	logSourceAgent   = "agent"
+	// diskUsageReportPeriod is how often the agent reports the disk usage of its images.
+	diskUsageReportPeriod = time.Minute
 )
 
 // MasterWebsocket is the type for a websocket which communicates with the master.
@@ -48,6 +52,9 @@ type Agent struct {
 	opts    options.Options
 	log     *logrus.Entry
 	wg      errgroupx.Group
+	// session numbers the container state transitions sent to the master, so that those the
+	// master didn't receive are replayed if it resumes the session after a reconnect.
+	session *aproto.Session[*aproto.MasterMessage]
 }
 
 // NewAgent constructs and runs a new agent according to the provided configuration.
@@ -57,6 +64,7 @@ func NewAgent(parent context.Context, version string, opts options.Options) *Age
 		opts:    opts,
 		log:     logrus.WithField("component", "agent"),
 		wg:      errgroupx.WithContext(parent),
+		session: aproto.NewSession[*aproto.MasterMessage](uuid.NewString()),
 	}
 
 	a.wg.Go(func(ctx context.Context) error {
@@ -110,6 +118,7 @@ func (a *Agent) run(ctx context.Context) error {
 	case <-ctx.Done():
 		return fmt.Errorf("canceled while reading setup messages: %w", ctx.Err())
 	}
+	a.logVersionCompatibility(mopts)
 
 	a.log.Trace("detecting devices")
 	devices, err := detect.Detect(
@@ -118,6 +127,16 @@ func (a *Agent) run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to detect devices: %v", devices)
 	}
+	devices = detect.Reserve(devices, a.opts.ReservedSlots)
+	if numCPU := runtime.NumCPU(); a.opts.ReservedCPUCores >= numCPU {
+		return fmt.Errorf(
+			"cannot reserve %d CPU cores on an agent with %d", a.opts.ReservedCPUCores, numCPU)
+	}
+	if a.opts.ScratchDir != "" {
+		if err := os.MkdirAll(a.opts.ScratchDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create scratch directory: %w", err)
+		}
+	}
 
 	a.log.Tracef("setting up %s runtime", a.opts.ContainerRuntime)
 	if a.opts.ContainerRuntime != options.DockerContainerRuntime {
@@ -149,6 +168,11 @@ func (a *Agent) run(ctx context.Context) error {
 		manager.Detach()
 	}()
 
+	execs := newExecs(cruntime, outbox)
+	defer execs.detachAll()
+	tunnels := newTunnels(a.opts.OutboundOnly, outbox)
+	defer tunnels.closeAll()
+
 	a.log.Trace("reattaching containers")
 	reattached, err := manager.ReattachContainers(ctx, mopts.ContainersToReattach)
 	if err != nil {
@@ -162,10 +186,19 @@ func (a *Agent) run(ctx context.Context) error {
 		Devices:              devices,
 		ContainersReattached: reattached,
 		ResourcePoolName:     a.opts.ResourcePool,
+		Labels:               a.opts.Labels,
+		NetworkInterfaces:    a.networkInterfaces(),
+		ScratchCapacityGiB:   a.scratchCapacity(),
+		Time:                 time.Now(),
+		SupportsExec:         true,
+		OutboundOnly:         a.opts.OutboundOnly,
 	}}:
 	case <-ctx.Done():
 		return ctx.Err()
 	}
+	go a.reportDiskUsage(ctx, cruntime, outbox)
+	diskUsageTicker := time.NewTicker(diskUsageReportPeriod)
+	defer diskUsageTicker.Stop()
 
 	a.log.Trace("watching for ws requests and system events")
 	inbox := socket.Inbox
@@ -177,6 +210,17 @@ func (a *Agent) run(ctx context.Context) error {
 				inbox = nil
 				continue
 			}
+			if !a.session.Receive(msg.Seq, msg.Ack) {
+				a.log.Debugf("dropping replayed message %d", msg.Seq)
+				continue
+			}
+			if msg.Seq != 0 {
+				select {
+				case socket.Outbox <- &aproto.MasterMessage{Ack: a.session.Received()}:
+				case <-ctx.Done():
+					return nil
+				}
+			}
 
 			switch {
 			case msg.StartContainer != nil:
@@ -187,23 +231,40 @@ func (a *Agent) run(ctx context.Context) error {
 				manager.SignalContainer(ctx, *msg.SignalContainer)
 			case msg.AgentShutdown != nil:
 				return errors.New(msg.AgentShutdown.ErrMsg)
+			case msg.RemoveImages != nil:
+				go a.removeImages(ctx, cruntime, msg.RemoveImages.IDs, outbox)
+			case msg.ExecStart != nil:
+				go execs.start(ctx, *msg.ExecStart)
+			case msg.ExecInput != nil:
+				execs.input(*msg.ExecInput)
+			case msg.TunnelOpen != nil:
+				tunnels.open(ctx, *msg.TunnelOpen)
+			case msg.TunnelData != nil:
+				tunnels.data(*msg.TunnelData)
+			case msg.Ack != 0:
+				// The message only acknowledges messages, which the session recorded above.
 			default:
 				panic(fmt.Sprintf("unknown message received: %+v", msg))
 			}
 
 		case msg := <-outbox:
 			select {
-			case socket.Outbox <- msg:
+			case socket.Outbox <- a.session.Send(msg):
 			case <-ctx.Done():
 				return nil
 			}
 
+		case <-diskUsageTicker.C:
+			go a.reportDiskUsage(ctx, cruntime, outbox)
+
 		case <-socket.Done:
 			if err := socket.Error(); err != nil {
 				a.log.WithError(err).Error("socket disconnected")
 			} else {
 				a.log.Trace("socket disconnected")
 			}
+			execs.detachAll()
+			tunnels.closeAll()
 
 			newSocket, newMopts, err := a.reconnectFlow(ctx, manager, devices, outbox)
 			if err != nil {
@@ -242,9 +303,10 @@ func (a *Agent) connect(ctx context.Context, reconnect bool) (*MasterWebsocket,
 	}
 
 	masterAddr := fmt.Sprintf(
-		"%s://%s:%d/agents?id=%s&version=%s&resource_pool=%s&reconnect=%v&hostname=%s",
+		"%s://%s:%d/agents?id=%s&version=%s&resource_pool=%s&reconnect=%v&hostname=%s"+
+			"&session=%s&received=%d",
 		masterProto, a.opts.MasterHost, a.opts.MasterPort, a.opts.AgentID, url.QueryEscape(a.version),
-		a.opts.ResourcePool, reconnect, hostname,
+		a.opts.ResourcePool, reconnect, hostname, a.session.ID(), a.session.Received(),
 	)
 	a.log.Infof("connecting to master at: %s", masterAddr)
 	conn, resp, err := dialer.DialContext(ctx, masterAddr, nil)
@@ -270,6 +332,65 @@ func (a *Agent) connect(ctx context.Context, reconnect bool) (*MasterWebsocket,
 	return ws.Wrap[*aproto.AgentMessage, *aproto.MasterMessage]("agent-"+a.opts.AgentID, conn)
 }
 
+// networkInterfaces returns the network interfaces the master can pick from for distributed
+// training. Failing to detect them only leaves the master to fall back to the configured one.
+func (a *Agent) networkInterfaces() []aproto.NetworkInterface {
+	ifaces, err := detect.NetworkInterfaces(a.opts.MasterHost, a.opts.MasterPort)
+	if err != nil {
+		a.log.WithError(err).Warn("failed to detect network interfaces")
+		return nil
+	}
+	return ifaces
+}
+
+// scratchCapacity returns the GiB of scratch volumes tasks on the agent may reserve in total, or
+// 0 if it has no scratch directory.
+func (a *Agent) scratchCapacity() int {
+	switch {
+	case a.opts.ScratchDir == "":
+		return 0
+	case a.opts.ScratchCapacityGiB > 0:
+		return a.opts.ScratchCapacityGiB
+	}
+	capacity, err := detect.ScratchCapacityGiB(a.opts.ScratchDir)
+	if err != nil {
+		a.log.WithError(err).Warn("failed to detect scratch capacity")
+		return 0
+	}
+	return capacity
+}
+
+// reportDiskUsage sends the master the utilization of the partition the agent's images are on,
+// which it uses to stop scheduling onto the agent and to pick images to remove from it.
+func (a *Agent) reportDiskUsage(
+	ctx context.Context, cruntime *docker.Client, outbox chan *aproto.MasterMessage,
+) {
+	usage, err := cruntime.DiskUsage(ctx, a.opts.ImageDiskPath)
+	if err != nil {
+		a.log.WithError(err).Warn("failed to get disk usage")
+		return
+	}
+	select {
+	case outbox <- &aproto.MasterMessage{AgentDiskUsage: usage}:
+	case <-ctx.Done():
+	}
+}
+
+// removeImages removes the images the master picked to free up disk space, then reports the disk
+// usage of the agent again. Images that containers were created from since are kept.
+func (a *Agent) removeImages(
+	ctx context.Context, cruntime *docker.Client, ids []string, outbox chan *aproto.MasterMessage,
+) {
+	for _, id := range ids {
+		if err := cruntime.RemoveImage(ctx, id); err != nil {
+			a.log.WithError(err).Warnf("failed to remove image %s", id)
+			continue
+		}
+		a.log.Infof("removed image %s to free up disk space", id)
+	}
+	a.reportDiskUsage(ctx, cruntime, outbox)
+}
+
 func (a *Agent) sender(out chan *aproto.MasterMessage) events.Publisher[container.Event] {
 	return events.FuncPublisher[container.Event](
 		func(ctx context.Context, in container.Event) error {
@@ -335,6 +456,22 @@ func (a *Agent) reconnectFlow(
 	case <-ctx.Done():
 		return nil, nil, fmt.Errorf("canceled while reading setup messages: %w", ctx.Err())
 	}
+	a.logVersionCompatibility(*mopts)
+
+	// If the master resumed the session, it replays what this agent didn't receive, and the
+	// transitions it didn't receive are replayed to it, so none are dropped below. Otherwise, the
+	// master only knows the containers' states as they are reattached.
+	resumed := mopts.Session != nil && mopts.Session.Resumed
+	var replay []*aproto.MasterMessage
+	if resumed {
+		var ok bool
+		if replay, ok = a.session.Resume(mopts.Session.Received); !ok {
+			return nil, nil, fmt.Errorf("master resumed a session this agent fell too far behind on")
+		}
+		a.log.Infof("resumed session with master, replaying %d messages", len(replay))
+	} else {
+		a.session.Reset(a.session.ID())
+	}
 
 	a.log.Tracef("reattaching containers after reconnect: %+v", mopts.ContainersToReattach)
 	reattached, err := manager.RevalidateContainers(ctx, mopts.ContainersToReattach)
@@ -354,10 +491,23 @@ func (a *Agent) reconnectFlow(
 		Devices:              devices,
 		ContainersReattached: reattached,
 		ResourcePoolName:     a.opts.ResourcePool,
+		Labels:               a.opts.Labels,
+		NetworkInterfaces:    a.networkInterfaces(),
+		ScratchCapacityGiB:   a.scratchCapacity(),
+		Time:                 time.Now(),
+		SupportsExec:         true,
+		OutboundOnly:         a.opts.OutboundOnly,
 	}}:
 	case <-ctx.Done():
 		return nil, nil, ctx.Err()
 	}
+	for _, msg := range replay {
+		select {
+		case socket.Outbox <- msg:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
 
 	a.log.Trace("sending sentinel message into output stream")
 	a.wg.Go(func(ctx context.Context) error {
@@ -378,7 +528,7 @@ func (a *Agent) reconnectFlow(
 				return socket, mopts, nil
 			}
 
-			if csc := msg.ContainerStateChanged; csc != nil {
+			if csc := msg.ContainerStateChanged; csc != nil && !resumed {
 				reattachState, ok := reattachedStates[msg.ContainerStateChanged.Container.ID]
 				if ok && csc.Container.State.Before(reattachState) {
 					a.log.Tracef(
@@ -390,7 +540,7 @@ func (a *Agent) reconnectFlow(
 			}
 
 			select {
-			case socket.Outbox <- msg:
+			case socket.Outbox <- a.session.Send(msg):
 			case <-ctx.Done():
 				return nil, nil, ctx.Err()
 			}
@@ -461,3 +611,14 @@ func (a *Agent) tlsConfig() (*tls.Config, error) {
 		Certificates:       certs,
 	}, nil
 }
+
+// logVersionCompatibility warns when the master found the version of the agent incompatible.
+func (a *Agent) logVersionCompatibility(mopts aproto.MasterSetAgentOptions) {
+	compat := mopts.VersionCompatibility
+	if compat == nil || compat.Compatible {
+		return
+	}
+	a.log.Warnf("agent version %s is not compatible with master version %s (%s), "+
+		"the master applies its %q policy to this agent", a.version, mopts.MasterInfo.Version,
+		compat.Reason, compat.Policy)
+}
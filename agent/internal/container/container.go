@@ -297,7 +297,11 @@ func (c *Container) wait(ctx context.Context, dc *docker.Container) error {
 			if exit.Error != nil {
 				return fmt.Errorf("receiving container exit: %s", exit.Error.Message)
 			}
-			return aproto.NewContainerExit(aproto.ExitCode(exit.StatusCode))
+			failure := aproto.NewContainerExit(aproto.ExitCode(exit.StatusCode))
+			if failure != nil {
+				failure.Diagnostics = diagnose(dc.ContainerInfo.ID)
+			}
+			return failure
 
 		case err := <-dc.ContainerWaiter.Errs:
 			c.log.Trace("container waiter failed")
@@ -339,7 +343,9 @@ func (c *Container) finalize(ctx context.Context, err error) {
 	case *aproto.ContainerFailureError:
 		stop = aproto.ContainerStopped{Failure: err}
 	default:
+		// The container runtime failed, rather than the container's process.
 		stop = aproto.ContainerError(aproto.TaskError, err)
+		stop.Failure.Reason = aproto.InfraFailureReason
 	}
 
 	if err := c.terminated(ctx, stop); err != nil {
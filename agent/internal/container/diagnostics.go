@@ -0,0 +1,80 @@
+package container
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+)
+
+const (
+	kernelLogPath = "/dev/kmsg"
+	// maxKernelLogRecords is how many out of memory records are kept from the kernel log.
+	maxKernelLogRecords = 20
+)
+
+// diagnose collects what the agent can tell about why a container failed, beyond its exit code.
+// It is best effort: reading the kernel log needs privileges the agent may not have.
+func diagnose(runtimeID string) *aproto.ContainerDiagnostics {
+	records, err := readKernelLog()
+	if err != nil {
+		return nil
+	}
+	return diagnoseFromKernelLog(records, runtimeID)
+}
+
+// diagnoseFromKernelLog picks the out of memory records out of the messages in the kernel log. A
+// container was killed for running out of memory if an oom-kill record names its cgroup, which
+// contains its ID with both the cgroupfs and systemd cgroup drivers.
+func diagnoseFromKernelLog(records []string, runtimeID string) *aproto.ContainerDiagnostics {
+	diag := &aproto.ContainerDiagnostics{}
+	for _, msg := range records {
+		lower := strings.ToLower(msg)
+		if !strings.Contains(lower, "out of memory") && !strings.Contains(lower, "oom-kill") {
+			continue
+		}
+		if runtimeID != "" && strings.HasPrefix(lower, "oom-kill:") &&
+			strings.Contains(msg, runtimeID) {
+			diag.OOMKilled = true
+		}
+		diag.KernelLog = append(diag.KernelLog, msg)
+	}
+	if len(diag.KernelLog) > maxKernelLogRecords {
+		diag.KernelLog = diag.KernelLog[len(diag.KernelLog)-maxKernelLogRecords:]
+	}
+	return diag
+}
+
+// readKernelLog returns the messages in the kernel ring buffer, oldest first, like dmesg.
+func readKernelLog() ([]string, error) {
+	fd, err := unix.Open(kernelLogPath, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(fd)
+
+	// Each read returns a single record, like "6,1234,5678901,-;message", followed by indented
+	// key/value continuation lines.
+	var msgs []string
+	buf := make([]byte, 8192)
+	for {
+		n, err := unix.Read(fd, buf)
+		switch {
+		case errors.Is(err, unix.EAGAIN):
+			return msgs, nil
+		case errors.Is(err, unix.EPIPE):
+			// Records were overwritten while being read; carry on from the oldest remaining.
+			continue
+		case err != nil:
+			return nil, err
+		case n == 0:
+			return msgs, nil
+		}
+		record, _, _ := strings.Cut(string(buf[:n]), "\n")
+		if _, msg, ok := strings.Cut(record, ";"); ok {
+			msgs = append(msgs, msg)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package container
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseFromKernelLog(t *testing.T) {
+	const id = "4f2a9c1e8b7d"
+	records := []string{
+		"eth0: link up",
+		"python3 invoked oom-killer: gfp_mask=0xcc0(GFP_KERNEL), order=0, oom_score_adj=0",
+		"oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=" + id +
+			",oom_memcg=/system.slice/docker-" + id + ".scope,task=python3,pid=4242,uid=0",
+		"Memory cgroup out of memory: Killed process 4242 (python3) total-vm:1024kB",
+	}
+
+	diag := diagnoseFromKernelLog(records, id)
+	require.True(t, diag.OOMKilled)
+	require.Equal(t, records[1:], diag.KernelLog)
+
+	// Out of memory kills of other containers are kept, but don't count against this one.
+	diag = diagnoseFromKernelLog(records, "0123456789ab")
+	require.False(t, diag.OOMKilled)
+	require.Len(t, diag.KernelLog, 3)
+
+	require.Empty(t, diagnoseFromKernelLog(records[:1], id).KernelLog)
+
+	var many []string
+	for i := 0; i < 2*maxKernelLogRecords; i++ {
+		many = append(many, fmt.Sprintf("Out of memory: Killed process %d", i))
+	}
+	diag = diagnoseFromKernelLog(many, id)
+	require.Equal(t, many[maxKernelLogRecords:], diag.KernelLog)
+}
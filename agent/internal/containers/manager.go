@@ -4,10 +4,13 @@ import (
 	"container/ring"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"syscall"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
@@ -134,6 +137,7 @@ func (m *Manager) ReattachContainers(
 			m.log.WithError(err).Warnf("failed to kill container %s", cid)
 		}
 	}
+	m.removeOrphanedScratch()
 
 	return result, nil
 }
@@ -198,6 +202,9 @@ func (m *Manager) StartContainer(ctx context.Context, req aproto.StartContainer)
 	if !validateDevices(m.devices, req.Container.Devices) {
 		return fmt.Errorf("devices specified in container spec not found on agent")
 	}
+	if err := checkBindMounts(m.opts.BindMountConstraints, req.Spec.RunSpec.HostConfig.Mounts); err != nil {
+		return fmt.Errorf("bind mounts specified in container spec not allowed on agent: %w", err)
+	}
 
 	spec, err := overwriteSpec(req.Spec, req.Container, m.opts, m.mopts)
 	if err != nil {
@@ -210,6 +217,14 @@ func (m *Manager) StartContainer(ctx context.Context, req aproto.StartContainer)
 		m.mu.Unlock()
 		return fmt.Errorf("container already created: %s", req.Container.ID)
 	}
+	if scratch := req.Spec.RunSpec.Scratch; scratch != nil {
+		scratchMount, err := m.makeScratch(req.Container.ID, scratch)
+		if err != nil {
+			m.mu.Unlock()
+			return err
+		}
+		req.Spec.RunSpec.HostConfig.Mounts = append(req.Spec.RunSpec.HostConfig.Mounts, scratchMount)
+	}
 	c := container.Start(req, m.cruntime, m.pub)
 	m.containers[req.Container.ID] = c
 	m.mu.Unlock()
@@ -223,10 +238,62 @@ func (m *Manager) StartContainer(ctx context.Context, req aproto.StartContainer)
 		}
 		delete(m.containers, req.Container.ID)
 		m.mu.Unlock()
+		m.removeScratch(req.Container.ID)
 	})
 	return nil
 }
 
+// makeScratch makes the scratch directory of a container on the agent's scratch disk, which any
+// user the container runs as may write to, and returns the mount for it.
+func (m *Manager) makeScratch(cID cproto.ID, scratch *cproto.ScratchSpec) (mount.Mount, error) {
+	if m.opts.ScratchDir == "" {
+		return mount.Mount{}, fmt.Errorf(
+			"container requested a %d GiB scratch volume but the agent has no scratch_dir",
+			scratch.SizeGiB)
+	}
+	dir := filepath.Join(m.opts.ScratchDir, string(cID))
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return mount.Mount{}, fmt.Errorf("failed to make scratch directory: %w", err)
+	}
+	// MkdirAll is subject to the umask.
+	if err := os.Chmod(dir, 0o777); err != nil { // #nosec G302
+		return mount.Mount{}, fmt.Errorf("failed to make scratch directory writable: %w", err)
+	}
+	return mount.Mount{Type: mount.TypeBind, Source: dir, Target: scratch.ContainerPath}, nil
+}
+
+// removeScratch removes the scratch directory of a container that has exited, if it had one.
+func (m *Manager) removeScratch(cID cproto.ID) {
+	if m.opts.ScratchDir == "" {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(m.opts.ScratchDir, string(cID))); err != nil {
+		m.log.WithError(err).Warnf("failed to remove scratch directory of container %s", cID)
+	}
+}
+
+// removeOrphanedScratch removes the scratch directories of containers that exited while the agent
+// was down, which are all but those of the containers it is managing.
+func (m *Manager) removeOrphanedScratch() {
+	if m.opts.ScratchDir == "" {
+		return
+	}
+	entries, err := os.ReadDir(m.opts.ScratchDir)
+	if err != nil {
+		m.log.WithError(err).Warn("failed to list scratch directories")
+		return
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, e := range entries {
+		if _, ok := m.containers[cproto.ID(e.Name())]; ok || !e.IsDir() {
+			continue
+		}
+		m.log.Infof("removing scratch directory of exited container %s", e.Name())
+		m.removeScratch(cproto.ID(e.Name()))
+	}
+}
+
 // SignalContainer signals a container.
 func (m *Manager) SignalContainer(ctx context.Context, msg aproto.SignalContainer) {
 	m.mu.RLock()
@@ -316,6 +383,7 @@ func (m *Manager) reattachContainer(
 		}
 		delete(m.containers, cID)
 		m.mu.Unlock()
+		m.removeScratch(cID)
 	})
 	m.log.Debugf("reattached container actor %s", cID)
 	return containerCurrState, nil
@@ -1,11 +1,17 @@
 package containers
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/docker/docker/api/types/mount"
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestAddProxyInfo(t *testing.T) {
@@ -78,3 +84,58 @@ func TestAddProxyInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckBindMounts(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	denied := filepath.Join(dir, "denied")
+	require.NoError(t, os.Mkdir(denied, 0o700))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(denied, link))
+	constraints := model.BindMountConstraints{DeniedHostPaths: []string{denied}}
+
+	bind := func(source string) []mount.Mount {
+		return []mount.Mount{{Type: mount.TypeBind, Source: source, Target: "/mnt"}}
+	}
+	require.NoError(t, checkBindMounts(constraints, bind(filepath.Join(dir, "other"))))
+	require.Error(t, checkBindMounts(constraints, bind(denied)))
+	require.Error(t, checkBindMounts(constraints, bind(link)), "symlinks are resolved")
+	require.NoError(t, checkBindMounts(constraints, []mount.Mount{
+		{Type: mount.TypeVolume, Source: denied, Target: "/mnt"},
+	}), "only bind mounts are checked")
+}
+
+func TestTaskCPUSet(t *testing.T) {
+	require.Equal(t, "2-15", taskCPUSet(2, 16))
+	require.Equal(t, "1-1", taskCPUSet(1, 2))
+}
+
+func TestScratch(t *testing.T) {
+	scratchDir := t.TempDir()
+	m, err := New(options.Options{ScratchDir: scratchDir}, aproto.MasterSetAgentOptions{},
+		nil, nil, nil)
+	require.NoError(t, err)
+
+	cID := cproto.NewID()
+	scratchMount, err := m.makeScratch(cID, &cproto.ScratchSpec{
+		ContainerPath: "/scratch", SizeGiB: 10,
+	})
+	require.NoError(t, err)
+	dir := filepath.Join(scratchDir, string(cID))
+	require.Equal(t, mount.Mount{Type: mount.TypeBind, Source: dir, Target: "/scratch"},
+		scratchMount)
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o777), info.Mode().Perm())
+
+	// The directory of a container the agent isn't managing is left over from before it restarted.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data"), []byte("data"), 0o600))
+	m.removeOrphanedScratch()
+	_, err = os.Stat(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	noScratch, err := New(options.Options{}, aproto.MasterSetAgentOptions{}, nil, nil, nil)
+	require.NoError(t, err)
+	_, err = noScratch.makeScratch(cID, &cproto.ScratchSpec{ContainerPath: "/scratch", SizeGiB: 1})
+	require.ErrorContains(t, err, "no scratch_dir")
+}
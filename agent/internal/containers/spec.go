@@ -3,11 +3,13 @@ package containers
 import (
 	"fmt"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
 
 	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 
 	"github.com/docker/docker/api/types"
 	"github.com/pkg/errors"
@@ -19,6 +21,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func overwriteSpec(
@@ -54,11 +57,21 @@ func overwriteSpec(
 		}
 	}
 
+	if opts.ReservedCPUCores > 0 {
+		spec.RunSpec.HostConfig.CpusetCpus = taskCPUSet(opts.ReservedCPUCores, runtime.NumCPU())
+	}
+
 	spec.RunSpec.HostConfig.LogConfig = dcontainer.LogConfig{}
 
 	return spec, nil
 }
 
+// taskCPUSet returns the cpuset that pins task containers to the cores that aren't reserved for the
+// system daemons on the agent, which are the first cores.
+func taskCPUSet(reservedCores, numCPU int) string {
+	return fmt.Sprintf("%d-%d", reservedCores, numCPU-1)
+}
+
 func addProxyInfo(env []string, opts options.Options) []string {
 	addVars := map[string]string{
 		"HTTP_PROXY":  opts.HTTPProxy,
@@ -249,3 +262,25 @@ func validateDevices(agentDevices, containerDevices []device.Device) bool {
 	}
 	return true
 }
+
+// checkBindMounts checks the bind mounts in a container spec satisfy the agent's bind mount
+// constraints. Host paths are also checked with symlinks resolved where the agent can see them,
+// since the master can't, so a symlink can't be used to reach a denied path.
+func checkBindMounts(constraints model.BindMountConstraints, mounts []mount.Mount) error {
+	for _, m := range mounts {
+		if m.Type != mount.TypeBind {
+			continue
+		}
+		if err := constraints.CheckHostPath(m.Source, m.ReadOnly); err != nil {
+			return err
+		}
+		resolved, err := filepath.EvalSymlinks(m.Source)
+		if err != nil || resolved == filepath.Clean(m.Source) {
+			continue
+		}
+		if err := constraints.CheckHostPath(resolved, m.ReadOnly); err != nil {
+			return fmt.Errorf("%s resolves to %s: %w", m.Source, resolved, err)
+		}
+	}
+	return nil
+}
@@ -110,6 +110,16 @@ func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]devic
 	return detected, nil
 }
 
+// Reserve withholds the last n devices from scheduling, leaving them to the system daemons and log
+// shipper on the agent.
+func Reserve(devices []device.Device, n int) []device.Device {
+	n = min(n, len(devices))
+	for _, d := range devices[len(devices)-n:] {
+		log.Infof("reserving device for system tasks: %s", d.String())
+	}
+	return devices[:len(devices)-n]
+}
+
 // randFromString returns a random-number generated seeded from an input string.
 func randFromString(seed string) (*rand.Rand, error) {
 	h := sha256.New()
@@ -0,0 +1,26 @@
+package detect
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestReserve(t *testing.T) {
+	devices := []device.Device{{ID: 0}, {ID: 1}, {ID: 2}}
+
+	assert.DeepEqual(t, Reserve(devices, 0), devices)
+	assert.DeepEqual(t, Reserve(devices, 1), devices[:2])
+	assert.Equal(t, len(Reserve(devices, 5)), 0)
+}
+
+func TestIsVirtualInterface(t *testing.T) {
+	for _, name := range []string{"docker0", "veth1a2b3c", "br-0123456789ab", "cni0", "flannel.1"} {
+		assert.Assert(t, isVirtualInterface(name), name)
+	}
+	for _, name := range []string{"eth0", "ens5", "ib0", "bond0", "hsn0"} {
+		assert.Assert(t, !isVirtualInterface(name), name)
+	}
+}
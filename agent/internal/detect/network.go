@@ -0,0 +1,79 @@
+package detect
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+)
+
+// virtualInterfacePrefixes are the name prefixes of the interfaces container runtimes and overlay
+// networks create, which other hosts can't reach.
+var virtualInterfacePrefixes = []string{
+	"docker", "veth", "br-", "virbr", "cni", "flannel", "cali", "tunl", "vxlan", "weave", "kube-",
+	"lxc", "lxd",
+}
+
+// isVirtualInterface returns whether an interface is one other hosts can't reach by name.
+func isVirtualInterface(name string) bool {
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkInterfaces returns the network interfaces of the host that containers on different
+// agents could communicate over, marking the one the master is reached through.
+func NetworkInterfaces(masterHost string, masterPort int) ([]aproto.NetworkInterface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("listing network interfaces: %w", err)
+	}
+	masterRoute := routeTo(masterHost, masterPort)
+
+	var detected []aproto.NetworkInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 ||
+			isVirtualInterface(iface.Name) {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("listing addresses of network interface %s: %w", iface.Name, err)
+		}
+		ni := aproto.NetworkInterface{Name: iface.Name}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			ni.Addresses = append(ni.Addresses, ipNet.IP.String())
+			if masterRoute != nil && ipNet.IP.Equal(masterRoute) {
+				ni.RoutesToMaster = true
+			}
+		}
+		if len(ni.Addresses) > 0 {
+			detected = append(detected, ni)
+		}
+	}
+	return detected, nil
+}
+
+// routeTo returns the local address the host reaches an address from, or nil if it can't tell.
+func routeTo(host string, port int) net.IP {
+	// Connecting a UDP socket only looks up the route, without sending anything.
+	conn, err := net.Dial("udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+	addr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return nil
+	}
+	return addr.IP
+}
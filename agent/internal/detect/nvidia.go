@@ -23,7 +23,7 @@ var (
 	detectMIGRegExp    = regexp.MustCompile(`(?P<dev>MIG \S+).+\(UUID.+(?P<uuid>MIG.+)\)`)
 	detectCudaDevices  = []string{"nvidia-smi", "-L"} // Lists both GPUs and MIG instances
 	detectCudaGPUsArgs = []string{
-		"nvidia-smi", "--query-gpu=index,name,uuid", "--format=csv,noheader",
+		"nvidia-smi", "--query-gpu=index,name,uuid,memory.total", "--format=csv,noheader,nounits",
 	}
 	detectCudaGPUsIDFlagTpl = "--id=%v"
 )
@@ -78,8 +78,12 @@ func detectCudaGPUs(visibleGPUs string) ([]device.Device, error) {
 			"error while executing nvidia-smi to detect GPUs")
 		return nil, nil
 	}
+	return parseNvidiaSmiGPUs(out)
+}
 
-	devices = make([]device.Device, 0)
+// parseNvidiaSmiGPUs parses the GPUs listed by nvidia-smi with detectCudaGPUsArgs.
+func parseNvidiaSmiGPUs(out []byte) ([]device.Device, error) {
+	devices := make([]device.Device, 0)
 
 	r := csv.NewReader(strings.NewReader(string(out)))
 	for {
@@ -89,9 +93,9 @@ func detectCudaGPUs(visibleGPUs string) ([]device.Device, error) {
 			return devices, nil
 		case err != nil:
 			return nil, errors.Wrap(err, "error parsing output of nvidia-smi as CSV")
-		case len(record) != 3:
+		case len(record) != 4:
 			return nil, errors.New(
-				"error parsing output of nvidia-smi; GPU record should have exactly 3 fields")
+				"error parsing output of nvidia-smi; GPU record should have exactly 4 fields")
 		}
 
 		index, err := strconv.Atoi(strings.TrimSpace(record[0]))
@@ -103,11 +107,15 @@ func detectCudaGPUs(visibleGPUs string) ([]device.Device, error) {
 		brand := strings.TrimSpace(record[1])
 		uuid := strings.TrimSpace(record[2])
 
+		// Some devices report their memory as "[N/A]", which leaves it unknown.
+		memory, _ := strconv.Atoi(strings.TrimSpace(record[3]))
+
 		devices = append(devices, device.Device{
-			ID:    device.ID(index),
-			Brand: brand,
-			UUID:  uuid,
-			Type:  device.CUDA,
+			ID:        device.ID(index),
+			Brand:     brand,
+			UUID:      uuid,
+			Type:      device.CUDA,
+			MemoryMiB: memory,
 		})
 	}
 }
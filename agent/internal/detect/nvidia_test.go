@@ -0,0 +1,28 @@
+package detect
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const testNvidiaSmiData = `0, NVIDIA A100-SXM4-40GB, GPU-5f2a6e61-3bd4-1c58-a1a4-6c0d5ff8e2a1, 40960
+1, NVIDIA A100-SXM4-80GB, GPU-9b1ec1b1-7f1c-4a83-8f37-0c24a0c1e0d2, 81920
+2, NVIDIA Graphics Device, GPU-0e3b7a52-61d5-4c1e-9f4b-55d2e1c1a6f7, [N/A]
+`
+
+func TestNvidiaSmiParser(t *testing.T) {
+	result, err := parseNvidiaSmiGPUs([]byte(testNvidiaSmiData))
+	assert.NilError(t, err)
+	assert.Equal(t, len(result), 3)
+	assert.DeepEqual(t, result[1], device.Device{
+		ID:        1,
+		Brand:     "NVIDIA A100-SXM4-80GB",
+		UUID:      "GPU-9b1ec1b1-7f1c-4a83-8f37-0c24a0c1e0d2",
+		Type:      device.CUDA,
+		MemoryMiB: 81920,
+	})
+	assert.Equal(t, result[2].MemoryMiB, 0)
+}
@@ -61,9 +61,19 @@ type RocmDevice struct {
 	CardVendor string `json:"Card vendor"`
 	CardModel  string `json:"Card model"`
 	PCIBus     string `json:"PCI Bus"`
+	VRAMTotal  string `json:"VRAM Total Memory (B)"`
 	Index      int
 }
 
+// MemoryMiB returns the total VRAM of the device, or 0 if it is unknown.
+func (d RocmDevice) MemoryMiB() int {
+	bytes, err := strconv.ParseInt(strings.TrimSpace(d.VRAMTotal), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return int(bytes / (1024 * 1024))
+}
+
 // Cache discovered devices for runtime lookups.
 var discoveredRocmDevices []RocmDevice
 
@@ -92,7 +102,7 @@ func parseRocmSmi(jsonData []byte) ([]RocmDevice, error) {
 }
 
 func detectRocmGPUs(visibleGPUs string) ([]device.Device, error) {
-	args := []string{"--showuniqueid", "--showproductname", "--showbus", "--json"}
+	args := []string{"--showuniqueid", "--showproductname", "--showbus", "--showmeminfo", "vram", "--json"}
 
 	if visibleGPUs != "" {
 		gpuIds := strings.Split(visibleGPUs, ",")
@@ -139,10 +149,11 @@ func detectRocmGPUs(visibleGPUs string) ([]device.Device, error) {
 
 	for _, rocmDevice := range discoveredRocmDevices {
 		result = append(result, device.Device{
-			ID:    device.ID(rocmDevice.Index),
-			Brand: rocmDevice.CardVendor,
-			UUID:  rocmDevice.UUID,
-			Type:  device.ROCM,
+			ID:        device.ID(rocmDevice.Index),
+			Brand:     rocmDevice.CardVendor,
+			UUID:      rocmDevice.UUID,
+			Type:      device.ROCM,
+			MemoryMiB: rocmDevice.MemoryMiB(),
 		})
 	}
 
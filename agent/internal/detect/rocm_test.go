@@ -34,6 +34,7 @@ const testRocmSmiData = `{
 "card1" : {
    "GPU ID" : "0x738c",
    "Unique ID" : "0x6be2ee3b2b314cfc",
+   "VRAM Total Memory (B)" : "34342961152",
    "Card SKU" : "D34304",
    "PCI Bus" : "0000:43:00.0",
    "Card vendor" : "0x1002",
@@ -48,4 +49,6 @@ func TestRocmSmiParser(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, len(result), 4)
 	assert.Equal(t, result[1].UUID, "0x6be2ee3b2b314cfc")
+	assert.Equal(t, result[1].MemoryMiB(), 32752)
+	assert.Equal(t, result[0].MemoryMiB(), 0)
 }
@@ -0,0 +1,17 @@
+package detect
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// ScratchCapacityGiB returns the size in GiB of the filesystem dir is on, which is how much the
+// agent offers for scratch volumes unless it is configured otherwise.
+func ScratchCapacityGiB(dir string) (int, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, fmt.Errorf("getting the size of scratch directory %s: %w", dir, err)
+	}
+	return int(stat.Blocks * uint64(stat.Bsize) >> 30), nil
+}
@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+)
+
+// execInputBufferSize is the number of inputs to an exec process buffered while it is busy. The
+// agent doesn't block on processes that don't read their input; it detaches from them instead.
+const execInputBufferSize = 64
+
+// execs relays the processes the master starts in containers on the agent for interactive
+// sessions.
+type execs struct {
+	log      *logrus.Entry
+	cruntime *docker.Client
+	outbox   chan *aproto.MasterMessage
+
+	mu       sync.Mutex
+	sessions map[string]*execSession
+}
+
+type execSession struct {
+	// exec is set once the process starts. Access is protected by the lock of execs.
+	exec  *docker.Exec
+	input chan aproto.ExecInput
+	done  chan struct{}
+}
+
+func newExecs(cruntime *docker.Client, outbox chan *aproto.MasterMessage) *execs {
+	return &execs{
+		log:      logrus.WithField("component", "exec"),
+		cruntime: cruntime,
+		outbox:   outbox,
+		sessions: map[string]*execSession{},
+	}
+}
+
+// start registers the session of an ExecStart, so input sent right after it is queued, then starts
+// its process and relays its output until it exits.
+func (e *execs) start(ctx context.Context, msg aproto.ExecStart) {
+	s := &execSession{
+		input: make(chan aproto.ExecInput, execInputBufferSize),
+		done:  make(chan struct{}),
+	}
+	e.mu.Lock()
+	e.sessions[msg.ExecID] = s
+	e.mu.Unlock()
+	go e.run(ctx, msg, s)
+}
+
+func (e *execs) run(ctx context.Context, msg aproto.ExecStart, s *execSession) {
+	defer e.detach(msg.ExecID)
+	log := e.log.WithField("exec-id", msg.ExecID).WithField("cproto-id", msg.ContainerID)
+	exec, err := e.cruntime.ExecContainer(
+		ctx, msg.ContainerID, msg.Cmd, msg.TTY, msg.Rows, msg.Cols,
+	)
+	if err != nil {
+		log.WithError(err).Warn("failed to start exec")
+		e.send(ctx, aproto.ExecOutput{ExecID: msg.ExecID, Error: err.Error()})
+		return
+	}
+	log.Infof("started exec %v", msg.Cmd)
+
+	e.mu.Lock()
+	s.exec = exec
+	e.mu.Unlock()
+	select {
+	case <-s.done:
+		// Detached while the process was starting.
+		exec.Conn.Close()
+		return
+	default:
+	}
+	go e.relayInput(ctx, msg.ExecID, s)
+
+	w := execWriter{ctx: ctx, execs: e, execID: msg.ExecID}
+	if exec.TTY {
+		_, err = io.Copy(w, exec.Conn.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(w, w, exec.Conn.Reader)
+	}
+	select {
+	case <-s.done:
+		log.Info("detached from exec")
+		return
+	default:
+	}
+	if err != nil {
+		e.send(ctx, aproto.ExecOutput{
+			ExecID: msg.ExecID, Error: fmt.Sprintf("reading exec output: %s", err),
+		})
+		return
+	}
+	code, err := e.cruntime.ExecExitCode(ctx, exec.ID)
+	if err != nil {
+		e.send(ctx, aproto.ExecOutput{ExecID: msg.ExecID, Error: err.Error()})
+		return
+	}
+	log.Infof("exec exited with %d", code)
+	e.send(ctx, aproto.ExecOutput{ExecID: msg.ExecID, ExitCode: &code})
+}
+
+// input queues input to an exec process, or detaches from it.
+func (e *execs) input(msg aproto.ExecInput) {
+	if msg.Detach {
+		e.detach(msg.ExecID)
+		return
+	}
+
+	e.mu.Lock()
+	s, ok := e.sessions[msg.ExecID]
+	e.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case s.input <- msg:
+	case <-s.done:
+	default:
+		e.log.WithField("exec-id", msg.ExecID).Warn("detaching from exec that isn't reading input")
+		e.detach(msg.ExecID)
+	}
+}
+
+// relayInput writes the queued input of an exec process to it, in order.
+func (e *execs) relayInput(ctx context.Context, execID string, s *execSession) {
+	for {
+		select {
+		case msg := <-s.input:
+			var err error
+			switch {
+			case msg.Resize != nil:
+				err = e.cruntime.ResizeExec(ctx, s.exec.ID, msg.Resize.Rows, msg.Resize.Cols)
+			case msg.CloseInput:
+				err = s.exec.Conn.CloseWrite()
+			default:
+				_, err = s.exec.Conn.Conn.Write(msg.Data)
+			}
+			if err != nil {
+				e.log.WithField("exec-id", execID).WithError(err).Warn("failed to relay exec input")
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// detach stops relaying an exec process. Processes reading from a TTY exit when it is closed.
+func (e *execs) detach(execID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s, ok := e.sessions[execID]
+	if !ok {
+		return
+	}
+	delete(e.sessions, execID)
+	close(s.done)
+	if s.exec != nil {
+		s.exec.Conn.Close()
+	}
+}
+
+// detachAll detaches from every exec process. The master ends exec sessions when the agent
+// disconnects, since their output is lost while it is away.
+func (e *execs) detachAll() {
+	e.mu.Lock()
+	ids := make([]string, 0, len(e.sessions))
+	for id := range e.sessions {
+		ids = append(ids, id)
+	}
+	e.mu.Unlock()
+	for _, id := range ids {
+		e.detach(id)
+	}
+}
+
+func (e *execs) send(ctx context.Context, out aproto.ExecOutput) {
+	select {
+	case e.outbox <- &aproto.MasterMessage{ExecOutput: &out}:
+	case <-ctx.Done():
+	}
+}
+
+// execWriter sends what is written to it to the master as the output of an exec process.
+type execWriter struct {
+	ctx    context.Context
+	execs  *execs
+	execID string
+}
+
+func (w execWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	// The caller reuses p, so it is copied before being queued.
+	w.execs.send(w.ctx, aproto.ExecOutput{ExecID: w.execID, Data: append([]byte(nil), p...)})
+	return len(p), nil
+}
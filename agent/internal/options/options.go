@@ -10,6 +10,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/logger"
+	"github.com/determined-ai/determined/master/pkg/model"
 
 	"github.com/pkg/errors"
 )
@@ -50,6 +51,8 @@ type Options struct {
 
 	// Label has been deprecated; we now use ResourcePool to classify the agent.
 	ResourcePool string `json:"resource_pool"`
+	// Labels are arbitrary key/value pairs that experiments can select the agent by.
+	Labels map[string]string `json:"labels"`
 
 	ContainerMasterHost string `json:"container_master_host"`
 	ContainerMasterPort int    `json:"container_master_port"`
@@ -57,6 +60,21 @@ type Options struct {
 	SlotType    string `json:"slot_type"`
 	VisibleGPUs string `json:"visible_gpus"`
 
+	// ReservedSlots and ReservedCPUCores are withheld from tasks for the system daemons and log
+	// shipper on the agent, so fully packed agents don't starve them.
+	ReservedSlots    int `json:"reserved_slots"`
+	ReservedCPUCores int `json:"reserved_cpu_cores"`
+
+	// ScratchDir is the directory, usually on a local NVMe disk, that the scratch volumes of tasks
+	// are made in. ScratchCapacityGiB is how much of it they may reserve in total, which defaults
+	// to the size of its filesystem.
+	ScratchDir         string `json:"scratch_dir"`
+	ScratchCapacityGiB int    `json:"scratch_capacity_gib"`
+
+	// ImageDiskPath is a path on the partition Docker stores images on, for when the agent can't
+	// see the Docker root directory, like when it runs in a container.
+	ImageDiskPath string `json:"image_disk_path"`
+
 	Security SecurityOptions `json:"security"`
 
 	Debug           bool `json:"debug"`
@@ -70,6 +88,11 @@ type Options struct {
 	BindIP     string `json:"bind_ip"`
 	BindPort   int    `json:"bind_port"`
 
+	// OutboundOnly is set for agents behind firewalls or NAT that accept no connections from the
+	// master. The master proxies to the services of their containers through tunnels over their
+	// connection to it instead.
+	OutboundOnly bool `json:"outbound_only"`
+
 	HTTPProxy  string `json:"http_proxy"`
 	HTTPSProxy string `json:"https_proxy"`
 	FTPProxy   string `json:"ftp_proxy"`
@@ -86,6 +109,10 @@ type Options struct {
 
 	Hooks HooksOptions `json:"hooks"`
 
+	// BindMountConstraints restrict which host paths tasks on the agent may bind mount. They're
+	// checked when each container starts, in addition to any config policies on the master.
+	BindMountConstraints model.BindMountConstraints `json:"bind_mount_constraints"`
+
 	// The Fluent docker image to use, deprecated.
 	Fluent FluentOptions `json:"fluent"`
 }
@@ -113,7 +140,28 @@ func (o Options) Validate() []error {
 		o.validateTLS(),
 		check.In(o.SlotType, []string{"gpu", "cuda", "rocm", "cpu", "auto", "none"}),
 		check.NotEmpty(o.MasterHost, "master host must be provided"),
+		o.validateLabels(),
+		check.GreaterThanOrEqualTo(o.ReservedSlots, 0, "reserved slots must be >= 0"),
+		check.GreaterThanOrEqualTo(o.ReservedCPUCores, 0, "reserved CPU cores must be >= 0"),
+		check.GreaterThanOrEqualTo(o.ScratchCapacityGiB, 0, "scratch capacity must be >= 0"),
+		o.validateOutboundOnly(),
+	}
+}
+
+func (o Options) validateOutboundOnly() error {
+	if o.OutboundOnly && o.APIEnabled {
+		return errors.New("the agent API can't be enabled on an outbound-only agent")
 	}
+	return nil
+}
+
+func (o Options) validateLabels() error {
+	for key := range o.Labels {
+		if key == "" {
+			return errors.New("agent label keys must not be empty")
+		}
+	}
+	return nil
 }
 
 func (o Options) validateTLS() error {
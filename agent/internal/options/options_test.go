@@ -86,10 +86,15 @@ master_host: master_host_IP
 master_port: 5000
 agent_id: agent_device_name
 resource_pool: agent_rp
+labels:
+    gpu: a100
+    rack: r12
 container_master_host: docker_localhost
 container_master_port: 2000
 slot_type: gpu_slot_type
 visible_gpus: 3
+reserved_slots: 1
+reserved_cpu_cores: 2
 security:
     tls:
         enabled: true
@@ -122,10 +127,13 @@ container_runtime: docker_runtime_env
 				MasterPort:          5000,
 				AgentID:             "agent_device_name",
 				ResourcePool:        "agent_rp",
+				Labels:              map[string]string{"gpu": "a100", "rack": "r12"},
 				ContainerMasterHost: "docker_localhost",
 				ContainerMasterPort: 2000,
 				SlotType:            "gpu_slot_type",
 				VisibleGPUs:         "3",
+				ReservedSlots:       1,
+				ReservedCPUCores:    2,
 				Security: SecurityOptions{
 					TLS: TLSOptions{
 						Enabled:        true,
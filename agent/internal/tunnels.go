@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+)
+
+const (
+	// tunnelInputBufferSize is the number of messages from the master buffered for a tunnel while
+	// the connection it relays is busy. The agent doesn't block on connections that don't read
+	// them; it closes them instead.
+	tunnelInputBufferSize = 256
+	// tunnelChunkSize is the most data sent to the master in one message.
+	tunnelChunkSize = 32 * 1024
+)
+
+// tunnels relays the connections the master opens to ports on an outbound-only agent's host, to
+// proxy to the services of its containers.
+type tunnels struct {
+	log     *logrus.Entry
+	enabled bool
+	outbox  chan *aproto.MasterMessage
+
+	mu      sync.Mutex
+	tunnels map[string]*tunnel
+}
+
+type tunnel struct {
+	// conn is set once the connection is open. Access is protected by the lock of tunnels.
+	conn  net.Conn
+	input chan aproto.TunnelData
+	done  chan struct{}
+}
+
+func newTunnels(enabled bool, outbox chan *aproto.MasterMessage) *tunnels {
+	return &tunnels{
+		log:     logrus.WithField("component", "tunnel"),
+		enabled: enabled,
+		outbox:  outbox,
+		tunnels: map[string]*tunnel{},
+	}
+}
+
+// open registers the tunnel of a TunnelOpen, so data sent right after it is queued, then connects
+// to its port and relays the connection until either side closes it.
+func (t *tunnels) open(ctx context.Context, msg aproto.TunnelOpen) {
+	if !t.enabled {
+		t.send(ctx, aproto.TunnelData{
+			TunnelID: msg.TunnelID, Close: true, Error: "agent accepts connections from the master",
+		})
+		return
+	}
+	s := &tunnel{
+		input: make(chan aproto.TunnelData, tunnelInputBufferSize),
+		done:  make(chan struct{}),
+	}
+	t.mu.Lock()
+	t.tunnels[msg.TunnelID] = s
+	t.mu.Unlock()
+	go t.run(ctx, msg, s)
+}
+
+func (t *tunnels) run(ctx context.Context, msg aproto.TunnelOpen, s *tunnel) {
+	defer t.close(msg.TunnelID)
+	log := t.log.WithField("tunnel-id", msg.TunnelID)
+	addr := net.JoinHostPort("localhost", strconv.Itoa(msg.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		log.WithError(err).Warn("failed to open tunnel")
+		t.send(ctx, aproto.TunnelData{TunnelID: msg.TunnelID, Close: true, Error: err.Error()})
+		return
+	}
+	log.Debugf("opened tunnel to %s", addr)
+
+	t.mu.Lock()
+	s.conn = conn
+	t.mu.Unlock()
+	select {
+	case <-s.done:
+		// Closed while the connection was opening.
+		conn.Close()
+		return
+	default:
+	}
+	go t.relayInput(msg.TunnelID, s)
+
+	buf := make([]byte, tunnelChunkSize)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			t.send(ctx, aproto.TunnelData{
+				TunnelID: msg.TunnelID, Data: append([]byte(nil), buf[:n]...),
+			})
+		}
+		if err != nil {
+			select {
+			case <-s.done:
+				return
+			default:
+			}
+			t.send(ctx, aproto.TunnelData{TunnelID: msg.TunnelID, Close: true})
+			return
+		}
+	}
+}
+
+// data queues data the master sent over a tunnel, or closes it.
+func (t *tunnels) data(msg aproto.TunnelData) {
+	t.mu.Lock()
+	s, ok := t.tunnels[msg.TunnelID]
+	t.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case s.input <- msg:
+	case <-s.done:
+	default:
+		t.log.WithField("tunnel-id", msg.TunnelID).Warn("closing tunnel that isn't reading input")
+		t.close(msg.TunnelID)
+	}
+}
+
+// relayInput writes the queued data of a tunnel to its connection, in order.
+func (t *tunnels) relayInput(tunnelID string, s *tunnel) {
+	for {
+		select {
+		case msg := <-s.input:
+			if len(msg.Data) > 0 {
+				if _, err := s.conn.Write(msg.Data); err != nil {
+					t.log.WithField("tunnel-id", tunnelID).WithError(err).Warn("failed to relay tunnel data")
+				}
+			}
+			if msg.Close {
+				t.close(tunnelID)
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// close stops relaying a tunnel and closes its connection.
+func (t *tunnels) close(tunnelID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.tunnels[tunnelID]
+	if !ok {
+		return
+	}
+	delete(t.tunnels, tunnelID)
+	close(s.done)
+	if s.conn != nil {
+		s.conn.Close()
+	}
+}
+
+// closeAll closes every tunnel. The master closes its side of them when the agent disconnects.
+func (t *tunnels) closeAll() {
+	t.mu.Lock()
+	ids := make([]string, 0, len(t.tunnels))
+	for id := range t.tunnels {
+		ids = append(ids, id)
+	}
+	t.mu.Unlock()
+	for _, id := range ids {
+		t.close(id)
+	}
+}
+
+func (t *tunnels) send(ctx context.Context, data aproto.TunnelData) {
+	select {
+	case t.outbox <- &aproto.MasterMessage{TunnelData: &data}:
+	case <-ctx.Done():
+	}
+}
@@ -356,6 +356,116 @@ func (d *Client) ListRunningContainers(ctx context.Context, fs filters.Args) (
 	return result, nil
 }
 
+// DiskUsage returns the utilization of the partition at path, or of the partition Docker stores
+// its data on if it is empty, and the images Docker has.
+func (d *Client) DiskUsage(ctx context.Context, path string) (*aproto.AgentDiskUsage, error) {
+	if path == "" {
+		info, err := d.cl.Info(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting docker info: %w", err)
+		}
+		path = info.DockerRootDir
+	}
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return nil, fmt.Errorf("getting disk usage of %s: %w", path, err)
+	}
+
+	containers, err := d.cl.ContainerList(ctx, dcontainer.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+	inUse := make(map[string]bool, len(containers))
+	for _, cont := range containers {
+		inUse[cont.ImageID] = true
+	}
+	images, err := d.cl.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing images: %w", err)
+	}
+
+	usage := &aproto.AgentDiskUsage{
+		TotalBytes: stat.Blocks * uint64(stat.Bsize),
+		UsedBytes:  (stat.Blocks - stat.Bfree) * uint64(stat.Bsize),
+	}
+	for _, img := range images {
+		usage.Images = append(usage.Images, aproto.Image{
+			ID:        img.ID,
+			Refs:      append(append([]string{}, img.RepoTags...), img.RepoDigests...),
+			SizeBytes: img.Size,
+			InUse:     inUse[img.ID],
+		})
+	}
+	return usage, nil
+}
+
+// RemoveImage removes a Docker image by ID, along with its untagged parents. It fails, rather than
+// force the removal, if a container uses the image.
+func (d *Client) RemoveImage(ctx context.Context, id string) error {
+	_, err := d.cl.ImageRemove(ctx, id, types.ImageRemoveOptions{PruneChildren: true})
+	return err
+}
+
+// Exec is a process started in a running container, attached to its standard streams. Without a
+// TTY, its output is multiplexed as stdcopy frames.
+type Exec struct {
+	ID   string
+	TTY  bool
+	Conn types.HijackedResponse
+}
+
+// ExecContainer starts a process in the running container with the given Determined container ID
+// and attaches to it. The initial size of the TTY is only set if rows and cols are.
+func (d *Client) ExecContainer(
+	ctx context.Context, id cproto.ID, cmd []string, tty bool, rows, cols uint,
+) (*Exec, error) {
+	filter := LabelFilter(ContainerIDLabel, id.String())
+	containers, err := d.cl.ContainerList(ctx, dcontainer.ListOptions{Filters: filter})
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("finding container %s: %w", id, err)
+	case len(containers) != 1:
+		return nil, fmt.Errorf("container %s is not running", id)
+	}
+
+	config := types.ExecConfig{
+		Cmd:          cmd,
+		Tty:          tty,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+	if tty && rows > 0 && cols > 0 {
+		config.ConsoleSize = &[2]uint{rows, cols}
+	}
+	created, err := d.cl.ContainerExecCreate(ctx, containers[0].ID, config)
+	if err != nil {
+		return nil, fmt.Errorf("creating exec in container %s: %w", id, err)
+	}
+	conn, err := d.cl.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{
+		Tty:         tty,
+		ConsoleSize: config.ConsoleSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("attaching to exec in container %s: %w", id, err)
+	}
+	return &Exec{ID: created.ID, TTY: tty, Conn: conn}, nil
+}
+
+// ResizeExec resizes the TTY of an exec process.
+func (d *Client) ResizeExec(ctx context.Context, execID string, rows, cols uint) error {
+	return d.cl.ContainerExecResize(ctx, execID, dcontainer.ResizeOptions{Height: rows, Width: cols})
+}
+
+// ExecExitCode returns the exit code of an exec process that has exited.
+func (d *Client) ExecExitCode(ctx context.Context, execID string) (int, error) {
+	inspect, err := d.cl.ContainerExecInspect(ctx, execID)
+	if err != nil {
+		return 0, fmt.Errorf("inspecting exec %s: %w", execID, err)
+	}
+	return inspect.ExitCode, nil
+}
+
 // LabelFilter is a convenience that takes a key and value and returns a docker label filter.
 func LabelFilter(key, val string) filters.Args {
 	return filters.NewArgs(filters.Arg("label", key+"="+val))
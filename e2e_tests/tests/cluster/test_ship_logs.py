@@ -1,7 +1,9 @@
+import gzip
 import io
 import json
 import logging
 import os
+import re
 import shutil
 import signal
 import socket
@@ -12,7 +14,7 @@ import tempfile
 import textwrap
 import threading
 import time
-from typing import Any, List, Optional
+from typing import Any, Dict, List, Optional
 
 import pytest
 
@@ -34,11 +36,23 @@ class ShipLogServer:
     shuts down much faster.
     """
 
-    def __init__(self, ctx: Optional[ssl.SSLContext] = None, reject_logs: bool = False) -> None:
+    def __init__(
+        self,
+        ctx: Optional[ssl.SSLContext] = None,
+        reject_logs: bool = False,
+        fail_first: int = 0,
+        busy_first: int = 0,
+    ) -> None:
         self.ctx = ctx
         self.reject_logs = reject_logs
+        # How many batches to fail to acknowledge after keeping their logs, like a master that
+        # crashes before it responds, or to ask to retry later, like a busy master.
+        self.fail_first = fail_first
+        self.busy_first = busy_first
         self.quit = False
         self.logs: List[str] = []
+        self.seqs: List[int] = []
+        self.acked: Dict[str, int] = {}
 
         self.listener = socket.socket()
         self.listener.bind(("127.0.0.1", 0))
@@ -100,26 +114,39 @@ class ShipLogServer:
         if self.reject_logs:
             s.sendall(b"HTTP/1.1 500 No! I don't wanna!\r\n\r\n")
             return
-        # Receive body until we have valid json.
+        # Receive the whole body.
         hdrs, body = hdrs.split(b"\r\n\r\n", maxsplit=1)
-        while True:
-            try:
-                jbody = json.loads(body)
-                break
-            except json.decoder.JSONDecodeError:
-                # Must not have the full body yet.
-                pass
+        m = re.search(rb"content-length: *(\d+)", hdrs, flags=re.IGNORECASE)
+        assert m, hdrs
+        while len(body) < int(m.group(1)):
             buf = s.recv(4096)
             if not buf:
                 # EOF
                 return
             body += buf
+        assert re.search(rb"content-encoding: *gzip", hdrs, flags=re.IGNORECASE), hdrs
+        jbody = json.loads(gzip.decompress(body))
 
-        # Remember the logs we saw.
-        self.logs.extend(j["log"] for j in jbody["logs"])
+        if self.busy_first:
+            self.busy_first -= 1
+            s.sendall(b"HTTP/1.1 429 Too Many Requests\r\nRetry-After: 0\r\n\r\n")
+            return
+
+        # Remember the logs we saw, unless we already acknowledged the batch.
+        stream_id, seq = jbody["stream_id"], jbody["seq"]
+        if seq > self.acked.get(stream_id, 0):
+            self.logs.extend(j["log"] for j in jbody["logs"])
+            self.seqs.append(seq)
+            self.acked[stream_id] = seq
+
+        if self.fail_first:
+            self.fail_first -= 1
+            s.sendall(b"HTTP/1.1 500 Crashed\r\n\r\n")
+            return
 
         # Send a response.
-        s.sendall(b"HTTP/1.1 200 OK\r\n\r\n")
+        ack = json.dumps({"acked_seq": self.acked[stream_id], "credit": 1000}).encode("utf8")
+        s.sendall(b"HTTP/1.1 200 OK\r\nContent-Length: %d\r\n\r\n%s" % (len(ack), ack))
 
     def master_url(self) -> str:
         return f"http://127.0.0.1:{self.port}"
@@ -145,6 +172,8 @@ class TestShipLogs:
         log_wait_time: float = 30,
         cert_name: str = "",
         cert_file: str = "",
+        limiter: Optional[ship_logs.LogLimiter] = None,
+        spool: Optional[ship_logs.Spool] = None,
     ) -> int:
         exit_code = ship_logs.main(
             master_url=master_url,
@@ -155,6 +184,8 @@ class TestShipLogs:
             emit_stdout_logs=False,
             cmd=cmd,
             log_wait_time=log_wait_time,
+            limiter=limiter,
+            spool=spool,
         )
         assert isinstance(exit_code, int), exit_code
         return exit_code
@@ -330,7 +361,9 @@ class TestShipLogs:
         cmd = mkcmd(
             """
             # ONLY STANDARD LIBRARY IMPORTS ARE ALLOWED
+            import collections
             import datetime
+            import gzip
             import io
             import json
             import logging
@@ -341,6 +374,7 @@ class TestShipLogs:
             import ssl
             import subprocess
             import sys
+            import tempfile
             import threading
             import time
             import traceback
@@ -474,6 +508,162 @@ class TestShipLogs:
             duration = time.time() - start
             assert duration < 2, duration
 
+    @pytest.mark.e2e_cpu
+    def test_dedup(self) -> None:
+        cmd = mkcmd(
+            """
+            for _ in range(100):
+                print("again", flush=True)
+            print("done", flush=True)
+            """
+        )
+        with ShipLogServer() as srv:
+            limiter = ship_logs.LogLimiter(dedup=True)
+            exit_code = self.run_ship_logs(srv.master_url(), cmd, limiter=limiter)
+        assert exit_code == 0, exit_code
+        # The repeats may be split across batches, but none of them are lost.
+        assert srv.logs[0] == "again\n", srv.logs
+        assert srv.logs[-1] == "done\n", srv.logs
+        repeats = 0
+        for log in srv.logs[1:-1]:
+            m = re.fullmatch(r"\[previous line repeated (\d+) times\]\n", log)
+            assert m, srv.logs
+            repeats += int(m.group(1))
+        assert repeats == 99, srv.logs
+
+
+    @pytest.mark.e2e_cpu
+    def test_retries_are_not_duplicated(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        monkeypatch.setattr(ship_logs, "SHIPPER_RETRY_BACKOFFS", [0])
+        cmd = mkcmd(
+            """
+            import time
+            for i in range(5):
+                print(i, flush=True)
+                time.sleep(0.3)
+            """
+        )
+        with ShipLogServer(fail_first=2, busy_first=1) as srv:
+            exit_code = self.run_ship_logs(srv.master_url(), cmd)
+        assert exit_code == 0, exit_code
+        # Batches whose acknowledgements were lost are retried, but their logs are kept once.
+        assert srv.logs == [f"{i}\n" for i in range(5)], srv.logs
+        assert srv.seqs == sorted(srv.seqs), srv.seqs
+
+    @pytest.mark.e2e_cpu
+    def test_spool_survives_outage(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        monkeypatch.setattr(ship_logs, "SHIPPER_RETRY_BACKOFFS", [0.5])
+        tmp = tempfile.mkdtemp(suffix="ship_logs_spool")
+        try:
+            spool = ship_logs.Spool(tmp, 1 << 20)
+            cmd = mkcmd(
+                """
+                import time
+                for i in range(10):
+                    print(i, flush=True)
+                    time.sleep(0.2)
+                """
+            )
+            # The master drops every batch for a while, and the shipper spools them meanwhile.
+            with ShipLogServer(fail_first=1000) as srv:
+                threading.Timer(1, lambda: setattr(srv, "fail_first", 0)).start()
+                exit_code = self.run_ship_logs(srv.master_url(), cmd, spool=spool)
+            assert exit_code == 0, exit_code
+            assert srv.logs == [f"{i}\n" for i in range(10)], srv.logs
+            assert len(spool) == 0 and os.listdir(tmp) == []
+        finally:
+            shutil.rmtree(tmp)
+
+
+class TestSpool:
+    @pytest.mark.e2e_cpu
+    def test_spool_order_and_size(self) -> None:
+        tmp = tempfile.mkdtemp(suffix="ship_logs_spool")
+        try:
+            spool = ship_logs.Spool(os.path.join(tmp, "spool"), 10)
+            spool.push(b"first")
+            assert not spool.full()
+            spool.push(b"second")
+            assert spool.full()
+            assert len(os.listdir(os.path.join(tmp, "spool"))) == 2
+            assert spool.peek() == b"first"
+            spool.pop()
+            assert spool.peek() == b"second"
+            spool.pop()
+            assert len(spool) == 0 and not spool.full()
+            assert os.listdir(os.path.join(tmp, "spool")) == []
+        finally:
+            shutil.rmtree(tmp)
+
+    @pytest.mark.e2e_cpu
+    def test_spool_in_memory(self) -> None:
+        # A spool that can't write to disk holds batches in memory.
+        spool = ship_logs.Spool("/dev/null/spool", 10)
+        spool.push(b"batch")
+        assert spool.peek() == b"batch"
+        spool.pop()
+        assert len(spool) == 0
+
+        # Without room for anything, every batch must be shipped before the next is read.
+        spool = ship_logs.Spool("/dev/null/spool", 0)
+        assert spool.full()
+
+
+class TestLogLimiter:
+    @pytest.mark.e2e_cpu
+    def test_no_limits(self) -> None:
+        limiter = ship_logs.LogLimiter()
+        logs = [{"log": "same\n"} for _ in range(3)]
+        assert [out for log in logs for out in limiter.add(log)] == logs
+        assert limiter.flush() == []
+
+    @pytest.mark.e2e_cpu
+    def test_dedup_keys(self) -> None:
+        limiter = ship_logs.LogLimiter(dedup=True)
+        out = limiter.add({"log": "x\n", "stdtype": "stdout"})
+        out += limiter.add({"log": "x\n", "stdtype": "stdout"})
+        # The same text on another stream or rank isn't a repeat.
+        out += limiter.add({"log": "x\n", "stdtype": "stderr"})
+        out += limiter.add({"log": "x\n", "stdtype": "stderr", "rank_id": 1})
+        out += limiter.flush()
+        assert [log["log"] for log in out] == [
+            "x\n",
+            "[previous line repeated 1 times]\n",
+            "x\n",
+            "x\n",
+        ], out
+        assert out[1]["stdtype"] == "stdout", out
+
+    @pytest.mark.e2e_cpu
+    def test_rate_limit(self) -> None:
+        limiter = ship_logs.LogLimiter(lines_per_second=1, burst=3)
+        out = []
+        for i in range(10):
+            out += limiter.add({"log": f"{i}\n", "stdtype": "stdout"})
+        out += limiter.flush()
+        assert [log["log"] for log in out] == [
+            "0\n",
+            "1\n",
+            "2\n",
+            "[7 lines dropped by the log rate limit of 1 lines per second]\n",
+        ], out
+        assert out[-1]["level"] == "LOG_LEVEL_WARNING", out
+        # Markers aren't repeated once they've been shipped.
+        assert limiter.flush() == []
+
+    @pytest.mark.e2e_cpu
+    def test_rate_limit_counts_repeats(self) -> None:
+        limiter = ship_logs.LogLimiter(dedup=True, lines_per_second=1, burst=1)
+        out = []
+        for _ in range(5):
+            out += limiter.add({"log": "same\n"})
+        out += limiter.flush()
+        # The first line used the only token, so its repeats marker was dropped in its place.
+        assert [log["log"] for log in out] == [
+            "same\n",
+            "[4 lines dropped by the log rate limit of 1 lines per second]\n",
+        ], out
+
 
 class TestReadNewlinesOrCarriageReturns:
     # read_newlines_or_carriage_returns is designed to read from filedescriptors resulting from
@@ -4,6 +4,7 @@ from typing import Any, List, Optional
 
 from determined import cli, errors, experimental
 from determined.cli import render
+from determined.common import util
 from determined.common.api import bindings
 from determined.experimental import client
 
@@ -113,6 +114,42 @@ def describe(args: argparse.Namespace) -> None:
     render_checkpoint(checkpoint)
 
 
+def inspect(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    summary = bindings.get_GetCheckpointInspect(sess, checkpointUuid=args.uuid).summary
+    if args.json:
+        render.print_json(summary.to_json())
+        return
+
+    print(f"Frameworks: {', '.join(summary.frameworks) or 'unknown'}")
+    print(f"Total size: {util.sizeof_fmt(int(summary.totalSize))}\n")
+    render.tabulate_or_csv(
+        ["Path", "Size"],
+        [[f.path, util.sizeof_fmt(int(f.size))] for f in summary.files],
+        False,
+    )
+    for m in summary.models:
+        by_dtype = ", ".join(f"{d}: {int(n):,}" for d, n in sorted(m.parametersByDtype.items()))
+        print(
+            f"\n{m.path} ({m.format}): {m.tensorCount:,} tensors, "
+            f"{int(m.parameters):,} parameters ({by_dtype})"
+        )
+        if args.tensors:
+            render.tabulate_or_csv(
+                ["Tensor", "DType", "Shape", "Parameters"],
+                [
+                    [t.name, t.dtype, [int(d) for d in t.shape], int(t.parameters)]
+                    for t in m.tensors
+                ],
+                False,
+            )
+    for path in sorted(summary.metadata):
+        print(f"\n{path}:")
+        render.print_json(summary.metadata[path])
+    for warning in summary.warnings:
+        print(f"\nWarning: {warning}")
+
+
 def delete_checkpoints(args: argparse.Namespace) -> None:
     sess = cli.setup_session(args)
     if args.yes or render.yes_or_no(
@@ -199,6 +236,16 @@ main_cmd = cli.Cmd(
             "describe checkpoint",
             [cli.Arg("uuid", type=str, help="checkpoint uuid to describe")],
         ),
+        cli.Cmd(
+            "inspect",
+            inspect,
+            "summarize a checkpoint's files, framework, and tensors without downloading it",
+            [
+                cli.Arg("uuid", type=str, help="checkpoint uuid to inspect"),
+                cli.Arg("--tensors", action="store_true", help="list each model's tensors"),
+                cli.Arg("--json", action="store_true", help="print as JSON"),
+            ],
+        ),
         cli.Cmd(
             "delete",
             delete_checkpoints,
@@ -1,5 +1,6 @@
 import argparse
 import hashlib
+import json
 import os
 import socket
 import ssl
@@ -24,17 +25,20 @@ from determined.cli import (
     dev,
     errors,
     experiment,
+    federation,
     job,
     master,
     model,
     notebook,
     oauth,
     project,
+    ray,
     rbac,
     render,
     resource_pool,
     resources,
     shell,
+    spark,
     sso,
     task,
     template,
@@ -78,6 +82,26 @@ def _render_search_summary(resp: bindings.v1PreviewHPSearchResponse) -> str:
     return "\n".join(output)
 
 
+def _render_search_samples(resp: bindings.v1PreviewHPSearchResponse) -> str:
+    samples = resp.samples or []
+    output = [
+        "",
+        termcolor.colored(
+            f"First {len(samples)} trials (experiment_seed: {resp.experimentSeed}):",
+            "green",
+        ),
+    ]
+    rows = [[s["trial_seed"], json.dumps(s["hparams"], sort_keys=True)] for s in samples]
+    output.append(tabulate.tabulate(rows, ["Trial Seed", "Hyperparameters"], tablefmt="presto"))
+
+    output.append("")
+    output.append(f"Estimated total trials: {resp.estimatedTrials or 0}")
+    if resp.estimatedUnit:
+        output.append(f"Estimated total training: {resp.estimatedUnits} {resp.estimatedUnit}")
+        output.append(f"Estimated slot-{resp.estimatedUnit}: {resp.estimatedSlotUnits}")
+    return "\n".join(output)
+
+
 def preview_search(args: argparse.Namespace) -> None:
     sess = cli.setup_session(args)
     experiment_config = util.safe_load_yaml_with_exceptions(args.config_file)
@@ -90,10 +114,14 @@ def preview_search(args: argparse.Namespace) -> None:
         session=sess,
         body=bindings.v1PreviewHPSearchRequest(
             config=experiment_config,
+            sampleCount=args.samples,
         ),
     )
     print(_render_search_summary(resp=resp))
 
+    if args.samples:
+        print(_render_search_samples(resp))
+
 
 args_description = [
     cli.Arg("-u", "--user", help="run as the given user", metavar="username", default=None),
@@ -119,7 +147,13 @@ args_description = [
         [
             cli.Arg(
                 "config_file", type=argparse.FileType("r"), help="experiment config file (.yaml)"
-            )
+            ),
+            cli.Arg(
+                "--samples",
+                type=int,
+                default=0,
+                help="also show the hyperparameters of the first N trials the search would create",
+            ),
         ],
     ),
     top_arg_descriptions.deploy_cmd,
@@ -128,6 +162,7 @@ args_description = [
 all_args_description: cli.ArgsDescription = (
     args_description
     + experiment.args_description
+    + federation.args_description
     + checkpoint.args_description
     + master.args_description
     + model.args_description
@@ -137,7 +172,9 @@ all_args_description: cli.ArgsDescription = (
     + resources.args_description
     + resource_pool.args_description
     + project.args_description
+    + ray.args_description
     + shell.args_description
+    + spark.args_description
     + task.args_description
     + template.args_description
     + tensorboard.args_description
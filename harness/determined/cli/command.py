@@ -6,13 +6,15 @@ import termcolor
 
 from determined import cli
 from determined.cli import ntsc, render, task, workspace
-from determined.common import api
+from determined.common import api, util
 from determined.common.api import bindings
 
 
 def run_command(args: argparse.Namespace) -> None:
     sess = cli.setup_session(args)
     config = ntsc.parse_config(args.config_file, args.entrypoint, args.config, args.volume)
+    if args.output:
+        config.setdefault("outputs", []).extend(args.output)
     workspace_id = workspace.get_workspace_id_from_args(args)
     resp = ntsc.launch_command(
         sess,
@@ -44,6 +46,20 @@ def run_command(args: argparse.Namespace) -> None:
         )
 
 
+def list_outputs(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    outputs = bindings.get_GetTaskOutputs(sess, taskId=args.command_id).outputs
+    if args.json:
+        render.print_json([o.to_json() for o in outputs])
+        return
+
+    headers = ["Path", "Storage Path", "Size", "Uploaded"]
+    values = [
+        [o.path, o.storagePath, util.sizeof_fmt(int(o.sizeBytes)), o.createdAt] for o in outputs
+    ]
+    render.tabulate_or_csv(headers, values, args.csv)
+
+
 args_description: cli.ArgsDescription = [
     cli.Cmd(
         "command cmd",
@@ -103,6 +119,14 @@ args_description: cli.ArgsDescription = [
                         help="command config file (.yaml)",
                     ),
                     cli.Arg("-v", "--volume", action="append", default=[], help=ntsc.VOLUME_DESC),
+                    cli.Arg(
+                        "-o",
+                        "--output",
+                        action="append",
+                        default=[],
+                        help="path in the container to upload to checkpoint storage when the "
+                        "command exits; may be specified multiple times",
+                    ),
                     cli.Arg(
                         "-c", "--context", default=None, type=pathlib.Path, help=ntsc.CONTEXT_DESC
                     ),
@@ -138,6 +162,15 @@ args_description: cli.ArgsDescription = [
                     *task.common_log_options,
                 ],
             ),
+            cli.Cmd(
+                "outputs",
+                list_outputs,
+                "list the outputs a command uploaded when it exited",
+                [
+                    cli.Arg("command_id", type=str, help="command ID"),
+                    cli.Group(cli.output_format_args["json"], cli.output_format_args["csv"]),
+                ],
+            ),
             cli.Cmd(
                 "kill",
                 functools.partial(ntsc.kill),
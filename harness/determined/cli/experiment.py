@@ -2,12 +2,15 @@ import argparse
 import base64
 import json
 import numbers
+import os
 import pathlib
 import pprint
+import subprocess
 import sys
 import time
+import urllib.parse
 import warnings
-from typing import Any, Dict, Iterable, List, Optional, Sequence, Set, Union
+from typing import Any, Dict, Iterable, List, Optional, Sequence, Set, Tuple, Union
 
 import tabulate
 import termcolor
@@ -163,6 +166,42 @@ def _follow_test_experiment_logs(sess: api.Session, exp_id: int) -> None:
             time.sleep(0.2)
 
 
+def _git_metadata(model_def: pathlib.Path) -> Tuple[Optional[str], Optional[str]]:
+    """
+    Return the commit and the origin remote of the git checkout a model definition is in, if it is
+    in one, so that the master can tell experiments submitted from different commits apart.
+    """
+
+    checkout = model_def if model_def.is_dir() else model_def.parent
+
+    def git(*git_args: str) -> Optional[str]:
+        try:
+            out = subprocess.run(
+                ["git", "-C", str(checkout), *git_args],
+                stdout=subprocess.PIPE,
+                stderr=subprocess.DEVNULL,
+                text=True,
+            )
+        except OSError:
+            return None
+        return (out.stdout.strip() or None) if out.returncode == 0 else None
+
+    remote = git("config", "--get", "remote.origin.url")
+    if remote:
+        # Don't send credentials embedded in the remote's URL.
+        parsed = urllib.parse.urlsplit(remote)
+        if parsed.hostname and "@" in parsed.netloc:
+            netloc = parsed.hostname + (f":{parsed.port}" if parsed.port else "")
+            remote = urllib.parse.urlunsplit(parsed._replace(netloc=netloc))
+    return git("rev-parse", "HEAD"), remote
+
+
+def _print_duplicates(master: str, experiment_ids: Sequence[int]) -> None:
+    for exp_id in experiment_ids:
+        link = f"{master}/det/experiments/{exp_id}/overview"
+        print(termcolor.colored(f"  {exp_id}: {link}", "yellow"), file=sys.stderr)
+
+
 def submit_experiment(args: argparse.Namespace) -> None:
     sess = cli.setup_session(args)
     config_text = args.config_file.read()
@@ -178,9 +217,12 @@ def submit_experiment(args: argparse.Namespace) -> None:
         assert yaml_dump is not None
         config_text = yaml_dump
 
+    git_commit, git_remote = _git_metadata(args.model_def) if args.model_def else (None, None)
     req = bindings.v1CreateExperimentRequest(
         activate=not args.paused,
         config=config_text,
+        gitCommit=git_commit,
+        gitRemote=git_remote,
         modelDefinition=model_context,
         parentId=None,
         projectId=args.project_id,
@@ -217,6 +259,7 @@ def submit_experiment(args: argparse.Namespace) -> None:
 
         if resp.warnings:
             cli.print_launch_warnings(resp.warnings)
+        _print_duplicates(args.master, resp.duplicateExperimentIds or [])
 
         if not args.paused and args.follow_first_trial:
             if args.publish:
@@ -240,6 +283,9 @@ def continue_experiment(args: argparse.Namespace) -> None:
     else:
         experiment_config = ntsc.parse_config_overrides({}, args.config)
 
+    if args.repin_image:
+        experiment_config.setdefault("environment", {})["image_pinning"] = "repin"
+
     config_text = util.yaml_safe_dump(experiment_config)
 
     req = bindings.v1ContinueExperimentRequest(
@@ -337,6 +383,8 @@ def describe(args: argparse.Namespace) -> None:
         "Description",
         "Archived",
         "Resource Pool",
+        "Storage Used",
+        "Checkpoints",
         "Labels",
     ]
     values: List[List] = [
@@ -350,6 +398,8 @@ def describe(args: argparse.Namespace) -> None:
             exp.description,
             exp.archived,
             exp.resourcePool,
+            util.sizeof_fmt(int(exp.checkpointSize or 0)),
+            exp.checkpointCount or 0,
             ", ".join(sorted(exp.labels or [])),
         ]
         for exp in exps
@@ -689,6 +739,7 @@ def list_experiments(args: argparse.Namespace, sess: api.Session) -> None:
             render.format_time(e.startTime),
             render.format_time(e.endTime),
             e.resourcePool,
+            util.sizeof_fmt(int(e.checkpointSize or 0)),
         ]  # type: List[Any]
         if args.show_project:
             result = [e.workspaceName, e.projectName] + result
@@ -706,6 +757,7 @@ def list_experiments(args: argparse.Namespace, sess: api.Session) -> None:
         "Started",
         "Ended",
         "Resource Pool",
+        "Storage Used",
     ]
     if args.show_project:
         headers = ["Workspace", "Project"] + headers
@@ -925,6 +977,121 @@ def set_gc_policy(args: argparse.Namespace) -> None:
         print("Aborting operations.")
 
 
+def import_mlflow(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    body = bindings.v1PostImportFromMLflowRequest(
+        url=args.tracking_uri,
+        apiKey=os.environ.get("MLFLOW_TRACKING_TOKEN", ""),
+        experiment=args.mlflow_experiment,
+        maxRuns=args.max_runs,
+        projectId=args.project_id,
+        name=args.name or None,
+        searcherMetric=args.searcher_metric or None,
+    )
+    imp = bindings.post_PostImportFromMLflow(sess, body=body).mlflowImport
+    print(f"Started MLflow import {imp.id}")
+    while imp.state == bindings.v1MLflowImportState.RUNNING:
+        time.sleep(2)
+        imp = bindings.get_GetMLflowImport(sess, importId=imp.id).mlflowImport
+    if imp.state != bindings.v1MLflowImportState.COMPLETED:
+        raise cli.CliError(f"MLflow import {imp.id} failed: {imp.error}")
+    print(f"Imported {imp.trials} MLflow runs into archived experiment {imp.experimentId}")
+
+
+def estimate_cost(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    body = bindings.v1PostEstimateExperimentCostRequest(
+        config=util.safe_load_yaml_with_exceptions(args.config_file),
+        projectId=args.project_id,
+    )
+    est = bindings.post_PostEstimateExperimentCost(sess, body=body).estimate
+    if args.json:
+        render.print_json(est.to_json())
+        return
+
+    print(f"Trials: {est.trialCount} with {est.slotsPerTrial} slots each")
+    print(f"Similar completed trials: {est.similarTrials}")
+    if est.slotHours is not None:
+        print(
+            f"Slot hours: {est.slotHours.expected:.1f} "
+            f"(range {est.slotHours.low:.1f} to {est.slotHours.high:.1f})"
+        )
+    if est.cost is not None:
+        print(
+            f"Cost: {est.cost.expected:.2f} {est.currency} "
+            f"(range {est.cost.low:.2f} to {est.cost.high:.2f} {est.currency})"
+        )
+    for note in est.notes:
+        print(f"Note: {note}")
+
+
+def config_hash(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    if args.experiment_id is not None:
+        exp = bindings.get_GetExperiment(sess, experimentId=args.experiment_id).experiment
+    else:
+        # The config is resolved exactly as it would be to create the experiment.
+        req = bindings.v1CreateExperimentRequest(
+            config=args.config_file.read(),
+            projectId=args.project_id,
+            template=args.template,
+            validateOnly=True,
+        )
+        exp = bindings.post_CreateExperiment(sess, body=req).experiment
+    if not exp.configHash:
+        print("The experiment was created before config hashes were introduced.")
+        return
+    print(exp.configHash)
+
+
+def request_boost(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    if args.priority is not None:
+        kind, value = bindings.v1BoostRequestKind.PRIORITY, args.priority
+    else:
+        kind, value = bindings.v1BoostRequestKind.MAX_SLOTS, args.max_slots
+    body = bindings.v1PostExperimentBoostRequestRequest(
+        experimentId=args.experiment_id,
+        kind=kind,
+        value=value,
+        durationSeconds=int(args.hours * 3600),
+        reason=args.reason,
+    )
+    created = bindings.post_PostExperimentBoostRequest(
+        sess, body=body, experimentId=args.experiment_id
+    ).boostRequest
+    print(f"Submitted boost request {created.id} for review.")
+
+
+def export_metrics(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    path = f"experiments/{args.experiment_id}/metrics/export"
+    trial.write_metrics_export(sess, path, f"exp{args.experiment_id}_metrics", args)
+
+
+def list_boost_requests(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    resp = bindings.get_GetExperimentBoostRequests(sess, experimentId=args.experiment_id)
+    if args.json:
+        render.print_json([r.to_json() for r in resp.boostRequests])
+        return
+
+    headers = ["ID", "Kind", "Value", "Duration (s)", "State", "Expires", "Reason"]
+    values = [
+        [
+            r.id,
+            r.kind.name.lower(),
+            r.value,
+            r.durationSeconds,
+            r.state.name.lower(),
+            r.expiresAt,
+            r.reason,
+        ]
+        for r in resp.boostRequests
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
 def unarchive(args: argparse.Namespace) -> None:
     bindings.post_UnarchiveExperiment(cli.setup_session(args), id=args.experiment_id)
     print(f"Unarchived experiment {args.experiment_id}")
@@ -1120,6 +1287,107 @@ main_cmd = cli.Cmd(
                 ),
             ],
         ),
+        cli.Cmd(
+            "import-mlflow",
+            import_mlflow,
+            "import the runs of an MLflow experiment as an archived experiment",
+            [
+                cli.Arg("tracking_uri", type=str, help="URL of the MLflow tracking server"),
+                cli.Arg("mlflow_experiment", type=str, help="name or ID of the MLflow experiment"),
+                cli.Arg("--project_id", type=int, help="place the experiment inside this project"),
+                cli.Arg("--name", type=str, help="name of the experiment to create"),
+                cli.Arg(
+                    "--searcher-metric",
+                    type=str,
+                    help="metric used to rank trials, by default the first validation metric",
+                ),
+                cli.Arg(
+                    "--max-runs",
+                    type=int,
+                    default=1000,
+                    help="maximum number of runs to import, newest first",
+                ),
+            ],
+        ),
+        cli.Cmd(
+            "estimate-cost",
+            estimate_cost,
+            "estimate the slot hours and cost of an experiment before creating it",
+            [
+                cli.Arg(
+                    "config_file",
+                    type=argparse.FileType("r"),
+                    help="experiment config file (.yaml)",
+                ),
+                cli.Arg(
+                    "--project_id",
+                    type=int,
+                    help="project to estimate from similar experiments in",
+                ),
+                cli.Arg("--json", action="store_true", help="print as JSON"),
+            ],
+        ),
+        cli.Cmd(
+            "config-hash",
+            config_hash,
+            "print the hash of an experiment's effective config, or of the config an experiment "
+            "would have",
+            [
+                cli.Group(
+                    cli.Arg("--experiment-id", type=int, help="an existing experiment"),
+                    cli.Arg(
+                        "--config-file",
+                        type=argparse.FileType("r"),
+                        help="experiment config file (.yaml) to hash as if it were created",
+                    ),
+                    required=True,
+                ),
+                cli.Arg(
+                    "--project_id",
+                    type=int,
+                    help="project the experiment would be created in",
+                ),
+                cli.Arg("--template", type=str, help="name of template to apply to the config"),
+            ],
+        ),
+        cli.Cmd(
+            "request-boost",
+            request_boost,
+            "request a temporary priority boost or max slots exception for an experiment",
+            [
+                experiment_id_arg("experiment ID"),
+                cli.Group(
+                    cli.Arg("--priority", type=int, help="priority to boost the experiment to"),
+                    cli.Arg("--max-slots", type=int, help="max slots to raise the experiment to"),
+                    required=True,
+                ),
+                cli.Arg(
+                    "--hours",
+                    type=float,
+                    required=True,
+                    help="how long to apply the boost for once approved",
+                ),
+                cli.Arg("--reason", type=str, required=True, help="why the boost is needed"),
+            ],
+        ),
+        cli.Cmd(
+            "list-boosts",
+            list_boost_requests,
+            "list the boost requests for an experiment",
+            [
+                experiment_id_arg("experiment ID"),
+                cli.Arg("--json", action="store_true", help="print as JSON"),
+            ],
+        ),
+        cli.Cmd(
+            "export-metrics",
+            export_metrics,
+            "export the full metric history of every trial of an experiment as ndjson or Parquet",
+            [
+                experiment_id_arg("experiment ID"),
+                *trial.metrics_export_args_description,
+            ],
+        ),
         # Continue experiment command.
         cli.Cmd(
             "continue",
@@ -1133,6 +1401,12 @@ main_cmd = cli.Cmd(
                     help="experiment config file (.yaml)",
                 ),
                 cli.Arg("--config", action="append", default=[], help=ntsc.CONFIG_DESC),
+                cli.Arg(
+                    "--repin-image",
+                    action="store_true",
+                    help="resolve the image tags of the experiment to digests again, rather than "
+                    "keeping the image digests pinned when it was created",
+                ),
                 cli.Arg(
                     "-f",
                     "--follow-first-trial",
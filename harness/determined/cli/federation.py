@@ -0,0 +1,94 @@
+import argparse
+from typing import Any, List
+
+from determined import cli
+from determined.cli import render
+from determined.common.api import bindings
+
+
+def print_cluster_errors(clusters: List[bindings.v1FederationCluster]) -> None:
+    for c in clusters:
+        if c.error:
+            print(f"Could not read cluster {c.name}: {c.error}")
+
+
+def list_experiments(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    resp = bindings.get_GetFederationExperiments(sess, limit=args.limit)
+    if args.json:
+        render.print_json(resp.to_json())
+        return
+
+    headers = ["Cluster", "ID", "Name", "Owner", "State", "Progress", "Start Time", "End Time"]
+    values = [
+        [
+            e.cluster,
+            e.id,
+            e.name,
+            e.username,
+            e.state.name,
+            "" if e.progress is None else f"{e.progress:.0%}",
+            e.startTime,
+            e.endTime or "",
+        ]
+        for e in resp.experiments
+    ]
+    render.tabulate_or_csv(headers, values, False)
+    print_cluster_errors(resp.clusters)
+
+
+def list_jobs(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    resp = bindings.get_GetFederationJobs(sess)
+    if args.json:
+        render.print_json(resp.to_json())
+        return
+
+    headers = ["Cluster", "Job ID", "Type", "Name", "Owner", "Resource Pool", "State", "Slots"]
+    values = [
+        [
+            j.cluster,
+            j.jobId,
+            j.type.name,
+            j.name,
+            j.username,
+            j.resourcePool,
+            j.state.name,
+            f"{j.allocatedSlots}/{j.requestedSlots}",
+        ]
+        for j in resp.jobs
+    ]
+    render.tabulate_or_csv(headers, values, False)
+    print_cluster_errors(resp.clusters)
+
+
+args_description: List[Any] = [
+    cli.Cmd(
+        "federation",
+        None,
+        "view the experiments and jobs of this cluster and its federation peers",
+        [
+            cli.Cmd(
+                "experiments",
+                list_experiments,
+                "list experiments across clusters, newest first",
+                [
+                    cli.Arg(
+                        "--limit",
+                        type=int,
+                        default=100,
+                        help="maximum number of experiments to list from each cluster",
+                    ),
+                    cli.Group(cli.output_format_args["json"]),
+                ],
+                is_default=True,
+            ),
+            cli.Cmd(
+                "jobs",
+                list_jobs,
+                "list queued and running jobs across clusters",
+                [cli.Group(cli.output_format_args["json"])],
+            ),
+        ],
+    )
+]
@@ -104,6 +104,124 @@ def get_cluster_message(args: argparse.Namespace) -> None:
         print(util.yaml_safe_dump(message, default_flow_style=False))
 
 
+def _check_timestamp(name: str, value: Optional[str]) -> None:
+    if value is not None and not util.is_protobuf_timestamp(value):
+        raise ValueError(f"{name} must be RFC-3339, i.e. of the form YYYY-MM-DDThh:mm:ssZ")
+
+
+def list_announcements(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    announcements = bindings.get_GetAnnouncements(sess, includeExpired=args.all).announcements
+    if args.json:
+        render.print_json([a.to_json() for a in announcements])
+        return
+
+    headers = ["ID", "Severity", "Start", "End", "Message"]
+    values = [
+        [a.id, a.severity.name.lower(), a.startTime, a.endTime, a.message]
+        for a in announcements
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def create_announcement(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    _check_timestamp("Start time", args.start)
+    _check_timestamp("End time", args.end)
+    body = bindings.v1PostAnnouncementRequest(
+        message=args.message,
+        severity=bindings.v1AnnouncementSeverity[args.severity.upper()],
+        startTime=args.start,
+        endTime=args.end,
+    )
+    created = bindings.post_PostAnnouncement(sess, body=body).announcement
+    print(f"Published announcement {created.id}.")
+
+
+def delete_announcement(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    bindings.delete_DeleteAnnouncement(sess, announcementId=args.announcement_id)
+    print(f"Deleted announcement {args.announcement_id}.")
+
+
+def list_maintenance_windows(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    windows = bindings.get_GetMaintenanceWindows(sess, includePast=args.all).maintenanceWindows
+    if args.json:
+        render.print_json([w.to_json() for w in windows])
+        return
+
+    headers = ["ID", "Start", "End", "Blocks Submissions", "Message"]
+    values = [[w.id, w.startTime, w.endTime, w.blockSubmissions, w.message] for w in windows]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def create_maintenance_window(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    _check_timestamp("Start time", args.start)
+    _check_timestamp("End time", args.end)
+    body = bindings.v1PostMaintenanceWindowRequest(
+        message=args.message,
+        startTime=args.start,
+        endTime=args.end,
+        blockSubmissions=args.block_submissions,
+    )
+    created = bindings.post_PostMaintenanceWindow(sess, body=body).maintenanceWindow
+    print(f"Scheduled maintenance window {created.id}.")
+
+
+def delete_maintenance_window(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    bindings.delete_DeleteMaintenanceWindow(sess, windowId=args.window_id)
+    print(f"Deleted maintenance window {args.window_id}.")
+
+
+def list_boost_requests(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    state = (
+        bindings.v1BoostRequestState[args.state.upper()] if args.state != "all" else None
+    )
+    requests = bindings.get_GetBoostRequests(sess, state=state).boostRequests
+    if args.json:
+        render.print_json([r.to_json() for r in requests])
+        return
+
+    headers = ["ID", "Experiment", "Kind", "Value", "Duration (s)", "State", "Requested By",
+               "Reason"]
+    values = [
+        [
+            r.id,
+            r.experimentId,
+            r.kind.name.lower(),
+            r.value,
+            r.durationSeconds,
+            r.state.name.lower(),
+            r.requestedBy,
+            r.reason,
+        ]
+        for r in requests
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def approve_boost_request(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    body = bindings.v1PostApproveBoostRequestRequest(
+        requestId=args.request_id, comment=args.comment
+    )
+    approved = bindings.post_PostApproveBoostRequest(
+        sess, body=body, requestId=args.request_id
+    ).boostRequest
+    print(f"Approved boost request {args.request_id}; it expires at {approved.expiresAt}.")
+
+
+def deny_boost_request(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    body = bindings.v1PostDenyBoostRequestRequest(requestId=args.request_id, comment=args.comment)
+    bindings.post_PostDenyBoostRequest(sess, body=body, requestId=args.request_id)
+    print(f"Denied boost request {args.request_id}.")
+
+
 # fmt: off
 
 args_description = [
@@ -183,7 +301,62 @@ args_description = [
             cli.Cmd("get", get_cluster_message, "get cluster-wide message", [
                 cli.Group(cli.output_format_args["json"], cli.output_format_args["yaml"])
             ]),
-        ])
+        ]),
+        cli.Cmd("announcement", None, "manage cluster-wide announcements", [
+            cli.Cmd("list ls", list_announcements, "list announcements", [
+                cli.Arg("--all", action="store_true", help="include expired announcements"),
+                cli.Arg("--json", action="store_true", help="print as JSON"),
+            ], is_default=True),
+            cli.Cmd("create", create_announcement, "publish an announcement", [
+                cli.Arg("message", type=str, help="text of the announcement"),
+                cli.Arg("--severity", choices=["info", "warning", "critical"], default="info",
+                        help="how prominently to show the announcement"),
+                cli.Arg("-s", "--start", default=None,
+                        help="timestamp to start showing the announcement (RFC 3339 format); "
+                        + "default is now"),
+                cli.Arg("-e", "--end", default=None,
+                        help="timestamp to stop showing the announcement (RFC 3339 format); "
+                        + "default is indefinite"),
+            ]),
+            cli.Cmd("delete", delete_announcement, "delete an announcement", [
+                cli.Arg("announcement_id", type=int, help="ID of the announcement"),
+            ]),
+        ]),
+        cli.Cmd("maintenance", None, "manage scheduled maintenance windows", [
+            cli.Cmd("list ls", list_maintenance_windows, "list maintenance windows", [
+                cli.Arg("--all", action="store_true", help="include finished maintenance windows"),
+                cli.Arg("--json", action="store_true", help="print as JSON"),
+            ], is_default=True),
+            cli.Cmd("create", create_maintenance_window, "schedule a maintenance window", [
+                cli.Arg("message", type=str, help="description of the maintenance"),
+                cli.Arg("-s", "--start", required=True,
+                        help="timestamp the maintenance starts (RFC 3339 format)"),
+                cli.Arg("-e", "--end", required=True,
+                        help="timestamp the maintenance ends (RFC 3339 format)"),
+                cli.Arg("--block-submissions", action="store_true",
+                        help="reject new experiments and tasks during the maintenance"),
+            ]),
+            cli.Cmd("delete", delete_maintenance_window,
+                    "delete a maintenance window, ending it early if it is underway", [
+                        cli.Arg("window_id", type=int, help="ID of the maintenance window"),
+                    ]),
+        ]),
+        cli.Cmd("boost-request", None, "review requests to boost experiments", [
+            cli.Cmd("list ls", list_boost_requests, "list boost requests", [
+                cli.Arg("--state", choices=["pending", "approved", "denied", "expired", "all"],
+                        default="pending", help="only list requests in this state"),
+                cli.Arg("--json", action="store_true", help="print as JSON"),
+            ], is_default=True),
+            cli.Cmd("approve", approve_boost_request,
+                    "approve a boost request, applying the boost for its duration", [
+                        cli.Arg("request_id", type=int, help="ID of the boost request"),
+                        cli.Arg("--comment", type=str, help="note for the requester"),
+                    ]),
+            cli.Cmd("deny", deny_boost_request, "deny a boost request", [
+                cli.Arg("request_id", type=int, help="ID of the boost request"),
+                cli.Arg("--comment", type=str, help="note for the requester"),
+            ]),
+        ]),
     ])
 ]  # type: List[Any]
 
@@ -1,10 +1,12 @@
 import argparse
 import json
-from typing import Any, List
+import pathlib
+from typing import Any, List, Sequence
 
 from determined import cli
-from determined.cli import render, workspace
-from determined.common import api
+from determined.cli import errors, render, workspace
+from determined.common import api, context
+from determined.common.api import bindings
 from determined.experimental import client
 
 
@@ -159,6 +161,288 @@ def register_version(args: argparse.Namespace) -> None:
         _render_model_versions([model_version])
 
 
+def import_version(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    resp = bindings.post_PostModelVersionImport(
+        sess,
+        body=bindings.v1PostModelVersionImportRequest(
+            modelName=str(model.model_id),
+            path=args.path,
+            endpointUrl=args.endpoint_url,
+            checksum=args.checksum,
+            checkpointMetadata=json.loads(args.checkpoint_metadata or "{}"),
+            name=args.version_name or "",
+        ),
+        modelName=str(model.model_id),
+    )
+    if args.json:
+        render.print_json(resp.to_json())
+        return
+    version = resp.modelVersion.version
+    print(f"Imported checkpoint {resp.checkpointUuid} as version {version} of model {model.name}")
+    if resp.checksums:
+        render.tabulate_or_csv(["File", "Checksum"], sorted(resp.checksums.items()), False)
+
+
+def _render_evaluation_policies(policies: Sequence[bindings.v1ModelEvaluationPolicy]) -> None:
+    headers = ["ID", "Name", "Project ID", "On Register", "Schedule", "Metric", "Tolerance"]
+    values = []
+    for p in policies:
+        schedule = p.scheduleInterval or ""
+        if p.scheduleLabel:
+            schedule += f" ({p.scheduleLabel})"
+        direction = "smaller is better" if p.smallerIsBetter else "larger is better"
+        values.append(
+            [
+                p.id,
+                p.name,
+                p.projectId,
+                p.onRegister,
+                schedule,
+                f"{p.metric} ({direction})",
+                p.regressionTolerance,
+            ]
+        )
+    render.tabulate_or_csv(headers, values, False)
+
+
+def _render_evaluations(evaluations: Sequence[bindings.v1ModelEvaluation]) -> None:
+    headers = ["ID", "Version #", "Policy ID", "Trigger", "Experiment ID", "State", "Metric"]
+    headers += ["Regressed", "Error"]
+    values = []
+    for e in evaluations:
+        values.append(
+            [
+                e.id,
+                e.modelVersion,
+                e.policyId,
+                e.trigger.name.lower(),
+                e.experimentId,
+                e.state.name.lower(),
+                e.metricValue,
+                e.regressed,
+                e.error or "",
+            ]
+        )
+    render.tabulate_or_csv(headers, values, False)
+
+
+def list_evaluation_policies(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    policies = bindings.get_GetModelEvaluationPolicies(sess, modelName=str(model.model_id)).policies
+    if args.json:
+        render.print_json([p.to_json() for p in policies])
+    else:
+        _render_evaluation_policies(policies)
+
+
+def add_evaluation_policy(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    config_text = args.config_file.read()
+    args.config_file.close()
+    body = bindings.v1PostModelEvaluationPolicyRequest(
+        modelName=str(model.model_id),
+        name=args.policy_name,
+        config=config_text,
+        projectId=args.project_id or 0,
+        onRegister=not args.no_on_register,
+        scheduleInterval=args.schedule,
+        scheduleLabel=args.schedule_label,
+        metric=args.metric,
+        smallerIsBetter=not args.larger_is_better,
+        regressionTolerance=args.tolerance,
+    )
+    if args.model_def is not None:
+        body.modelDefinition = context.read_v1_context(args.model_def, args.include)
+    policy = bindings.post_PostModelEvaluationPolicy(
+        sess, body=body, modelName=str(model.model_id)
+    ).policy
+    if args.json:
+        render.print_json(policy.to_json())
+    else:
+        _render_evaluation_policies([policy])
+
+
+def remove_evaluation_policy(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    bindings.delete_DeleteModelEvaluationPolicy(
+        sess, modelName=str(model.model_id), policyId=args.policy_id
+    )
+    print(f"Removed evaluation policy {args.policy_id} of model {model.name}")
+
+
+def run_evaluation(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    evaluation = bindings.post_PostModelEvaluationPolicyRun(
+        sess,
+        body=bindings.v1PostModelEvaluationPolicyRunRequest(
+            modelName=str(model.model_id), policyId=args.policy_id, version=args.version
+        ),
+        modelName=str(model.model_id),
+        policyId=args.policy_id,
+    ).evaluation
+    if args.json:
+        render.print_json(evaluation.to_json())
+    else:
+        _render_evaluations([evaluation])
+
+
+def list_evaluations(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    evaluations = bindings.get_GetModelVersionEvaluations(
+        sess, modelName=str(model.model_id), modelVersionNum=args.version
+    ).evaluations
+    if args.json:
+        render.print_json([e.to_json() for e in evaluations])
+    else:
+        _render_evaluations(evaluations)
+
+
+def _render_promotion_gates(results: Sequence[bindings.v1ModelPromotionGateResult]) -> None:
+    headers = ["ID", "Policy ID", "Gate", "Evaluation ID", "Value", "Passed", "Reason"]
+    values = []
+    for r in results:
+        g = r.gate
+        values.append(
+            [
+                g.id,
+                g.policyId,
+                f"{g.metric} {g.comparison} {g.threshold}",
+                r.evaluationId,
+                r.value,
+                r.passed,
+                r.reason or "",
+            ]
+        )
+    render.tabulate_or_csv(headers, values, False)
+
+
+def list_promotion_gates(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    gates = bindings.get_GetModelPromotionGates(sess, modelName=str(model.model_id)).gates
+    if args.json:
+        render.print_json([g.to_json() for g in gates])
+        return
+    headers = ["ID", "Policy ID", "Metric", "Comparison", "Threshold", "Created By"]
+    values = [[g.id, g.policyId, g.metric, g.comparison, g.threshold, g.createdBy] for g in gates]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def add_promotion_gate(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    body = bindings.v1PostModelPromotionGateRequest(
+        modelName=str(model.model_id),
+        policyId=args.policy_id,
+        metric=args.metric,
+        comparison=args.comparison,
+        threshold=args.threshold,
+    )
+    gate = bindings.post_PostModelPromotionGate(sess, body=body, modelName=str(model.model_id)).gate
+    if args.json:
+        render.print_json(gate.to_json())
+    else:
+        print(
+            f"Added promotion gate {gate.id} to model {model.name}: {gate.metric} "
+            f"{gate.comparison} {gate.threshold} in evaluations by policy {gate.policyId}"
+        )
+
+
+def remove_promotion_gate(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    bindings.delete_DeleteModelPromotionGate(
+        sess, modelName=str(model.model_id), gateId=args.gate_id
+    )
+    print(f"Removed promotion gate {args.gate_id} of model {model.name}")
+
+
+def check_promotion_gates(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    check = bindings.get_GetModelVersionPromotionGates(
+        sess, modelName=str(model.model_id), modelVersionNum=args.version
+    )
+    if args.json:
+        render.print_json(check.to_json())
+        return
+    _render_promotion_gates(check.gates)
+    passed = "passes" if check.passed else "doesn't pass"
+    print(f"Version {args.version} of model {model.name} {passed} its promotion gates")
+
+
+def list_promotion_overrides(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    overrides = bindings.get_GetModelPromotionOverrides(
+        sess, modelName=str(model.model_id)
+    ).overrides
+    if args.json:
+        render.print_json([o.to_json() for o in overrides])
+        return
+    headers = ["ID", "Version #", "User ID", "Reason", "Unmet Gates", "Time"]
+    values = [
+        [o.id, o.modelVersion, o.userId, o.reason, len(o.unmetGates), o.createdAt]
+        for o in overrides
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def list_promotion_gate_changes(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    changes = bindings.get_GetModelPromotionGateChanges(sess, modelName=str(model.model_id)).changes
+    if args.json:
+        render.print_json([c.to_json() for c in changes])
+        return
+    headers = ["ID", "Action", "Gate ID", "Policy ID", "Gate", "User ID", "Time"]
+    values = [
+        [
+            c.id,
+            c.action.name.lower(),
+            c.gateId,
+            c.policyId,
+            f"{c.metric} {c.comparison} {c.threshold}",
+            c.userId,
+            c.createdAt,
+        ]
+        for c in changes
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def promote(args: argparse.Namespace) -> None:
+    if args.override and not args.reason:
+        raise errors.CliError("--reason is required to override promotion gates")
+    sess = cli.setup_session(args)
+    model = model_by_name(sess, args.name)
+    resp = bindings.post_PostModelVersionPromote(
+        sess,
+        body=bindings.v1PostModelVersionPromoteRequest(
+            modelName=str(model.model_id),
+            modelVersionNum=args.version,
+            override=args.override,
+            reason=args.reason or "",
+        ),
+        modelName=str(model.model_id),
+        modelVersionNum=args.version,
+    )
+    if args.json:
+        render.print_json(resp.to_json())
+        return
+    print(f"Promoted version {args.version} of model {model.name} to production")
+    if resp.override is not None:
+        unmet = len(resp.override.unmetGates)
+        print(f"Overrode {unmet} unmet promotion gates (override {resp.override.id})")
+
+
 args_description = [
     cli.Cmd(
         "m|odel",
@@ -206,6 +490,32 @@ args_description = [
                     cli.Arg("--json", action="store_true", help="print as JSON"),
                 ],
             ),
+            cli.Cmd(
+                "import-version",
+                import_version,
+                "register a checkpoint in S3 that Determined didn't save as a new version",
+                [
+                    cli.Arg("name", type=str, help="name of the model"),
+                    cli.Arg(
+                        "path",
+                        type=str,
+                        help="path of the checkpoint, like s3://bucket/prefix/<checkpoint uuid>",
+                    ),
+                    cli.Arg("--endpoint-url", type=str, help="endpoint of S3-compatible storage"),
+                    cli.Arg(
+                        "--checksum",
+                        action="store_true",
+                        help="record the SHA-256 checksums of the files of the checkpoint",
+                    ),
+                    cli.Arg(
+                        "--checkpoint-metadata",
+                        type=str,
+                        help="metadata of the checkpoint, as a JSON object",
+                    ),
+                    cli.Arg("--version-name", type=str, help="name of the new version"),
+                    cli.Arg("--json", action="store_true", help="print as JSON"),
+                ],
+            ),
             cli.Cmd(
                 "describe",
                 describe,
@@ -258,6 +568,219 @@ args_description = [
                     workspace.workspace_arg,
                 ],
             ),
+            cli.Cmd(
+                "evaluation",
+                None,
+                "manage evaluation experiments that run automatically against model versions",
+                [
+                    cli.Cmd(
+                        "list ls",
+                        list_evaluation_policies,
+                        "list the evaluation policies of a model",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                        is_default=True,
+                    ),
+                    cli.Cmd(
+                        "add",
+                        add_evaluation_policy,
+                        "run an evaluation experiment against new versions of a model, or its "
+                        "latest version on a schedule",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("policy_name", type=str, help="name of the evaluation policy"),
+                            cli.Arg(
+                                "config_file",
+                                type=argparse.FileType("r"),
+                                help="experiment config file (.yaml) of the evaluation",
+                            ),
+                            cli.Arg(
+                                "model_def",
+                                nargs="?",
+                                default=None,
+                                type=pathlib.Path,
+                                help="file or directory containing the evaluation's model "
+                                "definition",
+                            ),
+                            cli.Arg(
+                                "-i",
+                                "--include",
+                                default=[],
+                                action="append",
+                                type=pathlib.Path,
+                                help="additional files to copy into the task container",
+                            ),
+                            cli.Arg(
+                                "--metric",
+                                type=str,
+                                required=True,
+                                help="validation metric to compare between evaluations",
+                            ),
+                            cli.Arg(
+                                "--larger-is-better",
+                                action="store_true",
+                                help="larger values of the metric are better",
+                            ),
+                            cli.Arg(
+                                "--tolerance",
+                                type=float,
+                                default=0.0,
+                                help="how much worse than the last evaluation, relative to it, "
+                                "an evaluation can be without counting as a regression",
+                            ),
+                            cli.Arg(
+                                "--schedule",
+                                type=str,
+                                help="also evaluate the latest version this often, e.g. 24h",
+                            ),
+                            cli.Arg(
+                                "--schedule-label",
+                                type=str,
+                                help="evaluate the latest version with this label, e.g. "
+                                "production, on the schedule",
+                            ),
+                            cli.Arg(
+                                "--no-on-register",
+                                action="store_true",
+                                help="don't evaluate new versions when they're registered",
+                            ),
+                            cli.Arg(
+                                "--project-id",
+                                type=int,
+                                help="project to create evaluation experiments in",
+                            ),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "remove rm",
+                        remove_evaluation_policy,
+                        "remove an evaluation policy of a model",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("policy_id", type=int, help="ID of the evaluation policy"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "run",
+                        run_evaluation,
+                        "evaluate a version of a model now",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("policy_id", type=int, help="ID of the evaluation policy"),
+                            cli.Arg(
+                                "--version",
+                                type=int,
+                                help="version to evaluate, by default the latest",
+                            ),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "results",
+                        list_evaluations,
+                        "list the evaluations of a model version",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("version", type=int, help="version of the model"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                    ),
+                ],
+            ),
+            cli.Cmd(
+                "gate",
+                None,
+                "manage the evaluation metric gates versions of a model must pass to be "
+                "promoted to production",
+                [
+                    cli.Cmd(
+                        "list ls",
+                        list_promotion_gates,
+                        "list the promotion gates of a model",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                        is_default=True,
+                    ),
+                    cli.Cmd(
+                        "add",
+                        add_promotion_gate,
+                        "require a metric of the latest evaluation by a policy to pass a "
+                        "threshold to promote versions of a model to production",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("policy_id", type=int, help="ID of the evaluation policy"),
+                            cli.Arg("metric", type=str, help="name of the evaluation metric"),
+                            cli.Arg(
+                                "comparison",
+                                choices=[">=", ">", "<=", "<"],
+                                help="how the metric is compared with the threshold",
+                            ),
+                            cli.Arg("threshold", type=float, help="threshold of the metric"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "remove rm",
+                        remove_promotion_gate,
+                        "remove a promotion gate of a model",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("gate_id", type=int, help="ID of the promotion gate"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "check",
+                        check_promotion_gates,
+                        "check whether a model version passes the promotion gates of its model",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("version", type=int, help="version of the model"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "overrides",
+                        list_promotion_overrides,
+                        "list the promotions of versions of a model that overrode its gates",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "changes",
+                        list_promotion_gate_changes,
+                        "list who added and removed the promotion gates of a model",
+                        [
+                            cli.Arg("name", type=str, help="name of model"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                    ),
+                ],
+            ),
+            cli.Cmd(
+                "promote",
+                promote,
+                "promote a model version to production if it passes the promotion gates of "
+                "its model",
+                [
+                    cli.Arg("name", type=str, help="name of model"),
+                    cli.Arg("version", type=int, help="version of the model"),
+                    cli.Arg(
+                        "--override",
+                        action="store_true",
+                        help="promote the version even if it doesn't pass the gates, which "
+                        "requires permission to override them",
+                    ),
+                    cli.Arg("--reason", type=str, help="why the gates are overridden"),
+                    cli.Arg("--json", action="store_true", help="print as JSON"),
+                ],
+            ),
         ],
     )
 ]  # type: List[Any]
@@ -53,6 +53,7 @@ the command container.
 
 _CONFIG_PATHS_COERCE_TO_LIST = {
     "bind_mounts",
+    "outputs",
 }
 
 TASK_ID_REGEX = re.compile(
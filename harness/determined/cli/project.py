@@ -180,6 +180,55 @@ def unarchive_project(args: argparse.Namespace) -> None:
     print(f"Successfully un-archived project {args.project_name}.")
 
 
+def experiment_naming(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    (w, p) = project_by_name(sess, args.workspace_name, args.project_name)
+    naming = bindings.get_GetProjectExperimentNaming(sess, projectId=p.id).naming
+    if args.unique_names is not None or args.name_template or args.clear_name_template:
+        body = bindings.v1PutProjectExperimentNamingRequest(
+            projectId=p.id, uniqueNames=naming.uniqueNames, nameTemplate=naming.nameTemplate
+        )
+        if args.unique_names is not None:
+            body.uniqueNames = args.unique_names == "true"
+        if args.name_template:
+            body.nameTemplate = args.name_template
+        if args.clear_name_template:
+            body.nameTemplate = None
+        naming = bindings.put_PutProjectExperimentNaming(sess, body=body, projectId=p.id).naming
+
+    if args.json:
+        render.print_json(naming.to_json())
+        return
+    headers = ["Unique Names", "Name Template", "Next {seq}"]
+    values = [[naming.uniqueNames, naming.nameTemplate, naming.nextSeq]]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def pin_project(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    (w, p) = project_by_name(sess, args.workspace_name, args.project_name)
+    bindings.put_PutProjectPin(sess, projectId=p.id)
+    print(f"Successfully pinned project {args.project_name}.")
+
+
+def unpin_project(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    (w, p) = project_by_name(sess, args.workspace_name, args.project_name)
+    bindings.delete_DeleteProjectPin(sess, projectId=p.id)
+    print(f"Successfully unpinned project {args.project_name}.")
+
+
+def list_pinned_projects(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    pinned = bindings.get_GetPins(sess).projects
+    if args.json:
+        render.print_json([p.to_json() for p in pinned])
+        return
+    headers = ["ID", "Name", "Workspace", "Archived"]
+    values = [[p.id, p.name, p.workspaceName, p.archived] for p in pinned]
+    render.tabulate_or_csv(headers, values, False)
+
+
 args_description = [
     cli.Cmd(
         "p|roject",
@@ -287,6 +336,60 @@ args_description = [
                     cli.Arg("project_name", type=str, help="name of the project"),
                 ],
             ),
+            cli.Cmd(
+                "naming",
+                experiment_naming,
+                "show or set how experiments in the project are named",
+                [
+                    cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                    cli.Arg("project_name", type=str, help="name of the project"),
+                    cli.Arg(
+                        "--unique-names",
+                        choices=["true", "false"],
+                        help="whether experiment names must be unique in the project",
+                    ),
+                    cli.Group(
+                        cli.Arg(
+                            "--name-template",
+                            type=str,
+                            help="template naming experiments whose config omits a name, "
+                            "using {user}, {project}, {workspace}, {date}, {time} and {seq}",
+                        ),
+                        cli.Arg(
+                            "--clear-name-template",
+                            action="store_true",
+                            help="stop naming experiments from a template",
+                        ),
+                    ),
+                    cli.Arg("--json", action="store_true", help="print as JSON"),
+                ],
+            ),
+            cli.Cmd(
+                "pin",
+                pin_project,
+                "pin project so it is listed first",
+                [
+                    cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                    cli.Arg("project_name", type=str, help="name of the project"),
+                ],
+            ),
+            cli.Cmd(
+                "unpin",
+                unpin_project,
+                "unpin project",
+                [
+                    cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                    cli.Arg("project_name", type=str, help="name of the project"),
+                ],
+            ),
+            cli.Cmd(
+                "pinned",
+                list_pinned_projects,
+                "list pinned projects in order",
+                [
+                    cli.Arg("--json", action="store_true", help="print as JSON"),
+                ],
+            ),
             cli.Cmd(
                 "describe",
                 describe_project,
@@ -0,0 +1,128 @@
+import argparse
+import pathlib
+import webbrowser
+from typing import Any, List
+
+from determined import cli
+from determined.cli import ntsc, render, task
+from determined.common import context, util
+from determined.common.api import bindings
+
+
+def start_cluster(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    config = ntsc.parse_config(args.config_file, None, args.config, [])
+    # A generic task runs a Ray cluster when its config has a "ray" section, even an empty one.
+    if config.get("ray") is None:
+        config["ray"] = {}
+    context_directory = context.read_v1_context(args.context, args.include)
+
+    req = bindings.v1CreateGenericTaskRequest(
+        config=util.yaml_safe_dump(config),
+        contextDirectory=context_directory,
+        projectId=args.project_id,
+    )
+    task_resp = bindings.post_CreateGenericTask(sess, body=req)
+    task.task_creation_output(session=sess, task_resp=task_resp, follow=args.follow)
+
+
+def list_clusters(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    clusters = bindings.get_GetRayClusters(sess).clusters
+    if args.json:
+        render.print_json([c.to_json() for c in clusters])
+        return
+
+    headers = ["Task ID", "Workspace ID", "Start Time", "Healthy", "Alive Nodes", "Idle"]
+    values = []
+    for c in clusters:
+        alive_nodes = f"{c.health.aliveNodes}/{c.health.expectedNodes}" if c.health else "0/?"
+        values.append(
+            [
+                c.taskId,
+                c.workspaceId,
+                c.startTime,
+                c.healthy,
+                alive_nodes,
+                c.health.idle if c.health else "",
+            ]
+        )
+    render.tabulate_or_csv(headers, values, False)
+
+
+def open_dashboard(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    # Fails if the task isn't a running Ray cluster.
+    bindings.get_GetRayCluster(sess, taskId=args.task_id)
+    url = f"{args.master}/proxy/{args.task_id}/"
+    print(f"The Ray dashboard is at: {url}")
+    webbrowser.open(url)
+
+
+def kill_cluster(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    req = bindings.v1KillGenericTaskRequest(taskId=args.task_id)
+    bindings.post_KillGenericTask(sess, taskId=args.task_id, body=req)
+    print(f"Killed Ray cluster {args.task_id}.")
+
+
+args_description: List[Any] = [
+    cli.Cmd(
+        "ray",
+        None,
+        "manage Ray clusters",
+        [
+            cli.Cmd(
+                "list ls",
+                list_clusters,
+                "list running Ray clusters",
+                [cli.Group(cli.output_format_args["json"])],
+                is_default=True,
+            ),
+            cli.Cmd(
+                "start",
+                start_cluster,
+                "start a Ray cluster",
+                [
+                    cli.Arg(
+                        "config_file",
+                        type=argparse.FileType("r"),
+                        nargs="?",
+                        help="task config file (.yaml)",
+                    ),
+                    cli.Arg("--context", "-c", type=pathlib.Path, help=ntsc.CONTEXT_DESC),
+                    cli.Arg(
+                        "-i",
+                        "--include",
+                        action="append",
+                        default=[],
+                        type=pathlib.Path,
+                        help=ntsc.INCLUDE_DESC,
+                    ),
+                    cli.Arg(
+                        "--project_id", type=int, help="place this cluster inside this project"
+                    ),
+                    cli.Arg("--config", action="append", default=[], help=ntsc.CONFIG_DESC),
+                    cli.Arg(
+                        "-f",
+                        "--follow",
+                        action="store_true",
+                        help="follow the logs of the cluster's task",
+                    ),
+                ],
+            ),
+            cli.Cmd(
+                "dashboard",
+                open_dashboard,
+                "open the dashboard of a Ray cluster",
+                [cli.Arg("task_id", help="task ID of the Ray cluster")],
+            ),
+            cli.Cmd(
+                "kill",
+                kill_cluster,
+                "kill a Ray cluster",
+                [cli.Arg("task_id", help="task ID of the Ray cluster")],
+            ),
+        ],
+    )
+]
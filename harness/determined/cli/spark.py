@@ -0,0 +1,94 @@
+import argparse
+import pathlib
+from typing import Any, Dict, List
+
+from determined import cli
+from determined.cli import ntsc, task
+from determined.common import context, util
+from determined.common.api import bindings
+
+
+def parse_spark_conf(conf_args: List[str]) -> Dict[str, str]:
+    conf = {}
+    for arg in conf_args:
+        if "=" not in arg:
+            raise ValueError(f"Could not read Spark property '{arg}', expecting KEY=VALUE")
+        key, value = arg.split("=", maxsplit=1)
+        conf[key] = value
+    return conf
+
+
+def submit(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    config = ntsc.parse_config(args.config_file, None, args.config, [])
+    spark = config.setdefault("spark", {})
+    spark["application"] = args.application
+    if args.app_args:
+        spark["args"] = args.app_args
+    if args.main_class:
+        spark["main_class"] = args.main_class
+    if args.spark_conf:
+        spark["conf"] = {**spark.get("conf", {}), **parse_spark_conf(args.spark_conf)}
+    context_directory = context.read_v1_context(args.context, args.include)
+
+    req = bindings.v1CreateGenericTaskRequest(
+        config=util.yaml_safe_dump(config),
+        contextDirectory=context_directory,
+        projectId=args.project_id,
+    )
+    task_resp = bindings.post_CreateGenericTask(sess, body=req)
+    task.task_creation_output(session=sess, task_resp=task_resp, follow=args.follow)
+
+
+args_description: List[Any] = [
+    cli.Cmd(
+        "spark",
+        None,
+        "manage Spark jobs",
+        [
+            cli.Cmd(
+                "submit",
+                submit,
+                "submit a Spark job, whose driver runs as a task",
+                [
+                    cli.Arg("application", help="path or URI of the application's jar or .py file"),
+                    cli.Arg(
+                        "app_args",
+                        nargs=argparse.REMAINDER,
+                        help="arguments to the application",
+                    ),
+                    cli.Arg("--class", dest="main_class", help="the application's main class"),
+                    cli.Arg(
+                        "--spark-conf",
+                        action="append",
+                        default=[],
+                        help="a Spark property for the job, as KEY=VALUE",
+                    ),
+                    cli.Arg(
+                        "--config-file",
+                        dest="config_file",
+                        type=argparse.FileType("r"),
+                        help="task config file (.yaml)",
+                    ),
+                    cli.Arg("--context", "-c", type=pathlib.Path, help=ntsc.CONTEXT_DESC),
+                    cli.Arg(
+                        "-i",
+                        "--include",
+                        action="append",
+                        default=[],
+                        type=pathlib.Path,
+                        help=ntsc.INCLUDE_DESC,
+                    ),
+                    cli.Arg("--project_id", type=int, help="place this job inside this project"),
+                    cli.Arg("--config", action="append", default=[], help=ntsc.CONFIG_DESC),
+                    cli.Arg(
+                        "-f",
+                        "--follow",
+                        action="store_true",
+                        help="follow the logs of the job's driver",
+                    ),
+                ],
+            ),
+        ],
+    )
+]
@@ -336,6 +336,22 @@ def create_json_file_in_dir(content: Any, file_path: str) -> None:
         json.dump(content, f)
 
 
+def write_metrics_export(sess: api.Session, path: str, name: str, args: argparse.Namespace) -> None:
+    params = {"format": args.format, "group": args.group}
+    output = args.output or f"{name}.{args.format}"
+    r = sess.get(path, params=params, stream=True)
+    with open(output, "wb") as f:
+        for chunk in r.iter_content(chunk_size=64 * 1024):
+            f.write(chunk)
+    print(f"Wrote metrics to {output}")
+
+
+def export_metrics(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    path = f"trials/{args.trial_id}/metrics/export"
+    write_metrics_export(sess, path, f"trial{args.trial_id}_metrics", args)
+
+
 logs_args_description: cli.ArgsDescription = [
     cli.Arg(
         "-f",
@@ -406,6 +422,17 @@ logs_args_description: cli.ArgsDescription = [
     ),
 ]
 
+metrics_export_args_description: cli.ArgsDescription = [
+    cli.Arg(
+        "--format",
+        choices=["ndjson", "parquet"],
+        default="ndjson",
+        help="file format; Parquet files have a row for each numeric metric of each report",
+    ),
+    cli.Arg("--group", type=str, help="only export this metric group, e.g. training"),
+    cli.Arg("-o", "--output", type=str, help="file to write to (default is named after the IDs)"),
+]
+
 args_description: cli.ArgsDescription = [
     cli.Cmd(
         "t|rial",
@@ -517,6 +544,15 @@ args_description: cli.ArgsDescription = [
                     *logs_args_description,
                 ],
             ),
+            cli.Cmd(
+                "export-metrics",
+                export_metrics,
+                "export the full metric history of a trial as ndjson or Parquet",
+                [
+                    cli.Arg("trial_id", type=int, help="trial ID"),
+                    *metrics_export_args_description,
+                ],
+            ),
             cli.Cmd(
                 "kill",
                 kill_trial,
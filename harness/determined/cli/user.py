@@ -218,6 +218,40 @@ def edit(args: argparse.Namespace) -> None:
         raise errors.CliError("No field provided. Use 'det user edit -h' for usage.")
 
 
+def reassign(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    d = client.Determined._from_session(sess)
+    from_user = d.get_user_by_name(args.username)
+    to_user = d.get_user_by_name(args.to_username)
+    body = bindings.v1PostUserReassignmentRequest(
+        userId=from_user.user_id, toUserId=to_user.user_id, dryRun=args.dry_run
+    )
+    if args.to_group:
+        body.toGroupId = api.group_name_to_group_id(sess, args.to_group)
+    res = bindings.post_PostUserReassignment(sess, body=body, userId=from_user.user_id).reassignment
+
+    if args.json:
+        render.print_json(res.to_json())
+        return
+    kinds = [
+        (res.experiments, "Experiment"),
+        (res.models, "Model"),
+        (res.modelVersions, "Model Version"),
+        (res.workspaces, "Workspace"),
+        (res.projects, "Project"),
+    ]
+    values = [[kind, r.id, r.name] for resources, kind in kinds for r in resources]
+    values += [["Task", t.id, t.description] for t in res.tasks]
+    for a in res.roleAssignments:
+        scope = f"workspace {a.workspaceId}" if a.workspaceId else "global"
+        values.append(["Role", a.roleName, scope])
+    render.tabulate_or_csv(["Kind", "ID", "Name"], values, False)
+    if args.dry_run:
+        print(f"Dry run: nothing was reassigned from {args.username} to {args.to_username}.")
+    else:
+        print(f"Reassigned {len(values)} resources from {args.username} to {args.to_username}.")
+
+
 AGENT_USER_GROUP_ARGS = [
     cli.Arg("--agent-uid", type=int, help="UID on the agent to run tasks as"),
     cli.Arg("--agent-user", help="user on the agent to run tasks as"),
@@ -280,6 +314,17 @@ args_description = [
             *AGENT_USER_GROUP_ARGS,
         ]),
         cli.Cmd("whoami", whoami, "print the active user", []),
+        cli.Cmd("reassign", reassign, "give what a user owns to another user", [
+            cli.Arg("username", help="name of user to reassign resources from"),
+            cli.Arg("to_username", help="name of user to reassign resources to"),
+            cli.Arg("--to-group", help="name of group to give the user's roles to"),
+            cli.Arg(
+                "--dry-run",
+                action="store_true",
+                help="list what would be reassigned without reassigning it",
+            ),
+            cli.Arg("--json", action="store_true", help="print as JSON"),
+        ]),
         cli.Cmd("edit", edit, "edit user fields", [
             cli.Arg(
                 "target_user",
@@ -90,11 +90,15 @@ def render_workspaces(
         ]
         if not from_list_api:
             value.append(w.checkpointStorageConfig)
+        value.append(util.sizeof_fmt(int(w.storageBytes or 0)))
+        for quota in (w.softStorageQuotaBytes, w.hardStorageQuotaBytes):
+            value.append(util.sizeof_fmt(int(quota)) if quota is not None else None)
         values.append(value)
 
     headers = WORKSPACE_HEADERS
     if not from_list_api:
         headers = WORKSPACE_HEADERS + ["Checkpoint Storage Config"]
+    headers = headers + ["Storage Used", "Soft Storage Quota", "Hard Storage Quota"]
     render.tabulate_or_csv(headers, values, False)
 
 
@@ -286,6 +290,147 @@ def set_resource_quota(args: argparse.Namespace) -> None:
     return None
 
 
+def list_api_keys(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    w = api.workspace_by_name(sess, args.workspace_name)
+    keys = bindings.get_GetWorkspaceAPIKeys(sess, workspaceId=w.id).keys
+    if args.json:
+        render.print_json([k.to_json() for k in keys])
+        return
+
+    headers = ["ID", "Name", "Key Prefix", "Created", "Expires", "Last Used", "Revoked"]
+    values = [
+        [k.id, k.name, k.keyPrefix, k.createdAt, k.expiresAt, k.lastUsedAt, k.revokedAt]
+        for k in keys
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def _describe_event(event: bindings.v1WorkspaceEvent) -> str:
+    details = event.details
+    kind = event.kind
+    if kind in (
+        bindings.v1WorkspaceEventKind.EXPERIMENT_CREATED,
+        bindings.v1WorkspaceEventKind.EXPERIMENT_COMPLETED,
+        bindings.v1WorkspaceEventKind.EXPERIMENT_FAILED,
+    ):
+        return f"experiment {event.experimentId} ({details.get('name')})"
+    if kind == bindings.v1WorkspaceEventKind.MODEL_REGISTERED:
+        return f"model {event.modelId} ({details.get('name')})"
+    member = details.get("username") or details.get("group_name")
+    return f"{member} as {details.get('role')}"
+
+
+def list_activity(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    w = api.workspace_by_name(sess, args.workspace_name)
+    kinds = [bindings.v1WorkspaceEventKind[k.upper()] for k in args.kind or []]
+    page = bindings.get_GetWorkspaceActivity(
+        sess,
+        workspaceId=w.id,
+        kinds=kinds or None,
+        limit=args.limit,
+        beforeId=None if args.before_id is None else str(args.before_id),
+    )
+    if args.json:
+        render.print_json(page.to_json())
+        return
+
+    headers = ["ID", "Time", "Event", "By", "Subject"]
+    values = [
+        [e.id, e.createdAt, e.kind.name.lower(), e.actorUsername, _describe_event(e)]
+        for e in page.events
+    ]
+    render.tabulate_or_csv(headers, values, False)
+    if page.nextBeforeId is not None:
+        print(f"More events are available with --before-id {page.nextBeforeId}.")
+
+
+def create_api_key(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    w = api.workspace_by_name(sess, args.workspace_name)
+    body = bindings.v1PostWorkspaceAPIKeyRequest(
+        name=args.name, workspaceId=w.id, expiresInDays=args.expires_in_days
+    )
+    created = bindings.post_PostWorkspaceAPIKey(sess, body=body, workspaceId=w.id)
+    if args.json:
+        render.print_json(created.to_json())
+        return
+    print(f"Created read-only API key {created.apiKey.id} for workspace {w.name}:")
+    print(created.key)
+    print("Store it now: it can't be shown again.")
+
+
+def revoke_api_key(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    w = api.workspace_by_name(sess, args.workspace_name)
+    bindings.delete_DeleteWorkspaceAPIKey(sess, keyId=args.key_id, workspaceId=w.id)
+    print(f"Revoked API key {args.key_id} of workspace {w.name}.")
+
+
+def list_env_var_sets(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    w = api.workspace_by_name(sess, args.workspace_name)
+    sets = bindings.get_GetWorkspaceEnvVarSets(sess, workspaceId=w.id).envVarSets
+    if args.json:
+        render.print_json([s.to_json() for s in sets])
+        return
+
+    headers = ["Name", "Variables", "Description", "Updated"]
+    values = [
+        [
+            s.name,
+            "\n".join(f"{k}={v}" for k, v in sorted(s.variables.items())),
+            s.description,
+            s.updatedAt,
+        ]
+        for s in sets
+    ]
+    render.tabulate_or_csv(headers, values, False)
+
+
+def set_env_var_set(args: argparse.Namespace) -> None:
+    variables = {}
+    for var in args.variables:
+        key, sep, value = var.partition("=")
+        if not sep:
+            raise cli.errors.CliError(f"{var} isn't of the form KEY=VALUE")
+        variables[key] = value
+    sess = cli.setup_session(args)
+    w = api.workspace_by_name(sess, args.workspace_name)
+    body = bindings.v1PutWorkspaceEnvVarSetRequest(
+        name=args.name,
+        workspaceId=w.id,
+        variables=variables,
+        description=args.description,
+    )
+    bindings.put_PutWorkspaceEnvVarSet(sess, body=body, name=args.name, workspaceId=w.id)
+    print(f"Saved environment variable set {args.name} of workspace {w.name}.")
+
+
+def remove_env_var_set(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    w = api.workspace_by_name(sess, args.workspace_name)
+    bindings.delete_DeleteWorkspaceEnvVarSet(sess, name=args.name, workspaceId=w.id)
+    print(f"Removed environment variable set {args.name} of workspace {w.name}.")
+
+
+def duplicate_experiment_policy(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    w = api.workspace_by_name(sess, args.workspace_name)
+    if args.policy is not None:
+        bindings.put_PutWorkspaceDuplicateExperimentPolicy(
+            sess,
+            body=bindings.v1PutWorkspaceDuplicateExperimentPolicyRequest(
+                workspaceId=w.id,
+                policy=bindings.v1DuplicateExperimentPolicy[args.policy.upper()],
+            ),
+            workspaceId=w.id,
+        )
+    policy = bindings.get_GetWorkspaceDuplicateExperimentPolicy(sess, workspaceId=w.id).policy
+    print(f"Duplicate experiment policy of workspace {w.name}: {policy.name.lower()}")
+
+
 def _parse_agent_user_group_args(args: argparse.Namespace) -> Optional[bindings.v1AgentUserGroup]:
     if args.agent_uid or args.agent_gid or args.agent_user or args.agent_group:
         return bindings.v1AgentUserGroup(
@@ -756,6 +901,129 @@ args_description = [
                     ),
                 ],
             ),
+            cli.Cmd(
+                "activity",
+                list_activity,
+                "show the feed of notable events in a workspace, newest first",
+                [
+                    cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                    cli.Arg(
+                        "--kind",
+                        action="append",
+                        choices=[
+                            "experiment_created",
+                            "experiment_completed",
+                            "experiment_failed",
+                            "model_registered",
+                            "member_added",
+                        ],
+                        help="only show events of this kind (repeat for multiple values)",
+                    ),
+                    cli.Arg("--limit", type=int, default=50, help="number of events to show"),
+                    cli.Arg("--before-id", type=int, help="only show events before this ID"),
+                    cli.Arg("--json", action="store_true", help="print as JSON"),
+                ],
+            ),
+            cli.Cmd(
+                "api-keys",
+                None,
+                "manage read-only API keys for external dashboards",
+                [
+                    cli.Cmd(
+                        "list ls",
+                        list_api_keys,
+                        "list the API keys of a workspace",
+                        [
+                            cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                        is_default=True,
+                    ),
+                    cli.Cmd(
+                        "create",
+                        create_api_key,
+                        "create a read-only API key for a workspace",
+                        [
+                            cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                            cli.Arg("name", type=str, help="unique name of the key"),
+                            cli.Arg(
+                                "--expires-in-days",
+                                type=int,
+                                help="days until the key expires; by default it never expires",
+                            ),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "revoke",
+                        revoke_api_key,
+                        "revoke an API key of a workspace",
+                        [
+                            cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                            cli.Arg("key_id", type=int, help="ID of the key"),
+                        ],
+                    ),
+                ],
+            ),
+            cli.Cmd(
+                "env-vars",
+                None,
+                "manage named sets of environment variables that task configs in a workspace "
+                "refer to",
+                [
+                    cli.Cmd(
+                        "list ls",
+                        list_env_var_sets,
+                        "list the environment variable sets of a workspace",
+                        [
+                            cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                            cli.Arg("--json", action="store_true", help="print as JSON"),
+                        ],
+                        is_default=True,
+                    ),
+                    cli.Cmd(
+                        "set",
+                        set_env_var_set,
+                        "create or replace an environment variable set of a workspace",
+                        [
+                            cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                            cli.Arg("name", type=str, help="name of the set"),
+                            cli.Arg(
+                                "variables",
+                                nargs="+",
+                                metavar="KEY=VALUE",
+                                help="variables of the set, which replace its current ones",
+                            ),
+                            cli.Arg("--description", type=str, default="", help="description"),
+                        ],
+                    ),
+                    cli.Cmd(
+                        "remove rm",
+                        remove_env_var_set,
+                        "remove an environment variable set of a workspace",
+                        [
+                            cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                            cli.Arg("name", type=str, help="name of the set"),
+                        ],
+                    ),
+                ],
+            ),
+            cli.Cmd(
+                "duplicate-policy",
+                duplicate_experiment_policy,
+                "show or set what happens when an experiment identical to an existing one is "
+                "submitted to a workspace",
+                [
+                    cli.Arg("workspace_name", type=str, help="name of the workspace"),
+                    cli.Arg(
+                        "policy",
+                        nargs="?",
+                        choices=["off", "warn", "block"],
+                        help="off doesn't look for duplicates, warn creates the experiment and "
+                        "warns about them, and block doesn't create the experiment",
+                    ),
+                ],
+            ),
             cli.Cmd(
                 "archive",
                 archive_workspace,
@@ -25,7 +25,14 @@ WARNING_MESSAGE_MAP = {
     bindings.v1LaunchWarning.CURRENT_SLOTS_EXCEEDED: (
         "Warning: The requested job requires more slots than currently available. "
         "You may need to increase cluster resources in order for the job to run."
-    )
+    ),
+    bindings.v1LaunchWarning.DUPLICATE_EXPERIMENT: (
+        "Warning: The workspace already has experiments identical to this one."
+    ),
+    bindings.v1LaunchWarning.CURRENT_GPU_MEMORY_UNAVAILABLE: (
+        "Warning: No agent currently connected has GPUs with the memory requested. "
+        "The job will wait until one connects."
+    ),
 }
 
 
@@ -262,7 +262,8 @@ class Checkpoint:
             self._download_direct(checkpoint_storage, local_ckpt_dir)
 
         except (errors.NoDirectStorageAccess, FileNotFoundError):
-            if checkpoint_storage["type"] == "azure":
+            if checkpoint_storage["type"] in ("azure", "sftp"):
+                # The master can't download from these storage types either.
                 raise
 
             logger.info("Unable to download directly, proxying download through master")
@@ -302,11 +303,13 @@ class Checkpoint:
                     storage.S3StorageManager,
                     storage.GCSStorageManager,
                     storage.AzureStorageManager,
+                    storage.SFTPStorageManager,
+                    storage.HDFSStorageManager,
                 ),
             ):
                 raise AssertionError(
-                    "Downloading from Azure, S3 or GCS requires the experiment "
-                    "to be configured with Azure, S3 or GCS checkpointing"
+                    "Downloading from Azure, S3, GCS, SFTP or HDFS requires the experiment "
+                    "to be configured with Azure, S3, GCS, SFTP or HDFS checkpointing"
                     ", {} found instead".format(checkpoint_storage["type"])
                 )
 
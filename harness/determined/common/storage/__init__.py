@@ -7,7 +7,9 @@ from determined.common.storage.base import StorageManager, Paths, Selector, from
 from determined.common.storage.cloud import CloudStorageManager
 from determined.common.storage.azure import AzureStorageManager
 from determined.common.storage.gcs import GCSStorageManager
+from determined.common.storage.hdfs import HDFSStorageManager
 from determined.common.storage.s3 import S3StorageManager
+from determined.common.storage.sftp import SFTPStorageManager
 from determined.common.storage.shared import SharedFSStorageManager
 from determined.common.storage.directory import DirectoryStorageManager
 
@@ -16,7 +18,9 @@ __all__ = [
     "CloudStorageManager",
     "DirectoryStorageManager",
     "GCSStorageManager",
+    "HDFSStorageManager",
     "S3StorageManager",
+    "SFTPStorageManager",
     "SharedFSStorageManager",
     "StorageManager",
 ]
@@ -28,6 +32,8 @@ _STORAGE_MANAGERS = {
     "s3": S3StorageManager,
     "shared_fs": SharedFSStorageManager,
     "directory": DirectoryStorageManager,
+    "sftp": SFTPStorageManager,
+    "hdfs": HDFSStorageManager,
 }  # type: Dict[str, Type[StorageManager]]
 
 
@@ -169,6 +169,29 @@ class StorageManager(metaclass=abc.ABCMeta):
 
         return result
 
+    @staticmethod
+    def _verify_upload(
+        src: Union[str, os.PathLike], paths: Paths, uploaded: Dict[str, int], location: str
+    ) -> None:
+        """
+        Raises an IOError if any of the paths uploaded from `src` are missing from `uploaded`, the
+        listing of the storage they were uploaded to, or differ in size from the local files.
+        """
+        mismatched = []
+        for rel_path in sorted(paths):
+            if rel_path.endswith("/"):
+                if rel_path not in uploaded:
+                    mismatched.append(rel_path)
+                continue
+            size = os.path.getsize(os.path.join(src, rel_path))
+            if uploaded.get(rel_path) != size:
+                mismatched.append(rel_path)
+        if mismatched:
+            raise IOError(
+                f"upload to {location} could not be verified; missing or incomplete files: "
+                + ", ".join(mismatched)
+            )
+
     @staticmethod
     def _apply_globs_to_resources(
         file_paths_to_sizes: Dict[str, int],
@@ -0,0 +1,196 @@
+import logging
+import os
+import posixpath
+import tempfile
+from typing import Any, Dict, Iterator, List, Optional, Tuple, Union
+from urllib import parse
+
+import requests
+
+from determined import errors
+from determined.common import storage, util
+
+logger = logging.getLogger("determined.common.storage.hdfs")
+
+# The size of the chunks that files are streamed in when downloading them.
+_DOWNLOAD_CHUNK_SIZE = 8 * 1024 * 1024
+
+
+class HDFSStorageManager(storage.CloudStorageManager):
+    """
+    Store and load checkpoints on HDFS, through the WebHDFS REST API of its namenode, so that no
+    Hadoop client libraries are needed in task containers.
+
+    Requests are made as `username` with simple authentication, or as the default user of the
+    cluster if it is not set.
+    """
+
+    def __init__(
+        self,
+        url: str,
+        storage_path: str,
+        username: Optional[str] = None,
+        temp_dir: Optional[str] = None,
+    ) -> None:
+        super().__init__(temp_dir if temp_dir is not None else tempfile.gettempdir())
+        self.url = url.rstrip("/")
+        self.username = username
+        self.storage_path = posixpath.normpath(posixpath.join("/", storage_path))
+        self.session = requests.Session()
+
+    def get_storage_path(self, storage_id: str) -> str:
+        return posixpath.join(self.storage_path, storage_id)
+
+    def _request(self, method: str, path: str, op: str, **kwargs: Any) -> requests.Response:
+        params = {"op": op, **kwargs.pop("params", {})}
+        if self.username is not None:
+            params["user.name"] = self.username
+        url = f"{self.url}/webhdfs/v1{parse.quote(path)}"
+        try:
+            resp = self.session.request(method, url, params=params, **kwargs)
+        except requests.exceptions.ConnectionError as e:
+            raise errors.NoDirectStorageAccess(
+                f"Unable to access HDFS checkpoint storage at {self.url}"
+            ) from e
+
+        if resp.status_code == 404:
+            raise FileNotFoundError(path)
+        if resp.status_code in (401, 403):
+            raise errors.NoDirectStorageAccess(
+                f"Unable to access HDFS checkpoint storage at {self.url}: {resp.text}"
+            )
+        resp.raise_for_status()
+        return resp
+
+    def _list_status(self, root: str) -> Iterator[Tuple[str, Dict[str, Any]]]:
+        """
+        Yields the paths relative to `root` of the files and directories under it, directories
+        ending in "/", along with their WebHDFS file statuses. Raises FileNotFoundError if `root`
+        does not exist.
+        """
+        dirs = [""]
+        while dirs:
+            rel_dir = dirs.pop()
+            resp = self._request("GET", posixpath.join(root, rel_dir), "LISTSTATUS")
+            for status in resp.json()["FileStatuses"]["FileStatus"]:
+                rel_path = posixpath.join(rel_dir, status["pathSuffix"])
+                if status["type"] == "DIRECTORY":
+                    dirs.append(rel_path)
+                    rel_path += "/"
+                yield rel_path, status
+
+    def _walk(self, root: str) -> Dict[str, int]:
+        """
+        Returns the sizes of the files under `root`, like _list_directory does for local
+        directories. Raises FileNotFoundError if `root` does not exist.
+        """
+        return {
+            rel_path: 0 if rel_path.endswith("/") else status["length"]
+            for rel_path, status in self._list_status(root)
+        }
+
+    def _put_file(self, local_path: str, remote_path: str) -> None:
+        # Creating a file is two steps: the namenode redirects to the datanode to write it to.
+        resp = self._request(
+            "PUT",
+            remote_path,
+            "CREATE",
+            params={"overwrite": "true"},
+            allow_redirects=False,
+        )
+        location = resp.headers.get("Location")
+        with open(local_path, "rb") as f:
+            if location is None:
+                # Some gateways, like HttpFS, take the data on the first request.
+                self._request(
+                    "PUT",
+                    remote_path,
+                    "CREATE",
+                    params={"overwrite": "true", "data": "true"},
+                    data=f,
+                    headers={"Content-Type": "application/octet-stream"},
+                )
+                return
+            self.session.put(location, data=f).raise_for_status()
+
+    @util.preserve_random_state
+    def upload(
+        self, src: Union[str, os.PathLike], dst: str, paths: Optional[storage.Paths] = None
+    ) -> None:
+        src = os.fspath(src)
+        storage_path = self.get_storage_path(dst)
+        logger.info(f"Uploading to HDFS: {storage_path}")
+        upload_paths = paths if paths is not None else self._list_directory(src)
+        self._request("PUT", storage_path, "MKDIRS")
+        for rel_path in sorted(upload_paths):
+            remote_path = posixpath.join(storage_path, rel_path)
+            logger.debug(f"Uploading {rel_path} to HDFS: {remote_path}")
+            if rel_path.endswith("/"):
+                self._request("PUT", remote_path, "MKDIRS")
+            else:
+                self._put_file(os.path.join(src, rel_path), remote_path)
+
+        self._verify_upload(src, set(upload_paths), self._walk(storage_path), self.url)
+
+    @util.preserve_random_state
+    def download(
+        self,
+        src: str,
+        dst: Union[str, os.PathLike],
+        selector: Optional[storage.Selector] = None,
+    ) -> None:
+        dst = os.fspath(dst)
+        storage_path = self.get_storage_path(src)
+        logger.info(f"Downloading {storage_path} from HDFS")
+        try:
+            files = self._walk(storage_path)
+        except FileNotFoundError:
+            raise errors.CheckpointNotFound(f"Did not find {storage_path} in HDFS") from None
+
+        for rel_path in sorted(files):
+            if selector is not None and not selector(rel_path):
+                continue
+            local_path = os.path.join(dst, rel_path)
+            if rel_path.endswith("/"):
+                os.makedirs(local_path, exist_ok=True)
+                continue
+            os.makedirs(os.path.dirname(local_path), exist_ok=True)
+            with self._request(
+                "GET", posixpath.join(storage_path, rel_path), "OPEN", stream=True
+            ) as resp, open(local_path, "wb") as f:
+                for chunk in resp.iter_content(chunk_size=_DOWNLOAD_CHUNK_SIZE):
+                    f.write(chunk)
+            if os.path.getsize(local_path) != files[rel_path]:
+                raise IOError(f"Download of {rel_path} from HDFS is incomplete")
+
+    @util.preserve_random_state
+    def delete(self, tgt: str, globs: List[str]) -> Dict[str, int]:
+        storage_path = self.get_storage_path(tgt)
+        logger.info(f"Deleting {storage_path} from HDFS")
+        if "**/*" in globs:
+            # Deleting a directory recursively is a single operation in HDFS.
+            self._request("DELETE", storage_path, "DELETE", params={"recursive": "true"})
+            return {}
+
+        try:
+            files = self._walk(storage_path)
+        except FileNotFoundError:
+            logger.info(f"Storage directory does not exist: {storage_path}")
+            return {}
+
+        resources = self._apply_globs_to_resources(files, "", globs)
+        for rel_path in files:
+            parent = posixpath.dirname(rel_path.rstrip("/"))
+            if rel_path in resources or (parent and parent + "/" not in resources):
+                # Kept, or deleted along with the directory it is in.
+                continue
+            self._request(
+                "DELETE",
+                posixpath.join(storage_path, rel_path),
+                "DELETE",
+                params={"recursive": "true"},
+            )
+        if not resources:
+            self._request("DELETE", storage_path, "DELETE", params={"recursive": "true"})
+
+        return resources
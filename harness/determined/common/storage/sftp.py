@@ -0,0 +1,203 @@
+import contextlib
+import logging
+import os
+import posixpath
+import stat
+import tempfile
+from typing import TYPE_CHECKING, Dict, Iterator, List, Optional, Union
+
+from determined import errors
+from determined.common import storage, util
+
+if TYPE_CHECKING:
+    import paramiko
+
+logger = logging.getLogger("determined.common.storage.sftp")
+
+
+class SFTPStorageManager(storage.CloudStorageManager):
+    """
+    Store and load checkpoints on an SFTP server, for clusters without object storage.
+
+    Authentication is by password, by the private key file at `private_key`, or, if neither is
+    configured, by the default keys of the user. The host key of the server must be `host_key`,
+    or be listed in the `known_hosts` file or the system's known hosts; connections to unknown
+    hosts are rejected.
+    """
+
+    def __init__(
+        self,
+        host: str,
+        username: str,
+        storage_path: str,
+        port: int = 22,
+        password: Optional[str] = None,
+        private_key: Optional[str] = None,
+        private_key_passphrase: Optional[str] = None,
+        known_hosts: Optional[str] = None,
+        host_key: Optional[str] = None,
+        temp_dir: Optional[str] = None,
+    ) -> None:
+        super().__init__(temp_dir if temp_dir is not None else tempfile.gettempdir())
+        if password is not None and private_key is not None:
+            raise ValueError("password and private_key must not both be set for SFTP storage")
+        self.host = host
+        self.port = port
+        self.username = username
+        self.password = password
+        self.private_key = private_key
+        self.private_key_passphrase = private_key_passphrase
+        self.known_hosts = known_hosts
+        self.host_key = host_key
+        self.storage_path = posixpath.normpath(storage_path)
+
+    def get_storage_path(self, storage_id: str) -> str:
+        return posixpath.join(self.storage_path, storage_id)
+
+    @contextlib.contextmanager
+    def _connect(self) -> Iterator["paramiko.SFTPClient"]:
+        import paramiko
+
+        client = paramiko.SSHClient()
+        client.load_system_host_keys()
+        if self.known_hosts is not None:
+            client.load_host_keys(self.known_hosts)
+        if self.host_key is not None:
+            # Host keys are listed by host name, or by "[host]:port" for other ports than 22.
+            name = self.host if self.port == 22 else f"[{self.host}]:{self.port}"
+            entry = paramiko.hostkeys.HostKeyEntry.from_line(f"{name} {self.host_key}")
+            if entry is None or entry.key is None:
+                raise ValueError(f"invalid SFTP host_key: {self.host_key}")
+            client.get_host_keys().add(name, entry.key.get_name(), entry.key)
+        client.set_missing_host_key_policy(paramiko.RejectPolicy())
+        try:
+            client.connect(
+                self.host,
+                port=self.port,
+                username=self.username,
+                password=self.password,
+                key_filename=self.private_key,
+                passphrase=self.private_key_passphrase,
+                look_for_keys=self.password is None and self.private_key is None,
+            )
+            sftp = client.open_sftp()
+        except (paramiko.SSHException, OSError) as e:
+            client.close()
+            raise errors.NoDirectStorageAccess(
+                f"Unable to access SFTP checkpoint storage at {self.host}:{self.port}"
+            ) from e
+        try:
+            yield sftp
+        finally:
+            sftp.close()
+            client.close()
+
+    @staticmethod
+    def _makedirs(sftp: "paramiko.SFTPClient", path: str) -> None:
+        parts = [p for p in path.split("/") if p]
+        cur = "/" if path.startswith("/") else ""
+        for part in parts:
+            cur = posixpath.join(cur, part)
+            try:
+                sftp.stat(cur)
+            except FileNotFoundError:
+                sftp.mkdir(cur)
+
+    @staticmethod
+    def _walk(sftp: "paramiko.SFTPClient", root: str) -> Dict[str, int]:
+        """
+        Returns the sizes of the files under `root`, like _list_directory does for local
+        directories. Raises FileNotFoundError if `root` does not exist.
+        """
+        result = {}
+        dirs = [""]
+        while dirs:
+            rel_dir = dirs.pop()
+            for attr in sftp.listdir_attr(posixpath.join(root, rel_dir)):
+                rel_path = posixpath.join(rel_dir, attr.filename)
+                if attr.st_mode is not None and stat.S_ISDIR(attr.st_mode):
+                    result[rel_path + "/"] = 0
+                    dirs.append(rel_path)
+                else:
+                    result[rel_path] = attr.st_size or 0
+        return result
+
+    @util.preserve_random_state
+    def upload(
+        self, src: Union[str, os.PathLike], dst: str, paths: Optional[storage.Paths] = None
+    ) -> None:
+        src = os.fspath(src)
+        storage_path = self.get_storage_path(dst)
+        logger.info(f"Uploading to sftp://{self.host}{storage_path}")
+        upload_paths = paths if paths is not None else self._list_directory(src)
+        with self._connect() as sftp:
+            self._makedirs(sftp, storage_path)
+            for rel_path in sorted(upload_paths):
+                remote_path = posixpath.join(storage_path, rel_path)
+                logger.debug(f"Uploading {rel_path} to sftp://{self.host}{remote_path}")
+                if rel_path.endswith("/"):
+                    self._makedirs(sftp, remote_path)
+                else:
+                    sftp.put(os.path.join(src, rel_path), remote_path)
+
+            self._verify_upload(
+                src, set(upload_paths), self._walk(sftp, storage_path), f"sftp://{self.host}"
+            )
+
+    @util.preserve_random_state
+    def download(
+        self,
+        src: str,
+        dst: Union[str, os.PathLike],
+        selector: Optional[storage.Selector] = None,
+    ) -> None:
+        dst = os.fspath(dst)
+        storage_path = self.get_storage_path(src)
+        logger.info(f"Downloading sftp://{self.host}{storage_path}")
+        with self._connect() as sftp:
+            try:
+                files = self._walk(sftp, storage_path)
+            except FileNotFoundError:
+                raise errors.CheckpointNotFound(
+                    f"Did not find {storage_path} on SFTP server {self.host}"
+                ) from None
+
+            for rel_path in sorted(files):
+                if selector is not None and not selector(rel_path):
+                    continue
+                local_path = os.path.join(dst, rel_path)
+                if rel_path.endswith("/"):
+                    os.makedirs(local_path, exist_ok=True)
+                    continue
+                os.makedirs(os.path.dirname(local_path), exist_ok=True)
+                # get() streams the file in chunks, checking its size when it is done.
+                sftp.get(posixpath.join(storage_path, rel_path), local_path)
+
+    @util.preserve_random_state
+    def delete(self, tgt: str, globs: List[str]) -> Dict[str, int]:
+        storage_path = self.get_storage_path(tgt)
+        logger.info(f"Deleting {storage_path} from SFTP server {self.host}")
+        with self._connect() as sftp:
+            try:
+                files = self._walk(sftp, storage_path)
+            except FileNotFoundError:
+                logger.info(f"Storage directory does not exist: {storage_path}")
+                return {}
+
+            resources = {}
+            if "**/*" not in globs:  # Partial delete case.
+                resources = self._apply_globs_to_resources(files, "", globs)
+
+            # Remove files before the directories that contain them, deepest first.
+            for rel_path in sorted(files, key=lambda p: p.rstrip("/").count("/"), reverse=True):
+                if rel_path in resources:
+                    continue
+                remote_path = posixpath.join(storage_path, rel_path)
+                if rel_path.endswith("/"):
+                    sftp.rmdir(remote_path)
+                else:
+                    sftp.remove(remote_path)
+            if not resources:
+                sftp.rmdir(storage_path)
+
+        return resources
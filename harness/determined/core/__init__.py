@@ -19,6 +19,7 @@ from determined.core._train import (
     TrainContext,
     DummyTrainContext,
     EarlyExitReason,
+    _BatchSizeProbeTrainContext,
 )
 from determined.core._searcher import (
     DummySearcherContext,
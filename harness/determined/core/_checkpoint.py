@@ -9,7 +9,7 @@ import pathlib
 import uuid
 from typing import Any, Callable, Dict, Iterator, List, Optional, Set, Tuple, Union
 
-from determined import core, tensorboard
+from determined import core, errors, tensorboard
 from determined.common import api, storage
 from determined.common.api import bindings
 
@@ -265,6 +265,7 @@ class CheckpointContext:
             f"Uploading content from checkpoint directory {ckpt_dir} to storage "
             f"(metadata={metadata})"
         )
+        self._check_storage_quota(shard=False)
         storage_id = str(uuid.uuid4())
         # Write metadata first so we get it in resources.
         self._write_metadata_file(ckpt_dir, metadata or {})
@@ -294,6 +295,8 @@ class CheckpointContext:
         if selector is not None and ckpt_dir is None:
             raise RuntimeError("ckpt_dir has to be provided if selector is not None")
 
+        self._check_storage_quota(shard=True)
+
         ckpt_dir_mask = self._dist.allgather(ckpt_dir is not None)
         if not any(ckpt_dir_mask):
             raise RuntimeError(
@@ -517,6 +520,7 @@ class CheckpointContext:
                 f"(rank={self._dist.rank})"
             )
 
+        self._check_storage_quota(shard=False)
         storage_id = str(uuid.uuid4())
         with self._storage_manager.store_path(storage_id) as path:
             yield path, storage_id
@@ -529,6 +533,7 @@ class CheckpointContext:
         self, metadata: Optional[Dict[str, Any]] = None
     ) -> Iterator[Tuple[pathlib.Path, str]]:
         logger.debug(f"Getting path for sharded storage (metadata={metadata})")
+        self._check_storage_quota(shard=True)
         storage_id = None
         if self._dist.rank == 0:
             storage_id = str(uuid.uuid4())
@@ -701,6 +706,21 @@ class CheckpointContext:
             self._session, body=bindings.v1PatchCheckpointsRequest(checkpoints=deleted_checkpoint)
         )
 
+    def _check_storage_quota(self, shard: bool) -> None:
+        """
+        Raise before anything is written if the workspace has reached its hard storage quota, so a
+        checkpoint isn't uploaded only to be rejected by the master.
+        """
+        message = None
+        if self._dist.rank == 0:
+            resp = bindings.get_GetTaskStorageQuota(self._session, taskId=self._task_id)
+            if not resp.allowed:
+                message = resp.message
+        if shard:
+            message = self._dist.broadcast(message)
+        if message:
+            raise errors.CheckpointStorageQuotaExceeded(message)
+
     def _write_metadata_file(self, ckpt_dir: str, metadata: Dict[str, Any]) -> None:
         metadata_path = pathlib.Path(ckpt_dir).joinpath("metadata.json")
         with metadata_path.open("w") as f:
@@ -735,7 +755,16 @@ class CheckpointContext:
             state=bindings.checkpointv1State.COMPLETED,
             storageId=self._storage_backend_id,
         )
-        bindings.post_ReportCheckpoint(self._session, body=ckpt)
+        try:
+            bindings.post_ReportCheckpoint(self._session, body=ckpt)
+        except api.errors.APIException as e:
+            if (e.response_error or {}).get("reason") != "FailedPrecondition":
+                raise
+            # The upload took the workspace past its hard storage quota, and the master rejected
+            # the checkpoint; delete what was uploaded so it isn't left orphaned in storage.
+            logger.warning(f"Deleting checkpoint {storage_id} rejected by the master: {e}")
+            self._storage_manager.delete(storage_id, ["**/*"])
+            raise errors.CheckpointStorageQuotaExceeded(e.message) from e
         logger.info(f"Reported checkpoint to master {storage_id}")
 
         # Also sync tensorboard.
@@ -762,6 +791,10 @@ class DummyCheckpointContext(CheckpointContext):
         # No master to report to; just log the event.
         logger.info(f"saved checkpoint {storage_id}")
 
+    def _check_storage_quota(self, shard: bool) -> None:
+        # No master to enforce storage quotas.
+        pass
+
     def _report_checkpoint_deleted(
         self,
         storage_id: str,
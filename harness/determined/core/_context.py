@@ -1,4 +1,5 @@
 import logging
+import os
 import pathlib
 import signal
 import sys
@@ -293,10 +294,23 @@ def init(
     experimental = None
     profiler = None
     metrics = None
+    heartbeat = None
 
     storage_manager = _get_storage_manager(checkpoint_storage)
 
-    if info.task_type == "TRIAL":
+    probe_steps = os.environ.get("DET_BATCH_SIZE_PROBE_STEPS")
+    if info.task_type == "TRIAL" and probe_steps is not None:
+        # Batch size probes only train, to tell whether their batch size fits in memory; they have
+        # no trial to report metrics or checkpoints to.
+        train = core._BatchSizeProbeTrainContext(int(probe_steps))
+        searcher = core.DummySearcherContext(distributed, int(probe_steps))
+        if storage_manager is None:
+            base_path = appdirs.user_data_dir("determined")
+            storage_manager = storage.SharedFSStorageManager(base_path)
+        checkpoint = core.DummyCheckpointContext(distributed, storage_manager)
+        preempt = core.PreemptContext(session, info.allocation_id, distributed, preempt_mode)
+
+    elif info.task_type == "TRIAL":
         # Prepare the tensorboard hooks.
         tensorboard_manager = tensorboard.build(
             info.cluster_id,
@@ -316,6 +330,20 @@ def init(
             info.trial._trial_run_id,
         )
 
+        if distributed.rank == 0:
+            _check_harness_version(session, info.task_id, info.allocation_id)
+
+        # Only the chief reports metrics, so only the chief reports liveness.
+        liveness = info.trial._config.get("liveness") or {}  # type: Dict[str, Any]
+        if liveness.get("enabled") and distributed.rank == 0:
+            heartbeat = core._ManagedTrialHeartbeat(
+                session=session,
+                trial_id=info.trial.trial_id,
+                task_id=info.task_id,
+                allocation_id=info.allocation_id,
+                interval=liveness.get("heartbeat_interval") or 30,
+            )
+
         train = core.TrainContext(
             session,
             info.trial.trial_id,
@@ -325,6 +353,7 @@ def init(
             tensorboard_mode,
             tensorboard_manager,
             tbd_writer,
+            heartbeat,
         )
 
         # only provide a .searcher if max_length appears in the experiment config
@@ -393,11 +422,36 @@ def init(
         profiler=profiler,
         _metrics=metrics,
         _tensorboard_manager=tensorboard_manager,
+        _heartbeat=heartbeat,
         _session=session,
         info=info,
     )
 
 
+def _check_harness_version(session: api.Session, task_id: str, allocation_id: str) -> None:
+    """
+    Report the version of the harness to the master, which judges it against its compatibility
+    policy. Raises if the master refuses to run this version.
+    """
+    body = bindings.v1PostTaskVersionRequest(
+        allocationId=allocation_id, taskId=task_id, version=det.__version__
+    )
+    try:
+        verdict = bindings.post_PostTaskVersion(session, body=body, taskId=task_id)
+    except Exception:
+        # Older masters don't judge harness versions.
+        logger.debug("failure reporting harness version", exc_info=True)
+        return
+    if verdict.compatible:
+        return
+
+    policy, reason = verdict.policy, verdict.reason
+    if policy == "refuse_scheduling":
+        raise RuntimeError(f"the master refuses to run this harness version: {reason}")
+    if policy != "ignore":
+        logger.warning(f"harness version is not compatible with the master ({policy}): {reason}")
+
+
 def _run_prepare(
     distributed: Optional[core.DistributedContext],
     sess: api.Session,
@@ -50,10 +50,58 @@ class _Heartbeat:
 
 class _ManagedTrialHeartbeat(_Heartbeat):
     """
-    ManagedTrialHeartbeat leaves the state management to the server.
+    ManagedTrialHeartbeat leaves the state management to the server, but reports the liveness of
+    the trial, and the last step it reported metrics for, when the experiment configures liveness
+    detection. Heartbeats are sent from a thread, so a training loop stuck in e.g. a collective
+    keeps heartbeating but stops advancing its step, which the master detects separately.
     """
 
-    pass
+    def __init__(
+        self,
+        *,
+        session: api.Session,
+        trial_id: int,
+        task_id: str,
+        allocation_id: str,
+        interval: float,
+    ) -> None:
+        super().__init__(session=session, trial_id=trial_id)
+        self._task_id = task_id
+        self._allocation_id = allocation_id
+        self._interval = interval
+        self._steps_completed = None  # type: Optional[int]
+        self._stop = threading.Event()
+
+    def record_step(self, steps_completed: int) -> None:
+        self._steps_completed = steps_completed
+
+    def _post_heartbeat(self) -> None:
+        body = {"allocation_id": self._allocation_id, "steps_completed": self._steps_completed}
+        self._session.post(f"/tasks/{self._task_id}/heartbeat", json=body)
+
+    def _run(self) -> None:
+        while True:
+            try:
+                self._post_heartbeat()
+            except Exception:
+                # Liveness detection is advisory; never interrupt training because of it.
+                logger.warning("failure sending liveness heartbeat", exc_info=True)
+            if self._stop.wait(self._interval):
+                return
+
+    def start(self) -> "_Heartbeat":
+        threading.Thread(target=self._run, daemon=True, name="LivenessHeartbeatThread").start()
+        return self
+
+    def close(
+        self,
+        exc_type: Optional[type] = None,
+        exc_val: Optional[BaseException] = None,
+        exc_tb: Optional[types.TracebackType] = None,
+    ) -> "_Heartbeat":
+        # The thread isn't joined, since a heartbeat may be retrying against an unreachable master.
+        self._stop.set()
+        return self
 
 
 class _HeartbeatReporter(threading.Thread):
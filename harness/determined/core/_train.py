@@ -1,6 +1,7 @@
 import enum
 import logging
 import pathlib
+import sys
 from typing import Any, Callable, Dict, List, Optional, Set
 
 import determined as det
@@ -33,6 +34,7 @@ class TrainContext:
         tensorboard_mode: core.TensorboardMode,
         tensorboard_manager: Optional[tensorboard.TensorboardManager],
         tbd_writer: Optional[tensorboard.BatchMetricWriter],
+        heartbeat: Optional[core._ManagedTrialHeartbeat] = None,
     ) -> None:
         self._session = session
         self._trial_id = trial_id
@@ -44,6 +46,7 @@ class TrainContext:
         self._tensorboard_mode = tensorboard_mode
         self._tensorboard_manager = tensorboard_manager
         self._tbd_writer = tbd_writer
+        self._heartbeat = heartbeat
 
     def set_status(self, status: str) -> None:
         """
@@ -93,6 +96,18 @@ class TrainContext:
         r = bindings.get_GetRunMetadata(session=self._session, runId=self._trial_id)
         return r.metadata
 
+    def set_metric_metadata(self, metadata: Dict[str, Dict[str, str]]) -> None:
+        """
+        Declare how clients should display the metrics this trial reports, by metric name.
+
+        The metadata of a metric may set its ``unit``, its ``direction`` (``"minimize"`` or
+        ``"maximize"``), its ``display_name``, and the ``scale`` (``"linear"`` or ``"log"``) to plot
+        it on. The metadata applies to the whole experiment, and fields left out keep the values
+        declared before. The ``metric_metadata`` of the experiment config takes precedence.
+        """
+        logger.debug(f"set_metric_metadata({metadata})")
+        self._session.put(f"/trials/{self._trial_id}/metric-metadata", json=metadata)
+
     def _report_trial_metrics(
         self,
         group: str,
@@ -119,6 +134,8 @@ class TrainContext:
             metrics=reportable_metrics,
             batch_metrics=batch_metrics,
         )
+        if self._heartbeat is not None:
+            self._heartbeat.record_step(steps_completed)
 
         # Also sync tensorboard (all metrics, not just json-serializable ones).
         if self._tensorboard_mode == core.TensorboardMode.AUTO:
@@ -311,6 +328,9 @@ class DummyTrainContext(TrainContext):
     def _get_last_validation(self) -> Optional[int]:
         return None
 
+    def set_metric_metadata(self, metadata: Dict[str, Dict[str, str]]) -> None:
+        logger.info(f"metric metadata: {metadata}")
+
     def _report_trial_metrics(
         self,
         group: str,
@@ -341,3 +361,26 @@ class DummyTrainContext(TrainContext):
 
     def get_tensorboard_path(self) -> pathlib.Path:
         return self._tbd_directory  # type: ignore
+
+
+class _BatchSizeProbeTrainContext(DummyTrainContext):
+    """
+    The TrainContext of a batch size probe, which the master runs to tell whether a batch size fits
+    in memory.  Metrics are not reported; the probe exits successfully once it has trained for its
+    steps, which it can only do if it did not run out of memory first.
+    """
+
+    def __init__(self, probe_steps: int) -> None:
+        super().__init__()
+        self._probe_steps = probe_steps
+
+    def _report_trial_metrics(
+        self,
+        group: str,
+        steps_completed: int,
+        metrics: Dict[str, Any],
+        batch_metrics: Optional[List[Dict[str, Any]]] = None,
+    ) -> None:
+        if group == util._LEGACY_TRAINING and steps_completed >= self._probe_steps:
+            logger.info(f"batch size probe trained for {steps_completed} steps, exiting")
+            sys.exit(0)
@@ -99,6 +99,15 @@ class CheckpointStateException(Exception):
     pass
 
 
+class CheckpointStorageQuotaExceeded(Exception):
+    """
+    CheckpointStorageQuotaExceeded indicates a checkpoint could not be saved because its workspace
+    has reached its hard storage quota.
+    """
+
+    pass
+
+
 class NoDirectStorageAccess(Exception):
     """Direct checkpoint storage access unavailable, e.g., no credentials or permissions."""
 
@@ -5,6 +5,7 @@ import signal
 import subprocess
 import sys
 import types
+from typing import List, Union
 
 import determined as det
 from determined.common import constants, storage
@@ -26,8 +27,35 @@ def trigger_preemption(signum: int, frame: types.FrameType) -> None:
         sess.post(f"/api/v1/allocations/{info.allocation_id}/signals/pending_preemption")
 
 
+def fill_launch_placeholders(cmd: List[str]) -> List[str]:
+    """
+    Fill in the placeholders of a launch layer command that depend on where the containers of the
+    trial run; the master fills in the rest.
+    """
+    info = det.get_cluster_info()
+    assert info is not None, "must be run on-cluster"
+    values = {
+        "{num_nodes}": str(len(info.container_addrs)),
+        "{slots_per_node}": str(len(info.slot_ids)),
+        "{node_rank}": str(info.container_rank),
+        "{chief_ip}": info.container_addrs[0],
+    }
+    filled = []
+    for arg in cmd:
+        for placeholder, value in values.items():
+            arg = arg.replace(placeholder, value)
+        filled.append(arg)
+    return filled
+
+
 def launch(experiment_config: det.ExperimentConfig) -> int:
-    entrypoint = experiment_config.get_entrypoint()
+    entrypoint: Union[str, List[str]]
+    launch_command = os.environ.get("DET_LAUNCH_COMMAND")
+    if launch_command is not None:
+        # The master built the command from the launch_layer of the experiment config.
+        entrypoint = fill_launch_placeholders(json.loads(launch_command))
+    else:
+        entrypoint = experiment_config.get_entrypoint()
 
     if isinstance(entrypoint, str) and det.util.match_legacy_trial_class(entrypoint):
         # Legacy entrypoint ("model_def:Trial") detected
@@ -0,0 +1,94 @@
+"""
+Sync a notebook's working directory to and from checkpoint storage.
+
+The master enables this for notebooks launched in workspaces with notebook working directory sync
+turned on, by setting DET_NOTEBOOK_SYNC_PREFIX and DET_NOTEBOOK_SYNC_STORAGE_CONFIG.
+"""
+
+import argparse
+import json
+import logging
+import os
+import pathlib
+import sys
+import time
+from typing import List
+
+import urllib3
+
+import determined as det
+from determined import errors
+from determined.common import api, constants, storage
+from determined.common.api import authentication, bindings, certs
+
+logger = logging.getLogger("determined")
+
+
+def build_manager() -> storage.StorageManager:
+    with open(os.environ["DET_NOTEBOOK_SYNC_STORAGE_CONFIG"]) as f:
+        storage_config = json.load(f)
+    masked_config = json.dumps(det.util.mask_checkpoint_storage(storage_config))
+    logger.info(f"Using checkpoint storage for notebook sync: {masked_config}")
+    return storage.build(storage_config, container_path=constants.SHARED_FS_CONTAINER_PATH)
+
+
+def restore(manager: storage.StorageManager, prefix: str, workdir: pathlib.Path) -> None:
+    try:
+        manager.download(prefix, workdir)
+    except errors.CheckpointNotFound:
+        logger.info(f"No synced working directory found at {prefix}, starting fresh")
+        return
+    logger.info(f"Restored working directory from {prefix}")
+
+
+def upload(
+    sess: api.Session,
+    task_id: str,
+    manager: storage.StorageManager,
+    prefix: str,
+    workdir: pathlib.Path,
+) -> None:
+    manager.upload(workdir, prefix)
+    # The master only records the sync once the upload has finished, so a notebook killed
+    # mid-upload isn't shown as synced.
+    bindings.post_PostTaskNotebookSync(
+        sess, body=bindings.v1PostTaskNotebookSyncRequest(taskId=task_id), taskId=task_id
+    )
+    logger.info(f"Synced working directory to {prefix}")
+
+
+def main(argv: List[str]) -> None:
+    parser = argparse.ArgumentParser(description="Determined notebook working directory sync")
+    parser.add_argument("action", choices=["restore", "upload", "watch"])
+    parser.add_argument("--workdir", type=pathlib.Path, default=pathlib.Path.cwd())
+    parser.add_argument(
+        "--interval", type=int, default=300, help="seconds between syncs when watching"
+    )
+    args = parser.parse_args(argv)
+
+    logging.basicConfig(level=logging.INFO, format=det.LOG_FORMAT)
+
+    prefix = os.environ["DET_NOTEBOOK_SYNC_PREFIX"]
+    manager = build_manager()
+    info = det.get_cluster_info()
+    assert info is not None, "must be run on-cluster"
+    cert = certs.default_load(info.master_url)
+    sess = authentication.login_from_task(info.master_url, cert=cert).with_retry(
+        urllib3.util.retry.Retry(total=6, backoff_factor=0.5)
+    )
+
+    if args.action == "restore":
+        restore(manager, prefix, args.workdir)
+    elif args.action == "upload":
+        upload(sess, info.task_id, manager, prefix, args.workdir)
+    else:
+        while True:
+            time.sleep(args.interval)
+            try:
+                upload(sess, info.task_id, manager, prefix, args.workdir)
+            except Exception as e:
+                logger.warning(f"Failed to sync working directory to {prefix}: {e}")
+
+
+if __name__ == "__main__":
+    main(sys.argv[1:])
@@ -0,0 +1,123 @@
+"""
+The entrypoint for the containers of a generic task that runs a Ray cluster.
+
+The first container runs the Ray head node and reports the cluster's health to the master, and
+every other container runs a worker node connected to it. If the task has an entrypoint, it is run
+on the head node as the Ray driver once the cluster is up, and the task exits with it.
+"""
+
+import logging
+import os
+import shlex
+import subprocess
+import sys
+import threading
+import time
+from typing import List
+
+import urllib3
+
+import determined as det
+from determined.common import api
+from determined.common.api import authentication, bindings, certs
+
+logger = logging.getLogger("determined")
+
+HEALTH_REPORT_PERIOD = 30
+
+
+def ray_start_args() -> List[str]:
+    return shlex.split(os.environ.get("DET_RAY_START_ARGS", ""))
+
+
+def cluster_health(
+    task_id: str, head_address: str, expected_nodes: int
+) -> bindings.v1PostRayClusterHealthRequest:
+    # Ray is only required in the task's image, so it isn't imported at the top of the module.
+    import ray
+
+    if not ray.is_initialized():
+        ray.init(address=head_address, logging_level=logging.WARNING)
+    alive_nodes = sum(1 for node in ray.nodes() if node["Alive"])
+    # A cluster with every resource available isn't running any Ray tasks or actors.
+    idle = ray.available_resources() == ray.cluster_resources()
+    return bindings.v1PostRayClusterHealthRequest(
+        taskId=task_id,
+        headAddress=head_address,
+        expectedNodes=expected_nodes,
+        aliveNodes=alive_nodes,
+        idle=idle,
+    )
+
+
+def report_health(sess: api.Session, task_id: str, head_address: str, expected_nodes: int) -> None:
+    while True:
+        try:
+            health = cluster_health(task_id, head_address, expected_nodes)
+            bindings.post_PostRayClusterHealth(sess, body=health, taskId=task_id)
+        except Exception as e:
+            logger.warning(f"Failed to report the health of the Ray cluster: {e}")
+        time.sleep(HEALTH_REPORT_PERIOD)
+
+
+def run_head(
+    info: det.ClusterInfo, head_address: str, expected_nodes: int, driver: List[str]
+) -> int:
+    head_port = os.environ["DET_RAY_HEAD_PORT"]
+    dashboard_port = os.environ["DET_RAY_DASHBOARD_PORT"]
+    subprocess.run(
+        [
+            "ray",
+            "start",
+            "--head",
+            f"--port={head_port}",
+            "--dashboard-host=0.0.0.0",
+            f"--dashboard-port={dashboard_port}",
+            *ray_start_args(),
+        ],
+        check=True,
+    )
+
+    cert = certs.default_load(info.master_url)
+    sess = authentication.login_from_task(info.master_url, cert=cert).with_retry(
+        urllib3.util.retry.Retry(total=6, backoff_factor=0.5)
+    )
+    reporter = threading.Thread(
+        target=report_health,
+        args=(sess, info.task_id, head_address, expected_nodes),
+        daemon=True,
+    )
+    reporter.start()
+
+    if not driver:
+        # Without a driver, the cluster runs until it is killed or times out as idle.
+        reporter.join()
+        return 0
+
+    env = {**os.environ, "RAY_ADDRESS": head_address}
+    if len(driver) == 1:
+        driver = ["/bin/sh", "-c", driver[0]]
+    try:
+        return subprocess.run(driver, env=env).returncode
+    finally:
+        subprocess.run(["ray", "stop"])
+
+
+def main(driver: List[str]) -> int:
+    info = det.get_cluster_info()
+    assert info is not None, "must be run on-cluster"
+    rendezvous_info = det.RendezvousInfo._from_file()
+    assert rendezvous_info is not None, "must be run after rendezvous"
+
+    head_address = f"{rendezvous_info.container_addrs[0]}:{os.environ['DET_RAY_HEAD_PORT']}"
+    if rendezvous_info.container_rank == 0:
+        return run_head(info, head_address, len(rendezvous_info.container_addrs), driver)
+
+    logger.info(f"Starting a Ray worker node connected to {head_address}")
+    os.execvp("ray", ["ray", "start", f"--address={head_address}", "--block", *ray_start_args()])
+    return 1
+
+
+if __name__ == "__main__":
+    logging.basicConfig(level=logging.INFO, format=det.LOG_FORMAT)
+    sys.exit(main(sys.argv[1:]))
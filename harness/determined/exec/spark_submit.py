@@ -0,0 +1,104 @@
+"""
+The entrypoint for a generic task that runs a Spark job.
+
+The master templates the spark-submit command, which runs the application's driver in this
+container. The task's state and logs are those of the driver. When the executors run as pods on
+Kubernetes, their logs are streamed into the task's logs too.
+"""
+
+import logging
+import os
+import subprocess
+import sys
+import threading
+from typing import Any, List, Set
+
+import determined as det
+
+logger = logging.getLogger("determined")
+
+# Labels the executor pods of a Spark job with the ID of the task that runs its driver.
+TASK_ID_LABEL = "determined-task-id"
+
+EXECUTOR_POLL_PERIOD = 10
+
+
+def runtime_conf() -> List[str]:
+    """Return the Spark properties that are only known once the driver's container is running."""
+    conf = []
+    task_id = os.environ.get("DET_TASK_ID")
+    if task_id and "DET_SPARK_EXECUTOR_NAMESPACE" in os.environ:
+        conf += ["--conf", f"spark.kubernetes.executor.label.{TASK_ID_LABEL}={task_id}"]
+    pod_ip = os.environ.get("DET_KUBERNETES_POD_IP")
+    if pod_ip:
+        # Executors connect back to the driver, which runs in this pod.
+        conf += ["--conf", f"spark.driver.host={pod_ip}"]
+    pod_name = os.environ.get("DET_KUBERNETES_POD_NAME")
+    if pod_name:
+        # The driver's pod owns the executor pods, so they're deleted along with it.
+        conf += ["--conf", f"spark.kubernetes.driver.pod.name={pod_name}"]
+    return conf
+
+
+def follow_pod_logs(core: Any, watch: Any, namespace: str, pod: str) -> None:
+    try:
+        for line in watch.Watch().stream(
+            core.read_namespaced_pod_log, name=pod, namespace=namespace, follow=True
+        ):
+            print(f"[{pod}] {line}", flush=True)
+    except Exception as e:
+        logger.warning(f"Stopped streaming the logs of executor {pod}: {e}")
+
+
+def stream_executor_logs(namespace: str, task_id: str, done: threading.Event) -> None:
+    try:
+        # The Kubernetes client is only required to stream executor logs.
+        from kubernetes import client, config, watch
+
+        config.load_incluster_config()
+    except Exception as e:
+        logger.warning(f"Executor logs won't be collected: {e}")
+        return
+
+    core = client.CoreV1Api()
+    selector = f"{TASK_ID_LABEL}={task_id}"
+    following: Set[str] = set()
+    while not done.is_set():
+        try:
+            pods = core.list_namespaced_pod(namespace, label_selector=selector).items
+        except Exception as e:
+            logger.warning(f"Failed to list executor pods: {e}")
+            pods = []
+        for pod in pods:
+            name = pod.metadata.name
+            if name in following or pod.status.phase == "Pending":
+                continue
+            following.add(name)
+            threading.Thread(
+                target=follow_pod_logs, args=(core, watch, namespace, name), daemon=True
+            ).start()
+        done.wait(EXECUTOR_POLL_PERIOD)
+
+
+def main(spark_submit: List[str]) -> int:
+    assert spark_submit, "missing the spark-submit command"
+    cmd = [spark_submit[0], *runtime_conf(), *spark_submit[1:]]
+
+    done = threading.Event()
+    namespace = os.environ.get("DET_SPARK_EXECUTOR_NAMESPACE")
+    task_id = os.environ.get("DET_TASK_ID")
+    if namespace and task_id:
+        threading.Thread(
+            target=stream_executor_logs, args=(namespace, task_id, done), daemon=True
+        ).start()
+
+    logger.info(f"Running: {' '.join(cmd)}")
+    try:
+        return subprocess.run(cmd).returncode
+    finally:
+        done.set()
+
+
+if __name__ == "__main__":
+    logging.basicConfig(level=logging.INFO, format=det.LOG_FORMAT)
+    sys.exit(main(sys.argv[1:]))
@@ -0,0 +1,90 @@
+"""
+Upload a command's declared outputs to checkpoint storage and record them with the master.
+
+The master enables this for commands launched with `outputs` in their config, by setting
+DET_COMMAND_OUTPUTS and DET_COMMAND_OUTPUTS_STORAGE_CONFIG. The command entrypoint runs it after
+the user's command exits.
+"""
+
+import json
+import logging
+import os
+import pathlib
+import sys
+from typing import List, Set
+
+import urllib3
+
+import determined as det
+from determined.common import constants, storage
+from determined.common.api import authentication, bindings, certs
+
+logger = logging.getLogger("determined")
+
+
+def build_manager() -> storage.StorageManager:
+    with open(os.environ["DET_COMMAND_OUTPUTS_STORAGE_CONFIG"]) as f:
+        storage_config = json.load(f)
+    masked_config = json.dumps(det.util.mask_checkpoint_storage(storage_config))
+    logger.info(f"Using checkpoint storage for command outputs: {masked_config}")
+    return storage.build(storage_config, container_path=constants.SHARED_FS_CONTAINER_PATH)
+
+
+def path_size(path: pathlib.Path) -> int:
+    if path.is_file():
+        return path.stat().st_size
+    return sum(p.stat().st_size for p in path.rglob("*") if p.is_file())
+
+
+def upload_output(
+    manager: storage.StorageManager, dst: str, path: pathlib.Path
+) -> bindings.PostTaskOutputsRequestOutput:
+    if path.is_file():
+        manager.upload(path.parent, dst, paths={path.name})
+    else:
+        manager.upload(path, dst)
+    logger.info(f"Uploaded output {path} to {dst}")
+    return bindings.PostTaskOutputsRequestOutput(
+        path=str(path), storagePath=dst, sizeBytes=str(path_size(path))
+    )
+
+
+def main(outputs: List[str]) -> int:
+    info = det.ClusterInfo._from_file()
+    if info is None:
+        info = det.ClusterInfo._from_env()
+
+    prefix = f"outputs/{info.task_id}"
+    manager = build_manager()
+
+    records = []  # type: List[bindings.PostTaskOutputsRequestOutput]
+    used_names = set()  # type: Set[str]
+    failed = False
+    for i, output in enumerate(outputs):
+        path = pathlib.Path(output).absolute()
+        if not path.exists():
+            logger.warning(f"Declared output {output} does not exist, skipping")
+            continue
+        # Outputs from different directories may share a name; keep them apart in storage.
+        name = path.name if path.name not in used_names else f"{i}-{path.name}"
+        used_names.add(name)
+        try:
+            records.append(upload_output(manager, f"{prefix}/{name}", path))
+        except Exception as e:
+            logger.error(f"Failed to upload output {output}: {e}")
+            failed = True
+
+    if records:
+        cert = certs.default_load(info.master_url)
+        sess = authentication.login_from_task(info.master_url, cert=cert).with_retry(
+            urllib3.util.retry.Retry(total=6, backoff_factor=0.5)
+        )
+        body = bindings.v1PostTaskOutputsRequest(taskId=info.task_id, outputs=records)
+        bindings.post_PostTaskOutputs(sess, body=body, taskId=info.task_id)
+
+    return 1 if failed else 0
+
+
+if __name__ == "__main__":
+    logging.basicConfig(level=logging.INFO, format=det.LOG_FORMAT)
+    sys.exit(main(json.loads(os.environ["DET_COMMAND_OUTPUTS"])))
@@ -39,5 +39,6 @@ from determined.pytorch._pytorch_trial import (
     PyTorchTrial,
     _PyTorchTrialController,
 )
+from determined.pytorch._straggler import _StragglerReporter
 from determined.pytorch._load import CheckpointLoadContext, load_trial_from_checkpoint_path
 from determined.pytorch._trainer import init, Trainer
@@ -1,4 +1,5 @@
 import contextlib
+import functools
 import logging
 import pathlib
 import time
@@ -130,6 +131,11 @@ class PyTorchTrialContext(pytorch._PyTorchReducerContext):
 
         self._stop_requested = False
 
+        # Seconds this rank spent waiting on its peers in gradient collectives since the last
+        # _pop_collective_wait(), tracked once _time_collectives() is called.
+        self._timing_collectives = False
+        self._collective_wait = 0.0
+
         self._tbd_writer = None  # type: Optional[Any]
         self._enable_tensorboard_logging = enable_tensorboard_logging
         # Timestamp for batching TensorBoard uploads
@@ -781,7 +787,7 @@ class PyTorchTrialContext(pytorch._PyTorchReducerContext):
                     # to integrate torch native AMP (https://pytorch.org/docs/stable/amp.html),
                     # which will come out soon.
                     for optimizer in self.optimizers:
-                        optimizer.synchronize()  # type: ignore
+                        self._synchronize(optimizer)
         else:
             if self._scaler and self.experimental._auto_amp:
                 loss = self._scaler.scale(loss)
@@ -801,6 +807,46 @@ class PyTorchTrialContext(pytorch._PyTorchReducerContext):
                 loss.backward(  # type: ignore
                     gradient=gradient, retain_graph=retain_graph, create_graph=create_graph
                 )
+                self._record_ddp_wait()
+
+    def _time_collectives(self) -> None:
+        """
+        Start measuring how long this rank waits on its peers in gradient collectives, so that
+        straggler detection can compare ranks by the time they spend on their own work.
+        """
+        self._timing_collectives = True
+        if self.distributed.size > 1 and self._distributed_backend.use_torch():
+            for model in self.models:
+                if isinstance(model, self._PyTorchDistributedDataParallel):
+                    model._time_collectives()
+
+    def _pop_collective_wait(self) -> float:
+        wait, self._collective_wait = self._collective_wait, 0.0
+        return wait
+
+    def _record_ddp_wait(self) -> None:
+        """
+        DDP all-reduces gradient buckets while the backward pass runs and waits for the last of
+        them before backward() returns. Time spent after this rank's last bucket was ready was spent
+        waiting on the other ranks.
+        """
+        if not self._timing_collectives:
+            return
+        ready = []
+        for model in self.models:
+            if isinstance(model, self._PyTorchDistributedDataParallel):
+                if model._last_bucket_ready is not None:
+                    ready.append(model._last_bucket_ready)
+                model._last_bucket_ready = None
+        if ready:
+            self._collective_wait += max(0.0, time.time() - max(ready))
+
+    def _synchronize(self, optimizer: torch.optim.Optimizer) -> None:
+        # Horovod all-reduces gradients in the background and waits for them here.
+        start = time.time()
+        optimizer.synchronize()  # type: ignore
+        if self._timing_collectives:
+            self._collective_wait += time.time() - start
 
     @staticmethod
     def _average_gradients(parameters: Any, divisor: int) -> None:
@@ -871,7 +917,7 @@ class PyTorchTrialContext(pytorch._PyTorchReducerContext):
             and self._distributed_backend.use_horovod()
             and not self._use_apex
         ):
-            optimizer.synchronize()  # type: ignore
+            self._synchronize(optimizer)
 
         parameters = (
             [p for group in optimizer.param_groups for p in group.get("params", [])]
@@ -1062,6 +1108,30 @@ class PyTorchTrialContext(pytorch._PyTorchReducerContext):
         when using PyTorch DDP
         """
 
+        def __init__(self, *args: Any, **kwargs: Any) -> None:
+            super().__init__(*args, **kwargs)
+            # When the last gradient bucket of the current backward pass was ready on this rank.
+            self._last_bucket_ready = None  # type: Optional[float]
+            self._has_comm_hook = False
+
+        def register_comm_hook(self, state: object, hook: Callable) -> None:
+            # Hooks run as each bucket is ready locally, before it is communicated; note when.
+            @functools.wraps(hook)
+            def timed_hook(state: object, bucket: Any) -> Any:
+                self._last_bucket_ready = time.time()
+                return hook(state, bucket)
+
+            super().register_comm_hook(state, timed_hook)
+            self._has_comm_hook = True
+
+        def _time_collectives(self) -> None:
+            # DDP only calls into Python for buckets when a comm hook is registered, so register
+            # the default all-reduce as one unless the user registered their own.
+            if not self._has_comm_hook:
+                from torch.distributed.algorithms.ddp_comm_hooks import default_hooks
+
+                self.register_comm_hook(None, default_hooks.allreduce_hook)
+
         def __getattr__(self, name: str) -> Any:
             try:
                 return super().__getattr__(name)
@@ -95,6 +95,18 @@ class _PyTorchTrialController:
         self.global_batch_size = global_batch_size
         self.profiling_enabled = profiling_enabled
 
+        self._straggler_reporter = (
+            None
+            if local_training
+            else pytorch._StragglerReporter.from_cluster_info(
+                self.core_context._session,
+                self.context.distributed.rank,
+                self.context.distributed.size,
+            )
+        )
+        if self._straggler_reporter:
+            self.context._time_collectives()
+
         self.callbacks = self.trial.build_callbacks()
         for callback in self.callbacks.values():
             if util.is_overridden(callback.on_checkpoint_end, pytorch.PyTorchCallback):
@@ -449,6 +461,15 @@ class _PyTorchTrialController:
                 # If we are not loading, initialize a fresh state.
                 self.state = pytorch._TrialState(trial_id=self.trial_id)
 
+            assert self.state
+            world_size = self.context.distributed.size
+            if self.state.world_size and self.state.world_size != world_size:
+                logger.info(
+                    f"Resuming training with {world_size} workers, resized from "
+                    f"{self.state.world_size}; the global batch size is unchanged"
+                )
+            self.state.world_size = world_size
+
             if self.context.distributed.size > 1 and self.use_horovod:
                 hvd = horovod.hvd
                 hvd.broadcast_parameters(self.context._main_model.state_dict(), root_rank=0)
@@ -720,6 +741,9 @@ class _PyTorchTrialController:
         samples_per_second = self.trial.get_batch_length(batch) / batch_dur
         samples_per_second *= self.context.distributed.size
 
+        if self._straggler_reporter:
+            self._straggler_reporter.record(batch_dur, self.context._pop_collective_wait())
+
         return training_metrics
 
     @torch.no_grad()
@@ -0,0 +1,90 @@
+import logging
+from typing import Any, Dict, List, Optional
+
+import determined as det
+from determined.common import api
+from determined.common.api import bindings
+
+logger = logging.getLogger("determined.pytorch")
+
+
+class _StragglerReporter:
+    """
+    Report this rank's mean batch time, and how much of it was spent waiting on peers in gradient
+    collectives, to the master once per window of batches, so that the master can flag a rank that
+    consistently lags behind its peers.
+    """
+
+    def __init__(
+        self,
+        session: api.Session,
+        task_id: str,
+        allocation_id: str,
+        agent_id: str,
+        rank: int,
+        world_size: int,
+        window: int,
+    ) -> None:
+        self._session = session
+        self._task_id = task_id
+        self._allocation_id = allocation_id
+        self._agent_id = agent_id
+        self._rank = rank
+        self._world_size = world_size
+        self._window = window
+
+        self._window_idx = 0
+        self._durations = []  # type: List[float]
+        self._waits = []  # type: List[float]
+        self._failed = False
+
+    @classmethod
+    def from_cluster_info(
+        cls, session: Optional[api.Session], rank: int, world_size: int
+    ) -> Optional["_StragglerReporter"]:
+        info = det.get_cluster_info()
+        if session is None or info is None or info.task_type != "TRIAL" or world_size < 2:
+            return None
+        config = info.trial._config.get("straggler_detection") or {}  # type: Dict[str, Any]
+        if not config.get("enabled"):
+            return None
+        return cls(
+            session,
+            info.task_id,
+            info.allocation_id,
+            info.agent_id,
+            rank,
+            world_size,
+            config.get("window") or 20,
+        )
+
+    def record(self, batch_dur: float, wait_dur: float) -> None:
+        self._durations.append(batch_dur)
+        self._waits.append(wait_dur)
+        if len(self._durations) < self._window:
+            return
+
+        mean = sum(self._durations) / len(self._durations)
+        mean_wait = sum(self._waits) / len(self._waits)
+        body = bindings.v1PostTaskStepTimingsRequest(
+            taskId=self._task_id,
+            allocationId=self._allocation_id,
+            rank=self._rank,
+            agentId=self._agent_id,
+            window=self._window_idx,
+            worldSize=self._world_size,
+            meanSeconds=mean,
+            meanWaitSeconds=mean_wait,
+        )
+        self._durations = []
+        self._waits = []
+        self._window_idx += 1
+
+        if self._failed:
+            return
+        try:
+            bindings.post_PostTaskStepTimings(self._session, body=body, taskId=self._task_id)
+        except Exception as e:
+            # Straggler detection is advisory; never interrupt training because of it.
+            logger.warning(f"failed to report step timings, disabling straggler reporting: {e}")
+            self._failed = True
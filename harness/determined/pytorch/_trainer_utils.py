@@ -121,6 +121,7 @@ class _TrialState:
         last_val: int = 0,
         batches_trained: int = 0,
         epochs_trained: int = 0,
+        world_size: int = 0,
     ) -> None:
         # Store TrialID to distinguish between e.g. pause/restart and continue training.
         self.trial_id = trial_id
@@ -129,6 +130,8 @@ class _TrialState:
         self.last_val = last_val
         self.batches_trained = batches_trained
         self.epochs_trained = epochs_trained
+        # Store the number of workers to detect when an elastic trial was resized.
+        self.world_size = world_size
 
 
 class _TrainBoundaryType(enum.Enum):
@@ -331,6 +331,11 @@ def _get_storage_information(
     elif storage_type == "azure":
         container = checkpoint_config["container"]
         return f"Azure container: {container} Directory:{default_uuid_path}"
+    elif storage_type == "sftp":
+        host, storage_path = checkpoint_config["host"], checkpoint_config["storage_path"]
+        return f"sftp://{host}{os.path.join(storage_path, default_uuid_path)}"
+    elif storage_type == "hdfs":
+        return f"HDFS: {os.path.join(checkpoint_config['storage_path'], default_uuid_path)}"
     elif storage_type == "shared_fs":
         base_path = core_context.checkpoint._storage_manager._base_path
         return f"{base_path}/{default_uuid_path}"
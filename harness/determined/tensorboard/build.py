@@ -3,7 +3,7 @@ import pathlib
 from typing import Any, Dict, Optional, Union
 
 from determined.common.storage import shared as shared_storage
-from determined.tensorboard import azure, base, directory, gcs, s3, shared
+from determined.tensorboard import azure, base, directory, gcs, hdfs, s3, sftp, shared
 
 
 def get_sync_path(cluster_id: str, experiment_id: str, trial_id: str) -> pathlib.Path:
@@ -134,5 +134,33 @@ def build(
             sync_on_close=sync_on_close,
         )
 
+    elif type_name == "sftp":
+        return sftp.SFTPTensorboardManager(
+            checkpoint_config["host"],
+            checkpoint_config.get("port", 22),
+            checkpoint_config["username"],
+            checkpoint_config.get("password", None),
+            checkpoint_config.get("private_key", None),
+            checkpoint_config.get("private_key_passphrase", None),
+            checkpoint_config.get("known_hosts", None),
+            checkpoint_config.get("host_key", None),
+            checkpoint_config["storage_path"],
+            base_path,
+            sync_path,
+            async_upload=async_upload,
+            sync_on_close=sync_on_close,
+        )
+
+    elif type_name == "hdfs":
+        return hdfs.HDFSTensorboardManager(
+            checkpoint_config["url"],
+            checkpoint_config.get("username", None),
+            checkpoint_config["storage_path"],
+            base_path,
+            sync_path,
+            async_upload=async_upload,
+            sync_on_close=sync_on_close,
+        )
+
     else:
         raise TypeError(f"Unknown storage type: {type_name}")
@@ -3,7 +3,9 @@ from typing import Any, Dict, List, Type
 from determined.tensorboard.fetchers.azure import AzureFetcher
 from determined.tensorboard.fetchers.base import Fetcher
 from determined.tensorboard.fetchers.gcs import GCSFetcher
+from determined.tensorboard.fetchers.hdfs import HDFSFetcher
 from determined.tensorboard.fetchers.s3 import S3Fetcher
+from determined.tensorboard.fetchers.sftp import SFTPFetcher
 from determined.tensorboard.fetchers.shared import SharedFSFetcher
 from determined.tensorboard.fetchers.directory import DirectoryFetcher
 
@@ -12,6 +14,8 @@ __all__ = [
     "GCSFetcher",
     "AzureFetcher",
     "SharedFSFetcher",
+    "SFTPFetcher",
+    "HDFSFetcher",
 ]
 
 _FETCHERS = {
@@ -20,6 +24,8 @@ _FETCHERS = {
     "azure": AzureFetcher,
     "shared_fs": SharedFSFetcher,
     "directory": DirectoryFetcher,
+    "sftp": SFTPFetcher,
+    "hdfs": HDFSFetcher,
 }  # type: Dict[str, Type[Fetcher]]
 
 
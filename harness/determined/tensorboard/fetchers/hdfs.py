@@ -0,0 +1,55 @@
+import datetime
+import logging
+import os
+import posixpath
+from typing import Any, Callable, Dict, Generator, List
+from urllib import parse
+
+from determined.common import storage
+from determined.tensorboard.fetchers import base
+
+logger = logging.getLogger("determined.tensorboard.hdfs")
+
+
+class HDFSFetcher(base.Fetcher):
+    def __init__(self, storage_config: Dict[str, Any], storage_paths: List[str], local_dir: str):
+        self.storage = storage.HDFSStorageManager(
+            storage_config["url"],
+            storage_config["storage_path"],
+            username=storage_config.get("username"),
+        )
+
+        self.local_dir = local_dir
+        self.storage_paths = storage_paths
+        self._file_records = {}  # type: Dict[str, datetime.datetime]
+
+    def _list(self, storage_path: str) -> Generator[str, None, None]:
+        logger.debug(f"Listing files in HDFS with storage_path: '{storage_path}'")
+        root = parse.urlparse(storage_path).path
+        try:
+            for rel_path, status in self.storage._list_status(root):
+                if rel_path.endswith("/"):
+                    continue
+                filepath = posixpath.join(root, rel_path)
+                # WebHDFS modification times are in milliseconds.
+                mdatetime = datetime.datetime.fromtimestamp(status["modificationTime"] / 1000)
+                prev_mdatetime = self._file_records.get(filepath)
+                if prev_mdatetime is not None and prev_mdatetime >= mdatetime:
+                    continue
+                self._file_records[filepath] = mdatetime
+                yield filepath
+        except FileNotFoundError:
+            return
+
+    def _fetch(self, filepath: str, new_file_callback: Callable) -> None:
+        local_path = posixpath.join(self.local_dir, filepath.lstrip("/"))
+        os.makedirs(os.path.dirname(local_path), exist_ok=True)
+
+        with self.storage._request("GET", filepath, "OPEN", stream=True) as resp, open(
+            local_path, "wb"
+        ) as local_file:
+            for chunk in resp.iter_content(chunk_size=1024 * 1024):
+                local_file.write(chunk)
+
+        logger.debug(f"Downloaded HDFS file to local: {local_path}")
+        new_file_callback()
@@ -0,0 +1,63 @@
+import datetime
+import logging
+import os
+import posixpath
+import stat
+from typing import Any, Callable, Dict, Generator, List
+from urllib import parse
+
+from determined.common import storage
+from determined.tensorboard.fetchers import base
+
+logger = logging.getLogger("determined.tensorboard.sftp")
+
+
+class SFTPFetcher(base.Fetcher):
+    def __init__(self, storage_config: Dict[str, Any], storage_paths: List[str], local_dir: str):
+        self.storage = storage.SFTPStorageManager(
+            host=storage_config["host"],
+            port=storage_config.get("port") or 22,
+            username=storage_config["username"],
+            password=storage_config.get("password"),
+            private_key=storage_config.get("private_key"),
+            private_key_passphrase=storage_config.get("private_key_passphrase"),
+            known_hosts=storage_config.get("known_hosts"),
+            host_key=storage_config.get("host_key"),
+            storage_path=storage_config["storage_path"],
+        )
+        # Keep one connection open for the life of the fetcher.
+        self.sftp = self.storage._connect().__enter__()
+
+        self.local_dir = local_dir
+        self.storage_paths = storage_paths
+        self._file_records = {}  # type: Dict[str, datetime.datetime]
+
+    def _list(self, storage_path: str) -> Generator[str, None, None]:
+        logger.debug(f"Listing files on SFTP server with storage_path: '{storage_path}'")
+        dirs = [parse.urlparse(storage_path).path]
+        while dirs:
+            cur = dirs.pop()
+            try:
+                attrs = self.sftp.listdir_attr(cur)
+            except FileNotFoundError:
+                continue
+            for attr in attrs:
+                filepath = posixpath.join(cur, attr.filename)
+                if attr.st_mode is not None and stat.S_ISDIR(attr.st_mode):
+                    dirs.append(filepath)
+                    continue
+                mdatetime = datetime.datetime.fromtimestamp(attr.st_mtime or 0)
+                prev_mdatetime = self._file_records.get(filepath)
+                if prev_mdatetime is not None and prev_mdatetime >= mdatetime:
+                    continue
+                self._file_records[filepath] = mdatetime
+                yield filepath
+
+    def _fetch(self, filepath: str, new_file_callback: Callable) -> None:
+        local_path = posixpath.join(self.local_dir, filepath.lstrip("/"))
+        os.makedirs(os.path.dirname(local_path), exist_ok=True)
+
+        self.sftp.get(filepath, local_path)
+
+        logger.debug(f"Downloaded SFTP file to local: {local_path}")
+        new_file_callback()
@@ -0,0 +1,39 @@
+import logging
+import posixpath
+from typing import Any, List, Optional
+
+from determined.common import storage
+from determined.tensorboard import base
+
+logger = logging.getLogger("determined.tensorboard.hdfs")
+
+
+class HDFSTensorboardManager(base.TensorboardManager):
+    """
+    Store and load tf event logs on HDFS.
+    """
+
+    def __init__(
+        self,
+        url: str,
+        username: Optional[str],
+        storage_path: str,
+        *args: Any,
+        **kwargs: Any,
+    ) -> None:
+        super().__init__(*args, **kwargs)
+        self.storage = storage.HDFSStorageManager(url, storage_path, username=username)
+
+    def _sync_impl(
+        self,
+        path_info_list: List[base.PathUploadInfo],
+    ) -> None:
+        for path_info in path_info_list:
+            mangled_path = self.sync_path.joinpath(path_info.mangled_relative_path)
+            remote_path = self.storage.get_storage_path(str(mangled_path))
+            logger.debug(f"Uploading {path_info.path} to HDFS: {remote_path}")
+            self.storage._request("PUT", posixpath.dirname(remote_path), "MKDIRS")
+            self.storage._put_file(str(path_info.path), remote_path)
+
+    def delete(self) -> None:
+        self.storage.delete(str(self.sync_path), ["**/*"])
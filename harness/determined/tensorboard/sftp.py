@@ -0,0 +1,56 @@
+import logging
+import posixpath
+from typing import Any, List, Optional
+
+from determined.common import storage
+from determined.tensorboard import base
+
+logger = logging.getLogger("determined.tensorboard.sftp")
+
+
+class SFTPTensorboardManager(base.TensorboardManager):
+    """
+    Store and load tf event logs on an SFTP server.
+    """
+
+    def __init__(
+        self,
+        host: str,
+        port: int,
+        username: str,
+        password: Optional[str],
+        private_key: Optional[str],
+        private_key_passphrase: Optional[str],
+        known_hosts: Optional[str],
+        host_key: Optional[str],
+        storage_path: str,
+        *args: Any,
+        **kwargs: Any,
+    ) -> None:
+        super().__init__(*args, **kwargs)
+        self.storage = storage.SFTPStorageManager(
+            host=host,
+            port=port,
+            username=username,
+            password=password,
+            private_key=private_key,
+            private_key_passphrase=private_key_passphrase,
+            known_hosts=known_hosts,
+            host_key=host_key,
+            storage_path=storage_path,
+        )
+
+    def _sync_impl(
+        self,
+        path_info_list: List[base.PathUploadInfo],
+    ) -> None:
+        with self.storage._connect() as sftp:
+            for path_info in path_info_list:
+                mangled_path = self.sync_path.joinpath(path_info.mangled_relative_path)
+                remote_path = self.storage.get_storage_path(str(mangled_path))
+                logger.debug(f"Uploading {path_info.path} to SFTP: {remote_path}")
+                self.storage._makedirs(sftp, posixpath.dirname(remote_path))
+                sftp.put(str(path_info.path), remote_path)
+
+    def delete(self) -> None:
+        self.storage.delete(str(self.sync_path), ["**/*"])
@@ -495,7 +495,7 @@ def mask_checkpoint_storage(d: Dict) -> Dict:
     new_dict = copy.deepcopy(d)
 
     # checkpoint_storage
-    for key in ("access_key", "secret_key"):
+    for key in ("access_key", "secret_key", "password", "private_key_passphrase"):
         if key in new_dict:
             new_dict[key] = mask
 
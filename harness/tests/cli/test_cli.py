@@ -609,6 +609,7 @@ def test_preview_search(tmp_path: pathlib.Path) -> None:
                 matchers.json_params_matcher(
                     params={
                         "config": searcher_config,
+                        "sampleCount": 0,
                     }
                 )
             ],
@@ -622,6 +623,43 @@ def test_preview_search(tmp_path: pathlib.Path) -> None:
 """
         util.check_cli_output(["preview-search", str(conf_path)], expected_output)
 
+    # Random, with sampled trials
+    mock_resp.samples = [
+        {"trial_seed": 11, "hparams": {"x": 12}},
+        {"trial_seed": 22, "hparams": {"x": 12}},
+    ]
+    mock_resp.experimentSeed = 7
+    mock_resp.estimatedTrials = max_trials
+    with util.standard_cli_rsps() as rsps:
+        rsps.post(
+            f"{MASTER_HOST}/api/v1/preview-hp-search",
+            status=200,
+            match=[
+                matchers.json_params_matcher(
+                    params={
+                        "config": searcher_config,
+                        "sampleCount": 2,
+                    }
+                )
+            ],
+            json=mock_resp.to_json(),
+        )
+        expected_output = f"""Using search configuration:
+{render.format_object_as_yaml(searcher_config)}
+   Trials | Training Time
+----------+---------------------
+       10 | train to completion
+
+First 2 trials (experiment_seed: 7):
+   Trial Seed | Hyperparameters
+--------------+-------------------
+           11 | {{"x": 12}}
+           22 | {{"x": 12}}
+
+Estimated total trials: 10
+"""
+        util.check_cli_output(["preview-search", str(conf_path), "--samples", "2"], expected_output)
+
     # ASHA
     searcher_config = {
         "hyperparameters": {
@@ -668,6 +706,7 @@ def test_preview_search(tmp_path: pathlib.Path) -> None:
                 matchers.json_params_matcher(
                     params={
                         "config": searcher_config,
+                        "sampleCount": 0,
                     }
                 )
             ],
@@ -1,4 +1,5 @@
 import contextlib
+import json
 import pathlib
 from typing import Any, Callable, Dict, Iterator, List, Optional
 from unittest import mock
@@ -6,10 +7,19 @@ from unittest import mock
 import pytest
 import requests
 
-from determined import core
+from determined import core, errors
+from determined.common import api
 from tests import parallel
 
 
+def make_quota_response(allowed: bool, message: str = "") -> requests.Response:
+    """A response from the master to a trial checking its workspace's hard storage quota."""
+    response = requests.Response()
+    response.status_code = 200
+    response._content = json.dumps({"allowed": allowed, "message": message}).encode()
+    return response
+
+
 def make_mock_storage_manager(
     basedir: pathlib.Path,
     dir_files: Optional[List[str]] = None,
@@ -70,9 +80,7 @@ def test_checkpoint_context(dummy: bool, mode: core.DownloadMode, tmp_path: path
             storage_manager = make_mock_storage_manager(tmp_path)
             if not dummy:
                 session = mock.MagicMock()
-                response = requests.Response()
-                response.status_code = 200
-                session._do_request.return_value = response
+                session._do_request.return_value = make_quota_response(allowed=True)
                 tensorboard_manager = mock.MagicMock()
                 checkpoint_context = core.CheckpointContext(
                     pex.distributed,
@@ -100,7 +108,8 @@ def test_checkpoint_context(dummy: bool, mode: core.DownloadMode, tmp_path: path
                 storage_manager._list_directory.assert_called_once()
                 storage_manager._list_directory.reset_mock()
                 if not dummy:
-                    session._do_request.assert_called_once()
+                    # The storage quota check and the checkpoint report.
+                    assert session._do_request.call_count == 2
                     session._do_request.reset_mock()
             else:
                 storage_manager.upload.assert_not_called()
@@ -123,7 +132,8 @@ def test_checkpoint_context(dummy: bool, mode: core.DownloadMode, tmp_path: path
                 storage_manager._list_directory.assert_called_once()
                 storage_manager._list_directory.reset_mock()
                 if not dummy:
-                    session._do_request.assert_called_once()
+                    # The storage quota check and the checkpoint report.
+                    assert session._do_request.call_count == 2
                     session._do_request.reset_mock()
             else:
                 storage_manager.store_path.assert_not_called()
@@ -379,3 +389,82 @@ def test_store_path(sharded: bool, tmp_path: pathlib.Path) -> None:
                 else:
                     storage_manager.post_store_path.assert_not_called()
                     storage_manager._list_directory.assert_not_called()
+
+
+@pytest.mark.parametrize("sharded", [True, False])
+def test_checkpoint_upload_over_hard_quota(sharded: bool, tmp_path: pathlib.Path) -> None:
+    ckpt_dir = tmp_path.joinpath("ckpt-dir")
+    ckpt_dir.mkdir(exist_ok=True)
+
+    with parallel.Execution(2) as pex:
+
+        @pex.run
+        def upload_ckpt() -> None:
+            storage_manager = make_mock_storage_manager(ckpt_dir)
+            session = mock.MagicMock()
+            session._do_request.return_value = make_quota_response(
+                allowed=False, message="workspace hard storage quota exceeded"
+            )
+            checkpoint_context = core.CheckpointContext(
+                pex.distributed,
+                storage_manager,
+                session=session,
+                task_id="task-id",
+                allocation_id="allocation-id",
+                tbd_sync_mode=core.TensorboardMode.MANUAL,
+                tensorboard_manager=None,
+                storage_backend_id=None,
+            )
+
+            if not sharded and pex.rank != 0:
+                return
+
+            # Every rank raises before anything is uploaded, but only the chief asks the master.
+            with pytest.raises(errors.CheckpointStorageQuotaExceeded, match="hard storage quota"):
+                checkpoint_context.upload(ckpt_dir, metadata={"steps_completed": 1}, shard=sharded)
+            storage_manager.upload.assert_not_called()
+            if pex.rank == 0:
+                session._do_request.assert_called_once()
+                path = session._do_request.call_args.kwargs["path"]
+                assert path == "/api/v1/tasks/task-id/storage-quota"
+            else:
+                session._do_request.assert_not_called()
+
+
+def test_checkpoint_rejected_over_hard_quota(tmp_path: pathlib.Path) -> None:
+    ckpt_dir = tmp_path.joinpath("ckpt-dir")
+    ckpt_dir.mkdir(exist_ok=True)
+
+    storage_manager = make_mock_storage_manager(ckpt_dir)
+    session = mock.MagicMock()
+    resp = mock.MagicMock(status_code=400)
+    resp.json.return_value = {
+        "error": {
+            "code": 9,
+            "reason": "FailedPrecondition",
+            "error": "workspace hard storage quota exceeded",
+        }
+    }
+    # The quota check passes, then the report is rejected.
+    session._do_request.side_effect = [
+        make_quota_response(allowed=True),
+        api.errors.APIException(resp),
+    ]
+    checkpoint_context = core.CheckpointContext(
+        core.DummyDistributedContext(),
+        storage_manager,
+        session=session,
+        task_id="task-id",
+        allocation_id="allocation-id",
+        tbd_sync_mode=core.TensorboardMode.MANUAL,
+        tensorboard_manager=None,
+        storage_backend_id=None,
+    )
+
+    # The quota is reached part-way through the upload, so the master rejects the checkpoint and
+    # the upload is deleted rather than left orphaned.
+    with pytest.raises(errors.CheckpointStorageQuotaExceeded, match="hard storage quota"):
+        checkpoint_context.upload(ckpt_dir, metadata={"steps_completed": 1}, shard=False)
+    storage_manager.upload.assert_called_once()
+    storage_id = storage_manager.upload.call_args.kwargs["dst"]
+    storage_manager.delete.assert_called_once_with(storage_id, ["**/*"])
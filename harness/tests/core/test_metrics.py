@@ -58,3 +58,12 @@ def test_metrics_report_raises_exception(mock_post_metrics: mock.MagicMock) -> N
         metrics_context.close()
 
     assert mock_post_metrics.call_count == 1
+
+
+def test_batch_size_probe_exits_after_probe_steps() -> None:
+    train = core._BatchSizeProbeTrainContext(probe_steps=10)
+    train.report_training_metrics(steps_completed=5, metrics={"loss": 0.5})
+    train.report_validation_metrics(steps_completed=10, metrics={"loss": 0.5})
+    with pytest.raises(SystemExit) as e:
+        train.report_training_metrics(steps_completed=10, metrics={"loss": 0.4})
+    assert e.value.code == 0
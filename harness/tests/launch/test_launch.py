@@ -1,3 +1,4 @@
+import json
 import os
 import runpy
 import signal
@@ -42,6 +43,36 @@ def test_launch_list() -> None:
     do_test_launch(config, cmd)
 
 
+def test_launch_layer_command() -> None:
+    cmd = [
+        "python3",
+        "-m",
+        "determined.launch.torch_distributed",
+        "--nnodes={num_nodes}",
+        "--nproc_per_node={slots_per_node}",
+        "--node_rank={node_rank}",
+        "--master_addr={chief_ip}",
+        "--",
+        "python3",
+        "train.py",
+    ]
+    expected = [
+        "python3",
+        "-m",
+        "determined.launch.torch_distributed",
+        "--nnodes=2",
+        "--nproc_per_node=2",
+        "--node_rank=1",
+        "--master_addr=0.0.0.1",
+        "--",
+        "python3",
+        "train.py",
+    ]
+    with test_util.set_env_vars({"DET_LAUNCH_COMMAND": json.dumps(cmd)}):
+        with test_util.set_mock_cluster_info(["0.0.0.1", "0.0.0.2"], 1, 2):
+            do_test_launch({"entrypoint": "ignored"}, expected)
+
+
 @mock.patch("determined.common.storage.validate_config")
 def test_launch_script(mock_validate_config: mock.MagicMock) -> None:
     # Use runpy to actually run the whole launch script.
@@ -135,6 +135,27 @@ def test_s3_shortcut_dict() -> None:
     mocked.assert_called_once_with(shortcut, None)
 
 
+def test_sftp_shortcut_dict() -> None:
+    shortcut = {
+        "type": "sftp",
+        "host": "sftp.example.com",
+        "username": "determined",
+        "storage_path": "/checkpoints",
+    }
+    with mock.patch("determined.common.storage.SFTPStorageManager.from_config") as mocked:
+        _ = core._context._get_storage_manager(checkpoint_storage=shortcut)
+    shortcut.pop("type")
+    mocked.assert_called_once_with(shortcut, None)
+
+
+def test_hdfs_shortcut_dict() -> None:
+    shortcut = {"type": "hdfs", "url": "http://namenode:9870", "storage_path": "/checkpoints"}
+    with mock.patch("determined.common.storage.HDFSStorageManager.from_config") as mocked:
+        _ = core._context._get_storage_manager(checkpoint_storage=shortcut)
+    shortcut.pop("type")
+    mocked.assert_called_once_with(shortcut, None)
+
+
 def test_shared_fs_shortcut_dict() -> None:
     shortcut = {"type": "shared_fs", "base_path": "test_base_path"}
     with pytest.raises(ValueError):
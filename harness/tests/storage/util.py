@@ -721,6 +721,8 @@ def create_checkpoint_context(
     session = mock.MagicMock()
     response = requests.Response()
     response.status_code = 200
+    # Trials check their workspace's storage quota before writing checkpoints.
+    response._content = b'{"allowed": true, "message": ""}'
     session._do_request.return_value = response
     tensorboard_manager = mock.MagicMock()
     checkpoint_context = core.CheckpointContext(
@@ -104,6 +104,19 @@ def test_is_numerical_scalar() -> None:
     assert util.is_numerical_scalar(np.array([1.0])[0])
 
 
+def test_mask_checkpoint_storage() -> None:
+    storage = {
+        "type": "sftp",
+        "host": "sftp.example.com",
+        "password": "hunter2",
+        "private_key_passphrase": "swordfish",
+    }
+    masked = util.mask_checkpoint_storage(storage)
+    assert masked["password"] == masked["private_key_passphrase"] == "********"
+    assert masked["host"] == "sftp.example.com"
+    assert storage["password"] == "hunter2", "the original config is left alone"
+
+
 def test_local_trial_user_code_detignore(tmp_path: pathlib.Path) -> None:
     src_path = tmp_path / "src"
     dst_path = tmp_path / "dst"
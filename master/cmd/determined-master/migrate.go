@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -52,8 +54,26 @@ func runMigrate(args []string) error {
 		}
 	}()
 
-	if err = database.Migrate(config.DB.Migrations, config.DB.ViewsAndTriggers, args); err != nil {
-		return errors.Wrap(err, "running migrations")
+	switch {
+	case len(args) > 0 && args[0] == "plan":
+		// Print what the pending migrations would lock, without applying them.
+		plan, err := database.PlanMigrations(context.Background(), config.DB.Migrations)
+		if err != nil {
+			return errors.Wrap(err, "planning migrations")
+		}
+		b, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	case len(args) > 0 && args[0] == "contract":
+		if err = database.MigrateContract(config.DB.Migrations); err != nil {
+			return errors.Wrap(err, "running contract migrations")
+		}
+	default:
+		if err = database.Migrate(config.DB.Migrations, config.DB.ViewsAndTriggers, args); err != nil {
+			return errors.Wrap(err, "running migrations")
+		}
 	}
 
 	return nil
@@ -103,6 +103,7 @@ webhooks:
 			Provider:                 providerConf,
 			MaxAuxContainersPerAgent: 100,
 			AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+			DiskPressureThreshold:    0.9,
 			TaskContainerDefaults:    &expected.TaskContainerDefaults,
 		},
 	}
@@ -0,0 +1,57 @@
+// Package activity records notable events in each workspace, like experiments being created or
+// finishing and members being added, for a shared feed of what is happening in the workspace.
+package activity
+
+import (
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+const (
+	// DefaultLimit is the number of events in a page of a feed if no limit is given.
+	DefaultLimit = 50
+	// MaxLimit caps the number of events in a page of a feed.
+	MaxLimit = 500
+)
+
+// experimentEndedKind returns the kind of event recorded for an experiment reaching a state, if
+// any.
+func experimentEndedKind(state model.State) (model.WorkspaceEventKind, bool) {
+	switch state {
+	case model.CompletedState:
+		return model.WorkspaceEventExperimentCompleted, true
+	case model.ErrorState:
+		return model.WorkspaceEventExperimentFailed, true
+	default:
+		return "", false
+	}
+}
+
+// memberEvents returns an event for each role assignment scoped to a workspace. Cluster-wide
+// assignments don't belong to any workspace's feed.
+func memberEvents(
+	actor model.UserID, groups []*rbacv1.GroupRoleAssignment, users []*rbacv1.UserRoleAssignment,
+) []*model.WorkspaceEvent {
+	var events []*model.WorkspaceEvent
+	add := func(a *rbacv1.RoleAssignment, details map[string]any) {
+		if a == nil || a.ScopeWorkspaceId == nil {
+			return
+		}
+		if a.Role != nil {
+			details["role_id"] = int(a.Role.RoleId)
+		}
+		events = append(events, &model.WorkspaceEvent{
+			WorkspaceID: int(*a.ScopeWorkspaceId),
+			Kind:        model.WorkspaceEventMemberAdded,
+			ActorID:     &actor,
+			Details:     details,
+		})
+	}
+	for _, g := range groups {
+		add(g.RoleAssignment, map[string]any{"group_id": int(g.GroupId)})
+	}
+	for _, u := range users {
+		add(u.RoleAssignment, map[string]any{"user_id": int(u.UserId)})
+	}
+	return events
+}
@@ -0,0 +1,53 @@
+package activity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+func TestExperimentEndedKind(t *testing.T) {
+	kind, ok := experimentEndedKind(model.CompletedState)
+	require.True(t, ok)
+	require.Equal(t, model.WorkspaceEventExperimentCompleted, kind)
+
+	kind, ok = experimentEndedKind(model.ErrorState)
+	require.True(t, ok)
+	require.Equal(t, model.WorkspaceEventExperimentFailed, kind)
+
+	_, ok = experimentEndedKind(model.CanceledState)
+	require.False(t, ok)
+}
+
+func TestMemberEvents(t *testing.T) {
+	role := &rbacv1.Role{RoleId: 2}
+	events := memberEvents(7,
+		[]*rbacv1.GroupRoleAssignment{{
+			GroupId:        3,
+			RoleAssignment: &rbacv1.RoleAssignment{Role: role, ScopeWorkspaceId: ptrs.Ptr(int32(5))},
+		}},
+		[]*rbacv1.UserRoleAssignment{
+			{
+				UserId:         4,
+				RoleAssignment: &rbacv1.RoleAssignment{Role: role, ScopeWorkspaceId: ptrs.Ptr(int32(6))},
+			},
+			{
+				UserId:         4,
+				RoleAssignment: &rbacv1.RoleAssignment{Role: role, ScopeCluster: true},
+			},
+		},
+	)
+	require.Len(t, events, 2, "cluster-wide assignments aren't recorded")
+
+	require.Equal(t, 5, events[0].WorkspaceID)
+	require.Equal(t, model.WorkspaceEventMemberAdded, events[0].Kind)
+	require.Equal(t, model.UserID(7), *events[0].ActorID)
+	require.Equal(t, map[string]any{"group_id": 3, "role_id": 2}, events[0].Details)
+
+	require.Equal(t, 6, events[1].WorkspaceID)
+	require.Equal(t, map[string]any{"user_id": 4, "role_id": 2}, events[1].Details)
+}
@@ -0,0 +1,142 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+// Record adds an event to its workspace's feed.
+func Record(ctx context.Context, e *model.WorkspaceEvent) error {
+	if e.Details == nil {
+		e.Details = map[string]any{}
+	}
+	if _, err := db.Bun().NewInsert().Model(e).Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("recording %s event in workspace %d: %w", e.Kind, e.WorkspaceID, err)
+	}
+	return nil
+}
+
+// recordInProject adds an event to the feed of the workspace a project belongs to.
+func recordInProject(ctx context.Context, projectID int, e *model.WorkspaceEvent) error {
+	if _, err := db.Bun().NewInsert().Model(e).
+		Value("workspace_id", "(SELECT workspace_id FROM projects WHERE id = ?)", projectID).
+		Returning("id, workspace_id, created_at").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("recording %s event in project %d: %w", e.Kind, projectID, err)
+	}
+	return nil
+}
+
+// RecordExperimentCreated records that a user created an experiment.
+func RecordExperimentCreated(
+	ctx context.Context, actor model.UserID, e model.Experiment,
+	activeConfig expconf.ExperimentConfig,
+) error {
+	return recordInProject(ctx, e.ProjectID, &model.WorkspaceEvent{
+		Kind:         model.WorkspaceEventExperimentCreated,
+		ActorID:      &actor,
+		ExperimentID: &e.ID,
+		Details:      map[string]any{"name": activeConfig.Name().String()},
+	})
+}
+
+// RecordExperimentEnded records that an experiment completed or failed, attributed to its owner.
+// Experiments reaching other states aren't recorded.
+func RecordExperimentEnded(
+	ctx context.Context, e model.Experiment, activeConfig expconf.ExperimentConfig,
+) error {
+	kind, ok := experimentEndedKind(e.State)
+	if !ok {
+		return nil
+	}
+	return recordInProject(ctx, e.ProjectID, &model.WorkspaceEvent{
+		Kind:         kind,
+		ActorID:      e.OwnerID,
+		ExperimentID: &e.ID,
+		Details:      map[string]any{"name": activeConfig.Name().String()},
+	})
+}
+
+// RecordModelRegistered records that a user added a model to the registry of a workspace.
+func RecordModelRegistered(
+	ctx context.Context, actor model.UserID, workspaceID, modelID int, name string,
+) error {
+	return Record(ctx, &model.WorkspaceEvent{
+		WorkspaceID: workspaceID,
+		Kind:        model.WorkspaceEventModelRegistered,
+		ActorID:     &actor,
+		ModelID:     &modelID,
+		Details:     map[string]any{"name": name},
+	})
+}
+
+// RecordMembersAdded records that a user assigned roles in workspaces to users or groups, naming
+// the members and roles so the feed reads the same after they are renamed or deleted.
+func RecordMembersAdded(
+	ctx context.Context, actor model.UserID,
+	groups []*rbacv1.GroupRoleAssignment, users []*rbacv1.UserRoleAssignment,
+) error {
+	for _, e := range memberEvents(actor, groups, users) {
+		names := []struct {
+			key, table, column, idKey string
+		}{
+			{"role", "roles", "role_name", "role_id"},
+			{"group_name", "groups", "group_name", "group_id"},
+			{"username", "users", "username", "user_id"},
+		}
+		for _, n := range names {
+			id, ok := e.Details[n.idKey]
+			if !ok {
+				continue
+			}
+			var name string
+			if err := db.Bun().NewSelect().
+				Table(n.table).
+				Column(n.column).
+				Where("id = ?", id).
+				Scan(ctx, &name); err != nil {
+				return fmt.Errorf("looking up %s %v: %w", n.idKey, id, err)
+			}
+			e.Details[n.key] = name
+		}
+		if err := Record(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Feed returns a page of a workspace's events, newest first, starting before an event ID if one
+// is given. Only events of the given kinds are returned.
+func Feed(
+	ctx context.Context, workspaceID int, kinds []model.WorkspaceEventKind, beforeID *int64,
+	limit int,
+) ([]model.WorkspaceEvent, error) {
+	events := []model.WorkspaceEvent{}
+	if len(kinds) == 0 {
+		return events, nil
+	}
+	q := db.Bun().NewSelect().Model(&events).
+		ModelTableExpr("workspace_events AS we").
+		ColumnExpr("we.*").
+		ColumnExpr("u.username AS actor_username").
+		Join("LEFT JOIN users AS u ON u.id = we.actor_id").
+		Where("we.workspace_id = ?", workspaceID).
+		Where("we.kind IN (?)", bun.In(kinds)).
+		OrderExpr("we.id DESC").
+		Limit(limit)
+	if beforeID != nil {
+		q = q.Where("we.id < ?", *beforeID)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting activity of workspace %d: %w", workspaceID, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,89 @@
+//go:build integration
+// +build integration
+
+package activity
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestFeed(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	exp := db.RequireMockExperimentProject(t, db.SingleDB(), user, projectID)
+	activeConfig := schemas.WithDefaults(expconf.ExperimentConfig{
+		RawName: expconf.Name{RawString: ptrs.Ptr("resnet")},
+	})
+
+	require.NoError(t, RecordExperimentCreated(ctx, user.ID, *exp, activeConfig))
+	exp.State = model.StoppingCompletedState
+	require.NoError(t, RecordExperimentEnded(ctx, *exp, activeConfig), "not an ended state")
+	exp.State = model.CompletedState
+	require.NoError(t, RecordExperimentEnded(ctx, *exp, activeConfig))
+	require.NoError(t, RecordModelRegistered(ctx, user.ID, workspaceID, 12, "classifier"))
+
+	all := []model.WorkspaceEventKind{
+		model.WorkspaceEventExperimentCreated, model.WorkspaceEventExperimentCompleted,
+		model.WorkspaceEventExperimentFailed, model.WorkspaceEventModelRegistered,
+		model.WorkspaceEventMemberAdded,
+	}
+	events, err := Feed(ctx, workspaceID, all, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, model.WorkspaceEventModelRegistered, events[0].Kind)
+	require.Equal(t, model.WorkspaceEventExperimentCompleted, events[1].Kind)
+	require.Equal(t, model.WorkspaceEventExperimentCreated, events[2].Kind)
+	require.Equal(t, workspaceID, events[2].WorkspaceID, "resolved from the experiment's project")
+	require.Equal(t, exp.ID, *events[2].ExperimentID)
+	require.Equal(t, map[string]any{"name": "resnet"}, events[2].Details)
+	require.Equal(t, user.Username, *events[2].ActorUsername)
+
+	page, err := Feed(ctx, workspaceID, all, &events[0].ID, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, events[1].ID, page[0].ID)
+
+	page, err = Feed(ctx, workspaceID, []model.WorkspaceEventKind{
+		model.WorkspaceEventModelRegistered,
+	}, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, 12, *page[0].ModelID)
+
+	page, err = Feed(ctx, workspaceID, nil, nil, 10)
+	require.NoError(t, err)
+	require.Empty(t, page)
+}
@@ -0,0 +1,118 @@
+// Package announcement manages cluster-wide announcements and scheduled maintenance windows, and
+// blocks new submissions during maintenance windows that ask for it.
+package announcement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// MessageMaxLength caps the length of the message of an announcement or maintenance window.
+const MessageMaxLength = 1000
+
+// ErrSubmissionsBlocked is returned when an experiment or task is submitted during a maintenance
+// window that blocks submissions.
+var ErrSubmissionsBlocked = errors.New("new submissions are blocked for cluster maintenance")
+
+// Current is what users are shown: the active announcements and the ongoing and upcoming
+// maintenance windows.
+type Current struct {
+	Announcements      []model.Announcement      `json:"announcements"`
+	MaintenanceWindows []model.MaintenanceWindow `json:"maintenance_windows"`
+	SubmissionsBlocked bool                      `json:"submissions_blocked"`
+}
+
+// ValidateAnnouncement returns an error wrapping db.ErrInvalidInput if a is invalid, filling in
+// the default severity.
+func ValidateAnnouncement(a *model.Announcement) error {
+	if a.Severity == "" {
+		a.Severity = model.AnnouncementSeverityInfo
+	}
+	if err := validateMessage(a.Message); err != nil {
+		return err
+	}
+	if !a.Severity.Valid() {
+		return fmt.Errorf("%w: severity must be one of info, warning or critical; got %q",
+			db.ErrInvalidInput, a.Severity)
+	}
+	if a.EndTime != nil && !a.EndTime.After(a.StartTime) {
+		return fmt.Errorf("%w: end time must be after start time", db.ErrInvalidInput)
+	}
+	return nil
+}
+
+// ValidateMaintenanceWindow returns an error wrapping db.ErrInvalidInput if w is invalid.
+func ValidateMaintenanceWindow(w *model.MaintenanceWindow, now time.Time) error {
+	if err := validateMessage(w.Message); err != nil {
+		return err
+	}
+	if !w.EndTime.After(w.StartTime) {
+		return fmt.Errorf("%w: end time must be after start time", db.ErrInvalidInput)
+	}
+	if !w.EndTime.After(now) {
+		return fmt.Errorf("%w: end time must be in the future", db.ErrInvalidInput)
+	}
+	return nil
+}
+
+func validateMessage(msg string) error {
+	switch n := utf8.RuneCountInString(msg); {
+	case n == 0:
+		return fmt.Errorf("%w: message must be set", db.ErrInvalidInput)
+	case n > MessageMaxLength:
+		return fmt.Errorf("%w: message must be at most %d characters; got %d",
+			db.ErrInvalidInput, MessageMaxLength, n)
+	}
+	return nil
+}
+
+// CheckCanSubmit returns an error wrapping ErrSubmissionsBlocked if a maintenance window that
+// blocks submissions is underway, explaining why and until when.
+func CheckCanSubmit(ctx context.Context) error {
+	windows, err := MaintenanceWindows(ctx, false)
+	if err != nil {
+		return err
+	}
+	return blockingErr(windows, time.Now())
+}
+
+// blockingErr returns the error for the first of windows blocking submissions at now, if any.
+func blockingErr(windows []model.MaintenanceWindow, now time.Time) error {
+	for _, w := range windows {
+		if w.BlockSubmissions && w.Active(now) {
+			return fmt.Errorf("%w until %s: %s",
+				ErrSubmissionsBlocked, w.EndTime.UTC().Format(time.RFC3339), w.Message)
+		}
+	}
+	return nil
+}
+
+// GetCurrent returns the announcements and maintenance windows to show users at now.
+func GetCurrent(ctx context.Context, now time.Time) (*Current, error) {
+	announcements, err := Announcements(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := MaintenanceWindows(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := &Current{
+		Announcements:      []model.Announcement{},
+		MaintenanceWindows: windows,
+		SubmissionsBlocked: blockingErr(windows, now) != nil,
+	}
+	for _, a := range announcements {
+		if a.Active(now) {
+			cur.Announcements = append(cur.Announcements, a)
+		}
+	}
+	return cur, nil
+}
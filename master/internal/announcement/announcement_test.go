@@ -0,0 +1,67 @@
+package announcement
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestValidateAnnouncement(t *testing.T) {
+	now := time.Now()
+
+	a := &model.Announcement{Message: "upgrading tonight", StartTime: now}
+	require.NoError(t, ValidateAnnouncement(a))
+	require.Equal(t, model.AnnouncementSeverityInfo, a.Severity)
+
+	for _, a := range []*model.Announcement{
+		{StartTime: now},
+		{Message: strings.Repeat("x", MessageMaxLength+1), StartTime: now},
+		{Message: "m", Severity: "urgent", StartTime: now},
+		{Message: "m", StartTime: now, EndTime: ptrs.Ptr(now.Add(-time.Minute))},
+	} {
+		require.ErrorIs(t, ValidateAnnouncement(a), db.ErrInvalidInput)
+	}
+}
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	now := time.Now()
+
+	require.NoError(t, ValidateMaintenanceWindow(&model.MaintenanceWindow{
+		Message: "m", StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour),
+	}, now))
+	require.ErrorIs(t, ValidateMaintenanceWindow(&model.MaintenanceWindow{
+		Message: "m", StartTime: now.Add(time.Hour), EndTime: now,
+	}, now), db.ErrInvalidInput)
+	require.ErrorIs(t, ValidateMaintenanceWindow(&model.MaintenanceWindow{
+		Message: "m", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour),
+	}, now), db.ErrInvalidInput)
+}
+
+func TestBlockingErr(t *testing.T) {
+	now := time.Date(2024, 11, 13, 12, 0, 0, 0, time.UTC)
+	upcoming := model.MaintenanceWindow{
+		Message: "upgrade", StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour),
+		BlockSubmissions: true,
+	}
+	nonBlocking := model.MaintenanceWindow{
+		Message: "network work", StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour),
+	}
+	require.NoError(t, blockingErr([]model.MaintenanceWindow{upcoming, nonBlocking}, now))
+
+	ongoing := nonBlocking
+	ongoing.Message = "storage migration"
+	ongoing.BlockSubmissions = true
+	err := blockingErr([]model.MaintenanceWindow{nonBlocking, ongoing}, now)
+	require.ErrorIs(t, err, ErrSubmissionsBlocked)
+	require.Equal(t, "new submissions are blocked for cluster maintenance until "+
+		"2024-11-13T13:00:00Z: storage migration", err.Error())
+
+	// Windows end exactly at their end time.
+	require.NoError(t, blockingErr([]model.MaintenanceWindow{ongoing}, ongoing.EndTime))
+}
@@ -0,0 +1,77 @@
+package announcement
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// CreateAnnouncement publishes an announcement.
+func CreateAnnouncement(ctx context.Context, a *model.Announcement) error {
+	if _, err := db.Bun().NewInsert().Model(a).Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("creating announcement: %w", err)
+	}
+	return nil
+}
+
+// Announcements returns announcements ordered by start time, including those that haven't started
+// yet. Expired announcements are only included if includeExpired is set.
+func Announcements(ctx context.Context, includeExpired bool) ([]model.Announcement, error) {
+	announcements := []model.Announcement{}
+	q := db.Bun().NewSelect().Model(&announcements).Order("start_time", "id")
+	if !includeExpired {
+		q = q.Where("end_time IS NULL OR end_time > NOW()")
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting announcements: %w", err)
+	}
+	return announcements, nil
+}
+
+// DeleteAnnouncement deletes an announcement, returning db.ErrNotFound if it doesn't exist.
+func DeleteAnnouncement(ctx context.Context, id int) error {
+	return deleteByID(ctx, (*model.Announcement)(nil), "announcement", id)
+}
+
+// CreateMaintenanceWindow schedules a maintenance window.
+func CreateMaintenanceWindow(ctx context.Context, w *model.MaintenanceWindow) error {
+	if _, err := db.Bun().NewInsert().Model(w).Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("creating maintenance window: %w", err)
+	}
+	return nil
+}
+
+// MaintenanceWindows returns maintenance windows ordered by start time, including upcoming ones.
+// Finished windows are only included if includePast is set.
+func MaintenanceWindows(ctx context.Context, includePast bool) ([]model.MaintenanceWindow, error) {
+	windows := []model.MaintenanceWindow{}
+	q := db.Bun().NewSelect().Model(&windows).Order("start_time", "id")
+	if !includePast {
+		q = q.Where("end_time > NOW()")
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
+// DeleteMaintenanceWindow deletes a maintenance window, ending it early if it is underway. It
+// returns db.ErrNotFound if the window doesn't exist.
+func DeleteMaintenanceWindow(ctx context.Context, id int) error {
+	return deleteByID(ctx, (*model.MaintenanceWindow)(nil), "maintenance window", id)
+}
+
+func deleteByID(ctx context.Context, m any, kind string, id int) error {
+	res, err := db.Bun().NewDelete().Model(m).Where("id = ?", id).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting %s %d: %w", kind, id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
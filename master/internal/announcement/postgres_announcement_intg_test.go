@@ -0,0 +1,118 @@
+//go:build integration
+// +build integration
+
+package announcement
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func announcementIDs(as []model.Announcement) []int {
+	var ids []int
+	for _, a := range as {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestAnnouncements(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	now := time.Now()
+
+	active := &model.Announcement{
+		Message: "active", Severity: model.AnnouncementSeverityWarning,
+		StartTime: now.Add(-time.Hour), CreatedBy: user.ID,
+	}
+	upcoming := &model.Announcement{
+		Message: "upcoming", Severity: model.AnnouncementSeverityInfo,
+		StartTime: now.Add(time.Hour), CreatedBy: user.ID,
+	}
+	expired := &model.Announcement{
+		Message: "expired", Severity: model.AnnouncementSeverityInfo,
+		StartTime: now.Add(-2 * time.Hour), EndTime: ptrs.Ptr(now.Add(-time.Hour)), CreatedBy: user.ID,
+	}
+	for _, a := range []*model.Announcement{active, upcoming, expired} {
+		require.NoError(t, CreateAnnouncement(ctx, a))
+		require.NotZero(t, a.ID)
+	}
+
+	as, err := Announcements(ctx, false)
+	require.NoError(t, err)
+	require.Subset(t, announcementIDs(as), []int{active.ID, upcoming.ID})
+	require.NotContains(t, announcementIDs(as), expired.ID)
+
+	as, err = Announcements(ctx, true)
+	require.NoError(t, err)
+	require.Subset(t, announcementIDs(as), []int{active.ID, upcoming.ID, expired.ID})
+
+	cur, err := GetCurrent(ctx, now)
+	require.NoError(t, err)
+	require.Contains(t, announcementIDs(cur.Announcements), active.ID)
+	require.NotContains(t, announcementIDs(cur.Announcements), upcoming.ID)
+
+	for _, a := range []*model.Announcement{active, upcoming, expired} {
+		require.NoError(t, DeleteAnnouncement(ctx, a.ID))
+	}
+	require.ErrorIs(t, DeleteAnnouncement(ctx, active.ID), db.ErrNotFound)
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	now := time.Now()
+
+	w := &model.MaintenanceWindow{
+		Message: "upgrading storage", StartTime: now.Add(-time.Minute), EndTime: now.Add(time.Hour),
+		CreatedBy: user.ID,
+	}
+	require.NoError(t, CreateMaintenanceWindow(ctx, w))
+	require.NoError(t, CheckCanSubmit(ctx))
+
+	blocking := &model.MaintenanceWindow{
+		Message: "upgrading the master", StartTime: now.Add(-time.Minute), EndTime: now.Add(time.Hour),
+		BlockSubmissions: true, CreatedBy: user.ID,
+	}
+	require.NoError(t, CreateMaintenanceWindow(ctx, blocking))
+	require.ErrorIs(t, CheckCanSubmit(ctx), ErrSubmissionsBlocked)
+
+	cur, err := GetCurrent(ctx, time.Now())
+	require.NoError(t, err)
+	require.True(t, cur.SubmissionsBlocked)
+	require.Len(t, cur.MaintenanceWindows, 2)
+
+	require.NoError(t, DeleteMaintenanceWindow(ctx, blocking.ID))
+	require.NoError(t, CheckCanSubmit(ctx))
+	require.NoError(t, DeleteMaintenanceWindow(ctx, w.ID))
+	require.ErrorIs(t, DeleteMaintenanceWindow(ctx, w.ID), db.ErrNotFound)
+}
@@ -7,9 +7,12 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/httppolicy"
 )
 
-var upgrader = websocket.Upgrader{}
+// upgrader allows websockets from the master's origin and the origins the HTTP policy allows.
+var upgrader = websocket.Upgrader{CheckOrigin: httppolicy.CheckOrigin}
 
 // Route returns an echo compatible handler for JSON requests.
 func Route(handler func(c echo.Context) (interface{}, error)) echo.HandlerFunc {
@@ -39,11 +42,8 @@ func Route(handler func(c echo.Context) (interface{}, error)) echo.HandlerFunc {
 }
 
 // WebSocketRoute upgrades incoming requests to websocket requests.
-func WebSocketRoute(handler func(socket *websocket.Conn, c echo.Context) error, enableCORS bool) echo.HandlerFunc {
+func WebSocketRoute(handler func(socket *websocket.Conn, c echo.Context) error) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		if enableCORS {
-			upgrader.CheckOrigin = func(r *http.Request) bool { return true }
-		}
 		ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
 		if err != nil {
 			c.Logger().Error("websocket connection error: ", err)
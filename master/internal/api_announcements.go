@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/announcement"
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+// checkCanManageAnnouncements checks the user can manage announcements and maintenance windows,
+// which takes the same permission as setting the cluster message.
+func checkCanManageAnnouncements(ctx context.Context) (*model.User, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, curUser)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, status.Error(codes.PermissionDenied, permErr.Error())
+	}
+	return curUser, nil
+}
+
+// checkCanSubmit returns a FailedPrecondition error if a maintenance window is blocking new
+// experiments and tasks.
+func checkCanSubmit(ctx context.Context) error {
+	if err := announcement.CheckCanSubmit(ctx); errors.Is(err, announcement.ErrSubmissionsBlocked) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func announcementsToProto(announcements []model.Announcement) []*masterv1.Announcement {
+	pbs := []*masterv1.Announcement{}
+	for _, a := range announcements {
+		pbs = append(pbs, a.Proto())
+	}
+	return pbs
+}
+
+func maintenanceWindowsToProto(windows []model.MaintenanceWindow) []*masterv1.MaintenanceWindow {
+	pbs := []*masterv1.MaintenanceWindow{}
+	for _, w := range windows {
+		pbs = append(pbs, w.Proto())
+	}
+	return pbs
+}
+
+func (a *apiServer) GetCurrentAnnouncements(
+	ctx context.Context, req *apiv1.GetCurrentAnnouncementsRequest,
+) (*apiv1.GetCurrentAnnouncementsResponse, error) {
+	cur, err := announcement.GetCurrent(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetCurrentAnnouncementsResponse{
+		Announcements:      announcementsToProto(cur.Announcements),
+		MaintenanceWindows: maintenanceWindowsToProto(cur.MaintenanceWindows),
+		SubmissionsBlocked: cur.SubmissionsBlocked,
+	}, nil
+}
+
+func (a *apiServer) GetAnnouncements(
+	ctx context.Context, req *apiv1.GetAnnouncementsRequest,
+) (*apiv1.GetAnnouncementsResponse, error) {
+	if _, err := checkCanManageAnnouncements(ctx); err != nil {
+		return nil, err
+	}
+
+	announcements, err := announcement.Announcements(ctx, req.IncludeExpired)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetAnnouncementsResponse{
+		Announcements: announcementsToProto(announcements),
+	}, nil
+}
+
+func (a *apiServer) PostAnnouncement(
+	ctx context.Context, req *apiv1.PostAnnouncementRequest,
+) (*apiv1.PostAnnouncementResponse, error) {
+	curUser, err := checkCanManageAnnouncements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ann := &model.Announcement{
+		Message:   req.Message,
+		Severity:  model.AnnouncementSeverityFromProto(req.Severity),
+		StartTime: time.Now().UTC(),
+		CreatedBy: curUser.ID,
+	}
+	if req.StartTime != nil {
+		ann.StartTime = req.StartTime.AsTime()
+	}
+	if req.EndTime != nil {
+		end := req.EndTime.AsTime()
+		ann.EndTime = &end
+	}
+	if err = announcement.ValidateAnnouncement(ann); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = announcement.CreateAnnouncement(ctx, ann); err != nil {
+		return nil, err
+	}
+	return &apiv1.PostAnnouncementResponse{Announcement: ann.Proto()}, nil
+}
+
+func (a *apiServer) DeleteAnnouncement(
+	ctx context.Context, req *apiv1.DeleteAnnouncementRequest,
+) (*apiv1.DeleteAnnouncementResponse, error) {
+	if _, err := checkCanManageAnnouncements(ctx); err != nil {
+		return nil, err
+	}
+
+	err := announcement.DeleteAnnouncement(ctx, int(req.AnnouncementId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("announcement", strconv.Itoa(int(req.AnnouncementId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteAnnouncementResponse{}, nil
+}
+
+func (a *apiServer) GetMaintenanceWindows(
+	ctx context.Context, req *apiv1.GetMaintenanceWindowsRequest,
+) (*apiv1.GetMaintenanceWindowsResponse, error) {
+	if _, err := checkCanManageAnnouncements(ctx); err != nil {
+		return nil, err
+	}
+
+	windows, err := announcement.MaintenanceWindows(ctx, req.IncludePast)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetMaintenanceWindowsResponse{
+		MaintenanceWindows: maintenanceWindowsToProto(windows),
+	}, nil
+}
+
+func (a *apiServer) PostMaintenanceWindow(
+	ctx context.Context, req *apiv1.PostMaintenanceWindowRequest,
+) (*apiv1.PostMaintenanceWindowResponse, error) {
+	if req.StartTime == nil || req.EndTime == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time and end_time must be set")
+	}
+	curUser, err := checkCanManageAnnouncements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &model.MaintenanceWindow{
+		Message:          req.Message,
+		StartTime:        req.StartTime.AsTime(),
+		EndTime:          req.EndTime.AsTime(),
+		BlockSubmissions: req.BlockSubmissions,
+		CreatedBy:        curUser.ID,
+	}
+	if err = announcement.ValidateMaintenanceWindow(w, time.Now()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = announcement.CreateMaintenanceWindow(ctx, w); err != nil {
+		return nil, err
+	}
+	return &apiv1.PostMaintenanceWindowResponse{MaintenanceWindow: w.Proto()}, nil
+}
+
+func (a *apiServer) DeleteMaintenanceWindow(
+	ctx context.Context, req *apiv1.DeleteMaintenanceWindowRequest,
+) (*apiv1.DeleteMaintenanceWindowResponse, error) {
+	if _, err := checkCanManageAnnouncements(ctx); err != nil {
+		return nil, err
+	}
+
+	err := announcement.DeleteMaintenanceWindow(ctx, int(req.WindowId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("maintenance window", strconv.Itoa(int(req.WindowId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteMaintenanceWindowResponse{}, nil
+}
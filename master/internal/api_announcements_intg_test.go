@@ -0,0 +1,87 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+func TestAnnouncements(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+
+	created, err := api.PostAnnouncement(ctx, &apiv1.PostAnnouncementRequest{
+		Message:  "GPU nodes are being upgraded",
+		Severity: masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_WARNING,
+	})
+	require.NoError(t, err)
+	require.Equal(t, masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_WARNING,
+		created.Announcement.Severity)
+
+	// The current announcements don't need a user.
+	cur, err := api.GetCurrentAnnouncements(context.Background(),
+		&apiv1.GetCurrentAnnouncementsRequest{})
+	require.NoError(t, err)
+	var ids []int32
+	for _, a := range cur.Announcements {
+		ids = append(ids, a.Id)
+	}
+	require.Contains(t, ids, created.Announcement.Id)
+
+	req := &apiv1.DeleteAnnouncementRequest{AnnouncementId: created.Announcement.Id}
+	_, err = api.DeleteAnnouncement(ctx, req)
+	require.NoError(t, err)
+	_, err = api.DeleteAnnouncement(ctx, req)
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.PostAnnouncement(ctx, &apiv1.PostAnnouncementRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestMaintenanceWindows(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	now := time.Now()
+
+	created, err := api.PostMaintenanceWindow(ctx, &apiv1.PostMaintenanceWindowRequest{
+		Message:   "Upgrading the master",
+		StartTime: timestamppb.New(now.Add(time.Hour)),
+		EndTime:   timestamppb.New(now.Add(2 * time.Hour)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(curUser.ID), created.MaintenanceWindow.CreatedBy)
+
+	windows, err := api.GetMaintenanceWindows(ctx, &apiv1.GetMaintenanceWindowsRequest{})
+	require.NoError(t, err)
+	var ids []int32
+	for _, w := range windows.MaintenanceWindows {
+		ids = append(ids, w.Id)
+	}
+	require.Contains(t, ids, created.MaintenanceWindow.Id)
+
+	_, err = api.DeleteMaintenanceWindow(ctx, &apiv1.DeleteMaintenanceWindowRequest{
+		WindowId: created.MaintenanceWindow.Id,
+	})
+	require.NoError(t, err)
+
+	for _, req := range []*apiv1.PostMaintenanceWindowRequest{
+		{Message: "m", StartTime: timestamppb.New(now)},
+		{
+			Message:   "m",
+			StartTime: timestamppb.New(now.Add(-2 * time.Hour)),
+			EndTime:   timestamppb.New(now.Add(-time.Hour)),
+		},
+	} {
+		_, err = api.PostMaintenanceWindow(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	}
+}
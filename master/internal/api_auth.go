@@ -179,7 +179,50 @@ func processProxyAuthentication(c echo.Context) (done bool, err error) {
 		err = command.AuthZProvider.Get().CanGetNSC(
 			ctx, *usr, spec.WorkspaceID)
 	}
-	return err != nil, authz.SubIfUnauthorized(err, serviceNotFoundErr)
+	if err != nil {
+		return true, authz.SubIfUnauthorized(err, serviceNotFoundErr)
+	}
+	return processProxyShare(ctx, c, *usr, taskID, spec)
+}
+
+// processProxyShare enforces the access a task's owner has shared with other users. Owners and
+// admins are unrestricted. Other users need a share to reach a notebook, and a read-only share
+// restricts them to viewing the proxied service.
+func processProxyShare(
+	ctx context.Context, c echo.Context, usr model.User, taskID model.TaskID,
+	spec command.TaskMetadata,
+) (done bool, err error) {
+	if usr.ID == spec.OwnerID || usr.Admin {
+		return false, nil
+	}
+
+	share, err := command.ProxyShareForUser(ctx, taskID, usr.ID)
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		if spec.TaskType == model.TaskTypeNotebook {
+			return true, echo.NewHTTPError(http.StatusForbidden,
+				fmt.Sprintf("notebook %s has not been shared with you", taskID))
+		}
+		return false, nil
+	case err != nil:
+		return true, err
+	}
+
+	if !share.Access.AllowsRequest(c.Request(), c.IsWebSocket()) {
+		return true, echo.NewHTTPError(http.StatusForbidden,
+			fmt.Sprintf("you have read-only access to %s", taskID))
+	}
+
+	if spec.TaskType == model.TaskTypeNotebook {
+		// Shared users don't hold the notebook's token, so present it to the notebook server on
+		// their behalf.
+		token, err := command.NotebookTokenForTask(ctx, taskID)
+		if err != nil {
+			return true, err
+		}
+		c.Request().Header.Set("Authorization", "token "+token)
+	}
+	return false, nil
 }
 
 // extractNotebookTokenFromRequest looks for auth token for Jupyter notebooks
@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/boostrequest"
+	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// checkCanReviewBoostRequests checks the user can review boost requests, returning the user.
+// Approving a boost overrides the priority and max slots an experiment's owner could set
+// themselves, so it takes the same permission as updating the master config.
+func checkCanReviewBoostRequests(ctx context.Context) (*model.User, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, curUser)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, status.Error(codes.PermissionDenied, permErr.Error())
+	}
+	return curUser, nil
+}
+
+// boostReviewErr converts an error reviewing a boost request to a gRPC error.
+func boostReviewErr(id int32, err error) error {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return api.NotFoundErrs("boost request", strconv.Itoa(int(id)), true)
+	case errors.Is(err, boostrequest.ErrNotPending), errors.Is(err, boostrequest.ErrAlreadyBoosted),
+		errors.Is(err, errExperimentNotActive):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return err
+	}
+}
+
+func boostRequestsToProto(requests []model.BoostRequest) []*experimentv1.BoostRequest {
+	pbs := []*experimentv1.BoostRequest{}
+	for _, r := range requests {
+		pbs = append(pbs, r.Proto())
+	}
+	return pbs
+}
+
+func (a *apiServer) PostExperimentBoostRequest(
+	ctx context.Context, req *apiv1.PostExperimentBoostRequestRequest,
+) (*apiv1.PostExperimentBoostRequestResponse, error) {
+	kind, err := model.BoostRequestKindFromProto(req.Kind)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	e, curUser, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanEditExperiment)
+	if err != nil {
+		return nil, err
+	}
+	if model.TerminalStates[e.State] {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"experiment %d has already finished", e.ID)
+	}
+
+	r := &model.BoostRequest{
+		ExperimentID:    e.ID,
+		Kind:            kind,
+		Value:           int(req.Value),
+		DurationSeconds: int(req.DurationSeconds),
+		Reason:          req.Reason,
+		RequestedBy:     curUser.ID,
+	}
+	if err = boostrequest.Validate(r); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	current, err := a.m.boostedValue(e.ID, r.Kind)
+	if err != nil {
+		return nil, err
+	}
+	var smallerIsHigher bool
+	if r.Kind == model.BoostRequestKindPriority {
+		if smallerIsHigher, err = a.m.rm.SmallerValueIsHigherPriority(); err != nil {
+			return nil, status.Error(codes.InvalidArgument,
+				"the resource manager doesn't support priorities: "+err.Error())
+		}
+	}
+	if !boostrequest.Raises(r.Kind, current, r.Value, smallerIsHigher) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"requested %s %d isn't a boost over the experiment's current %s",
+			r.Kind, r.Value, r.Kind)
+	}
+
+	if err = boostrequest.CreateBoostRequest(ctx, r); err != nil {
+		return nil, err
+	}
+	return &apiv1.PostExperimentBoostRequestResponse{BoostRequest: r.Proto()}, nil
+}
+
+func (a *apiServer) GetExperimentBoostRequests(
+	ctx context.Context, req *apiv1.GetExperimentBoostRequestsRequest,
+) (*apiv1.GetExperimentBoostRequestsResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId)); err != nil {
+		return nil, err
+	}
+
+	expID := int(req.ExperimentId)
+	requests, err := boostrequest.BoostRequests(ctx, nil, &expID)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetExperimentBoostRequestsResponse{
+		BoostRequests: boostRequestsToProto(requests),
+	}, nil
+}
+
+func (a *apiServer) GetBoostRequests(
+	ctx context.Context, req *apiv1.GetBoostRequestsRequest,
+) (*apiv1.GetBoostRequestsResponse, error) {
+	var state *model.BoostRequestState
+	if req.State != experimentv1.BoostRequestState_BOOST_REQUEST_STATE_UNSPECIFIED {
+		s, err := model.BoostRequestStateFromProto(req.State)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		state = &s
+	}
+	if _, err := checkCanReviewBoostRequests(ctx); err != nil {
+		return nil, err
+	}
+
+	requests, err := boostrequest.BoostRequests(ctx, state, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetBoostRequestsResponse{BoostRequests: boostRequestsToProto(requests)}, nil
+}
+
+func (a *apiServer) PostApproveBoostRequest(
+	ctx context.Context, req *apiv1.PostApproveBoostRequestRequest,
+) (*apiv1.PostApproveBoostRequestResponse, error) {
+	reviewer, err := checkCanReviewBoostRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := boostrequest.BoostRequestByID(ctx, int(req.RequestId))
+	if err != nil {
+		return nil, boostReviewErr(req.RequestId, err)
+	}
+	original, err := a.m.boostedValue(r.ExperimentID, r.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err = boostrequest.Approve(ctx, int(req.RequestId), reviewer.ID, req.Comment, original,
+		func(r *model.BoostRequest) error {
+			return a.m.applyBoost(r.ExperimentID, r.Kind, &r.Value)
+		})
+	if err != nil {
+		return nil, boostReviewErr(req.RequestId, err)
+	}
+	log.Infof("approved boost request %d: %s of experiment %d set to %d until %s",
+		r.ID, r.Kind, r.ExperimentID, r.Value, r.ExpiresAt.Format(time.RFC3339))
+	return &apiv1.PostApproveBoostRequestResponse{BoostRequest: r.Proto()}, nil
+}
+
+func (a *apiServer) PostDenyBoostRequest(
+	ctx context.Context, req *apiv1.PostDenyBoostRequestRequest,
+) (*apiv1.PostDenyBoostRequestResponse, error) {
+	reviewer, err := checkCanReviewBoostRequests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := boostrequest.Deny(ctx, int(req.RequestId), reviewer.ID, req.Comment)
+	if err != nil {
+		return nil, boostReviewErr(req.RequestId, err)
+	}
+	return &apiv1.PostDenyBoostRequestResponse{BoostRequest: r.Proto()}, nil
+}
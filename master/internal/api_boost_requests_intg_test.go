@@ -0,0 +1,46 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+func TestBoostRequests(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+
+	listResp, err := api.GetExperimentBoostRequests(ctx, &apiv1.GetExperimentBoostRequestsRequest{
+		ExperimentId: int32(exp.ID),
+	})
+	require.NoError(t, err)
+	require.Empty(t, listResp.BoostRequests)
+
+	_, err = api.GetBoostRequests(ctx, &apiv1.GetBoostRequestsRequest{
+		State: experimentv1.BoostRequestState_BOOST_REQUEST_STATE_PENDING,
+	})
+	require.NoError(t, err)
+
+	_, err = api.PostExperimentBoostRequest(ctx, &apiv1.PostExperimentBoostRequestRequest{
+		ExperimentId:    int32(exp.ID),
+		DurationSeconds: 3600,
+		Reason:          "deadline",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.PostApproveBoostRequest(ctx, &apiv1.PostApproveBoostRequestRequest{
+		RequestId: -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+	_, err = api.PostDenyBoostRequest(ctx, &apiv1.PostDenyBoostRequestRequest{RequestId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
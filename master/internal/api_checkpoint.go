@@ -15,15 +15,18 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/checkpoints"
+	"github.com/determined-ai/determined/master/internal/cluster"
 	internaldb "github.com/determined-ai/determined/master/internal/db"
 	expauth "github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	modelauth "github.com/determined-ai/determined/master/internal/model"
 	"github.com/determined-ai/determined/master/internal/storage"
+	"github.com/determined-ai/determined/master/internal/storageusage"
 	"github.com/determined-ai/determined/master/internal/trials"
 	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/internal/workspace"
@@ -110,6 +113,21 @@ func (m *Master) canDoActionOnCheckpointThroughModel(
 	return authz.SubIfUnauthorized(errCanGetModel, api.NotFoundErrs("checkpoint", ckptID, true))
 }
 
+// canGetCheckpointArtifacts checks that a user can get a checkpoint's files, through either its
+// experiment or a model it is registered to.
+func (m *Master) canGetCheckpointArtifacts(
+	ctx context.Context, curUser model.User, ckptID string,
+) error {
+	errE := m.canDoActionOnCheckpoint(ctx, curUser, ckptID,
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts)
+	if errE != nil {
+		if errM := m.canDoActionOnCheckpointThroughModel(ctx, curUser, ckptID); errM != nil {
+			return errE
+		}
+	}
+	return nil
+}
+
 func (a *apiServer) GetCheckpoint(
 	ctx context.Context, req *apiv1.GetCheckpointRequest,
 ) (*apiv1.GetCheckpointResponse, error) {
@@ -118,14 +136,8 @@ func (a *apiServer) GetCheckpoint(
 		return nil, err
 	}
 
-	errE := a.m.canDoActionOnCheckpoint(ctx, *curUser, req.CheckpointUuid,
-		expauth.AuthZProvider.Get().CanGetExperimentArtifacts)
-
-	if errE != nil {
-		errM := a.m.canDoActionOnCheckpointThroughModel(ctx, *curUser, req.CheckpointUuid)
-		if errM != nil {
-			return nil, errE
-		}
+	if err = a.m.canGetCheckpointArtifacts(ctx, *curUser, req.CheckpointUuid); err != nil {
+		return nil, err
 	}
 
 	resp := &apiv1.GetCheckpointResponse{}
@@ -285,8 +297,8 @@ func (a *apiServer) PatchCheckpoints(
 		return nil, status.Errorf(codes.InvalidArgument, *errMsg)
 	}
 
+	var updatedCheckpointSizes []uuid.UUID
 	err = internaldb.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
-		var updatedCheckpointSizes []uuid.UUID
 		for i, c := range req.Checkpoints {
 			if c.Resources != nil {
 				size := int64(0)
@@ -345,6 +357,11 @@ func (a *apiServer) PatchCheckpoints(
 	if err != nil {
 		return nil, fmt.Errorf("error patching checkpoints: %w", err)
 	}
+	if len(updatedCheckpointSizes) > 0 {
+		if err := storageusage.CheckQuotas(ctx, updatedCheckpointSizes); err != nil {
+			log.WithError(err).Error("checking workspace storage quotas")
+		}
+	}
 
 	return &apiv1.PatchCheckpointsResponse{}, nil
 }
@@ -474,7 +491,7 @@ func (a *apiServer) PostCheckpointMetadata(
 	}
 
 	if err := a.m.canDoActionOnCheckpoint(ctx, *curUser, req.Checkpoint.Uuid,
-		expauth.AuthZProvider.Get().CanEditExperiment); err != nil {
+		expauth.AuthZProvider.Get().CanEditExperimentsMetadata); err != nil {
 		return nil, err
 	}
 
@@ -497,8 +514,17 @@ func (a *apiServer) PostCheckpointMetadata(
 	currCheckpoint.Metadata = req.Checkpoint.Metadata
 	log.Infof("checkpoint (%s) metadata changing from %s to %s",
 		req.Checkpoint.Uuid, currMeta, newMeta)
-	err = a.m.db.QueryProto("update_checkpoint_metadata",
-		&checkpointv1.Checkpoint{}, req.Checkpoint.Uuid, newMeta)
+	ckptUUID, err := uuid.Parse(req.Checkpoint.Uuid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	_, err = checkpoints.UpdateMetadata(ctx, ckptUUID, curUser.ID,
+		func(map[string]interface{}) map[string]interface{} {
+			return req.Checkpoint.Metadata.AsMap()
+		})
+	if errors.Is(err, internaldb.ErrInvalidInput) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	return &apiv1.PostCheckpointMetadataResponse{Checkpoint: currCheckpoint},
 		errors.Wrapf(err, "error updating checkpoint %s in database", req.Checkpoint.Uuid)
@@ -534,3 +560,46 @@ func (a *apiServer) GetTrialMetricsByCheckpoint(
 	resp.Metrics = append(resp.Metrics, metrics...)
 	return resp, nil
 }
+
+func (a *apiServer) PostCheckpointStorageVerify(
+	ctx context.Context, req *apiv1.PostCheckpointStorageVerifyRequest,
+) (*apiv1.PostCheckpointStorageVerifyResponse, error) {
+	// Probing writes to the storage of every workspace.
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, curUser)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, permErr
+	}
+
+	results, err := storage.ProbeConfigured(ctx, a.m.config.CheckpointStorage)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.PostCheckpointStorageVerifyResponse{Healthy: true}
+	for _, r := range results {
+		resp.Healthy = resp.Healthy && r.Status != storage.ProbeFailed
+		bytes, err := r.Config.Printable().MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		config := &structpb.Struct{}
+		if err = config.UnmarshalJSON(bytes); err != nil {
+			return nil, err
+		}
+		resp.Results = append(resp.Results, &apiv1.CheckpointStorageProbeResult{
+			Name:            r.Name,
+			UsedBy:          r.UsedBy,
+			Type:            r.Type,
+			Config:          config,
+			Status:          string(r.Status),
+			Error:           r.Error,
+			DurationSeconds: r.DurationSeconds,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	ckpt "github.com/determined-ai/determined/master/internal/checkpoints"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/pkg/checkpoints"
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+	"github.com/determined-ai/determined/master/pkg/checkpoints/inspect"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) GetCheckpointInspect(
+	ctx context.Context, req *apiv1.GetCheckpointInspectRequest,
+) (*apiv1.GetCheckpointInspectResponse, error) {
+	id, err := uuid.Parse(req.CheckpointUuid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unable to parse checkpoint UUID %s: %s", req.CheckpointUuid, err)
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.m.canGetCheckpointArtifacts(ctx, *curUser, req.CheckpointUuid); err != nil {
+		return nil, err
+	}
+
+	summary, err := a.m.inspectCheckpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetCheckpointInspectResponse{Summary: summary.Proto()}, nil
+}
+
+func (m *Master) inspectCheckpoint(ctx context.Context, id uuid.UUID) (*inspect.Summary, error) {
+	storageConfig, err := m.getCheckpointStorageConfig(ctx, id)
+	switch {
+	case err != nil:
+		return nil, status.Errorf(codes.Internal,
+			"unable to retrieve experiment config for checkpoint %s: %s", id, err)
+	case storageConfig == nil:
+		return nil, api.NotFoundErrs("checkpoint", id.String(), true)
+	}
+
+	// Nothing is written to the archive; files are only listed and read in part.
+	aw, err := archive.NewArchiveWriter(io.Discard, archive.ArchiveTar)
+	if err != nil {
+		return nil, err
+	}
+	downloader, err := checkpoints.NewDownloader(ctx, io.Discard, id.String(), storageConfig, aw)
+	if err != nil {
+		// Storage the master can't read is summarized from the files the checkpoint reported.
+		return inspectReportedCheckpointFiles(ctx, id, err)
+	}
+	defer func() {
+		_ = downloader.Close()
+	}()
+
+	files, err := downloader.ListFiles(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to list checkpoint %s files: %s", id, err)
+	}
+	return inspect.Inspect(ctx, downloader, files)
+}
+
+func inspectReportedCheckpointFiles(
+	ctx context.Context, id uuid.UUID, storageErr error,
+) (*inspect.Summary, error) {
+	checkpoint, err := ckpt.CheckpointByUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if checkpoint == nil {
+		return nil, api.NotFoundErrs("checkpoint", id.String(), true)
+	}
+
+	var files []archive.FileEntry
+	for path, size := range checkpoint.Resources {
+		// Directories are reported with a trailing slash.
+		if strings.HasSuffix(path, "/") {
+			continue
+		}
+		f := archive.FileEntry{Path: path}
+		if s, ok := size.(float64); ok {
+			f.Size = int64(s)
+		}
+		files = append(files, f)
+	}
+	s, err := inspect.Inspect(ctx, nil, files)
+	if err != nil {
+		return nil, err
+	}
+	s.Warnings = append(s.Warnings, storageErr.Error())
+	return s, nil
+}
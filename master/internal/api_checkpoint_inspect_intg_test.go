@@ -0,0 +1,27 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestGetCheckpointInspectErrors(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+
+	_, err := api.GetCheckpointInspect(ctx,
+		&apiv1.GetCheckpointInspectRequest{CheckpointUuid: "not-a-uuid"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.GetCheckpointInspect(ctx,
+		&apiv1.GetCheckpointInspectRequest{CheckpointUuid: uuid.NewString()})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
@@ -24,6 +24,7 @@ import (
 	apiPkg "github.com/determined-ai/determined/master/internal/api"
 	authz2 "github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
@@ -600,12 +601,23 @@ func TestCheckpointAuthZ(t *testing.T) {
 			})
 			return err
 		}, true},
-		{"CanEditExperiment", func(id string) error {
+		{"CanEditExperimentsMetadata", func(id string) error {
 			_, err := api.PostCheckpointMetadata(ctx, &apiv1.PostCheckpointMetadataRequest{
 				Checkpoint: &checkpointv1.Checkpoint{Uuid: id},
 			})
 			return err
 		}, false},
+		{"CanEditExperimentsMetadata", func(id string) error {
+			_, err := api.PatchCheckpointMetadata(ctx, &apiv1.PatchCheckpointMetadataRequest{
+				CheckpointUuid: id, Patch: &structpb.Struct{},
+			})
+			return err
+		}, false},
+		{"CanGetExperimentArtifacts", func(id string) error {
+			_, err := api.GetCheckpointMetadataHistory(ctx,
+				&apiv1.GetCheckpointMetadataHistoryRequest{CheckpointUuid: id})
+			return err
+		}, false},
 		{"CanGetExperimentArtifacts", func(id string) error {
 			_, err := api.GetTrialMetricsByCheckpoint(ctx,
 				&apiv1.GetTrialMetricsByCheckpointRequest{CheckpointUuid: id})
@@ -651,3 +663,13 @@ func TestCheckpointAuthZ(t *testing.T) {
 		require.Equal(t, expectedErr, curCase.IDToReqCall(checkpointID))
 	}
 }
+
+func TestPostCheckpointStorageVerifyRequiresAdmin(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+
+	// Probing writes to the storage of every workspace, so only admins may do it.
+	curUser.Admin = false
+	require.NoError(t, user.Update(ctx, &curUser, []string{"admin"}, nil))
+	_, err := api.PostCheckpointStorageVerify(ctx, &apiv1.PostCheckpointStorageVerifyRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+}
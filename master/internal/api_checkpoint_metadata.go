@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/checkpoints"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+)
+
+func (a *apiServer) PatchCheckpointMetadata(
+	ctx context.Context, req *apiv1.PatchCheckpointMetadataRequest,
+) (*apiv1.PatchCheckpointMetadataResponse, error) {
+	if req.Patch == nil {
+		return nil, status.Error(codes.InvalidArgument, "patch must be set")
+	}
+	ckptUUID, err := uuid.Parse(req.CheckpointUuid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unable to parse checkpoint UUID %s: %s", req.CheckpointUuid, err)
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.m.canDoActionOnCheckpoint(ctx, *curUser, req.CheckpointUuid,
+		expauth.AuthZProvider.Get().CanEditExperimentsMetadata); err != nil {
+		return nil, err
+	}
+
+	metadata, err := checkpoints.UpdateMetadata(ctx, ckptUUID, curUser.ID,
+		func(metadata map[string]interface{}) map[string]interface{} {
+			return checkpoints.MergeMetadataPatch(metadata, req.Patch.AsMap())
+		})
+	switch {
+	case errors.Is(err, db.ErrInvalidInput):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, db.ErrNotFound):
+		return nil, api.NotFoundErrs("checkpoint", req.CheckpointUuid, true)
+	case err != nil:
+		return nil, err
+	}
+	return &apiv1.PatchCheckpointMetadataResponse{Metadata: protoutils.ToStruct(metadata)}, nil
+}
+
+func (a *apiServer) GetCheckpointMetadataHistory(
+	ctx context.Context, req *apiv1.GetCheckpointMetadataHistoryRequest,
+) (*apiv1.GetCheckpointMetadataHistoryResponse, error) {
+	ckptUUID, err := uuid.Parse(req.CheckpointUuid)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unable to parse checkpoint UUID %s: %s", req.CheckpointUuid, err)
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.m.canGetCheckpointArtifacts(ctx, *curUser, req.CheckpointUuid); err != nil {
+		return nil, err
+	}
+
+	changes, err := checkpoints.MetadataHistory(ctx, ckptUUID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetCheckpointMetadataHistoryResponse{
+		Changes: make([]*checkpointv1.CheckpointMetadataChange, 0, len(changes)),
+	}
+	for _, c := range changes {
+		resp.Changes = append(resp.Changes, c.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetProjectCheckpointMetadataSchema(
+	ctx context.Context, req *apiv1.GetProjectCheckpointMetadataSchemaRequest,
+) (*apiv1.GetProjectCheckpointMetadataSchemaResponse, error) {
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId); err != nil {
+		return nil, err
+	}
+	s, err := checkpoints.MetadataSchema(ctx, int(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, api.NotFoundErrs("checkpoint metadata schema of project",
+			strconv.Itoa(int(req.ProjectId)), true)
+	}
+	return &apiv1.GetProjectCheckpointMetadataSchemaResponse{Schema: s.Proto()}, nil
+}
+
+func (a *apiServer) PutProjectCheckpointMetadataSchema(
+	ctx context.Context, req *apiv1.PutProjectCheckpointMetadataSchemaRequest,
+) (*apiv1.PutProjectCheckpointMetadataSchemaResponse, error) {
+	if req.Schema == nil {
+		return nil, status.Error(codes.InvalidArgument, "schema must be set")
+	}
+	schema, err := json.Marshal(req.Schema.AsMap())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, _, err = a.getProjectAndCheckCanDoActions(ctx, req.ProjectId,
+		project.AuthZProvider.Get().CanSetProjectMetadataSchemas,
+	); err != nil {
+		return nil, err
+	}
+	err = checkpoints.SetMetadataSchema(ctx, int(req.ProjectId), schema)
+	if errors.Is(err, db.ErrInvalidInput) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	s, err := checkpoints.MetadataSchema(ctx, int(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PutProjectCheckpointMetadataSchemaResponse{Schema: s.Proto()}, nil
+}
+
+func (a *apiServer) DeleteProjectCheckpointMetadataSchema(
+	ctx context.Context, req *apiv1.DeleteProjectCheckpointMetadataSchemaRequest,
+) (*apiv1.DeleteProjectCheckpointMetadataSchemaResponse, error) {
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId,
+		project.AuthZProvider.Get().CanSetProjectMetadataSchemas,
+	); err != nil {
+		return nil, err
+	}
+	if err := checkpoints.DeleteMetadataSchema(ctx, int(req.ProjectId)); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteProjectCheckpointMetadataSchemaResponse{}, nil
+}
@@ -0,0 +1,83 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/checkpoints"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestCheckpointMetadataAPI(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	ckptUUID := createVersionTwoCheckpoint(ctx, t, api, curUser, nil)
+	ckpt, err := checkpoints.CheckpointByUUID(ctx, uuid.MustParse(ckptUUID))
+	require.NoError(t, err)
+	exp, err := db.ExperimentByID(ctx, ckpt.CheckpointTrainingMetadata.ExperimentID)
+	require.NoError(t, err)
+	projectID := int32(exp.ProjectID)
+
+	patch, err := structpb.NewStruct(map[string]interface{}{
+		"eval":            map[string]interface{}{"mmlu": 0.61},
+		"steps_completed": nil,
+	})
+	require.NoError(t, err)
+	patched, err := api.PatchCheckpointMetadata(ctx, &apiv1.PatchCheckpointMetadataRequest{
+		CheckpointUuid: ckptUUID, Patch: patch,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"framework":          "tensortorch",
+		"determined_version": "1.0.0",
+		"eval":               map[string]interface{}{"mmlu": 0.61},
+	}, patched.Metadata.AsMap())
+
+	history, err := api.GetCheckpointMetadataHistory(ctx,
+		&apiv1.GetCheckpointMetadataHistoryRequest{CheckpointUuid: ckptUUID})
+	require.NoError(t, err)
+	require.Len(t, history.Changes, 1)
+	require.Equal(t, float64(5), history.Changes[0].Previous.AsMap()["steps_completed"])
+	require.Equal(t, patched.Metadata.AsMap(), history.Changes[0].Metadata.AsMap())
+	require.Equal(t, int32(curUser.ID), history.Changes[0].GetChangedBy())
+
+	_, err = api.GetProjectCheckpointMetadataSchema(ctx,
+		&apiv1.GetProjectCheckpointMetadataSchemaRequest{ProjectId: projectID})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	schema, err := structpb.NewStruct(map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"eval"},
+	})
+	require.NoError(t, err)
+	putSchema, err := api.PutProjectCheckpointMetadataSchema(ctx,
+		&apiv1.PutProjectCheckpointMetadataSchemaRequest{ProjectId: projectID, Schema: schema})
+	require.NoError(t, err)
+	require.Equal(t, "object", putSchema.Schema.Schema.AsMap()["type"])
+	defer func() {
+		_, err := api.DeleteProjectCheckpointMetadataSchema(ctx,
+			&apiv1.DeleteProjectCheckpointMetadataSchemaRequest{ProjectId: projectID})
+		require.NoError(t, err)
+	}()
+
+	// Changes must follow the project's schema.
+	patch, err = structpb.NewStruct(map[string]interface{}{"eval": nil})
+	require.NoError(t, err)
+	_, err = api.PatchCheckpointMetadata(ctx, &apiv1.PatchCheckpointMetadataRequest{
+		CheckpointUuid: ckptUUID, Patch: patch,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.PatchCheckpointMetadata(ctx, &apiv1.PatchCheckpointMetadataRequest{
+		CheckpointUuid: uuid.New().String(), Patch: &structpb.Struct{},
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
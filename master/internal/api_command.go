@@ -25,6 +25,7 @@ import (
 	masterConfig "github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/configpolicy"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/envvarsets"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/rbac/audit"
 	"github.com/determined-ai/determined/master/internal/templates"
@@ -77,6 +78,9 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 			nil,
 			status.Errorf(codes.Unauthenticated, "failed to get the user: %s", err)
 	}
+	if err = checkCanSubmit(ctx); err != nil {
+		return nil, nil, err
+	}
 
 	// TODO(ilia): When commands are workspaced, also use workspace AgentUserGroup here.
 	agentUserGroup, err := user.GetAgentUserGroup(ctx, userModel.ID, int(cmdSpec.Metadata.WorkspaceID))
@@ -128,6 +132,7 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 		return nil, launchWarnings, err
 	}
 	taskSpec.Workspace = w.Name
+	taskSpec.WorkspaceID = int(w.Id)
 
 	workDirInDefaults := config.WorkDir
 	if len(configBytes) != 0 {
@@ -163,6 +168,18 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 	if err != nil {
 		return nil, nil, status.Errorf(codes.InvalidArgument, "failed constraint check: %v", err)
 	}
+	_, err = envvarsets.Resolve(ctx, int(cmdSpec.Metadata.WorkspaceID),
+		config.Environment.EnvironmentVariableSets)
+	if missingErr := (envvarsets.MissingError{}); errors.As(err, &missingErr) {
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, nil, err
+	}
+	err = a.checkCanOptOutOfPodSecurity(ctx, *userModel, int32(cmdSpec.Metadata.WorkspaceID),
+		taskSpec.TaskContainerDefaults, config.Environment.PodSpec)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	token, err := getTaskSessionToken(ctx, userModel)
 	if err != nil {
@@ -392,6 +409,32 @@ func (a *apiServer) LaunchCommand(
 	}
 	maps.Copy(launchReq.Spec.Base.ExtraEnvVars, oidcPachydermEnvVars)
 
+	if outputs := launchReq.Spec.Config.Outputs; len(outputs) > 0 {
+		var storage *expconf.CheckpointStorageConfig
+		var storageBytes, outputsBytes []byte
+		storage, err = a.workspaceCheckpointStorage(ctx, int(launchReq.Spec.Metadata.WorkspaceID))
+		if err != nil {
+			return nil, err
+		}
+		if storageBytes, err = json.Marshal(storage); err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot marshal outputs storage: %s", err)
+		}
+		if outputsBytes, err = json.Marshal(outputs); err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot marshal outputs: %s", err)
+		}
+		launchReq.Spec.Base.ExtraEnvVars[model.CommandOutputsEnvVar] = string(outputsBytes)
+		launchReq.Spec.Base.ExtraEnvVars[model.CommandOutputsStorageConfigEnvVar] =
+			model.CommandOutputsStorageConfigPath
+		launchReq.Spec.AdditionalFiles = append(launchReq.Spec.AdditionalFiles,
+			launchReq.Spec.Base.AgentUserGroup.OwnedArchiveItem(
+				model.CommandOutputsStorageConfigPath,
+				storageBytes,
+				0o600,
+				tar.TypeReg,
+			),
+		)
+	}
+
 	// Launch a command.
 	cmd, err := command.DefaultCmdService.LaunchGenericCommand(
 		model.TaskTypeCommand,
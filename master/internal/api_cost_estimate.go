@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/determined-ai/determined/master/internal/costestimate"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/searcher"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) PostEstimateExperimentCost(
+	ctx context.Context, req *apiv1.PostEstimateExperimentCostRequest,
+) (*apiv1.PostEstimateExperimentCostResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = expauth.AuthZProvider.Get().CanPreviewHPSearch(ctx, *curUser); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if req.Config == nil {
+		return nil, status.Error(codes.InvalidArgument, "config must be set")
+	}
+
+	bytes, err := protojson.Marshal(req.Config)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error parsing experiment config: %s", err)
+	}
+	config, err := parsePreviewHPSearchConfig(bytes)
+	if err != nil {
+		return nil, err
+	}
+	config = schemas.WithDefaults(config)
+	// Similar experiments are looked for in the project the experiment would be created in.
+	p, err := getCreateExperimentsProject(
+		a.m, &apiv1.CreateExperimentRequest{ProjectId: req.ProjectId}, curUser, config)
+	if err != nil {
+		return nil, err
+	}
+	resources := config.Resources()
+	pool, err := a.m.rm.ResolveResourcePool(
+		rm.ResourcePoolName(resources.ResourcePool()), int(p.WorkspaceId), resources.SlotsPerTrial())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sim, err := searcher.Simulate(config.Searcher(), config.Hyperparameters())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	history, err := costestimate.SimilarTrials(ctx, int(p.Id), config.Name().String())
+	if err != nil {
+		return nil, err
+	}
+
+	pricing := a.m.config.Pricing
+	est := costestimate.Compute(sim, resources.SlotsPerTrial(), history,
+		pricing.SlotHourPriceOf(pool.String()), pricing.Currency)
+	return &apiv1.PostEstimateExperimentCostResponse{Estimate: est.Proto()}, nil
+}
@@ -0,0 +1,30 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestPostEstimateExperimentCostErrors(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+
+	_, err := api.PostEstimateExperimentCost(ctx, &apiv1.PostEstimateExperimentCostRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	// The config must have a searcher to estimate from.
+	config, err := structpb.NewStruct(map[string]any{"name": "estimate"})
+	require.NoError(t, err)
+	_, err = api.PostEstimateExperimentCost(ctx, &apiv1.PostEstimateExperimentCostRequest{
+		Config: config,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
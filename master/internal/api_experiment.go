@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ghodss/yaml"
 	structpb "github.com/golang/protobuf/ptypes/struct"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -27,16 +28,24 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	structpbmap "google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	k8sV1 "k8s.io/api/core/v1"
 
+	"github.com/determined-ai/determined/master/internal/activity"
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/configpolicy"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/db/bunutils"
+	"github.com/determined-ai/determined/master/internal/egress"
+	"github.com/determined-ai/determined/master/internal/envvarsets"
+	"github.com/determined-ai/determined/master/internal/expdupes"
 	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/expnaming"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/imagedigest"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/prom"
+	"github.com/determined-ai/determined/master/internal/relations"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/trials"
@@ -72,6 +81,11 @@ var SummaryMetricStatistics = []string{"last", "max", "mean", "min"}
 
 const maxConcurrentDeletes = 10
 
+// imagePinningTimeout bounds how long creating or continuing an experiment waits on registries to
+// resolve the digests of its images. It is short so that clusters that can't reach a registry
+// don't stall submissions; the images then run by their tags.
+const imagePinningTimeout = 5 * time.Second
+
 func (a *apiServer) enrichExperimentState(experiments ...*experimentv1.Experiment) error {
 	return a.enrichExperimentStateTx(context.Background(), db.Bun(), experiments...)
 }
@@ -187,6 +201,7 @@ func (a *apiServer) getExperimentTx(
 		e.owner_id AS user_id,
 		e.checkpoint_size AS checkpoint_size,
 		e.checkpoint_count AS checkpoint_count,
+		COALESCE(e.config_hash, '') AS config_hash,
 		u.username AS username,
 		(SELECT json_agg(id) FROM trial_ids) AS trial_ids,
 		(SELECT count(id) FROM trial_ids) AS num_trials,
@@ -328,9 +343,10 @@ func (a *apiServer) DeleteExperiment(
 	go func() {
 		if err := a.deleteExperiments([]*model.Experiment{e}, &curUser); err != nil {
 			log.WithError(err).Errorf("deleting experiment %d", e.ID)
-			e.State = model.DeleteFailedState
-			if err := a.m.db.SaveExperimentState(e); err != nil {
-				log.WithError(err).Errorf("transitioning experiment %d to %s", e.ID, e.State)
+			if _, _, err := db.TransitionExperimentState(context.Background(), e.ID,
+				model.DeleteFailedState, err.Error()); err != nil {
+				log.WithError(err).Errorf("transitioning experiment %d to %s", e.ID,
+					model.DeleteFailedState)
 			}
 		} else {
 			log.Infof("experiment %d deleted successfully", e.ID)
@@ -357,16 +373,14 @@ func (a *apiServer) DeleteExperiments(
 		err := a.deleteExperiments(experiments, curUser)
 		if err != nil {
 			// set experiment state to DeleteFailed
-			for _, id := range req.ExperimentIds {
-				log.WithError(err).Errorf("deleting experiment %d", id)
+			ids := make([]int, 0, len(experiments))
+			for _, e := range experiments {
+				log.WithError(err).Errorf("deleting experiment %d", e.ID)
+				ids = append(ids, e.ID)
 			}
-			_, err = db.Bun().NewUpdate().
-				ModelTableExpr("experiments as e").
-				Set("state = ?", model.DeleteFailedState).
-				Where("id IN (?)", bun.In(req.ExperimentIds)).
-				Exec(ctx)
-			if err != nil {
-				for _, id := range req.ExperimentIds {
+			if _, err := db.TransitionExperimentStates(context.Background(), ids,
+				model.DeleteFailedState, err.Error()); err != nil {
+				for _, id := range ids {
 					log.WithError(err).Errorf("transitioning experiment %d to %s", id,
 						model.DeleteFailedState)
 				}
@@ -479,8 +493,7 @@ func getExperimentColumns(q *bun.SelectQuery) *bun.SelectQuery {
 		ColumnExpr(bunutils.ProtoStateDBCaseString(experimentv1.State_value, "e.state", "state",
 			"STATE_")).
 		Column("e.archived").
-		ColumnExpr(
-			"(SELECT COUNT(*) FROM trials t WHERE e.id = t.experiment_id) AS num_trials").
+		ColumnExpr("COALESCE(es.num_trials, 0) AS num_trials").
 		// Intentionally not sending trial_ids due to performance.
 		ColumnExpr("COALESCE(u.display_name, u.username) as display_name").
 		ColumnExpr("e.owner_id as user_id").
@@ -506,6 +519,7 @@ func getExperimentColumns(q *bun.SelectQuery) *bun.SelectQuery {
 		Column("e.config").
 		Column("e.checkpoint_size").
 		Column("e.checkpoint_count").
+		ColumnExpr("COALESCE(e.config_hash, '') AS config_hash").
 		Column("e.unmanaged").
 		Column("e.external_experiment_id").
 		ColumnExpr(`r.external_run_id AS external_trial_id`).
@@ -513,12 +527,17 @@ func getExperimentColumns(q *bun.SelectQuery) *bun.SelectQuery {
 		Join("LEFT JOIN users u ON e.owner_id = u.id").
 		Join("LEFT JOIN projects p ON e.project_id = p.id").
 		Join("LEFT JOIN workspaces w ON p.workspace_id = w.id").
-		Join("LEFT JOIN runs AS r ON r.id = e.best_trial_id")
+		Join("LEFT JOIN runs AS r ON r.id = e.best_trial_id").
+		Join("LEFT JOIN experiment_summaries AS es ON es.experiment_id = e.id")
 }
 
 func (a *apiServer) GetExperiments(
 	ctx context.Context, req *apiv1.GetExperimentsRequest,
 ) (*apiv1.GetExperimentsResponse, error) {
+	include, err := relations.Parse(req.Include)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	resp := &apiv1.GetExperimentsResponse{Experiments: []*experimentv1.Experiment{}}
 	query := db.Bun().NewSelect().
 		Model(&resp.Experiments).
@@ -526,37 +545,27 @@ func (a *apiServer) GetExperiments(
 		Apply(getExperimentColumns)
 
 	if req.ShowTrialData {
-		query.ColumnExpr(`
-		(
-			SELECT searcher_metric_value
-			FROM trials t
-			WHERE t.id = e.best_trial_id
-		) AS best_trial_searcher_metric`)
+		query.ColumnExpr("es.best_trial_searcher_metric")
 	}
 
 	// Construct the ordering expression.
 	orderColMap := map[apiv1.GetExperimentsRequest_SortBy]string{
-		apiv1.GetExperimentsRequest_SORT_BY_UNSPECIFIED:      "id",
-		apiv1.GetExperimentsRequest_SORT_BY_ID:               "id",
-		apiv1.GetExperimentsRequest_SORT_BY_DESCRIPTION:      "description",
-		apiv1.GetExperimentsRequest_SORT_BY_NAME:             "name",
-		apiv1.GetExperimentsRequest_SORT_BY_START_TIME:       "e.start_time",
-		apiv1.GetExperimentsRequest_SORT_BY_END_TIME:         "e.end_time",
-		apiv1.GetExperimentsRequest_SORT_BY_STATE:            "e.state",
-		apiv1.GetExperimentsRequest_SORT_BY_NUM_TRIALS:       "num_trials",
-		apiv1.GetExperimentsRequest_SORT_BY_PROGRESS:         "COALESCE(progress, 0)",
-		apiv1.GetExperimentsRequest_SORT_BY_USER:             "display_name",
-		apiv1.GetExperimentsRequest_SORT_BY_FORKED_FROM:      "e.parent_id",
-		apiv1.GetExperimentsRequest_SORT_BY_RESOURCE_POOL:    "resource_pool",
-		apiv1.GetExperimentsRequest_SORT_BY_PROJECT_ID:       "e.project_id",
-		apiv1.GetExperimentsRequest_SORT_BY_CHECKPOINT_SIZE:  "checkpoint_size",
-		apiv1.GetExperimentsRequest_SORT_BY_CHECKPOINT_COUNT: "checkpoint_count",
-		apiv1.GetExperimentsRequest_SORT_BY_SEARCHER_METRIC_VAL: `(
-			SELECT
-				searcher_metric_value
-			FROM trials t
-			WHERE t.id = e.best_trial_id
-		) `,
+		apiv1.GetExperimentsRequest_SORT_BY_UNSPECIFIED:         "id",
+		apiv1.GetExperimentsRequest_SORT_BY_ID:                  "id",
+		apiv1.GetExperimentsRequest_SORT_BY_DESCRIPTION:         "description",
+		apiv1.GetExperimentsRequest_SORT_BY_NAME:                "name",
+		apiv1.GetExperimentsRequest_SORT_BY_START_TIME:          "e.start_time",
+		apiv1.GetExperimentsRequest_SORT_BY_END_TIME:            "e.end_time",
+		apiv1.GetExperimentsRequest_SORT_BY_STATE:               "e.state",
+		apiv1.GetExperimentsRequest_SORT_BY_NUM_TRIALS:          "num_trials",
+		apiv1.GetExperimentsRequest_SORT_BY_PROGRESS:            "COALESCE(progress, 0)",
+		apiv1.GetExperimentsRequest_SORT_BY_USER:                "display_name",
+		apiv1.GetExperimentsRequest_SORT_BY_FORKED_FROM:         "e.parent_id",
+		apiv1.GetExperimentsRequest_SORT_BY_RESOURCE_POOL:       "resource_pool",
+		apiv1.GetExperimentsRequest_SORT_BY_PROJECT_ID:          "e.project_id",
+		apiv1.GetExperimentsRequest_SORT_BY_CHECKPOINT_SIZE:     "checkpoint_size",
+		apiv1.GetExperimentsRequest_SORT_BY_CHECKPOINT_COUNT:    "checkpoint_count",
+		apiv1.GetExperimentsRequest_SORT_BY_SEARCHER_METRIC_VAL: "es.best_trial_searcher_metric",
 	}
 	sortByMap := map[apiv1.OrderBy]string{
 		apiv1.OrderBy_ORDER_BY_UNSPECIFIED: "ASC",
@@ -652,6 +661,23 @@ func (a *apiServer) GetExperiments(
 		return nil, err
 	}
 
+	ids := make([]int, 0, len(resp.Experiments))
+	for _, e := range resp.Experiments {
+		ids = append(ids, int(e.Id))
+	}
+	included, err := relations.Experiments(ctx, ids, include)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range resp.Experiments {
+		if v := included[int(e.Id)].LatestValidation; v != nil {
+			e.LatestValidation = protoutils.ToStruct(v)
+		}
+		if c := included[int(e.Id)].BestCheckpoint; c != nil {
+			e.BestCheckpoint = protoutils.ToStruct(c)
+		}
+	}
+
 	return resp, nil
 }
 
@@ -782,6 +808,9 @@ func (a *apiServer) GetExperimentValidationHistory(
 	return &resp, nil
 }
 
+// maxPreviewSampleCount bounds the sampled trials a search preview returns.
+const maxPreviewSampleCount = 1000
+
 func (a *apiServer) PreviewHPSearch(
 	ctx context.Context, req *apiv1.PreviewHPSearchRequest,
 ) (*apiv1.PreviewHPSearchResponse, error) {
@@ -792,23 +821,77 @@ func (a *apiServer) PreviewHPSearch(
 	if err = experiment.AuthZProvider.Get().CanPreviewHPSearch(ctx, *curUser); err != nil {
 		return nil, status.Errorf(codes.PermissionDenied, err.Error())
 	}
+	if req.SampleCount < 0 || req.SampleCount > maxPreviewSampleCount {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"sample_count must be between 0 and %d", maxPreviewSampleCount)
+	}
 
 	bytes, err := protojson.Marshal(req.Config)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "error parsing experiment config: %s", err)
 	}
+	config, err := parsePreviewHPSearchConfig(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	sim, err := searcher.Simulate(config.Searcher(), config.Hyperparameters())
+	if err != nil {
+		return nil, err
+	}
+
+	var resources expconf.ResourcesConfig
+	if config.RawResources != nil {
+		resources = *config.RawResources
+	}
+	resources = schemas.WithDefaults(resources)
+	var reproducibility expconf.ReproducibilityConfig
+	if config.RawReproducibility != nil {
+		reproducibility = *config.RawReproducibility
+	}
+	// An unset seed is picked at random, as it would be when creating the experiment; it is
+	// returned so the preview can be reproduced by setting it.
+	seed := schemas.WithDefaults(reproducibility).ExperimentSeed()
+
+	samples, err := searcher.Sample(
+		config.Searcher(), config.Hyperparameters(), seed, int(req.SampleCount))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	estimate := searcher.Estimate(sim, resources.SlotsPerTrial())
 
+	resp := &apiv1.PreviewHPSearchResponse{
+		Summary:         sim.Proto(),
+		ExperimentSeed:  seed,
+		EstimatedTrials: int32(estimate.TrialCount),
+	}
+	for _, sample := range samples {
+		resp.Samples = append(resp.Samples, protoutils.ToStruct(sample))
+	}
+	if estimate.Units != nil {
+		resp.EstimatedUnits = *estimate.Units
+		resp.EstimatedSlotUnits = *estimate.SlotUnits
+	}
+	if estimate.Unit != nil {
+		resp.EstimatedUnit = *estimate.Unit
+	}
+	return resp, nil
+}
+
+// parsePreviewHPSearchConfig parses an experiment config for previewing its hyperparameter search,
+// checking that its searcher and hyperparameters are complete and applying their defaults.
+func parsePreviewHPSearchConfig(bytes []byte) (expconf.ExperimentConfig, error) {
 	// Parse the provided experiment config.
 	config, err := expconf.ParseAnyExperimentConfigYAML(bytes)
 	if err != nil {
-		return nil, status.Errorf(
+		return expconf.ExperimentConfig{}, status.Errorf(
 			codes.InvalidArgument, "invalid experiment configuration: %s", err,
 		)
 	}
 
 	// Get the useful subconfigs for preview search.
 	if config.RawSearcher == nil {
-		return nil, status.Errorf(
+		return expconf.ExperimentConfig{}, status.Errorf(
 			codes.InvalidArgument, "invalid experiment configuration; missing searcher",
 		)
 	}
@@ -818,30 +901,27 @@ func (a *apiServer) PreviewHPSearch(
 	// Apply any json-schema-defined defaults.
 	sc = schemas.WithDefaults(sc)
 	hc = schemas.WithDefaults(hc)
+	config.RawSearcher = &sc
+	config.RawHyperparameters = hc
 
 	// Make sure the searcher config has all eventuallyRequired fields.
 	if err = schemas.IsComplete(sc); err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid searcher configuration: %s", err)
+		return expconf.ExperimentConfig{}, status.Errorf(
+			codes.InvalidArgument, "invalid searcher configuration: %s", err,
+		)
 	}
 	if err = schemas.IsComplete(hc); err != nil {
-		return nil, status.Errorf(
+		return expconf.ExperimentConfig{}, status.Errorf(
 			codes.InvalidArgument, "invalid hyperparameters configuration: %s", err,
 		)
 	}
 
 	// Disallow EOL searchers.
 	if err = sc.AssertCurrent(); err != nil {
-		return nil, errors.Wrap(err, "invalid experiment configuration")
+		return expconf.ExperimentConfig{}, errors.Wrap(err, "invalid experiment configuration")
 	}
 
-	sim, err := searcher.Simulate(sc, hc)
-	if err != nil {
-		return nil, err
-	}
-
-	return &apiv1.PreviewHPSearchResponse{
-		Summary: sim.Proto(),
-	}, nil
+	return config, nil
 }
 
 func (a *apiServer) ActivateExperiment(
@@ -1012,6 +1092,13 @@ func (a *apiServer) PatchExperiment(
 			return nil, status.Errorf(codes.InvalidArgument,
 				"`name` must not be an empty or whitespace string")
 		}
+		err = expnaming.CheckNameAvailable(ctx, int(exp.ProjectId), req.Experiment.Name.Value,
+			int(exp.Id))
+		if nameErr := (expnaming.NameTakenError{}); errors.As(err, &nameErr) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		} else if err != nil {
+			return nil, err
+		}
 		exp.Name = req.Experiment.Name.Value
 	}
 
@@ -1346,7 +1433,8 @@ func (a *apiServer) parseAndMergeContinueConfig(expID int, overrideConfig string
 		return nil, false, status.Errorf(codes.InvalidArgument,
 			fmt.Sprintf("Unsupported searcher type provided: '%s'", name))
 	}
-	if !isSingle && strings.TrimSpace(overrideConfig) != "{}" { //nolint: goconst
+	if !isSingle && strings.TrimSpace(overrideConfig) != "{}" && //nolint: goconst
+		!onlyOverridesImagePinning(overrideConfig) {
 		return nil, false, status.Errorf(codes.InvalidArgument,
 			fmt.Sprintf("override config is provided and experiment is not single searcher, got '%s' instead", name))
 	}
@@ -1405,6 +1493,45 @@ func getWorkspaceByConfig(config expconf.ExperimentConfig) (*model.Workspace, er
 	return workspace.WorkspaceByName(ctx, wkspName)
 }
 
+// onlyOverridesImagePinning returns whether an override config sets nothing but the image pinning
+// mode, which experiments of any searcher can be continued with.
+func onlyOverridesImagePinning(overrideConfig string) bool {
+	var override map[string]interface{}
+	if err := yaml.Unmarshal([]byte(overrideConfig), &override); err != nil || len(override) != 1 {
+		return false
+	}
+	env, ok := override["environment"].(map[string]interface{})
+	if !ok || len(env) != 1 {
+		return false
+	}
+	_, ok = env["image_pinning"]
+	return ok
+}
+
+// pinExperimentImages resolves the tags of the images of an experiment config to digests,
+// carrying over the images pinned by the experiment with ID previousID, if it isn't 0. Images
+// that can't be resolved keep running by their tags, so registries being unreachable from the
+// master doesn't keep experiments from starting.
+func pinExperimentImages(
+	ctx context.Context, config expconf.ExperimentConfig, previousID int,
+) (expconf.ExperimentConfig, []model.ExperimentImagePin, error) {
+	var previous []model.ExperimentImagePin
+	if previousID != 0 {
+		var err error
+		if previous, err = imagedigest.PinsByExperiment(ctx, previousID); err != nil {
+			return config, nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, imagePinningTimeout)
+	defer cancel()
+	pinned, pins, err := imagedigest.Pin(ctx, egress.Client(), config, previous)
+	if err != nil {
+		log.WithError(err).Warn("running experiment images that couldn't be pinned by their tags")
+	}
+	return pinned, pins, nil
+}
+
 var errContinueHPSearchCompleted = status.Error(codes.FailedPrecondition,
 	"experiment has been completed, cannot continue this experiment")
 
@@ -1421,6 +1548,9 @@ func (a *apiServer) ContinueExperiment(
 	if err != nil {
 		return nil, err
 	}
+	if err = checkCanSubmit(ctx); err != nil {
+		return nil, err
+	}
 
 	trialsResp, err := a.GetExperimentTrials(ctx, &apiv1.GetExperimentTrialsRequest{
 		ExperimentId: req.Id,
@@ -1444,11 +1574,18 @@ func (a *apiServer) ContinueExperiment(
 	dbExp.ID = int(req.Id)
 	dbExp.JobID = origExperiment.JobID // Revive job.
 
+	activeConfig, pins, err := pinExperimentImages(ctx, activeConfig, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+	dbExp.Config = activeConfig.AsLegacy()
+
 	e, launchWarnings, err := newExperiment(a.m, dbExp, modelDef, activeConfig, taskSpec)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create experiment: %s", err)
 	}
 
+	var transitions []model.ExperimentStateTransition
 	err = db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		// Lock experiment state.
 		var expState model.State
@@ -1482,10 +1619,14 @@ func (a *apiServer) ContinueExperiment(
 			}
 		}
 
+		// Throw it in paused.
+		if transitions, err = db.TransitionExperimentStatesTx(
+			ctx, tx, []int{int(req.Id)}, model.PausedState, "continued by user",
+		); err != nil {
+			return err
+		}
 		if _, err := tx.NewUpdate().Model(&model.Experiment{}).
-			Set("state = ?", model.PausedState). // Throw it in paused.
-			Set("progress = ?", 0.0).            // Reset progress.
-			Set("end_time = null").
+			Set("progress = ?", 0.0). // Reset progress.
 			Where("id = ?", req.Id).
 			Exec(ctx); err != nil {
 			return fmt.Errorf("updating experiments config: %w", err)
@@ -1539,6 +1680,10 @@ func (a *apiServer) ContinueExperiment(
 	if err != nil {
 		return nil, fmt.Errorf("experiment continue database updates: %w", err)
 	}
+	db.EmitExperimentStateTransitions(ctx, transitions)
+	if err = imagedigest.SetPins(ctx, e.ID, pins); err != nil {
+		return nil, err
+	}
 
 	if err = e.Start(); err != nil {
 		return nil, errors.Wrapf(err, "failed to start experiment %d", e.ID)
@@ -1621,15 +1766,47 @@ func (a *apiServer) CreateExperiment(
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, err.Error())
 	}
+	_, err = envvarsets.Resolve(ctx, int(wkspIDs[0]),
+		activeConfig.Environment().EnvironmentVariableSets())
+	if missingErr := (envvarsets.MissingError{}); errors.As(err, &missingErr) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	err = a.checkCanOptOutOfPodSecurity(ctx, *user, wkspIDs[0], taskSpec.TaskContainerDefaults,
+		(*k8sV1.Pod)(activeConfig.Environment().PodSpec()))
+	if err != nil {
+		return nil, err
+	}
+	err = expnaming.CheckNameAvailable(ctx, int(p.Id), activeConfig.Name().String(), 0)
+	if nameErr := (expnaming.NameTakenError{}); errors.As(err, &nameErr) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	dups, err := expdupes.Check(ctx, int(wkspIDs[0]), dbExp, activeConfig.Metadata())
+	if dupErr := (expdupes.DuplicateError{}); errors.As(err, &dupErr) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
 
 	if req.ValidateOnly {
-		return &apiv1.CreateExperimentResponse{
-			Experiment: &experimentv1.Experiment{},
-		}, nil
+		return warnAboutDuplicates(&apiv1.CreateExperimentResponse{
+			Experiment: &experimentv1.Experiment{ConfigHash: *dbExp.ConfigHash},
+		}, dups), nil
 	}
 
 	if req.Unmanaged != nil && *req.Unmanaged {
-		return a.createUnmanagedExperimentTx(ctx, db.Bun(), dbExp, modelDef, activeConfig, user)
+		resp, err := a.createUnmanagedExperimentTx(ctx, db.Bun(), dbExp, modelDef, activeConfig, user)
+		if err != nil {
+			return nil, err
+		}
+		recordExperimentCreated(ctx, user.ID, *dbExp, activeConfig)
+		return warnAboutDuplicates(resp, dups), nil
+	}
+	if err = checkCanSubmit(ctx); err != nil {
+		return nil, err
 	}
 	// Check user has permission for what they are trying to do
 	// before actually saving the experiment.
@@ -1639,11 +1816,20 @@ func (a *apiServer) CreateExperiment(
 		}
 	}
 
+	activeConfig, pins, err := pinExperimentImages(ctx, activeConfig, int(req.ParentId))
+	if err != nil {
+		return nil, err
+	}
+	dbExp.Config = activeConfig.AsLegacy()
+
 	e, launchWarnings, err := newExperiment(a.m, dbExp, modelDef, activeConfig, taskSpec)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create experiment: %s", err)
 	}
 	modelDef = nil //nolint:ineffassign
+	if err = imagedigest.SetPins(ctx, e.ID, pins); err != nil {
+		return nil, err
+	}
 
 	if err = e.Start(); err != nil {
 		return nil, errors.Wrapf(err, "failed to start experiment %d", e.ID)
@@ -1656,15 +1842,44 @@ func (a *apiServer) CreateExperiment(
 		}
 	}
 
+	recordExperimentCreated(ctx, user.ID, *e.Experiment, activeConfig)
+
 	protoExp, err := a.getExperiment(ctx, *user, e.ID)
 	if err != nil {
 		return nil, err
 	}
-	return &apiv1.CreateExperimentResponse{
+	return warnAboutDuplicates(&apiv1.CreateExperimentResponse{
 		Experiment: protoExp,
 		Config:     protoutils.ToStruct(activeConfig),
 		Warnings:   command.LaunchWarningToProto(launchWarnings),
-	}, nil
+	}, dups), nil
+}
+
+// warnAboutDuplicates adds a warning about the experiments identical to a created experiment that
+// its workspace already has to its response.
+func warnAboutDuplicates(
+	resp *apiv1.CreateExperimentResponse, dups []expdupes.Duplicate,
+) *apiv1.CreateExperimentResponse {
+	if len(dups) == 0 {
+		return resp
+	}
+	resp.Warnings = append(resp.Warnings,
+		command.LaunchWarningToProto([]command.LaunchWarning{command.DuplicateExperiment})...)
+	for _, d := range dups {
+		resp.DuplicateExperimentIds = append(resp.DuplicateExperimentIds, int32(d.ExperimentID))
+	}
+	return resp
+}
+
+// recordExperimentCreated adds a created experiment to its workspace's activity feed. The feed is
+// best effort, so failures are only logged.
+func recordExperimentCreated(
+	ctx context.Context, actor model.UserID, e model.Experiment,
+	activeConfig expconf.ExperimentConfig,
+) {
+	if err := activity.RecordExperimentCreated(ctx, actor, e, activeConfig); err != nil {
+		log.WithError(err).Warnf("failed to record creation of experiment %d", e.ID)
+	}
 }
 
 func (a *apiServer) PutExperimentRetainLogs(
@@ -3038,3 +3253,22 @@ func (a *apiServer) DeleteTensorboardFiles(
 
 	return &apiv1.DeleteTensorboardFilesResponse{}, nil
 }
+
+func (a *apiServer) GetExperimentStateHistory(
+	ctx context.Context, req *apiv1.GetExperimentStateHistoryRequest,
+) (*apiv1.GetExperimentStateHistoryResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId)); err != nil {
+		return nil, err
+	}
+	history, err := db.ExperimentStateHistory(ctx, int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetExperimentStateHistoryResponse{
+		Transitions: make([]*experimentv1.ExperimentStateTransition, 0, len(history)),
+	}
+	for _, t := range history {
+		resp.Transitions = append(resp.Transitions, t.Proto())
+	}
+	return resp, nil
+}
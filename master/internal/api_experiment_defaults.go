@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/expdefaults"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/templates"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) GetProjectExperimentDefaults(
+	ctx context.Context, req *apiv1.GetProjectExperimentDefaultsRequest,
+) (*apiv1.GetProjectExperimentDefaultsResponse, error) {
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId); err != nil {
+		return nil, err
+	}
+	d, err := expdefaults.Defaults(ctx, int(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetProjectExperimentDefaultsResponse{Defaults: d.Proto()}, nil
+}
+
+func (a *apiServer) PutProjectExperimentDefaults(
+	ctx context.Context, req *apiv1.PutProjectExperimentDefaultsRequest,
+) (*apiv1.PutProjectExperimentDefaultsResponse, error) {
+	d := &model.ProjectExperimentDefaults{
+		ProjectID:         int(req.ProjectId),
+		ResourcePool:      req.ResourcePool,
+		ForceResourcePool: req.ForceResourcePool,
+		CheckpointPolicy:  req.CheckpointPolicy,
+	}
+	if req.Priority != nil {
+		d.Priority = ptrs.Ptr(int(*req.Priority))
+	}
+	if err := expdefaults.Validate(d); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	p, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId,
+		project.AuthZProvider.Get().CanSetProjectExperimentDefaults)
+	if err != nil {
+		return nil, err
+	}
+	// The pool must exist and be available to the project's workspace.
+	if d.ResourcePool != nil {
+		if _, err = a.m.rm.ResolveResourcePool(
+			rm.ResourcePoolName(*d.ResourcePool), int(p.WorkspaceId), 0,
+		); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if err = expdefaults.SetDefaults(ctx, d); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutProjectExperimentDefaultsResponse{Defaults: d.Proto()}, nil
+}
+
+func (a *apiServer) PreviewExperimentConfig(
+	ctx context.Context, req *apiv1.PreviewExperimentConfigRequest,
+) (*apiv1.PreviewExperimentConfigResponse, error) {
+	if strings.TrimSpace(req.Config) == "" {
+		return nil, status.Error(codes.InvalidArgument, "config must be set")
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The config is resolved exactly as it would be to create the experiment.
+	dbExp, _, config, p, _, err := a.m.parseCreateExperiment(ctx,
+		&apiv1.CreateExperimentRequest{
+			Config:       req.Config,
+			ProjectId:    req.ProjectId,
+			Template:     req.Template,
+			ValidateOnly: true,
+		}, curUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both parsed without error while resolving the config above.
+	userConfig, err := expconf.ParseAnyExperimentConfigYAML([]byte(req.Config))
+	if err != nil {
+		return nil, err
+	}
+	var template *expconf.ExperimentConfig
+	if req.Template != nil {
+		template = &expconf.ExperimentConfig{}
+		if err = templates.UnmarshalTemplateConfig(
+			ctx, *req.Template, curUser, template, true,
+		); err != nil {
+			return nil, err
+		}
+	}
+	projectDefaults, err := expdefaults.Defaults(ctx, int(p.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	sources := map[string]string{}
+	for path, source := range expdefaults.Sources(userConfig, template, projectDefaults) {
+		sources[path] = string(source)
+	}
+	return &apiv1.PreviewExperimentConfigResponse{
+		ProjectId:  p.Id,
+		Config:     protoutils.ToStruct(config),
+		ConfigHash: dbExp.ConfigHash,
+		Sources:    sources,
+		Precedence: expdefaults.Precedence,
+	}, nil
+}
@@ -0,0 +1,74 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/expdefaults"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestProjectExperimentDefaults(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+
+	getResp, err := api.GetProjectExperimentDefaults(ctx, &apiv1.GetProjectExperimentDefaultsRequest{
+		ProjectId: int32(projectID),
+	})
+	require.NoError(t, err)
+	require.Nil(t, getResp.Defaults.ResourcePool)
+	require.Nil(t, getResp.Defaults.Priority)
+
+	putResp, err := api.PutProjectExperimentDefaults(ctx, &apiv1.PutProjectExperimentDefaultsRequest{
+		ProjectId:        int32(projectID),
+		Priority:         ptrs.Ptr(int32(10)),
+		CheckpointPolicy: ptrs.Ptr("none"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(10), putResp.Defaults.GetPriority())
+	require.Equal(t, "none", putResp.Defaults.GetCheckpointPolicy())
+
+	getResp, err = api.GetProjectExperimentDefaults(ctx, &apiv1.GetProjectExperimentDefaultsRequest{
+		ProjectId: int32(projectID),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(10), getResp.Defaults.GetPriority())
+
+	_, err = api.PutProjectExperimentDefaults(ctx, &apiv1.PutProjectExperimentDefaultsRequest{
+		ProjectId:         int32(projectID),
+		ForceResourcePool: true,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.GetProjectExperimentDefaults(ctx, &apiv1.GetProjectExperimentDefaultsRequest{
+		ProjectId: -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
+
+func TestPreviewExperimentConfig(t *testing.T) {
+	mockRM := MockRM()
+	api, _, ctx := setupAPITest(t, nil, mockRM)
+	mockRM.On("SmallerValueIsHigherPriority", mock.Anything).Return(true, nil)
+
+	resp, err := api.PreviewExperimentConfig(ctx, &apiv1.PreviewExperimentConfigRequest{
+		Config:    minExpConfToYaml(t),
+		ProjectId: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), resp.ProjectId)
+	require.NotEmpty(t, resp.GetConfigHash())
+	require.Equal(t, string(expdefaults.SourceConfig), resp.Sources["resources.resource_pool"])
+	require.Equal(t, expdefaults.Precedence, resp.Precedence)
+
+	_, err = api.PreviewExperimentConfig(ctx, &apiv1.PreviewExperimentConfigRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/expdupes"
+	"github.com/determined-ai/determined/master/internal/expmetadata"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+func (a *apiServer) GetWorkspaceDuplicateExperimentPolicy(
+	ctx context.Context, req *apiv1.GetWorkspaceDuplicateExperimentPolicyRequest,
+) (*apiv1.GetWorkspaceDuplicateExperimentPolicyResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false); err != nil {
+		return nil, err
+	}
+
+	policy, err := expdupes.WorkspacePolicy(ctx, int(req.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetWorkspaceDuplicateExperimentPolicyResponse{Policy: policy.Proto()}, nil
+}
+
+func (a *apiServer) PutWorkspaceDuplicateExperimentPolicy(
+	ctx context.Context, req *apiv1.PutWorkspaceDuplicateExperimentPolicyRequest,
+) (*apiv1.PutWorkspaceDuplicateExperimentPolicyResponse, error) {
+	policy, err := expdupes.PolicyFromProto(req.Policy)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, _, err = a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	); err != nil {
+		return nil, err
+	}
+
+	if err = expdupes.SetWorkspacePolicy(ctx, int(req.WorkspaceId), policy); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutWorkspaceDuplicateExperimentPolicyResponse{}, nil
+}
+
+func (a *apiServer) GetExperimentDuplicates(
+	ctx context.Context, req *apiv1.GetExperimentDuplicatesRequest,
+) (*apiv1.GetExperimentDuplicatesResponse, error) {
+	e, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := workspace.WorkspaceByProjectID(ctx, e.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := expdupes.WorkspacePolicy(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := expmetadata.Metadata(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	dups, err := expdupes.Find(ctx, w.ID, e, metadata)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetExperimentDuplicatesResponse{
+		WorkspaceId: int32(w.ID),
+		Policy:      policy.Proto(),
+		Duplicates:  []*experimentv1.DuplicateExperiment{},
+	}
+	for _, d := range dups {
+		resp.Duplicates = append(resp.Duplicates, d.Proto())
+	}
+	return resp, nil
+}
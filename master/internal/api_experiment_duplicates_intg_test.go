@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func TestWorkspaceDuplicateExperimentPolicy(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	wsID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	get, err := api.GetWorkspaceDuplicateExperimentPolicy(ctx,
+		&apiv1.GetWorkspaceDuplicateExperimentPolicyRequest{WorkspaceId: int32(wsID)})
+	require.NoError(t, err)
+	require.Equal(t, workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_OFF,
+		get.Policy)
+
+	_, err = api.PutWorkspaceDuplicateExperimentPolicy(ctx,
+		&apiv1.PutWorkspaceDuplicateExperimentPolicyRequest{
+			WorkspaceId: int32(wsID),
+			Policy:      workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_BLOCK,
+		})
+	require.NoError(t, err)
+	get, err = api.GetWorkspaceDuplicateExperimentPolicy(ctx,
+		&apiv1.GetWorkspaceDuplicateExperimentPolicyRequest{WorkspaceId: int32(wsID)})
+	require.NoError(t, err)
+	require.Equal(t, workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_BLOCK,
+		get.Policy)
+
+	_, err = api.PutWorkspaceDuplicateExperimentPolicy(ctx,
+		&apiv1.PutWorkspaceDuplicateExperimentPolicyRequest{WorkspaceId: int32(wsID)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.GetWorkspaceDuplicateExperimentPolicy(ctx,
+		&apiv1.GetWorkspaceDuplicateExperimentPolicyRequest{WorkspaceId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
+
+func TestGetExperimentDuplicates(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+
+	resp, err := api.GetExperimentDuplicates(ctx,
+		&apiv1.GetExperimentDuplicatesRequest{ExperimentId: int32(exp.ID)})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), resp.WorkspaceId)
+	require.Empty(t, resp.Duplicates)
+
+	_, err = api.GetExperimentDuplicates(ctx,
+		&apiv1.GetExperimentDuplicatesRequest{ExperimentId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
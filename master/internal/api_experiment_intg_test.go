@@ -43,6 +43,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/etc"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
 	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/schemas"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
@@ -148,6 +149,27 @@ var minExpConfig = expconf.ExperimentConfig{
 	},
 }
 
+func TestGetExperimentsInclude(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+	exp := createTestExpWithProjectID(t, api, curUser, projectID)
+
+	// An experiment without validations or checkpoints has nothing to include.
+	resp, err := api.GetExperiments(ctx, &apiv1.GetExperimentsRequest{
+		ProjectId: int32(projectID),
+		Include:   []string{"latest_validation,best_checkpoint", "owner"},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Experiments, 1)
+	require.Equal(t, int32(exp.ID), resp.Experiments[0].Id)
+	require.Nil(t, resp.Experiments[0].LatestValidation)
+	require.Nil(t, resp.Experiments[0].BestCheckpoint)
+	require.Equal(t, curUser.Username, resp.Experiments[0].Username)
+
+	_, err = api.GetExperiments(ctx, &apiv1.GetExperimentsRequest{Include: []string{"trials"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestGetExperimentLabels(t *testing.T) {
 	api, curUser, ctx := setupAPITest(t, nil)
 	_, p0 := createProjectAndWorkspace(ctx, t, api)
@@ -650,6 +672,43 @@ searcher:
 		"override config is provided and experiment is not single searcher, got 'random' instead")
 }
 
+// nolint: exhaustruct
+func TestExperimentConfigHash(t *testing.T) {
+	mockRM := MockRM()
+	api, _, ctx := setupAPITest(t, nil, mockRM)
+	mockRM.On("SmallerValueIsHigherPriority", mock.Anything).Return(true, nil)
+
+	createReq := &apiv1.CreateExperimentRequest{
+		ModelDefinition: []*utilv1.File{{Content: []byte{1}}},
+		Config:          minExpConfToYaml(t),
+		ProjectId:       1,
+		ValidateOnly:    true,
+	}
+	resp, err := api.CreateExperiment(ctx, createReq)
+	require.NoError(t, err)
+	hash := resp.Experiment.ConfigHash
+	require.NotEmpty(t, hash)
+
+	// Creating the experiment gives it the hash it was validated with.
+	createReq.ValidateOnly = false
+	resp, err = api.CreateExperiment(ctx, createReq)
+	require.NoError(t, err)
+	require.Equal(t, hash, resp.Experiment.ConfigHash)
+
+	getResp, err := api.GetExperiment(ctx, &apiv1.GetExperimentRequest{
+		ExperimentId: resp.Experiment.Id,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hash, getResp.Experiment.ConfigHash)
+
+	listResp, err := api.GetExperiments(ctx, &apiv1.GetExperimentsRequest{
+		ExperimentIdFilter: &commonv1.Int32FieldFilter{Incl: []int32{resp.Experiment.Id}},
+	})
+	require.NoError(t, err)
+	require.Len(t, listResp.Experiments, 1)
+	require.Equal(t, hash, listResp.Experiments[0].ConfigHash)
+}
+
 // nolint: exhaustruct
 func TestCreateExperimentCheckpointStorage(t *testing.T) {
 	mockRM := MockRM()
@@ -1530,6 +1589,31 @@ func TestAuthZPreviewHPSearch(t *testing.T) {
 	require.Equal(t, expectedErr.Error(), err.Error())
 }
 
+func TestPreviewHPSearchSamples(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+
+	req := &apiv1.PreviewHPSearchRequest{Config: protoutils.ToStruct(minExpConfig)}
+	resp, err := api.PreviewHPSearch(ctx, req)
+	require.NoError(t, err)
+	require.Empty(t, resp.Samples)
+	require.Equal(t, uint32(42), resp.ExperimentSeed)
+	require.Equal(t, int32(1), resp.EstimatedTrials)
+	require.Equal(t, "batches", resp.EstimatedUnit)
+	require.Equal(t, int64(10), resp.EstimatedUnits)
+	require.Equal(t, int64(10), resp.EstimatedSlotUnits)
+
+	// A single-trial search has only one trial to sample.
+	req.SampleCount = 5
+	resp, err = api.PreviewHPSearch(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Samples, 1)
+	require.Contains(t, resp.Samples[0].AsMap(), "trial_seed")
+
+	req.SampleCount = maxPreviewSampleCount + 1
+	_, err = api.PreviewHPSearch(ctx, req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestAuthZGetExperimentLabels(t *testing.T) {
 	api, authZExp, authZProject, curUser, ctx := setupExpAuthTest(t, nil)
 	_, projectID := createProjectAndWorkspace(ctx, t, api)
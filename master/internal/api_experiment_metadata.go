@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/expmetadata"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) GetExperimentMetadata(
+	ctx context.Context, req *apiv1.GetExperimentMetadataRequest,
+) (*apiv1.GetExperimentMetadataResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId)); err != nil {
+		return nil, err
+	}
+	metadata, err := expmetadata.Metadata(ctx, int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetExperimentMetadataResponse{Metadata: protoutils.ToStruct(metadata)}, nil
+}
+
+func (a *apiServer) PutExperimentMetadata(
+	ctx context.Context, req *apiv1.PutExperimentMetadataRequest,
+) (*apiv1.PutExperimentMetadataResponse, error) {
+	e, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanEditExperimentsMetadata)
+	if err != nil {
+		return nil, err
+	}
+	err = expmetadata.SetMetadata(ctx, e.ID, e.ProjectID, req.Metadata.AsMap())
+	if errors.Is(err, db.ErrInvalidInput) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	metadata, err := expmetadata.Metadata(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PutExperimentMetadataResponse{Metadata: protoutils.ToStruct(metadata)}, nil
+}
+
+func (a *apiServer) GetProjectExperimentMetadataSchema(
+	ctx context.Context, req *apiv1.GetProjectExperimentMetadataSchemaRequest,
+) (*apiv1.GetProjectExperimentMetadataSchemaResponse, error) {
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId); err != nil {
+		return nil, err
+	}
+	s, err := expmetadata.Schema(ctx, int(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, api.NotFoundErrs("experiment metadata schema of project",
+			strconv.Itoa(int(req.ProjectId)), true)
+	}
+	return &apiv1.GetProjectExperimentMetadataSchemaResponse{Schema: s.Proto()}, nil
+}
+
+func (a *apiServer) PutProjectExperimentMetadataSchema(
+	ctx context.Context, req *apiv1.PutProjectExperimentMetadataSchemaRequest,
+) (*apiv1.PutProjectExperimentMetadataSchemaResponse, error) {
+	if req.Schema == nil {
+		return nil, status.Error(codes.InvalidArgument, "schema must be set")
+	}
+	schema, err := json.Marshal(req.Schema.AsMap())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, _, err = a.getProjectAndCheckCanDoActions(ctx, req.ProjectId,
+		project.AuthZProvider.Get().CanSetProjectMetadataSchemas,
+	); err != nil {
+		return nil, err
+	}
+	err = expmetadata.SetSchema(ctx, int(req.ProjectId), schema)
+	if errors.Is(err, db.ErrInvalidInput) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	s, err := expmetadata.Schema(ctx, int(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PutProjectExperimentMetadataSchemaResponse{Schema: s.Proto()}, nil
+}
+
+func (a *apiServer) DeleteProjectExperimentMetadataSchema(
+	ctx context.Context, req *apiv1.DeleteProjectExperimentMetadataSchemaRequest,
+) (*apiv1.DeleteProjectExperimentMetadataSchemaResponse, error) {
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId,
+		project.AuthZProvider.Get().CanSetProjectMetadataSchemas,
+	); err != nil {
+		return nil, err
+	}
+	if err := expmetadata.DeleteSchema(ctx, int(req.ProjectId)); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteProjectExperimentMetadataSchemaResponse{}, nil
+}
@@ -0,0 +1,71 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestExperimentMetadataAPI(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+	exp := createTestExpWithProjectID(t, api, curUser, projectID)
+
+	_, err := api.GetProjectExperimentMetadataSchema(ctx,
+		&apiv1.GetProjectExperimentMetadataSchemaRequest{ProjectId: int32(projectID)})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	schema, err := structpb.NewStruct(map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"ticket_url"},
+	})
+	require.NoError(t, err)
+	putSchema, err := api.PutProjectExperimentMetadataSchema(ctx,
+		&apiv1.PutProjectExperimentMetadataSchemaRequest{
+			ProjectId: int32(projectID), Schema: schema,
+		})
+	require.NoError(t, err)
+	require.Equal(t, "object", putSchema.Schema.Schema.AsMap()["type"])
+
+	bad, err := structpb.NewStruct(map[string]interface{}{"type": 1})
+	require.NoError(t, err)
+	_, err = api.PutProjectExperimentMetadataSchema(ctx,
+		&apiv1.PutProjectExperimentMetadataSchemaRequest{ProjectId: int32(projectID), Schema: bad})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	// The metadata must follow the project's schema.
+	_, err = api.PutExperimentMetadata(ctx, &apiv1.PutExperimentMetadataRequest{
+		ExperimentId: int32(exp.ID), Metadata: &structpb.Struct{},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	metadata, err := structpb.NewStruct(map[string]interface{}{"ticket_url": "https://t/1"})
+	require.NoError(t, err)
+	put, err := api.PutExperimentMetadata(ctx, &apiv1.PutExperimentMetadataRequest{
+		ExperimentId: int32(exp.ID), Metadata: metadata,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://t/1", put.Metadata.AsMap()["ticket_url"])
+
+	get, err := api.GetExperimentMetadata(ctx, &apiv1.GetExperimentMetadataRequest{
+		ExperimentId: int32(exp.ID),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://t/1", get.Metadata.AsMap()["ticket_url"])
+
+	_, err = api.DeleteProjectExperimentMetadataSchema(ctx,
+		&apiv1.DeleteProjectExperimentMetadataSchemaRequest{ProjectId: int32(projectID)})
+	require.NoError(t, err)
+	_, err = api.PutExperimentMetadata(ctx, &apiv1.PutExperimentMetadataRequest{
+		ExperimentId: int32(exp.ID), Metadata: &structpb.Struct{},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/expnaming"
+	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) GetProjectExperimentNaming(
+	ctx context.Context, req *apiv1.GetProjectExperimentNamingRequest,
+) (*apiv1.GetProjectExperimentNamingResponse, error) {
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId); err != nil {
+		return nil, err
+	}
+	n, err := expnaming.Naming(ctx, int(req.ProjectId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetProjectExperimentNamingResponse{Naming: n.Proto()}, nil
+}
+
+func (a *apiServer) PutProjectExperimentNaming(
+	ctx context.Context, req *apiv1.PutProjectExperimentNamingRequest,
+) (*apiv1.PutProjectExperimentNamingResponse, error) {
+	if req.NameTemplate != nil {
+		if err := expnaming.ValidateTemplate(*req.NameTemplate); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if _, _, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId,
+		project.AuthZProvider.Get().CanSetProjectName,
+	); err != nil {
+		return nil, err
+	}
+	n := &model.ProjectExperimentNaming{
+		ProjectID:    int(req.ProjectId),
+		UniqueNames:  req.UniqueNames,
+		NameTemplate: req.NameTemplate,
+	}
+	if err := expnaming.SetNaming(ctx, n); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutProjectExperimentNamingResponse{Naming: n.Proto()}, nil
+}
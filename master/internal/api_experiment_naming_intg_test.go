@@ -0,0 +1,49 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestProjectExperimentNaming(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+
+	getResp, err := api.GetProjectExperimentNaming(ctx, &apiv1.GetProjectExperimentNamingRequest{
+		ProjectId: int32(projectID),
+	})
+	require.NoError(t, err)
+	require.False(t, getResp.Naming.UniqueNames)
+	require.Nil(t, getResp.Naming.NameTemplate)
+	require.Equal(t, int32(1), getResp.Naming.NextSeq)
+
+	putResp, err := api.PutProjectExperimentNaming(ctx, &apiv1.PutProjectExperimentNamingRequest{
+		ProjectId:    int32(projectID),
+		UniqueNames:  true,
+		NameTemplate: ptrs.Ptr("{user}-{seq}"),
+	})
+	require.NoError(t, err)
+	require.True(t, putResp.Naming.UniqueNames)
+	require.Equal(t, "{user}-{seq}", putResp.Naming.GetNameTemplate())
+	require.Equal(t, int32(1), putResp.Naming.NextSeq)
+
+	_, err = api.PutProjectExperimentNaming(ctx, &apiv1.PutProjectExperimentNamingRequest{
+		ProjectId:    int32(projectID),
+		NameTemplate: ptrs.Ptr("{unknown}"),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.GetProjectExperimentNaming(ctx, &apiv1.GetProjectExperimentNamingRequest{
+		ProjectId: -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
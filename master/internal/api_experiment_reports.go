@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/report"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+func (a *apiServer) GenerateExperimentReport(
+	ctx context.Context, req *apiv1.GenerateExperimentReportRequest,
+) (*apiv1.GenerateExperimentReportResponse, error) {
+	opts := report.Options{
+		Format:    model.ExperimentReportFormat(req.Format),
+		TopTrials: int(req.TopTrials),
+		Metrics:   req.Metrics,
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	exp, user, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanEditExperiment)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := report.Build(ctx, exp, opts)
+	if err != nil {
+		return nil, err
+	}
+	content, err := report.Render(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	stored := &model.ExperimentReport{
+		ExperimentID: exp.ID,
+		Format:       opts.Format,
+		Content:      content,
+		CreatedBy:    user.ID,
+	}
+	if err = report.SaveReport(ctx, stored); err != nil {
+		return nil, err
+	}
+	return &apiv1.GenerateExperimentReportResponse{Report: stored.Proto()}, nil
+}
+
+func (a *apiServer) GetExperimentReports(
+	ctx context.Context, req *apiv1.GetExperimentReportsRequest,
+) (*apiv1.GetExperimentReportsResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+		return nil, err
+	}
+	reports, err := report.Reports(ctx, int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetExperimentReportsResponse{Reports: []*experimentv1.ExperimentReport{}}
+	for _, r := range reports {
+		resp.Reports = append(resp.Reports, r.Proto())
+	}
+	return resp, nil
+}
@@ -0,0 +1,43 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestExperimentReportsAPI(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+
+	_, err := api.GenerateExperimentReport(ctx, &apiv1.GenerateExperimentReportRequest{
+		ExperimentId: int32(exp.ID), Format: "pdf",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	gen, err := api.GenerateExperimentReport(ctx, &apiv1.GenerateExperimentReportRequest{
+		ExperimentId: int32(exp.ID), Format: "markdown", TopTrials: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(exp.ID), gen.Report.ExperimentId)
+	require.Equal(t, "markdown", gen.Report.Format)
+	require.Equal(t, int32(curUser.ID), gen.Report.CreatedBy)
+
+	list, err := api.GetExperimentReports(ctx, &apiv1.GetExperimentReportsRequest{
+		ExperimentId: int32(exp.ID),
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Reports, 1)
+	require.Equal(t, gen.Report.Id, list.Reports[0].Id)
+
+	_, err = api.GetExperimentReports(ctx, &apiv1.GetExperimentReportsRequest{ExperimentId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
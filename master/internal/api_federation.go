@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/federation"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+const localFederationClusterName = "local"
+
+// federationClusterName is the name of this master's cluster in a merged view.
+func (m *Master) federationClusterName() string {
+	if m.config.ClusterName != "" {
+		return m.config.ClusterName
+	}
+	return localFederationClusterName
+}
+
+// canViewFederation returns whether the user may see what the peers' tokens can see. Users who
+// can't only see this cluster's experiments and jobs, which are checked as usual.
+func canViewFederation(ctx context.Context) (bool, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanViewFederation(ctx, curUser)
+	if err != nil {
+		return false, err
+	}
+	return permErr == nil, nil
+}
+
+func federationClustersToProto(clusters []federation.Cluster) []*masterv1.FederationCluster {
+	pbs := make([]*masterv1.FederationCluster, 0, len(clusters))
+	for _, c := range clusters {
+		pbs = append(pbs, c.Proto())
+	}
+	return pbs
+}
+
+func (a *apiServer) GetFederationExperiments(
+	ctx context.Context, req *apiv1.GetFederationExperimentsRequest,
+) (*apiv1.GetFederationExperimentsResponse, error) {
+	limit := federation.DefaultExperimentLimit
+	if req.Limit != nil {
+		if *req.Limit < 1 || *req.Limit > federation.MaxExperimentLimit {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"limit must be between 1 and %d", federation.MaxExperimentLimit)
+		}
+		limit = int(*req.Limit)
+	}
+
+	resp, err := a.GetExperiments(ctx, &apiv1.GetExperimentsRequest{
+		Limit:    int32(limit),
+		Archived: wrapperspb.Bool(false),
+		SortBy:   apiv1.GetExperimentsRequest_SORT_BY_START_TIME,
+		OrderBy:  apiv1.OrderBy_ORDER_BY_DESC,
+	})
+	if err != nil {
+		return nil, err
+	}
+	name := a.m.federationClusterName()
+	experiments := make([]federation.Experiment, 0, len(resp.Experiments))
+	for _, e := range resp.Experiments {
+		experiments = append(experiments, federation.ExperimentFromProto(name, e))
+	}
+
+	var peers []federation.Cluster
+	if canView, err := canViewFederation(ctx); err != nil {
+		return nil, err
+	} else if canView {
+		var peerExperiments []federation.Experiment
+		peerExperiments, peers = federation.Experiments(ctx, a.m.federationPeers, limit)
+		experiments = append(experiments, peerExperiments...)
+	}
+	federation.SortExperiments(experiments)
+
+	out := &apiv1.GetFederationExperimentsResponse{
+		Clusters:    federationClustersToProto(append([]federation.Cluster{{Name: name}}, peers...)),
+		Experiments: make([]*masterv1.FederationExperiment, 0, len(experiments)),
+	}
+	for _, e := range experiments {
+		out.Experiments = append(out.Experiments, e.Proto())
+	}
+	return out, nil
+}
+
+func (a *apiServer) GetFederationJobs(
+	ctx context.Context, req *apiv1.GetFederationJobsRequest,
+) (*apiv1.GetFederationJobsResponse, error) {
+	pools, err := a.GetResourcePools(ctx, &apiv1.GetResourcePoolsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	name := a.m.federationClusterName()
+	jobs := []federation.Job{}
+	for _, pool := range pools.ResourcePools {
+		resp, err := a.GetJobs(ctx, &apiv1.GetJobsRequest{
+			ResourcePool: pool.Name,
+			Limit:        federation.MaxJobsPerPool,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range resp.Jobs {
+			jobs = append(jobs, federation.JobFromProto(name, j))
+		}
+	}
+
+	var peers []federation.Cluster
+	if canView, err := canViewFederation(ctx); err != nil {
+		return nil, err
+	} else if canView {
+		var peerJobs []federation.Job
+		peerJobs, peers = federation.Jobs(ctx, a.m.federationPeers)
+		jobs = append(jobs, peerJobs...)
+	}
+	federation.SortJobs(jobs)
+
+	out := &apiv1.GetFederationJobsResponse{
+		Clusters: federationClustersToProto(append([]federation.Cluster{{Name: name}}, peers...)),
+		Jobs:     make([]*masterv1.FederationJob, 0, len(jobs)),
+	}
+	for _, j := range jobs {
+		out.Jobs = append(out.Jobs, j.Proto())
+	}
+	return out, nil
+}
@@ -0,0 +1,38 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestGetFederationExperiments(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+
+	resp, err := api.GetFederationExperiments(ctx, &apiv1.GetFederationExperimentsRequest{
+		Limit: ptrs.Ptr(int32(1000)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, api.m.federationClusterName(), resp.Clusters[0].Name)
+	var ids []int32
+	for _, e := range resp.Experiments {
+		require.Equal(t, api.m.federationClusterName(), e.Cluster)
+		ids = append(ids, e.Id)
+	}
+	require.Contains(t, ids, int32(exp.ID))
+
+	_, err = api.GetFederationExperiments(ctx, &apiv1.GetFederationExperimentsRequest{
+		Limit: ptrs.Ptr(int32(0)),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
@@ -19,10 +19,13 @@ import (
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/command"
 	masterConfig "github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/configpolicy"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/envvarsets"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/project"
+	"github.com/determined-ai/determined/master/internal/raycluster"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
@@ -86,6 +89,9 @@ func (a *apiServer) getGenericTaskLaunchParameters(
 			nil,
 			status.Errorf(codes.Unauthenticated, "failed to get the user: %s", err)
 	}
+	if err = checkCanSubmit(ctx); err != nil {
+		return nil, nil, nil, err
+	}
 
 	proj, err := a.GetProjectByID(ctx, int32(genericTaskSpec.ProjectID), *userModel)
 	if err != nil {
@@ -148,6 +154,25 @@ func (a *apiServer) getGenericTaskLaunchParameters(
 		return nil, nil, nil, err
 	}
 
+	// Check submitted config against task config policies.
+	err = configpolicy.CheckNTSCConstraints(ctx, int(proj.WorkspaceId), model.CommandConfig{
+		BindMounts: taskConfig.BindMounts,
+		Resources: model.ResourcesConfig{
+			Slots:    resources.Slots,
+			MaxSlots: taskConfig.Resources.MaxSlots(),
+			Priority: taskConfig.Resources.Priority(),
+		},
+	}, a.m.rm)
+	if err != nil {
+		return nil, nil, nil, status.Errorf(codes.InvalidArgument, "failed constraint check: %v", err)
+	}
+
+	err = a.checkCanOptOutOfPodSecurity(ctx, *userModel, proj.WorkspaceId,
+		taskSpec.TaskContainerDefaults, taskConfig.Environment.PodSpec)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	var token string
 	token, err = getTaskSessionToken(ctx, userModel)
 	if err != nil {
@@ -158,11 +183,31 @@ func (a *apiServer) getGenericTaskLaunchParameters(
 
 	genericTaskSpec.Base = taskSpec
 	genericTaskSpec.GenericTaskConfig = taskConfig
+	if taskConfig.Spark != nil {
+		if a.m.config.Integrations.Spark == nil {
+			return nil, nil, nil, status.Error(codes.FailedPrecondition,
+				"spark jobs can't be submitted: integrations.spark isn't configured on the master")
+		}
+		genericTaskSpec.SparkBackend = a.m.config.Integrations.Spark
+	}
 
 	genericTaskSpec.Base.ExtraEnvVars = map[string]string{
 		"DET_TASK_TYPE": string(model.TaskTypeGeneric),
 	}
 
+	// Generic tasks don't know their workspace when they start, unlike other tasks, so their
+	// environment variable sets are resolved now.
+	envVars, err := envvarsets.Resolve(ctx, genericTaskSpec.WorkspaceID,
+		taskConfig.Environment.EnvironmentVariableSets)
+	if missingErr := (envvarsets.MissingError{}); errors.As(err, &missingErr) {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, nil, nil, err
+	}
+	for k, v := range envVars {
+		genericTaskSpec.Base.ExtraEnvVars[k] = v
+	}
+
 	return genericTaskSpec, launchWarnings, contextDirectoryBytes, nil
 }
 
@@ -354,10 +399,11 @@ func (a *apiServer) CreateGenericTask(
 	allocationID := model.AllocationID(fmt.Sprintf("%s.%d", taskID, 1))
 	isSingleNode := genericTaskSpec.GenericTaskConfig.Resources.IsSingleNode() != nil &&
 		*genericTaskSpec.GenericTaskConfig.Resources.IsSingleNode()
-	err = task.DefaultService.StartAllocation(logCtx, sproto.AllocateRequest{
+	allocateReq := sproto.AllocateRequest{
 		AllocationID:      allocationID,
 		TaskID:            taskID,
 		JobID:             jobID,
+		TaskType:          model.TaskTypeGeneric,
 		JobSubmissionTime: startTime,
 		IsUserVisible:     true,
 		Name:              fmt.Sprintf("Generic Task %s", taskID),
@@ -369,8 +415,13 @@ func (a *apiServer) CreateGenericTask(
 		},
 
 		Restore: false,
-	}, a.m.db, a.m.rm, genericTaskSpec, onAllocationExit)
+	}
+	configureGenericTaskAllocation(&allocateReq, genericTaskSpec)
+	registerRayCluster(taskID, genericTaskSpec)
+	err = task.DefaultService.StartAllocation(
+		logCtx, allocateReq, a.m.db, a.m.rm, genericTaskSpec, onAllocationExit)
 	if err != nil {
+		raycluster.Unregister(taskID)
 		return nil, err
 	}
 
@@ -656,27 +707,32 @@ func (a *apiServer) UnpauseGenericTask(
 		resumingAllocationID := model.AllocationID(fmt.Sprintf("%s.%d", resumingTask.TaskID, allocationSpecifier+1))
 		isSingleNode := genericTaskSpec.GenericTaskConfig.Resources.IsSingleNode() != nil &&
 			*genericTaskSpec.GenericTaskConfig.Resources.IsSingleNode()
+		allocateReq := sproto.AllocateRequest{
+			AllocationID:      resumingAllocationID,
+			TaskID:            resumingTask.TaskID,
+			JobID:             *resumingTask.JobID,
+			TaskType:          model.TaskTypeGeneric,
+			JobSubmissionTime: time.Now().UTC(),
+			RequestTime:       time.Now().UTC(),
+			IsUserVisible:     true,
+			Name:              fmt.Sprintf("Generic Task %s", resumingTask.TaskID),
+			SlotsNeeded:       *genericTaskSpec.GenericTaskConfig.Resources.Slots(),
+			ResourcePool:      genericTaskSpec.GenericTaskConfig.Resources.ResourcePool(),
+			FittingRequirements: sproto.FittingRequirements{
+				SingleAgent: isSingleNode,
+			},
+			Preemption: sproto.PreemptionConfig{
+				Preemptible:     true,
+				TimeoutDuration: time.Duration(genericTaskSpec.GenericTaskConfig.PreemptionTimeout) * time.Second,
+			},
+			Restore: false,
+		}
+		configureGenericTaskAllocation(&allocateReq, genericTaskSpec)
+		registerRayCluster(resumingTask.TaskID, genericTaskSpec)
 		err = task.DefaultService.StartAllocation(
-			logCtx, sproto.AllocateRequest{
-				AllocationID:      resumingAllocationID,
-				TaskID:            resumingTask.TaskID,
-				JobID:             *resumingTask.JobID,
-				JobSubmissionTime: time.Now().UTC(),
-				RequestTime:       time.Now().UTC(),
-				IsUserVisible:     true,
-				Name:              fmt.Sprintf("Generic Task %s", resumingTask.TaskID),
-				SlotsNeeded:       *genericTaskSpec.GenericTaskConfig.Resources.Slots(),
-				ResourcePool:      genericTaskSpec.GenericTaskConfig.Resources.ResourcePool(),
-				FittingRequirements: sproto.FittingRequirements{
-					SingleAgent: isSingleNode,
-				},
-				Preemption: sproto.PreemptionConfig{
-					Preemptible:     true,
-					TimeoutDuration: time.Duration(genericTaskSpec.GenericTaskConfig.PreemptionTimeout) * time.Second,
-				},
-				Restore: false,
-			}, a.m.db, a.m.rm, genericTaskSpec, onAllocationExit)
+			logCtx, allocateReq, a.m.db, a.m.rm, genericTaskSpec, onAllocationExit)
 		if err != nil {
+			raycluster.Unregister(resumingTask.TaskID)
 			return nil, err
 		}
 		err = persistGenericTaskSpec(ctx, resumingTask.TaskID, *genericTaskSpec, resumingAllocationID)
@@ -5,9 +5,9 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
+	"github.com/determined-ai/determined/master/internal/job/jobstream"
 	"github.com/determined-ai/determined/master/internal/rm"
 
-	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/job"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
@@ -61,31 +61,10 @@ func (a *apiServer) GetJobsV2(
 	if err != nil {
 		return nil, err
 	}
-	resp = &apiv1.GetJobsV2Response{Jobs: make([]*jobv1.RBACJob, 0)}
-
-	okJobs, err := job.AuthZProvider.Get().FilterJobs(ctx, *curUser, jobs)
-	if err != nil {
+	resp = &apiv1.GetJobsV2Response{}
+	if resp.Jobs, err = jobstream.RBACJobs(ctx, *curUser, jobs); err != nil {
 		return nil, err
 	}
-	okJobsMap := make(map[string]bool)
-	for _, j := range okJobs {
-		okJobsMap[j.JobId] = true
-	}
-
-	for _, job := range jobs {
-		j := jobv1.RBACJob{}
-		if ok := okJobsMap[job.JobId]; ok {
-			j.Job = &jobv1.RBACJob_Full{
-				Full: job,
-			}
-		} else {
-			limitedJob := authz.ObfuscateJob(job)
-			j.Job = &jobv1.RBACJob_Limited{
-				Limited: &limitedJob,
-			}
-		}
-		resp.Jobs = append(resp.Jobs, &j)
-	}
 
 	if req.Limit == 0 {
 		req.Limit = 100
@@ -19,6 +19,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/httppolicy"
 	"github.com/determined-ai/determined/master/internal/license"
 	"github.com/determined-ai/determined/master/internal/plugin/sso"
 	"github.com/determined-ai/determined/master/pkg/logger"
@@ -47,7 +48,7 @@ func (a *apiServer) GetMaster(
 		MasterId:              a.m.MasterID,
 		ClusterId:             a.m.ClusterID,
 		ClusterName:           a.m.config.ClusterName,
-		TelemetryEnabled:      a.m.config.Telemetry.Enabled && a.m.config.Telemetry.SegmentWebUIKey != "",
+		TelemetryEnabled:      a.m.config.Telemetry.WebUISegmentEnabled(),
 		HasCustomLogo:         a.m.config.UICustomization.HasCustomLogo(),
 		ExternalLoginUri:      a.m.config.InternalConfig.ExternalSessions.LoginURI,
 		ExternalLogoutUri:     a.m.config.InternalConfig.ExternalSessions.LogoutURI,
@@ -77,7 +78,7 @@ func (a *apiServer) GetTelemetry(
 	_ context.Context, _ *apiv1.GetTelemetryRequest,
 ) (*apiv1.GetTelemetryResponse, error) {
 	resp := apiv1.GetTelemetryResponse{}
-	if a.m.config.Telemetry.Enabled && a.m.config.Telemetry.SegmentWebUIKey != "" {
+	if a.m.config.Telemetry.WebUISegmentEnabled() {
 		resp.Enabled = true
 		resp.SegmentKey = a.m.config.Telemetry.SegmentWebUIKey
 	}
@@ -366,6 +367,94 @@ func (a *apiServer) DeleteClusterMessage(
 	return &apiv1.DeleteClusterMessageResponse{}, nil
 }
 
+func (a *apiServer) GetHTTPPolicy(
+	ctx context.Context, req *apiv1.GetHTTPPolicyRequest,
+) (*apiv1.GetHTTPPolicyResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permErr, err := cluster.AuthZProvider.Get().CanGetMasterConfig(ctx, u)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, permErr
+	}
+
+	return &apiv1.GetHTTPPolicyResponse{Policy: httpPolicyToProto(httppolicy.Get())}, nil
+}
+
+func (a *apiServer) PutHTTPPolicy(
+	ctx context.Context, req *apiv1.PutHTTPPolicyRequest,
+) (*apiv1.PutHTTPPolicyResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, u)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, permErr
+	}
+
+	p := httpPolicyFromProto(req.Policy)
+	if errs := p.Validate(); len(errs) > 0 {
+		return nil, status.Error(codes.InvalidArgument, errors.Join(errs...).Error())
+	}
+	if err = saveHTTPPolicy(ctx, p, u.ID); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutHTTPPolicyResponse{Policy: httpPolicyToProto(p)}, nil
+}
+
+func (a *apiServer) DeleteHTTPPolicy(
+	ctx context.Context, req *apiv1.DeleteHTTPPolicyRequest,
+) (*apiv1.DeleteHTTPPolicyResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, u)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, permErr
+	}
+
+	if err = a.m.deleteHTTPPolicy(ctx); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteHTTPPolicyResponse{Policy: httpPolicyToProto(a.m.config.HTTPPolicy)}, nil
+}
+
+func httpPolicyToProto(p config.HTTPPolicyConfig) *apiv1.HTTPPolicy {
+	return &apiv1.HTTPPolicy{
+		Cors: &apiv1.CORSPolicy{
+			AllowedOrigins:   p.CORS.AllowedOrigins,
+			AllowCredentials: p.CORS.AllowCredentials,
+			MaxAge:           int32(p.CORS.MaxAge),
+		},
+		ContentSecurityPolicy:           p.ContentSecurityPolicy,
+		ContentSecurityPolicyReportOnly: p.ContentSecurityPolicyReportOnly,
+	}
+}
+
+func httpPolicyFromProto(p *apiv1.HTTPPolicy) config.HTTPPolicyConfig {
+	return config.HTTPPolicyConfig{
+		CORS: config.CORSConfig{
+			AllowedOrigins:   p.GetCors().GetAllowedOrigins(),
+			AllowCredentials: p.GetCors().GetAllowCredentials(),
+			MaxAge:           int(p.GetCors().GetMaxAge()),
+		},
+		ContentSecurityPolicy:           p.GetContentSecurityPolicy(),
+		ContentSecurityPolicyReportOnly: p.GetContentSecurityPolicyReportOnly(),
+	}
+}
+
 func (a *apiServer) GetKubernetesResourceManagers(
 	ctx context.Context,
 	req *apiv1.GetKubernetesResourceManagersRequest,
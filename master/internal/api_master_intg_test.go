@@ -9,8 +9,13 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/uptrace/bun"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/httppolicy"
+	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/pkg/etc"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/masterv1"
@@ -217,3 +222,56 @@ func TestResourceAllocationAggregatedDaily(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPPolicy(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	t.Cleanup(func() {
+		_, err := api.DeleteHTTPPolicy(ctx, &apiv1.DeleteHTTPPolicyRequest{})
+		require.NoError(t, err)
+	})
+
+	_, err := api.PutHTTPPolicy(ctx, &apiv1.PutHTTPPolicyRequest{Policy: &apiv1.HTTPPolicy{
+		Cors: &apiv1.CORSPolicy{
+			AllowedOrigins:   []string{"https://*.example.com"},
+			AllowCredentials: true,
+		},
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	policy := &apiv1.HTTPPolicy{
+		Cors: &apiv1.CORSPolicy{
+			AllowedOrigins:   []string{"https://portal.example.com"},
+			AllowCredentials: true,
+			MaxAge:           600,
+		},
+		ContentSecurityPolicy: "frame-ancestors 'self' https://portal.example.com",
+	}
+	put, err := api.PutHTTPPolicy(ctx, &apiv1.PutHTTPPolicyRequest{Policy: policy})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(policy, put.Policy))
+	get, err := api.GetHTTPPolicy(ctx, &apiv1.GetHTTPPolicyRequest{})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(policy, get.Policy))
+	require.True(t, httppolicy.Get().CORS.OriginAllowed("https://portal.example.com"))
+
+	// The policy set through the API is applied again after a restart.
+	httppolicy.Set(api.m.config.HTTPPolicy)
+	require.NoError(t, api.m.loadHTTPPolicy(ctx))
+	require.True(t, httppolicy.Get().CORS.OriginAllowed("https://portal.example.com"))
+
+	del, err := api.DeleteHTTPPolicy(ctx, &apiv1.DeleteHTTPPolicyRequest{})
+	require.NoError(t, err)
+	require.Empty(t, del.Policy.Cors.AllowedOrigins)
+	require.False(t, httppolicy.Get().CORS.OriginAllowed("https://portal.example.com"))
+
+	curUser.Admin = false
+	require.NoError(t, user.Update(ctx, &curUser, []string{"admin"}, nil))
+	t.Cleanup(func() {
+		curUser.Admin = true
+		require.NoError(t, user.Update(ctx, &curUser, []string{"admin"}, nil))
+	})
+	_, err = api.PutHTTPPolicy(ctx, &apiv1.PutHTTPPolicyRequest{Policy: policy})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+	_, err = api.DeleteHTTPPolicy(ctx, &apiv1.DeleteHTTPPolicyRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/metricsexport"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func (a *apiServer) GetWorkspaceMetricsExports(
+	ctx context.Context, req *apiv1.GetWorkspaceMetricsExportsRequest,
+) (*apiv1.GetWorkspaceMetricsExportsResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false); err != nil {
+		return nil, err
+	}
+
+	ds, err := metricsexport.Destinations(ctx, int(req.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetWorkspaceMetricsExportsResponse{
+		Destinations: []*workspacev1.MetricsExportDestination{},
+	}
+	for _, d := range ds {
+		resp.Destinations = append(resp.Destinations, d.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostWorkspaceMetricsExport(
+	ctx context.Context, req *apiv1.PostWorkspaceMetricsExportRequest,
+) (*apiv1.PostWorkspaceMetricsExportResponse, error) {
+	exportType, err := model.MetricsExportTypeFromProto(req.Type)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	switch {
+	case req.Name == "":
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	case exportType == model.MetricsExportWandB &&
+		(req.ApiKey == "" || req.Project == "" || req.Entity == ""):
+		return nil, status.Error(codes.InvalidArgument,
+			"api_key, project and entity are required for wandb")
+	}
+
+	if _, _, err = a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	); err != nil {
+		return nil, err
+	}
+	// The destination is only resolved for users who may add it.
+	if err = metricsexport.CheckDestination(ctx, req.Url); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	d := model.MetricsExportDestination{
+		WorkspaceID: int(req.WorkspaceId),
+		Type:        exportType,
+		Name:        req.Name,
+		URL:         req.Url,
+		APIKey:      req.ApiKey,
+		Project:     req.Project,
+		Entity:      req.Entity,
+	}
+	if err = metricsexport.AddDestination(ctx, &d); err != nil {
+		return nil, err
+	}
+	return &apiv1.PostWorkspaceMetricsExportResponse{Destination: d.Proto()}, nil
+}
+
+func (a *apiServer) DeleteWorkspaceMetricsExport(
+	ctx context.Context, req *apiv1.DeleteWorkspaceMetricsExportRequest,
+) (*apiv1.DeleteWorkspaceMetricsExportResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	); err != nil {
+		return nil, err
+	}
+
+	err := metricsexport.DeleteDestination(ctx, int(req.WorkspaceId), int(req.DestinationId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("metrics export destination", fmt.Sprint(req.DestinationId), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteWorkspaceMetricsExportResponse{}, nil
+}
@@ -0,0 +1,73 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func TestWorkspaceMetricsExports(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	post, err := api.PostWorkspaceMetricsExport(ctx, &apiv1.PostWorkspaceMetricsExportRequest{
+		WorkspaceId: int32(workspaceID),
+		Type:        workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_MLFLOW,
+		Name:        "team-mlflow",
+		Url:         "https://203.0.113.1",
+		ApiKey:      "secret",
+	})
+	require.NoError(t, err)
+
+	get, err := api.GetWorkspaceMetricsExports(ctx, &apiv1.GetWorkspaceMetricsExportsRequest{
+		WorkspaceId: int32(workspaceID),
+	})
+	require.NoError(t, err)
+	require.Len(t, get.Destinations, 1)
+	require.Equal(t, post.Destination.Id, get.Destinations[0].Id)
+	require.Equal(t, int32(0), get.Destinations[0].Pending)
+
+	req := &apiv1.DeleteWorkspaceMetricsExportRequest{
+		WorkspaceId:   int32(workspaceID),
+		DestinationId: post.Destination.Id,
+	}
+	_, err = api.DeleteWorkspaceMetricsExport(ctx, req)
+	require.NoError(t, err)
+	_, err = api.DeleteWorkspaceMetricsExport(ctx, req)
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
+
+func TestPostWorkspaceMetricsExportInvalid(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	for name, req := range map[string]*apiv1.PostWorkspaceMetricsExportRequest{
+		"no type": {Name: "d", Url: "https://203.0.113.1"},
+		"wandb without entity": {
+			Type:    workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_WANDB,
+			Name:    "d",
+			Url:     "https://203.0.113.1",
+			ApiKey:  "secret",
+			Project: "p",
+		},
+		"loopback": {
+			Type: workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_MLFLOW,
+			Name: "d",
+			Url:  "https://127.0.0.1",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req.WorkspaceId = int32(workspaceID)
+			_, err := api.PostWorkspaceMetricsExport(ctx, req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+		})
+	}
+}
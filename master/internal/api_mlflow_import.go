@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/mlflowimport"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) PostImportFromMLflow(
+	ctx context.Context, req *apiv1.PostImportFromMLflowRequest,
+) (*apiv1.PostImportFromMLflowResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u, err := url.Parse(req.Url); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, status.Error(codes.InvalidArgument, "url must be an http(s) URL")
+	}
+	if !a.m.config.Integrations.MLflow.ServerAllowed(req.Url) {
+		return nil, status.Error(codes.InvalidArgument, "url must be one of the MLflow servers "+
+			"in the integrations.mlflow.allowed_servers master configuration")
+	}
+	if req.Experiment == "" {
+		return nil, status.Error(codes.InvalidArgument,
+			"experiment must be set to the name or ID of an MLflow experiment")
+	}
+	maxRuns := defaultMLflowImportRuns
+	if req.MaxRuns != nil {
+		maxRuns = int(*req.MaxRuns)
+	}
+	if maxRuns < 1 || maxRuns > maxMLflowImportRuns {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"max_runs must be between 1 and %d", maxMLflowImportRuns)
+	}
+
+	client := mlflowimport.NewClient(req.Url, req.ApiKey)
+	mlflowExp, err := client.Experiment(ctx, req.Experiment)
+	if errors.Is(err, mlflowimport.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil {
+		log.WithError(err).Warnf("reading MLflow experiment %s from %s", req.Experiment, req.Url)
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	name := req.Name
+	if name == "" {
+		name = mlflowExp.Name
+	}
+	description := fmt.Sprintf("Imported from MLflow experiment %s (%s) at %s",
+		mlflowExp.Name, mlflowExp.ExperimentID, req.Url)
+	// The experiment is only created once the runs are read, and with the searcher metric picked
+	// from them unless one is given, so a placeholder is enough to check the request.
+	searcherMetric := req.SearcherMetric
+	if searcherMetric == "" {
+		searcherMetric = mlflowimport.SearcherMetric(nil)
+	}
+	config, err := mlflowimport.ExperimentConfig(name, description, searcherMetric)
+	if err != nil {
+		return nil, err
+	}
+	createReq := &apiv1.CreateExperimentRequest{
+		Config:    config,
+		ProjectId: req.ProjectId,
+		Unmanaged: ptrs.Ptr(true),
+	}
+	_, _, _, p, _, err := a.m.parseCreateExperiment(ctx, createReq, curUser)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = expauth.AuthZProvider.Get().CanCreateExperiment(ctx, *curUser, p); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	imp := &mlflowimport.Import{
+		ServerURL:        req.Url,
+		MLflowExperiment: mlflowExp.ExperimentID,
+		CreatedBy:        ptrs.Ptr(int(curUser.ID)),
+	}
+	if err := mlflowimport.CreateImport(ctx, imp); err != nil {
+		return nil, err
+	}
+	go a.m.runMLflowImport(imp.ID, client, mlflowExp, mlflowImportRequest{
+		req:            createReq,
+		user:           *curUser,
+		name:           name,
+		description:    description,
+		searcherMetric: req.SearcherMetric,
+		maxRuns:        maxRuns,
+	})
+	return &apiv1.PostImportFromMLflowResponse{MlflowImport: imp.Proto()}, nil
+}
+
+func (a *apiServer) GetMLflowImport(
+	ctx context.Context, req *apiv1.GetMLflowImportRequest,
+) (*apiv1.GetMLflowImportResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	imp, err := mlflowimport.GetImport(ctx, int(req.ImportId))
+	if err == nil && !curUser.Admin && (imp.CreatedBy == nil || *imp.CreatedBy != int(curUser.ID)) {
+		// Don't reveal the imports of other users.
+		err = db.ErrNotFound
+	}
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("MLflow import", strconv.Itoa(int(req.ImportId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetMLflowImportResponse{MlflowImport: imp.Proto()}, nil
+}
@@ -0,0 +1,49 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/mlflowimport"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+func TestGetMLflowImport(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+
+	imp := &mlflowimport.Import{
+		ServerURL:        "https://mlflow.example.com",
+		MLflowExperiment: "1",
+		CreatedBy:        ptrs.Ptr(int(curUser.ID)),
+	}
+	require.NoError(t, mlflowimport.CreateImport(ctx, imp))
+
+	resp, err := api.GetMLflowImport(ctx, &apiv1.GetMLflowImportRequest{ImportId: int32(imp.ID)})
+	require.NoError(t, err)
+	require.Equal(t, experimentv1.MLflowImportState_MLFLOW_IMPORT_STATE_RUNNING,
+		resp.MlflowImport.State)
+	require.Equal(t, "1", resp.MlflowImport.MlflowExperiment)
+
+	_, err = api.GetMLflowImport(ctx, &apiv1.GetMLflowImportRequest{ImportId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
+
+func TestPostImportFromMLflowErrors(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+
+	for _, req := range []*apiv1.PostImportFromMLflowRequest{
+		{Url: "ftp://mlflow.example.com", Experiment: "1"},
+		{Url: "https://mlflow.example.com", Experiment: "1"},
+	} {
+		_, err := api.PostImportFromMLflow(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/uptrace/bun"
@@ -15,13 +18,20 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 
+	"github.com/determined-ai/determined/master/internal/activity"
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
+	ckpt "github.com/determined-ai/determined/master/internal/checkpoints"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	modelauth "github.com/determined-ai/determined/master/internal/model"
+	"github.com/determined-ai/determined/master/internal/modelgates"
+	"github.com/determined-ai/determined/master/internal/storage"
 	"github.com/determined-ai/determined/master/internal/trials"
+	"github.com/determined-ai/determined/master/pkg/checkpoints"
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
 	"github.com/determined-ai/determined/proto/pkg/modelv1"
@@ -70,6 +80,32 @@ func (a *apiServer) ModelVersionFromID(modelIdentifier string,
 	}
 }
 
+// getModelAndCheckCanDoActions returns a model, by name or ID, if the caller can see it and do the
+// given actions on it.
+func (a *apiServer) getModelAndCheckCanDoActions(ctx context.Context, identifier string,
+	actions ...func(context.Context, model.User, *modelv1.Model, int32) error,
+) (*modelv1.Model, model.User, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, model.User{}, err
+	}
+	m, err := a.ModelFromIdentifier(identifier)
+	if err != nil {
+		return nil, model.User{}, err
+	}
+	notFoundErr := api.NotFoundErrs("model", identifier, true)
+	if err = modelauth.AuthZProvider.Get().CanGetModel(ctx, *curUser, m,
+		m.WorkspaceId); err != nil {
+		return nil, model.User{}, authz.SubIfUnauthorized(err, notFoundErr)
+	}
+	for _, action := range actions {
+		if err = action(ctx, *curUser, m, m.WorkspaceId); err != nil {
+			return nil, model.User{}, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+	return m, *curUser, nil
+}
+
 func (a *apiServer) GetModel(
 	ctx context.Context, req *apiv1.GetModelRequest,
 ) (*apiv1.GetModelResponse, error) {
@@ -291,9 +327,16 @@ func (a *apiServer) PostModel(
 	if err != nil && strings.Contains(err.Error(), db.CodeUniqueViolation) {
 		return nil,
 			status.Errorf(codes.AlreadyExists, "avoid names equal to other models (case-sensitive)")
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "error creating model %q in database", req.Name)
+	}
+
+	if err := activity.RecordModelRegistered(
+		ctx, curUser.ID, workspaceID, int(m.Id), m.Name,
+	); err != nil {
+		log.WithError(err).Warnf("failed to record registration of model %d", m.Id)
 	}
-	return &apiv1.PostModelResponse{Model: m},
-		errors.Wrapf(err, "error creating model %q in database", req.Name)
+	return &apiv1.PostModelResponse{Model: m}, nil
 }
 
 func (a *apiServer) PatchModel(
@@ -660,6 +703,12 @@ func (a *apiServer) PostModelVersion(
 		return nil, errors.Wrap(err, "error marshaling ModelVersion.Metadata")
 	}
 
+	if modelgates.Promotes(nil, req.Labels) {
+		if err = checkModelPromotion(ctx, modelResp, nil, 0); err != nil {
+			return nil, err
+		}
+	}
+
 	reqLabels := strings.Join(req.Labels, ",")
 
 	modelVersion, err := db.InsertModelVersion(
@@ -674,10 +723,109 @@ func (a *apiServer) PostModelVersion(
 		model.UserID(user.User.GetId()),
 	)
 
+	if err != nil {
+		return nil, errors.Wrapf(err, "error adding model version to model %q", req.ModelName)
+	}
 	respModelVersion.ModelVersion = modelVersion
 
-	return respModelVersion, errors.Wrapf(err, "error adding model version to model %q",
-		req.ModelName)
+	// Evaluations of the new version are recorded now, and their experiments created in the
+	// background, so that registering it doesn't wait for them. Evaluations that fail to launch are
+	// recorded as failed, with why.
+	// The version is already registered, so failing to queue its evaluations doesn't fail the
+	// request.
+	v, queued, err := a.m.queueNewModelVersionEvaluations(
+		ctx, int(modelResp.Id), int(modelVersion.Version))
+	if err != nil {
+		log.WithError(err).Errorf("failed to queue evaluations of version %d of model %q",
+			modelVersion.Version, req.ModelName)
+	} else if len(queued) > 0 {
+		go a.m.launchQueuedModelEvaluations(context.WithoutCancel(ctx), v, queued)
+	}
+
+	return respModelVersion, nil
+}
+
+func (a *apiServer) PostModelVersionImport(
+	ctx context.Context, req *apiv1.PostModelVersionImportRequest,
+) (*apiv1.PostModelVersionImportResponse, error) {
+	if req.EndpointUrl != nil && !a.m.config.CheckpointImport.EndpointURLAllowed(*req.EndpointUrl) {
+		return nil, status.Error(codes.InvalidArgument, "endpoint_url must be one of the "+
+			"checkpoint_import.allowed_endpoint_urls in the master configuration")
+	}
+	mdl, _, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName,
+		modelauth.AuthZProvider.Get().CanEditModel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checkpoints are only imported from the checkpoint storage of the model's workspace, with its
+	// credentials, so users can only register checkpoints the workspace's experiments could have
+	// saved, rather than anything the master's own credentials can read.
+	storageConfig, err := a.workspaceCheckpointStorage(ctx, int(mdl.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	id, err := checkpoints.ParseImportPath(req.Path, *storageConfig)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.EndpointUrl != nil {
+		storageConfig.RawS3Config.RawEndpointURL = req.EndpointUrl
+	}
+	existing, err := ckpt.CheckpointByUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "checkpoint %s is already registered", id)
+	}
+
+	resources, checksums, err := readImportedCheckpoint(ctx, storageConfig, id, req.Checksum)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"checkpoint at %s is not accessible: %s", req.Path, err)
+	}
+	storageID, err := storage.AddBackend(ctx, storageConfig)
+	if err != nil {
+		return nil, err
+	}
+	metadata := req.CheckpointMetadata.AsMap()
+	if checksums != nil {
+		metadata["checksums"] = checksums
+	}
+	if err = ckpt.AddImportedCheckpoint(ctx, &model.CheckpointV2{
+		UUID:       id,
+		ReportTime: time.Now().UTC(),
+		State:      model.CompletedState,
+		Resources:  resources,
+		Metadata:   metadata,
+		StorageID:  &storageID,
+	}); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.PostModelVersion(ctx, &apiv1.PostModelVersionRequest{
+		ModelName:      req.ModelName,
+		CheckpointUuid: id.String(),
+		Name:           req.Name,
+		Comment:        req.Comment,
+		Metadata:       req.Metadata,
+		Labels:         req.Labels,
+		Notes:          req.Notes,
+	})
+	if err != nil {
+		// Forget the checkpoint, so that importing it can be retried.
+		if delErr := ckpt.DeleteImportedCheckpoint(ctx, id); delErr != nil {
+			log.WithError(delErr).Errorf("failed to clean up imported checkpoint %s", id)
+		}
+		return nil, err
+	}
+	log.WithField("model", mdl.Name).Infof("imported checkpoint %s from %s as version %d",
+		id, req.Path, resp.ModelVersion.Version)
+	return &apiv1.PostModelVersionImportResponse{
+		ModelVersion:   resp.ModelVersion,
+		CheckpointUuid: id.String(),
+		Checksums:      checksums,
+	}, nil
 }
 
 func (a *apiServer) PatchModelVersion(
@@ -773,6 +921,14 @@ func (a *apiServer) PatchModelVersion(
 		return &apiv1.PatchModelVersionResponse{ModelVersion: currModelVersion}, nil
 	}
 
+	if modelgates.Promotes(currModelVersion.Labels, strings.Split(currLabels, ",")) {
+		versionID := int(currModelVersion.Id)
+		err = checkModelPromotion(ctx, currModel, &versionID, int(currModelVersion.Version))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	finalModelVersion := &modelv1.ModelVersion{}
 	err = a.m.db.QueryProto("update_model_version", finalModelVersion, currModelVersion.Id,
 		parentModel.Id, currModelVersion.Name, currModelVersion.Comment, currModelVersion.Notes,
@@ -852,3 +1008,44 @@ func (a *apiServer) GetTrialMetricsByModelVersion(
 	resp.Metrics = append(resp.Metrics, metrics...)
 	return resp, nil
 }
+
+// readImportedCheckpoint lists the files of a checkpoint in external storage, which checks that
+// the master can read it, and returns their sizes, along with their checksums if asked to.
+func readImportedCheckpoint(
+	ctx context.Context, storageConfig *expconf.CheckpointStorageConfig, id uuid.UUID,
+	checksum bool,
+) (map[string]int64, map[string]string, error) {
+	// Nothing is written to the archive; files are only listed and read.
+	aw, err := archive.NewArchiveWriter(io.Discard, archive.ArchiveTar)
+	if err != nil {
+		return nil, nil, err
+	}
+	downloader, err := checkpoints.NewDownloader(ctx, io.Discard, id.String(), storageConfig, aw)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		_ = downloader.Close()
+	}()
+
+	files, err := downloader.ListFiles(ctx)
+	if err != nil {
+		return nil, nil, err
+	} else if len(files) == 0 {
+		return nil, nil, fmt.Errorf("it has no files")
+	}
+	resources := make(map[string]int64, len(files))
+	for _, f := range files {
+		resources[f.Path] = f.Size
+	}
+	if !checksum {
+		return resources, nil, nil
+	}
+	checksums := make(map[string]string, len(files))
+	for _, f := range files {
+		if checksums[f.Path], err = checkpoints.Checksum(ctx, downloader, f.Path, f.Size); err != nil {
+			return nil, nil, err
+		}
+	}
+	return resources, checksums, nil
+}
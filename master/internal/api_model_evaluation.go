@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	modelauth "github.com/determined-ai/determined/master/internal/model"
+	"github.com/determined-ai/determined/master/internal/modeleval"
+	"github.com/determined-ai/determined/master/internal/modelgates"
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+func (a *apiServer) GetModelEvaluationPolicies(
+	ctx context.Context, req *apiv1.GetModelEvaluationPoliciesRequest,
+) (*apiv1.GetModelEvaluationPoliciesResponse, error) {
+	mdl, _, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := modeleval.Policies(ctx, int(mdl.Id))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetModelEvaluationPoliciesResponse{
+		Policies: []*modelv1.ModelEvaluationPolicy{},
+	}
+	for _, p := range policies {
+		resp.Policies = append(resp.Policies, p.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostModelEvaluationPolicy(
+	ctx context.Context, req *apiv1.PostModelEvaluationPolicyRequest,
+) (*apiv1.PostModelEvaluationPolicyResponse, error) {
+	p := model.ModelEvaluationPolicy{
+		Name:                req.Name,
+		Config:              req.Config,
+		OnRegister:          req.OnRegister == nil || *req.OnRegister,
+		ScheduleInterval:    req.ScheduleInterval,
+		ScheduleLabel:       req.ScheduleLabel,
+		Metric:              req.Metric,
+		SmallerIsBetter:     req.SmallerIsBetter == nil || *req.SmallerIsBetter,
+		RegressionTolerance: req.RegressionTolerance,
+	}
+	if err := modeleval.ValidatePolicy(&p); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(req.ModelDefinition) > 0 {
+		var err error
+		if p.ModelDefinition, err = archive.ToTarGz(filesToArchive(req.ModelDefinition)); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"reading model_definition: %s", err)
+		}
+	}
+
+	mdl, curUser, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName,
+		modelauth.AuthZProvider.Get().CanEditModel)
+	if err != nil {
+		return nil, err
+	}
+
+	// The config is resolved and checked as it would be to create the experiment, to find its
+	// project and catch mistakes now rather than when the model is evaluated. It is checked again
+	// each time it is launched, since the workspace's constraints can change in between.
+	_, _, project, _, err := a.m.parseModelEvaluationExperiment(ctx, req.Config, req.ProjectId,
+		curUser, true)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	p.ModelID = int(mdl.Id)
+	p.ProjectID = int(project.Id)
+	p.OwnerID = curUser.ID
+	err = modeleval.AddPolicy(ctx, &p)
+	if errors.Is(err, db.ErrDuplicateRecord) {
+		return nil, status.Errorf(codes.AlreadyExists,
+			"model %s already has an evaluation policy named %s", mdl.Name, p.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.PostModelEvaluationPolicyResponse{Policy: p.Proto()}, nil
+}
+
+func (a *apiServer) DeleteModelEvaluationPolicy(
+	ctx context.Context, req *apiv1.DeleteModelEvaluationPolicyRequest,
+) (*apiv1.DeleteModelEvaluationPolicyResponse, error) {
+	mdl, curUser, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName,
+		modelauth.AuthZProvider.Get().CanEditModel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleting a policy deletes the promotion gates that use it, which takes the same permission
+	// as overriding them. The gates are deleted first, so their removal is recorded.
+	policyID := int(req.PolicyId)
+	gates, err := modelgates.PolicyGates(ctx, int(mdl.Id), policyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(gates) > 0 {
+		err = modelauth.AuthZProvider.Get().CanOverrideModelPromotionGates(ctx, curUser, mdl,
+			mdl.WorkspaceId)
+		if err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if err = modelgates.DeletePolicyGates(ctx, int(mdl.Id), policyID, curUser.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = modeleval.DeletePolicy(ctx, int(mdl.Id), policyID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("evaluation policy", strconv.Itoa(policyID), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteModelEvaluationPolicyResponse{}, nil
+}
+
+func (a *apiServer) PostModelEvaluationPolicyRun(
+	ctx context.Context, req *apiv1.PostModelEvaluationPolicyRunRequest,
+) (*apiv1.PostModelEvaluationPolicyRunResponse, error) {
+	mdl, curUser, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName,
+		modelauth.AuthZProvider.Get().CanEditModel)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := modeleval.PolicyByID(ctx, int(mdl.Id), int(req.PolicyId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("evaluation policy", strconv.Itoa(int(req.PolicyId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	// The evaluation runs as the caller, who must be able to create its experiment in the policy's
+	// project themselves. That is checked before the evaluation is recorded, so that a caller who
+	// can't doesn't leave a failed evaluation behind.
+	_, _, _, _, err = a.m.parseModelEvaluationExperiment(ctx, p.Config, int32(p.ProjectID),
+		curUser, true)
+	if err != nil {
+		return nil, err
+	}
+	var v *modeleval.Version
+	if req.Version != nil {
+		v, err = modeleval.VersionByNumber(ctx, int(mdl.Id), int(*req.Version))
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, api.NotFoundErrs("model version", strconv.Itoa(int(*req.Version)), true)
+		}
+	} else {
+		v, err = modeleval.LatestVersion(ctx, int(mdl.Id), nil)
+		if err == nil && v == nil {
+			return nil, status.Errorf(codes.NotFound, "model %s has no versions to evaluate",
+				mdl.Name)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	eval, err := a.m.launchModelEvaluation(ctx, p, v, model.ModelEvaluationTriggerManual, &curUser)
+	if eval == nil {
+		return nil, err
+	} else if err != nil {
+		log.WithError(err).Warnf("failed to evaluate version %d of model %s with %s",
+			v.Version, v.ModelName, p.Name)
+	}
+	eval.ModelVersion = v.Version
+	return &apiv1.PostModelEvaluationPolicyRunResponse{Evaluation: eval.Proto()}, nil
+}
+
+func (a *apiServer) GetModelVersionEvaluations(
+	ctx context.Context, req *apiv1.GetModelVersionEvaluationsRequest,
+) (*apiv1.GetModelVersionEvaluationsResponse, error) {
+	mdl, _, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := modeleval.VersionByNumber(ctx, int(mdl.Id), int(req.ModelVersionNum))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("model version", strconv.Itoa(int(req.ModelVersionNum)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	evals, err := modeleval.Evaluations(ctx, v.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetModelVersionEvaluationsResponse{Evaluations: []*modelv1.ModelEvaluation{}}
+	for _, e := range evals {
+		resp.Evaluations = append(resp.Evaluations, e.Proto())
+	}
+	return resp, nil
+}
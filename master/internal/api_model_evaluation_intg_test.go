@@ -0,0 +1,55 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestModelEvaluationPolicies(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	modelName := uuid.NewString()
+	_, err := api.PostModel(ctx, &apiv1.PostModelRequest{Name: modelName})
+	require.NoError(t, err)
+
+	resp, err := api.GetModelEvaluationPolicies(ctx,
+		&apiv1.GetModelEvaluationPoliciesRequest{ModelName: modelName})
+	require.NoError(t, err)
+	require.Empty(t, resp.Policies)
+
+	_, err = api.PostModelEvaluationPolicy(ctx, &apiv1.PostModelEvaluationPolicyRequest{
+		ModelName: modelName,
+		Name:      "nightly",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.DeleteModelEvaluationPolicy(ctx, &apiv1.DeleteModelEvaluationPolicyRequest{
+		ModelName: modelName,
+		PolicyId:  -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.PostModelEvaluationPolicyRun(ctx, &apiv1.PostModelEvaluationPolicyRunRequest{
+		ModelName: modelName,
+		PolicyId:  -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.GetModelVersionEvaluations(ctx, &apiv1.GetModelVersionEvaluationsRequest{
+		ModelName:       modelName,
+		ModelVersionNum: 1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.GetModelEvaluationPolicies(ctx,
+		&apiv1.GetModelEvaluationPoliciesRequest{ModelName: uuid.NewString()})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
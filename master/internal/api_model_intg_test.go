@@ -0,0 +1,41 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestPostModelVersionImport(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	modelName := uuid.NewString()
+	_, err := api.PostModel(ctx, &apiv1.PostModelRequest{Name: modelName})
+	require.NoError(t, err)
+	path := "s3://bucket/" + uuid.NewString()
+
+	_, err = api.PostModelVersionImport(ctx, &apiv1.PostModelVersionImportRequest{
+		ModelName: uuid.NewString(), Path: path,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	// Endpoints must be allowed by the master config.
+	_, err = api.PostModelVersionImport(ctx, &apiv1.PostModelVersionImportRequest{
+		ModelName: modelName, Path: path, EndpointUrl: ptrs.Ptr("http://localhost:9000"),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	// Checkpoints are only imported from the S3 checkpoint storage of the model's workspace.
+	_, err = api.PostModelVersionImport(ctx, &apiv1.PostModelVersionImportRequest{
+		ModelName: modelName, Path: path,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
@@ -0,0 +1,260 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	modelauth "github.com/determined-ai/determined/master/internal/model"
+	"github.com/determined-ai/determined/master/internal/modeleval"
+	"github.com/determined-ai/determined/master/internal/modelgates"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+// checkModelPromotion returns an error if a model version, or one that is being registered if
+// versionID is nil, doesn't pass its model's gates to be promoted to production.
+func checkModelPromotion(
+	ctx context.Context, mdl *modelv1.Model, versionID *int, version int,
+) error {
+	results, err := modelgates.CheckVersion(ctx, int(mdl.Id), versionID)
+	if err != nil {
+		return err
+	}
+	if unmet := modelgates.Unmet(results); len(unmet) > 0 {
+		return status.Error(codes.FailedPrecondition, modelgates.UnmetError{
+			ModelName: mdl.Name,
+			Version:   version,
+			Unmet:     unmet,
+		}.Error())
+	}
+	return nil
+}
+
+func (a *apiServer) GetModelPromotionGates(
+	ctx context.Context, req *apiv1.GetModelPromotionGatesRequest,
+) (*apiv1.GetModelPromotionGatesResponse, error) {
+	mdl, _, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	gates, err := modelgates.Gates(ctx, int(mdl.Id))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetModelPromotionGatesResponse{Gates: []*modelv1.ModelPromotionGate{}}
+	for _, g := range gates {
+		resp.Gates = append(resp.Gates, g.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostModelPromotionGate(
+	ctx context.Context, req *apiv1.PostModelPromotionGateRequest,
+) (*apiv1.PostModelPromotionGateResponse, error) {
+	if req.Threshold == nil {
+		return nil, status.Error(codes.InvalidArgument, "threshold must be set")
+	}
+	g := model.ModelPromotionGate{
+		PolicyID:   int(req.PolicyId),
+		Metric:     req.Metric,
+		Comparison: req.Comparison,
+		Threshold:  *req.Threshold,
+	}
+	if err := modelgates.ValidateGate(&g); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	mdl, curUser, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName,
+		modelauth.AuthZProvider.Get().CanEditModel)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = modeleval.PolicyByID(ctx, int(mdl.Id), g.PolicyID); errors.Is(err, db.ErrNotFound) {
+		return nil, status.Errorf(codes.InvalidArgument, "model %s has no evaluation policy %d",
+			mdl.Name, g.PolicyID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	g.ModelID = int(mdl.Id)
+	g.CreatedBy = curUser.ID
+	if err = modelgates.AddGate(ctx, &g); err != nil {
+		return nil, err
+	}
+	return &apiv1.PostModelPromotionGateResponse{Gate: g.Proto()}, nil
+}
+
+func (a *apiServer) DeleteModelPromotionGate(
+	ctx context.Context, req *apiv1.DeleteModelPromotionGateRequest,
+) (*apiv1.DeleteModelPromotionGateResponse, error) {
+	// Removing a gate lets versions that don't pass it be promoted, so it takes the same
+	// permission as overriding gates.
+	mdl, curUser, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName,
+		modelauth.AuthZProvider.Get().CanEditModel,
+		modelauth.AuthZProvider.Get().CanOverrideModelPromotionGates)
+	if err != nil {
+		return nil, err
+	}
+
+	err = modelgates.DeleteGate(ctx, int(mdl.Id), int(req.GateId), curUser.ID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("promotion gate", strconv.Itoa(int(req.GateId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteModelPromotionGateResponse{}, nil
+}
+
+func (a *apiServer) GetModelPromotionGateChanges(
+	ctx context.Context, req *apiv1.GetModelPromotionGateChangesRequest,
+) (*apiv1.GetModelPromotionGateChangesResponse, error) {
+	mdl, _, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := modelgates.GateChanges(ctx, int(mdl.Id))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetModelPromotionGateChangesResponse{
+		Changes: []*modelv1.ModelPromotionGateChange{},
+	}
+	for _, c := range changes {
+		resp.Changes = append(resp.Changes, c.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetModelVersionPromotionGates(
+	ctx context.Context, req *apiv1.GetModelVersionPromotionGatesRequest,
+) (*apiv1.GetModelVersionPromotionGatesResponse, error) {
+	mdl, _, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := modeleval.VersionByNumber(ctx, int(mdl.Id), int(req.ModelVersionNum))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("model version", strconv.Itoa(int(req.ModelVersionNum)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	results, err := modelgates.CheckVersion(ctx, int(mdl.Id), &v.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetModelVersionPromotionGatesResponse{
+		Passed: len(modelgates.Unmet(results)) == 0,
+		Gates:  []*modelv1.ModelPromotionGateResult{},
+	}
+	for _, r := range results {
+		resp.Gates = append(resp.Gates, r.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostModelVersionPromote(
+	ctx context.Context, req *apiv1.PostModelVersionPromoteRequest,
+) (*apiv1.PostModelVersionPromoteResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if req.Override && reason == "" {
+		return nil, status.Error(codes.InvalidArgument,
+			"a reason is required to override promotion gates")
+	}
+
+	mdl, curUser, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName,
+		modelauth.AuthZProvider.Get().CanEditModel)
+	if err != nil {
+		return nil, err
+	}
+	mv, err := a.ModelVersionFromID(strconv.Itoa(int(mdl.Id)), req.ModelVersionNum)
+	if err != nil {
+		return nil, err
+	}
+	labels := append(slices.Clone(mv.Labels), modelgates.ProductionLabel)
+	if !modelgates.Promotes(mv.Labels, labels) {
+		return &apiv1.PostModelVersionPromoteResponse{ModelVersion: mv}, nil
+	}
+
+	versionID := int(mv.Id)
+	results, err := modelgates.CheckVersion(ctx, int(mdl.Id), &versionID)
+	if err != nil {
+		return nil, err
+	}
+	var override *model.ModelPromotionOverride
+	if unmet := modelgates.Unmet(results); len(unmet) > 0 {
+		if !req.Override {
+			return nil, status.Error(codes.FailedPrecondition, modelgates.UnmetError{
+				ModelName: mdl.Name,
+				Version:   int(req.ModelVersionNum),
+				Unmet:     unmet,
+			}.Error())
+		}
+		err = modelauth.AuthZProvider.Get().CanOverrideModelPromotionGates(ctx, curUser, mdl,
+			mdl.WorkspaceId)
+		if err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if override, err = modelgates.RecordOverride(ctx, versionID, curUser.ID, reason,
+			unmet); err != nil {
+			return nil, err
+		}
+		log.WithFields(log.Fields{
+			"model":    mdl.Name,
+			"version":  req.ModelVersionNum,
+			"user":     curUser.Username,
+			"override": override.ID,
+		}).Warnf("model version promoted to %s without passing %d gates: %s",
+			modelgates.ProductionLabel, len(unmet), reason)
+	}
+
+	meta, err := protojson.Marshal(mv.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	promoted := &modelv1.ModelVersion{}
+	err = a.m.db.QueryProto("update_model_version", promoted, mv.Id, mdl.Id, mv.Name, mv.Comment,
+		mv.Notes, meta, strings.Join(labels, ","))
+	if err != nil {
+		return nil, fmt.Errorf("promoting version %d of model %s: %w",
+			req.ModelVersionNum, mdl.Name, err)
+	}
+	resp := &apiv1.PostModelVersionPromoteResponse{ModelVersion: promoted}
+	if override != nil {
+		resp.Override = override.Proto()
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetModelPromotionOverrides(
+	ctx context.Context, req *apiv1.GetModelPromotionOverridesRequest,
+) (*apiv1.GetModelPromotionOverridesResponse, error) {
+	mdl, _, err := a.getModelAndCheckCanDoActions(ctx, req.ModelName)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := modelgates.Overrides(ctx, int(mdl.Id))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetModelPromotionOverridesResponse{
+		Overrides: []*modelv1.ModelPromotionOverride{},
+	}
+	for _, o := range overrides {
+		resp.Overrides = append(resp.Overrides, o.Proto())
+	}
+	return resp, nil
+}
@@ -0,0 +1,74 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestModelPromotionGates(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	modelName := uuid.NewString()
+	_, err := api.PostModel(ctx, &apiv1.PostModelRequest{Name: modelName})
+	require.NoError(t, err)
+
+	gates, err := api.GetModelPromotionGates(ctx,
+		&apiv1.GetModelPromotionGatesRequest{ModelName: modelName})
+	require.NoError(t, err)
+	require.Empty(t, gates.Gates)
+
+	_, err = api.PostModelPromotionGate(ctx, &apiv1.PostModelPromotionGateRequest{
+		ModelName:  modelName,
+		PolicyId:   1,
+		Metric:     "accuracy",
+		Comparison: ">=",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.PostModelPromotionGate(ctx, &apiv1.PostModelPromotionGateRequest{
+		ModelName:  modelName,
+		PolicyId:   -1,
+		Metric:     "accuracy",
+		Comparison: ">=",
+		Threshold:  ptrs.Ptr(0.92),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.DeleteModelPromotionGate(ctx, &apiv1.DeleteModelPromotionGateRequest{
+		ModelName: modelName,
+		GateId:    -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	changes, err := api.GetModelPromotionGateChanges(ctx,
+		&apiv1.GetModelPromotionGateChangesRequest{ModelName: modelName})
+	require.NoError(t, err)
+	require.Empty(t, changes.Changes)
+
+	overrides, err := api.GetModelPromotionOverrides(ctx,
+		&apiv1.GetModelPromotionOverridesRequest{ModelName: modelName})
+	require.NoError(t, err)
+	require.Empty(t, overrides.Overrides)
+
+	_, err = api.GetModelVersionPromotionGates(ctx, &apiv1.GetModelVersionPromotionGatesRequest{
+		ModelName:       modelName,
+		ModelVersionNum: 1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.PostModelVersionPromote(ctx, &apiv1.PostModelVersionPromoteRequest{
+		ModelName:       modelName,
+		ModelVersionNum: 1,
+		Override:        true,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
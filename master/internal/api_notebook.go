@@ -21,6 +21,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/command"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/notebooksync"
 	"github.com/determined-ai/determined/master/internal/proxy"
 	"github.com/determined-ai/determined/master/internal/rbac/audit"
 	"github.com/determined-ai/determined/master/internal/task/idle"
@@ -357,17 +358,66 @@ func (a *apiServer) LaunchNotebook(
 		),
 	}
 
-	// Launch a Notebook.
-	genericCmd, err := command.DefaultCmdService.LaunchNotebookCommand(
-		launchReq,
-		user)
+	workspaceID := int(launchReq.Spec.Metadata.WorkspaceID)
+	syncStorage, err := a.notebookSyncStorage(ctx, workspaceID)
 	if err != nil {
 		return nil, err
 	}
+	if syncStorage != nil {
+		storageBytes, mErr := json.Marshal(syncStorage)
+		if mErr != nil {
+			return nil, status.Errorf(codes.Internal, "cannot marshal notebook sync storage: %s", mErr)
+		}
+		maps.Copy(launchReq.Spec.Base.ExtraEnvVars,
+			notebooksync.EnvVars(notebooksync.StoragePrefix(workspaceID, user.ID)))
+		launchReq.Spec.AdditionalFiles = append(launchReq.Spec.AdditionalFiles,
+			launchReq.Spec.Base.AgentUserGroup.OwnedArchiveItem(
+				notebooksync.StorageConfigPath,
+				storageBytes,
+				0o600,
+				tar.TypeReg,
+			),
+		)
+	}
+
+	// Launch a Notebook.
+	var genericCmd *command.Command
+	launch := func() (model.TaskID, error) {
+		cmd, err := command.DefaultCmdService.LaunchNotebookCommand(launchReq, user)
+		if err != nil {
+			return "", err
+		}
+		genericCmd = cmd
+		return model.TaskID(cmd.ToV1Notebook().Id), nil
+	}
+	if syncStorage != nil {
+		err = notebooksync.Launch(ctx, workspaceID, user.ID, launch)
+	} else {
+		_, err = launch()
+	}
+	if errors.Is(err, notebooksync.ErrNotebookRunning) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	notebook := genericCmd.ToV1Notebook()
 
 	return &apiv1.LaunchNotebookResponse{
-		Notebook: genericCmd.ToV1Notebook(),
+		Notebook: notebook,
 		Config:   protoutils.ToStruct(launchReq.Spec.Config),
 		Warnings: pkgCommand.LaunchWarningToProto(launchWarnings),
 	}, nil
 }
+
+// notebookSyncStorage returns the checkpoint storage that notebooks in the workspace sync their
+// working directory to, or nil if the workspace does not have notebook sync turned on.
+func (a *apiServer) notebookSyncStorage(
+	ctx context.Context, workspaceID int,
+) (*expconf.CheckpointStorageConfig, error) {
+	enabled, err := notebooksync.Enabled(ctx, workspaceID)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	return a.workspaceCheckpointStorage(ctx, workspaceID)
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/notebooksync"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func (a *apiServer) GetWorkspaceNotebookSync(
+	ctx context.Context, req *apiv1.GetWorkspaceNotebookSyncRequest,
+) (*apiv1.GetWorkspaceNotebookSyncResponse, error) {
+	_, curUser, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := notebooksync.Enabled(ctx, int(req.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	records, err := notebooksync.ByWorkspace(ctx, int(req.WorkspaceId), &curUser.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetWorkspaceNotebookSyncResponse{
+		Enabled: enabled,
+		Records: []*workspacev1.NotebookWorkDirSync{},
+	}
+	for _, r := range records {
+		resp.Records = append(resp.Records, r.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PutWorkspaceNotebookSync(
+	ctx context.Context, req *apiv1.PutWorkspaceNotebookSyncRequest,
+) (*apiv1.PutWorkspaceNotebookSyncResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := notebooksync.SetEnabled(ctx, int(req.WorkspaceId), req.Enabled); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutWorkspaceNotebookSyncResponse{}, nil
+}
+
+func (a *apiServer) PostTaskNotebookSync(
+	ctx context.Context, req *apiv1.PostTaskNotebookSyncRequest,
+) (*apiv1.PostTaskNotebookSyncResponse, error) {
+	// Notebooks report their syncs with the session of the user who launched them, which is the
+	// only user whose sync record they can update.
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = notebooksync.RecordSync(ctx, model.TaskID(req.TaskId), curUser.ID, time.Now().UTC())
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("notebook sync record", req.TaskId, true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.PostTaskNotebookSyncResponse{}, nil
+}
@@ -0,0 +1,43 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestWorkspaceNotebookSync(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	resp, err := api.GetWorkspaceNotebookSync(ctx, &apiv1.GetWorkspaceNotebookSyncRequest{
+		WorkspaceId: int32(workspaceID),
+	})
+	require.NoError(t, err)
+	require.False(t, resp.Enabled)
+	require.Empty(t, resp.Records)
+
+	_, err = api.PutWorkspaceNotebookSync(ctx, &apiv1.PutWorkspaceNotebookSyncRequest{
+		WorkspaceId: int32(workspaceID),
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+	resp, err = api.GetWorkspaceNotebookSync(ctx, &apiv1.GetWorkspaceNotebookSyncRequest{
+		WorkspaceId: int32(workspaceID),
+	})
+	require.NoError(t, err)
+	require.True(t, resp.Enabled)
+
+	_, err = api.PostTaskNotebookSync(ctx, &apiv1.PostTaskNotebookSyncRequest{
+		TaskId: uuid.NewString(),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
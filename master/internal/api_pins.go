@@ -0,0 +1,201 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/pins"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+// pinsOrderErr converts an error reordering pins to a gRPC error.
+func pinsOrderErr(err error, kind string) error {
+	var orderErr pins.InvalidOrderError
+	if errors.As(err, &orderErr) {
+		return status.Error(codes.InvalidArgument, kind+" "+orderErr.Error())
+	}
+	return err
+}
+
+func int32sToInts(ids []int32) []int {
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, int(id))
+	}
+	return out
+}
+
+func (a *apiServer) GetPins(
+	ctx context.Context, req *apiv1.GetPinsRequest,
+) (*apiv1.GetPinsResponse, error) {
+	user, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := pins.Workspaces(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := pins.Projects(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pins stay when a user loses access to what they pinned, so they're hidden rather than
+	// deleted, in case access is given back.
+	var ids []int32
+	for _, p := range append(workspaces, projects...) {
+		ids = append(ids, int32(p.WorkspaceID))
+	}
+	visible, err := workspace.AuthZProvider.Get().FilterWorkspaceIDs(ctx, *user, ids)
+	if err != nil {
+		return nil, err
+	}
+	visibleIDs := make(map[int]bool, len(visible))
+	for _, id := range visible {
+		visibleIDs[int(id)] = true
+	}
+	filter := func(ps []pins.Pin) []*workspacev1.Pin {
+		out := []*workspacev1.Pin{}
+		for _, p := range ps {
+			if visibleIDs[p.WorkspaceID] {
+				out = append(out, p.Proto())
+			}
+		}
+		return out
+	}
+	return &apiv1.GetPinsResponse{Workspaces: filter(workspaces), Projects: filter(projects)}, nil
+}
+
+func (a *apiServer) PutProjectPin(
+	ctx context.Context, req *apiv1.PutProjectPinRequest,
+) (*apiv1.PutProjectPinResponse, error) {
+	_, user, err := a.getProjectAndCheckCanDoActions(ctx, req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	if err := pins.PinProject(ctx, user.ID, int(req.ProjectId)); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutProjectPinResponse{}, nil
+}
+
+func (a *apiServer) DeleteProjectPin(
+	ctx context.Context, req *apiv1.DeleteProjectPinRequest,
+) (*apiv1.DeleteProjectPinResponse, error) {
+	// Anyone may unpin a project they can no longer see.
+	user, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := pins.UnpinProject(ctx, user.ID, int(req.ProjectId)); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteProjectPinResponse{}, nil
+}
+
+func (a *apiServer) PutWorkspacePinsOrder(
+	ctx context.Context, req *apiv1.PutWorkspacePinsOrderRequest,
+) (*apiv1.PutWorkspacePinsOrderResponse, error) {
+	user, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = pins.ReorderWorkspaces(ctx, user.ID, int32sToInts(req.WorkspaceIds))
+	if err != nil {
+		return nil, pinsOrderErr(err, "workspace")
+	}
+	return &apiv1.PutWorkspacePinsOrderResponse{}, nil
+}
+
+func (a *apiServer) PutProjectPinsOrder(
+	ctx context.Context, req *apiv1.PutProjectPinsOrderRequest,
+) (*apiv1.PutProjectPinsOrderResponse, error) {
+	user, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = pins.ReorderProjects(ctx, user.ID, int32sToInts(req.ProjectIds))
+	if err != nil {
+		return nil, pinsOrderErr(err, "project")
+	}
+	return &apiv1.PutProjectPinsOrderResponse{}, nil
+}
+
+func (a *apiServer) GetTrialPins(
+	ctx context.Context, req *apiv1.GetTrialPinsRequest,
+) (*apiv1.GetTrialPinsResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+		return nil, err
+	}
+	trialPins, err := pins.Trials(ctx, int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetTrialPinsResponse{Pins: make([]*trialv1.TrialPin, 0, len(trialPins))}
+	for _, p := range trialPins {
+		resp.Pins = append(resp.Pins, p.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PutTrialPin(
+	ctx context.Context, req *apiv1.PutTrialPinRequest,
+) (*apiv1.PutTrialPinResponse, error) {
+	_, user, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanEditExperimentsMetadata)
+	if err != nil {
+		return nil, err
+	}
+	if eID, _, err := a.m.db.TrialExperimentAndRequestID(int(req.TrialId)); err != nil ||
+		eID != int(req.ExperimentId) {
+		return nil, api.NotFoundErrs("trial", strconv.Itoa(int(req.TrialId)), true)
+	}
+	err = pins.PinTrial(ctx, int(req.ExperimentId), int(req.TrialId), user.ID)
+	if errors.Is(err, db.ErrInvalidInput) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.PutTrialPinResponse{}, nil
+}
+
+func (a *apiServer) DeleteTrialPin(
+	ctx context.Context, req *apiv1.DeleteTrialPinRequest,
+) (*apiv1.DeleteTrialPinResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanEditExperimentsMetadata); err != nil {
+		return nil, err
+	}
+	if err := pins.UnpinTrial(ctx, int(req.ExperimentId), int(req.TrialId)); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteTrialPinResponse{}, nil
+}
+
+func (a *apiServer) PutTrialPinsOrder(
+	ctx context.Context, req *apiv1.PutTrialPinsOrderRequest,
+) (*apiv1.PutTrialPinsOrderResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanEditExperimentsMetadata); err != nil {
+		return nil, err
+	}
+	err := pins.ReorderTrials(ctx, int(req.ExperimentId), int32sToInts(req.TrialIds))
+	if err != nil {
+		return nil, pinsOrderErr(err, "trial")
+	}
+	return &apiv1.PutTrialPinsOrderResponse{}, nil
+}
@@ -0,0 +1,105 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestProjectPins(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+
+	_, err := api.PutProjectPin(ctx, &apiv1.PutProjectPinRequest{ProjectId: int32(projectID)})
+	require.NoError(t, err)
+	resp, err := api.GetPins(ctx, &apiv1.GetPinsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Projects, 1)
+	require.Equal(t, int32(projectID), resp.Projects[0].Id)
+
+	_, err = api.PutProjectPinsOrder(ctx, &apiv1.PutProjectPinsOrderRequest{
+		ProjectIds: []int32{int32(projectID), int32(projectID)},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.DeleteProjectPin(ctx, &apiv1.DeleteProjectPinRequest{ProjectId: int32(projectID)})
+	require.NoError(t, err)
+	resp, err = api.GetPins(ctx, &apiv1.GetPinsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.Projects)
+
+	_, err = api.PutProjectPin(ctx, &apiv1.PutProjectPinRequest{ProjectId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
+
+func TestTrialPins(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := createTestExp(t, api, curUser)
+	var trialIDs []int32
+	for i := 0; i < 3; i++ {
+		trialIDs = append(trialIDs, int32(db.RequireMockTrialID(t, api.m.db, exp)))
+	}
+
+	for _, id := range []int32{trialIDs[2], trialIDs[1]} {
+		_, err := api.PutTrialPin(ctx, &apiv1.PutTrialPinRequest{
+			ExperimentId: int32(exp.ID), TrialId: id,
+		})
+		require.NoError(t, err)
+	}
+
+	// Pinned trials are listed first, in the order they are pinned in, whatever the sort order.
+	for _, orderBy := range []apiv1.OrderBy{apiv1.OrderBy_ORDER_BY_ASC, apiv1.OrderBy_ORDER_BY_DESC} {
+		resp, err := api.GetExperimentTrials(ctx, &apiv1.GetExperimentTrialsRequest{
+			ExperimentId: int32(exp.ID), OrderBy: orderBy,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Trials, 3)
+		require.Equal(t, trialIDs[2], resp.Trials[0].Id)
+		require.True(t, resp.Trials[0].Pinned)
+		require.Equal(t, int32(1), resp.Trials[0].GetPinPosition())
+		require.Equal(t, trialIDs[1], resp.Trials[1].Id)
+		require.Equal(t, int32(2), resp.Trials[1].GetPinPosition())
+		require.Equal(t, trialIDs[0], resp.Trials[2].Id)
+		require.False(t, resp.Trials[2].Pinned)
+		require.Nil(t, resp.Trials[2].PinPosition)
+	}
+
+	_, err := api.PutTrialPinsOrder(ctx, &apiv1.PutTrialPinsOrderRequest{
+		ExperimentId: int32(exp.ID), TrialIds: []int32{trialIDs[1]},
+	})
+	require.NoError(t, err)
+	pins, err := api.GetTrialPins(ctx, &apiv1.GetTrialPinsRequest{ExperimentId: int32(exp.ID)})
+	require.NoError(t, err)
+	require.Len(t, pins.Pins, 2)
+	require.Equal(t, trialIDs[1], pins.Pins[0].TrialId)
+	require.Equal(t, curUser.Username, pins.Pins[0].GetPinnedBy())
+	require.Equal(t, trialIDs[2], pins.Pins[1].TrialId)
+
+	_, err = api.PutTrialPinsOrder(ctx, &apiv1.PutTrialPinsOrderRequest{
+		ExperimentId: int32(exp.ID), TrialIds: []int32{trialIDs[0]},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	other := createTestExp(t, api, curUser)
+	_, err = api.PutTrialPin(ctx, &apiv1.PutTrialPinRequest{
+		ExperimentId: int32(other.ID), TrialId: trialIDs[0],
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.DeleteTrialPin(ctx, &apiv1.DeleteTrialPinRequest{
+		ExperimentId: int32(exp.ID), TrialId: trialIDs[1],
+	})
+	require.NoError(t, err)
+	pins, err = api.GetTrialPins(ctx, &apiv1.GetTrialPinsRequest{ExperimentId: int32(exp.ID)})
+	require.NoError(t, err)
+	require.Len(t, pins.Pins, 1)
+	require.Equal(t, trialIDs[2], pins.Pins[0].TrialId)
+}
@@ -665,7 +665,13 @@ func (a *apiServer) GetProject(
 	}
 
 	p, err := a.GetProjectByID(ctx, req.Id, *curUser)
-	return &apiv1.GetProjectResponse{Project: p}, err
+	if err != nil {
+		return nil, err
+	}
+	if err := setProjectStorageUsage(ctx, []*projectv1.Project{p}); err != nil {
+		return nil, err
+	}
+	return &apiv1.GetProjectResponse{Project: p}, nil
 }
 
 func (a *apiServer) GetProjectColumns(
@@ -1097,20 +1103,15 @@ func (a *apiServer) GetProjectsByUserActivity(
 		'WORKSPACE_STATE_' || p.state AS state,
 		p.error_message,
 		(SELECT COUNT(*) FROM runs as r WHERE r.project_id=p.id) as num_runs,
-		COUNT(*) FILTER (WHERE e.project_id = p.id) AS num_experiments,
-		COUNT(*) FILTER (WHERE e.project_id = p.id AND e.state = 'ACTIVE') AS num_active_experiments,
-		MAX(e.start_time) FILTER (WHERE e.project_id = p.id) AS last_experiment_started_at
+		COALESCE(ps.num_experiments, 0) AS num_experiments,
+		COALESCE(ps.num_active_experiments, 0) AS num_active_experiments,
+		ps.last_experiment_started_at
 	FROM
 		projects AS p
 		INNER JOIN activity AS a ON p.id = a.entity_id AND a.user_id = ?
 		LEFT JOIN users AS u ON u.id = p.user_id
 		LEFT JOIN workspaces AS w ON w.id = p.workspace_id
-		LEFT JOIN experiments AS e ON e.project_id = p.id
-	GROUP BY
-		p.id,
-		u.username,
-		w.name,
-		a.activity_time
+		LEFT JOIN project_experiment_summaries AS ps ON ps.project_id = p.id
 	ORDER BY
 		a.activity_time DESC NULLS LAST
 	LIMIT ?;
@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	apiPkg "github.com/determined-ai/determined/master/internal/api"
@@ -312,6 +313,32 @@ func TestAuthZRoutesGetProjectThenAction(t *testing.T) {
 			})
 			return err
 		}},
+		{"CanSetProjectMetadataSchemas", func(id int) error {
+			_, err := api.PutProjectExperimentMetadataSchema(ctx,
+				&apiv1.PutProjectExperimentMetadataSchemaRequest{
+					ProjectId: int32(id),
+					Schema:    &structpb.Struct{},
+				})
+			return err
+		}},
+		{"CanSetProjectMetadataSchemas", func(id int) error {
+			_, err := api.DeleteProjectExperimentMetadataSchema(ctx,
+				&apiv1.DeleteProjectExperimentMetadataSchemaRequest{ProjectId: int32(id)})
+			return err
+		}},
+		{"CanSetProjectMetadataSchemas", func(id int) error {
+			_, err := api.PutProjectCheckpointMetadataSchema(ctx,
+				&apiv1.PutProjectCheckpointMetadataSchemaRequest{
+					ProjectId: int32(id),
+					Schema:    &structpb.Struct{},
+				})
+			return err
+		}},
+		{"CanSetProjectMetadataSchemas", func(id int) error {
+			_, err := api.DeleteProjectCheckpointMetadataSchema(ctx,
+				&apiv1.DeleteProjectCheckpointMetadataSchemaRequest{ProjectId: int32(id)})
+			return err
+		}},
 		{"CanDeleteProject", func(id int) error {
 			_, err := api.DeleteProject(ctx, &apiv1.DeleteProjectRequest{
 				Id: int32(id),
@@ -389,6 +416,56 @@ func TestGetProjectByActivity(t *testing.T) {
 	require.Len(t, resp.Projects, 1)
 }
 
+func TestProjectExperimentSummaries(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+	_, otherProjectID := createProjectAndWorkspace(ctx, t, api)
+
+	type summary struct {
+		NumExperiments          int        `bun:"num_experiments"`
+		NumActiveExperiments    int        `bun:"num_active_experiments"`
+		LastExperimentStartedAt *time.Time `bun:"last_experiment_started_at"`
+	}
+	requireSummary := func(projectID, experiments, active int, started time.Time) {
+		var s summary
+		require.NoError(t, db.Bun().NewSelect().
+			Table("project_experiment_summaries").
+			Column("num_experiments", "num_active_experiments", "last_experiment_started_at").
+			Where("project_id = ?", projectID).
+			Scan(ctx, &s))
+		require.Equal(t, experiments, s.NumExperiments)
+		require.Equal(t, active, s.NumActiveExperiments)
+		require.NotNil(t, s.LastExperimentStartedAt)
+		require.WithinDuration(t, started, *s.LastExperimentStartedAt, time.Millisecond)
+	}
+
+	exp1 := createTestExpWithProjectID(t, api, curUser, projectID)
+	exp2 := createTestExpWithProjectID(t, api, curUser, projectID)
+	requireSummary(projectID, 2, 0, exp2.StartTime)
+
+	_, err := db.Bun().NewUpdate().Table("experiments").
+		Set("state = ?", model.ActiveState).
+		Where("id = ?", exp1.ID).
+		Exec(ctx)
+	require.NoError(t, err)
+	requireSummary(projectID, 2, 1, exp2.StartTime)
+
+	_, err = db.Bun().NewUpdate().Table("experiments").
+		Set("project_id = ?", otherProjectID).
+		Where("id = ?", exp2.ID).
+		Exec(ctx)
+	require.NoError(t, err)
+	requireSummary(projectID, 1, 1, exp1.StartTime)
+	requireSummary(otherProjectID, 1, 0, exp2.StartTime)
+
+	_, err = db.Bun().NewUpdate().Table("experiments").
+		Set("state = ?", model.CompletedState).
+		Where("id = ?", exp1.ID).
+		Exec(ctx)
+	require.NoError(t, err)
+	requireSummary(projectID, 1, 0, exp1.StartTime)
+}
+
 func TestGetProjectColumnsRuns(t *testing.T) {
 	api, curUser, ctx := setupAPITest(t, nil)
 
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/raycluster"
+	"github.com/determined-ai/determined/master/internal/task/idle"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+func (a *apiServer) GetRayClusters(
+	ctx context.Context, req *apiv1.GetRayClustersRequest,
+) (*apiv1.GetRayClustersResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.GetRayClustersResponse{Clusters: []*taskv1.RayCluster{}}
+	for _, cluster := range raycluster.List() {
+		err := command.AuthZProvider.Get().CanGetNSC(
+			ctx, *curUser, model.AccessScopeID(cluster.WorkspaceID))
+		if authz.IsPermissionDenied(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		resp.Clusters = append(resp.Clusters, cluster.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetRayCluster(
+	ctx context.Context, req *apiv1.GetRayClusterRequest,
+) (*apiv1.GetRayClusterResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notFoundErr := api.NotFoundErrs("ray cluster", req.TaskId, true)
+	cluster, err := raycluster.Get(model.TaskID(req.TaskId))
+	if errors.Is(err, raycluster.ErrNotFound) {
+		return nil, notFoundErr
+	}
+	if err := command.AuthZProvider.Get().CanGetNSC(
+		ctx, *curUser, model.AccessScopeID(cluster.WorkspaceID),
+	); err != nil {
+		return nil, authz.SubIfUnauthorized(err, notFoundErr)
+	}
+	return &apiv1.GetRayClusterResponse{Cluster: cluster.Proto()}, nil
+}
+
+func (a *apiServer) PostRayClusterHealth(
+	ctx context.Context, req *apiv1.PostRayClusterHealthRequest,
+) (*apiv1.PostRayClusterHealthResponse, error) {
+	if req.ExpectedNodes < 0 || req.AliveNodes < 0 {
+		return nil, status.Error(codes.InvalidArgument, "node counts must be non-negative")
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	taskID := model.TaskID(req.TaskId)
+	notFoundErr := api.NotFoundErrs("ray cluster", req.TaskId, true)
+	cluster, err := raycluster.Get(taskID)
+	if errors.Is(err, raycluster.ErrNotFound) {
+		return nil, notFoundErr
+	}
+	// Like reporting a notebook as idle, reporting a cluster's health can get it killed.
+	if err := command.AuthZProvider.Get().CanTerminateNSC(
+		ctx, *curUser, model.AccessScopeID(cluster.WorkspaceID),
+	); err != nil {
+		return nil, authz.SubIfUnauthorized(err, notFoundErr)
+	}
+
+	health := raycluster.Health{
+		HeadAddress:   req.HeadAddress,
+		ExpectedNodes: int(req.ExpectedNodes),
+		AliveNodes:    int(req.AliveNodes),
+		Idle:          req.Idle,
+		ReportTime:    time.Now().UTC(),
+	}
+	if cluster, err = raycluster.ReportHealth(taskID, health); errors.Is(err, raycluster.ErrNotFound) {
+		return nil, notFoundErr
+	}
+	if !health.Idle {
+		idle.RecordActivity(string(taskID))
+	}
+	return &apiv1.PostRayClusterHealthResponse{Cluster: cluster.Proto()}, nil
+}
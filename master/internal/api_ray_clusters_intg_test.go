@@ -0,0 +1,47 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/raycluster"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestRayClusters(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	raycluster.Register(raycluster.Cluster{TaskID: "ray-cluster-intg", WorkspaceID: 1})
+	defer raycluster.Unregister("ray-cluster-intg")
+
+	listResp, err := api.GetRayClusters(ctx, &apiv1.GetRayClustersRequest{})
+	require.NoError(t, err)
+	require.NotEmpty(t, listResp.Clusters)
+
+	healthResp, err := api.PostRayClusterHealth(ctx, &apiv1.PostRayClusterHealthRequest{
+		TaskId:        "ray-cluster-intg",
+		HeadAddress:   "10.0.0.1:6379",
+		ExpectedNodes: 2,
+		AliveNodes:    2,
+	})
+	require.NoError(t, err)
+	require.True(t, healthResp.Cluster.Healthy)
+
+	getResp, err := api.GetRayCluster(ctx, &apiv1.GetRayClusterRequest{TaskId: "ray-cluster-intg"})
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1:6379", getResp.Cluster.Health.HeadAddress)
+
+	_, err = api.PostRayClusterHealth(ctx, &apiv1.PostRayClusterHealthRequest{
+		TaskId:        "ray-cluster-intg",
+		ExpectedNodes: -1,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.GetRayCluster(ctx, &apiv1.GetRayClusterRequest{TaskId: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/reservation"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
+)
+
+func (a *apiServer) GetResourcePoolReservations(
+	ctx context.Context, req *apiv1.GetResourcePoolReservationsRequest,
+) (*apiv1.GetResourcePoolReservationsResponse, error) {
+	if err := a.m.rm.ValidateResourcePool(rm.ResourcePoolName(req.ResourcePoolName)); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	since := time.Now()
+	if req.IncludePast {
+		since = time.Time{}
+	}
+	reservations, err := reservation.Reservations(ctx, req.ResourcePoolName, since)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetResourcePoolReservationsResponse{
+		Reservations: reservationsToProto(reservations),
+	}, nil
+}
+
+func (a *apiServer) GetResourcePoolReservationConflicts(
+	ctx context.Context, req *apiv1.GetResourcePoolReservationConflictsRequest,
+) (*apiv1.GetResourcePoolReservationConflictsResponse, error) {
+	if req.StartTime == nil || req.EndTime == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time and end_time must be set")
+	}
+	start, end := req.StartTime.AsTime(), req.EndTime.AsTime()
+	if !start.Before(end) {
+		return nil, status.Error(codes.InvalidArgument, "start time must be before end time")
+	}
+	if err := a.m.rm.ValidateResourcePool(rm.ResourcePoolName(req.ResourcePoolName)); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	existing, err := reservation.Reservations(ctx, req.ResourcePoolName, start)
+	if err != nil {
+		return nil, err
+	}
+	c := reservation.FindConflicts(existing, start, end, req.SlotFraction)
+	return &apiv1.GetResourcePoolReservationConflictsResponse{
+		Overlapping:      reservationsToProto(c.Overlapping),
+		PeakSlotFraction: c.PeakSlotFraction,
+		Conflict:         c.Conflict,
+	}, nil
+}
+
+func (a *apiServer) PostResourcePoolReservation(
+	ctx context.Context, req *apiv1.PostResourcePoolReservationRequest,
+) (*apiv1.PostResourcePoolReservationResponse, error) {
+	if req.StartTime == nil || req.EndTime == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time and end_time must be set")
+	}
+	curUser, err := checkCanManageReservations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &model.ResourcePoolReservation{
+		ResourcePool: req.ResourcePoolName,
+		SlotFraction: req.SlotFraction,
+		StartTime:    req.StartTime.AsTime(),
+		EndTime:      req.EndTime.AsTime(),
+		Description:  req.Description,
+		CreatedBy:    curUser.ID,
+	}
+	if req.WorkspaceId != nil {
+		workspaceID := int(*req.WorkspaceId)
+		r.WorkspaceID = &workspaceID
+	}
+	if req.UserId != nil {
+		userID := model.UserID(*req.UserId)
+		r.UserID = &userID
+	}
+	if err = reservation.Validate(r, time.Now()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = a.m.rm.ValidateResourcePool(rm.ResourcePoolName(r.ResourcePool)); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	// Syncing the reservations the pool already has first tells whether its resource manager
+	// honors reservations before the new one is made.
+	err = a.m.syncResourcePoolReservations(ctx, r.ResourcePool)
+	if errors.Is(err, rmerrors.ErrNotSupported) {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	conflicts, err := reservation.CreateReservation(ctx, r)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "workspace or user not found")
+	} else if err != nil {
+		return nil, err
+	}
+	if conflicts.Conflict {
+		ids := make([]int, 0, len(conflicts.Overlapping))
+		for _, o := range conflicts.Overlapping {
+			ids = append(ids, o.ID)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"with reservations %v, %.0f%% of the pool would be reserved at once",
+			ids, conflicts.PeakSlotFraction*100)
+	}
+	if err = a.m.syncResourcePoolReservations(ctx, r.ResourcePool); err != nil {
+		return nil, err
+	}
+	return &apiv1.PostResourcePoolReservationResponse{Reservation: r.Proto()}, nil
+}
+
+func (a *apiServer) DeleteResourcePoolReservation(
+	ctx context.Context, req *apiv1.DeleteResourcePoolReservationRequest,
+) (*apiv1.DeleteResourcePoolReservationResponse, error) {
+	if _, err := checkCanManageReservations(ctx); err != nil {
+		return nil, err
+	}
+
+	r, err := reservation.ReservationByID(ctx, int(req.ReservationId))
+	if errors.Is(err, db.ErrNotFound) || (err == nil && r.ResourcePool != req.ResourcePoolName) {
+		return nil, api.NotFoundErrs("reservation", strconv.Itoa(int(req.ReservationId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	if err = reservation.DeleteReservation(ctx, int(req.ReservationId)); err != nil {
+		return nil, err
+	}
+	if err = a.m.syncResourcePoolReservations(ctx, req.ResourcePoolName); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteResourcePoolReservationResponse{}, nil
+}
+
+// checkCanManageReservations checks the user can reserve resource pools, returning the user.
+func checkCanManageReservations(ctx context.Context) (*model.User, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, curUser)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, status.Error(codes.PermissionDenied, permErr.Error())
+	}
+	return curUser, nil
+}
+
+func reservationsToProto(
+	reservations []model.ResourcePoolReservation,
+) []*resourcepoolv1.ResourcePoolReservation {
+	pbs := make([]*resourcepoolv1.ResourcePoolReservation, 0, len(reservations))
+	for _, r := range reservations {
+		pbs = append(pbs, r.Proto())
+	}
+	return pbs
+}
@@ -0,0 +1,89 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestResourcePoolReservationsAPI(t *testing.T) {
+	mockRM := MockRM()
+	api, _, ctx := setupAPITest(t, nil, mockRM)
+	mockRM.On("ValidateResourcePool", mock.Anything).Return(nil)
+	mockRM.On("SetReservations", mock.Anything, mock.Anything).Return(nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+	pool := "reservations-" + time.Now().Format("150405.000000")
+
+	start := time.Now().Add(time.Hour)
+	end := start.Add(8 * time.Hour)
+	post, err := api.PostResourcePoolReservation(ctx, &apiv1.PostResourcePoolReservationRequest{
+		ResourcePoolName: pool,
+		WorkspaceId:      ptrs.Ptr(int32(workspaceID)),
+		SlotFraction:     0.7,
+		StartTime:        timestamppb.New(start),
+		EndTime:          timestamppb.New(end),
+		Description:      "Demo day",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(workspaceID), post.Reservation.GetWorkspaceId())
+	require.Nil(t, post.Reservation.UserId)
+
+	conflicts, err := api.GetResourcePoolReservationConflicts(ctx,
+		&apiv1.GetResourcePoolReservationConflictsRequest{
+			ResourcePoolName: pool,
+			StartTime:        timestamppb.New(start),
+			EndTime:          timestamppb.New(end),
+			SlotFraction:     0.5,
+		})
+	require.NoError(t, err)
+	require.True(t, conflicts.Conflict)
+	require.Len(t, conflicts.Overlapping, 1)
+
+	// Reserving more than the whole pool at once is refused.
+	_, err = api.PostResourcePoolReservation(ctx, &apiv1.PostResourcePoolReservationRequest{
+		ResourcePoolName: pool,
+		WorkspaceId:      ptrs.Ptr(int32(workspaceID)),
+		SlotFraction:     0.5,
+		StartTime:        timestamppb.New(start),
+		EndTime:          timestamppb.New(end),
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), err)
+	_, err = api.PostResourcePoolReservation(ctx, &apiv1.PostResourcePoolReservationRequest{
+		ResourcePoolName: pool,
+		SlotFraction:     0.1,
+		StartTime:        timestamppb.New(start),
+		EndTime:          timestamppb.New(end),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	list, err := api.GetResourcePoolReservations(ctx, &apiv1.GetResourcePoolReservationsRequest{
+		ResourcePoolName: pool,
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Reservations, 1)
+
+	_, err = api.DeleteResourcePoolReservation(ctx, &apiv1.DeleteResourcePoolReservationRequest{
+		ResourcePoolName: "other-pool", ReservationId: post.Reservation.Id,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+	_, err = api.DeleteResourcePoolReservation(ctx, &apiv1.DeleteResourcePoolReservationRequest{
+		ResourcePoolName: pool, ReservationId: post.Reservation.Id,
+	})
+	require.NoError(t, err)
+	list, err = api.GetResourcePoolReservations(ctx, &apiv1.GetResourcePoolReservationsRequest{
+		ResourcePoolName: pool,
+	})
+	require.NoError(t, err)
+	require.Empty(t, list.Reservations)
+}
@@ -2,16 +2,24 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/cluster"
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
+	"github.com/determined-ai/determined/master/internal/sproto"
 	workspaceauth "github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/set"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
@@ -282,3 +290,110 @@ func combineWorkspaceIDsAndNames(ctx context.Context, ids []int32, names []strin
 
 	return idSet.ToSlice(), nil
 }
+
+func (a *apiServer) GetResourcePoolWorkloadClasses(
+	ctx context.Context, req *apiv1.GetResourcePoolWorkloadClassesRequest,
+) (*apiv1.GetResourcePoolWorkloadClassesResponse, error) {
+	pool := rm.ResourcePoolName(req.ResourcePoolName)
+	if err := a.m.rm.ValidateResourcePool(pool); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanGetMasterConfig(ctx, curUser)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, permErr
+	}
+
+	summary, err := a.m.rm.GetWorkloadClasses(pool)
+	if errors.Is(err, rmerrors.ErrNotSupported) {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	cfg, usage := workloadClassesToProto(summary)
+	return &apiv1.GetResourcePoolWorkloadClassesResponse{Config: cfg, Usage: usage}, nil
+}
+
+func (a *apiServer) PutResourcePoolWorkloadClasses(
+	ctx context.Context, req *apiv1.PutResourcePoolWorkloadClassesRequest,
+) (*apiv1.PutResourcePoolWorkloadClassesResponse, error) {
+	pool := rm.ResourcePoolName(req.ResourcePoolName)
+	if err := a.m.rm.ValidateResourcePool(pool); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanUpdateMasterConfig(ctx, curUser)
+	if err != nil {
+		return nil, err
+	} else if permErr != nil {
+		return nil, permErr
+	}
+
+	var cfg *config.WorkloadClassesConfig
+	if req.Config != nil {
+		cfg = &config.WorkloadClassesConfig{
+			Experiment: workloadClassFromProto(req.Config.Experiment),
+			NTSC:       workloadClassFromProto(req.Config.Ntsc),
+		}
+		if err = check.Validate(cfg); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	err = a.m.rm.SetWorkloadClasses(pool, cfg)
+	if errors.Is(err, rmerrors.ErrNotSupported) {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	summary, err := a.m.rm.GetWorkloadClasses(pool)
+	if err != nil {
+		return nil, err
+	}
+	pbCfg, usage := workloadClassesToProto(summary)
+	return &apiv1.PutResourcePoolWorkloadClassesResponse{Config: pbCfg, Usage: usage}, nil
+}
+
+func workloadClassFromProto(pb *resourcepoolv1.WorkloadClassConfig) config.WorkloadClassConfig {
+	return config.WorkloadClassConfig{
+		Weight:        pb.GetWeight(),
+		ReservedSlots: int(pb.GetReservedSlots()),
+	}
+}
+
+func workloadClassToProto(c config.WorkloadClassConfig) *resourcepoolv1.WorkloadClassConfig {
+	return &resourcepoolv1.WorkloadClassConfig{
+		Weight:        c.Weight,
+		ReservedSlots: int32(c.ReservedSlots),
+	}
+}
+
+func workloadClassesToProto(
+	s *sproto.WorkloadClassesSummary,
+) (*resourcepoolv1.WorkloadClassesConfig, []*resourcepoolv1.WorkloadClassUsage) {
+	var cfg *resourcepoolv1.WorkloadClassesConfig
+	if s.Config != nil {
+		cfg = &resourcepoolv1.WorkloadClassesConfig{
+			Experiment: workloadClassToProto(s.Config.Experiment),
+			Ntsc:       workloadClassToProto(s.Config.NTSC),
+		}
+	}
+	usage := make([]*resourcepoolv1.WorkloadClassUsage, 0, len(s.Usage))
+	for _, u := range s.Usage {
+		usage = append(usage, &resourcepoolv1.WorkloadClassUsage{
+			Class:         string(u.Class),
+			EntitledSlots: int32(u.EntitledSlots),
+			UsedSlots:     int32(u.UsedSlots),
+			PendingSlots:  int32(u.PendingSlots),
+		})
+	}
+	return cfg, usage
+}
@@ -5,6 +5,7 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/uptrace/bun"
@@ -12,10 +13,15 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/mocks"
 	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/pkg/set"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
@@ -367,3 +373,63 @@ func TestDeleteBindingsSucceeds(t *testing.T) {
 
 	require.True(t, mockRM.AssertExpectations(t))
 }
+
+func TestResourcePoolWorkloadClasses(t *testing.T) {
+	mockRM := MockRM()
+	api, curUser, ctx := setupAPITest(t, nil, mockRM)
+	mockRM.On("ValidateResourcePool", rm.ResourcePoolName("missing")).
+		Return(fmt.Errorf("no such pool"))
+	mockRM.On("ValidateResourcePool", rm.ResourcePoolName(testPoolName)).Return(nil)
+	cfg := &config.WorkloadClassesConfig{
+		Experiment: config.WorkloadClassConfig{Weight: 3},
+		NTSC:       config.WorkloadClassConfig{Weight: 1, ReservedSlots: 4},
+	}
+	mockRM.On("SetWorkloadClasses", rm.ResourcePoolName(testPoolName), cfg).Return(nil)
+	mockRM.On("GetWorkloadClasses", rm.ResourcePoolName(testPoolName)).
+		Return(&sproto.WorkloadClassesSummary{
+			Config: cfg,
+			Usage: []sproto.WorkloadClassUsage{
+				{Class: sproto.WorkloadClassExperiment, EntitledSlots: 6, UsedSlots: 2},
+				{Class: sproto.WorkloadClassNTSC, EntitledSlots: 4, PendingSlots: 1},
+			},
+		}, nil)
+
+	_, err := api.GetResourcePoolWorkloadClasses(ctx,
+		&apiv1.GetResourcePoolWorkloadClassesRequest{ResourcePoolName: "missing"})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.PutResourcePoolWorkloadClasses(ctx, &apiv1.PutResourcePoolWorkloadClassesRequest{
+		ResourcePoolName: testPoolName,
+		Config: &resourcepoolv1.WorkloadClassesConfig{
+			Experiment: &resourcepoolv1.WorkloadClassConfig{Weight: -1},
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	put, err := api.PutResourcePoolWorkloadClasses(ctx, &apiv1.PutResourcePoolWorkloadClassesRequest{
+		ResourcePoolName: testPoolName,
+		Config: &resourcepoolv1.WorkloadClassesConfig{
+			Experiment: &resourcepoolv1.WorkloadClassConfig{Weight: 3},
+			Ntsc:       &resourcepoolv1.WorkloadClassConfig{Weight: 1, ReservedSlots: 4},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(4), put.Config.Ntsc.ReservedSlots)
+
+	get, err := api.GetResourcePoolWorkloadClasses(ctx,
+		&apiv1.GetResourcePoolWorkloadClassesRequest{ResourcePoolName: testPoolName})
+	require.NoError(t, err)
+	require.Len(t, get.Usage, 2)
+	require.Equal(t, "ntsc", get.Usage[1].Class)
+	require.Equal(t, int32(1), get.Usage[1].PendingSlots)
+
+	// Only admins may read or change how a pool is shared.
+	curUser.Admin = false
+	require.NoError(t, user.Update(ctx, &curUser, []string{"admin"}, nil))
+	_, err = api.GetResourcePoolWorkloadClasses(ctx,
+		&apiv1.GetResourcePoolWorkloadClassesRequest{ResourcePoolName: testPoolName})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+	_, err = api.PutResourcePoolWorkloadClasses(ctx,
+		&apiv1.PutResourcePoolWorkloadClassesRequest{ResourcePoolName: testPoolName})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+}
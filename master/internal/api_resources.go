@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/capacitysim"
+	"github.com/determined-ai/determined/master/internal/fairness"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// defaultReplayDays is how many days of allocations a capacity simulation replays by default.
+const defaultReplayDays = 30
+
+func (a *apiServer) PostCapacitySimulation(
+	ctx context.Context, req *apiv1.PostCapacitySimulationRequest,
+) (*apiv1.PostCapacitySimulationResponse, error) {
+	if (req.Replay == nil) == (len(req.Jobs) == 0) {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of jobs and replay must be set")
+	}
+	pool := capacitysim.Pool{Scheduler: req.Pool.GetScheduler()}
+	for _, g := range req.Pool.GetAgents() {
+		pool.Agents = append(pool.Agents, capacitysim.AgentGroup{
+			Count: int(g.Count), Slots: int(g.Slots),
+		})
+	}
+	if err := check.Validate(pool); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	jobs := make([]capacitysim.Job, 0, len(req.Jobs))
+	for _, j := range req.Jobs {
+		job := capacitysim.Job{
+			SubmitSeconds:   j.SubmitSeconds,
+			DurationSeconds: j.DurationSeconds,
+			Slots:           int(j.Slots),
+			Priority:        int(j.Priority),
+		}
+		if err := check.Validate(job); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		jobs = append(jobs, job)
+	}
+
+	if replay := req.Replay; replay != nil {
+		// Replaying reads the allocations of every user of the pool.
+		curUser, _, err := grpcutil.GetUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err = a.m.canGetUsageDetails(ctx, curUser); err != nil {
+			return nil, err
+		}
+
+		days := int(replay.Days)
+		if days == 0 {
+			days = defaultReplayDays
+		}
+		if days < 0 {
+			return nil, status.Error(codes.InvalidArgument, "replay days must be positive")
+		}
+		if err = a.m.rm.ValidateResourcePool(rm.ResourcePoolName(replay.ResourcePool)); err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		since := time.Now().AddDate(0, 0, -days)
+		if jobs, err = capacitysim.Replay(ctx, replay.ResourcePool, since); err != nil {
+			return nil, err
+		}
+	}
+
+	res := capacitysim.Simulate(pool, jobs)
+	return &apiv1.PostCapacitySimulationResponse{
+		TotalSlots:    int32(res.TotalSlots),
+		Jobs:          int32(res.Jobs),
+		Unschedulable: int32(res.Unschedulable),
+		WaitSeconds: &apiv1.CapacitySimulationStats{
+			Mean: res.WaitSeconds.Mean,
+			P50:  res.WaitSeconds.P50,
+			P90:  res.WaitSeconds.P90,
+			P99:  res.WaitSeconds.P99,
+			Max:  res.WaitSeconds.Max,
+		},
+		MakespanSeconds: res.MakespanSeconds,
+		Utilization:     res.Utilization,
+	}, nil
+}
+
+func (a *apiServer) GetQueueFairness(
+	ctx context.Context, req *apiv1.GetQueueFairnessRequest,
+) (*apiv1.GetQueueFairnessResponse, error) {
+	// The report has the wait times and usage of every user and workspace.
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.m.canGetUsageDetails(ctx, curUser); err != nil {
+		return nil, err
+	}
+
+	if req.TimestampAfter == nil {
+		return nil, status.Error(codes.InvalidArgument, "no start time provided")
+	}
+	if req.TimestampBefore == nil {
+		return nil, status.Error(codes.InvalidArgument, "no end time provided")
+	}
+	start := req.TimestampAfter.AsTime()
+	end := req.TimestampBefore.AsTime()
+	if !start.Before(end) {
+		return nil, status.Error(codes.InvalidArgument, "start time must be before end time")
+	}
+	pool := req.GetResourcePool()
+	if req.ResourcePool != nil {
+		if err = a.m.rm.ValidateResourcePool(rm.ResourcePoolName(pool)); err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+	}
+
+	allocations, err := fairness.Allocations(ctx, start, end, pool)
+	if err != nil {
+		return nil, err
+	}
+	capacity, err := fairness.CapacitySlotSeconds(ctx, start, end, pool)
+	if err != nil {
+		return nil, err
+	}
+	report := fairness.Compute(start, end, pool, allocations, capacity)
+	return &apiv1.GetQueueFairnessResponse{
+		Allocations:       int32(report.Allocations),
+		WaitSeconds:       fairnessWaitStatsToProto(report.WaitSeconds),
+		CapacitySlotHours: report.CapacitySlotHours,
+		UsedSlotHours:     report.UsedSlotHours,
+		Users:             fairnessGroupsToProto(report.Users),
+		Workspaces:        fairnessGroupsToProto(report.Workspaces),
+	}, nil
+}
+
+func fairnessWaitStatsToProto(s fairness.WaitStats) *apiv1.QueueFairnessWaitStats {
+	return &apiv1.QueueFairnessWaitStats{Mean: s.Mean, P50: s.P50, P90: s.P90, Max: s.Max}
+}
+
+func fairnessGroupsToProto(groups []fairness.GroupStats) []*apiv1.QueueFairnessGroup {
+	res := make([]*apiv1.QueueFairnessGroup, 0, len(groups))
+	for _, g := range groups {
+		res = append(res, &apiv1.QueueFairnessGroup{
+			Name:            g.Name,
+			Allocations:     int32(g.Allocations),
+			WaitSeconds:     fairnessWaitStatsToProto(g.WaitSeconds),
+			SlotHours:       g.SlotHours,
+			ShareOfUsage:    g.ShareOfUsage,
+			ShareOfCapacity: g.ShareOfCapacity,
+			RelativeWait:    g.RelativeWait,
+			Starved:         g.Starved,
+		})
+	}
+	return res
+}
@@ -0,0 +1,81 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestPostCapacitySimulation(t *testing.T) {
+	mockRM := MockRM()
+	api, _, ctx := setupAPITest(t, nil, mockRM)
+	mockRM.On("ValidateResourcePool", mock.Anything).Return(fmt.Errorf("no such pool"))
+	pool := &apiv1.CapacitySimulationPool{
+		Agents: []*apiv1.CapacitySimulationAgentGroup{{Count: 1, Slots: 4}},
+	}
+
+	_, err := api.PostCapacitySimulation(ctx, &apiv1.PostCapacitySimulationRequest{Pool: pool})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	_, err = api.PostCapacitySimulation(ctx, &apiv1.PostCapacitySimulationRequest{
+		Pool: &apiv1.CapacitySimulationPool{},
+		Jobs: []*apiv1.CapacitySimulationJob{{DurationSeconds: 10, Slots: 1}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	_, err = api.PostCapacitySimulation(ctx, &apiv1.PostCapacitySimulationRequest{
+		Pool:   pool,
+		Replay: &apiv1.CapacitySimulationReplay{ResourcePool: "missing"},
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	// The second job waits for the first to finish.
+	resp, err := api.PostCapacitySimulation(ctx, &apiv1.PostCapacitySimulationRequest{
+		Pool: pool,
+		Jobs: []*apiv1.CapacitySimulationJob{
+			{SubmitSeconds: 0, DurationSeconds: 10, Slots: 4},
+			{SubmitSeconds: 0, DurationSeconds: 10, Slots: 4},
+			{SubmitSeconds: 0, DurationSeconds: 10, Slots: 8},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(4), resp.TotalSlots)
+	require.Equal(t, int32(1), resp.Unschedulable)
+	require.InDelta(t, 10, resp.WaitSeconds.Max, 1e-9)
+	require.InDelta(t, 20, resp.MakespanSeconds, 1e-9)
+}
+
+func TestGetQueueFairness(t *testing.T) {
+	mockRM := MockRM()
+	api, _, ctx := setupAPITest(t, nil, mockRM)
+	mockRM.On("ValidateResourcePool", mock.Anything).Return(fmt.Errorf("no such pool"))
+	now := time.Now()
+	after, before := timestamppb.New(now.Add(-time.Hour)), timestamppb.New(now)
+
+	_, err := api.GetQueueFairness(ctx, &apiv1.GetQueueFairnessRequest{TimestampAfter: after})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	_, err = api.GetQueueFairness(ctx, &apiv1.GetQueueFairnessRequest{
+		TimestampAfter: before, TimestampBefore: after,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	missing := "missing"
+	_, err = api.GetQueueFairness(ctx, &apiv1.GetQueueFairnessRequest{
+		TimestampAfter: after, TimestampBefore: before, ResourcePool: &missing,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	resp, err := api.GetQueueFairness(ctx, &apiv1.GetQueueFairnessRequest{
+		TimestampAfter: after, TimestampBefore: before,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.WaitSeconds)
+}
@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/rungroups"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+// runGroupFilter narrows experiments to those a user can view the metadata of.
+func runGroupFilter(ctx context.Context, user model.User) rungroups.ExperimentFilter {
+	return func(q *bun.SelectQuery) (*bun.SelectQuery, error) {
+		return expauth.AuthZProvider.Get().FilterExperimentsQuery(ctx, user, nil, q,
+			[]rbacv1.PermissionType{rbacv1.PermissionType_PERMISSION_TYPE_VIEW_EXPERIMENT_METADATA})
+	}
+}
+
+// getRunGroup returns a run group the caller owns or can see a member of.
+func (a *apiServer) getRunGroup(
+	ctx context.Context, groupID int32,
+) (*rungroups.Group, model.User, error) {
+	user, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, model.User{}, err
+	}
+	g, err := rungroups.GroupByID(ctx, int(groupID), user.ID, runGroupFilter(ctx, *user))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, model.User{}, api.NotFoundErrs("run group", strconv.Itoa(int(groupID)), true)
+	} else if err != nil {
+		return nil, model.User{}, err
+	}
+	return g, *user, nil
+}
+
+// checkCanChangeRunGroup checks the caller may rename or delete a run group, which its owner and
+// anyone who can edit every one of its experiments may.
+func (a *apiServer) checkCanChangeRunGroup(
+	ctx context.Context, user model.User, g *rungroups.Group,
+) error {
+	if g.OwnerID == user.ID {
+		return nil
+	}
+	ids, err := rungroups.ExperimentIDs(ctx, g.ID)
+	if err != nil {
+		return err
+	}
+	forbidden := status.Error(codes.PermissionDenied,
+		"only the owner of a run group or users who can edit all of its experiments can change it")
+	if len(ids) == 0 {
+		return forbidden
+	}
+	for _, id := range ids {
+		if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, id,
+			expauth.AuthZProvider.Get().CanEditExperimentsMetadata); err != nil {
+			if code := status.Code(err); code == codes.NotFound || code == codes.PermissionDenied {
+				return forbidden
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *apiServer) GetRunGroups(
+	ctx context.Context, req *apiv1.GetRunGroupsRequest,
+) (*apiv1.GetRunGroupsResponse, error) {
+	user, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := rungroups.Groups(ctx, user.ID, runGroupFilter(ctx, *user))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetRunGroupsResponse{RunGroups: []*experimentv1.RunGroup{}}
+	for _, g := range groups {
+		resp.RunGroups = append(resp.RunGroups, g.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostRunGroup(
+	ctx context.Context, req *apiv1.PostRunGroupRequest,
+) (*apiv1.PostRunGroupResponse, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, status.Error(codes.InvalidArgument, "name must be set")
+	}
+
+	ids := make([]int, 0, len(req.ExperimentIds))
+	for _, id := range req.ExperimentIds {
+		if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(id),
+			expauth.AuthZProvider.Get().CanEditExperimentsMetadata); err != nil {
+			return nil, err
+		}
+		ids = append(ids, int(id))
+	}
+	user, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	g := &model.RunGroup{Name: req.Name, Description: req.Description, OwnerID: user.ID}
+	if err := rungroups.Create(ctx, g, ids); err != nil {
+		return nil, err
+	}
+	res, _, err := a.getRunGroup(ctx, int32(g.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PostRunGroupResponse{RunGroup: res.Proto()}, nil
+}
+
+func (a *apiServer) GetRunGroup(
+	ctx context.Context, req *apiv1.GetRunGroupRequest,
+) (*apiv1.GetRunGroupResponse, error) {
+	g, user, err := a.getRunGroup(ctx, req.GroupId)
+	if err != nil {
+		return nil, err
+	}
+	members, err := rungroups.Members(ctx, g.ID, runGroupFilter(ctx, user))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetRunGroupResponse{
+		RunGroup:    g.Proto(),
+		Experiments: []*experimentv1.RunGroupExperiment{},
+	}
+	for _, m := range members {
+		resp.Experiments = append(resp.Experiments, m.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PatchRunGroup(
+	ctx context.Context, req *apiv1.PatchRunGroupRequest,
+) (*apiv1.PatchRunGroupResponse, error) {
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return nil, status.Error(codes.InvalidArgument, "name must not be empty")
+	}
+
+	g, user, err := a.getRunGroup(ctx, req.GroupId)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkCanChangeRunGroup(ctx, user, g); err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		g.Name = *req.Name
+	}
+	if req.Description != nil {
+		g.Description = *req.Description
+	}
+	if err := rungroups.Update(ctx, &g.RunGroup); err != nil {
+		return nil, err
+	}
+	return &apiv1.PatchRunGroupResponse{RunGroup: g.Proto()}, nil
+}
+
+func (a *apiServer) DeleteRunGroup(
+	ctx context.Context, req *apiv1.DeleteRunGroupRequest,
+) (*apiv1.DeleteRunGroupResponse, error) {
+	g, user, err := a.getRunGroup(ctx, req.GroupId)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkCanChangeRunGroup(ctx, user, g); err != nil {
+		return nil, err
+	}
+	if err := rungroups.Delete(ctx, g.ID); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteRunGroupResponse{}, nil
+}
+
+func (a *apiServer) PutRunGroupExperiment(
+	ctx context.Context, req *apiv1.PutRunGroupExperimentRequest,
+) (*apiv1.PutRunGroupExperimentResponse, error) {
+	if _, _, err := a.getRunGroup(ctx, req.GroupId); err != nil {
+		return nil, err
+	}
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		expauth.AuthZProvider.Get().CanEditExperimentsMetadata); err != nil {
+		return nil, err
+	}
+	err := rungroups.AddExperiments(ctx, int(req.GroupId), []int{int(req.ExperimentId)})
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PutRunGroupExperimentResponse{}, nil
+}
+
+func (a *apiServer) DeleteRunGroupExperiment(
+	ctx context.Context, req *apiv1.DeleteRunGroupExperimentRequest,
+) (*apiv1.DeleteRunGroupExperimentResponse, error) {
+	if _, _, err := a.getRunGroup(ctx, req.GroupId); err != nil {
+		return nil, err
+	}
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		expauth.AuthZProvider.Get().CanEditExperimentsMetadata); err != nil {
+		return nil, err
+	}
+	err := rungroups.RemoveExperiment(ctx, int(req.GroupId), int(req.ExperimentId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("experiment in run group",
+			strconv.Itoa(int(req.ExperimentId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteRunGroupExperimentResponse{}, nil
+}
+
+func (a *apiServer) GetRunGroupSummary(
+	ctx context.Context, req *apiv1.GetRunGroupSummaryRequest,
+) (*apiv1.GetRunGroupSummaryResponse, error) {
+	if req.MetricName == "" {
+		return nil, status.Error(codes.InvalidArgument, "metric_name must be set")
+	}
+	smallerIsBetter := req.SmallerIsBetter == nil || *req.SmallerIsBetter
+
+	g, user, err := a.getRunGroup(ctx, req.GroupId)
+	if err != nil {
+		return nil, err
+	}
+	// Only the experiments the caller can see count towards the summary.
+	members, err := rungroups.Members(ctx, g.ID, runGroupFilter(ctx, user))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.ExperimentID)
+	}
+	values, err := rungroups.FinalValues(ctx, ids, req.MetricName)
+	if err != nil {
+		return nil, err
+	}
+	return rungroups.Summarize(req.MetricName, smallerIsBetter, ids, values).Proto(), nil
+}
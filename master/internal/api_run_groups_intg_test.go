@@ -0,0 +1,81 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestRunGroupsAPI(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+
+	_, err := api.PostRunGroup(ctx, &apiv1.PostRunGroupRequest{Name: " "})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	_, err = api.PostRunGroup(ctx, &apiv1.PostRunGroupRequest{
+		Name: "sweeps", ExperimentIds: []int32{-1},
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	post, err := api.PostRunGroup(ctx, &apiv1.PostRunGroupRequest{
+		Name:          "sweeps",
+		Description:   "one sweep per dataset",
+		ExperimentIds: []int32{int32(exp.ID)},
+	})
+	require.NoError(t, err)
+	groupID := post.RunGroup.Id
+	require.Equal(t, int32(1), post.RunGroup.NumExperiments)
+	require.Equal(t, int32(curUser.ID), post.RunGroup.OwnerId)
+
+	list, err := api.GetRunGroups(ctx, &apiv1.GetRunGroupsRequest{})
+	require.NoError(t, err)
+	require.NotEmpty(t, list.RunGroups)
+
+	get, err := api.GetRunGroup(ctx, &apiv1.GetRunGroupRequest{GroupId: groupID})
+	require.NoError(t, err)
+	require.Len(t, get.Experiments, 1)
+	require.Equal(t, int32(exp.ID), get.Experiments[0].ExperimentId)
+
+	patch, err := api.PatchRunGroup(ctx, &apiv1.PatchRunGroupRequest{
+		GroupId: groupID, Name: ptrs.Ptr("renamed"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "renamed", patch.RunGroup.Name)
+	require.Equal(t, "one sweep per dataset", patch.RunGroup.Description)
+
+	summary, err := api.GetRunGroupSummary(ctx, &apiv1.GetRunGroupSummaryRequest{
+		GroupId: groupID, MetricName: "loss",
+	})
+	require.NoError(t, err)
+	require.True(t, summary.SmallerIsBetter)
+	require.Len(t, summary.Experiments, 1)
+	_, err = api.GetRunGroupSummary(ctx, &apiv1.GetRunGroupSummaryRequest{GroupId: groupID})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.DeleteRunGroupExperiment(ctx, &apiv1.DeleteRunGroupExperimentRequest{
+		GroupId: groupID, ExperimentId: int32(exp.ID),
+	})
+	require.NoError(t, err)
+	_, err = api.DeleteRunGroupExperiment(ctx, &apiv1.DeleteRunGroupExperimentRequest{
+		GroupId: groupID, ExperimentId: int32(exp.ID),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+	_, err = api.PutRunGroupExperiment(ctx, &apiv1.PutRunGroupExperimentRequest{
+		GroupId: groupID, ExperimentId: int32(exp.ID),
+	})
+	require.NoError(t, err)
+
+	_, err = api.DeleteRunGroup(ctx, &apiv1.DeleteRunGroupRequest{GroupId: groupID})
+	require.NoError(t, err)
+	_, err = api.GetRunGroup(ctx, &apiv1.GetRunGroupRequest{GroupId: groupID})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
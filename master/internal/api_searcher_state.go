@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) GetExperimentSearcherState(
+	ctx context.Context, req *apiv1.GetExperimentSearcherStateRequest,
+) (*apiv1.GetExperimentSearcherStateResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId)); err != nil {
+		return nil, err
+	}
+
+	e, ok := experiment.ExperimentRegistry.Load(int(req.ExperimentId))
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "experiment %d is not running", req.ExperimentId)
+	}
+	trialIDs, err := db.TrialIDsByRequestID(ctx, int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetExperimentSearcherStateResponse{
+		State:    e.IntrospectSearcher(trialIDs).Proto(),
+		Progress: e.EstimateSearcherProgress().Proto(),
+	}, nil
+}
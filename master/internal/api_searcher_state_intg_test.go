@@ -0,0 +1,30 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestGetExperimentSearcherStateNotRunning(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+
+	_, err := api.GetExperimentSearcherState(ctx, &apiv1.GetExperimentSearcherStateRequest{
+		ExperimentId: int32(exp.ID),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.GetExperimentSearcherState(ctx, &apiv1.GetExperimentSearcherStateRequest{
+		ExperimentId: -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/storageusage"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) checkCanSetStorageQuotas(ctx context.Context, workspaceID int32) error {
+	_, user, err := a.getWorkspaceAndCheckCanDoActions(ctx, workspaceID, false)
+	if err != nil {
+		return err
+	}
+	if err = workspace.AuthZProvider.Get().CanSetResourceQuotas(ctx, user); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+func storageQuotaOverrideErr(err error, workspaceID int32) error {
+	if errors.Is(err, db.ErrNotFound) {
+		return status.Errorf(codes.NotFound,
+			"workspace %d has no storage quotas to override", workspaceID)
+	}
+	return err
+}
+
+func (a *apiServer) PutWorkspaceStorageQuota(
+	ctx context.Context, req *apiv1.PutWorkspaceStorageQuotaRequest,
+) (*apiv1.PutWorkspaceStorageQuotaResponse, error) {
+	switch soft, hard := req.SoftQuotaBytes, req.HardQuotaBytes; {
+	case soft != nil && *soft < 0, hard != nil && *hard < 0:
+		return nil, status.Error(codes.InvalidArgument, "quotas must be >= 0")
+	case soft != nil && hard != nil && *soft > *hard:
+		return nil, status.Error(codes.InvalidArgument,
+			"soft_quota_bytes must not exceed hard_quota_bytes")
+	}
+
+	if err := a.checkCanSetStorageQuotas(ctx, req.WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	if err := storageusage.SetQuotas(
+		ctx, int(req.WorkspaceId), req.SoftQuotaBytes, req.HardQuotaBytes,
+	); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutWorkspaceStorageQuotaResponse{}, nil
+}
+
+func (a *apiServer) PutWorkspaceStorageQuotaOverride(
+	ctx context.Context, req *apiv1.PutWorkspaceStorageQuotaOverrideRequest,
+) (*apiv1.PutWorkspaceStorageQuotaOverrideResponse, error) {
+	if req.Until == nil {
+		return nil, status.Error(codes.InvalidArgument, "until must be set")
+	}
+	if err := a.checkCanSetStorageQuotas(ctx, req.WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	until := req.Until.AsTime()
+	if err := storageQuotaOverrideErr(
+		storageusage.SetOverride(ctx, int(req.WorkspaceId), &until), req.WorkspaceId,
+	); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutWorkspaceStorageQuotaOverrideResponse{}, nil
+}
+
+func (a *apiServer) DeleteWorkspaceStorageQuotaOverride(
+	ctx context.Context, req *apiv1.DeleteWorkspaceStorageQuotaOverrideRequest,
+) (*apiv1.DeleteWorkspaceStorageQuotaOverrideResponse, error) {
+	if err := a.checkCanSetStorageQuotas(ctx, req.WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	if err := storageQuotaOverrideErr(
+		storageusage.SetOverride(ctx, int(req.WorkspaceId), nil), req.WorkspaceId,
+	); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteWorkspaceStorageQuotaOverrideResponse{}, nil
+}
+
+func (a *apiServer) GetTaskStorageQuota(
+	ctx context.Context, req *apiv1.GetTaskStorageQuotaRequest,
+) (*apiv1.GetTaskStorageQuotaResponse, error) {
+	exp, err := a.getTrialTaskExperiment(ctx, model.TaskID(req.TaskId))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.GetTaskStorageQuotaResponse{Allowed: true}
+	err = storageusage.CheckCanWrite(ctx, exp.ID)
+	if errors.Is(err, storageusage.ErrHardQuotaExceeded) {
+		resp.Allowed, resp.Message = false, err.Error()
+	} else if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
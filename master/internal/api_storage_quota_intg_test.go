@@ -0,0 +1,82 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestPutWorkspaceStorageQuota(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	_, err := api.PutWorkspaceStorageQuota(ctx, &apiv1.PutWorkspaceStorageQuotaRequest{
+		WorkspaceId:    int32(workspaceID),
+		SoftQuotaBytes: ptrs.Ptr(int64(10)),
+		HardQuotaBytes: ptrs.Ptr(int64(20)),
+	})
+	require.NoError(t, err)
+
+	resp, err := api.GetWorkspace(ctx, &apiv1.GetWorkspaceRequest{Id: int32(workspaceID)})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), resp.Workspace.GetSoftStorageQuotaBytes())
+	require.Equal(t, int64(20), resp.Workspace.GetHardStorageQuotaBytes())
+
+	_, err = api.PutWorkspaceStorageQuota(ctx, &apiv1.PutWorkspaceStorageQuotaRequest{
+		WorkspaceId:    int32(workspaceID),
+		SoftQuotaBytes: ptrs.Ptr(int64(30)),
+		HardQuotaBytes: ptrs.Ptr(int64(20)),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestWorkspaceStorageQuotaOverride(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	overrideReq := &apiv1.PutWorkspaceStorageQuotaOverrideRequest{
+		WorkspaceId: int32(workspaceID),
+		Until:       timestamppb.New(time.Now().Add(time.Hour)),
+	}
+	// There's nothing to override until the workspace has quotas.
+	_, err := api.PutWorkspaceStorageQuotaOverride(ctx, overrideReq)
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.PutWorkspaceStorageQuota(ctx, &apiv1.PutWorkspaceStorageQuotaRequest{
+		WorkspaceId:    int32(workspaceID),
+		HardQuotaBytes: ptrs.Ptr(int64(20)),
+	})
+	require.NoError(t, err)
+	_, err = api.PutWorkspaceStorageQuotaOverride(ctx, overrideReq)
+	require.NoError(t, err)
+	_, err = api.DeleteWorkspaceStorageQuotaOverride(ctx,
+		&apiv1.DeleteWorkspaceStorageQuotaOverrideRequest{WorkspaceId: int32(workspaceID)})
+	require.NoError(t, err)
+
+	_, err = api.PutWorkspaceStorageQuotaOverride(ctx,
+		&apiv1.PutWorkspaceStorageQuotaOverrideRequest{WorkspaceId: int32(workspaceID)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestGetTaskStorageQuota(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+	_, task := db.RequireMockTrial(t, api.m.db, exp)
+
+	resp, err := api.GetTaskStorageQuota(ctx, &apiv1.GetTaskStorageQuotaRequest{
+		TaskId: string(task.TaskID),
+	})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+}
@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/internal/task/straggler"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+func (a *apiServer) PostTaskStepTimings(
+	ctx context.Context, req *apiv1.PostTaskStepTimingsRequest,
+) (*apiv1.PostTaskStepTimingsResponse, error) {
+	taskID := model.TaskID(req.TaskId)
+	allocationID := model.AllocationID(req.AllocationId)
+	if allocationID.ToTaskID() != taskID {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"allocation %s does not belong to task %s", allocationID, taskID)
+	}
+	if _, err := a.getTrialTaskExperiment(ctx, taskID,
+		expauth.AuthZProvider.Get().CanEditExperiment,
+	); err != nil {
+		return nil, err
+	}
+
+	err := straggler.Report(allocationID.String(), straggler.Timing{
+		Rank:            int(req.Rank),
+		AgentID:         req.AgentId,
+		Window:          int(req.Window),
+		WorldSize:       int(req.WorldSize),
+		MeanSeconds:     req.MeanSeconds,
+		MeanWaitSeconds: req.MeanWaitSeconds,
+	})
+	switch {
+	case errors.Is(err, straggler.ErrDetectionDisabled):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &apiv1.PostTaskStepTimingsResponse{}, nil
+}
+
+func (a *apiServer) GetTaskStragglerAlerts(
+	ctx context.Context, req *apiv1.GetTaskStragglerAlertsRequest,
+) (*apiv1.GetTaskStragglerAlertsResponse, error) {
+	taskID := model.TaskID(req.TaskId)
+	if _, err := a.getTrialTaskExperiment(ctx, taskID); err != nil {
+		return nil, err
+	}
+	alerts, err := task.StragglerAlertsByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetTaskStragglerAlertsResponse{Alerts: []*taskv1.StragglerAlert{}}
+	for _, alert := range alerts {
+		resp.Alerts = append(resp.Alerts, alert.Proto())
+	}
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestPostTaskStepTimings(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+	_, task := db.RequireMockTrial(t, api.m.db, exp)
+	alloc := db.RequireMockAllocation(t, api.m.db, task.TaskID)
+
+	req := &apiv1.PostTaskStepTimingsRequest{
+		TaskId:       string(task.TaskID),
+		AllocationId: string(alloc.AllocationID),
+		WorldSize:    2,
+		MeanSeconds:  1,
+	}
+	// Straggler detection isn't enabled for the allocation.
+	_, err := api.PostTaskStepTimings(ctx, req)
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	req.AllocationId = string(model.NewTaskID()) + ".1"
+	_, err = api.PostTaskStepTimings(ctx, req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestGetTaskStragglerAlerts(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+	_, task := db.RequireMockTrial(t, api.m.db, exp)
+
+	resp, err := api.GetTaskStragglerAlerts(ctx, &apiv1.GetTaskStragglerAlertsRequest{
+		TaskId: string(task.TaskID),
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Alerts)
+
+	// Only trials have straggler alerts.
+	_, err = api.GetTaskStragglerAlerts(ctx, &apiv1.GetTaskStragglerAlertsRequest{
+		TaskId: string(model.NewTaskID()),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
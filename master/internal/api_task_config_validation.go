@@ -0,0 +1,249 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	masterConfig "github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/configpolicy"
+	"github.com/determined-ai/determined/master/internal/egress"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/imagedigest"
+	"github.com/determined-ai/determined/master/internal/templates"
+	"github.com/determined-ai/determined/master/internal/user"
+	pkgCommand "github.com/determined-ai/determined/master/pkg/command"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/devicev1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+// imageCheckTimeout bounds how long validating a task config waits on the registry of its image.
+const imageCheckTimeout = 15 * time.Second
+
+// The checks a task config is validated with.
+const (
+	taskConfigCheckConfig     = "config"
+	taskConfigCheckResources  = "resources"
+	taskConfigCheckPolicy     = "policy"
+	taskConfigCheckBindMounts = "bind_mounts"
+	taskConfigCheckImage      = "image"
+	taskConfigCheckProxyPorts = "proxy_ports"
+)
+
+// addTaskConfigProblem records a problem found by a check, on a field of the config if it is from
+// one.
+func addTaskConfigProblem(
+	v *apiv1.ValidateTaskConfigResponse, check, field string, err error,
+) {
+	p := &taskv1.TaskConfigProblem{Check: check, Message: err.Error()}
+	if field != "" {
+		p.Field = &field
+	}
+	v.Problems = append(v.Problems, p)
+}
+
+// proxyPortRanges are the ranges the ports of the servers notebooks, shells and tensorboards run
+// are picked from, at random, when they launch.
+var proxyPortRanges = map[model.TaskType][2]int{
+	model.TaskTypeNotebook:    {minNotebookPort, maxNotebookPort},
+	model.TaskTypeShell:       {minSshdPort, maxSshdPort},
+	model.TaskTypeTensorboard: {minTensorBoardPort, maxTensorBoardPort},
+}
+
+// checkProxyPorts returns the problems with the proxy ports of a task: ports that aren't valid,
+// ports that are configured more than once, and ports its own server might be assigned.
+func checkProxyPorts(
+	taskType model.TaskType, ports model.ProxyPortsConfig,
+) []*taskv1.TaskConfigProblem {
+	var problems []*taskv1.TaskConfigProblem
+	seen := map[int]bool{}
+	for i, p := range ports {
+		field := fmt.Sprintf("environment.proxy_ports[%d]", i)
+		port := p.ProxyPort
+		r, reserved := proxyPortRanges[taskType]
+		switch {
+		case port < 1 || port > 65535:
+			problems = append(problems, &taskv1.TaskConfigProblem{
+				Check: taskConfigCheckProxyPorts, Field: &field,
+				Message: fmt.Sprintf("proxy port %d is not between 1 and 65535", port),
+			})
+		case seen[port]:
+			problems = append(problems, &taskv1.TaskConfigProblem{
+				Check: taskConfigCheckProxyPorts, Field: &field,
+				Message: fmt.Sprintf("proxy port %d is configured more than once", port),
+			})
+		case reserved && port >= r[0] && port < r[1]:
+			problems = append(problems, &taskv1.TaskConfigProblem{
+				Check: taskConfigCheckProxyPorts, Field: &field,
+				Message: fmt.Sprintf("proxy port %d may conflict with the port of the %s's own "+
+					"server, which is picked from %d-%d", port, strings.ToLower(string(taskType)),
+					r[0], r[1]-1),
+			})
+		}
+		seen[port] = true
+	}
+	return problems
+}
+
+// slotDeviceType returns the type of the devices a task with slots gets in a resource pool, given
+// the slot types of the pools.
+func slotDeviceType(slotTypes map[string]devicev1.Type, pool string, slots int) device.Type {
+	if slots == 0 {
+		return device.CPU
+	}
+	switch slotTypes[pool] {
+	case devicev1.Type_TYPE_CPU:
+		return device.CPU
+	case devicev1.Type_TYPE_ROCM:
+		return device.ROCM
+	default:
+		return device.CUDA
+	}
+}
+
+// ValidateTaskConfig checks a config as it would be resolved to launch a task, collecting every
+// problem that would make the launch fail, or the task fail once its container starts, instead of
+// stopping at the first.
+func (a *apiServer) ValidateTaskConfig(
+	ctx context.Context, req *apiv1.ValidateTaskConfigRequest,
+) (*apiv1.ValidateTaskConfigResponse, error) {
+	taskType := model.TaskType(strings.TrimPrefix(req.TaskType.String(), "TASK_TYPE_"))
+	switch taskType {
+	case model.TaskTypeCommand, model.TaskTypeNotebook, model.TaskTypeShell,
+		model.TaskTypeTensorboard:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unsupported task_type %s", req.TaskType)
+	}
+	workspaceID := model.AccessScopeID(model.DefaultWorkspaceID)
+	if req.WorkspaceId != 0 {
+		workspaceID = model.AccessScopeID(req.WorkspaceId)
+	}
+	var configBytes []byte
+	if req.Config != nil {
+		var err error
+		if configBytes, err = protojson.Marshal(req.Config); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &tasks.GenericCommandSpec{}
+	spec.Metadata.WorkspaceID = workspaceID
+	if err := a.isNTSCPermittedToLaunch(ctx, spec, curUser); err != nil {
+		return nil, err
+	}
+	if err := checkCanSubmit(ctx); err != nil {
+		return nil, err
+	}
+	agentUserGroup, err := user.GetAgentUserGroup(ctx, curUser.ID, int(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	v := &apiv1.ValidateTaskConfigResponse{
+		Problems: []*taskv1.TaskConfigProblem{},
+		Warnings: []string{},
+	}
+	resources := model.ParseJustResources(configBytes)
+	if taskType == model.TaskTypeTensorboard {
+		resources.Slots = 0
+	}
+	poolName, launchWarnings, err := a.m.ResolveResources(
+		resources.ResourcePool, resources.Slots, int(workspaceID), true,
+	)
+	if err != nil {
+		addTaskConfigProblem(v, taskConfigCheckResources, "resources", err)
+		// The rest of the config is checked with the defaults of the default pool.
+		if poolName, err = a.m.rm.ResolveResourcePool("", int(workspaceID), 0); err != nil {
+			return v, nil
+		}
+	}
+	for _, warning := range launchWarnings {
+		if warning == pkgCommand.CurrentSlotsExceeded {
+			v.Warnings = append(v.Warnings,
+				"the slots requested exceed the slots currently available in the resource pool")
+		}
+	}
+
+	taskSpec, err := a.m.fillTaskSpec(poolName, agentUserGroup, curUser)
+	if err != nil {
+		return nil, err
+	}
+	config := model.DefaultConfig(&taskSpec.TaskContainerDefaults)
+	if req.TemplateName != "" {
+		if err = templates.UnmarshalTemplateConfig(
+			ctx, req.TemplateName, curUser, &config, false,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if len(configBytes) != 0 {
+		dec := json.NewDecoder(bytes.NewBuffer(configBytes))
+		dec.DisallowUnknownFields()
+		if err = dec.Decode(&config); err != nil {
+			addTaskConfigProblem(v, taskConfigCheckConfig, "", err)
+			return v, nil
+		}
+	}
+	config.Resources.ResourcePool = poolName.String()
+	config.Resources.Slots = resources.Slots
+	if config.Resources.Priority == nil {
+		prio := masterConfig.DefaultPriorityForPool(poolName.String())
+		config.Resources.Priority = &prio
+	}
+
+	bindMounts := config.BindMounts.ToExpconf()
+	violations, err := configpolicy.BindMountViolations(ctx, int(workspaceID), model.NTSCType, bindMounts)
+	if err != nil {
+		return nil, err
+	}
+	mountsOK := true
+	for i, err := range violations {
+		if err != nil {
+			mountsOK = false
+			addTaskConfigProblem(v, taskConfigCheckBindMounts, fmt.Sprintf("bind_mounts[%d]", i), err)
+		}
+	}
+	// The bind mounts are checked again with the rest of the constraints, which stop at the first.
+	if mountsOK {
+		if err = configpolicy.CheckNTSCConstraints(ctx, int(workspaceID), config, a.m.rm); err != nil {
+			addTaskConfigProblem(v, taskConfigCheckPolicy, "", err)
+		}
+	}
+
+	slotTypes := map[string]devicev1.Type{}
+	if pools, err := a.m.rm.GetResourcePools(); err == nil {
+		for _, p := range pools.ResourcePools {
+			slotTypes[p.Name] = p.SlotType
+		}
+	}
+	deviceType := slotDeviceType(slotTypes, poolName.String(), resources.Slots)
+	if image := config.Environment.Image.For(deviceType); image != "" {
+		imageCtx, cancel := context.WithTimeout(ctx, imageCheckTimeout)
+		defer cancel()
+		if _, err = imagedigest.Resolve(
+			imageCtx, egress.Client(), image, config.Environment.RegistryAuth,
+		); err != nil {
+			addTaskConfigProblem(v, taskConfigCheckImage, "environment.image."+string(deviceType), err)
+		}
+	}
+
+	v.Problems = append(v.Problems, checkProxyPorts(taskType, config.Environment.ProxyPorts)...)
+	v.Valid = len(v.Problems) == 0
+	return v, nil
+}
@@ -0,0 +1,28 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+func TestValidateTaskConfigTaskType(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+
+	for _, taskType := range []taskv1.TaskType{
+		taskv1.TaskType_TASK_TYPE_UNSPECIFIED,
+		taskv1.TaskType_TASK_TYPE_TRIAL,
+		taskv1.TaskType_TASK_TYPE_CHECKPOINT_GC,
+	} {
+		_, err := api.ValidateTaskConfig(ctx, &apiv1.ValidateTaskConfigRequest{TaskType: taskType})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), taskType)
+	}
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/devicev1"
+)
+
+func TestCheckProxyPorts(t *testing.T) {
+	ports := model.ProxyPortsConfig{
+		{ProxyPort: 8080},
+		{ProxyPort: 0},
+		{ProxyPort: 8080},
+		{ProxyPort: minNotebookPort + 1},
+		{ProxyPort: maxNotebookPort},
+	}
+
+	var fields []string
+	for _, p := range checkProxyPorts(model.TaskTypeNotebook, ports) {
+		require.Equal(t, taskConfigCheckProxyPorts, p.Check)
+		fields = append(fields, p.GetField())
+	}
+	require.Equal(t, []string{
+		"environment.proxy_ports[1]",
+		"environment.proxy_ports[2]",
+		"environment.proxy_ports[3]",
+	}, fields)
+
+	// Commands don't run a server of their own, so no ports are reserved for them.
+	require.Len(t, checkProxyPorts(model.TaskTypeCommand, ports), 2)
+	require.Empty(t, checkProxyPorts(model.TaskTypeShell, nil))
+}
+
+func TestSlotDeviceType(t *testing.T) {
+	slotTypes := map[string]devicev1.Type{
+		"cpu":  devicev1.Type_TYPE_CPU,
+		"rocm": devicev1.Type_TYPE_ROCM,
+		"cuda": devicev1.Type_TYPE_CUDA,
+	}
+	require.Equal(t, device.CPU, slotDeviceType(slotTypes, "cuda", 0))
+	require.Equal(t, device.CUDA, slotDeviceType(slotTypes, "cuda", 1))
+	require.Equal(t, device.ROCM, slotDeviceType(slotTypes, "rocm", 1))
+	require.Equal(t, device.CPU, slotDeviceType(slotTypes, "cpu", 1))
+	require.Equal(t, device.CUDA, slotDeviceType(slotTypes, "unknown", 1))
+}
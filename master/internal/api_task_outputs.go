@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+// getCommandTask returns the metadata of a command task the current user can see. Only the
+// command's owner, or an admin, may record its outputs.
+func getCommandTask(
+	ctx context.Context, taskID model.TaskID, forWrite bool,
+) (command.TaskMetadata, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return command.TaskMetadata{}, err
+	}
+	notFoundErr := api.NotFoundErrs("task", string(taskID), true)
+	spec, err := command.IdentifyTask(ctx, taskID)
+	if errors.Is(err, db.ErrNotFound) {
+		return spec, notFoundErr
+	} else if err != nil {
+		return spec, err
+	}
+	if spec.TaskType != model.TaskTypeCommand {
+		return spec, status.Error(codes.InvalidArgument, "only commands have outputs")
+	}
+	if err := command.AuthZProvider.Get().CanGetNSC(ctx, *curUser, spec.WorkspaceID); err != nil {
+		return spec, authz.SubIfUnauthorized(err, notFoundErr)
+	}
+	if forWrite && spec.OwnerID != curUser.ID && !curUser.Admin {
+		return spec, status.Error(codes.PermissionDenied,
+			"only the owner of a command can record its outputs")
+	}
+	return spec, nil
+}
+
+func (a *apiServer) GetTaskOutputs(
+	ctx context.Context, req *apiv1.GetTaskOutputsRequest,
+) (*apiv1.GetTaskOutputsResponse, error) {
+	taskID := model.TaskID(req.TaskId)
+	if _, err := getCommandTask(ctx, taskID, false); err != nil {
+		return nil, err
+	}
+	outputs, err := command.TaskOutputsByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetTaskOutputsResponse{Outputs: []*taskv1.TaskOutput{}}
+	for _, o := range outputs {
+		resp.Outputs = append(resp.Outputs, o.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostTaskOutputs(
+	ctx context.Context, req *apiv1.PostTaskOutputsRequest,
+) (*apiv1.PostTaskOutputsResponse, error) {
+	taskID := model.TaskID(req.TaskId)
+	if _, err := getCommandTask(ctx, taskID, true); err != nil {
+		return nil, err
+	}
+
+	prefix := command.OutputsStoragePrefix(taskID)
+	outputs := make([]model.TaskOutput, 0, len(req.Outputs))
+	for _, o := range req.Outputs {
+		if o.Path == "" || !strings.HasPrefix(o.StoragePath, prefix+"/") {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"output %q must be stored under %s", o.Path, prefix)
+		}
+		outputs = append(outputs, model.TaskOutput{
+			TaskID:      taskID,
+			Path:        o.Path,
+			StoragePath: o.StoragePath,
+			SizeBytes:   o.SizeBytes,
+		})
+	}
+	if err := command.RecordTaskOutputs(ctx, outputs); err != nil {
+		return nil, err
+	}
+	return &apiv1.PostTaskOutputsResponse{}, nil
+}
@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestTaskOutputs(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+	taskID := mockOwnedTask(ctx, t, model.TaskTypeCommand, workspaceID, curUser.ID)
+	prefix := command.OutputsStoragePrefix(taskID)
+
+	_, err := api.PostTaskOutputs(ctx, &apiv1.PostTaskOutputsRequest{
+		TaskId: string(taskID),
+		Outputs: []*apiv1.PostTaskOutputsRequest_Output{
+			{Path: "/run/out.txt", StoragePath: prefix + "/out.txt", SizeBytes: 10},
+		},
+	})
+	require.NoError(t, err)
+
+	resp, err := api.GetTaskOutputs(ctx, &apiv1.GetTaskOutputsRequest{TaskId: string(taskID)})
+	require.NoError(t, err)
+	require.Len(t, resp.Outputs, 1)
+	require.Equal(t, "/run/out.txt", resp.Outputs[0].Path)
+	require.Equal(t, int64(10), resp.Outputs[0].SizeBytes)
+
+	// Outputs must be stored under the task's own prefix.
+	_, err = api.PostTaskOutputs(ctx, &apiv1.PostTaskOutputsRequest{
+		TaskId: string(taskID),
+		Outputs: []*apiv1.PostTaskOutputsRequest_Output{
+			{Path: "/run/out.txt", StoragePath: "outputs/other/out.txt"},
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+}
+
+func TestTaskOutputsNotCommand(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+	taskID := mockOwnedTask(ctx, t, model.TaskTypeNotebook, workspaceID, curUser.ID)
+
+	_, err := api.GetTaskOutputs(ctx, &apiv1.GetTaskOutputsRequest{TaskId: string(taskID)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.GetTaskOutputs(ctx, &apiv1.GetTaskOutputsRequest{
+		TaskId: string(model.NewTaskID()),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+// getSharableTask returns the metadata of a notebook or TensorBoard task, checking that the
+// current user owns it or is an admin, since only they may manage who it is shared with.
+func getSharableTask(
+	ctx context.Context, taskID model.TaskID,
+) (command.TaskMetadata, model.User, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return command.TaskMetadata{}, model.User{}, err
+	}
+	notFoundErr := api.NotFoundErrs("task", string(taskID), true)
+	spec, err := command.IdentifyTask(ctx, taskID)
+	if errors.Is(err, db.ErrNotFound) {
+		return spec, *curUser, notFoundErr
+	} else if err != nil {
+		return spec, *curUser, err
+	}
+	if spec.TaskType != model.TaskTypeNotebook && spec.TaskType != model.TaskTypeTensorboard {
+		return spec, *curUser, status.Error(codes.InvalidArgument,
+			"only notebooks and TensorBoards can be shared")
+	}
+	if err := command.AuthZProvider.Get().CanGetNSC(ctx, *curUser, spec.WorkspaceID); err != nil {
+		return spec, *curUser, authz.SubIfUnauthorized(err, notFoundErr)
+	}
+	if spec.OwnerID != curUser.ID && !curUser.Admin {
+		return spec, *curUser, status.Error(codes.PermissionDenied,
+			"only the owner of a task can manage who it is shared with")
+	}
+	return spec, *curUser, nil
+}
+
+func (a *apiServer) GetTaskShares(
+	ctx context.Context, req *apiv1.GetTaskSharesRequest,
+) (*apiv1.GetTaskSharesResponse, error) {
+	if _, _, err := getSharableTask(ctx, model.TaskID(req.TaskId)); err != nil {
+		return nil, err
+	}
+	shares, err := command.ProxySharesByTask(ctx, model.TaskID(req.TaskId))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetTaskSharesResponse{Shares: []*taskv1.TaskProxyShare{}}
+	for _, s := range shares {
+		resp.Shares = append(resp.Shares, s.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PutTaskShare(
+	ctx context.Context, req *apiv1.PutTaskShareRequest,
+) (*apiv1.PutTaskShareResponse, error) {
+	access, err := model.ProxyShareAccessFromProto(req.Access)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	taskID := model.TaskID(req.TaskId)
+	spec, curUser, err := getSharableTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shares are limited to users who could otherwise see the task in its workspace.
+	target, err := user.ByID(ctx, model.UserID(req.UserId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("user", fmt.Sprint(req.UserId), true)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := command.AuthZProvider.Get().CanGetNSC(
+		ctx, target.ToUser(), spec.WorkspaceID,
+	); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"user %s is not a member of the task's workspace", target.Username)
+	}
+
+	share := &model.TaskProxyShare{
+		TaskID:    taskID,
+		UserID:    target.ID,
+		Access:    access,
+		GrantedBy: curUser.ID,
+	}
+	if err := command.UpsertProxyShare(ctx, share); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutTaskShareResponse{Share: share.Proto()}, nil
+}
+
+func (a *apiServer) DeleteTaskShare(
+	ctx context.Context, req *apiv1.DeleteTaskShareRequest,
+) (*apiv1.DeleteTaskShareResponse, error) {
+	taskID := model.TaskID(req.TaskId)
+	if _, _, err := getSharableTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	err := command.DeleteProxyShare(ctx, taskID, model.UserID(req.UserId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("share for user", fmt.Sprint(req.UserId), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteTaskShareResponse{}, nil
+}
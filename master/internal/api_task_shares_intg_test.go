@@ -0,0 +1,75 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+func TestTaskShares(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+	taskID := mockOwnedTask(ctx, t, model.TaskTypeNotebook, workspaceID, curUser.ID)
+	target := db.RequireMockUser(t, api.m.db)
+
+	put, err := api.PutTaskShare(ctx, &apiv1.PutTaskShareRequest{
+		TaskId: string(taskID),
+		UserId: int32(target.ID),
+		Access: taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_READ_ONLY,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(curUser.ID), put.Share.GrantedBy)
+
+	// Sharing again updates the existing share.
+	_, err = api.PutTaskShare(ctx, &apiv1.PutTaskShareRequest{
+		TaskId: string(taskID),
+		UserId: int32(target.ID),
+		Access: taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_FULL,
+	})
+	require.NoError(t, err)
+
+	get, err := api.GetTaskShares(ctx, &apiv1.GetTaskSharesRequest{TaskId: string(taskID)})
+	require.NoError(t, err)
+	require.Len(t, get.Shares, 1)
+	require.Equal(t, taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_FULL, get.Shares[0].Access)
+
+	req := &apiv1.DeleteTaskShareRequest{TaskId: string(taskID), UserId: int32(target.ID)}
+	_, err = api.DeleteTaskShare(ctx, req)
+	require.NoError(t, err)
+	_, err = api.DeleteTaskShare(ctx, req)
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
+
+func TestTaskSharesErrors(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+	taskID := mockOwnedTask(ctx, t, model.TaskTypeNotebook, workspaceID, curUser.ID)
+
+	_, err := api.GetTaskShares(ctx, &apiv1.GetTaskSharesRequest{
+		TaskId: string(model.NewTaskID()),
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.PutTaskShare(ctx, &apiv1.PutTaskShareRequest{
+		TaskId: string(taskID),
+		UserId: int32(curUser.ID),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.PutTaskShare(ctx, &apiv1.PutTaskShareRequest{
+		TaskId: string(taskID),
+		UserId: -1,
+		Access: taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_READ_ONLY,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
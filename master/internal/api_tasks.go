@@ -22,6 +22,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	expauth "github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/logmetrics"
 	"github.com/determined-ai/determined/master/internal/logpattern"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/internal/webhooks"
@@ -130,6 +131,25 @@ func (a *apiServer) canDoActionsOnTask(
 	}
 }
 
+// getTrialTaskExperiment returns the experiment of a trial's task, checking that the current user
+// can do the given actions on it.
+func (a *apiServer) getTrialTaskExperiment(
+	ctx context.Context, taskID model.TaskID,
+	actions ...func(context.Context, model.User, *model.Experiment) error,
+) (*model.Experiment, error) {
+	isExp, exp, err := expFromTaskID(ctx, taskID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("task", string(taskID), true)
+	} else if err != nil {
+		return nil, err
+	}
+	if !isExp {
+		return nil, status.Errorf(codes.InvalidArgument, "task %s is not a trial", taskID)
+	}
+	exp, _, err = a.getExperimentAndCheckCanDoActions(ctx, exp.ID, actions...)
+	return exp, err
+}
+
 func (a *apiServer) canGetTaskAcceleration(ctx context.Context, taskID string) error {
 	curUser, _, err := grpcutil.GetUser(ctx)
 	if err != nil {
@@ -459,6 +479,15 @@ func (a *apiServer) monitor(ctx context.Context, taskID model.TaskID, logs []*mo
 		return err
 	}
 
+	rules, err := logmetrics.ActiveRules(ctx, exp.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := logmetrics.Ingest(ctx, a.m.db, taskID, rules, logs); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -508,7 +537,7 @@ func (a *apiServer) PostTaskLogs(
 	case err != nil && errors.Is(err, context.Canceled):
 		return nil, err
 	case err != nil:
-		log.Errorf("monitor logs against log pattern policies: %s", err)
+		log.Errorf("monitor logs against log pattern policies and log metric rules: %s", err)
 	}
 
 	return &apiv1.PostTaskLogsResponse{}, nil
@@ -607,8 +636,11 @@ func (a *apiServer) GetTasks(
 	return &apiv1.GetTasksResponse{AllocationIdToSummary: pbAllocationIDToSummary}, nil
 }
 
+// taskLogs streams the logs of a task to res. Extra filters beyond those the request can express,
+// such as where a resumed stream left off, may be given.
 func (a *apiServer) taskLogs(
 	ctx context.Context, req *apiv1.TaskLogsRequest, res chan api.BatchResult,
+	extraFilters ...api.Filter,
 ) {
 	taskID := model.TaskID(req.TaskId)
 	filters, err := constructTaskLogsFilters(req)
@@ -618,6 +650,7 @@ func (a *apiServer) taskLogs(
 		)
 		return
 	}
+	filters = append(filters, extraFilters...)
 
 	var followState interface{}
 	var timeSinceLastAuth time.Time
@@ -160,6 +160,36 @@ func mockNotebookWithWorkspaceID(
 	return nb.TaskID
 }
 
+// mockOwnedTask creates a notebook, TensorBoard, shell or command task owned by a user.
+func mockOwnedTask(
+	ctx context.Context, t *testing.T, taskType model.TaskType, workspaceID int,
+	ownerID model.UserID,
+) model.TaskID {
+	tIn := &model.Task{
+		TaskID:   model.NewTaskID(),
+		TaskType: taskType,
+	}
+	require.NoError(t, db.AddTask(ctx, tIn))
+
+	type commandSnapshot struct {
+		bun.BaseModel `bun:"table:command_state"`
+
+		TaskID             model.TaskID   `bun:"task_id"`
+		GenericCommandSpec map[string]any `bun:"generic_command_spec"`
+	}
+	_, err := db.Bun().NewInsert().Model(&commandSnapshot{
+		TaskID: tIn.TaskID,
+		GenericCommandSpec: map[string]any{
+			"TaskType": taskType,
+			"Base":     map[string]any{"Owner": map[string]any{"id": ownerID}},
+			"Metadata": map[string]any{"workspace_id": workspaceID},
+		},
+	}).Exec(ctx)
+	require.NoError(t, err)
+
+	return tIn.TaskID
+}
+
 func TestGetTasksAuthZ(t *testing.T) {
 	var allocations map[model.AllocationID]sproto.AllocationSummary
 
@@ -355,6 +355,12 @@ func (a *apiServer) LaunchTensorboard(
 		case expconf.AzureConfig:
 			logBasePath = "azure://" + c.Container()
 
+		case expconf.SFTPConfig:
+			logBasePath = fmt.Sprintf("sftp://%s%s", c.Host(), filepath.Clean("/"+c.StoragePath()))
+
+		case expconf.HDFSConfig:
+			logBasePath = fmt.Sprintf("hdfs://%s", filepath.Clean("/"+c.StoragePath()))
+
 		case expconf.GCSConfig:
 			prefix := c.Prefix()
 			if prefix != nil {
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/trials"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+func (a *apiServer) GetTrialPriority(
+	ctx context.Context, req *apiv1.GetTrialPriorityRequest,
+) (*apiv1.GetTrialPriorityResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := trials.CanGetTrialsExperimentAndCheckCanDoAction(ctx, int(req.TrialId), curUser,
+		experiment.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+		return nil, err
+	}
+
+	priority, err := trialPriority(ctx, int(req.TrialId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetTrialPriorityResponse{Priority: priority}, nil
+}
+
+func (a *apiServer) PutTrialPriority(
+	ctx context.Context, req *apiv1.PutTrialPriorityRequest,
+) (*apiv1.PutTrialPriorityResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := trials.CanGetTrialsExperimentAndCheckCanDoAction(ctx, int(req.TrialId), curUser,
+		experiment.AuthZProvider.Get().CanEditExperiment); err != nil {
+		return nil, err
+	}
+	eID, rID, err := a.m.db.TrialExperimentAndRequestID(int(req.TrialId))
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := experiment.ExperimentRegistry.Load(eID)
+	if !ok {
+		return nil, status.Error(codes.FailedPrecondition,
+			"the experiment of the trial is not active")
+	}
+	if err = e.SetTrialPriority(experiment.SetTrialPriority{
+		RequestID: rID,
+		Priority:  int(req.Priority),
+	}); err != nil {
+		return nil, err
+	}
+
+	priority, err := trialPriority(ctx, int(req.TrialId))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.PutTrialPriorityResponse{Priority: priority}, nil
+}
+
+// trialPriority returns the priority of a trial, with the default priority if none was set.
+func trialPriority(ctx context.Context, trialID int) (*trialv1.TrialPriority, error) {
+	priority, err := db.TrialPriorityByTrialID(ctx, trialID)
+	if err != nil {
+		return nil, err
+	}
+	if priority == nil {
+		priority = &model.TrialPriority{TrialID: trialID}
+	}
+	return priority.Proto(), nil
+}
@@ -0,0 +1,47 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestTrialPriorityAPI(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+	trial, _ := db.RequireMockTrial(t, api.m.db, exp)
+
+	get, err := api.GetTrialPriority(ctx, &apiv1.GetTrialPriorityRequest{TrialId: int32(trial.ID)})
+	require.NoError(t, err)
+	require.Equal(t, int32(trial.ID), get.Priority.TrialId)
+	require.Equal(t, int32(0), get.Priority.Priority)
+	require.Nil(t, get.Priority.SetBy)
+
+	require.NoError(t, db.SetTrialPriority(ctx, &model.TrialPriority{
+		TrialID:  trial.ID,
+		Priority: 3,
+		SetBy:    model.TrialPrioritySetBySearcher,
+	}))
+	get, err = api.GetTrialPriority(ctx, &apiv1.GetTrialPriorityRequest{TrialId: int32(trial.ID)})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), get.Priority.Priority)
+	require.Equal(t, "searcher", get.Priority.GetSetBy())
+
+	// The mock experiment isn't running, so its trials can't be reprioritized.
+	_, err = api.PutTrialPriority(ctx, &apiv1.PutTrialPriorityRequest{
+		TrialId: int32(trial.ID), Priority: 10,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), err)
+
+	_, err = api.GetTrialPriority(ctx, &apiv1.GetTrialPriorityRequest{TrialId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
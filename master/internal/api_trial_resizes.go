@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+func (a *apiServer) GetTaskResizes(
+	ctx context.Context, req *apiv1.GetTaskResizesRequest,
+) (*apiv1.GetTaskResizesResponse, error) {
+	taskID := model.TaskID(req.TaskId)
+	if _, err := a.getTrialTaskExperiment(ctx, taskID); err != nil {
+		return nil, err
+	}
+	tr, err := db.TrialByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	resizes, err := db.TrialResizesByTrialID(ctx, tr.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetTaskResizesResponse{Resizes: []*trialv1.TrialResize{}}
+	for _, r := range resizes {
+		resp.Resizes = append(resp.Resizes, r.Proto())
+	}
+	return resp, nil
+}
@@ -0,0 +1,34 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestGetTaskResizes(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	exp := db.RequireMockExperiment(t, api.m.db, curUser)
+	trial, task := db.RequireMockTrial(t, api.m.db, exp)
+
+	_, err := db.Bun().NewInsert().Model(&model.TrialResize{
+		TrialID:   trial.ID,
+		FromSlots: 2,
+		ToSlots:   4,
+		Reason:    "free slots in pool",
+	}).Exec(ctx)
+	require.NoError(t, err)
+
+	resp, err := api.GetTaskResizes(ctx, &apiv1.GetTaskResizesRequest{TaskId: string(task.TaskID)})
+	require.NoError(t, err)
+	require.Len(t, resp.Resizes, 1)
+	require.Equal(t, int32(trial.ID), resp.Resizes[0].TrialId)
+	require.Equal(t, int32(4), resp.Resizes[0].ToSlots)
+}
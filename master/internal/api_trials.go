@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -24,8 +25,13 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/metricseries"
+	"github.com/determined-ai/determined/master/internal/metricsexport"
+	"github.com/determined-ai/determined/master/internal/relations"
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/storageusage"
 	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/internal/trialcompare"
 	"github.com/determined-ai/determined/master/internal/trials"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
@@ -33,6 +39,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/protoutils/protoless"
 	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/searcher"
+	"github.com/determined-ai/determined/master/pkg/set"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
 	"github.com/determined-ai/determined/proto/pkg/commonv1"
@@ -74,6 +81,8 @@ type trialAllocation struct {
 	Running  bool
 	Starting bool
 	Task     model.TaskID
+	// StallReason is set when liveness detection found the running allocation stalled.
+	StallReason *string `db:"stall_reason"`
 }
 
 func getLatestTaskIDFromTrialProto(t *trialv1.Trial) model.TaskID {
@@ -104,7 +113,11 @@ func (a *apiServer) enrichTrialState(trials ...*trialv1.Trial) error {
 
 	// Collect state information by TaskID
 	byTaskID := make(map[model.TaskID]trialv1.State, len(tasks))
+	stallReasons := make(map[model.TaskID]string)
 	for _, task := range tasks {
+		if task.StallReason != nil {
+			stallReasons[task.Task] = *task.StallReason
+		}
 		switch {
 		case task.Running:
 			byTaskID[task.Task] = trialv1.State_STATE_RUNNING
@@ -125,6 +138,9 @@ func (a *apiServer) enrichTrialState(trials ...*trialv1.Trial) error {
 			} else {
 				trial.State = trialv1.State_STATE_QUEUED
 			}
+			if reason, ok := stallReasons[getLatestTaskIDFromTrialProto(trial)]; ok {
+				trial.Stalled, trial.StallReason = true, reason
+			}
 		}
 	}
 	return nil
@@ -585,6 +601,10 @@ func (a *apiServer) KillTrial(
 func (a *apiServer) GetExperimentTrials(
 	ctx context.Context, req *apiv1.GetExperimentTrialsRequest,
 ) (resp *apiv1.GetExperimentTrialsResponse, err error) {
+	include, err := relations.Parse(req.Include)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if _, _, err = a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
 		experiment.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
 		return nil, err
@@ -629,6 +649,8 @@ func (a *apiServer) GetExperimentTrials(
 	default:
 		orderExpr = fmt.Sprintf("id %s", sortByMap[req.OrderBy])
 	}
+	// Pinned trials are listed first, in the order they are pinned in, whatever the sort order.
+	orderExpr = "pin_position ASC NULLS LAST, " + orderExpr
 
 	resp = &apiv1.GetExperimentTrialsResponse{}
 	if err = a.m.db.QueryProtof(
@@ -671,6 +693,24 @@ func (a *apiServer) GetExperimentTrials(
 		return nil, err
 	}
 
+	ids := make([]int, 0, len(resp.Trials))
+	for _, t := range resp.Trials {
+		ids = append(ids, int(t.Id))
+	}
+	owners, err := relations.Trials(ctx, ids, include)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range resp.Trials {
+		if u := owners[int(t.Id)]; u != nil {
+			t.UserId = int32(u.ID)
+			t.Username = u.Username
+			if u.DisplayName != nil {
+				t.DisplayName = *u.DisplayName
+			}
+		}
+	}
+
 	return resp, nil
 }
 
@@ -842,7 +882,7 @@ func (a *apiServer) parseMetricGroupArgs(
 func (a *apiServer) multiTrialSample(trialID int32, metricNames []string,
 	metricGroup model.MetricGroup, maxDatapoints int, startBatches int,
 	endBatches int, timeSeriesFilter *commonv1.PolymorphicFilter,
-	metricIds []string,
+	metricIds []string, smoothing float64, tier apiv1.MetricSeriesTier,
 ) ([]*apiv1.DownsampledMetrics, error) {
 	var startTime time.Time
 	var metrics []*apiv1.DownsampledMetrics
@@ -862,6 +902,12 @@ func (a *apiServer) multiTrialSample(trialID int32, metricNames []string,
 	if maxDatapoints == 0 {
 		maxDatapoints = 200
 	}
+	if err := metricseries.ValidateOptions(smoothing, 0); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if tier == apiv1.MetricSeriesTier_METRIC_SERIES_TIER_UNSPECIFIED {
+		tier = apiv1.MetricSeriesTier_METRIC_SERIES_TIER_SAMPLED
+	}
 
 	var timeSeriesColumn *string
 
@@ -895,13 +941,32 @@ func (a *apiServer) multiTrialSample(trialID int32, metricNames []string,
 	getDownSampledMetric := func(aMetricNames []string, aMetricGroup model.MetricGroup,
 	) (*apiv1.DownsampledMetrics, error) {
 		var metric apiv1.DownsampledMetrics
+		aTier := tier
+		if aTier == apiv1.MetricSeriesTier_METRIC_SERIES_TIER_AUTO {
+			reports, err := metricseries.ReportCount(context.TODO(), int(trialID), aMetricGroup)
+			if err != nil {
+				return nil, err
+			}
+			aTier = metricseries.Resolve(aTier, reports)
+		}
+		// The exact tier reads every report, which is reduced to maxDatapoints below.
+		limit := maxDatapoints
+		if aTier == apiv1.MetricSeriesTier_METRIC_SERIES_TIER_EXACT {
+			limit = 0
+		}
 		metricMeasurements, err := trials.MetricsTimeSeries(
 			trialID, startTime, aMetricNames, startBatches, endBatches,
-			maxDatapoints, *timeSeriesColumn, timeSeriesFilter, aMetricGroup)
+			limit, *timeSeriesColumn, timeSeriesFilter, aMetricGroup)
 		if err != nil {
 			return nil, errors.Wrapf(err, fmt.Sprintf("error fetching time series of %s metrics",
 				aMetricGroup))
 		}
+		kept := metricseries.Reduce(metricseries.Measurements(metricMeasurements),
+			smoothing, maxDatapoints)
+		for i, j := range kept {
+			metricMeasurements[i] = metricMeasurements[j]
+		}
+		metricMeasurements = metricMeasurements[:len(kept)]
 		//nolint:staticcheck // SA1019: backward compatibility
 		metric.Type = aMetricGroup.ToProto()
 		metric.Group = aMetricGroup.ToString()
@@ -974,7 +1039,7 @@ func (a *apiServer) CompareTrials(ctx context.Context,
 
 		tsample, err := a.multiTrialSample(trialObj.Id, req.MetricNames, metricGroup,
 			int(req.MaxDatapoints), int(req.StartBatches), int(req.EndBatches),
-			req.TimeSeriesFilter, req.MetricIds)
+			req.TimeSeriesFilter, req.MetricIds, req.Smoothing, req.Tier)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed sampling")
 		}
@@ -984,20 +1049,190 @@ func (a *apiServer) CompareTrials(ctx context.Context,
 	return &apiv1.CompareTrialsResponse{Trials: trialsList}, nil
 }
 
+func (a *apiServer) CompareTrialMetrics(
+	ctx context.Context, req *apiv1.CompareTrialMetricsRequest,
+) (*apiv1.CompareTrialMetricsResponse, error) {
+	switch {
+	case req.MetricName == "":
+		return nil, status.Error(codes.InvalidArgument, "metric_name must be set")
+	case len(req.GroupA) == 0 || len(req.GroupB) == 0:
+		return nil, status.Error(codes.InvalidArgument, "group_a and group_b must have trials")
+	case len(req.GroupA) > trialcompare.MaxTrials || len(req.GroupB) > trialcompare.MaxTrials:
+		return nil, status.Errorf(codes.InvalidArgument,
+			"groups can have at most %d trials", trialcompare.MaxTrials)
+	}
+
+	trialIDs := set.New[int]()
+	for _, id := range append(slices.Clone(req.GroupA), req.GroupB...) {
+		trialIDs.Insert(int(id))
+	}
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = trials.CanGetTrialsExperimentAndCheckCanDoActionBulk(ctx, trialIDs.ToSlice(), curUser,
+		experiment.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+		return nil, err
+	}
+
+	values, err := trialcompare.FinalValues(ctx, req.MetricName, trialIDs.ToSlice())
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]trialcompare.TrialValue, len(values))
+	for _, v := range values {
+		byID[v.TrialID] = v
+	}
+	group := func(ids []int32) ([]trialcompare.TrialValue, error) {
+		res := make([]trialcompare.TrialValue, 0, len(ids))
+		for _, id := range ids {
+			v, ok := byID[int(id)]
+			if !ok {
+				return nil, api.NotFoundErrs("trial", strconv.Itoa(int(id)), true)
+			}
+			res = append(res, v)
+		}
+		return res, nil
+	}
+	groupA, err := group(req.GroupA)
+	if err != nil {
+		return nil, err
+	}
+	groupB, err := group(req.GroupB)
+	if err != nil {
+		return nil, err
+	}
+
+	c := trialcompare.Compare(req.MetricName, groupA, groupB)
+	resp := &apiv1.CompareTrialMetricsResponse{
+		MetricName:     c.MetricName,
+		GroupA:         trialMetricGroupSummaryToProto(c.GroupA),
+		GroupB:         trialMetricGroupSummaryToProto(c.GroupB),
+		MeanDifference: c.MeanDifference,
+	}
+	if c.TTest != nil {
+		resp.TTest = &apiv1.TrialMetricTTest{
+			Statistic:        c.TTest.Statistic,
+			DegreesOfFreedom: c.TTest.DegreesOfFreedom,
+			PValue:           c.TTest.PValue,
+		}
+	}
+	if c.MannWhitneyU != nil {
+		resp.MannWhitneyU = &apiv1.TrialMetricMannWhitneyU{
+			Statistic: c.MannWhitneyU.Statistic,
+			Z:         c.MannWhitneyU.Z,
+			PValue:    c.MannWhitneyU.PValue,
+		}
+	}
+	return resp, nil
+}
+
+func trialMetricGroupSummaryToProto(s trialcompare.Summary) *apiv1.TrialMetricGroupSummary {
+	toInt32s := func(ids []int) []int32 {
+		res := make([]int32, 0, len(ids))
+		for _, id := range ids {
+			res = append(res, int32(id))
+		}
+		return res
+	}
+	return &apiv1.TrialMetricGroupSummary{
+		TrialIds:        toInt32s(s.TrialIDs),
+		MissingTrialIds: toInt32s(s.MissingTrialIDs),
+		Count:           int32(s.Count),
+		Mean:            s.Mean,
+		StdDev:          s.StdDev,
+		Min:             s.Min,
+		Median:          s.Median,
+		Max:             s.Max,
+	}
+}
+
 func (a *apiServer) GetMetrics(
 	req *apiv1.GetMetricsRequest, resp apiv1.Determined_GetMetricsServer,
 ) error {
 	sendFunc := func(m []*trialv1.MetricsReport) error {
 		return resp.Send(&apiv1.GetMetricsResponse{Metrics: m})
 	}
-	if err := a.streamMetrics(resp.Context(), req.TrialIds, sendFunc,
-		model.MetricGroup(req.Group)); err != nil {
-		return err
+	if err := metricseries.ValidateOptions(req.Smoothing, int(req.MaxPoints)); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Smoothing == 0 && req.MaxPoints == 0 &&
+		(req.Tier == apiv1.MetricSeriesTier_METRIC_SERIES_TIER_UNSPECIFIED ||
+			req.Tier == apiv1.MetricSeriesTier_METRIC_SERIES_TIER_EXACT) {
+		return a.streamMetrics(resp.Context(), req.TrialIds, sendFunc, model.MetricGroup(req.Group))
 	}
 
+	// Each trial's reports are read whole to be smoothed and reduced, and sent at once.
+	trialIDs, err := a.checkCanGetTrialsMetrics(resp.Context(), req.TrialIds)
+	if err != nil {
+		return err
+	}
+	for _, trialID := range trialIDs {
+		reports, err := a.readMetricSeries(resp.Context(), int(trialID), model.MetricGroup(req.Group),
+			req.Smoothing, int(req.MaxPoints), req.Tier)
+		if err != nil {
+			return err
+		}
+		if len(reports) > 0 {
+			if err := sendFunc(reports); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// readMetricSeries returns the reports of a metric group of a trial read with tier, smoothed and
+// reduced to at most maxPoints reports.
+func (a *apiServer) readMetricSeries(ctx context.Context, trialID int, group model.MetricGroup,
+	smoothing float64, maxPoints int, tier apiv1.MetricSeriesTier,
+) ([]*trialv1.MetricsReport, error) {
+	switch tier {
+	case apiv1.MetricSeriesTier_METRIC_SERIES_TIER_UNSPECIFIED:
+		tier = apiv1.MetricSeriesTier_METRIC_SERIES_TIER_EXACT
+	case apiv1.MetricSeriesTier_METRIC_SERIES_TIER_AUTO:
+		count, err := metricseries.ReportCount(ctx, trialID, group)
+		if err != nil {
+			return nil, err
+		}
+		tier = metricseries.Resolve(tier, count)
+	}
+
+	var reports []*trialv1.MetricsReport
+	if tier == apiv1.MetricSeriesTier_METRIC_SERIES_TIER_SAMPLED {
+		limit := maxPoints
+		if limit == 0 {
+			limit = metricseries.DefaultSampledPoints
+		}
+		sample, err := metricseries.SampleReports(ctx, trialID, group, limit)
+		if err != nil {
+			return nil, err
+		}
+		reports = sample
+	} else {
+		mGroup := group.ToString()
+		for key := -1; ; {
+			res, err := db.GetMetrics(ctx, trialID, key, streamMetricsPageSize, &mGroup)
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, res...)
+			if len(res) != streamMetricsPageSize {
+				break
+			}
+			key = int(res[len(res)-1].TotalBatches)
+		}
+	}
+
+	kept := metricseries.Reduce(
+		metricseries.MetricsReports{Reports: reports, Group: group}, smoothing, maxPoints)
+	out := make([]*trialv1.MetricsReport, len(kept))
+	for i, j := range kept {
+		out[i] = reports[j]
+	}
+	return out, nil
+}
+
 func (a *apiServer) GetTrainingMetrics(
 	req *apiv1.GetTrainingMetricsRequest, resp apiv1.Determined_GetTrainingMetricsServer,
 ) error {
@@ -1026,38 +1261,50 @@ func (a *apiServer) GetValidationMetrics(
 	return nil
 }
 
-func (a *apiServer) streamMetrics(ctx context.Context,
-	trialIDs []int32, sendFunc func(m []*trialv1.MetricsReport) error, metricGroup model.MetricGroup,
-) error {
+// checkCanGetTrialsMetrics validates the trials to get metrics of, checks the user can view their
+// metrics, and returns them sorted.
+func (a *apiServer) checkCanGetTrialsMetrics(ctx context.Context, trialIDs []int32) ([]int32, error) {
 	if len(trialIDs) == 0 {
-		return status.Error(codes.InvalidArgument, "must specify at least one trialId")
+		return nil, status.Error(codes.InvalidArgument, "must specify at least one trialId")
 	}
 	ids := make(map[int32]bool)
 	for _, id := range trialIDs {
 		if ids[id] {
-			return status.Errorf(codes.InvalidArgument, "duplicate id=%d specified", id)
+			return nil, status.Errorf(codes.InvalidArgument, "duplicate id=%d specified", id)
 		}
 	}
 	slices.Sort(trialIDs)
 
 	curUser, _, err := grpcutil.GetUser(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	for _, trialID := range trialIDs {
 		if err := trials.CanGetTrialsExperimentAndCheckCanDoAction(ctx, int(trialID), curUser,
 			experiment.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
-			return err
+			return nil, err
 		}
 	}
+	return trialIDs, nil
+}
 
-	const size = 1000
+// streamMetricsPageSize is how many reports of a trial streamMetrics sends at once.
+const streamMetricsPageSize = 1000
+
+func (a *apiServer) streamMetrics(ctx context.Context,
+	trialIDs []int32, sendFunc func(m []*trialv1.MetricsReport) error, metricGroup model.MetricGroup,
+) error {
+	trialIDs, err := a.checkCanGetTrialsMetrics(ctx, trialIDs)
+	if err != nil {
+		return err
+	}
 
 	trialIDIndex := 0
 	key := -1
 	mGroup := metricGroup.ToString()
 	for {
-		res, err := db.GetMetrics(ctx, int(trialIDs[trialIDIndex]), key, size, &mGroup)
+		res, err := db.GetMetrics(ctx, int(trialIDs[trialIDIndex]), key, streamMetricsPageSize,
+			&mGroup)
 		if err != nil {
 			return err
 		}
@@ -1071,7 +1318,7 @@ func (a *apiServer) streamMetrics(ctx context.Context,
 			key = int(res[len(res)-1].TotalBatches)
 		}
 
-		if len(res) != size {
+		if len(res) != streamMetricsPageSize {
 			trialIDIndex++
 			if trialIDIndex >= len(trialIDs) {
 				break
@@ -1464,7 +1711,8 @@ func (a *apiServer) ReportTrialMetrics(
 		if ok {
 			// Report validation metrics to the searcher. Skip for experiments (such as detached mode)
 			// that are not already loaded in the master ExperimentRegistry.
-			err = e.TrialReportValidation(rID, req.Metrics.Metrics.AvgMetrics.AsMap())
+			err = e.TrialReportValidation(
+				rID, int(req.Metrics.GetStepsCompleted()), req.Metrics.Metrics.AvgMetrics.AsMap())
 			if err != nil {
 				return nil, err
 			}
@@ -1473,6 +1721,10 @@ func (a *apiServer) ReportTrialMetrics(
 	if err := a.m.db.AddTrialMetrics(ctx, req.Metrics, metricGroup); err != nil {
 		return nil, err
 	}
+	if err := metricsexport.ReportMetrics(ctx, int(req.Metrics.TrialId), metricGroup,
+		int(req.Metrics.GetStepsCompleted()), req.Metrics.Metrics.AvgMetrics.AsMap()); err != nil {
+		log.WithError(err).Errorf("failed to queue metrics export for trial %d", req.Metrics.TrialId)
+	}
 	return &apiv1.ReportTrialMetricsResponse{}, nil
 }
 
@@ -1529,9 +1781,20 @@ func (a *apiServer) ReportCheckpoint(
 		return nil, fmt.Errorf("getting trial by task ID: %w", err)
 	}
 
+	if err := storageusage.CheckCanWrite(ctx, trial.ExperimentID); errors.Is(
+		err, storageusage.ErrHardQuotaExceeded,
+	) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
 	if err := db.AddCheckpointMetadata(ctx, c, trial.ID); err != nil {
 		return nil, err
 	}
+	if err := storageusage.CheckQuotas(ctx, []uuid.UUID{c.UUID}); err != nil {
+		log.WithError(err).Error("checking workspace storage quotas")
+	}
 
 	return &apiv1.ReportCheckpointResponse{}, nil
 }
@@ -292,7 +292,7 @@ func TestMultiTrialSampleSpecialMetrics(t *testing.T) {
 	actualMetrics, err := api.multiTrialSample(int32(trial.ID), []string{},
 		"", maxDataPoints, 0, 10, nil, []string{
 			"mygroup.zgroup_b/me.t r%i]\\c_1",
-		})
+		}, 0, apiv1.MetricSeriesTier_METRIC_SERIES_TIER_UNSPECIFIED)
 	require.Len(t, actualMetrics, 1)
 	require.NoError(t, err)
 	mygroup := actualMetrics[0]
@@ -317,7 +317,8 @@ func TestMultiTrialSampleMetrics(t *testing.T) {
 		metricIds = append(metricIds, "training."+metricName)
 	}
 	actualTrainingMetrics, err := api.multiTrialSample(int32(trial.ID), trainMetricNames,
-		model.TrainingMetricGroup, maxDataPoints, 0, 10, nil, []string{})
+		model.TrainingMetricGroup, maxDataPoints, 0, 10, nil, []string{}, 0,
+		apiv1.MetricSeriesTier_METRIC_SERIES_TIER_UNSPECIFIED)
 	require.NoError(t, err)
 	require.Len(t, actualTrainingMetrics, 1)
 
@@ -328,7 +329,7 @@ func TestMultiTrialSampleMetrics(t *testing.T) {
 	}
 	actualValidationTrainingMetrics, err := api.multiTrialSample(int32(trial.ID),
 		validationMetricNames, model.ValidationMetricGroup, maxDataPoints,
-		0, 10, nil, []string{})
+		0, 10, nil, []string{}, 0, apiv1.MetricSeriesTier_METRIC_SERIES_TIER_UNSPECIFIED)
 	require.Len(t, actualValidationTrainingMetrics, 1)
 	require.NoError(t, err)
 
@@ -339,7 +340,7 @@ func TestMultiTrialSampleMetrics(t *testing.T) {
 	}
 	actualGenericTrainingMetrics, err := api.multiTrialSample(int32(trial.ID),
 		genericMetricNames, model.MetricGroup("mygroup"), maxDataPoints,
-		0, 10, nil, []string{})
+		0, 10, nil, []string{}, 0, apiv1.MetricSeriesTier_METRIC_SERIES_TIER_UNSPECIFIED)
 	require.Len(t, actualGenericTrainingMetrics, 1)
 	require.NoError(t, err)
 
@@ -347,7 +348,8 @@ func TestMultiTrialSampleMetrics(t *testing.T) {
 	require.True(t, isMultiTrialSampleCorrect(expectedValMetrics, actualValidationTrainingMetrics[0]))
 
 	actualAllMetrics, err := api.multiTrialSample(int32(trial.ID), []string{},
-		"", maxDataPoints, 0, 10, nil, metricIds)
+		"", maxDataPoints, 0, 10, nil, metricIds, 0,
+		apiv1.MetricSeriesTier_METRIC_SERIES_TIER_UNSPECIFIED)
 	require.Len(t, actualAllMetrics, 3)
 	require.NoError(t, err)
 	require.Len(t, actualAllMetrics[1].Data, maxDataPoints) // max datapoints check
@@ -356,6 +358,67 @@ func TestMultiTrialSampleMetrics(t *testing.T) {
 	require.True(t, isMultiTrialSampleCorrect(expectedValMetrics, actualAllMetrics[2]))
 }
 
+func TestMultiTrialSampleSeries(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	trial, _ := createTestTrialWithMetrics(ctx, t, api, curUser, false)
+
+	_, err := api.multiTrialSample(int32(trial.ID), []string{"loss"}, model.TrainingMetricGroup,
+		4, 0, 10, nil, nil, 1, apiv1.MetricSeriesTier_METRIC_SERIES_TIER_EXACT)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The exact tier reads all 10 reports, smooths them, and keeps the first and last.
+	metrics, err := api.multiTrialSample(int32(trial.ID), []string{"loss"},
+		model.TrainingMetricGroup, 4, 0, 10, nil, nil, 0.5,
+		apiv1.MetricSeriesTier_METRIC_SERIES_TIER_EXACT)
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	data := metrics[0].Data
+	require.Len(t, data, 4)
+	require.Equal(t, int32(0), data[0].Batches)
+	require.Equal(t, int32(9), data[3].Batches)
+	require.InDelta(t, 0, data[0].Values.AsMap()["loss"], 1e-9)
+	// Smoothing pulls the last loss towards the earlier, smaller losses.
+	require.Less(t, data[3].Values.AsMap()["loss"], 9.0)
+	require.Greater(t, data[3].Values.AsMap()["loss"], 8.0)
+}
+
+func TestGetMetricsSeries(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	trial, _ := createTestTrialWithMetrics(ctx, t, api, curUser, false)
+
+	getMetrics := func(req *apiv1.GetMetricsRequest) ([]*apiv1.GetMetricsResponse, error) {
+		req.TrialIds = []int32{int32(trial.ID)}
+		req.Group = model.TrainingMetricGroup.ToString()
+		res := &mockStream[*apiv1.GetMetricsResponse]{ctx: ctx}
+		err := api.GetMetrics(req, res)
+		return res.getData(), err
+	}
+
+	_, err := getMetrics(&apiv1.GetMetricsRequest{MaxPoints: 2})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	resps, err := getMetrics(&apiv1.GetMetricsRequest{MaxPoints: 3, Smoothing: 0.5})
+	require.NoError(t, err)
+	require.Len(t, resps, 1)
+	reports := resps[0].Metrics
+	require.Len(t, reports, 3)
+	require.Equal(t, int32(0), reports[0].TotalBatches)
+	require.Equal(t, int32(9), reports[2].TotalBatches)
+	last := reports[2].Metrics.AsMap()["avg_metrics"].(map[string]any)
+	require.Less(t, last["loss"], 9.0)
+	require.Equal(t, "random_text", last["textMetric"])
+
+	resps, err = getMetrics(&apiv1.GetMetricsRequest{
+		MaxPoints: 5, Tier: apiv1.MetricSeriesTier_METRIC_SERIES_TIER_SAMPLED,
+	})
+	require.NoError(t, err)
+	require.Len(t, resps, 1)
+	require.Len(t, resps[0].Metrics, 5)
+	for i := 1; i < 5; i++ {
+		require.Greater(t, resps[0].Metrics[i].TotalBatches, resps[0].Metrics[i-1].TotalBatches)
+	}
+}
+
 func TestStreamTrainingMetrics(t *testing.T) {
 	api, curUser, ctx := setupAPITest(t, nil)
 
@@ -1185,6 +1248,45 @@ func compareTrialsResponseToBatches(resp *apiv1.CompareTrialsResponse) []int32 {
 	return sampleBatches
 }
 
+func TestCompareTrialMetrics(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+
+	var ids []int32
+	for _, acc := range []float64{0.5, 0.75} {
+		trial, _ := createTestTrial(t, api, curUser)
+		metrics, err := structpb.NewStruct(map[string]any{"acc": acc})
+		require.NoError(t, err)
+		_, err = api.ReportTrialMetrics(ctx, &apiv1.ReportTrialMetricsRequest{
+			Metrics: &trialv1.TrialMetrics{
+				TrialId:        int32(trial.ID),
+				StepsCompleted: ptrs.Ptr(int32(1)),
+				Metrics:        &commonv1.Metrics{AvgMetrics: metrics},
+			},
+			Group: model.ValidationMetricGroup.ToString(),
+		})
+		require.NoError(t, err)
+		ids = append(ids, int32(trial.ID))
+	}
+
+	_, err := api.CompareTrialMetrics(ctx, &apiv1.CompareTrialMetricsRequest{
+		MetricName: "acc", GroupA: ids[:1],
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	_, err = api.CompareTrialMetrics(ctx, &apiv1.CompareTrialMetricsRequest{
+		MetricName: "acc", GroupA: ids[:1], GroupB: []int32{-1},
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	resp, err := api.CompareTrialMetrics(ctx, &apiv1.CompareTrialMetricsRequest{
+		MetricName: "acc", GroupA: ids[:1], GroupB: ids[1:],
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), resp.GroupA.Count)
+	require.InDelta(t, 0.25, resp.GetMeanDifference(), 1e-9)
+	// One value per group is too few for the tests.
+	require.Nil(t, resp.TTest)
+}
+
 func TestCompareTrialsSampling(t *testing.T) {
 	api, curUser, ctx := setupAPITest(t, nil)
 
@@ -1580,3 +1682,25 @@ func TestRunLocalID(t *testing.T) {
 		require.Equal(t, i, maxLocalID)
 	}
 }
+
+func TestGetExperimentTrialsIncludeOwner(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	trial, _ := createTestTrial(t, api, curUser)
+
+	req := &apiv1.GetExperimentTrialsRequest{ExperimentId: int32(trial.ExperimentID)}
+	resp, err := api.GetExperimentTrials(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Trials, 1)
+	require.Zero(t, resp.Trials[0].UserId)
+
+	req.Include = []string{"owner"}
+	resp, err = api.GetExperimentTrials(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Trials, 1)
+	require.Equal(t, int32(curUser.ID), resp.Trials[0].UserId)
+	require.Equal(t, curUser.Username, resp.Trials[0].Username)
+
+	req.Include = []string{"hyperparameters"}
+	_, err = api.GetExperimentTrials(ctx, req)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
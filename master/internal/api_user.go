@@ -632,24 +632,85 @@ func (a *apiServer) PostUserSetting(
 	if err != nil {
 		return nil, err
 	}
-	settingsModel := make([]*model.UserWebSetting, 0, len(req.Settings))
-	for _, setting := range req.Settings {
-		settingsModel = append(settingsModel, &model.UserWebSetting{
-			UserID:      curUser.ID,
-			Key:         setting.Key,
-			Value:       setting.Value,
-			StoragePath: setting.StoragePath,
-		})
-	}
+	settingsModel := userSettingsFromProto(curUser.ID, req.Settings)
 	if err = user.AuthZProvider.Get().CanCreateUsersOwnSetting(
 		ctx, *curUser, settingsModel); err != nil {
 		return nil, status.Error(codes.PermissionDenied, err.Error())
 	}
+	for _, setting := range settingsModel {
+		if err = user.ValidateUserSetting(setting); err != nil {
+			return nil, err
+		}
+	}
 
 	err = user.UpdateUserSetting(ctx, settingsModel)
 	return &apiv1.PostUserSettingResponse{}, err
 }
 
+// userSettingExportVersion is the version of the format user settings are exported in.
+const userSettingExportVersion = 1
+
+func (a *apiServer) ExportUserSetting(
+	ctx context.Context, req *apiv1.ExportUserSettingRequest,
+) (*apiv1.ExportUserSettingResponse, error) {
+	res, err := a.GetUserSetting(ctx, &apiv1.GetUserSettingRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.ExportUserSettingResponse{
+		Version:  userSettingExportVersion,
+		Settings: res.Settings,
+	}, nil
+}
+
+func (a *apiServer) ImportUserSetting(
+	ctx context.Context, req *apiv1.ImportUserSettingRequest,
+) (*apiv1.ImportUserSettingResponse, error) {
+	if req.Version != userSettingExportVersion {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unsupported settings export version %d, must be %d",
+			req.Version, userSettingExportVersion)
+	}
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settingsModel := userSettingsFromProto(curUser.ID, req.Settings)
+	if err = user.AuthZProvider.Get().CanCreateUsersOwnSetting(
+		ctx, *curUser, settingsModel); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	for _, setting := range settingsModel {
+		if err = user.ValidateUserSetting(setting); err != nil {
+			return nil, err
+		}
+	}
+	if req.Replace {
+		if err = user.AuthZProvider.Get().CanResetUsersOwnSettings(ctx, *curUser); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+
+	err = user.ImportUserSetting(ctx, curUser.ID, settingsModel, req.Replace)
+	return &apiv1.ImportUserSettingResponse{}, err
+}
+
+func userSettingsFromProto(
+	userID model.UserID, settings []*userv1.UserWebSetting,
+) []*model.UserWebSetting {
+	settingsModel := make([]*model.UserWebSetting, 0, len(settings))
+	for _, setting := range settings {
+		settingsModel = append(settingsModel, &model.UserWebSetting{
+			UserID:      userID,
+			Key:         setting.Key,
+			Value:       setting.Value,
+			StoragePath: setting.StoragePath,
+		})
+	}
+	return settingsModel
+}
+
 func (a *apiServer) ResetUserSetting(
 	ctx context.Context, req *apiv1.ResetUserSettingRequest,
 ) (*apiv1.ResetUserSettingResponse, error) {
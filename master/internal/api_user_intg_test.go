@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -905,6 +906,123 @@ func TestAuthzResetUserSetting(t *testing.T) {
 	require.Equal(t, expectedErr.Error(), err.Error())
 }
 
+func TestAuthzImportUserSetting(t *testing.T) {
+	api, authzUsers, curUser, ctx := setupUserAuthzTest(t, nil)
+	settings := []*model.UserWebSetting{{UserID: curUser.ID, Key: "k", Value: `"v"`}}
+	req := &apiv1.ImportUserSettingRequest{
+		Version:  1,
+		Settings: []*userv1.UserWebSetting{{Key: "k", Value: `"v"`}},
+		Replace:  true,
+	}
+
+	expectedErr := status.Error(codes.PermissionDenied, "canCreateUsersOwnSetting")
+	authzUsers.On("CanCreateUsersOwnSetting", mock.Anything, curUser, settings).
+		Return(fmt.Errorf("canCreateUsersOwnSetting")).Once()
+	_, err := api.ImportUserSetting(ctx, req)
+	require.Equal(t, expectedErr.Error(), err.Error())
+
+	// Replacing settings also deletes them, so it needs permission to reset them.
+	expectedErr = status.Error(codes.PermissionDenied, "canResetUsersOwnSettings")
+	authzUsers.On("CanCreateUsersOwnSetting", mock.Anything, curUser, settings).
+		Return(nil).Once()
+	authzUsers.On("CanResetUsersOwnSettings", mock.Anything, curUser).
+		Return(fmt.Errorf("canResetUsersOwnSettings")).Once()
+	_, err = api.ImportUserSetting(ctx, req)
+	require.Equal(t, expectedErr.Error(), err.Error())
+}
+
+func TestUserSettingLimits(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	resetUserSetting := func() {
+		_, err := api.ResetUserSetting(ctx, &apiv1.ResetUserSettingRequest{})
+		require.NoError(t, err)
+	}
+	resetUserSetting()
+	t.Cleanup(resetUserSetting)
+
+	for _, setting := range []*userv1.UserWebSetting{
+		{Key: "", Value: `"v"`},
+		{Key: strings.Repeat("k", user.MaxSettingKeyLength+1), Value: `"v"`},
+		{Key: "k", StoragePath: strings.Repeat("p", user.MaxSettingKeyLength+1), Value: `"v"`},
+		{Key: "k", Value: `"` + strings.Repeat("v", user.MaxSettingValueBytes) + `"`},
+		{Key: "k", Value: "not json"},
+	} {
+		_, err := api.PostUserSetting(ctx, &apiv1.PostUserSettingRequest{
+			Settings: []*userv1.UserWebSetting{setting},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	}
+
+	// Too many settings are rejected without saving any of them.
+	var settings []*userv1.UserWebSetting
+	for i := 0; i <= user.MaxSettings; i++ {
+		settings = append(settings, &userv1.UserWebSetting{Key: fmt.Sprint(i), Value: "1"})
+	}
+	_, err := api.PostUserSetting(ctx, &apiv1.PostUserSettingRequest{Settings: settings})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+	res, err := api.GetUserSetting(ctx, &apiv1.GetUserSettingRequest{})
+	require.NoError(t, err)
+	require.Empty(t, res.Settings)
+
+	_, err = api.PostUserSetting(ctx, &apiv1.PostUserSettingRequest{
+		Settings: settings[:user.MaxSettings],
+	})
+	require.NoError(t, err)
+	_, err = api.ImportUserSetting(ctx, &apiv1.ImportUserSettingRequest{
+		Version: 1, Settings: settings[user.MaxSettings:],
+	})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+}
+
+func TestExportImportUserSetting(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	resetUserSetting := func() {
+		_, err := api.ResetUserSetting(ctx, &apiv1.ResetUserSettingRequest{})
+		require.NoError(t, err)
+	}
+	resetUserSetting()
+	t.Cleanup(resetUserSetting)
+
+	_, err := api.PostUserSetting(ctx, &apiv1.PostUserSettingRequest{
+		Settings: []*userv1.UserWebSetting{
+			{Key: "theme", StoragePath: "settings", Value: `"dark"`},
+			{Key: "columns", StoragePath: "experiments", Value: `["id","name"]`},
+		},
+	})
+	require.NoError(t, err)
+	export, err := api.ExportUserSetting(ctx, &apiv1.ExportUserSettingRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), export.Version)
+	require.Len(t, export.Settings, 2)
+
+	_, err = api.ImportUserSetting(ctx, &apiv1.ImportUserSettingRequest{
+		Version: 2, Settings: export.Settings,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	// Importing without replacing keeps the settings that aren't imported.
+	_, err = api.ImportUserSetting(ctx, &apiv1.ImportUserSettingRequest{
+		Version:  1,
+		Settings: []*userv1.UserWebSetting{{Key: "theme", StoragePath: "settings", Value: `"light"`}},
+	})
+	require.NoError(t, err)
+	res, err := api.GetUserSetting(ctx, &apiv1.GetUserSettingRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Settings, 2)
+
+	// Replacing deletes them.
+	_, err = api.ImportUserSetting(ctx, &apiv1.ImportUserSettingRequest{
+		Version:  1,
+		Settings: []*userv1.UserWebSetting{{Key: "theme", StoragePath: "settings", Value: `"light"`}},
+		Replace:  true,
+	})
+	require.NoError(t, err)
+	res, err = api.GetUserSetting(ctx, &apiv1.GetUserSettingRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Settings, 1)
+	require.Equal(t, `"light"`, res.Settings[0].Value)
+}
+
 func TestPostUserActivity(t *testing.T) {
 	api, _, curUser, ctx := setupUserAuthzTest(t, nil)
 
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/command"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/rbac"
+	"github.com/determined-ai/determined/master/internal/reassignment"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/usergroup"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+func (a *apiServer) PostUserReassignment(
+	ctx context.Context, req *apiv1.PostUserReassignmentRequest,
+) (*apiv1.PostUserReassignmentResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := user.ByID(ctx, model.UserID(req.UserId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("user", strconv.Itoa(int(req.UserId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	// Reassigning is how a user is prepared for deactivation, so it takes the same permission.
+	err = user.AuthZProvider.Get().CanSetUsersActive(ctx, *curUser, from.ToUser(), false)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if req.ToUserId == req.UserId {
+		return nil, status.Error(codes.InvalidArgument,
+			"cannot reassign a user's resources to themselves")
+	}
+	to, err := user.ByID(ctx, model.UserID(req.ToUserId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("user", strconv.Itoa(int(req.ToUserId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	if !to.Active {
+		return nil, status.Error(codes.InvalidArgument,
+			"cannot reassign resources to inactive user "+to.Username)
+	}
+	r := reassignment.Request{From: from.ID, To: to.ToUser()}
+	if req.ToGroupId != nil {
+		groupID := int(*req.ToGroupId)
+		group, err := usergroup.GroupByIDTx(ctx, nil, groupID)
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, api.NotFoundErrs("group", strconv.Itoa(groupID), true)
+		} else if err != nil {
+			return nil, err
+		}
+		// Roles can't be assigned to a personal group directly, so they can't be given to one
+		// here either.
+		if group.OwnerID != 0 {
+			return nil, status.Error(codes.InvalidArgument,
+				"cannot give roles to the personal group of a user")
+		}
+		r.ToGroupID = &groupID
+		r.CanAssignRoles = func(ctx context.Context, a []*rbacv1.GroupRoleAssignment) error {
+			if err := rbac.AuthZProvider.Get().CanAssignRoles(ctx, *curUser, a, nil); err != nil {
+				return status.Error(codes.PermissionDenied, err.Error())
+			}
+			return nil
+		}
+	}
+
+	var res *reassignment.Reassignment
+	err = command.DefaultCmdService.ReassignOwner(r.To, func() ([]model.TaskID, error) {
+		var err error
+		if res, err = reassignment.Reassign(ctx, r, req.DryRun); err != nil || req.DryRun {
+			return nil, err
+		}
+		var ids []model.TaskID
+		for _, t := range res.Tasks {
+			ids = append(ids, t.ID)
+		}
+		return ids, nil
+	})
+	switch {
+	case status.Code(err) == codes.PermissionDenied:
+		return nil, err
+	case errors.Is(err, rbac.ErrGlobalAssignedLocally):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, err
+	}
+	return &apiv1.PostUserReassignmentResponse{Reassignment: res.Proto()}, nil
+}
@@ -0,0 +1,42 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestPostUserReassignment(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	from := db.RequireMockUser(t, api.m.db)
+	to := db.RequireMockUser(t, api.m.db)
+
+	resp, err := api.PostUserReassignment(ctx, &apiv1.PostUserReassignmentRequest{
+		UserId:   int32(from.ID),
+		ToUserId: int32(to.ID),
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Reassignment.Experiments)
+	require.Empty(t, resp.Reassignment.Tasks)
+
+	_, err = api.PostUserReassignment(ctx, &apiv1.PostUserReassignmentRequest{
+		UserId:   int32(from.ID),
+		ToUserId: int32(from.ID),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.PostUserReassignment(ctx, &apiv1.PostUserReassignmentRequest{
+		UserId:   int32(from.ID),
+		ToUserId: -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/internal/versioncompat"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func (a *apiServer) PostTaskVersion(
+	ctx context.Context, req *apiv1.PostTaskVersionRequest,
+) (*apiv1.PostTaskVersionResponse, error) {
+	taskID := model.TaskID(req.TaskId)
+	allocationID := model.AllocationID(req.AllocationId)
+	if allocationID.ToTaskID() != taskID {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"allocation %s does not belong to task %s", allocationID, taskID)
+	}
+	if _, err := a.getTrialTaskExperiment(ctx, taskID,
+		expauth.AuthZProvider.Get().CanEditExperiment,
+	); err != nil {
+		return nil, err
+	}
+
+	// The harness of an allocation is tracked until the allocation terminates.
+	id := allocationID.String()
+	_, reported := a.m.versions.Verdict(versioncompat.KindHarness, id)
+	verdict := a.m.versions.Report(versioncompat.KindHarness, id, req.Version)
+	if !reported {
+		go func() {
+			task.DefaultService.AwaitTermination(allocationID)
+			a.m.versions.Forget(versioncompat.KindHarness, id)
+		}()
+	}
+	return &apiv1.PostTaskVersionResponse{
+		Compatible: verdict.Compatible,
+		Policy:     verdict.Policy,
+		Reason:     verdict.Reason,
+	}, nil
+}
+
+func (a *apiServer) GetVersionSkew(
+	ctx context.Context, req *apiv1.GetVersionSkewRequest,
+) (*apiv1.GetVersionSkewResponse, error) {
+	// The skew lists the agents and allocations of every user.
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.m.canGetUsageDetails(ctx, curUser); err != nil {
+		return nil, err
+	}
+
+	skew := a.m.versions.Skew()
+	resp := &apiv1.GetVersionSkewResponse{MasterVersion: skew.MasterVersion}
+	for _, k := range skew.Kinds {
+		kind := &apiv1.VersionSkewKind{
+			Kind:                   string(k.Kind),
+			Policy:                 k.Policy,
+			Total:                  int32(k.Total),
+			Incompatible:           int32(k.Incompatible),
+			Versions:               make([]*apiv1.VersionSkewCount, 0, len(k.Versions)),
+			IncompatibleComponents: make([]*apiv1.VersionSkewComponent, 0, len(k.IncompatibleComponents)),
+		}
+		for _, v := range k.Versions {
+			kind.Versions = append(kind.Versions, &apiv1.VersionSkewCount{
+				Version:    v.Version,
+				Count:      int32(v.Count),
+				Compatible: v.Compatible,
+			})
+		}
+		for _, c := range k.IncompatibleComponents {
+			kind.IncompatibleComponents = append(kind.IncompatibleComponents,
+				&apiv1.VersionSkewComponent{
+					Kind:       string(c.Kind),
+					Id:         c.ID,
+					Version:    c.Version,
+					Policy:     c.Verdict.Policy,
+					Reason:     c.Verdict.Reason,
+					ReportedAt: timestamppb.New(c.ReportedAt),
+				})
+		}
+		resp.Kinds = append(resp.Kinds, kind)
+	}
+	return resp, nil
+}
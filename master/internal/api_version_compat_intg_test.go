@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/versioncompat"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestGetVersionSkew(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	api.m.versions = versioncompat.NewRegistry("1.5.0", config.VersionCompatibilityConfig{})
+	api.m.versions.Report(versioncompat.KindAgent, "agent-1", "1.5.0")
+	api.m.versions.Report(versioncompat.KindAgent, "agent-2", "0.9.0")
+
+	resp, err := api.GetVersionSkew(ctx, &apiv1.GetVersionSkewRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "1.5.0", resp.MasterVersion)
+	require.Equal(t, "agent", resp.Kinds[0].Kind)
+	require.Equal(t, int32(2), resp.Kinds[0].Total)
+	require.Len(t, resp.Kinds[0].IncompatibleComponents, 1)
+	require.Equal(t, "agent-2", resp.Kinds[0].IncompatibleComponents[0].Id)
+
+	// The skew lists the agents and allocations of every user.
+	curUser.Admin = false
+	require.NoError(t, user.Update(ctx, &curUser, []string{"admin"}, nil))
+	_, err = api.GetVersionSkew(ctx, &apiv1.GetVersionSkewRequest{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+}
+
+func TestPostTaskVersion(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	api.m.versions = versioncompat.NewRegistry("1.5.0", config.VersionCompatibilityConfig{})
+	_, task := createTestTrial(t, api, curUser)
+
+	_, err := api.PostTaskVersion(ctx, &apiv1.PostTaskVersionRequest{
+		TaskId: string(task.TaskID), AllocationId: "other-task.0", Version: "1.5.0",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	_, err = api.PostTaskVersion(ctx, &apiv1.PostTaskVersionRequest{
+		TaskId: "missing", AllocationId: "missing.0", Version: "1.5.0",
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	resp, err := api.PostTaskVersion(ctx, &apiv1.PostTaskVersionRequest{
+		TaskId: string(task.TaskID), AllocationId: string(task.TaskID) + ".0", Version: "0.9.0",
+	})
+	require.NoError(t, err)
+	require.False(t, resp.Compatible)
+	require.Equal(t, config.VersionPolicyWarn, resp.Policy)
+}
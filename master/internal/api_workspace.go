@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/pkg/errors"
@@ -18,6 +19,7 @@ import (
 	"github.com/uptrace/bun"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
@@ -26,7 +28,9 @@ import (
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 
 	"github.com/determined-ai/determined/master/internal/license"
+	"github.com/determined-ai/determined/master/internal/project"
 	"github.com/determined-ai/determined/master/internal/rm/kubernetesrm"
+	"github.com/determined-ai/determined/master/internal/storageusage"
 	"github.com/determined-ai/determined/master/internal/templates"
 	"github.com/determined-ai/determined/master/internal/workspace"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -316,7 +320,71 @@ func (a *apiServer) GetWorkspace(
 	}
 
 	w, err := a.GetWorkspaceByID(ctx, req.Id, *curUser, false)
-	return &apiv1.GetWorkspaceResponse{Workspace: w}, err
+	if err != nil {
+		return nil, err
+	}
+	if err := setWorkspaceStorageUsage(ctx, []*workspacev1.Workspace{w}); err != nil {
+		return nil, err
+	}
+	return &apiv1.GetWorkspaceResponse{Workspace: w}, nil
+}
+
+// setWorkspaceStorageUsage fills in the checkpoint storage used by each workspace and its quotas.
+func setWorkspaceStorageUsage(ctx context.Context, ws []*workspacev1.Workspace) error {
+	if len(ws) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(ws))
+	for _, w := range ws {
+		ids = append(ids, int(w.Id))
+	}
+	usages, err := storageusage.Workspaces(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int32]storageusage.WorkspaceUsage, len(usages))
+	for _, u := range usages {
+		byID[int32(u.WorkspaceID)] = u
+	}
+	now := time.Now()
+	for _, w := range ws {
+		u, ok := byID[w.Id]
+		if !ok {
+			continue
+		}
+		w.StorageBytes = u.Bytes
+		w.CheckpointCount = int32(u.CheckpointCount)
+		w.SoftStorageQuotaBytes = u.SoftQuotaBytes
+		w.HardStorageQuotaBytes = u.HardQuotaBytes
+		w.StorageWritesBlocked = u.WritesBlocked(now)
+	}
+	return nil
+}
+
+// setProjectStorageUsage fills in the checkpoint storage used by each project.
+func setProjectStorageUsage(ctx context.Context, ps []*projectv1.Project) error {
+	if len(ps) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(ps))
+	for _, p := range ps {
+		ids = append(ids, int(p.Id))
+	}
+	usages, err := storageusage.Projects(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int32]storageusage.ProjectUsage, len(usages))
+	for _, u := range usages {
+		byID[int32(u.ProjectID)] = u
+	}
+	for _, p := range ps {
+		if u, ok := byID[p.Id]; ok {
+			p.StorageBytes = u.Bytes
+			p.CheckpointCount = int32(u.CheckpointCount)
+		}
+	}
+	return nil
 }
 
 func (a *apiServer) GetWorkspaceProjects(
@@ -371,6 +439,10 @@ func (a *apiServer) GetWorkspaceProjects(
 	default:
 		orderExpr = fmt.Sprintf("id %s", orderByMap[req.OrderBy])
 	}
+	if req.SortBy == apiv1.GetWorkspaceProjectsRequest_SORT_BY_UNSPECIFIED {
+		// Without a requested sort, the user's pinned projects come first in their chosen order.
+		orderExpr = "pins.position ASC NULLS LAST, " + orderExpr
+	}
 
 	resp := &apiv1.GetWorkspaceProjectsResponse{}
 	err = a.m.db.QueryProtof(
@@ -382,6 +454,7 @@ func (a *apiServer) GetWorkspaceProjects(
 		userIDFilterExpr,
 		nameFilter,
 		archFilterExpr,
+		curUser.ID,
 	)
 	if err != nil {
 		return nil, err
@@ -393,7 +466,47 @@ func (a *apiServer) GetWorkspaceProjects(
 		return nil, err
 	}
 
-	return resp, api.Paginate(&resp.Pagination, &resp.Projects, req.Offset, req.Limit)
+	if err := api.Paginate(&resp.Pagination, &resp.Projects, req.Offset, req.Limit); err != nil {
+		return nil, err
+	}
+	if err := setProjectStorageUsage(ctx, resp.Projects); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetWorkspaceProjectMetrics(
+	ctx context.Context, req *apiv1.GetWorkspaceProjectMetricsRequest,
+) (*apiv1.GetWorkspaceProjectMetricsResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.Id, false); err != nil {
+		return nil, err
+	}
+
+	metrics, err := project.WorkspaceMetrics(ctx, int(req.Id))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetWorkspaceProjectMetricsResponse{}
+	for _, m := range metrics {
+		p := &apiv1.WorkspaceProjectMetrics{
+			ProjectId:        int32(m.ProjectID),
+			Name:             m.Name,
+			ExperimentStates: make(map[string]int32, len(m.ExperimentStates)),
+			TrialStates:      make(map[string]int32, len(m.TrialStates)),
+			GpuHours:         m.GPUHours,
+		}
+		for state, n := range m.ExperimentStates {
+			p.ExperimentStates[state] = int32(n)
+		}
+		for state, n := range m.TrialStates {
+			p.TrialStates[state] = int32(n)
+		}
+		if m.LastActivity != nil {
+			p.LastActivity = timestamppb.New(*m.LastActivity)
+		}
+		resp.Projects = append(resp.Projects, p)
+	}
+	return resp, nil
 }
 
 func (a *apiServer) GetWorkspaces(
@@ -443,6 +556,10 @@ func (a *apiServer) GetWorkspaces(
 	default:
 		orderExpr = fmt.Sprintf("id %s", orderByMap[req.OrderBy])
 	}
+	if req.SortBy == apiv1.GetWorkspacesRequest_SORT_BY_UNSPECIFIED && pinFilterExpr == "true" {
+		// Pinned workspaces are listed in the order the user arranged them.
+		orderExpr = "pins.position ASC, " + orderExpr
+	}
 
 	resp := &apiv1.GetWorkspacesResponse{}
 	err = a.m.db.QueryProtof(
@@ -467,7 +584,13 @@ func (a *apiServer) GetWorkspaces(
 		return nil, err
 	}
 
-	return resp, api.Paginate(&resp.Pagination, &resp.Workspaces, req.Offset, req.Limit)
+	if err := api.Paginate(&resp.Pagination, &resp.Workspaces, req.Offset, req.Limit); err != nil {
+		return nil, err
+	}
+	if err := setWorkspaceStorageUsage(ctx, resp.Workspaces); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
 func (a *apiServer) PostWorkspace(
@@ -1319,6 +1442,7 @@ func (a *apiServer) DeleteWorkspace(
 		"",
 		"",
 		"",
+		0,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("getting workspace projects: %w", err)
@@ -1646,3 +1770,21 @@ func (a *apiServer) GetKubernetesResourceQuotas(
 	}
 	return &apiv1.GetKubernetesResourceQuotasResponse{ResourceQuotas: quotas}, nil
 }
+
+// workspaceCheckpointStorage returns the checkpoint storage that tasks in a workspace use: the
+// workspace's storage config, with the master's filling in anything it leaves unset.
+func (a *apiServer) workspaceCheckpointStorage(
+	ctx context.Context, workspaceID int,
+) (*expconf.CheckpointStorageConfig, error) {
+	w := &model.Workspace{}
+	if err := db.Bun().NewSelect().Model(w).
+		Where("id = ?", workspaceID).
+		Column("checkpoint_storage_config").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting checkpoint storage for workspace %d: %w", workspaceID, err)
+	}
+	storage := schemas.WithDefaults(*schemas.Merge(
+		w.CheckpointStorageConfig, &a.m.config.CheckpointStorage,
+	))
+	return &storage, nil
+}
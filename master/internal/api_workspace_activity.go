@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/activity"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	modelauth "github.com/determined-ai/determined/master/internal/model"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/set"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func (a *apiServer) GetWorkspaceActivity(
+	ctx context.Context, req *apiv1.GetWorkspaceActivityRequest,
+) (*apiv1.GetWorkspaceActivityResponse, error) {
+	limit := activity.DefaultLimit
+	if req.Limit != nil {
+		if *req.Limit < 1 || *req.Limit > activity.MaxLimit {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"limit must be between 1 and %d", activity.MaxLimit)
+		}
+		limit = int(*req.Limit)
+	}
+	var requested set.Set[model.WorkspaceEventKind]
+	if len(req.Kinds) > 0 {
+		requested = set.New[model.WorkspaceEventKind]()
+		for _, k := range req.Kinds {
+			kind := model.WorkspaceEventKindFromProto(k)
+			if !kind.Valid() {
+				return nil, status.Errorf(codes.InvalidArgument, "unknown kind of event %q", kind)
+			}
+			requested.Insert(kind)
+		}
+	}
+
+	w, user, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false)
+	if err != nil {
+		return nil, err
+	}
+	visible, err := visibleWorkspaceEventKinds(ctx, user, w)
+	if err != nil {
+		return nil, err
+	}
+	var kinds []model.WorkspaceEventKind
+	for _, k := range visible {
+		if requested == nil || requested.Contains(k) {
+			kinds = append(kinds, k)
+		}
+	}
+
+	events, err := activity.Feed(ctx, int(req.WorkspaceId), kinds, req.BeforeId, limit)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetWorkspaceActivityResponse{Events: []*workspacev1.WorkspaceEvent{}}
+	for _, e := range events {
+		resp.Events = append(resp.Events, e.Proto())
+	}
+	if len(events) == limit {
+		resp.NextBeforeId = &events[len(events)-1].ID
+	}
+	return resp, nil
+}
+
+// visibleWorkspaceEventKinds returns the kinds of events in a workspace's activity feed a user may
+// see. Anyone who can view the workspace sees its members being added, but events about
+// experiments and models need permission to view those in the workspace.
+func visibleWorkspaceEventKinds(
+	ctx context.Context, user model.User, w *workspacev1.Workspace,
+) ([]model.WorkspaceEventKind, error) {
+	kinds := []model.WorkspaceEventKind{model.WorkspaceEventMemberAdded}
+
+	probe, err := expauth.AuthZProvider.Get().FilterExperimentsQuery(ctx, user, nil,
+		db.Bun().NewSelect().TableExpr("(SELECT ?::integer AS workspace_id) AS w", w.Id),
+		[]rbacv1.PermissionType{rbacv1.PermissionType_PERMISSION_TYPE_VIEW_EXPERIMENT_METADATA},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := probe.Exists(ctx); err != nil {
+		return nil, err
+	} else if ok {
+		kinds = append(kinds, model.WorkspaceEventExperimentCreated,
+			model.WorkspaceEventExperimentCompleted, model.WorkspaceEventExperimentFailed)
+	}
+
+	modelWorkspaces, err := modelauth.AuthZProvider.Get().CanGetModels(ctx, user, []int32{w.Id})
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(modelWorkspaces, w.Id) {
+		kinds = append(kinds, model.WorkspaceEventModelRegistered)
+	}
+	return kinds, nil
+}
@@ -0,0 +1,48 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func TestGetWorkspaceActivity(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	wsID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	resp, err := api.GetWorkspaceActivity(ctx, &apiv1.GetWorkspaceActivityRequest{
+		WorkspaceId: int32(wsID),
+		Kinds: []workspacev1.WorkspaceEventKind{
+			workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_EXPERIMENT_CREATED,
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Events)
+	require.Nil(t, resp.NextBeforeId)
+
+	_, err = api.GetWorkspaceActivity(ctx, &apiv1.GetWorkspaceActivityRequest{
+		WorkspaceId: int32(wsID),
+		Limit:       ptrs.Ptr(int32(0)),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.GetWorkspaceActivity(ctx, &apiv1.GetWorkspaceActivityRequest{
+		WorkspaceId: int32(wsID),
+		Kinds: []workspacev1.WorkspaceEventKind{
+			workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_UNSPECIFIED,
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.GetWorkspaceActivity(ctx, &apiv1.GetWorkspaceActivityRequest{WorkspaceId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func (a *apiServer) GetWorkspaceAPIKeys(
+	ctx context.Context, req *apiv1.GetWorkspaceAPIKeysRequest,
+) (*apiv1.GetWorkspaceAPIKeysResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	); err != nil {
+		return nil, err
+	}
+
+	keys, err := workspace.APIKeys(ctx, int(req.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetWorkspaceAPIKeysResponse{Keys: []*workspacev1.WorkspaceAPIKey{}}
+	for _, k := range keys {
+		resp.Keys = append(resp.Keys, k.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PostWorkspaceAPIKey(
+	ctx context.Context, req *apiv1.PostWorkspaceAPIKeyRequest,
+) (*apiv1.PostWorkspaceAPIKeyResponse, error) {
+	switch {
+	case req.Name == "":
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	case req.ExpiresInDays != nil && *req.ExpiresInDays < 1:
+		return nil, status.Error(codes.InvalidArgument, "expires_in_days must be positive")
+	}
+
+	_, user, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		t := time.Now().UTC().AddDate(0, 0, int(*req.ExpiresInDays))
+		expiresAt = &t
+	}
+	key, k, err := workspace.CreateAPIKey(ctx, int(req.WorkspaceId), req.Name, user.ID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	// The key is only ever returned here.
+	return &apiv1.PostWorkspaceAPIKeyResponse{ApiKey: k.Proto(), Key: key}, nil
+}
+
+func (a *apiServer) DeleteWorkspaceAPIKey(
+	ctx context.Context, req *apiv1.DeleteWorkspaceAPIKeyRequest,
+) (*apiv1.DeleteWorkspaceAPIKeyResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	); err != nil {
+		return nil, err
+	}
+
+	err := workspace.RevokeAPIKey(ctx, int(req.WorkspaceId), int(req.KeyId))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("API key", strconv.Itoa(int(req.KeyId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteWorkspaceAPIKeyResponse{}, nil
+}
@@ -0,0 +1,70 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestWorkspaceAPIKeys(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	created, err := api.PostWorkspaceAPIKey(ctx, &apiv1.PostWorkspaceAPIKeyRequest{
+		WorkspaceId:   int32(workspaceID),
+		Name:          "grafana",
+		ExpiresInDays: ptrs.Ptr(int32(90)),
+	})
+	require.NoError(t, err)
+	require.True(t, model.IsWorkspaceAPIKey(created.Key))
+	require.Equal(t, int32(curUser.ID), created.ApiKey.CreatedBy)
+	require.NotNil(t, created.ApiKey.ExpiresAt)
+
+	keys, err := api.GetWorkspaceAPIKeys(ctx, &apiv1.GetWorkspaceAPIKeysRequest{
+		WorkspaceId: int32(workspaceID),
+	})
+	require.NoError(t, err)
+	require.Len(t, keys.Keys, 1)
+	require.Equal(t, created.ApiKey.KeyPrefix, keys.Keys[0].KeyPrefix)
+	require.Nil(t, keys.Keys[0].RevokedAt)
+
+	req := &apiv1.DeleteWorkspaceAPIKeyRequest{
+		WorkspaceId: int32(workspaceID),
+		KeyId:       created.ApiKey.Id,
+	}
+	_, err = api.DeleteWorkspaceAPIKey(ctx, req)
+	require.NoError(t, err)
+	keys, err = api.GetWorkspaceAPIKeys(ctx, &apiv1.GetWorkspaceAPIKeysRequest{
+		WorkspaceId: int32(workspaceID),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, keys.Keys[0].RevokedAt)
+}
+
+func TestWorkspaceAPIKeysErrors(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	workspaceID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	for _, req := range []*apiv1.PostWorkspaceAPIKeyRequest{
+		{WorkspaceId: int32(workspaceID)},
+		{WorkspaceId: int32(workspaceID), Name: "grafana", ExpiresInDays: ptrs.Ptr(int32(0))},
+	} {
+		_, err := api.PostWorkspaceAPIKey(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+	}
+
+	_, err := api.DeleteWorkspaceAPIKey(ctx, &apiv1.DeleteWorkspaceAPIKeyRequest{
+		WorkspaceId: int32(workspaceID),
+		KeyId:       -1,
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/envvarsets"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func (a *apiServer) GetWorkspaceEnvVarSets(
+	ctx context.Context, req *apiv1.GetWorkspaceEnvVarSetsRequest,
+) (*apiv1.GetWorkspaceEnvVarSetsResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false); err != nil {
+		return nil, err
+	}
+
+	sets, err := envvarsets.Sets(ctx, int(req.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetWorkspaceEnvVarSetsResponse{EnvVarSets: []*workspacev1.EnvVarSet{}}
+	for _, s := range sets {
+		resp.EnvVarSets = append(resp.EnvVarSets, s.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) PutWorkspaceEnvVarSet(
+	ctx context.Context, req *apiv1.PutWorkspaceEnvVarSetRequest,
+) (*apiv1.PutWorkspaceEnvVarSetResponse, error) {
+	s := model.WorkspaceEnvVarSet{
+		WorkspaceID: int(req.WorkspaceId),
+		Name:        req.Name,
+		Variables:   req.Variables,
+		Description: req.Description,
+	}
+	if err := envvarsets.ValidateSet(&s); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	_, user, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.UpdatedBy = user.ID
+	if err = envvarsets.PutSet(ctx, &s); err != nil {
+		return nil, err
+	}
+	return &apiv1.PutWorkspaceEnvVarSetResponse{EnvVarSet: s.Proto()}, nil
+}
+
+func (a *apiServer) DeleteWorkspaceEnvVarSet(
+	ctx context.Context, req *apiv1.DeleteWorkspaceEnvVarSetRequest,
+) (*apiv1.DeleteWorkspaceEnvVarSetResponse, error) {
+	if _, _, err := a.getWorkspaceAndCheckCanDoActions(ctx, req.WorkspaceId, false,
+		workspace.AuthZProvider.Get().CanSetWorkspacesSettings,
+	); err != nil {
+		return nil, err
+	}
+
+	err := envvarsets.DeleteSet(ctx, int(req.WorkspaceId), req.Name)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("environment variable set", req.Name, true)
+	} else if err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteWorkspaceEnvVarSetResponse{}, nil
+}
@@ -0,0 +1,57 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func TestWorkspaceEnvVarSets(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	wsID, _ := createProjectAndWorkspace(ctx, t, api)
+
+	put, err := api.PutWorkspaceEnvVarSet(ctx, &apiv1.PutWorkspaceEnvVarSetRequest{
+		WorkspaceId: int32(wsID),
+		Name:        "proxy",
+		Variables:   map[string]string{"HTTPS_PROXY": "http://proxy:3128"},
+		Description: "Proxy settings",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "proxy", put.EnvVarSet.Name)
+	require.Equal(t, int32(curUser.ID), put.EnvVarSet.UpdatedBy)
+
+	get, err := api.GetWorkspaceEnvVarSets(ctx,
+		&apiv1.GetWorkspaceEnvVarSetsRequest{WorkspaceId: int32(wsID)})
+	require.NoError(t, err)
+	require.Len(t, get.EnvVarSets, 1)
+	require.Equal(t, map[string]string{"HTTPS_PROXY": "http://proxy:3128"},
+		get.EnvVarSets[0].Variables)
+
+	_, err = api.PutWorkspaceEnvVarSet(ctx, &apiv1.PutWorkspaceEnvVarSetRequest{
+		WorkspaceId: int32(wsID),
+		Name:        "reserved",
+		Variables:   map[string]string{"DET_MASTER": "http://master:8080"},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, err = api.DeleteWorkspaceEnvVarSet(ctx, &apiv1.DeleteWorkspaceEnvVarSetRequest{
+		WorkspaceId: int32(wsID),
+		Name:        "proxy",
+	})
+	require.NoError(t, err)
+	_, err = api.DeleteWorkspaceEnvVarSet(ctx, &apiv1.DeleteWorkspaceEnvVarSetRequest{
+		WorkspaceId: int32(wsID),
+		Name:        "proxy",
+	})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+
+	_, err = api.GetWorkspaceEnvVarSets(ctx, &apiv1.GetWorkspaceEnvVarSetsRequest{WorkspaceId: -1})
+	require.Equal(t, codes.NotFound, status.Code(err), err)
+}
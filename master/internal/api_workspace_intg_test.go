@@ -31,10 +31,12 @@ import (
 	"github.com/determined-ai/determined/master/internal/mocks"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/multirm"
+	"github.com/determined-ai/determined/master/internal/storageusage"
 	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/internal/workspace"
 	"github.com/determined-ai/determined/master/pkg/etc"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/test/testutils"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/projectv1"
@@ -493,6 +495,72 @@ func TestGetWorkspaces(t *testing.T) {
 	}
 }
 
+func TestWorkspaceStorageUsage(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+
+	workspaceID, projectID := createProjectAndWorkspace(ctx, t, api)
+	exp := createTestExpWithProjectID(t, api, curUser, projectID)
+	_, err := db.Bun().NewUpdate().Table("experiments").
+		Set("checkpoint_size = ?", 120).
+		Set("checkpoint_count = ?", 2).
+		Where("id = ?", exp.ID).
+		Exec(ctx)
+	require.NoError(t, err)
+	require.NoError(t, storageusage.SetQuotas(ctx, workspaceID, ptrs.Ptr(int64(100)), ptrs.Ptr(int64(120))))
+
+	getResp, err := api.GetWorkspace(ctx, &apiv1.GetWorkspaceRequest{Id: int32(workspaceID)})
+	require.NoError(t, err)
+	w := getResp.Workspace
+	require.Equal(t, int64(120), w.StorageBytes)
+	require.Equal(t, int32(2), w.CheckpointCount)
+	require.Equal(t, int64(100), w.GetSoftStorageQuotaBytes())
+	require.Equal(t, int64(120), w.GetHardStorageQuotaBytes())
+	require.True(t, w.StorageWritesBlocked)
+
+	listResp, err := api.GetWorkspaces(ctx, &apiv1.GetWorkspacesRequest{})
+	require.NoError(t, err)
+	for _, lw := range listResp.Workspaces {
+		if lw.Id == w.Id {
+			require.Equal(t, int64(120), lw.StorageBytes)
+			require.True(t, lw.StorageWritesBlocked)
+		} else if lw.Id == 1 {
+			require.Nil(t, lw.SoftStorageQuotaBytes)
+			require.False(t, lw.StorageWritesBlocked)
+		}
+	}
+
+	projResp, err := api.GetProject(ctx, &apiv1.GetProjectRequest{Id: int32(projectID)})
+	require.NoError(t, err)
+	require.Equal(t, int64(120), projResp.Project.StorageBytes)
+	require.Equal(t, int32(2), projResp.Project.CheckpointCount)
+
+	projsResp, err := api.GetWorkspaceProjects(ctx, &apiv1.GetWorkspaceProjectsRequest{
+		Id: int32(workspaceID),
+	})
+	require.NoError(t, err)
+	require.Len(t, projsResp.Projects, 1)
+	require.Equal(t, int64(120), projsResp.Projects[0].StorageBytes)
+
+	expResp, err := api.GetExperiment(ctx, &apiv1.GetExperimentRequest{ExperimentId: int32(exp.ID)})
+	require.NoError(t, err)
+	require.Equal(t, int64(120), expResp.Experiment.CheckpointSize)
+	require.Equal(t, int32(2), expResp.Experiment.CheckpointCount)
+}
+
+func TestGetWorkspaceProjectMetrics(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+
+	workspaceID, projectID := createProjectAndWorkspace(ctx, t, api)
+	createTestExpWithProjectID(t, api, curUser, projectID)
+
+	resp, err := api.GetWorkspaceProjectMetrics(ctx,
+		&apiv1.GetWorkspaceProjectMetricsRequest{Id: int32(workspaceID)})
+	require.NoError(t, err)
+	require.Len(t, resp.Projects, 1)
+	require.Equal(t, int32(projectID), resp.Projects[0].ProjectId)
+	require.Equal(t, int32(1), resp.Projects[0].ExperimentStates[string(model.ActiveState)])
+}
+
 // This should eventually be in internal/workspaces.
 func TestWorkspacesIDsByExperimentIDs(t *testing.T) {
 	api, curUser, ctx := setupAPITest(t, nil)
@@ -713,6 +781,16 @@ func TestAuthzGetWorkspace(t *testing.T) {
 	require.Equal(t, expectedErr, err)
 }
 
+func TestAuthzGetWorkspaceProjectMetrics(t *testing.T) {
+	api, workspaceAuthZ, _, ctx := setupWorkspaceAuthZTest(t, nil)
+
+	// Deny returns same as 404.
+	workspaceAuthZ.On("CanGetWorkspace", mock.Anything, mock.Anything, mock.Anything).
+		Return(authz2.PermissionDeniedError{}).Once()
+	_, err := api.GetWorkspaceProjectMetrics(ctx, &apiv1.GetWorkspaceProjectMetricsRequest{Id: 1})
+	require.Equal(t, apiPkg.NotFoundErrs("workspace", "1", true).Error(), err.Error())
+}
+
 func TestAuthzGetWorkspaceProjects(t *testing.T) {
 	api, workspaceAuthZ, _, ctx := setupWorkspaceAuthZTest(t, nil)
 
@@ -112,17 +112,18 @@ func ObfuscateAgent(agent *agentv1.Agent) error {
 // ObfuscateJob obfuscates sensitive information in given Job.
 func ObfuscateJob(job *jobv1.Job) jobv1.LimitedJob {
 	return jobv1.LimitedJob{
-		Summary:        job.Summary,
-		Type:           job.Type,
-		ResourcePool:   job.ResourcePool,
-		IsPreemptible:  job.IsPreemptible,
-		Priority:       job.Priority,
-		Weight:         job.Weight,
-		JobId:          job.JobId,
-		RequestedSlots: job.RequestedSlots,
-		AllocatedSlots: job.AllocatedSlots,
-		Progress:       job.Progress,
-		WorkspaceId:    job.WorkspaceId,
+		Summary:           job.Summary,
+		Type:              job.Type,
+		ResourcePool:      job.ResourcePool,
+		IsPreemptible:     job.IsPreemptible,
+		Priority:          job.Priority,
+		EffectivePriority: job.EffectivePriority,
+		Weight:            job.Weight,
+		JobId:             job.JobId,
+		RequestedSlots:    job.RequestedSlots,
+		AllocatedSlots:    job.AllocatedSlots,
+		Progress:          job.Progress,
+		WorkspaceId:       job.WorkspaceId,
 	}
 }
 
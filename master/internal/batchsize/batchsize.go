@@ -0,0 +1,52 @@
+// Package batchsize finds the largest batch size a trial can train with before running out of
+// memory, by launching short probes of the trial at different batch sizes before its experiment
+// starts training.
+package batchsize
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoBatchSizeFits is returned when not even the smallest batch size of a search fits.
+var ErrNoBatchSizeFits = errors.New("no batch size fits")
+
+// Search returns the largest batch size between min and max that fits, according to probe. It
+// doubles the batch size from min until a probe fails or max is reached, then bisects between the
+// largest batch size that fit and the smallest that didn't. Each batch size is probed at most once.
+func Search(min, max int, probe func(batchSize int) (fits bool, err error)) (int, error) {
+	if min < 1 || max < min {
+		return 0, fmt.Errorf("invalid batch size bounds [%d, %d]", min, max)
+	}
+	// fit is the largest batch size known to fit, and noFit the smallest known not to.
+	fit, noFit := 0, max+1
+	for size := min; ; {
+		fits, err := probe(size)
+		if err != nil {
+			return 0, fmt.Errorf("probing batch size %d: %w", size, err)
+		}
+		if fits {
+			fit = size
+		} else {
+			noFit = size
+		}
+
+		switch {
+		case fit == 0:
+			return 0, fmt.Errorf("%w: batch size %d is too large", ErrNoBatchSizeFits, min)
+		case noFit > max:
+			if fit == max {
+				return fit, nil
+			}
+			size = fit * 2
+			if size > max {
+				size = max
+			}
+		default:
+			size = fit + (noFit-fit)/2
+			if size == fit {
+				return fit, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package batchsize
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	cases := []struct {
+		name     string
+		min, max int
+		largest  int
+		expected int
+		probes   []int
+	}{
+		{"fits between powers of two", 1, 64, 37, 37, []int{1, 2, 4, 8, 16, 32, 64, 48, 40, 36, 38, 37}},
+		{"fits the maximum", 4, 100, 1000, 100, []int{4, 8, 16, 32, 64, 100}},
+		{"fits only the minimum", 3, 10, 3, 3, []int{3, 6, 4}},
+		{"minimum is the maximum", 8, 8, 8, 8, []int{8}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var probes []int
+			size, err := Search(tc.min, tc.max, func(batchSize int) (bool, error) {
+				probes = append(probes, batchSize)
+				return batchSize <= tc.largest, nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, size)
+			require.Equal(t, tc.probes, probes)
+		})
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	_, err := Search(16, 64, func(int) (bool, error) { return false, nil })
+	require.ErrorIs(t, err, ErrNoBatchSizeFits)
+
+	probeErr := errors.New("probe failed to start")
+	_, err = Search(1, 64, func(batchSize int) (bool, error) {
+		if batchSize > 4 {
+			return false, probeErr
+		}
+		return true, nil
+	})
+	require.ErrorIs(t, err, probeErr)
+
+	_, err = Search(8, 4, func(int) (bool, error) { return true, nil })
+	require.Error(t, err)
+}
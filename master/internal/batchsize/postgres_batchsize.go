@@ -0,0 +1,83 @@
+package batchsize
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddProbe records a probe started for an experiment.
+func AddProbe(ctx context.Context, probe *model.BatchSizeProbe) error {
+	if _, err := db.Bun().NewInsert().Model(probe).Exec(ctx); err != nil {
+		return fmt.Errorf("adding batch size probe %s: %w", probe.TaskID, err)
+	}
+	return nil
+}
+
+// CompleteProbe records whether the batch size of a probe fit.
+func CompleteProbe(ctx context.Context, taskID model.TaskID, fits bool) error {
+	if _, err := db.Bun().NewUpdate().Model((*model.BatchSizeProbe)(nil)).
+		Set("fits = ?", fits).
+		Where("task_id = ?", taskID).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("completing batch size probe %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// ProbesByExperiment returns the probes run for an experiment, oldest first.
+func ProbesByExperiment(ctx context.Context, experimentID int) ([]model.BatchSizeProbe, error) {
+	probes := []model.BatchSizeProbe{}
+	if err := db.Bun().NewSelect().Model(&probes).
+		Where("experiment_id = ?", experimentID).
+		Order("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting batch size probes of experiment %d: %w", experimentID, err)
+	}
+	return probes, nil
+}
+
+// ProbeExperimentID returns the experiment a probe task belongs to, or sql.ErrNoRows if the task
+// isn't a probe.
+func ProbeExperimentID(ctx context.Context, taskID model.TaskID) (int, error) {
+	var experimentID int
+	err := db.Bun().NewSelect().Model((*model.BatchSizeProbe)(nil)).
+		Column("experiment_id").
+		Where("task_id = ?", taskID).
+		Scan(ctx, &experimentID)
+	return experimentID, err
+}
+
+// RecordTunedBatchSize records the batch size tuning chose for an experiment.
+func RecordTunedBatchSize(ctx context.Context, experimentID, batchSize int) error {
+	if _, err := db.Bun().NewInsert().Model(&model.TunedBatchSize{
+		ExperimentID: experimentID,
+		BatchSize:    batchSize,
+	}).On("CONFLICT (experiment_id) DO UPDATE").
+		Set("batch_size = EXCLUDED.batch_size").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("recording tuned batch size of experiment %d: %w", experimentID, err)
+	}
+	return nil
+}
+
+// TunedBatchSizeByExperiment returns the batch size tuning chose for an experiment, or nil if
+// tuning hasn't finished for it.
+func TunedBatchSizeByExperiment(
+	ctx context.Context, experimentID int,
+) (*model.TunedBatchSize, error) {
+	var tuned model.TunedBatchSize
+	err := db.Bun().NewSelect().Model(&tuned).
+		Where("experiment_id = ?", experimentID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting tuned batch size of experiment %d: %w", experimentID, err)
+	}
+	return &tuned, nil
+}
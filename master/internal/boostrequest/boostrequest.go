@@ -0,0 +1,91 @@
+// Package boostrequest lets users request a temporary priority boost or max slots exception for an
+// experiment. Requests wait in a queue for an admin to review them, and approved boosts are
+// applied for a bounded duration before the experiment is reverted.
+package boostrequest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// MaxDuration is the longest a boost can be applied for.
+	MaxDuration = 7 * 24 * time.Hour
+	// ReasonMaxLength is the longest reason a request can give, in bytes.
+	ReasonMaxLength = 1000
+
+	// Requested priorities are bounded like those set on jobs directly.
+	minPriority = 1
+	maxPriority = 99
+)
+
+var (
+	// ErrNotPending is returned when reviewing a request that has already been reviewed.
+	ErrNotPending = errors.New("boost request has already been reviewed")
+	// ErrAlreadyBoosted is returned when approving a request for an experiment that already has
+	// a boost of the same kind applied.
+	ErrAlreadyBoosted = errors.New("experiment already has an approved boost of this kind")
+)
+
+// Validate checks a new boost request.
+func Validate(r *model.BoostRequest) error {
+	switch r.Kind {
+	case model.BoostRequestKindPriority:
+		if r.Value < minPriority || r.Value > maxPriority {
+			return fmt.Errorf("priority must be between %d and %d", minPriority, maxPriority)
+		}
+	case model.BoostRequestKindMaxSlots:
+		if r.Value < 1 {
+			return errors.New("max_slots must be positive")
+		}
+	default:
+		return fmt.Errorf("kind must be %q or %q, not %q",
+			model.BoostRequestKindPriority, model.BoostRequestKindMaxSlots, r.Kind)
+	}
+
+	switch {
+	case r.DurationSeconds <= 0:
+		return errors.New("duration must be positive")
+	case r.Duration() > MaxDuration:
+		return fmt.Errorf("duration must be at most %s", MaxDuration)
+	}
+
+	switch reason := strings.TrimSpace(r.Reason); {
+	case reason == "":
+		return errors.New("a reason is required")
+	case len(reason) > ReasonMaxLength:
+		return fmt.Errorf("reason must be at most %d bytes", ReasonMaxLength)
+	}
+	return nil
+}
+
+// Raises returns whether value is a boost over an experiment's current priority or max slots.
+// current is unset for an experiment without a max slots limit, which can't be raised.
+// smallerIsHigher is whether smaller priority values are scheduled first.
+func Raises(kind model.BoostRequestKind, current *int, value int, smallerIsHigher bool) bool {
+	switch kind {
+	case model.BoostRequestKindPriority:
+		if current == nil {
+			return true
+		}
+		if smallerIsHigher {
+			return value < *current
+		}
+		return value > *current
+	case model.BoostRequestKindMaxSlots:
+		return current != nil && value > *current
+	default:
+		return false
+	}
+}
+
+// StillBoosted returns whether an experiment's current priority or max slots is still the value
+// an approved boost set. Boosts are only reverted while it is, so a value changed by hand during
+// the boost isn't clobbered by the one the boost replaced.
+func StillBoosted(r *model.BoostRequest, current *int) bool {
+	return current != nil && *current == r.Value
+}
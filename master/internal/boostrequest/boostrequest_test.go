@@ -0,0 +1,63 @@
+package boostrequest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestValidate(t *testing.T) {
+	valid := func() *model.BoostRequest {
+		return &model.BoostRequest{
+			Kind: model.BoostRequestKindPriority, Value: 10, DurationSeconds: 3600,
+			Reason: "paper deadline",
+		}
+	}
+	require.NoError(t, Validate(valid()))
+
+	cases := map[string]func(r *model.BoostRequest){
+		"unknown kind":       func(r *model.BoostRequest) { r.Kind = "weight" },
+		"priority too small": func(r *model.BoostRequest) { r.Value = 0 },
+		"priority too large": func(r *model.BoostRequest) { r.Value = 100 },
+		"no max slots": func(r *model.BoostRequest) {
+			r.Kind, r.Value = model.BoostRequestKindMaxSlots, 0
+		},
+		"no duration":       func(r *model.BoostRequest) { r.DurationSeconds = 0 },
+		"too long duration": func(r *model.BoostRequest) { r.DurationSeconds = 8 * 24 * 3600 },
+		"blank reason":      func(r *model.BoostRequest) { r.Reason = "  " },
+		"long reason": func(r *model.BoostRequest) {
+			r.Reason = strings.Repeat("x", ReasonMaxLength+1)
+		},
+	}
+	for name, invalidate := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := valid()
+			invalidate(r)
+			require.Error(t, Validate(r))
+		})
+	}
+}
+
+func TestRaises(t *testing.T) {
+	priority, maxSlots := model.BoostRequestKindPriority, model.BoostRequestKindMaxSlots
+
+	require.True(t, Raises(priority, ptrs.Ptr(42), 10, true))
+	require.False(t, Raises(priority, ptrs.Ptr(42), 42, true))
+	require.False(t, Raises(priority, ptrs.Ptr(42), 10, false))
+	require.True(t, Raises(priority, ptrs.Ptr(42), 60, false))
+
+	require.True(t, Raises(maxSlots, ptrs.Ptr(4), 8, false))
+	require.False(t, Raises(maxSlots, ptrs.Ptr(4), 2, false))
+	require.False(t, Raises(maxSlots, nil, 8, false), "no limit to raise")
+}
+
+func TestStillBoosted(t *testing.T) {
+	r := &model.BoostRequest{Kind: model.BoostRequestKindPriority, Value: 10}
+	require.True(t, StillBoosted(r, ptrs.Ptr(10)))
+	require.False(t, StillBoosted(r, ptrs.Ptr(20)), "changed by hand during the boost")
+	require.False(t, StillBoosted(r, nil), "max slots limit removed during the boost")
+}
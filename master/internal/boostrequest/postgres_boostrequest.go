@@ -0,0 +1,153 @@
+package boostrequest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+// CreateBoostRequest queues a boost request for review.
+func CreateBoostRequest(ctx context.Context, r *model.BoostRequest) error {
+	r.State = model.BoostRequestStatePending
+	if _, err := db.Bun().NewInsert().Model(r).Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("creating boost request for experiment %d: %w", r.ExperimentID, err)
+	}
+	return nil
+}
+
+// BoostRequestByID returns a boost request, or db.ErrNotFound if it doesn't exist.
+func BoostRequestByID(ctx context.Context, id int) (*model.BoostRequest, error) {
+	var r model.BoostRequest
+	if err := db.Bun().NewSelect().Model(&r).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, db.MatchSentinelError(err)
+	}
+	return &r, nil
+}
+
+// BoostRequests returns boost requests, oldest first, optionally only those in a state or for an
+// experiment.
+func BoostRequests(
+	ctx context.Context, state *model.BoostRequestState, experimentID *int,
+) ([]model.BoostRequest, error) {
+	requests := []model.BoostRequest{}
+	q := db.Bun().NewSelect().Model(&requests).Order("id")
+	if state != nil {
+		q = q.Where("state = ?", *state)
+	}
+	if experimentID != nil {
+		q = q.Where("experiment_id = ?", *experimentID)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting boost requests: %w", err)
+	}
+	return requests, nil
+}
+
+// Approve approves a pending boost request on behalf of reviewer and applies its boost with apply,
+// recording original as the value to revert to when it expires. The request is left pending if
+// apply fails.
+func Approve(
+	ctx context.Context, id int, reviewer model.UserID, comment *string, original *int,
+	apply func(*model.BoostRequest) error,
+) (*model.BoostRequest, error) {
+	var r model.BoostRequest
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockPending(ctx, tx, id, &r); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		r.State = model.BoostRequestStateApproved
+		r.ReviewedBy, r.ReviewedAt, r.ReviewComment = &reviewer, &now, comment
+		r.ExpiresAt = ptrs.Ptr(now.Add(r.Duration()))
+		r.OriginalValue = original
+		_, err := tx.NewUpdate().Model(&r).
+			Column("state", "reviewed_by", "reviewed_at", "review_comment", "expires_at",
+				"original_value").
+			WherePK().
+			Exec(ctx)
+		if errors.Is(db.MatchSentinelError(err), db.ErrDuplicateRecord) {
+			return ErrAlreadyBoosted
+		} else if err != nil {
+			return err
+		}
+		return apply(&r)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("approving boost request %d: %w", id, err)
+	}
+	return &r, nil
+}
+
+// Deny denies a pending boost request on behalf of reviewer.
+func Deny(
+	ctx context.Context, id int, reviewer model.UserID, comment *string,
+) (*model.BoostRequest, error) {
+	var r model.BoostRequest
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := lockPending(ctx, tx, id, &r); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		r.State = model.BoostRequestStateDenied
+		r.ReviewedBy, r.ReviewedAt, r.ReviewComment = &reviewer, &now, comment
+		_, err := tx.NewUpdate().Model(&r).
+			Column("state", "reviewed_by", "reviewed_at", "review_comment").
+			WherePK().
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("denying boost request %d: %w", id, err)
+	}
+	return &r, nil
+}
+
+// DueBoostRequests returns the approved boost requests whose boosts have expired by now.
+func DueBoostRequests(ctx context.Context, now time.Time) ([]model.BoostRequest, error) {
+	requests := []model.BoostRequest{}
+	if err := db.Bun().NewSelect().Model(&requests).
+		Where("state = ?", model.BoostRequestStateApproved).
+		Where("expires_at <= ?", now).
+		Order("expires_at").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting expired boost requests: %w", err)
+	}
+	return requests, nil
+}
+
+// Expire marks an approved boost request as expired once its boost has been reverted.
+func Expire(ctx context.Context, id int) error {
+	if _, err := db.Bun().NewUpdate().Model((*model.BoostRequest)(nil)).
+		Set("state = ?", model.BoostRequestStateExpired).
+		Where("id = ?", id).
+		Where("state = ?", model.BoostRequestStateApproved).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("expiring boost request %d: %w", id, err)
+	}
+	return nil
+}
+
+// lockPending locks a boost request for review, returning db.ErrNotFound if it doesn't exist and
+// ErrNotPending if it has already been reviewed.
+func lockPending(ctx context.Context, tx bun.Tx, id int, r *model.BoostRequest) error {
+	err := tx.NewSelect().Model(r).Where("id = ?", id).For("UPDATE").Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	if r.State != model.BoostRequestStatePending {
+		return ErrNotPending
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+//go:build integration
+// +build integration
+
+package boostrequest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestBoostRequests(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	exp := db.RequireMockExperiment(t, db.SingleDB(), user)
+
+	newRequest := func() *model.BoostRequest {
+		r := &model.BoostRequest{
+			ExperimentID: exp.ID, Kind: model.BoostRequestKindPriority, Value: 10,
+			DurationSeconds: 3600, Reason: "paper deadline", RequestedBy: user.ID,
+		}
+		require.NoError(t, CreateBoostRequest(ctx, r))
+		return r
+	}
+	first, second, denied := newRequest(), newRequest(), newRequest()
+
+	pending, err := BoostRequests(ctx, ptrs.Ptr(model.BoostRequestStatePending), &exp.ID)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+
+	// A failed apply leaves the request pending.
+	_, err = Approve(ctx, first.ID, user.ID, nil, ptrs.Ptr(42), func(*model.BoostRequest) error {
+		return errors.New("experiment isn't running")
+	})
+	require.Error(t, err)
+	r, err := BoostRequestByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.BoostRequestStatePending, r.State)
+
+	var applied *model.BoostRequest
+	r, err = Approve(ctx, first.ID, user.ID, ptrs.Ptr("ok"), ptrs.Ptr(42),
+		func(r *model.BoostRequest) error {
+			applied = r
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, applied.ID)
+	require.Equal(t, model.BoostRequestStateApproved, r.State)
+	require.Equal(t, 42, *r.OriginalValue)
+	require.WithinDuration(t, time.Now().Add(time.Hour), *r.ExpiresAt, time.Minute)
+
+	apply := func(*model.BoostRequest) error { return nil }
+	_, err = Approve(ctx, first.ID, user.ID, nil, nil, apply)
+	require.ErrorIs(t, err, ErrNotPending)
+	_, err = Approve(ctx, second.ID, user.ID, nil, nil, apply)
+	require.ErrorIs(t, err, ErrAlreadyBoosted)
+	_, err = Approve(ctx, -1, user.ID, nil, nil, apply)
+	require.ErrorIs(t, err, db.ErrNotFound)
+
+	r, err = Deny(ctx, denied.ID, user.ID, ptrs.Ptr("not this time"))
+	require.NoError(t, err)
+	require.Equal(t, model.BoostRequestStateDenied, r.State)
+	_, err = Deny(ctx, denied.ID, user.ID, nil)
+	require.ErrorIs(t, err, ErrNotPending)
+
+	due, err := DueBoostRequests(ctx, time.Now())
+	require.NoError(t, err)
+	for _, d := range due {
+		require.NotEqual(t, first.ID, d.ID)
+	}
+	due, err = DueBoostRequests(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	var found bool
+	for _, d := range due {
+		found = found || d.ID == first.ID
+	}
+	require.True(t, found)
+
+	require.NoError(t, Expire(ctx, first.ID))
+	r, err = BoostRequestByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.BoostRequestStateExpired, r.State)
+
+	// Once the first boost expires, another can be approved.
+	_, err = Approve(ctx, second.ID, user.ID, nil, ptrs.Ptr(42), apply)
+	require.NoError(t, err)
+}
@@ -0,0 +1,277 @@
+// Package capacitysim simulates how a hypothetical resource pool would schedule a set of job
+// submissions, so that the wait times and utilization of a capacity change can be estimated before
+// it is bought.
+package capacitysim
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+)
+
+// Schedulers the simulation supports.
+const (
+	// PriorityScheduler starts pending jobs in priority order, letting jobs further back in the
+	// queue start when the jobs ahead of them don't fit, like the agent RM priority scheduler
+	// without preemption.
+	PriorityScheduler = "priority"
+	// FIFOScheduler starts pending jobs in submission order, and no job starts while the job at the
+	// head of the queue doesn't fit.
+	FIFOScheduler = "fifo"
+)
+
+// AgentGroup is a number of identical agents in a pool.
+type AgentGroup struct {
+	Count int `json:"count"`
+	Slots int `json:"slots"`
+}
+
+// Pool is the hypothetical configuration of a resource pool.
+type Pool struct {
+	Agents    []AgentGroup `json:"agents"`
+	Scheduler string       `json:"scheduler"`
+}
+
+// Validate implements the check.Validatable interface.
+func (p Pool) Validate() []error {
+	var errs []error
+	if len(p.Agents) == 0 {
+		errs = append(errs, fmt.Errorf("pool must have agents"))
+	}
+	for i, g := range p.Agents {
+		if g.Count <= 0 || g.Slots <= 0 {
+			errs = append(errs, fmt.Errorf("agent group %d must have a positive count and slots", i))
+		}
+	}
+	switch p.Scheduler {
+	case "", PriorityScheduler, FIFOScheduler:
+	default:
+		errs = append(errs, fmt.Errorf("scheduler must be %q or %q", PriorityScheduler, FIFOScheduler))
+	}
+	return errs
+}
+
+// Job is a job submission to simulate. Jobs with smaller priorities are scheduled first.
+type Job struct {
+	SubmitSeconds   float64 `json:"submit_seconds"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Slots           int     `json:"slots"`
+	Priority        int     `json:"priority"`
+}
+
+// Validate implements the check.Validatable interface.
+func (j Job) Validate() []error {
+	if j.SubmitSeconds < 0 || j.DurationSeconds < 0 || j.Slots < 0 {
+		return []error{fmt.Errorf("job submit_seconds, duration_seconds and slots must be >= 0")}
+	}
+	return nil
+}
+
+// Stats summarizes a distribution of durations, in seconds.
+type Stats struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	TotalSlots int `json:"total_slots"`
+	Jobs       int `json:"jobs"`
+	// Unschedulable jobs need more slots than the pool has and never start.
+	Unschedulable int   `json:"unschedulable"`
+	WaitSeconds   Stats `json:"wait_seconds"`
+	// MakespanSeconds is the time from the first submission until the last job finishes.
+	MakespanSeconds float64 `json:"makespan_seconds"`
+	// Utilization is the fraction of the slot time of the pool over the makespan that jobs used.
+	Utilization float64 `json:"utilization"`
+}
+
+// agent is the state of a simulated agent.
+type agent struct {
+	slots int
+	free  int
+}
+
+// running is a simulated job that holds slots on some agents until it ends.
+type running struct {
+	end    float64
+	agents []int
+	slots  []int
+}
+
+type runningHeap []running
+
+func (h runningHeap) Len() int            { return len(h) }
+func (h runningHeap) Less(i, j int) bool  { return h[i].end < h[j].end }
+func (h runningHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runningHeap) Push(x interface{}) { *h = append(*h, x.(running)) }
+func (h *runningHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// Simulate schedules the jobs on the pool and reports how long they waited and how busy the pool
+// was. Jobs that fit on a single agent are placed on the agent that fits them best; larger jobs take
+// whole idle agents, as the agent RM does for distributed trials.
+func Simulate(pool Pool, jobs []Job) Result {
+	var agents []*agent
+	maxAgentSlots := 0
+	res := Result{Jobs: len(jobs)}
+	for _, g := range pool.Agents {
+		for i := 0; i < g.Count; i++ {
+			agents = append(agents, &agent{slots: g.Slots, free: g.Slots})
+		}
+		res.TotalSlots += g.Count * g.Slots
+		maxAgentSlots = max(maxAgentSlots, g.Slots)
+	}
+
+	order := make([]int, len(jobs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return jobs[order[a]].SubmitSeconds < jobs[order[b]].SubmitSeconds
+	})
+
+	var (
+		pending    []int
+		inFlight   runningHeap
+		waits      []float64
+		busy       float64
+		start, end float64
+		next       int
+	)
+	if len(order) > 0 {
+		start = jobs[order[0]].SubmitSeconds
+		end = start
+	}
+	for next < len(order) || len(pending) > 0 {
+		// Advance to the next arrival or completion, whichever comes first.
+		arriving := next < len(order)
+		if !arriving && len(inFlight) == 0 {
+			// Nothing runs and nothing arrives, so the pending jobs can never start.
+			break
+		}
+		var now float64
+		if arriving && (len(inFlight) == 0 || jobs[order[next]].SubmitSeconds < inFlight[0].end) {
+			now = jobs[order[next]].SubmitSeconds
+		} else {
+			now = inFlight[0].end
+		}
+		for len(inFlight) > 0 && inFlight[0].end <= now {
+			r := heap.Pop(&inFlight).(running)
+			for i, a := range r.agents {
+				agents[a].free += r.slots[i]
+			}
+		}
+		for next < len(order) && jobs[order[next]].SubmitSeconds <= now {
+			j := order[next]
+			next++
+			if jobs[j].Slots > res.TotalSlots {
+				res.Unschedulable++
+				continue
+			}
+			pending = append(pending, j)
+		}
+
+		if pool.Scheduler != FIFOScheduler {
+			sort.SliceStable(pending, func(a, b int) bool {
+				return jobs[pending[a]].Priority < jobs[pending[b]].Priority
+			})
+		}
+		var stillPending []int
+		for i, j := range pending {
+			job := jobs[j]
+			placed, slots := place(agents, job.Slots, maxAgentSlots)
+			if placed == nil && job.Slots > 0 {
+				if pool.Scheduler == FIFOScheduler {
+					stillPending = append(stillPending, pending[i:]...)
+					break
+				}
+				stillPending = append(stillPending, j)
+				continue
+			}
+			for k, a := range placed {
+				agents[a].free -= slots[k]
+			}
+			waits = append(waits, now-job.SubmitSeconds)
+			busy += float64(job.Slots) * job.DurationSeconds
+			end = max(end, now+job.DurationSeconds)
+			heap.Push(&inFlight, running{end: now + job.DurationSeconds, agents: placed, slots: slots})
+		}
+		pending = stillPending
+	}
+	res.Unschedulable += len(pending)
+
+	res.WaitSeconds = stats(waits)
+	res.MakespanSeconds = end - start
+	if res.MakespanSeconds > 0 && res.TotalSlots > 0 {
+		res.Utilization = busy / (res.MakespanSeconds * float64(res.TotalSlots))
+	}
+	return res
+}
+
+// place returns the agents a job would run on and the slots it would take on each, or nil if the
+// job doesn't fit now.
+func place(agents []*agent, slots, maxAgentSlots int) ([]int, []int) {
+	if slots == 0 {
+		return nil, nil
+	}
+	if slots <= maxAgentSlots {
+		best := -1
+		for i, a := range agents {
+			if a.free >= slots && (best < 0 || a.free < agents[best].free) {
+				best = i
+			}
+		}
+		if best < 0 {
+			return nil, nil
+		}
+		return []int{best}, []int{slots}
+	}
+
+	var idle []int
+	for i, a := range agents {
+		if a.free == a.slots {
+			idle = append(idle, i)
+		}
+	}
+	sort.SliceStable(idle, func(a, b int) bool { return agents[idle[a]].slots > agents[idle[b]].slots })
+	var placed, taken []int
+	for _, i := range idle {
+		placed = append(placed, i)
+		taken = append(taken, agents[i].slots)
+		slots -= agents[i].slots
+		if slots <= 0 {
+			return placed, taken
+		}
+	}
+	return nil, nil
+}
+
+// stats summarizes xs, or returns zero Stats if xs is empty.
+func stats(xs []float64) Stats {
+	if len(xs) == 0 {
+		return Stats{}
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, x := range sorted {
+		sum += x
+	}
+	return Stats{
+		Mean: sum / float64(len(sorted)),
+		P50:  mathx.Quantile(sorted, 0.5),
+		P90:  mathx.Quantile(sorted, 0.9),
+		P99:  mathx.Quantile(sorted, 0.99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
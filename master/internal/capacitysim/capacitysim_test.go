@@ -0,0 +1,58 @@
+package capacitysim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	pool := Pool{Agents: []AgentGroup{{Count: 2, Slots: 4}}}
+	jobs := []Job{
+		{SubmitSeconds: 0, DurationSeconds: 100, Slots: 4},
+		{SubmitSeconds: 0, DurationSeconds: 100, Slots: 4},
+		// Waits for a whole agent to free up.
+		{SubmitSeconds: 10, DurationSeconds: 100, Slots: 4},
+		// Needs both agents.
+		{SubmitSeconds: 20, DurationSeconds: 50, Slots: 8},
+		{SubmitSeconds: 30, DurationSeconds: 10, Slots: 16},
+	}
+
+	res := Simulate(pool, jobs)
+	require.Equal(t, 8, res.TotalSlots)
+	require.Equal(t, 5, res.Jobs)
+	require.Equal(t, 1, res.Unschedulable)
+	// The 4-slot job starts at 100 and the 8-slot job once it ends at 200.
+	require.Equal(t, 67.5, res.WaitSeconds.Mean)
+	require.Equal(t, 45.0, res.WaitSeconds.P50)
+	require.InDelta(t, 153, res.WaitSeconds.P90, 1e-9)
+	require.Equal(t, 180.0, res.WaitSeconds.Max)
+	require.Equal(t, 250.0, res.MakespanSeconds)
+	require.InDelta(t, (3*400+400)/(250.0*8), res.Utilization, 1e-9)
+}
+
+func TestSimulateSchedulers(t *testing.T) {
+	jobs := []Job{
+		{SubmitSeconds: 0, DurationSeconds: 100, Slots: 2},
+		{SubmitSeconds: 1, DurationSeconds: 100, Slots: 4},
+		{SubmitSeconds: 2, DurationSeconds: 100, Slots: 1},
+	}
+
+	// The small job starts ahead of the job that doesn't fit yet, which then waits for both.
+	res := Simulate(Pool{Agents: []AgentGroup{{Count: 1, Slots: 4}}}, jobs)
+	require.Equal(t, 0.0, res.WaitSeconds.P50)
+	require.Equal(t, 101.0, res.WaitSeconds.Max)
+	require.Equal(t, 202.0, res.MakespanSeconds)
+
+	res = Simulate(Pool{Agents: []AgentGroup{{Count: 1, Slots: 4}}, Scheduler: FIFOScheduler}, jobs)
+	require.Equal(t, 198.0, res.WaitSeconds.Max)
+	require.Equal(t, 300.0, res.MakespanSeconds)
+
+	// Smaller priorities go first.
+	jobs[2].Priority = -1
+	jobs[2].SubmitSeconds = 0
+	jobs[2].Slots = 4
+	res = Simulate(Pool{Agents: []AgentGroup{{Count: 1, Slots: 4}}}, jobs)
+	require.Equal(t, 300.0, res.MakespanSeconds)
+	require.Equal(t, 199.0, res.WaitSeconds.Max)
+}
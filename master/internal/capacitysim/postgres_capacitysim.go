@@ -0,0 +1,50 @@
+package capacitysim
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// maxReplayJobs caps how many allocations a replay simulates.
+const maxReplayJobs = 100000
+
+// Replay returns the allocations of a resource pool that started since the given time as job
+// submissions, timed from the first of them. Jobs are submitted when their allocations were queued
+// and run for as long as the allocations did; the priorities they ran at aren't recorded, so all
+// jobs are replayed at the same priority.
+func Replay(ctx context.Context, pool string, since time.Time) ([]Job, error) {
+	var allocations []struct {
+		SubmitTime      time.Time `bun:"submit_time"`
+		DurationSeconds float64   `bun:"duration_seconds"`
+		Slots           int       `bun:"slots"`
+	}
+	if err := db.Bun().NewSelect().
+		TableExpr("allocations AS a").
+		ColumnExpr(`COALESCE((
+			SELECT MIN(q.start_time) FROM task_stats AS q
+			WHERE q.allocation_id = a.allocation_id AND q.event_type = 'QUEUED'
+		), a.start_time) AS submit_time`).
+		ColumnExpr("EXTRACT(EPOCH FROM (a.end_time - a.start_time)) AS duration_seconds").
+		Column("a.slots").
+		Where("a.resource_pool = ?", pool).
+		Where("a.start_time >= ?", since).
+		Where("a.end_time IS NOT NULL").
+		OrderExpr("submit_time").
+		Limit(maxReplayJobs).
+		Scan(ctx, &allocations); err != nil {
+		return nil, fmt.Errorf("getting allocations of resource pool %s to replay: %w", pool, err)
+	}
+
+	jobs := make([]Job, 0, len(allocations))
+	for _, a := range allocations {
+		jobs = append(jobs, Job{
+			SubmitSeconds:   a.SubmitTime.Sub(allocations[0].SubmitTime).Seconds(),
+			DurationSeconds: a.DurationSeconds,
+			Slots:           a.Slots,
+		})
+	}
+	return jobs, nil
+}
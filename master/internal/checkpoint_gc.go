@@ -175,6 +175,7 @@ func runCheckpointGCTask(
 	err = task.DefaultService.StartAllocation(logCtx, sproto.AllocateRequest{
 		TaskID:            taskID,
 		JobID:             gcJobID,
+		TaskType:          model.TaskTypeCheckpointGC,
 		JobSubmissionTime: jobSubmissionTime,
 		AllocationID:      allocationID,
 		Name:              fmt.Sprintf("Checkpoint GC (Experiment %d)", expID),
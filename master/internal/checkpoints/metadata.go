@@ -0,0 +1,54 @@
+package checkpoints
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/expmetadata"
+)
+
+// MergeMetadataPatch returns the metadata of a checkpoint with a JSON merge patch (RFC 7386)
+// applied: keys set to null are removed, objects are merged recursively and any other value
+// replaces the value it is set on. metadata is not modified.
+func MergeMetadataPatch(metadata, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		switch v := v.(type) {
+		case nil:
+			delete(merged, k)
+		case map[string]interface{}:
+			prev, _ := merged[k].(map[string]interface{})
+			merged[k] = MergeMetadataPatch(prev, v)
+		default:
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// ValidateMetadata returns an error if the metadata of a checkpoint does not follow the checkpoint
+// metadata schema of its project. A nil schema accepts any metadata.
+func ValidateMetadata(schema json.RawMessage, metadata map[string]interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	compiled, err := expmetadata.CompileSchema(schema)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	doc, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	if err := compiled.Validate(bytes.NewReader(doc)); err != nil {
+		return fmt.Errorf("metadata does not follow the project's checkpoint metadata schema: %w", err)
+	}
+	return nil
+}
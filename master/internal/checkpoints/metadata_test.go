@@ -0,0 +1,48 @@
+package checkpoints
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeMetadataPatch(t *testing.T) {
+	metadata := map[string]interface{}{
+		"framework": "torch",
+		"eval":      map[string]interface{}{"mmlu": 0.5, "gsm8k": 0.3},
+		"stale":     true,
+	}
+	merged := MergeMetadataPatch(metadata, map[string]interface{}{
+		"eval":         map[string]interface{}{"mmlu": 0.6, "gsm8k": nil},
+		"stale":        nil,
+		"quantization": map[string]interface{}{"bits": 4.0},
+	})
+	require.Equal(t, map[string]interface{}{
+		"framework":    "torch",
+		"eval":         map[string]interface{}{"mmlu": 0.6},
+		"quantization": map[string]interface{}{"bits": 4.0},
+	}, merged)
+
+	// The metadata patched is left as it was.
+	require.Equal(t, 0.5, metadata["eval"].(map[string]interface{})["mmlu"])
+	require.Contains(t, metadata, "stale")
+}
+
+func TestValidateMetadata(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"required": ["eval"],
+		"properties": {"eval": {"type": "object", "additionalProperties": {"type": "number"}}}
+	}`)
+
+	require.NoError(t, ValidateMetadata(nil, map[string]interface{}{"anything": []interface{}{1.0}}))
+	require.NoError(t, ValidateMetadata(schema, map[string]interface{}{
+		"eval": map[string]interface{}{"mmlu": 0.6},
+	}))
+	require.Error(t, ValidateMetadata(schema, nil))
+	require.Error(t, ValidateMetadata(schema, map[string]interface{}{
+		"eval": map[string]interface{}{"mmlu": "high"},
+	}))
+	require.Error(t, ValidateMetadata(json.RawMessage(`[]`), nil))
+}
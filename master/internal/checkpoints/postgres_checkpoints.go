@@ -29,6 +29,33 @@ func CheckpointByUUID(ctx context.Context, id uuid.UUID) (*model.Checkpoint, err
 	return &checkpoint, nil
 }
 
+// AddImportedCheckpoint records a completed checkpoint that was saved outside of Determined, so it
+// has no task or allocation.
+func AddImportedCheckpoint(ctx context.Context, ckpt *model.CheckpointV2) error {
+	ckpt.Size = 0
+	for _, size := range ckpt.Resources {
+		ckpt.Size += size
+	}
+	if _, err := db.Bun().NewInsert().Model(ckpt).
+		ExcludeColumn("task_id", "allocation_id").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("adding imported checkpoint %s: %w", ckpt.UUID, err)
+	}
+	return nil
+}
+
+// DeleteImportedCheckpoint deletes the record of a checkpoint that was saved outside of
+// Determined, leaving its files in place.
+func DeleteImportedCheckpoint(ctx context.Context, id uuid.UUID) error {
+	if _, err := db.Bun().NewDelete().Model((*model.CheckpointV2)(nil)).
+		Where("uuid = ?", id).
+		Where("task_id IS NULL").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("deleting imported checkpoint %s: %w", id, err)
+	}
+	return nil
+}
+
 // CheckpointByUUIDs looks up a checkpoint by list of UUIDS, returning nil if error.
 func CheckpointByUUIDs(ctx context.Context, ckptUUIDs []uuid.UUID) ([]model.Checkpoint, error) {
 	var checkpoints []model.Checkpoint
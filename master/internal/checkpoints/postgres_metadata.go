@@ -0,0 +1,148 @@
+package checkpoints
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/expmetadata"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// MetadataSchema returns the checkpoint metadata schema of a project, or nil if the project has
+// none.
+func MetadataSchema(ctx context.Context, projectID int) (*model.ProjectCheckpointMetadataSchema, error) {
+	s := &model.ProjectCheckpointMetadataSchema{ProjectID: projectID}
+	err := db.Bun().NewSelect().Model(s).WherePK().Scan(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting checkpoint metadata schema of project %d: %w", projectID, err)
+	}
+	return s, nil
+}
+
+// SetMetadataSchema sets the checkpoint metadata schema of a project. Checkpoints already in the
+// project are not revalidated; their metadata is validated against the schema the next time it is
+// changed.
+func SetMetadataSchema(ctx context.Context, projectID int, schema json.RawMessage) error {
+	if _, err := expmetadata.CompileSchema(schema); err != nil {
+		return fmt.Errorf("%w: %s", db.ErrInvalidInput, err)
+	}
+	s := &model.ProjectCheckpointMetadataSchema{
+		ProjectID: projectID,
+		Schema:    schema,
+		UpdatedAt: time.Now(),
+	}
+	_, err := db.Bun().NewInsert().Model(s).
+		On("CONFLICT (project_id) DO UPDATE").
+		Set("schema = EXCLUDED.schema").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting checkpoint metadata schema of project %d: %w", projectID, err)
+	}
+	return nil
+}
+
+// DeleteMetadataSchema removes the checkpoint metadata schema of a project, so that its
+// checkpoints may have any metadata.
+func DeleteMetadataSchema(ctx context.Context, projectID int) error {
+	_, err := db.Bun().NewDelete().Model((*model.ProjectCheckpointMetadataSchema)(nil)).
+		Where("project_id = ?", projectID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting checkpoint metadata schema of project %d: %w", projectID, err)
+	}
+	return nil
+}
+
+// UpdateMetadata changes the metadata of a checkpoint to what update returns given its current
+// metadata, after validating it against the checkpoint metadata schema of the project of the
+// checkpoint's experiment, and records the change. Checkpoints that aren't from an experiment
+// are not validated.
+func UpdateMetadata(
+	ctx context.Context, id uuid.UUID, changedBy model.UserID,
+	update func(map[string]interface{}) map[string]interface{},
+) (map[string]interface{}, error) {
+	var metadata map[string]interface{}
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var raw []byte
+		err := tx.NewSelect().Table("checkpoints_v2").Column("metadata").
+			Where("uuid = ?", id).
+			For("UPDATE").
+			Scan(ctx, &raw)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("checkpoint %s: %w", id, db.ErrNotFound)
+		} else if err != nil {
+			return fmt.Errorf("getting metadata of checkpoint %s: %w", id, err)
+		}
+		previous := map[string]interface{}{}
+		if raw != nil {
+			if err = json.Unmarshal(raw, &previous); err != nil {
+				return fmt.Errorf("parsing metadata of checkpoint %s: %w", id, err)
+			}
+			if previous == nil {
+				previous = map[string]interface{}{}
+			}
+		}
+		if metadata = update(previous); metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+
+		var schema json.RawMessage
+		err = tx.NewSelect().Table("project_checkpoint_metadata_schemas").Column("schema").
+			Where("project_id = (?)", tx.NewSelect().
+				Table("checkpoints_view").
+				Join("JOIN experiments e ON e.id = checkpoints_view.experiment_id").
+				ColumnExpr("e.project_id").
+				Where("checkpoints_view.uuid = ?", id)).
+			Scan(ctx, &schema)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("getting checkpoint metadata schema of checkpoint %s: %w", id, err)
+		}
+		if err = ValidateMetadata(schema, metadata); err != nil {
+			return fmt.Errorf("%w: %s", db.ErrInvalidInput, err)
+		}
+
+		if _, err = tx.NewUpdate().Table("checkpoints_v2").
+			Set("metadata = ?", metadata).
+			Where("uuid = ?", id).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("setting metadata of checkpoint %s: %w", id, err)
+		}
+		if _, err = tx.NewInsert().Model(&model.CheckpointMetadataChange{
+			CheckpointUUID: id,
+			ChangedBy:      &changedBy,
+			Previous:       previous,
+			Metadata:       metadata,
+		}).Exec(ctx); err != nil {
+			return fmt.Errorf("recording metadata change of checkpoint %s: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// MetadataHistory returns the changes made to the metadata of a checkpoint, oldest first.
+func MetadataHistory(ctx context.Context, id uuid.UUID) ([]model.CheckpointMetadataChange, error) {
+	changes := []model.CheckpointMetadataChange{}
+	if err := db.Bun().NewSelect().Model(&changes).
+		Where("checkpoint_uuid = ?", id).
+		Order("id").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting metadata history of checkpoint %s: %w", id, err)
+	}
+	return changes, nil
+}
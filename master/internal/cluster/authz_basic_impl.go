@@ -54,6 +54,16 @@ func (a *MiscAuthZBasic) CanUpdateMasterConfig(
 	return nil, nil
 }
 
+// CanProfileMaster checks if user has access to profile the master.
+func (a *MiscAuthZBasic) CanProfileMaster(
+	ctx context.Context, curUser *model.User,
+) (permErr error, err error) {
+	if !curUser.Admin {
+		return grpcutil.ErrPermissionDenied, nil
+	}
+	return nil, nil
+}
+
 // CanGetUsageDetails returns nil and nil error.
 func (a *MiscAuthZBasic) CanGetUsageDetails(
 	ctx context.Context, curUser *model.User,
@@ -68,6 +78,16 @@ func (a *MiscAuthZBasic) CanViewExternalJobs(
 	return nil, nil
 }
 
+// CanViewFederation checks if the user is an admin, since peers are read with one token.
+func (a *MiscAuthZBasic) CanViewFederation(
+	ctx context.Context, curUser *model.User,
+) (permErr error, err error) {
+	if !curUser.Admin {
+		return grpcutil.ErrPermissionDenied, nil
+	}
+	return nil, nil
+}
+
 func init() {
 	AuthZProvider.Register("basic", &MiscAuthZBasic{})
 }
@@ -47,6 +47,12 @@ type MiscAuthZ interface {
 		ctx context.Context, curUser *model.User,
 	) (permErr error, err error)
 
+	// CanProfileMaster returns an error if the user is not authorized to profile the master or
+	// dump its internal state.
+	CanProfileMaster(
+		ctx context.Context, curUser *model.User,
+	) (permErr error, err error)
+
 	// CanGetHistoricalUsage returns an error if the user is not authorized to get usage
 	// related information.
 	CanGetUsageDetails(
@@ -57,6 +63,12 @@ type MiscAuthZ interface {
 	CanViewExternalJobs(
 		ctx context.Context, curUser *model.User,
 	) (permErr error, err error)
+
+	// CanViewFederation returns an error if the user is not authorized to view the experiments
+	// and jobs of federation peers.
+	CanViewFederation(
+		ctx context.Context, curUser *model.User,
+	) (permErr error, err error)
 }
 
 // AuthZProvider is the authz registry for Notebooks, Shells, and Commands.
@@ -3,9 +3,11 @@ package cluster
 import (
 	"net/http"
 
-	detContext "github.com/determined-ai/determined/master/internal/context"
-
 	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/rbac/audit"
 )
 
 // CanGetUsageDetails returns an echo middleware that checks if the user has permission to get
@@ -25,3 +27,25 @@ func CanGetUsageDetails() echo.MiddlewareFunc {
 		}
 	})
 }
+
+// CanProfileMaster returns an echo middleware that checks if the user has permission to profile
+// the master. Since profiling can slow the master down, each request that is let through is logged.
+func CanProfileMaster() echo.MiddlewareFunc {
+	return echo.MiddlewareFunc(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			ctx := c.Request().Context()
+			user := c.(*detContext.DetContext).MustGetUser()
+			permErr, err := AuthZProvider.Get().CanProfileMaster(ctx, &user)
+			if err != nil {
+				return err
+			}
+			if permErr != nil {
+				return echo.NewHTTPError(http.StatusForbidden, permErr.Error())
+			}
+			log.WithFields(audit.ExtractLogFields(ctx)).
+				WithField("username", user.Username).
+				Infof("profiling master: %s", c.Request().URL.Path)
+			return next(c)
+		}
+	})
+}
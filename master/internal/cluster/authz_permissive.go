@@ -49,6 +49,14 @@ func (a *MiscAuthZPermissive) CanUpdateMasterConfig(
 	return (&MiscAuthZBasic{}).CanUpdateMasterConfig(ctx, curUser)
 }
 
+// CanProfileMaster calls the RBAC implementation but always allows access.
+func (a *MiscAuthZPermissive) CanProfileMaster(
+	ctx context.Context, curUser *model.User,
+) (permErr error, err error) {
+	_, _ = (&MiscAuthZRBAC{}).CanProfileMaster(ctx, curUser)
+	return (&MiscAuthZBasic{}).CanProfileMaster(ctx, curUser)
+}
+
 // CanGetUsageDetails calls the RBAC implementation but always allows access.
 func (a *MiscAuthZPermissive) CanGetUsageDetails(
 	ctx context.Context, curUser *model.User,
@@ -65,6 +73,14 @@ func (a *MiscAuthZPermissive) CanViewExternalJobs(
 	return (&MiscAuthZBasic{}).CanViewExternalJobs(ctx, curUser)
 }
 
+// CanViewFederation calls the RBAC implementation but always allows access.
+func (a *MiscAuthZPermissive) CanViewFederation(
+	ctx context.Context, curUser *model.User,
+) (permErr error, err error) {
+	_, _ = (&MiscAuthZRBAC{}).CanViewFederation(ctx, curUser)
+	return (&MiscAuthZBasic{}).CanViewFederation(ctx, curUser)
+}
+
 func init() {
 	AuthZProvider.Register("permissive", &MiscAuthZPermissive{})
 }
@@ -81,6 +81,19 @@ func (a *MiscAuthZRBAC) CanUpdateMasterConfig(
 	)
 }
 
+// CanProfileMaster checks if the user has permission to profile the master. Profiles expose as
+// much as the master config does and can slow the master down, so this takes the permission to
+// update the master config, which only cluster admins have.
+func (a *MiscAuthZRBAC) CanProfileMaster(
+	ctx context.Context, curUser *model.User,
+) (permErr error, err error) {
+	return a.checkForPermission(
+		ctx,
+		curUser,
+		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_MASTER_CONFIG,
+	)
+}
+
 // CanGetUsageDetails checks if the user can get usage related details.
 func (a *MiscAuthZRBAC) CanGetUsageDetails(
 	ctx context.Context, curUser *model.User,
@@ -103,6 +116,17 @@ func (a *MiscAuthZRBAC) CanViewExternalJobs(
 	)
 }
 
+// CanViewFederation checks if the user can view the experiments and jobs of federation peers.
+func (a *MiscAuthZRBAC) CanViewFederation(
+	ctx context.Context, curUser *model.User,
+) (permErr error, err error) {
+	return a.checkForPermission(
+		ctx,
+		curUser,
+		rbacv1.PermissionType_PERMISSION_TYPE_VIEW_FEDERATION,
+	)
+}
+
 // CanModifyGlobalConfigPolicies checks if the user can modify global
 // task config policies.
 func (a *MiscAuthZRBAC) CanModifyGlobalConfigPolicies(
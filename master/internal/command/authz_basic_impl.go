@@ -5,6 +5,7 @@ import (
 
 	"github.com/determined-ai/determined/proto/pkg/tensorboardv1"
 
+	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/pkg/model"
 )
@@ -31,6 +32,20 @@ func (a *NSCAuthZBasic) CanTerminateNSC(
 	return nil
 }
 
+// CanExecInNSC returns an error if the NSC is not owned by the current user and the current user
+// is not an admin, since processes started in it run as its owner.
+func (a *NSCAuthZBasic) CanExecInNSC(
+	ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
+	ownerID model.UserID,
+) error {
+	if !curUser.Admin && curUser.ID != ownerID {
+		return authz.PermissionDeniedError{}.WithPrefix(
+			"non-admin users may not exec into other users' tasks",
+		)
+	}
+	return nil
+}
+
 // CanCreateNSC always returns a nil error.
 func (a *NSCAuthZBasic) CanCreateNSC(
 	ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
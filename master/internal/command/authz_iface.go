@@ -26,6 +26,12 @@ type NSCAuthZ interface {
 		ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
 	) error
 
+	// GET /commands/:command_id/exec
+	CanExecInNSC(
+		ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
+		ownerID model.UserID,
+	) error
+
 	// POST /api/v1/NSCs
 	CanCreateNSC(
 		ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
@@ -34,6 +34,16 @@ func (a *NSCAuthZPermissive) CanTerminateNSC(
 	return (&NSCAuthZBasic{}).CanTerminateNSC(ctx, curUser, workspaceID)
 }
 
+// CanExecInNSC returns an error if the NSC is not owned by the current user and the current user
+// is not an admin.
+func (a *NSCAuthZPermissive) CanExecInNSC(
+	ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
+	ownerID model.UserID,
+) error {
+	_ = (&NSCAuthZRBAC{}).CanExecInNSC(ctx, curUser, workspaceID, ownerID)
+	return (&NSCAuthZBasic{}).CanExecInNSC(ctx, curUser, workspaceID, ownerID)
+}
+
 // CanCreateNSC always returns a nil error.
 func (a *NSCAuthZPermissive) CanCreateNSC(
 	ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
@@ -119,6 +119,20 @@ func (a *NSCAuthZRBAC) CanTerminateNSC(
 		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_NSC)
 }
 
+// CanExecInNSC checks if the user is authorized to exec into NSCs in the workspace. Since processes
+// started in an NSC run as its owner, exec into other users' NSCs requires a separate permission.
+func (a *NSCAuthZRBAC) CanExecInNSC(
+	ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
+	ownerID model.UserID,
+) error {
+	if curUser.ID != ownerID {
+		return a.checkForPermission(ctx, curUser, workspaceID,
+			rbacv1.PermissionType_PERMISSION_TYPE_EXEC_OTHER_USER_NSC)
+	}
+	return a.checkForPermission(ctx, curUser, workspaceID,
+		rbacv1.PermissionType_PERMISSION_TYPE_EXEC_NSC)
+}
+
 // CanCreateNSC checks if the user is authorized to create NSCs in the workspace.
 func (a *NSCAuthZRBAC) CanCreateNSC(
 	ctx context.Context, curUser model.User, workspaceID model.AccessScopeID,
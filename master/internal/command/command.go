@@ -163,9 +163,12 @@ func (c *Command) Start(ctx context.Context) error {
 			AllocationID:        c.allocationID,
 			TaskID:              c.taskID,
 			JobID:               c.jobID,
+			TaskType:            c.taskType,
 			JobSubmissionTime:   c.registeredTime,
 			IsUserVisible:       true,
 			Name:                c.Config.Description,
+			WorkspaceID:         int(c.Metadata.WorkspaceID),
+			OwnerID:             c.Base.Owner.ID,
 			SlotsNeeded:         c.Config.Resources.Slots,
 			ResourcePool:        c.Config.Resources.ResourcePool,
 			FittingRequirements: sproto.FittingRequirements{SingleAgent: true},
@@ -11,6 +11,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -247,6 +248,32 @@ func (cs *CommandService) DeleteWorkspaceNTSC(req *apiv1.DeleteWorkspaceRequest)
 	}
 }
 
+// ReassignOwner runs reassign, which gives commands to a new owner in the database and returns
+// their IDs, and then gives the same commands to the new owner in memory. Commands aren't launched
+// or killed meanwhile, so the database and memory agree on who owns each command.
+func (cs *CommandService) ReassignOwner(
+	to model.User, reassign func() ([]model.TaskID, error),
+) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	ids, err := reassign()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		c, ok := cs.commands[id]
+		if !ok {
+			continue
+		}
+		c.mu.Lock()
+		owner := to
+		c.Base.Owner = &owner
+		c.mu.Unlock()
+	}
+	return nil
+}
+
 // SetNTSCPriority sets the NTSC's resource manager group priority.
 func (cs *CommandService) SetNTSCPriority(
 	id string, priority int, taskType model.TaskType,
@@ -293,6 +320,28 @@ func (cs *CommandService) KillNTSC(id string, taskType model.TaskType) (*Command
 	return c, nil
 }
 
+// ExecCommand starts a process in the container of a running command and relays it. The start of
+// the session is recorded in the command's logs, so its owner can see who ran what in it.
+func (cs *CommandService) ExecCommand(
+	ctx context.Context, id string, curUser model.User, req sproto.ExecRequest,
+) (*sproto.ExecSession, error) {
+	cs.mu.Lock()
+	c, err := cs.getNTSC(model.TaskID(id), model.TaskTypeCommand)
+	cs.mu.Unlock()
+	if err != nil {
+		return nil, api.NotFoundErrs("command", id, true)
+	}
+
+	session, err := task.DefaultService.Exec(ctx, c.allocationID, req)
+	if err != nil {
+		return nil, err
+	}
+	msg := fmt.Sprintf("user %s started an exec session: %s",
+		curUser.Username, strings.Join(req.Cmd, " "))
+	task.DefaultService.SendLog(ctx, c.allocationID, &sproto.ContainerLog{AuxMessage: &msg})
+	return session, nil
+}
+
 // GetCommands returns all commands in the command service registry matching the workspace ID.
 func (cs *CommandService) GetCommands(req *apiv1.GetCommandsRequest) (*apiv1.GetCommandsResponse, error) {
 	cs.mu.Lock()
@@ -36,6 +36,7 @@ func GetCommandOwnerID(ctx context.Context, taskID model.TaskID) (model.UserID,
 type TaskMetadata struct {
 	bun.BaseModel `bun:"table:command_state"`
 	WorkspaceID   model.AccessScopeID `bun:"workspace_id"`
+	OwnerID       model.UserID        `bun:"owner_id"`
 	TaskType      model.TaskType      `bun:"task_type"`
 	ExperimentIDs []int32             `bun:"experiment_ids"`
 	TrialIDs      []int32             `bun:"trial_ids"`
@@ -47,6 +48,7 @@ func IdentifyTask(ctx context.Context, taskID model.TaskID) (TaskMetadata, error
 	metadata := TaskMetadata{}
 	if err := db.Bun().NewSelect().Model(&metadata).
 		ColumnExpr("generic_command_spec->'Metadata'->'workspace_id' AS workspace_id").
+		ColumnExpr("generic_command_spec->'Base'->'Owner'->'id' AS owner_id").
 		ColumnExpr("generic_command_spec->>'TaskType' as task_type").
 		ColumnExpr("generic_command_spec->'Metadata'->'experiment_ids' as experiment_ids").
 		ColumnExpr("generic_command_spec->'Metadata'->'trial_ids' as trial_ids").
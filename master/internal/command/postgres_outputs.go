@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// OutputsStoragePrefix returns the storage path under which a command's declared outputs are
+// uploaded when it exits.
+func OutputsStoragePrefix(taskID model.TaskID) string {
+	return fmt.Sprintf("outputs/%s", taskID)
+}
+
+// RecordTaskOutputs records outputs a task uploaded to storage, replacing earlier records for
+// the same paths.
+func RecordTaskOutputs(ctx context.Context, outputs []model.TaskOutput) error {
+	if len(outputs) == 0 {
+		return nil
+	}
+	if _, err := db.Bun().NewInsert().Model(&outputs).
+		On("CONFLICT (task_id, path) DO UPDATE").
+		Set("storage_path = EXCLUDED.storage_path").
+		Set("size_bytes = EXCLUDED.size_bytes").
+		Set("created_at = NOW()").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("recording outputs of task %s: %w", outputs[0].TaskID, err)
+	}
+	return nil
+}
+
+// TaskOutputsByTask returns the outputs a task uploaded to storage.
+func TaskOutputsByTask(ctx context.Context, taskID model.TaskID) ([]model.TaskOutput, error) {
+	outputs := []model.TaskOutput{}
+	if err := db.Bun().NewSelect().Model(&outputs).
+		Where("task_id = ?", taskID).
+		Order("path ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting outputs of task %s: %w", taskID, err)
+	}
+	return outputs, nil
+}
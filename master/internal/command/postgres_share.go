@@ -0,0 +1,88 @@
+package command
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// NotebookTokenForTask returns the notebook session token the notebook server for a task was
+// launched with. The proxy presents it on behalf of users the notebook has been shared with.
+func NotebookTokenForTask(ctx context.Context, taskID model.TaskID) (string, error) {
+	var token string
+	err := db.Bun().NewSelect().Table("command_state").
+		ColumnExpr("generic_command_spec->'Base'->'ExtraEnvVars'->>? AS token",
+			model.NotebookSessionEnvVar).
+		Where("task_id = ?", taskID).
+		Scan(ctx, &token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", db.ErrNotFound
+	} else if err != nil {
+		return "", fmt.Errorf("getting notebook token for task %s: %w", taskID, err)
+	}
+	return token, nil
+}
+
+// UpsertProxyShare grants a user proxied access to a task, replacing any existing grant.
+func UpsertProxyShare(ctx context.Context, share *model.TaskProxyShare) error {
+	if _, err := db.Bun().NewInsert().Model(share).
+		On("CONFLICT (task_id, user_id) DO UPDATE").
+		Set("access = EXCLUDED.access").
+		Set("granted_by = EXCLUDED.granted_by").
+		Returning("id, created_at").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("sharing task %s with user %d: %w", share.TaskID, share.UserID, err)
+	}
+	return nil
+}
+
+// DeleteProxyShare revokes a user's proxied access to a task.
+func DeleteProxyShare(ctx context.Context, taskID model.TaskID, userID model.UserID) error {
+	res, err := db.Bun().NewDelete().Model((*model.TaskProxyShare)(nil)).
+		Where("task_id = ?", taskID).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("unsharing task %s with user %d: %w", taskID, userID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// ProxySharesByTask returns every grant of proxied access to a task.
+func ProxySharesByTask(ctx context.Context, taskID model.TaskID) ([]model.TaskProxyShare, error) {
+	shares := []model.TaskProxyShare{}
+	if err := db.Bun().NewSelect().Model(&shares).
+		Where("task_id = ?", taskID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting shares of task %s: %w", taskID, err)
+	}
+	return shares, nil
+}
+
+// ProxyShareForUser returns a user's grant of proxied access to a task, or db.ErrNotFound.
+func ProxyShareForUser(
+	ctx context.Context, taskID model.TaskID, userID model.UserID,
+) (*model.TaskProxyShare, error) {
+	var share model.TaskProxyShare
+	err := db.Bun().NewSelect().Model(&share).
+		Where("task_id = ?", taskID).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("getting share of task %s for user %d: %w", taskID, userID, err)
+	}
+	return &share, nil
+}
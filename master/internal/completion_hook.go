@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/determined-ai/determined/master/internal/command"
+	internaldb "github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+)
+
+// Environment variables the completion hook of an experiment gets.
+const (
+	completionHookExperimentIDEnvVar         = "DET_EXPERIMENT_ID"
+	completionHookExperimentStateEnvVar      = "DET_EXPERIMENT_STATE"
+	completionHookSearcherMetricEnvVar       = "DET_SEARCHER_METRIC"
+	completionHookBestCheckpointUUIDEnvVar   = "DET_BEST_CHECKPOINT_UUID"
+	completionHookBestCheckpointTrialEnvVar  = "DET_BEST_CHECKPOINT_TRIAL_ID"
+	completionHookBestCheckpointStepsEnvVar  = "DET_BEST_CHECKPOINT_STEPS_COMPLETED"
+	completionHookBestCheckpointMetricEnvVar = "DET_BEST_CHECKPOINT_METRIC_VALUE"
+)
+
+// completionHookEnvVars returns the environment variables that tell the completion hook of an
+// experiment how the experiment ended. The best checkpoint variables are left out if the experiment
+// has no checkpoint with a value of its searcher metric.
+func completionHookEnvVars(
+	experimentID int, state model.State, searcherMetric string, best *internaldb.BestCheckpoint,
+) map[string]string {
+	env := map[string]string{
+		"DET_TASK_TYPE":                     string(model.TaskTypeCommand),
+		completionHookExperimentIDEnvVar:    strconv.Itoa(experimentID),
+		completionHookExperimentStateEnvVar: string(state),
+		completionHookSearcherMetricEnvVar:  searcherMetric,
+	}
+	if best != nil {
+		env[completionHookBestCheckpointUUIDEnvVar] = best.UUID
+		env[completionHookBestCheckpointTrialEnvVar] = strconv.Itoa(best.TrialID)
+		env[completionHookBestCheckpointStepsEnvVar] = strconv.Itoa(best.StepsCompleted)
+		env[completionHookBestCheckpointMetricEnvVar] = strconv.FormatFloat(
+			best.SearcherMetric, 'g', -1, 64)
+	}
+	return env
+}
+
+// launchCompletionHook launches the completion hook of the experiment as a command owned by the
+// owner of the experiment, if it has a hook that runs on the terminal state the experiment is in.
+// The command uses the image and environment of the experiment and runs in its resource pool.
+func (e *internalExperiment) launchCompletionHook(taskSpec tasks.TaskSpec) error {
+	hook := e.activeConfig.CompletionHook()
+	if hook == nil || !hook.RunsOn(string(e.State)) {
+		return nil
+	}
+	if taskSpec.Owner == nil {
+		return fmt.Errorf("experiment %d has no owner to run its completion hook as", e.ID)
+	}
+
+	ctx := context.TODO()
+	searcher := e.activeConfig.Searcher()
+	best, err := internaldb.ExperimentBestCheckpoint(ctx, e.ID, searcher.SmallerIsBetter())
+	if err != nil {
+		return err
+	}
+
+	// The session of the experiment is deleted when it stops, so the hook gets its own, which is
+	// deleted when the hook exits.
+	token, err := user.StartSession(ctx, taskSpec.Owner)
+	if err != nil {
+		return fmt.Errorf("starting a session for the completion hook: %w", err)
+	}
+	taskSpec.UserSessionToken = token
+
+	workspaceID := model.AccessScopeID(model.DefaultWorkspaceID)
+	if w, err := workspace.WorkspaceByProjectID(ctx, e.ProjectID); err != nil {
+		return err
+	} else if w != nil {
+		workspaceID = model.AccessScopeID(resolveWorkspaceID(w))
+		taskSpec.Workspace = w.Name
+		taskSpec.WorkspaceID = w.ID
+	}
+
+	env := e.activeConfig.Environment()
+	config := model.DefaultConfig(&taskSpec.TaskContainerDefaults)
+	config.Description = fmt.Sprintf("Completion hook of experiment %d", e.ID)
+	config.Environment.Image = model.RuntimeItem{
+		CPU:  env.Image().CPU(),
+		CUDA: env.Image().CUDA(),
+		ROCM: env.Image().ROCM(),
+	}
+	config.Environment.EnvironmentVariables = model.RuntimeItems{
+		CPU:  env.EnvironmentVariables().CPU(),
+		CUDA: env.EnvironmentVariables().CUDA(),
+		ROCM: env.EnvironmentVariables().ROCM(),
+	}
+	config.Environment.EnvironmentVariableSets = env.EnvironmentVariableSets()
+	config.Environment.RegistryAuth = env.RegistryAuth()
+	config.Environment.ForcePullImage = env.ForcePullImage()
+	fillTaskConfig(hook.Slots(), taskSpec, &config.Environment)
+	config.Resources.ResourcePool = e.activeConfig.Resources().ResourcePool()
+	config.Resources.Slots = hook.Slots()
+	config.Resources.Priority = e.activeConfig.Resources().Priority()
+	config.Entrypoint = append([]string{commandEntrypoint}, hook.Command()...)
+	if err := check.Validate(config); err != nil {
+		return fmt.Errorf("invalid completion hook config: %w", err)
+	}
+
+	spec := tasks.GenericCommandSpec{
+		Base:   taskSpec,
+		Config: config,
+		AdditionalFiles: archive.Archive{
+			taskSpec.AgentUserGroup.OwnedArchiveItem(
+				commandEntrypoint,
+				etc.MustStaticFile(etc.CommandEntrypointResource),
+				0o700,
+				tar.TypeReg,
+			),
+		},
+	}
+	spec.Base.ExtraEnvVars = completionHookEnvVars(e.ID, e.State, searcher.Metric(), best)
+	spec.Metadata.WorkspaceID = workspaceID
+	spec.Metadata.ExperimentIDs = []int32{int32(e.ID)}
+
+	cmd, err := command.DefaultCmdService.LaunchGenericCommand(
+		model.TaskTypeCommand, model.JobTypeCommand, &command.CreateGeneric{Spec: &spec})
+	if err != nil {
+		return fmt.Errorf("launching completion hook: %w", err)
+	}
+	e.syslog.Infof("launched completion hook %s", cmd.ToV1Command().Id)
+	return nil
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	internaldb "github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestCompletionHookEnvVars(t *testing.T) {
+	env := completionHookEnvVars(7, model.CompletedState, "loss", &internaldb.BestCheckpoint{
+		UUID:           "5b8c4a0e-54bb-4d6a-9e2b-0c6e8f0a4f51",
+		TrialID:        12,
+		StepsCompleted: 300,
+		SearcherMetric: 0.25,
+	})
+	require.Equal(t, map[string]string{
+		"DET_TASK_TYPE":                       "COMMAND",
+		"DET_EXPERIMENT_ID":                   "7",
+		"DET_EXPERIMENT_STATE":                "COMPLETED",
+		"DET_SEARCHER_METRIC":                 "loss",
+		"DET_BEST_CHECKPOINT_UUID":            "5b8c4a0e-54bb-4d6a-9e2b-0c6e8f0a4f51",
+		"DET_BEST_CHECKPOINT_TRIAL_ID":        "12",
+		"DET_BEST_CHECKPOINT_STEPS_COMPLETED": "300",
+		"DET_BEST_CHECKPOINT_METRIC_VALUE":    "0.25",
+	}, env)
+
+	// Without a checkpoint, the hook still learns how the experiment ended.
+	env = completionHookEnvVars(7, model.ErrorState, "loss", nil)
+	require.Equal(t, "ERROR", env["DET_EXPERIMENT_STATE"])
+	require.NotContains(t, env, "DET_BEST_CHECKPOINT_UUID")
+}
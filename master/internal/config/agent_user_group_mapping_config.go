@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os/user"
+	"strconv"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// DefaultMinMappedID is the lowest UID and GID users are mapped to by default, which keeps them
+// off the system accounts most distributions number below it.
+const DefaultMinMappedID = 1000
+
+// AgentUserGroupMappingConfig maps users who aren't linked to an agent user to one, so their tasks
+// run as their own UID and GID instead of security.default_task, and files they write to shared
+// mounts are owned by them.
+type AgentUserGroupMappingConfig struct {
+	// Users maps Determined usernames to agent users.
+	Users map[string]model.AgentUserGroup `json:"users"`
+	// LookupHostUsers looks users who aren't in Users up by username in the master host's name
+	// service, which may be backed by LDAP through SSSD or nslcd.
+	LookupHostUsers bool `json:"lookup_host_users"`
+	// MinUID and MinGID are the lowest UID and GID users may be mapped to, which default to
+	// DefaultMinMappedID. Host users below them aren't mapped.
+	MinUID *int `json:"min_uid"`
+	MinGID *int `json:"min_gid"`
+}
+
+func (a AgentUserGroupMappingConfig) minUID() int {
+	if a.MinUID == nil {
+		return DefaultMinMappedID
+	}
+	return *a.MinUID
+}
+
+func (a AgentUserGroupMappingConfig) minGID() int {
+	if a.MinGID == nil {
+		return DefaultMinMappedID
+	}
+	return *a.MinGID
+}
+
+// Validate implements the check.Validatable interface.
+func (a AgentUserGroupMappingConfig) Validate() []error {
+	var errs []error
+	if a.minUID() < 0 || a.minGID() < 0 {
+		errs = append(errs, errors.New("agent_user_group_mapping.min_uid and min_gid must be >= 0"))
+	}
+	for username, aug := range a.Users {
+		switch {
+		case aug.UID == 0:
+			errs = append(errs, fmt.Errorf(
+				"agent_user_group_mapping cannot map user %s to root, use security.default_task", username))
+		case aug.UID < a.minUID() || aug.GID < a.minGID():
+			errs = append(errs, fmt.Errorf(
+				"agent_user_group_mapping cannot map user %s to UID %d and GID %d, below min_uid %d "+
+					"or min_gid %d", username, aug.UID, aug.GID, a.minUID(), a.minGID()))
+		}
+	}
+	return errs
+}
+
+// lookupHostUser is user.Lookup, replaced in tests.
+var lookupHostUser = user.Lookup
+
+// AgentUserGroup returns the agent user a username maps to, or nil if it isn't mapped. Users are
+// never mapped to root, nor to host users below MinUID or MinGID.
+func (a AgentUserGroupMappingConfig) AgentUserGroup(username string) (*model.AgentUserGroup, error) {
+	if aug, ok := a.Users[username]; ok {
+		return &aug, nil
+	}
+	if !a.LookupHostUsers {
+		return nil, nil
+	}
+
+	u, err := lookupHostUser(username)
+	if errors.As(err, new(user.UnknownUserError)) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("looking up host user %s: %w", username, err)
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return nil, fmt.Errorf("host user %s has non-numeric UID %q", username, u.Uid)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return nil, fmt.Errorf("host user %s has non-numeric GID %q", username, u.Gid)
+	}
+	if uid == 0 || uid < a.minUID() || gid < a.minGID() {
+		return nil, nil
+	}
+	// Tasks still run if the group has no name; it's only used in the container's group file.
+	groupName := u.Gid
+	if g, err := user.LookupGroupId(u.Gid); err == nil {
+		groupName = g.Name
+	}
+	return &model.AgentUserGroup{User: u.Username, UID: uid, Group: groupName, GID: gid}, nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os/user"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestAgentUserGroupMapping(t *testing.T) {
+	alice := model.AgentUserGroup{User: "alice", UID: 1001, Group: "research", GID: 2001}
+	mapping := AgentUserGroupMappingConfig{Users: map[string]model.AgentUserGroup{"alice": alice}}
+	require.Empty(t, mapping.Validate())
+
+	aug, err := mapping.AgentUserGroup("alice")
+	require.NoError(t, err)
+	require.Equal(t, alice, *aug)
+	aug, err = mapping.AgentUserGroup("bob")
+	require.NoError(t, err)
+	require.Nil(t, aug, "host users aren't looked up unless enabled")
+
+	lookupHostUser = func(username string) (*user.User, error) {
+		switch username {
+		case "bob":
+			return &user.User{Username: "bob", Uid: "1002", Gid: "1002"}, nil
+		case "admin":
+			return &user.User{Username: "root", Uid: "0", Gid: "0"}, nil
+		case "daemon":
+			return &user.User{Username: "daemon", Uid: "2", Gid: "2"}, nil
+		case "dave":
+			return &user.User{Username: "dave", Uid: "1003", Gid: "100"}, nil
+		default:
+			return nil, user.UnknownUserError(username)
+		}
+	}
+	t.Cleanup(func() { lookupHostUser = user.Lookup })
+	mapping.LookupHostUsers = true
+
+	aug, err = mapping.AgentUserGroup("bob")
+	require.NoError(t, err)
+	require.Equal(t, 1002, aug.UID)
+	require.Equal(t, 1002, aug.GID)
+	require.Equal(t, "bob", aug.User)
+	require.NotEmpty(t, aug.Group)
+
+	aug, err = mapping.AgentUserGroup("admin")
+	require.NoError(t, err)
+	require.Nil(t, aug, "users aren't mapped to root")
+
+	aug, err = mapping.AgentUserGroup("daemon")
+	require.NoError(t, err)
+	require.Nil(t, aug, "users aren't mapped to system accounts")
+
+	aug, err = mapping.AgentUserGroup("dave")
+	require.NoError(t, err)
+	require.Nil(t, aug, "users aren't mapped to a GID below min_gid")
+	mapping.MinGID = ptrs.Ptr(100)
+	aug, err = mapping.AgentUserGroup("dave")
+	require.NoError(t, err)
+	require.Equal(t, 100, aug.GID)
+
+	aug, err = mapping.AgentUserGroup("carol")
+	require.NoError(t, err)
+	require.Nil(t, aug)
+
+	mapping.Users["admin"] = model.AgentUserGroup{User: "root", Group: "root"}
+	require.Len(t, mapping.Validate(), 1)
+	mapping.Users["admin"] = model.AgentUserGroup{User: "daemon", UID: 2, Group: "daemon", GID: 2}
+	require.Len(t, mapping.Validate(), 1)
+	mapping.MinUID = ptrs.Ptr(-1)
+	require.Len(t, mapping.Validate(), 2)
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CheckpointImportConfig configures registering checkpoints Determined didn't save as model
+// versions.
+type CheckpointImportConfig struct {
+	// AllowedEndpointURLs are the endpoints of S3-compatible storage users may import checkpoints
+	// through instead of the endpoint of the checkpoint storage they import from. Users can't pick
+	// an endpoint without any, so they can't have the master send credentials to other hosts.
+	AllowedEndpointURLs []string `json:"allowed_endpoint_urls"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c CheckpointImportConfig) Validate() []error {
+	var errs []error
+	for i, e := range c.AllowedEndpointURLs {
+		if u, err := url.Parse(e); err != nil || u.Host == "" ||
+			(u.Scheme != "http" && u.Scheme != "https") {
+			errs = append(errs, fmt.Errorf(
+				"checkpoint_import.allowed_endpoint_urls[%d] must be an http or https URL", i))
+		}
+	}
+	return errs
+}
+
+// EndpointURLAllowed returns whether checkpoints may be imported through an endpoint.
+func (c CheckpointImportConfig) EndpointURLAllowed(endpointURL string) bool {
+	return slices.ContainsFunc(c.AllowedEndpointURLs, func(allowed string) bool {
+		return strings.TrimSuffix(allowed, "/") == strings.TrimSuffix(endpointURL, "/")
+	})
+}
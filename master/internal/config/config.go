@@ -12,7 +12,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/jinzhu/copier"
+	"github.com/robfig/cron/v3"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/pkg/errors"
@@ -81,6 +83,214 @@ type CacheConfig struct {
 	CacheDir string `json:"cache_dir"`
 }
 
+// CheckpointDownloadConfig configures checkpoint downloads streamed through the master.
+type CheckpointDownloadConfig struct {
+	// BandwidthLimit caps the bytes per second sent to each download; zero means unlimited.
+	BandwidthLimit int64 `json:"bandwidth_limit"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *CheckpointDownloadConfig) Validate() []error {
+	if c.BandwidthLimit < 0 {
+		return []error{errors.New("checkpoint_download.bandwidth_limit must be >= 0")}
+	}
+	return nil
+}
+
+// TaskLogLimitsConfig configures how the log shipper in each task container limits runaway logs
+// before they reach the log backend.
+type TaskLogLimitsConfig struct {
+	// Dedup collapses identical consecutive lines into one line and a count of its repeats.
+	Dedup bool `json:"dedup"`
+	// LinesPerSecond is the sustained rate at which each task container ships lines; lines over
+	// the limit are dropped and replaced by a truncation marker. Zero means unlimited.
+	LinesPerSecond int `json:"lines_per_second"`
+	// Burst is how many lines can be shipped at once before the rate limit applies. It defaults
+	// to LinesPerSecond.
+	Burst int `json:"burst"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *TaskLogLimitsConfig) Validate() []error {
+	var errs []error
+	if c.LinesPerSecond < 0 {
+		errs = append(errs, errors.New("task_log_limits.lines_per_second must be >= 0"))
+	}
+	if c.Burst < 0 {
+		errs = append(errs, errors.New("task_log_limits.burst must be >= 0"))
+	}
+	return errs
+}
+
+// Modes of task record retention.
+const (
+	// TaskRecordRetentionArchive sums up pruned records by day into summary tables first.
+	TaskRecordRetentionArchive = "archive"
+	// TaskRecordRetentionPrune deletes pruned records outright.
+	TaskRecordRetentionPrune = "prune"
+)
+
+// TaskRecordRetentionConfig configures when the records of terminated allocations, like their
+// task stats and proxy shares, are pruned, to keep those tables small on clusters that have run
+// millions of tasks.
+type TaskRecordRetentionConfig struct {
+	// Days is how many days after they end allocations are pruned. Unset keeps them forever.
+	Days *int `json:"days"`
+	// Schedule is a duration or cron expression for when to prune.
+	Schedule *string `json:"schedule"`
+	// Mode is whether pruned records are archived into summary tables or just deleted.
+	Mode string `json:"mode"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *TaskRecordRetentionConfig) Validate() []error {
+	var errs []error
+	if c.Days != nil && *c.Days < 1 {
+		errs = append(errs, errors.New("task_record_retention.days must be >= 1"))
+	}
+	if c.Days != nil && c.Schedule == nil {
+		errs = append(errs, errors.New("task_record_retention.schedule is required with days"))
+	}
+	if c.Schedule != nil {
+		if _, err := time.ParseDuration(*c.Schedule); err != nil {
+			if _, err := cron.ParseStandard(*c.Schedule); err != nil {
+				errs = append(errs, errors.New(
+					"task_record_retention.schedule must be a valid duration or cron expression"))
+			}
+		}
+	}
+	switch c.Mode {
+	case "", TaskRecordRetentionArchive, TaskRecordRetentionPrune:
+	default:
+		errs = append(errs, fmt.Errorf("task_record_retention.mode must be %q or %q",
+			TaskRecordRetentionArchive, TaskRecordRetentionPrune))
+	}
+	return errs
+}
+
+// Policies for agents and harnesses whose versions aren't compatible with the master.
+const (
+	// VersionPolicyIgnore only tracks the versions of components.
+	VersionPolicyIgnore = "ignore"
+	// VersionPolicyWarn warns in the logs of the master and of the component.
+	VersionPolicyWarn = "warn"
+	// VersionPolicyRefuseScheduling keeps work off agents and stops harnesses before they run.
+	VersionPolicyRefuseScheduling = "refuse_scheduling"
+	// VersionPolicyRestrictFeatures keeps using components but turns off the features of the master
+	// that depend on them matching its version.
+	VersionPolicyRestrictFeatures = "restrict_features"
+)
+
+// ComponentVersionPolicy configures which versions of a component are compatible with the master
+// and what the master does about components that aren't.
+type ComponentVersionPolicy struct {
+	// Policy is what the master does about incompatible components. It defaults to warn.
+	Policy string `json:"policy"`
+	// AllowedMinorSkew is how many minor versions a component may be behind the master, to allow
+	// upgrading the master ahead of its agents and harnesses. Components ahead of the master or on
+	// another major version are never compatible.
+	AllowedMinorSkew int `json:"allowed_minor_skew"`
+	// MinimumVersion is the oldest compatible version, regardless of the skew.
+	MinimumVersion string `json:"minimum_version"`
+}
+
+func (c ComponentVersionPolicy) validate(name string) []error {
+	var errs []error
+	switch c.Policy {
+	case "", VersionPolicyIgnore, VersionPolicyWarn, VersionPolicyRefuseScheduling,
+		VersionPolicyRestrictFeatures:
+	default:
+		errs = append(errs, fmt.Errorf("version_compatibility.%s.policy must be one of %q, %q, %q "+
+			"or %q", name, VersionPolicyIgnore, VersionPolicyWarn, VersionPolicyRefuseScheduling,
+			VersionPolicyRestrictFeatures))
+	}
+	if c.AllowedMinorSkew < 0 {
+		errs = append(errs, fmt.Errorf("version_compatibility.%s.allowed_minor_skew must be >= 0",
+			name))
+	}
+	if c.MinimumVersion != "" {
+		if _, err := semver.NewVersion(c.MinimumVersion); err != nil {
+			errs = append(errs, fmt.Errorf("version_compatibility.%s.minimum_version: %w", name, err))
+		}
+	}
+	return errs
+}
+
+// VersionCompatibilityConfig configures the versions of agents and harnesses the master works
+// with, which they report when they connect.
+type VersionCompatibilityConfig struct {
+	Agent   ComponentVersionPolicy `json:"agent"`
+	Harness ComponentVersionPolicy `json:"harness"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *VersionCompatibilityConfig) Validate() []error {
+	return append(c.Agent.validate("agent"), c.Harness.validate("harness")...)
+}
+
+// TaskLogShippingConfig configures how task containers ship logs to the master: the master grants
+// log shippers credit for the lines of their next batch out of a budget of lines it is writing at
+// once, and log shippers spool batches to disk while the master can't take them.
+type TaskLogShippingConfig struct {
+	// MaxInflightLines is how many shipped lines the master writes to the log backend at once;
+	// shippers are asked to retry batches that would exceed it.
+	MaxInflightLines int `json:"max_inflight_lines"`
+	// MaxBatchLines is the most lines the master grants a shipper credit for in one batch.
+	MaxBatchLines int `json:"max_batch_lines"`
+	// SpoolMaxBytes is the most compressed logs each log shipper spools to disk while the master
+	// can't take them, before it stops reading logs from the task until the spool drains.
+	SpoolMaxBytes int64 `json:"spool_max_bytes"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *TaskLogShippingConfig) Validate() []error {
+	var errs []error
+	if c.MaxInflightLines <= 0 {
+		errs = append(errs, errors.New("task_log_shipping.max_inflight_lines must be > 0"))
+	}
+	if c.MaxBatchLines <= 0 {
+		errs = append(errs, errors.New("task_log_shipping.max_batch_lines must be > 0"))
+	}
+	if c.SpoolMaxBytes < 0 {
+		errs = append(errs, errors.New("task_log_shipping.spool_max_bytes must be >= 0"))
+	}
+	return errs
+}
+
+// PricingConfig sets the price of slot time, used to estimate what experiments will cost before
+// they are submitted.
+type PricingConfig struct {
+	Currency string `json:"currency"`
+	// SlotHourPrice is the price of using one slot for an hour, unless the resource pool has its
+	// own price. Costs aren't estimated if neither is set.
+	SlotHourPrice *float64 `json:"slot_hour_price"`
+	// ResourcePoolSlotHourPrices are the slot hour prices of individual resource pools.
+	ResourcePoolSlotHourPrices map[string]float64 `json:"resource_pool_slot_hour_prices"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *PricingConfig) Validate() []error {
+	var errs []error
+	if c.SlotHourPrice != nil && *c.SlotHourPrice < 0 {
+		errs = append(errs, errors.New("pricing.slot_hour_price must be >= 0"))
+	}
+	for pool, price := range c.ResourcePoolSlotHourPrices {
+		if price < 0 {
+			errs = append(errs, fmt.Errorf(
+				"pricing.resource_pool_slot_hour_prices of %s must be >= 0", pool))
+		}
+	}
+	return errs
+}
+
+// SlotHourPriceOf returns the slot hour price of a resource pool, or nil if it has none.
+func (c PricingConfig) SlotHourPriceOf(pool string) *float64 {
+	if price, ok := c.ResourcePoolSlotHourPrices[pool]; ok {
+		return &price
+	}
+	return c.SlotHourPrice
+}
+
 // DBConfig hosts configuration fields of the database.
 type DBConfig struct {
 	User             string `json:"user"`
@@ -92,6 +302,9 @@ type DBConfig struct {
 	Name             string `json:"name"`
 	SSLMode          string `json:"ssl_mode"`
 	SSLRootCert      string `json:"ssl_root_cert"`
+	// OnlineMigrations defers contract migrations, which remove what the previous version of the
+	// master used, until the master is serving, rather than running them before it starts.
+	OnlineMigrations bool `json:"online_migrations"`
 }
 
 // WebhooksConfig hosts configuration fields for webhook functionality.
@@ -103,6 +316,10 @@ type WebhooksConfig struct {
 // IntegrationsConfig stores configs related to integrations like pachyderm.
 type IntegrationsConfig struct {
 	Pachyderm PachydermConfig `json:"pachyderm"`
+	// Spark is where Spark jobs are submitted to. Spark jobs are disabled without it.
+	Spark *model.SparkBackendConfig `json:"spark"`
+	// MLflow is the MLflow tracking servers experiments may be imported from.
+	MLflow MLflowConfig `json:"mlflow"`
 }
 
 // PachydermConfig stores fields needed to integrate Pachyderm with determined.
@@ -155,6 +372,14 @@ func DefaultConfig() *Config {
 		},
 		FeatureSwitches: []string{},
 		ResourceConfig:  *DefaultResourceConfig(),
+		Pricing: PricingConfig{
+			Currency: "USD",
+		},
+		TaskLogShipping: TaskLogShippingConfig{
+			MaxInflightLines: 100000,
+			MaxBatchLines:    1000,
+			SpoolMaxBytes:    256 << 20,
+		},
 		Observability: ObservabilityConfig{
 			EnablePrometheus: true,
 		},
@@ -163,6 +388,9 @@ func DefaultConfig() *Config {
 			SCIMAuthenticationAttribute: "userName",
 			AutoProvisionUsers:          false,
 		},
+		ProxyAuth: ProxyAuthConfig{
+			UsernameClaim: "email",
+		},
 	}
 }
 
@@ -188,9 +416,15 @@ type Config struct {
 	UICustomization       UICustomizationConfig             `json:"ui_customization"`
 	Logging               model.LoggingConfig               `json:"logging"`
 	RetentionPolicy       model.LogRetentionPolicy          `json:"retention_policy"`
+	TaskRecordRetention   TaskRecordRetentionConfig         `json:"task_record_retention"`
+	TaskLogLimits         TaskLogLimitsConfig               `json:"task_log_limits"`
+	TaskLogShipping       TaskLogShippingConfig             `json:"task_log_shipping"`
+	VersionCompatibility  VersionCompatibilityConfig        `json:"version_compatibility"`
 	Observability         ObservabilityConfig               `json:"observability"`
 	Cache                 CacheConfig                       `json:"cache"`
+	CheckpointDownload    CheckpointDownloadConfig          `json:"checkpoint_download"`
 	Webhooks              WebhooksConfig                    `json:"webhooks"`
+	Pricing               PricingConfig                     `json:"pricing"`
 	FeatureSwitches       []string                          `json:"feature_switches"`
 	ReservedPorts         []int                             `json:"reserved_ports"`
 	ResourceConfig
@@ -201,8 +435,15 @@ type Config struct {
 	Scim         ScimConfig         `json:"scim"`
 	SAML         SAMLConfig         `json:"saml"`
 	OIDC         OIDCConfig         `json:"oidc"`
+	ProxyAuth    ProxyAuthConfig    `json:"proxy_auth"`
 	DetCloud     DetCloudConfig     `json:"det_cloud"`
 	Integrations IntegrationsConfig `json:"integrations"`
+	Federation   FederationConfig   `json:"federation"`
+	EgressProxy  EgressProxyConfig  `json:"egress_proxy"`
+	HTTPPolicy   HTTPPolicyConfig   `json:"http_policy"`
+
+	CheckpointImport CheckpointImportConfig `json:"checkpoint_import"`
+	MetricsExport    MetricsExportConfig    `json:"metrics_export"`
 }
 
 // GetMasterConfig returns reference to the master config singleton.
@@ -241,6 +482,9 @@ func (c Config) Printable() ([]byte, error) {
 	if configCopy.DB.Password != "" {
 		configCopy.DB.Password = hiddenValue
 	}
+	if configCopy.ProxyAuth.SharedSecret != "" {
+		configCopy.ProxyAuth.SharedSecret = hiddenValue
+	}
 	if configCopy.Telemetry.SegmentMasterKey != "" {
 		configCopy.Telemetry.SegmentMasterKey = hiddenValue
 	}
@@ -267,6 +511,11 @@ func (c Config) Printable() ([]byte, error) {
 	}
 
 	configCopy.CheckpointStorage = configCopy.CheckpointStorage.Printable()
+	configCopy.EgressProxy = configCopy.EgressProxy.Printable()
+
+	for i := range configCopy.Federation.Peers {
+		configCopy.Federation.Peers[i].Token = hiddenValue
+	}
 
 	maskPools := func(pools []ResourcePoolConfig) []ResourcePoolConfig {
 		for i, p := range pools {
@@ -417,6 +666,21 @@ func (c *Config) Resolve() error {
 		c.SAML.GroupsAttributeName = ""
 	}
 
+	if c.ProxyAuth.AutoProvisionUsers && c.Scim.Enabled {
+		log.Warn("scim enabled; overriding proxy auth user & group provisions")
+		c.ProxyAuth.AutoProvisionUsers = false
+		c.ProxyAuth.GroupsClaim = ""
+	}
+
+	if c.ProxyAuth.GroupsClaim != "" && !c.Security.AuthZ.IsRBACUIEnabled() {
+		log.Warn("proxy_auth.groups_claim requires rbac to be enabled")
+		c.ProxyAuth.GroupsClaim = ""
+	}
+
+	if err := c.resolveEnableCors(); err != nil {
+		return err
+	}
+
 	if c.Security.Token.MaxLifespanDays == InfiniteTokenLifespan {
 		c.Security.Token.MaxLifespanDays = MaxAllowedTokenLifespanDays
 	}
@@ -427,6 +691,22 @@ func (c *Config) Resolve() error {
 	return nil
 }
 
+// resolveEnableCors translates the legacy enable_cors option into http_policy, which is the only
+// place CORS and websocket origins are checked. The legacy option echoed any origin back with
+// credentials, which http_policy doesn't allow, so it becomes any origin without credentials.
+func (c *Config) resolveEnableCors() error {
+	if !c.EnableCors {
+		return nil
+	}
+	if len(c.HTTPPolicy.CORS.AllowedOrigins) > 0 {
+		return fmt.Errorf("enable_cors can't be combined with http_policy.cors; remove enable_cors")
+	}
+	log.Warn("enable_cors is deprecated; allowing any origin without credentials. " +
+		"Set http_policy.cors.allowed_origins instead.")
+	c.HTTPPolicy.CORS.AllowedOrigins = []string{"*"}
+	return nil
+}
+
 // Deprecations describe fields which were recently or will soon be removed.
 func (c *Config) Deprecations() (errs []error) {
 	for _, r := range c.ResourceManagers() {
@@ -472,11 +752,12 @@ func (c *Config) Deprecations() (errs []error) {
 
 // SecurityConfig is the security configuration for the master.
 type SecurityConfig struct {
-	DefaultTask model.AgentUserGroup `json:"default_task"`
-	TLS         TLSConfig            `json:"tls"`
-	SSH         SSHConfig            `json:"ssh"`
-	AuthZ       AuthZConfig          `json:"authz"`
-	Token       TokenConfig          `json:"token"`
+	DefaultTask           model.AgentUserGroup        `json:"default_task"`
+	AgentUserGroupMapping AgentUserGroupMappingConfig `json:"agent_user_group_mapping"`
+	TLS                   TLSConfig                   `json:"tls"`
+	SSH                   SSHConfig                   `json:"ssh"`
+	AuthZ                 AuthZConfig                 `json:"authz"`
+	Token                 TokenConfig                 `json:"token"`
 
 	InitialUserPassword string `json:"initial_user_password"`
 }
@@ -132,7 +132,8 @@ integrations:
 						PreemptionTimeout:      model.DefaultPreemptionTimeout,
 						DtrainNetworkInterface: "if0",
 					},
-					AgentReconnectWait: model.Duration(aproto.AgentReconnectWait),
+					AgentReconnectWait:    model.Duration(aproto.AgentReconnectWait),
+					DiskPressureThreshold: 0.9,
 				},
 			},
 		},
@@ -251,6 +252,7 @@ resource_pools:
 					MaxAuxContainersPerAgent: 10,
 					MaxCPUContainersPerAgent: 0,
 					AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+					DiskPressureThreshold:    0.9,
 				},
 				{
 					PoolName: "gpu-pool",
@@ -267,6 +269,7 @@ resource_pools:
 					MaxAuxContainersPerAgent: 0,
 					MaxCPUContainersPerAgent: 0,
 					AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+					DiskPressureThreshold:    0.9,
 				},
 			},
 		},
@@ -583,6 +586,8 @@ func TestPrintableConfig(t *testing.T) {
 	registryAuthSecret := "i_love_cellos"
 	startupScriptSecret := "my_startup_script_secret"
 	containerStartupScriptSecret := "my_container_startup_secret"
+	federationTokenSecret := "my_federation_token_secret"
+	egressProxySecret := "my_egress_proxy_secret"
 
 	raw := fmt.Sprintf(`
 db:
@@ -623,8 +628,18 @@ additional_resource_managers:
           type: gcp
           startup_script: %v
           container_startup_script: %v
+
+federation:
+  peers:
+    - name: east
+      url: https://det-east.example.com:8443
+      token: %v
+
+egress_proxy:
+  http_proxy: http://det:%v@proxy.example.com:3128
 `, s3Key, s3Secret, masterSecret, webuiSecret, registryAuthSecret, startupScriptSecret,
-		containerStartupScriptSecret, startupScriptSecret, containerStartupScriptSecret)
+		containerStartupScriptSecret, startupScriptSecret, containerStartupScriptSecret,
+		federationTokenSecret, egressProxySecret)
 
 	provConfig := provconfig.DefaultConfig()
 	provConfig.StartupScript = startupScriptSecret
@@ -672,6 +687,7 @@ additional_resource_managers:
 						{
 							Provider:                 provConfig,
 							AgentReconnectWait:       25000000000,
+							DiskPressureThreshold:    0.9,
 							MaxAuxContainersPerAgent: 100,
 						},
 					},
@@ -681,10 +697,19 @@ additional_resource_managers:
 				{
 					Provider:                 provConfig,
 					AgentReconnectWait:       25000000000,
+					DiskPressureThreshold:    0.9,
 					MaxAuxContainersPerAgent: 100,
 				},
 			},
 		},
+		Federation: FederationConfig{
+			Peers: []FederationPeerConfig{
+				{Name: "east", URL: "https://det-east.example.com:8443", Token: federationTokenSecret},
+			},
+		},
+		EgressProxy: EgressProxyConfig{
+			HTTPProxy: "http://det:" + egressProxySecret + "@proxy.example.com:3128",
+		},
 	}
 
 	unmarshaled := Config{
@@ -705,6 +730,8 @@ additional_resource_managers:
 	assert.Assert(t, !bytes.Contains(printable, []byte(registryAuthSecret)))
 	assert.Assert(t, !bytes.Contains(printable, []byte(startupScriptSecret)))
 	assert.Assert(t, !bytes.Contains(printable, []byte(containerStartupScriptSecret)))
+	assert.Assert(t, !bytes.Contains(printable, []byte(federationTokenSecret)))
+	assert.Assert(t, !bytes.Contains(printable, []byte(egressProxySecret)))
 
 	// Ensure that the original was unmodified.
 	assert.DeepEqual(t, unmarshaled, expected)
@@ -946,3 +973,257 @@ func TestPickVariation(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskRecordRetentionConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TaskRecordRetentionConfig
+		errs   int
+	}{
+		{name: "unset", config: TaskRecordRetentionConfig{}},
+		{
+			name:   "duration",
+			config: TaskRecordRetentionConfig{Days: ptrs.Ptr(90), Schedule: ptrs.Ptr("24h")},
+		},
+		{
+			name: "cron",
+			config: TaskRecordRetentionConfig{
+				Days: ptrs.Ptr(90), Schedule: ptrs.Ptr("0 3 * * *"), Mode: TaskRecordRetentionPrune,
+			},
+		},
+		{name: "no schedule", config: TaskRecordRetentionConfig{Days: ptrs.Ptr(90)}, errs: 1},
+		{
+			name:   "bad days",
+			config: TaskRecordRetentionConfig{Days: ptrs.Ptr(0), Schedule: ptrs.Ptr("24h")},
+			errs:   1,
+		},
+		{
+			name:   "bad schedule",
+			config: TaskRecordRetentionConfig{Days: ptrs.Ptr(90), Schedule: ptrs.Ptr("daily")},
+			errs:   1,
+		},
+		{name: "bad mode", config: TaskRecordRetentionConfig{Mode: "delete"}, errs: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Len(t, tt.config.Validate(), tt.errs)
+		})
+	}
+}
+
+func TestVersionCompatibilityConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config VersionCompatibilityConfig
+		errs   int
+	}{
+		{name: "unset", config: VersionCompatibilityConfig{}},
+		{
+			name: "canary",
+			config: VersionCompatibilityConfig{
+				Agent: ComponentVersionPolicy{
+					Policy: VersionPolicyRefuseScheduling, AllowedMinorSkew: 1, MinimumVersion: "0.36.0",
+				},
+				Harness: ComponentVersionPolicy{Policy: VersionPolicyRestrictFeatures},
+			},
+		},
+		{
+			name:   "bad policy",
+			config: VersionCompatibilityConfig{Harness: ComponentVersionPolicy{Policy: "refuse"}},
+			errs:   1,
+		},
+		{
+			name: "bad skew and minimum version",
+			config: VersionCompatibilityConfig{
+				Agent: ComponentVersionPolicy{AllowedMinorSkew: -1, MinimumVersion: "latest"},
+			},
+			errs: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Len(t, tt.config.Validate(), tt.errs)
+		})
+	}
+}
+
+func TestEgressProxyConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config EgressProxyConfig
+		errs   int
+	}{
+		{name: "unset", config: EgressProxyConfig{}},
+		{
+			name: "proxies and rules",
+			config: EgressProxyConfig{
+				HTTPProxy:  "http://proxy.example.com:3128",
+				HTTPSProxy: "socks5://proxy.example.com:1080",
+				NoProxy:    []string{"internal.example.com", "10.0.0.0/8", "hooks.example.com:8443", "*"},
+			},
+		},
+		{
+			name:   "bad proxies",
+			config: EgressProxyConfig{HTTPProxy: "proxy.example.com", HTTPSProxy: "ftp://proxy:21"},
+			errs:   2,
+		},
+		{
+			name:   "bad rules",
+			config: EgressProxyConfig{NoProxy: []string{"", "a.com,b.com", "10.0.0.0/33"}},
+			errs:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Len(t, tt.config.Validate(), tt.errs)
+		})
+	}
+}
+
+func TestHTTPPolicyConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config HTTPPolicyConfig
+		errs   int
+	}{
+		{name: "unset", config: HTTPPolicyConfig{}},
+		{
+			name: "origins and policy",
+			config: HTTPPolicyConfig{
+				CORS: CORSConfig{
+					AllowedOrigins: []string{"https://portal.example.com", "https://*.example.com:8443", "*"},
+					MaxAge:         600,
+				},
+				ContentSecurityPolicy: "default-src 'self'; frame-ancestors 'self' https://portal.example.com",
+			},
+		},
+		{
+			name: "bad origins",
+			config: HTTPPolicyConfig{CORS: CORSConfig{
+				AllowedOrigins: []string{
+					"portal.example.com", "ftp://example.com", "https://example.com/ui", "https://a.*.com",
+				},
+				MaxAge: -1,
+			}},
+			errs: 5,
+		},
+		{
+			name: "wildcards with credentials",
+			config: HTTPPolicyConfig{CORS: CORSConfig{
+				AllowedOrigins:   []string{"https://portal.example.com", "https://*.example.com", "*"},
+				AllowCredentials: true,
+			}},
+			errs: 2,
+		},
+		{
+			name:   "bad policy",
+			config: HTTPPolicyConfig{ContentSecurityPolicy: "default-src 'self', script-src 'none'"},
+			errs:   1,
+		},
+		{
+			name:   "repeated directive",
+			config: HTTPPolicyConfig{ContentSecurityPolicy: "default-src 'self'; default-src 'none'"},
+			errs:   1,
+		},
+		{
+			name:   "report only without policy",
+			config: HTTPPolicyConfig{ContentSecurityPolicyReportOnly: true},
+			errs:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Len(t, tt.config.Validate(), tt.errs)
+		})
+	}
+}
+
+func TestResolveEnableCors(t *testing.T) {
+	c := Config{EnableCors: true}
+	require.NoError(t, c.resolveEnableCors())
+	require.Equal(t, []string{"*"}, c.HTTPPolicy.CORS.AllowedOrigins)
+	require.False(t, c.HTTPPolicy.CORS.AllowCredentials)
+	require.Empty(t, c.HTTPPolicy.Validate())
+	require.False(t, c.HTTPPolicy.CORS.OriginListed("https://attacker.example.com"))
+
+	c = Config{EnableCors: true}
+	c.HTTPPolicy.CORS.AllowedOrigins = []string{"https://portal.example.com"}
+	require.ErrorContains(t, c.resolveEnableCors(), "enable_cors")
+
+	c = Config{}
+	require.NoError(t, c.resolveEnableCors())
+	require.Empty(t, c.HTTPPolicy.CORS.AllowedOrigins)
+}
+
+func TestMLflowConfig(t *testing.T) {
+	c := MLflowConfig{AllowedServers: []string{"https://MLflow.example.com/", "http://10.0.0.5:5000/mlflow"}}
+	require.Empty(t, c.Validate())
+	for server, allowed := range map[string]bool{
+		"https://mlflow.example.com":         true,
+		"https://mlflow.example.com/":        true,
+		"http://10.0.0.5:5000/mlflow/":       true,
+		"http://mlflow.example.com":          false,
+		"https://mlflow.example.com:8443":    false,
+		"http://10.0.0.5:5000":               false,
+		"http://169.254.169.254/latest":      false,
+		"https://user:pw@mlflow.example.com": false,
+	} {
+		require.Equal(t, allowed, c.ServerAllowed(server), server)
+	}
+	require.False(t, MLflowConfig{}.ServerAllowed("https://mlflow.example.com"))
+
+	bad := MLflowConfig{AllowedServers: []string{"mlflow", "ftp://mlflow.example.com", "https://a:b@mlflow"}}
+	require.Len(t, bad.Validate(), 3)
+}
+
+func TestMetricsExportConfig(t *testing.T) {
+	var c MetricsExportConfig
+	require.Empty(t, c.Validate())
+	for u, allowed := range map[string]bool{
+		"https://mlflow.example.com":     true,
+		"http://mlflow.example.com":      false,
+		"ftp://mlflow.example.com":       false,
+		"https://127.0.0.1:5000":         false,
+		"https://[::1]/":                 false,
+		"https://169.254.169.254/latest": false,
+		"https://0.0.0.0":                false,
+		"https://10.0.0.5:5000":          true,
+		"mlflow.example.com":             false,
+	} {
+		require.Equal(t, allowed, c.CheckURL(u) == nil, u)
+	}
+
+	c = MetricsExportConfig{
+		AllowedSchemes: []string{"http", "https"},
+		AllowedHosts:   []string{"example.com", "10.0.0.0/8", "127.0.0.1"},
+	}
+	require.Empty(t, c.Validate())
+	for u, allowed := range map[string]bool{
+		"http://mlflow.example.com":   true,
+		"https://example.com":         true,
+		"https://badexample.com":      false,
+		"https://10.1.2.3":            true,
+		"https://192.168.0.1":         false,
+		"https://127.0.0.1":           false,
+		"https://wandb.attacker.test": false,
+	} {
+		require.Equal(t, allowed, c.CheckURL(u) == nil, u)
+	}
+	c.AllowInternalAddresses = true
+	require.NoError(t, c.CheckURL("https://127.0.0.1"))
+
+	bad := MetricsExportConfig{
+		AllowedSchemes: []string{"ftp"},
+		AllowedHosts:   []string{"", "10.0.0.0/33", "a.com,b.com"},
+	}
+	require.Len(t, bad.Validate(), 4)
+}
+
+func TestCheckpointImportConfig(t *testing.T) {
+	c := CheckpointImportConfig{AllowedEndpointURLs: []string{"https://minio.example.com/"}}
+	require.Empty(t, c.Validate())
+	require.True(t, c.EndpointURLAllowed("https://minio.example.com"))
+	require.False(t, c.EndpointURLAllowed("https://attacker.example.com"))
+	require.False(t, CheckpointImportConfig{}.EndpointURLAllowed("https://minio.example.com"))
+	require.Len(t, CheckpointImportConfig{AllowedEndpointURLs: []string{"minio:9000"}}.Validate(), 1)
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// EgressProxyConfig routes the requests the master makes to services outside the cluster, such as
+// image registries, webhooks, cloud APIs and telemetry, through a proxy. Without a proxy set, the
+// master uses the proxy set by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+type EgressProxyConfig struct {
+	// HTTPProxy is the proxy for HTTP requests: an http, https or socks5 URL.
+	HTTPProxy string `json:"http_proxy"`
+	// HTTPSProxy is the proxy for HTTPS requests, which defaults to HTTPProxy.
+	HTTPSProxy string `json:"https_proxy"`
+	// NoProxy are the destinations requests go to directly: hostnames, which also match their
+	// subdomains, IP addresses and CIDR ranges, each optionally with a port, or "*" for all.
+	NoProxy []string `json:"no_proxy"`
+}
+
+// Enabled returns whether a proxy is set.
+func (c EgressProxyConfig) Enabled() bool {
+	return c.HTTPProxy != "" || c.HTTPSProxy != ""
+}
+
+// Validate implements the check.Validatable interface.
+func (c EgressProxyConfig) Validate() []error {
+	var errs []error
+	for _, p := range []struct{ name, url string }{
+		{"http_proxy", c.HTTPProxy}, {"https_proxy", c.HTTPSProxy},
+	} {
+		if p.url == "" {
+			continue
+		}
+		u, err := url.Parse(p.url)
+		switch {
+		case err != nil || u.Host == "":
+			errs = append(errs, fmt.Errorf("egress_proxy.%s must be a URL", p.name))
+		case u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5":
+			errs = append(errs, fmt.Errorf(
+				"egress_proxy.%s must be an http, https or socks5 URL, not %s", p.name, u.Scheme))
+		}
+	}
+	for i, rule := range c.NoProxy {
+		host := rule
+		if h, _, err := net.SplitHostPort(rule); err == nil {
+			host = h
+		}
+		switch {
+		case rule == "" || strings.ContainsAny(rule, ", \t"):
+			errs = append(errs, fmt.Errorf(
+				"egress_proxy.no_proxy[%d] must be a single hostname, IP address or CIDR range", i))
+		case strings.Contains(host, "/"):
+			if _, _, err := net.ParseCIDR(host); err != nil {
+				errs = append(errs, fmt.Errorf("egress_proxy.no_proxy[%d]: %w", i, err))
+			}
+		}
+	}
+	return errs
+}
+
+// Printable returns the config with the passwords in the proxy URLs hidden.
+func (c EgressProxyConfig) Printable() EgressProxyConfig {
+	c.HTTPProxy = redactURLPassword(c.HTTPProxy)
+	c.HTTPSProxy = redactURLPassword(c.HTTPSProxy)
+	return c
+}
+
+func redactURLPassword(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "********")
+	}
+	return u.String()
+}
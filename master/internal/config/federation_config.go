@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// FederationConfig configures the peer clusters whose experiments and jobs this master shows,
+// alongside its own, in a read-only merged view.
+type FederationConfig struct {
+	Peers []FederationPeerConfig `json:"peers"`
+}
+
+// FederationPeerConfig configures how to reach another Determined master.
+type FederationPeerConfig struct {
+	// Name identifies the peer in the merged view. It must be unique.
+	Name string `json:"name"`
+	// URL is the peer master's address, e.g. https://det.example.com:8443.
+	URL string `json:"url"`
+	// Token is an access token for the peer. Users with the view federation permission, only
+	// admins by default, see the experiments and jobs the token's user can see on the peer, so it
+	// should belong to a user who can only view what they may all see.
+	Token string `json:"token"`
+	// SkipVerify skips verifying the peer's TLS certificate.
+	SkipVerify bool `json:"skip_verify"`
+	// CertificatePath is a CA bundle to verify the peer's TLS certificate with.
+	CertificatePath string `json:"certificate"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c FederationConfig) Validate() []error {
+	var errs []error
+	names := map[string]bool{}
+	for i, p := range c.Peers {
+		switch {
+		case p.Name == "":
+			errs = append(errs, fmt.Errorf("federation.peers[%d].name must be set", i))
+		case names[p.Name]:
+			errs = append(errs, fmt.Errorf("federation.peers[%d].name %q is not unique", i, p.Name))
+		}
+		names[p.Name] = true
+
+		if u, err := url.Parse(p.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("federation.peers[%d].url must be a URL", i))
+		}
+		if p.Token == "" {
+			errs = append(errs, fmt.Errorf("federation.peers[%d].token must be set", i))
+		}
+	}
+	return errs
+}
+
+// Enabled returns whether any peers are configured.
+func (c FederationConfig) Enabled() bool {
+	return len(c.Peers) > 0
+}
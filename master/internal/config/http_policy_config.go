@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var cspDirectiveName = regexp.MustCompile(`^[a-z][a-z-]*$`)
+
+// HTTPPolicyConfig is the CORS and Content Security Policy the master's HTTP server applies, so
+// the API and WebUI can be embedded in other sites, e.g. internal portals.
+type HTTPPolicyConfig struct {
+	CORS CORSConfig `json:"cors"`
+	// ContentSecurityPolicy is the Content-Security-Policy header sent with every response. If it
+	// has no frame-ancestors directive, responses also forbid framing by other origins with
+	// X-Frame-Options.
+	ContentSecurityPolicy string `json:"content_security_policy"`
+	// ContentSecurityPolicyReportOnly sends the policy as Content-Security-Policy-Report-Only,
+	// to try it out without enforcing it.
+	ContentSecurityPolicyReportOnly bool `json:"content_security_policy_report_only"`
+}
+
+// CORSConfig is the origins allowed to make cross-origin requests to the master.
+type CORSConfig struct {
+	// AllowedOrigins are origins like https://portal.example.com, which may use a wildcard for
+	// subdomains, like https://*.example.com, or "*" for any origin. Wildcards can't be combined
+	// with AllowCredentials, and never allow websockets.
+	AllowedOrigins []string `json:"allowed_origins"`
+	// AllowCredentials allows cross-origin requests to send cookies and authorization headers.
+	AllowCredentials bool `json:"allow_credentials"`
+	// MaxAge is how many seconds browsers may cache the result of a preflight request.
+	MaxAge int `json:"max_age"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c HTTPPolicyConfig) Validate() []error {
+	var errs []error
+	for i, origin := range c.CORS.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			errs = append(errs, fmt.Errorf("http_policy.cors.allowed_origins[%d]: %w", i, err))
+		} else if c.CORS.AllowCredentials && strings.Contains(origin, "*") {
+			errs = append(errs, fmt.Errorf(
+				"http_policy.cors.allowed_origins[%d]: %q can't use a wildcard with allow_credentials",
+				i, origin))
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("http_policy.cors.max_age must be non-negative"))
+	}
+	if c.ContentSecurityPolicyReportOnly && c.ContentSecurityPolicy == "" {
+		errs = append(errs, fmt.Errorf(
+			"http_policy.content_security_policy must be set to report it only"))
+	}
+	if err := validateContentSecurityPolicy(c.ContentSecurityPolicy); err != nil {
+		errs = append(errs, fmt.Errorf("http_policy.content_security_policy: %w", err))
+	}
+	return errs
+}
+
+// OriginAllowed returns whether an origin may make cross-origin requests.
+func (c CORSConfig) OriginAllowed(origin string) bool {
+	return c.originAllowed(origin, true)
+}
+
+// OriginListed returns whether an origin is allowed by name rather than by a wildcard, which is
+// required to open websockets, since browsers send cookies with them regardless of CORS.
+func (c CORSConfig) OriginListed(origin string) bool {
+	return c.originAllowed(origin, false)
+}
+
+func (c CORSConfig) originAllowed(origin string, wildcards bool) bool {
+	u, err := url.Parse(strings.ToLower(origin))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, allowed := range c.AllowedOrigins {
+		if strings.Contains(allowed, "*") && !wildcards {
+			continue
+		}
+		if allowed == "*" {
+			return true
+		}
+		a, err := url.Parse(strings.ToLower(allowed))
+		if err != nil || a.Scheme != u.Scheme {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(a.Host, "*"); ok {
+			if strings.HasSuffix(u.Host, suffix) && len(u.Host) > len(suffix) {
+				return true
+			}
+		} else if a.Host == u.Host {
+			return true
+		}
+	}
+	return false
+}
+
+// FrameAncestors returns whether the enforced policy sets which sites may embed the master.
+func (c HTTPPolicyConfig) FrameAncestors() bool {
+	if c.ContentSecurityPolicyReportOnly {
+		return false
+	}
+	for _, directive := range strings.Split(c.ContentSecurityPolicy, ";") {
+		if fields := strings.Fields(directive); len(fields) > 0 &&
+			strings.EqualFold(fields[0], "frame-ancestors") {
+			return true
+		}
+	}
+	return false
+}
+
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	switch {
+	case err != nil || u.Host == "" || u.Opaque != "":
+		return fmt.Errorf("%q must be an origin like https://portal.example.com", origin)
+	case u.Scheme != "http" && u.Scheme != "https":
+		return fmt.Errorf("%q must be an http or https origin", origin)
+	case u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "":
+		return fmt.Errorf("%q must only have a scheme, host and port", origin)
+	case strings.Contains(strings.TrimPrefix(u.Host, "*."), "*"):
+		return fmt.Errorf("%q may only use a wildcard for the first label of its host", origin)
+	}
+	return nil
+}
+
+func validateContentSecurityPolicy(policy string) error {
+	if strings.ContainsAny(policy, ",\r\n") {
+		return fmt.Errorf("must be a single policy on one line")
+	}
+	seen := map[string]bool{}
+	for _, directive := range strings.Split(policy, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		switch {
+		case !cspDirectiveName.MatchString(name):
+			return fmt.Errorf("%q is not a directive name", fields[0])
+		case seen[name]:
+			return fmt.Errorf("directive %s is set more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// defaultMetricsExportSchemes are the schemes of the destinations metrics may be exported to when
+// none are configured.
+var defaultMetricsExportSchemes = []string{"https"}
+
+// MetricsExportConfig limits the external systems workspaces may export trial metrics to, so users
+// can't have the master send requests to hosts inside the cluster's network.
+type MetricsExportConfig struct {
+	// AllowedSchemes are the URL schemes of destinations, which default to https only.
+	AllowedSchemes []string `json:"allowed_schemes"`
+	// AllowedHosts are the hosts of destinations: hostnames, which also match their subdomains, IP
+	// addresses and CIDR ranges. Any host is allowed without any.
+	AllowedHosts []string `json:"allowed_hosts"`
+	// AllowInternalAddresses allows destinations that resolve to loopback, link-local or
+	// unspecified addresses, such as cloud metadata services.
+	AllowInternalAddresses bool `json:"allow_internal_addresses"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c MetricsExportConfig) Validate() []error {
+	var errs []error
+	for i, s := range c.AllowedSchemes {
+		if s != "http" && s != "https" {
+			errs = append(errs, fmt.Errorf(
+				"metrics_export.allowed_schemes[%d] must be http or https, not %q", i, s))
+		}
+	}
+	for i, h := range c.AllowedHosts {
+		switch {
+		case strings.Contains(h, "/"):
+			if _, _, err := net.ParseCIDR(h); err != nil {
+				errs = append(errs, fmt.Errorf("metrics_export.allowed_hosts[%d]: %w", i, err))
+			}
+		case h == "" || (net.ParseIP(h) == nil && strings.ContainsAny(h, ":, \t")):
+			errs = append(errs, fmt.Errorf(
+				"metrics_export.allowed_hosts[%d] must be a hostname, IP address or CIDR range", i))
+		}
+	}
+	return errs
+}
+
+// CheckURL returns an error if a destination's URL doesn't have an allowed scheme and host. It
+// doesn't resolve the host; see CheckAddress.
+func (c MetricsExportConfig) CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("url must be a URL")
+	}
+	schemes := c.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = defaultMetricsExportSchemes
+	}
+	if !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+		return fmt.Errorf("url must be a %s URL", strings.Join(schemes, " or "))
+	}
+	if !c.hostAllowed(strings.ToLower(u.Hostname())) {
+		return fmt.Errorf("host %s isn't an allowed metrics export destination", u.Hostname())
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		return c.CheckAddress(ip)
+	}
+	return nil
+}
+
+// CheckAddress returns an error if a destination's host resolved to an address metrics may not be
+// sent to.
+func (c MetricsExportConfig) CheckAddress(ip net.IP) error {
+	if c.AllowInternalAddresses {
+		return nil
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%s is a loopback or link-local address", ip)
+	}
+	return nil
+}
+
+func (c MetricsExportConfig) hostAllowed(host string) bool {
+	if len(c.AllowedHosts) == 0 {
+		return true
+	}
+	ip := net.ParseIP(host)
+	for _, allowed := range c.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		switch {
+		case ip != nil && strings.Contains(allowed, "/"):
+			if _, cidr, err := net.ParseCIDR(allowed); err == nil && cidr.Contains(ip) {
+				return true
+			}
+		case ip != nil:
+			if ip.Equal(net.ParseIP(allowed)) {
+				return true
+			}
+		case host == allowed || strings.HasSuffix(host, "."+allowed):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MLflowConfig is the MLflow tracking servers users may import experiments from.
+type MLflowConfig struct {
+	// AllowedServers are the base URLs of the tracking servers the master may read from. Imports
+	// are disabled without any, so users can't have the master make requests to other hosts.
+	AllowedServers []string `json:"allowed_servers"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c MLflowConfig) Validate() []error {
+	var errs []error
+	for i, s := range c.AllowedServers {
+		u, err := url.Parse(s)
+		switch {
+		case err != nil || u.Host == "":
+			errs = append(errs, fmt.Errorf("integrations.mlflow.allowed_servers[%d] must be a URL", i))
+		case u.Scheme != "http" && u.Scheme != "https":
+			errs = append(errs, fmt.Errorf(
+				"integrations.mlflow.allowed_servers[%d] must be an http or https URL", i))
+		case u.User != nil || u.RawQuery != "" || u.Fragment != "":
+			errs = append(errs, fmt.Errorf(
+				"integrations.mlflow.allowed_servers[%d] must not have credentials, a query or a fragment", i))
+		}
+	}
+	return errs
+}
+
+// ServerAllowed returns whether the tracking server at a base URL may be imported from.
+func (c MLflowConfig) ServerAllowed(server string) bool {
+	normalized, ok := normalizeMLflowServer(server)
+	if !ok {
+		return false
+	}
+	for _, s := range c.AllowedServers {
+		if allowed, ok := normalizeMLflowServer(s); ok && allowed == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeMLflowServer returns a base URL with a lower case scheme and host and without a
+// trailing slash, so equivalent URLs compare equal.
+func normalizeMLflowServer(server string) (string, bool) {
+	u, err := url.Parse(server)
+	if err != nil || u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), true
+}
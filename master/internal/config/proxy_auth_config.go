@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+)
+
+// ProxyAuthConfig configures trusting the identity of users asserted by an authenticating proxy,
+// such as oauth2-proxy or Pomerium, in a signed JWT header.
+type ProxyAuthConfig struct {
+	Enabled bool `json:"enabled"`
+	// Header is the request header the proxy puts its signed JWT in.
+	Header string `json:"header"`
+	// JWKSURL is where the keys the proxy signs with are published. Exactly one of JWKSURL and
+	// SharedSecret must be set.
+	JWKSURL string `json:"jwks_url"`
+	// SharedSecret verifies JWTs signed with HMAC.
+	SharedSecret string `json:"shared_secret"`
+	// Issuer and Audience, if set, must match the JWT's iss and aud claims.
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+
+	UsernameClaim    string `json:"username_claim"`
+	DisplayNameClaim string `json:"display_name_claim"`
+	// GroupsClaim, if set, syncs users' group memberships from the named claim.
+	GroupsClaim string `json:"groups_claim"`
+	// GroupMapping renames the proxy's groups to Determined groups. When it is set, groups that
+	// aren't mapped are ignored.
+	GroupMapping       map[string]string `json:"group_mapping"`
+	AutoProvisionUsers bool              `json:"auto_provision_users"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c ProxyAuthConfig) Validate() []error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errs []error
+	if c.Header == "" {
+		errs = append(errs, errors.New("proxy_auth.header must be set"))
+	}
+	if c.UsernameClaim == "" {
+		errs = append(errs, errors.New("proxy_auth.username_claim must be set"))
+	}
+	switch {
+	case (c.JWKSURL == "") == (c.SharedSecret == ""):
+		errs = append(errs, errors.New("exactly one of proxy_auth.jwks_url and shared_secret must be set"))
+	case c.JWKSURL != "":
+		if u, err := url.Parse(c.JWKSURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, errors.New("proxy_auth.jwks_url must be a URL"))
+		}
+	}
+	return errs
+}
@@ -319,6 +319,17 @@ resource_manager:
   `, nil, "Check Failed! 1 errors found:\n\terror found at " +
 			"root.ResourceConfig.RootManagerInternal.KubernetesRM.InternalTaskGateway: " +
 			"invalid gateway_ip:  must be non-empty"},
+
+		{"image gc thresholds inverted", `
+resource_manager:
+  type: agent
+resource_pools:
+  - pool_name: a
+    image_gc:
+      high_threshold: 0.6
+      low_threshold: 0.8`, nil, "Check Failed! 1 errors found:\n\terror found at " +
+			"root.ResourceConfig.RootPoolsInternal[0].ImageGC: image_gc.low_threshold must be >= 0 " +
+			"and less than image_gc.high_threshold: expected true, got false"},
 	}
 
 	RegisterAuthZType("basic")
@@ -362,6 +373,7 @@ func TestResolveConfig(t *testing.T) {
 						MaxAuxContainersPerAgent: 100,
 						MaxCPUContainersPerAgent: -1,
 						AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+						DiskPressureThreshold:    0.9,
 					},
 				},
 			},
@@ -385,6 +397,7 @@ resource_manager:
 						MaxAuxContainersPerAgent: 100,
 						MaxCPUContainersPerAgent: -1,
 						AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+						DiskPressureThreshold:    0.9,
 					},
 				},
 			},
@@ -412,6 +425,7 @@ resource_manager:
 						MaxAuxContainersPerAgent: 100,
 						MaxCPUContainersPerAgent: -1,
 						AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+						DiskPressureThreshold:    0.9,
 					},
 				},
 			},
@@ -440,6 +454,7 @@ resource_manager:
 						MaxAuxContainersPerAgent: 100,
 						MaxCPUContainersPerAgent: -1,
 						AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+						DiskPressureThreshold:    0.9,
 					},
 				},
 			},
@@ -470,11 +485,13 @@ resource_pools:
 						PoolName:                 "test",
 						MaxAuxContainersPerAgent: 100,
 						AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+						DiskPressureThreshold:    0.9,
 					},
 					{
 						PoolName:                 "test2",
 						MaxAuxContainersPerAgent: 100,
 						AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+						DiskPressureThreshold:    0.9,
 					},
 				},
 			},
@@ -520,11 +537,13 @@ additional_resource_managers:
 						PoolName:                 "a",
 						MaxAuxContainersPerAgent: 100,
 						AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+						DiskPressureThreshold:    0.9,
 					},
 					{
 						PoolName:                 "b",
 						MaxAuxContainersPerAgent: 100,
 						AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+						DiskPressureThreshold:    0.9,
 					},
 				},
 				AdditionalResourceManagersInternal: []*ResourceManagerWithPoolsConfig{
@@ -548,11 +567,13 @@ additional_resource_managers:
 								PoolName:                 "c",
 								MaxAuxContainersPerAgent: 100,
 								AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+								DiskPressureThreshold:    0.9,
 							},
 							{
 								PoolName:                 "d",
 								MaxAuxContainersPerAgent: 100,
 								AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+								DiskPressureThreshold:    0.9,
 							},
 						},
 					},
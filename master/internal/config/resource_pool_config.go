@@ -15,6 +15,25 @@ func defaultRPConfig() ResourcePoolConfig {
 		MaxAuxContainersPerAgent: 100,
 		MaxCPUContainersPerAgent: -1,
 		AgentReconnectWait:       model.Duration(aproto.AgentReconnectWait),
+		DiskPressureThreshold:    0.9,
+	}
+}
+
+// ImageGCConfig configures the removal of unused images from the agents of a resource pool.
+type ImageGCConfig struct {
+	// HighThreshold is the disk utilization of the image partition of an agent at which images
+	// start being removed from it, and LowThreshold the utilization they are removed down to.
+	HighThreshold float64 `json:"high_threshold"`
+	LowThreshold  float64 `json:"low_threshold"`
+}
+
+// Validate implements the check.Validatable interface.
+func (i ImageGCConfig) Validate() []error {
+	return []error{
+		check.True(i.HighThreshold > 0 && i.HighThreshold <= 1,
+			"image_gc.high_threshold must be in (0, 1]"),
+		check.True(i.LowThreshold >= 0 && i.LowThreshold < i.HighThreshold,
+			"image_gc.low_threshold must be >= 0 and less than image_gc.high_threshold"),
 	}
 }
 
@@ -32,6 +51,11 @@ type ResourcePoolConfig struct {
 	// AgentReconnectWait define the time master will wait for agent
 	// before abandoning it.
 	AgentReconnectWait model.Duration `json:"agent_reconnect_wait"`
+	// DiskPressureThreshold is the disk utilization of the image partition of an agent above
+	// which no more containers are scheduled onto it.
+	DiskPressureThreshold float64 `json:"disk_pressure_threshold"`
+	// ImageGC enables removing the least recently used images from agents that are low on disk.
+	ImageGC *ImageGCConfig `json:"image_gc,omitempty"`
 
 	// Deprecated: Use MaxAuxContainersPerAgent instead.
 	MaxCPUContainersPerAgent int `json:"max_cpu_containers_per_agent,omitempty"`
@@ -60,6 +84,8 @@ func (r ResourcePoolConfig) Validate() []error {
 		check.True(len(r.PoolName) != 0, "resource pool name cannot be empty"),
 		check.True(r.MaxAuxContainersPerAgent >= 0,
 			"resource pool max cpu containers per agent should be >= 0"),
+		check.True(r.DiskPressureThreshold > 0 && r.DiskPressureThreshold <= 1,
+			"resource pool disk_pressure_threshold must be in (0, 1]"),
 	}
 }
 
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -21,6 +22,8 @@ const (
 
 	best             = "best"
 	worst            = "worst"
+	pack             = "pack"
+	spread           = "spread"
 	defaultFitPolicy = best
 )
 
@@ -43,6 +46,7 @@ type SchedulerConfig struct {
 	RoundRobin             *RoundRobinSchedulerConfig `union:"type,round_robin" json:"-"`
 	FittingPolicy          string                     `json:"fitting_policy"`
 	AllowHeterogeneousFits bool                       `json:"allow_heterogeneous_fits"`
+	WorkloadClasses        *WorkloadClassesConfig     `json:"workload_classes,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -80,7 +84,7 @@ func (s *SchedulerConfig) UnmarshalJSON(data []byte) error {
 func (s SchedulerConfig) Validate() []error {
 	return []error{
 		check.Contains(
-			s.FittingPolicy, []interface{}{best, worst}, "invalid fitting policy",
+			s.FittingPolicy, []interface{}{best, worst, pack, spread}, "invalid fitting policy",
 		),
 	}
 }
@@ -118,8 +122,9 @@ type FairShareSchedulerConfig struct{}
 
 // PrioritySchedulerConfig holds the configurations for the priority scheduler.
 type PrioritySchedulerConfig struct {
-	Preemption      bool `json:"preemption"`
-	DefaultPriority *int `json:"default_priority"`
+	Preemption      bool                 `json:"preemption"`
+	DefaultPriority *int                 `json:"default_priority"`
+	Aging           *PriorityAgingConfig `json:"aging,omitempty"`
 }
 
 // RoundRobinSchedulerConfig holds the configurations for the round robing scheduler.
@@ -129,3 +134,50 @@ type RoundRobinSchedulerConfig struct{}
 func (p PrioritySchedulerConfig) Validate() []error {
 	return model.ValidatePrioritySetting(p.DefaultPriority)
 }
+
+// PriorityAgingConfig raises the priority of queued jobs the longer they wait, so that jobs of
+// low priority eventually run on a pool that is always busy with jobs of higher priority.
+type PriorityAgingConfig struct {
+	// Interval is how long a job waits for its priority to be raised by one.
+	Interval model.Duration `json:"interval"`
+	// MaxBoost is the most a job's priority is raised by, however long it waits.
+	MaxBoost int `json:"max_boost"`
+	// MaxPriority is the highest priority aging raises a job to. Jobs already at a higher priority
+	// aren't raised.
+	MaxPriority *int `json:"max_priority"`
+}
+
+// Validate implements the check.Validatable interface.
+func (a PriorityAgingConfig) Validate() []error {
+	errs := []error{
+		check.True(time.Duration(a.Interval) >= time.Second,
+			"priority aging interval must be at least 1s"),
+		check.GreaterThan(a.MaxBoost, 0, "priority aging max_boost must be positive"),
+	}
+	return append(errs, model.ValidatePrioritySetting(a.MaxPriority)...)
+}
+
+// WorkloadClassesConfig shares the slots of a resource pool between the classes of workloads it
+// runs: experiments, and notebooks, shells, commands and TensorBoards (NTSC).
+type WorkloadClassesConfig struct {
+	Experiment WorkloadClassConfig `json:"experiment"`
+	NTSC       WorkloadClassConfig `json:"ntsc"`
+}
+
+// WorkloadClassConfig holds the share of a resource pool of a class of workloads.
+type WorkloadClassConfig struct {
+	// Weight is the share of the pool the class is entitled to when other classes compete for it,
+	// relative to the weights of the other classes.
+	Weight float64 `json:"weight"`
+	// ReservedSlots are held for the class even when it doesn't use them.
+	ReservedSlots int `json:"reserved_slots"`
+}
+
+// Validate implements the check.Validatable interface.
+func (w WorkloadClassConfig) Validate() []error {
+	return []error{
+		check.GreaterThanOrEqualTo(w.Weight, 0.0, "workload class weight must be non-negative"),
+		check.GreaterThanOrEqualTo(w.ReservedSlots, 0,
+			"workload class reserved_slots must be non-negative"),
+	}
+}
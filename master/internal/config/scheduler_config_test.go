@@ -2,9 +2,13 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestResourcePoolDefaults(t *testing.T) {
@@ -33,3 +37,74 @@ resource_pools:
 	require.Equal(t, PriorityScheduling, rm[0].ResourceManager.AgentRM.Scheduler.GetType())
 	require.Equal(t, PriorityScheduling, rp[0].Scheduler.GetType())
 }
+
+func TestWorkloadClasses(t *testing.T) {
+	workloadClasses := dbConfig + `
+resource_manager:
+  type: agent
+  scheduler:
+    type: priority
+    workload_classes:
+      experiment:
+        weight: 3
+      ntsc:
+        weight: 1
+        reserved_slots: 4
+`
+	unmarshaled := Config{}
+	err := yaml.Unmarshal([]byte(workloadClasses), &unmarshaled, yaml.DisallowUnknownFields)
+	require.NoError(t, err)
+	require.NoError(t, unmarshaled.Resolve())
+
+	scheduler := unmarshaled.ResourceManagers()[0].ResourceManager.AgentRM.Scheduler
+	require.NoError(t, check.Validate(scheduler))
+	require.Equal(t, &WorkloadClassesConfig{
+		Experiment: WorkloadClassConfig{Weight: 3},
+		NTSC:       WorkloadClassConfig{Weight: 1, ReservedSlots: 4},
+	}, scheduler.WorkloadClasses)
+
+	require.Error(t, check.Validate(WorkloadClassesConfig{
+		NTSC: WorkloadClassConfig{ReservedSlots: -1},
+	}))
+}
+
+func TestPriorityAging(t *testing.T) {
+	priorityAging := dbConfig + `
+resource_manager:
+  type: agent
+  scheduler:
+    type: priority
+    aging:
+      interval: 10m
+      max_boost: 20
+      max_priority: 10
+`
+	unmarshaled := Config{}
+	err := yaml.Unmarshal([]byte(priorityAging), &unmarshaled, yaml.DisallowUnknownFields)
+	require.NoError(t, err)
+	require.NoError(t, unmarshaled.Resolve())
+
+	scheduler := unmarshaled.ResourceManagers()[0].ResourceManager.AgentRM.Scheduler
+	require.NoError(t, check.Validate(scheduler))
+	maxPriority := 10
+	require.Equal(t, &PriorityAgingConfig{
+		Interval:    model.Duration(10 * time.Minute),
+		MaxBoost:    20,
+		MaxPriority: &maxPriority,
+	}, scheduler.Priority.Aging)
+
+	require.Error(t, check.Validate(PriorityAgingConfig{
+		Interval: model.Duration(time.Millisecond),
+		MaxBoost: 1,
+	}))
+	require.Error(t, check.Validate(PriorityAgingConfig{
+		Interval: model.Duration(time.Minute),
+	}))
+}
+
+func TestFittingPolicies(t *testing.T) {
+	for _, policy := range []string{"best", "worst", "pack", "spread"} {
+		require.NoError(t, check.Validate(SchedulerConfig{FittingPolicy: policy}), policy)
+	}
+	require.Error(t, check.Validate(SchedulerConfig{FittingPolicy: "random"}))
+}
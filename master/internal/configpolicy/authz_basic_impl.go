@@ -22,6 +22,16 @@ func (a *ConfigPolicyAuthZBasic) CanModifyWorkspaceConfigPolicies(
 	return nil
 }
 
+// CanOptOutOfPodSecurity requires curUser to be an admin.
+func (a *ConfigPolicyAuthZBasic) CanOptOutOfPodSecurity(
+	ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
+) error {
+	if !curUser.Admin {
+		return fmt.Errorf("only admins may opt out of pod security defaults")
+	}
+	return nil
+}
+
 // CanViewWorkspaceConfigPolicies returns a nil error.
 func (a *ConfigPolicyAuthZBasic) CanViewWorkspaceConfigPolicies(
 	ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
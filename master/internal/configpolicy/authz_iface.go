@@ -19,6 +19,12 @@ type ConfigPolicyAuthZ interface {
 		workspace *workspacev1.Workspace,
 	) error
 
+	// CanOptOutOfPodSecurity returns an error if the user may not run tasks in a workspace whose
+	// pod specs opt out of the pod security defaults of their resource pools.
+	CanOptOutOfPodSecurity(ctx context.Context, curUser model.User,
+		workspace *workspacev1.Workspace,
+	) error
+
 	// CanModifyGlobalConfigPolicies returns an error if the user is not authorized to
 	// modify task config policies.
 	CanModifyGlobalConfigPolicies(ctx context.Context, curUser *model.User,
@@ -18,6 +18,14 @@ func (p *ConfigPolicyAuthZPermissive) CanModifyWorkspaceConfigPolicies(
 	return (&ConfigPolicyAuthZBasic{}).CanModifyWorkspaceConfigPolicies(ctx, curUser, workspace)
 }
 
+// CanOptOutOfPodSecurity calls RBAC authz but enforces basic authz.
+func (p *ConfigPolicyAuthZPermissive) CanOptOutOfPodSecurity(
+	ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
+) error {
+	_ = (&ConfigPolicyAuthZRBAC{}).CanOptOutOfPodSecurity(ctx, curUser, workspace)
+	return (&ConfigPolicyAuthZBasic{}).CanOptOutOfPodSecurity(ctx, curUser, workspace)
+}
+
 // CanViewWorkspaceConfigPolicies calls RBAC authz but enforces basic authz.
 func (p *ConfigPolicyAuthZPermissive) CanViewWorkspaceConfigPolicies(
 	ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
@@ -38,6 +38,23 @@ func (r *ConfigPolicyAuthZRBAC) CanModifyWorkspaceConfigPolicies(
 		rbacv1.PermissionType_PERMISSION_TYPE_MODIFY_WORKSPACE_CONFIG_POLICIES)
 }
 
+// CanOptOutOfPodSecurity determines whether a user can run tasks in a workspace whose pod specs
+// opt out of the pod security defaults of their resource pools.
+func (r *ConfigPolicyAuthZRBAC) CanOptOutOfPodSecurity(
+	ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
+) (err error) {
+	fields := audit.ExtractLogFields(ctx)
+	addConfigPolicyInfo(curUser, workspace, fields, []rbacv1.PermissionType{
+		rbacv1.PermissionType_PERMISSION_TYPE_OPT_OUT_OF_POD_SECURITY,
+	})
+	defer func() {
+		audit.LogFromErr(fields, err)
+	}()
+
+	return db.DoesPermissionMatch(ctx, curUser.ID, ptrs.Ptr(workspace.Id),
+		rbacv1.PermissionType_PERMISSION_TYPE_OPT_OUT_OF_POD_SECURITY)
+}
+
 // CanViewWorkspaceConfigPolicies determines whether a user can view
 // workspace task config policies.
 func (r *ConfigPolicyAuthZRBAC) CanViewWorkspaceConfigPolicies(
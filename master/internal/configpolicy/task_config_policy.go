@@ -36,9 +36,10 @@ type NTSCConfigPolicies struct {
 }
 
 var (
-	errPriorityConstraintFailure = errors.New("submitted workload failed priority constraint")
-	errResourceConstraintFailure = errors.New("submitted workload failed a resource constraint")
-	errPriorityImmutable         = errors.New("priority cannot be modified")
+	errPriorityConstraintFailure  = errors.New("submitted workload failed priority constraint")
+	errResourceConstraintFailure  = errors.New("submitted workload failed a resource constraint")
+	errPriorityImmutable          = errors.New("priority cannot be modified")
+	errBindMountConstraintFailure = errors.New("submitted workload failed a bind mount constraint")
 )
 
 // CheckNTSCConstraints returns an error if the NTSC config fails constraint checks.
@@ -53,6 +54,12 @@ func CheckNTSCConstraints(
 		return err
 	}
 
+	err = checkBindMountConstraints(ctx, workspaceID, model.NTSCType,
+		workloadConfig.BindMounts.ToExpconf())
+	if err != nil {
+		return err
+	}
+
 	if constraints.ResourceConstraints != nil && constraints.ResourceConstraints.MaxSlots != nil {
 		if err = checkSlotsConstraint(*constraints.ResourceConstraints.MaxSlots, &workloadConfig.Resources.Slots,
 			workloadConfig.Resources.MaxSlots); err != nil {
@@ -86,6 +93,21 @@ func CheckExperimentConstraints(
 		return err
 	}
 
+	// Shared filesystem checkpoint storage is bind mounted read-write as well.
+	bindMounts := workloadConfig.BindMounts()
+	if workloadConfig.RawCheckpointStorage != nil {
+		if fs := workloadConfig.RawCheckpointStorage.RawSharedFSConfig; fs != nil {
+			readOnly := false
+			bindMounts = append(bindMounts, expconf.BindMountV0{
+				RawHostPath: fs.HostPath(), RawReadOnly: &readOnly,
+			})
+		}
+	}
+	err = checkBindMountConstraints(ctx, workspaceID, model.ExperimentType, bindMounts)
+	if err != nil {
+		return err
+	}
+
 	if constraints.ResourceConstraints != nil && constraints.ResourceConstraints.MaxSlots != nil {
 		// users cannot specify number of slots for an experiment
 		if workloadConfig.RawResources != nil {
@@ -148,6 +170,54 @@ func checkSlotsConstraint(slotsLimit int, slotsRequest *int, maxSlotsRequest *in
 	return nil
 }
 
+// checkBindMountConstraints returns an error if a bind mount violates the workspace or global bind
+// mount constraints. Unlike other constraints, global bind mount constraints don't replace the
+// workspace's; bind mounts must satisfy both.
+func checkBindMountConstraints(
+	ctx context.Context, workspaceID int, workloadType string, bindMounts expconf.BindMountsConfig,
+) error {
+	violations, err := BindMountViolations(ctx, workspaceID, workloadType, bindMounts)
+	if err != nil {
+		return err
+	}
+	for _, err := range violations {
+		if err != nil {
+			return fmt.Errorf("%s: %w", err, errBindMountConstraintFailure)
+		}
+	}
+	return nil
+}
+
+// BindMountViolations returns the violation of the workspace or global bind mount constraints of
+// each bind mount, or nil for the bind mounts that satisfy them, in the order of bindMounts.
+func BindMountViolations(
+	ctx context.Context, workspaceID int, workloadType string, bindMounts expconf.BindMountsConfig,
+) ([]error, error) {
+	violations := make([]error, len(bindMounts))
+	for _, scope := range []*int{&workspaceID, nil} {
+		configPolicies, err := GetTaskConfigPolicies(ctx, scope, workloadType)
+		if err != nil {
+			return nil, err
+		}
+		if configPolicies.Constraints == nil {
+			continue
+		}
+		var constraints model.Constraints
+		if err = json.Unmarshal([]byte(*configPolicies.Constraints), &constraints); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal task config policies: %w", err)
+		}
+		if constraints.BindMounts == nil {
+			continue
+		}
+		for i, m := range bindMounts {
+			if violations[i] == nil {
+				violations[i] = constraints.BindMounts.CheckHostPath(m.HostPath(), m.ReadOnly())
+			}
+		}
+	}
+	return violations, nil
+}
+
 // GetMergedConstraints retrieves Workspace and Global constraints and returns a merged result.
 // workloadType is expected to be model.ExperimentType or model.NTSCType.
 func GetMergedConstraints(ctx context.Context, workspaceID int, workloadType string) (*model.Constraints, error) {
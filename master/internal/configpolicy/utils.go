@@ -140,6 +140,9 @@ func ValidateExperimentConfig(
 
 	if cp.Constraints != nil {
 		checkAgainstGlobalPriority(priorityEnabledErr, cp.Constraints.PriorityLimit)
+		if err := checkBindMountConstraintPaths(cp.Constraints); err != nil {
+			return status.Errorf(codes.InvalidArgument, fmt.Sprintf(InvalidExperimentConfigPolicyErr+": %s.", err))
+		}
 	}
 
 	if cp.InvariantConfig != nil {
@@ -202,6 +205,9 @@ func ValidateNTSCConfig(
 
 	if cp.Constraints != nil {
 		checkAgainstGlobalPriority(priorityEnabledErr, cp.Constraints.PriorityLimit)
+		if err := checkBindMountConstraintPaths(cp.Constraints); err != nil {
+			return status.Errorf(codes.InvalidArgument, fmt.Sprintf(InvalidNTSCConfigPolicyErr+": %s.", err))
+		}
 	}
 
 	if cp.InvariantConfig != nil {
@@ -237,6 +243,16 @@ func checkAgainstGlobalPriority(priorityEnabledErr error, taskPriority *int) {
 	}
 }
 
+func checkBindMountConstraintPaths(constraints *model.Constraints) error {
+	if constraints.BindMounts == nil {
+		return nil
+	}
+	if errs := constraints.BindMounts.Validate(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
 func checkConstraintConflicts(constraints *model.Constraints, maxSlots, slots, priority *int) error {
 	if constraints == nil {
 		return nil
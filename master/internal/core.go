@@ -46,16 +46,25 @@ import (
 	"github.com/determined-ai/determined/master/internal/connsave"
 	detContext "github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/internal/elastic"
+	"github.com/determined-ai/determined/master/internal/federation"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/httppolicy"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
+	"github.com/determined-ai/determined/master/internal/job/jobstream"
 	"github.com/determined-ai/determined/master/internal/license"
 	"github.com/determined-ai/determined/master/internal/logpattern"
 	"github.com/determined-ai/determined/master/internal/logretention"
+	"github.com/determined-ai/determined/master/internal/logship"
+	"github.com/determined-ai/determined/master/internal/metricsexport"
+	"github.com/determined-ai/determined/master/internal/modeleval"
+	"github.com/determined-ai/determined/master/internal/plugin/proxyauth"
 	"github.com/determined-ai/determined/master/internal/plugin/sso"
 	"github.com/determined-ai/determined/master/internal/portregistry"
 	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/proxy"
+	"github.com/determined-ai/determined/master/internal/raycluster"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm"
 	"github.com/determined-ai/determined/master/internal/rm/dispatcherrm"
@@ -71,6 +80,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/telemetry"
 	"github.com/determined-ai/determined/master/internal/trials"
 	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/versioncompat"
 	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/etc"
@@ -80,6 +90,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/tasks"
 	"github.com/determined-ai/determined/master/version"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
 	"github.com/determined-ai/determined/proto/pkg/masterv1"
 )
 
@@ -112,6 +123,11 @@ type Master struct {
 
 	trialLogBackend TrialLogBackend
 	taskLogBackend  TaskLogBackend
+	logReceiver     *logship.Receiver
+
+	federationPeers []*federation.Peer
+	jobQueues       *jobstream.Publisher
+	versions        *versioncompat.Registry
 }
 
 // New creates an instance of the Determined master.
@@ -127,7 +143,8 @@ func New(logStore *logger.LogBuffer, config *config.Config) *Master {
 // Info returns this master's information.
 func (m *Master) Info() aproto.MasterInfo {
 	telemetryInfo := aproto.TelemetryInfo{}
-	if m.config.Telemetry.SegmentWebUIKey != "" {
+	// The WebUI reports to Segment directly, so it doesn't report when telemetry is exported.
+	if m.config.Telemetry.SegmentWebUIKey != "" && m.config.Telemetry.Export == nil {
 		telemetryInfo.SegmentKey = m.config.Telemetry.SegmentWebUIKey
 	}
 
@@ -835,7 +852,33 @@ func (m *Master) tryRestoreExperiment(sema chan struct{}, wg *sync.WaitGroup, e
 		if err := m.db.TerminateExperimentInRestart(e.ID, e.State); err != nil {
 			log.WithError(err).Error("failed to mark experiment as errored")
 		}
-		telemetry.ReportExperimentStateChanged(m.db, e)
+	}
+}
+
+// reportExperimentStateTransition sends the telemetry and webhook events for a change of the state
+// of an experiment. It's registered with db.OnExperimentStateTransition, so the events are sent
+// for every transition, whichever code path made it.
+func (m *Master) reportExperimentStateTransition(
+	ctx context.Context, t model.ExperimentStateTransition,
+) {
+	e, err := db.ExperimentByID(ctx, t.ExperimentID)
+	if err != nil {
+		log.WithError(err).Errorf("getting experiment %d to report its state change",
+			t.ExperimentID)
+		return
+	}
+	// The experiment may have changed state again since; report the state of this transition.
+	e.State = t.ToState
+	telemetry.ReportExperimentStateChanged(m.db, e)
+
+	activeConfig, err := m.db.ActiveExperimentConfig(e.ID)
+	if err != nil {
+		log.WithError(err).Errorf("getting config of experiment %d to report its state change",
+			e.ID)
+		return
+	}
+	if err := webhooks.ReportExperimentStateChanged(ctx, *e, activeConfig); err != nil {
+		log.WithError(err).Errorf("failed to send state change webhook of experiment %d", e.ID)
 	}
 }
 
@@ -931,23 +974,28 @@ func (m *Master) restoreGenericTasks(ctx context.Context) error {
 			continue
 		}
 
+		allocateReq := sproto.AllocateRequest{
+			AllocationID:      snapshots[i].AllocationID,
+			TaskID:            taskID,
+			JobID:             *jobID,
+			TaskType:          model.TaskTypeGeneric,
+			JobSubmissionTime: snapshots[i].RegisteredTime,
+			IsUserVisible:     true,
+			Name:              fmt.Sprintf("Generic Task %s", taskID),
+			SlotsNeeded:       *slots,
+			ResourcePool:      *resourcePool,
+			FittingRequirements: sproto.FittingRequirements{
+				SingleAgent: isSingleNode,
+			},
+
+			Restore: true,
+		}
+		configureGenericTaskAllocation(&allocateReq, snapshots[i].GenericTaskSpec)
+		registerRayCluster(taskID, snapshots[i].GenericTaskSpec)
 		err := task.DefaultService.StartAllocation(logCtx,
-			sproto.AllocateRequest{
-				AllocationID:      snapshots[i].AllocationID,
-				TaskID:            taskID,
-				JobID:             *jobID,
-				JobSubmissionTime: snapshots[i].RegisteredTime,
-				IsUserVisible:     true,
-				Name:              fmt.Sprintf("Generic Task %s", taskID),
-				SlotsNeeded:       *slots,
-				ResourcePool:      *resourcePool,
-				FittingRequirements: sproto.FittingRequirements{
-					SingleAgent: isSingleNode,
-				},
-
-				Restore: true,
-			}, m.db, m.rm, snapshots[i].GenericTaskSpec, onAllocationExit)
+			allocateReq, m.db, m.rm, snapshots[i].GenericTaskSpec, onAllocationExit)
 		if err != nil {
+			raycluster.Unregister(taskID)
 			return err
 		}
 	}
@@ -962,6 +1010,17 @@ func (m *Master) closeOpenAllocations(ctx context.Context) error {
 	return nil
 }
 
+// liveAllocationIDs returns the allocations running in this master, so proxied services of others
+// can be removed.
+func liveAllocationIDs() map[string]bool {
+	ids := task.DefaultService.GetAllAllocationIDs()
+	live := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		live[string(id)] = true
+	}
+	return live
+}
+
 // convertDBErrorsToNotFound helps reduce boilerplate in our handlers, by
 // classifying database "not found" errors as HTTP "not found" errors.
 func convertDBErrorsToNotFound(next echo.HandlerFunc) echo.HandlerFunc {
@@ -986,6 +1045,13 @@ func convertCtxErrsToTimeout(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// echoAPIServer returns an API server and a context carrying the request's user, so echo handlers
+// can share code with the gRPC API.
+func (m *Master) echoAPIServer(c echo.Context) (*apiServer, context.Context) {
+	user := c.(*detContext.DetContext).MustGetUser()
+	return &apiServer{m: m}, grpcutil.ContextWithUser(c.Request().Context(), &user)
+}
+
 func updateClusterHeartbeat(ctx context.Context, db *db.PgDB) {
 	t := time.NewTicker(10 * time.Minute)
 	defer t.Stop()
@@ -1078,7 +1144,7 @@ func (m *Master) buildRM(
 		clusterName := config.ResourceManager.ClusterName()
 		switch {
 		case config.ResourceManager.AgentRM != nil:
-			agentRM, err := agentrm.New(db, echo, config, opts, cert)
+			agentRM, err := agentrm.New(db, echo, config, opts, m.versions, cert)
 			if err != nil {
 				return nil, err
 			}
@@ -1123,7 +1189,7 @@ func (m *Master) buildRM(
 			}
 			clusterNames[rmClusterName] = 0
 
-			agentRM, err := agentrm.New(db, echo, cfg, opts, cert)
+			agentRM, err := agentrm.New(db, echo, cfg, opts, m.versions, cert)
 			if err != nil {
 				return nil, fmt.Errorf("resource manager %s: %w", c.ClusterName(), err)
 			}
@@ -1164,6 +1230,8 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	if err = etc.SetRootPath(filepath.Join(m.config.Root, "static/srv")); err != nil {
 		return errors.Wrap(err, "could not set static root")
 	}
+	egress.Configure(m.config.EgressProxy)
+	httppolicy.Set(m.config.HTTPPolicy)
 
 	var isOldCluster bool
 	newClustersRequirePasswords := func(*db.PgDB) error {
@@ -1243,9 +1311,11 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 		TaskContainerDefaults: m.config.TaskContainerDefaults,
 		MasterCert:            config.GetCertPEM(cert),
 		SSHConfig:             m.config.Security.SSH,
-		SegmentEnabled:        m.config.Telemetry.Enabled && m.config.Telemetry.SegmentMasterKey != "",
+		SegmentEnabled:        m.config.Telemetry.SegmentEnabled(),
 		SegmentAPIKey:         m.config.Telemetry.SegmentMasterKey,
 		LogRetentionDays:      m.config.RetentionPolicy.LogRetentionDays,
+		TaskLogLimits:         m.config.TaskLogLimits,
+		TaskLogShipping:       m.config.TaskLogShipping,
 	}
 	if m.config.RetentionPolicy.Schedule != nil {
 		lrs, err := logretention.NewScheduler()
@@ -1262,7 +1332,31 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 		}()
 	}
 
+	if r := m.config.TaskRecordRetention; r.Days != nil && r.Schedule != nil {
+		trs, err := logretention.NewScheduler()
+		if err != nil {
+			return fmt.Errorf("initializing task record retention scheduler: %w", err)
+		}
+		if err := trs.ScheduleTaskRecords(r); err != nil {
+			return fmt.Errorf("scheduling task record retention enforcer: %w", err)
+		}
+		defer func() {
+			if err := trs.Shutdown(); err != nil {
+				log.WithError(err).Warn("shutting down task record retention workers")
+			}
+		}()
+	}
+
 	go m.cleanUpExperimentSnapshots()
+	if err := m.failInterruptedMLflowImports(ctx); err != nil {
+		return err
+	}
+	if err := m.loadHTTPPolicy(ctx); err != nil {
+		return err
+	}
+	if err := modeleval.FailInterruptedLaunches(ctx); err != nil {
+		return err
+	}
 
 	switch {
 	case m.config.Logging.DefaultLoggingConfig != nil:
@@ -1279,11 +1373,14 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 		panic("unsupported logging backend")
 	}
 	tasklogger.SetDefaultLogger(tasklogger.New(m.taskLogBackend))
+	m.logReceiver = logship.NewReceiver(
+		m.config.TaskLogShipping.MaxInflightLines, m.config.TaskLogShipping.MaxBatchLines)
 
 	user.InitService(m.db, &m.config.InternalConfig.ExternalSessions)
 	userService := user.GetService()
 
 	proxy.InitProxy(processProxyAuthentication)
+	go proxy.DefaultProxy.Reconciler(context.Background(), liveAllocationIDs)
 	portregistry.InitPortRegistry(config.GetMasterConfig().ReservedPorts)
 
 	go periodicallyAggregateResourceAllocation(m.db)
@@ -1319,16 +1416,14 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	}))
 	setupEchoRedirects(m)
 
-	if m.config.EnableCors {
-		m.echo.Use(api.CORSWithTargetedOrigin)
-	}
+	// Apply the CORS and Content Security Policy admins set, including X-Frame-Options.
+	m.echo.Use(httppolicy.Middleware)
 
 	// Add resistance to common HTTP attacks.
 	secureConfig := middleware.SecureConfig{
 		Skipper:            middleware.DefaultSkipper,
 		XSSProtection:      "1; mode=block",
 		ContentTypeNosniff: "nosniff",
-		XFrameOptions:      "SAMEORIGIN",
 	}
 	m.echo.Use(middleware.SecureWithConfig(secureConfig))
 
@@ -1358,6 +1453,11 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	for _, ps := range m.config.InternalConfig.ProxiedServers {
 		proxiedRoutes = append(proxiedRoutes, ps.PathPrefix)
 	}
+	if m.config.ProxyAuth.Enabled {
+		log.Infof("authenticating requests with the %s header from a trusted proxy",
+			m.config.ProxyAuth.Header)
+		m.echo.Use(proxyauth.New(m.config.ProxyAuth).ProcessAuthentication)
+	}
 	m.echo.Use(processAuthWithRedirect(proxiedRoutes))
 
 	m.echo.Logger = logger.New()
@@ -1372,6 +1472,13 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 		return errors.Wrap(err, "could not update end stats for instances")
 	}
 
+	if m.federationPeers, err = federation.NewPeers(m.config.Federation); err != nil {
+		return err
+	}
+
+	// Agents report their versions when they connect, so this must happen before the RM starts.
+	m.versions = versioncompat.NewRegistry(version.Version, m.config.VersionCompatibility)
+
 	// Resource Manager.
 	if m.rm, err = m.buildRM(m.db, m.echo, m.config.ResourceManagers(),
 		&m.config.TaskContainerDefaults,
@@ -1385,13 +1492,33 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	}
 
 	jobservice.SetDefaultService(m.rm)
+	if err = m.restoreResourcePoolReservations(context.TODO()); err != nil {
+		return fmt.Errorf("restoring resource pool reservations: %w", err)
+	}
+	m.jobQueues = jobstream.NewPublisher(jobstream.DefaultPollInterval,
+		func(resourcePool string) ([]*jobv1.Job, error) {
+			return jobservice.DefaultService.GetJobs(rm.ResourcePoolName(resourcePool), false, nil)
+		})
 
 	tasksGroup := m.echo.Group("/tasks")
 	tasksGroup.GET("", api.Route(m.getTasks))
-
+	tasksGroup.GET("/:task_id/rendezvous", api.Route(m.getTaskRendezvous))
+	tasksGroup.GET("/:task_id/rendezvous/observed-address", api.Route(m.getTaskObservedAddress))
+	tasksGroup.POST("/:task_id/rendezvous/reachability",
+		api.Route(m.postTaskRendezvousReachability))
+	tasksGroup.POST("/:task_id/metrics", api.Route(m.postTaskMetrics))
+	tasksGroup.POST("/:task_id/checkpoints", api.Route(m.postTaskCheckpoint))
+	tasksGroup.GET("/:task_id/diagnostics", api.Route(m.getTaskDiagnostics))
+	tasksGroup.GET("/:task_id/restarts", api.Route(m.getTaskRestarts))
+	tasksGroup.POST("/:task_id/heartbeat", api.Route(m.postTaskHeartbeat))
+	tasksGroup.GET("/:task_id/liveness", api.Route(m.getTaskLiveness))
+	tasksGroup.GET("/:task_id/logs/stream", m.getTaskLogsStream)
+
+	db.OnExperimentStateTransition(m.reportExperimentStateTransition)
 	if err = m.restoreNonTerminalExperiments(); err != nil {
 		return err
 	}
+	go m.expireBoosts(ctx)
 
 	if err = m.db.FailDeletingExperiment(); err != nil {
 		return err
@@ -1430,6 +1557,7 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	// This ensures that in the scenario where a cluster fails all open allocations are
 	// set to the last cluster heartbeat when the cluster was running.
 	go updateClusterHeartbeat(ctx, m.db)
+	go m.runModelEvaluationSchedules(ctx)
 	go trials.MarkLostTrialsWorker(ctx)
 
 	// Docs and WebUI.
@@ -1503,36 +1631,62 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 
 	m.echo.GET("/info", api.Route(m.getInfo))
 	m.echo.GET("/health", m.healthCheckEndpoint)
+	m.echo.GET("/support-bundle", m.getSupportBundle)
 
 	experimentsGroup := m.echo.Group("/experiments")
 	experimentsGroup.GET("/:experiment_id/model_def", m.getExperimentModelDefinition)
 	experimentsGroup.GET("/:experiment_id/file/download", m.getExperimentModelFile)
 	experimentsGroup.GET("/:experiment_id/preview_gc", api.Route(m.getExperimentCheckpointsToGC))
+	experimentsGroup.POST("/external-runs", api.Route(m.postExternalRun))
+	experimentsGroup.GET("/:experiment_id/batch-size-tuning",
+		api.Route(m.getExperimentBatchSizeTuning))
+	experimentsGroup.GET("/:experiment_id/metrics/export", m.getExperimentMetricsExport)
+	experimentsGroup.GET("/:experiment_id/metric-metadata", api.Route(m.getExperimentMetricMetadata))
+	experimentsGroup.GET("/:experiment_id/reports/:report_id", m.getExperimentReport)
+
+	trialsGroup := m.echo.Group("/trials")
+	trialsGroup.GET("/:trial_id/logs/stream", m.getTrialLogsStream)
+	trialsGroup.GET("/:trial_id/logs/ordered", api.Route(m.getTrialLogsOrdered))
+	trialsGroup.PUT("/:trial_id/metric-metadata", api.Route(m.putTrialMetricMetadata))
+	trialsGroup.GET("/:trial_id/metrics/stream", m.getTrialMetricsStream)
+	trialsGroup.GET("/:trial_id/metrics/export", m.getTrialMetricsExport)
 
 	checkpointsGroup := m.echo.Group("/checkpoints")
 	checkpointsGroup.GET("/:checkpoint_uuid", m.getCheckpoint)
 
+	commandsGroup := m.echo.Group("/commands")
+	commandsGroup.GET("/:command_id/exec",
+		api.WebSocketRoute(m.getCommandExec))
+
+	jobQueuesGroup := m.echo.Group("/job-queues")
+	jobQueuesGroup.GET("/:resource_pool/stream",
+		api.WebSocketRoute(m.getJobQueueStream))
+
 	resourcesGroup := m.echo.Group("/resources", cluster.CanGetUsageDetails())
 	resourcesGroup.GET("/allocation/raw", m.getRawResourceAllocation)
 	resourcesGroup.GET("/allocation/allocations-csv", m.getResourceAllocations)
 	resourcesGroup.GET("/allocation/aggregated", m.getAggregatedResourceAllocation)
 
-	m.echo.POST("/task-logs", api.Route(m.postTaskLogs))
+	// The workspace API authenticates with workspace API keys rather than user tokens.
+	workspaceAPIGroup := m.echo.Group("/workspace-api/v1", processWorkspaceAPIKeyAuthentication)
+	workspaceAPIGroup.GET("/workspace", api.Route(m.getWorkspaceAPIWorkspace))
+	workspaceAPIGroup.GET("/experiments", api.Route(m.getWorkspaceAPIExperiments))
+	workspaceAPIGroup.GET("/trials/:trial_id/metrics", api.Route(m.getWorkspaceAPITrialMetrics))
 
-	m.echo.Any("/debug/pprof/*", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
-	m.echo.Any(
-		"/debug/pprof/cmdline",
-		echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)),
-	)
-	m.echo.Any(
-		"/debug/pprof/profile",
-		echo.WrapHandler(http.HandlerFunc(pprof.Profile)),
-	)
-	m.echo.Any(
-		"/debug/pprof/symbol",
-		echo.WrapHandler(http.HandlerFunc(pprof.Symbol)),
-	)
-	m.echo.Any("/debug/pprof/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
+	m.echo.POST("/task-logs", api.Route(m.postTaskLogs))
+	m.echo.POST("/task-logs/v2", api.Route(m.postTaskLogBatch))
+
+	// Profiling and state dumps are for diagnosing a misbehaving master without restarting it, so
+	// they are always served, but only to users allowed to profile the master.
+	debugGroup := m.echo.Group("/debug", cluster.CanProfileMaster())
+	debugGroup.Any("/pprof/*", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	debugGroup.Any("/pprof/cmdline", echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+	debugGroup.Any("/pprof/profile", echo.WrapHandler(http.HandlerFunc(pprof.Profile)))
+	debugGroup.Any("/pprof/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+	debugGroup.Any("/pprof/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
+	debugGroup.GET("/allocations", api.Route(m.getDebugAllocations))
+	debugGroup.GET("/experiment-snapshots", api.Route(m.getDebugExperimentSnapshots))
+	debugGroup.GET("/migrations", api.Route(m.getDebugMigrations))
 
 	if m.config.Observability.EnablePrometheus {
 		p := prometheus.NewPrometheus("echo", nil)
@@ -1549,6 +1703,7 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 		}
 
 		m.promHealth(ctx)
+		m.promCheckpointStorageHealth(ctx)
 		p.Use(m.echo)
 		m.echo.Any("/debug/prom/metrics", echo.WrapHandler(promhttp.Handler()))
 		m.echo.Any("/prom/det-state-metrics",
@@ -1593,12 +1748,23 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	webhooks.Init()
 	defer webhooks.Deinit()
 
+	metricsexport.Init()
+	defer metricsexport.Deinit()
+
 	if slices.Contains(m.config.FeatureSwitches, "streaming_updates") {
 		ssup := stream.NewSupervisor(m.db.URL)
 		go func() {
 			_ = ssup.Run(ctx)
 		}()
-		m.echo.GET("/stream", api.WebSocketRoute(ssup.Websocket, m.config.EnableCors))
+		m.echo.GET("/stream", api.WebSocketRoute(ssup.Websocket))
+	}
+
+	if m.config.DB.OnlineMigrations {
+		go func() {
+			if err := m.db.MigrateContract(m.config.DB.Migrations); err != nil {
+				log.WithError(err).Error("failed to apply contract migrations")
+			}
+		}()
 	}
 
 	return m.startServers(ctx, cert, gRPCLogInitDone)
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/boostrequest"
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// boostExpiryInterval is how often approved boosts are checked for expiry.
+const boostExpiryInterval = time.Minute
+
+// errExperimentNotActive is returned when boosting an experiment that isn't running.
+var errExperimentNotActive = errors.New("experiment isn't active")
+
+// boostedValue returns an experiment's current effective priority, or its max slots, which is
+// unset if it has no limit.
+func (m *Master) boostedValue(expID int, kind model.BoostRequestKind) (*int, error) {
+	activeConfig, err := m.db.ActiveExperimentConfig(expID)
+	if err != nil {
+		return nil, fmt.Errorf("getting config of experiment %d: %w", expID, err)
+	}
+	switch kind {
+	case model.BoostRequestKindPriority:
+		priority := config.ReadPriority(activeConfig.Resources().ResourcePool(), &activeConfig)
+		return &priority, nil
+	case model.BoostRequestKindMaxSlots:
+		return activeConfig.Resources().MaxSlots(), nil
+	default:
+		return nil, fmt.Errorf("unknown boost request kind %q", kind)
+	}
+}
+
+// applyBoost sets the priority or max slots of a running experiment. An unset max slots removes
+// the experiment's limit.
+func (m *Master) applyBoost(expID int, kind model.BoostRequestKind, value *int) error {
+	e, ok := experiment.ExperimentRegistry.Load(expID)
+	if !ok {
+		return fmt.Errorf("boosting experiment %d: %w", expID, errExperimentNotActive)
+	}
+
+	switch kind {
+	case model.BoostRequestKindPriority:
+		if value == nil {
+			return fmt.Errorf("boosting experiment %d: priority must be set", expID)
+		}
+		return e.SetGroupPriority(*value)
+	case model.BoostRequestKindMaxSlots:
+		activeConfig, err := m.db.ActiveExperimentConfig(expID)
+		if err != nil {
+			return fmt.Errorf("getting config of experiment %d: %w", expID, err)
+		}
+		resources := activeConfig.Resources()
+		resources.SetMaxSlots(value)
+		activeConfig.SetResources(resources)
+		if err := m.db.SaveExperimentConfig(expID, activeConfig); err != nil {
+			return fmt.Errorf("setting max slots of experiment %d: %w", expID, err)
+		}
+		e.SetGroupMaxSlots(sproto.SetGroupMaxSlots{MaxSlots: value})
+		return nil
+	default:
+		return fmt.Errorf("unknown boost request kind %q", kind)
+	}
+}
+
+// expireBoosts reverts approved boosts to the values they replaced once they expire, until ctx is
+// canceled. Boosts that expired while the master was down are reverted on startup.
+func (m *Master) expireBoosts(ctx context.Context) {
+	t := time.NewTicker(boostExpiryInterval)
+	defer t.Stop()
+	for {
+		m.revertExpiredBoosts(ctx)
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Master) revertExpiredBoosts(ctx context.Context) {
+	due, err := boostrequest.DueBoostRequests(ctx, time.Now())
+	if err != nil {
+		log.WithError(err).Error("failed to get expired boosts")
+		return
+	}
+	for _, r := range due {
+		current, err := m.boostedValue(r.ExperimentID, r.Kind)
+		if err != nil {
+			log.WithError(err).Errorf("failed to revert boost request %d", r.ID)
+			continue
+		}
+		reverted := boostrequest.StillBoosted(&r, current)
+		if reverted {
+			// There's nothing to revert once the experiment has finished.
+			err := m.applyBoost(r.ExperimentID, r.Kind, r.OriginalValue)
+			if err != nil && !errors.Is(err, errExperimentNotActive) {
+				log.WithError(err).Errorf("failed to revert boost request %d", r.ID)
+				continue
+			}
+		}
+		if err := boostrequest.Expire(ctx, r.ID); err != nil {
+			log.WithError(err).Errorf("failed to expire boost request %d", r.ID)
+			continue
+		}
+		if !reverted {
+			log.Infof("boost request %d expired: %s of experiment %d was changed during the "+
+				"boost and is kept", r.ID, r.Kind, r.ExperimentID)
+			continue
+		}
+		log.Infof("boost request %d expired: %s of experiment %d reverted",
+			r.ID, r.Kind, r.ExperimentID)
+	}
+}
@@ -70,6 +70,11 @@ func (w *delayWriter) Close() error {
 	return w.next.Flush()
 }
 
+// Reset discards any buffered bytes and directs later writes to next.
+func (w *delayWriter) Reset(next io.Writer) {
+	w.next.Reset(next)
+}
+
 func newDelayWriter(w io.Writer, delayBytes int) *delayWriter {
 	return &delayWriter{
 		next: bufio.NewWriterSize(w, delayBytes),
@@ -107,7 +112,7 @@ func (m *Master) getCheckpointStorageConfig(ctx context.Context, id uuid.UUID) (
 }
 
 func (m *Master) getCheckpointImpl(
-	ctx context.Context, id uuid.UUID, mimeType string, content *echo.Response,
+	ctx context.Context, id uuid.UUID, mimeType string, rangeHeader string, content *echo.Response,
 ) error {
 	// Assume a checkpoint always has experiment configs
 	storageConfig, err := m.getCheckpointStorageConfig(ctx, id)
@@ -120,19 +125,27 @@ func (m *Master) getCheckpointImpl(
 		return api.NotFoundErrs("checkpoint", id.String(), false)
 	}
 
+	// Bytes reach the client at no more than the configured bandwidth limit.
+	out := checkpoints.NewThrottledWriter(ctx, content, m.config.CheckpointDownload.BandwidthLimit)
+
+	// Serving a range stops the download early once the range is written.
+	downloadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// DelayWriter delays the first write until we have successfully downloaded
 	// some bytes and are more confident that the download will succeed.
-	dw := newDelayWriter(content, 16*1024)
+	dw := newDelayWriter(out, 16*1024)
 	aw, err := archive.NewArchiveWriter(dw, mimeToArchiveType(mimeType))
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	downloader, err := checkpoints.NewDownloader(ctx, dw, id.String(), storageConfig, aw)
+	downloader, err := checkpoints.NewDownloader(downloadCtx, dw, id.String(), storageConfig, aw)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	var rw *checkpoints.RangeWriter
 	if aw.DryRunEnabled() {
 		files, err := downloader.ListFiles(ctx)
 		if err != nil {
@@ -147,11 +160,31 @@ func (m *Master) getCheckpointImpl(
 		}
 		if contentLength > 0 {
 			log.Debugf("dry-run content-length: %d", contentLength)
+			// Tar archives of the same checkpoint are byte-for-byte identical, so once the length
+			// is known an interrupted download can be resumed with a Range request.
+			content.Header().Set("Accept-Ranges", "bytes")
+			r, err := checkpoints.ParseRange(rangeHeader, contentLength)
+			switch {
+			case errors.Is(err, checkpoints.ErrRangeNotSatisfiable):
+				content.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", contentLength))
+				return echo.NewHTTPError(http.StatusRequestedRangeNotSatisfiable, err.Error())
+			case r != nil:
+				rw = checkpoints.NewRangeWriter(out, *r, cancel)
+				dw.Reset(rw)
+				content.Header().Set("Content-Range", r.ContentRange(contentLength))
+				contentLength = r.Length()
+				content.Status = http.StatusPartialContent
+			}
 			content.Header().Set(echo.HeaderContentLength, strconv.FormatInt(contentLength, 10))
 		}
 	}
 
-	err = downloader.Download(ctx)
+	err = downloader.Download(downloadCtx)
+	if rw != nil && rw.Satisfied() {
+		// The rest of the archive was cut off on purpose, so errors from here on don't matter.
+		_ = downloader.Close()
+		return nil
+	}
 	switch {
 	case err != nil && errors.Is(err, context.Canceled):
 		return err
@@ -172,13 +205,41 @@ func (m *Master) getCheckpointImpl(
 	return nil
 }
 
+// echoCanGetCheckpointArtifacts checks that the caller can get a checkpoint's files, through
+// either its experiment or a model version it is registered as.
+func (m *Master) echoCanGetCheckpointArtifacts(c echo.Context, checkpointUUID string) error {
+	curUser := c.(*detContext.DetContext).MustGetUser()
+	errE := m.canDoActionOnCheckpoint(c.Request().Context(), curUser, checkpointUUID,
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts)
+	if errE != nil {
+		errM := m.canDoActionOnCheckpointThroughModel(c.Request().Context(), curUser, checkpointUUID)
+		if errM != nil {
+			s, ok := status.FromError(errE)
+			if !ok {
+				return errE
+			}
+			switch s.Code() {
+			case codes.NotFound:
+				return echo.NewHTTPError(http.StatusNotFound, s.Message())
+			case codes.PermissionDenied:
+				return echo.NewHTTPError(http.StatusForbidden, s.Message())
+			default:
+				return fmt.Errorf(s.Message())
+			}
+		}
+	}
+	return nil
+}
+
 //	@Summary	Get a checkpoint's contents in a tar, tgz, or zip file.
 //	@Tags		Checkpoints
 //	@ID			get-checkpoint
 //	@Accept		json
 //	@Produce	application/x-tar,application/gzip,application/zip
 //	@Param		checkpoint_uuid	path	string	true	"Checkpoint UUID"
+//	@Param		Range			header	string	false	"Byte range to resume a tar download"
 //	@Success	200				{}		string	""
+//	@Success	206				{}		string	""
 //	@Router		/checkpoints/{checkpoint_uuid} [get]
 //
 // Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
@@ -209,26 +270,10 @@ func (m *Master) getCheckpoint(c echo.Context) error {
 				args.CheckpointUUID, err))
 	}
 
-	curUser := c.(*detContext.DetContext).MustGetUser()
-	errE := m.canDoActionOnCheckpoint(c.Request().Context(), curUser, args.CheckpointUUID,
-		expauth.AuthZProvider.Get().CanGetExperimentArtifacts)
-	if errE != nil {
-		errM := m.canDoActionOnCheckpointThroughModel(c.Request().Context(), curUser, args.CheckpointUUID)
-		if errM != nil {
-			s, ok := status.FromError(errE)
-			if !ok {
-				return errE
-			}
-			switch s.Code() {
-			case codes.NotFound:
-				return echo.NewHTTPError(http.StatusNotFound, s.Message())
-			case codes.PermissionDenied:
-				return echo.NewHTTPError(http.StatusForbidden, s.Message())
-			default:
-				return fmt.Errorf(s.Message())
-			}
-		}
+	if err := m.echoCanGetCheckpointArtifacts(c, args.CheckpointUUID); err != nil {
+		return err
 	}
 	c.Response().Header().Set(echo.HeaderContentType, mimeType)
-	return m.getCheckpointImpl(c.Request().Context(), id, mimeType, c.Response())
+	return m.getCheckpointImpl(
+		c.Request().Context(), id, mimeType, c.Request().Header.Get("Range"), c.Response())
 }
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	promclient "github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/storage"
+)
+
+// checkpointStorageProbeInterval is how often the checkpoint storage is probed for Prometheus.
+// Each probe writes and deletes an object, so it is not done as often as the health check.
+const checkpointStorageProbeInterval = 5 * time.Minute
+
+// promCheckpointStorageHealth periodically probes the checkpoint storage, so that misconfigured
+// storage or credentials show up in Prometheus before the first checkpoint fails to upload.
+func (m *Master) promCheckpointStorageHealth(ctx context.Context) {
+	labels := []string{"name", "type"}
+	healthy := promclient.NewGaugeVec(promclient.GaugeOpts{
+		Name: "determined_checkpoint_storage_healthy",
+		Help: "Whether a probe object could be written to, read from and deleted from " +
+			"checkpoint storage (1 for healthy, 0 for unhealthy)",
+	}, labels)
+	duration := promclient.NewGaugeVec(promclient.GaugeOpts{
+		Name: "determined_checkpoint_storage_probe_duration_seconds",
+		Help: "How long the last probe of checkpoint storage took",
+	}, labels)
+	promclient.MustRegister(healthy, duration)
+
+	update := func() {
+		results, err := storage.ProbeConfigured(ctx, m.config.CheckpointStorage)
+		if err != nil {
+			log.WithError(err).Warn("failed to probe checkpoint storage")
+			return
+		}
+		// Workspaces may have stopped overriding the storage since the last probe.
+		healthy.Reset()
+		duration.Reset()
+		for _, r := range results {
+			if r.Status == storage.ProbeSkipped {
+				continue
+			}
+			if r.Status == storage.ProbeOK {
+				healthy.WithLabelValues(r.Name, r.Type).Set(1)
+			} else {
+				healthy.WithLabelValues(r.Name, r.Type).Set(0)
+				log.Warnf("checkpoint storage of %s is unhealthy: %s", r.Name, r.Error)
+			}
+			duration.WithLabelValues(r.Name, r.Type).Set(r.DurationSeconds)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(checkpointStorageProbeInterval)
+		defer ticker.Stop()
+
+		update()
+		for {
+			select {
+			case <-ticker.C:
+				update()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
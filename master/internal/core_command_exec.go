@@ -0,0 +1,237 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/command"
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/rbac/audit"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const (
+	// execPingInterval is how often exec sessions are pinged, so proxies don't close them while a
+	// process is quiet.
+	execPingInterval = 30 * time.Second
+	execWriteTimeout = 10 * time.Second
+	// defaultExecCmd is the process started when an exec session doesn't name one.
+	defaultExecCmd = "/bin/sh"
+)
+
+// execControl is a control message of an exec session, sent as a text frame. Binary frames carry
+// the input and output of the process. Clients send resizes and the end of their input; the
+// master sends the exit code of the process or the error that ended the session, then closes it.
+type execControl struct {
+	Resize     *execSize `json:"resize,omitempty"`
+	CloseInput bool      `json:"close_input,omitempty"`
+	ExitCode   *int      `json:"exit_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type execSize struct {
+	Rows uint `json:"rows"`
+	Cols uint `json:"cols"`
+}
+
+// parseExecRequest parses the process an exec session starts from its query parameters.
+func parseExecRequest(q url.Values) (sproto.ExecRequest, error) {
+	req := sproto.ExecRequest{Cmd: q["cmd"], TTY: true}
+	if len(req.Cmd) == 0 {
+		req.Cmd = []string{defaultExecCmd}
+	}
+	if tty := q.Get("tty"); tty != "" {
+		b, err := strconv.ParseBool(tty)
+		if err != nil {
+			return req, fmt.Errorf("invalid tty %q: %w", tty, err)
+		}
+		req.TTY = b
+	}
+	for name, size := range map[string]*uint{"rows": &req.Rows, "cols": &req.Cols} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.ParseUint(v, 10, 16)
+			if err != nil {
+				return req, fmt.Errorf("invalid %s %q: %w", name, v, err)
+			}
+			*size = uint(n)
+		}
+	}
+	return req, nil
+}
+
+// parseExecInput parses a message from the client of an exec session.
+func parseExecInput(messageType int, data []byte) (sproto.ExecInput, error) {
+	switch messageType {
+	case websocket.BinaryMessage:
+		return sproto.ExecInput{Data: data}, nil
+	case websocket.TextMessage:
+		var msg execControl
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return sproto.ExecInput{}, fmt.Errorf("invalid control message: %w", err)
+		}
+		switch {
+		case msg.Resize != nil:
+			return sproto.ExecInput{
+				Resize: &sproto.ExecSize{Rows: msg.Resize.Rows, Cols: msg.Resize.Cols},
+			}, nil
+		case msg.CloseInput:
+			return sproto.ExecInput{CloseInput: true}, nil
+		}
+		return sproto.ExecInput{}, errors.New("control message must resize or close input")
+	default:
+		return sproto.ExecInput{}, fmt.Errorf("unexpected message type %d", messageType)
+	}
+}
+
+//	@Summary	Start a process in the container of a running command and attach to it over a WebSocket.
+//	@Description	Binary frames carry the input and output of the process. Text frames carry JSON
+//	@Description	control messages: clients may send {"resize": {"rows": 24, "cols": 80}} or
+//	@Description	{"close_input": true}, and the master ends the session with {"exit_code": 0} or
+//	@Description	{"error": "..."}.
+//	@Tags		Commands
+//	@ID			get-command-exec
+//	@Param		command_id	path	string		true	"Command ID"
+//	@Param		cmd			query	[]string	false	"The process to start, /bin/sh by default"
+//	@Param		tty			query	bool		false	"Whether to allocate a TTY, true by default"
+//	@Param		rows		query	int			false	"The initial rows of the TTY"
+//	@Param		cols		query	int			false	"The initial columns of the TTY"
+//	@Success	101	{}	string	""
+//	@Router		/commands/{command_id}/exec [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getCommandExec(socket *websocket.Conn, c echo.Context) error {
+	defer func() {
+		if err := socket.Close(); err != nil {
+			log.WithError(err).Debug("closing exec session")
+		}
+	}()
+	commandID := c.Param("command_id")
+	curUser := c.(*detContext.DetContext).MustGetUser()
+
+	req, err := parseExecRequest(c.QueryParams())
+	if err != nil {
+		return writeExecControl(socket, execControl{Error: err.Error()})
+	}
+	a, ctx := m.echoAPIServer(c)
+	cmd, err := a.GetCommand(ctx, &apiv1.GetCommandRequest{CommandId: commandID})
+	if err != nil {
+		return writeExecControl(socket, execControl{Error: err.Error()})
+	}
+	ctx = audit.SupplyEntityID(ctx, commandID)
+	if err = command.AuthZProvider.Get().CanExecInNSC(
+		ctx, curUser, model.AccessScopeID(cmd.Command.WorkspaceId),
+		model.UserID(cmd.Command.UserId),
+	); err != nil {
+		return writeExecControl(socket, execControl{Error: err.Error()})
+	}
+
+	session, err := command.DefaultCmdService.ExecCommand(ctx, commandID, curUser, req)
+	if err != nil {
+		return writeExecControl(socket, execControl{Error: err.Error()})
+	}
+	defer session.Close()
+
+	auditLog := log.WithFields(log.Fields{
+		"type":            "exec_audit_log",
+		"remote_ip":       c.RealIP(),
+		"determined_user": curUser.Username,
+		"command_id":      commandID,
+		"cmd":             req.Cmd,
+		"tty":             req.TTY,
+	})
+	auditLog.Info("exec session started")
+	var end execControl
+	defer func() {
+		auditLog.WithFields(log.Fields{
+			"exit_code": end.ExitCode,
+			"error":     end.Error,
+		}).Info("exec session ended")
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Only this goroutine reads from the socket and only the handler writes to it.
+	inputErrs := make(chan error, 1)
+	go func() {
+		defer cancel()
+		for {
+			messageType, data, err := socket.ReadMessage()
+			if err != nil {
+				return
+			}
+			in, err := parseExecInput(messageType, data)
+			if err == nil {
+				err = session.Send(in)
+			}
+			if err != nil {
+				inputErrs <- err
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(execPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			select {
+			case err := <-inputErrs:
+				end.Error = err.Error()
+				return writeExecControl(socket, end)
+			default:
+				end.Error = "client disconnected"
+				return nil
+			}
+		case <-ping.C:
+			if err := socket.WriteControl(
+				websocket.PingMessage, nil, time.Now().Add(execWriteTimeout),
+			); err != nil {
+				return err
+			}
+		case out, ok := <-session.Output:
+			switch {
+			case !ok:
+				end.Error = "exec session ended before the process exited"
+				return writeExecControl(socket, end)
+			case out.Err != nil:
+				end.Error = out.Err.Error()
+				return writeExecControl(socket, end)
+			case out.ExitCode != nil:
+				end.ExitCode = out.ExitCode
+				return writeExecControl(socket, end)
+			}
+			if err := socket.SetWriteDeadline(time.Now().Add(execWriteTimeout)); err != nil {
+				return err
+			}
+			if err := socket.WriteMessage(websocket.BinaryMessage, out.Data); err != nil {
+				return err
+			}
+			ping.Reset(execPingInterval)
+		}
+	}
+}
+
+// writeExecControl writes the last control message of an exec session and closes it.
+func writeExecControl(socket *websocket.Conn, msg execControl) error {
+	deadline := time.Now().Add(execWriteTimeout)
+	if err := socket.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	if err := socket.WriteJSON(msg); err != nil {
+		return err
+	}
+	return socket.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), deadline)
+}
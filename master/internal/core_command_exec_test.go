@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+)
+
+func TestParseExecRequest(t *testing.T) {
+	req, err := parseExecRequest(url.Values{})
+	require.NoError(t, err)
+	require.Equal(t, sproto.ExecRequest{Cmd: []string{defaultExecCmd}, TTY: true}, req)
+
+	req, err = parseExecRequest(url.Values{
+		"cmd":  {"python", "-c", "print(1)"},
+		"tty":  {"false"},
+		"rows": {"24"},
+		"cols": {"80"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, sproto.ExecRequest{
+		Cmd: []string{"python", "-c", "print(1)"}, Rows: 24, Cols: 80,
+	}, req)
+
+	for _, bad := range []url.Values{
+		{"tty": {"maybe"}},
+		{"rows": {"-1"}},
+		{"cols": {"100000"}},
+	} {
+		_, err := parseExecRequest(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestParseExecInput(t *testing.T) {
+	in, err := parseExecInput(websocket.BinaryMessage, []byte("ls\n"))
+	require.NoError(t, err)
+	require.Equal(t, sproto.ExecInput{Data: []byte("ls\n")}, in)
+
+	in, err = parseExecInput(websocket.TextMessage, []byte(`{"resize": {"rows": 24, "cols": 80}}`))
+	require.NoError(t, err)
+	require.Equal(t, sproto.ExecInput{Resize: &sproto.ExecSize{Rows: 24, Cols: 80}}, in)
+
+	in, err = parseExecInput(websocket.TextMessage, []byte(`{"close_input": true}`))
+	require.NoError(t, err)
+	require.Equal(t, sproto.ExecInput{CloseInput: true}, in)
+
+	for _, bad := range []string{`{}`, `{"exit_code": 0}`, `ls`} {
+		_, err := parseExecInput(websocket.TextMessage, []byte(bad))
+		require.Error(t, err, bad)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/projectv1"
@@ -16,12 +17,17 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/batchsize"
 	"github.com/determined-ai/determined/master/internal/checkpoints"
 	masterConfig "github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/configpolicy"
 	detContext "github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/expdefaults"
 	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/expmetadata"
+	"github.com/determined-ai/determined/master/internal/expnaming"
+	"github.com/determined-ai/determined/master/internal/logmetrics"
 	"github.com/determined-ai/determined/master/internal/project"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/templates"
@@ -291,12 +297,21 @@ func (m *Master) parseCreateExperiment(ctx context.Context, req *apiv1.CreateExp
 	}
 
 	defaulted := schemas.WithDefaults(config)
-	resources := defaulted.Resources()
 
 	p, err := getCreateExperimentsProject(m, req, owner, defaulted)
 	if err != nil {
 		return nil, nil, config, nil, nil, err
 	}
+
+	// Apply the project's experiment defaults, which take precedence over those of the resource
+	// pool and workspace.
+	projectDefaults, err := expdefaults.Defaults(ctx, int(p.Id))
+	if err != nil {
+		return nil, nil, config, nil, nil, err
+	}
+	expdefaults.Apply(&config, projectDefaults)
+	defaulted = schemas.WithDefaults(config)
+	resources := defaulted.Resources()
 	workspaceModel, err := workspace.WorkspaceByProjectID(ctx, int(p.Id))
 	if err != nil && errors.Cause(err) != sql.ErrNoRows {
 		return nil, nil, config, nil, nil, err
@@ -355,6 +370,18 @@ func (m *Master) parseCreateExperiment(ctx context.Context, req *apiv1.CreateExp
 		config.RawResources.RawPriority = &prio
 	}
 
+	// Name the experiment from the project's naming template if its config doesn't.
+	if config.RawName.RawString == nil {
+		v := expnaming.Values{Project: p.Name, Workspace: workspaceModel.Name, Time: time.Now()}
+		if owner != nil {
+			v.User = owner.Username
+		}
+		config.RawName.RawString, err = expnaming.TemplateName(ctx, int(p.Id), v, !req.ValidateOnly)
+		if err != nil {
+			return nil, nil, config, nil, nil, err
+		}
+	}
+
 	// Lastly, apply any json-schema-defined defaults.
 	config = schemas.WithDefaults(config)
 
@@ -378,6 +405,15 @@ func (m *Master) parseCreateExperiment(ctx context.Context, req *apiv1.CreateExp
 		return nil, nil, config, nil, nil, errors.Wrap(err, "invalid experiment configuration")
 	}
 
+	if err = logmetrics.Validate(config.LogMetrics()); err != nil {
+		return nil, nil, config, nil, nil, errors.Wrap(err, "invalid experiment configuration")
+	}
+
+	// Projects may require experiments to have metadata that follows their schema.
+	if err = expmetadata.ValidateForProject(ctx, int(p.Id), config.Metadata()); err != nil {
+		return nil, nil, config, nil, nil, errors.Wrap(err, "invalid experiment metadata")
+	}
+
 	modelBytes := []byte{}
 	var parentID *int
 	if req.ParentId != 0 {
@@ -414,6 +450,16 @@ func (m *Master) parseCreateExperiment(ctx context.Context, req *apiv1.CreateExp
 	if err != nil {
 		return nil, nil, config, nil, nil, err
 	}
+	dbExp.ModelDefinitionHash, err = model.ModelDefinitionHash(modelBytes)
+	if err != nil {
+		return nil, nil, config, nil, nil, err
+	}
+	if req.GitCommit != "" {
+		dbExp.GitCommit = &req.GitCommit
+	}
+	if req.GitRemote != "" {
+		dbExp.GitRemote = &req.GitRemote
+	}
 
 	if owner != nil {
 		dbExp.OwnerID = &owner.ID
@@ -422,9 +468,49 @@ func (m *Master) parseCreateExperiment(ctx context.Context, req *apiv1.CreateExp
 
 	taskSpec.Project = p.Name
 	taskSpec.Workspace = workspaceModel.Name
+	taskSpec.WorkspaceID = workspaceModel.ID
 	for label := range config.Labels() {
 		taskSpec.Labels = append(taskSpec.Labels, label)
 	}
 
 	return dbExp, modelBytes, config, p, &taskSpec, err
 }
+
+//	@Summary	Get the batch size probes of an experiment and the batch size tuning chose.
+//	@Tags		Experiments
+//	@ID			get-experiment-batch-size-tuning
+//	@Produce	json
+//	@Param		experiment_id	path	int	true	"Experiment ID"
+//	@Success	200				{}		string	""
+//	@Router		/experiments/{experiment_id}/batch-size-tuning [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getExperimentBatchSizeTuning(c echo.Context) (interface{}, error) {
+	args := struct {
+		ExperimentID int `path:"experiment_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	ctx := c.Request().Context()
+	if _, _, err := echoGetExperimentAndCheckCanDoActions(ctx, c, args.ExperimentID); err != nil {
+		return nil, err
+	}
+
+	probes, err := batchsize.ProbesByExperiment(ctx, args.ExperimentID)
+	if err != nil {
+		return nil, err
+	}
+	tuned, err := batchsize.TunedBatchSizeByExperiment(ctx, args.ExperimentID)
+	if err != nil {
+		return nil, err
+	}
+	var batchSize *int
+	if tuned != nil {
+		batchSize = &tuned.BatchSize
+	}
+	return map[string]interface{}{
+		"batch_size": batchSize,
+		"probes":     probes,
+	}, nil
+}
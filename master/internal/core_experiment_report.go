@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/report"
+)
+
+//	@Summary	Download a report of an experiment.
+//	@Tags		Experiments
+//	@ID			get-experiment-report
+//	@Produce	text/html,text/markdown
+//	@Param		experiment_id	path	int	true	"Experiment ID"
+//	@Param		report_id		path	int	true	"Report ID"
+//	@Success	200				{}		string	""
+//	@Router		/experiments/{experiment_id}/reports/{report_id} [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getExperimentReport(c echo.Context) error {
+	args := struct {
+		ExperimentID int `path:"experiment_id"`
+		ReportID     int `path:"report_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+	if _, _, err := echoGetExperimentAndCheckCanDoActions(ctx, c, args.ExperimentID,
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+		return err
+	}
+
+	r, err := report.ReportByID(ctx, args.ExperimentID, args.ReportID)
+	if errors.Is(err, db.ErrNotFound) {
+		return api.NotFoundErrs("report", strconv.Itoa(args.ReportID), false)
+	} else if err != nil {
+		return err
+	}
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf(
+		`attachment; filename="exp%d_report%d.%s"`, r.ExperimentID, r.ID, r.Format.Extension()))
+	return c.Blob(http.StatusOK, r.Format.ContentType(), r.Content)
+}
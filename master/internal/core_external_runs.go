@@ -0,0 +1,250 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/checkpoints"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// defaultExternalRunSearcherMetric is the searcher metric of the experiments external runs are
+// pushed into when neither a config nor a searcher metric is given.
+const defaultExternalRunSearcherMetric = "loss"
+
+// externalRunParams is a push of the results of a run that Determined didn't launch.
+type externalRunParams struct {
+	ProjectID int32 `json:"project_id"`
+	// ExternalExperimentID and ExternalRunID identify the experiment and trial of the run, so that
+	// pushing the same run again updates them rather than creating new ones.
+	ExternalExperimentID string `json:"external_experiment_id"`
+	ExternalRunID        string `json:"external_run_id"`
+	// Config is the experiment config; if it is unset, one is made from Name and SearcherMetric.
+	Config         string         `json:"config"`
+	Name           string         `json:"name"`
+	SearcherMetric string         `json:"searcher_metric"`
+	HParams        map[string]any `json:"hparams"`
+	// State is the trial state, such as ACTIVE or COMPLETED. The trial is left as it is if unset.
+	State       string                  `json:"state"`
+	Metrics     []externalRunMetrics    `json:"metrics"`
+	Checkpoints []externalRunCheckpoint `json:"checkpoints"`
+}
+
+type externalRunMetrics struct {
+	Group          string         `json:"group"`
+	StepsCompleted int32          `json:"steps_completed"`
+	Metrics        map[string]any `json:"metrics"`
+}
+
+type externalRunCheckpoint struct {
+	UUID      string           `json:"uuid"`
+	StorageID *int32           `json:"storage_id"`
+	Resources map[string]int64 `json:"resources"`
+	Metadata  map[string]any   `json:"metadata"`
+}
+
+func (p externalRunParams) experimentConfig() (string, error) {
+	if p.Config != "" {
+		return p.Config, nil
+	}
+	name := p.Name
+	if name == "" {
+		name = p.ExternalExperimentID
+	}
+	metric := p.SearcherMetric
+	if metric == "" {
+		metric = defaultExternalRunSearcherMetric
+	}
+	b, err := json.Marshal(map[string]any{
+		"name": name,
+		"searcher": map[string]any{
+			"name":   "single",
+			"metric": metric,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshaling experiment config: %w", err)
+	}
+	// JSON is valid YAML, which is what experiment configs are parsed as.
+	return string(b), nil
+}
+
+//	@Summary	Push the hyperparameters, metrics and checkpoints of a run Determined didn't launch.
+//	@Tags		Experiments
+//	@ID			post-external-run
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{}	string	""
+//	@Router		/experiments/external-runs [post]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) postExternalRun(c echo.Context) (interface{}, error) {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	var params externalRunParams
+	if err = json.Unmarshal(body, &params); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+	}
+	if params.ExternalExperimentID == "" || params.ExternalRunID == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			"bad request: external_experiment_id and external_run_id must be set")
+	}
+	var state *trialv1.State
+	if params.State != "" {
+		s, ok := trialv1.State_value["STATE_"+strings.ToUpper(params.State)]
+		if !ok {
+			return nil, echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Sprintf("bad request: unknown trial state %q", params.State))
+		}
+		state = ptrs.Ptr(trialv1.State(s))
+	}
+	for _, r := range params.Metrics {
+		if err = model.MetricGroup(r.Group).Validate(); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+		}
+	}
+	config, err := params.experimentConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// The run is pushed through the API the unmanaged experiment clients use, so it is subject to
+	// the same permission checks.
+	a, ctx := m.echoAPIServer(c)
+	ids, err := pushExternalRun(ctx, a, params, config, state)
+	if err != nil {
+		if ok, echoErr := api.GrpcErrToEcho(err); ok {
+			return nil, echoErr
+		}
+		return nil, err
+	}
+	return map[string]interface{}{
+		"experiment_id": ids.experimentID,
+		"trial_id":      ids.trialID,
+		"metrics":       len(params.Metrics),
+		"checkpoints":   len(params.Checkpoints),
+	}, nil
+}
+
+// externalRunIDs are the experiment and trial an external run was pushed into.
+type externalRunIDs struct {
+	experimentID int32
+	trialID      int32
+}
+
+// pushExternalRun records an external run as a trial of an unmanaged experiment, creating them if
+// they don't exist yet, and returns their IDs.
+func pushExternalRun(
+	ctx context.Context, a *apiServer, params externalRunParams, config string,
+	state *trialv1.State,
+) (externalRunIDs, error) {
+	exp, err := a.PutExperiment(ctx, &apiv1.PutExperimentRequest{
+		CreateExperimentRequest: &apiv1.CreateExperimentRequest{
+			Config:    config,
+			ProjectId: params.ProjectID,
+			Unmanaged: ptrs.Ptr(true),
+		},
+		ExternalExperimentId: params.ExternalExperimentID,
+	})
+	if err != nil {
+		return externalRunIDs{}, err
+	}
+	ids := externalRunIDs{experimentID: exp.Experiment.Id}
+
+	hparams, err := structpb.NewStruct(params.HParams)
+	if err != nil {
+		return ids, echo.NewHTTPError(http.StatusBadRequest, "bad request: hparams: "+err.Error())
+	}
+	trial, err := a.PutTrial(ctx, &apiv1.PutTrialRequest{
+		CreateTrialRequest: &apiv1.CreateTrialRequest{
+			ExperimentId: exp.Experiment.Id,
+			Hparams:      hparams,
+			Unmanaged:    true,
+		},
+		ExternalTrialId: params.ExternalRunID,
+	})
+	if err != nil {
+		return ids, err
+	}
+	ids.trialID = trial.Trial.Id
+
+	for _, r := range params.Metrics {
+		metrics, err := structpb.NewStruct(r.Metrics)
+		if err != nil {
+			return ids, echo.NewHTTPError(http.StatusBadRequest, "bad request: metrics: "+err.Error())
+		}
+		// Metrics already pushed with the same values are accepted again, so that a run can be
+		// pushed repeatedly as it progresses.
+		if _, err := a.ReportTrialMetrics(ctx, &apiv1.ReportTrialMetricsRequest{
+			Metrics: &trialv1.TrialMetrics{
+				TrialId:        trial.Trial.Id,
+				StepsCompleted: ptrs.Ptr(r.StepsCompleted),
+				Metrics:        &commonv1.Metrics{AvgMetrics: metrics},
+			},
+			Group: r.Group,
+		}); err != nil {
+			return ids, fmt.Errorf("reporting %s metrics at step %d: %w", r.Group, r.StepsCompleted, err)
+		}
+	}
+
+	for _, ckpt := range params.Checkpoints {
+		id, err := uuid.Parse(ckpt.UUID)
+		if err != nil {
+			return ids, echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Sprintf("bad request: checkpoint uuid %q: %s", ckpt.UUID, err))
+		}
+		existing, err := checkpoints.CheckpointByUUID(ctx, id)
+		if err != nil {
+			return ids, err
+		}
+		if existing != nil {
+			if existing.TaskID == nil || *existing.TaskID != model.TaskID(trial.Trial.TaskId) {
+				return ids, echo.NewHTTPError(http.StatusConflict,
+					fmt.Sprintf("checkpoint %s belongs to another trial", ckpt.UUID))
+			}
+			// The checkpoint was pushed before.
+			continue
+		}
+		metadata, err := structpb.NewStruct(ckpt.Metadata)
+		if err != nil {
+			return ids, echo.NewHTTPError(http.StatusBadRequest,
+				"bad request: checkpoint metadata: "+err.Error())
+		}
+		if _, err := a.ReportCheckpoint(ctx, &apiv1.ReportCheckpointRequest{
+			Checkpoint: &checkpointv1.Checkpoint{
+				TaskId:    trial.Trial.TaskId,
+				Uuid:      ckpt.UUID,
+				Resources: ckpt.Resources,
+				Metadata:  metadata,
+				StorageId: ckpt.StorageID,
+			},
+		}); err != nil {
+			return ids, fmt.Errorf("reporting checkpoint %s: %w", ckpt.UUID, err)
+		}
+	}
+
+	// Patching the trial also records activity on it, which keeps unmanaged trials from timing out.
+	if _, err := a.PatchTrial(ctx, &apiv1.PatchTrialRequest{
+		TrialId: trial.Trial.Id,
+		State:   state,
+	}); err != nil {
+		return ids, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,99 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func testExternalRunParams(projectID int, runID string, ckptUUID uuid.UUID) externalRunParams {
+	return externalRunParams{
+		ProjectID:            int32(projectID),
+		ExternalExperimentID: "external-exp-" + uuid.NewString(),
+		ExternalRunID:        runID,
+		HParams:              map[string]any{"lr": 0.1},
+		Metrics: []externalRunMetrics{{
+			Group:          model.ValidationMetricGroup.ToString(),
+			StepsCompleted: 10,
+			Metrics:        map[string]any{"loss": 0.5},
+		}},
+		Checkpoints: []externalRunCheckpoint{{
+			UUID:      ckptUUID.String(),
+			Resources: map[string]int64{"model.pt": 1},
+		}},
+	}
+}
+
+func TestPushExternalRunIdempotent(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+
+	ckptUUID := uuid.New()
+	params := testExternalRunParams(projectID, "run-"+uuid.NewString(), ckptUUID)
+	config, err := params.experimentConfig()
+	require.NoError(t, err)
+
+	first, err := pushExternalRun(ctx, api, params, config, nil)
+	require.NoError(t, err)
+	second, err := pushExternalRun(ctx, api, params, config, nil)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	count, err := db.Bun().NewSelect().Table("checkpoints_v2").
+		Where("uuid = ?", ckptUUID.String()).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestPushExternalRunConflictingCheckpoint(t *testing.T) {
+	api, _, ctx := setupAPITest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+
+	ckptUUID := uuid.New()
+	params := testExternalRunParams(projectID, "run-"+uuid.NewString(), ckptUUID)
+	config, err := params.experimentConfig()
+	require.NoError(t, err)
+	_, err = pushExternalRun(ctx, api, params, config, nil)
+	require.NoError(t, err)
+
+	// Another run of the same experiment can't claim the checkpoint.
+	params.ExternalRunID = "run-" + uuid.NewString()
+	_, err = pushExternalRun(ctx, api, params, config, nil)
+	var httpErr *echo.HTTPError
+	require.True(t, errors.As(err, &httpErr), err)
+	require.Equal(t, http.StatusConflict, httpErr.Code)
+}
+
+func TestAuthZPushExternalRun(t *testing.T) {
+	api, authZExp, pAuthZ, curUser, ctx := setupExpAuthTest(t, nil)
+	_, projectID := createProjectAndWorkspace(ctx, t, api)
+
+	// user is logged in again during experiment creation, meaning args don't match
+	mockUserArg := mock.MatchedBy(func(u model.User) bool {
+		return u.ID == curUser.ID
+	})
+
+	pAuthZ.On("CanGetProject", mock.Anything, mockUserArg, mock.Anything).Return(nil).Once()
+	authZExp.On("CanCreateExperiment", mock.Anything, mockUserArg, mock.Anything).
+		Return(fmt.Errorf("canCreateExperimentError")).Once()
+
+	params := testExternalRunParams(projectID, "run-"+uuid.NewString(), uuid.New())
+	config, err := params.experimentConfig()
+	require.NoError(t, err)
+	_, err = pushExternalRun(ctx, api, params, config, nil)
+	require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+}
@@ -0,0 +1,294 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// The endpoints in this file are the API of the harness-free container contract: trial containers
+// that don't run the Determined harness call them with the DET_MASTER, DET_USER_TOKEN,
+// DET_TASK_ID, DET_ALLOCATION_ID and DET_RESOURCES_ID environment variables they are started with,
+// using plain JSON rather than the protobuf forms of the gRPC API.
+
+//	@Summary	Wait for the containers of a trial's allocation to start and get their addresses.
+//	@Tags		Tasks
+//	@ID			get-task-rendezvous
+//	@Produce	json
+//	@Param		task_id			path	string	true	"Task ID"
+//	@Param		allocation_id	query	string	true	"Allocation ID"
+//	@Param		resources_id	query	string	true	"Resources ID of the calling container"
+//	@Param		advertise		query	string	false	"Address for peers to reach the container at"
+//	@Success	200				{}		string	""
+//	@Router		/tasks/{task_id}/rendezvous [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTaskRendezvous(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID       string  `path:"task_id"`
+		AllocationID string  `query:"allocation_id"`
+		ResourcesID  string  `query:"resources_id"`
+		Advertise    *string `query:"advertise"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	if model.AllocationID(args.AllocationID).ToTaskID() != model.TaskID(args.TaskID) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("allocation %s does not belong to task %s", args.AllocationID, args.TaskID))
+	}
+
+	a, ctx := m.echoAPIServer(c)
+	if args.Advertise != nil {
+		// Containers whose host address their peers can't reach, such as behind NAT, have the
+		// master relay the address they can be reached at instead.
+		if err := a.canEditAllocation(ctx, args.AllocationID); err != nil {
+			if ok, echoErr := api.GrpcErrToEcho(err); ok {
+				return nil, echoErr
+			}
+			return nil, err
+		}
+		err := task.DefaultService.AdvertiseRendezvousAddress(ctx,
+			model.AllocationID(args.AllocationID), sproto.ResourcesID(args.ResourcesID), *args.Advertise)
+		if errors.Is(err, api.ErrInvalid) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	resp, err := a.AllocationRendezvousInfo(ctx, &apiv1.AllocationRendezvousInfoRequest{
+		AllocationId: args.AllocationID,
+		ResourcesId:  args.ResourcesID,
+	})
+	if err != nil {
+		if ok, echoErr := api.GrpcErrToEcho(err); ok {
+			return nil, echoErr
+		}
+		return nil, err
+	}
+	info := resp.RendezvousInfo
+	return map[string]interface{}{
+		"addresses": info.Addresses,
+		"rank":      info.Rank,
+		"slots":     info.Slots,
+	}, nil
+}
+
+//	@Summary	Get the address the master sees a container's requests come from.
+//	@Tags		Tasks
+//	@ID			get-task-observed-address
+//	@Produce	json
+//	@Param		task_id			path	string	true	"Task ID"
+//	@Param		allocation_id	query	string	true	"Allocation ID"
+//	@Success	200				{}		string	""
+//	@Router		/tasks/{task_id}/rendezvous/observed-address [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTaskObservedAddress(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID       string `path:"task_id"`
+		AllocationID string `query:"allocation_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	if model.AllocationID(args.AllocationID).ToTaskID() != model.TaskID(args.TaskID) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("allocation %s does not belong to task %s", args.AllocationID, args.TaskID))
+	}
+	a, ctx := m.echoAPIServer(c)
+	if err := a.canEditAllocation(ctx, args.AllocationID); err != nil {
+		if ok, echoErr := api.GrpcErrToEcho(err); ok {
+			return nil, echoErr
+		}
+		return nil, err
+	}
+	// Like a STUN server, this lets a container behind NAT learn the address it is reached at
+	// from outside, to advertise to its peers when it can't find it out itself.
+	return map[string]interface{}{"address": c.RealIP()}, nil
+}
+
+//	@Summary	Report the ranks a container could not connect to after the rendezvous.
+//	@Tags		Tasks
+//	@ID			post-task-rendezvous-reachability
+//	@Accept		json
+//	@Param		task_id	path	string	true	"Task ID"
+//	@Success	200	{}	string	""
+//	@Router		/tasks/{task_id}/rendezvous/reachability [post]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) postTaskRendezvousReachability(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID string `path:"task_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	var params struct {
+		AllocationID     model.AllocationID `json:"allocation_id"`
+		ResourcesID      sproto.ResourcesID `json:"resources_id"`
+		UnreachableRanks []int              `json:"unreachable_ranks"`
+	}
+	if err = json.Unmarshal(body, &params); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+	}
+	if params.AllocationID.ToTaskID() != model.TaskID(args.TaskID) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("allocation %s does not belong to task %s", params.AllocationID, args.TaskID))
+	}
+
+	a, ctx := m.echoAPIServer(c)
+	if err = a.canEditAllocation(ctx, params.AllocationID.String()); err != nil {
+		if ok, echoErr := api.GrpcErrToEcho(err); ok {
+			return nil, echoErr
+		}
+		return nil, err
+	}
+	err = task.DefaultService.ReportRendezvousReachability(ctx, params.AllocationID,
+		params.ResourcesID, params.UnreachableRanks)
+	if errors.Is(err, api.ErrInvalid) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return nil, err
+}
+
+//	@Summary	Report metrics of a trial from a container that doesn't run the harness.
+//	@Tags		Tasks
+//	@ID			post-task-metrics
+//	@Accept		json
+//	@Param		task_id	path	string	true	"Task ID"
+//	@Success	200	{}	string	""
+//	@Router		/tasks/{task_id}/metrics [post]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) postTaskMetrics(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID string `path:"task_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	var params struct {
+		Group          string         `json:"group"`
+		StepsCompleted int32          `json:"steps_completed"`
+		Metrics        map[string]any `json:"metrics"`
+	}
+	if err = json.Unmarshal(body, &params); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+	}
+	metrics, err := structpb.NewStruct(params.Metrics)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: metrics: "+err.Error())
+	}
+
+	ctx := c.Request().Context()
+	taskID := model.TaskID(args.TaskID)
+	if _, err = echoGetTrialTaskExperiment(ctx, c, taskID,
+		expauth.AuthZProvider.Get().CanEditExperiment,
+	); err != nil {
+		return nil, err
+	}
+	tr, err := db.TrialByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	a, ctx := m.echoAPIServer(c)
+	if _, err = a.ReportTrialMetrics(ctx, &apiv1.ReportTrialMetricsRequest{
+		Metrics: &trialv1.TrialMetrics{
+			TrialId:        int32(tr.ID),
+			TrialRunId:     int32(tr.RunID),
+			StepsCompleted: ptrs.Ptr(params.StepsCompleted),
+			Metrics:        &commonv1.Metrics{AvgMetrics: metrics},
+		},
+		Group: params.Group,
+	}); err != nil {
+		if ok, echoErr := api.GrpcErrToEcho(err); ok {
+			return nil, echoErr
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+//	@Summary	Report a checkpoint of a trial from a container that doesn't run the harness.
+//	@Tags		Tasks
+//	@ID			post-task-checkpoint
+//	@Accept		json
+//	@Param		task_id	path	string	true	"Task ID"
+//	@Success	200	{}	string	""
+//	@Router		/tasks/{task_id}/checkpoints [post]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) postTaskCheckpoint(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID string `path:"task_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	var params struct {
+		AllocationID model.AllocationID `json:"allocation_id"`
+		UUID         string             `json:"uuid"`
+		Resources    map[string]int64   `json:"resources"`
+		Metadata     map[string]any     `json:"metadata"`
+	}
+	if err = json.Unmarshal(body, &params); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+	}
+	if params.AllocationID.ToTaskID() != model.TaskID(args.TaskID) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("allocation %s does not belong to task %s", params.AllocationID, args.TaskID))
+	}
+	metadata, err := structpb.NewStruct(params.Metadata)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: metadata: "+err.Error())
+	}
+
+	a, ctx := m.echoAPIServer(c)
+	if _, err = a.ReportCheckpoint(ctx, &apiv1.ReportCheckpointRequest{
+		Checkpoint: &checkpointv1.Checkpoint{
+			TaskId:       args.TaskID,
+			AllocationId: ptrs.Ptr(params.AllocationID.String()),
+			Uuid:         params.UUID,
+			Resources:    params.Resources,
+			Metadata:     metadata,
+			State:        checkpointv1.State_STATE_COMPLETED,
+		},
+	}); err != nil {
+		if ok, echoErr := api.GrpcErrToEcho(err); ok {
+			return nil, echoErr
+		}
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/httppolicy"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+// httpPolicyOverride is the policy set through the API. It replaces the one in the master config,
+// across restarts, until it is deleted.
+type httpPolicyOverride struct {
+	bun.BaseModel `bun:"table:http_policy"`
+
+	ID        bool                    `bun:"id,pk"`
+	Policy    config.HTTPPolicyConfig `bun:"policy,type:jsonb"`
+	UpdatedBy *int                    `bun:"updated_by"`
+	UpdatedAt time.Time               `bun:"updated_at"`
+}
+
+// loadHTTPPolicy applies the policy set through the API, if there is one, instead of the one in
+// the master config.
+func (m *Master) loadHTTPPolicy(ctx context.Context) error {
+	var o httpPolicyOverride
+	err := db.Bun().NewSelect().Model(&o).Scan(ctx)
+	switch {
+	case errors.Is(db.MatchSentinelError(err), db.ErrNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("loading HTTP policy: %w", err)
+	}
+	if errs := o.Policy.Validate(); len(errs) > 0 {
+		log.WithError(errors.Join(errs...)).
+			Warn("ignoring invalid HTTP policy set through the API, using the master config's")
+		return nil
+	}
+	httppolicy.Set(o.Policy)
+	return nil
+}
+
+// saveHTTPPolicy saves a policy to apply instead of the one in the master config, including after
+// restarts, and applies it.
+func saveHTTPPolicy(ctx context.Context, p config.HTTPPolicyConfig, userID model.UserID) error {
+	o := httpPolicyOverride{
+		ID:        true,
+		Policy:    p,
+		UpdatedBy: ptrs.Ptr(int(userID)),
+		UpdatedAt: time.Now().UTC(),
+	}
+	if _, err := db.Bun().NewInsert().Model(&o).
+		On("CONFLICT (id) DO UPDATE").
+		Set("policy = EXCLUDED.policy").
+		Set("updated_by = EXCLUDED.updated_by").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("saving HTTP policy: %w", err)
+	}
+	httppolicy.Set(p)
+	return nil
+}
+
+// deleteHTTPPolicy deletes the policy set through the API and applies the master config's again.
+func (m *Master) deleteHTTPPolicy(ctx context.Context) error {
+	if _, err := db.Bun().NewDelete().Model((*httpPolicyOverride)(nil)).
+		Where("id").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("deleting HTTP policy: %w", err)
+	}
+	httppolicy.Set(m.config.HTTPPolicy)
+	return nil
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/job/jobstream"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+const (
+	// jobQueuePingInterval is how often idle job queue streams are pinged, so proxies don't close
+	// them while the queue isn't changing.
+	jobQueuePingInterval = 30 * time.Second
+	jobQueueWriteTimeout = 10 * time.Second
+)
+
+// Types of job queue stream messages.
+const (
+	jobQueueMessageSnapshot = "snapshot"
+	jobQueueMessageUpdate   = "update"
+	jobQueueMessageError    = "error"
+)
+
+// jobQueueMessage is a message of a job queue stream. The first message is a snapshot of every
+// job in the queue; later ones hold only the jobs that changed, and the IDs of the jobs that left.
+type jobQueueMessage struct {
+	Type         string            `json:"type"`
+	ResourcePool string            `json:"resource_pool"`
+	Jobs         []json.RawMessage `json:"jobs,omitempty"`
+	Removed      []string          `json:"removed,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+//	@Summary	Stream the changes to the job queue of a resource pool over a WebSocket.
+//	@Tags		Jobs
+//	@ID			get-job-queue-stream
+//	@Param		resource_pool	path	string	true	"Resource pool name"
+//	@Success	101	{}	string	""
+//	@Router		/job-queues/{resource_pool}/stream [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getJobQueueStream(socket *websocket.Conn, c echo.Context) error {
+	defer func() {
+		if err := socket.Close(); err != nil {
+			log.WithError(err).Debug("closing job queue stream")
+		}
+	}()
+	curUser := c.(*detContext.DetContext).MustGetUser()
+	pool := c.Param("resource_pool")
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+	// Clients don't send anything, but reading handles pings and notices when they go away.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := socket.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	sub := m.jobQueues.Subscribe(pool)
+	defer sub.Close()
+	ping := time.NewTicker(jobQueuePingInterval)
+	defer ping.Stop()
+
+	var sent map[string]*jobv1.RBACJob
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ping.C:
+			if err := socket.WriteControl(
+				websocket.PingMessage, nil, time.Now().Add(jobQueueWriteTimeout),
+			); err != nil {
+				return err
+			}
+		case q := <-sub.Updates():
+			msg := jobQueueMessage{ResourcePool: pool}
+			if q.Err != nil {
+				msg.Type, msg.Error = jobQueueMessageError, q.Err.Error()
+				return writeJobQueueMessage(socket, msg)
+			}
+			// Permissions are applied to each change, so jobs the user can't view never reach them.
+			jobs, err := jobstream.RBACJobs(ctx, curUser, q.Jobs)
+			if err != nil {
+				msg.Type, msg.Error = jobQueueMessageError, err.Error()
+				return writeJobQueueMessage(socket, msg)
+			}
+			msg.Type = jobQueueMessageUpdate
+			if sent == nil {
+				msg.Type, sent = jobQueueMessageSnapshot, map[string]*jobv1.RBACJob{}
+			}
+			changed, removed := jobstream.Diff(sent, jobs)
+			if msg.Type == jobQueueMessageUpdate && len(changed) == 0 && len(removed) == 0 {
+				continue
+			}
+			msg.Removed = removed
+			for _, j := range changed {
+				b, err := protojson.Marshal(j)
+				if err != nil {
+					return err
+				}
+				msg.Jobs = append(msg.Jobs, b)
+			}
+			if err := writeJobQueueMessage(socket, msg); err != nil {
+				return err
+			}
+			ping.Reset(jobQueuePingInterval)
+		}
+	}
+}
+
+func writeJobQueueMessage(socket *websocket.Conn, msg jobQueueMessage) error {
+	if err := socket.SetWriteDeadline(time.Now().Add(jobQueueWriteTimeout)); err != nil {
+		return err
+	}
+	return socket.WriteJSON(msg)
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/metricmetadata"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+//	@Summary	Get the metrics of an experiment and how to display them.
+//	@Description	Metadata declared in the experiment config takes precedence over metadata
+//	@Description	declared by the harness. The direction of the searcher metric follows the searcher.
+//	@Tags		Experiments
+//	@ID			get-experiment-metric-metadata
+//	@Produce	json
+//	@Param		experiment_id	path	int	true	"Experiment ID"
+//	@Success	200				{}		string	""
+//	@Router		/experiments/{experiment_id}/metric-metadata [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getExperimentMetricMetadata(c echo.Context) (interface{}, error) {
+	args := struct {
+		ExperimentID int `path:"experiment_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	ctx := c.Request().Context()
+	exp, _, err := echoGetExperimentAndCheckCanDoActions(ctx, c, args.ExperimentID,
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := m.db.ActiveExperimentConfig(exp.ID)
+	if err != nil {
+		return nil, err
+	}
+	reported, err := m.db.MetricNames(ctx, []int{exp.ID})
+	if err != nil {
+		return nil, err
+	}
+	declared, err := metricmetadata.Declared(ctx, exp.ID)
+	if err != nil {
+		return nil, err
+	}
+	return metricmetadata.Resolve(metricmetadata.FromConfig(config), declared, reported), nil
+}
+
+//	@Summary	Declare how to display metrics that a trial reports.
+//	@Description	The body maps metric names to their unit, direction, display_name and scale. The
+//	@Description	metadata applies to the whole experiment, and fields left out keep their values.
+//	@Tags		Trials
+//	@ID			put-trial-metric-metadata
+//	@Accept		json
+//	@Param		trial_id	path	int	true	"Trial ID"
+//	@Success	200	{}	string	""
+//	@Router		/trials/{trial_id}/metric-metadata [put]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) putTrialMetricMetadata(c echo.Context) (interface{}, error) {
+	args := struct {
+		TrialID int `path:"trial_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	var params map[string]model.MetricMetadata
+	if err = json.Unmarshal(body, &params); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+	}
+	declared := make([]model.MetricMetadata, 0, len(params))
+	for name, metadata := range params {
+		metadata.Name = name
+		declared = append(declared, metadata)
+	}
+	if err = metricmetadata.Validate(declared); errors.Is(err, db.ErrInvalidInput) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	eID, _, err := m.db.TrialExperimentAndRequestID(args.TrialID)
+	if err != nil {
+		return nil, api.NotFoundErrs("trial", strconv.Itoa(args.TrialID), false)
+	}
+	if _, _, err = echoGetExperimentAndCheckCanDoActions(ctx, c, eID,
+		expauth.AuthZProvider.Get().CanEditExperiment); err != nil {
+		return nil, err
+	}
+	return nil, metricmetadata.Declare(ctx, eID, declared)
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/metrichistory"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+//	@Summary	Export the full metric history of a trial as ndjson or Parquet.
+//	@Tags		Trials
+//	@ID			get-trial-metrics-export
+//	@Produce	application/x-ndjson,application/vnd.apache.parquet
+//	@Param		trial_id	path	int		true	"Trial ID"
+//	@Param		format		query	string	false	"ndjson (default) or parquet"
+//	@Param		group		query	string	false	"Metric group, all groups by default"
+//	@Success	200	{}	string	""
+//	@Router		/trials/{trial_id}/metrics/export [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTrialMetricsExport(c echo.Context) error {
+	args := struct {
+		TrialID int     `path:"trial_id"`
+		Format  *string `query:"format"`
+		Group   *string `query:"group"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	filter := metrichistory.Filter{TrialID: ptrs.Ptr(args.TrialID)}
+	format, err := bindMetricsExport(&filter, args.Format, args.Group)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if _, err := m.echoCheckCanGetTrialArtifacts(ctx, c, args.TrialID); err != nil {
+		return err
+	}
+	return writeMetricsExport(c, fmt.Sprintf("trial%d_metrics", args.TrialID), format, filter)
+}
+
+//	@Summary	Export the full metric history of every trial of an experiment as ndjson or Parquet.
+//	@Tags		Experiments
+//	@ID			get-experiment-metrics-export
+//	@Produce	application/x-ndjson,application/vnd.apache.parquet
+//	@Param		experiment_id	path	int		true	"Experiment ID"
+//	@Param		format			query	string	false	"ndjson (default) or parquet"
+//	@Param		group			query	string	false	"Metric group, all groups by default"
+//	@Success	200	{}	string	""
+//	@Router		/experiments/{experiment_id}/metrics/export [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getExperimentMetricsExport(c echo.Context) error {
+	args := struct {
+		ExperimentID int     `path:"experiment_id"`
+		Format       *string `query:"format"`
+		Group        *string `query:"group"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	filter := metrichistory.Filter{ExperimentID: ptrs.Ptr(args.ExperimentID)}
+	format, err := bindMetricsExport(&filter, args.Format, args.Group)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := echoGetExperimentAndCheckCanDoActions(
+		c.Request().Context(), c, args.ExperimentID,
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts,
+	); err != nil {
+		return err
+	}
+	return writeMetricsExport(c, fmt.Sprintf("exp%d_metrics", args.ExperimentID), format, filter)
+}
+
+// bindMetricsExport parses the format and metric group of an export request.
+func bindMetricsExport(
+	filter *metrichistory.Filter, format, group *string,
+) (metrichistory.Format, error) {
+	f := metrichistory.FormatNDJSON
+	if format != nil {
+		var err error
+		if f, err = metrichistory.ParseFormat(*format); err != nil {
+			return "", echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+	if group != nil {
+		if err := model.MetricGroup(*group).Validate(); err != nil {
+			return "", echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		filter.Group = group
+	}
+	return f, nil
+}
+
+// writeMetricsExport streams the metrics matching the filter as an attachment named after name.
+func writeMetricsExport(
+	c echo.Context, name string, format metrichistory.Format, filter metrichistory.Filter,
+) error {
+	c.Response().Header().Set("Content-Type", format.ContentType())
+	c.Response().Header().Set(
+		"Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	w, err := metrichistory.NewWriter(c.Response(), format)
+	if err != nil {
+		return err
+	}
+	return metrichistory.Export(c.Request().Context(), filter, w)
+}
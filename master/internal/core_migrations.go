@@ -0,0 +1,21 @@
+package internal
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// getDebugMigrations reports the migrations that are pending, with what they would lock, and the
+// progress of the migrations this master has run, including contract migrations that run while
+// it serves in online mode.
+func (m *Master) getDebugMigrations(c echo.Context) (interface{}, error) {
+	plan, err := m.db.PlanMigrations(c.Request().Context(), m.config.DB.Migrations)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"plan":     plan,
+		"progress": db.MigrationStatus(),
+	}, nil
+}
@@ -0,0 +1,203 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/mlflowimport"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+const (
+	// defaultMLflowImportRuns is how many runs an MLflow import reads by default.
+	defaultMLflowImportRuns = 1000
+	// maxMLflowImportRuns bounds the runs an MLflow import reads.
+	maxMLflowImportRuns = 10000
+)
+
+// mlflowImportRequest is what an import creates its experiment with.
+type mlflowImportRequest struct {
+	req            *apiv1.CreateExperimentRequest
+	user           model.User
+	name           string
+	description    string
+	searcherMetric string
+	maxRuns        int
+}
+
+// runMLflowImport reads the runs of an MLflow experiment and, once it has them all, writes them
+// to a new archived experiment. If writing fails part way, the experiment is deleted, so an import
+// either creates a complete experiment or none.
+func (m *Master) runMLflowImport(
+	importID int, client *mlflowimport.Client, mlflowExp *mlflowimport.Experiment, r mlflowImportRequest,
+) {
+	ctx := context.Background()
+	syslog := log.WithField("mlflow-import-id", importID)
+
+	trials, err := m.importMLflowExperiment(ctx, importID, client, mlflowExp, r)
+	if err != nil {
+		syslog.WithError(err).Warn("MLflow import failed")
+	} else {
+		syslog.Infof("imported %d MLflow runs", trials)
+	}
+	if err := mlflowimport.FinishImport(ctx, importID, trials, err); err != nil {
+		syslog.WithError(err).Error("recording the end of MLflow import")
+	}
+}
+
+func (m *Master) importMLflowExperiment(
+	ctx context.Context, importID int, client *mlflowimport.Client,
+	mlflowExp *mlflowimport.Experiment, r mlflowImportRequest,
+) (int, error) {
+	runs, err := client.Runs(ctx, mlflowExp.ExperimentID, r.maxRuns)
+	if err != nil {
+		return 0, err
+	}
+	histories := make([][]mlflowimport.Metric, len(runs))
+	for i, run := range runs {
+		for _, latest := range run.Data.Metrics {
+			h, err := client.MetricHistory(ctx, run.Info.RunID, latest.Key)
+			if err != nil {
+				return 0, err
+			}
+			histories[i] = append(histories[i], h...)
+		}
+	}
+
+	searcherMetric := r.searcherMetric
+	if searcherMetric == "" {
+		searcherMetric = mlflowimport.SearcherMetric(runs)
+	}
+	r.req.Config, err = mlflowimport.ExperimentConfig(r.name, r.description, searcherMetric)
+	if err != nil {
+		return 0, err
+	}
+	dbExp, modelDef, activeConfig, _, _, err := m.parseCreateExperiment(ctx, r.req, &r.user)
+	if err != nil {
+		return 0, fmt.Errorf("creating experiment for MLflow import: %w", err)
+	}
+	e, _, err := newUnmanagedExperiment(ctx, db.Bun(), dbExp, modelDef, activeConfig)
+	if err != nil {
+		return 0, fmt.Errorf("creating experiment for MLflow import: %w", err)
+	}
+	if err := mlflowimport.SetImportExperiment(ctx, importID, e.ID); err != nil {
+		return 0, err
+	}
+
+	err = m.importMLflowRuns(ctx, e.ID, runs, histories)
+	if err == nil {
+		// Imported experiments are archived so they stay out of the way of live experiments, but
+		// can still be found and compared with them.
+		_, err = db.Bun().NewUpdate().Table("experiments").
+			Set("state = ?", model.CompletedState).
+			Set("end_time = ?", time.Now().UTC()).
+			Set("archived = true").
+			Where("id = ?", e.ID).
+			Exec(ctx)
+	}
+	if err != nil {
+		if cleanupErr := m.deleteMLflowImportExperiment(ctx, e.ID); cleanupErr != nil {
+			log.WithError(cleanupErr).Errorf("deleting experiment %d of failed MLflow import", e.ID)
+		}
+		return 0, fmt.Errorf("writing MLflow runs to experiment %d: %w", e.ID, err)
+	}
+	return len(runs), nil
+}
+
+// importMLflowRuns adds a trial with the parameters and metric history of each run to an
+// experiment.
+func (m *Master) importMLflowRuns(
+	ctx context.Context, experimentID int, runs []mlflowimport.Run, histories [][]mlflowimport.Metric,
+) error {
+	for i, r := range runs {
+		start, end := mlflowimport.Times(r)
+		// HACK: needed for ``experimentIDFromTrialTaskID``, as in createTrialTx.
+		taskID := model.TaskID(fmt.Sprintf("%d.%s", experimentID, model.NewTaskID()))
+		if err := db.AddTask(ctx, &model.Task{
+			TaskID:     taskID,
+			TaskType:   model.TaskTypeTrial,
+			StartTime:  start,
+			EndTime:    &end,
+			LogVersion: model.CurrentTaskLogVersion,
+		}); err != nil {
+			return err
+		}
+
+		tr := model.NewTrial(
+			mlflowimport.TrialState(r.Info.Status),
+			model.RequestID{},
+			experimentID,
+			mlflowimport.HParams(r.Data.Params),
+			nil,
+			0,
+			m.taskSpec.LogRetentionDays)
+		tr.StartTime = start
+		tr.EndTime = &end
+		tr.ExternalTrialID = ptrs.Ptr("mlflow-" + r.Info.RunID)
+		if err := db.AddTrial(ctx, tr, taskID); err != nil {
+			return fmt.Errorf("adding trial for MLflow run %s: %w", r.Info.RunID, err)
+		}
+
+		for _, report := range mlflowimport.Reports(histories[i]) {
+			metrics, err := structpb.NewStruct(report.Metrics)
+			if err != nil {
+				return fmt.Errorf("converting metrics of MLflow run %s: %w", r.Info.RunID, err)
+			}
+			if err := m.db.AddTrialMetrics(ctx, &trialv1.TrialMetrics{
+				TrialId:        int32(tr.ID),
+				StepsCompleted: ptrs.Ptr(int32(report.Step)),
+				Metrics:        &commonv1.Metrics{AvgMetrics: metrics},
+			}, report.Group); err != nil {
+				return fmt.Errorf("adding metrics of MLflow run %s: %w", r.Info.RunID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteMLflowImportExperiment deletes the experiment of a failed import, with its trials,
+// metrics and tasks.
+func (m *Master) deleteMLflowImportExperiment(ctx context.Context, experimentID int) error {
+	if err := m.db.DeleteExperiments(ctx, []int{experimentID}); err != nil {
+		return err
+	}
+	// Trial task IDs start with the experiment ID; see importMLflowRuns. This also catches a task
+	// whose trial failed to be added.
+	if _, err := db.Bun().NewDelete().Table("tasks").
+		Where("task_id LIKE ?", fmt.Sprintf("%d.%%", experimentID)).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("deleting tasks of experiment %d: %w", experimentID, err)
+	}
+	return nil
+}
+
+// failInterruptedMLflowImports fails the imports that were running when the master stopped and
+// deletes the experiments they were writing.
+func (m *Master) failInterruptedMLflowImports(ctx context.Context) error {
+	imports, err := mlflowimport.InterruptedImports(ctx)
+	if err != nil {
+		return err
+	}
+	for _, imp := range imports {
+		if imp.ExperimentID != nil {
+			if err := m.deleteMLflowImportExperiment(ctx, *imp.ExperimentID); err != nil {
+				return err
+			}
+		}
+		if err := mlflowimport.FinishImport(ctx, imp.ID, 0,
+			errors.New("the master restarted during the import")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8sV1 "k8s.io/api/core/v1"
+
+	"github.com/determined-ai/determined/master/internal/configpolicy"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/modeleval"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+// modelEvaluationScheduleInterval is how often scheduled model evaluations are checked for.
+const modelEvaluationScheduleInterval = time.Minute
+
+// launchModelEvaluation runs a policy's evaluation experiment against a model version and records
+// the evaluation. The experiment is created as runAs, or as the policy's owner if it is nil. An
+// evaluation whose experiment couldn't be created is recorded as failed.
+func (m *Master) launchModelEvaluation(
+	ctx context.Context, p *model.ModelEvaluationPolicy, v *modeleval.Version,
+	trigger model.ModelEvaluationTrigger, runAs *model.User,
+) (*model.ModelEvaluation, error) {
+	eval := &model.ModelEvaluation{
+		PolicyID:       p.ID,
+		ModelVersionID: v.ID,
+		Trigger:        trigger,
+		State:          model.ModelEvaluationLaunching,
+	}
+	if err := modeleval.AddEvaluation(ctx, eval); err != nil {
+		return nil, err
+	}
+	return eval, m.startModelEvaluation(ctx, p, v, eval, runAs)
+}
+
+// startModelEvaluation creates the experiment of an evaluation that is launching and records that
+// it is running, or that it failed and why.
+func (m *Master) startModelEvaluation(
+	ctx context.Context, p *model.ModelEvaluationPolicy, v *modeleval.Version,
+	eval *model.ModelEvaluation, runAs *model.User,
+) error {
+	e, err := m.createModelEvaluationExperiment(ctx, p, v, runAs)
+	if err == nil {
+		eval.ExperimentID = &e.ID
+		eval.State = model.ModelEvaluationRunning
+		if err = modeleval.UpdateEvaluation(ctx, eval); err != nil {
+			return err
+		}
+		// The experiment is only activated once the evaluation is recorded, so that the
+		// evaluation can't miss it ending.
+		if err = e.ActivateExperiment(); err == nil {
+			return nil
+		}
+		err = errors.Wrapf(err, "failed to activate evaluation experiment %d", e.ID)
+	}
+
+	msg := status.Convert(err).Message()
+	now := time.Now().UTC()
+	eval.State = model.ModelEvaluationFailed
+	eval.Error = &msg
+	eval.EndedAt = &now
+	if updateErr := modeleval.UpdateEvaluation(ctx, eval); updateErr != nil {
+		log.WithError(updateErr).Errorf("failed to record that evaluation %d failed", eval.ID)
+	}
+	return err
+}
+
+// createModelEvaluationExperiment creates and starts, but doesn't activate, a policy's evaluation
+// experiment for a model version, as runAs or, if it is nil, the policy's owner.
+func (m *Master) createModelEvaluationExperiment(
+	ctx context.Context, p *model.ModelEvaluationPolicy, v *modeleval.Version, runAs *model.User,
+) (*internalExperiment, error) {
+	var owner model.User
+	if runAs != nil {
+		owner = *runAs
+	} else {
+		fullOwner, err := user.ByID(ctx, p.OwnerID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting owner of evaluation policy %s", p.Name)
+		}
+		owner = fullOwner.ToUser()
+		if !owner.Active {
+			return nil, fmt.Errorf("owner %s of evaluation policy %s is deactivated",
+				owner.Username, p.Name)
+		}
+	}
+
+	dbExp, activeConfig, _, taskSpec, err := m.parseModelEvaluationExperiment(ctx, p.Config,
+		int32(p.ProjectID), owner, false)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCanSubmit(ctx); err != nil {
+		return nil, err
+	}
+
+	modelDef := p.ModelDefinition
+	if dbExp.ModelDefinitionHash, err = model.ModelDefinitionHash(modelDef); err != nil {
+		return nil, err
+	}
+	if taskSpec.ExtraEnvVars == nil {
+		taskSpec.ExtraEnvVars = map[string]string{}
+	}
+	maps.Copy(taskSpec.ExtraEnvVars, v.EnvVars())
+
+	e, _, err := newExperiment(m, dbExp, modelDef, activeConfig, taskSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create evaluation experiment")
+	}
+	if err = e.Start(); err != nil {
+		return nil, errors.Wrapf(err, "failed to start evaluation experiment %d", e.ID)
+	}
+	recordExperimentCreated(ctx, owner.ID, *e.Experiment, activeConfig)
+	return e, nil
+}
+
+// parseModelEvaluationExperiment resolves the config of an evaluation experiment as it would be to
+// create the experiment as owner, and runs the checks CreateExperiment runs on it, so that a policy
+// can't create an experiment that its owner or its workspace doesn't allow.
+func (m *Master) parseModelEvaluationExperiment(
+	ctx context.Context, config string, projectID int32, owner model.User, validateOnly bool,
+) (*model.Experiment, expconf.ExperimentConfig, *projectv1.Project, *tasks.TaskSpec, error) {
+	dbExp, _, activeConfig, project, taskSpec, err := m.parseCreateExperiment(ctx,
+		&apiv1.CreateExperimentRequest{
+			Config:       config,
+			ProjectId:    projectID,
+			ValidateOnly: validateOnly,
+		}, &owner)
+	if err != nil {
+		return nil, expconf.ExperimentConfig{}, nil, nil, err
+	}
+	if err = expauth.AuthZProvider.Get().CanCreateExperiment(ctx, owner, project); err != nil {
+		return nil, expconf.ExperimentConfig{}, nil, nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	err = configpolicy.CheckExperimentConstraints(ctx, int(project.WorkspaceId), activeConfig, m.rm)
+	if err != nil {
+		return nil, expconf.ExperimentConfig{}, nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	a := &apiServer{m: m}
+	err = a.checkCanOptOutOfPodSecurity(ctx, owner, project.WorkspaceId,
+		taskSpec.TaskContainerDefaults, (*k8sV1.Pod)(activeConfig.Environment().PodSpec()))
+	if err != nil {
+		return nil, expconf.ExperimentConfig{}, nil, nil, err
+	}
+	return dbExp, activeConfig, project, taskSpec, nil
+}
+
+// queuedModelEvaluation is an evaluation of a new model version that is recorded, but whose
+// experiment hasn't been created yet.
+type queuedModelEvaluation struct {
+	policy *model.ModelEvaluationPolicy
+	eval   *model.ModelEvaluation
+}
+
+// queueNewModelVersionEvaluations records the evaluations of a version that was just registered
+// by the policies of its model that evaluate each new version. Their experiments are created by
+// launchQueuedModelEvaluations.
+func (m *Master) queueNewModelVersionEvaluations(
+	ctx context.Context, modelID int, version int,
+) (*modeleval.Version, []queuedModelEvaluation, error) {
+	policies, err := modeleval.RegisterPolicies(ctx, modelID)
+	if err != nil || len(policies) == 0 {
+		return nil, nil, err
+	}
+	v, err := modeleval.VersionByNumber(ctx, modelID, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	queued := make([]queuedModelEvaluation, 0, len(policies))
+	for i := range policies {
+		eval := &model.ModelEvaluation{
+			PolicyID:       policies[i].ID,
+			ModelVersionID: v.ID,
+			Trigger:        model.ModelEvaluationTriggerRegister,
+			State:          model.ModelEvaluationLaunching,
+		}
+		if err := modeleval.AddEvaluation(ctx, eval); err != nil {
+			return nil, nil, err
+		}
+		queued = append(queued, queuedModelEvaluation{policy: &policies[i], eval: eval})
+	}
+	return v, queued, nil
+}
+
+// launchQueuedModelEvaluations creates the experiments of queued evaluations of a model version.
+// Evaluations whose experiments couldn't be created are recorded as failed.
+func (m *Master) launchQueuedModelEvaluations(
+	ctx context.Context, v *modeleval.Version, queued []queuedModelEvaluation,
+) {
+	for _, q := range queued {
+		if err := m.startModelEvaluation(ctx, q.policy, v, q.eval, nil); err != nil {
+			log.WithError(err).Warnf("failed to evaluate version %d of model %s with %s",
+				v.Version, v.ModelName, q.policy.Name)
+		}
+	}
+}
+
+// runModelEvaluationSchedules runs the evaluations that are due on their schedule until the
+// context is canceled.
+func (m *Master) runModelEvaluationSchedules(ctx context.Context) {
+	ticker := time.NewTicker(modelEvaluationScheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.runDueModelEvaluations(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Master) runDueModelEvaluations(ctx context.Context) {
+	now := time.Now().UTC()
+	policies, err := modeleval.DuePolicies(ctx, now)
+	if err != nil {
+		log.WithError(err).Error("failed to get scheduled evaluation policies")
+		return
+	}
+	for i := range policies {
+		p := &policies[i]
+		// A policy is marked first, so that one that fails isn't retried every tick.
+		if err := modeleval.MarkScheduled(ctx, p.ID, now); err != nil {
+			log.WithError(err).Error("failed to mark evaluation policy scheduled")
+			continue
+		}
+		v, err := modeleval.LatestVersion(ctx, p.ModelID, p.ScheduleLabel)
+		if err != nil {
+			log.WithError(err).Errorf("failed to get latest version of model %d", p.ModelID)
+			continue
+		} else if v == nil {
+			log.Debugf("model %d has no version for evaluation policy %s to evaluate",
+				p.ModelID, p.Name)
+			continue
+		}
+		if _, err := m.launchModelEvaluation(ctx, p, v, model.ModelEvaluationTriggerSchedule, nil); err != nil {
+			log.WithError(err).Warnf("failed to evaluate version %d of model %s with %s",
+				v.Version, v.ModelName, p.Name)
+		}
+	}
+}
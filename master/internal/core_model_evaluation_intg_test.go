@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/configpolicy"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestParseModelEvaluationExperimentChecksConstraints(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	workspaceID, projectID := createProjectAndWorkspace(ctx, t, api)
+
+	err := configpolicy.SetTaskConfigPolicies(ctx, &model.TaskConfigPolicies{
+		WorkspaceID:     &workspaceID,
+		WorkloadType:    model.ExperimentType,
+		LastUpdatedBy:   curUser.ID,
+		LastUpdatedTime: time.Now(),
+		Constraints:     ptrs.Ptr(`{"bind_mounts": {"denied_host_paths": ["/etc"]}}`),
+	})
+	require.NoError(t, err)
+
+	config := func(hostPath string) string {
+		c := minExpConfig
+		c.RawBindMounts = expconf.BindMountsConfigV0{
+			{RawHostPath: hostPath, RawContainerPath: "/mnt"},
+		}
+		b, err := yaml.Marshal(c)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	_, _, _, _, err = api.m.parseModelEvaluationExperiment(ctx, config("/etc"), int32(projectID),
+		curUser, true)
+	require.Equal(t, codes.InvalidArgument, status.Code(err), err)
+
+	_, _, project, _, err := api.m.parseModelEvaluationExperiment(ctx, config("/tmp"),
+		int32(projectID), curUser, true)
+	require.NoError(t, err)
+	require.Equal(t, int32(projectID), project.Id)
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/reservation"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// syncResourcePoolReservations sends the current and upcoming reservations of a pool to its
+// resource manager.
+func (m *Master) syncResourcePoolReservations(ctx context.Context, pool string) error {
+	reservations, err := reservation.Reservations(ctx, pool, time.Now())
+	if err != nil {
+		return err
+	}
+	return m.rm.SetReservations(rm.ResourcePoolName(pool), reservations)
+}
+
+// restoreResourcePoolReservations sends the current and upcoming reservations of every pool to
+// the resource managers when the master starts.
+func (m *Master) restoreResourcePoolReservations(ctx context.Context) error {
+	reservations, err := reservation.Reservations(ctx, "", time.Now())
+	if err != nil {
+		return err
+	}
+	byPool := make(map[string][]model.ResourcePoolReservation)
+	for _, r := range reservations {
+		byPool[r.ResourcePool] = append(byPool[r.ResourcePool], r)
+	}
+	for pool, rs := range byPool {
+		if err := m.rm.SetReservations(rm.ResourcePoolName(pool), rs); err != nil {
+			log.WithError(err).Warnf("restoring the reservations of resource pool %s", pool)
+		}
+	}
+	return nil
+}
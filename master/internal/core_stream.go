@@ -0,0 +1,351 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/sse"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const (
+	// timestampTokenPrefix marks resume tokens of log backends, like Elasticsearch, whose logs
+	// don't have numeric IDs to resume from.
+	timestampTokenPrefix = "ts:"
+	// metricsStreamPollInterval is how often a followed metrics stream checks for new metrics.
+	metricsStreamPollInterval = time.Second
+	metricsStreamBatchSize    = 1000
+)
+
+//	@Summary	Stream the logs of a task as server-sent events.
+//	@Tags		Tasks
+//	@ID			get-task-logs-stream
+//	@Produce	text/event-stream
+//	@Param		task_id			path	string	true	"Task ID"
+//	@Param		follow			query	bool	false	"Keep streaming new logs until the task ends"
+//	@Param		resume_token	query	string	false	"ID of the last event received"
+//	@Success	200	{}	string	""
+//	@Router		/tasks/{task_id}/logs/stream [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTaskLogsStream(c echo.Context) error {
+	args := struct {
+		TaskID string `path:"task_id"`
+		Follow *bool  `query:"follow"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	resume, err := parseLogResumeToken(sse.ResumeToken(c.Request()))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	a, ctx := m.echoAPIServer(c)
+	if _, _, err := a.canDoActionsOnTask(ctx, model.TaskID(args.TaskID),
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+		_, err = api.GrpcErrToEcho(err)
+		return err
+	}
+
+	w, err := sse.NewWriter(c.Response())
+	if err != nil {
+		return err
+	}
+	follow := args.Follow == nil || *args.Follow
+	return endStream(w, a.streamTaskLogs(ctx, w, model.TaskID(args.TaskID), follow, resume))
+}
+
+//	@Summary	Stream the logs of a trial as server-sent events.
+//	@Tags		Trials
+//	@ID			get-trial-logs-stream
+//	@Produce	text/event-stream
+//	@Param		trial_id		path	int		true	"Trial ID"
+//	@Param		follow			query	bool	false	"Keep streaming new logs until the trial ends"
+//	@Param		resume_token	query	string	false	"ID of the last event received"
+//	@Success	200	{}	string	""
+//	@Router		/trials/{trial_id}/logs/stream [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTrialLogsStream(c echo.Context) error {
+	args := struct {
+		TrialID int   `path:"trial_id"`
+		Follow  *bool `query:"follow"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	resume, err := parseLogResumeToken(sse.ResumeToken(c.Request()))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	a, ctx := m.echoAPIServer(c)
+	if _, err := m.echoCheckCanGetTrialArtifacts(ctx, c, args.TrialID); err != nil {
+		return err
+	}
+	trialTaskIDs, err := db.TrialTaskIDsByTrialID(ctx, args.TrialID)
+	if err != nil {
+		return fmt.Errorf("retrieving task IDs for trial logs: %w", err)
+	}
+
+	w, err := sse.NewWriter(c.Response())
+	if err != nil {
+		return err
+	}
+	follow := args.Follow == nil || *args.Follow
+	for i, t := range trialTaskIDs {
+		// Only the latest task of a trial can still be running. Logs from before task logs
+		// replaced trial logs are only available from the trial logs API.
+		if err := a.streamTaskLogs(ctx, w, t.TaskID, follow && i == len(trialTaskIDs)-1,
+			resume); err != nil {
+			return endStream(w, err)
+		}
+	}
+	return endStream(w, nil)
+}
+
+//	@Summary	Stream the metrics of a trial as server-sent events.
+//	@Tags		Trials
+//	@ID			get-trial-metrics-stream
+//	@Produce	text/event-stream
+//	@Param		trial_id		path	int		true	"Trial ID"
+//	@Param		group			query	string	false	"Metric group, training by default"
+//	@Param		follow			query	bool	false	"Keep streaming new metrics until the trial ends"
+//	@Param		resume_token	query	string	false	"ID of the last event received"
+//	@Success	200	{}	string	""
+//	@Router		/trials/{trial_id}/metrics/stream [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTrialMetricsStream(c echo.Context) error {
+	args := struct {
+		TrialID int     `path:"trial_id"`
+		Group   *string `query:"group"`
+		Follow  *bool   `query:"follow"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+	group := model.TrainingMetricGroup
+	if args.Group != nil {
+		group = model.MetricGroup(*args.Group)
+	}
+	if err := group.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	// Metrics are resumed after the steps completed of the last report received.
+	afterBatches := -1
+	if token := sse.ResumeToken(c.Request()); token != "" {
+		n, err := strconv.Atoi(token)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid resume token: "+token)
+		}
+		afterBatches = n
+	}
+
+	ctx := c.Request().Context()
+	if _, err := m.echoCheckCanGetTrialArtifacts(ctx, c, args.TrialID); err != nil {
+		return err
+	}
+
+	w, err := sse.NewWriter(c.Response())
+	if err != nil {
+		return err
+	}
+	follow := args.Follow == nil || *args.Follow
+	return endStream(w, m.streamTrialMetrics(ctx, w, args.TrialID, group, follow, afterBatches))
+}
+
+func (m *Master) streamTrialMetrics(
+	ctx context.Context, w *sse.Writer, trialID int, group model.MetricGroup, follow bool,
+	afterBatches int,
+) error {
+	heartbeat := time.NewTicker(sse.HeartbeatInterval)
+	defer heartbeat.Stop()
+	poll := time.NewTicker(metricsStreamPollInterval)
+	defer poll.Stop()
+
+	groupStr := group.ToString()
+	for {
+		// Check whether the trial has ended before fetching, so metrics reported just before it
+		// ended are still sent.
+		ended := true
+		if follow {
+			t, err := db.TrialByID(ctx, trialID)
+			if err != nil {
+				return err
+			}
+			ended = model.TerminalStates[t.State]
+		}
+
+		for {
+			reports, err := db.GetMetrics(ctx, trialID, afterBatches, metricsStreamBatchSize, &groupStr)
+			if err != nil {
+				return err
+			}
+			for _, r := range reports {
+				b, err := protojson.Marshal(r)
+				if err != nil {
+					return err
+				}
+				if err := w.Event(strconv.Itoa(int(r.TotalBatches)), "metrics", b); err != nil {
+					return err
+				}
+				afterBatches = int(r.TotalBatches)
+				heartbeat.Reset(sse.HeartbeatInterval)
+			}
+			if len(reports) < metricsStreamBatchSize {
+				break
+			}
+		}
+		if ended {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-heartbeat.C:
+			if err := w.Heartbeat(); err != nil {
+				return err
+			}
+		case <-poll.C:
+		}
+	}
+}
+
+// streamTaskLogs writes the logs of a task after the resume filter, if any, to w as events,
+// sending heartbeats while waiting for new logs.
+func (a *apiServer) streamTaskLogs(
+	ctx context.Context, w *sse.Writer, taskID model.TaskID, follow bool, resume *api.Filter,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var extraFilters []api.Filter
+	if resume != nil {
+		extraFilters = append(extraFilters, *resume)
+	}
+	res := make(chan api.BatchResult, taskLogsChanBuffer)
+	go a.taskLogs(ctx, &apiv1.TaskLogsRequest{
+		TaskId: string(taskID),
+		Follow: follow,
+	}, res, extraFilters...)
+
+	heartbeat := time.NewTicker(sse.HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-heartbeat.C:
+			if err := w.Heartbeat(); err != nil {
+				return err
+			}
+		case r, ok := <-res:
+			if !ok {
+				return nil
+			}
+			if r.Err() != nil {
+				return r.Err()
+			}
+			if err := r.Batch().ForEach(func(i interface{}) error {
+				l := i.(*model.TaskLog)
+				pl, err := l.Proto()
+				if err != nil {
+					return err
+				}
+				b, err := protojson.Marshal(pl)
+				if err != nil {
+					return err
+				}
+				return w.Event(logResumeToken(l), "log", b)
+			}); err != nil {
+				return err
+			}
+			heartbeat.Reset(sse.HeartbeatInterval)
+		}
+	}
+}
+
+func (m *Master) echoCheckCanGetTrialArtifacts(
+	ctx context.Context, c echo.Context, trialID int,
+) (*model.Experiment, error) {
+	t, err := db.TrialByID(ctx, trialID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("trial", strconv.Itoa(trialID), false)
+	} else if err != nil {
+		return nil, err
+	}
+	exp, _, err := echoGetExperimentAndCheckCanDoActions(ctx, c, t.ExperimentID,
+		expauth.AuthZProvider.Get().CanGetExperimentArtifacts)
+	return exp, err
+}
+
+// logResumeToken returns the ID of a log's event: the log's ID where the log backend has numeric
+// IDs, and its timestamp otherwise.
+func logResumeToken(l *model.TaskLog) string {
+	switch {
+	case l.ID != nil:
+		return strconv.Itoa(*l.ID)
+	case l.Timestamp != nil:
+		return timestampTokenPrefix + l.Timestamp.UTC().Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
+
+// parseLogResumeToken returns the filter that resumes a log stream after the event with the given
+// ID, or nil if there is no token.
+func parseLogResumeToken(token string) (*api.Filter, error) {
+	if token == "" {
+		return nil, nil
+	}
+	if ts, ok := strings.CutPrefix(token, timestampTokenPrefix); ok {
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resume token %q: %w", token, err)
+		}
+		return &api.Filter{
+			Field:     "timestamp",
+			Operation: api.FilterOperationGreaterThan,
+			Values:    t,
+		}, nil
+	}
+	id, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resume token %q", token)
+	}
+	return &api.Filter{
+		Field:     "id",
+		Operation: api.FilterOperationGreaterThan,
+		Values:    []int64{id},
+	}, nil
+}
+
+// endStream finishes an event stream, reporting err to the client, since the response status has
+// already been sent.
+func endStream(w *sse.Writer, err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The client went away.
+		return nil
+	case err != nil:
+		if wErr := w.Error(err); wErr != nil {
+			return errors.Join(err, wErr)
+		}
+		return nil
+	default:
+		return w.End()
+	}
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestLogResumeToken(t *testing.T) {
+	ts := time.Date(2024, 11, 12, 1, 2, 3, 4, time.UTC)
+
+	require.Equal(t, "42", logResumeToken(&model.TaskLog{ID: ptrs.Ptr(42), Timestamp: &ts}))
+	token := logResumeToken(&model.TaskLog{StringID: ptrs.Ptr("abc"), Timestamp: &ts})
+	require.Equal(t, "ts:2024-11-12T01:02:03.000000004Z", token)
+
+	f, err := parseLogResumeToken(token)
+	require.NoError(t, err)
+	require.Equal(t, &api.Filter{
+		Field:     "timestamp",
+		Operation: api.FilterOperationGreaterThan,
+		Values:    ts,
+	}, f)
+
+	f, err = parseLogResumeToken("42")
+	require.NoError(t, err)
+	require.Equal(t, []int64{42}, f.Values)
+
+	f, err = parseLogResumeToken("")
+	require.NoError(t, err)
+	require.Nil(t, f)
+
+	_, err = parseLogResumeToken("ts:yesterday")
+	require.Error(t, err)
+	_, err = parseLogResumeToken("abc")
+	require.Error(t, err)
+}
@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/determined-ai/determined/master/internal/cluster"
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// supportBundleLogLines is how many of the most recent master log lines a support bundle holds.
+	supportBundleLogLines = 10000
+	// supportBundleMigrations is how many of the most recently applied migrations are listed.
+	supportBundleMigrations = 20
+)
+
+// supportBundleFile is a file of a support bundle and how to generate it.
+type supportBundleFile struct {
+	name    string
+	content func(ctx context.Context) ([]byte, error)
+}
+
+// schedulerSnapshot is the state of the resource manager when a support bundle is generated.
+type schedulerSnapshot struct {
+	ResourcePools json.RawMessage                                 `json:"resource_pools"`
+	Agents        json.RawMessage                                 `json:"agents"`
+	JobQueues     map[string]map[model.JobID]*sproto.RMJobInfo    `json:"job_queues"`
+	Allocations   map[model.AllocationID]sproto.AllocationSummary `json:"allocations"`
+	Errors        []string                                        `json:"errors,omitempty"`
+}
+
+func marshalSupportBundleJSON(v any) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
+
+func (m *Master) supportBundleFiles() []supportBundleFile {
+	return []supportBundleFile{
+		{name: "info.json", content: func(context.Context) ([]byte, error) {
+			return marshalSupportBundleJSON(m.Info())
+		}},
+		{name: "master-config.json", content: func(context.Context) ([]byte, error) {
+			// Printable redacts passwords, keys and other secrets.
+			return m.config.Printable()
+		}},
+		{name: "master.log", content: func(context.Context) ([]byte, error) {
+			var b strings.Builder
+			total := m.logs.Len()
+			for _, e := range m.logs.Entries(max(total-supportBundleLogLines, 0), -1, -1) {
+				fmt.Fprintf(&b, "%s [%s] %s\n", e.Time.Format(time.RFC3339Nano), e.Level, e.Message)
+			}
+			return []byte(b.String()), nil
+		}},
+		{name: "scheduler.json", content: func(context.Context) ([]byte, error) {
+			return marshalSupportBundleJSON(m.schedulerSnapshot())
+		}},
+		{name: "migrations.json", content: func(ctx context.Context) ([]byte, error) {
+			versions, err := db.MigrationVersions(ctx, supportBundleMigrations)
+			if err != nil {
+				return nil, err
+			}
+			return marshalSupportBundleJSON(versions)
+		}},
+		{name: "health.json", content: func(ctx context.Context) ([]byte, error) {
+			// The health check covers the connectivity of the database and resource managers.
+			return marshalSupportBundleJSON(m.healthCheck(ctx))
+		}},
+	}
+}
+
+// schedulerSnapshot gathers what it can of the state of the resource manager, noting what it
+// couldn't, since a support bundle is most needed when something is wrong.
+func (m *Master) schedulerSnapshot() schedulerSnapshot {
+	snapshot := schedulerSnapshot{
+		JobQueues: map[string]map[model.JobID]*sproto.RMJobInfo{},
+	}
+
+	pools, err := m.rm.GetResourcePools()
+	if err != nil {
+		snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("getting resource pools: %s", err))
+	} else {
+		snapshot.ResourcePools, _ = protojson.Marshal(pools)
+		for _, pool := range pools.ResourcePools {
+			jobQ, err := m.rm.GetJobQ(rm.ResourcePoolName(pool.Name))
+			if err != nil {
+				snapshot.Errors = append(snapshot.Errors,
+					fmt.Sprintf("getting job queue of resource pool %s: %s", pool.Name, err))
+				continue
+			}
+			snapshot.JobQueues[pool.Name] = jobQ
+		}
+	}
+
+	agents, err := m.rm.GetAgents()
+	if err != nil {
+		snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("getting agents: %s", err))
+	} else {
+		snapshot.Agents, _ = protojson.Marshal(agents)
+	}
+
+	if snapshot.Allocations, err = m.rm.GetAllocationSummaries(); err != nil {
+		snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("getting allocations: %s", err))
+	}
+	return snapshot
+}
+
+//	@Summary	Download a support bundle of the master's state, with secrets redacted.
+//	@Tags		Cluster
+//	@ID			get-support-bundle
+//	@Produce	application/gzip
+//	@Success	200	{}	string	""
+//	@Router		/support-bundle [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getSupportBundle(c echo.Context) error {
+	ctx := c.Request().Context()
+	curUser := c.(*detContext.DetContext).MustGetUser()
+	for _, can := range []func(context.Context, *model.User) (error, error){
+		cluster.AuthZProvider.Get().CanGetMasterConfig,
+		cluster.AuthZProvider.Get().CanGetMasterLogs,
+	} {
+		permErr, err := can(ctx, &curUser)
+		if err != nil {
+			return err
+		}
+		if permErr != nil {
+			return echo.NewHTTPError(http.StatusForbidden, permErr.Error())
+		}
+	}
+
+	name := fmt.Sprintf("determined-support-bundle-%s", time.Now().UTC().Format("20060102T150405Z"))
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationGZip)
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="%s.tar.gz"`, name))
+	c.Response().WriteHeader(http.StatusOK)
+
+	aw, err := archive.NewArchiveWriter(c.Response(), archive.ArchiveTgz)
+	if err != nil {
+		return err
+	}
+	// Once the archive is being streamed, failures can't be reported with the status code, so the
+	// files that couldn't be generated are listed in the bundle instead.
+	var failures []string
+	writeFile := func(path string, content []byte) error {
+		if err := aw.WriteHeader(name+"/"+path, int64(len(content))); err != nil {
+			return err
+		}
+		_, err := aw.Write(content)
+		return err
+	}
+	for _, f := range m.supportBundleFiles() {
+		content, err := f.content(ctx)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", f.name, err))
+			continue
+		}
+		if err := writeFile(f.name, content); err != nil {
+			return fmt.Errorf("writing %s to support bundle: %w", f.name, err)
+		}
+	}
+	if len(failures) > 0 {
+		if err := writeFile("errors.txt", []byte(strings.Join(failures, "\n")+"\n")); err != nil {
+			return fmt.Errorf("writing errors to support bundle: %w", err)
+		}
+	}
+	return aw.Close()
+}
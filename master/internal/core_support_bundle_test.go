@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/mocks"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
+)
+
+func TestSchedulerSnapshot(t *testing.T) {
+	mockRM := &mocks.ResourceManager{}
+	mockRM.On("GetResourcePools").Return(&apiv1.GetResourcePoolsResponse{
+		ResourcePools: []*resourcepoolv1.ResourcePool{{Name: "default"}, {Name: "gpu"}},
+	}, nil)
+	mockRM.On("GetJobQ", rm.ResourcePoolName("default")).Return(
+		map[model.JobID]*sproto.RMJobInfo{"job": {State: sproto.SchedulingStateQueued}}, nil)
+	mockRM.On("GetJobQ", rm.ResourcePoolName("gpu")).Return(
+		map[model.JobID]*sproto.RMJobInfo(nil), errors.New("pool unreachable"))
+	mockRM.On("GetAgents").Return(nil, errors.New("agents unreachable"))
+	mockRM.On("GetAllocationSummaries").Return(
+		map[model.AllocationID]sproto.AllocationSummary{}, nil)
+
+	m := &Master{rm: mockRM}
+	snapshot := m.schedulerSnapshot()
+
+	// What couldn't be gathered is noted, rather than failing the whole snapshot.
+	require.Equal(t, []string{
+		"getting job queue of resource pool gpu: pool unreachable",
+		"getting agents: agents unreachable",
+	}, snapshot.Errors)
+	require.Contains(t, snapshot.JobQueues, "default")
+	require.NotContains(t, snapshot.JobQueues, "gpu")
+	require.Nil(t, snapshot.Agents)
+
+	b, err := marshalSupportBundleJSON(snapshot)
+	require.NoError(t, err)
+	require.True(t, json.Valid(b))
+}
@@ -1,20 +1,40 @@
 package internal
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
 	"github.com/labstack/echo/v4"
 
+	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
-	"github.com/determined-ai/determined/master/internal/context"
+	detContext "github.com/determined-ai/determined/master/internal/context"
+	"github.com/determined-ai/determined/master/internal/db"
 	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/internal/task/liveness"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
 )
 
+// getDebugAllocations dumps the internal state of every allocation in the allocation service.
+func (m *Master) getDebugAllocations(c echo.Context) (interface{}, error) {
+	return task.DefaultService.DebugState(), nil
+}
+
 func (m *Master) getTasks(c echo.Context) (interface{}, error) {
 	summary, err := m.rm.GetAllocationSummaries()
 	if err != nil {
 		return nil, err
 	}
 
-	curUser := c.(*context.DetContext).MustGetUser()
+	curUser := c.(*detContext.DetContext).MustGetUser()
 	ctx := c.Request().Context()
 	for allocationID, allocationSummary := range summary {
 		isExp, exp, err := expFromTaskID(ctx, allocationSummary.TaskID)
@@ -35,3 +55,264 @@ func (m *Master) getTasks(c echo.Context) (interface{}, error) {
 	}
 	return summary, nil
 }
+
+func echoGetTrialTaskExperiment(
+	ctx context.Context, c echo.Context, taskID model.TaskID,
+	actions ...func(context.Context, model.User, *model.Experiment) error,
+) (*model.Experiment, error) {
+	isExp, exp, err := expFromTaskID(ctx, taskID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, api.NotFoundErrs("task", string(taskID), false)
+	} else if err != nil {
+		return nil, err
+	}
+	if !isExp {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("task %s is not a trial", taskID))
+	}
+	exp, _, err = echoGetExperimentAndCheckCanDoActions(ctx, c, exp.ID, actions...)
+	return exp, err
+}
+
+const (
+	defaultDiagnosticsLogLines = 100
+	maxDiagnosticsLogLines     = 10000
+)
+
+// allocationDiagnosticsBundle is the diagnostics bundle of a failed allocation along with the last
+// lines each rank logged before it failed.
+type allocationDiagnosticsBundle struct {
+	model.AllocationDiagnostics
+	Logs []rankLogs `json:"logs"`
+}
+
+type rankLogs struct {
+	Rank  int      `json:"rank"`
+	Lines []string `json:"lines"`
+}
+
+//	@Summary	Download the diagnostics bundles collected when a trial's allocations failed.
+//	@Tags		Tasks
+//	@ID			get-task-diagnostics
+//	@Produce	json
+//	@Param		task_id		path	string	true	"Task ID"
+//	@Param		log_lines	query	integer	false	"Number of log lines to include per rank"
+//	@Success	200			{}		string	""
+//	@Router		/tasks/{task_id}/diagnostics [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTaskDiagnostics(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID   string `path:"task_id"`
+		LogLines *int   `query:"log_lines"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	logLines := defaultDiagnosticsLogLines
+	if args.LogLines != nil {
+		logLines = *args.LogLines
+	}
+	if logLines < 0 || logLines > maxDiagnosticsLogLines {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("log_lines must be between 0 and %d", maxDiagnosticsLogLines))
+	}
+
+	ctx := c.Request().Context()
+	taskID := model.TaskID(args.TaskID)
+	if _, err := echoGetTrialTaskExperiment(ctx, c, taskID); err != nil {
+		return nil, err
+	}
+	diags, err := task.AllocationDiagnosticsByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranks []int32
+	if len(diags) > 0 && logLines > 0 {
+		fields, err := m.taskLogBackend.TaskLogsFields(taskID)
+		if err != nil {
+			return nil, fmt.Errorf("getting ranks of task %s: %w", taskID, err)
+		}
+		ranks = fields.RankIds
+		slices.Sort(ranks)
+	}
+
+	bundles := make([]allocationDiagnosticsBundle, 0, len(diags))
+	for _, diag := range diags {
+		bundle := allocationDiagnosticsBundle{AllocationDiagnostics: diag, Logs: []rankLogs{}}
+		for _, rank := range ranks {
+			logs, _, err := m.taskLogBackend.TaskLogs(taskID, logLines, []api.Filter{
+				{
+					Field:     "allocation_id",
+					Operation: api.FilterOperationIn,
+					Values:    []string{diag.AllocationID.String()},
+				},
+				{
+					Field:     "rank_id",
+					Operation: api.FilterOperationIn,
+					Values:    []int32{rank},
+				},
+			}, apiv1.OrderBy_ORDER_BY_DESC, nil)
+			if err != nil {
+				return nil, fmt.Errorf("getting logs of rank %d of allocation %s: %w",
+					rank, diag.AllocationID, err)
+			}
+			if len(logs) == 0 {
+				continue
+			}
+			lines := make([]string, len(logs))
+			for i, l := range logs {
+				// Logs were fetched newest first.
+				lines[len(logs)-1-i] = strings.TrimSuffix(l.Log, "\n")
+			}
+			bundle.Logs = append(bundle.Logs, rankLogs{Rank: int(rank), Lines: lines})
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles, nil
+}
+
+// taskRestartsResponse is a trial's restart budgets, and the failures that count against them.
+type taskRestartsResponse struct {
+	Restarts         int                  `json:"restarts"`
+	MaxRestarts      int                  `json:"max_restarts"`
+	InfraRestarts    int                  `json:"infra_restarts"`
+	MaxInfraRestarts *int                 `json:"max_infra_restarts"`
+	Failures         []model.TrialFailure `json:"failures"`
+}
+
+//	@Summary	Get the restart budgets of a trial and why its allocations failed.
+//	@Tags		Tasks
+//	@ID			get-task-restarts
+//	@Produce	json
+//	@Param		task_id	path	string	true	"Task ID"
+//	@Success	200		{}		string	""
+//	@Router		/tasks/{task_id}/restarts [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTaskRestarts(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID string `path:"task_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	ctx := c.Request().Context()
+	taskID := model.TaskID(args.TaskID)
+	exp, err := echoGetTrialTaskExperiment(ctx, c, taskID)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := db.TrialByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	config, err := m.db.ActiveExperimentConfig(exp.ID)
+	if err != nil {
+		return nil, err
+	}
+	failures, err := db.TrialFailuresByTrialID(ctx, tr.ID)
+	if err != nil {
+		return nil, err
+	}
+	infraRestarts := 0
+	for _, f := range failures {
+		if f.Class == model.InfraFailure {
+			infraRestarts++
+		}
+	}
+	return taskRestartsResponse{
+		Restarts:         tr.Restarts,
+		MaxRestarts:      config.MaxRestarts(),
+		InfraRestarts:    infraRestarts,
+		MaxInfraRestarts: config.MaxInfraRestarts(),
+		Failures:         failures,
+	}, nil
+}
+
+//	@Summary	Report that a trial's allocation is alive, and the last step it reported.
+//	@Tags		Tasks
+//	@ID			post-task-heartbeat
+//	@Accept		json
+//	@Param		task_id	path	string	true	"Task ID"
+//	@Success	200	{}	string	""
+//	@Router		/tasks/{task_id}/heartbeat [post]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) postTaskHeartbeat(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID string `path:"task_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	var params struct {
+		AllocationID model.AllocationID `json:"allocation_id"`
+		liveness.Heartbeat
+	}
+	if err = json.Unmarshal(body, &params); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request")
+	}
+
+	ctx := c.Request().Context()
+	taskID := model.TaskID(args.TaskID)
+	if params.AllocationID.ToTaskID() != taskID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("allocation %s does not belong to task %s", params.AllocationID, taskID))
+	}
+	if _, err = echoGetTrialTaskExperiment(ctx, c, taskID,
+		expauth.AuthZProvider.Get().CanEditExperiment,
+	); err != nil {
+		return nil, err
+	}
+
+	err = liveness.RecordHeartbeat(taskID, params.AllocationID, params.Heartbeat)
+	if errors.Is(err, liveness.ErrNotWatched) {
+		return nil, echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	return nil, err
+}
+
+// taskLivenessResponse is the liveness of a trial's current allocation, and the stalls of all of
+// its allocations.
+type taskLivenessResponse struct {
+	Status *liveness.Status   `json:"status"`
+	Stalls []model.StallAlert `json:"stalls"`
+}
+
+//	@Summary	Get the liveness of a trial and the stalls liveness detection found.
+//	@Tags		Tasks
+//	@ID			get-task-liveness
+//	@Produce	json
+//	@Param		task_id	path	string	true	"Task ID"
+//	@Success	200		{}		string	""
+//	@Router		/tasks/{task_id}/liveness [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTaskLiveness(c echo.Context) (interface{}, error) {
+	args := struct {
+		TaskID string `path:"task_id"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	ctx := c.Request().Context()
+	taskID := model.TaskID(args.TaskID)
+	if _, err := echoGetTrialTaskExperiment(ctx, c, taskID); err != nil {
+		return nil, err
+	}
+	stalls, err := task.StallAlertsByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	res := taskLivenessResponse{Stalls: stalls}
+	if status, ok := liveness.GetStatus(taskID); ok {
+		res.Status = &status
+	}
+	return res, nil
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	expauth "github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/logship"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+// maxTaskLogBatchBytes limits the decompressed size of a batch of task logs.
+const maxTaskLogBatchBytes = 64 << 20
+
+//	@Summary	Receive a batch of task logs from the log shipper of a task container.
+//	@Tags		Tasks
+//	@ID			post-task-log-batch
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{}	logship.Ack	""
+//	@Failure	429	{}	string		"The master can't take the batch yet; retry after Retry-After."
+//	@Router		/task-logs/v2 [post]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) postTaskLogBatch(c echo.Context) (interface{}, error) {
+	var body io.Reader = c.Request().Body
+	if c.Request().Header.Get(echo.HeaderContentEncoding) == "gzip" {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var batch logship.Batch
+	if err := json.NewDecoder(io.LimitReader(body, maxTaskLogBatchBytes)).Decode(&batch); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+	}
+	if err := batch.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+	}
+	logs := make([]*taskv1.TaskLog, len(batch.Logs))
+	for i, raw := range batch.Logs {
+		logs[i] = &taskv1.TaskLog{}
+		if err := protojson.Unmarshal(raw, logs[i]); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "bad request: "+err.Error())
+		}
+	}
+
+	// Log shippers authenticate with the allocation token, like they do with the gRPC API.
+	md := metadata.MD{}
+	for k, v := range c.Request().Header {
+		if strings.HasPrefix(k, grpcutil.GrpcMetadataPrefix) {
+			md.Append(strings.TrimPrefix(k, grpcutil.GrpcMetadataPrefix), v...)
+		}
+	}
+	ctx := metadata.NewIncomingContext(c.Request().Context(), md)
+
+	// Callers are authorized before the receiver keeps any state for their stream, and streams
+	// are scoped to their task, so callers can only grow or touch the streams of tasks they can
+	// post logs to.
+	a := &apiServer{m: m}
+	taskID := model.TaskID(logs[0].TaskId)
+	if _, _, err := a.canDoActionsOnTask(ctx, taskID,
+		expauth.AuthZProvider.Get().CanEditExperiment); err != nil {
+		return nil, taskLogBatchErr(err)
+	}
+	batch.StreamID = string(taskID) + "/" + batch.StreamID
+
+	ack, err := m.logReceiver.Receive(&batch, func() error {
+		_, err := a.PostTaskLogs(ctx, &apiv1.PostTaskLogsRequest{Logs: logs})
+		return err
+	})
+	var busy logship.BusyError
+	switch {
+	case errors.As(err, &busy):
+		c.Response().Header().Set("Retry-After",
+			strconv.Itoa(int(logship.RetryAfter.Seconds())))
+		return nil, echo.NewHTTPError(http.StatusTooManyRequests, busy.Error())
+	case err != nil:
+		return nil, taskLogBatchErr(err)
+	}
+	return ack, nil
+}
+
+func taskLogBatchErr(err error) error {
+	if ok, echoErr := api.GrpcErrToEcho(err); ok {
+		return echoErr
+	}
+	return err
+}
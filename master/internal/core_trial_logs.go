@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/logorder"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const (
+	defaultOrderedTrialLogsLimit = 10000
+	maxOrderedTrialLogsLimit     = 100000
+)
+
+// orderedTrialLogs is a page of the logs of all ranks of a trial in one order.
+type orderedTrialLogs struct {
+	Order logorder.Order  `json:"order"`
+	Logs  []*logorder.Log `json:"logs"`
+	// Truncated is whether the trial has more logs than limit.
+	Truncated bool `json:"truncated"`
+}
+
+//	@Summary	Get the logs of all ranks of a trial, interleaved by timestamp or grouped by rank.
+//	@Tags		Trials
+//	@ID			get-trial-logs-ordered
+//	@Produce	json
+//	@Param		trial_id	path	int		true	"Trial ID"
+//	@Param		order		query	string	false	"timestamp (default) or rank"
+//	@Param		limit		query	int		false	"Maximum number of logs to return"
+//	@Success	200			{}		string	""
+//	@Router		/trials/{trial_id}/logs/ordered [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getTrialLogsOrdered(c echo.Context) (interface{}, error) {
+	args := struct {
+		TrialID int     `path:"trial_id"`
+		Order   *string `query:"order"`
+		Limit   *int    `query:"limit"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	var orderStr string
+	if args.Order != nil {
+		orderStr = *args.Order
+	}
+	order, err := logorder.ParseOrder(orderStr)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	limit := defaultOrderedTrialLogsLimit
+	if args.Limit != nil {
+		limit = *args.Limit
+	}
+	if limit <= 0 || limit > maxOrderedTrialLogsLimit {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("limit must be between 1 and %d", maxOrderedTrialLogsLimit))
+	}
+
+	a, ctx := m.echoAPIServer(c)
+	if _, err := m.echoCheckCanGetTrialArtifacts(ctx, c, args.TrialID); err != nil {
+		return nil, err
+	}
+	trialTaskIDs, err := db.TrialTaskIDsByTrialID(ctx, args.TrialID)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving task IDs for trial logs: %w", err)
+	}
+
+	// Fetch one more log than the limit to know whether there are more.
+	var logs []*model.TaskLog
+	for _, t := range trialTaskIDs {
+		var followState interface{}
+		for len(logs) <= limit {
+			batchSize := min(taskLogsBatchSize, limit+1-len(logs))
+			b, state, err := a.m.taskLogBackend.TaskLogs(
+				t.TaskID, batchSize, nil, apiv1.OrderBy_ORDER_BY_ASC, followState)
+			if err != nil {
+				return nil, fmt.Errorf("fetching logs of task %s: %w", t.TaskID, err)
+			}
+			logs = append(logs, b...)
+			followState = state
+			if len(b) < batchSize {
+				break
+			}
+		}
+	}
+	truncated := len(logs) > limit
+	if truncated {
+		logs = logs[:limit]
+	}
+
+	agentIDs := make(map[string]bool)
+	for _, l := range logs {
+		if l.AgentID != nil {
+			agentIDs[*l.AgentID] = true
+		}
+	}
+	ids := make([]string, 0, len(agentIDs))
+	for id := range agentIDs {
+		ids = append(ids, id)
+	}
+	offsets, err := db.AgentClockOffsets(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := logorder.Normalize(logs, offsets)
+	logorder.Sort(ordered, order)
+	return orderedTrialLogs{Order: order, Logs: ordered, Truncated: truncated}, nil
+}
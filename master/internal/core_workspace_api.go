@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/workspace"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// workspaceAPIKeyContextKey is where the workspace API key of a request is kept on its context.
+	workspaceAPIKeyContextKey = "workspace-api-key"
+	// workspaceAPIMaxLimit caps the page size of workspace API lists.
+	workspaceAPIMaxLimit = 1000
+	// workspaceAPIMaxMetrics caps the number of metrics reports a workspace API series returns.
+	workspaceAPIMaxMetrics = 10000
+)
+
+// processWorkspaceAPIKeyAuthentication authenticates requests to the workspace API, which takes
+// read-only workspace API keys instead of user tokens.
+func processWorkspaceAPIKeyAuthentication(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		switch c.Request().Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			return echo.NewHTTPError(http.StatusMethodNotAllowed, "workspace API keys are read-only")
+		}
+
+		key, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+		if !ok || !model.IsWorkspaceAPIKey(key) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "a workspace API key is required")
+		}
+		k, err := workspace.AuthenticateAPIKey(c.Request().Context(), key)
+		if errors.Is(err, db.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid, expired or revoked API key")
+		} else if err != nil {
+			return err
+		}
+		c.Set(workspaceAPIKeyContextKey, k)
+		return next(c)
+	}
+}
+
+func mustGetWorkspaceAPIKey(c echo.Context) *model.WorkspaceAPIKey {
+	return c.Get(workspaceAPIKeyContextKey).(*model.WorkspaceAPIKey)
+}
+
+//	@Summary	Get the workspace a workspace API key belongs to.
+//	@Tags		Workspace API
+//	@ID			get-workspace-api-workspace
+//	@Produce	json
+//	@Success	200	{}	string	""
+//	@Router		/workspace-api/v1/workspace [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getWorkspaceAPIWorkspace(c echo.Context) (interface{}, error) {
+	k := mustGetWorkspaceAPIKey(c)
+	var w model.Workspace
+	if err := db.Bun().NewSelect().Model(&w).
+		Where("id = ?", k.WorkspaceID).
+		Scan(c.Request().Context()); err != nil {
+		return nil, err
+	}
+	return map[string]any{"id": w.ID, "name": w.Name, "archived": w.Archived}, nil
+}
+
+//	@Summary	Get summaries of the experiments in a workspace API key's workspace, newest first.
+//	@Tags		Workspace API
+//	@ID			get-workspace-api-experiments
+//	@Produce	json
+//	@Param		project_id	query	int		false	"Only experiments in this project"
+//	@Param		state		query	string	false	"Only experiments in this state, e.g. COMPLETED"
+//	@Param		archived	query	bool	false	"Get archived experiments instead"
+//	@Param		limit		query	int		false	"Page size, at most 1000"
+//	@Param		offset		query	int		false	"Number of experiments to skip"
+//	@Success	200	{}	string	""
+//	@Router		/workspace-api/v1/experiments [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getWorkspaceAPIExperiments(c echo.Context) (interface{}, error) {
+	args := struct {
+		ProjectID *int    `query:"project_id"`
+		State     *string `query:"state"`
+		Archived  *bool   `query:"archived"`
+		Limit     *int    `query:"limit"`
+		Offset    *int    `query:"offset"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	f := workspace.ExperimentSummaryFilter{
+		ProjectID: args.ProjectID,
+		State:     args.State,
+		Limit:     100,
+	}
+	if args.Archived != nil {
+		f.Archived = *args.Archived
+	}
+	if args.Limit != nil {
+		f.Limit = *args.Limit
+	}
+	if args.Offset != nil {
+		f.Offset = *args.Offset
+	}
+	if f.Limit < 1 || f.Limit > workspaceAPIMaxLimit || f.Offset < 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			"limit must be between 1 and 1000 and offset can't be negative")
+	}
+
+	return workspace.ExperimentSummaries(c.Request().Context(), mustGetWorkspaceAPIKey(c).WorkspaceID, f)
+}
+
+//	@Summary	Get a metric of a trial in a workspace API key's workspace as a series.
+//	@Tags		Workspace API
+//	@ID			get-workspace-api-trial-metrics
+//	@Produce	json
+//	@Param		trial_id	path	int		true	"Trial ID"
+//	@Param		metric		query	string	true	"Metric name, e.g. loss"
+//	@Param		group		query	string	false	"Metric group, validation by default"
+//	@Success	200	{}	string	""
+//	@Router		/workspace-api/v1/trials/{trial_id}/metrics [get]
+//
+// Read why this line exists on the comment on getAggregatedResourceAllocation in core.go.
+func (m *Master) getWorkspaceAPITrialMetrics(c echo.Context) (interface{}, error) {
+	args := struct {
+		TrialID int     `path:"trial_id"`
+		Metric  string  `query:"metric"`
+		Group   *string `query:"group"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	group := model.ValidationMetricGroup
+	if args.Group != nil {
+		group = model.MetricGroup(*args.Group)
+	}
+	if err := group.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// Trials outside the key's workspace are reported as missing, so keys can't probe for them.
+	ctx := c.Request().Context()
+	notFoundErr := api.NotFoundErrs("trial", strconv.Itoa(args.TrialID), false)
+	switch workspaceID, err := workspace.WorkspaceIDOfTrial(ctx, args.TrialID); {
+	case errors.Is(err, db.ErrNotFound):
+		return nil, notFoundErr
+	case err != nil:
+		return nil, err
+	case workspaceID != mustGetWorkspaceAPIKey(c).WorkspaceID:
+		return nil, notFoundErr
+	}
+
+	type point struct {
+		StepsCompleted int       `json:"steps_completed"`
+		Time           time.Time `json:"time"`
+		Value          float64   `json:"value"`
+	}
+	groupStr := group.ToString()
+	reports, err := db.GetMetrics(ctx, args.TrialID, -1, workspaceAPIMaxMetrics, &groupStr)
+	if err != nil {
+		return nil, err
+	}
+	points := []point{}
+	for _, r := range reports {
+		// Skip reports without the metric, or where it isn't a finite number.
+		v := r.Metrics.GetFields()["avg_metrics"].GetStructValue().GetFields()[args.Metric]
+		if _, ok := v.GetKind().(*structpb.Value_NumberValue); !ok {
+			continue
+		}
+		points = append(points, point{
+			StepsCompleted: int(r.TotalBatches),
+			Time:           r.EndTime.AsTime(),
+			Value:          v.GetNumberValue(),
+		})
+	}
+	return points, nil
+}
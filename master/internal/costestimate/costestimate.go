@@ -0,0 +1,176 @@
+// Package costestimate estimates the slot hours and cost of an experiment before it is submitted,
+// from its search plan and the training times of trials of similar experiments.
+package costestimate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/searcher"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// Quantiles of the historical training rates taken as the low, expected and high estimates.
+const (
+	lowQuantile      = 0.25
+	expectedQuantile = 0.5
+	highQuantile     = 0.75
+)
+
+// batchesUnit is the searcher length unit that historical training rates are measured in.
+const batchesUnit = "batches"
+
+// TrialHistory is how long a completed trial of a similar experiment trained.
+type TrialHistory struct {
+	TrialID      int     `bun:"trial_id"`
+	TotalBatches int     `bun:"total_batches"`
+	SlotSeconds  float64 `bun:"slot_seconds"`
+}
+
+// Range is a low, expected and high estimate.
+type Range struct {
+	Low      float64 `json:"low"`
+	Expected float64 `json:"expected"`
+	High     float64 `json:"high"`
+}
+
+func (r Range) scale(f float64) Range {
+	return Range{Low: r.Low * f, Expected: r.Expected * f, High: r.High * f}
+}
+
+// Estimate is the estimated slot hours and cost of an experiment.
+type Estimate struct {
+	TrialCount    int `json:"trial_count"`
+	SlotsPerTrial int `json:"slots_per_trial"`
+	// SimilarTrials is the number of completed trials of similar experiments estimated from.
+	SimilarTrials int `json:"similar_trials"`
+	// SlotHours is unset when there are no similar trials to estimate from.
+	SlotHours *Range `json:"slot_hours"`
+	// Cost is unset when SlotHours is, or when slot time has no configured price.
+	Cost          *Range   `json:"cost"`
+	Currency      string   `json:"currency,omitempty"`
+	SlotHourPrice *float64 `json:"slot_hour_price"`
+	// Notes explain the assumptions of the estimate.
+	Notes []string `json:"notes"`
+}
+
+// Proto converts an estimate to its protobuf representation.
+func (e Estimate) Proto() *experimentv1.CostEstimate {
+	pb := &experimentv1.CostEstimate{
+		TrialCount:    int32(e.TrialCount),
+		SlotsPerTrial: int32(e.SlotsPerTrial),
+		SimilarTrials: int32(e.SimilarTrials),
+		Currency:      e.Currency,
+		SlotHourPrice: e.SlotHourPrice,
+		Notes:         e.Notes,
+	}
+	if e.SlotHours != nil {
+		pb.SlotHours = e.SlotHours.proto()
+	}
+	if e.Cost != nil {
+		pb.Cost = e.Cost.proto()
+	}
+	return pb
+}
+
+func (r Range) proto() *experimentv1.EstimateRange {
+	return &experimentv1.EstimateRange{Low: r.Low, Expected: r.Expected, High: r.High}
+}
+
+// Compute estimates the slot hours of a search whose trials each use slotsPerTrial slots from the
+// training times of similar trials, and its cost at slotHourPrice. Trials that train for a known
+// number of batches are estimated from the slot time similar trials took per batch; all others are
+// estimated from the total slot time of similar trials.
+func Compute(
+	summary searcher.SearchSummary, slotsPerTrial int, history []TrialHistory,
+	slotHourPrice *float64, currency string,
+) Estimate {
+	est := Estimate{
+		SlotsPerTrial: slotsPerTrial,
+		SimilarTrials: len(history),
+		SlotHourPrice: slotHourPrice,
+		Currency:      currency,
+		Notes:         []string{},
+	}
+	for _, t := range summary.Trials {
+		est.TrialCount += t.Count
+	}
+	if slotsPerTrial == 0 {
+		est.SlotHours, est.Cost = &Range{}, &Range{}
+		est.Notes = append(est.Notes, "trials use no slots")
+		return est
+	}
+	if len(history) == 0 {
+		est.Notes = append(est.Notes, "no completed trials of similar experiments to estimate from")
+		return est
+	}
+
+	var perTrial, perBatch []float64
+	for _, h := range history {
+		perTrial = append(perTrial, h.SlotSeconds)
+		if h.TotalBatches > 0 {
+			perBatch = append(perBatch, h.SlotSeconds/float64(h.TotalBatches))
+		}
+	}
+	perTrialRange := quantiles(perTrial)
+	perBatchRange := quantiles(perBatch)
+
+	var slotSeconds Range
+	var byLength, byTrial bool
+	for _, t := range summary.Trials {
+		var r Range
+		switch {
+		case !t.Unit.MaxLength && t.Unit.Name != nil && *t.Unit.Name == batchesUnit &&
+			t.Unit.Value != nil && len(perBatch) > 0:
+			r = perBatchRange.scale(float64(*t.Unit.Value))
+			byLength = true
+		default:
+			r = perTrialRange
+			byTrial = true
+		}
+		r = r.scale(float64(t.Count))
+		slotSeconds = Range{
+			Low:      slotSeconds.Low + r.Low,
+			Expected: slotSeconds.Expected + r.Expected,
+			High:     slotSeconds.High + r.High,
+		}
+	}
+	slotHours := slotSeconds.scale(1.0 / 3600)
+	est.SlotHours = &slotHours
+
+	if byLength {
+		est.Notes = append(est.Notes, fmt.Sprintf(
+			"trials with a known length are estimated from the slot time %d similar trials took "+
+				"per batch", len(perBatch)))
+	}
+	if byTrial {
+		est.Notes = append(est.Notes, fmt.Sprintf(
+			"trials without a known length in batches are assumed to train as long as %d similar "+
+				"trials did", len(perTrial)))
+	}
+	est.Notes = append(est.Notes,
+		"slot time per batch is assumed not to depend on slots_per_trial")
+
+	if slotHourPrice == nil {
+		est.Notes = append(est.Notes, "no slot hour price is configured for the resource pool")
+		return est
+	}
+	cost := slotHours.scale(*slotHourPrice)
+	est.Cost = &cost
+	return est
+}
+
+// quantiles returns the low, expected and high quantiles of xs, or a zero Range if xs is empty.
+func quantiles(xs []float64) Range {
+	if len(xs) == 0 {
+		return Range{}
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	return Range{
+		Low:      mathx.Quantile(sorted, lowQuantile),
+		Expected: mathx.Quantile(sorted, expectedQuantile),
+		High:     mathx.Quantile(sorted, highQuantile),
+	}
+}
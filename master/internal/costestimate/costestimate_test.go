@@ -0,0 +1,71 @@
+package costestimate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/searcher"
+)
+
+func TestQuantiles(t *testing.T) {
+	require.Equal(t, Range{}, quantiles(nil))
+	require.Equal(t, Range{Low: 7, Expected: 7, High: 7}, quantiles([]float64{7}))
+	require.Equal(t, Range{Low: 2, Expected: 3, High: 4}, quantiles([]float64{5, 1, 3, 2, 4}))
+	require.Equal(t, Range{Low: 1.75, Expected: 2.5, High: 3.25}, quantiles([]float64{4, 3, 2, 1}))
+}
+
+func TestCompute(t *testing.T) {
+	// Three similar trials took 1, 2 and 3 slot-hours to train 100 batches each.
+	history := []TrialHistory{
+		{TrialID: 1, TotalBatches: 100, SlotSeconds: 3600},
+		{TrialID: 2, TotalBatches: 100, SlotSeconds: 7200},
+		{TrialID: 3, TotalBatches: 100, SlotSeconds: 10800},
+	}
+	maxLength := searcher.SearchSummary{Trials: []searcher.TrialSummary{
+		{Count: 4, Unit: searcher.SearchUnit{MaxLength: true}},
+	}}
+
+	est := Compute(maxLength, 2, history, ptrs.Ptr(3.0), "USD")
+	require.Equal(t, 4, est.TrialCount)
+	require.Equal(t, 3, est.SimilarTrials)
+	require.Equal(t, &Range{Low: 6, Expected: 8, High: 10}, est.SlotHours)
+	require.Equal(t, &Range{Low: 18, Expected: 24, High: 30}, est.Cost)
+
+	batches := searcher.SearchSummary{Trials: []searcher.TrialSummary{
+		{Count: 2, Unit: searcher.SearchUnit{Name: ptrs.Ptr("batches"), Value: ptrs.Ptr(int32(50))}},
+		{Count: 1, Unit: searcher.SearchUnit{Name: ptrs.Ptr("batches"), Value: ptrs.Ptr(int32(200))}},
+	}}
+	est = Compute(batches, 1, history, nil, "USD")
+	require.Equal(t, 3, est.TrialCount)
+	require.InDelta(t, 4.5, est.SlotHours.Low, 1e-9)
+	require.InDelta(t, 6, est.SlotHours.Expected, 1e-9)
+	require.InDelta(t, 7.5, est.SlotHours.High, 1e-9)
+	require.Nil(t, est.Cost, "no price is configured")
+
+	est = Compute(maxLength, 1, nil, ptrs.Ptr(3.0), "USD")
+	require.Nil(t, est.SlotHours)
+	require.Nil(t, est.Cost)
+	require.NotEmpty(t, est.Notes)
+
+	est = Compute(maxLength, 0, nil, ptrs.Ptr(3.0), "USD")
+	require.Equal(t, &Range{}, est.SlotHours)
+	require.Equal(t, &Range{}, est.Cost)
+}
+
+func TestEstimateProto(t *testing.T) {
+	pb := Estimate{TrialCount: 4, SlotsPerTrial: 2, Notes: []string{"n"}}.Proto()
+	require.Equal(t, int32(4), pb.TrialCount)
+	require.Nil(t, pb.SlotHours)
+	require.Nil(t, pb.SlotHourPrice)
+
+	pb = Estimate{
+		SlotHours:     &Range{Low: 1, Expected: 2, High: 3},
+		Cost:          &Range{Low: 2, Expected: 4, High: 6},
+		SlotHourPrice: ptrs.Ptr(2.0),
+	}.Proto()
+	require.Equal(t, 2.0, pb.SlotHours.Expected)
+	require.Equal(t, 6.0, pb.Cost.High)
+	require.Equal(t, 2.0, *pb.SlotHourPrice)
+}
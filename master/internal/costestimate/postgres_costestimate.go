@@ -0,0 +1,49 @@
+package costestimate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+const (
+	// maxSimilarExperiments caps how many recent experiments are estimated from.
+	maxSimilarExperiments = 20
+	// maxSimilarTrials caps how many of their trials are estimated from.
+	maxSimilarTrials = 500
+)
+
+// SimilarTrials returns how long the completed trials of recent experiments in a project trained,
+// preferring experiments with the same name as the one being estimated.
+func SimilarTrials(ctx context.Context, projectID int, name string) ([]TrialHistory, error) {
+	similar := db.Bun().NewSelect().
+		TableExpr("experiments AS e").
+		Column("e.id").
+		Where("e.project_id = ?", projectID).
+		Where("NOT e.unmanaged").
+		Where("EXISTS (SELECT 1 FROM trials AS t WHERE t.experiment_id = e.id AND t.state = 'COMPLETED')").
+		OrderExpr("e.config->>'name' = ? DESC", name).
+		OrderExpr("e.id DESC").
+		Limit(maxSimilarExperiments)
+
+	history := []TrialHistory{}
+	if err := db.Bun().NewSelect().
+		TableExpr("trials AS t").
+		ColumnExpr("t.id AS trial_id").
+		ColumnExpr("t.total_batches").
+		ColumnExpr("SUM(EXTRACT(EPOCH FROM (a.end_time - a.start_time)) * a.slots) AS slot_seconds").
+		Join("JOIN run_id_task_id AS rt ON rt.run_id = t.id").
+		Join("JOIN allocations AS a ON a.task_id = rt.task_id").
+		Where("t.experiment_id IN (?)", similar).
+		Where("t.state = 'COMPLETED'").
+		Where("a.start_time IS NOT NULL AND a.end_time IS NOT NULL").
+		Group("t.id").
+		Having("SUM(EXTRACT(EPOCH FROM (a.end_time - a.start_time)) * a.slots) > 0").
+		OrderExpr("t.id DESC").
+		Limit(maxSimilarTrials).
+		Scan(ctx, &history); err != nil {
+		return nil, fmt.Errorf("getting trials similar to %q in project %d: %w", name, projectID, err)
+	}
+	return history, nil
+}
@@ -24,7 +24,6 @@ type DB interface {
 	NonTerminalExperiments() ([]*model.Experiment, error)
 	TerminateExperimentInRestart(id int, state model.State) error
 	SaveExperimentConfig(id int, config expconf.ExperimentConfig) error
-	SaveExperimentState(experiment *model.Experiment) error
 	SaveExperimentArchiveStatus(experiment *model.Experiment) error
 	DeleteExperiments(ctx context.Context, ids []int) error
 	ExperimentHasCheckpointsInRegistry(id int) (bool, error)
@@ -72,6 +71,7 @@ type DB interface {
 	SaveSnapshot(
 		experimentID int, version int, experimentSnapshot []byte,
 	) error
+	SaveSnapshotDelta(experimentID int, version int, delta []byte) error
 	DeleteSnapshotsForExperiment(experimentID int) error
 	DeleteSnapshotsForTerminalExperiments() error
 	QueryProto(queryName string, v interface{}, args ...interface{}) error
@@ -0,0 +1,405 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+)
+
+// Postgres table lock modes that migration statements take, from least to most restrictive. See
+// https://www.postgresql.org/docs/current/explicit-locking.html#LOCKING-TABLES.
+const (
+	LockRowExclusive         = "ROW EXCLUSIVE"
+	LockShareUpdateExclusive = "SHARE UPDATE EXCLUSIVE"
+	LockShare                = "SHARE"
+	LockShareRowExclusive    = "SHARE ROW EXCLUSIVE"
+	LockExclusive            = "EXCLUSIVE"
+	LockAccessExclusive      = "ACCESS EXCLUSIVE"
+)
+
+const (
+	// MigrationPhaseExpand is the phase of migrations that only add to the schema, which the
+	// previous version of the master keeps working with.
+	MigrationPhaseExpand = "expand"
+	// MigrationPhaseContract is the phase of migrations that remove what the previous version of
+	// the master used, which can run once the master is serving again in online mode.
+	MigrationPhaseContract = "contract"
+
+	// contractMigrationsDir is the directory under the migrations directory that holds the
+	// contract migrations.
+	contractMigrationsDir = "contract"
+	// contractMigrationsTable is where the applied contract migrations are recorded.
+	contractMigrationsTable = "gopg_contract_migrations"
+
+	// largeTableBytes is how big a table has to be for holding a lock on it while scanning it to
+	// be worth a warning.
+	largeTableBytes = 1 << 30
+)
+
+// MigrationStatement is what a statement of a migration locks.
+type MigrationStatement struct {
+	Table string `json:"table,omitempty"`
+	Lock  string `json:"lock"`
+	// ScansTable is set if the statement reads or rewrites every row of the table while holding
+	// the lock, so the lock is held for as long as that takes.
+	ScansTable bool   `json:"scans_table"`
+	Statement  string `json:"statement"`
+}
+
+// PlannedMigration is a migration that hasn't been applied yet.
+type PlannedMigration struct {
+	Version       int64                `json:"version"`
+	Name          string               `json:"name"`
+	Phase         string               `json:"phase"`
+	Transactional bool                 `json:"transactional"`
+	Statements    []MigrationStatement `json:"statements"`
+	Warnings      []string             `json:"warnings,omitempty"`
+}
+
+// TableSize is the size of a table on disk, including its indexes, and its estimated row count.
+type TableSize struct {
+	bun.BaseModel `bun:"table:pg_class"`
+	Name          string `bun:"name" json:"-"`
+	Bytes         int64  `bun:"bytes" json:"bytes"`
+	Rows          int64  `bun:"rows" json:"rows"`
+}
+
+// MigrationPlan is what applying the pending migrations would do.
+type MigrationPlan struct {
+	Version         int64                `json:"version"`
+	ContractVersion int64                `json:"contract_version"`
+	Pending         []PlannedMigration   `json:"pending"`
+	Tables          map[string]TableSize `json:"tables"`
+}
+
+// Warnings returns the warnings of all the pending migrations.
+func (p *MigrationPlan) Warnings() []string {
+	var warnings []string
+	for _, m := range p.Pending {
+		for _, w := range m.Warnings {
+			warnings = append(warnings, fmt.Sprintf("%d_%s: %s", m.Version, m.Name, w))
+		}
+	}
+	return warnings
+}
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(.+?)\.(tx\.)?up\.sql$`)
+
+// migrationsDir returns the directory of a migrations URL, like file:///static/migrations.
+func migrationsDir(migrationURL string) (string, error) {
+	dir, ok := strings.CutPrefix(migrationURL, "file://")
+	if !ok {
+		return "", fmt.Errorf("failed to parse migrationsURL: %s", migrationURL)
+	}
+	return dir, nil
+}
+
+// PlanMigrations reads the migrations that haven't been applied yet and estimates which tables
+// they lock, how, and how big those tables are.
+func (db *PgDB) PlanMigrations(ctx context.Context, migrationURL string) (*MigrationPlan, error) {
+	dir, err := migrationsDir(migrationURL)
+	if err != nil {
+		return nil, err
+	}
+	plan := &MigrationPlan{Tables: map[string]TableSize{}}
+	if plan.Version, err = appliedMigrationVersion(ctx, "gopg_migrations"); err != nil {
+		return nil, err
+	}
+	if plan.ContractVersion, err = appliedMigrationVersion(ctx, contractMigrationsTable); err != nil {
+		return nil, err
+	}
+
+	for _, phase := range []struct {
+		name    string
+		dir     string
+		version int64
+	}{
+		{MigrationPhaseExpand, dir, plan.Version},
+		{MigrationPhaseContract, filepath.Join(dir, contractMigrationsDir), plan.ContractVersion},
+	} {
+		pending, err := readPendingMigrations(phase.dir, phase.version)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pending {
+			pending[i].Phase = phase.name
+		}
+		plan.Pending = append(plan.Pending, pending...)
+	}
+
+	var tables []string
+	for _, m := range plan.Pending {
+		for _, s := range m.Statements {
+			if s.Table != "" {
+				tables = append(tables, s.Table)
+			}
+		}
+	}
+	if len(tables) > 0 {
+		var sizes []TableSize
+		if err := Bun().NewSelect().Model(&sizes).
+			ColumnExpr("relname AS name").
+			ColumnExpr("pg_total_relation_size(oid) AS bytes").
+			ColumnExpr("greatest(reltuples, 0)::bigint AS rows").
+			Where("relnamespace = 'public'::regnamespace").
+			Where("relkind IN ('r', 'p')").
+			Where("relname IN (?)", bun.In(tables)).
+			Scan(ctx); err != nil {
+			return nil, fmt.Errorf("getting table sizes: %w", err)
+		}
+		for _, s := range sizes {
+			plan.Tables[s.Name] = s
+		}
+	}
+
+	for i, m := range plan.Pending {
+		plan.Pending[i].Warnings = migrationWarnings(m, plan.Tables)
+	}
+	return plan, nil
+}
+
+func appliedMigrationVersion(ctx context.Context, table string) (int64, error) {
+	var exists bool
+	if err := Bun().NewRaw(
+		"SELECT EXISTS(SELECT 1 FROM pg_tables WHERE schemaname = 'public' AND tablename = ?)", table,
+	).Scan(ctx, &exists); err != nil {
+		return 0, fmt.Errorf("checking whether %s exists: %w", table, err)
+	}
+	if !exists {
+		return 0, nil
+	}
+	var versions []int64
+	if err := Bun().NewSelect().Table(table).Column("version").
+		Order("id DESC").Limit(1).Scan(ctx, &versions); err != nil {
+		return 0, fmt.Errorf("getting version from %s: %w", table, err)
+	}
+	if len(versions) == 0 {
+		return 0, nil
+	}
+	return versions[0], nil
+}
+
+// readPendingMigrations reads the migrations in a directory newer than a version, oldest first.
+func readPendingMigrations(dir string, version int64) ([]PlannedMigration, error) {
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading migrations from %s: %w", dir, err)
+	}
+	var pending []PlannedMigration
+	for _, f := range files {
+		match := migrationFileRegex.FindStringSubmatch(f.Name())
+		if f.IsDir() || match == nil {
+			continue
+		}
+		v, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if v <= version {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, f.Name())) //nolint: gosec // We trust dir.
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", f.Name(), err)
+		}
+		pending = append(pending, PlannedMigration{
+			Version:       v,
+			Name:          match[2],
+			Transactional: match[3] != "",
+			Statements:    analyzeMigrationSQL(string(b)),
+		})
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Version < pending[j].Version })
+	return pending, nil
+}
+
+// migrationWarnings points out the statements of a migration that would block the use of a large
+// table for as long as it takes to scan it.
+func migrationWarnings(m PlannedMigration, tables map[string]TableSize) []string {
+	var warnings []string
+	for _, s := range m.Statements {
+		size, ok := tables[s.Table]
+		if !ok || !s.ScansTable || size.Bytes < largeTableBytes {
+			continue
+		}
+		var blocks string
+		switch s.Lock {
+		case LockAccessExclusive:
+			blocks = "reads and writes"
+		case LockShare, LockShareRowExclusive, LockExclusive:
+			blocks = "writes"
+		default:
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"%q blocks %s to %s (%.1f GiB, ~%d rows) while it scans the table",
+			s.Statement, blocks, s.Table, float64(size.Bytes)/(1<<30), size.Rows))
+	}
+	if !m.Transactional {
+		warnings = append(warnings,
+			"migration isn't transactional, so it has to be fixed by hand if it fails partway")
+	}
+	return warnings
+}
+
+var (
+	identRegex = `(?:IF\s+(?:NOT\s+)?EXISTS\s+)?(?:ONLY\s+)?(?:public\.)?"?(\w+)"?`
+
+	createTableRegex = regexp.MustCompile(`(?is)^CREATE\s+(?:UNLOGGED\s+)?TABLE\s+` + identRegex)
+	createIndexRegex = regexp.MustCompile(
+		`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?.*?\sON\s+` + identRegex)
+	dropIndexRegex     = regexp.MustCompile(`(?is)^DROP\s+INDEX\s+(CONCURRENTLY\s+)?`)
+	alterTableRegex    = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+` + identRegex)
+	dropTableRegex     = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+` + identRegex)
+	truncateRegex      = regexp.MustCompile(`(?is)^TRUNCATE\s+(?:TABLE\s+)?` + identRegex)
+	updateRegex        = regexp.MustCompile(`(?is)^UPDATE\s+` + identRegex)
+	deleteRegex        = regexp.MustCompile(`(?is)^DELETE\s+FROM\s+` + identRegex)
+	insertRegex        = regexp.MustCompile(`(?is)^INSERT\s+INTO\s+` + identRegex)
+	createTriggerRegex = regexp.MustCompile(
+		`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:CONSTRAINT\s+)?TRIGGER\s+.*?\sON\s+` + identRegex)
+	lockTableRegex = regexp.MustCompile(
+		`(?is)^LOCK\s+(?:TABLE\s+)?` + identRegex + `(?:\s+IN\s+(.+?)\s+MODE)?`)
+
+	// Alterations that rewrite the table, or that check every row against a new constraint.
+	alterRewriteRegex = regexp.MustCompile(`(?is)\s(?:SET\s+DATA\s+)?TYPE\s|\sSET\s+NOT\s+NULL` +
+		`|\sADD\s+(?:CONSTRAINT\s+\w+\s+)?(?:PRIMARY\s+KEY|UNIQUE|CHECK|FOREIGN\s+KEY)` +
+		`|\sADD\s+(?:COLUMN\s+)?.*\sDEFAULT\s+.*\(\)`)
+	alterNotValidRegex = regexp.MustCompile(`(?is)\sNOT\s+VALID\b`)
+	validateRegex      = regexp.MustCompile(`(?is)\sVALIDATE\s+CONSTRAINT\s`)
+)
+
+// analyzeMigrationSQL estimates which tables each statement of a migration locks, and how. It
+// recognizes the statements migrations commonly use, and skips the rest.
+func analyzeMigrationSQL(sql string) []MigrationStatement {
+	var statements []MigrationStatement
+	for _, stmt := range splitSQLStatements(sql) {
+		s := MigrationStatement{Statement: summarizeStatement(stmt)}
+		switch {
+		case createTableRegex.MatchString(stmt):
+			// New tables are empty, and nothing else uses them yet.
+			continue
+		case createIndexRegex.MatchString(stmt):
+			m := createIndexRegex.FindStringSubmatch(stmt)
+			s.Table, s.Lock, s.ScansTable = m[2], LockShare, true
+			if m[1] != "" {
+				s.Lock = LockShareUpdateExclusive
+			}
+		case dropIndexRegex.MatchString(stmt):
+			// The table of the index isn't named, but dropping it is quick.
+			s.Lock = LockAccessExclusive
+			if dropIndexRegex.FindStringSubmatch(stmt)[1] != "" {
+				s.Lock = LockShareUpdateExclusive
+			}
+		case alterTableRegex.MatchString(stmt):
+			s.Table, s.Lock = alterTableRegex.FindStringSubmatch(stmt)[1], LockAccessExclusive
+			switch {
+			case validateRegex.MatchString(stmt):
+				s.Lock, s.ScansTable = LockShareUpdateExclusive, true
+			case alterNotValidRegex.MatchString(stmt):
+			case alterRewriteRegex.MatchString(stmt):
+				s.ScansTable = true
+			}
+		case dropTableRegex.MatchString(stmt):
+			s.Table, s.Lock = dropTableRegex.FindStringSubmatch(stmt)[1], LockAccessExclusive
+		case truncateRegex.MatchString(stmt):
+			s.Table, s.Lock = truncateRegex.FindStringSubmatch(stmt)[1], LockAccessExclusive
+		case updateRegex.MatchString(stmt):
+			s.Table, s.Lock, s.ScansTable = updateRegex.FindStringSubmatch(stmt)[1],
+				LockRowExclusive, true
+		case deleteRegex.MatchString(stmt):
+			s.Table, s.Lock, s.ScansTable = deleteRegex.FindStringSubmatch(stmt)[1],
+				LockRowExclusive, true
+		case insertRegex.MatchString(stmt):
+			s.Table, s.Lock = insertRegex.FindStringSubmatch(stmt)[1], LockRowExclusive
+		case createTriggerRegex.MatchString(stmt):
+			s.Table, s.Lock = createTriggerRegex.FindStringSubmatch(stmt)[1], LockShareRowExclusive
+		case lockTableRegex.MatchString(stmt):
+			m := lockTableRegex.FindStringSubmatch(stmt)
+			s.Table, s.Lock = m[1], LockAccessExclusive
+			if m[2] != "" {
+				s.Lock = strings.ToUpper(strings.Join(strings.Fields(m[2]), " "))
+			}
+		default:
+			continue
+		}
+		statements = append(statements, s)
+	}
+	return statements
+}
+
+// summarizeStatement shortens a statement to its first line, for showing in plans.
+func summarizeStatement(stmt string) string {
+	const maxLen = 80
+	line, _, _ := strings.Cut(stmt, "\n")
+	line = strings.Join(strings.Fields(line), " ")
+	if len(line) > maxLen {
+		line = line[:maxLen] + "..."
+	}
+	return line
+}
+
+// splitSQLStatements splits SQL into statements on semicolons, skipping comments and semicolons
+// in quotes and dollar-quoted function bodies.
+func splitSQLStatements(sql string) []string {
+	var statements []string
+	var cur strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			statements = append(statements, s)
+		}
+		cur.Reset()
+	}
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end
+			}
+			cur.WriteByte('\n')
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			cur.WriteByte(' ')
+		case c == '\'' || c == '"' || c == '$':
+			// Quotes and dollar-quoted bodies are copied through to where they end.
+			open := string(c)
+			if c == '$' {
+				if open = dollarQuoteRegex.FindString(sql[i:]); open == "" {
+					cur.WriteByte(c)
+					continue
+				}
+			}
+			end := len(sql)
+			if j := strings.Index(sql[i+len(open):], open); j >= 0 {
+				end = i + len(open) + j + len(open)
+			}
+			cur.WriteString(sql[i:end])
+			i = end - 1
+		case c == ';':
+			flush()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	flush()
+	return statements
+}
+
+var dollarQuoteRegex = regexp.MustCompile(`^\$\w*\$`)
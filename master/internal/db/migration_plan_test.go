@@ -0,0 +1,85 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitSQLStatements(t *testing.T) {
+	sql := `-- a comment; with a semicolon
+CREATE TABLE a (x text DEFAULT 'a;b');
+/* another; comment */ CREATE FUNCTION f() RETURNS trigger AS $$
+BEGIN
+  UPDATE a SET x = 'y';
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+UPDATE "b;c" SET x = 1`
+
+	statements := splitSQLStatements(sql)
+	require.Len(t, statements, 3)
+	require.Equal(t, "CREATE TABLE a (x text DEFAULT 'a;b')", statements[0])
+	require.Contains(t, statements[1], "RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql")
+	require.Equal(t, `UPDATE "b;c" SET x = 1`, statements[2])
+}
+
+func TestAnalyzeMigrationSQL(t *testing.T) {
+	statements := analyzeMigrationSQL(`
+CREATE TABLE new_table (id int);
+CREATE INDEX ix_raw_steps_trial_id ON raw_steps(trial_id);
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS ix_trials_id ON public.trials USING btree (id);
+ALTER TABLE experiments ADD COLUMN notes text;
+ALTER TABLE ONLY trials ALTER COLUMN id SET DATA TYPE bigint;
+ALTER TABLE trials ADD CONSTRAINT fk FOREIGN KEY (x) REFERENCES y(id) NOT VALID;
+ALTER TABLE trials VALIDATE CONSTRAINT fk;
+ALTER TABLE trials ALTER COLUMN x SET NOT NULL;
+UPDATE checkpoints_v2 SET size = 0 WHERE size IS NULL;
+INSERT INTO cluster_id VALUES ('a');
+DROP INDEX CONCURRENTLY ix_old;
+LOCK TABLE allocations IN SHARE ROW EXCLUSIVE MODE;
+CREATE TRIGGER autoupdate AFTER INSERT ON tasks FOR EACH ROW EXECUTE PROCEDURE f();
+SELECT 1;`)
+
+	var got []MigrationStatement
+	for _, s := range statements {
+		got = append(got, MigrationStatement{Table: s.Table, Lock: s.Lock, ScansTable: s.ScansTable})
+	}
+	require.Equal(t, []MigrationStatement{
+		{Table: "raw_steps", Lock: LockShare, ScansTable: true},
+		{Table: "trials", Lock: LockShareUpdateExclusive, ScansTable: true},
+		{Table: "experiments", Lock: LockAccessExclusive},
+		{Table: "trials", Lock: LockAccessExclusive, ScansTable: true},
+		{Table: "trials", Lock: LockAccessExclusive},
+		{Table: "trials", Lock: LockShareUpdateExclusive, ScansTable: true},
+		{Table: "trials", Lock: LockAccessExclusive, ScansTable: true},
+		{Table: "checkpoints_v2", Lock: LockRowExclusive, ScansTable: true},
+		{Table: "cluster_id", Lock: LockRowExclusive},
+		{Lock: LockShareUpdateExclusive},
+		{Table: "allocations", Lock: LockShareRowExclusive},
+		{Table: "tasks", Lock: LockShareRowExclusive},
+	}, got)
+	require.Equal(t, "CREATE INDEX ix_raw_steps_trial_id ON raw_steps(trial_id)",
+		statements[0].Statement)
+}
+
+func TestMigrationWarnings(t *testing.T) {
+	m := PlannedMigration{
+		Transactional: true,
+		Statements: analyzeMigrationSQL(`
+CREATE INDEX ix_a ON big(x);
+CREATE INDEX CONCURRENTLY ix_b ON big(x);
+ALTER TABLE big ADD COLUMN y text;
+ALTER TABLE small ALTER COLUMN x TYPE bigint;`),
+	}
+	tables := map[string]TableSize{
+		"big":   {Bytes: 2 * largeTableBytes, Rows: 1000},
+		"small": {Bytes: 1 << 20, Rows: 10},
+	}
+	warnings := migrationWarnings(m, tables)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "blocks writes to big (2.0 GiB, ~1000 rows)")
+
+	m.Transactional = false
+	require.Len(t, migrationWarnings(m, tables), 2)
+}
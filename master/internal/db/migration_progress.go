@@ -0,0 +1,69 @@
+package db
+
+import (
+	"sync"
+	"time"
+)
+
+// Migration states.
+const (
+	MigrationStateRunning   = "running"
+	MigrationStateCompleted = "completed"
+	MigrationStateFailed    = "failed"
+)
+
+// AppliedMigrationProgress is how applying a migration went.
+type AppliedMigrationProgress struct {
+	Version         int64   `json:"version"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	// Attempts is how many times the migration was tried; migrations that time out waiting for
+	// locks in online mode are retried.
+	Attempts int `json:"attempts"`
+}
+
+// MigrationProgress is the progress of the latest run of a phase of migrations by this master.
+type MigrationProgress struct {
+	Phase     string     `json:"phase"`
+	State     string     `json:"state"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Total     int        `json:"total"`
+	// Current is the version of the migration being applied.
+	Current *int64                     `json:"current,omitempty"`
+	Applied []AppliedMigrationProgress `json:"applied"`
+	Error   string                     `json:"error,omitempty"`
+}
+
+var migrationProgress = struct {
+	mu     sync.Mutex
+	phases map[string]*MigrationProgress
+}{phases: map[string]*MigrationProgress{}}
+
+// MigrationStatus returns the progress of each phase of migrations this master has run.
+func MigrationStatus() []MigrationProgress {
+	migrationProgress.mu.Lock()
+	defer migrationProgress.mu.Unlock()
+
+	var progress []MigrationProgress
+	for _, phase := range []string{MigrationPhaseExpand, MigrationPhaseContract} {
+		if p, ok := migrationProgress.phases[phase]; ok {
+			c := *p
+			c.Applied = append([]AppliedMigrationProgress(nil), p.Applied...)
+			progress = append(progress, c)
+		}
+	}
+	return progress
+}
+
+// updateMigrationProgress updates the progress of a phase of migrations.
+func updateMigrationProgress(phase string, update func(p *MigrationProgress)) {
+	migrationProgress.mu.Lock()
+	defer migrationProgress.mu.Unlock()
+
+	p, ok := migrationProgress.phases[phase]
+	if !ok {
+		p = &MigrationProgress{Phase: phase}
+		migrationProgress.phases[phase] = p
+	}
+	update(p)
+}
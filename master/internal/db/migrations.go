@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/go-pg/migrations/v8"
 	"github.com/go-pg/pg/v10"
@@ -15,6 +17,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
 )
 
 func makeGoPgOpts(dbURL string) (*pg.Options, error) {
@@ -213,9 +216,21 @@ func (db *PgDB) dropDBCode(dbCodeDir string) error {
 // run migrations.
 var testOnlyDBLock func(sql *sqlx.DB) (unlock func())
 
-// Migrate runs the migrations from the specified directory URL.
+// Migrate runs the migrations from the specified directory URL, contract migrations included.
 func (db *PgDB) Migrate(
 	migrationURL string, dbCodeDir string, actions []string,
+) error {
+	return db.migrate(migrationURL, dbCodeDir, actions, true)
+}
+
+// MigrateExpand runs the migrations from the specified directory URL, leaving the contract
+// migrations for MigrateContract to run once the master is serving.
+func (db *PgDB) MigrateExpand(migrationURL string, dbCodeDir string) error {
+	return db.migrate(migrationURL, dbCodeDir, []string{"up"}, false)
+}
+
+func (db *PgDB) migrate(
+	migrationURL string, dbCodeDir string, actions []string, contract bool,
 ) error {
 	if testOnlyDBLock != nil {
 		// In integration tests, multiple processes can be running this code at once, which can lead to
@@ -265,22 +280,30 @@ func (db *PgDB) Migrate(
 
 	log.Infof("running DB migrations from %s; this might take a while...", migrationURL)
 
-	re := regexp.MustCompile(`file://(.+)`)
-	match := re.FindStringSubmatch(migrationURL)
-	if len(match) != 2 {
-		return fmt.Errorf("failed to parse migrationsURL: %s", migrationURL)
+	dir, err := migrationsDir(migrationURL)
+	if err != nil {
+		return err
 	}
 
 	collection := migrations.NewCollection()
 	collection.DisableSQLAutodiscover(true)
-	if err = collection.DiscoverSQLMigrations(match[1]); err != nil {
+	if err = collection.DiscoverSQLMigrations(dir); err != nil {
 		return err
 	}
 	if len(collection.Migrations()) == 0 {
 		return errors.New("failed to discover any migrations")
 	}
 
-	oldVersion, newVersion, err := collection.Run(pgConn, actions...)
+	// Anything but a plain "up" is passed through to go-pg/migrations, like from the migrate
+	// command of the master.
+	up := len(actions) == 0 || (len(actions) == 1 && actions[0] == "up")
+	var oldVersion, newVersion int64
+	if up {
+		db.logMigrationPlan(migrationURL)
+		oldVersion, newVersion, err = runMigrations(MigrationPhaseExpand, pgConn, collection, false)
+	} else {
+		oldVersion, newVersion, err = collection.Run(pgConn, actions...)
+	}
 	if err != nil {
 		return errors.Wrap(err, "error applying migrations")
 	}
@@ -302,6 +325,176 @@ func (db *PgDB) Migrate(
 		}
 	}
 
+	if up && contract {
+		if err := db.migrateContract(pgConn, dir); err != nil {
+			return err
+		}
+	}
+
 	log.Info("DB migrations completed")
 	return nil
 }
+
+// logMigrationPlan logs what the pending migrations will lock before they are applied, so that
+// long upgrades can be told apart from stuck ones.
+func (db *PgDB) logMigrationPlan(migrationURL string) {
+	plan, err := db.PlanMigrations(context.TODO(), migrationURL)
+	if err != nil {
+		log.WithError(err).Warn("failed to plan migrations")
+		return
+	}
+	if len(plan.Pending) == 0 {
+		return
+	}
+	log.Infof("%d migrations to apply", len(plan.Pending))
+	for _, w := range plan.Warnings() {
+		log.Warn(w)
+	}
+}
+
+const (
+	// onlineMigrationLockTimeout is how long statements of contract migrations run alongside a
+	// serving master wait for locks. Statements waiting for a lock block every query that comes
+	// after them, so they give up quickly and are retried.
+	onlineMigrationLockTimeout = 5 * time.Second
+	maxOnlineMigrationAttempts = 10
+	onlineMigrationRetryDelay  = 10 * time.Second
+	// codeLockNotAvailable is the error code Postgres uses when a lock_timeout expires.
+	codeLockNotAvailable = "55P03"
+)
+
+// MigrateContract runs the contract migrations, which remove what the previous version of the
+// master used, alongside a master that is serving.
+func (db *PgDB) MigrateContract(migrationURL string) error {
+	dir, err := migrationsDir(migrationURL)
+	if err != nil {
+		return err
+	}
+	pgOpts, err := makeGoPgOpts(db.URL)
+	if err != nil {
+		return err
+	}
+	pgOpts.OnConnect = func(ctx context.Context, cn *pg.Conn) error {
+		_, err := cn.ExecContext(ctx, fmt.Sprintf("SET lock_timeout = %d",
+			onlineMigrationLockTimeout.Milliseconds()))
+		return err
+	}
+	pgConn := pg.Connect(pgOpts)
+	defer func() {
+		if errd := pgConn.Close(); errd != nil {
+			log.Errorf("error closing pg connection: %s", errd)
+		}
+	}()
+	return db.migrateContract(pgConn, dir)
+}
+
+func (db *PgDB) migrateContract(pgConn *pg.DB, dir string) error {
+	collection := migrations.NewCollection().SetTableName(contractMigrationsTable)
+	collection.DisableSQLAutodiscover(true)
+	if err := collection.DiscoverSQLMigrations(filepath.Join(dir, contractMigrationsDir)); err != nil {
+		return err
+	}
+	if len(collection.Migrations()) == 0 {
+		return nil
+	}
+	if _, _, err := collection.Run(pgConn, "init"); err != nil {
+		return errors.Wrap(err, "error creating contract migrations table")
+	}
+	oldVersion, newVersion, err := runMigrations(MigrationPhaseContract, pgConn, collection, true)
+	if err != nil {
+		return errors.Wrap(err, "error applying contract migrations")
+	}
+	if oldVersion != newVersion {
+		log.Infof("applied contract migrations from %d to %d", oldVersion, newVersion)
+	}
+	return nil
+}
+
+// runMigrations applies the pending migrations of a collection one at a time, tracking their
+// progress. With retryLocks set, transactional migrations that time out waiting for a lock are
+// retried.
+func runMigrations(
+	phase string, pgConn *pg.DB, collection *migrations.Collection, retryLocks bool,
+) (oldVersion, newVersion int64, err error) {
+	if oldVersion, err = collection.Version(pgConn); err != nil {
+		return 0, 0, err
+	}
+	var pending []*migrations.Migration
+	for _, m := range collection.Migrations() {
+		if m.Version > oldVersion {
+			pending = append(pending, m)
+		}
+	}
+
+	updateMigrationProgress(phase, func(p *MigrationProgress) {
+		*p = MigrationProgress{
+			Phase:     phase,
+			State:     MigrationStateRunning,
+			StartedAt: time.Now(),
+			Total:     len(pending),
+		}
+	})
+	defer func() {
+		updateMigrationProgress(phase, func(p *MigrationProgress) {
+			now := time.Now()
+			p.EndedAt, p.Current, p.State = &now, nil, MigrationStateCompleted
+			if err != nil {
+				p.State, p.Error = MigrationStateFailed, err.Error()
+			}
+		})
+	}()
+
+	newVersion = oldVersion
+	for _, m := range pending {
+		version := m.Version
+		updateMigrationProgress(phase, func(p *MigrationProgress) { p.Current = &version })
+
+		start := time.Now()
+		attempts := 0
+		for {
+			attempts++
+			_, newVersion, err = collection.Run(pgConn, "up", strconv.FormatInt(version, 10))
+			var pgErr pg.Error
+			if err == nil || !retryLocks || !m.UpTx || attempts >= maxOnlineMigrationAttempts ||
+				!errors.As(err, &pgErr) || pgErr.Field('C') != codeLockNotAvailable {
+				break
+			}
+			log.WithError(err).Warnf("%s migration %d timed out waiting for a lock, retrying",
+				phase, version)
+			time.Sleep(onlineMigrationRetryDelay)
+		}
+		if err != nil {
+			return oldVersion, newVersion, errors.Wrapf(err, "applying migration %d", version)
+		}
+
+		duration := time.Since(start)
+		log.Debugf("applied %s migration %d in %s", phase, version, duration)
+		updateMigrationProgress(phase, func(p *MigrationProgress) {
+			p.Applied = append(p.Applied, AppliedMigrationProgress{
+				Version:         version,
+				DurationSeconds: duration.Seconds(),
+				Attempts:        attempts,
+			})
+		})
+	}
+	return oldVersion, newVersion, nil
+}
+
+// MigrationVersion is a database migration that has been applied.
+type MigrationVersion struct {
+	bun.BaseModel `bun:"table:gopg_migrations"`
+	Version       int64     `bun:"version" json:"version"`
+	CreatedAt     time.Time `bun:"created_at" json:"created_at"`
+}
+
+// MigrationVersions returns the most recently applied database migrations, newest first.
+func MigrationVersions(ctx context.Context, limit int) ([]MigrationVersion, error) {
+	versions := []MigrationVersion{}
+	if err := Bun().NewSelect().Model(&versions).
+		Order("id DESC").
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting migration versions: %w", err)
+	}
+	return versions, nil
+}
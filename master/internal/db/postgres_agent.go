@@ -3,8 +3,10 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 )
@@ -44,3 +46,41 @@ UPDATE agent_stats SET end_time = greatest(cluster_heartbeat, start_time) FROM c
 WHERE end_time IS NULL`)
 	return err
 }
+
+// SetAgentClockOffset records how far ahead of an agent's clock the master's clock is.
+func SetAgentClockOffset(ctx context.Context, agentID string, offset time.Duration) error {
+	_, err := Bun().NewRaw(`
+INSERT INTO agent_clock_offsets (agent_id, offset_ms, measured_at)
+VALUES (?, ?, NOW())
+ON CONFLICT (agent_id) DO UPDATE SET offset_ms = EXCLUDED.offset_ms, measured_at = NOW()
+`, agentID, offset.Milliseconds()).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting clock offset of agent %s: %w", agentID, err)
+	}
+	return nil
+}
+
+// AgentClockOffsets returns the last clock offsets recorded for the given agents. Agents without
+// one are left out.
+func AgentClockOffsets(ctx context.Context, agentIDs []string) (map[string]time.Duration, error) {
+	offsets := make(map[string]time.Duration, len(agentIDs))
+	if len(agentIDs) == 0 {
+		return offsets, nil
+	}
+
+	var rows []struct {
+		AgentID  string `bun:"agent_id"`
+		OffsetMS int64  `bun:"offset_ms"`
+	}
+	if err := Bun().NewSelect().
+		Table("agent_clock_offsets").
+		Column("agent_id", "offset_ms").
+		Where("agent_id IN (?)", bun.In(agentIDs)).
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("getting agent clock offsets: %w", err)
+	}
+	for _, r := range rows {
+		offsets[r.AgentID] = time.Duration(r.OffsetMS) * time.Millisecond
+	}
+	return offsets, nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -16,6 +17,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/db/bunutils"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/schemas"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
@@ -455,7 +457,9 @@ func AddExperimentTx(
 		// No-op SET for external_experiment_id is required for `RETURNING` clause to work.
 		q = q.On("CONFLICT (external_experiment_id) DO UPDATE").
 			Set("external_experiment_id = EXCLUDED.external_experiment_id").
-			Set("config = EXCLUDED.config")
+			Set("config = EXCLUDED.config").
+			Set("config_hash = EXCLUDED.config_hash").
+			Set("model_definition_hash = EXCLUDED.model_definition_hash")
 		// TODO(ilia): do something with the job we've already created.
 		// Option A) make jobs nullable/optional for unmanaged experiments.
 		// Option B) just delete it.
@@ -473,8 +477,9 @@ func ExperimentByID(ctx context.Context, expID int) (*model.Experiment, error) {
 	var experiment model.Experiment
 
 	if err := Bun().NewRaw(`
-SELECT e.id, state, config, start_time, end_time, archived,
-	   owner_id, notes, job_id, u.username as username, project_id, unmanaged, external_experiment_id
+SELECT e.id, state, config, config_hash, model_definition_hash, git_commit, git_remote, start_time,
+	   end_time, archived, owner_id, notes, job_id, u.username as username, project_id, unmanaged,
+	   external_experiment_id
 FROM experiments e
 JOIN users u ON (e.owner_id = u.id)
 WHERE e.id = ?`, expID).Scan(ctx, &experiment); err != nil {
@@ -668,11 +673,16 @@ WHERE unmanaged = false AND state IN (
 // FailDeletingExperiment finds all experiments that were deleting when the master crashed and moves
 // them to DELETE_FAILED.
 func (db *PgDB) FailDeletingExperiment() error {
-	if _, err := db.sql.Exec(`
-UPDATE experiments
-SET state = 'DELETE_FAILED'
-WHERE state = 'DELETING'
-`); err != nil {
+	ctx := context.TODO()
+	var ids []int
+	if err := Bun().NewSelect().Table("experiments").Column("id").
+		Where("state = ?", model.DeletingState).
+		Scan(ctx, &ids); err != nil {
+		return errors.Wrap(err, "finding deleting experiments")
+	}
+	if _, err := TransitionExperimentStates(
+		ctx, ids, model.DeleteFailedState, "master restarted while deleting",
+	); err != nil {
 		return errors.Wrap(err, "failing deleting experiments")
 	}
 	return nil
@@ -686,45 +696,37 @@ func (db *PgDB) TerminateExperimentInRestart(id int, state model.State) error {
 		return errors.Errorf("state %v is not a terminal state", state)
 	}
 
-	now := time.Now().UTC()
-
-	tx, err := db.sql.Begin()
-	if err != nil {
-		return errors.Wrap(err, "starting transaction")
-	}
-	defer func() {
-		if tx == nil {
-			return
+	ctx := context.TODO()
+	var ts []model.ExperimentStateTransition
+	if err := Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Terminate trials.
+		if _, err := tx.NewUpdate().Table("runs").
+			Set("state = ?", state).
+			Set("end_time = ?", time.Now().UTC()).
+			Where("experiment_id = ?", id).
+			Where("end_time IS NULL").
+			Exec(ctx); err != nil {
+			return errors.Wrap(err, "terminating trials of a stopping experiment")
 		}
 
-		if rErr := tx.Rollback(); rErr != nil {
-			log.Errorf("during rollback: %v", rErr)
+		// Terminate experiment.
+		var err error
+		if ts, err = TransitionExperimentStatesTx(
+			ctx, tx, []int{id}, state, "terminated on master restart",
+		); err != nil {
+			return errors.Wrap(err, "terminating a stopping experiment")
 		}
-	}()
-
-	// Terminate trials.
-	if _, err = tx.Exec(
-		`UPDATE runs SET state=$1, end_time=$2 WHERE experiment_id=$3 and end_time IS NULL`,
-		state,
-		now,
-		id,
-	); err != nil {
-		return errors.Wrap(err, "terminating trials of a stopping experiment")
-	}
-
-	// Terminate experiment.
-	if _, err = tx.Exec(
-		`UPDATE experiments SET state=$1, end_time=$2, progress=NULL WHERE id=$3`, state, now, id,
-	); err != nil {
-		return errors.Wrap(err, "terminating a stopping experiment")
-	}
-
-	if err = tx.Commit(); err != nil {
+		if _, err := tx.NewUpdate().Table("experiments").
+			Set("progress = NULL").
+			Where("id = ?", id).
+			Exec(ctx); err != nil {
+			return errors.Wrap(err, "terminating a stopping experiment")
+		}
+		return nil
+	}); err != nil {
 		return errors.Wrapf(err, "committing termination of stopping experiment %v", id)
 	}
-
-	tx = nil
-
+	EmitExperimentStateTransitions(ctx, ts)
 	return nil
 }
 
@@ -738,32 +740,147 @@ WHERE id = $2`
 	return err
 }
 
-// SaveExperimentState saves the current experiment state to the database.
-func (db *PgDB) SaveExperimentState(experiment *model.Experiment) error {
-	query := `
-UPDATE experiments
-SET state=:state, end_time=:end_time
-WHERE id = :id`
-	return db.namedExecOne(query, experiment)
+// TransitionExperimentState changes the state of an experiment with TransitionExperimentStates. It
+// returns the state the experiment had, and false if it already had the new state.
+func TransitionExperimentState(
+	ctx context.Context, id int, to model.State, reason string,
+) (model.State, bool, error) {
+	ts, err := TransitionExperimentStates(ctx, []int{id}, to, reason)
+	if err != nil {
+		return "", false, err
+	}
+	if len(ts) == 0 {
+		return to, false, nil
+	}
+	return *ts[0].FromState, true, nil
 }
 
-// TrySaveExperimentState saves the current experiment state to the database and
-// returns if we successfully changed the state or not.
-func (db *PgDB) TrySaveExperimentState(experiment *model.Experiment) error {
-	var newState, oldState model.State
-	if err := db.sql.QueryRowx(`
-UPDATE experiments e
-SET state=$2
-FROM (SELECT state FROM experiments WHERE id = $1 FOR UPDATE) old
-WHERE e.id = $1
-RETURNING e.state, old.state
-`, experiment.ID, experiment.State).Scan(&newState, &oldState); err != nil {
-		return errors.Wrap(err, "updating experiment state")
-	}
-	if newState == oldState {
-		return fmt.Errorf("could not transition experiment")
+// TransitionExperimentStates changes the state of experiments in one transaction with
+// TransitionExperimentStatesTx, then emits the changes to the handlers registered with
+// OnExperimentStateTransition.
+func TransitionExperimentStates(
+	ctx context.Context, ids []int, to model.State, reason string,
+) ([]model.ExperimentStateTransition, error) {
+	var ts []model.ExperimentStateTransition
+	if err := Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		ts, err = TransitionExperimentStatesTx(ctx, tx, ids, to, reason)
+		return err
+	}); err != nil {
+		return nil, err
 	}
-	return nil
+	EmitExperimentStateTransitions(ctx, ts)
+	return ts, nil
+}
+
+// TransitionExperimentStatesTx changes the state of experiments if ExperimentTransitions allows it
+// from the states they have in the database, which it locks so concurrent transitions, like a pause
+// and a kill, are checked one after the other. If any of the transitions isn't allowed, none are
+// made. end_time is set if the experiments move to a terminal state and cleared if they move back
+// to a running or stopping state. Each transition is recorded in the state history of its experiment
+// with reason. It returns the transitions it made, skipping experiments that already had the new
+// state. Callers must emit the transitions with EmitExperimentStateTransitions once tx commits.
+func TransitionExperimentStatesTx(
+	ctx context.Context, tx bun.IDB, ids []int, to model.State, reason string,
+) ([]model.ExperimentStateTransition, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var rows []struct {
+		ID    int
+		State model.State
+	}
+	if err := tx.NewSelect().Table("experiments").Column("id", "state").
+		Where("id IN (?)", bun.In(ids)).
+		Order("id").
+		For("UPDATE").
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("locking experiments to transition to %s: %w", to, err)
+	}
+	if len(rows) < len(ids) {
+		return nil, fmt.Errorf("transitioning experiments to %s: %w", to, ErrNotFound)
+	}
+
+	now := time.Now().UTC()
+	var ts []model.ExperimentStateTransition
+	var changed []int
+	for _, r := range rows {
+		if r.State == to {
+			continue
+		}
+		if !model.ExperimentTransitions[r.State][to] {
+			return nil, fmt.Errorf("transitioning experiment %d to %s: %w", r.ID, to,
+				model.IllegalTransitionError{ID: r.ID, From: r.State, To: to})
+		}
+		ts = append(ts, model.ExperimentStateTransition{
+			ExperimentID:   r.ID,
+			FromState:      ptrs.Ptr(r.State),
+			ToState:        to,
+			Reason:         reason,
+			TransitionedAt: now,
+		})
+		changed = append(changed, r.ID)
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.NewRaw("SELECT set_config('determined.experiment_state_reason', ?, true)",
+		reason).Exec(ctx); err != nil {
+		return nil, err
+	}
+	q := tx.NewUpdate().Table("experiments").
+		Set("state = ?", to).
+		Where("id IN (?)", bun.In(changed))
+	switch {
+	case model.TerminalStates[to]:
+		q = q.Set("end_time = ?", now)
+	case !model.DeletingStates[to]:
+		q = q.Set("end_time = NULL")
+	}
+	if _, err := q.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("transitioning experiments to %s: %w", to, err)
+	}
+	return ts, nil
+}
+
+var (
+	experimentStateTransitionHandlersMu sync.RWMutex
+	experimentStateTransitionHandlers   []func(context.Context, model.ExperimentStateTransition)
+)
+
+// OnExperimentStateTransition registers a handler that is called with every change of the state of
+// an experiment made through TransitionExperimentStates, after the change is committed.
+func OnExperimentStateTransition(handler func(context.Context, model.ExperimentStateTransition)) {
+	experimentStateTransitionHandlersMu.Lock()
+	defer experimentStateTransitionHandlersMu.Unlock()
+	experimentStateTransitionHandlers = append(experimentStateTransitionHandlers, handler)
+}
+
+// EmitExperimentStateTransitions calls the handlers registered with OnExperimentStateTransition
+// with each of the transitions.
+func EmitExperimentStateTransitions(ctx context.Context, ts []model.ExperimentStateTransition) {
+	experimentStateTransitionHandlersMu.RLock()
+	defer experimentStateTransitionHandlersMu.RUnlock()
+	for _, t := range ts {
+		for _, handler := range experimentStateTransitionHandlers {
+			handler(ctx, t)
+		}
+	}
+}
+
+// ExperimentStateHistory returns the changes of the state of an experiment, oldest first.
+func ExperimentStateHistory(
+	ctx context.Context, id int,
+) ([]model.ExperimentStateTransition, error) {
+	history := []model.ExperimentStateTransition{}
+	if err := Bun().NewSelect().Model(&history).
+		Where("experiment_id = ?", id).
+		Order("id").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting state history of experiment %d: %w", id, err)
+	}
+	return history, nil
 }
 
 // SaveExperimentArchiveStatus saves the current experiment archive status to the database.
@@ -827,6 +944,43 @@ EXISTS(
 	return exists, err
 }
 
+// BestCheckpoint is the completed checkpoint of an experiment with the best searcher metric.
+type BestCheckpoint struct {
+	UUID           string  `bun:"uuid"`
+	TrialID        int     `bun:"trial_id"`
+	StepsCompleted int     `bun:"steps_completed"`
+	SearcherMetric float64 `bun:"searcher_metric"`
+}
+
+// ExperimentBestCheckpoint returns the completed checkpoint of an experiment with the best value of
+// its searcher metric, or nil if no completed checkpoint has a value of it.
+func ExperimentBestCheckpoint(
+	ctx context.Context, id int, smallerIsBetter bool,
+) (*BestCheckpoint, error) {
+	order := "searcher_metric DESC"
+	if smallerIsBetter {
+		order = "searcher_metric ASC"
+	}
+	var best BestCheckpoint
+	err := Bun().NewSelect().
+		TableExpr("checkpoints_view").
+		ColumnExpr("uuid::text AS uuid").
+		Column("trial_id", "steps_completed", "searcher_metric").
+		Where("experiment_id = ?", id).
+		Where("state = ?", model.CompletedState).
+		Where("searcher_metric IS NOT NULL").
+		Where("searcher_metric != 'NaN'").
+		OrderExpr(order).
+		Limit(1).
+		Scan(ctx, &best)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting best checkpoint of experiment %d: %w", id, err)
+	}
+	return &best, nil
+}
+
 // SaveExperimentProgress stores the progress for an experiment in the database.
 func (db *PgDB) SaveExperimentProgress(id int, progress *float64) error {
 	if progress != nil && (*progress < 0 || *progress > 1) {
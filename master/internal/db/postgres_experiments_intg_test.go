@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -329,6 +330,122 @@ func TestTerminateExperimentInRestart(t *testing.T) {
 	}
 }
 
+func TestTransitionExperimentState(t *testing.T) {
+	ctx := context.Background()
+
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+	user := RequireMockUser(t, db)
+	exp := RequireMockExperiment(t, db, user)
+
+	from, changed, err := TransitionExperimentState(ctx, exp.ID, model.PausedState, "paused by user")
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, model.ActiveState, from)
+
+	_, changed, err = TransitionExperimentState(ctx, exp.ID, model.PausedState, "paused again")
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// Completing needs the experiment to stop first.
+	_, _, err = TransitionExperimentState(ctx, exp.ID, model.CompletedState, "")
+	require.ErrorAs(t, err, &model.IllegalTransitionError{})
+
+	_, _, err = TransitionExperimentState(ctx, exp.ID, model.StoppingCompletedState, "searcher done")
+	require.NoError(t, err)
+	_, _, err = TransitionExperimentState(ctx, exp.ID, model.CompletedState, "trials exited")
+	require.NoError(t, err)
+	actualExp, err := ExperimentByID(ctx, exp.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.CompletedState, actualExp.State)
+	require.NotNil(t, actualExp.EndTime)
+
+	// Concurrent transitions are checked one after the other, so only one of these succeeds.
+	exp = RequireMockExperiment(t, db, user)
+	errs := make(chan error, 2)
+	for _, to := range []model.State{model.StoppingKilledState, model.StoppingCompletedState} {
+		go func() {
+			_, _, err := TransitionExperimentState(ctx, exp.ID, to, "")
+			errs <- err
+		}()
+	}
+	failed := 0
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			require.ErrorAs(t, err, &model.IllegalTransitionError{})
+			failed++
+		}
+	}
+	require.Equal(t, 1, failed)
+
+	history, err := ExperimentStateHistory(ctx, exp.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Nil(t, history[0].FromState)
+	require.Equal(t, model.ActiveState, history[0].ToState)
+	require.Equal(t, model.ActiveState, *history[1].FromState)
+}
+
+func TestTransitionExperimentStates(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+	user := RequireMockUser(t, db)
+
+	var mu sync.Mutex
+	var emitted []model.ExperimentStateTransition
+	OnExperimentStateTransition(func(_ context.Context, t model.ExperimentStateTransition) {
+		mu.Lock()
+		defer mu.Unlock()
+		emitted = append(emitted, t)
+	})
+
+	exp0 := RequireMockExperiment(t, db, user)
+	exp1 := RequireMockExperiment(t, db, user)
+	ts, err := TransitionExperimentStates(ctx, []int{exp0.ID, exp1.ID}, model.PausedState, "paused in bulk")
+	require.NoError(t, err)
+	require.Len(t, ts, 2)
+	mu.Lock()
+	require.Len(t, emitted, 2)
+	mu.Unlock()
+	for _, tr := range ts {
+		require.Equal(t, model.ActiveState, *tr.FromState)
+		require.Equal(t, model.PausedState, tr.ToState)
+		require.Equal(t, "paused in bulk", tr.Reason)
+	}
+
+	// One illegal transition rolls back the whole batch.
+	_, _, err = TransitionExperimentState(ctx, exp1.ID, model.StoppingCompletedState, "")
+	require.NoError(t, err)
+	_, err = TransitionExperimentStates(ctx, []int{exp0.ID, exp1.ID}, model.ActiveState, "")
+	require.ErrorAs(t, err, &model.IllegalTransitionError{})
+	actualExp, err := ExperimentByID(ctx, exp0.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.PausedState, actualExp.State)
+
+	_, err = TransitionExperimentStates(ctx, []int{exp0.ID, -1}, model.ActiveState, "")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Experiments left deleting when the master restarts fail their deletion.
+	exp2 := RequireMockExperiment(t, db, user)
+	_, _, err = TransitionExperimentState(ctx, exp2.ID, model.StoppingCanceledState, "")
+	require.NoError(t, err)
+	_, _, err = TransitionExperimentState(ctx, exp2.ID, model.CanceledState, "")
+	require.NoError(t, err)
+	_, _, err = TransitionExperimentState(ctx, exp2.ID, model.DeletingState, "")
+	require.NoError(t, err)
+	require.NoError(t, db.FailDeletingExperiment())
+	history, err := ExperimentStateHistory(ctx, exp2.ID)
+	require.NoError(t, err)
+	last := history[len(history)-1]
+	require.Equal(t, model.DeleteFailedState, last.ToState)
+	require.Equal(t, "master restarted while deleting", last.Reason)
+}
+
 func TestExperimentsTrialAndTaskIDs(t *testing.T) {
 	ctx := context.Background()
 
@@ -1,12 +1,20 @@
 package db
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/jmoiron/sqlx"
+	"github.com/uptrace/bun"
 
 	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/jsondelta"
 )
 
-// ExperimentSnapshot returns the snapshot for the specified experiment.
+// ExperimentSnapshot returns the snapshot for the specified experiment, with any deltas saved
+// since it was last compacted applied.
 func (db *PgDB) ExperimentSnapshot(experimentID int) ([]byte, int, error) {
 	ret := struct {
 		Version int    `db:"version"`
@@ -22,14 +30,43 @@ LIMIT 1`, &ret, experimentID); errors.Cause(err) == ErrNotFound {
 	} else if err != nil {
 		return nil, 0, errors.Wrapf(err, "error querying for experiment snapshot (%d)", experimentID)
 	}
-	return ret.Content, ret.Version, nil
+
+	var deltas []struct {
+		Version int    `db:"version"`
+		Content []byte `db:"content"`
+	}
+	if err := db.queryRows(`
+SELECT version, content
+FROM experiment_snapshot_deltas
+WHERE experiment_id = $1
+ORDER BY id`, &deltas, experimentID); err != nil {
+		return nil, 0, errors.Wrapf(err, "error querying for experiment snapshot deltas (%d)",
+			experimentID)
+	}
+	content := ret.Content
+	for _, d := range deltas {
+		// The first save after a restore is always a full snapshot, so deltas are never applied
+		// across versions.
+		if d.Version != ret.Version {
+			return nil, 0, fmt.Errorf("experiment snapshot (%d) is version %d but has a delta of "+
+				"version %d", experimentID, ret.Version, d.Version)
+		}
+		var err error
+		if content, err = jsondelta.Apply(content, d.Content); err != nil {
+			return nil, 0, errors.Wrapf(err, "failed to apply experiment snapshot delta (%d)",
+				experimentID)
+		}
+	}
+	return content, ret.Version, nil
 }
 
-// SaveSnapshot saves a searcher and trial snapshot together.
+// SaveSnapshot saves a searcher and trial snapshot together, replacing any deltas saved since
+// the last one.
 func (db *PgDB) SaveSnapshot(
 	experimentID int, version int, experimentSnapshot []byte,
 ) error {
-	if _, err := db.sql.Exec(`
+	return db.withTransaction("save snapshot", func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(`
 INSERT INTO experiment_snapshots (experiment_id, content, version)
 VALUES ($1, $2, $3)
 ON CONFLICT (experiment_id)
@@ -37,7 +74,23 @@ DO UPDATE SET
   updated_at = now(),
   content = EXCLUDED.content,
   version = EXCLUDED.version`, experimentID, experimentSnapshot, version); err != nil {
-		return errors.Wrap(err, "failed to upsert experiment snapshot")
+			return errors.Wrap(err, "failed to upsert experiment snapshot")
+		}
+		if _, err := tx.Exec(`
+DELETE FROM experiment_snapshot_deltas
+WHERE experiment_id = $1`, experimentID); err != nil {
+			return errors.Wrap(err, "failed to compact experiment snapshot deltas")
+		}
+		return nil
+	})
+}
+
+// SaveSnapshotDelta saves the changes to the snapshot of an experiment since it was last saved.
+func (db *PgDB) SaveSnapshotDelta(experimentID int, version int, delta []byte) error {
+	if _, err := db.sql.Exec(`
+INSERT INTO experiment_snapshot_deltas (experiment_id, content, version)
+VALUES ($1, $2, $3)`, experimentID, delta, version); err != nil {
+		return errors.Wrap(err, "failed to insert experiment snapshot delta")
 	}
 	return nil
 }
@@ -50,6 +103,11 @@ func (db *PgDB) DeleteSnapshotsForExperiment(experimentID int) error {
 func (db *PgDB) deleteSnapshotsForExperiment(experimentID int) func(tx *sqlx.Tx) error {
 	return func(tx *sqlx.Tx) error {
 		if _, err := tx.Exec(`
+DELETE FROM experiment_snapshot_deltas
+WHERE experiment_id = $1`, experimentID); err != nil {
+			return errors.Wrap(err, "failed to delete experiment snapshot deltas")
+		}
+		if _, err := tx.Exec(`
 DELETE FROM experiment_snapshots
 WHERE experiment_id = $1`, experimentID); err != nil {
 			return errors.Wrap(err, "failed to delete experiment snapshots")
@@ -61,13 +119,52 @@ WHERE experiment_id = $1`, experimentID); err != nil {
 // DeleteSnapshotsForTerminalExperiments deletes all snapshots for
 // terminal state experiments from the database.
 func (db *PgDB) DeleteSnapshotsForTerminalExperiments() error {
-	if _, err := db.sql.Exec(`
-DELETE FROM experiment_snapshots
+	for _, table := range []string{"experiment_snapshot_deltas", "experiment_snapshots"} {
+		if _, err := db.sql.Exec(fmt.Sprintf(`
+DELETE FROM %s
 WHERE experiment_id IN (
 	SELECT id
 	FROM experiments
-	WHERE state IN ('COMPLETED', 'CANCELED', 'ERROR'))`); err != nil {
-		return errors.Wrap(err, "failed to delete experiment snapshots")
+	WHERE state IN ('COMPLETED', 'CANCELED', 'ERROR'))`, table)); err != nil {
+			return errors.Wrapf(err, "failed to delete %s", table)
+		}
 	}
 	return nil
 }
+
+// ExperimentSnapshotSize is how much space the snapshot of an experiment takes.
+type ExperimentSnapshotSize struct {
+	bun.BaseModel `bun:"table:experiment_snapshots,alias:s"`
+	ExperimentID  int       `bun:"experiment_id" json:"experiment_id"`
+	Version       int       `bun:"version" json:"version"`
+	UpdatedAt     time.Time `bun:"updated_at" json:"updated_at"`
+	// BaseBytes is the size of the snapshot as of when it was last compacted.
+	BaseBytes int64 `bun:"base_bytes" json:"base_bytes"`
+	// DeltaCount and DeltaBytes are the number and total size of the deltas saved since.
+	DeltaCount int   `bun:"delta_count" json:"delta_count"`
+	DeltaBytes int64 `bun:"delta_bytes" json:"delta_bytes"`
+}
+
+// ExperimentSnapshotSizes returns the sizes of the largest experiment snapshots, largest first.
+func ExperimentSnapshotSizes(ctx context.Context, limit int) ([]ExperimentSnapshotSize, error) {
+	sizes := []ExperimentSnapshotSize{}
+	deltas := Bun().NewSelect().
+		Table("experiment_snapshot_deltas").
+		Column("experiment_id").
+		ColumnExpr("count(*) AS delta_count").
+		ColumnExpr("sum(octet_length(content::text)) AS delta_bytes").
+		Group("experiment_id")
+	if err := Bun().NewSelect().Model(&sizes).
+		With("deltas", deltas).
+		Column("s.experiment_id", "s.version", "s.updated_at").
+		ColumnExpr("octet_length(s.content::text) AS base_bytes").
+		ColumnExpr("coalesce(d.delta_count, 0) AS delta_count").
+		ColumnExpr("coalesce(d.delta_bytes, 0) AS delta_bytes").
+		Join("LEFT JOIN deltas d ON d.experiment_id = s.experiment_id").
+		OrderExpr("octet_length(s.content::text) + coalesce(d.delta_bytes, 0) DESC").
+		Limit(limit).
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting experiment snapshot sizes: %w", err)
+	}
+	return sizes, nil
+}
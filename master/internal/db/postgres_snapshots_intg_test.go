@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+)
+
+func TestExperimentSnapshotDeltas(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+
+	user := RequireMockUser(t, db)
+	exp := RequireMockExperiment(t, db, user)
+
+	require.NoError(t, db.SaveSnapshot(exp.ID, 6, []byte(`{"a": 1, "b": {"c": 2}}`)))
+	require.NoError(t, db.SaveSnapshotDelta(exp.ID, 6,
+		[]byte(`{"a": {"delete": true}, "b": {"patch": {"d": {"set": null}}}}`)))
+	require.NoError(t, db.SaveSnapshotDelta(exp.ID, 6, []byte(`{"e": {"set": [1]}}`)))
+
+	snapshot, version, err := db.ExperimentSnapshot(exp.ID)
+	require.NoError(t, err)
+	require.Equal(t, 6, version)
+	require.JSONEq(t, `{"b": {"c": 2, "d": null}, "e": [1]}`, string(snapshot))
+
+	sizes, err := ExperimentSnapshotSizes(ctx, 10000)
+	require.NoError(t, err)
+	var found bool
+	for _, s := range sizes {
+		if s.ExperimentID == exp.ID {
+			found = true
+			require.Equal(t, 2, s.DeltaCount)
+			require.Positive(t, s.BaseBytes)
+			require.Positive(t, s.DeltaBytes)
+		}
+	}
+	require.True(t, found)
+
+	// Saving a full snapshot compacts the deltas.
+	require.NoError(t, db.SaveSnapshot(exp.ID, 6, []byte(`{"f": 3}`)))
+	snapshot, _, err = db.ExperimentSnapshot(exp.ID)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"f": 3}`, string(snapshot))
+
+	// Deltas are never applied across snapshot versions.
+	require.NoError(t, db.SaveSnapshotDelta(exp.ID, 5, []byte(`{"g": {"set": 4}}`)))
+	_, _, err = db.ExperimentSnapshot(exp.ID)
+	require.ErrorContains(t, err, "has a delta of version 5")
+
+	require.NoError(t, db.DeleteSnapshotsForExperiment(exp.ID))
+	snapshot, _, err = db.ExperimentSnapshot(exp.ID)
+	require.NoError(t, err)
+	require.Nil(t, snapshot)
+}
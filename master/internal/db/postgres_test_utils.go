@@ -272,6 +272,31 @@ func RequireMockProjectID(t *testing.T, db *PgDB, workspaceID int, archived bool
 	return mockProject.ID, mockProject.Name
 }
 
+// RequireMockWorkspaceRole assigns a role to a user on a workspace, through a group of its own.
+func RequireMockWorkspaceRole(t *testing.T, db *PgDB, userID model.UserID, workspaceID, roleID int) {
+	ctx := context.TODO()
+	scope := &model.RoleAssignmentScope{
+		WorkspaceID: sql.NullInt32{Int32: int32(workspaceID), Valid: true},
+	}
+	_, err := Bun().NewInsert().Model(scope).Returning("id").Exec(ctx)
+	require.NoError(t, err)
+
+	group := &model.Group{Name: uuid.New().String()}
+	_, err = Bun().NewInsert().Model(group).Returning("id").Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = Bun().NewInsert().Model(&model.GroupMembership{UserID: userID, GroupID: group.ID}).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = Bun().NewInsert().Model(&map[string]interface{}{
+		"group_id": group.ID,
+		"role_id":  roleID,
+		"scope_id": scope.ID,
+	}).Table("role_assignments").Exec(ctx)
+	require.NoError(t, err)
+}
+
 // RequireGetProjectHParams returns projects hparams.
 func RequireGetProjectHParams(t *testing.T, db *PgDB, projectID int) []string {
 	p := struct {
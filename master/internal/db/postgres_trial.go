@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/set"
 	"github.com/determined-ai/determined/proto/pkg/trialv1"
 )
@@ -1083,3 +1085,101 @@ func TrialByExperimentAndRequestID(
 	}
 	return &t, nil
 }
+
+// TrialIDsByRequestID returns the IDs of an experiment's trials keyed by searcher request ID.
+func TrialIDsByRequestID(ctx context.Context, experimentID int) (map[model.RequestID]int, error) {
+	var rows []struct {
+		ID        int
+		RequestID model.RequestID
+	}
+	if err := Bun().NewSelect().Table("trials").
+		Column("id", "request_id").
+		Where("experiment_id = ?", experimentID).
+		Where("request_id IS NOT NULL").
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("error querying for trials of experiment %d: %w", experimentID, err)
+	}
+	ids := make(map[model.RequestID]int, len(rows))
+	for _, r := range rows {
+		ids[r.RequestID] = r.ID
+	}
+	return ids, nil
+}
+
+// AddTrialResize records a change in the number of slots an elastic trial runs with.
+func AddTrialResize(ctx context.Context, resize *model.TrialResize) error {
+	if _, err := Bun().NewInsert().Model(resize).Exec(ctx); err != nil {
+		return fmt.Errorf("adding resize of trial %d: %w", resize.TrialID, err)
+	}
+	return nil
+}
+
+// TrialResizesByTrialID returns the slot count changes of an elastic trial, oldest first.
+func TrialResizesByTrialID(ctx context.Context, trialID int) ([]model.TrialResize, error) {
+	resizes := []model.TrialResize{}
+	if err := Bun().NewSelect().Model(&resizes).
+		Where("trial_id = ?", trialID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting resizes of trial %d: %w", trialID, err)
+	}
+	return resizes, nil
+}
+
+// AddTrialFailure records a failed allocation of a trial.
+func AddTrialFailure(ctx context.Context, failure *model.TrialFailure) error {
+	if _, err := Bun().NewInsert().Model(failure).Exec(ctx); err != nil {
+		return fmt.Errorf("adding failure of trial %d: %w", failure.TrialID, err)
+	}
+	return nil
+}
+
+// TrialFailuresByTrialID returns the failed allocations of a trial, oldest first.
+func TrialFailuresByTrialID(ctx context.Context, trialID int) ([]model.TrialFailure, error) {
+	failures := []model.TrialFailure{}
+	if err := Bun().NewSelect().Model(&failures).
+		Where("trial_id = ?", trialID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting failures of trial %d: %w", trialID, err)
+	}
+	return failures, nil
+}
+
+// TrialInfraRestarts returns how many times a trial was restarted after infrastructure failures.
+func TrialInfraRestarts(ctx context.Context, trialID int) (int, error) {
+	n, err := Bun().NewSelect().Model((*model.TrialFailure)(nil)).
+		Where("trial_id = ?", trialID).
+		Where("class = ?", model.InfraFailure).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("counting infrastructure failures of trial %d: %w", trialID, err)
+	}
+	return n, nil
+}
+
+// SetTrialPriority records the priority of a trial among the trials of its experiment.
+func SetTrialPriority(ctx context.Context, priority *model.TrialPriority) error {
+	priority.UpdatedAt = ptrs.Ptr(time.Now().UTC())
+	if _, err := Bun().NewInsert().Model(priority).
+		On("CONFLICT (trial_id) DO UPDATE").
+		Set("priority = EXCLUDED.priority").
+		Set("set_by = EXCLUDED.set_by").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("setting priority of trial %d: %w", priority.TrialID, err)
+	}
+	return nil
+}
+
+// TrialPriorityByTrialID returns the priority of a trial, or nil if none was set.
+func TrialPriorityByTrialID(ctx context.Context, trialID int) (*model.TrialPriority, error) {
+	var priority model.TrialPriority
+	err := Bun().NewSelect().Model(&priority).Where("trial_id = ?", trialID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting priority of trial %d: %w", trialID, err)
+	}
+	return &priority, nil
+}
@@ -135,7 +135,11 @@ func Setup(
 		}
 	}
 
-	err = db.Migrate(opts.Migrations, opts.ViewsAndTriggers, []string{"up"})
+	if opts.OnlineMigrations {
+		err = db.MigrateExpand(opts.Migrations, opts.ViewsAndTriggers)
+	} else {
+		err = db.Migrate(opts.Migrations, opts.ViewsAndTriggers, []string{"up"})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error running migrations: %s", err)
 	}
@@ -0,0 +1,68 @@
+// Package egress routes the requests the master makes to services outside the cluster through the
+// proxy configured for them, so every subsystem reaches the outside the same way.
+package egress
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+var (
+	mu sync.RWMutex
+	// proxy returns the proxy for a URL, or is nil to use the proxy set by the environment.
+	proxy func(*url.URL) (*url.URL, error)
+
+	client = &http.Client{Transport: Transport()} //nolint:forbidigo
+)
+
+// Configure sets the proxy requests to services outside the cluster go through.
+func Configure(c config.EgressProxyConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !c.Enabled() {
+		proxy = nil
+		return
+	}
+	httpsProxy := c.HTTPSProxy
+	if httpsProxy == "" {
+		httpsProxy = c.HTTPProxy
+	}
+	proxy = (&httpproxy.Config{
+		HTTPProxy:  c.HTTPProxy,
+		HTTPSProxy: httpsProxy,
+		NoProxy:    strings.Join(c.NoProxy, ","),
+	}).ProxyFunc()
+}
+
+// Proxy returns the proxy for a request, or nil if it goes to its destination directly. It is
+// meant for the Proxy of a transport.
+func Proxy(req *http.Request) (*url.URL, error) {
+	mu.RLock()
+	p := proxy
+	mu.RUnlock()
+	if p == nil {
+		return http.ProxyFromEnvironment(req)
+	}
+	return p(req.URL)
+}
+
+// Transport returns a new transport for requests to services outside the cluster, for callers
+// that customize it, e.g. with their own TLS config.
+func Transport() *http.Transport { //nolint:forbidigo
+	t := cleanhttp.DefaultPooledTransport()
+	t.Proxy = Proxy
+	return t
+}
+
+// Client returns the client shared by requests to services outside the cluster. Callers must not
+// modify it.
+func Client() *http.Client { //nolint:forbidigo
+	return client
+}
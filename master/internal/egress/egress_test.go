@@ -0,0 +1,76 @@
+package egress
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+func proxyFor(t *testing.T, rawURL string) string {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	require.NoError(t, err)
+	u, err := Proxy(req)
+	require.NoError(t, err)
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func TestProxy(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	Configure(config.EgressProxyConfig{})
+	require.Equal(t, "http://env-proxy:3128", proxyFor(t, "https://registry-1.docker.io/v2/"))
+
+	Configure(config.EgressProxyConfig{
+		HTTPProxy: "socks5://proxy:1080",
+		NoProxy:   []string{"internal.example.com", "10.0.0.0/8", "hooks.example.com:8443"},
+	})
+	defer Configure(config.EgressProxyConfig{})
+	for rawURL, expected := range map[string]string{
+		"https://registry-1.docker.io/v2/":       "socks5://proxy:1080",
+		"http://api.segment.io/v1/batch":         "socks5://proxy:1080",
+		"https://registry.internal.example.com/": "",
+		"https://10.1.2.3/hook":                  "",
+		"https://hooks.example.com:8443/hook":    "",
+		"https://hooks.example.com/hook":         "socks5://proxy:1080",
+	} {
+		require.Equal(t, expected, proxyFor(t, rawURL), rawURL)
+	}
+}
+
+func TestDoJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("internal details"))
+			return
+		}
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		_, _ = w.Write([]byte(`{"echo": "` + body["msg"] + `"}`))
+	}))
+	defer srv.Close()
+
+	auth := func(req *http.Request) { req.Header.Set("Authorization", "Bearer token") }
+	var out struct {
+		Echo string `json:"echo"`
+	}
+	require.NoError(t, DoJSON(context.Background(), srv.Client(), http.MethodPost, srv.URL, auth,
+		map[string]string{"msg": "hi"}, &out))
+	require.Equal(t, "hi", out.Echo)
+
+	// Error responses aren't passed on to callers.
+	err := DoJSON(context.Background(), srv.Client(), http.MethodGet, srv.URL, nil, nil, nil)
+	var se *StatusError
+	require.ErrorAs(t, err, &se)
+	require.Equal(t, http.StatusUnauthorized, se.StatusCode)
+	require.NotContains(t, err.Error(), "internal details")
+}
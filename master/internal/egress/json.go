@@ -0,0 +1,67 @@
+package egress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxJSONResponseSize caps how much of a response DoJSON reads.
+const maxJSONResponseSize = 1 << 20
+
+// StatusError is returned when an external service responds with an error status. It leaves out
+// the body of the response, since errors are shown to users and the URLs they came from may be set
+// by users.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request returned %d", e.StatusCode)
+}
+
+// DoJSON sends body, if any, as JSON and decodes the response into out, if any. A non-nil auth is
+// called to authenticate the request before it is sent.
+func DoJSON(
+	ctx context.Context, cl *http.Client, //nolint:forbidigo
+	method, url string, auth func(*http.Request), body, out any,
+) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth != nil {
+		auth(req)
+	}
+
+	resp, err := cl.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJSONResponseSize)).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package envvarsets manages named sets of environment variables of workspaces, which task configs
+// refer to by name and which the master sets in a task's containers when it starts.
+package envvarsets
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// reservedPrefix starts the environment variables the master sets for tasks itself.
+const reservedPrefix = "DET_"
+
+var (
+	namePattern     = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	variablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ValidateSet returns an error if a set can't be saved as it is.
+func ValidateSet(s *model.WorkspaceEnvVarSet) error {
+	if !namePattern.MatchString(s.Name) {
+		return fmt.Errorf("name %q must start with a letter or digit and contain only letters, "+
+			"digits, '_', '.' and '-'", s.Name)
+	}
+	if len(s.Variables) == 0 {
+		return fmt.Errorf("set %s must have at least one variable", s.Name)
+	}
+	for k := range s.Variables {
+		switch {
+		case !variablePattern.MatchString(k):
+			return fmt.Errorf("%q isn't a valid environment variable name", k)
+		case strings.HasPrefix(strings.ToUpper(k), reservedPrefix):
+			return fmt.Errorf("%s is reserved: variables starting with %s are set by the master",
+				k, reservedPrefix)
+		}
+	}
+	return nil
+}
+
+// Merge returns the variables of sets in order, so variables of later sets override those of
+// earlier ones.
+func Merge(sets []model.WorkspaceEnvVarSet) map[string]string {
+	vars := map[string]string{}
+	for _, s := range sets {
+		for k, v := range s.Variables {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// MissingError is returned when a config refers to sets its workspace doesn't have.
+type MissingError struct {
+	Workspace string
+	Names     []string
+}
+
+func (e MissingError) Error() string {
+	return fmt.Sprintf("workspace %s has no environment variable sets named %s",
+		e.Workspace, strings.Join(e.Names, ", "))
+}
+
+// ordered returns the sets in the order of their names, or a MissingError.
+func ordered(workspace string, names []string, sets []model.WorkspaceEnvVarSet) (
+	[]model.WorkspaceEnvVarSet, error,
+) {
+	var missing []string
+	res := make([]model.WorkspaceEnvVarSet, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(sets, func(s model.WorkspaceEnvVarSet) bool { return s.Name == name })
+		if i < 0 {
+			missing = append(missing, name)
+			continue
+		}
+		res = append(res, sets[i])
+	}
+	if len(missing) > 0 {
+		return nil, MissingError{Workspace: workspace, Names: missing}
+	}
+	return res, nil
+}
@@ -0,0 +1,42 @@
+package envvarsets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestValidateSet(t *testing.T) {
+	valid := func(name string, vars map[string]string) error {
+		return ValidateSet(&model.WorkspaceEnvVarSet{Name: name, Variables: vars})
+	}
+	require.NoError(t, valid("proxy", map[string]string{"HTTPS_PROXY": "http://proxy:3128"}))
+	require.NoError(t, valid("datasets.v2", map[string]string{"_DATA": ""}))
+	require.ErrorContains(t, valid("-proxy", map[string]string{"A": "b"}), "name")
+	require.ErrorContains(t, valid("a b", map[string]string{"A": "b"}), "name")
+	require.ErrorContains(t, valid("proxy", nil), "at least one variable")
+	require.ErrorContains(t, valid("proxy", map[string]string{"1A": "b"}), "valid environment")
+	require.ErrorContains(t, valid("proxy", map[string]string{"A=B": "b"}), "valid environment")
+	require.ErrorContains(t, valid("proxy", map[string]string{"det_master": "x"}), "reserved")
+}
+
+func TestOrderedAndMerge(t *testing.T) {
+	sets := []model.WorkspaceEnvVarSet{
+		{Name: "proxy", Variables: map[string]string{"HTTPS_PROXY": "a", "NO_PROXY": "b"}},
+		{Name: "override", Variables: map[string]string{"NO_PROXY": "c"}},
+	}
+
+	sorted, err := ordered("ws", []string{"proxy", "override"}, sets)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"HTTPS_PROXY": "a", "NO_PROXY": "c"}, Merge(sorted))
+
+	sorted, err = ordered("ws", []string{"override", "proxy"}, sets)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"HTTPS_PROXY": "a", "NO_PROXY": "b"}, Merge(sorted))
+
+	_, err = ordered("ws", []string{"proxy", "datasets", "s3"}, sets)
+	require.Equal(t, MissingError{Workspace: "ws", Names: []string{"datasets", "s3"}}, err)
+	require.EqualError(t, err, "workspace ws has no environment variable sets named datasets, s3")
+}
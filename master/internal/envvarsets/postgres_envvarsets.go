@@ -0,0 +1,83 @@
+package envvarsets
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Sets returns the environment variable sets of a workspace.
+func Sets(ctx context.Context, workspaceID int) ([]model.WorkspaceEnvVarSet, error) {
+	sets := []model.WorkspaceEnvVarSet{}
+	if err := db.Bun().NewSelect().Model(&sets).
+		Where("workspace_id = ?", workspaceID).
+		Order("name").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting environment variable sets of workspace %d: %w", workspaceID, err)
+	}
+	return sets, nil
+}
+
+// PutSet creates a set, or replaces the variables and description of the workspace's set with the
+// same name. Tasks that already started keep the variables they started with.
+func PutSet(ctx context.Context, s *model.WorkspaceEnvVarSet) error {
+	if err := ValidateSet(s); err != nil {
+		return err
+	}
+	if _, err := db.Bun().NewInsert().Model(s).
+		ExcludeColumn("id", "updated_at").
+		On("CONFLICT (workspace_id, name) DO UPDATE").
+		Set("variables = EXCLUDED.variables").
+		Set("description = EXCLUDED.description").
+		Set("updated_by = EXCLUDED.updated_by").
+		Set("updated_at = NOW()").
+		Returning("id, updated_at").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("saving environment variable set %s of workspace %d: %w",
+			s.Name, s.WorkspaceID, err)
+	}
+	return nil
+}
+
+// DeleteSet deletes a set of a workspace. It returns db.ErrNotFound if the workspace has no such
+// set. Tasks that refer to it fail to start afterwards.
+func DeleteSet(ctx context.Context, workspaceID int, name string) error {
+	res, err := db.Bun().NewDelete().Model((*model.WorkspaceEnvVarSet)(nil)).
+		Where("workspace_id = ?", workspaceID).
+		Where("name = ?", name).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting environment variable set %s: %w", name, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// Resolve returns the variables of the named sets of a workspace, with those of later sets
+// overriding those of earlier ones, or a MissingError if the workspace lacks any of them.
+func Resolve(ctx context.Context, workspaceID int, names []string) (map[string]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var sets []model.WorkspaceEnvVarSet
+	if err := db.Bun().NewSelect().Model(&sets).
+		Where("workspace_id = ?", workspaceID).
+		Where("name IN (?)", bun.In(names)).
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting environment variable sets of workspace %d: %w", workspaceID, err)
+	}
+	sorted, err := ordered(strconv.Itoa(workspaceID), names, sets)
+	if err != nil {
+		return nil, err
+	}
+	return Merge(sorted), nil
+}
@@ -0,0 +1,89 @@
+//go:build integration
+// +build integration
+
+package envvarsets
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestSets(t *testing.T) {
+	ctx := context.Background()
+	pgDB := db.SingleDB()
+	user := db.RequireMockUser(t, pgDB)
+	workspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	otherWorkspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+
+	proxy := model.WorkspaceEnvVarSet{
+		WorkspaceID: workspaceID,
+		Name:        "proxy",
+		Variables:   map[string]string{"HTTPS_PROXY": "http://old:3128"},
+		UpdatedBy:   user.ID,
+	}
+	require.NoError(t, PutSet(ctx, &proxy))
+	datasets := model.WorkspaceEnvVarSet{
+		WorkspaceID: workspaceID,
+		Name:        "datasets",
+		Variables:   map[string]string{"DATASET_ENDPOINT": "s3://bucket", "NO_PROXY": "s3"},
+		UpdatedBy:   user.ID,
+	}
+	require.NoError(t, PutSet(ctx, &datasets))
+
+	// Putting a set with the same name replaces it.
+	proxy.Variables = map[string]string{"HTTPS_PROXY": "http://new:3128", "NO_PROXY": "local"}
+	require.NoError(t, PutSet(ctx, &proxy))
+	sets, err := Sets(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Len(t, sets, 2)
+	require.Equal(t, "datasets", sets[0].Name)
+	require.Equal(t, proxy.Variables, sets[1].Variables)
+
+	vars, err := Resolve(ctx, workspaceID, []string{"datasets", "proxy"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"DATASET_ENDPOINT": "s3://bucket",
+		"HTTPS_PROXY":      "http://new:3128",
+		"NO_PROXY":         "local",
+	}, vars)
+	vars, err = Resolve(ctx, workspaceID, []string{"proxy", "datasets"})
+	require.NoError(t, err)
+	require.Equal(t, "s3", vars["NO_PROXY"])
+
+	// Sets of other workspaces can't be referred to.
+	_, err = Resolve(ctx, otherWorkspaceID, []string{"proxy"})
+	require.ErrorAs(t, err, &MissingError{})
+
+	require.NoError(t, DeleteSet(ctx, workspaceID, "proxy"))
+	require.ErrorIs(t, DeleteSet(ctx, workspaceID, "proxy"), db.ErrNotFound)
+	_, err = Resolve(ctx, workspaceID, []string{"proxy", "datasets"})
+	require.Equal(t, MissingError{Workspace: strconv.Itoa(workspaceID), Names: []string{"proxy"}}, err)
+}
@@ -0,0 +1,128 @@
+// Package expdefaults applies the experiment defaults of projects to the configs of experiments
+// created in them, and explains which setting each of those values came from.
+package expdefaults
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// Source is the setting a value of an experiment's config came from.
+type Source string
+
+const (
+	// SourceProjectForced is a resource pool the project forces its experiments to run in.
+	SourceProjectForced Source = "project_forced"
+	// SourceConfig is the experiment's config.
+	SourceConfig Source = "config"
+	// SourceTemplate is the template the experiment was created with.
+	SourceTemplate Source = "template"
+	// SourceProject is the project's experiment defaults.
+	SourceProject Source = "project"
+	// SourceDefault is the defaults of the resource pool, workspace or master.
+	SourceDefault Source = "default"
+)
+
+// Precedence describes, from highest to lowest, the settings an experiment's config is resolved
+// from.
+var Precedence = []string{
+	"invariant configs of the workspace's task config policies",
+	"the resource pool forced by the project",
+	"the experiment config",
+	"the template the experiment is created with",
+	"the project's experiment defaults",
+	"the defaults of the resource pool and workspace, such as the workspace's default compute " +
+		"pool, the pool's scheduler priority and the workspace's checkpoint storage",
+	"the master's defaults",
+}
+
+// Validate returns an error if defaults are invalid.
+func Validate(d *model.ProjectExperimentDefaults) error {
+	if d.ResourcePool != nil && strings.TrimSpace(*d.ResourcePool) == "" {
+		return fmt.Errorf("resource_pool must not be empty")
+	}
+	if d.ForceResourcePool && d.ResourcePool == nil {
+		return fmt.Errorf("force_resource_pool requires resource_pool")
+	}
+	if d.Priority != nil && (*d.Priority < 1 || *d.Priority > 99) {
+		return fmt.Errorf("priority must be between 1 and 99; got %d", *d.Priority)
+	}
+	if d.CheckpointPolicy != nil {
+		switch *d.CheckpointPolicy {
+		case "best", "all", "none":
+		default:
+			return fmt.Errorf("checkpoint_policy must be best, all or none; got %q",
+				*d.CheckpointPolicy)
+		}
+	}
+	return nil
+}
+
+// Apply fills in the values of config that the project's defaults set, and overwrites its
+// resource pool if the project forces one.
+func Apply(config *expconf.ExperimentConfig, d *model.ProjectExperimentDefaults) {
+	if config.RawResources == nil && (d.ResourcePool != nil || d.Priority != nil) {
+		config.RawResources = &expconf.ResourcesConfig{}
+	}
+	if d.ResourcePool != nil && (d.ForceResourcePool || config.RawResources.RawResourcePool == nil) {
+		pool := *d.ResourcePool
+		config.RawResources.RawResourcePool = &pool
+	}
+	if d.Priority != nil && config.RawResources.RawPriority == nil {
+		priority := *d.Priority
+		config.RawResources.RawPriority = &priority
+	}
+	if d.CheckpointPolicy != nil && config.RawCheckpointPolicy == nil {
+		policy := *d.CheckpointPolicy
+		config.RawCheckpointPolicy = &policy
+	}
+}
+
+// Sources returns the source of each of the values that project defaults can set, keyed by their
+// path in the config, given the config as the user wrote it and the template it's created with.
+func Sources(
+	config expconf.ExperimentConfig, template *expconf.ExperimentConfig,
+	d *model.ProjectExperimentDefaults,
+) map[string]Source {
+	var tmpl expconf.ExperimentConfig
+	if template != nil {
+		tmpl = *template
+	}
+	source := func(inConfig, inTemplate, inProject bool) Source {
+		switch {
+		case inConfig:
+			return SourceConfig
+		case inTemplate:
+			return SourceTemplate
+		case inProject:
+			return SourceProject
+		default:
+			return SourceDefault
+		}
+	}
+
+	pool := source(
+		config.RawResources != nil && config.RawResources.RawResourcePool != nil,
+		tmpl.RawResources != nil && tmpl.RawResources.RawResourcePool != nil,
+		d.ResourcePool != nil,
+	)
+	if d.ForceResourcePool {
+		pool = SourceProjectForced
+	}
+	return map[string]Source{
+		"resources.resource_pool": pool,
+		"resources.priority": source(
+			config.RawResources != nil && config.RawResources.RawPriority != nil,
+			tmpl.RawResources != nil && tmpl.RawResources.RawPriority != nil,
+			d.Priority != nil,
+		),
+		"checkpoint_policy": source(
+			config.RawCheckpointPolicy != nil,
+			tmpl.RawCheckpointPolicy != nil,
+			d.CheckpointPolicy != nil,
+		),
+	}
+}
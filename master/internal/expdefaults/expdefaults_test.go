@@ -0,0 +1,92 @@
+package expdefaults
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(&model.ProjectExperimentDefaults{}))
+	require.NoError(t, Validate(&model.ProjectExperimentDefaults{
+		ResourcePool:      ptrs.Ptr("gpu"),
+		ForceResourcePool: true,
+		Priority:          ptrs.Ptr(10),
+		CheckpointPolicy:  ptrs.Ptr("all"),
+	}))
+
+	for _, bad := range []model.ProjectExperimentDefaults{
+		{ResourcePool: ptrs.Ptr(" ")},
+		{ForceResourcePool: true},
+		{Priority: ptrs.Ptr(0)},
+		{Priority: ptrs.Ptr(100)},
+		{CheckpointPolicy: ptrs.Ptr("latest")},
+	} {
+		require.Error(t, Validate(&bad), "%+v", bad)
+	}
+}
+
+func TestApply(t *testing.T) {
+	d := &model.ProjectExperimentDefaults{
+		ResourcePool:     ptrs.Ptr("gpu"),
+		Priority:         ptrs.Ptr(10),
+		CheckpointPolicy: ptrs.Ptr("none"),
+	}
+
+	config := expconf.ExperimentConfig{}
+	Apply(&config, d)
+	require.Equal(t, "gpu", *config.RawResources.RawResourcePool)
+	require.Equal(t, 10, *config.RawResources.RawPriority)
+	require.Equal(t, "none", *config.RawCheckpointPolicy)
+
+	config = expconf.ExperimentConfig{
+		RawResources: &expconf.ResourcesConfig{
+			RawResourcePool: ptrs.Ptr("cpu"),
+			RawPriority:     ptrs.Ptr(42),
+		},
+		RawCheckpointPolicy: ptrs.Ptr("best"),
+	}
+	Apply(&config, d)
+	require.Equal(t, "cpu", *config.RawResources.RawResourcePool)
+	require.Equal(t, 42, *config.RawResources.RawPriority)
+	require.Equal(t, "best", *config.RawCheckpointPolicy)
+
+	d.ForceResourcePool = true
+	Apply(&config, d)
+	require.Equal(t, "gpu", *config.RawResources.RawResourcePool)
+
+	// Applying no defaults leaves the config as it is.
+	config = expconf.ExperimentConfig{}
+	Apply(&config, &model.ProjectExperimentDefaults{})
+	require.Nil(t, config.RawResources)
+	require.Nil(t, config.RawCheckpointPolicy)
+}
+
+func TestSources(t *testing.T) {
+	config := expconf.ExperimentConfig{
+		RawResources: &expconf.ResourcesConfig{RawResourcePool: ptrs.Ptr("cpu")},
+	}
+	template := &expconf.ExperimentConfig{
+		RawResources: &expconf.ResourcesConfig{RawPriority: ptrs.Ptr(42)},
+	}
+	d := &model.ProjectExperimentDefaults{CheckpointPolicy: ptrs.Ptr("none")}
+
+	require.Equal(t, map[string]Source{
+		"resources.resource_pool": SourceConfig,
+		"resources.priority":      SourceTemplate,
+		"checkpoint_policy":       SourceProject,
+	}, Sources(config, template, d))
+
+	require.Equal(t, map[string]Source{
+		"resources.resource_pool": SourceDefault,
+		"resources.priority":      SourceDefault,
+		"checkpoint_policy":       SourceDefault,
+	}, Sources(expconf.ExperimentConfig{}, nil, &model.ProjectExperimentDefaults{}))
+
+	d = &model.ProjectExperimentDefaults{ResourcePool: ptrs.Ptr("gpu"), ForceResourcePool: true}
+	require.Equal(t, SourceProjectForced, Sources(config, nil, d)["resources.resource_pool"])
+}
@@ -0,0 +1,40 @@
+package expdefaults
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Defaults returns the experiment defaults of a project. Projects that were never configured have
+// no defaults.
+func Defaults(ctx context.Context, projectID int) (*model.ProjectExperimentDefaults, error) {
+	d := &model.ProjectExperimentDefaults{ProjectID: projectID}
+	err := db.Bun().NewSelect().Model(d).WherePK().Scan(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting experiment defaults of project %d: %w", projectID, err)
+	}
+	return d, nil
+}
+
+// SetDefaults sets the experiment defaults of a project.
+func SetDefaults(ctx context.Context, d *model.ProjectExperimentDefaults) error {
+	if err := Validate(d); err != nil {
+		return err
+	}
+	_, err := db.Bun().NewInsert().Model(d).
+		On("CONFLICT (project_id) DO UPDATE").
+		Set("resource_pool = EXCLUDED.resource_pool").
+		Set("force_resource_pool = EXCLUDED.force_resource_pool").
+		Set("priority = EXCLUDED.priority").
+		Set("checkpoint_policy = EXCLUDED.checkpoint_policy").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting experiment defaults of project %d: %w", d.ProjectID, err)
+	}
+	return nil
+}
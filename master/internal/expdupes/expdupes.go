@@ -0,0 +1,114 @@
+// Package expdupes detects experiments submitted to a workspace that already has an identical
+// experiment, and warns about or blocks them according to the workspace's policy.
+package expdupes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+// MaxDuplicates is the most duplicates of an experiment that are reported.
+const MaxDuplicates = 20
+
+// DatasetMetadataKey is the key of the metadata of experiments that names the dataset they train
+// on, such as its name and version, which identical experiments share.
+const DatasetMetadataKey = "dataset"
+
+// Policy is what happens when an experiment is submitted to a workspace that already has an
+// identical experiment.
+type Policy string
+
+const (
+	// PolicyOff doesn't look for duplicates.
+	PolicyOff Policy = ""
+	// PolicyWarn creates the experiment, and warns about its duplicates.
+	PolicyWarn Policy = "warn"
+	// PolicyBlock doesn't create the experiment.
+	PolicyBlock Policy = "block"
+)
+
+// PolicyFromProto returns the policy a protobuf policy names.
+func PolicyFromProto(p workspacev1.DuplicateExperimentPolicy) (Policy, error) {
+	switch p {
+	case workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_OFF:
+		return PolicyOff, nil
+	case workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_WARN:
+		return PolicyWarn, nil
+	case workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_BLOCK:
+		return PolicyBlock, nil
+	default:
+		return PolicyOff, fmt.Errorf("unknown duplicate experiment policy %s; known policies are "+
+			"off, warn and block", p)
+	}
+}
+
+// Proto returns the policy as a protobuf policy.
+func (p Policy) Proto() workspacev1.DuplicateExperimentPolicy {
+	switch p {
+	case PolicyWarn:
+		return workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_WARN
+	case PolicyBlock:
+		return workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_BLOCK
+	default:
+		return workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_OFF
+	}
+}
+
+// String returns the policy as users write it.
+func (p Policy) String() string {
+	if p == PolicyOff {
+		return "off"
+	}
+	return string(p)
+}
+
+// Duplicate is an existing experiment that is identical to one being submitted.
+type Duplicate struct {
+	ExperimentID int         `json:"experiment_id"`
+	Name         string      `json:"name"`
+	State        model.State `json:"state"`
+	StartTime    time.Time   `json:"start_time"`
+	// URL is the path of the experiment in the WebUI, relative to the master.
+	URL string `json:"url" bun:"-"`
+}
+
+// Proto returns the duplicate as a protobuf message.
+func (d Duplicate) Proto() *experimentv1.DuplicateExperiment {
+	return &experimentv1.DuplicateExperiment{
+		ExperimentId: int32(d.ExperimentID),
+		Name:         d.Name,
+		State:        model.StateToProto(d.State),
+		StartTime:    timestamppb.New(d.StartTime),
+		Url:          d.URL,
+	}
+}
+
+// ExperimentURL returns the path of an experiment in the WebUI, relative to the master.
+func ExperimentURL(experimentID int) string {
+	return fmt.Sprintf("/det/experiments/%d/overview", experimentID)
+}
+
+// DuplicateError is returned when an experiment is submitted to a workspace that blocks
+// duplicates and already has an identical experiment.
+type DuplicateError struct {
+	WorkspaceID int
+	Duplicates  []Duplicate
+}
+
+func (e DuplicateError) Error() string {
+	links := make([]string, 0, len(e.Duplicates))
+	for _, d := range e.Duplicates {
+		links = append(links, fmt.Sprintf("%d (%s)", d.ExperimentID, d.URL))
+	}
+	return fmt.Sprintf("workspace %d blocks duplicate experiments and already has identical "+
+		"experiments: %s; change the experiment's config or model definition, or ask a workspace "+
+		"admin to change the workspace's duplicate experiment policy",
+		e.WorkspaceID, strings.Join(links, ", "))
+}
@@ -0,0 +1,53 @@
+package expdupes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func TestPolicyProto(t *testing.T) {
+	for _, p := range []Policy{PolicyOff, PolicyWarn, PolicyBlock} {
+		got, err := PolicyFromProto(p.Proto())
+		require.NoError(t, err, p)
+		require.Equal(t, p, got)
+	}
+	_, err := PolicyFromProto(workspacev1.DuplicateExperimentPolicy_DUPLICATE_EXPERIMENT_POLICY_UNSPECIFIED)
+	require.Error(t, err)
+	require.Equal(t, "off", PolicyOff.String())
+	require.Equal(t, "block", PolicyBlock.String())
+}
+
+func TestDuplicateProto(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	d := Duplicate{
+		ExperimentID: 12,
+		Name:         "mnist",
+		State:        model.CompletedState,
+		StartTime:    start,
+		URL:          ExperimentURL(12),
+	}
+	pb := d.Proto()
+	require.Equal(t, int32(12), pb.ExperimentId)
+	require.Equal(t, "mnist", pb.Name)
+	require.Equal(t, experimentv1.State_STATE_COMPLETED, pb.State)
+	require.Equal(t, start, pb.StartTime.AsTime())
+	require.Equal(t, "/det/experiments/12/overview", pb.Url)
+}
+
+func TestDuplicateError(t *testing.T) {
+	err := DuplicateError{
+		WorkspaceID: 3,
+		Duplicates: []Duplicate{
+			{ExperimentID: 12, URL: ExperimentURL(12)},
+			{ExperimentID: 7, URL: ExperimentURL(7)},
+		},
+	}
+	require.Contains(t, err.Error(), "workspace 3 blocks duplicate experiments")
+	require.Contains(t, err.Error(), "12 (/det/experiments/12/overview), 7 (/det/experiments/7/overview)")
+}
@@ -0,0 +1,129 @@
+package expdupes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+// ignoredStates are the states of experiments that don't count as duplicates, since submitting
+// an experiment again after it failed or was canceled is expected.
+var ignoredStates = []model.State{
+	model.ErrorState,
+	model.StoppingErrorState,
+	model.CanceledState,
+	model.StoppingCanceledState,
+	model.DeletingState,
+	model.DeleteFailedState,
+	model.DeletedState,
+}
+
+// WorkspacePolicy returns a workspace's duplicate experiment policy.
+func WorkspacePolicy(ctx context.Context, workspaceID int) (Policy, error) {
+	var p sql.NullString
+	err := db.Bun().NewSelect().Table("workspaces").
+		Column("duplicate_experiment_policy").
+		Where("id = ?", workspaceID).
+		Scan(ctx, &p)
+	if errors.Is(err, sql.ErrNoRows) {
+		return PolicyOff, db.ErrNotFound
+	} else if err != nil {
+		return PolicyOff, fmt.Errorf("getting duplicate experiment policy of workspace %d: %w",
+			workspaceID, err)
+	}
+	return Policy(p.String), nil
+}
+
+// SetWorkspacePolicy sets a workspace's duplicate experiment policy.
+func SetWorkspacePolicy(ctx context.Context, workspaceID int, p Policy) error {
+	var value *string
+	if p != PolicyOff {
+		value = (*string)(&p)
+	}
+	res, err := db.Bun().NewUpdate().Table("workspaces").
+		Set("duplicate_experiment_policy = ?", value).
+		Where("id = ?", workspaceID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting duplicate experiment policy of workspace %d: %w", workspaceID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// Find returns the experiments in a workspace that are identical to an experiment with the given
+// metadata, newest first, other than the experiment itself: those with the same config hash and
+// model definition hash, submitted from the same git commit, and with the same dataset in their
+// metadata. The metadata of experiments can change after they are created, so it is compared as it
+// is now. Experiments without a config hash have no duplicates.
+func Find(
+	ctx context.Context, workspaceID int, e *model.Experiment, metadata map[string]interface{},
+) ([]Duplicate, error) {
+	if e.ConfigHash == nil {
+		return nil, nil
+	}
+	var dataset *string
+	if d, ok := metadata[DatasetMetadataKey]; ok && d != nil {
+		b, err := json.Marshal(d)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling dataset metadata: %w", err)
+		}
+		dataset = ptrs.Ptr(string(b))
+	}
+
+	var dups []Duplicate
+	err := db.Bun().NewSelect().
+		TableExpr("experiments AS e").
+		Join("JOIN projects AS p ON p.id = e.project_id").
+		ColumnExpr("e.id AS experiment_id").
+		ColumnExpr("e.config->>'name' AS name").
+		ColumnExpr("e.state").
+		ColumnExpr("e.start_time").
+		Where("p.workspace_id = ?", workspaceID).
+		Where("e.config_hash = ?", *e.ConfigHash).
+		Where("e.model_definition_hash IS NOT DISTINCT FROM ?", e.ModelDefinitionHash).
+		Where("e.git_commit IS NOT DISTINCT FROM ?", e.GitCommit).
+		Where("e.config->'metadata'->? IS NOT DISTINCT FROM ?::jsonb", DatasetMetadataKey, dataset).
+		Where("e.id != ?", e.ID).
+		Where("e.state NOT IN (?)", bun.In(ignoredStates)).
+		OrderExpr("e.id DESC").
+		Limit(MaxDuplicates).
+		Scan(ctx, &dups)
+	if err != nil {
+		return nil, fmt.Errorf("finding duplicates of experiment in workspace %d: %w", workspaceID, err)
+	}
+	for i := range dups {
+		dups[i].URL = ExperimentURL(dups[i].ExperimentID)
+	}
+	return dups, nil
+}
+
+// Check looks for the experiments a workspace already has that are identical to an experiment
+// about to be created in it with the given metadata. It returns a DuplicateError if the workspace
+// blocks duplicates, and the duplicates to warn about if it warns about them.
+func Check(
+	ctx context.Context, workspaceID int, e *model.Experiment, metadata map[string]interface{},
+) ([]Duplicate, error) {
+	p, err := WorkspacePolicy(ctx, workspaceID)
+	if err != nil || p == PolicyOff {
+		return nil, err
+	}
+	dups, err := Find(ctx, workspaceID, e, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if p == PolicyBlock && len(dups) > 0 {
+		return nil, DuplicateError{WorkspaceID: workspaceID, Duplicates: dups}
+	}
+	return dups, nil
+}
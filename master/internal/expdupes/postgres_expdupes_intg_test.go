@@ -0,0 +1,124 @@
+//go:build integration
+// +build integration
+
+package expdupes
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	existing := db.RequireMockExperimentProject(t, db.SingleDB(), user, projectID)
+
+	configHash, modelDefHash, commit := "config-hash", "model-definition-hash", "0123abc"
+	_, err := db.Bun().NewUpdate().Table("experiments").
+		Set("config_hash = ?", configHash).
+		Set("model_definition_hash = ?", modelDefHash).
+		Set("git_commit = ?", commit).
+		Set(`config = jsonb_set(config, '{metadata}', '{"dataset": "d-v1"}')`).
+		Where("id = ?", existing.ID).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	submitted := &model.Experiment{
+		ConfigHash: &configHash, ModelDefinitionHash: &modelDefHash, GitCommit: &commit,
+	}
+	metadata := map[string]interface{}{
+		"ticket":           "T-1",
+		DatasetMetadataKey: "d-v1",
+	}
+
+	// Workspaces don't look for duplicates until they are told to.
+	policy, err := WorkspacePolicy(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Equal(t, PolicyOff, policy)
+	dups, err := Check(ctx, workspaceID, submitted, metadata)
+	require.NoError(t, err)
+	require.Empty(t, dups)
+
+	dups, err = Find(ctx, workspaceID, submitted, metadata)
+	require.NoError(t, err)
+	require.Len(t, dups, 1)
+	require.Equal(t, existing.ID, dups[0].ExperimentID)
+	require.Equal(t, ExperimentURL(existing.ID), dups[0].URL)
+
+	require.NoError(t, SetWorkspacePolicy(ctx, workspaceID, PolicyWarn))
+	dups, err = Check(ctx, workspaceID, submitted, metadata)
+	require.NoError(t, err)
+	require.Len(t, dups, 1)
+
+	require.NoError(t, SetWorkspacePolicy(ctx, workspaceID, PolicyBlock))
+	_, err = Check(ctx, workspaceID, submitted, metadata)
+	require.ErrorAs(t, err, &DuplicateError{})
+
+	// Experiments that run different code, from a different commit or on a different dataset
+	// aren't duplicates.
+	for _, c := range []struct {
+		e        *model.Experiment
+		metadata map[string]interface{}
+	}{
+		{&model.Experiment{
+			ConfigHash: &configHash, ModelDefinitionHash: ptrs.Ptr("changed"), GitCommit: &commit,
+		}, metadata},
+		{&model.Experiment{ConfigHash: &configHash, ModelDefinitionHash: &modelDefHash}, metadata},
+		{submitted, map[string]interface{}{DatasetMetadataKey: "d-v2"}},
+		{submitted, nil},
+	} {
+		dups, err = Check(ctx, workspaceID, c.e, c.metadata)
+		require.NoError(t, err)
+		require.Empty(t, dups)
+	}
+
+	// An experiment isn't a duplicate of itself, nor of experiments that failed.
+	dups, err = Find(ctx, workspaceID, &model.Experiment{
+		ID: existing.ID, ConfigHash: &configHash, ModelDefinitionHash: &modelDefHash,
+		GitCommit: &commit,
+	}, metadata)
+	require.NoError(t, err)
+	require.Empty(t, dups)
+	_, err = db.Bun().NewUpdate().Table("experiments").
+		Set("state = ?", model.ErrorState).
+		Where("id = ?", existing.ID).
+		Exec(ctx)
+	require.NoError(t, err)
+	_, err = Check(ctx, workspaceID, submitted, metadata)
+	require.NoError(t, err)
+
+	require.NoError(t, SetWorkspacePolicy(ctx, workspaceID, PolicyOff))
+	policy, err = WorkspacePolicy(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Equal(t, PolicyOff, policy)
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -16,20 +18,22 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/determined-ai/determined/master/internal/activity"
 	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/batchsize"
 	"github.com/determined-ai/determined/master/internal/checkpoints"
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/configpolicy"
 	internaldb "github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
+	"github.com/determined-ai/determined/master/internal/modeleval"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/telemetry"
 	"github.com/determined-ai/determined/master/internal/user"
-	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/internal/workspace"
 	"github.com/determined-ai/determined/master/pkg/command"
 	"github.com/determined-ai/determined/master/pkg/logger"
@@ -50,6 +54,7 @@ type (
 	experimentState struct {
 		SearcherState      json.RawMessage                                   `json:"searcher_state"`
 		TrialSearcherState map[model.RequestID]experiment.TrialSearcherState `json:"trial_searcher_state"`
+		Improvement        improvementState                                  `json:"improvement"`
 	}
 
 	internalExperiment struct {
@@ -73,6 +78,12 @@ type (
 
 		faultToleranceEnabled bool
 		restored              bool
+		snapshots             snapshotCompactor
+		durationLimit         *time.Timer
+
+		// tunedBatchSize is the batch size tuning chose, which the trials of the experiment use.
+		tunedBatchSize        *int
+		cancelBatchSizeTuning context.CancelFunc
 
 		logCtx logger.Context
 	}
@@ -119,10 +130,13 @@ func newExperiment(
 			ResourcePool: poolName.String(),
 			Slots:        resources.SlotsPerTrial(),
 			IsSingleNode: resources.IsSingleNode() != nil && *resources.IsSingleNode(),
+
+			MinGPUMemoryMiB: minGPUMemory(resources),
+			Scratch:         resources.Scratch(),
 		}); err != nil {
 			return nil, nil, fmt.Errorf("validating resources: %v", err)
 		}
-		if m.config.LaunchError && len(launchWarnings) > 0 {
+		if m.config.LaunchError && slices.Contains(launchWarnings, command.CurrentSlotsExceeded) {
 			return nil, nil, errors.New("slots requested exceeds cluster capacity")
 		}
 	}
@@ -270,6 +284,14 @@ func (e *internalExperiment) start() error {
 	}
 
 	jobservice.DefaultService.RegisterJob(e.JobID, e)
+	e.startDurationLimit()
+	if err := e.loadTunedBatchSize(); err != nil {
+		e.updateState(model.StateWithReason{
+			State:               model.StoppingErrorState,
+			InformationalReason: err.Error(),
+		})
+		return err
+	}
 
 	if e.restored {
 		j, err := internaldb.JobByID(context.TODO(), e.JobID)
@@ -310,7 +332,9 @@ func (e *internalExperiment) start() error {
 		})
 		return err
 	}
-	e.handleSearcherActions(creates, nil)
+	if !e.startBatchSizeTuning(creates) {
+		e.handleSearcherActions(creates, nil)
+	}
 
 	return nil
 }
@@ -328,9 +352,12 @@ func (e *internalExperiment) TrialReportProgress(requestID model.RequestID, msg
 	return nil
 }
 
-func (e *internalExperiment) TrialReportValidation(requestID model.RequestID, metrics map[string]interface{}) error {
+func (e *internalExperiment) TrialReportValidation(
+	requestID model.RequestID, stepsCompleted int, metrics map[string]interface{},
+) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	e.checkImprovementLimit(requestID, stepsCompleted, metrics)
 	ops, err := e.searcher.ValidationCompleted(requestID, metrics)
 	e.handleSearcherActions(ops, err)
 	return nil
@@ -364,6 +391,17 @@ func (e *internalExperiment) PatchTrialState(msg experiment.PatchTrialState) err
 	return nil
 }
 
+func (e *internalExperiment) SetTrialPriority(msg experiment.SetTrialPriority) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ref, ok := e.trials[msg.RequestID]
+	if !ok {
+		return api.AsErrNotFound("trial not found")
+	}
+	return ref.SetPriority(msg.Priority, model.TrialPrioritySetByUser)
+}
+
 func (e *internalExperiment) SetGroupMaxSlots(msg sproto.SetGroupMaxSlots) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -405,6 +443,12 @@ func (e *internalExperiment) SetGroupPriority(priority int) error {
 
 func (e *internalExperiment) stop() error {
 	e.unregister()
+	if e.durationLimit != nil {
+		e.durationLimit.Stop()
+	}
+	if e.cancelBatchSizeTuning != nil {
+		e.cancelBatchSizeTuning()
+	}
 
 	if err := tasklist.GroupPriorityChangeRegistry.Delete(e.JobID); err != nil {
 		e.syslog.WithError(err).Error("failed to remove priority change registry")
@@ -419,21 +463,23 @@ func (e *internalExperiment) stop() error {
 	if state == "" {
 		state = model.ErrorState
 	}
-	if wasPatched, err := e.Transition(state); err != nil {
+	if wasPatched, err := e.transition(model.StateWithReason{
+		State:               state,
+		InformationalReason: "experiment stopped",
+	}); err != nil {
 		return err
 	} else if !wasPatched {
 		return errors.New("experiment is already in a terminal state")
 	}
-	telemetry.ReportExperimentStateChanged(e.db, e.Experiment)
-	if err := webhooks.ReportExperimentStateChanged(
+	if err := activity.RecordExperimentEnded(
 		context.TODO(), *e.Experiment, e.activeConfig,
 	); err != nil {
-		e.syslog.WithError(err).Error("failed to send experiment state change webhook")
+		e.syslog.WithError(err).Warn("failed to record experiment state change activity")
 	}
-
-	if err := e.db.SaveExperimentState(e.Experiment); err != nil {
-		return err
+	if err := modeleval.ExperimentEnded(context.TODO(), e.ID, e.State); err != nil {
+		e.syslog.WithError(err).Error("failed to record model evaluation")
 	}
+
 	e.syslog.Infof("PostStop state changed to %s", e.State)
 
 	taskSpec, err := e.taskSpec.Clone()
@@ -456,6 +502,7 @@ func (e *internalExperiment) stop() error {
 		e.syslog.WithError(err).Errorf(
 			"failure to delete snapshots for experiment: %d", e.Experiment.ID)
 	}
+	e.snapshots = snapshotCompactor{}
 
 	// May be no checkpoints to GC, if so skip. We can do this since we don't want to GC tensorboards.
 	if len(checkpoints) > 0 {
@@ -471,6 +518,12 @@ func (e *internalExperiment) stop() error {
 		}()
 	}
 
+	if hookSpec, err := e.taskSpec.Clone(); err != nil {
+		e.syslog.WithError(err).Error("cloning completion hook task spec")
+	} else if err := e.launchCompletionHook(*hookSpec); err != nil {
+		e.syslog.WithError(err).Error("failed to launch completion hook")
+	}
+
 	if err := user.DeleteSessionByToken(
 		context.TODO(),
 		taskSpec.UserSessionToken,
@@ -546,6 +599,16 @@ func (e *internalExperiment) KillExperiment() error {
 	return nil
 }
 
+func (e *internalExperiment) IntrospectSearcher(
+	trialIDs map[model.RequestID]int,
+) searcher.Introspection {
+	return e.searcher.Introspect(trialIDs)
+}
+
+func (e *internalExperiment) EstimateSearcherProgress() searcher.ProgressEstimate {
+	return e.searcher.EstimateProgress()
+}
+
 func (e *internalExperiment) TrialExited(requestID model.RequestID, reason *model.ExitedReason) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -648,6 +711,10 @@ func (e *internalExperiment) handleSearcherActions(
 			if closed {
 				continue
 			}
+			if e.tunedBatchSize != nil {
+				action.Hparams = withBatchSize(action.Hparams,
+					e.activeConfig.BatchSizeTuning().Hyperparameter(), *e.tunedBatchSize)
+			}
 			state := experiment.TrialSearcherState{Create: action}
 			e.TrialSearcherState[action.RequestID] = state
 
@@ -676,6 +743,15 @@ func (e *internalExperiment) handleSearcherActions(
 			state.EarlyStoppedBySearcher = true
 			e.TrialSearcherState[action.RequestID] = state
 			updatedTrials[action.RequestID] = true
+		case searcher.Prioritize:
+			t, ok := e.trials[action.RequestID]
+			if !ok {
+				e.syslog.Warnf("missing trial %s to prioritize", action.RequestID)
+				continue
+			}
+			if err := t.SetPriority(action.Priority, model.TrialPrioritySetBySearcher); err != nil {
+				e.syslog.WithError(err).Error("setting trial priority")
+			}
 		case searcher.Shutdown:
 			e.syslog.WithField("action", action).Info("searcher shutdown")
 			switch {
@@ -760,6 +836,10 @@ func experimentIDFromTrialTaskID(taskID model.TaskID) (int, error) {
 		Join("LEFT JOIN trials ON trials.id = run_id_task_id.run_id").
 		Where("task_id = ?", taskID).
 		Scan(context.TODO(), &experimentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Batch size probes run like trials of their experiment, without trials of their own.
+		experimentID, err = batchsize.ProbeExperimentID(context.TODO(), taskID)
+	}
 	if errors.Is(err, sql.ErrNoRows) {
 		return 0, errIsNotTrialTaskID
 	} else if err != nil {
@@ -770,26 +850,24 @@ func experimentIDFromTrialTaskID(taskID model.TaskID) (int, error) {
 }
 
 func (e *internalExperiment) updateState(state model.StateWithReason) bool {
-	if wasPatched, err := e.Transition(state.State); err != nil {
+	if wasPatched, err := e.transition(state); err != nil {
 		e.syslog.Errorf("error transitioning experiment state: %s", err)
 		return false
 	} else if !wasPatched {
 		return true
 	}
-	telemetry.ReportExperimentStateChanged(e.db, e.Experiment)
-	if err := webhooks.ReportExperimentStateChanged(
+	if err := activity.RecordExperimentEnded(
 		context.TODO(), *e.Experiment, e.activeConfig,
 	); err != nil {
-		e.syslog.WithError(err).Error("failed to send experiment state change webhook")
+		e.syslog.WithError(err).Warn("failed to record experiment state change activity")
+	}
+	if err := modeleval.ExperimentEnded(context.TODO(), e.ID, e.State); err != nil {
+		e.syslog.WithError(err).Error("failed to record model evaluation")
 	}
 
 	e.syslog.Infof("updateState changed to %s", state.State)
 	e.patchTrialsState(state)
 
-	// The database error is explicitly ignored.
-	if err := e.db.SaveExperimentState(e.Experiment); err != nil {
-		e.syslog.Errorf("error saving experiment state: %s", err)
-	}
 	if e.canTerminate() {
 		if err := e.stop(); err != nil {
 			e.syslog.WithError(err).Error("failed to stop experiment on updateState")
@@ -799,6 +877,23 @@ func (e *internalExperiment) updateState(state model.StateWithReason) bool {
 	return true
 }
 
+// transition changes the state of the experiment in the database and then in memory. The state in
+// the database is checked and changed in one transaction, so other changes of the state, such as
+// from the API while the experiment is stopping, can't interleave with this one. If the state in
+// the database differs from the state in memory, the database wins.
+func (e *internalExperiment) transition(state model.StateWithReason) (bool, error) {
+	from, _, err := internaldb.TransitionExperimentState(
+		context.TODO(), e.ID, state.State, state.InformationalReason)
+	if err != nil {
+		return false, err
+	}
+	if from != e.State {
+		e.syslog.Warnf("experiment was %s in the database but %s in memory", from, e.State)
+		e.State = from
+	}
+	return e.Transition(state.State)
+}
+
 func (e *internalExperiment) patchTrialsState(state model.StateWithReason) {
 	var g errgroup.Group
 	g.SetLimit(maxConcurrentTrialOps)
@@ -0,0 +1,80 @@
+//go:build integration
+// +build integration
+
+package experiment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+const (
+	viewerRoleID = 4
+	editorRoleID = 5
+)
+
+func TestArchivedProjectAuthZ(t *testing.T) {
+	ctx := context.Background()
+	pgDB := db.SingleDB()
+	workspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	projectID, _ := db.RequireMockProjectID(t, pgDB, workspaceID, false)
+	archivedID, _ := db.RequireMockProjectID(t, pgDB, workspaceID, true)
+
+	archivedWorkspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	inArchivedWorkspaceID, _ := db.RequireMockProjectID(t, pgDB, archivedWorkspaceID, false)
+	_, err := db.Bun().NewUpdate().Table("workspaces").Set("archived = true").
+		Where("id = ?", archivedWorkspaceID).Exec(ctx)
+	require.NoError(t, err)
+
+	editor := db.RequireMockUser(t, pgDB)
+	viewer := db.RequireMockUser(t, pgDB)
+	for _, id := range []int{workspaceID, archivedWorkspaceID} {
+		db.RequireMockWorkspaceRole(t, pgDB, editor.ID, id, editorRoleID)
+		db.RequireMockWorkspaceRole(t, pgDB, viewer.ID, id, viewerRoleID)
+	}
+
+	project := &projectv1.Project{Id: int32(projectID), WorkspaceId: int32(workspaceID)}
+	archived := &projectv1.Project{
+		Id: int32(archivedID), WorkspaceId: int32(workspaceID), Archived: true,
+	}
+	inArchivedWorkspace := &projectv1.Project{
+		Id: int32(inArchivedWorkspaceID), WorkspaceId: int32(archivedWorkspaceID),
+	}
+	exp := db.RequireMockExperimentProject(t, pgDB, editor, projectID)
+	archivedExp := db.RequireMockExperimentProject(t, pgDB, editor, archivedID)
+	inArchivedWorkspaceExp := db.RequireMockExperimentProject(t, pgDB, editor, inArchivedWorkspaceID)
+
+	for name, a := range map[string]ExperimentAuthZ{
+		"rbac":  &ExperimentAuthZRBAC{},
+		"basic": &ExperimentAuthZBasic{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, a.CanCreateExperiment(ctx, editor, project))
+			require.NoError(t, a.CanEditExperimentsMetadata(ctx, editor, exp))
+
+			for _, p := range []*projectv1.Project{archived, inArchivedWorkspace} {
+				require.ErrorContains(t, a.CanCreateExperiment(ctx, editor, p), "archived")
+			}
+			for _, e := range []*model.Experiment{archivedExp, inArchivedWorkspaceExp} {
+				require.ErrorContains(t, a.CanEditExperimentsMetadata(ctx, editor, e), "archived")
+			}
+		})
+	}
+
+	// Users without permission are denied without learning that the project is archived.
+	a := &ExperimentAuthZRBAC{}
+	for _, err := range []error{
+		a.CanCreateExperiment(ctx, viewer, archived),
+		a.CanEditExperimentsMetadata(ctx, viewer, archivedExp),
+	} {
+		require.True(t, authz.IsPermissionDenied(err), err)
+		require.NotContains(t, err.Error(), "archived")
+	}
+}
@@ -69,18 +69,18 @@ func (a *ExperimentAuthZBasic) CanEditExperiment(
 	return nil
 }
 
-// CanEditExperimentsMetadata always returns a nil error.
+// CanEditExperimentsMetadata returns an error if the experiment's project is archived.
 func (a *ExperimentAuthZBasic) CanEditExperimentsMetadata(
 	ctx context.Context, curUser model.User, e *model.Experiment,
 ) error {
-	return nil
+	return checkExperimentProjectUnarchived(ctx, e)
 }
 
-// CanCreateExperiment always returns a nil error.
+// CanCreateExperiment returns an error if the project is archived.
 func (a *ExperimentAuthZBasic) CanCreateExperiment(
 	ctx context.Context, curUser model.User, proj *projectv1.Project,
 ) error {
-	return nil
+	return checkProjectUnarchived(ctx, proj.Id)
 }
 
 // CanForkFromExperiment always returns a nil error.
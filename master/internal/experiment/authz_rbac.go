@@ -308,8 +308,11 @@ func (a *ExperimentAuthZRBAC) CanEditExperimentsMetadata(
 		return err
 	}
 
-	return db.DoesPermissionMatch(ctx, curUser.ID, &workspaceID,
-		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_EXPERIMENT_METADATA)
+	if err = db.DoesPermissionMatch(ctx, curUser.ID, &workspaceID,
+		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_EXPERIMENT_METADATA); err != nil {
+		return err
+	}
+	return checkExperimentProjectUnarchived(ctx, e)
 }
 
 // CanCreateExperiment checks if a user can create an experiment.
@@ -326,8 +329,11 @@ func (a *ExperimentAuthZRBAC) CanCreateExperiment(
 		return err
 	}
 
-	return db.DoesPermissionMatch(ctx, curUser.ID, &workspaceID,
-		rbacv1.PermissionType_PERMISSION_TYPE_CREATE_EXPERIMENT)
+	if err = db.DoesPermissionMatch(ctx, curUser.ID, &workspaceID,
+		rbacv1.PermissionType_PERMISSION_TYPE_CREATE_EXPERIMENT); err != nil {
+		return err
+	}
+	return checkProjectUnarchived(ctx, proj.Id)
 }
 
 // CanForkFromExperiment checks if a user can create an experiment.
@@ -472,17 +472,29 @@ func DeleteExperiments(
 
 	var acceptedExperiments []*model.Experiment
 	if len(validIDs) > 0 {
-		_, err = db.Bun().NewUpdate().
-			ModelTableExpr("experiments as e").
-			Set("state = ?", model.DeletingState).
-			Where("id IN (?)", bun.In(validIDs)).
-			Returning(`id, state, config, start_time, end_time, archived,
+		ids := make([]int, 0, len(validIDs))
+		for _, id := range validIDs {
+			ids = append(ids, int(id))
+		}
+		var ts []model.ExperimentStateTransition
+		err = db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if ts, err = db.TransitionExperimentStatesTx(
+				ctx, tx, ids, model.DeletingState, "deleted by user",
+			); err != nil {
+				return err
+			}
+			return tx.NewSelect().
+				Model(&acceptedExperiments).
+				ModelTableExpr("experiments as e").
+				ColumnExpr(`id, state, config, start_time, end_time, archived,
 				   owner_id, notes, job_id, '' as username, project_id`).
-			Model(&acceptedExperiments).
-			Exec(ctx)
+				Where("id IN (?)", bun.In(ids)).
+				Scan(ctx)
+		})
 		if err != nil {
 			return nil, nil, err
 		}
+		db.EmitExperimentStateTransitions(ctx, ts)
 
 		for _, exp := range acceptedExperiments {
 			results = append(results, ExperimentActionResult{
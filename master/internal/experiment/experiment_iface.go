@@ -35,6 +35,13 @@ type (
 		State     model.StateWithReason
 	}
 
+	// SetTrialPriority is a message sent to an experiment to change the priority of a trial among
+	// its trials.
+	SetTrialPriority struct {
+		RequestID model.RequestID
+		Priority  int
+	}
+
 	// TrialSearcherState is a message sent to an search to indicate that a run has
 	// changed searcher state.
 	TrialSearcherState struct {
@@ -47,9 +54,12 @@ type (
 // Experiment is an interface that represents an experiment.
 type Experiment interface {
 	TrialReportProgress(requestID model.RequestID, msg TrialReportProgress) error
-	TrialReportValidation(requestID model.RequestID, metrics map[string]interface{}) error
+	TrialReportValidation(
+		requestID model.RequestID, stepsCompleted int, metrics map[string]interface{},
+	) error
 	UserInitiatedEarlyTrialExit(msg UserInitiatedEarlyTrialExit) error
 	PatchTrialState(msg PatchTrialState) error
+	SetTrialPriority(msg SetTrialPriority) error
 	SetGroupMaxSlots(msg sproto.SetGroupMaxSlots)
 	SetGroupWeight(weight float64) error
 	SetGroupPriority(priority int) error
@@ -57,4 +67,6 @@ type Experiment interface {
 	PauseExperiment() error
 	CancelExperiment() error
 	KillExperiment() error
+	IntrospectSearcher(trialIDs map[model.RequestID]int) searcher.Introspection
+	EstimateSearcherProgress() searcher.ProgressEstimate
 }
@@ -134,3 +134,36 @@ WHERE ((experiment_rank > ? AND trial_rank > ?) OR (val_metric IS NULL))
 
 	return deleteCheckpoints, nil
 }
+
+// checkProjectUnarchived returns an error if a project, or its workspace, is archived, in which
+// case experiments can't be created in it and the experiments in it can't be edited. The project
+// is either a project ID or a query selecting one.
+func checkProjectUnarchived(ctx context.Context, project interface{}) error {
+	var res struct {
+		ID       int
+		Archived bool
+	}
+	if err := db.Bun().NewSelect().
+		TableExpr("projects AS p").
+		Join("JOIN workspaces AS w ON w.id = p.workspace_id").
+		ColumnExpr("p.id").
+		ColumnExpr("p.archived OR w.archived AS archived").
+		Where("p.id = (?)", project).
+		Scan(ctx, &res); err != nil {
+		return fmt.Errorf("checking whether project is archived: %w", err)
+	}
+	if res.Archived {
+		return fmt.Errorf("project (%d) is archived and read-only", res.ID)
+	}
+	return nil
+}
+
+// checkExperimentProjectUnarchived returns an error if the project of an experiment is archived.
+func checkExperimentProjectUnarchived(ctx context.Context, e *model.Experiment) error {
+	var project interface{} = db.Bun().NewSelect().Table("experiments").
+		Column("project_id").Where("id = ?", e.ID)
+	if e.ProjectID > 0 {
+		project = e.ProjectID
+	}
+	return checkProjectUnarchived(ctx, project)
+}
@@ -0,0 +1,217 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/determined-ai/determined/master/internal/batchsize"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/master/pkg/searcher"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+)
+
+// loadTunedBatchSize loads the batch size tuning chose for the experiment, if it tunes its batch
+// size and tuning finished, to apply it to the trials the experiment creates.
+func (e *internalExperiment) loadTunedBatchSize() error {
+	if e.activeConfig.BatchSizeTuning() == nil {
+		return nil
+	}
+	tuned, err := batchsize.TunedBatchSizeByExperiment(context.TODO(), e.ID)
+	if err != nil {
+		return err
+	}
+	if tuned != nil {
+		e.tunedBatchSize = &tuned.BatchSize
+	}
+	return nil
+}
+
+// startBatchSizeTuning starts tuning the batch size of the experiment, if it needs to, and returns
+// whether it did. Once tuning finishes, the initial trials of the experiment are created.
+func (e *internalExperiment) startBatchSizeTuning(creates []searcher.Action) bool {
+	if e.activeConfig.BatchSizeTuning() == nil || e.tunedBatchSize != nil {
+		return false
+	}
+	var create *searcher.Create
+	for _, action := range creates {
+		if c, ok := action.(searcher.Create); ok {
+			create = &c
+			break
+		}
+	}
+	if create == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancelBatchSizeTuning = cancel
+	go e.tuneBatchSize(ctx, *e.activeConfig.BatchSizeTuning(), *create, creates)
+	return true
+}
+
+// tuneBatchSize searches for the largest batch size the trials of the experiment fit in memory
+// with, by probing the hyperparameters of its first trial at different batch sizes, then creates
+// its initial trials with that batch size. It doesn't hold the experiment lock while probing.
+func (e *internalExperiment) tuneBatchSize(
+	ctx context.Context, tuning expconf.BatchSizeTuningConfig, create searcher.Create,
+	creates []searcher.Action,
+) {
+	e.syslog.Infof("tuning batch size between %d and %d",
+		tuning.MinBatchSize(), tuning.MaxBatchSize())
+	size, err := batchsize.Search(tuning.MinBatchSize(), tuning.MaxBatchSize(),
+		func(batchSize int) (bool, error) {
+			return e.runBatchSizeProbe(ctx, tuning, create, batchSize)
+		})
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cancelBatchSizeTuning = nil
+	if ctx.Err() != nil {
+		// The experiment stopped while tuning.
+		return
+	}
+	if err == nil {
+		err = batchsize.RecordTunedBatchSize(context.TODO(), e.ID, size)
+	}
+	if err != nil {
+		e.syslog.WithError(err).Error("failed to tune batch size")
+		e.updateState(model.StateWithReason{
+			State:               model.StoppingErrorState,
+			InformationalReason: fmt.Sprintf("batch size tuning failed: %s", err),
+		})
+		return
+	}
+	e.syslog.Infof("tuned batch size to %d", size)
+	e.tunedBatchSize = &size
+	e.handleSearcherActions(creates, nil)
+}
+
+// runBatchSizeProbe runs the hyperparameters of a trial at a batch size for the probe steps of the
+// experiment and returns whether it fit, which is whether the probe exited successfully.
+func (e *internalExperiment) runBatchSizeProbe(
+	ctx context.Context, tuning expconf.BatchSizeTuningConfig, create searcher.Create,
+	batchSize int,
+) (bool, error) {
+	e.mu.Lock()
+	config := schemas.Copy(e.activeConfig)
+	taskSpec, err := e.taskSpec.Clone()
+	e.mu.Unlock()
+	if err != nil {
+		return false, fmt.Errorf("cloning batch size probe task spec: %w", err)
+	}
+	resources := config.Resources()
+
+	// Probes are named like trial tasks, so that their logs are authorized like the trials'.
+	taskID := model.TaskID(fmt.Sprintf("%d.%s", e.ID, uuid.New()))
+	if err := db.AddTask(context.TODO(), &model.Task{
+		TaskID:     taskID,
+		TaskType:   model.TaskTypeTrial,
+		StartTime:  time.Now().UTC(),
+		JobID:      &e.JobID,
+		LogVersion: model.CurrentTaskLogVersion,
+	}); err != nil {
+		return false, fmt.Errorf("persisting batch size probe task %s: %w", taskID, err)
+	}
+	if err := batchsize.AddProbe(context.TODO(), &model.BatchSizeProbe{
+		TaskID:       taskID,
+		ExperimentID: e.ID,
+		BatchSize:    batchSize,
+	}); err != nil {
+		return false, err
+	}
+
+	hparams := withBatchSize(create.Hparams, tuning.Hyperparameter(), batchSize)
+	specifier := &tasks.TrialSpec{
+		Base: *taskSpec,
+
+		ExperimentID:     e.ID,
+		ExperimentConfig: config,
+		HParams:          hparams,
+		TrialSeed:        create.TrialSeed,
+		Slots:            resources.SlotsPerTrial(),
+
+		Keys: e.generatedKeys,
+
+		BatchSizeProbeSteps: tuning.ProbeSteps(),
+	}
+	ar := sproto.AllocateRequest{
+		AllocationID:      model.AllocationID(fmt.Sprintf("%s.1", taskID)),
+		TaskID:            taskID,
+		JobID:             e.JobID,
+		TaskType:          model.TaskTypeTrial,
+		RequestTime:       time.Now().UTC(),
+		JobSubmissionTime: e.StartTime,
+		IsUserVisible:     true,
+		Name:              fmt.Sprintf("Batch Size Probe %d (Experiment %d)", batchSize, e.ID),
+		WorkspaceID:       e.taskSpec.WorkspaceID,
+		OwnerID:           e.taskSpec.OwnerID(),
+
+		SlotsNeeded:  resources.SlotsPerTrial(),
+		ResourcePool: resources.ResourcePool(),
+		FittingRequirements: sproto.FittingRequirements{
+			SingleAgent:      resources.IsSingleNode() != nil && *resources.IsSingleNode(),
+			MinGPUMemoryMiB:  minGPUMemory(resources),
+			AgentConstraints: sproto.NewAgentConstraints(resources.AgentConstraints()),
+			ScratchGiB:       localScratchGiB(resources),
+		},
+	}
+	if err := task.InsertTrialAllocationWorkspaceRecord(
+		context.TODO(), e.ID, ar.AllocationID,
+	); err != nil {
+		return false, err
+	}
+
+	exited := make(chan *task.AllocationExited, 1)
+	onExit := func(ae *task.AllocationExited) {
+		if err := db.CompleteTask(context.TODO(), taskID, time.Now().UTC()); err != nil {
+			e.syslog.WithError(err).Errorf("marking batch size probe %s complete", taskID)
+		}
+		exited <- ae
+	}
+	if err := task.DefaultService.StartAllocation(
+		e.logCtx, ar, e.db, e.rm, specifier, onExit,
+	); err != nil {
+		return false, err
+	}
+
+	var exit *task.AllocationExited
+	select {
+	case exit = <-exited:
+	case <-ctx.Done():
+		if err := task.DefaultService.Signal(
+			ar.AllocationID, task.KillAllocation, "experiment stopped while tuning batch size",
+		); err != nil {
+			e.syslog.WithError(err).Warnf("failed to kill batch size probe %s", taskID)
+		}
+		<-exited
+		return false, ctx.Err()
+	}
+
+	fits := exit.Err == nil
+	if err := batchsize.CompleteProbe(context.TODO(), taskID, fits); err != nil {
+		return false, err
+	}
+	e.syslog.WithField("task-id", taskID).
+		Infof("batch size %d fits: %t (%s)", batchSize, fits, exit)
+	return fits, nil
+}
+
+// withBatchSize returns a copy of hparams with the batch size hyperparameter set to batchSize.
+func withBatchSize(
+	hparams searcher.HParamSample, hyperparameter string, batchSize int,
+) searcher.HParamSample {
+	tuned := make(searcher.HParamSample, len(hparams)+1)
+	for name, value := range hparams {
+		tuned[name] = value
+	}
+	tuned[hyperparameter] = batchSize
+	return tuned
+}
@@ -123,6 +123,7 @@ func expColumnNameToSQL(columnName string) (string, error) {
 		) `,
 		"externalExperimentId": "e.external_experiment_id",
 		"externalTrialId":      "r.external_run_id",
+		"configHash":           "e.config_hash",
 	}
 	var exists bool
 	col, exists := filterExperimentColMap[columnName]
@@ -161,6 +162,7 @@ func runColumnNameToSQL(columnName string) (string, error) {
 		"localId":               "CONCAT(p.key, '-' , r.local_id::text)",
 		"isExpMultitrial":       "e.config->'searcher'->>'name' != 'single'",
 		"parentArchived":        "(w.archived OR p.archived)",
+		"experimentConfigHash":  "e.config_hash",
 	}
 	var exists bool
 	col, exists := filterExperimentColMap[columnName]
@@ -555,6 +557,48 @@ func expRunOperatorQuery(o operator, col string, oSQL string, val *interface{})
 	return queryString, queryArgs
 }
 
+// expMetadataQuery filters experiments on a key of their metadata, given as metadata.<key>.
+// Number filters only match experiments whose value for the key is a number.
+func expMetadataQuery(o operator, columnName string, columnType *string, oSQL string,
+	val *interface{},
+) (string, []interface{}) {
+	key := strings.TrimPrefix(columnName, "metadata.")
+	col := "e.config->'metadata'->>?"
+	colArgs := []interface{}{key}
+	isNumber := columnType != nil && *columnType == projectv1.ColumnType_COLUMN_TYPE_NUMBER.String()
+	if isNumber {
+		col = `(CASE WHEN jsonb_typeof(e.config->'metadata'->?) = 'number'
+			THEN (e.config->'metadata'->>?)::float8 END)`
+		colArgs = []interface{}{key, key}
+	}
+
+	var queryArgs []interface{}
+	var queryString string
+	switch o {
+	case contains:
+		queryString = fmt.Sprintf("%s ILIKE ?", col)
+		queryArgs = append(append(queryArgs, colArgs...), fmt.Sprintf("%%%v%%", *val))
+	case doesNotContain:
+		queryString = fmt.Sprintf("%s NOT ILIKE ?", col)
+		queryArgs = append(append(queryArgs, colArgs...), fmt.Sprintf("%%%v%%", *val))
+	case empty:
+		queryString = fmt.Sprintf("%s IS NULL", col)
+		queryArgs = append(queryArgs, colArgs...)
+	case notEmpty:
+		queryString = fmt.Sprintf("%s IS NOT NULL", col)
+		queryArgs = append(queryArgs, colArgs...)
+	default:
+		// Booleans are compared as the text ->> gives them, 'true' or 'false'.
+		value := *val
+		if !isNumber {
+			value = fmt.Sprint(value)
+		}
+		queryString = fmt.Sprintf("%s ? ?", col)
+		queryArgs = append(append(queryArgs, colArgs...), bun.Safe(oSQL), value)
+	}
+	return queryString, queryArgs
+}
+
 func (e experimentFilterRoot) toSQL(q *bun.SelectQuery) (*bun.SelectQuery, error) {
 	q, err := e.FilterGroup.toSQL(q, nil)
 	if err != nil {
@@ -584,12 +628,18 @@ func (e experimentFilter) toSQL(q *bun.SelectQuery,
 		}
 		switch location {
 		case projectv1.LocationType_LOCATION_TYPE_EXPERIMENT.String():
-			var col string
-			col, err = expColumnNameToSQL(e.ColumnName)
-			if err != nil {
-				return nil, err
+			var queryString string
+			var queryArgs []interface{}
+			if strings.HasPrefix(e.ColumnName, "metadata.") {
+				queryString, queryArgs = expMetadataQuery(*e.Operator, e.ColumnName, e.Type, oSQL, e.Value)
+			} else {
+				var col string
+				col, err = expColumnNameToSQL(e.ColumnName)
+				if err != nil {
+					return nil, err
+				}
+				queryString, queryArgs = expRunOperatorQuery(*e.Operator, col, oSQL, e.Value)
 			}
-			queryString, queryArgs := expRunOperatorQuery(*e.Operator, col, oSQL, e.Value)
 			if c != nil && *c == or {
 				q.WhereOr(queryString, queryArgs...)
 			} else {
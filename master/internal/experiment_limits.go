@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// improvementState tracks how many steps the trials of an experiment trained since the searcher
+// metric last improved. It is part of the experiment snapshot so it survives master restarts.
+type improvementState struct {
+	BestMetric              *float64                `json:"best_metric,omitempty"`
+	StepsWithoutImprovement int                     `json:"steps_without_improvement"`
+	TrialSteps              map[model.RequestID]int `json:"trial_steps,omitempty"`
+}
+
+// recordValidation records a validation of a trial at the given step and returns the number of
+// steps trained across all trials since the best validation. Steps are counted from the last
+// validation of each trial, so trials training in parallel each add their own progress.
+func (s *improvementState) recordValidation(
+	requestID model.RequestID, stepsCompleted int, metric float64, smallerIsBetter bool,
+) int {
+	if s.TrialSteps == nil {
+		s.TrialSteps = map[model.RequestID]int{}
+	}
+	if delta := stepsCompleted - s.TrialSteps[requestID]; delta > 0 {
+		s.StepsWithoutImprovement += delta
+	}
+	s.TrialSteps[requestID] = stepsCompleted
+
+	if !smallerIsBetter {
+		metric = -metric
+	}
+	if s.BestMetric == nil || metric < *s.BestMetric {
+		s.BestMetric = &metric
+		s.StepsWithoutImprovement = 0
+	}
+	return s.StepsWithoutImprovement
+}
+
+// startDurationLimit arms the max_duration limit of the experiment, if it has one. The limit is
+// measured from the start time of the experiment, so it holds across pauses and master restarts.
+func (e *internalExperiment) startDurationLimit() {
+	maxDuration := e.activeConfig.MaxDuration()
+	if maxDuration == nil || model.StoppingStates[e.State] || model.TerminalStates[e.State] {
+		return
+	}
+	limit := time.Duration(*maxDuration) * time.Second
+	e.durationLimit = time.AfterFunc(time.Until(e.StartTime.Add(limit)), func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.stopForLimit(fmt.Sprintf("experiment exceeded max_duration of %s", limit))
+	})
+}
+
+// checkImprovementLimit records a validation against the max_steps_without_improvement limit of
+// the experiment, if it has one, and stops the experiment once the limit is reached.
+func (e *internalExperiment) checkImprovementLimit(
+	requestID model.RequestID, stepsCompleted int, metrics map[string]interface{},
+) {
+	maxSteps := e.activeConfig.MaxStepsWithoutImprovement()
+	if maxSteps == nil {
+		return
+	}
+	searcherConfig := e.activeConfig.Searcher()
+	metric, ok := metrics[searcherConfig.Metric()].(float64)
+	if !ok {
+		return
+	}
+	steps := e.Improvement.recordValidation(
+		requestID, stepsCompleted, metric, searcherConfig.SmallerIsBetter())
+	if steps >= *maxSteps {
+		e.stopForLimit(fmt.Sprintf(
+			"%s did not improve for %d steps, reaching max_steps_without_improvement",
+			searcherConfig.Metric(), steps))
+	}
+}
+
+// stopForLimit stops the experiment as if its search completed; trials are preempted so they
+// checkpoint before exiting.
+func (e *internalExperiment) stopForLimit(reason string) {
+	if model.StoppingStates[e.State] || model.TerminalStates[e.State] {
+		return
+	}
+	e.syslog.Info(reason)
+	e.updateState(model.StateWithReason{
+		State:               model.StoppingCompletedState,
+		InformationalReason: reason,
+	})
+}
@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestImprovementStateRecordValidation(t *testing.T) {
+	a, b := model.NewRequestID(rand.Reader), model.NewRequestID(rand.Reader)
+	var s improvementState
+
+	require.Equal(t, 0, s.recordValidation(a, 100, 1.0, true))
+	// Steps of every trial count towards the limit.
+	require.Equal(t, 100, s.recordValidation(a, 200, 1.5, true))
+	require.Equal(t, 400, s.recordValidation(b, 300, 2.0, true))
+	// An improvement from any trial resets the count.
+	require.Equal(t, 0, s.recordValidation(b, 400, 0.5, true))
+	require.Equal(t, 100, s.recordValidation(a, 300, 0.5, true))
+}
+
+func TestImprovementStateLargerIsBetter(t *testing.T) {
+	a := model.NewRequestID(rand.Reader)
+	var s improvementState
+
+	require.Equal(t, 0, s.recordValidation(a, 10, 0.5, false))
+	require.Equal(t, 10, s.recordValidation(a, 20, 0.4, false))
+	require.Equal(t, 0, s.recordValidation(a, 30, 0.9, false))
+}
@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	promclient "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/jsondelta"
+)
+
+const (
+	// maxSnapshotDeltas is how many deltas are saved before the snapshot of an experiment is
+	// compacted, to bound how many a restore has to apply.
+	maxSnapshotDeltas = 100
+
+	defaultSnapshotSizesLimit = 100
+	maxSnapshotSizesLimit     = 10000
+)
+
+var (
+	snapshotBytes = promclient.NewHistogramVec(promclient.HistogramOpts{
+		Name:    "determined_experiment_snapshot_bytes",
+		Help:    "Size of the experiment snapshots and snapshot deltas saved",
+		Buckets: promclient.ExponentialBuckets(1024, 4, 10),
+	}, []string{"kind"})
+	snapshotCompactions = promclient.NewCounter(promclient.CounterOpts{
+		Name: "determined_experiment_snapshot_compactions_total",
+		Help: "Number of times experiment snapshot deltas were compacted into a full snapshot",
+	})
+)
+
+func init() {
+	promclient.MustRegister(snapshotBytes, snapshotCompactions)
+}
+
+// snapshotCompactor saves the snapshots of an experiment incrementally. Rather than rewriting the
+// whole snapshot, which for long adaptive searches can be large, on every change, it saves what
+// changed since the last save as a delta, and periodically compacts the deltas into a full
+// snapshot so that restoring doesn't have to apply too many.
+type snapshotCompactor struct {
+	// saved is the snapshot as of the last save, or nil if the next save must be a full snapshot.
+	saved      []byte
+	deltas     int
+	deltaBytes int
+}
+
+type snapshotSaver interface {
+	SaveSnapshot(experimentID int, version int, experimentSnapshot []byte) error
+	SaveSnapshotDelta(experimentID int, version int, delta []byte) error
+}
+
+func (c *snapshotCompactor) save(db snapshotSaver, experimentID int, snapshot []byte) error {
+	if c.saved != nil && c.deltas < maxSnapshotDeltas {
+		delta, err := jsondelta.Diff(c.saved, snapshot)
+		switch {
+		case err != nil:
+			return fmt.Errorf("computing experiment snapshot delta: %w", err)
+		case delta == nil:
+			return nil
+		// Once the deltas are as large as the snapshot itself, restoring from them costs more
+		// than from a full snapshot.
+		case c.deltaBytes+len(delta) < len(snapshot):
+			if err := db.SaveSnapshotDelta(experimentID, experimentSnapshotVersion, delta); err != nil {
+				c.saved = nil
+				return err
+			}
+			observeSnapshotSize("delta", len(delta))
+			c.saved = snapshot
+			c.deltas++
+			c.deltaBytes += len(delta)
+			return nil
+		}
+	}
+
+	if err := db.SaveSnapshot(experimentID, experimentSnapshotVersion, snapshot); err != nil {
+		c.saved = nil
+		return err
+	}
+	observeSnapshotSize("full", len(snapshot))
+	if c.deltas > 0 && config.GetMasterConfig().Observability.EnablePrometheus {
+		snapshotCompactions.Inc()
+	}
+	c.saved = snapshot
+	c.deltas = 0
+	c.deltaBytes = 0
+	return nil
+}
+
+func observeSnapshotSize(kind string, size int) {
+	if !config.GetMasterConfig().Observability.EnablePrometheus {
+		return
+	}
+	snapshotBytes.WithLabelValues(kind).Observe(float64(size))
+}
+
+// getDebugExperimentSnapshots lists the experiments with the largest snapshots, which are the
+// slowest to restore when the master restarts.
+func (m *Master) getDebugExperimentSnapshots(c echo.Context) (interface{}, error) {
+	args := struct {
+		Limit *int `query:"limit"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return nil, err
+	}
+	limit := defaultSnapshotSizesLimit
+	if args.Limit != nil {
+		limit = *args.Limit
+	}
+	if limit < 1 || limit > maxSnapshotSizesLimit {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("limit must be between 1 and %d", maxSnapshotSizesLimit))
+	}
+	return db.ExperimentSnapshotSizes(c.Request().Context(), limit)
+}
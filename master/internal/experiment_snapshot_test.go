@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/jsondelta"
+)
+
+type fakeSnapshotStore struct {
+	base   []byte
+	deltas [][]byte
+}
+
+func (f *fakeSnapshotStore) SaveSnapshot(_ int, _ int, snapshot []byte) error {
+	f.base, f.deltas = snapshot, nil
+	return nil
+}
+
+func (f *fakeSnapshotStore) SaveSnapshotDelta(_ int, _ int, delta []byte) error {
+	f.deltas = append(f.deltas, delta)
+	return nil
+}
+
+func (f *fakeSnapshotStore) restore(t *testing.T) string {
+	content := f.base
+	for _, d := range f.deltas {
+		var err error
+		content, err = jsondelta.Apply(content, d)
+		require.NoError(t, err)
+	}
+	return string(content)
+}
+
+func TestSnapshotCompactor(t *testing.T) {
+	snapshot := func(trials int) []byte {
+		var ids []string
+		for i := 0; i < trials; i++ {
+			ids = append(ids, fmt.Sprintf(`"%d": {"hparams": {"lr": 0.%d}}`, i, i))
+		}
+		return []byte(fmt.Sprintf(`{"padding": %q, "trials": {%s}}`,
+			strings.Repeat("x", 10000), strings.Join(ids, ",")))
+	}
+
+	store := &fakeSnapshotStore{}
+	var c snapshotCompactor
+	require.NoError(t, c.save(store, 1, snapshot(1)))
+	require.Equal(t, snapshot(1), store.base)
+
+	// Changes are saved as deltas, and unchanged snapshots aren't saved at all.
+	require.NoError(t, c.save(store, 1, snapshot(2)))
+	require.NoError(t, c.save(store, 1, snapshot(2)))
+	require.Equal(t, snapshot(1), store.base)
+	require.Len(t, store.deltas, 1)
+	require.JSONEq(t, string(snapshot(2)), store.restore(t))
+
+	// The deltas are compacted periodically.
+	for i := 3; i < maxSnapshotDeltas+3; i++ {
+		require.NoError(t, c.save(store, 1, snapshot(i)))
+		if i == maxSnapshotDeltas+1 {
+			require.Len(t, store.deltas, maxSnapshotDeltas)
+			require.JSONEq(t, string(snapshot(i)), store.restore(t))
+		}
+	}
+	require.Equal(t, snapshot(maxSnapshotDeltas+2), store.base)
+	require.Empty(t, store.deltas)
+
+	// And when they grow as large as the snapshot itself.
+	require.NoError(t, c.save(store, 1, []byte(`{"padding": "", "trials": {}}`)))
+	require.Empty(t, store.deltas)
+	require.NoError(t, c.save(store, 1, snapshot(1)))
+	require.Empty(t, store.deltas)
+	require.JSONEq(t, string(snapshot(1)), store.restore(t))
+}
@@ -0,0 +1,84 @@
+// Package expmetadata validates the metadata of experiments, such as ticket URLs, dataset cards
+// or approval IDs, against the JSON schemas projects may require it to follow.
+package expmetadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/santhosh-tekuri/jsonschema/v2"
+)
+
+// schemaURL is the URL a project's schema is compiled under. Nothing is ever loaded from it.
+const schemaURL = "metadata-schema.json"
+
+// keyPattern matches the keys metadata may have. Dots are excluded so that experiments can be
+// filtered on a key with the column name metadata.<key>.
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateMetadata returns an error if metadata has keys that cannot be filtered on or values
+// that are not strings, numbers or booleans.
+func ValidateMetadata(metadata map[string]interface{}) error {
+	for k, v := range metadata {
+		if !keyPattern.MatchString(k) {
+			return fmt.Errorf("metadata key %q may only contain letters, digits, '_' and '-'", k)
+		}
+		switch v.(type) {
+		case string, bool, float64, int, int64, json.Number:
+		default:
+			return fmt.Errorf("metadata %q must be a string, number or boolean; got %T", k, v)
+		}
+	}
+	return nil
+}
+
+// CompileSchema compiles a project's metadata schema. Schemas must be JSON objects and cannot
+// reference other documents, so compiling one never reads files or makes requests.
+func CompileSchema(schema json.RawMessage) (*jsonschema.Schema, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal(schema, &root); err != nil || root == nil {
+		return nil, fmt.Errorf("metadata schema must be a JSON object")
+	}
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("metadata schemas cannot reference %s", s)
+	}
+	if err := compiler.AddResource(schemaURL, bytes.NewReader(schema)); err != nil {
+		return nil, fmt.Errorf("invalid metadata schema: %w", err)
+	}
+	compiled, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata schema: %w", err)
+	}
+	return compiled, nil
+}
+
+// Validate returns an error if metadata is invalid or does not follow a project's schema. A nil
+// schema accepts any valid metadata; missing metadata is validated as if it were empty, so that
+// schemas can require keys.
+func Validate(schema json.RawMessage, metadata map[string]interface{}) error {
+	if err := ValidateMetadata(metadata); err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+	compiled, err := CompileSchema(schema)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	doc, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	if err := compiled.Validate(bytes.NewReader(doc)); err != nil {
+		return fmt.Errorf("metadata does not follow the project's schema: %w", err)
+	}
+	return nil
+}
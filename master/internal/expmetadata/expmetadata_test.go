@@ -0,0 +1,73 @@
+package expmetadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ticketSchema = `{
+	"type": "object",
+	"required": ["ticket_url", "approval_id"],
+	"properties": {
+		"ticket_url": {"type": "string", "pattern": "^https://"},
+		"approval_id": {"type": "integer"}
+	}
+}`
+
+func TestValidateMetadata(t *testing.T) {
+	require.NoError(t, ValidateMetadata(nil))
+	require.NoError(t, ValidateMetadata(map[string]interface{}{
+		"ticket_url":  "https://tickets.example.com/ML-42",
+		"approval-id": 1234.0,
+		"approved":    true,
+	}))
+
+	require.Error(t, ValidateMetadata(map[string]interface{}{"dataset.card": "imagenet"}))
+	require.Error(t, ValidateMetadata(map[string]interface{}{"": "imagenet"}))
+	require.Error(t, ValidateMetadata(map[string]interface{}{"card": nil}))
+	require.Error(t, ValidateMetadata(map[string]interface{}{
+		"card": map[string]interface{}{"name": "imagenet"},
+	}))
+	require.Error(t, ValidateMetadata(map[string]interface{}{"cards": []interface{}{"imagenet"}}))
+}
+
+func TestCompileSchema(t *testing.T) {
+	_, err := CompileSchema(json.RawMessage(ticketSchema))
+	require.NoError(t, err)
+
+	for _, bad := range []string{
+		`null`,
+		`[]`,
+		`"object"`,
+		`{"type": "objekt"}`,
+		`{"$ref": "file:///etc/passwd"}`,
+		`{"$ref": "https://example.com/schema.json"}`,
+		`{"$schema": "https://example.com/schema.json"}`,
+	} {
+		_, err := CompileSchema(json.RawMessage(bad))
+		require.Error(t, err, bad)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := map[string]interface{}{
+		"ticket_url":  "https://tickets.example.com/ML-42",
+		"approval_id": 1234.0,
+	}
+	require.NoError(t, Validate(nil, valid))
+	require.NoError(t, Validate(nil, nil))
+	require.NoError(t, Validate(json.RawMessage(ticketSchema), valid))
+
+	for _, bad := range []map[string]interface{}{
+		nil,
+		{"ticket_url": "https://tickets.example.com/ML-42"},
+		{"ticket_url": "tickets.example.com/ML-42", "approval_id": 1234.0},
+		{"ticket_url": "https://tickets.example.com/ML-42", "approval_id": 12.5},
+	} {
+		require.Error(t, Validate(json.RawMessage(ticketSchema), bad), bad)
+	}
+	// Metadata must be valid even without a schema.
+	require.Error(t, Validate(nil, map[string]interface{}{"dataset.card": "imagenet"}))
+}
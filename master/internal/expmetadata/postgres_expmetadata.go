@@ -0,0 +1,124 @@
+package expmetadata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Schema returns the metadata schema of a project, or nil if the project has none.
+func Schema(ctx context.Context, projectID int) (*model.ProjectExperimentMetadataSchema, error) {
+	s := &model.ProjectExperimentMetadataSchema{ProjectID: projectID}
+	err := db.Bun().NewSelect().Model(s).WherePK().Scan(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting experiment metadata schema of project %d: %w", projectID, err)
+	}
+	return s, nil
+}
+
+// SetSchema sets the metadata schema of a project. Experiments already in the project are not
+// revalidated; their metadata is validated against the schema the next time it is changed.
+func SetSchema(ctx context.Context, projectID int, schema json.RawMessage) error {
+	if _, err := CompileSchema(schema); err != nil {
+		return fmt.Errorf("%w: %s", db.ErrInvalidInput, err)
+	}
+	s := &model.ProjectExperimentMetadataSchema{
+		ProjectID: projectID,
+		Schema:    schema,
+		UpdatedAt: time.Now(),
+	}
+	_, err := db.Bun().NewInsert().Model(s).
+		On("CONFLICT (project_id) DO UPDATE").
+		Set("schema = EXCLUDED.schema").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting experiment metadata schema of project %d: %w", projectID, err)
+	}
+	return nil
+}
+
+// DeleteSchema removes the metadata schema of a project, so that its experiments may have any
+// metadata.
+func DeleteSchema(ctx context.Context, projectID int) error {
+	_, err := db.Bun().NewDelete().Model((*model.ProjectExperimentMetadataSchema)(nil)).
+		Where("project_id = ?", projectID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting experiment metadata schema of project %d: %w", projectID, err)
+	}
+	return nil
+}
+
+// ValidateForProject returns an error if metadata is invalid or does not follow the schema of the
+// project.
+func ValidateForProject(
+	ctx context.Context, projectID int, metadata map[string]interface{},
+) error {
+	s, err := Schema(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	var schema json.RawMessage
+	if s != nil {
+		schema = s.Schema
+	}
+	if err := Validate(schema, metadata); err != nil {
+		return fmt.Errorf("%w: %s", db.ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// Metadata returns the metadata of an experiment.
+func Metadata(ctx context.Context, experimentID int) (map[string]interface{}, error) {
+	var raw []byte
+	err := db.Bun().NewSelect().
+		Table("experiments").
+		ColumnExpr("config->'metadata'").
+		Where("id = ?", experimentID).
+		Scan(ctx, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("getting metadata of experiment %d: %w", experimentID, err)
+	}
+	metadata := map[string]interface{}{}
+	if raw != nil {
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			return nil, fmt.Errorf("parsing metadata of experiment %d: %w", experimentID, err)
+		}
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+	}
+	return metadata, nil
+}
+
+// SetMetadata replaces the metadata of an experiment, after validating it against the schema of
+// the experiment's project.
+func SetMetadata(
+	ctx context.Context, experimentID, projectID int, metadata map[string]interface{},
+) error {
+	if err := ValidateForProject(ctx, projectID, metadata); err != nil {
+		return err
+	}
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	_, err := db.Bun().NewUpdate().
+		Table("experiments").
+		Set("config = jsonb_set(config, '{metadata}', ?, true)", metadata).
+		Where("id = ?", experimentID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting metadata of experiment %d: %w", experimentID, err)
+	}
+	return nil
+}
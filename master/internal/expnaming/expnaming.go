@@ -0,0 +1,69 @@
+// Package expnaming names experiments from per-project templates and checks that experiment names
+// are unique in projects that require it.
+package expnaming
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxTemplateLength is the longest naming template a project may have.
+const MaxTemplateLength = 255
+
+var placeholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// Values are what a template's placeholders are replaced with.
+type Values struct {
+	User      string
+	Project   string
+	Workspace string
+	Time      time.Time
+	// Seq is the number of the experiment among those the project's template has named.
+	Seq int
+}
+
+// placeholders maps each placeholder a template may use to its value.
+var placeholders = map[string]func(v Values) string{
+	"user":      func(v Values) string { return v.User },
+	"project":   func(v Values) string { return v.Project },
+	"workspace": func(v Values) string { return v.Workspace },
+	"date":      func(v Values) string { return v.Time.UTC().Format("2006-01-02") },
+	"time":      func(v Values) string { return v.Time.UTC().Format("150405") },
+	"seq":       func(v Values) string { return strconv.Itoa(v.Seq) },
+}
+
+// ValidateTemplate checks that a template only uses known placeholders.
+func ValidateTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("naming template must not be empty")
+	}
+	if len(template) > MaxTemplateLength {
+		return fmt.Errorf("naming template must be at most %d characters", MaxTemplateLength)
+	}
+	for _, m := range placeholder.FindAllStringSubmatch(template, -1) {
+		if _, ok := placeholders[m[1]]; !ok {
+			return fmt.Errorf("naming template has unknown placeholder {%s}; known placeholders are "+
+				"{user}, {project}, {workspace}, {date}, {time} and {seq}", m[1])
+		}
+	}
+	return nil
+}
+
+// UsesSeq returns whether a template uses the {seq} placeholder.
+func UsesSeq(template string) bool {
+	return strings.Contains(template, "{seq}")
+}
+
+// Render replaces the placeholders in a template with their values. Unknown placeholders are left
+// as they are.
+func Render(template string, v Values) string {
+	return placeholder.ReplaceAllStringFunc(template, func(m string) string {
+		if value, ok := placeholders[m[1:len(m)-1]]; ok {
+			return value(v)
+		}
+		return m
+	})
+}
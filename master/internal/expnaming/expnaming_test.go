@@ -0,0 +1,33 @@
+package expnaming
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	for _, template := range []string{"{user}-{date}-{seq}", "nightly {project} {time}", "fixed"} {
+		require.NoError(t, ValidateTemplate(template), template)
+	}
+	for _, template := range []string{"", "  ", "{user}-{when}", "{}"} {
+		require.Error(t, ValidateTemplate(template), template)
+	}
+}
+
+func TestRender(t *testing.T) {
+	v := Values{
+		User:      "alice",
+		Project:   "vision",
+		Workspace: "research",
+		Time:      time.Date(2024, 11, 18, 9, 5, 3, 0, time.FixedZone("", -5*60*60)),
+		Seq:       42,
+	}
+	require.Equal(t, "alice-2024-11-18-42", Render("{user}-{date}-{seq}", v))
+	require.Equal(t, "research/vision 140503", Render("{workspace}/{project} {time}", v))
+	require.Equal(t, "{other} alice", Render("{other} {user}", v))
+
+	require.True(t, UsesSeq("{user}-{seq}"))
+	require.False(t, UsesSeq("{user}-{date}"))
+}
@@ -0,0 +1,101 @@
+package expnaming
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// NameTakenError is returned when an experiment name is already used in a project that requires
+// unique names.
+type NameTakenError struct {
+	Name      string
+	ProjectID int
+}
+
+func (e NameTakenError) Error() string {
+	return fmt.Sprintf("experiment name %q is already used in project %d, which requires unique "+
+		"experiment names", e.Name, e.ProjectID)
+}
+
+// Naming returns how experiments in a project are named. Projects that were never configured have
+// no template and don't require unique names.
+func Naming(ctx context.Context, projectID int) (*model.ProjectExperimentNaming, error) {
+	n := &model.ProjectExperimentNaming{ProjectID: projectID, NextSeq: 1}
+	err := db.Bun().NewSelect().Model(n).WherePK().Scan(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting experiment naming of project %d: %w", projectID, err)
+	}
+	return n, nil
+}
+
+// SetNaming sets how experiments in a project are named, keeping the project's sequence number.
+func SetNaming(ctx context.Context, n *model.ProjectExperimentNaming) error {
+	if n.NameTemplate != nil {
+		if err := ValidateTemplate(*n.NameTemplate); err != nil {
+			return err
+		}
+	}
+	_, err := db.Bun().NewInsert().Model(n).
+		On("CONFLICT (project_id) DO UPDATE").
+		Set("unique_names = EXCLUDED.unique_names").
+		Set("name_template = EXCLUDED.name_template").
+		Returning("next_seq").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting experiment naming of project %d: %w", n.ProjectID, err)
+	}
+	return nil
+}
+
+// TemplateName names an experiment whose config omits a name from its project's template, or
+// returns nil if the project has no template. The project's sequence number is only used up if
+// reserve is set, so validating an experiment doesn't skip numbers.
+func TemplateName(
+	ctx context.Context, projectID int, v Values, reserve bool,
+) (*string, error) {
+	n, err := Naming(ctx, projectID)
+	if err != nil || n.NameTemplate == nil {
+		return nil, err
+	}
+	v.Seq = n.NextSeq
+	if reserve && UsesSeq(*n.NameTemplate) {
+		err = db.Bun().NewUpdate().Model(n).
+			Set("next_seq = next_seq + 1").
+			WherePK().
+			Returning("next_seq - 1 AS next_seq").
+			Scan(ctx, &v.Seq)
+		if err != nil {
+			return nil, fmt.Errorf("reserving experiment number in project %d: %w", projectID, err)
+		}
+	}
+	name := Render(*n.NameTemplate, v)
+	return &name, nil
+}
+
+// CheckNameAvailable returns a NameTakenError if a project requires unique experiment names and
+// another of its experiments already has the name. experimentID is the experiment being named, or
+// 0 for a new one. The database enforces this as well; checking first gives a clearer error.
+func CheckNameAvailable(ctx context.Context, projectID int, name string, experimentID int) error {
+	n, err := Naming(ctx, projectID)
+	if err != nil || !n.UniqueNames {
+		return err
+	}
+	taken, err := db.Bun().NewSelect().
+		Table("experiments").
+		Where("project_id = ?", projectID).
+		Where("config->>'name' = ?", name).
+		Where("id != ?", experimentID).
+		Exists(ctx)
+	if err != nil {
+		return fmt.Errorf("checking experiment names in project %d: %w", projectID, err)
+	}
+	if taken {
+		return NameTakenError{Name: name, ProjectID: projectID}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+//go:build integration
+// +build integration
+
+package expnaming
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestTemplateName(t *testing.T) {
+	ctx := context.Background()
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	v := Values{User: "alice", Time: time.Date(2024, 11, 18, 0, 0, 0, 0, time.UTC)}
+
+	name, err := TemplateName(ctx, projectID, v, true)
+	require.NoError(t, err)
+	require.Nil(t, name, "projects without a template leave names to the default")
+
+	require.Error(t, SetNaming(ctx, &model.ProjectExperimentNaming{
+		ProjectID: projectID, NameTemplate: ptrs.Ptr("{user}-{unknown}"),
+	}))
+	require.NoError(t, SetNaming(ctx, &model.ProjectExperimentNaming{
+		ProjectID: projectID, NameTemplate: ptrs.Ptr("{user}-{date}-{seq}"),
+	}))
+
+	name, err = TemplateName(ctx, projectID, v, false)
+	require.NoError(t, err)
+	require.Equal(t, "alice-2024-11-18-1", *name)
+	for _, expected := range []string{"alice-2024-11-18-1", "alice-2024-11-18-2"} {
+		name, err = TemplateName(ctx, projectID, v, true)
+		require.NoError(t, err)
+		require.Equal(t, expected, *name)
+	}
+
+	// Changing the template keeps the project's sequence.
+	require.NoError(t, SetNaming(ctx, &model.ProjectExperimentNaming{
+		ProjectID: projectID, NameTemplate: ptrs.Ptr("run-{seq}"),
+	}))
+	n, err := Naming(ctx, projectID)
+	require.NoError(t, err)
+	require.Equal(t, 3, n.NextSeq)
+}
+
+func TestUniqueNames(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	exp0 := db.RequireMockExperimentProject(t, db.SingleDB(), user, projectID)
+	exp1 := db.RequireMockExperimentProject(t, db.SingleDB(), user, projectID)
+
+	var name string
+	require.NoError(t, db.Bun().NewSelect().Table("experiments").
+		ColumnExpr("config->>'name'").Where("id = ?", exp0.ID).Scan(ctx, &name))
+	rename := func(id int, name string) error {
+		_, err := db.Bun().NewUpdate().Table("experiments").
+			Set("config = jsonb_set(config, '{name}', to_jsonb(?::text))", name).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	}
+
+	// Names may be shared until the project requires them to be unique.
+	require.NoError(t, CheckNameAvailable(ctx, projectID, name, 0))
+	require.NoError(t, rename(exp1.ID, name))
+	require.NoError(t, rename(exp1.ID, "other"))
+
+	require.NoError(t, SetNaming(ctx, &model.ProjectExperimentNaming{
+		ProjectID: projectID, UniqueNames: true,
+	}))
+	require.ErrorAs(t, CheckNameAvailable(ctx, projectID, name, 0), &NameTakenError{})
+	require.ErrorAs(t, CheckNameAvailable(ctx, projectID, name, exp1.ID), &NameTakenError{})
+	require.NoError(t, CheckNameAvailable(ctx, projectID, name, exp0.ID),
+		"an experiment's own name isn't taken")
+	require.NoError(t, CheckNameAvailable(ctx, projectID, "unused", 0))
+	require.Error(t, rename(exp1.ID, name), "the database rejects duplicate names")
+	require.NoError(t, rename(exp0.ID, name), "keeping its own name is allowed")
+}
@@ -0,0 +1,163 @@
+// Package fairness reports how fairly the queue treated users and workspaces over a time window,
+// from the wait times and slot usage of their historical allocations, so that admins can detect
+// chronic starvation and tune priorities.
+package fairness
+
+import (
+	"sort"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+)
+
+const (
+	// starvationFactor is how many times the median wait of all allocations the median wait of a
+	// user or workspace must be for it to be reported as starved.
+	starvationFactor = 2
+	// minStarvedWait is the median wait below which no user or workspace is reported as starved.
+	minStarvedWait = 5 * time.Minute
+	// minStarvedAllocations is the number of allocations below which a user or workspace waited too
+	// rarely to be reported as starved.
+	minStarvedAllocations = 3
+)
+
+// Allocation is an allocation that waited or ran during the window of a report.
+type Allocation struct {
+	Username      string  `bun:"username"`
+	WorkspaceName string  `bun:"workspace_name"`
+	WaitSeconds   float64 `bun:"wait_seconds"`
+	// SlotSeconds is the slot time the allocation used during the window.
+	SlotSeconds float64 `bun:"slot_seconds"`
+}
+
+// WaitStats summarizes how long allocations waited to be scheduled, in seconds.
+type WaitStats struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	Max  float64 `json:"max"`
+}
+
+// GroupStats is how the allocations of a user or workspace fared.
+type GroupStats struct {
+	Name        string    `json:"name"`
+	Allocations int       `json:"allocations"`
+	WaitSeconds WaitStats `json:"wait_seconds"`
+	SlotHours   float64   `json:"slot_hours"`
+	// ShareOfUsage is the fraction of the slot time used during the window that the group used.
+	ShareOfUsage float64 `json:"share_of_usage"`
+	// ShareOfCapacity is the fraction of the slot time agents offered during the window that the
+	// group used. It is unset when the capacity isn't known.
+	ShareOfCapacity *float64 `json:"share_of_capacity"`
+	// RelativeWait is the median wait of the group over the median wait of all allocations. It is
+	// unset when allocations didn't wait at all.
+	RelativeWait *float64 `json:"relative_wait"`
+	// Starved is set when the group waited much longer than allocations generally did.
+	Starved bool `json:"starved"`
+}
+
+// Report is the fairness report of a time window.
+type Report struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	ResourcePool string    `json:"resource_pool,omitempty"`
+	Allocations  int       `json:"allocations"`
+	WaitSeconds  WaitStats `json:"wait_seconds"`
+	// CapacitySlotHours is the slot time agents offered during the window, or 0 if it isn't
+	// known, such as for resource managers other than the agent RM.
+	CapacitySlotHours float64      `json:"capacity_slot_hours"`
+	UsedSlotHours     float64      `json:"used_slot_hours"`
+	Users             []GroupStats `json:"users"`
+	Workspaces        []GroupStats `json:"workspaces"`
+}
+
+// Compute builds the fairness report of the allocations of a window in which agents offered
+// capacitySlotSeconds of slot time. Users and workspaces are sorted from the longest to the
+// shortest median wait.
+func Compute(
+	start, end time.Time, pool string, allocations []Allocation, capacitySlotSeconds float64,
+) Report {
+	var waits []float64
+	var used float64
+	for _, a := range allocations {
+		waits = append(waits, a.WaitSeconds)
+		used += a.SlotSeconds
+	}
+	overall := waitStats(waits)
+	return Report{
+		Start:             start,
+		End:               end,
+		ResourcePool:      pool,
+		Allocations:       len(allocations),
+		WaitSeconds:       overall,
+		CapacitySlotHours: capacitySlotSeconds / 3600,
+		UsedSlotHours:     used / 3600,
+		Users: groupStats(allocations, func(a Allocation) string { return a.Username },
+			overall, used, capacitySlotSeconds),
+		Workspaces: groupStats(allocations, func(a Allocation) string { return a.WorkspaceName },
+			overall, used, capacitySlotSeconds),
+	}
+}
+
+func groupStats(
+	allocations []Allocation, key func(Allocation) string, overall WaitStats,
+	used, capacity float64,
+) []GroupStats {
+	waits := map[string][]float64{}
+	slotSeconds := map[string]float64{}
+	for _, a := range allocations {
+		waits[key(a)] = append(waits[key(a)], a.WaitSeconds)
+		slotSeconds[key(a)] += a.SlotSeconds
+	}
+
+	groups := []GroupStats{}
+	for name, w := range waits {
+		g := GroupStats{
+			Name:        name,
+			Allocations: len(w),
+			WaitSeconds: waitStats(w),
+			SlotHours:   slotSeconds[name] / 3600,
+		}
+		if used > 0 {
+			g.ShareOfUsage = slotSeconds[name] / used
+		}
+		if capacity > 0 {
+			share := slotSeconds[name] / capacity
+			g.ShareOfCapacity = &share
+		}
+		if overall.P50 > 0 {
+			relative := g.WaitSeconds.P50 / overall.P50
+			g.RelativeWait = &relative
+		}
+		g.Starved = g.Allocations >= minStarvedAllocations &&
+			g.WaitSeconds.P50 >= minStarvedWait.Seconds() &&
+			g.WaitSeconds.P50 >= starvationFactor*overall.P50
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].WaitSeconds.P50 != groups[j].WaitSeconds.P50 {
+			return groups[i].WaitSeconds.P50 > groups[j].WaitSeconds.P50
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// waitStats summarizes waits, or returns zero WaitStats if there are none.
+func waitStats(waits []float64) WaitStats {
+	if len(waits) == 0 {
+		return WaitStats{}
+	}
+	sorted := append([]float64(nil), waits...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, w := range sorted {
+		sum += w
+	}
+	return WaitStats{
+		Mean: sum / float64(len(sorted)),
+		P50:  mathx.Quantile(sorted, 0.5),
+		P90:  mathx.Quantile(sorted, 0.9),
+		Max:  sorted[len(sorted)-1],
+	}
+}
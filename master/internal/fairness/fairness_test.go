@@ -0,0 +1,52 @@
+package fairness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestCompute(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	allocations := []Allocation{
+		{Username: "alice", WorkspaceName: "nlp", WaitSeconds: 60, SlotSeconds: 3600},
+		{Username: "alice", WorkspaceName: "nlp", WaitSeconds: 60, SlotSeconds: 3600},
+		{Username: "alice", WorkspaceName: "vision", WaitSeconds: 120, SlotSeconds: 7200},
+		{Username: "bob", WorkspaceName: "vision", WaitSeconds: 3600, SlotSeconds: 1800},
+		{Username: "bob", WorkspaceName: "vision", WaitSeconds: 7200, SlotSeconds: 1800},
+		{Username: "bob", WorkspaceName: "vision", WaitSeconds: 1800, SlotSeconds: 0},
+	}
+
+	report := Compute(start, end, "", allocations, 36000)
+	require.Equal(t, 6, report.Allocations)
+	require.Equal(t, 10.0, report.CapacitySlotHours)
+	require.Equal(t, 5.0, report.UsedSlotHours)
+	require.Equal(t, 960.0, report.WaitSeconds.P50)
+
+	// Bob waited the longest, so he comes first, and waited long enough to be starved.
+	require.Len(t, report.Users, 2)
+	bob, alice := report.Users[0], report.Users[1]
+	require.Equal(t, "bob", bob.Name)
+	require.Equal(t, 3, bob.Allocations)
+	require.Equal(t, 3600.0, bob.WaitSeconds.P50)
+	require.Equal(t, 0.2, bob.ShareOfUsage)
+	require.Equal(t, ptrs.Ptr(0.1), bob.ShareOfCapacity)
+	require.Equal(t, ptrs.Ptr(3600.0/960), bob.RelativeWait)
+	require.True(t, bob.Starved)
+	require.Equal(t, "alice", alice.Name)
+	require.Equal(t, 0.8, alice.ShareOfUsage)
+	require.False(t, alice.Starved)
+
+	require.Equal(t, []string{"vision", "nlp"},
+		[]string{report.Workspaces[0].Name, report.Workspaces[1].Name})
+
+	// Without capacity or waits, shares of capacity and relative waits are unset.
+	report = Compute(start, end, "", []Allocation{{Username: "alice"}}, 0)
+	require.Nil(t, report.Users[0].ShareOfCapacity)
+	require.Nil(t, report.Users[0].RelativeWait)
+	require.False(t, report.Users[0].Starved)
+}
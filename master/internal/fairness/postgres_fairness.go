@@ -0,0 +1,88 @@
+package fairness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// Allocations returns the allocations that waited or ran during a window, optionally only those of
+// one resource pool. Allocations wait from the submission of their job until they are scheduled;
+// allocations that were never scheduled wait from the creation of their task until they ended, or
+// until now.
+func Allocations(
+	ctx context.Context, start, end time.Time, pool string,
+) ([]Allocation, error) {
+	queued := db.Bun().NewSelect().
+		TableExpr("task_stats AS q").
+		ColumnExpr("MIN(q.start_time)").
+		Where("q.allocation_id = a.allocation_id").
+		Where("q.event_type = 'QUEUED'")
+	scheduled := db.Bun().NewSelect().
+		TableExpr("task_stats AS q").
+		ColumnExpr("MAX(q.end_time)").
+		Where("q.allocation_id = a.allocation_id").
+		Where("q.event_type = 'QUEUED'")
+
+	allocations := []Allocation{}
+	err := db.Bun().NewSelect().
+		TableExpr("allocations AS a").
+		ColumnExpr("COALESCE(u.username, '') AS username").
+		ColumnExpr("COALESCE(awi.workspace_name, '') AS workspace_name").
+		ColumnExpr(`EXTRACT(EPOCH FROM CASE
+			WHEN a.start_time IS NULL THEN COALESCE(a.end_time, now()) - t.start_time
+			ELSE COALESCE((?), a.start_time) - COALESCE((?), a.start_time)
+		END) AS wait_seconds`, scheduled, queued).
+		ColumnExpr(`CASE WHEN a.start_time IS NULL THEN 0.0 ELSE EXTRACT(EPOCH FROM (
+			LEAST(GREATEST(COALESCE(a.end_time, now()), a.start_time), ?::timestamptz) -
+			GREATEST(a.start_time, ?::timestamptz)
+		)) * a.slots END AS slot_seconds`, end, start).
+		Join("JOIN tasks AS t ON t.task_id = a.task_id").
+		Join("LEFT JOIN jobs AS j ON j.job_id = t.job_id").
+		Join("LEFT JOIN users AS u ON u.id = j.owner_id").
+		Join("LEFT JOIN allocation_workspace_info AS awi ON awi.allocation_id = a.allocation_id").
+		Where("tstzrange(COALESCE(a.start_time, t.start_time), "+
+			"GREATEST(COALESCE(a.start_time, t.start_time), COALESCE(a.end_time, now()))) && "+
+			"tstzrange(?::timestamptz, ?::timestamptz)", start, end).
+		Apply(func(q *bun.SelectQuery) *bun.SelectQuery {
+			if pool != "" {
+				q = q.Where("a.resource_pool = ?", pool)
+			}
+			return q
+		}).
+		Scan(ctx, &allocations)
+	if err != nil {
+		return nil, fmt.Errorf("getting allocations between %s and %s: %w", start, end, err)
+	}
+	return allocations, nil
+}
+
+// CapacitySlotSeconds returns the slot time agents offered during a window, optionally only the
+// agents of one resource pool.
+func CapacitySlotSeconds(
+	ctx context.Context, start, end time.Time, pool string,
+) (float64, error) {
+	var capacity float64
+	err := db.Bun().NewSelect().
+		TableExpr("agent_stats AS s").
+		ColumnExpr(`COALESCE(SUM(EXTRACT(EPOCH FROM (
+			LEAST(COALESCE(s.end_time, now()), ?::timestamptz) - GREATEST(s.start_time, ?::timestamptz)
+		)) * s.slots), 0)`, end, start).
+		Where("tstzrange(s.start_time, GREATEST(s.start_time, COALESCE(s.end_time, now()))) && "+
+			"tstzrange(?::timestamptz, ?::timestamptz)", start, end).
+		Apply(func(q *bun.SelectQuery) *bun.SelectQuery {
+			if pool != "" {
+				q = q.Where("s.resource_pool = ?", pool)
+			}
+			return q
+		}).
+		Scan(ctx, &capacity)
+	if err != nil {
+		return 0, fmt.Errorf("getting agent capacity between %s and %s: %w", start, end, err)
+	}
+	return capacity, nil
+}
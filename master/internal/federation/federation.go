@@ -0,0 +1,360 @@
+// Package federation reads experiments and jobs from peer Determined masters, so that one master
+// can show a read-only view of them merged with its own.
+package federation
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/egress"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+const (
+	// DefaultExperimentLimit and MaxExperimentLimit bound how many experiments are read from each
+	// cluster.
+	DefaultExperimentLimit = 100
+	MaxExperimentLimit     = 1000
+	// MaxJobsPerPool bounds how many jobs are read from each of a cluster's queues.
+	MaxJobsPerPool = 1000
+
+	peerTimeout = 10 * time.Second
+)
+
+// Cluster is the status of a cluster whose experiments or jobs are in a merged view.
+type Cluster struct {
+	Name string `json:"name"`
+	// URL is empty for this master's own cluster.
+	URL string `json:"url,omitempty"`
+	// Error is why the cluster's experiments or jobs are missing from the view, if they are.
+	Error string `json:"error,omitempty"`
+}
+
+// Proto converts a cluster to its protobuf representation.
+func (c Cluster) Proto() *masterv1.FederationCluster {
+	pb := &masterv1.FederationCluster{Name: c.Name}
+	if c.URL != "" {
+		pb.Url = ptrs.Ptr(c.URL)
+	}
+	if c.Error != "" {
+		pb.Error = ptrs.Ptr(c.Error)
+	}
+	return pb
+}
+
+// Experiment is an experiment in a merged view.
+type Experiment struct {
+	Cluster      string             `json:"cluster"`
+	ID           int32              `json:"id"`
+	Name         string             `json:"name"`
+	State        experimentv1.State `json:"state"`
+	Username     string             `json:"username"`
+	ProjectID    int32              `json:"project_id"`
+	WorkspaceID  int32              `json:"workspace_id"`
+	ResourcePool string             `json:"resource_pool"`
+	StartTime    time.Time          `json:"start_time"`
+	EndTime      *time.Time         `json:"end_time"`
+	Progress     *float64           `json:"progress"`
+}
+
+// ExperimentFromProto flattens an experiment from a cluster's API.
+func ExperimentFromProto(cluster string, e *experimentv1.Experiment) Experiment {
+	out := Experiment{
+		Cluster:      cluster,
+		ID:           e.Id,
+		Name:         e.Name,
+		State:        e.State,
+		Username:     e.Username,
+		ProjectID:    e.ProjectId,
+		WorkspaceID:  e.WorkspaceId,
+		ResourcePool: e.ResourcePool,
+		StartTime:    e.StartTime.AsTime(),
+	}
+	if e.EndTime != nil {
+		endTime := e.EndTime.AsTime()
+		out.EndTime = &endTime
+	}
+	if e.Progress != nil {
+		progress := e.Progress.Value
+		out.Progress = &progress
+	}
+	return out
+}
+
+// Proto converts an experiment to its protobuf representation.
+func (e Experiment) Proto() *masterv1.FederationExperiment {
+	pb := &masterv1.FederationExperiment{
+		Cluster:      e.Cluster,
+		Id:           e.ID,
+		Name:         e.Name,
+		State:        e.State,
+		Username:     e.Username,
+		ProjectId:    e.ProjectID,
+		WorkspaceId:  e.WorkspaceID,
+		ResourcePool: e.ResourcePool,
+		StartTime:    timestamppb.New(e.StartTime),
+		Progress:     e.Progress,
+	}
+	if e.EndTime != nil {
+		pb.EndTime = timestamppb.New(*e.EndTime)
+	}
+	return pb
+}
+
+// SortExperiments sorts experiments from newest to oldest.
+func SortExperiments(experiments []Experiment) {
+	sort.SliceStable(experiments, func(i, j int) bool {
+		return experiments[i].StartTime.After(experiments[j].StartTime)
+	})
+}
+
+// Job is a job in a merged view.
+type Job struct {
+	Cluster        string      `json:"cluster"`
+	JobID          string      `json:"job_id"`
+	Type           jobv1.Type  `json:"type"`
+	Name           string      `json:"name"`
+	Username       string      `json:"username"`
+	WorkspaceID    int32       `json:"workspace_id"`
+	ResourcePool   string      `json:"resource_pool"`
+	State          jobv1.State `json:"state"`
+	JobsAhead      int32       `json:"jobs_ahead"`
+	SubmissionTime time.Time   `json:"submission_time"`
+	RequestedSlots int32       `json:"requested_slots"`
+	AllocatedSlots int32       `json:"allocated_slots"`
+	Progress       float32     `json:"progress"`
+}
+
+// JobFromProto flattens a job from a cluster's API.
+func JobFromProto(cluster string, j *jobv1.Job) Job {
+	return Job{
+		Cluster:        cluster,
+		JobID:          j.JobId,
+		Type:           j.Type,
+		Name:           j.Name,
+		Username:       j.Username,
+		WorkspaceID:    j.WorkspaceId,
+		ResourcePool:   j.ResourcePool,
+		State:          j.Summary.GetState(),
+		JobsAhead:      j.Summary.GetJobsAhead(),
+		SubmissionTime: j.SubmissionTime.AsTime(),
+		RequestedSlots: j.RequestedSlots,
+		AllocatedSlots: j.AllocatedSlots,
+		Progress:       j.Progress,
+	}
+}
+
+// Proto converts a job to its protobuf representation.
+func (j Job) Proto() *masterv1.FederationJob {
+	return &masterv1.FederationJob{
+		Cluster:        j.Cluster,
+		JobId:          j.JobID,
+		Type:           j.Type,
+		Name:           j.Name,
+		Username:       j.Username,
+		WorkspaceId:    j.WorkspaceID,
+		ResourcePool:   j.ResourcePool,
+		State:          j.State,
+		JobsAhead:      j.JobsAhead,
+		SubmissionTime: timestamppb.New(j.SubmissionTime),
+		RequestedSlots: j.RequestedSlots,
+		AllocatedSlots: j.AllocatedSlots,
+		Progress:       j.Progress,
+	}
+}
+
+// SortJobs sorts jobs from the earliest submitted to the latest.
+func SortJobs(jobs []Job) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].SubmissionTime.Before(jobs[j].SubmissionTime)
+	})
+}
+
+// Peer is a Determined master whose experiments and jobs are read with a token.
+type Peer struct {
+	name   string
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewPeers creates the configured peers.
+func NewPeers(conf config.FederationConfig) ([]*Peer, error) {
+	var peers []*Peer
+	for _, pc := range conf.Peers {
+		p, err := NewPeer(pc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "configuring federation peer %s", pc.Name)
+		}
+		peers = append(peers, p)
+	}
+	return peers, nil
+}
+
+// NewPeer creates a peer.
+func NewPeer(conf config.FederationPeerConfig) (*Peer, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: conf.SkipVerify, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+	if conf.CertificatePath != "" {
+		certBytes, err := os.ReadFile(conf.CertificatePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading certificate")
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(certBytes) {
+			return nil, errors.New("certificate file contains no certificates")
+		}
+	}
+
+	transport := egress.Transport()
+	transport.TLSClientConfig = tlsConfig
+	return &Peer{
+		name:   conf.Name,
+		url:    strings.TrimSuffix(conf.URL, "/"),
+		token:  conf.Token,
+		client: &http.Client{Transport: transport, Timeout: peerTimeout},
+	}, nil
+}
+
+// Name returns the name of the peer.
+func (p *Peer) Name() string {
+	return p.name
+}
+
+func (p *Peer) get(ctx context.Context, path string, query url.Values, out proto.Message) error {
+	u := p.url + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "reading response of %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	// Peers may run newer versions of Determined, whose responses have fields this one lacks.
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, out); err != nil {
+		return errors.Wrapf(err, "parsing response of %s", path)
+	}
+	return nil
+}
+
+// Experiments returns up to limit of the peer's unarchived experiments, newest first.
+func (p *Peer) Experiments(ctx context.Context, limit int) ([]Experiment, error) {
+	var resp apiv1.GetExperimentsResponse
+	if err := p.get(ctx, "/api/v1/experiments", url.Values{
+		"limit":    {strconv.Itoa(limit)},
+		"archived": {"false"},
+		"sortBy":   {apiv1.GetExperimentsRequest_SORT_BY_START_TIME.String()},
+		"orderBy":  {apiv1.OrderBy_ORDER_BY_DESC.String()},
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	out := make([]Experiment, 0, len(resp.Experiments))
+	for _, e := range resp.Experiments {
+		out = append(out, ExperimentFromProto(p.name, e))
+	}
+	return out, nil
+}
+
+// Jobs returns the jobs in the queues of every one of the peer's resource pools.
+func (p *Peer) Jobs(ctx context.Context) ([]Job, error) {
+	var pools apiv1.GetResourcePoolsResponse
+	if err := p.get(ctx, "/api/v1/resource-pools", nil, &pools); err != nil {
+		return nil, err
+	}
+
+	var out []Job
+	for _, pool := range pools.ResourcePools {
+		var resp apiv1.GetJobsResponse
+		if err := p.get(ctx, "/api/v1/job-queues", url.Values{
+			"resourcePool": {pool.Name},
+			"limit":        {strconv.Itoa(MaxJobsPerPool)},
+		}, &resp); err != nil {
+			return nil, err
+		}
+		for _, j := range resp.Jobs {
+			out = append(out, JobFromProto(p.name, j))
+		}
+	}
+	return out, nil
+}
+
+// Experiments reads experiments from every peer concurrently. A peer that can't be read is
+// reported with an error rather than failing the whole view.
+func Experiments(ctx context.Context, peers []*Peer, limit int) ([]Experiment, []Cluster) {
+	return gather(peers, func(p *Peer) ([]Experiment, error) {
+		return p.Experiments(ctx, limit)
+	})
+}
+
+// Jobs reads jobs from every peer concurrently. A peer that can't be read is reported with an
+// error rather than failing the whole view.
+func Jobs(ctx context.Context, peers []*Peer) ([]Job, []Cluster) {
+	return gather(peers, func(p *Peer) ([]Job, error) {
+		return p.Jobs(ctx)
+	})
+}
+
+func gather[T any](peers []*Peer, read func(*Peer) ([]T, error)) ([]T, []Cluster) {
+	results := make([][]T, len(peers))
+	clusters := make([]Cluster, len(peers))
+	var wg sync.WaitGroup
+	for i, p := range peers {
+		clusters[i] = Cluster{Name: p.name, URL: p.url}
+		wg.Add(1)
+		go func(i int, p *Peer) {
+			defer wg.Done()
+			items, err := read(p)
+			if err != nil {
+				clusters[i].Error = err.Error()
+				return
+			}
+			results[i] = items
+		}(i, p)
+	}
+	wg.Wait()
+
+	var out []T
+	for _, items := range results {
+		out = append(out, items...)
+	}
+	return out, clusters
+}
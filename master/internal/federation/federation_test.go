@@ -0,0 +1,126 @@
+package federation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
+)
+
+const testToken = "peer-token"
+
+func newTestPeer(t *testing.T) *Peer {
+	start := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	responses := map[string]proto.Message{
+		"/api/v1/experiments": &apiv1.GetExperimentsResponse{
+			Experiments: []*experimentv1.Experiment{
+				{Id: 7, Name: "mnist", State: experimentv1.State_STATE_ACTIVE, StartTime: timestamppb.New(start)},
+			},
+		},
+		"/api/v1/resource-pools": &apiv1.GetResourcePoolsResponse{
+			ResourcePools: []*resourcepoolv1.ResourcePool{{Name: "gpu"}},
+		},
+		"/api/v1/job-queues": &apiv1.GetJobsResponse{
+			Jobs: []*jobv1.Job{{
+				JobId:          "job-1",
+				Type:           jobv1.Type_TYPE_EXPERIMENT,
+				ResourcePool:   "gpu",
+				Summary:        &jobv1.JobSummary{State: jobv1.State_STATE_QUEUED, JobsAhead: 2},
+				SubmissionTime: timestamppb.New(start),
+			}},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		resp, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == "/api/v1/job-queues" {
+			require.Equal(t, "gpu", r.URL.Query().Get("resourcePool"))
+		}
+		body, err := protojson.Marshal(resp)
+		require.NoError(t, err)
+		_, err = w.Write(body)
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	p, err := NewPeer(config.FederationPeerConfig{Name: "east", URL: server.URL + "/", Token: testToken})
+	require.NoError(t, err)
+	return p
+}
+
+func TestPeer(t *testing.T) {
+	ctx := context.Background()
+	p := newTestPeer(t)
+
+	experiments, err := p.Experiments(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, []Experiment{{
+		Cluster:   "east",
+		ID:        7,
+		Name:      "mnist",
+		State:     experimentv1.State_STATE_ACTIVE,
+		StartTime: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+	}}, experiments)
+
+	jobs, err := p.Jobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "east", jobs[0].Cluster)
+	require.Equal(t, "job-1", jobs[0].JobID)
+	require.Equal(t, jobv1.State_STATE_QUEUED, jobs[0].State)
+	require.Equal(t, int32(2), jobs[0].JobsAhead)
+}
+
+func TestGatherReportsFailingPeers(t *testing.T) {
+	good := newTestPeer(t)
+	bad, err := NewPeer(config.FederationPeerConfig{Name: "west", URL: good.url, Token: "wrong"})
+	require.NoError(t, err)
+
+	experiments, clusters := Experiments(context.Background(), []*Peer{good, bad}, 10)
+	require.Len(t, experiments, 1)
+	require.Equal(t, "east", experiments[0].Cluster)
+
+	require.Len(t, clusters, 2)
+	require.Equal(t, "east", clusters[0].Name)
+	require.Empty(t, clusters[0].Error)
+	require.Equal(t, "west", clusters[1].Name)
+	require.Contains(t, clusters[1].Error, "401")
+}
+
+func TestProto(t *testing.T) {
+	pb := Cluster{Name: "local"}.Proto()
+	require.Nil(t, pb.Url)
+	require.Nil(t, pb.Error)
+	pb = Cluster{Name: "west", URL: "https://west", Error: "unreachable"}.Proto()
+	require.Equal(t, "https://west", pb.GetUrl())
+	require.Equal(t, "unreachable", pb.GetError())
+
+	progress := 0.5
+	e := Experiment{ID: 7, State: experimentv1.State_STATE_ACTIVE, Progress: &progress}.Proto()
+	require.Equal(t, experimentv1.State_STATE_ACTIVE, e.State)
+	require.Equal(t, 0.5, e.GetProgress())
+	require.Nil(t, e.EndTime)
+
+	j := Job{JobID: "job-1", Type: jobv1.Type_TYPE_EXPERIMENT}.Proto()
+	require.Equal(t, jobv1.Type_TYPE_EXPERIMENT, j.Type)
+}
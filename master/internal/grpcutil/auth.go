@@ -45,9 +45,10 @@ type (
 )
 
 var unauthenticatedMethods = map[string]bool{
-	"/determined.api.v1.Determined/Login":        true,
-	"/determined.api.v1.Determined/GetMaster":    true,
-	"/determined.api.v1.Determined/GetTelemetry": true,
+	"/determined.api.v1.Determined/Login":                   true,
+	"/determined.api.v1.Determined/GetMaster":               true,
+	"/determined.api.v1.Determined/GetTelemetry":            true,
+	"/determined.api.v1.Determined/GetCurrentAnnouncements": true,
 }
 
 var (
@@ -110,6 +111,12 @@ func getAllocationSessionBun(ctx context.Context) (*model.AllocationSession, err
 	}
 }
 
+// ContextWithUser returns a context that GetUser returns the given user for. It lets HTTP
+// handlers, which authenticate users themselves, share code with the gRPC API.
+func ContextWithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
 // GetUser returns the currently logged in user.
 func GetUser(ctx context.Context) (*model.User, *model.UserSession, error) {
 	if user, ok := ctx.Value(userContextKey{}).(*model.User); ok {
@@ -0,0 +1,77 @@
+// Package httppolicy applies the CORS and Content Security Policy of the master's HTTP server,
+// which admins may change while the master runs.
+package httppolicy
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+var (
+	mu     sync.RWMutex
+	policy config.HTTPPolicyConfig
+)
+
+// Set replaces the policy applied to requests. It must have been validated.
+func Set(c config.HTTPPolicyConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	policy = c
+}
+
+// Get returns the policy applied to requests.
+func Get() config.HTTPPolicyConfig {
+	mu.RLock()
+	defer mu.RUnlock()
+	return policy
+}
+
+// Middleware applies the current policy to each request: it answers cross-origin requests from
+// allowed origins and sets the Content Security Policy and framing headers of responses.
+func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		p := Get()
+
+		header := c.Response().Header()
+		switch {
+		case p.ContentSecurityPolicy == "":
+		case p.ContentSecurityPolicyReportOnly:
+			header.Set(echo.HeaderContentSecurityPolicyReportOnly, p.ContentSecurityPolicy)
+		default:
+			header.Set(echo.HeaderContentSecurityPolicy, p.ContentSecurityPolicy)
+		}
+		if !p.FrameAncestors() {
+			header.Set(echo.HeaderXFrameOptions, "SAMEORIGIN")
+		}
+
+		origin := c.Request().Header.Get(echo.HeaderOrigin)
+		if origin == "" || !p.CORS.OriginAllowed(origin) {
+			return next(c)
+		}
+		return middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins:     []string{origin},
+			AllowCredentials: p.CORS.AllowCredentials,
+			MaxAge:           p.CORS.MaxAge,
+		})(next)(c)
+	}
+}
+
+// CheckOrigin returns whether a websocket may be opened from the origin of a request: the
+// master's own or one the current policy lists without a wildcard.
+func CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get(echo.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return Get().CORS.OriginListed(origin)
+}
@@ -0,0 +1,88 @@
+package httppolicy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+func serve(method, origin string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(Middleware)
+	e.GET("/", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	req := httptest.NewRequest(method, "/", nil)
+	if origin != "" {
+		req.Header.Set(echo.HeaderOrigin, origin)
+	}
+	if method == http.MethodOptions {
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	defer Set(config.HTTPPolicyConfig{})
+
+	rec := serve(http.MethodGet, "https://portal.example.com")
+	require.Equal(t, "SAMEORIGIN", rec.Header().Get(echo.HeaderXFrameOptions))
+	require.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	require.Empty(t, rec.Header().Get(echo.HeaderContentSecurityPolicy))
+
+	Set(config.HTTPPolicyConfig{
+		CORS: config.CORSConfig{
+			AllowedOrigins:   []string{"https://portal.example.com"},
+			AllowCredentials: true,
+		},
+		ContentSecurityPolicy: "frame-ancestors 'self' https://*.example.com",
+	})
+	rec = serve(http.MethodGet, "https://portal.example.com")
+	require.Equal(t, "https://portal.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	require.Equal(t, "true", rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+	require.Equal(t, "frame-ancestors 'self' https://*.example.com",
+		rec.Header().Get(echo.HeaderContentSecurityPolicy))
+	require.Empty(t, rec.Header().Get(echo.HeaderXFrameOptions))
+
+	rec = serve(http.MethodOptions, "https://portal.example.com")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, "https://portal.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	rec = serve(http.MethodGet, "https://example.org")
+	require.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	Set(config.HTTPPolicyConfig{
+		ContentSecurityPolicy:           "frame-ancestors 'none'",
+		ContentSecurityPolicyReportOnly: true,
+	})
+	rec = serve(http.MethodGet, "")
+	require.Equal(t, "frame-ancestors 'none'", rec.Header().Get(echo.HeaderContentSecurityPolicyReportOnly))
+	require.Equal(t, "SAMEORIGIN", rec.Header().Get(echo.HeaderXFrameOptions))
+}
+
+func TestCheckOrigin(t *testing.T) {
+	defer Set(config.HTTPPolicyConfig{})
+	Set(config.HTTPPolicyConfig{CORS: config.CORSConfig{
+		AllowedOrigins: []string{"https://portal.example.com", "https://*.tools.example.com", "*"},
+	}})
+
+	for origin, expected := range map[string]bool{
+		"":                                true,
+		"http://master.example.com:8080":  true,
+		"https://portal.example.com":      true,
+		"http://portal.example.com":       false,
+		"https://evil.example.com":        false,
+		"https://board.tools.example.com": false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://master.example.com:8080/stream", nil)
+		if origin != "" {
+			req.Header.Set(echo.HeaderOrigin, origin)
+		}
+		require.Equal(t, expected, CheckOrigin(req), origin)
+	}
+}
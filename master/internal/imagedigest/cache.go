@@ -0,0 +1,68 @@
+package imagedigest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/registry"
+)
+
+// resolveCacheTTL is how long the digest an image resolved to, or the error resolving it, is
+// reused. Caching errors keeps clusters that can't reach a registry from waiting on it every time
+// an experiment is submitted.
+const resolveCacheTTL = 5 * time.Minute
+
+type resolveCacheKey struct {
+	image, serverAddress, username string
+}
+
+type resolveCacheEntry struct {
+	pinned  string
+	err     error
+	expires time.Time
+}
+
+// resolveCache remembers the results of resolving images for a while.
+type resolveCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[resolveCacheKey]resolveCacheEntry
+}
+
+func newResolveCache(now func() time.Time) *resolveCache {
+	return &resolveCache{now: now, entries: map[resolveCacheKey]resolveCacheEntry{}}
+}
+
+var defaultResolveCache = newResolveCache(time.Now)
+
+// resolve resolves an image like Resolve, reusing a recent result unless fresh is set.
+func (c *resolveCache) resolve(
+	ctx context.Context, client *http.Client, image string, auth *registry.AuthConfig, fresh bool,
+) (string, error) {
+	key := resolveCacheKey{image: image}
+	if auth != nil {
+		key.serverAddress, key.username = auth.ServerAddress, auth.Username
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && !fresh && c.now().Before(entry.expires) {
+		return entry.pinned, entry.err
+	}
+
+	pinned, err := Resolve(ctx, client, image, auth)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = resolveCacheEntry{pinned: pinned, err: err, expires: now.Add(resolveCacheTTL)}
+	return pinned, err
+}
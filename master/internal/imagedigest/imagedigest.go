@@ -0,0 +1,174 @@
+package imagedigest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+	dockerregistry "github.com/docker/docker/registry"
+)
+
+// manifestMediaTypes are the manifest types the digests of images are resolved from. Listing the
+// multi-platform types first resolves a tag to the same digest docker pulls it by.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// challengeParamRegex matches the parameters of a WWW-Authenticate challenge.
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Resolve returns the reference of an image by the digest of the manifest its tag points to in its
+// registry, so that pulling it later gets the same image even if the tag moves. Images that are
+// already referenced by digest are returned as they are.
+func Resolve(
+	ctx context.Context, client *http.Client, image string, auth *registry.AuthConfig,
+) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("parsing image %s: %w", image, err)
+	}
+	if _, ok := named.(reference.Digested); ok {
+		return image, nil
+	}
+	tagged, ok := reference.TagNameOnly(named).(reference.NamedTagged)
+	if !ok {
+		return "", fmt.Errorf("image %s has neither a tag nor a digest", image)
+	}
+
+	domain := reference.Domain(named)
+	if auth != nil && auth.ServerAddress != "" &&
+		dockerregistry.ConvertToHostname(auth.ServerAddress) != domain {
+		// Like the agents, only send the credentials to the registry they are for.
+		auth = nil
+	}
+	host := domain
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s",
+		host, reference.Path(named), tagged.Tag())
+
+	resp, err := headManifest(ctx, client, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := authorize(ctx, client, resp.Header.Get("WWW-Authenticate"), auth)
+		if err != nil {
+			return "", fmt.Errorf("authorizing with the registry of %s: %w", image, err)
+		}
+		if resp, err = headManifest(ctx, client, manifestURL, authorization); err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("looking up the manifest of %s: registry responded %s", image, resp.Status)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("the registry of %s did not respond with the digest of its manifest",
+			image)
+	}
+	pinned, err := reference.ParseNormalizedNamed(named.Name() + "@" + digest)
+	if err != nil {
+		return "", fmt.Errorf("parsing digest of %s: %w", image, err)
+	}
+	return reference.FamiliarString(pinned), nil
+}
+
+// headManifest requests the headers of a manifest, which include its digest.
+func headManifest(
+	ctx context.Context, client *http.Client, manifestURL, authorization string,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting manifest %s: %w", manifestURL, err)
+	}
+	defer resp.Body.Close()
+	return resp, nil
+}
+
+// authorize answers the challenge of a registry and returns the Authorization header to retry
+// with, which for bearer challenges means getting a token from the registry's token service.
+func authorize(
+	ctx context.Context, client *http.Client, challenge string, auth *registry.AuthConfig,
+) (string, error) {
+	scheme, rawParams, _ := strings.Cut(challenge, " ")
+	params := map[string]string{}
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(rawParams, -1) {
+		params[match[1]] = match[2]
+	}
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if auth == nil {
+			return "", fmt.Errorf("the registry requires credentials")
+		}
+		req, err := http.NewRequest(http.MethodGet, "", nil)
+		if err != nil {
+			return "", err
+		}
+		req.SetBasicAuth(auth.Username, auth.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		realm, err := url.Parse(params["realm"])
+		if err != nil || realm.Host == "" {
+			return "", fmt.Errorf("invalid token realm %q", params["realm"])
+		}
+		query := realm.Query()
+		for _, key := range []string{"service", "scope"} {
+			if params[key] != "" {
+				query.Set(key, params[key])
+			}
+		}
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if auth != nil && auth.Username != "" {
+			req.SetBasicAuth(auth.Username, auth.Password)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("requesting token: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("requesting token: token service responded %s", resp.Status)
+		}
+		var body struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return "", fmt.Errorf("decoding token: %w", err)
+		}
+		token := body.Token
+		if token == "" {
+			token = body.AccessToken
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("unsupported authentication scheme %q", scheme)
+	}
+}
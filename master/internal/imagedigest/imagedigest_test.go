@@ -0,0 +1,182 @@
+package imagedigest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+var (
+	digestA = "sha256:" + strings.Repeat("a", 64)
+	digestB = "sha256:" + strings.Repeat("b", 64)
+)
+
+// fakeRegistry serves the digests of the manifests of tags, like a registry that requires a
+// bearer token from its token service.
+type fakeRegistry struct {
+	server  *httptest.Server
+	digests map[string]string
+}
+
+func newFakeRegistry(t *testing.T, digests map[string]string) *fakeRegistry {
+	r := &fakeRegistry{digests: digests}
+	r.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			require.Equal(t, "repository:team/model:pull", req.URL.Query().Get("scope"))
+			fmt.Fprint(w, `{"token": "secret"}`)
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer realm="%s/token",service="fake",scope="repository:team/model:pull"`,
+				r.server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		digest, ok := r.digests[strings.TrimPrefix(req.URL.Path, "/v2/team/model/manifests/")]
+		if req.Method != http.MethodHead || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", digest)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *fakeRegistry) image(ref string) string {
+	return strings.TrimPrefix(r.server.URL, "https://") + "/team/model" + ref
+}
+
+func TestResolve(t *testing.T) {
+	r := newFakeRegistry(t, map[string]string{"v1": digestA, "latest": digestB})
+	ctx := context.Background()
+	client := r.server.Client()
+
+	pinned, err := Resolve(ctx, client, r.image(":v1"), nil)
+	require.NoError(t, err)
+	require.Equal(t, r.image("@"+digestA), pinned)
+
+	pinned, err = Resolve(ctx, client, r.image(""), nil)
+	require.NoError(t, err)
+	require.Equal(t, r.image("@"+digestB), pinned)
+
+	pinned, err = Resolve(ctx, client, r.image("@"+digestA), nil)
+	require.NoError(t, err)
+	require.Equal(t, r.image("@"+digestA), pinned)
+
+	_, err = Resolve(ctx, client, r.image(":missing"), nil)
+	require.ErrorContains(t, err, "404")
+}
+
+func TestPin(t *testing.T) {
+	r := newFakeRegistry(t, map[string]string{"v1": digestA, "gpu": digestA})
+	ctx := context.Background()
+	config := func(mode, cpu, cuda string) expconf.ExperimentConfig {
+		return expconf.ExperimentConfig{RawEnvironment: &expconf.EnvironmentConfig{
+			RawImage: &expconf.EnvironmentImageMap{
+				RawCPU:  ptrs.Ptr(cpu),
+				RawCUDA: ptrs.Ptr(cuda),
+				RawROCM: ptrs.Ptr(cpu),
+			},
+			RawImagePinning: ptrs.Ptr(mode),
+		}}
+	}
+	images := func(c expconf.ExperimentConfig) []string {
+		i := c.Environment().Image()
+		return []string{i.For(device.CPU), i.For(device.CUDA), i.For(device.ROCM)}
+	}
+
+	t.Run("off", func(t *testing.T) {
+		pinned, pins, err := Pin(ctx, r.server.Client(),
+			config(expconf.NoImagePinning, r.image(":v1"), r.image(":gpu")), nil)
+		require.NoError(t, err)
+		require.Empty(t, pins)
+		require.Equal(t, []string{r.image(":v1"), r.image(":gpu"), r.image(":v1")}, images(pinned))
+	})
+
+	t.Run("pin", func(t *testing.T) {
+		pinned, pins, err := Pin(ctx, r.server.Client(),
+			config(expconf.PinImages, r.image(":v1"), r.image(":gpu")), nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			r.image("@" + digestA), r.image("@" + digestA), r.image("@" + digestA),
+		}, images(pinned))
+		require.Equal(t, []model.ExperimentImagePin{
+			{DeviceType: device.CPU, Image: r.image(":v1"), PinnedImage: r.image("@" + digestA)},
+			{DeviceType: device.CUDA, Image: r.image(":gpu"), PinnedImage: r.image("@" + digestA)},
+			{DeviceType: device.ROCM, Image: r.image(":v1"), PinnedImage: r.image("@" + digestA)},
+		}, pins)
+
+		// The tags moving doesn't change the images of experiments continued or forked from it.
+		r.digests["v1"] = digestB
+		repinned, repins, err := Pin(ctx, r.server.Client(), pinned, pins)
+		require.NoError(t, err)
+		require.Equal(t, images(pinned), images(repinned))
+		require.Equal(t, pins, repins)
+
+		// Unless they repin.
+		pinned.RawEnvironment.SetImagePinning(expconf.RepinImages)
+		repinned, repins, err = Pin(ctx, r.server.Client(), pinned, pins)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			r.image("@" + digestB), r.image("@" + digestA), r.image("@" + digestB),
+		}, images(repinned))
+		require.Equal(t, r.image(":v1"), repins[0].Image)
+		require.Equal(t, expconf.PinImages, repinned.Environment().ImagePinning())
+	})
+
+	t.Run("unresolvable", func(t *testing.T) {
+		pinned, pins, err := Pin(ctx, r.server.Client(),
+			config(expconf.PinImages, r.image(":missing"), r.image(":gpu")), nil)
+		require.ErrorContains(t, err, "pinning cpu image")
+		require.ErrorContains(t, err, "pinning rocm image")
+		require.Equal(t, []string{
+			r.image(":missing"), r.image("@" + digestA), r.image(":missing"),
+		}, images(pinned))
+		require.Len(t, pins, 1)
+	})
+}
+
+func TestResolveCache(t *testing.T) {
+	r := newFakeRegistry(t, map[string]string{"v1": digestA})
+	ctx := context.Background()
+	client := r.server.Client()
+	now := time.Now()
+	c := newResolveCache(func() time.Time { return now })
+
+	pinned, err := c.resolve(ctx, client, r.image(":v1"), nil, false)
+	require.NoError(t, err)
+	require.Equal(t, r.image("@"+digestA), pinned)
+	_, err = c.resolve(ctx, client, r.image(":missing"), nil, false)
+	require.ErrorContains(t, err, "404")
+
+	// Results, including errors, are reused until they expire, unless resolving fresh.
+	r.digests["v1"] = digestB
+	r.digests["missing"] = digestB
+	pinned, err = c.resolve(ctx, client, r.image(":v1"), nil, false)
+	require.NoError(t, err)
+	require.Equal(t, r.image("@"+digestA), pinned)
+	_, err = c.resolve(ctx, client, r.image(":missing"), nil, false)
+	require.ErrorContains(t, err, "404")
+	pinned, err = c.resolve(ctx, client, r.image(":v1"), nil, true)
+	require.NoError(t, err)
+	require.Equal(t, r.image("@"+digestB), pinned)
+
+	now = now.Add(resolveCacheTTL)
+	pinned, err = c.resolve(ctx, client, r.image(":missing"), nil, false)
+	require.NoError(t, err)
+	require.Equal(t, r.image("@"+digestB), pinned)
+	require.Len(t, c.entries, 1)
+}
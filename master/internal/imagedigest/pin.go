@@ -0,0 +1,90 @@
+package imagedigest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// Pin resolves the tags of the images of an experiment config to digests, as its image pinning
+// mode says, and returns the config with its images replaced by their digests, along with the
+// pins to record for the experiment. previous are the pins of the experiment being continued or
+// forked, if any; images they pinned keep their digests unless the mode is to repin them.
+//
+// Images that can't be resolved, such as ones in registries the master can't reach, keep running
+// by their tags; the errors resolving them are returned along with the config and the pins. The
+// results of resolving images are reused for a few minutes, except when repinning.
+func Pin(
+	ctx context.Context, client *http.Client, config expconf.ExperimentConfig,
+	previous []model.ExperimentImagePin,
+) (expconf.ExperimentConfig, []model.ExperimentImagePin, error) {
+	config = schemas.Copy(config)
+	env := config.Environment()
+	mode := env.ImagePinning()
+	if mode == expconf.NoImagePinning {
+		return config, nil, nil
+	}
+
+	previousPins := map[device.Type]model.ExperimentImagePin{}
+	for _, pin := range previous {
+		previousPins[pin.DeviceType] = pin
+	}
+	images := env.Image()
+	resolved := map[string]string{}
+	var pins []model.ExperimentImagePin
+	var errs []error
+	for _, deviceType := range []device.Type{device.CPU, device.CUDA, device.ROCM} {
+		image := images.For(deviceType)
+		tag := image
+		previousPin, wasPinned := previousPins[deviceType]
+		if wasPinned = wasPinned && previousPin.PinnedImage == image; wasPinned {
+			tag = previousPin.Image
+		}
+
+		pinned := image
+		if !wasPinned || mode == expconf.RepinImages {
+			var ok bool
+			if pinned, ok = resolved[tag]; !ok {
+				var err error
+				pinned, err = defaultResolveCache.resolve(
+					ctx, client, tag, env.RegistryAuth(), mode == expconf.RepinImages)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("pinning %s image: %w", deviceType, err))
+					if !wasPinned {
+						continue
+					}
+					pinned = image
+				} else {
+					resolved[tag] = pinned
+				}
+			}
+		}
+
+		switch deviceType {
+		case device.CPU:
+			images.RawCPU = ptrs.Ptr(pinned)
+		case device.CUDA:
+			images.RawCUDA = ptrs.Ptr(pinned)
+		case device.ROCM:
+			images.RawROCM = ptrs.Ptr(pinned)
+		}
+		pins = append(pins, model.ExperimentImagePin{
+			DeviceType:  deviceType,
+			Image:       tag,
+			PinnedImage: pinned,
+		})
+	}
+
+	env.SetImage(images)
+	// Repinning applies once; continuing or forking the experiment later keeps the new pins.
+	env.SetImagePinning(expconf.PinImages)
+	config.SetEnvironment(env)
+	return config, pins, errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+package imagedigest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// PinsByExperiment returns the images an experiment pinned.
+func PinsByExperiment(ctx context.Context, experimentID int) ([]model.ExperimentImagePin, error) {
+	pins := []model.ExperimentImagePin{}
+	if err := db.Bun().NewSelect().Model(&pins).
+		Where("experiment_id = ?", experimentID).
+		Order("device_type ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting image pins of experiment %d: %w", experimentID, err)
+	}
+	return pins, nil
+}
+
+// SetPins replaces the images an experiment pinned.
+func SetPins(ctx context.Context, experimentID int, pins []model.ExperimentImagePin) error {
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewDelete().Model((*model.ExperimentImagePin)(nil)).
+			Where("experiment_id = ?", experimentID).
+			Exec(ctx); err != nil {
+			return err
+		}
+		if len(pins) == 0 {
+			return nil
+		}
+		for i := range pins {
+			pins[i].ExperimentID = experimentID
+		}
+		_, err := tx.NewInsert().Model(&pins).Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("setting image pins of experiment %d: %w", experimentID, err)
+	}
+	return nil
+}
@@ -253,4 +253,9 @@ func updateJobQInfo(job *jobv1.Job, rmInfo *sproto.RMJobInfo) {
 	}
 	job.Summary.State = rmInfo.State.Proto()
 	job.Summary.JobsAhead = int32(rmInfo.JobsAhead)
+	job.EffectivePriority = nil
+	if rmInfo.EffectivePriority != nil {
+		p := int32(*rmInfo.EffectivePriority)
+		job.EffectivePriority = &p
+	}
 }
@@ -0,0 +1,27 @@
+package jobservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+func TestUpdateJobQInfoEffectivePriority(t *testing.T) {
+	job := &jobv1.Job{Priority: 42}
+	effective := 30
+	updateJobQInfo(job, &sproto.RMJobInfo{
+		JobsAhead:         2,
+		State:             sproto.SchedulingStateQueued,
+		EffectivePriority: &effective,
+	})
+	require.Equal(t, int32(2), job.Summary.JobsAhead)
+	require.Equal(t, int32(42), job.Priority)
+	require.Equal(t, int32(30), job.GetEffectivePriority())
+
+	// Pools that don't age priorities don't report one.
+	updateJobQInfo(job, &sproto.RMJobInfo{State: sproto.SchedulingStateScheduled})
+	require.Nil(t, job.EffectivePriority)
+}
@@ -0,0 +1,219 @@
+package jobstream
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/job"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+// DefaultPollInterval is how often the job queue of a resource pool with subscribers is checked.
+const DefaultPollInterval = time.Second
+
+var log = logrus.WithField("component", "job-stream")
+
+// Queue is the job queue of a resource pool, or the error getting it.
+type Queue struct {
+	Jobs []*jobv1.Job
+	Err  error
+}
+
+// Publisher watches the job queues of the resource pools that have subscribers, checking each
+// queue once per interval however many subscribers it has, and passes them changed queues.
+type Publisher struct {
+	interval time.Duration
+	getJobs  func(resourcePool string) ([]*jobv1.Job, error)
+
+	mu    sync.Mutex
+	pools map[string]*poolWatch
+}
+
+type poolWatch struct {
+	subs   map[*Subscription]bool
+	latest *Queue
+	cancel context.CancelFunc
+}
+
+// Subscription receives the job queue of a resource pool each time it changes.
+type Subscription struct {
+	p    *Publisher
+	pool string
+	// updates holds the latest queue the subscriber hasn't received yet; older queues are
+	// replaced, so a slow subscriber skips to the latest.
+	updates chan Queue
+}
+
+// NewPublisher creates a Publisher that gets the job queues of resource pools with getJobs.
+func NewPublisher(
+	interval time.Duration, getJobs func(resourcePool string) ([]*jobv1.Job, error),
+) *Publisher {
+	return &Publisher{
+		interval: interval,
+		getJobs:  getJobs,
+		pools:    map[string]*poolWatch{},
+	}
+}
+
+// Subscribe starts receiving the job queue of a resource pool. The current queue is received
+// first, as soon as it is known.
+func (p *Publisher) Subscribe(resourcePool string) *Subscription {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := &Subscription{p: p, pool: resourcePool, updates: make(chan Queue, 1)}
+	w, ok := p.pools[resourcePool]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		w = &poolWatch{subs: map[*Subscription]bool{}, cancel: cancel}
+		p.pools[resourcePool] = w
+		go p.watch(ctx, resourcePool, w)
+	}
+	w.subs[s] = true
+	if w.latest != nil {
+		s.offer(*w.latest)
+	}
+	return s
+}
+
+// Updates returns the channel that receives the job queue each time it changes.
+func (s *Subscription) Updates() <-chan Queue {
+	return s.updates
+}
+
+// Close stops the subscription. The resource pool stops being watched once it has no subscribers.
+func (s *Subscription) Close() {
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+
+	w, ok := s.p.pools[s.pool]
+	if !ok || !w.subs[s] {
+		return
+	}
+	delete(w.subs, s)
+	if len(w.subs) == 0 {
+		w.cancel()
+		delete(s.p.pools, s.pool)
+	}
+}
+
+func (s *Subscription) offer(q Queue) {
+	select {
+	case <-s.updates:
+	default:
+	}
+	s.updates <- q
+}
+
+func (p *Publisher) watch(ctx context.Context, resourcePool string, w *poolWatch) {
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+	for {
+		jobs, err := p.getJobs(resourcePool)
+		q := Queue{Jobs: jobs, Err: err}
+		if err != nil {
+			log.WithError(err).WithField("resource-pool", resourcePool).
+				Debug("getting job queue for subscribers")
+		}
+
+		p.mu.Lock()
+		if ctx.Err() != nil {
+			p.mu.Unlock()
+			return
+		}
+		if w.latest == nil || !queuesEqual(*w.latest, q) {
+			w.latest = &q
+			for s := range w.subs {
+				s.offer(q)
+			}
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func queuesEqual(a, b Queue) bool {
+	if (a.Err == nil) != (b.Err == nil) ||
+		(a.Err != nil && a.Err.Error() != b.Err.Error()) ||
+		len(a.Jobs) != len(b.Jobs) {
+		return false
+	}
+	for i := range a.Jobs {
+		if !proto.Equal(a.Jobs[i], b.Jobs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// RBACJobs returns the jobs with only what the user is allowed to see of each: the full job if
+// they can view it, and its place in the queue otherwise.
+func RBACJobs(
+	ctx context.Context, curUser model.User, jobs []*jobv1.Job,
+) ([]*jobv1.RBACJob, error) {
+	okJobs, err := job.AuthZProvider.Get().FilterJobs(ctx, curUser, jobs)
+	if err != nil {
+		return nil, err
+	}
+	okJobsMap := make(map[string]bool)
+	for _, j := range okJobs {
+		okJobsMap[j.JobId] = true
+	}
+
+	rbacJobs := make([]*jobv1.RBACJob, 0, len(jobs))
+	for _, j := range jobs {
+		if okJobsMap[j.JobId] {
+			rbacJobs = append(rbacJobs, &jobv1.RBACJob{Job: &jobv1.RBACJob_Full{Full: j}})
+		} else {
+			limitedJob := authz.ObfuscateJob(j)
+			rbacJobs = append(rbacJobs, &jobv1.RBACJob{
+				Job: &jobv1.RBACJob_Limited{Limited: &limitedJob},
+			})
+		}
+	}
+	return rbacJobs, nil
+}
+
+// JobID returns the ID of a job, whether the user can view it in full or not.
+func JobID(j *jobv1.RBACJob) string {
+	if full := j.GetFull(); full != nil {
+		return full.JobId
+	}
+	return j.GetLimited().GetJobId()
+}
+
+// Diff returns the jobs that are new or changed since sent, and the IDs of the jobs that have
+// left the queue, and updates sent to the jobs.
+func Diff(
+	sent map[string]*jobv1.RBACJob, jobs []*jobv1.RBACJob,
+) (changed []*jobv1.RBACJob, removed []string) {
+	current := make(map[string]bool, len(jobs))
+	for _, j := range jobs {
+		id := JobID(j)
+		current[id] = true
+		if prev, ok := sent[id]; !ok || !proto.Equal(prev, j) {
+			changed = append(changed, j)
+			sent[id] = j
+		}
+	}
+	for id := range sent {
+		if !current[id] {
+			removed = append(removed, id)
+			delete(sent, id)
+		}
+	}
+	sort.Strings(removed)
+	return changed, removed
+}
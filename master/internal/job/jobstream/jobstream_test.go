@@ -0,0 +1,111 @@
+package jobstream
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+func testJob(id string, jobsAhead int32) *jobv1.Job {
+	return &jobv1.Job{
+		JobId:   id,
+		Summary: &jobv1.JobSummary{State: jobv1.State_STATE_QUEUED, JobsAhead: jobsAhead},
+	}
+}
+
+type fakeQueues struct {
+	mu    sync.Mutex
+	calls map[string]int
+	jobs  map[string][]*jobv1.Job
+	err   error
+}
+
+func (f *fakeQueues) getJobs(pool string) ([]*jobv1.Job, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[pool]++
+	return f.jobs[pool], f.err
+}
+
+func (f *fakeQueues) set(pool string, jobs []*jobv1.Job, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.jobs[pool], f.err = jobs, err
+}
+
+func (f *fakeQueues) callCount(pool string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[pool]
+}
+
+func receive(t *testing.T, s *Subscription) Queue {
+	select {
+	case q := <-s.Updates():
+		return q
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a job queue")
+		return Queue{}
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	f := &fakeQueues{calls: map[string]int{}, jobs: map[string][]*jobv1.Job{}}
+	f.set("default", []*jobv1.Job{testJob("a", 0)}, nil)
+	p := NewPublisher(10*time.Millisecond, f.getJobs)
+
+	s1 := p.Subscribe("default")
+	require.Equal(t, "a", receive(t, s1).Jobs[0].JobId)
+	s2 := p.Subscribe("default")
+	require.Len(t, receive(t, s2).Jobs, 1)
+
+	// Unchanged queues aren't sent again.
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, s1.Updates())
+
+	f.set("default", []*jobv1.Job{testJob("a", 0), testJob("b", 1)}, nil)
+	require.Len(t, receive(t, s1).Jobs, 2)
+	require.Len(t, receive(t, s2).Jobs, 2)
+
+	f.set("default", nil, errors.New("no such pool"))
+	require.EqualError(t, receive(t, s1).Err, "no such pool")
+
+	// The pool stops being watched once it has no subscribers.
+	s1.Close()
+	s2.Close()
+	s2.Close()
+	calls := f.callCount("default")
+	time.Sleep(50 * time.Millisecond)
+	require.LessOrEqual(t, f.callCount("default"), calls+1)
+	require.Empty(t, p.pools)
+}
+
+func TestDiff(t *testing.T) {
+	full := func(j *jobv1.Job) *jobv1.RBACJob {
+		return &jobv1.RBACJob{Job: &jobv1.RBACJob_Full{Full: j}}
+	}
+	limited := func(id string) *jobv1.RBACJob {
+		return &jobv1.RBACJob{Job: &jobv1.RBACJob_Limited{Limited: &jobv1.LimitedJob{JobId: id}}}
+	}
+
+	sent := map[string]*jobv1.RBACJob{}
+	changed, removed := Diff(sent, []*jobv1.RBACJob{full(testJob("a", 0)), limited("b")})
+	require.Len(t, changed, 2)
+	require.Empty(t, removed)
+
+	changed, removed = Diff(sent, []*jobv1.RBACJob{full(testJob("a", 0)), limited("b")})
+	require.Empty(t, changed)
+	require.Empty(t, removed)
+
+	// Job a leaves the queue and c moves ahead of b.
+	c := full(testJob("c", 0))
+	changed, removed = Diff(sent, []*jobv1.RBACJob{c, limited("b")})
+	require.Equal(t, []*jobv1.RBACJob{c}, changed)
+	require.Equal(t, []string{"a"}, removed)
+	require.Len(t, sent, 2)
+}
@@ -0,0 +1,197 @@
+// Package logmetrics derives metrics from the logs of trials with the log_metrics rules of their
+// experiments, so that training code that can't report metrics through the harness, such as a
+// framework's own training loop, can still have its metrics tracked.
+package logmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+const (
+	// Group is the metric group that metrics derived from logs are stored in.
+	Group model.MetricGroup = "system"
+
+	regexCacheSize = 256
+)
+
+// regexCache holds compiled regexes of rules, since rules are applied to every batch of logs.
+var regexCache = func() *lru.Cache[string, *regexp.Regexp] {
+	c, err := lru.New[string, *regexp.Regexp](regexCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}()
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Get(pattern); ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling regex %q: %w", pattern, err)
+	}
+	regexCache.Add(pattern, re)
+	return re, nil
+}
+
+// rule is a compiled log metric rule.
+type rule struct {
+	regex *regexp.Regexp
+	json  []string
+	steps string
+}
+
+// Sample is the metrics derived from a log line.
+type Sample struct {
+	// Steps is the steps completed when the line was logged, if the rule reads it from the line.
+	Steps   *int
+	Metrics map[string]float64
+}
+
+// Validate returns an error if a rule can't be applied, such as one whose regular expression
+// doesn't compile or has no named groups for metrics.
+func Validate(rules []expconf.LogMetricRule) error {
+	for i, r := range rules {
+		if _, err := compile(r); err != nil {
+			return fmt.Errorf("log_metrics[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func compile(r expconf.LogMetricRule) (*rule, error) {
+	out := &rule{json: r.RawJSON}
+	if r.RawSteps != nil {
+		out.steps = *r.RawSteps
+	}
+	if r.RawRegex == nil {
+		return out, nil
+	}
+
+	re, err := compileRegex(*r.RawRegex)
+	if err != nil {
+		return nil, err
+	}
+	out.regex = re
+	metrics, hasSteps := 0, false
+	for _, name := range re.SubexpNames() {
+		switch {
+		case name == "":
+		case name == out.steps:
+			hasSteps = true
+		default:
+			metrics++
+		}
+	}
+	if metrics == 0 {
+		return nil, fmt.Errorf("regex %q has no named groups for metrics", *r.RawRegex)
+	}
+	if out.steps != "" && !hasSteps {
+		return nil, fmt.Errorf("regex %q has no group %q for the steps", *r.RawRegex, out.steps)
+	}
+	return out, nil
+}
+
+// extract returns the metrics the rule derives from a log line, if it matches.
+func (r *rule) extract(line string) (Sample, bool) {
+	if r.regex != nil {
+		return r.extractRegex(line)
+	}
+	return r.extractJSON(line)
+}
+
+func (r *rule) extractRegex(line string) (Sample, bool) {
+	match := r.regex.FindStringSubmatch(line)
+	if match == nil {
+		return Sample{}, false
+	}
+	s := Sample{Metrics: map[string]float64{}}
+	for i, name := range r.regex.SubexpNames() {
+		if name == "" || match[i] == "" {
+			continue
+		}
+		if name == r.steps {
+			if steps, err := strconv.Atoi(match[i]); err == nil && steps >= 0 {
+				s.Steps = &steps
+			}
+			continue
+		}
+		if v, err := strconv.ParseFloat(match[i], 64); err == nil && !math.IsNaN(v) &&
+			!math.IsInf(v, 0) {
+			s.Metrics[name] = v
+		}
+	}
+	return s, len(s.Metrics) > 0
+}
+
+func (r *rule) extractJSON(line string) (Sample, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") {
+		return Sample{}, false
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return Sample{}, false
+	}
+	s := Sample{Metrics: map[string]float64{}}
+	for _, name := range r.json {
+		if v, ok := fields[name].(float64); ok {
+			s.Metrics[name] = v
+		}
+	}
+	if r.steps != "" {
+		if steps, ok := fields[r.steps].(float64); ok && steps >= 0 && steps == math.Trunc(steps) {
+			s.Steps = ptrs.Ptr(int(steps))
+		}
+	}
+	return s, len(s.Metrics) > 0
+}
+
+// extractAll applies rules to log lines, in order, returning the samples they derive. A line that
+// several rules match yields a sample for each of them.
+func extractAll(rules []*rule, lines []string) []Sample {
+	var out []Sample
+	for _, line := range lines {
+		// Trials log their config, which holds the rules themselves.
+		if strings.Contains(line, `"log_metrics":`) {
+			continue
+		}
+		for _, r := range rules {
+			if s, ok := r.extract(line); ok {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// byStep merges samples into the metrics reported at each step, with later samples overwriting
+// earlier ones. Samples without steps are reported at the given step.
+func byStep(samples []Sample, current int) map[int]map[string]float64 {
+	out := make(map[int]map[string]float64)
+	for _, s := range samples {
+		steps := current
+		if s.Steps != nil {
+			steps = *s.Steps
+		}
+		if out[steps] == nil {
+			out[steps] = make(map[string]float64)
+		}
+		for name, v := range s.Metrics {
+			out[steps][name] = v
+		}
+	}
+	return out
+}
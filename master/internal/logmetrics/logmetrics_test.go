@@ -0,0 +1,86 @@
+package logmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate([]expconf.LogMetricRule{
+		{RawRegex: ptrs.Ptr(`throughput=(?P<throughput>[0-9.]+)`)},
+		{RawRegex: ptrs.Ptr(`step (?P<step>\d+) loss (?P<loss>[0-9.]+)`), RawSteps: ptrs.Ptr("step")},
+		{RawJSON: []string{"loss"}, RawSteps: ptrs.Ptr("step")},
+	}))
+
+	for _, bad := range []expconf.LogMetricRule{
+		{RawRegex: ptrs.Ptr(`throughput=(`)},
+		{RawRegex: ptrs.Ptr(`throughput=([0-9.]+)`)},
+		{RawRegex: ptrs.Ptr(`step (?P<step>\d+)`), RawSteps: ptrs.Ptr("step")},
+		{RawRegex: ptrs.Ptr(`loss (?P<loss>[0-9.]+)`), RawSteps: ptrs.Ptr("step")},
+	} {
+		require.Error(t, Validate([]expconf.LogMetricRule{bad}), *bad.RawRegex)
+	}
+}
+
+func compileAll(t *testing.T, rules ...expconf.LogMetricRule) []*rule {
+	var out []*rule
+	for _, r := range rules {
+		c, err := compile(r)
+		require.NoError(t, err)
+		out = append(out, c)
+	}
+	return out
+}
+
+func TestExtractRegex(t *testing.T) {
+	rules := compileAll(t, expconf.LogMetricRule{
+		RawRegex: ptrs.Ptr(`(?:step (?P<step>\d+) )?throughput=(?P<throughput>[0-9.]+) img/s`),
+		RawSteps: ptrs.Ptr("step"),
+	})
+	samples := extractAll(rules, []string{
+		"epoch 1 throughput=123.5 img/s",
+		"step 20 throughput=130 img/s",
+		"nothing to see",
+		"throughput=inf img/s",
+	})
+	require.Equal(t, []Sample{
+		{Metrics: map[string]float64{"throughput": 123.5}},
+		{Steps: ptrs.Ptr(20), Metrics: map[string]float64{"throughput": 130}},
+	}, samples)
+}
+
+func TestExtractJSON(t *testing.T) {
+	rules := compileAll(t, expconf.LogMetricRule{
+		RawJSON:  []string{"loss", "accuracy"},
+		RawSteps: ptrs.Ptr("step"),
+	})
+	samples := extractAll(rules, []string{
+		`  {"loss": 0.5, "accuracy": "high", "step": 10}`,
+		`{"loss": 0.25, "step": 1.5}`,
+		`{"accuracy": 0.9}`,
+		`{"other": 1}`,
+		`{"loss": `,
+		`loss: 0.1`,
+		`{"log_metrics": [{"json": ["loss"]}], "loss": 1}`,
+	})
+	require.Equal(t, []Sample{
+		{Steps: ptrs.Ptr(10), Metrics: map[string]float64{"loss": 0.5}},
+		{Metrics: map[string]float64{"loss": 0.25}},
+		{Metrics: map[string]float64{"accuracy": 0.9}},
+	}, samples)
+}
+
+func TestByStep(t *testing.T) {
+	require.Equal(t, map[int]map[string]float64{
+		5:  {"loss": 0.25, "throughput": 100},
+		10: {"loss": 0.1},
+	}, byStep([]Sample{
+		{Metrics: map[string]float64{"loss": 0.5, "throughput": 100}},
+		{Metrics: map[string]float64{"loss": 0.25}},
+		{Steps: ptrs.Ptr(10), Metrics: map[string]float64{"loss": 0.1}},
+	}, 5))
+}
@@ -0,0 +1,88 @@
+package logmetrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/commonv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// ActiveRules returns the log metric rules of an experiment.
+func ActiveRules(ctx context.Context, experimentID int) ([]expconf.LogMetricRule, error) {
+	res := struct {
+		LogMetrics []expconf.LogMetricRule
+	}{}
+	if err := db.Bun().NewSelect().Table("experiments").
+		ColumnExpr("config -> 'log_metrics' AS log_metrics").
+		Where("id = ?", experimentID).
+		Scan(ctx, &res); err != nil {
+		return nil, fmt.Errorf("getting log metric rules for experiment %d: %w", experimentID, err)
+	}
+	return res.LogMetrics, nil
+}
+
+// Ingest applies rules to the logs of a trial's task and stores the metrics they derive in the
+// system metric group. Metrics from lines that don't hold the steps completed are stored at the
+// steps the trial last reported.
+func Ingest(
+	ctx context.Context, pgDB *db.PgDB, taskID model.TaskID,
+	rules []expconf.LogMetricRule, logs []*model.TaskLog,
+) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	compiled := make([]*rule, 0, len(rules))
+	for _, r := range rules {
+		c, err := compile(r)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, c)
+	}
+	lines := make([]string, 0, len(logs))
+	for _, l := range logs {
+		lines = append(lines, l.Log)
+	}
+	samples := extractAll(compiled, lines)
+	if len(samples) == 0 {
+		return nil
+	}
+
+	trial, err := db.TrialByTaskID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("getting trial of task %s: %w", taskID, err)
+	}
+	metrics := byStep(samples, trial.TotalBatches)
+	steps := make([]int, 0, len(metrics))
+	for s := range metrics {
+		steps = append(steps, s)
+	}
+	sort.Ints(steps)
+	for _, s := range steps {
+		values := make(map[string]any, len(metrics[s]))
+		for name, v := range metrics[s] {
+			values[name] = v
+		}
+		avg, err := structpb.NewStruct(values)
+		if err != nil {
+			return fmt.Errorf("converting metrics derived from logs: %w", err)
+		}
+		if err := pgDB.AddTrialMetrics(ctx, &trialv1.TrialMetrics{
+			TrialId:        int32(trial.ID),
+			TrialRunId:     int32(trial.RunID),
+			StepsCompleted: ptrs.Ptr(int32(s)),
+			Metrics:        &commonv1.Metrics{AvgMetrics: avg},
+		}, Group); err != nil {
+			return fmt.Errorf("adding metrics derived from logs of trial %d: %w", trial.ID, err)
+		}
+	}
+	return nil
+}
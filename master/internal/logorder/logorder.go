@@ -0,0 +1,116 @@
+// Package logorder puts the logs of the ranks of a distributed task in an order that is easy to
+// debug: interleaved by when they were written, or grouped by rank.
+package logorder
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Order is an order of task logs.
+type Order string
+
+const (
+	// ByTimestamp interleaves the logs of all ranks by their normalized timestamps.
+	ByTimestamp Order = "timestamp"
+	// ByRank groups logs by rank, in the order each rank wrote them. Logs without a rank come last.
+	ByRank Order = "rank"
+)
+
+// ParseOrder parses an Order, defaulting to ByTimestamp.
+func ParseOrder(s string) (Order, error) {
+	switch o := Order(s); o {
+	case "":
+		return ByTimestamp, nil
+	case ByTimestamp, ByRank:
+		return o, nil
+	default:
+		return "", fmt.Errorf("unknown log order %q, must be %q or %q", s, ByTimestamp, ByRank)
+	}
+}
+
+// Log is a task log with its timestamp normalized to the master's clock.
+type Log struct {
+	*model.TaskLog
+	NormalizedTimestamp *time.Time `json:"normalized_timestamp"`
+}
+
+// stream identifies the logs written in order by one rank of one container.
+type stream struct {
+	containerID string
+	rankID      int
+	hasRank     bool
+}
+
+func streamOf(l *model.TaskLog) stream {
+	var s stream
+	if l.ContainerID != nil {
+		s.containerID = *l.ContainerID
+	}
+	if l.RankID != nil {
+		s.rankID, s.hasRank = *l.RankID, true
+	}
+	return s
+}
+
+// Normalize shifts the timestamp of each log by the clock offset of the agent it came from, then
+// corrects it to be no earlier than the previous log of the same rank of the same container, since
+// a rank writes its logs in order; clock corrections and the precision of timestamps can otherwise
+// make them appear out of order. Logs must be given in the order they were received.
+func Normalize(logs []*model.TaskLog, agentClockOffsets map[string]time.Duration) []*Log {
+	last := make(map[stream]time.Time)
+	normalized := make([]*Log, 0, len(logs))
+	for _, l := range logs {
+		s := streamOf(l)
+		var ts *time.Time
+		if l.Timestamp != nil {
+			t := *l.Timestamp
+			if l.AgentID != nil {
+				t = t.Add(agentClockOffsets[*l.AgentID])
+			}
+			if prev, ok := last[s]; ok && t.Before(prev) {
+				t = prev
+			}
+			last[s] = t
+			ts = &t
+		} else if prev, ok := last[s]; ok {
+			ts = &prev
+		}
+		normalized = append(normalized, &Log{TaskLog: l, NormalizedTimestamp: ts})
+	}
+	return normalized
+}
+
+// Sort orders normalized logs in place. Logs that compare equal keep the order they were received
+// in; for ByTimestamp, logs without a timestamp come first.
+func Sort(logs []*Log, order Order) {
+	switch order {
+	case ByRank:
+		sort.SliceStable(logs, func(i, j int) bool {
+			a, b := logs[i].RankID, logs[j].RankID
+			switch {
+			case a == nil:
+				return false
+			case b == nil:
+				return true
+			default:
+				return *a < *b
+			}
+		})
+	default:
+		sort.SliceStable(logs, func(i, j int) bool {
+			a, b := logs[i].NormalizedTimestamp, logs[j].NormalizedTimestamp
+			switch {
+			case b == nil:
+				return false
+			case a == nil:
+				return true
+			default:
+				return a.Before(*b)
+			}
+		})
+	}
+}
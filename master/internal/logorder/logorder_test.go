@@ -0,0 +1,88 @@
+package logorder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+var base = time.Date(2024, 12, 16, 12, 0, 0, 0, time.UTC)
+
+func taskLog(agent string, rank int, at time.Duration, msg string) *model.TaskLog {
+	container := agent + "-container"
+	ts := base.Add(at)
+	return &model.TaskLog{
+		AgentID:     &agent,
+		ContainerID: &container,
+		RankID:      &rank,
+		Timestamp:   &ts,
+		Log:         msg,
+	}
+}
+
+func messages(logs []*Log) []string {
+	var msgs []string
+	for _, l := range logs {
+		msgs = append(msgs, l.Log)
+	}
+	return msgs
+}
+
+func TestParseOrder(t *testing.T) {
+	for in, want := range map[string]Order{"": ByTimestamp, "timestamp": ByTimestamp, "rank": ByRank} {
+		got, err := ParseOrder(in)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err := ParseOrder("agent")
+	require.ErrorContains(t, err, "unknown log order")
+}
+
+func TestInterleaveByTimestampCorrectsClockOffsets(t *testing.T) {
+	// Agent b's clock is two seconds behind the master's, so without correction its logs would
+	// appear before logs of agent a that were written earlier.
+	logs := []*model.TaskLog{
+		taskLog("a", 0, 0, "a0"),
+		taskLog("a", 0, 2*time.Second, "a2"),
+		taskLog("b", 1, -time.Second, "b1"),
+		taskLog("b", 1, time.Second, "b3"),
+	}
+	ordered := Normalize(logs, map[string]time.Duration{"b": 2 * time.Second})
+	Sort(ordered, ByTimestamp)
+	require.Equal(t, []string{"a0", "b1", "a2", "b3"}, messages(ordered))
+	require.Equal(t, base.Add(3*time.Second), *ordered[3].NormalizedTimestamp)
+	require.Equal(t, base.Add(time.Second), *ordered[3].Timestamp, "raw timestamp is kept")
+}
+
+func TestNormalizeIsMonotonicPerRank(t *testing.T) {
+	logs := []*model.TaskLog{
+		taskLog("a", 0, 2*time.Second, "first"),
+		taskLog("a", 0, time.Second, "second"),
+		taskLog("a", 1, time.Second, "other rank"),
+	}
+	ordered := Normalize(logs, nil)
+	require.Equal(t, base.Add(2*time.Second), *ordered[1].NormalizedTimestamp)
+	require.Equal(t, base.Add(time.Second), *ordered[2].NormalizedTimestamp)
+
+	Sort(ordered, ByTimestamp)
+	require.Equal(t, []string{"other rank", "first", "second"}, messages(ordered))
+}
+
+func TestGroupByRank(t *testing.T) {
+	unranked := &model.TaskLog{Log: "unranked"}
+	logs := []*model.TaskLog{
+		taskLog("a", 1, 0, "r1-0"),
+		unranked,
+		taskLog("a", 0, time.Second, "r0-1"),
+		taskLog("a", 1, 2*time.Second, "r1-2"),
+		taskLog("a", 0, 3*time.Second, "r0-3"),
+	}
+	ordered := Normalize(logs, nil)
+	require.Nil(t, ordered[1].NormalizedTimestamp)
+
+	Sort(ordered, ByRank)
+	require.Equal(t, []string{"r0-1", "r0-3", "r1-0", "r1-2", "unranked"}, messages(ordered))
+}
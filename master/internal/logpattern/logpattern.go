@@ -140,7 +140,8 @@ type retryOnDifferentNode struct {
 	TriggeringLog string       `bun:"triggering_log"`
 }
 
-// GetBlockedNodes returns nodes you can't schedule on due to log pattern policies.
+// GetBlockedNodes returns nodes you can't schedule on due to log pattern policies or because
+// straggler detection excluded them.
 func GetBlockedNodes(ctx context.Context, taskID model.TaskID) ([]string, error) {
 	var resp []retryOnDifferentNode
 	if err := db.Bun().NewSelect().Model(&resp).
@@ -151,9 +152,25 @@ func GetBlockedNodes(ctx context.Context, taskID model.TaskID) ([]string, error)
 		return nil, fmt.Errorf("getting nodes for taskID %s: %w", taskID, err)
 	}
 
+	var stragglers []string
+	if err := db.Bun().NewSelect().Model((*model.StragglerAlert)(nil)).
+		Where("task_id = ?", taskID).
+		Where("excluded").
+		ColumnExpr("DISTINCT agent_id").
+		Scan(ctx, &stragglers); err != nil {
+		return nil, fmt.Errorf("getting straggler nodes for taskID %s: %w", taskID, err)
+	}
+
 	var o []string
+	seen := map[string]bool{}
 	for _, r := range resp {
 		o = append(o, r.NodeName)
+		seen[r.NodeName] = true
+	}
+	for _, n := range stragglers {
+		if !seen[n] {
+			o = append(o, n)
+		}
 	}
 	return o, nil
 }
@@ -0,0 +1,161 @@
+package logretention
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// taskRecordBatchSize is how many allocations are pruned per transaction, so that pruning a
+// backlog of millions doesn't hold locks on the tables for long.
+const taskRecordBatchSize = 5000
+
+// ScheduleTaskRecords begins a schedule of pruning the records of terminated allocations
+// according to the provided TaskRecordRetentionConfig.
+func (s *Scheduler) ScheduleTaskRecords(conf config.TaskRecordRetentionConfig) error {
+	if conf.Days == nil || conf.Schedule == nil {
+		return nil
+	}
+	archive := conf.Mode != config.TaskRecordRetentionPrune
+	task := gocron.NewTask(func() {
+		defer func() {
+			if s.TestingOnlySynchronizationHelper != nil {
+				s.TestingOnlySynchronizationHelper.Done()
+			}
+		}()
+		count, err := PruneTaskRecords(context.Background(), *conf.Days, archive)
+		if err != nil {
+			syslog.WithError(err).Error("failed to prune task records")
+		} else if count > 0 {
+			syslog.WithField("count", count).Info("pruned records of terminated allocations")
+		}
+	})
+	if d, err := time.ParseDuration(*conf.Schedule); err == nil {
+		syslog.WithField("duration", d).Debug("running task record pruning with duration")
+		if _, err := s.sched.NewJob(gocron.DurationJob(d), task); err != nil {
+			return errors.Wrapf(err, "failed to schedule duration task record pruning")
+		}
+	} else {
+		syslog.WithField("cron", *conf.Schedule).Debug("running task record pruning with cron")
+		if _, err := s.sched.NewJob(gocron.CronJob(*conf.Schedule, false), task); err != nil {
+			return errors.Wrapf(err, "failed to schedule cron task record pruning")
+		}
+	}
+	s.sched.Start()
+	return nil
+}
+
+// PruneTaskRecords deletes the allocations that ended more than days ago, along with their task
+// stats, sessions, workspace records and diagnostics, and the proxy shares of tasks that ended as
+// long ago. Allocations that produced checkpoints are kept. With archive set, the allocations and
+// task stats are first summed up by day into allocation_summaries and task_stats_summaries. It
+// returns the number of allocations pruned.
+func PruneTaskRecords(ctx context.Context, days int, archive bool) (int64, error) {
+	var total int64
+	for {
+		var ids []string
+		if err := db.Bun().NewSelect().Table("allocations").Column("allocation_id").
+			Where("end_time <= retention_timestamp() - make_interval(days => ?)", days).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM checkpoints_v2 c WHERE c.allocation_id = allocations.allocation_id
+			)`).
+			Order("end_time").
+			Limit(taskRecordBatchSize).
+			Scan(ctx, &ids); err != nil {
+			return total, errors.Wrap(err, "error finding expired allocations")
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		if err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if archive {
+				if err := archiveAllocations(ctx, tx, ids); err != nil {
+					return err
+				}
+			}
+			for _, table := range []string{
+				"task_stats",
+				"allocation_sessions",
+				"allocation_workspace_info",
+				"allocation_diagnostics",
+				"allocations",
+			} {
+				if _, err := tx.NewDelete().Table(table).
+					Where("allocation_id IN (?)", bun.In(ids)).
+					Exec(ctx); err != nil {
+					return errors.Wrapf(err, "error pruning %s", table)
+				}
+			}
+			return nil
+		}); err != nil {
+			return total, err
+		}
+		total += int64(len(ids))
+		syslog.WithFields(logrus.Fields{"count": len(ids), "archive": archive}).
+			Debug("pruned a batch of expired allocations")
+		if len(ids) < taskRecordBatchSize {
+			break
+		}
+	}
+
+	if _, err := db.Bun().NewDelete().Table("task_proxy_shares").
+		Where(`task_id IN (
+			SELECT task_id FROM tasks
+			WHERE end_time <= retention_timestamp() - make_interval(days => ?)
+		)`, days).
+		Exec(ctx); err != nil {
+		return total, errors.Wrap(err, "error pruning task proxy shares")
+	}
+	return total, nil
+}
+
+// archiveAllocations adds allocations and their task stats to the daily summaries.
+func archiveAllocations(ctx context.Context, tx bun.Tx, ids []string) error {
+	if _, err := tx.NewRaw(`
+		INSERT INTO allocation_summaries AS s
+			(day, resource_pool, task_type, allocations, slot_seconds)
+		SELECT
+			date_trunc('day', coalesce(a.start_time, a.end_time))::date,
+			a.resource_pool,
+			t.task_type::text,
+			count(*),
+			coalesce(sum(a.slots * extract(epoch FROM a.end_time - a.start_time)), 0)
+		FROM allocations a
+		JOIN tasks t ON t.task_id = a.task_id
+		WHERE a.allocation_id IN (?)
+		GROUP BY 1, 2, 3
+		ON CONFLICT (day, resource_pool, task_type) DO UPDATE SET
+			allocations = s.allocations + EXCLUDED.allocations,
+			slot_seconds = s.slot_seconds + EXCLUDED.slot_seconds
+	`, bun.In(ids)).Exec(ctx); err != nil {
+		return errors.Wrap(err, "error archiving allocations")
+	}
+	if _, err := tx.NewRaw(`
+		INSERT INTO task_stats_summaries AS s
+			(day, event_type, events, total_seconds, max_seconds)
+		SELECT
+			date_trunc('day', start_time)::date,
+			event_type,
+			count(*),
+			coalesce(sum(extract(epoch FROM end_time - start_time)), 0),
+			coalesce(max(extract(epoch FROM end_time - start_time)), 0)
+		FROM task_stats
+		WHERE allocation_id IN (?)
+		GROUP BY 1, 2
+		ON CONFLICT (day, event_type) DO UPDATE SET
+			events = s.events + EXCLUDED.events,
+			total_seconds = s.total_seconds + EXCLUDED.total_seconds,
+			max_seconds = greatest(s.max_seconds, EXCLUDED.max_seconds)
+	`, bun.In(ids)).Exec(ctx); err != nil {
+		return errors.Wrap(err, "error archiving task stats")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package logship implements the master's side of the protocol the log shippers in task containers
+// ship batches of task logs with. The master acknowledges batches by their sequence number, so
+// shippers can retry a batch without duplicating its lines, and grants shippers credit for the
+// lines of their next batch out of a budget of lines it writes at once, so log-heavy tasks can't
+// overwhelm the log backend.
+package logship
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// StreamTTL is how long the master remembers the last batch it acknowledged of a stream that
+	// stopped shipping. A shipper that retries a batch after that long may duplicate its lines.
+	StreamTTL = time.Hour
+	// RetryAfter is how long shippers are asked to wait before they retry a batch the master
+	// can't take yet.
+	RetryAfter = time.Second
+	// MaxStreams is how many streams the master remembers at once. Past it, the master forgets
+	// the stream that shipped least recently to make room for a new one.
+	MaxStreams = 10000
+)
+
+// Batch is a batch of task logs a shipper ships. Each shipper ships one stream of batches, with
+// sequence numbers that increase from one.
+type Batch struct {
+	StreamID string            `json:"stream_id"`
+	Seq      uint64            `json:"seq"`
+	Logs     []json.RawMessage `json:"logs"`
+}
+
+// Validate returns an error if a batch can't be received.
+func (b *Batch) Validate() error {
+	switch {
+	case b.StreamID == "":
+		return errors.New("stream_id is required")
+	case b.Seq == 0:
+		return errors.New("seq must be > 0")
+	case len(b.Logs) == 0:
+		return errors.New("len logs must be greater than 0")
+	}
+	return nil
+}
+
+// Ack acknowledges every batch of a stream up to AckedSeq, and limits the lines of the shipper's
+// next batch to Credit.
+type Ack struct {
+	AckedSeq uint64 `json:"acked_seq"`
+	Credit   int    `json:"credit"`
+}
+
+// BusyError is returned for batches the master can't take yet; the shipper should retry them
+// after RetryAfter.
+type BusyError struct {
+	Reason string
+}
+
+func (e BusyError) Error() string {
+	return fmt.Sprintf("retry after %s: %s", RetryAfter, e.Reason)
+}
+
+type stream struct {
+	acked     uint64
+	receiving bool
+	seen      time.Time
+}
+
+// Receiver receives batches of every stream shipped to the master.
+type Receiver struct {
+	maxInflight int
+	maxBatch    int
+	maxStreams  int
+
+	mu       sync.Mutex
+	inflight int
+	streams  map[string]*stream
+	sweptAt  time.Time
+}
+
+// NewReceiver returns a Receiver that writes at most maxInflight lines at once, and grants
+// shippers credit for at most maxBatch lines per batch.
+func NewReceiver(maxInflight, maxBatch int) *Receiver {
+	return &Receiver{
+		maxInflight: maxInflight,
+		maxBatch:    maxBatch,
+		maxStreams:  MaxStreams,
+		streams:     map[string]*stream{},
+		sweptAt:     time.Now(),
+	}
+}
+
+// Receive writes the logs of a batch with write, unless its stream already acknowledged it. It
+// returns a BusyError instead if the stream is still writing an earlier attempt of a batch, or if
+// writing the batch would exceed the lines the master writes at once.
+func (r *Receiver) Receive(b *Batch, write func() error) (*Ack, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.sweep(now)
+	s, ok := r.streams[b.StreamID]
+	if !ok {
+		if len(r.streams) >= r.maxStreams && !r.evict() {
+			return nil, BusyError{Reason: "too many streams are being written"}
+		}
+		s = &stream{}
+		r.streams[b.StreamID] = s
+	}
+	s.seen = now
+
+	switch {
+	case b.Seq <= s.acked:
+		return r.ack(s), nil
+	case s.receiving:
+		return nil, BusyError{Reason: "an earlier attempt of the batch is still being written"}
+	case r.inflight > 0 && r.inflight+len(b.Logs) > r.maxInflight:
+		// A batch is always let through when nothing else is written, so batches larger than
+		// the budget aren't retried forever.
+		return nil, BusyError{Reason: fmt.Sprintf("the master is writing %d lines", r.inflight)}
+	}
+
+	s.receiving = true
+	r.inflight += len(b.Logs)
+	r.mu.Unlock()
+	err := write()
+	r.mu.Lock()
+	s.receiving = false
+	r.inflight -= len(b.Logs)
+	if err != nil {
+		return nil, err
+	}
+	s.acked = b.Seq
+	return r.ack(s), nil
+}
+
+// ack acknowledges a stream, granting at least one line of credit so shippers keep shipping.
+func (r *Receiver) ack(s *stream) *Ack {
+	return &Ack{AckedSeq: s.acked, Credit: max(min(r.maxBatch, r.maxInflight-r.inflight), 1)}
+}
+
+// sweep forgets streams that stopped shipping, at most once a minute.
+func (r *Receiver) sweep(now time.Time) {
+	if now.Sub(r.sweptAt) < time.Minute {
+		return
+	}
+	r.sweptAt = now
+	for id, s := range r.streams {
+		if !s.receiving && now.Sub(s.seen) > StreamTTL {
+			delete(r.streams, id)
+		}
+	}
+}
+
+// evict forgets the stream that shipped least recently, unless every stream is being written. It
+// returns whether it forgot a stream.
+func (r *Receiver) evict() bool {
+	var oldest string
+	for id, s := range r.streams {
+		if !s.receiving && (oldest == "" || s.seen.Before(r.streams[oldest].seen)) {
+			oldest = id
+		}
+	}
+	if oldest == "" {
+		return false
+	}
+	delete(r.streams, oldest)
+	return true
+}
@@ -0,0 +1,138 @@
+package logship
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func batch(streamID string, seq uint64, lines int) *Batch {
+	logs := make([]json.RawMessage, lines)
+	for i := range logs {
+		logs[i] = json.RawMessage(`{"log": "hi\n"}`)
+	}
+	return &Batch{StreamID: streamID, Seq: seq, Logs: logs}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, batch("a", 1, 1).Validate())
+	require.ErrorContains(t, batch("", 1, 1).Validate(), "stream_id")
+	require.ErrorContains(t, batch("a", 0, 1).Validate(), "seq")
+	require.ErrorContains(t, batch("a", 1, 0).Validate(), "logs")
+}
+
+func TestReceiveDedupesRetries(t *testing.T) {
+	r := NewReceiver(100, 10)
+	writes := 0
+	write := func() error {
+		writes++
+		return nil
+	}
+
+	ack, err := r.Receive(batch("a", 1, 5), write)
+	require.NoError(t, err)
+	require.Equal(t, &Ack{AckedSeq: 1, Credit: 10}, ack)
+
+	// Retries of acknowledged batches aren't written again.
+	ack, err = r.Receive(batch("a", 1, 5), write)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ack.AckedSeq)
+	require.Equal(t, 1, writes)
+
+	// Batches that fail to be written aren't acknowledged, and can be retried.
+	_, err = r.Receive(batch("a", 2, 5), func() error { return errors.New("db down") })
+	require.ErrorContains(t, err, "db down")
+	ack, err = r.Receive(batch("a", 2, 5), write)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), ack.AckedSeq)
+	require.Equal(t, 2, writes)
+
+	// Streams are independent.
+	ack, err = r.Receive(batch("b", 1, 5), write)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ack.AckedSeq)
+	require.Equal(t, 3, writes)
+}
+
+func TestReceiveFlowControl(t *testing.T) {
+	r := NewReceiver(10, 8)
+	writing, done, errs := make(chan struct{}), make(chan struct{}), make(chan error, 1)
+	go func() {
+		_, err := r.Receive(batch("a", 1, 6), func() error {
+			close(writing)
+			<-done
+			return nil
+		})
+		errs <- err
+	}()
+	<-writing
+
+	// Retries of a batch that is still being written, and batches over the budget, are refused.
+	var busy BusyError
+	_, err := r.Receive(batch("a", 1, 6), func() error { return nil })
+	require.ErrorAs(t, err, &busy)
+	_, err = r.Receive(batch("b", 1, 5), func() error { return nil })
+	require.ErrorAs(t, err, &busy)
+
+	// Batches within the budget aren't, and the credit shrinks with the lines being written.
+	ack, err := r.Receive(batch("c", 1, 4), func() error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, 4, ack.Credit)
+
+	close(done)
+	require.NoError(t, <-errs)
+	ack, err = r.Receive(batch("c", 1, 4), func() error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, 8, ack.Credit)
+
+	// Batches over the budget are let through when nothing else is being written.
+	ack, err = r.Receive(batch("d", 1, 20), func() error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ack.AckedSeq)
+}
+
+func TestSweep(t *testing.T) {
+	r := NewReceiver(10, 10)
+	_, err := r.Receive(batch("a", 1, 1), func() error { return nil })
+	require.NoError(t, err)
+
+	r.sweep(time.Now().Add(StreamTTL / 2))
+	require.Contains(t, r.streams, "a")
+	r.sweep(time.Now().Add(2 * StreamTTL))
+	require.NotContains(t, r.streams, "a")
+}
+
+func TestReceiveCapsStreams(t *testing.T) {
+	r := NewReceiver(10, 10)
+	r.maxStreams = 2
+	for _, id := range []string{"a", "b", "c"} {
+		_, err := r.Receive(batch(id, 1, 1), func() error { return nil })
+		require.NoError(t, err)
+	}
+	require.Len(t, r.streams, 2)
+	require.NotContains(t, r.streams, "a", "the stream that shipped least recently is forgotten")
+
+	// Streams being written aren't forgotten.
+	done, errs := make(chan struct{}), make(chan error, 2)
+	for _, id := range []string{"b", "c"} {
+		started := make(chan struct{})
+		go func() {
+			_, err := r.Receive(batch(id, 2, 1), func() error {
+				close(started)
+				<-done
+				return nil
+			})
+			errs <- err
+		}()
+		<-started
+	}
+	var busy BusyError
+	_, err := r.Receive(batch("d", 1, 1), func() error { return nil })
+	require.ErrorAs(t, err, &busy)
+	close(done)
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+}
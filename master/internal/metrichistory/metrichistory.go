@@ -0,0 +1,169 @@
+// Package metrichistory writes the full metric history of trials as ndjson or Parquet, for
+// analysts to load into pandas or Spark in one request.
+package metrichistory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Format is a file format metric history can be written in.
+type Format string
+
+const (
+	// FormatNDJSON writes each metrics report as a JSON object on its own line.
+	FormatNDJSON Format = "ndjson"
+	// FormatParquet writes a Parquet file with a row for each numeric metric of each report.
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat parses a format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatNDJSON, FormatParquet:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, must be %q or %q", s, FormatNDJSON, FormatParquet)
+	}
+}
+
+// ContentType returns the MIME type of files in the format.
+func (f Format) ContentType() string {
+	if f == FormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "application/x-ndjson"
+}
+
+// Record is a metrics report of a trial.
+type Record struct {
+	TrialID      int            `bun:"trial_id" json:"trial_id"`
+	TrialRunID   int            `bun:"trial_run_id" json:"trial_run_id"`
+	Group        string         `bun:"metric_group" json:"group"`
+	TotalBatches int            `bun:"total_batches" json:"total_batches"`
+	EndTime      time.Time      `bun:"end_time" json:"end_time"`
+	Metrics      map[string]any `bun:"metrics" json:"metrics"`
+}
+
+// Writer writes records in a format.
+type Writer interface {
+	Write(r Record) error
+	// Close finishes the file. It doesn't close the underlying writer.
+	Close() error
+}
+
+// NewWriter returns a Writer of records in the format to w.
+func NewWriter(w io.Writer, f Format) (Writer, error) {
+	switch f {
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatParquet:
+		return &parquetWriter{w: parquet.NewGenericWriter[parquetRow](w,
+			parquet.Compression(&parquet.Snappy),
+			parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+		)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", f)
+	}
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(r Record) error {
+	// Encode adds the newline that ends each record.
+	return w.enc.Encode(r)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+// parquetRowGroupSize is the number of rows buffered before they are written as a row group, so
+// that long histories are written as they are read.
+const parquetRowGroupSize = 64 * 1024
+
+// parquetRow is a row of Parquet files, which hold one row per metric so that every file has the
+// same schema whatever metrics were reported.
+type parquetRow struct {
+	TrialID      int64     `parquet:"trial_id"`
+	TrialRunID   int64     `parquet:"trial_run_id"`
+	Group        string    `parquet:"group"`
+	TotalBatches int64     `parquet:"total_batches"`
+	EndTime      time.Time `parquet:"end_time,timestamp(microsecond)"`
+	Metric       string    `parquet:"metric"`
+	Value        float64   `parquet:"value"`
+}
+
+type parquetWriter struct {
+	w *parquet.GenericWriter[parquetRow]
+}
+
+func (w *parquetWriter) Write(r Record) error {
+	metrics := NumericMetrics(r.Metrics)
+	rows := make([]parquetRow, 0, len(metrics))
+	for _, m := range metrics {
+		rows = append(rows, parquetRow{
+			TrialID:      int64(r.TrialID),
+			TrialRunID:   int64(r.TrialRunID),
+			Group:        r.Group,
+			TotalBatches: int64(r.TotalBatches),
+			EndTime:      r.EndTime.UTC(),
+			Metric:       m.Name,
+			Value:        m.Value,
+		})
+	}
+	_, err := w.w.Write(rows)
+	return err
+}
+
+func (w *parquetWriter) Close() error {
+	return w.w.Close()
+}
+
+// Metric is a numeric metric of a report.
+type Metric struct {
+	Name  string
+	Value float64
+}
+
+// NumericMetrics returns the numeric metrics of a report sorted by name. Booleans are converted to
+// 0 or 1, the strings "NaN", "Infinity" and "-Infinity" that non-finite metrics are stored as are
+// converted back, and other values are skipped.
+func NumericMetrics(metrics map[string]any) []Metric {
+	var out []Metric
+	for name, v := range metrics {
+		var f float64
+		switch v := v.(type) {
+		case float64:
+			f = v
+		case bool:
+			if v {
+				f = 1
+			}
+		case string:
+			switch v {
+			case "NaN":
+				f = math.NaN()
+			case "Infinity":
+				f = math.Inf(1)
+			case "-Infinity":
+				f = math.Inf(-1)
+			default:
+				continue
+			}
+		default:
+			continue
+		}
+		out = append(out, Metric{Name: name, Value: f})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,99 @@
+package metrichistory
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("parquet")
+	require.NoError(t, err)
+	require.Equal(t, FormatParquet, f)
+	_, err = ParseFormat("csv")
+	require.Error(t, err)
+}
+
+func TestNumericMetrics(t *testing.T) {
+	metrics := NumericMetrics(map[string]any{
+		"loss":     0.5,
+		"accuracy": 0.9,
+		"done":     true,
+		"lr":       "Infinity",
+		"name":     "resnet",
+		"nested":   map[string]any{"a": 1.0},
+	})
+	require.Equal(t, []Metric{
+		{Name: "accuracy", Value: 0.9},
+		{Name: "done", Value: 1},
+		{Name: "loss", Value: 0.5},
+		{Name: "lr", Value: math.Inf(1)},
+	}, metrics)
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatNDJSON)
+	require.NoError(t, err)
+	end := time.Date(2024, 11, 15, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= 2; i++ {
+		require.NoError(t, w.Write(Record{
+			TrialID: 1, TrialRunID: 1, Group: "training", TotalBatches: 100 * i, EndTime: end,
+			Metrics: map[string]any{"loss": 1 / float64(i)},
+		}))
+	}
+	require.NoError(t, w.Close())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var r Record
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &r))
+	require.Equal(t, 200, r.TotalBatches)
+	require.Equal(t, "training", r.Group)
+	require.Equal(t, map[string]any{"loss": 0.5}, r.Metrics)
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatParquet)
+	require.NoError(t, err)
+	endTime := time.Date(2024, 11, 15, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, w.Write(Record{
+		TrialID: 1, TrialRunID: 1, Group: "validation", TotalBatches: 100, EndTime: endTime,
+		Metrics: map[string]any{"loss": 0.5, "accuracy": 0.9, "note": "skipped"},
+	}))
+	require.NoError(t, w.Write(Record{
+		TrialID: 2, TrialRunID: 3, Group: "training", TotalBatches: 200, EndTime: endTime,
+		Metrics: map[string]any{"loss": "Infinity"},
+	}))
+	require.NoError(t, w.Close())
+
+	// Read the file back, with its schema, as any other reader would.
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Equal(t, int64(3), f.NumRows())
+	var names []string
+	for _, field := range f.Schema().Fields() {
+		names = append(names, field.Name())
+	}
+	require.Equal(t, []string{
+		"trial_id", "trial_run_id", "group", "total_batches", "end_time", "metric", "value",
+	}, names)
+
+	rows, err := parquet.Read[parquetRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	for i := range rows {
+		rows[i].EndTime = rows[i].EndTime.UTC()
+	}
+	require.Equal(t, []parquetRow{
+		{1, 1, "validation", 100, endTime, "accuracy", 0.9},
+		{1, 1, "validation", 100, endTime, "loss", 0.5},
+		{2, 3, "training", 200, endTime, "loss", math.Inf(1)},
+	}, rows)
+}
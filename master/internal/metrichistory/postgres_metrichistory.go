@@ -0,0 +1,62 @@
+package metrichistory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// Filter selects the reports to export.
+type Filter struct {
+	// TrialID, if set, selects the reports of a trial.
+	TrialID *int
+	// ExperimentID, if set, selects the reports of every trial of an experiment.
+	ExperimentID *int
+	// Group, if set, selects only the reports of a metric group.
+	Group *string
+}
+
+// Export writes every unarchived metrics report matching the filter to w, in order of trial,
+// trial run and batches. Reports are streamed from the database rather than loaded all at once.
+func Export(ctx context.Context, f Filter, w Writer) error {
+	q := db.Bun().NewSelect().
+		TableExpr("metrics AS m").
+		ColumnExpr("m.trial_id, m.trial_run_id, m.metric_group, m.total_batches, m.end_time").
+		ColumnExpr(
+			"COALESCE(m.metrics->'avg_metrics', m.metrics->'validation_metrics', '{}'::jsonb) AS metrics",
+		).
+		Where("NOT m.archived").
+		OrderExpr("m.trial_id, m.trial_run_id, m.total_batches")
+	if f.TrialID != nil {
+		q = q.Where("m.trial_id = ?", *f.TrialID)
+	}
+	if f.ExperimentID != nil {
+		q = q.Where("m.trial_id IN (?)", db.Bun().NewSelect().
+			Table("trials").
+			Column("id").
+			Where("experiment_id = ?", *f.ExperimentID))
+	}
+	if f.Group != nil {
+		q = q.Where("m.metric_group = ?", *f.Group)
+	}
+
+	rows, err := q.Rows(ctx)
+	if err != nil {
+		return fmt.Errorf("querying metrics to export: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r Record
+		if err := db.Bun().ScanRow(ctx, rows, &r); err != nil {
+			return fmt.Errorf("scanning metrics to export: %w", err)
+		}
+		if err := w.Write(r); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading metrics to export: %w", err)
+	}
+	return w.Close()
+}
@@ -0,0 +1,131 @@
+// Package metricmetadata resolves how clients should display the metrics of an experiment, such as
+// their units and whether they should be minimized, from what its config and its harness declare.
+package metricmetadata
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+const (
+	// MaxDeclared caps how many metrics the harness can declare metadata for at once.
+	MaxDeclared = 1000
+	// MaxNameLength caps the length of the names of metrics metadata is declared for.
+	MaxNameLength = 255
+	// MaxUnitLength caps the length of the unit of a metric.
+	MaxUnitLength = 64
+	// MaxDisplayNameLength caps the length of the display name of a metric.
+	MaxDisplayNameLength = 128
+)
+
+// Metric is the metadata of a metric of an experiment, with the groups it was reported in.
+type Metric struct {
+	model.MetricMetadata
+	Groups []model.MetricGroup `json:"groups"`
+}
+
+// Validate returns an error wrapping db.ErrInvalidInput if declared metadata is invalid.
+func Validate(declared []model.MetricMetadata) error {
+	if len(declared) > MaxDeclared {
+		return fmt.Errorf("%w: metadata can be declared for at most %d metrics at once; got %d",
+			db.ErrInvalidInput, MaxDeclared, len(declared))
+	}
+	for _, m := range declared {
+		switch {
+		case m.Name == "" || len(m.Name) > MaxNameLength:
+			return fmt.Errorf("%w: metric names must have between 1 and %d characters; got %q",
+				db.ErrInvalidInput, MaxNameLength, m.Name)
+		case len(m.Unit) > MaxUnitLength:
+			return fmt.Errorf("%w: the unit of %s must have at most %d characters",
+				db.ErrInvalidInput, m.Name, MaxUnitLength)
+		case len(m.DisplayName) > MaxDisplayNameLength:
+			return fmt.Errorf("%w: the display name of %s must have at most %d characters",
+				db.ErrInvalidInput, m.Name, MaxDisplayNameLength)
+		case m.Direction != "" && m.Direction != model.MetricDirectionMinimize &&
+			m.Direction != model.MetricDirectionMaximize:
+			return fmt.Errorf("%w: the direction of %s must be minimize or maximize; got %q",
+				db.ErrInvalidInput, m.Name, m.Direction)
+		case m.Scale != "" && m.Scale != model.MetricScaleLinear && m.Scale != model.MetricScaleLog:
+			return fmt.Errorf("%w: the scale of %s must be linear or log; got %q",
+				db.ErrInvalidInput, m.Name, m.Scale)
+		}
+	}
+	return nil
+}
+
+// FromConfig returns the metadata an experiment config declares, by metric name. The direction of
+// the searcher metric follows the searcher unless the metadata declares it.
+func FromConfig(config expconf.ExperimentConfig) map[string]model.MetricMetadata {
+	out := make(map[string]model.MetricMetadata)
+	for name, c := range config.MetricMetadata() {
+		m := model.MetricMetadata{Name: name}
+		if c.Unit() != nil {
+			m.Unit = *c.Unit()
+		}
+		if c.Direction() != nil {
+			m.Direction = model.MetricDirection(*c.Direction())
+		}
+		if c.DisplayName() != nil {
+			m.DisplayName = *c.DisplayName()
+		}
+		if c.Scale() != nil {
+			m.Scale = model.MetricScale(*c.Scale())
+		}
+		out[name] = m
+	}
+
+	if metric := config.Searcher().Metric(); metric != "" {
+		direction := model.MetricDirectionMaximize
+		if config.Searcher().SmallerIsBetter() {
+			direction = model.MetricDirectionMinimize
+		}
+		out[metric] = out[metric].Merge(model.MetricMetadata{Name: metric, Direction: direction})
+	}
+	return out
+}
+
+// Resolve returns the metadata of the metrics of an experiment, sorted by name. Metrics are the
+// ones the experiment reported and the ones with declared metadata. What the config declares takes
+// precedence over what the harness declares.
+func Resolve(
+	config map[string]model.MetricMetadata,
+	declared []model.MetricMetadata,
+	reported map[model.MetricGroup][]string,
+) []Metric {
+	byName := make(map[string]*Metric)
+	get := func(name string) *Metric {
+		m, ok := byName[name]
+		if !ok {
+			m = &Metric{MetricMetadata: model.MetricMetadata{Name: name}, Groups: []model.MetricGroup{}}
+			byName[name] = m
+		}
+		return m
+	}
+	for group, names := range reported {
+		for _, name := range names {
+			m := get(name)
+			m.Groups = append(m.Groups, group)
+		}
+	}
+	for _, d := range declared {
+		m := get(d.Name)
+		m.MetricMetadata = m.MetricMetadata.Merge(d)
+	}
+	for name, c := range config {
+		m := get(name)
+		m.MetricMetadata = c.Merge(m.MetricMetadata)
+	}
+
+	out := make([]Metric, 0, len(byName))
+	for _, m := range byName {
+		m.MetricMetadata.ExperimentID = 0
+		sort.Slice(m.Groups, func(i, j int) bool { return m.Groups[i] < m.Groups[j] })
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
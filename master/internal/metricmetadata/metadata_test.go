@@ -0,0 +1,105 @@
+package metricmetadata
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate([]model.MetricMetadata{{
+		Name:      "loss",
+		Unit:      "nats",
+		Direction: model.MetricDirectionMinimize,
+		Scale:     model.MetricScaleLog,
+	}}))
+
+	for _, bad := range []model.MetricMetadata{
+		{Name: ""},
+		{Name: strings.Repeat("x", MaxNameLength+1)},
+		{Name: "loss", Unit: strings.Repeat("x", MaxUnitLength+1)},
+		{Name: "loss", DisplayName: strings.Repeat("x", MaxDisplayNameLength+1)},
+		{Name: "loss", Direction: "down"},
+		{Name: "loss", Scale: "exponential"},
+	} {
+		err := Validate([]model.MetricMetadata{bad})
+		require.True(t, errors.Is(err, db.ErrInvalidInput), "%+v: %v", bad, err)
+	}
+	err := Validate(make([]model.MetricMetadata, MaxDeclared+1))
+	require.True(t, errors.Is(err, db.ErrInvalidInput))
+}
+
+func TestFromConfig(t *testing.T) {
+	config := expconf.ExperimentConfig{
+		RawSearcher: &expconf.SearcherConfig{
+			RawMetric:          ptrs.Ptr("validation_error"),
+			RawSmallerIsBetter: ptrs.Ptr(true),
+		},
+		RawMetricMetadata: expconf.MetricMetadataConfig{
+			"validation_error": {RawUnit: ptrs.Ptr("%")},
+			"accuracy": {
+				RawDirection:   ptrs.Ptr("maximize"),
+				RawDisplayName: ptrs.Ptr("Accuracy"),
+			},
+		},
+	}
+	require.Equal(t, map[string]model.MetricMetadata{
+		"validation_error": {
+			Name:      "validation_error",
+			Unit:      "%",
+			Direction: model.MetricDirectionMinimize,
+		},
+		"accuracy": {
+			Name:        "accuracy",
+			Direction:   model.MetricDirectionMaximize,
+			DisplayName: "Accuracy",
+		},
+	}, FromConfig(config))
+
+	// The config's direction of the searcher metric wins over the searcher's.
+	config.RawMetricMetadata["validation_error"] = expconf.MetricMetadata{
+		RawDirection: ptrs.Ptr("maximize"),
+	}
+	require.Equal(t, model.MetricDirectionMaximize,
+		FromConfig(config)["validation_error"].Direction)
+}
+
+func TestResolve(t *testing.T) {
+	config := map[string]model.MetricMetadata{
+		"loss": {Name: "loss", Direction: model.MetricDirectionMinimize, Scale: model.MetricScaleLog},
+	}
+	declared := []model.MetricMetadata{
+		{ExperimentID: 1, Name: "loss", Unit: "nats", Scale: model.MetricScaleLinear},
+		{ExperimentID: 1, Name: "lr", DisplayName: "Learning rate"},
+	}
+	reported := map[model.MetricGroup][]string{
+		model.TrainingMetricGroup:   {"loss", "lr"},
+		model.ValidationMetricGroup: {"loss", "accuracy"},
+	}
+	require.Equal(t, []Metric{
+		{
+			MetricMetadata: model.MetricMetadata{Name: "accuracy"},
+			Groups:         []model.MetricGroup{model.ValidationMetricGroup},
+		},
+		{
+			MetricMetadata: model.MetricMetadata{
+				Name:      "loss",
+				Unit:      "nats",
+				Direction: model.MetricDirectionMinimize,
+				Scale:     model.MetricScaleLog,
+			},
+			Groups: []model.MetricGroup{model.TrainingMetricGroup, model.ValidationMetricGroup},
+		},
+		{
+			MetricMetadata: model.MetricMetadata{Name: "lr", DisplayName: "Learning rate"},
+			Groups:         []model.MetricGroup{model.TrainingMetricGroup},
+		},
+	}, Resolve(config, declared, reported))
+}
@@ -0,0 +1,49 @@
+package metricmetadata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Declare records the metadata the harness declares for metrics of an experiment. Fields it leaves
+// empty keep what was declared for them before.
+func Declare(ctx context.Context, experimentID int, declared []model.MetricMetadata) error {
+	if len(declared) == 0 {
+		return nil
+	}
+	// Upserting in a consistent order keeps concurrent declarations from deadlocking.
+	sort.Slice(declared, func(i, j int) bool { return declared[i].Name < declared[j].Name })
+	now := time.Now()
+	for i := range declared {
+		declared[i].ExperimentID = experimentID
+		declared[i].UpdatedAt = now
+	}
+	if _, err := db.Bun().NewInsert().Model(&declared).
+		On("CONFLICT (experiment_id, name) DO UPDATE").
+		Set("unit = COALESCE(NULLIF(EXCLUDED.unit, ''), metric_metadata.unit)").
+		Set("direction = COALESCE(NULLIF(EXCLUDED.direction, ''), metric_metadata.direction)").
+		Set("display_name = COALESCE(NULLIF(EXCLUDED.display_name, ''), metric_metadata.display_name)").
+		Set("scale = COALESCE(NULLIF(EXCLUDED.scale, ''), metric_metadata.scale)").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("declaring metric metadata of experiment %d: %w", experimentID, err)
+	}
+	return nil
+}
+
+// Declared returns the metadata the harness declared for metrics of an experiment.
+func Declared(ctx context.Context, experimentID int) ([]model.MetricMetadata, error) {
+	declared := []model.MetricMetadata{}
+	if err := db.Bun().NewSelect().Model(&declared).
+		Where("experiment_id = ?", experimentID).
+		Order("name").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting metric metadata of experiment %d: %w", experimentID, err)
+	}
+	return declared, nil
+}
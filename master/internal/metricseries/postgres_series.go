@@ -0,0 +1,51 @@
+package metricseries
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// ReportCount returns how many reports of a metric group a trial has.
+func ReportCount(ctx context.Context, trialID int, group model.MetricGroup) (int, error) {
+	count, err := db.BunSelectMetricsQuery(group, false).Table("metrics").
+		Where("trial_id = ?", trialID).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("counting %s metrics of trial %d: %w", group, trialID, err)
+	}
+	return count, nil
+}
+
+// SampleReports returns a random sample of limit reports of a metric group of a trial, ordered by
+// batches.
+func SampleReports(
+	ctx context.Context, trialID int, group model.MetricGroup, limit int,
+) ([]*trialv1.MetricsReport, error) {
+	var res []*trialv1.MetricsReport
+	if err := db.BunSelectMetricsQuery(group, false).Table("metrics").
+		Column("trial_id", "metrics", "total_batches", "archived", "id", "trial_run_id").
+		ColumnExpr("proto_time(end_time) AS end_time").
+		ColumnExpr("metric_group AS group").
+		Where("trial_id = ?", trialID).
+		OrderExpr("random()").
+		Limit(limit).
+		Scan(ctx, &res); err != nil {
+		return nil, fmt.Errorf("sampling %s metrics of trial %d: %w", group, trialID, err)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].TotalBatches < res[j].TotalBatches
+	})
+	for _, r := range res {
+		// Truncate the timestamp to milliseconds like db.GetMetrics.
+		r.EndTime = timestamppb.New(r.EndTime.AsTime().Truncate(time.Millisecond))
+	}
+	return res, nil
+}
@@ -0,0 +1,103 @@
+package metricseries
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// MetricsReports are the reports of a trial returned by GetMetrics.
+type MetricsReports struct {
+	Reports []*trialv1.MetricsReport
+	Group   model.MetricGroup
+}
+
+func (r MetricsReports) values(i int) map[string]*structpb.Value {
+	path := model.TrialMetricsJSONPath(r.Group == model.ValidationMetricGroup)
+	return r.Reports[i].GetMetrics().GetFields()[path].GetStructValue().GetFields()
+}
+
+// Len implements Reports.
+func (r MetricsReports) Len() int {
+	return len(r.Reports)
+}
+
+// Batches implements Reports.
+func (r MetricsReports) Batches(i int) int {
+	return int(r.Reports[i].TotalBatches)
+}
+
+// Metrics implements Reports.
+func (r MetricsReports) Metrics() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for i := range r.Reports {
+		for name := range r.values(i) {
+			if _, ok := r.Value(i, name); ok && !seen[name] {
+				seen[name] = true
+				out = append(out, name)
+			}
+		}
+	}
+	return out
+}
+
+// Value implements Reports.
+func (r MetricsReports) Value(i int, metric string) (float64, bool) {
+	v, ok := r.values(i)[metric].GetKind().(*structpb.Value_NumberValue)
+	if !ok || math.IsNaN(v.NumberValue) || math.IsInf(v.NumberValue, 0) {
+		return 0, false
+	}
+	return v.NumberValue, true
+}
+
+// SetValue implements Reports.
+func (r MetricsReports) SetValue(i int, metric string, value float64) {
+	r.values(i)[metric] = structpb.NewNumberValue(value)
+}
+
+// Measurements are the reports of a trial returned by CompareTrials.
+type Measurements []db.MetricMeasurements
+
+// Len implements Reports.
+func (m Measurements) Len() int {
+	return len(m)
+}
+
+// Batches implements Reports.
+func (m Measurements) Batches(i int) int {
+	return int(m[i].Batches)
+}
+
+// Metrics implements Reports.
+func (m Measurements) Metrics() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for i := range m {
+		for name := range m[i].Values {
+			if _, ok := m.Value(i, name); ok && !seen[name] {
+				seen[name] = true
+				out = append(out, name)
+			}
+		}
+	}
+	return out
+}
+
+// Value implements Reports.
+func (m Measurements) Value(i int, metric string) (float64, bool) {
+	v, ok := m[i].Values[metric].(float64)
+	if !ok || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// SetValue implements Reports.
+func (m Measurements) SetValue(i int, metric string, value float64) {
+	m[i].Values[metric] = value
+}
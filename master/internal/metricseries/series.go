@@ -0,0 +1,194 @@
+// Package metricseries smooths the metrics of trials on the server and reduces them to the points
+// a chart can draw, so clients don't pull every report of long-running trials to smooth them in
+// the browser.
+package metricseries
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const (
+	// MaxMaxPoints caps how many reports of a trial may be asked for.
+	MaxMaxPoints = 10000
+	// DefaultSampledPoints is how many reports of a trial the sampled tier reads when the request
+	// doesn't cap them.
+	DefaultSampledPoints = 1000
+	// ExactTierLimit is the most reports a trial may have for the auto tier to read all of them.
+	ExactTierLimit = 100000
+)
+
+// ValidateOptions returns an error if smoothing or maxPoints are out of range.
+func ValidateOptions(smoothing float64, maxPoints int) error {
+	switch {
+	case smoothing < 0 || smoothing >= 1 || math.IsNaN(smoothing):
+		return fmt.Errorf("smoothing must be at least 0 and less than 1; got %v", smoothing)
+	case maxPoints != 0 && (maxPoints < 3 || maxPoints > MaxMaxPoints):
+		return fmt.Errorf("max_points must be between 3 and %d; got %d", MaxMaxPoints, maxPoints)
+	}
+	return nil
+}
+
+// Resolve returns the tier to read a trial with, given how many reports it has. requested must not
+// be unspecified, which each request defaults differently.
+func Resolve(requested apiv1.MetricSeriesTier, reports int) apiv1.MetricSeriesTier {
+	if requested != apiv1.MetricSeriesTier_METRIC_SERIES_TIER_AUTO {
+		return requested
+	}
+	if reports > ExactTierLimit {
+		return apiv1.MetricSeriesTier_METRIC_SERIES_TIER_SAMPLED
+	}
+	return apiv1.MetricSeriesTier_METRIC_SERIES_TIER_EXACT
+}
+
+// Reports is the reports of a trial's metric group, ordered by batches.
+type Reports interface {
+	Len() int
+	Batches(i int) int
+	// Metrics returns the names of the metrics the reports have numeric values of.
+	Metrics() []string
+	// Value returns the value of a metric in a report, if it is a finite number.
+	Value(i int, metric string) (float64, bool)
+	SetValue(i int, metric string, value float64)
+}
+
+// Reduce smooths the numeric metrics of reports in place and returns the indices, in order, of at
+// most maxPoints reports that keep the shape of the series of each metric. A maxPoints of 0 keeps
+// every report.
+func Reduce(reports Reports, smoothing float64, maxPoints int) []int {
+	if reports.Len() <= maxPoints {
+		maxPoints = 0
+	}
+	metrics := reports.Metrics()
+	sort.Strings(metrics)
+
+	keep := make(map[int]bool)
+	// Each metric gets an even share of the reports, so that no metric is drawn with fewer points
+	// than another.
+	share := 0
+	if maxPoints != 0 && len(metrics) > 0 {
+		share = maxPoints / len(metrics)
+	}
+	for _, metric := range metrics {
+		var indices []int
+		var points []Point
+		for i := 0; i < reports.Len(); i++ {
+			if v, ok := reports.Value(i, metric); ok {
+				indices = append(indices, i)
+				points = append(points, Point{Batches: reports.Batches(i), Value: v})
+			}
+		}
+		points = Smooth(points, smoothing)
+		for j, p := range points {
+			reports.SetValue(indices[j], metric, p.Value)
+		}
+		if maxPoints == 0 {
+			continue
+		}
+		for _, j := range downsampleIndices(points, share) {
+			keep[indices[j]] = true
+		}
+	}
+
+	out := make([]int, 0, reports.Len())
+	for i := 0; i < reports.Len(); i++ {
+		if maxPoints == 0 || keep[i] {
+			out = append(out, i)
+		}
+	}
+	if maxPoints != 0 && len(out) > maxPoints {
+		// With more metrics than a third of maxPoints, each metric keeps at least its first, last
+		// and largest point, and the reports kept are thinned evenly.
+		thinned := make([]int, maxPoints)
+		for j := range thinned {
+			thinned[j] = out[j*(len(out)-1)/(maxPoints-1)]
+		}
+		out = thinned
+	}
+	return out
+}
+
+// Point is the value of a metric after a number of batches.
+type Point struct {
+	Batches int
+	Value   float64
+}
+
+// Smooth returns the exponential moving average of points, debiased so that the first points
+// aren't pulled towards zero. A factor of 0 returns the points as they are.
+func Smooth(points []Point, factor float64) []Point {
+	if factor == 0 {
+		return points
+	}
+	out := make([]Point, len(points))
+	last, weight := 0.0, 0.0
+	for i, p := range points {
+		last = last*factor + (1-factor)*p.Value
+		weight = weight*factor + (1 - factor)
+		out[i] = Point{Batches: p.Batches, Value: last / weight}
+	}
+	return out
+}
+
+// Downsample reduces points to at most max points with the Largest-Triangle-Three-Buckets
+// algorithm, which keeps the first and last points and the peaks and troughs that give a series
+// its shape.
+func Downsample(points []Point, max int) []Point {
+	indices := downsampleIndices(points, max)
+	out := make([]Point, len(indices))
+	for i, j := range indices {
+		out[i] = points[j]
+	}
+	return out
+}
+
+// downsampleIndices returns the indices of the points Downsample keeps.
+func downsampleIndices(points []Point, max int) []int {
+	max = int(math.Max(float64(max), 3))
+	if len(points) <= max {
+		out := make([]int, len(points))
+		for i := range out {
+			out[i] = i
+		}
+		return out
+	}
+	out := make([]int, 0, max)
+	out = append(out, 0)
+	// The points between the first and last are split into max-2 buckets, and the point of each
+	// bucket that makes the largest triangle with the previously kept point and the average of the
+	// next bucket is kept.
+	size := float64(len(points)-2) / float64(max-2)
+	prev := 0
+	for i := 0; i < max-2; i++ {
+		start := int(math.Floor(float64(i)*size)) + 1
+		end := int(math.Floor(float64(i+1)*size)) + 1
+
+		nextStart, nextEnd := end, int(math.Floor(float64(i+2)*size))+1
+		if nextEnd > len(points) {
+			nextEnd = len(points)
+		}
+		var avgX, avgY float64
+		for _, p := range points[nextStart:nextEnd] {
+			avgX += float64(p.Batches)
+			avgY += p.Value
+		}
+		n := float64(nextEnd - nextStart)
+		avgX, avgY = avgX/n, avgY/n
+
+		a := points[prev]
+		best, bestArea := start, -1.0
+		for j := start; j < end; j++ {
+			area := math.Abs((float64(a.Batches)-avgX)*(points[j].Value-a.Value) -
+				(float64(a.Batches)-float64(points[j].Batches))*(avgY-a.Value))
+			if area > bestArea {
+				best, bestArea = j, area
+			}
+		}
+		out = append(out, best)
+		prev = best
+	}
+	return append(out, len(points)-1)
+}
@@ -0,0 +1,142 @@
+package metricseries
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+func TestValidateOptions(t *testing.T) {
+	require.NoError(t, ValidateOptions(0, 0))
+	require.NoError(t, ValidateOptions(0.9, 3))
+	require.NoError(t, ValidateOptions(0, MaxMaxPoints))
+	for _, bad := range []struct {
+		smoothing float64
+		maxPoints int
+	}{
+		{smoothing: 1},
+		{smoothing: -0.1},
+		{smoothing: math.NaN()},
+		{maxPoints: 2},
+		{maxPoints: MaxMaxPoints + 1},
+	} {
+		require.Error(t, ValidateOptions(bad.smoothing, bad.maxPoints), "%+v", bad)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	auto := apiv1.MetricSeriesTier_METRIC_SERIES_TIER_AUTO
+	exact := apiv1.MetricSeriesTier_METRIC_SERIES_TIER_EXACT
+	sampled := apiv1.MetricSeriesTier_METRIC_SERIES_TIER_SAMPLED
+	require.Equal(t, exact, Resolve(auto, ExactTierLimit))
+	require.Equal(t, sampled, Resolve(auto, ExactTierLimit+1))
+	require.Equal(t, exact, Resolve(exact, ExactTierLimit+1))
+	require.Equal(t, sampled, Resolve(sampled, 0))
+}
+
+func TestSmooth(t *testing.T) {
+	points := []Point{{1, 1}, {2, 3}, {3, 3}}
+	require.Equal(t, points, Smooth(points, 0))
+
+	smoothed := Smooth(points, 0.5)
+	// Debiasing makes the first smoothed value the first value.
+	require.InDelta(t, 1, smoothed[0].Value, 1e-9)
+	require.InDelta(t, (0.5*0.5+0.5*3)/0.75, smoothed[1].Value, 1e-9)
+	require.Equal(t, 3, smoothed[2].Batches)
+
+	constant := Smooth([]Point{{1, 5}, {2, 5}, {3, 5}}, 0.9)
+	for _, p := range constant {
+		require.InDelta(t, 5, p.Value, 1e-9)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	short := []Point{{1, 1}, {2, 2}}
+	require.Equal(t, short, Downsample(short, 10))
+
+	var points []Point
+	for i := 0; i < 1000; i++ {
+		points = append(points, Point{Batches: i, Value: math.Sin(float64(i) / 50)})
+	}
+	// A spike must survive downsampling.
+	points[500].Value = 100
+
+	out := Downsample(points, 50)
+	require.Len(t, out, 50)
+	require.Equal(t, points[0], out[0])
+	require.Equal(t, points[len(points)-1], out[len(out)-1])
+	require.Contains(t, out, points[500])
+	for i := 1; i < len(out); i++ {
+		require.Greater(t, out[i].Batches, out[i-1].Batches)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	var reports Measurements
+	for i := 0; i < 1000; i++ {
+		values := map[string]any{"loss": math.Cos(float64(i) / 50), "name": "a"}
+		if i%2 == 0 {
+			values["accuracy"] = math.Sin(float64(i) / 50)
+		}
+		reports = append(reports, db.MetricMeasurements{Batches: uint(i), Values: values})
+	}
+	// A spike of either metric must survive reducing the reports.
+	reports[501].Values["loss"] = 100.0
+	reports[700].Values["accuracy"] = -100.0
+
+	kept := Reduce(reports, 0, 100)
+	require.LessOrEqual(t, len(kept), 100)
+	require.Contains(t, kept, 0)
+	require.Contains(t, kept, 501)
+	require.Contains(t, kept, 700)
+	require.Contains(t, kept, 999)
+	require.IsIncreasing(t, kept)
+
+	require.Len(t, Reduce(reports, 0, 0), 1000)
+	require.Len(t, Reduce(reports[:10], 0, 10), 10)
+
+	// With more metrics than a third of the points, the reports are still capped.
+	require.Len(t, Reduce(reports[:30], 0, 4), 4)
+}
+
+func TestReduceSmooths(t *testing.T) {
+	reports := Measurements{
+		{Batches: 1, Values: map[string]any{"loss": 1.0}},
+		{Batches: 2, Values: map[string]any{"loss": nil}},
+		{Batches: 3, Values: map[string]any{"loss": 3.0}},
+	}
+	require.Equal(t, []int{0, 1, 2}, Reduce(reports, 0.5, 0))
+	require.InDelta(t, 1, reports[0].Values["loss"], 1e-9)
+	require.Nil(t, reports[1].Values["loss"])
+	require.InDelta(t, (0.5*0.5+0.5*3)/0.75, reports[2].Values["loss"], 1e-9)
+}
+
+func TestMetricsReports(t *testing.T) {
+	report := func(batches int32, values map[string]any) *trialv1.MetricsReport {
+		metrics, err := structpb.NewStruct(map[string]any{"validation_metrics": values})
+		require.NoError(t, err)
+		return &trialv1.MetricsReport{TotalBatches: batches, Metrics: metrics}
+	}
+	reports := MetricsReports{
+		Reports: []*trialv1.MetricsReport{
+			report(1, map[string]any{"loss": 1.0, "name": "a"}),
+			report(2, map[string]any{"loss": 3.0}),
+		},
+		Group: model.ValidationMetricGroup,
+	}
+	require.Equal(t, []string{"loss"}, reports.Metrics())
+
+	require.Equal(t, []int{0, 1}, Reduce(reports, 0.5, 0))
+	v, ok := reports.Value(1, "loss")
+	require.True(t, ok)
+	require.InDelta(t, (0.5*0.5+0.5*3)/0.75, v, 1e-9)
+	_, ok = reports.Value(0, "name")
+	require.False(t, ok)
+}
@@ -0,0 +1,202 @@
+package metricsexport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/egress"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	maxBatchSize = 50
+	// pollInterval is how often the queue is checked for items due to be retried.
+	pollInterval = 10 * time.Second
+	sendTimeout  = 30 * time.Second
+	// leaseDuration is how long items are leased to the exporter for, longer than it takes to send
+	// a batch. Items of a batch that the exporter didn't finish are sent again once it expires.
+	leaseDuration = 2 * maxBatchSize * sendTimeout
+)
+
+var singletonExporter *exportWorker
+
+// Init starts the exporter singleton.
+func Init() {
+	singletonExporter = newExportWorker()
+}
+
+// Deinit stops the exporter.
+func Deinit() {
+	singletonExporter.Close()
+}
+
+type exportWorker struct {
+	log *log.Entry
+	cl  *http.Client //nolint:forbidigo
+
+	wake   chan struct{}
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+func newExportWorker() *exportWorker {
+	ctx, cancel := context.WithCancel(context.Background()) // Exporter-lifetime scoped context.
+
+	w := &exportWorker{
+		log:    log.WithField("component", "metrics-exporter"),
+		cl:     egress.Client(),
+		wake:   make(chan struct{}, 1),
+		cancel: cancel,
+	}
+	w.wake <- struct{}{} // Always attempt to send existing items.
+
+	// No other exporter runs alongside this one, so items still leased were left behind by a
+	// previous master.
+	if err := releaseLeases(ctx); err != nil {
+		w.log.WithError(err).Error("failed to release metrics export leases")
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.work(ctx)
+	}()
+	return w
+}
+
+// Wake attempts to wake the exporter.
+func (w *exportWorker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+		// Already woken; the pending wake will pick up the item that caused this one.
+	}
+}
+
+func (w *exportWorker) Close() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *exportWorker) work(ctx context.Context) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			w.log.Errorf("uncaught error, metrics exporter crashed: %v", rec)
+		}
+	}()
+
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.wake:
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+		if err := w.sendAll(ctx); err != nil {
+			w.log.WithError(err).Error("failed to export metrics")
+		}
+	}
+}
+
+func (w *exportWorker) sendAll(ctx context.Context) error {
+	for {
+		n, err := w.sendBatch(ctx)
+		if err != nil || n == 0 {
+			return err
+		}
+	}
+}
+
+func (w *exportWorker) sendBatch(ctx context.Context) (int, error) {
+	items, err := leaseItems(ctx, maxBatchSize, leaseDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	destinations := map[int]*model.MetricsExportDestination{}
+	for _, item := range items {
+		d, ok := destinations[item.DestinationID]
+		if !ok {
+			if d, err = destinationByID(ctx, item.DestinationID); err != nil {
+				return 0, err
+			}
+			destinations[item.DestinationID] = d
+		}
+
+		syslog := w.log.WithFields(log.Fields{
+			"destination": d.Name, "trial-id": item.TrialID, "attempt": item.Attempts + 1,
+		})
+		sendErr := w.send(ctx, d, item)
+		switch {
+		case sendErr == nil:
+			sent++
+			err = itemDone(ctx, item)
+		case ctx.Err() != nil:
+			return 0, ctx.Err()
+		case item.Attempts+1 >= maxAttempts:
+			syslog.WithError(sendErr).Error("dropping metrics export after too many failed attempts")
+			err = itemDone(ctx, item)
+		default:
+			syslog.WithError(sendErr).Warn("failed to export metrics, will retry")
+			err = retryItem(ctx, item, sendErr)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("updating metrics export queue: %w", err)
+		}
+	}
+	return sent, nil
+}
+
+func (w *exportWorker) send(
+	ctx context.Context, d *model.MetricsExportDestination, item queueItem,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	if err := CheckDestination(ctx, d.URL); err != nil {
+		return err
+	}
+	e, err := newExporter(w.cl, d)
+	if err != nil {
+		return err
+	}
+	r, err := getOrCreateRun(ctx, e, d.ID, item.TrialID)
+	if err != nil {
+		return err
+	}
+
+	switch item.Kind {
+	case kindMetrics:
+		offset := r.HistoryOffset
+		if err := e.logMetrics(ctx, r, item.Payload); err != nil {
+			return err
+		}
+		if r.HistoryOffset != offset {
+			return updateHistoryOffset(ctx, r)
+		}
+		return nil
+	case kindFinal:
+		return e.finish(ctx, r, item.Payload)
+	default:
+		return fmt.Errorf("unknown metrics export kind %q", item.Kind)
+	}
+}
+
+func newExporter(cl *http.Client, d *model.MetricsExportDestination) (exporter, error) { //nolint:forbidigo
+	switch d.Type {
+	case model.MetricsExportMLflow:
+		return &mlflowExporter{cl: cl, dest: d}, nil
+	case model.MetricsExportWandB:
+		return &wandbExporter{cl: cl, dest: d}, nil
+	default:
+		return nil, fmt.Errorf("unknown metrics export destination type %q", d.Type)
+	}
+}
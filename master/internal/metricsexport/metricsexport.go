@@ -0,0 +1,191 @@
+// Package metricsexport mirrors trial metrics to external experiment tracking systems, MLflow and
+// Weights & Biases, so teams migrating to or from Determined see their data in both systems.
+//
+// Metrics are queued in the database when they are reported and sent by a single background
+// exporter, which retries failed sends with an exponential backoff. Each trial is exported as one
+// run per destination, and a trial's queued items are always sent in the order they were queued.
+package metricsexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// kindMetrics is a queued item holding one report of training or validation metrics.
+	kindMetrics = "metrics"
+	// kindFinal is a queued item holding the final result of a trial.
+	kindFinal = "final"
+
+	maxAttempts  = 10
+	retryInitial = 10 * time.Second
+	retryMax     = time.Hour
+)
+
+// payload is the content of a queued item.
+type payload struct {
+	Group          string         `json:"group,omitempty"`
+	StepsCompleted int            `json:"steps_completed,omitempty"`
+	Metrics        map[string]any `json:"metrics,omitempty"`
+	State          model.State    `json:"state,omitempty"`
+	SearcherMetric *float64       `json:"searcher_metric,omitempty"`
+	EndTime        time.Time      `json:"end_time"`
+}
+
+// trialInfo describes the trial an external run is created for.
+type trialInfo struct {
+	TrialID        int            `bun:"trial_id"`
+	ExperimentID   int            `bun:"experiment_id"`
+	ExperimentName string         `bun:"experiment_name"`
+	HParams        map[string]any `bun:"hparams"`
+}
+
+// exporter sends a trial's metrics to one external system.
+type exporter interface {
+	// createRun creates the external run a trial's metrics are logged to and returns its ID.
+	createRun(ctx context.Context, t trialInfo) (string, error)
+	// logMetrics logs one report of metrics to a run.
+	logMetrics(ctx context.Context, r *exportRun, p payload) error
+	// finish records the final result of a run and closes it.
+	finish(ctx context.Context, r *exportRun, p payload) error
+}
+
+// ReportMetrics queues a report of a trial's metrics for every export destination of the trial's
+// workspace.
+func ReportMetrics(
+	ctx context.Context, trialID int, group model.MetricGroup, stepsCompleted int,
+	metrics map[string]any,
+) error {
+	return enqueue(ctx, trialID, kindMetrics, payload{
+		Group:          string(group),
+		StepsCompleted: stepsCompleted,
+		Metrics:        metrics,
+		EndTime:        time.Now().UTC(),
+	})
+}
+
+// ReportTrialEnded queues the final result of a trial that reached a terminal state for every
+// export destination of the trial's workspace.
+func ReportTrialEnded(ctx context.Context, trialID int, state model.State) error {
+	searcherMetric, err := searcherMetricOfTrial(ctx, trialID)
+	if err != nil {
+		return err
+	}
+	return enqueue(ctx, trialID, kindFinal, payload{
+		State:          state,
+		SearcherMetric: searcherMetric,
+		EndTime:        time.Now().UTC(),
+	})
+}
+
+func enqueue(ctx context.Context, trialID int, kind string, p payload) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshaling metrics export payload: %w", err)
+	}
+	n, err := enqueueForTrial(ctx, trialID, kind, b)
+	if err != nil {
+		return err
+	}
+	if n > 0 && singletonExporter != nil {
+		singletonExporter.Wake()
+	}
+	return nil
+}
+
+// CheckDestination returns an error if metrics may not be exported to a URL under the metrics
+// export config of the master. The host is resolved, so that names of internal addresses are
+// rejected too; it is checked again before each send, in case what it resolves to has changed.
+func CheckDestination(ctx context.Context, rawURL string) error {
+	c := config.GetMasterConfig().MetricsExport
+	if err := c.CheckURL(rawURL); err != nil {
+		return err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if net.ParseIP(u.Hostname()) != nil {
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", u.Hostname(), err)
+	}
+	for _, a := range addrs {
+		if err := c.CheckAddress(a.IP); err != nil {
+			return fmt.Errorf("%s resolves to %w", u.Hostname(), err)
+		}
+	}
+	return nil
+}
+
+// retryDelay returns how long to wait before retrying an item that has failed attempts times.
+func retryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := float64(retryInitial) * math.Pow(2, float64(attempts-1))
+	if d > float64(retryMax) {
+		return retryMax
+	}
+	return time.Duration(d)
+}
+
+// numericMetrics returns the metrics that external systems can plot, keyed by group and name,
+// dropping values that aren't numbers.
+func numericMetrics(group string, metrics map[string]any) map[string]float64 {
+	out := make(map[string]float64, len(metrics))
+	for name, v := range metrics {
+		var f float64
+		switch v := v.(type) {
+		case float64:
+			f = v
+		case float32:
+			f = float64(v)
+		case int:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		case int32:
+			f = float64(v)
+		case bool:
+			if v {
+				f = 1
+			}
+		default:
+			continue
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		out[group+"/"+name] = f
+	}
+	return out
+}
+
+// flattenHParams flattens nested hyperparameters into dotted keys, since both systems only
+// accept flat run parameters.
+func flattenHParams(hparams map[string]any) map[string]any {
+	out := map[string]any{}
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			if nested, ok := v.(map[string]any); ok {
+				walk(prefix+k+".", nested)
+				continue
+			}
+			out[prefix+k] = v
+		}
+	}
+	walk("", hparams)
+	return out
+}
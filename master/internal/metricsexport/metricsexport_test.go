@@ -0,0 +1,140 @@
+package metricsexport
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, time.Duration(0), retryDelay(0))
+	require.Equal(t, retryInitial, retryDelay(1))
+	require.Equal(t, 4*retryInitial, retryDelay(3))
+	require.Equal(t, retryMax, retryDelay(maxAttempts))
+}
+
+func TestNumericMetrics(t *testing.T) {
+	got := numericMetrics("validation", map[string]any{
+		"loss":     0.5,
+		"accuracy": float32(0.25),
+		"epochs":   3,
+		"done":     true,
+		"name":     "resnet",
+		"nan":      math.NaN(),
+		"matrix":   []any{1.0, 2.0},
+	})
+	require.Equal(t, map[string]float64{
+		"validation/loss":     0.5,
+		"validation/accuracy": 0.25,
+		"validation/epochs":   3,
+		"validation/done":     1,
+	}, got)
+}
+
+func TestMLflowBatches(t *testing.T) {
+	metrics := map[string]any{"ignored": "text"}
+	for i := 0; i < mlflowMaxMetricsPerBatch+1; i++ {
+		metrics[string(rune('a'+i%26))+string(rune('a'+i/26))] = float64(i)
+	}
+	end := time.Unix(100, 0)
+	batches := mlflowMetrics(payload{Group: "training", StepsCompleted: 7, Metrics: metrics, EndTime: end})
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], mlflowMaxMetricsPerBatch)
+	require.Len(t, batches[1], 1)
+	require.Equal(t, 7, batches[0][0].Step)
+	require.Equal(t, int64(100000), batches[0][0].Timestamp)
+
+	params := mlflowParams(map[string]any{
+		"lr":        0.1,
+		"optimizer": map[string]any{"name": "adam", "beta": 0.9},
+	})
+	require.Equal(t, [][]mlflowParam{{
+		{Key: "lr", Value: "0.1"},
+		{Key: "optimizer.beta", Value: "0.9"},
+		{Key: "optimizer.name", Value: "adam"},
+	}}, params)
+}
+
+func TestWandBHistoryLine(t *testing.T) {
+	_, ok, err := wandbHistoryLine(payload{Group: "training", Metrics: map[string]any{"x": "y"}})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	line, ok, err := wandbHistoryLine(payload{
+		Group:          "training",
+		StepsCompleted: 10,
+		Metrics:        map[string]any{"loss": 0.5},
+		EndTime:        time.UnixMilli(1500),
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+	var row map[string]any
+	require.NoError(t, json.Unmarshal([]byte(line), &row))
+	require.Equal(t, map[string]any{"training/loss": 0.5, "_step": 10.0, "_timestamp": 1.5}, row)
+}
+
+func TestMLflowExporter(t *testing.T) {
+	var requests []string
+	var logged []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/experiments/get-by-name":
+			require.Equal(t, "team", r.URL.Query().Get("experiment_name"))
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code": "RESOURCE_DOES_NOT_EXIST"}`))
+		case "/api/2.0/mlflow/experiments/create":
+			_, _ = w.Write([]byte(`{"experiment_id": "5"}`))
+		case "/api/2.0/mlflow/runs/create":
+			_, _ = w.Write([]byte(`{"run": {"info": {"run_id": "abc"}}}`))
+		case "/api/2.0/mlflow/runs/log-batch", "/api/2.0/mlflow/runs/update":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			logged = append(logged, body)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	e := &mlflowExporter{cl: srv.Client(), dest: &model.MetricsExportDestination{
+		Type: model.MetricsExportMLflow, URL: srv.URL + "/", APIKey: "token", Project: "team",
+	}}
+	ctx := context.Background()
+	runID, err := e.createRun(ctx, trialInfo{TrialID: 1, HParams: map[string]any{"lr": 0.1}})
+	require.NoError(t, err)
+	require.Equal(t, "abc", runID)
+
+	r := &exportRun{ExternalRunID: runID}
+	require.NoError(t, e.logMetrics(ctx, r, payload{
+		Group: "validation", StepsCompleted: 2, Metrics: map[string]any{"loss": 0.5},
+	}))
+	require.NoError(t, e.finish(ctx, r, payload{State: model.CanceledState}))
+
+	require.Equal(t, []string{
+		"GET /api/2.0/mlflow/experiments/get-by-name",
+		"POST /api/2.0/mlflow/experiments/create",
+		"POST /api/2.0/mlflow/runs/create",
+		"POST /api/2.0/mlflow/runs/log-batch",
+		"POST /api/2.0/mlflow/runs/log-batch",
+		"POST /api/2.0/mlflow/runs/update",
+	}, requests)
+	require.Equal(t, []any{map[string]any{"key": "lr", "value": "0.1"}}, logged[0]["params"])
+	require.Equal(t, "validation/loss", logged[1]["metrics"].([]any)[0].(map[string]any)["key"])
+	require.Equal(t, "KILLED", logged[2]["status"])
+
+	// Errors other than a missing experiment fail the export so it is retried.
+	e.dest.URL = srv.URL + "/missing"
+	_, err = e.createRun(ctx, trialInfo{TrialID: 1})
+	require.Error(t, err)
+}
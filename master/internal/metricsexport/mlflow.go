@@ -0,0 +1,223 @@
+package metricsexport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/egress"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// MLflow limits how many metrics and params a single log-batch request may hold.
+	mlflowMaxMetricsPerBatch = 1000
+	mlflowMaxParamsPerBatch  = 100
+	mlflowMaxParamLength     = 6000
+
+	mlflowTrialIDTag = "determined.trial_id"
+)
+
+// mlflowExporter exports trials to an MLflow tracking server through its REST API. Each trial
+// is a run in the MLflow experiment named by the destination's project, or after the Determined
+// experiment if no project is set.
+type mlflowExporter struct {
+	cl   *http.Client //nolint:forbidigo
+	dest *model.MetricsExportDestination
+}
+
+type mlflowMetric struct {
+	Key       string  `json:"key"`
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"`
+	Step      int     `json:"step"`
+}
+
+type mlflowParam struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type mlflowTag = mlflowParam
+
+func (e *mlflowExporter) do(ctx context.Context, method, path string, body, out any) error {
+	u := strings.TrimSuffix(e.dest.URL, "/") + "/api/2.0/mlflow/" + path
+	return egress.DoJSON(ctx, e.cl, method, u, func(req *http.Request) {
+		if e.dest.APIKey != "" {
+			req.Header.Set("Authorization", "Bearer "+e.dest.APIKey)
+		}
+	}, body, out)
+}
+
+func (e *mlflowExporter) experimentID(ctx context.Context, name string) (string, error) {
+	var got struct {
+		Experiment struct {
+			ExperimentID string `json:"experiment_id"`
+		} `json:"experiment"`
+	}
+	err := e.do(ctx, http.MethodGet,
+		"experiments/get-by-name?experiment_name="+url.QueryEscape(name), nil, &got)
+	var se *egress.StatusError
+	switch {
+	case err == nil:
+		return got.Experiment.ExperimentID, nil
+	case !errors.As(err, &se) || se.StatusCode != http.StatusNotFound:
+		return "", fmt.Errorf("getting MLflow experiment %q: %w", name, err)
+	}
+
+	var created struct {
+		ExperimentID string `json:"experiment_id"`
+	}
+	if err := e.do(ctx, http.MethodPost, "experiments/create",
+		map[string]any{"name": name}, &created); err != nil {
+		return "", fmt.Errorf("creating MLflow experiment %q: %w", name, err)
+	}
+	return created.ExperimentID, nil
+}
+
+func (e *mlflowExporter) createRun(ctx context.Context, t trialInfo) (string, error) {
+	name := e.dest.Project
+	if name == "" {
+		name = t.ExperimentName
+	}
+	experimentID, err := e.experimentID(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	var created struct {
+		Run struct {
+			Info struct {
+				RunID string `json:"run_id"`
+			} `json:"info"`
+		} `json:"run"`
+	}
+	if err := e.do(ctx, http.MethodPost, "runs/create", map[string]any{
+		"experiment_id": experimentID,
+		"run_name":      fmt.Sprintf("trial-%d", t.TrialID),
+		"start_time":    time.Now().UnixMilli(),
+		"tags": []mlflowTag{
+			{Key: mlflowTrialIDTag, Value: strconv.Itoa(t.TrialID)},
+			{Key: "determined.experiment_id", Value: strconv.Itoa(t.ExperimentID)},
+		},
+	}, &created); err != nil {
+		return "", fmt.Errorf("creating MLflow run: %w", err)
+	}
+	runID := created.Run.Info.RunID
+
+	for _, params := range mlflowParams(t.HParams) {
+		if err := e.logBatch(ctx, runID, nil, params); err != nil {
+			return "", err
+		}
+	}
+	return runID, nil
+}
+
+func (e *mlflowExporter) logBatch(
+	ctx context.Context, runID string, metrics []mlflowMetric, params []mlflowParam,
+) error {
+	body := map[string]any{"run_id": runID}
+	if metrics != nil {
+		body["metrics"] = metrics
+	}
+	if params != nil {
+		body["params"] = params
+	}
+	if err := e.do(ctx, http.MethodPost, "runs/log-batch", body, nil); err != nil {
+		return fmt.Errorf("logging to MLflow run %s: %w", runID, err)
+	}
+	return nil
+}
+
+func (e *mlflowExporter) logMetrics(ctx context.Context, r *exportRun, p payload) error {
+	for _, metrics := range mlflowMetrics(p) {
+		if err := e.logBatch(ctx, r.ExternalRunID, metrics, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *mlflowExporter) finish(ctx context.Context, r *exportRun, p payload) error {
+	if p.SearcherMetric != nil {
+		if err := e.logBatch(ctx, r.ExternalRunID, []mlflowMetric{{
+			Key:       "searcher_metric",
+			Value:     *p.SearcherMetric,
+			Timestamp: p.EndTime.UnixMilli(),
+		}}, nil); err != nil {
+			return err
+		}
+	}
+	if err := e.do(ctx, http.MethodPost, "runs/update", map[string]any{
+		"run_id":   r.ExternalRunID,
+		"status":   mlflowStatus(p.State),
+		"end_time": p.EndTime.UnixMilli(),
+	}, nil); err != nil {
+		return fmt.Errorf("finishing MLflow run %s: %w", r.ExternalRunID, err)
+	}
+	return nil
+}
+
+// mlflowMetrics splits a report of metrics into log-batch sized chunks.
+func mlflowMetrics(p payload) [][]mlflowMetric {
+	values := numericMetrics(p.Group, p.Metrics)
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var batches [][]mlflowMetric
+	for i, k := range keys {
+		if i%mlflowMaxMetricsPerBatch == 0 {
+			batches = append(batches, nil)
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], mlflowMetric{
+			Key:       k,
+			Value:     values[k],
+			Timestamp: p.EndTime.UnixMilli(),
+			Step:      p.StepsCompleted,
+		})
+	}
+	return batches
+}
+
+// mlflowParams converts hyperparameters to log-batch sized chunks of params.
+func mlflowParams(hparams map[string]any) [][]mlflowParam {
+	flat := flattenHParams(hparams)
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var batches [][]mlflowParam
+	for i, k := range keys {
+		if i%mlflowMaxParamsPerBatch == 0 {
+			batches = append(batches, nil)
+		}
+		v := fmt.Sprint(flat[k])
+		if len(v) > mlflowMaxParamLength {
+			v = v[:mlflowMaxParamLength]
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], mlflowParam{Key: k, Value: v})
+	}
+	return batches
+}
+
+func mlflowStatus(s model.State) string {
+	switch s {
+	case model.CompletedState:
+		return "FINISHED"
+	case model.CanceledState:
+		return "KILLED"
+	default:
+		return "FAILED"
+	}
+}
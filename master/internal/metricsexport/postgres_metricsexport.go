@@ -0,0 +1,244 @@
+package metricsexport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+// DestinationStatus is an export destination along with the state of its queue.
+type DestinationStatus struct {
+	model.MetricsExportDestination
+	Pending   int     `bun:"pending" json:"pending"`
+	LastError *string `bun:"last_error" json:"last_error"`
+}
+
+// Proto converts a destination and the state of its queue to its protobuf representation.
+func (d DestinationStatus) Proto() *workspacev1.MetricsExportDestination {
+	pb := d.MetricsExportDestination.Proto()
+	pb.Pending = int32(d.Pending)
+	pb.LastError = d.LastError
+	return pb
+}
+
+// queueItem is a row of metrics_export_queue.
+type queueItem struct {
+	bun.BaseModel `bun:"table:metrics_export_queue"`
+	ID            int64      `bun:"id,pk,autoincrement"`
+	DestinationID int        `bun:"destination_id"`
+	TrialID       int        `bun:"trial_id"`
+	Kind          string     `bun:"kind"`
+	Payload       payload    `bun:"payload,type:jsonb"`
+	Attempts      int        `bun:"attempts"`
+	NextAttemptAt time.Time  `bun:"next_attempt_at"`
+	LastError     *string    `bun:"last_error"`
+	LeasedUntil   *time.Time `bun:"leased_until"`
+}
+
+// exportRun is a row of metrics_export_runs, the external run a trial is exported to.
+type exportRun struct {
+	bun.BaseModel `bun:"table:metrics_export_runs"`
+	DestinationID int    `bun:"destination_id,pk"`
+	TrialID       int    `bun:"trial_id,pk"`
+	ExternalRunID string `bun:"external_run_id"`
+	HistoryOffset int    `bun:"history_offset"`
+}
+
+// Destinations returns the export destinations of a workspace.
+func Destinations(ctx context.Context, workspaceID int) ([]DestinationStatus, error) {
+	var ds []DestinationStatus
+	if err := db.Bun().NewSelect().
+		ColumnExpr("d.*").
+		ColumnExpr("(SELECT COUNT(*) FROM metrics_export_queue q WHERE q.destination_id = d.id) AS pending").
+		ColumnExpr(`(SELECT q.last_error FROM metrics_export_queue q
+			WHERE q.destination_id = d.id AND q.last_error IS NOT NULL
+			ORDER BY q.id DESC LIMIT 1) AS last_error`).
+		TableExpr("metrics_export_destinations AS d").
+		Where("d.workspace_id = ?", workspaceID).
+		Order("d.id").
+		Scan(ctx, &ds); err != nil {
+		return nil, fmt.Errorf("getting metrics export destinations of workspace %d: %w", workspaceID, err)
+	}
+	return ds, nil
+}
+
+// AddDestination adds an export destination to a workspace. Only metrics reported after it is
+// added are exported.
+func AddDestination(ctx context.Context, d *model.MetricsExportDestination) error {
+	if _, err := db.Bun().NewInsert().Model(d).Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("adding metrics export destination to workspace %d: %w", d.WorkspaceID, err)
+	}
+	return nil
+}
+
+// DeleteDestination removes an export destination from a workspace, dropping anything still
+// queued for it. It returns db.ErrNotFound if the workspace has no such destination.
+func DeleteDestination(ctx context.Context, workspaceID, destinationID int) error {
+	res, err := db.Bun().NewDelete().Model((*model.MetricsExportDestination)(nil)).
+		Where("id = ?", destinationID).
+		Where("workspace_id = ?", workspaceID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting metrics export destination %d: %w", destinationID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// enqueueForTrial queues a payload for every export destination of a trial's workspace and
+// returns the number of items queued.
+func enqueueForTrial(ctx context.Context, trialID int, kind string, p []byte) (int64, error) {
+	res, err := db.Bun().NewRaw(`
+INSERT INTO metrics_export_queue (destination_id, trial_id, kind, payload)
+SELECT d.id, r.id, ?, ?
+FROM runs r
+JOIN projects p ON p.id = r.project_id
+JOIN metrics_export_destinations d ON d.workspace_id = p.workspace_id
+WHERE r.id = ?
+`, kind, string(p), trialID).Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("queueing metrics export for trial %d: %w", trialID, err)
+	}
+	return res.RowsAffected()
+}
+
+func searcherMetricOfTrial(ctx context.Context, trialID int) (*float64, error) {
+	var v *float64
+	if err := db.Bun().NewSelect().Table("runs").
+		Column("searcher_metric_value").
+		Where("id = ?", trialID).
+		Scan(ctx, &v); err != nil {
+		return nil, fmt.Errorf("getting searcher metric of trial %d: %w", trialID, err)
+	}
+	return v, nil
+}
+
+func trialInfoOf(ctx context.Context, trialID int) (trialInfo, error) {
+	var t trialInfo
+	err := db.Bun().NewSelect().
+		TableExpr("runs AS r").
+		ColumnExpr("r.id AS trial_id").
+		ColumnExpr("r.experiment_id").
+		ColumnExpr("e.config->>'name' AS experiment_name").
+		ColumnExpr("r.hparams").
+		Join("JOIN experiments AS e ON e.id = r.experiment_id").
+		Where("r.id = ?", trialID).
+		Scan(ctx, &t)
+	if errors.Is(err, sql.ErrNoRows) {
+		return t, db.ErrNotFound
+	} else if err != nil {
+		return t, fmt.Errorf("getting trial %d for metrics export: %w", trialID, err)
+	}
+	return t, nil
+}
+
+// leaseItems leases a batch of due items to the caller for the lease duration, so that they aren't
+// taken again while they are sent. Only the oldest item of each trial and destination is taken, so
+// a trial's items are sent in order even when one has to be retried.
+func leaseItems(ctx context.Context, limit int, lease time.Duration) ([]queueItem, error) {
+	var items []queueItem
+	if err := db.Bun().NewRaw(`
+WITH due AS (
+  SELECT q.id FROM metrics_export_queue q
+  WHERE q.next_attempt_at <= NOW()
+  AND (q.leased_until IS NULL OR q.leased_until <= NOW())
+  AND NOT EXISTS (
+    SELECT 1 FROM metrics_export_queue o
+    WHERE o.destination_id = q.destination_id AND o.trial_id = q.trial_id AND o.id < q.id
+  )
+  ORDER BY q.id
+  LIMIT ?
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE metrics_export_queue q SET leased_until = NOW() + ? * INTERVAL '1 second'
+FROM due WHERE q.id = due.id
+RETURNING q.*
+`, limit, lease.Seconds()).Scan(ctx, &items); err != nil {
+		return nil, fmt.Errorf("leasing queued metrics exports: %w", err)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+// releaseLeases releases every leased item, for when no items can still be in flight.
+func releaseLeases(ctx context.Context) error {
+	if _, err := db.Bun().NewUpdate().Table("metrics_export_queue").
+		Set("leased_until = NULL").
+		Where("leased_until IS NOT NULL").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("releasing metrics export leases: %w", err)
+	}
+	return nil
+}
+
+// itemDone removes an item that was sent, or that failed too many times to retry.
+func itemDone(ctx context.Context, item queueItem) error {
+	_, err := db.Bun().NewDelete().Model(&item).WherePK().Exec(ctx)
+	return err
+}
+
+// retryItem releases an item that failed and schedules another attempt at it.
+func retryItem(ctx context.Context, item queueItem, sendErr error) error {
+	_, err := db.Bun().NewUpdate().Model(&item).
+		Set("attempts = ?", item.Attempts+1).
+		Set("next_attempt_at = ?", time.Now().Add(retryDelay(item.Attempts+1))).
+		Set("last_error = ?", sendErr.Error()).
+		Set("leased_until = NULL").
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+func destinationByID(ctx context.Context, id int) (*model.MetricsExportDestination, error) {
+	var d model.MetricsExportDestination
+	if err := db.Bun().NewSelect().Model(&d).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting metrics export destination %d: %w", id, err)
+	}
+	return &d, nil
+}
+
+// getOrCreateRun returns the external run a trial is exported to, creating it on first use.
+func getOrCreateRun(
+	ctx context.Context, e exporter, destinationID, trialID int,
+) (*exportRun, error) {
+	r := exportRun{DestinationID: destinationID, TrialID: trialID}
+	err := db.Bun().NewSelect().Model(&r).WherePK().Scan(ctx)
+	switch {
+	case err == nil:
+		return &r, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("getting exported run of trial %d: %w", trialID, err)
+	}
+
+	t, err := trialInfoOf(ctx, trialID)
+	if err != nil {
+		return nil, err
+	}
+	if r.ExternalRunID, err = e.createRun(ctx, t); err != nil {
+		return nil, fmt.Errorf("creating run for trial %d: %w", trialID, err)
+	}
+	if _, err := db.Bun().NewInsert().Model(&r).Exec(ctx); err != nil {
+		return nil, fmt.Errorf("recording exported run of trial %d: %w", trialID, err)
+	}
+	return &r, nil
+}
+
+func updateHistoryOffset(ctx context.Context, r *exportRun) error {
+	if _, err := db.Bun().NewUpdate().Model(r).Column("history_offset").WherePK().Exec(ctx); err != nil {
+		return fmt.Errorf("updating exported run of trial %d: %w", r.TrialID, err)
+	}
+	return nil
+}
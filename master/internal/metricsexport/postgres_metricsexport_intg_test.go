@@ -0,0 +1,102 @@
+//go:build integration
+// +build integration
+
+package metricsexport
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+
+	user := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	exp := db.RequireMockExperimentProject(t, db.SingleDB(), user, projectID)
+	tr, _ := db.RequireMockTrial(t, db.SingleDB(), exp)
+
+	// Nothing is queued for workspaces without destinations.
+	require.NoError(t, ReportMetrics(ctx, tr.ID, model.TrainingMetricGroup, 1, map[string]any{"loss": 1.0}))
+
+	d := model.MetricsExportDestination{
+		WorkspaceID: workspaceID,
+		Type:        model.MetricsExportMLflow,
+		Name:        "mlflow",
+		URL:         "http://localhost:5000",
+	}
+	require.NoError(t, AddDestination(ctx, &d))
+
+	require.NoError(t, ReportMetrics(ctx, tr.ID, model.TrainingMetricGroup, 2, map[string]any{"loss": 0.5}))
+	require.NoError(t, ReportTrialEnded(ctx, tr.ID, model.CompletedState))
+
+	ds, err := Destinations(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.Equal(t, 2, ds[0].Pending)
+	require.Nil(t, ds[0].LastError)
+
+	// Only the oldest item of the trial is taken, so items are sent in order.
+	items, err := leaseItems(ctx, maxBatchSize, leaseDuration)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, kindMetrics, items[0].Kind)
+	require.Equal(t, 2, items[0].Payload.StepsCompleted)
+
+	// Leased items aren't taken again, and hold back the items queued after them.
+	leased, err := leaseItems(ctx, maxBatchSize, leaseDuration)
+	require.NoError(t, err)
+	require.Empty(t, leased)
+
+	// Released items are taken again, e.g. after the master restarts.
+	require.NoError(t, releaseLeases(ctx))
+	items, err = leaseItems(ctx, maxBatchSize, leaseDuration)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.NoError(t, retryItem(ctx, items[0], errors.New("unavailable")))
+
+	// The failed item isn't due yet and holds back the items queued after it.
+	items, err = leaseItems(ctx, maxBatchSize, leaseDuration)
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	ds, err = Destinations(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Equal(t, 2, ds[0].Pending)
+	require.Equal(t, "unavailable", *ds[0].LastError)
+
+	require.ErrorIs(t, DeleteDestination(ctx, workspaceID+1, d.ID), db.ErrNotFound)
+	require.NoError(t, DeleteDestination(ctx, workspaceID, d.ID))
+	ds, err = Destinations(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Empty(t, ds)
+}
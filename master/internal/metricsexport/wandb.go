@@ -0,0 +1,144 @@
+package metricsexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/determined-ai/determined/master/internal/egress"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const wandbUpsertRunMutation = `mutation UpsertBucket(
+  $name: String, $project: String, $entity: String, $displayName: String, $config: JSONString
+) {
+  upsertBucket(input: {
+    name: $name, modelName: $project, entityName: $entity, displayName: $displayName, config: $config
+  }) {
+    bucket { name }
+  }
+}`
+
+// wandbExporter exports trials to Weights & Biases. Each trial is a run in the destination's
+// entity and project, created through the GraphQL API; metrics are appended to the run's history
+// through the file stream API, which is what the wandb client library itself uses.
+type wandbExporter struct {
+	cl   *http.Client //nolint:forbidigo
+	dest *model.MetricsExportDestination
+}
+
+func (e *wandbExporter) auth(req *http.Request) {
+	req.SetBasicAuth("api", e.dest.APIKey)
+}
+
+func (e *wandbExporter) baseURL() string {
+	return strings.TrimSuffix(e.dest.URL, "/")
+}
+
+func (e *wandbExporter) createRun(ctx context.Context, t trialInfo) (string, error) {
+	config := map[string]any{}
+	for k, v := range flattenHParams(t.HParams) {
+		config[k] = map[string]any{"value": v}
+	}
+	config["determined_trial_id"] = map[string]any{"value": t.TrialID}
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("marshaling W&B run config: %w", err)
+	}
+
+	name := wandbRunName(t.TrialID)
+	var got struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := egress.DoJSON(ctx, e.cl, http.MethodPost, e.baseURL()+"/graphql", e.auth, map[string]any{
+		"query": wandbUpsertRunMutation,
+		"variables": map[string]any{
+			"name":        name,
+			"project":     e.dest.Project,
+			"entity":      e.dest.Entity,
+			"displayName": fmt.Sprintf("%s (trial %d)", t.ExperimentName, t.TrialID),
+			"config":      string(configJSON),
+		},
+	}, &got); err != nil {
+		return "", fmt.Errorf("creating W&B run: %w", err)
+	}
+	if len(got.Errors) > 0 {
+		return "", fmt.Errorf("creating W&B run: %s", got.Errors[0].Message)
+	}
+	return name, nil
+}
+
+func (e *wandbExporter) stream(ctx context.Context, r *exportRun, body map[string]any) error {
+	u := fmt.Sprintf("%s/files/%s/%s/%s/file_stream", e.baseURL(),
+		url.PathEscape(e.dest.Entity), url.PathEscape(e.dest.Project), url.PathEscape(r.ExternalRunID))
+	if err := egress.DoJSON(ctx, e.cl, http.MethodPost, u, e.auth, body, nil); err != nil {
+		return fmt.Errorf("streaming to W&B run %s: %w", r.ExternalRunID, err)
+	}
+	return nil
+}
+
+func (e *wandbExporter) logMetrics(ctx context.Context, r *exportRun, p payload) error {
+	line, ok, err := wandbHistoryLine(p)
+	if err != nil || !ok {
+		return err
+	}
+	if err := e.stream(ctx, r, map[string]any{
+		"files": map[string]any{
+			"wandb-history.jsonl": map[string]any{"offset": r.HistoryOffset, "content": []string{line}},
+		},
+	}); err != nil {
+		return err
+	}
+	r.HistoryOffset++
+	return nil
+}
+
+func (e *wandbExporter) finish(ctx context.Context, r *exportRun, p payload) error {
+	body := map[string]any{"complete": true, "exitcode": wandbExitCode(p.State)}
+	if p.SearcherMetric != nil {
+		summary, err := json.Marshal(map[string]any{"searcher_metric": *p.SearcherMetric})
+		if err != nil {
+			return fmt.Errorf("marshaling W&B summary: %w", err)
+		}
+		body["files"] = map[string]any{
+			"wandb-summary.json": map[string]any{"offset": 0, "content": []string{string(summary)}},
+		}
+	}
+	return e.stream(ctx, r, body)
+}
+
+func wandbRunName(trialID int) string {
+	return fmt.Sprintf("determined-trial-%d", trialID)
+}
+
+// wandbHistoryLine returns a line of a run's history file for a report of metrics, or false if
+// the report has nothing W&B can plot.
+func wandbHistoryLine(p payload) (string, bool, error) {
+	values := numericMetrics(p.Group, p.Metrics)
+	if len(values) == 0 {
+		return "", false, nil
+	}
+	row := make(map[string]any, len(values)+2)
+	for k, v := range values {
+		row[k] = v
+	}
+	row["_step"] = p.StepsCompleted
+	row["_timestamp"] = float64(p.EndTime.UnixMilli()) / 1000
+	b, err := json.Marshal(row)
+	if err != nil {
+		return "", false, fmt.Errorf("marshaling W&B history: %w", err)
+	}
+	return string(b), true, nil
+}
+
+func wandbExitCode(s model.State) int {
+	if s == model.CompletedState {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,158 @@
+// Package mlflowimport reads runs from an MLflow tracking server so they can be imported as
+// archived Determined experiments, letting historical results be compared with new ones.
+package mlflowimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/determined-ai/determined/master/internal/egress"
+)
+
+// ErrNotFound is returned when the MLflow experiment to import doesn't exist.
+var ErrNotFound = errors.New("MLflow experiment not found")
+
+// MLflow caps the page size of runs/search and metrics/get-history.
+const (
+	maxRunsPerPage    = 1000
+	maxMetricsPerPage = 25000
+)
+
+// Client reads from the REST API of an MLflow tracking server.
+type Client struct {
+	cl    *http.Client //nolint:forbidigo
+	url   string
+	token string
+}
+
+// NewClient returns a client for the tracking server at baseURL. A non-empty token is sent as a
+// bearer token.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		cl:    egress.Client(),
+		url:   strings.TrimSuffix(baseURL, "/") + "/api/2.0/mlflow/",
+		token: token,
+	}
+}
+
+// Experiment is an MLflow experiment.
+type Experiment struct {
+	ExperimentID string `json:"experiment_id"`
+	Name         string `json:"name"`
+}
+
+// Run is an MLflow run, with the latest value of each of its metrics.
+type Run struct {
+	Info struct {
+		RunID     string `json:"run_id"`
+		RunName   string `json:"run_name"`
+		Status    string `json:"status"`
+		StartTime int64  `json:"start_time"`
+		EndTime   int64  `json:"end_time"`
+	} `json:"info"`
+	Data struct {
+		Metrics []Metric `json:"metrics"`
+		Params  []Param  `json:"params"`
+	} `json:"data"`
+}
+
+// Metric is one logged value of an MLflow metric.
+type Metric struct {
+	Key       string  `json:"key"`
+	Value     float64 `json:"value"`
+	Timestamp int64   `json:"timestamp"`
+	Step      int64   `json:"step"`
+}
+
+// Param is an MLflow run parameter.
+type Param struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Experiment returns an experiment by its ID, if ref is numeric, or else by its name.
+func (c *Client) Experiment(ctx context.Context, ref string) (*Experiment, error) {
+	path := "experiments/get-by-name?experiment_name=" + url.QueryEscape(ref)
+	if _, err := strconv.Atoi(ref); err == nil {
+		path = "experiments/get?experiment_id=" + url.QueryEscape(ref)
+	}
+
+	var got struct {
+		Experiment Experiment `json:"experiment"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &got); err != nil {
+		var se *egress.StatusError
+		if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+		}
+		return nil, fmt.Errorf("getting MLflow experiment %s: %w", ref, err)
+	}
+	return &got.Experiment, nil
+}
+
+// Runs returns up to limit runs of an experiment, newest first.
+func (c *Client) Runs(ctx context.Context, experimentID string, limit int) ([]Run, error) {
+	var runs []Run
+	pageToken := ""
+	for len(runs) < limit {
+		body := map[string]any{
+			"experiment_ids": []string{experimentID},
+			"max_results":    min(limit-len(runs), maxRunsPerPage),
+			"order_by":       []string{"attributes.start_time DESC"},
+		}
+		if pageToken != "" {
+			body["page_token"] = pageToken
+		}
+		var got struct {
+			Runs          []Run  `json:"runs"`
+			NextPageToken string `json:"next_page_token"`
+		}
+		if err := c.do(ctx, http.MethodPost, "runs/search", body, &got); err != nil {
+			return nil, fmt.Errorf("searching runs of MLflow experiment %s: %w", experimentID, err)
+		}
+		runs = append(runs, got.Runs...)
+		if pageToken = got.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+	return runs, nil
+}
+
+// MetricHistory returns every logged value of a run's metric.
+func (c *Client) MetricHistory(ctx context.Context, runID, key string) ([]Metric, error) {
+	var history []Metric
+	pageToken := ""
+	for {
+		q := url.Values{}
+		q.Set("run_id", runID)
+		q.Set("metric_key", key)
+		q.Set("max_results", strconv.Itoa(maxMetricsPerPage))
+		if pageToken != "" {
+			q.Set("page_token", pageToken)
+		}
+		var got struct {
+			Metrics       []Metric `json:"metrics"`
+			NextPageToken string   `json:"next_page_token"`
+		}
+		if err := c.do(ctx, http.MethodGet, "metrics/get-history?"+q.Encode(), nil, &got); err != nil {
+			return nil, fmt.Errorf("getting history of metric %s of MLflow run %s: %w", key, runID, err)
+		}
+		history = append(history, got.Metrics...)
+		if pageToken = got.NextPageToken; pageToken == "" {
+			return history, nil
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	return egress.DoJSON(ctx, c.cl, method, c.url+path, func(req *http.Request) {
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+	}, body, out)
+}
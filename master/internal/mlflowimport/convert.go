@@ -0,0 +1,158 @@
+package mlflowimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// defaultSearcherMetric is used when no run has any metrics to pick a searcher metric from.
+const defaultSearcherMetric = "loss"
+
+// validationPrefixes mark MLflow metrics that are imported as validation metrics. MLflow doesn't
+// distinguish training from validation metrics, so this follows common naming conventions.
+var validationPrefixes = []string{"val_", "val/", "val.", "validation", "eval", "test_", "test/"}
+
+// Report is the metrics of a run at one step, in the form Determined stores them.
+type Report struct {
+	Group   model.MetricGroup
+	Step    int
+	Metrics map[string]any
+}
+
+// ExperimentConfig returns the config of the unmanaged experiment that runs are imported into.
+func ExperimentConfig(name, description, searcherMetric string) (string, error) {
+	b, err := json.Marshal(map[string]any{
+		"name":        name,
+		"description": description,
+		"labels":      []string{"mlflow-import"},
+		"searcher": map[string]any{
+			"name":   "single",
+			"metric": searcherMetric,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshaling experiment config: %w", err)
+	}
+	// JSON is valid YAML, which is what experiment configs are parsed as.
+	return string(b), nil
+}
+
+// MetricGroup returns the group an MLflow metric is imported into.
+func MetricGroup(key string) model.MetricGroup {
+	lower := strings.ToLower(key)
+	for _, p := range validationPrefixes {
+		if strings.HasPrefix(lower, p) {
+			return model.ValidationMetricGroup
+		}
+	}
+	return model.TrainingMetricGroup
+}
+
+// SearcherMetric picks the metric to use as the searcher metric of an imported experiment: the
+// first validation metric any run has, else the first metric of any kind.
+func SearcherMetric(runs []Run) string {
+	var validation, training []string
+	for _, r := range runs {
+		for _, m := range r.Data.Metrics {
+			if MetricGroup(m.Key) == model.ValidationMetricGroup {
+				validation = append(validation, m.Key)
+			} else {
+				training = append(training, m.Key)
+			}
+		}
+	}
+	for _, keys := range [][]string{validation, training} {
+		if len(keys) > 0 {
+			sort.Strings(keys)
+			return keys[0]
+		}
+	}
+	return defaultSearcherMetric
+}
+
+// TrialState returns the state of the trial an MLflow run is imported as.
+func TrialState(status string) model.State {
+	switch status {
+	case "FINISHED":
+		return model.CompletedState
+	case "FAILED":
+		return model.ErrorState
+	default:
+		// Killed runs, and runs still running that won't be updated once imported.
+		return model.CanceledState
+	}
+}
+
+// Times returns the start and end times of a run. Runs without an end time end when they start.
+func Times(r Run) (time.Time, time.Time) {
+	start := time.UnixMilli(r.Info.StartTime).UTC()
+	if r.Info.EndTime < r.Info.StartTime {
+		return start, start
+	}
+	return start, time.UnixMilli(r.Info.EndTime).UTC()
+}
+
+// HParams converts the parameters of a run to hyperparameters. MLflow stores every parameter as a
+// string, so values that look like finite numbers or booleans are converted back to them.
+func HParams(params []Param) map[string]any {
+	hparams := make(map[string]any, len(params))
+	for _, p := range params {
+		if i, err := strconv.ParseInt(p.Value, 10, 64); err == nil {
+			hparams[p.Key] = i
+		} else if f, err := strconv.ParseFloat(p.Value, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			hparams[p.Key] = f
+		} else if b, err := strconv.ParseBool(p.Value); err == nil {
+			hparams[p.Key] = b
+		} else {
+			hparams[p.Key] = p.Value
+		}
+	}
+	return hparams
+}
+
+// Reports groups the history of a run's metrics into reports by metric group and step, in the
+// order they must be added: Determined treats a report at an earlier step than the last one as a
+// rollback. A metric logged more than once at a step keeps its latest value.
+func Reports(history []Metric) []Report {
+	type key struct {
+		group model.MetricGroup
+		step  int
+	}
+	type value struct {
+		v  float64
+		ts int64
+	}
+	byKey := map[key]map[string]value{}
+	for _, m := range history {
+		k := key{group: MetricGroup(m.Key), step: int(m.Step)}
+		if byKey[k] == nil {
+			byKey[k] = map[string]value{}
+		}
+		if old, ok := byKey[k][m.Key]; !ok || m.Timestamp >= old.ts {
+			byKey[k][m.Key] = value{v: m.Value, ts: m.Timestamp}
+		}
+	}
+
+	reports := make([]Report, 0, len(byKey))
+	for k, values := range byKey {
+		metrics := make(map[string]any, len(values))
+		for name, v := range values {
+			metrics[name] = v.v
+		}
+		reports = append(reports, Report{Group: k.group, Step: k.step, Metrics: metrics})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Step != reports[j].Step {
+			return reports[i].Step < reports[j].Step
+		}
+		return reports[i].Group < reports[j].Group
+	})
+	return reports
+}
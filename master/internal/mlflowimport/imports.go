@@ -0,0 +1,137 @@
+package mlflowimport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// ImportState is the state of an import.
+type ImportState string
+
+const (
+	// ImportRunning is the state of an import that is reading from MLflow or writing the
+	// experiment.
+	ImportRunning ImportState = "RUNNING"
+	// ImportCompleted is the state of an import whose experiment has every run.
+	ImportCompleted ImportState = "COMPLETED"
+	// ImportFailed is the state of an import that failed. Its experiment was deleted.
+	ImportFailed ImportState = "FAILED"
+)
+
+// Proto converts an import state to its protobuf representation.
+func (s ImportState) Proto() experimentv1.MLflowImportState {
+	switch s {
+	case ImportRunning:
+		return experimentv1.MLflowImportState_MLFLOW_IMPORT_STATE_RUNNING
+	case ImportCompleted:
+		return experimentv1.MLflowImportState_MLFLOW_IMPORT_STATE_COMPLETED
+	case ImportFailed:
+		return experimentv1.MLflowImportState_MLFLOW_IMPORT_STATE_FAILED
+	default:
+		return experimentv1.MLflowImportState_MLFLOW_IMPORT_STATE_UNSPECIFIED
+	}
+}
+
+// Import is a request to import an MLflow experiment, which runs in the background.
+type Import struct {
+	bun.BaseModel `bun:"table:mlflow_imports"`
+
+	ID               int         `bun:"id,pk,autoincrement" json:"id"`
+	State            ImportState `bun:"state" json:"state"`
+	ServerURL        string      `bun:"server_url" json:"server_url"`
+	MLflowExperiment string      `bun:"mlflow_experiment" json:"mlflow_experiment"`
+	ExperimentID     *int        `bun:"experiment_id" json:"experiment_id"`
+	Trials           int         `bun:"trials" json:"trials"`
+	Error            *string     `bun:"error" json:"error"`
+	CreatedBy        *int        `bun:"created_by" json:"created_by"`
+	CreatedAt        time.Time   `bun:"created_at,nullzero,notnull,default:current_timestamp" json:"created_at"`
+	EndTime          *time.Time  `bun:"end_time" json:"end_time"`
+}
+
+// Proto converts an import to its protobuf representation.
+func (i Import) Proto() *experimentv1.MLflowImport {
+	pb := &experimentv1.MLflowImport{
+		Id:               int32(i.ID),
+		State:            i.State.Proto(),
+		ServerUrl:        i.ServerURL,
+		MlflowExperiment: i.MLflowExperiment,
+		Trials:           int32(i.Trials),
+		Error:            i.Error,
+		CreatedAt:        timestamppb.New(i.CreatedAt),
+	}
+	if i.ExperimentID != nil {
+		pb.ExperimentId = ptrs.Ptr(int32(*i.ExperimentID))
+	}
+	if i.CreatedBy != nil {
+		pb.CreatedBy = ptrs.Ptr(int32(*i.CreatedBy))
+	}
+	if i.EndTime != nil {
+		pb.EndTime = timestamppb.New(*i.EndTime)
+	}
+	return pb
+}
+
+// CreateImport records an import that is starting.
+func CreateImport(ctx context.Context, i *Import) error {
+	i.State = ImportRunning
+	if _, err := db.Bun().NewInsert().Model(i).Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("creating MLflow import: %w", err)
+	}
+	return nil
+}
+
+// GetImport returns an import, or db.ErrNotFound if it doesn't exist.
+func GetImport(ctx context.Context, id int) (*Import, error) {
+	var i Import
+	if err := db.Bun().NewSelect().Model(&i).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, db.MatchSentinelError(err)
+	}
+	return &i, nil
+}
+
+// SetImportExperiment records the experiment an import writes to.
+func SetImportExperiment(ctx context.Context, id, experimentID int) error {
+	if _, err := db.Bun().NewUpdate().Model((*Import)(nil)).
+		Set("experiment_id = ?", experimentID).
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("setting experiment of MLflow import %d: %w", id, err)
+	}
+	return nil
+}
+
+// FinishImport records the end of an import. A failed import records the error instead of the
+// trials imported.
+func FinishImport(ctx context.Context, id, trials int, importErr error) error {
+	q := db.Bun().NewUpdate().Model((*Import)(nil)).
+		Set("end_time = ?", time.Now().UTC()).
+		Where("id = ?", id)
+	if importErr != nil {
+		q = q.Set("state = ?", ImportFailed).Set("error = ?", importErr.Error())
+	} else {
+		q = q.Set("state = ?", ImportCompleted).Set("trials = ?", trials)
+	}
+	if _, err := q.Exec(ctx); err != nil {
+		return fmt.Errorf("finishing MLflow import %d: %w", id, err)
+	}
+	return nil
+}
+
+// InterruptedImports returns the imports that were running when the master stopped.
+func InterruptedImports(ctx context.Context) ([]Import, error) {
+	var imports []Import
+	if err := db.Bun().NewSelect().Model(&imports).
+		Where("state = ?", ImportRunning).
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting interrupted MLflow imports: %w", err)
+	}
+	return imports, nil
+}
@@ -0,0 +1,143 @@
+package mlflowimport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+func TestReports(t *testing.T) {
+	reports := Reports([]Metric{
+		{Key: "loss", Value: 3, Step: 10, Timestamp: 1},
+		{Key: "val_loss", Value: 2, Step: 10, Timestamp: 1},
+		{Key: "loss", Value: 5, Step: 0, Timestamp: 1},
+		{Key: "loss", Value: 4, Step: 0, Timestamp: 2},
+		{Key: "accuracy", Value: 0.5, Step: 10, Timestamp: 1},
+	})
+	require.Equal(t, []Report{
+		{Group: model.TrainingMetricGroup, Step: 0, Metrics: map[string]any{"loss": 4.0}},
+		{Group: model.TrainingMetricGroup, Step: 10, Metrics: map[string]any{"loss": 3.0, "accuracy": 0.5}},
+		{Group: model.ValidationMetricGroup, Step: 10, Metrics: map[string]any{"val_loss": 2.0}},
+	}, reports)
+}
+
+func TestHParams(t *testing.T) {
+	require.Equal(t, map[string]any{
+		"batch_size": int64(32),
+		"lr":         0.001,
+		"shuffle":    true,
+		"optimizer":  "adam",
+		"weird":      "NaN",
+	}, HParams([]Param{
+		{Key: "batch_size", Value: "32"},
+		{Key: "lr", Value: "1e-3"},
+		{Key: "shuffle", Value: "true"},
+		{Key: "optimizer", Value: "adam"},
+		{Key: "weird", Value: "NaN"},
+	}))
+}
+
+func TestSearcherMetric(t *testing.T) {
+	require.Equal(t, defaultSearcherMetric, SearcherMetric(nil))
+
+	var a, b Run
+	a.Data.Metrics = []Metric{{Key: "loss"}, {Key: "accuracy"}}
+	require.Equal(t, "accuracy", SearcherMetric([]Run{a}))
+	b.Data.Metrics = []Metric{{Key: "val_loss"}}
+	require.Equal(t, "val_loss", SearcherMetric([]Run{a, b}))
+}
+
+func TestTrialState(t *testing.T) {
+	require.Equal(t, model.CompletedState, TrialState("FINISHED"))
+	require.Equal(t, model.ErrorState, TrialState("FAILED"))
+	require.Equal(t, model.CanceledState, TrialState("KILLED"))
+	require.Equal(t, model.CanceledState, TrialState("RUNNING"))
+}
+
+func TestImportProto(t *testing.T) {
+	pb := Import{ID: 3, State: ImportRunning, ServerURL: "https://mlflow", Trials: 0}.Proto()
+	require.Equal(t, experimentv1.MLflowImportState_MLFLOW_IMPORT_STATE_RUNNING, pb.State)
+	require.Nil(t, pb.ExperimentId)
+	require.Nil(t, pb.EndTime)
+
+	end := time.Now()
+	pb = Import{
+		ID: 3, State: ImportCompleted, ExperimentID: ptrs.Ptr(7), Trials: 2, EndTime: &end,
+	}.Proto()
+	require.Equal(t, experimentv1.MLflowImportState_MLFLOW_IMPORT_STATE_COMPLETED, pb.State)
+	require.Equal(t, int32(7), *pb.ExperimentId)
+	require.Equal(t, int32(2), pb.Trials)
+	require.NotNil(t, pb.EndTime)
+}
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/2.0/mlflow/experiments/get-by-name":
+			if r.URL.Query().Get("experiment_name") != "mnist" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"experiment": {"experiment_id": "3", "name": "mnist"}}`))
+		case "/api/2.0/mlflow/experiments/get":
+			require.Equal(t, "3", r.URL.Query().Get("experiment_id"))
+			_, _ = w.Write([]byte(`{"experiment": {"experiment_id": "3", "name": "mnist"}}`))
+		case "/api/2.0/mlflow/runs/search":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["page_token"] == nil {
+				_, _ = w.Write([]byte(`{"runs": [{"info": {"run_id": "a", "start_time": 1000}}],
+					"next_page_token": "next"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"runs": [{"info": {"run_id": "b"}}]}`))
+		case "/api/2.0/mlflow/metrics/get-history":
+			if r.URL.Query().Get("page_token") == "" {
+				_, _ = w.Write([]byte(`{"metrics": [{"key": "loss", "value": 1, "step": 1}],
+					"next_page_token": "next"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"metrics": [{"key": "loss", "value": 0.5, "step": 2}]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := NewClient(srv.URL+"/", "token")
+
+	exp, err := c.Experiment(ctx, "mnist")
+	require.NoError(t, err)
+	require.Equal(t, &Experiment{ExperimentID: "3", Name: "mnist"}, exp)
+	exp, err = c.Experiment(ctx, "3")
+	require.NoError(t, err)
+	require.Equal(t, "mnist", exp.Name)
+	_, err = c.Experiment(ctx, "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	runs, err := c.Runs(ctx, "3", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, int64(1000), runs[0].Info.StartTime)
+	runs, err = c.Runs(ctx, "3", 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	history, err := c.MetricHistory(ctx, "a", "loss")
+	require.NoError(t, err)
+	require.Equal(t, []Metric{
+		{Key: "loss", Value: 1, Step: 1},
+		{Key: "loss", Value: 0.5, Step: 2},
+	}, history)
+}
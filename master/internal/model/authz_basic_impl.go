@@ -82,6 +82,18 @@ func (a *ModelAuthZBasic) CanMoveModel(
 	return nil
 }
 
+// CanOverrideModelPromotionGates returns an error if the current user is not an admin.
+func (a *ModelAuthZBasic) CanOverrideModelPromotionGates(ctx context.Context, curUser model.User,
+	m *modelv1.Model, workspaceID int32,
+) error {
+	if !curUser.Admin {
+		return authz.PermissionDeniedError{}.WithPrefix(
+			"non-admin users may not override model promotion gates",
+		)
+	}
+	return nil
+}
+
 // FilterReadableModelsQuery returns the query unmodified and a nil error.
 func (a *ModelAuthZBasic) FilterReadableModelsQuery(
 	ctx context.Context, curUser model.User, query *bun.SelectQuery,
@@ -45,6 +45,11 @@ type ModelAuthZ interface {
 	CanMoveModel(ctx context.Context, curUser model.User, model *modelv1.Model,
 		fromWorkspaceID int32, toWorkspaceID int32) error
 
+	// POST /models/{model}/versions/{version}/promote with override
+	CanOverrideModelPromotionGates(ctx context.Context, curUser model.User,
+		m *modelv1.Model, workspaceID int32,
+	) error
+
 	// GET /api/v1/models with filter to allow reading
 	FilterReadableModelsQuery(
 		ctx context.Context, curUser model.User, query *bun.SelectQuery,
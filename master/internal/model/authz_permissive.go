@@ -68,6 +68,14 @@ func (a *ModelAuthZPermissive) CanMoveModel(ctx context.Context,
 	return (&ModelAuthZBasic{}).CanMoveModel(ctx, curUser, m, origin, destination)
 }
 
+// CanOverrideModelPromotionGates calls RBAC authz but enforces basic authz.
+func (a *ModelAuthZPermissive) CanOverrideModelPromotionGates(ctx context.Context,
+	curUser model.User, m *modelv1.Model, workspaceID int32,
+) error {
+	_ = (&ModelAuthZRBAC{}).CanOverrideModelPromotionGates(ctx, curUser, m, workspaceID)
+	return (&ModelAuthZBasic{}).CanOverrideModelPromotionGates(ctx, curUser, m, workspaceID)
+}
+
 // FilterReadableModelsQuery returns query and a nil error.
 func (a *ModelAuthZPermissive) FilterReadableModelsQuery(
 	ctx context.Context, curUser model.User, query *bun.SelectQuery,
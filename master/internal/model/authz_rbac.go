@@ -237,6 +237,30 @@ func (a *ModelAuthZRBAC) CanMoveModel(ctx context.Context,
 		rbacv1.PermissionType_PERMISSION_TYPE_CREATE_MODEL_REGISTRY)
 }
 
+// CanOverrideModelPromotionGates checks if a user has permission to delete other users' models,
+// the strongest model registry permission, in the model's workspace, along with permission to
+// edit models.
+func (a *ModelAuthZRBAC) CanOverrideModelPromotionGates(ctx context.Context, curUser model.User,
+	m *modelv1.Model, workspaceID int32,
+) (err error) {
+	expectedPermissions := []rbacv1.PermissionType{
+		rbacv1.PermissionType_PERMISSION_TYPE_EDIT_MODEL_REGISTRY,
+		rbacv1.PermissionType_PERMISSION_TYPE_DELETE_OTHER_USER_MODEL_REGISTRY,
+	}
+	fields := audit.ExtractLogFields(ctx)
+	addExpInfo(curUser, fields, fmt.Sprint(m.Id), expectedPermissions)
+	defer func() {
+		audit.LogFromErr(fields, err)
+	}()
+
+	for _, perm := range expectedPermissions {
+		if err := db.DoesPermissionMatch(ctx, curUser.ID, &workspaceID, perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // FilterReadableModelsQuery returns query in relevant workspaces and a nil error.
 func (a *ModelAuthZRBAC) FilterReadableModelsQuery(
 	ctx context.Context, curUser model.User, query *bun.SelectQuery,
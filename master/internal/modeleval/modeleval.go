@@ -0,0 +1,124 @@
+// Package modeleval runs evaluation experiments against the versions of registry models, when
+// they're registered or on a schedule, and records the metrics they report and whether they
+// regressed from the last evaluation.
+package modeleval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// MinScheduleInterval is the shortest interval a policy can evaluate a model on.
+	MinScheduleInterval = 10 * time.Minute
+
+	// CheckpointUUIDEnvVar is the environment variable that tells an evaluation experiment the
+	// checkpoint of the model version it evaluates.
+	CheckpointUUIDEnvVar = "DET_EVAL_CHECKPOINT_UUID"
+	// ModelNameEnvVar is the environment variable that tells an evaluation experiment the name of
+	// the model it evaluates.
+	ModelNameEnvVar = "DET_EVAL_MODEL_NAME"
+	// ModelVersionEnvVar is the environment variable that tells an evaluation experiment the
+	// version of the model it evaluates.
+	ModelVersionEnvVar = "DET_EVAL_MODEL_VERSION"
+)
+
+// Version is a model version that can be evaluated.
+type Version struct {
+	ID             int    `bun:"id"`
+	Version        int    `bun:"version"`
+	CheckpointUUID string `bun:"checkpoint_uuid"`
+	ModelID        int    `bun:"model_id"`
+	ModelName      string `bun:"model_name"`
+	WorkspaceID    int    `bun:"workspace_id"`
+}
+
+// EnvVars returns the environment variables that tell an evaluation experiment what it evaluates.
+func (v Version) EnvVars() map[string]string {
+	return map[string]string{
+		CheckpointUUIDEnvVar: v.CheckpointUUID,
+		ModelNameEnvVar:      v.ModelName,
+		ModelVersionEnvVar:   strconv.Itoa(v.Version),
+	}
+}
+
+// ValidatePolicy returns an error if a policy can't be created as it is.
+func ValidatePolicy(p *model.ModelEvaluationPolicy) error {
+	var errs []error
+	if p.Name == "" {
+		errs = append(errs, errors.New("name must be set"))
+	}
+	if p.Config == "" {
+		errs = append(errs, errors.New("config must be set"))
+	}
+	if p.Metric == "" {
+		errs = append(errs, errors.New("metric must be set"))
+	}
+	if p.RegressionTolerance < 0 || math.IsNaN(p.RegressionTolerance) {
+		errs = append(errs, errors.New("regression_tolerance must not be negative"))
+	}
+	if p.ScheduleInterval != nil {
+		if _, err := ScheduleInterval(p); err != nil {
+			errs = append(errs, err)
+		}
+	} else if p.ScheduleLabel != nil {
+		errs = append(errs, errors.New("schedule_label requires schedule_interval"))
+	}
+	if !p.OnRegister && p.ScheduleInterval == nil {
+		errs = append(errs, errors.New("on_register or schedule_interval must be set"))
+	}
+	return errors.Join(errs...)
+}
+
+// ScheduleInterval returns how often a policy evaluates its model, or zero if it doesn't on a
+// schedule.
+func ScheduleInterval(p *model.ModelEvaluationPolicy) (time.Duration, error) {
+	if p.ScheduleInterval == nil {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(*p.ScheduleInterval)
+	if err != nil {
+		return 0, fmt.Errorf("schedule_interval %q is not a duration like 24h", *p.ScheduleInterval)
+	}
+	if d < MinScheduleInterval {
+		return 0, fmt.Errorf("schedule_interval must be at least %s", MinScheduleInterval)
+	}
+	return d, nil
+}
+
+// Due returns whether a policy should evaluate its model on its schedule at a time.
+func Due(p *model.ModelEvaluationPolicy, now time.Time) bool {
+	d, err := ScheduleInterval(p)
+	if err != nil || d == 0 {
+		return false
+	}
+	return p.LastScheduledAt == nil || !now.Before(p.LastScheduledAt.Add(d))
+}
+
+// Regressed returns whether a metric's value is worse than its baseline by more than a tolerance
+// relative to the baseline, e.g. 0.05 for 5%.
+func Regressed(value, baseline float64, smallerIsBetter bool, tolerance float64) bool {
+	margin := tolerance * math.Abs(baseline)
+	if smallerIsBetter {
+		return value > baseline+margin
+	}
+	return value < baseline-margin
+}
+
+// metricValue returns the value of a metric in validation metrics, if it's a number.
+func metricValue(metrics map[string]any, name string) *float64 {
+	switch v := metrics[name].(type) {
+	case float64:
+		return &v
+	case int:
+		f := float64(v)
+		return &f
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package modeleval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	valid := func() *model.ModelEvaluationPolicy {
+		return &model.ModelEvaluationPolicy{
+			Name:       "holdout",
+			Config:     "entrypoint: python3 eval.py",
+			Metric:     "loss",
+			OnRegister: true,
+		}
+	}
+	require.NoError(t, ValidatePolicy(valid()))
+
+	p := valid()
+	p.ScheduleInterval = ptrs.Ptr("24h")
+	p.ScheduleLabel = ptrs.Ptr("production")
+	require.NoError(t, ValidatePolicy(p))
+
+	p = valid()
+	p.OnRegister = false
+	require.ErrorContains(t, ValidatePolicy(p), "on_register or schedule_interval")
+
+	p = valid()
+	p.ScheduleInterval = ptrs.Ptr("daily")
+	require.ErrorContains(t, ValidatePolicy(p), "not a duration")
+
+	p = valid()
+	p.ScheduleInterval = ptrs.Ptr("1m")
+	require.ErrorContains(t, ValidatePolicy(p), "at least")
+
+	p = valid()
+	p.ScheduleLabel = ptrs.Ptr("production")
+	require.ErrorContains(t, ValidatePolicy(p), "requires schedule_interval")
+
+	p = valid()
+	p.Metric = ""
+	p.RegressionTolerance = -1
+	err := ValidatePolicy(p)
+	require.ErrorContains(t, err, "metric must be set")
+	require.ErrorContains(t, err, "regression_tolerance")
+}
+
+func TestDue(t *testing.T) {
+	now := time.Now()
+	p := &model.ModelEvaluationPolicy{}
+	require.False(t, Due(p, now))
+
+	p.ScheduleInterval = ptrs.Ptr("1h")
+	require.True(t, Due(p, now))
+
+	p.LastScheduledAt = ptrs.Ptr(now.Add(-30 * time.Minute))
+	require.False(t, Due(p, now))
+
+	p.LastScheduledAt = ptrs.Ptr(now.Add(-time.Hour))
+	require.True(t, Due(p, now))
+}
+
+func TestRegressed(t *testing.T) {
+	cases := []struct {
+		name            string
+		value, baseline float64
+		smallerIsBetter bool
+		tolerance       float64
+		regressed       bool
+	}{
+		{"loss improved", 0.4, 0.5, true, 0, false},
+		{"loss got worse", 0.6, 0.5, true, 0, true},
+		{"loss got worse within tolerance", 0.52, 0.5, true, 0.05, false},
+		{"loss got worse past tolerance", 0.53, 0.5, true, 0.05, true},
+		{"accuracy improved", 0.9, 0.8, false, 0, false},
+		{"accuracy got worse", 0.7, 0.8, false, 0, true},
+		{"accuracy got worse within tolerance", 0.79, 0.8, false, 0.05, false},
+		{"negative baseline", -0.9, -1, true, 0.05, true},
+		{"unchanged", 0.5, 0.5, true, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.regressed, Regressed(c.value, c.baseline, c.smallerIsBetter, c.tolerance))
+		})
+	}
+}
+
+func TestMetricValue(t *testing.T) {
+	metrics := map[string]any{"loss": 0.5, "steps": 10, "name": "holdout"}
+	require.Equal(t, 0.5, *metricValue(metrics, "loss"))
+	require.Equal(t, 10.0, *metricValue(metrics, "steps"))
+	require.Nil(t, metricValue(metrics, "name"))
+	require.Nil(t, metricValue(metrics, "accuracy"))
+	require.Nil(t, metricValue(nil, "loss"))
+}
+
+func TestVersionEnvVars(t *testing.T) {
+	v := Version{Version: 3, CheckpointUUID: "abc", ModelName: "mnist"}
+	require.Equal(t, map[string]string{
+		CheckpointUUIDEnvVar: "abc",
+		ModelNameEnvVar:      "mnist",
+		ModelVersionEnvVar:   "3",
+	}, v.EnvVars())
+}
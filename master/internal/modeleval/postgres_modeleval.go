@@ -0,0 +1,333 @@
+package modeleval
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/webhooks"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddPolicy creates a policy.
+func AddPolicy(ctx context.Context, p *model.ModelEvaluationPolicy) error {
+	if err := ValidatePolicy(p); err != nil {
+		return err
+	}
+	_, err := db.Bun().NewInsert().Model(p).Returning("id, created_at").Exec(ctx)
+	if err != nil {
+		return db.MatchSentinelError(err)
+	}
+	return nil
+}
+
+// Policies returns the policies of a model, without their model definitions.
+func Policies(ctx context.Context, modelID int) ([]model.ModelEvaluationPolicy, error) {
+	ps := []model.ModelEvaluationPolicy{}
+	err := db.Bun().NewSelect().Model(&ps).
+		ExcludeColumn("model_definition").
+		Where("model_id = ?", modelID).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting evaluation policies of model %d: %w", modelID, err)
+	}
+	return ps, nil
+}
+
+// PolicyByID returns a policy of a model.
+func PolicyByID(ctx context.Context, modelID, policyID int) (*model.ModelEvaluationPolicy, error) {
+	var p model.ModelEvaluationPolicy
+	err := db.Bun().NewSelect().Model(&p).
+		Where("model_id = ?", modelID).
+		Where("id = ?", policyID).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("getting evaluation policy %d: %w", policyID, err)
+	}
+	return &p, nil
+}
+
+// DeletePolicy deletes a policy of a model, along with its evaluations. The experiments it ran are
+// left as they are.
+func DeletePolicy(ctx context.Context, modelID, policyID int) error {
+	res, err := db.Bun().NewDelete().Model((*model.ModelEvaluationPolicy)(nil)).
+		Where("model_id = ?", modelID).
+		Where("id = ?", policyID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting evaluation policy %d: %w", policyID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// RegisterPolicies returns the policies that evaluate each new version of a model.
+func RegisterPolicies(ctx context.Context, modelID int) ([]model.ModelEvaluationPolicy, error) {
+	var ps []model.ModelEvaluationPolicy
+	err := db.Bun().NewSelect().Model(&ps).
+		Where("model_id = ?", modelID).
+		Where("on_register").
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting evaluation policies of model %d: %w", modelID, err)
+	}
+	return ps, nil
+}
+
+// DuePolicies returns the policies that should evaluate their model on their schedule at a time.
+func DuePolicies(ctx context.Context, now time.Time) ([]model.ModelEvaluationPolicy, error) {
+	var ps []model.ModelEvaluationPolicy
+	err := db.Bun().NewSelect().Model(&ps).
+		Where("schedule_interval IS NOT NULL").
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting scheduled evaluation policies: %w", err)
+	}
+	var due []model.ModelEvaluationPolicy
+	for _, p := range ps {
+		if Due(&p, now) {
+			due = append(due, p)
+		}
+	}
+	return due, nil
+}
+
+// MarkScheduled records that a policy evaluated its model on its schedule at a time.
+func MarkScheduled(ctx context.Context, policyID int, at time.Time) error {
+	_, err := db.Bun().NewUpdate().Model((*model.ModelEvaluationPolicy)(nil)).
+		Set("last_scheduled_at = ?", at).
+		Where("id = ?", policyID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("marking evaluation policy %d scheduled: %w", policyID, err)
+	}
+	return nil
+}
+
+func selectVersion() *bun.SelectQuery {
+	return db.Bun().NewSelect().
+		TableExpr("model_versions AS mv").
+		Join("JOIN models AS m ON m.id = mv.model_id").
+		ColumnExpr("mv.id, mv.version, mv.checkpoint_uuid, mv.model_id").
+		ColumnExpr("m.name AS model_name, m.workspace_id")
+}
+
+// VersionByNumber returns a version of a model.
+func VersionByNumber(ctx context.Context, modelID, version int) (*Version, error) {
+	var v Version
+	err := selectVersion().
+		Where("mv.model_id = ?", modelID).
+		Where("mv.version = ?", version).
+		Scan(ctx, &v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("getting version %d of model %d: %w", version, modelID, err)
+	}
+	return &v, nil
+}
+
+// LatestVersion returns the latest version of a model, or its latest version with a label if one
+// is given, or nil if it has none.
+func LatestVersion(ctx context.Context, modelID int, label *string) (*Version, error) {
+	q := selectVersion().
+		Where("mv.model_id = ?", modelID).
+		OrderExpr("mv.version DESC").
+		Limit(1)
+	if label != nil {
+		q = q.Where("? = ANY(mv.labels)", *label)
+	}
+	var v Version
+	err := q.Scan(ctx, &v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting latest version of model %d: %w", modelID, err)
+	}
+	return &v, nil
+}
+
+// AddEvaluation records an evaluation.
+func AddEvaluation(ctx context.Context, e *model.ModelEvaluation) error {
+	if _, err := db.Bun().NewInsert().Model(e).Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("adding evaluation of model version %d: %w", e.ModelVersionID, err)
+	}
+	return nil
+}
+
+// UpdateEvaluation records the experiment, state and error of an evaluation.
+func UpdateEvaluation(ctx context.Context, e *model.ModelEvaluation) error {
+	if _, err := db.Bun().NewUpdate().Model(e).
+		Column("experiment_id", "state", "error", "ended_at").
+		WherePK().
+		Exec(ctx); err != nil {
+		return fmt.Errorf("updating evaluation %d: %w", e.ID, err)
+	}
+	return nil
+}
+
+// FailInterruptedLaunches records that the evaluations whose experiments were being created when
+// the master stopped failed.
+func FailInterruptedLaunches(ctx context.Context) error {
+	if _, err := db.Bun().NewUpdate().Model((*model.ModelEvaluation)(nil)).
+		Set("state = ?", model.ModelEvaluationFailed).
+		Set("error = ?", "the master stopped before the evaluation experiment was created").
+		Set("ended_at = ?", time.Now().UTC()).
+		Where("state = ?", model.ModelEvaluationLaunching).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("failing interrupted evaluation launches: %w", err)
+	}
+	return nil
+}
+
+// Evaluations returns the evaluations of a model version, newest first.
+func Evaluations(ctx context.Context, modelVersionID int) ([]model.ModelEvaluation, error) {
+	es := []model.ModelEvaluation{}
+	err := db.Bun().NewSelect().Model(&es).
+		ColumnExpr("model_evaluation.*").
+		ColumnExpr("mv.version AS model_version").
+		Join("JOIN model_versions AS mv ON mv.id = model_evaluation.model_version_id").
+		Where("model_evaluation.model_version_id = ?", modelVersionID).
+		OrderExpr("model_evaluation.id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting evaluations of model version %d: %w", modelVersionID, err)
+	}
+	return es, nil
+}
+
+// ExperimentEnded records the result of the evaluation an experiment ran, if it ran one, once the
+// experiment ends. Evaluations that regressed from the last evaluation by the same policy of
+// another version of the model are reported to the model's workspace's webhooks.
+func ExperimentEnded(ctx context.Context, experimentID int, state model.State) error {
+	if !model.TerminalStates[state] {
+		return nil
+	}
+	var e model.ModelEvaluation
+	err := db.Bun().NewSelect().Model(&e).
+		Where("experiment_id = ?", experimentID).
+		Where("state = ?", model.ModelEvaluationRunning).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting evaluation run by experiment %d: %w", experimentID, err)
+	}
+	var p model.ModelEvaluationPolicy
+	err = db.Bun().NewSelect().Model(&p).
+		ExcludeColumn("model_definition").
+		Where("id = ?", e.PolicyID).
+		Scan(ctx)
+	if err != nil {
+		return fmt.Errorf("getting evaluation policy %d: %w", e.PolicyID, err)
+	}
+
+	now := time.Now().UTC()
+	e.EndedAt = &now
+	var baseline *model.ModelEvaluation
+	if state == model.CompletedState {
+		if baseline, err = completeEvaluation(ctx, &p, &e); err != nil {
+			return err
+		}
+	} else {
+		e.State = model.ModelEvaluationFailed
+		msg := fmt.Sprintf("evaluation experiment %d ended in state %s", experimentID, state)
+		e.Error = &msg
+	}
+
+	_, err = db.Bun().NewUpdate().Model(&e).
+		Column("state", "error", "metrics", "metric_value", "baseline_id", "regressed", "ended_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("recording evaluation %d: %w", e.ID, err)
+	}
+
+	if e.Regressed != nil && *e.Regressed {
+		return reportRegression(ctx, &p, &e, baseline)
+	}
+	return nil
+}
+
+// completeEvaluation fills in the metrics of an evaluation whose experiment completed, and
+// compares them with those of the last completed evaluation of another version by its policy,
+// which it returns.
+func completeEvaluation(
+	ctx context.Context, p *model.ModelEvaluationPolicy, e *model.ModelEvaluation,
+) (*model.ModelEvaluation, error) {
+	var metrics struct {
+		Metrics map[string]any `bun:"metrics"`
+	}
+	err := db.Bun().NewSelect().
+		TableExpr("validations AS v").
+		Join("JOIN trials AS t ON t.id = v.trial_id").
+		ColumnExpr("v.metrics->'validation_metrics' AS metrics").
+		Where("t.experiment_id = ?", *e.ExperimentID).
+		OrderExpr("v.end_time DESC").
+		Limit(1).
+		Scan(ctx, &metrics)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting validation metrics of experiment %d: %w", *e.ExperimentID, err)
+	}
+
+	e.State = model.ModelEvaluationCompleted
+	e.Metrics = metrics.Metrics
+	e.MetricValue = metricValue(metrics.Metrics, p.Metric)
+	if e.MetricValue == nil {
+		msg := fmt.Sprintf("evaluation experiment didn't report validation metric %s", p.Metric)
+		e.Error = &msg
+		return nil, nil
+	}
+
+	var baseline model.ModelEvaluation
+	err = db.Bun().NewSelect().Model(&baseline).
+		Where("policy_id = ?", p.ID).
+		Where("model_version_id != ?", e.ModelVersionID).
+		Where("state = ?", model.ModelEvaluationCompleted).
+		Where("metric_value IS NOT NULL").
+		Where("id < ?", e.ID).
+		OrderExpr("id DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting baseline of evaluation %d: %w", e.ID, err)
+	}
+	regressed := Regressed(*e.MetricValue, *baseline.MetricValue, p.SmallerIsBetter,
+		p.RegressionTolerance)
+	e.BaselineID = &baseline.ID
+	e.Regressed = &regressed
+	return &baseline, nil
+}
+
+func reportRegression(
+	ctx context.Context, p *model.ModelEvaluationPolicy, e, baseline *model.ModelEvaluation,
+) error {
+	var v Version
+	if err := selectVersion().Where("mv.id = ?", e.ModelVersionID).Scan(ctx, &v); err != nil {
+		return fmt.Errorf("getting model version %d: %w", e.ModelVersionID, err)
+	}
+	log.WithFields(log.Fields{
+		"model":      v.ModelName,
+		"version":    v.Version,
+		"policy":     p.Name,
+		"evaluation": e.ID,
+	}).Warnf("model evaluation regressed: %s was %g, the last evaluation's was %g",
+		p.Metric, *e.MetricValue, *baseline.MetricValue)
+	return webhooks.ReportModelEvaluationRegressed(ctx, int32(v.WorkspaceID), v.ModelName,
+		v.Version, p.Name, p.Metric, *e.MetricValue, *baseline.MetricValue)
+}
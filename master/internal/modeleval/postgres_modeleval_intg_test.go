@@ -0,0 +1,137 @@
+//go:build integration
+// +build integration
+
+package modeleval
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestExperimentEnded(t *testing.T) {
+	ctx := context.Background()
+	pgDB := db.SingleDB()
+	user := db.RequireMockUser(t, pgDB)
+	workspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	projectID, _ := db.RequireMockProjectID(t, pgDB, workspaceID, false)
+
+	// A model with two versions of a checkpoint.
+	exp := db.RequireMockExperimentProject(t, pgDB, user, projectID)
+	tr, task := db.RequireMockTrial(t, pgDB, exp)
+	a := db.RequireMockAllocation(t, pgDB, task.TaskID)
+	ckpt := db.MockModelCheckpoint(uuid.New(), a)
+	require.NoError(t, db.AddCheckpointMetadata(ctx, &ckpt, tr.ID))
+	mdl, err := db.InsertModel(ctx, uuid.NewString(), "", []byte("{}"), "", "", user.ID,
+		workspaceID)
+	require.NoError(t, err)
+	v1, err := db.InsertModelVersion(ctx, mdl.Id, ckpt.UUID.String(), "", "", []byte("{}"),
+		"production", "", user.ID)
+	require.NoError(t, err)
+	v2, err := db.InsertModelVersion(ctx, mdl.Id, ckpt.UUID.String(), "", "", []byte("{}"), "",
+		"", user.ID)
+	require.NoError(t, err)
+
+	latest, err := LatestVersion(ctx, int(mdl.Id), nil)
+	require.NoError(t, err)
+	require.Equal(t, int(v2.Version), latest.Version)
+	production, err := LatestVersion(ctx, int(mdl.Id), ptrs.Ptr("production"))
+	require.NoError(t, err)
+	require.Equal(t, int(v1.Version), production.Version)
+
+	p := model.ModelEvaluationPolicy{
+		ModelID:    int(mdl.Id),
+		Name:       "holdout",
+		Config:     "entrypoint: python3 eval.py",
+		ProjectID:  projectID,
+		OnRegister: true,
+		Metric:     "okness",
+		OwnerID:    user.ID,
+	}
+	require.NoError(t, AddPolicy(ctx, &p))
+	ps, err := RegisterPolicies(ctx, int(mdl.Id))
+	require.NoError(t, err)
+	require.Len(t, ps, 1)
+
+	// Each version is evaluated by an experiment that reports a validation metric.
+	evaluate := func(versionID int, okness int32) model.ModelEvaluation {
+		evalExp := db.RequireMockExperimentProject(t, pgDB, user, projectID)
+		evalTrial, _ := db.RequireMockTrial(t, pgDB, evalExp)
+		require.NoError(t, db.AddTrialValidationMetrics(ctx, uuid.New(), evalTrial, 10, okness, pgDB))
+		e := model.ModelEvaluation{
+			PolicyID:       p.ID,
+			ModelVersionID: versionID,
+			ExperimentID:   &evalExp.ID,
+			Trigger:        model.ModelEvaluationTriggerRegister,
+			State:          model.ModelEvaluationRunning,
+		}
+		require.NoError(t, AddEvaluation(ctx, &e))
+		require.NoError(t, ExperimentEnded(ctx, evalExp.ID, model.CompletedState))
+		es, err := Evaluations(ctx, versionID)
+		require.NoError(t, err)
+		require.Len(t, es, 1)
+		return es[0]
+	}
+
+	first := evaluate(int(v1.Id), 1)
+	require.Equal(t, model.ModelEvaluationCompleted, first.State)
+	require.Equal(t, 1.0, *first.MetricValue)
+	require.Nil(t, first.BaselineID)
+	require.Nil(t, first.Regressed)
+
+	// Smaller is better, so a larger metric regressed.
+	second := evaluate(int(v2.Id), 2)
+	require.Equal(t, model.ModelEvaluationCompleted, second.State)
+	require.Equal(t, int(v2.Version), second.ModelVersion)
+	require.Equal(t, first.ID, *second.BaselineID)
+	require.True(t, *second.Regressed)
+
+	// Experiments that didn't complete fail the evaluation.
+	failedExp := db.RequireMockExperimentProject(t, pgDB, user, projectID)
+	failed := model.ModelEvaluation{
+		PolicyID:       p.ID,
+		ModelVersionID: int(v1.Id),
+		ExperimentID:   &failedExp.ID,
+		Trigger:        model.ModelEvaluationTriggerManual,
+		State:          model.ModelEvaluationRunning,
+	}
+	require.NoError(t, AddEvaluation(ctx, &failed))
+	require.NoError(t, ExperimentEnded(ctx, failedExp.ID, model.PausedState))
+	require.NoError(t, ExperimentEnded(ctx, failedExp.ID, model.ErrorState))
+	es, err := Evaluations(ctx, int(v1.Id))
+	require.NoError(t, err)
+	require.Len(t, es, 2)
+	require.Equal(t, model.ModelEvaluationFailed, es[0].State)
+	require.Contains(t, *es[0].Error, "ERROR")
+
+	require.NoError(t, DeletePolicy(ctx, int(mdl.Id), p.ID))
+	require.ErrorIs(t, DeletePolicy(ctx, int(mdl.Id), p.ID), db.ErrNotFound)
+}
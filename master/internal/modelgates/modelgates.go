@@ -0,0 +1,150 @@
+// Package modelgates blocks the promotion of registry model versions to production unless their
+// latest evaluations pass their model's metric gates, and audits the promotions that override
+// them.
+package modelgates
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+// ProductionLabel is the label that marks a model version as promoted to production.
+const ProductionLabel = "production"
+
+// Comparisons are the ways a gate can compare a metric with its threshold.
+var Comparisons = []string{">=", ">", "<=", "<"}
+
+// Passes returns whether a metric's value passes a gate's comparison with its threshold.
+func Passes(comparison string, value, threshold float64) bool {
+	switch comparison {
+	case ">=":
+		return value >= threshold
+	case ">":
+		return value > threshold
+	case "<=":
+		return value <= threshold
+	case "<":
+		return value < threshold
+	default:
+		return false
+	}
+}
+
+// ValidateGate returns an error if a gate can't be created as it is.
+func ValidateGate(g *model.ModelPromotionGate) error {
+	switch {
+	case g.Metric == "":
+		return fmt.Errorf("metric must be set")
+	case !slices.Contains(Comparisons, g.Comparison):
+		return fmt.Errorf("comparison must be one of %s", strings.Join(Comparisons, ", "))
+	}
+	return nil
+}
+
+// Promotes returns whether changing a model version's labels promotes it to production.
+func Promotes(oldLabels, newLabels []string) bool {
+	return !hasProductionLabel(oldLabels) && hasProductionLabel(newLabels)
+}
+
+func hasProductionLabel(labels []string) bool {
+	return slices.ContainsFunc(labels, func(l string) bool {
+		return strings.EqualFold(strings.TrimSpace(l), ProductionLabel)
+	})
+}
+
+// Result is whether a model version passes a gate.
+type Result struct {
+	Gate model.ModelPromotionGate `json:"gate"`
+	// EvaluationID is the latest completed evaluation of the version by the gate's policy.
+	EvaluationID *int     `json:"evaluation_id"`
+	Value        *float64 `json:"value"`
+	Passed       bool     `json:"passed"`
+	// Reason says why the version didn't pass the gate.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Proto converts a result to its protobuf representation.
+func (r Result) Proto() *modelv1.ModelPromotionGateResult {
+	pb := &modelv1.ModelPromotionGateResult{
+		Gate:   r.Gate.Proto(),
+		Value:  r.Value,
+		Passed: r.Passed,
+	}
+	if r.EvaluationID != nil {
+		pb.EvaluationId = ptrs.Ptr(int32(*r.EvaluationID))
+	}
+	if r.Reason != "" {
+		pb.Reason = &r.Reason
+	}
+	return pb
+}
+
+// Check returns whether a model version passes each of its model's gates, given its latest
+// completed evaluation by each evaluation policy.
+func Check(
+	gates []model.ModelPromotionGate, latest map[int]*model.ModelEvaluation,
+) []Result {
+	results := make([]Result, 0, len(gates))
+	for _, g := range gates {
+		r := Result{Gate: g}
+		e, ok := latest[g.PolicyID]
+		if !ok || e == nil {
+			r.Reason = fmt.Sprintf("the version has no completed evaluation by policy %d", g.PolicyID)
+			results = append(results, r)
+			continue
+		}
+		r.EvaluationID = &e.ID
+		switch v, ok := e.Metrics[g.Metric].(float64); {
+		case !ok:
+			r.Reason = fmt.Sprintf("evaluation %d didn't report %s", e.ID, g.Metric)
+		case !Passes(g.Comparison, v, g.Threshold):
+			r.Value = &v
+			r.Reason = fmt.Sprintf("%s of %g in evaluation %d isn't %s %g",
+				g.Metric, v, e.ID, g.Comparison, g.Threshold)
+		default:
+			r.Value = &v
+			r.Passed = true
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Unmet returns the results of the gates that weren't passed.
+func Unmet(results []Result) []Result {
+	var unmet []Result
+	for _, r := range results {
+		if !r.Passed {
+			unmet = append(unmet, r)
+		}
+	}
+	return unmet
+}
+
+// UnmetError is returned when a model version is promoted without passing its model's gates.
+type UnmetError struct {
+	ModelName string
+	// Version is the version number, or zero for a version that is being registered.
+	Version int
+	Unmet   []Result
+}
+
+func (e UnmetError) Error() string {
+	version := "a new version"
+	if e.Version != 0 {
+		version = fmt.Sprintf("version %d", e.Version)
+	}
+	reasons := make([]string, 0, len(e.Unmet))
+	for _, r := range e.Unmet {
+		reasons = append(reasons, r.Reason)
+	}
+	return fmt.Sprintf(
+		"%s of model %s can't be promoted to %s since it doesn't pass %d of the model's gates: %s; "+
+			"promoting it anyway requires overriding the gates",
+		version, e.ModelName, ProductionLabel, len(e.Unmet), strings.Join(reasons, "; "))
+}
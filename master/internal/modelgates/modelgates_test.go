@@ -0,0 +1,86 @@
+package modelgates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestPasses(t *testing.T) {
+	require.True(t, Passes(">=", 0.92, 0.92))
+	require.False(t, Passes(">", 0.92, 0.92))
+	require.True(t, Passes("<=", 0.1, 0.2))
+	require.False(t, Passes("<", 0.2, 0.2))
+	require.False(t, Passes("==", 0.2, 0.2))
+}
+
+func TestValidateGate(t *testing.T) {
+	require.NoError(t, ValidateGate(&model.ModelPromotionGate{Metric: "accuracy", Comparison: ">="}))
+	require.ErrorContains(t, ValidateGate(&model.ModelPromotionGate{Comparison: ">="}), "metric")
+	require.ErrorContains(t,
+		ValidateGate(&model.ModelPromotionGate{Metric: "accuracy", Comparison: "=="}), "comparison")
+}
+
+func TestPromotes(t *testing.T) {
+	require.True(t, Promotes(nil, []string{"candidate", "Production "}))
+	require.False(t, Promotes([]string{"production"}, []string{"production", "v2"}))
+	require.False(t, Promotes([]string{"candidate"}, []string{"staging"}))
+	require.False(t, Promotes([]string{"production"}, nil))
+}
+
+func TestCheck(t *testing.T) {
+	gates := []model.ModelPromotionGate{
+		{ID: 1, PolicyID: 1, Metric: "accuracy", Comparison: ">=", Threshold: 0.92},
+		{ID: 2, PolicyID: 1, Metric: "loss", Comparison: "<", Threshold: 0.1},
+		{ID: 3, PolicyID: 1, Metric: "f1", Comparison: ">", Threshold: 0.5},
+		{ID: 4, PolicyID: 2, Metric: "accuracy", Comparison: ">=", Threshold: 0.8},
+	}
+	latest := map[int]*model.ModelEvaluation{
+		1: {ID: 7, PolicyID: 1, Metrics: map[string]any{"accuracy": 0.95, "loss": 0.2}},
+	}
+
+	results := Check(gates, latest)
+	require.Len(t, results, 4)
+	require.True(t, results[0].Passed)
+	require.Equal(t, 0.95, *results[0].Value)
+	require.Equal(t, 7, *results[0].EvaluationID)
+	require.False(t, results[1].Passed)
+	require.Equal(t, 0.2, *results[1].Value)
+	require.Contains(t, results[1].Reason, "isn't < 0.1")
+	require.False(t, results[2].Passed)
+	require.Nil(t, results[2].Value)
+	require.Contains(t, results[2].Reason, "didn't report f1")
+	require.False(t, results[3].Passed)
+	require.Nil(t, results[3].EvaluationID)
+	require.Contains(t, results[3].Reason, "no completed evaluation by policy 2")
+
+	unmet := Unmet(results)
+	require.Len(t, unmet, 3)
+	require.Empty(t, Unmet(results[:1]))
+
+	err := UnmetError{ModelName: "mnist", Version: 3, Unmet: unmet}
+	require.Contains(t, err.Error(), "version 3 of model mnist")
+	require.Contains(t, err.Error(), "doesn't pass 3 of the model's gates")
+	require.Contains(t, UnmetError{ModelName: "mnist", Unmet: unmet}.Error(), "a new version")
+}
+
+func TestResultProto(t *testing.T) {
+	evalID, value := 5, 0.8
+	pb := Result{
+		Gate:         model.ModelPromotionGate{ID: 1, Metric: "accuracy"},
+		EvaluationID: &evalID,
+		Value:        &value,
+		Reason:       "accuracy of 0.8 in evaluation 5 isn't >= 0.92",
+	}.Proto()
+	require.Equal(t, int32(1), pb.Gate.Id)
+	require.Equal(t, int32(5), pb.GetEvaluationId())
+	require.Equal(t, 0.8, pb.GetValue())
+	require.False(t, pb.Passed)
+	require.Equal(t, "accuracy of 0.8 in evaluation 5 isn't >= 0.92", pb.GetReason())
+
+	pb = Result{Gate: model.ModelPromotionGate{ID: 1}, Passed: true}.Proto()
+	require.Nil(t, pb.EvaluationId)
+	require.Nil(t, pb.Reason)
+}
@@ -0,0 +1,194 @@
+package modelgates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddGate creates a gate and records who added it.
+func AddGate(ctx context.Context, g *model.ModelPromotionGate) error {
+	if err := ValidateGate(g); err != nil {
+		return err
+	}
+	return db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(g).Returning("id, created_at").Exec(ctx); err != nil {
+			return fmt.Errorf("adding promotion gate to model %d: %w", g.ModelID, err)
+		}
+		return recordChanges(ctx, tx, []model.ModelPromotionGate{*g},
+			model.ModelPromotionGateAdded, g.CreatedBy)
+	})
+}
+
+// Gates returns the gates of a model.
+func Gates(ctx context.Context, modelID int) ([]model.ModelPromotionGate, error) {
+	gs := []model.ModelPromotionGate{}
+	err := db.Bun().NewSelect().Model(&gs).
+		Where("model_id = ?", modelID).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting promotion gates of model %d: %w", modelID, err)
+	}
+	return gs, nil
+}
+
+// PolicyGates returns the gates of a model that use an evaluation policy.
+func PolicyGates(ctx context.Context, modelID, policyID int) ([]model.ModelPromotionGate, error) {
+	gs := []model.ModelPromotionGate{}
+	err := db.Bun().NewSelect().Model(&gs).
+		Where("model_id = ?", modelID).
+		Where("policy_id = ?", policyID).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting promotion gates of evaluation policy %d: %w", policyID, err)
+	}
+	return gs, nil
+}
+
+// DeleteGate deletes a gate of a model and records who deleted it.
+func DeleteGate(ctx context.Context, modelID, gateID int, userID model.UserID) error {
+	return db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		gs := []model.ModelPromotionGate{}
+		_, err := tx.NewDelete().Model(&gs).
+			Where("model_id = ?", modelID).
+			Where("id = ?", gateID).
+			Returning("*").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("deleting promotion gate %d: %w", gateID, err)
+		}
+		if len(gs) == 0 {
+			return db.ErrNotFound
+		}
+		return recordChanges(ctx, tx, gs, model.ModelPromotionGateRemoved, userID)
+	})
+}
+
+// DeletePolicyGates deletes the gates of a model that use an evaluation policy, before the policy
+// is deleted, and records who deleted them.
+func DeletePolicyGates(ctx context.Context, modelID, policyID int, userID model.UserID) error {
+	return db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		gs := []model.ModelPromotionGate{}
+		_, err := tx.NewDelete().Model(&gs).
+			Where("model_id = ?", modelID).
+			Where("policy_id = ?", policyID).
+			Returning("*").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("deleting promotion gates of evaluation policy %d: %w", policyID, err)
+		}
+		return recordChanges(ctx, tx, gs, model.ModelPromotionGateRemoved, userID)
+	})
+}
+
+func recordChanges(
+	ctx context.Context, tx bun.IDB, gates []model.ModelPromotionGate,
+	action model.ModelPromotionGateAction, userID model.UserID,
+) error {
+	if len(gates) == 0 {
+		return nil
+	}
+	changes := make([]model.ModelPromotionGateChange, 0, len(gates))
+	for _, g := range gates {
+		changes = append(changes, model.ModelPromotionGateChange{
+			ModelID:    g.ModelID,
+			GateID:     g.ID,
+			Action:     action,
+			PolicyID:   g.PolicyID,
+			Metric:     g.Metric,
+			Comparison: g.Comparison,
+			Threshold:  g.Threshold,
+			UserID:     userID,
+		})
+	}
+	if _, err := tx.NewInsert().Model(&changes).Exec(ctx); err != nil {
+		return fmt.Errorf("recording promotion gate changes of model %d: %w", gates[0].ModelID, err)
+	}
+	return nil
+}
+
+// GateChanges returns the gates that were added to and removed from a model, newest first.
+func GateChanges(ctx context.Context, modelID int) ([]model.ModelPromotionGateChange, error) {
+	changes := []model.ModelPromotionGateChange{}
+	err := db.Bun().NewSelect().Model(&changes).
+		Where("model_id = ?", modelID).
+		OrderExpr("id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting promotion gate changes of model %d: %w", modelID, err)
+	}
+	return changes, nil
+}
+
+// CheckVersion returns whether a model version passes each of its model's gates. A version that
+// isn't registered yet, with a nil ID, has no evaluations to pass them with.
+func CheckVersion(ctx context.Context, modelID int, modelVersionID *int) ([]Result, error) {
+	gates, err := Gates(ctx, modelID)
+	if err != nil || len(gates) == 0 {
+		return nil, err
+	}
+	latest := map[int]*model.ModelEvaluation{}
+	if modelVersionID != nil {
+		var es []model.ModelEvaluation
+		err = db.Bun().NewSelect().Model(&es).
+			DistinctOn("policy_id").
+			Where("model_version_id = ?", *modelVersionID).
+			Where("state = ?", model.ModelEvaluationCompleted).
+			OrderExpr("policy_id, id DESC").
+			Scan(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting evaluations of model version %d: %w", *modelVersionID, err)
+		}
+		for i := range es {
+			latest[es[i].PolicyID] = &es[i]
+		}
+	}
+	return Check(gates, latest), nil
+}
+
+// RecordOverride records that a model version was promoted without passing its model's gates.
+func RecordOverride(
+	ctx context.Context, modelVersionID int, userID model.UserID, reason string, unmet []Result,
+) (*model.ModelPromotionOverride, error) {
+	// The gates are recorded as they were, since they can be changed or deleted afterwards.
+	b, err := json.Marshal(unmet)
+	if err != nil {
+		return nil, err
+	}
+	o := model.ModelPromotionOverride{
+		ModelVersionID: modelVersionID,
+		UserID:         userID,
+		Reason:         reason,
+	}
+	if err = json.Unmarshal(b, &o.UnmetGates); err != nil {
+		return nil, err
+	}
+	if _, err = db.Bun().NewInsert().Model(&o).Returning("id, created_at").Exec(ctx); err != nil {
+		return nil, fmt.Errorf("recording promotion override of model version %d: %w",
+			modelVersionID, err)
+	}
+	return &o, nil
+}
+
+// Overrides returns the promotions of a model's versions that overrode its gates, newest first.
+func Overrides(ctx context.Context, modelID int) ([]model.ModelPromotionOverride, error) {
+	overrides := []model.ModelPromotionOverride{}
+	err := db.Bun().NewSelect().Model(&overrides).
+		ColumnExpr("model_promotion_override.*").
+		ColumnExpr("mv.version AS model_version").
+		Join("JOIN model_versions AS mv ON mv.id = model_promotion_override.model_version_id").
+		Where("mv.model_id = ?", modelID).
+		OrderExpr("model_promotion_override.id DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting promotion overrides of model %d: %w", modelID, err)
+	}
+	return overrides, nil
+}
@@ -0,0 +1,148 @@
+//go:build integration
+// +build integration
+
+package modelgates
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/modeleval"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestCheckVersion(t *testing.T) {
+	ctx := context.Background()
+	pgDB := db.SingleDB()
+	user := db.RequireMockUser(t, pgDB)
+	workspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	projectID, _ := db.RequireMockProjectID(t, pgDB, workspaceID, false)
+
+	exp := db.RequireMockExperimentProject(t, pgDB, user, projectID)
+	tr, task := db.RequireMockTrial(t, pgDB, exp)
+	a := db.RequireMockAllocation(t, pgDB, task.TaskID)
+	ckpt := db.MockModelCheckpoint(uuid.New(), a)
+	require.NoError(t, db.AddCheckpointMetadata(ctx, &ckpt, tr.ID))
+	mdl, err := db.InsertModel(ctx, uuid.NewString(), "", []byte("{}"), "", "", user.ID,
+		workspaceID)
+	require.NoError(t, err)
+	mv, err := db.InsertModelVersion(ctx, mdl.Id, ckpt.UUID.String(), "", "", []byte("{}"), "",
+		"", user.ID)
+	require.NoError(t, err)
+	versionID := int(mv.Id)
+
+	// Models without gates pass them.
+	results, err := CheckVersion(ctx, int(mdl.Id), &versionID)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	p := model.ModelEvaluationPolicy{
+		ModelID:   int(mdl.Id),
+		Name:      "suite-x",
+		Config:    "entrypoint: python3 eval.py",
+		ProjectID: projectID,
+		Metric:    "accuracy",
+		OwnerID:   user.ID,
+	}
+	require.NoError(t, modeleval.AddPolicy(ctx, &p))
+	g := model.ModelPromotionGate{
+		ModelID:    int(mdl.Id),
+		PolicyID:   p.ID,
+		Metric:     "accuracy",
+		Comparison: ">=",
+		Threshold:  0.92,
+		CreatedBy:  user.ID,
+	}
+	require.NoError(t, AddGate(ctx, &g))
+
+	// Versions that weren't evaluated, or aren't registered yet, don't pass.
+	results, err = CheckVersion(ctx, int(mdl.Id), &versionID)
+	require.NoError(t, err)
+	require.Len(t, Unmet(results), 1)
+	results, err = CheckVersion(ctx, int(mdl.Id), nil)
+	require.NoError(t, err)
+	require.Len(t, Unmet(results), 1)
+
+	// Only the latest completed evaluation counts.
+	evaluate := func(accuracy float64) {
+		e := model.ModelEvaluation{
+			PolicyID:       p.ID,
+			ModelVersionID: versionID,
+			Trigger:        model.ModelEvaluationTriggerManual,
+			State:          model.ModelEvaluationRunning,
+		}
+		require.NoError(t, modeleval.AddEvaluation(ctx, &e))
+		e.State = model.ModelEvaluationCompleted
+		e.Metrics = map[string]any{"accuracy": accuracy}
+		_, err := db.Bun().NewUpdate().Model(&e).Column("state", "metrics").WherePK().
+			Exec(ctx)
+		require.NoError(t, err)
+	}
+	evaluate(0.95)
+	evaluate(0.9)
+	results, err = CheckVersion(ctx, int(mdl.Id), &versionID)
+	require.NoError(t, err)
+	unmet := Unmet(results)
+	require.Len(t, unmet, 1)
+	require.Equal(t, 0.9, *unmet[0].Value)
+
+	evaluate(0.93)
+	results, err = CheckVersion(ctx, int(mdl.Id), &versionID)
+	require.NoError(t, err)
+	require.Empty(t, Unmet(results))
+
+	o, err := RecordOverride(ctx, versionID, user.ID, "hotfix", unmet)
+	require.NoError(t, err)
+	overrides, err := Overrides(ctx, int(mdl.Id))
+	require.NoError(t, err)
+	require.Len(t, overrides, 1)
+	require.Equal(t, o.ID, overrides[0].ID)
+	require.Equal(t, int(mv.Version), overrides[0].ModelVersion)
+	require.Equal(t, "accuracy", overrides[0].UnmetGates[0]["gate"].(map[string]any)["metric"])
+
+	require.NoError(t, DeleteGate(ctx, int(mdl.Id), g.ID, user.ID))
+	require.ErrorIs(t, DeleteGate(ctx, int(mdl.Id), g.ID, user.ID), db.ErrNotFound)
+
+	g2 := g
+	g2.ID = 0
+	require.NoError(t, AddGate(ctx, &g2))
+	require.NoError(t, DeletePolicyGates(ctx, int(mdl.Id), p.ID, user.ID))
+	gates, err := PolicyGates(ctx, int(mdl.Id), p.ID)
+	require.NoError(t, err)
+	require.Empty(t, gates)
+
+	changes, err := GateChanges(ctx, int(mdl.Id))
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+	require.Equal(t, model.ModelPromotionGateRemoved, changes[0].Action)
+	require.Equal(t, g2.ID, changes[0].GateID)
+	require.Equal(t, model.ModelPromotionGateAdded, changes[3].Action)
+	require.Equal(t, g.ID, changes[3].GateID)
+	require.Equal(t, 0.92, changes[3].Threshold)
+}
@@ -0,0 +1,33 @@
+// Package notebooksync persists notebook working directories to object storage across launches.
+package notebooksync
+
+import (
+	"fmt"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+const (
+	// PrefixEnvVar is the environment variable that tells the notebook container where in
+	// checkpoint storage its working directory is synced to.
+	PrefixEnvVar = "DET_NOTEBOOK_SYNC_PREFIX"
+	// StorageConfigEnvVar is the environment variable holding the path to the storage config
+	// file used for syncing inside the notebook container.
+	StorageConfigEnvVar = "DET_NOTEBOOK_SYNC_STORAGE_CONFIG"
+	// StorageConfigPath is where the storage config is written inside the notebook container.
+	StorageConfigPath = "/run/determined/jupyter/sync-storage.json"
+)
+
+// StoragePrefix returns the storage path a user's notebook working directory in a workspace is
+// synced to. It is stable across launches so that a new notebook restores the last sync.
+func StoragePrefix(workspaceID int, userID model.UserID) string {
+	return fmt.Sprintf("notebooks/workspace-%d/user-%d", workspaceID, userID)
+}
+
+// EnvVars returns the environment variables needed to enable syncing in a notebook container.
+func EnvVars(prefix string) map[string]string {
+	return map[string]string{
+		PrefixEnvVar:        prefix,
+		StorageConfigEnvVar: StorageConfigPath,
+	}
+}
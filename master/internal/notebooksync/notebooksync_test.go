@@ -0,0 +1,18 @@
+package notebooksync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoragePrefix(t *testing.T) {
+	require.Equal(t, "notebooks/workspace-3/user-7", StoragePrefix(3, 7))
+	require.NotEqual(t, StoragePrefix(3, 7), StoragePrefix(7, 3))
+}
+
+func TestEnvVars(t *testing.T) {
+	env := EnvVars(StoragePrefix(1, 2))
+	require.Equal(t, "notebooks/workspace-1/user-2", env[PrefixEnvVar])
+	require.Equal(t, StorageConfigPath, env[StorageConfigEnvVar])
+}
@@ -0,0 +1,163 @@
+package notebooksync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Enabled returns whether notebook working directory sync is turned on for a workspace.
+func Enabled(ctx context.Context, workspaceID int) (bool, error) {
+	var enabled bool
+	err := db.Bun().NewSelect().Table("workspaces").
+		Column("notebook_workdir_sync").
+		Where("id = ?", workspaceID).
+		Scan(ctx, &enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, db.ErrNotFound
+	} else if err != nil {
+		return false, fmt.Errorf("getting notebook sync setting for workspace %d: %w", workspaceID, err)
+	}
+	return enabled, nil
+}
+
+// SetEnabled turns notebook working directory sync on or off for a workspace.
+func SetEnabled(ctx context.Context, workspaceID int, enabled bool) error {
+	res, err := db.Bun().NewUpdate().Table("workspaces").
+		Set("notebook_workdir_sync = ?", enabled).
+		Where("id = ?", workspaceID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting notebook sync for workspace %d: %w", workspaceID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// ErrNotebookRunning is returned when a user launches a synced notebook in a workspace while
+// another synced notebook of theirs in that workspace is still running. Both would sync to the
+// same storage prefix and overwrite each other's uploads.
+var ErrNotebookRunning = errors.New("a synced notebook is already running in this workspace")
+
+// launchMu serializes synced notebook launches so two concurrent launches by the same user can't
+// both pass the running check before either is recorded.
+var launchMu sync.Mutex
+
+// Launch starts a synced notebook for a user in a workspace with launch, and records it as the
+// user's current sync task. It returns ErrNotebookRunning without calling launch if the user's
+// last synced notebook in the workspace has not ended yet.
+func Launch(
+	ctx context.Context, workspaceID int, userID model.UserID, launch func() (model.TaskID, error),
+) error {
+	launchMu.Lock()
+	defer launchMu.Unlock()
+
+	running, err := RunningTask(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if running != nil {
+		return fmt.Errorf("%w (task %s)", ErrNotebookRunning, *running)
+	}
+	taskID, err := launch()
+	if err != nil {
+		return err
+	}
+	_, err = RecordLaunch(ctx, workspaceID, userID, taskID)
+	return err
+}
+
+// RunningTask returns the user's last synced notebook task in the workspace if it has not ended,
+// or nil otherwise.
+func RunningTask(ctx context.Context, workspaceID int, userID model.UserID) (*model.TaskID, error) {
+	var taskIDs []model.TaskID
+	err := db.Bun().NewSelect().Table("notebook_workdir_syncs").
+		ColumnExpr("notebook_workdir_syncs.last_task_id").
+		Join("JOIN tasks ON tasks.task_id = notebook_workdir_syncs.last_task_id").
+		Where("notebook_workdir_syncs.workspace_id = ?", workspaceID).
+		Where("notebook_workdir_syncs.user_id = ?", userID).
+		Where("tasks.end_time IS NULL").
+		Scan(ctx, &taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("getting running synced notebook for user %d: %w", userID, err)
+	}
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	return &taskIDs[0], nil
+}
+
+// RecordLaunch notes that a notebook task was launched with sync enabled, creating the user's
+// sync record for the workspace if this is the first time.
+func RecordLaunch(
+	ctx context.Context, workspaceID int, userID model.UserID, taskID model.TaskID,
+) (*model.NotebookWorkDirSync, error) {
+	rec := &model.NotebookWorkDirSync{
+		WorkspaceID:    workspaceID,
+		UserID:         userID,
+		StoragePrefix:  StoragePrefix(workspaceID, userID),
+		LastTaskID:     &taskID,
+		LastLaunchedAt: time.Now().UTC(),
+	}
+	_, err := db.Bun().NewInsert().Model(rec).
+		Column("workspace_id", "user_id", "storage_prefix", "last_task_id", "last_launched_at").
+		On("CONFLICT (user_id, workspace_id) DO UPDATE").
+		Set("last_task_id = EXCLUDED.last_task_id").
+		Set("last_launched_at = EXCLUDED.last_launched_at").
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("recording notebook sync launch for task %s: %w", taskID, err)
+	}
+	return rec, nil
+}
+
+// RecordSync marks the sync record of the given notebook task, launched by the given user, as
+// synced at syncTime. Notebooks report a sync only once storage has acknowledged the upload of
+// their working directory, so the time is the last time the stored copy was complete.
+func RecordSync(
+	ctx context.Context, taskID model.TaskID, userID model.UserID, syncTime time.Time,
+) error {
+	res, err := db.Bun().NewUpdate().Table("notebook_workdir_syncs").
+		Set("last_synced_at = ?", syncTime).
+		Where("last_task_id = ?", taskID).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("recording notebook sync for task %s: %w", taskID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// ByWorkspace returns the sync records of a workspace, optionally limited to a single user.
+func ByWorkspace(
+	ctx context.Context, workspaceID int, userID *model.UserID,
+) ([]model.NotebookWorkDirSync, error) {
+	recs := []model.NotebookWorkDirSync{}
+	q := db.Bun().NewSelect().Model(&recs).
+		Where("workspace_id = ?", workspaceID).
+		Order("id ASC")
+	if userID != nil {
+		q = q.Where("user_id = ?", *userID)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting notebook sync records for workspace %d: %w", workspaceID, err)
+	}
+	return recs, nil
+}
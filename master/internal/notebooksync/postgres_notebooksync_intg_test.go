@@ -0,0 +1,94 @@
+//go:build integration
+// +build integration
+
+package notebooksync
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestLaunchTwoNotebooksAtOnce(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	tasks := []*model.Task{
+		db.RequireMockTask(t, db.SingleDB(), &user.ID),
+		db.RequireMockTask(t, db.SingleDB(), &user.ID),
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(tasks))
+	launched := make([]bool, len(tasks))
+	for i, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = Launch(ctx, workspaceID, user.ID, func() (model.TaskID, error) {
+				launched[i] = true
+				return task.TaskID, nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	// Exactly one of the two notebooks starts; the other is refused before it launches.
+	winner, loser := 0, 1
+	if errs[0] != nil {
+		winner, loser = 1, 0
+	}
+	require.NoError(t, errs[winner])
+	require.ErrorIs(t, errs[loser], ErrNotebookRunning)
+	require.True(t, launched[winner])
+	require.False(t, launched[loser])
+
+	running, err := RunningTask(ctx, workspaceID, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, running)
+	require.Equal(t, tasks[winner].TaskID, *running)
+	require.NoError(t, RecordSync(ctx, tasks[winner].TaskID, user.ID, time.Now().UTC()))
+
+	// Once the running notebook ends, the user can launch another one.
+	require.NoError(t, db.CompleteTask(ctx, tasks[winner].TaskID, time.Now().UTC()))
+	running, err = RunningTask(ctx, workspaceID, user.ID)
+	require.NoError(t, err)
+	require.Nil(t, running)
+	require.NoError(t, Launch(ctx, workspaceID, user.ID, func() (model.TaskID, error) {
+		return tasks[loser].TaskID, nil
+	}))
+
+	recs, err := ByWorkspace(ctx, workspaceID, &user.ID)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	require.Equal(t, tasks[loser].TaskID, *recs[0].LastTaskID)
+	require.Equal(t, StoragePrefix(workspaceID, user.ID), recs[0].StoragePrefix)
+}
@@ -0,0 +1,175 @@
+// Package pins stores the workspaces and projects users have pinned, in the order each user
+// arranges them, so pins follow users across devices, and the trials pinned within experiments,
+// which everyone who can view an experiment shares.
+package pins
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+// Pin is a pinned workspace or project, as listed to the user who pinned it.
+type Pin struct {
+	// ID is the ID of the workspace or project.
+	ID            int       `bun:"id" json:"id"`
+	Name          string    `bun:"name" json:"name"`
+	WorkspaceID   int       `bun:"workspace_id" json:"workspace_id"`
+	WorkspaceName string    `bun:"workspace_name" json:"workspace_name"`
+	Archived      bool      `bun:"archived" json:"archived"`
+	Position      int       `bun:"position" json:"position"`
+	PinnedAt      time.Time `bun:"pinned_at" json:"pinned_at"`
+}
+
+// Proto converts a pin to its protobuf representation.
+func (p Pin) Proto() *workspacev1.Pin {
+	return &workspacev1.Pin{
+		Id:            int32(p.ID),
+		Name:          p.Name,
+		WorkspaceId:   int32(p.WorkspaceID),
+		WorkspaceName: p.WorkspaceName,
+		Archived:      p.Archived,
+		Position:      int32(p.Position),
+		PinnedAt:      timestamppb.New(p.PinnedAt),
+	}
+}
+
+// InvalidOrderError is returned when reordering pins with IDs that aren't pinned or are repeated.
+type InvalidOrderError struct {
+	ID int
+}
+
+func (e InvalidOrderError) Error() string {
+	return fmt.Sprintf("%d is not pinned or is listed more than once", e.ID)
+}
+
+// Workspaces returns the workspaces a user has pinned, in order.
+func Workspaces(ctx context.Context, userID model.UserID) ([]Pin, error) {
+	pins := []Pin{}
+	err := db.Bun().NewSelect().
+		TableExpr("workspace_pins AS p").
+		Join("JOIN workspaces AS w ON w.id = p.workspace_id").
+		ColumnExpr("w.id, w.name, w.id AS workspace_id, w.name AS workspace_name, w.archived").
+		ColumnExpr("p.position, p.created_at AS pinned_at").
+		Where("p.user_id = ?", userID).
+		Order("p.position", "p.id").
+		Scan(ctx, &pins)
+	if err != nil {
+		return nil, fmt.Errorf("getting pinned workspaces of user %d: %w", userID, err)
+	}
+	return pins, nil
+}
+
+// Projects returns the projects a user has pinned, in order.
+func Projects(ctx context.Context, userID model.UserID) ([]Pin, error) {
+	pins := []Pin{}
+	err := db.Bun().NewSelect().
+		TableExpr("project_pins AS p").
+		Join("JOIN projects AS pr ON pr.id = p.project_id").
+		Join("JOIN workspaces AS w ON w.id = pr.workspace_id").
+		ColumnExpr("pr.id, pr.name, w.id AS workspace_id, w.name AS workspace_name").
+		ColumnExpr("(pr.archived OR w.archived) AS archived").
+		ColumnExpr("p.position, p.created_at AS pinned_at").
+		Where("p.user_id = ?", userID).
+		Order("p.position", "p.id").
+		Scan(ctx, &pins)
+	if err != nil {
+		return nil, fmt.Errorf("getting pinned projects of user %d: %w", userID, err)
+	}
+	return pins, nil
+}
+
+// PinProject pins a project for a user after their other pinned projects. Pinning a project that
+// is already pinned leaves it where it is.
+func PinProject(ctx context.Context, userID model.UserID, projectID int) error {
+	_, err := db.Bun().NewInsert().
+		Model(&model.ProjectPin{ProjectID: projectID, UserID: userID}).
+		On("CONFLICT (user_id, project_id) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("pinning project %d for user %d: %w", projectID, userID, err)
+	}
+	return nil
+}
+
+// UnpinProject unpins a project for a user. Unpinning a project that isn't pinned does nothing.
+func UnpinProject(ctx context.Context, userID model.UserID, projectID int) error {
+	_, err := db.Bun().NewDelete().
+		Model((*model.ProjectPin)(nil)).
+		Where("user_id = ? AND project_id = ?", userID, projectID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("unpinning project %d for user %d: %w", projectID, userID, err)
+	}
+	return nil
+}
+
+// ReorderWorkspaces moves the given pinned workspaces, in the given order, ahead of the user's
+// other pinned workspaces, which keep their order.
+func ReorderWorkspaces(ctx context.Context, userID model.UserID, workspaceIDs []int) error {
+	return reorder(ctx, "workspace_pins", "workspace_id", "user_id", int(userID), workspaceIDs)
+}
+
+// ReorderProjects moves the given pinned projects, in the given order, ahead of the user's other
+// pinned projects, which keep their order.
+func ReorderProjects(ctx context.Context, userID model.UserID, projectIDs []int) error {
+	return reorder(ctx, "project_pins", "project_id", "user_id", int(userID), projectIDs)
+}
+
+// reorder moves the given pins, in the given order, ahead of the other pins with the same value of
+// the scope column, such as the other pins of the same user.
+func reorder(
+	ctx context.Context, table, column, scopeColumn string, scopeID int, ids []int,
+) error {
+	return db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var pinned []int
+		err := tx.NewSelect().
+			Table(table).
+			Column(column).
+			Where("? = ?", bun.Ident(scopeColumn), scopeID).
+			OrderExpr("position, ?", bun.Ident(column)).
+			For("UPDATE").
+			Scan(ctx, &pinned)
+		if err != nil {
+			return fmt.Errorf("getting pins of %s %d: %w", scopeColumn, scopeID, err)
+		}
+
+		unlisted := make(map[int]bool, len(pinned))
+		for _, id := range pinned {
+			unlisted[id] = true
+		}
+		order := make([]int, 0, len(pinned))
+		for _, id := range ids {
+			if !unlisted[id] {
+				return InvalidOrderError{ID: id}
+			}
+			unlisted[id] = false
+			order = append(order, id)
+		}
+		for _, id := range pinned {
+			if unlisted[id] {
+				order = append(order, id)
+			}
+		}
+
+		for i, id := range order {
+			_, err := tx.NewUpdate().
+				Table(table).
+				Set("position = ?", i+1).
+				Where("? = ?", bun.Ident(scopeColumn), scopeID).
+				Where("? = ?", bun.Ident(column), id).
+				Exec(ctx)
+			if err != nil {
+				return fmt.Errorf("reordering pins of %s %d: %w", scopeColumn, scopeID, err)
+			}
+		}
+		return nil
+	})
+}
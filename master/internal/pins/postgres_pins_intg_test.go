@@ -0,0 +1,190 @@
+//go:build integration
+// +build integration
+
+package pins
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func pinIDs(ps []Pin) []int {
+	ids := []int{}
+	for _, p := range ps {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestProjectPins(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	var projectIDs []int
+	for i := 0; i < 3; i++ {
+		projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+		projectIDs = append(projectIDs, projectID)
+	}
+
+	for _, id := range projectIDs {
+		require.NoError(t, PinProject(ctx, user.ID, id))
+	}
+	require.NoError(t, PinProject(ctx, user.ID, projectIDs[0]), "pinning twice is allowed")
+	pinned, err := Projects(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, projectIDs, pinIDs(pinned))
+	require.Equal(t, workspaceID, pinned[0].WorkspaceID)
+
+	require.NoError(t, ReorderProjects(ctx, user.ID, []int{projectIDs[2]}))
+	pinned, err = Projects(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int{projectIDs[2], projectIDs[0], projectIDs[1]}, pinIDs(pinned))
+
+	require.ErrorAs(t, ReorderProjects(ctx, user.ID, []int{projectIDs[1], projectIDs[1]}),
+		&InvalidOrderError{})
+	require.ErrorAs(t, ReorderProjects(ctx, user.ID, []int{-1}), &InvalidOrderError{})
+
+	require.NoError(t, UnpinProject(ctx, user.ID, projectIDs[0]))
+	require.NoError(t, UnpinProject(ctx, user.ID, projectIDs[0]), "unpinning twice is allowed")
+	require.NoError(t, PinProject(ctx, user.ID, projectIDs[0]))
+	pinned, err = Projects(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int{projectIDs[2], projectIDs[1], projectIDs[0]}, pinIDs(pinned))
+
+	other := db.RequireMockUser(t, db.SingleDB())
+	pinned, err = Projects(ctx, other.ID)
+	require.NoError(t, err)
+	require.Empty(t, pinned)
+}
+
+func TestWorkspacePins(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	var workspaceIDs []int
+	for i := 0; i < 3; i++ {
+		workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+		_, err := db.Bun().NewInsert().
+			Model(&model.WorkspacePin{WorkspaceID: workspaceID, UserID: user.ID}).
+			Exec(ctx)
+		require.NoError(t, err)
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+
+	pinned, err := Workspaces(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, workspaceIDs, pinIDs(pinned))
+
+	require.NoError(t, ReorderWorkspaces(ctx, user.ID, []int{workspaceIDs[1], workspaceIDs[0]}))
+	pinned, err = Workspaces(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int{workspaceIDs[1], workspaceIDs[0], workspaceIDs[2]}, pinIDs(pinned))
+}
+
+func trialPinIDs(ps []TrialPin) []int {
+	ids := []int{}
+	for _, p := range ps {
+		ids = append(ids, p.TrialID)
+	}
+	return ids
+}
+
+func TestTrialPins(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	exp := db.RequireMockExperiment(t, db.SingleDB(), user)
+	var trialIDs []int
+	for i := 0; i < 3; i++ {
+		trialIDs = append(trialIDs, db.RequireMockTrialID(t, db.SingleDB(), exp))
+	}
+
+	for _, id := range trialIDs {
+		require.NoError(t, PinTrial(ctx, exp.ID, id, user.ID))
+	}
+	require.NoError(t, PinTrial(ctx, exp.ID, trialIDs[0], user.ID), "pinning twice is allowed")
+	pinned, err := Trials(ctx, exp.ID)
+	require.NoError(t, err)
+	require.Equal(t, trialIDs, trialPinIDs(pinned))
+	require.Equal(t, user.Username, *pinned[0].PinnedBy)
+	require.Equal(t, model.ActiveState, pinned[0].State)
+
+	require.NoError(t, ReorderTrials(ctx, exp.ID, []int{trialIDs[2]}))
+	pinned, err = Trials(ctx, exp.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int{trialIDs[2], trialIDs[0], trialIDs[1]}, trialPinIDs(pinned))
+	require.ErrorAs(t, ReorderTrials(ctx, exp.ID, []int{trialIDs[1], trialIDs[1]}),
+		&InvalidOrderError{})
+
+	require.NoError(t, UnpinTrial(ctx, exp.ID, trialIDs[0]))
+	require.NoError(t, UnpinTrial(ctx, exp.ID, trialIDs[0]), "unpinning twice is allowed")
+	require.NoError(t, PinTrial(ctx, exp.ID, trialIDs[0], user.ID))
+	pinned, err = Trials(ctx, exp.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int{trialIDs[2], trialIDs[1], trialIDs[0]}, trialPinIDs(pinned))
+
+	other := db.RequireMockExperiment(t, db.SingleDB(), user)
+	pinned, err = Trials(ctx, other.ID)
+	require.NoError(t, err)
+	require.Empty(t, pinned)
+}
+
+func TestTrialPinsCap(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	exp := db.RequireMockExperiment(t, db.SingleDB(), user)
+	var trialIDs []int
+	for i := 0; i < MaxTrialPins+1; i++ {
+		trialIDs = append(trialIDs, db.RequireMockTrialID(t, db.SingleDB(), exp))
+	}
+	for _, id := range trialIDs[:MaxTrialPins-1] {
+		require.NoError(t, PinTrial(ctx, exp.ID, id, user.ID))
+	}
+
+	// Pins made at the same time are counted one after the other, so only one of these fits.
+	errs := make(chan error, 2)
+	for _, id := range trialIDs[MaxTrialPins-1:] {
+		go func() {
+			errs <- PinTrial(ctx, exp.ID, id, user.ID)
+		}()
+	}
+	failed := 0
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			require.ErrorIs(t, err, db.ErrInvalidInput)
+			failed++
+		}
+	}
+	require.Equal(t, 1, failed)
+	pinned, err := Trials(ctx, exp.ID)
+	require.NoError(t, err)
+	require.Len(t, pinned, MaxTrialPins)
+
+	// Pinning a trial that is already pinned doesn't count against the cap.
+	require.NoError(t, PinTrial(ctx, exp.ID, trialIDs[0], user.ID))
+}
@@ -0,0 +1,119 @@
+package pins
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// MaxTrialPins caps how many trials may be pinned in an experiment, so pinned trials stay a
+// curated view of a search.
+const MaxTrialPins = 100
+
+// TrialPin is a pinned trial, with a summary of its progress.
+type TrialPin struct {
+	TrialID      int         `bun:"trial_id" json:"trial_id"`
+	State        model.State `bun:"state" json:"state"`
+	TotalBatches int         `bun:"total_batches" json:"total_batches"`
+	// SearcherMetricValue is the trial's best value of the experiment's searcher metric.
+	SearcherMetricValue *float64  `bun:"searcher_metric_value" json:"searcher_metric_value"`
+	Position            int       `bun:"position" json:"position"`
+	PinnedBy            *string   `bun:"pinned_by" json:"pinned_by"`
+	PinnedAt            time.Time `bun:"pinned_at" json:"pinned_at"`
+}
+
+// Proto converts a pinned trial to its protobuf representation.
+func (p TrialPin) Proto() *trialv1.TrialPin {
+	return &trialv1.TrialPin{
+		TrialId:             int32(p.TrialID),
+		State:               trialv1.State(trialv1.State_value["STATE_"+string(p.State)]),
+		TotalBatches:        int32(p.TotalBatches),
+		SearcherMetricValue: p.SearcherMetricValue,
+		Position:            int32(p.Position),
+		PinnedBy:            p.PinnedBy,
+		PinnedAt:            timestamppb.New(p.PinnedAt),
+	}
+}
+
+// Trials returns the pinned trials of an experiment, in order.
+func Trials(ctx context.Context, experimentID int) ([]TrialPin, error) {
+	pins := []TrialPin{}
+	err := db.Bun().NewSelect().
+		TableExpr("trial_pins AS p").
+		Join("JOIN trials AS t ON t.id = p.trial_id").
+		Join("LEFT JOIN users AS u ON u.id = p.pinned_by").
+		ColumnExpr("p.trial_id, t.state, t.total_batches, t.searcher_metric_value").
+		ColumnExpr("p.position, u.username AS pinned_by, p.created_at AS pinned_at").
+		Where("p.experiment_id = ?", experimentID).
+		Order("p.position", "p.trial_id").
+		Scan(ctx, &pins)
+	if err != nil {
+		return nil, fmt.Errorf("getting pinned trials of experiment %d: %w", experimentID, err)
+	}
+	return pins, nil
+}
+
+// PinTrial pins a trial within its experiment after the experiment's other pinned trials. Pinning
+// a trial that is already pinned leaves it where it is.
+func PinTrial(ctx context.Context, experimentID, trialID int, userID model.UserID) error {
+	return db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Locking the experiment makes concurrent pins in it wait for each other, so they can't
+		// all see room under the cap.
+		if _, err := tx.NewSelect().
+			Table("experiments").
+			Column("id").
+			Where("id = ?", experimentID).
+			For("UPDATE").
+			Exec(ctx); err != nil {
+			return fmt.Errorf("locking experiment %d: %w", experimentID, err)
+		}
+		count, err := tx.NewSelect().
+			Model((*model.TrialPin)(nil)).
+			Where("experiment_id = ?", experimentID).
+			Where("trial_id != ?", trialID).
+			Count(ctx)
+		if err != nil {
+			return fmt.Errorf("counting pinned trials of experiment %d: %w", experimentID, err)
+		}
+		if count >= MaxTrialPins {
+			return fmt.Errorf("%w: experiment %d already has %d pinned trials",
+				db.ErrInvalidInput, experimentID, MaxTrialPins)
+		}
+
+		_, err = tx.NewInsert().
+			Model(&model.TrialPin{TrialID: trialID, ExperimentID: experimentID, PinnedBy: &userID}).
+			Value("position", "(SELECT COALESCE(MAX(position), 0) + 1 FROM trial_pins "+
+				"WHERE experiment_id = ?)", experimentID).
+			On("CONFLICT (trial_id) DO NOTHING").
+			Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("pinning trial %d: %w", trialID, err)
+		}
+		return nil
+	})
+}
+
+// UnpinTrial unpins a trial. Unpinning a trial that isn't pinned does nothing.
+func UnpinTrial(ctx context.Context, experimentID, trialID int) error {
+	_, err := db.Bun().NewDelete().
+		Model((*model.TrialPin)(nil)).
+		Where("experiment_id = ? AND trial_id = ?", experimentID, trialID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("unpinning trial %d: %w", trialID, err)
+	}
+	return nil
+}
+
+// ReorderTrials moves the given pinned trials, in the given order, ahead of the experiment's other
+// pinned trials, which keep their order.
+func ReorderTrials(ctx context.Context, experimentID int, trialIDs []int) error {
+	return reorder(ctx, "trial_pins", "trial_id", "experiment_id", experimentID, trialIDs)
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/internal/usergroup"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -63,9 +64,15 @@ type IDTokenClaims struct {
 
 var errNotProvisioned = echo.NewHTTPError(http.StatusNotFound, "user has not been provisioned")
 
+// egressContext returns a context that makes discovery, token exchange and user info requests to
+// the provider through the egress proxy.
+func egressContext(ctx context.Context) context.Context {
+	return oidc.ClientContext(ctx, egress.Client())
+}
+
 // New initiates an OIDC Service.
 func New(db *db.PgDB, config config.OIDCConfig, pachEnabled bool) (*Service, error) {
-	ctx := context.Background()
+	ctx := egressContext(context.Background())
 
 	provider, err := oidc.NewProvider(ctx, config.IDPSSOURL)
 	if err != nil {
@@ -122,7 +129,8 @@ func (s *Service) callback(c echo.Context) error {
 	if !ok {
 		return errors.Wrap(err, "failed to get raw ID token from oauth2token")
 	}
-	userInfo, err := s.provider.UserInfo(c.Request().Context(), oauth2.StaticTokenSource(oauth2token))
+	userInfo, err := s.provider.UserInfo(egressContext(c.Request().Context()),
+		oauth2.StaticTokenSource(oauth2token))
 	if err != nil {
 		return fmt.Errorf("failed to get user info from oidc provider: %w", err)
 	}
@@ -208,9 +216,9 @@ func (s *Service) getOauthToken(c echo.Context) (*oauth2.Token, error) {
 	if relayParam == cliRelayState || relayParam == deprecatedCliRelayState {
 		configCopy := s.oauth2Config
 		configCopy.RedirectURL = fmt.Sprintf("%s?relayState=%s", configCopy.RedirectURL, relayParam)
-		tok, err = configCopy.Exchange(c.Request().Context(), c.QueryParam("code"))
+		tok, err = configCopy.Exchange(egressContext(c.Request().Context()), c.QueryParam("code"))
 	} else {
-		tok, err = s.oauth2Config.Exchange(c.Request().Context(), c.QueryParam("code"))
+		tok, err = s.oauth2Config.Exchange(egressContext(c.Request().Context()), c.QueryParam("code"))
 	}
 	if err != nil {
 		if strings.Contains(err.Error(), "The authorization code is invalid or has expired.") {
@@ -0,0 +1,238 @@
+// Package proxyauth authenticates requests by the identity a trusted authenticating proxy, like
+// oauth2-proxy or Pomerium, asserts in a signed header.
+package proxyauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"gopkg.in/guregu/null.v3"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/usergroup"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// syncInterval is how long a user's session, display name and groups are trusted before they are
+// checked against the proxy's identity again.
+const syncInterval = time.Minute
+
+const (
+	// sessionTTL is how long the session of a user who stopped making requests is remembered.
+	// Later requests of the user start a new session.
+	sessionTTL = time.Hour
+	// maxSessions is how many sessions are remembered at once. Past it, the session of the user
+	// who made a request least recently is forgotten to make room for a new one.
+	maxSessions = 10000
+)
+
+var (
+	errNotProvisioned = errors.New("user has not been provisioned")
+	errInactive       = errors.New("user is not active")
+)
+
+// session is a master session started on behalf of a user the proxy authenticated.
+type session struct {
+	token    string
+	syncedAt time.Time
+}
+
+// Service authenticates requests from a trusted proxy.
+type Service struct {
+	config   config.ProxyAuthConfig
+	verifier verifier
+
+	mu          sync.Mutex
+	sessions    map[string]session
+	maxSessions int
+	sweptAt     time.Time
+}
+
+// New returns a Service for the given configuration.
+func New(c config.ProxyAuthConfig) *Service {
+	return &Service{
+		config:      c,
+		verifier:    newVerifier(c),
+		sessions:    map[string]session{},
+		maxSessions: maxSessions,
+		sweptAt:     time.Now(),
+	}
+}
+
+// ProcessAuthentication is middleware that authenticates requests carrying the proxy's signed
+// header as the user it names, by replacing the request's credentials with a session of that
+// user. Requests without the header fall through to the usual authentication.
+func (s *Service) ProcessAuthentication(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		raw := c.Request().Header.Get(s.config.Header)
+		if raw == "" {
+			return next(c)
+		}
+
+		token, err := s.sessionToken(c.Request().Context(), strings.TrimPrefix(raw, "Bearer "))
+		switch {
+		case errors.Is(err, errNotProvisioned), errors.Is(err, errInactive):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		case err != nil:
+			log.WithError(err).Debug("rejecting request with invalid proxy identity")
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid proxy identity")
+		}
+		c.Request().Header.Set("Authorization", "Bearer "+token)
+		return next(c)
+	}
+}
+
+// sessionToken returns the token of a session of the user a proxy's JWT names, provisioning and
+// syncing the user as configured.
+func (s *Service) sessionToken(ctx context.Context, raw string) (string, error) {
+	claims, err := s.verifier.verify(ctx, raw)
+	if err != nil {
+		return "", err
+	}
+	id, err := identityFromClaims(s.config, claims)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	cached, ok := s.sessions[id.Username]
+	s.mu.Unlock()
+	if ok && time.Since(cached.syncedAt) < syncInterval {
+		return cached.token, nil
+	}
+
+	u, err := s.syncUser(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if !u.Active {
+		return "", errInactive
+	}
+
+	// Reuse the previous session unless it has expired or the user logged out of it.
+	token := cached.token
+	if !ok || !sessionOf(ctx, token, u) {
+		if token, err = user.StartSession(ctx, u); err != nil {
+			return "", fmt.Errorf("starting session for %q: %w", u.Username, err)
+		}
+	}
+
+	s.putSession(id.Username, session{token: token, syncedAt: time.Now()})
+	return token, nil
+}
+
+// putSession remembers the session of a user, forgetting stale sessions and, if there are still
+// too many, the one synced least recently.
+func (s *Service) putSession(username string, sess session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(sess.syncedAt)
+	if _, ok := s.sessions[username]; !ok && len(s.sessions) >= s.maxSessions {
+		var oldest string
+		for name, cached := range s.sessions {
+			if oldest == "" || cached.syncedAt.Before(s.sessions[oldest].syncedAt) {
+				oldest = name
+			}
+		}
+		delete(s.sessions, oldest)
+	}
+	s.sessions[username] = sess
+}
+
+// sweep forgets sessions older than sessionTTL, at most once a minute.
+func (s *Service) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < time.Minute {
+		return
+	}
+	s.sweptAt = now
+	for name, cached := range s.sessions {
+		if now.Sub(cached.syncedAt) > sessionTTL {
+			delete(s.sessions, name)
+		}
+	}
+}
+
+// sessionOf returns whether token is a live session of u.
+func sessionOf(ctx context.Context, token string, u *model.User) bool {
+	extConfig := config.GetMasterConfig().InternalConfig.ExternalSessions
+	sessionUser, _, err := user.ByToken(ctx, token, &extConfig)
+	return err == nil && sessionUser.ID == u.ID
+}
+
+// syncUser returns the user named by an identity, provisioning them if configured to, and syncs
+// their display name and group memberships.
+func (s *Service) syncUser(ctx context.Context, id *identity) (*model.User, error) {
+	u, err := user.ByUsername(ctx, id.Username)
+	switch {
+	case errors.Is(err, db.ErrNotFound) && s.config.AutoProvisionUsers:
+		return s.provisionUser(ctx, id)
+	case errors.Is(err, db.ErrNotFound):
+		return nil, errNotProvisioned
+	case err != nil:
+		return nil, err
+	}
+
+	updateDisplayName := s.config.AutoProvisionUsers && id.DisplayName != "" &&
+		id.DisplayName != u.DisplayName.String
+	if !updateDisplayName && id.Groups == nil {
+		return u, nil
+	}
+	if err := db.Bun().RunInTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable},
+		func(ctx context.Context, tx bun.Tx) error {
+			if updateDisplayName {
+				if _, err := tx.NewUpdate().Model(&model.User{
+					DisplayName: null.NewString(id.DisplayName, true),
+				}).Column("display_name").Where("id = ?", u.ID).Exec(ctx); err != nil {
+					return fmt.Errorf("setting display name of %q: %w", u.Username, err)
+				}
+			}
+			if id.Groups != nil {
+				if err := usergroup.UpdateUserGroupMembershipTx(ctx, tx, u, id.Groups); err != nil {
+					return fmt.Errorf("updating group membership of %q: %w", u.Username, err)
+				}
+			}
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	return user.ByUsername(ctx, u.Username)
+}
+
+// provisionUser creates a remote user with no password for an identity the proxy vouches for.
+func (s *Service) provisionUser(ctx context.Context, id *identity) (*model.User, error) {
+	u := model.User{
+		Username:     id.Username,
+		DisplayName:  null.NewString(id.DisplayName, id.DisplayName != ""),
+		PasswordHash: model.NoPasswordLogin,
+		Active:       true,
+		Remote:       true,
+	}
+	if err := db.Bun().RunInTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable},
+		func(ctx context.Context, tx bun.Tx) error {
+			if _, err := user.AddUserTx(ctx, tx, &u); err != nil {
+				return fmt.Errorf("provisioning %q: %w", id.Username, err)
+			}
+			if id.Groups != nil {
+				if err := usergroup.UpdateUserGroupMembershipTx(ctx, tx, &u, id.Groups); err != nil {
+					return fmt.Errorf("updating group membership of %q: %w", u.Username, err)
+				}
+			}
+			return nil
+		}); err != nil {
+		return nil, err
+	}
+	log.Infof("provisioned user %q authenticated by proxy", u.Username)
+	return user.ByUsername(ctx, u.Username)
+}
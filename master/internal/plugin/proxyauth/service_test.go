@@ -0,0 +1,32 @@
+package proxyauth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutSession(t *testing.T) {
+	now := time.Now()
+	s := &Service{sessions: map[string]session{}, maxSessions: 2, sweptAt: now}
+	s.putSession("a", session{token: "a", syncedAt: now})
+	s.putSession("b", session{token: "b", syncedAt: now.Add(time.Second)})
+
+	// Past the cap, the session synced least recently is forgotten.
+	s.putSession("c", session{token: "c", syncedAt: now.Add(2 * time.Second)})
+	require.Len(t, s.sessions, 2)
+	require.NotContains(t, s.sessions, "a")
+
+	// Updating a remembered session doesn't forget another.
+	s.putSession("b", session{token: "b2", syncedAt: now.Add(3 * time.Second)})
+	require.Len(t, s.sessions, 2)
+	require.Equal(t, "b2", s.sessions["b"].token)
+
+	// Stale sessions are swept.
+	later := now.Add(sessionTTL + 2*time.Second + time.Millisecond)
+	s.putSession("d", session{token: "d", syncedAt: later})
+	require.Len(t, s.sessions, 2)
+	require.NotContains(t, s.sessions, "c")
+	require.Contains(t, s.sessions, "b")
+}
@@ -0,0 +1,141 @@
+package proxyauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+// errNoIdentity is returned when a JWT is valid but doesn't name a user.
+var errNoIdentity = errors.New("proxy JWT has no username claim")
+
+// verifier checks the signature and standard claims of a proxy's JWT and returns its claims.
+type verifier interface {
+	verify(ctx context.Context, raw string) (map[string]any, error)
+}
+
+func newVerifier(c config.ProxyAuthConfig) verifier {
+	if c.SharedSecret != "" {
+		return &hmacVerifier{secret: []byte(c.SharedSecret), issuer: c.Issuer, audience: c.Audience}
+	}
+	return &jwksVerifier{oidc.NewVerifier(c.Issuer,
+		oidc.NewRemoteKeySet(context.Background(), c.JWKSURL),
+		&oidc.Config{
+			ClientID:          c.Audience,
+			SkipClientIDCheck: c.Audience == "",
+			SkipIssuerCheck:   c.Issuer == "",
+			SupportedSigningAlgs: []string{
+				oidc.RS256, oidc.RS384, oidc.RS512, oidc.ES256, oidc.ES384, oidc.ES512,
+				oidc.PS256, oidc.PS384, oidc.PS512, oidc.EdDSA,
+			},
+		},
+	)}
+}
+
+// hmacVerifier verifies JWTs signed with a secret shared with the proxy.
+type hmacVerifier struct {
+	secret   []byte
+	issuer   string
+	audience string
+}
+
+func (v *hmacVerifier) verify(_ context.Context, raw string) (map[string]any, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}))
+	if _, err := parser.ParseWithClaims(raw, claims, func(*jwt.Token) (any, error) {
+		return v.secret, nil
+	}); err != nil {
+		return nil, fmt.Errorf("verifying proxy JWT: %w", err)
+	}
+
+	// Proxies issue short-lived JWTs, so require an expiry to limit replays.
+	switch {
+	case !claims.VerifyExpiresAt(time.Now().Unix(), true):
+		return nil, errors.New("verifying proxy JWT: missing or past expiry")
+	case v.issuer != "" && !claims.VerifyIssuer(v.issuer, true):
+		return nil, fmt.Errorf("verifying proxy JWT: issuer isn't %q", v.issuer)
+	case v.audience != "" && !claims.VerifyAudience(v.audience, true):
+		return nil, fmt.Errorf("verifying proxy JWT: audience isn't %q", v.audience)
+	}
+	return claims, nil
+}
+
+// jwksVerifier verifies JWTs signed with keys the proxy publishes, like Pomerium does.
+type jwksVerifier struct {
+	v *oidc.IDTokenVerifier
+}
+
+func (v *jwksVerifier) verify(ctx context.Context, raw string) (map[string]any, error) {
+	token, err := v.v.Verify(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("verifying proxy JWT: %w", err)
+	}
+	claims := map[string]any{}
+	if err := token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("parsing proxy JWT claims: %w", err)
+	}
+	return claims, nil
+}
+
+// identity is who a proxy says a request is from.
+type identity struct {
+	Username    string
+	DisplayName string
+	// Groups is nil when group memberships aren't synced.
+	Groups []string
+}
+
+// identityFromClaims reads the identity of a user from verified JWT claims.
+func identityFromClaims(c config.ProxyAuthConfig, claims map[string]any) (*identity, error) {
+	username, _ := claims[c.UsernameClaim].(string)
+	if username == "" {
+		return nil, errNoIdentity
+	}
+	id := &identity{Username: username}
+	if c.DisplayNameClaim != "" {
+		id.DisplayName, _ = claims[c.DisplayNameClaim].(string)
+	}
+	if c.GroupsClaim == "" {
+		return id, nil
+	}
+
+	id.Groups = []string{}
+	var groups []string
+	switch gs := claims[c.GroupsClaim].(type) {
+	case nil:
+	case string:
+		// Some proxies pass groups as a comma-separated string.
+		for _, g := range strings.Split(gs, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				groups = append(groups, g)
+			}
+		}
+	case []any:
+		for _, g := range gs {
+			s, ok := g.(string)
+			if !ok {
+				return nil, fmt.Errorf("proxy JWT claim %s has a non-string group", c.GroupsClaim)
+			}
+			groups = append(groups, s)
+		}
+	default:
+		return nil, fmt.Errorf("proxy JWT claim %s isn't a list of groups", c.GroupsClaim)
+	}
+	for _, g := range groups {
+		if len(c.GroupMapping) > 0 {
+			var ok bool
+			if g, ok = c.GroupMapping[g]; !ok {
+				continue
+			}
+		}
+		id.Groups = append(id.Groups, g)
+	}
+	return id, nil
+}
@@ -0,0 +1,93 @@
+package proxyauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+func signed(t *testing.T, secret string, claims jwt.MapClaims) string {
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return raw
+}
+
+func TestHMACVerifier(t *testing.T) {
+	v := newVerifier(config.ProxyAuthConfig{
+		SharedSecret: "secret",
+		Issuer:       "proxy",
+		Audience:     "determined",
+	})
+	exp := time.Now().Add(time.Minute).Unix()
+
+	cases := []struct {
+		name   string
+		secret string
+		claims jwt.MapClaims
+		ok     bool
+	}{
+		{"valid", "secret", jwt.MapClaims{"iss": "proxy", "aud": "determined", "exp": exp}, true},
+		{"wrong secret", "other", jwt.MapClaims{"iss": "proxy", "aud": "determined", "exp": exp}, false},
+		{"no expiry", "secret", jwt.MapClaims{"iss": "proxy", "aud": "determined"}, false},
+		{"expired", "secret", jwt.MapClaims{
+			"iss": "proxy", "aud": "determined", "exp": time.Now().Add(-time.Minute).Unix(),
+		}, false},
+		{"wrong issuer", "secret", jwt.MapClaims{"iss": "other", "aud": "determined", "exp": exp}, false},
+		{"wrong audience", "secret", jwt.MapClaims{"iss": "proxy", "aud": "other", "exp": exp}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := v.verify(context.Background(), signed(t, tc.secret, tc.claims))
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "proxy", claims["iss"])
+		})
+	}
+
+	// Unsigned tokens are never accepted.
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"exp": exp}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = v.verify(context.Background(), raw)
+	require.Error(t, err)
+}
+
+func TestIdentityFromClaims(t *testing.T) {
+	c := config.ProxyAuthConfig{UsernameClaim: "email", DisplayNameClaim: "name"}
+
+	_, err := identityFromClaims(c, map[string]any{"name": "Ada"})
+	require.ErrorIs(t, err, errNoIdentity)
+
+	id, err := identityFromClaims(c, map[string]any{
+		"email": "ada@example.com", "name": "Ada", "groups": []any{"ml"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &identity{Username: "ada@example.com", DisplayName: "Ada"}, id)
+
+	c.GroupsClaim = "groups"
+	id, err = identityFromClaims(c, map[string]any{"email": "ada@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, []string{}, id.Groups)
+
+	id, err = identityFromClaims(c, map[string]any{"email": "ada@example.com", "groups": "ml, infra,"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ml", "infra"}, id.Groups)
+
+	c.GroupMapping = map[string]string{"ml": "ml-team"}
+	id, err = identityFromClaims(c, map[string]any{
+		"email": "ada@example.com", "groups": []any{"ml", "infra"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ml-team"}, id.Groups)
+
+	_, err = identityFromClaims(c, map[string]any{"email": "ada@example.com", "groups": 3.0})
+	require.Error(t, err)
+}
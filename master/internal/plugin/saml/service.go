@@ -23,6 +23,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/internal/proxy"
 	"github.com/determined-ai/determined/master/internal/user"
 	"github.com/determined-ai/determined/master/internal/usergroup"
@@ -85,7 +86,7 @@ func New(db *db.PgDB, c config.SAMLConfig) (*Service, error) {
 	if err != nil {
 		return nil, err
 	}
-	idpMetadata, err := samlsp.FetchMetadata(context.Background(), http.DefaultClient,
+	idpMetadata, err := samlsp.FetchMetadata(context.Background(), egress.Client(),
 		*idpMetadataURL)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,17 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+// CheckUnarchived returns an error if a project is archived. Archived projects are read-only:
+// everything in them can still be viewed, but they can't be edited and no experiments can be
+// created in or moved into or out of them until they are unarchived.
+func CheckUnarchived(project *projectv1.Project) error {
+	if project.Archived {
+		return fmt.Errorf("project (%d) is archived and read-only", project.Id)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+func TestCheckUnarchived(t *testing.T) {
+	require.NoError(t, CheckUnarchived(&projectv1.Project{Id: 3}))
+	require.ErrorContains(t, CheckUnarchived(&projectv1.Project{Id: 3, Archived: true}),
+		"project (3) is archived")
+}
@@ -0,0 +1,68 @@
+//go:build integration
+// +build integration
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/authz"
+	internaldb "github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/projectv1"
+)
+
+const (
+	workspaceAdminRoleID = 2
+	viewerRoleID         = 4
+	editorRoleID         = 5
+)
+
+func TestArchivedProjectAuthZ(t *testing.T) {
+	require.NoError(t, etc.SetRootPath(internaldb.RootFromDB))
+	testDB, closeDB := internaldb.MustResolveTestPostgres(t)
+	defer closeDB()
+	internaldb.MustMigrateTestPostgres(t, testDB, internaldb.MigrationsFromDB)
+
+	ctx := context.Background()
+	workspaceID, _ := internaldb.RequireMockWorkspaceID(t, testDB, "")
+	projectID, _ := internaldb.RequireMockProjectID(t, testDB, workspaceID, true)
+	archived := &projectv1.Project{
+		Id: int32(projectID), WorkspaceId: int32(workspaceID), Archived: true,
+	}
+
+	workspaceAdmin := internaldb.RequireMockUser(t, testDB)
+	editor := internaldb.RequireMockUser(t, testDB)
+	viewer := internaldb.RequireMockUser(t, testDB)
+	internaldb.RequireMockWorkspaceRole(t, testDB, workspaceAdmin.ID, workspaceID,
+		workspaceAdminRoleID)
+	internaldb.RequireMockWorkspaceRole(t, testDB, editor.ID, workspaceID, editorRoleID)
+	internaldb.RequireMockWorkspaceRole(t, testDB, viewer.ID, workspaceID, viewerRoleID)
+
+	a := &ProjectAuthZRBAC{}
+
+	t.Run("unarchiving requires UPDATE_WORKSPACE", func(t *testing.T) {
+		require.NoError(t, a.CanUnarchiveProject(ctx, workspaceAdmin, archived))
+		// Editors can update the project, but not its workspace.
+		require.True(t, authz.IsPermissionDenied(a.CanUnarchiveProject(ctx, editor, archived)))
+		require.True(t, authz.IsPermissionDenied(a.CanUnarchiveProject(ctx, viewer, archived)))
+	})
+
+	t.Run("archived projects are read-only", func(t *testing.T) {
+		for _, check := range []func(context.Context, model.User, *projectv1.Project) error{
+			a.CanSetProjectName, a.CanSetProjectDescription, a.CanSetProjectNotes,
+			a.CanSetProjectKey,
+		} {
+			require.ErrorContains(t, check(ctx, editor, archived), "archived")
+
+			// Users without permission are denied without learning that the project is archived.
+			err := check(ctx, viewer, archived)
+			require.True(t, authz.IsPermissionDenied(err), err)
+			require.NotContains(t, err.Error(), "archived")
+		}
+	})
+}
@@ -29,11 +29,11 @@ func (a *ProjectAuthZBasic) CanCreateProject(
 	return nil
 }
 
-// CanSetProjectNotes always returns nil for basic auth.
+// CanSetProjectNotes returns an error if the project is archived.
 func (a *ProjectAuthZBasic) CanSetProjectNotes(
 	ctx context.Context, curUser model.User, project *projectv1.Project,
 ) error {
-	return nil
+	return CheckUnarchived(project)
 }
 
 func shouldBeAdminOrOwnWorkspaceOrProject(
@@ -43,22 +43,40 @@ func shouldBeAdminOrOwnWorkspaceOrProject(
 	if curUser.Admin || curUser.ID == model.UserID(project.UserId) {
 		return nil
 	}
-	// Is owner of the workspace?
-	type workspace struct {
-		bun.BaseModel `bun:"table:workspaces"`
-	}
-	exists, err := db.Bun().NewSelect().Model((*workspace)(nil)).
-		Where("id = ?", project.WorkspaceId).
-		Where("user_id = ?", curUser.ID).Exists(context.TODO())
+	owns, err := ownsWorkspace(curUser, project)
 	if err != nil {
 		return err
 	}
-	if !exists {
+	if !owns {
 		return fmt.Errorf("non admin users need to own the project or workspace")
 	}
 	return nil
 }
 
+func shouldBeAdminOrOwnWorkspace(curUser model.User, project *projectv1.Project) error {
+	if curUser.Admin {
+		return nil
+	}
+	owns, err := ownsWorkspace(curUser, project)
+	if err != nil {
+		return err
+	}
+	if !owns {
+		return fmt.Errorf("non admin users need to own the workspace")
+	}
+	return nil
+}
+
+// ownsWorkspace returns whether the user owns the project's workspace.
+func ownsWorkspace(curUser model.User, project *projectv1.Project) (bool, error) {
+	type workspace struct {
+		bun.BaseModel `bun:"table:workspaces"`
+	}
+	return db.Bun().NewSelect().Model((*workspace)(nil)).
+		Where("id = ?", project.WorkspaceId).
+		Where("user_id = ?", curUser.ID).Exists(context.TODO())
+}
+
 // CanSetProjectName returns an error if a non admin isn't the owner of the project or workspace.
 func (a *ProjectAuthZBasic) CanSetProjectName(
 	ctx context.Context, curUser model.User, project *projectv1.Project,
@@ -66,7 +84,7 @@ func (a *ProjectAuthZBasic) CanSetProjectName(
 	if err := shouldBeAdminOrOwnWorkspaceOrProject(curUser, project); err != nil {
 		return fmt.Errorf("can't set project name: %w", err)
 	}
-	return nil
+	return CheckUnarchived(project)
 }
 
 // CanSetProjectDescription returns an error if a non admin
@@ -77,7 +95,36 @@ func (a *ProjectAuthZBasic) CanSetProjectDescription(
 	if err := shouldBeAdminOrOwnWorkspaceOrProject(curUser, project); err != nil {
 		return fmt.Errorf("can't set project name: %w", err)
 	}
-	return nil
+	return CheckUnarchived(project)
+}
+
+// CanSetProjectExperimentDefaults returns an error if a non admin isn't the owner of the
+// project's workspace.
+func (a *ProjectAuthZBasic) CanSetProjectExperimentDefaults(
+	ctx context.Context, curUser model.User, project *projectv1.Project,
+) error {
+	if !curUser.Admin {
+		owns, err := ownsWorkspace(curUser, project)
+		if err != nil {
+			return err
+		}
+		if !owns {
+			return fmt.Errorf("can't set project experiment defaults: " +
+				"non admin users need to own the project's workspace")
+		}
+	}
+	return CheckUnarchived(project)
+}
+
+// CanSetProjectMetadataSchemas returns an error if a non admin isn't the owner of the project or
+// workspace.
+func (a *ProjectAuthZBasic) CanSetProjectMetadataSchemas(
+	ctx context.Context, curUser model.User, project *projectv1.Project,
+) error {
+	if err := shouldBeAdminOrOwnWorkspaceOrProject(curUser, project); err != nil {
+		return fmt.Errorf("can't set project metadata schemas: %w", err)
+	}
+	return CheckUnarchived(project)
 }
 
 // CanDeleteProject returns an error if a non admin isn't the owner of the project or workspace.
@@ -110,6 +157,11 @@ func (a *ProjectAuthZBasic) CanMoveProjectExperiments(
 	if !curUser.Admin && exp.OwnerID != nil && curUser.ID != *exp.OwnerID {
 		return fmt.Errorf("non admin users can't move others' experiments")
 	}
+	for _, p := range []*projectv1.Project{from, to} {
+		if err := CheckUnarchived(p); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -123,11 +175,11 @@ func (a *ProjectAuthZBasic) CanArchiveProject(
 	return nil
 }
 
-// CanUnarchiveProject returns an error if the user isn't the owner of the project or workspace.
+// CanUnarchiveProject returns an error if a non admin isn't the owner of the project's workspace.
 func (a *ProjectAuthZBasic) CanUnarchiveProject(
 	ctx context.Context, curUser model.User, project *projectv1.Project,
 ) error {
-	if err := shouldBeAdminOrOwnWorkspaceOrProject(curUser, project); err != nil {
+	if err := shouldBeAdminOrOwnWorkspace(curUser, project); err != nil {
 		return fmt.Errorf("can't unarchive project: %w", err)
 	}
 	return nil
@@ -140,7 +192,7 @@ func (a *ProjectAuthZBasic) CanSetProjectKey(
 	if err := shouldBeAdminOrOwnWorkspaceOrProject(curUser, project); err != nil {
 		return fmt.Errorf("can't set project key: %w", err)
 	}
-	return nil
+	return CheckUnarchived(project)
 }
 
 func init() {
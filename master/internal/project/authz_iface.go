@@ -30,6 +30,17 @@ type ProjectAuthZ interface {
 	) error
 	CanSetProjectKey(ctx context.Context, curUser model.User, project *projectv1.Project) error
 
+	// PUT /api/v1/projects/:project_id/experiment-defaults
+	CanSetProjectExperimentDefaults(
+		ctx context.Context, curUser model.User, project *projectv1.Project,
+	) error
+
+	// PUT/DELETE /api/v1/projects/:project_id/experiment-metadata-schema
+	// PUT/DELETE /api/v1/projects/:project_id/checkpoint-metadata-schema
+	CanSetProjectMetadataSchemas(
+		ctx context.Context, curUser model.User, project *projectv1.Project,
+	) error
+
 	// DELETE /api/v1/projects/:project_id
 	CanDeleteProject(
 		ctx context.Context, curUser model.User, targetProject *projectv1.Project,
@@ -44,6 +44,22 @@ func (p *ProjectAuthZPermissive) CanSetProjectName(
 	return (&ProjectAuthZBasic{}).CanSetProjectName(ctx, curUser, project)
 }
 
+// CanSetProjectExperimentDefaults calls RBAC authz but enforces basic authz.
+func (p *ProjectAuthZPermissive) CanSetProjectExperimentDefaults(
+	ctx context.Context, curUser model.User, project *projectv1.Project,
+) error {
+	_ = (&ProjectAuthZRBAC{}).CanSetProjectExperimentDefaults(ctx, curUser, project)
+	return (&ProjectAuthZBasic{}).CanSetProjectExperimentDefaults(ctx, curUser, project)
+}
+
+// CanSetProjectMetadataSchemas calls RBAC authz but enforces basic authz.
+func (p *ProjectAuthZPermissive) CanSetProjectMetadataSchemas(
+	ctx context.Context, curUser model.User, project *projectv1.Project,
+) error {
+	_ = (&ProjectAuthZRBAC{}).CanSetProjectMetadataSchemas(ctx, curUser, project)
+	return (&ProjectAuthZBasic{}).CanSetProjectMetadataSchemas(ctx, curUser, project)
+}
+
 // CanSetProjectDescription calls RBAC authz but enforces basic authz.
 func (p *ProjectAuthZPermissive) CanSetProjectDescription(
 	ctx context.Context, curUser model.User, project *projectv1.Project,
@@ -89,8 +89,11 @@ func (a *ProjectAuthZRBAC) CanSetProjectNotes(
 		audit.LogFromErr(fields, err)
 	}()
 
-	return permCheck(ctx, curUser, project.WorkspaceId,
-		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT)
+	if err = permCheck(ctx, curUser, project.WorkspaceId,
+		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT); err != nil {
+		return err
+	}
+	return CheckUnarchived(project)
 }
 
 // CanSetProjectName returns an error if a user doesn't have "UPDATE_PROJECT" globally
@@ -105,8 +108,49 @@ func (a *ProjectAuthZRBAC) CanSetProjectName(
 		audit.LogFromErr(fields, err)
 	}()
 
-	return permCheck(ctx, curUser, project.WorkspaceId,
-		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT)
+	if err = permCheck(ctx, curUser, project.WorkspaceId,
+		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT); err != nil {
+		return err
+	}
+	return CheckUnarchived(project)
+}
+
+// CanSetProjectExperimentDefaults returns an error if a user doesn't have
+// "SET_PROJECT_EXPERIMENT_DEFAULTS" globally or on the target project's workspace.
+func (a *ProjectAuthZRBAC) CanSetProjectExperimentDefaults(
+	ctx context.Context, curUser model.User, project *projectv1.Project,
+) (err error) {
+	fields := audit.ExtractLogFields(ctx)
+	logEntryWithProjectTarget(fields, curUser,
+		rbacv1.PermissionType_PERMISSION_TYPE_SET_PROJECT_EXPERIMENT_DEFAULTS, project.Id)
+	defer func() {
+		audit.LogFromErr(fields, err)
+	}()
+
+	if err = permCheck(ctx, curUser, project.WorkspaceId,
+		rbacv1.PermissionType_PERMISSION_TYPE_SET_PROJECT_EXPERIMENT_DEFAULTS); err != nil {
+		return err
+	}
+	return CheckUnarchived(project)
+}
+
+// CanSetProjectMetadataSchemas returns an error if a user doesn't have
+// "SET_PROJECT_METADATA_SCHEMAS" globally or on the target project's workspace.
+func (a *ProjectAuthZRBAC) CanSetProjectMetadataSchemas(
+	ctx context.Context, curUser model.User, project *projectv1.Project,
+) (err error) {
+	fields := audit.ExtractLogFields(ctx)
+	logEntryWithProjectTarget(fields, curUser,
+		rbacv1.PermissionType_PERMISSION_TYPE_SET_PROJECT_METADATA_SCHEMAS, project.Id)
+	defer func() {
+		audit.LogFromErr(fields, err)
+	}()
+
+	if err = permCheck(ctx, curUser, project.WorkspaceId,
+		rbacv1.PermissionType_PERMISSION_TYPE_SET_PROJECT_METADATA_SCHEMAS); err != nil {
+		return err
+	}
+	return CheckUnarchived(project)
 }
 
 // CanSetProjectDescription returns an error if a user doesn't have "UPDATE_PROJECT" globally
@@ -121,8 +165,11 @@ func (a *ProjectAuthZRBAC) CanSetProjectDescription(
 		audit.LogFromErr(fields, err)
 	}()
 
-	return permCheck(ctx, curUser, project.WorkspaceId,
-		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT)
+	if err = permCheck(ctx, curUser, project.WorkspaceId,
+		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT); err != nil {
+		return err
+	}
+	return CheckUnarchived(project)
 }
 
 // CanDeleteProject returns an error if a user doesn't have "DELETE_PROJECT" globally
@@ -207,12 +254,20 @@ func (a *ProjectAuthZRBAC) CanMoveProjectExperiments(
 		audit.LogFromErr(fields, err)
 	}()
 
-	if err := permCheck(ctx, curUser, from.WorkspaceId,
+	if err = permCheck(ctx, curUser, from.WorkspaceId,
 		rbacv1.PermissionType_PERMISSION_TYPE_DELETE_EXPERIMENT); err != nil {
 		return err
 	}
-	return permCheck(ctx, curUser, to.WorkspaceId,
-		rbacv1.PermissionType_PERMISSION_TYPE_CREATE_EXPERIMENT)
+	if err = permCheck(ctx, curUser, to.WorkspaceId,
+		rbacv1.PermissionType_PERMISSION_TYPE_CREATE_EXPERIMENT); err != nil {
+		return err
+	}
+	for _, p := range []*projectv1.Project{from, to} {
+		if err = CheckUnarchived(p); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // CanArchiveProject returns an error if a user doesn't have "UPDATE_PROJECT" globally
@@ -231,20 +286,21 @@ func (a *ProjectAuthZRBAC) CanArchiveProject(
 		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT)
 }
 
-// CanUnarchiveProject returns an error if a user doesn't have "UPDATE_PROJECT" globally
-// or on the target project's workspace.
+// CanUnarchiveProject returns an error if a user doesn't have "UPDATE_WORKSPACE" globally
+// or on the target project's workspace, so that only workspace admins can make an archived
+// project editable again.
 func (a *ProjectAuthZRBAC) CanUnarchiveProject(
 	ctx context.Context, curUser model.User, project *projectv1.Project,
 ) (err error) {
 	fields := audit.ExtractLogFields(ctx)
 	logEntryWithProjectTarget(fields, curUser,
-		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT, project.Id)
+		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_WORKSPACE, project.Id)
 	defer func() {
 		audit.LogFromErr(fields, err)
 	}()
 
 	return permCheck(ctx, curUser, project.WorkspaceId,
-		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT)
+		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_WORKSPACE)
 }
 
 // CanSetProjectKey returns an error if a user doesn't have "UPDATE_PROJECT" globally
@@ -259,6 +315,9 @@ func (a *ProjectAuthZRBAC) CanSetProjectKey(
 		audit.LogFromErr(fields, err)
 	}()
 
-	return permCheck(ctx, curUser, project.WorkspaceId,
-		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT)
+	if err = permCheck(ctx, curUser, project.WorkspaceId,
+		rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_PROJECT); err != nil {
+		return err
+	}
+	return CheckUnarchived(project)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -445,3 +446,82 @@ func UpdateProject(
 	}
 	return finalProject, nil
 }
+
+// Metrics are the counts of a project's experiments and trials by state, when they were last
+// active, and how many GPU hours they have used.
+type Metrics struct {
+	bun.BaseModel `bun:"table:projects"`
+
+	ProjectID        int            `bun:"project_id" json:"project_id"`
+	Name             string         `bun:"name" json:"name"`
+	ExperimentStates map[string]int `bun:"experiment_states,type:jsonb" json:"experiment_states"`
+	TrialStates      map[string]int `bun:"trial_states,type:jsonb" json:"trial_states"`
+	// LastActivity is when an experiment or trial of the project last started, ended or reported
+	// progress.
+	LastActivity *time.Time `bun:"last_activity" json:"last_activity"`
+	// GPUHours are the slot hours used by the allocations of the project's trials, counting
+	// allocations still running up to now. Slots are GPUs except in CPU-only resource pools.
+	GPUHours float64 `bun:"gpu_hours" json:"gpu_hours"`
+}
+
+// WorkspaceMetrics returns the metrics of each project in a workspace in one query, rather than
+// counting the experiments of each project separately.
+func WorkspaceMetrics(ctx context.Context, workspaceID int) ([]Metrics, error) {
+	experiments := db.Bun().NewSelect().
+		TableExpr("experiments AS e").
+		ColumnExpr("e.project_id").
+		ColumnExpr("e.state::text AS state").
+		ColumnExpr("count(*) AS n").
+		ColumnExpr("max(greatest(e.start_time, e.end_time)) AS last_activity").
+		Join("JOIN projects AS p ON p.id = e.project_id").
+		Where("p.workspace_id = ?", workspaceID).
+		Group("e.project_id", "e.state")
+	trials := db.Bun().NewSelect().
+		TableExpr("runs AS r").
+		ColumnExpr("r.project_id").
+		ColumnExpr("r.state::text AS state").
+		ColumnExpr("count(*) AS n").
+		ColumnExpr("max(greatest(r.start_time, r.end_time, r.last_activity)) AS last_activity").
+		Join("JOIN projects AS p ON p.id = r.project_id").
+		Where("p.workspace_id = ?", workspaceID).
+		Group("r.project_id", "r.state")
+	usage := db.Bun().NewSelect().
+		TableExpr("runs AS r").
+		ColumnExpr("r.project_id").
+		ColumnExpr(`sum(a.slots * extract(epoch FROM
+			greatest(coalesce(a.end_time, now()), a.start_time) - a.start_time)) / 3600 AS gpu_hours`).
+		Join("JOIN projects AS p ON p.id = r.project_id").
+		Join("JOIN run_id_task_id AS rt ON rt.run_id = r.id").
+		Join("JOIN allocations AS a ON a.task_id = rt.task_id").
+		Where("p.workspace_id = ?", workspaceID).
+		Where("a.start_time IS NOT NULL").
+		Group("r.project_id")
+
+	var metrics []Metrics
+	if err := db.Bun().NewSelect().
+		With("experiment_counts", experiments).
+		With("trial_counts", trials).
+		With("usage", usage).
+		TableExpr("projects AS p").
+		ColumnExpr("p.id AS project_id").
+		ColumnExpr("p.name").
+		ColumnExpr(`coalesce((
+			SELECT jsonb_object_agg(c.state, c.n) FROM experiment_counts AS c
+			WHERE c.project_id = p.id
+		), '{}') AS experiment_states`).
+		ColumnExpr(`coalesce((
+			SELECT jsonb_object_agg(c.state, c.n) FROM trial_counts AS c WHERE c.project_id = p.id
+		), '{}') AS trial_states`).
+		ColumnExpr(`greatest(
+			(SELECT max(c.last_activity) FROM experiment_counts AS c WHERE c.project_id = p.id),
+			(SELECT max(c.last_activity) FROM trial_counts AS c WHERE c.project_id = p.id)
+		) AS last_activity`).
+		ColumnExpr("coalesce(u.gpu_hours, 0) AS gpu_hours").
+		Join("LEFT JOIN usage AS u ON u.project_id = p.id").
+		Where("p.workspace_id = ?", workspaceID).
+		Order("p.id").
+		Scan(ctx, &metrics); err != nil {
+		return nil, fmt.Errorf("getting project metrics for workspace %d: %w", workspaceID, err)
+	}
+	return metrics, nil
+}
@@ -5,8 +5,10 @@ package project
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -16,6 +18,7 @@ import (
 	internaldb "github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/pkg/etc"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/proto/pkg/projectv1"
 )
 
@@ -141,3 +144,51 @@ func TestUpdateProjectKey(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestWorkspaceMetrics(t *testing.T) {
+	require.NoError(t, etc.SetRootPath(internaldb.RootFromDB))
+	testDB, closeDB := internaldb.MustResolveTestPostgres(t)
+	defer closeDB()
+	internaldb.MustMigrateTestPostgres(t, testDB, internaldb.MigrationsFromDB)
+	ctx := context.Background()
+
+	user := internaldb.RequireMockUser(t, testDB)
+	workspaceID, _ := internaldb.RequireMockWorkspaceID(t, testDB, "")
+	projectID, _ := internaldb.RequireMockProjectID(t, testDB, workspaceID, false)
+	emptyProjectID, _ := internaldb.RequireMockProjectID(t, testDB, workspaceID, false)
+
+	internaldb.RequireMockExperimentProject(t, testDB, user, projectID)
+	exp := internaldb.RequireMockExperimentParams(t, testDB, user, internaldb.MockExperimentParams{
+		State: ptrs.Ptr(model.CompletedState),
+	}, projectID)
+	_, task := internaldb.RequireMockTrial(t, testDB, exp)
+	end := time.Now().UTC().Truncate(time.Millisecond)
+	require.NoError(t, internaldb.AddAllocation(ctx, &model.Allocation{
+		AllocationID: model.AllocationID(fmt.Sprintf("%s-1", task.TaskID)),
+		TaskID:       task.TaskID,
+		Slots:        2,
+		ResourcePool: "default",
+		StartTime:    ptrs.Ptr(end.Add(-90 * time.Minute)),
+		EndTime:      &end,
+		State:        ptrs.Ptr(model.AllocationStateTerminated),
+	}))
+
+	metrics, err := WorkspaceMetrics(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	require.Equal(t, projectID, metrics[0].ProjectID)
+	require.Equal(t, map[string]int{
+		string(model.ActiveState):    1,
+		string(model.CompletedState): 1,
+	}, metrics[0].ExperimentStates)
+	require.Equal(t, map[string]int{string(model.ActiveState): 1}, metrics[0].TrialStates)
+	require.NotNil(t, metrics[0].LastActivity)
+	require.InDelta(t, 3.0, metrics[0].GPUHours, 0.001)
+
+	require.Equal(t, emptyProjectID, metrics[1].ProjectID)
+	require.Empty(t, metrics[1].ExperimentStates)
+	require.Empty(t, metrics[1].TrialStates)
+	require.Nil(t, metrics[1].LastActivity)
+	require.Zero(t, metrics[1].GPUHours)
+}
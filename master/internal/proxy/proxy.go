@@ -1,13 +1,16 @@
 package proxy
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -24,6 +27,9 @@ type Service struct {
 	LastRequested        time.Time
 	ProxyTCP             bool
 	AllowUnauthenticated bool
+	// AllocationID is the allocation that registered the service, if any.
+	AllocationID string
+	RegisteredAt time.Time
 }
 
 // Clone returns a deep copy of the Service.
@@ -34,6 +40,8 @@ func (s Service) Clone() Service {
 		LastRequested:        s.LastRequested,
 		ProxyTCP:             s.ProxyTCP,
 		AllowUnauthenticated: s.AllowUnauthenticated,
+		AllocationID:         s.AllocationID,
+		RegisteredAt:         s.RegisteredAt,
 	}
 }
 
@@ -41,12 +49,17 @@ func (s Service) Clone() Service {
 // immediately and an error if one was encountered during authentication.
 type ProxyHTTPAuth func(echo.Context) (done bool, err error)
 
+// TunnelDialer connects to a port on a host the master can't connect to, through a tunnel.
+type TunnelDialer func(port int) (net.Conn, error)
+
 // Proxy is an actor that proxies requests to registered services.
 type Proxy struct {
 	lock     sync.RWMutex
 	HTTPAuth ProxyHTTPAuth
 	services map[string]*Service
-	syslog   *logrus.Entry
+	// tunnels are the dialers of the hosts services are reached through tunnels on, by host.
+	tunnels map[string]TunnelDialer
+	syslog  *logrus.Entry
 }
 
 // DefaultProxy is the global proxy singleton.
@@ -62,6 +75,7 @@ func InitProxy(httpAuth ProxyHTTPAuth) {
 	DefaultProxy = &Proxy{
 		HTTPAuth: httpAuth,
 		services: make(map[string]*Service),
+		tunnels:  make(map[string]TunnelDialer),
 		syslog:   logrus.WithField("component", "proxy"),
 	}
 	err := LoadOrGenCA()
@@ -77,18 +91,39 @@ func InitProxy(httpAuth ProxyHTTPAuth) {
 // Register registers the service name with the associated target URL. All requests with the
 // format ".../:service-name/*" are forwarded to the service via the target URL.
 func (p *Proxy) Register(serviceID string, url *url.URL, proxyTCP bool, unauth bool) {
+	p.RegisterForAllocation(serviceID, "", url, proxyTCP, unauth)
+}
+
+// RegisterForAllocation registers a service like Register, on behalf of an allocation. The
+// service is removed by Reconcile if it outlives the allocation, and services of other allocations
+// at the same address are removed, since only one of them can be listening on it.
+func (p *Proxy) RegisterForAllocation(
+	serviceID, allocationID string, url *url.URL, proxyTCP bool, unauth bool,
+) {
 	if serviceID == "" {
 		return
 	}
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
+	if allocationID != "" && url.Host != "" {
+		for id, s := range p.services {
+			if id != serviceID && s.AllocationID != "" && s.AllocationID != allocationID &&
+				s.URL.Host == url.Host {
+				p.forceRemove(id, s, reasonAddressReused)
+			}
+		}
+	}
+
 	p.syslog.Infof("registering service: %s (%v)", serviceID, url)
+	now := time.Now()
 	p.services[serviceID] = &Service{
 		URL:                  url,
-		LastRequested:        time.Now(),
+		LastRequested:        now,
 		ProxyTCP:             proxyTCP,
 		AllowUnauthenticated: unauth,
+		AllocationID:         allocationID,
+		RegisteredAt:         now,
 	}
 }
 
@@ -101,6 +136,62 @@ func (p *Proxy) Unregister(serviceID string) {
 	delete(p.services, serviceID)
 }
 
+// RegisterTunnel routes the connections to services on host through dial, for hosts that accept no
+// connections from the master.
+func (p *Proxy) RegisterTunnel(host string, dial TunnelDialer) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.syslog.Infof("registering tunnel: %s", host)
+	p.tunnels[host] = dial
+}
+
+// UnregisterTunnel stops routing the connections to services on host through a tunnel.
+func (p *Proxy) UnregisterTunnel(host string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.tunnels, host)
+}
+
+// hasTunnel returns whether a tunnel is registered for the host of addr.
+func (p *Proxy) hasTunnel(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	_, ok := p.tunnels[host]
+	return ok
+}
+
+// dialTunnel connects to addr through the tunnel registered for its host, if there is one.
+func (p *Proxy) dialTunnel(addr string) (conn net.Conn, ok bool, err error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, false, nil
+	}
+	p.lock.RLock()
+	dial, ok := p.tunnels[host]
+	p.lock.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid port %q: %w", port, err)
+	}
+	conn, err = dial(portNum)
+	return conn, true, err
+}
+
+// dial connects to addr, through a tunnel if one is registered for its host.
+func (p *Proxy) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	if conn, ok, err := p.dialTunnel(addr); ok {
+		return conn, err
+	}
+	return (&net.Dialer{}).DialContext(ctx, network, addr)
+}
+
 // ClearProxy erases all services from the proxy in case any handlers are still active.
 func (p *Proxy) ClearProxy() {
 	p.lock.Lock()
@@ -161,11 +252,11 @@ func (p *Proxy) NewProxyHandler(serviceID string) echo.HandlerFunc {
 		var proxy http.Handler
 		switch {
 		case service.ProxyTCP:
-			proxy = newSingleHostReverseTCPOverWebSocketProxy(c, service.URL)
+			proxy = p.newSingleHostReverseTCPOverWebSocketProxy(c, service.URL)
 		case c.IsWebSocket():
-			proxy = newSingleHostReverseWebSocketProxy(c, service.URL)
+			proxy = p.newSingleHostReverseWebSocketProxy(c, service.URL)
 		default:
-			newProxy, err := setUpProxy(service.URL)
+			newProxy, err := p.setUpProxy(service.URL)
 			if err != nil {
 				return err
 			}
@@ -178,9 +269,15 @@ func (p *Proxy) NewProxyHandler(serviceID string) echo.HandlerFunc {
 	}
 }
 
-func setUpProxy(serviceURL *url.URL) (*httputil.ReverseProxy, error) {
+func (p *Proxy) setUpProxy(serviceURL *url.URL) (*httputil.ReverseProxy, error) {
 	proxy := httputil.NewSingleHostReverseProxy(serviceURL)
 	if serviceURL.Scheme != https {
+		if p.hasTunnel(serviceURL.Host) {
+			transport := cleanhttp.DefaultTransport()
+			transport.Proxy = nil
+			transport.DialContext = p.dial
+			proxy.Transport = transport
+		}
 		return proxy, nil
 	}
 	keyBytes, certBytes, err := MasterKeyAndCert()
@@ -200,6 +297,10 @@ func setUpProxy(serviceURL *url.URL) (*httputil.ReverseProxy, error) {
 	caCertPool.AppendCertsFromPEM(masterCaBytes)
 
 	transport := cleanhttp.DefaultTransport()
+	if p.hasTunnel(serviceURL.Host) {
+		transport.Proxy = nil
+		transport.DialContext = p.dial
+	}
 	transport.TLSClientConfig = &tls.Config{
 		RootCAs:            caCertPool,
 		Certificates:       []tls.Certificate{cert},
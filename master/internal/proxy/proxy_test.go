@@ -0,0 +1,33 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialThroughTunnel(t *testing.T) {
+	p := newTestProxy()
+	var dialed []int
+	p.RegisterTunnel("agent-1.tunnel", func(port int) (net.Conn, error) {
+		dialed = append(dialed, port)
+		local, remote := net.Pipe()
+		require.NoError(t, remote.Close())
+		return local, nil
+	})
+	require.True(t, p.hasTunnel("agent-1.tunnel:2700"))
+	require.False(t, p.hasTunnel("agent-2.tunnel:2700"))
+
+	conn, err := p.dial(context.Background(), "tcp", "agent-1.tunnel:2700")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	require.Equal(t, []int{2700}, dialed)
+
+	_, tunneled, _ := p.dialTunnel("10.0.0.1:2700")
+	require.False(t, tunneled)
+
+	p.UnregisterTunnel("agent-1.tunnel")
+	require.False(t, p.hasTunnel("agent-1.tunnel:2700"))
+}
@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ReconcileInterval is how often services are checked against live allocations.
+	ReconcileInterval = time.Minute
+	// reconcileGracePeriod is how long a service is kept after it is registered even if its
+	// allocation isn't live, so services aren't removed while their allocation is starting.
+	reconcileGracePeriod = time.Minute
+
+	// reasonAllocationEnded is why services are removed when their allocation is no longer live,
+	// because the allocation exited without unregistering them, such as after a crash.
+	reasonAllocationEnded = "allocation_ended"
+	// reasonAddressReused is why services are removed when another allocation registers a service
+	// at their address.
+	reasonAddressReused = "address_reused"
+)
+
+var routesForceRemoved = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "determined_proxy_routes_force_removed_total",
+	Help: "Number of proxied services removed because their allocation ended without " +
+		"unregistering them or another allocation registered a service at their address",
+}, []string{"reason"})
+
+// Reconcile removes services registered by allocations that aren't in live, if they were
+// registered longer than the grace period ago, which also covers allocations that started after
+// live was listed. It returns the IDs of the removed services.
+func (p *Proxy) Reconcile(live map[string]bool) []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var removed []string
+	for id, s := range p.services {
+		if s.AllocationID == "" || time.Since(s.RegisteredAt) < reconcileGracePeriod ||
+			live[s.AllocationID] {
+			continue
+		}
+		p.forceRemove(id, s, reasonAllocationEnded)
+		removed = append(removed, id)
+	}
+	return removed
+}
+
+// Reconciler calls Reconcile with the live allocations every ReconcileInterval until ctx is done.
+func (p *Proxy) Reconciler(ctx context.Context, liveAllocations func() map[string]bool) {
+	t := time.NewTicker(ReconcileInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			p.Reconcile(liveAllocations())
+		}
+	}
+}
+
+// forceRemove removes a service that wasn't unregistered by its allocation. The caller must hold
+// the lock.
+func (p *Proxy) forceRemove(id string, s *Service, reason string) {
+	p.syslog.WithFields(logrus.Fields{
+		"service-id":    id,
+		"allocation-id": s.AllocationID,
+		"address":       s.URL.Host,
+		"reason":        reason,
+	}).Warn("removing stale proxied service")
+	routesForceRemoved.WithLabelValues(reason).Inc()
+	delete(p.services, id)
+}
@@ -0,0 +1,60 @@
+package proxy
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProxy() *Proxy {
+	return &Proxy{
+		services: make(map[string]*Service),
+		tunnels:  make(map[string]TunnelDialer),
+		syslog:   logrus.WithField("component", "proxy"),
+	}
+}
+
+func TestReconcileRemovesServicesOfEndedAllocations(t *testing.T) {
+	p := newTestProxy()
+	p.RegisterForAllocation("crashed", "alloc-1", &url.URL{Host: "10.0.0.1:2700"}, false, false)
+	p.RegisterForAllocation("running", "alloc-2", &url.URL{Host: "10.0.0.1:2701"}, false, false)
+	p.RegisterForAllocation("starting", "alloc-3", &url.URL{Host: "10.0.0.1:2702"}, false, false)
+	p.Register("unowned", &url.URL{Host: "10.0.0.2:80"}, false, false)
+	for _, id := range []string{"crashed", "running", "unowned"} {
+		p.services[id].RegisteredAt = time.Now().Add(-2 * reconcileGracePeriod)
+	}
+
+	before := testutil.ToFloat64(routesForceRemoved.WithLabelValues(reasonAllocationEnded))
+	removed := p.Reconcile(map[string]bool{"alloc-2": true})
+	require.Equal(t, []string{"crashed"}, removed)
+	require.Nil(t, p.GetService("crashed"))
+	for _, id := range []string{"running", "starting", "unowned"} {
+		require.NotNil(t, p.GetService(id), id)
+	}
+	require.Equal(t, before+1,
+		testutil.ToFloat64(routesForceRemoved.WithLabelValues(reasonAllocationEnded)))
+}
+
+func TestRegisterReplacesServicesAtTheSameAddress(t *testing.T) {
+	p := newTestProxy()
+	addr := &url.URL{Host: "10.0.0.1:2700"}
+	p.RegisterForAllocation("old", "alloc-1", addr, false, false)
+	p.RegisterForAllocation("other-port", "alloc-1", &url.URL{Host: "10.0.0.1:2701"}, false, false)
+
+	// Services of the same allocation can share an address.
+	p.RegisterForAllocation("old-2", "alloc-1", addr, false, false)
+	require.NotNil(t, p.GetService("old"))
+
+	before := testutil.ToFloat64(routesForceRemoved.WithLabelValues(reasonAddressReused))
+	p.RegisterForAllocation("new", "alloc-2", addr, false, false)
+	require.Nil(t, p.GetService("old"))
+	require.Nil(t, p.GetService("old-2"))
+	require.NotNil(t, p.GetService("other-port"))
+	require.NotNil(t, p.GetService("new"))
+	require.Equal(t, before+2,
+		testutil.ToFloat64(routesForceRemoved.WithLabelValues(reasonAddressReused)))
+}
@@ -45,12 +45,13 @@ func (w *websocketReadWriter) Write(buf []byte) (int, error) {
 	return len(buf), nil
 }
 
-func newSingleHostReverseTCPOverWebSocketProxy(c echo.Context, t *url.URL) http.Handler {
+func (p *Proxy) newSingleHostReverseTCPOverWebSocketProxy(c echo.Context, t *url.URL) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		dialer := proxy.FromEnvironment()
-
 		// Make sure we can open the connection to the remote host.
-		out, err := dialer.Dial("tcp", t.Host)
+		out, tunneled, err := p.dialTunnel(t.Host)
+		if !tunneled {
+			out, err = proxy.FromEnvironment().Dial("tcp", t.Host)
+		}
 		if err != nil {
 			c.Error(echo.NewHTTPError(http.StatusBadGateway,
 				errors.Errorf("error dialing to %v: %v", t, err)))
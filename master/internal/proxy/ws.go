@@ -4,7 +4,6 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"math"
-	"net"
 	"net/http"
 	"net/url"
 	"time"
@@ -25,7 +24,7 @@ func connectWithbackoff(tlsConn *tls.Conn) error {
 	return err
 }
 
-func newSingleHostReverseWebSocketProxy(c echo.Context, t *url.URL) http.Handler {
+func (p *Proxy) newSingleHostReverseWebSocketProxy(c echo.Context, t *url.URL) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		in, _, err := c.Response().Hijack()
 		if err != nil {
@@ -38,7 +37,7 @@ func newSingleHostReverseWebSocketProxy(c echo.Context, t *url.URL) http.Handler
 			}
 		}()
 
-		out, err := net.Dial("tcp", t.Host)
+		out, err := p.dial(r.Context(), "tcp", t.Host)
 		if err != nil {
 			c.Error(echo.NewHTTPError(http.StatusBadGateway,
 				errors.Errorf("error dialing to %v: %v", t, err)))
@@ -0,0 +1,117 @@
+// Package raycluster tracks the Ray clusters that run as generic tasks, along with the health
+// their head nodes last reported.
+package raycluster
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/syncx/mapx"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+// ErrNotFound is returned when a task isn't a running Ray cluster.
+var ErrNotFound = errors.New("ray cluster not found")
+
+var clusters = mapx.New[model.TaskID, Cluster]()
+
+// Cluster is a Ray cluster running as a generic task.
+type Cluster struct {
+	TaskID        model.TaskID `json:"task_id"`
+	WorkspaceID   int          `json:"workspace_id"`
+	DashboardPort int          `json:"dashboard_port"`
+	StartTime     time.Time    `json:"start_time"`
+	// Health is the last health report from the head node, or nil until the cluster reports it.
+	Health *Health `json:"health"`
+}
+
+// Health is a report of a Ray cluster's health from its head node.
+type Health struct {
+	HeadAddress   string    `json:"head_address"`
+	ExpectedNodes int       `json:"expected_nodes"`
+	AliveNodes    int       `json:"alive_nodes"`
+	Idle          bool      `json:"idle"`
+	ReportTime    time.Time `json:"report_time"`
+}
+
+// Healthy returns whether every node of the cluster was alive as of its last health report.
+func (c Cluster) Healthy() bool {
+	return c.Health != nil && c.Health.AliveNodes >= c.Health.ExpectedNodes
+}
+
+// Proto converts a cluster to its protobuf representation.
+func (c Cluster) Proto() *taskv1.RayCluster {
+	pb := &taskv1.RayCluster{
+		TaskId:        string(c.TaskID),
+		WorkspaceId:   int32(c.WorkspaceID),
+		DashboardPort: int32(c.DashboardPort),
+		StartTime:     timestamppb.New(c.StartTime),
+		Healthy:       c.Healthy(),
+	}
+	if c.Health != nil {
+		pb.Health = &taskv1.RayClusterHealth{
+			HeadAddress:   c.Health.HeadAddress,
+			ExpectedNodes: int32(c.Health.ExpectedNodes),
+			AliveNodes:    int32(c.Health.AliveNodes),
+			Idle:          c.Health.Idle,
+			ReportTime:    timestamppb.New(c.Health.ReportTime),
+		}
+	}
+	return pb
+}
+
+// Register starts tracking a Ray cluster. A cluster registered again, e.g. when its task is
+// restored or unpaused, loses its last health report.
+func Register(c Cluster) {
+	c.Health = nil
+	clusters.Store(c.TaskID, c)
+}
+
+// Unregister stops tracking a Ray cluster.
+func Unregister(taskID model.TaskID) {
+	clusters.Delete(taskID)
+}
+
+// Get returns a Ray cluster.
+func Get(taskID model.TaskID) (Cluster, error) {
+	c, ok := clusters.Load(taskID)
+	if !ok {
+		return Cluster{}, ErrNotFound
+	}
+	return c, nil
+}
+
+// List returns every Ray cluster, oldest first.
+func List() []Cluster {
+	out := clusters.Values()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartTime.Equal(out[j].StartTime) {
+			return out[i].StartTime.Before(out[j].StartTime)
+		}
+		return out[i].TaskID < out[j].TaskID
+	})
+	return out
+}
+
+// ReportHealth records a health report from a Ray cluster's head node.
+func ReportHealth(taskID model.TaskID, h Health) (Cluster, error) {
+	var (
+		c  Cluster
+		ok bool
+	)
+	clusters.WithLock(func(m map[model.TaskID]Cluster) {
+		if c, ok = m[taskID]; !ok {
+			return
+		}
+		c.Health = &h
+		m[taskID] = c
+	})
+	if !ok {
+		return Cluster{}, ErrNotFound
+	}
+	return c, nil
+}
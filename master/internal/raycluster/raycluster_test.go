@@ -0,0 +1,67 @@
+package raycluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestRayClusters(t *testing.T) {
+	defer clusters.Clear()
+
+	now := time.Now()
+	Register(Cluster{TaskID: "newer", WorkspaceID: 1, StartTime: now})
+	Register(Cluster{TaskID: "older", WorkspaceID: 2, StartTime: now.Add(-time.Hour)})
+	require.Equal(t, []model.TaskID{"older", "newer"}, taskIDs(List()))
+
+	c, err := Get("newer")
+	require.NoError(t, err)
+	require.Nil(t, c.Health)
+	require.False(t, c.Healthy(), "a cluster that hasn't reported isn't healthy")
+
+	c, err = ReportHealth("newer", Health{ExpectedNodes: 2, AliveNodes: 1})
+	require.NoError(t, err)
+	require.False(t, c.Healthy())
+	_, err = ReportHealth("newer", Health{ExpectedNodes: 2, AliveNodes: 2})
+	require.NoError(t, err)
+	c, err = Get("newer")
+	require.NoError(t, err)
+	require.True(t, c.Healthy())
+
+	// Registering a cluster again, e.g. when it's unpaused, forgets its health.
+	Register(c)
+	c, err = Get("newer")
+	require.NoError(t, err)
+	require.Nil(t, c.Health)
+
+	Unregister("newer")
+	_, err = Get("newer")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = ReportHealth("newer", Health{})
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, []model.TaskID{"older"}, taskIDs(List()))
+}
+
+func TestClusterProto(t *testing.T) {
+	c := Cluster{TaskID: "task", WorkspaceID: 1, DashboardPort: 8265}
+	pb := c.Proto()
+	require.Equal(t, "task", pb.TaskId)
+	require.Nil(t, pb.Health)
+	require.False(t, pb.Healthy)
+
+	c.Health = &Health{HeadAddress: "10.0.0.1:6379", ExpectedNodes: 2, AliveNodes: 2}
+	pb = c.Proto()
+	require.Equal(t, int32(2), pb.Health.AliveNodes)
+	require.True(t, pb.Healthy)
+}
+
+func taskIDs(cs []Cluster) []model.TaskID {
+	var out []model.TaskID
+	for _, c := range cs {
+		out = append(out, c.TaskID)
+	}
+	return out
+}
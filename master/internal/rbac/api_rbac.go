@@ -18,6 +18,8 @@ type RBACAPIServer interface {
 	GetGroupsAndUsersAssignedToWorkspace(
 		context.Context, *apiv1.GetGroupsAndUsersAssignedToWorkspaceRequest,
 	) (*apiv1.GetGroupsAndUsersAssignedToWorkspaceResponse, error)
+	GetWorkspaceMembers(context.Context, *apiv1.GetWorkspaceMembersRequest) (
+		*apiv1.GetWorkspaceMembersResponse, error)
 	GetRolesByID(context.Context, *apiv1.GetRolesByIDRequest) (
 		*apiv1.GetRolesByIDResponse, error)
 	GetRolesAssignedToUser(context.Context, *apiv1.GetRolesAssignedToUserRequest) (
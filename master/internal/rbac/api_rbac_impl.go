@@ -5,10 +5,12 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/determined-ai/determined/master/internal/activity"
 	"github.com/determined-ai/determined/master/internal/api/apiutils"
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/config"
@@ -155,6 +157,48 @@ func (a *RBACAPIServerImpl) GetGroupsAndUsersAssignedToWorkspace(
 	}, nil
 }
 
+// GetWorkspaceMembers gets the users and groups with roles on a workspace, with each user's
+// effective roles and where they come from.
+func (a *RBACAPIServerImpl) GetWorkspaceMembers(
+	ctx context.Context, req *apiv1.GetWorkspaceMembersRequest,
+) (resp *apiv1.GetWorkspaceMembersResponse, err error) {
+	// Detect whether we're returning special errors and convert to gRPC error
+	defer func() {
+		err = apiutils.MapAndFilterErrors(err, nil, errorMapping)
+	}()
+
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = AuthZProvider.Get().CanGetWorkspaceMembership(ctx, *u, req.WorkspaceId); err != nil {
+		return nil, err
+	}
+	exists, err := db.Bun().NewSelect().Table("workspaces").
+		Where("id = ?", req.WorkspaceId).Exists(ctx)
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, errors.Wrapf(db.ErrNotFound, "Error getting workspace %d", req.WorkspaceId)
+	}
+
+	members, err := GetWorkspaceMembers(ctx, int(req.WorkspaceId))
+	if err != nil {
+		return nil, err
+	}
+	resp = &apiv1.GetWorkspaceMembersResponse{
+		Users:  make([]*rbacv1.WorkspaceUserMember, 0, len(members.Users)),
+		Groups: make([]*rbacv1.WorkspaceGroupMember, 0, len(members.Groups)),
+	}
+	for _, m := range members.Users {
+		resp.Users = append(resp.Users, m.Proto())
+	}
+	for _, g := range members.Groups {
+		resp.Groups = append(resp.Groups, g.Proto())
+	}
+	return resp, nil
+}
+
 // GetRolesByID searches for roles that fulfill the criteria given by the user.
 func (a *RBACAPIServerImpl) GetRolesByID(ctx context.Context, req *apiv1.GetRolesByIDRequest,
 ) (resp *apiv1.GetRolesByIDResponse, err error) {
@@ -409,6 +453,12 @@ func (a *RBACAPIServerImpl) AssignRoles(ctx context.Context, req *apiv1.AssignRo
 		return nil, err
 	}
 
+	if err := activity.RecordMembersAdded(
+		ctx, u.ID, req.GroupRoleAssignments, req.UserRoleAssignments,
+	); err != nil {
+		logrus.WithError(err).Warn("failed to record role assignments in workspace activity")
+	}
+
 	return &apiv1.AssignRolesResponse{}, nil
 }
 
@@ -31,6 +31,12 @@ func (s *rbacAPIServerStub) GetGroupsAndUsersAssignedToWorkspace(
 	return nil, UnimplementedError
 }
 
+func (s *rbacAPIServerStub) GetWorkspaceMembers(
+	context.Context, *apiv1.GetWorkspaceMembersRequest,
+) (*apiv1.GetWorkspaceMembersResponse, error) {
+	return nil, UnimplementedError
+}
+
 func (s *rbacAPIServerStub) GetRolesByID(ctx context.Context, req *apiv1.GetRolesByIDRequest) (
 	resp *apiv1.GetRolesByIDResponse, err error,
 ) {
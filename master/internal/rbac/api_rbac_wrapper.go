@@ -26,6 +26,13 @@ func (s *RBACAPIServerWrapper) GetGroupsAndUsersAssignedToWorkspace(
 	return rbacAPIServer.GetGroupsAndUsersAssignedToWorkspace(ctx, req)
 }
 
+// GetWorkspaceMembers is a wrapper the same function the RBACAPIServer interface.
+func (s *RBACAPIServerWrapper) GetWorkspaceMembers(
+	ctx context.Context, req *apiv1.GetWorkspaceMembersRequest,
+) (*apiv1.GetWorkspaceMembersResponse, error) {
+	return rbacAPIServer.GetWorkspaceMembers(ctx, req)
+}
+
 // GetRolesByID is a wrapper the same function the RBACAPIServer interface.
 func (s *RBACAPIServerWrapper) GetRolesByID(ctx context.Context, req *apiv1.GetRolesByIDRequest) (
 	resp *apiv1.GetRolesByIDResponse, err error,
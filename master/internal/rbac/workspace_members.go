@@ -0,0 +1,244 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+const (
+	// ScopeWorkspace is the scope of roles assigned on a workspace.
+	ScopeWorkspace = "workspace"
+	// ScopeGlobal is the scope of roles assigned on the whole cluster, which apply in every
+	// workspace.
+	ScopeGlobal = "global"
+
+	// ViaDirect marks roles assigned to a user themself.
+	ViaDirect = "direct"
+	// ViaGroup marks roles a user has because they are a member of a group.
+	ViaGroup = "group"
+)
+
+// MemberRole is a role a user or group has on a workspace, and where it comes from.
+type MemberRole struct {
+	RoleID   int    `json:"role_id"`
+	RoleName string `json:"role_name"`
+	Scope    string `json:"scope"`
+	// Via, GroupID and GroupName are only set for the roles of users.
+	Via       string  `json:"via,omitempty"`
+	GroupID   *int    `json:"group_id,omitempty"`
+	GroupName *string `json:"group_name,omitempty"`
+}
+
+// UserMember is a user with access to a workspace and all of the roles they have on it.
+type UserMember struct {
+	UserID      model.UserID `json:"user_id"`
+	Username    string       `json:"username"`
+	DisplayName string       `json:"display_name"`
+	Active      bool         `json:"active"`
+	Roles       []MemberRole `json:"roles"`
+}
+
+// GroupMember is a group with roles on a workspace.
+type GroupMember struct {
+	GroupID   int          `json:"group_id"`
+	GroupName string       `json:"group_name"`
+	Members   int          `json:"members"`
+	Roles     []MemberRole `json:"roles"`
+}
+
+// WorkspaceMembers is everyone with roles on a workspace.
+type WorkspaceMembers struct {
+	Users  []UserMember  `json:"users"`
+	Groups []GroupMember `json:"groups"`
+}
+
+// Proto converts a MemberRole to its protobuf representation.
+func (r MemberRole) Proto() *rbacv1.WorkspaceMemberRole {
+	pb := &rbacv1.WorkspaceMemberRole{
+		RoleId:    int32(r.RoleID),
+		RoleName:  r.RoleName,
+		Scope:     r.Scope,
+		GroupName: r.GroupName,
+	}
+	if r.Via != "" {
+		pb.Via = ptrs.Ptr(r.Via)
+	}
+	if r.GroupID != nil {
+		pb.GroupId = ptrs.Ptr(int32(*r.GroupID))
+	}
+	return pb
+}
+
+func memberRolesToProto(roles []MemberRole) []*rbacv1.WorkspaceMemberRole {
+	pbs := make([]*rbacv1.WorkspaceMemberRole, 0, len(roles))
+	for _, r := range roles {
+		pbs = append(pbs, r.Proto())
+	}
+	return pbs
+}
+
+// Proto converts a UserMember to its protobuf representation.
+func (u UserMember) Proto() *rbacv1.WorkspaceUserMember {
+	return &rbacv1.WorkspaceUserMember{
+		UserId:      int32(u.UserID),
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		Active:      u.Active,
+		Roles:       memberRolesToProto(u.Roles),
+	}
+}
+
+// Proto converts a GroupMember to its protobuf representation.
+func (g GroupMember) Proto() *rbacv1.WorkspaceGroupMember {
+	return &rbacv1.WorkspaceGroupMember{
+		GroupId:   int32(g.GroupID),
+		GroupName: g.GroupName,
+		Members:   int32(g.Members),
+		Roles:     memberRolesToProto(g.Roles),
+	}
+}
+
+// memberAssignment is a role assignment that applies to a workspace.
+type memberAssignment struct {
+	RoleID   int    `bun:"role_id"`
+	RoleName string `bun:"role_name"`
+	GroupID  int    `bun:"group_id"`
+	// GroupName is empty and GroupUserID is set for the personal groups that roles are directly
+	// assigned to users through.
+	GroupName   string       `bun:"group_name"`
+	GroupUserID model.UserID `bun:"group_user_id"`
+	Global      bool         `bun:"global"`
+}
+
+// memberUser is a user that has roles on a workspace, and the group they have them through if
+// any.
+type memberUser struct {
+	UserID      model.UserID `bun:"user_id"`
+	Username    string       `bun:"username"`
+	DisplayName string       `bun:"display_name"`
+	Active      bool         `bun:"active"`
+	GroupID     int          `bun:"group_id"`
+}
+
+// GetWorkspaceMembers returns the users and groups with roles on a workspace, with each user's
+// effective roles and whether they are assigned to them directly or through a group, on the
+// workspace or globally.
+func GetWorkspaceMembers(ctx context.Context, workspaceID int) (*WorkspaceMembers, error) {
+	var assignments []memberAssignment
+	if err := db.Bun().NewSelect().
+		TableExpr("role_assignments AS ra").
+		Join("JOIN role_assignment_scopes AS ras ON ras.id = ra.scope_id").
+		Join("JOIN roles AS r ON r.id = ra.role_id").
+		Join("JOIN groups AS g ON g.id = ra.group_id").
+		ColumnExpr("ra.role_id, r.role_name, ra.group_id").
+		ColumnExpr("CASE WHEN g.user_id IS NULL THEN g.group_name ELSE '' END AS group_name").
+		ColumnExpr("COALESCE(g.user_id, 0) AS group_user_id").
+		ColumnExpr("ras.scope_workspace_id IS NULL AS global").
+		Where("ras.scope_workspace_id = ? OR ras.scope_workspace_id IS NULL", workspaceID).
+		Scan(ctx, &assignments); err != nil {
+		return nil, fmt.Errorf("getting role assignments of workspace %d: %w", workspaceID, err)
+	}
+
+	// Personal groups have their user as their only member, so members of every group with a
+	// role are all of the users with roles.
+	groupIDs := make([]int, 0, len(assignments))
+	for _, a := range assignments {
+		groupIDs = append(groupIDs, a.GroupID)
+	}
+	var users []memberUser
+	if len(groupIDs) > 0 {
+		if err := db.Bun().NewSelect().
+			TableExpr("user_group_membership AS ugm").
+			Join("JOIN users AS u ON u.id = ugm.user_id").
+			ColumnExpr("u.id AS user_id, u.username, COALESCE(u.display_name, '') AS display_name").
+			ColumnExpr("u.active, ugm.group_id").
+			Where("ugm.group_id IN (?)", bun.In(groupIDs)).
+			Scan(ctx, &users); err != nil {
+			return nil, fmt.Errorf("getting members of workspace %d: %w", workspaceID, err)
+		}
+	}
+	return summarizeMembers(assignments, users), nil
+}
+
+// summarizeMembers groups role assignments by the users and groups they apply to.
+func summarizeMembers(assignments []memberAssignment, users []memberUser) *WorkspaceMembers {
+	groupRoles := make(map[int][]memberAssignment)
+	for _, a := range assignments {
+		groupRoles[a.GroupID] = append(groupRoles[a.GroupID], a)
+	}
+
+	res := &WorkspaceMembers{Users: []UserMember{}, Groups: []GroupMember{}}
+	byUser := make(map[model.UserID]*UserMember)
+	groupMembers := make(map[int]int)
+	for _, u := range users {
+		m, ok := byUser[u.UserID]
+		if !ok {
+			m = &UserMember{
+				UserID:      u.UserID,
+				Username:    u.Username,
+				DisplayName: u.DisplayName,
+				Active:      u.Active,
+				Roles:       []MemberRole{},
+			}
+			byUser[u.UserID] = m
+		}
+		for _, a := range groupRoles[u.GroupID] {
+			r := MemberRole{RoleID: a.RoleID, RoleName: a.RoleName, Scope: scope(a), Via: ViaDirect}
+			if a.GroupUserID == 0 {
+				groupID, groupName := a.GroupID, a.GroupName
+				r.Via, r.GroupID, r.GroupName = ViaGroup, &groupID, &groupName
+			}
+			m.Roles = append(m.Roles, r)
+		}
+		groupMembers[u.GroupID]++
+	}
+	for _, m := range byUser {
+		sort.Slice(m.Roles, func(i, j int) bool { return roleLess(m.Roles[i], m.Roles[j]) })
+		res.Users = append(res.Users, *m)
+	}
+	sort.Slice(res.Users, func(i, j int) bool { return res.Users[i].Username < res.Users[j].Username })
+
+	for groupID, as := range groupRoles {
+		if as[0].GroupUserID != 0 {
+			continue
+		}
+		g := GroupMember{GroupID: groupID, GroupName: as[0].GroupName, Members: groupMembers[groupID]}
+		for _, a := range as {
+			g.Roles = append(g.Roles, MemberRole{RoleID: a.RoleID, RoleName: a.RoleName, Scope: scope(a)})
+		}
+		sort.Slice(g.Roles, func(i, j int) bool { return roleLess(g.Roles[i], g.Roles[j]) })
+		res.Groups = append(res.Groups, g)
+	}
+	sort.Slice(res.Groups, func(i, j int) bool { return res.Groups[i].GroupName < res.Groups[j].GroupName })
+	return res
+}
+
+func scope(a memberAssignment) string {
+	if a.Global {
+		return ScopeGlobal
+	}
+	return ScopeWorkspace
+}
+
+// roleLess orders roles by name, then assignments on the workspace before global ones, then direct
+// assignments before those through groups.
+func roleLess(a, b MemberRole) bool {
+	if a.RoleName != b.RoleName {
+		return a.RoleName < b.RoleName
+	}
+	if a.Scope != b.Scope {
+		return a.Scope == ScopeWorkspace
+	}
+	if a.Via != b.Via {
+		return a.Via == ViaDirect
+	}
+	return a.GroupName != nil && b.GroupName != nil && *a.GroupName < *b.GroupName
+}
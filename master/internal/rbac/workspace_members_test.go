@@ -0,0 +1,77 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeMembers(t *testing.T) {
+	const (
+		aliceGroup = 10
+		bobGroup   = 11
+		mlGroup    = 20
+		emptyGroup = 21
+	)
+	assignments := []memberAssignment{
+		{RoleID: 2, RoleName: "Viewer", GroupID: mlGroup, GroupName: "ml"},
+		{RoleID: 3, RoleName: "Editor", GroupID: aliceGroup, GroupUserID: 1},
+		{RoleID: 1, RoleName: "ClusterAdmin", GroupID: bobGroup, GroupUserID: 2, Global: true},
+		{RoleID: 2, RoleName: "Viewer", GroupID: emptyGroup, GroupName: "empty", Global: true},
+	}
+	users := []memberUser{
+		{UserID: 1, Username: "alice", Active: true, GroupID: aliceGroup},
+		{UserID: 1, Username: "alice", Active: true, GroupID: mlGroup},
+		{UserID: 2, Username: "bob", GroupID: bobGroup},
+		{UserID: 3, Username: "carol", Active: true, GroupID: mlGroup},
+	}
+
+	ml := mlGroup
+	mlName := "ml"
+	members := summarizeMembers(assignments, users)
+	require.Equal(t, []UserMember{
+		{UserID: 1, Username: "alice", Active: true, Roles: []MemberRole{
+			{RoleID: 3, RoleName: "Editor", Scope: ScopeWorkspace, Via: ViaDirect},
+			{
+				RoleID: 2, RoleName: "Viewer", Scope: ScopeWorkspace, Via: ViaGroup,
+				GroupID: &ml, GroupName: &mlName,
+			},
+		}},
+		{UserID: 2, Username: "bob", Roles: []MemberRole{
+			{RoleID: 1, RoleName: "ClusterAdmin", Scope: ScopeGlobal, Via: ViaDirect},
+		}},
+		{UserID: 3, Username: "carol", Active: true, Roles: []MemberRole{
+			{
+				RoleID: 2, RoleName: "Viewer", Scope: ScopeWorkspace, Via: ViaGroup,
+				GroupID: &ml, GroupName: &mlName,
+			},
+		}},
+	}, members.Users)
+	require.Equal(t, []GroupMember{
+		{GroupID: emptyGroup, GroupName: "empty", Members: 0, Roles: []MemberRole{
+			{RoleID: 2, RoleName: "Viewer", Scope: ScopeGlobal},
+		}},
+		{GroupID: mlGroup, GroupName: "ml", Members: 2, Roles: []MemberRole{
+			{RoleID: 2, RoleName: "Viewer", Scope: ScopeWorkspace},
+		}},
+	}, members.Groups)
+}
+
+func TestMemberRoleProto(t *testing.T) {
+	groupID, groupName := 20, "ml"
+	pb := MemberRole{
+		RoleID: 2, RoleName: "Viewer", Scope: ScopeWorkspace, Via: ViaGroup,
+		GroupID: &groupID, GroupName: &groupName,
+	}.Proto()
+	require.Equal(t, int32(2), pb.RoleId)
+	require.Equal(t, ScopeWorkspace, pb.Scope)
+	require.Equal(t, ViaGroup, pb.GetVia())
+	require.Equal(t, int32(20), pb.GetGroupId())
+	require.Equal(t, "ml", pb.GetGroupName())
+
+	// The roles of groups don't say how they're had.
+	pb = MemberRole{RoleID: 2, RoleName: "Viewer", Scope: ScopeGlobal}.Proto()
+	require.Nil(t, pb.Via)
+	require.Nil(t, pb.GroupId)
+	require.Nil(t, pb.GroupName)
+}
@@ -0,0 +1,239 @@
+// Package reassignment hands what a departing user owns to another user in one transaction, so
+// deactivating the user doesn't orphan their experiments, models, workspaces, projects or tasks.
+package reassignment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rbac"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+	"github.com/determined-ai/determined/proto/pkg/userv1"
+)
+
+// Resource is something owned by the user being reassigned from.
+type Resource struct {
+	ID   int    `bun:"id" json:"id"`
+	Name string `bun:"name" json:"name"`
+}
+
+// Task is a notebook, shell, command or TensorBoard owned by the user being reassigned from.
+type Task struct {
+	ID          model.TaskID   `bun:"task_id" json:"id"`
+	Type        model.TaskType `bun:"task_type" json:"type"`
+	Description string         `bun:"description" json:"description"`
+}
+
+// RoleAssignment is a role the user being reassigned from has, which is given to a group.
+type RoleAssignment struct {
+	RoleID      int    `bun:"role_id" json:"role_id"`
+	RoleName    string `bun:"role_name" json:"role_name"`
+	WorkspaceID *int   `bun:"workspace_id" json:"workspace_id"`
+}
+
+// Request is a request to reassign what one user owns to another.
+type Request struct {
+	From model.UserID
+	To   model.User
+	// ToGroupID, if set, is a group given the roles the user being reassigned from has.
+	ToGroupID *int
+	// CanAssignRoles, if set, is called with the roles ToGroupID would be given before they are
+	// given, so they go through the same checks as roles assigned directly.
+	CanAssignRoles func(context.Context, []*rbacv1.GroupRoleAssignment) error
+}
+
+// Reassignment lists everything a reassignment changed, or would change if it was a dry run.
+type Reassignment struct {
+	Experiments     []Resource       `json:"experiments"`
+	Models          []Resource       `json:"models"`
+	ModelVersions   []Resource       `json:"model_versions"`
+	Workspaces      []Resource       `json:"workspaces"`
+	Projects        []Resource       `json:"projects"`
+	Tasks           []Task           `json:"tasks"`
+	RoleAssignments []RoleAssignment `json:"role_assignments"`
+}
+
+// Proto converts a reassignment to its protobuf representation.
+func (r Reassignment) Proto() *userv1.Reassignment {
+	resources := func(rs []Resource) []*userv1.ReassignedResource {
+		pbs := []*userv1.ReassignedResource{}
+		for _, r := range rs {
+			pbs = append(pbs, &userv1.ReassignedResource{Id: int32(r.ID), Name: r.Name})
+		}
+		return pbs
+	}
+	pb := &userv1.Reassignment{
+		Experiments:     resources(r.Experiments),
+		Models:          resources(r.Models),
+		ModelVersions:   resources(r.ModelVersions),
+		Workspaces:      resources(r.Workspaces),
+		Projects:        resources(r.Projects),
+		Tasks:           []*userv1.ReassignedTask{},
+		RoleAssignments: []*userv1.ReassignedRoleAssignment{},
+	}
+	for _, t := range r.Tasks {
+		pb.Tasks = append(pb.Tasks, &userv1.ReassignedTask{
+			Id:          string(t.ID),
+			Type:        taskv1.TaskType(taskv1.TaskType_value["TASK_TYPE_"+string(t.Type)]),
+			Description: t.Description,
+		})
+	}
+	for _, a := range r.RoleAssignments {
+		ra := &userv1.ReassignedRoleAssignment{RoleId: int32(a.RoleID), RoleName: a.RoleName}
+		if a.WorkspaceID != nil {
+			ra.WorkspaceId = ptrs.Ptr(int32(*a.WorkspaceID))
+		}
+		pb.RoleAssignments = append(pb.RoleAssignments, ra)
+	}
+	return pb
+}
+
+var errDryRun = errors.New("dry run")
+
+// Reassign gives what one user owns to another. On a dry run, it reports what would change
+// without changing anything. Reassigning the same way as a dry run makes exactly the reported
+// changes unless the user gains or loses resources in between.
+func Reassign(ctx context.Context, r Request, dryRun bool) (*Reassignment, error) {
+	var res *Reassignment
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var err error
+		if res, err = reassignTx(ctx, tx, r); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, fmt.Errorf("reassigning resources of user %d to user %d: %w", r.From, r.To.ID, err)
+	}
+	return res, nil
+}
+
+func reassignTx(ctx context.Context, tx bun.Tx, r Request) (*Reassignment, error) {
+	res := &Reassignment{
+		Experiments:     []Resource{},
+		Models:          []Resource{},
+		ModelVersions:   []Resource{},
+		Workspaces:      []Resource{},
+		Projects:        []Resource{},
+		Tasks:           []Task{},
+		RoleAssignments: []RoleAssignment{},
+	}
+
+	for _, u := range []struct {
+		table, column, name string
+		dest                *[]Resource
+	}{
+		{"experiments", "owner_id", "config->>'name'", &res.Experiments},
+		{"models", "user_id", "name", &res.Models},
+		{"model_versions", "user_id", `COALESCE(name, (
+			SELECT m.name FROM models AS m WHERE m.id = model_versions.model_id
+		) || ' version ' || version)`, &res.ModelVersions},
+		{"workspaces", "user_id", "name", &res.Workspaces},
+		{"projects", "user_id", "name", &res.Projects},
+	} {
+		err := tx.NewUpdate().
+			Table(u.table).
+			Set("? = ?", bun.Ident(u.column), r.To.ID).
+			Where("? = ?", bun.Ident(u.column), r.From).
+			Returning("id, "+u.name+" AS name").
+			Scan(ctx, u.dest)
+		if err != nil {
+			return nil, fmt.Errorf("reassigning %s: %w", u.table, err)
+		}
+	}
+
+	// The owner of a task is recorded in its command spec. Only tasks that are still running are
+	// reassigned; finished tasks keep their history.
+	owner, err := json.Marshal(r.To)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.NewUpdate().
+		TableExpr("command_state AS c").
+		Set("generic_command_spec = jsonb_set(c.generic_command_spec, '{Base,Owner}', ?::jsonb)",
+			string(owner)).
+		TableExpr("tasks AS t").
+		Where("t.task_id = c.task_id").
+		Where("t.end_time IS NULL").
+		Where("(c.generic_command_spec->'Base'->'Owner'->>'id')::integer = ?", r.From).
+		Returning("c.task_id, t.task_type, c.generic_command_spec->'Config'->>'description' "+
+			"AS description").
+		Scan(ctx, &res.Tasks)
+	if err != nil {
+		return nil, fmt.Errorf("reassigning tasks: %w", err)
+	}
+
+	// Jobs of reassigned experiments and tasks are queued as their new owner.
+	_, err = tx.NewUpdate().
+		Table("jobs").
+		Set("owner_id = ?", r.To.ID).
+		Where("owner_id = ?", r.From).
+		Where(`job_id IN (SELECT job_id FROM experiments WHERE owner_id = ?)
+			OR job_id IN (
+				SELECT t.job_id FROM tasks AS t JOIN command_state AS c ON c.task_id = t.task_id
+				WHERE t.end_time IS NULL
+					AND (c.generic_command_spec->'Base'->'Owner'->>'id')::integer = ?
+			)`, r.To.ID, r.To.ID).
+		Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reassigning jobs: %w", err)
+	}
+
+	if r.ToGroupID != nil {
+		// Only the roles the group doesn't have yet are given, so reassigning to a group that
+		// already has some of them doesn't fail.
+		err = tx.NewSelect().
+			TableExpr("role_assignments AS ra").
+			ColumnExpr("ra.role_id, r.role_name, s.scope_workspace_id AS workspace_id").
+			Join("JOIN groups AS g ON g.id = ra.group_id").
+			Join("JOIN roles AS r ON r.id = ra.role_id").
+			Join("LEFT JOIN role_assignment_scopes AS s ON s.id = ra.scope_id").
+			Where("g.user_id = ?", r.From).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM role_assignments AS existing
+				WHERE existing.group_id = ?
+					AND existing.role_id = ra.role_id
+					AND existing.scope_id IS NOT DISTINCT FROM ra.scope_id
+			)`, *r.ToGroupID).
+			Order("ra.role_id", "workspace_id").
+			Scan(ctx, &res.RoleAssignments)
+		if err != nil {
+			return nil, fmt.Errorf("listing roles to give group %d: %w", *r.ToGroupID, err)
+		}
+
+		assignments := make([]*rbacv1.GroupRoleAssignment, 0, len(res.RoleAssignments))
+		for _, ra := range res.RoleAssignments {
+			a := &rbacv1.GroupRoleAssignment{
+				GroupId: int32(*r.ToGroupID),
+				RoleAssignment: &rbacv1.RoleAssignment{
+					Role:         &rbacv1.Role{RoleId: int32(ra.RoleID)},
+					ScopeCluster: ra.WorkspaceID == nil,
+				},
+			}
+			if ra.WorkspaceID != nil {
+				a.RoleAssignment.ScopeWorkspaceId = ptrs.Ptr(int32(*ra.WorkspaceID))
+			}
+			assignments = append(assignments, a)
+		}
+		if r.CanAssignRoles != nil {
+			if err := r.CanAssignRoles(ctx, assignments); err != nil {
+				return nil, err
+			}
+		}
+		if err := rbac.AddGroupAssignmentsTx(ctx, tx, assignments); err != nil {
+			return nil, fmt.Errorf("giving roles to group %d: %w", *r.ToGroupID, err)
+		}
+	}
+	return res, nil
+}
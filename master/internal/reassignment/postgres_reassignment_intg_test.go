@@ -0,0 +1,178 @@
+//go:build integration
+// +build integration
+
+package reassignment
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rbac"
+	"github.com/determined-ai/determined/master/internal/usergroup"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func resourceIDs(rs []Resource) []int {
+	ids := []int{}
+	for _, r := range rs {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func requireOwner(t *testing.T, table, column string, id int, owner model.UserID) {
+	var actual model.UserID
+	err := db.Bun().NewSelect().Table(table).Column(column).Where("id = ?", id).
+		Scan(context.Background(), &actual)
+	require.NoError(t, err)
+	require.Equal(t, owner, actual, "owner of %s %d", table, id)
+}
+
+func TestReassign(t *testing.T) {
+	ctx := context.Background()
+	from := db.RequireMockUser(t, db.SingleDB())
+	to := db.RequireMockUser(t, db.SingleDB())
+
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	for _, table := range []string{"workspaces", "projects"} {
+		id := workspaceID
+		if table == "projects" {
+			id = projectID
+		}
+		_, err := db.Bun().NewUpdate().Table(table).Set("user_id = ?", from.ID).
+			Where("id = ?", id).Exec(ctx)
+		require.NoError(t, err)
+	}
+	exp := db.RequireMockExperimentProject(t, db.SingleDB(), from, projectID)
+	other := db.RequireMockExperimentProject(t, db.SingleDB(), to, projectID)
+
+	r := Request{From: from.ID, To: to}
+	dryRun, err := Reassign(ctx, r, true)
+	require.NoError(t, err)
+	require.Equal(t, []int{exp.ID}, resourceIDs(dryRun.Experiments))
+	require.Equal(t, []int{workspaceID}, resourceIDs(dryRun.Workspaces))
+	require.Equal(t, []int{projectID}, resourceIDs(dryRun.Projects))
+	require.Empty(t, dryRun.RoleAssignments)
+	requireOwner(t, "experiments", "owner_id", exp.ID, from.ID)
+	requireOwner(t, "workspaces", "user_id", workspaceID, from.ID)
+	requireOwner(t, "projects", "user_id", projectID, from.ID)
+
+	res, err := Reassign(ctx, r, false)
+	require.NoError(t, err)
+	require.Equal(t, dryRun, res, "a dry run reports what's reassigned")
+	requireOwner(t, "experiments", "owner_id", exp.ID, to.ID)
+	requireOwner(t, "experiments", "owner_id", other.ID, to.ID)
+	requireOwner(t, "workspaces", "user_id", workspaceID, to.ID)
+	requireOwner(t, "projects", "user_id", projectID, to.ID)
+
+	res, err = Reassign(ctx, r, false)
+	require.NoError(t, err)
+	require.Empty(t, res.Experiments, "nothing is left to reassign")
+}
+
+func TestReassignRolesToGroup(t *testing.T) {
+	ctx := context.Background()
+	from := db.RequireMockUser(t, db.SingleDB())
+	to := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	group, _, err := usergroup.AddGroupWithMembers(ctx, model.Group{Name: uuid.NewString()})
+	require.NoError(t, err)
+
+	const clusterAdminRoleID, editorRoleID = 1, 5
+	err = rbac.AddRoleAssignments(ctx, nil, []*rbacv1.UserRoleAssignment{
+		{
+			UserId: int32(from.ID),
+			RoleAssignment: &rbacv1.RoleAssignment{
+				Role:         &rbacv1.Role{RoleId: clusterAdminRoleID},
+				ScopeCluster: true,
+			},
+		},
+		{
+			UserId: int32(from.ID),
+			RoleAssignment: &rbacv1.RoleAssignment{
+				Role:             &rbacv1.Role{RoleId: editorRoleID},
+				ScopeWorkspaceId: ptrs.Ptr(int32(workspaceID)),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	groupRoles := func() []int32 {
+		roles, err := rbac.GetRolesAssignedToGroupsTx(ctx, nil, int32(group.ID))
+		require.NoError(t, err)
+		var ids []int32
+		for _, r := range roles {
+			ids = append(ids, int32(r.ID))
+		}
+		return ids
+	}
+
+	// The roles go through the same checks as roles assigned directly, and nothing is
+	// reassigned when they fail.
+	forbidden := errors.New("forbidden")
+	var checked []*rbacv1.GroupRoleAssignment
+	r := Request{
+		From:      from.ID,
+		To:        to,
+		ToGroupID: &group.ID,
+		CanAssignRoles: func(_ context.Context, a []*rbacv1.GroupRoleAssignment) error {
+			checked = a
+			return forbidden
+		},
+	}
+	_, err = Reassign(ctx, r, false)
+	require.ErrorIs(t, err, forbidden)
+	require.Len(t, checked, 2)
+	for _, a := range checked {
+		require.Equal(t, int32(group.ID), a.GroupId)
+		if a.RoleAssignment.Role.RoleId == clusterAdminRoleID {
+			require.True(t, a.RoleAssignment.ScopeCluster)
+		} else {
+			require.Equal(t, int32(workspaceID), *a.RoleAssignment.ScopeWorkspaceId)
+		}
+	}
+	require.Empty(t, groupRoles())
+
+	r.CanAssignRoles = nil
+	res, err := Reassign(ctx, r, false)
+	require.NoError(t, err)
+	require.Equal(t, []RoleAssignment{
+		{RoleID: clusterAdminRoleID, RoleName: "ClusterAdmin"},
+		{RoleID: editorRoleID, RoleName: "Editor", WorkspaceID: &workspaceID},
+	}, res.RoleAssignments)
+	require.ElementsMatch(t, []int32{clusterAdminRoleID, editorRoleID}, groupRoles())
+
+	res, err = Reassign(ctx, r, false)
+	require.NoError(t, err)
+	require.Empty(t, res.RoleAssignments, "roles the group has aren't given again")
+}
@@ -0,0 +1,100 @@
+package relations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// Experiments loads the given relations of experiments, keyed by experiment ID. Each relation is
+// loaded for every experiment in one query. Experiments already come with their owner.
+func Experiments(ctx context.Context, ids []int, include Set) (map[int]*Included, error) {
+	included := make(map[int]*Included, len(ids))
+	for _, id := range ids {
+		included[id] = &Included{}
+	}
+	if len(ids) == 0 {
+		return included, nil
+	}
+
+	if include[LatestValidation] {
+		var rows []struct {
+			ExperimentID int `bun:"experiment_id"`
+			Validation
+		}
+		if err := db.Bun().NewSelect().
+			TableExpr("trials AS t").
+			DistinctOn("t.experiment_id").
+			ColumnExpr("t.experiment_id").
+			ColumnExpr("v.trial_id, v.total_batches, v.end_time").
+			ColumnExpr("v.metrics->'validation_metrics' AS metrics").
+			Join("JOIN validations AS v ON v.id = t.latest_validation_id").
+			Where("t.experiment_id IN (?)", bun.In(ids)).
+			OrderExpr("t.experiment_id, v.end_time DESC").
+			Scan(ctx, &rows); err != nil {
+			return nil, fmt.Errorf("getting latest validations of experiments: %w", err)
+		}
+		for i := range rows {
+			included[rows[i].ExperimentID].LatestValidation = &rows[i].Validation
+		}
+	}
+
+	if include[BestCheckpoint] {
+		var rows []struct {
+			ExperimentID int `bun:"experiment_id"`
+			Checkpoint
+		}
+		if err := db.Bun().NewSelect().
+			TableExpr("checkpoints_view AS c").
+			DistinctOn("c.experiment_id").
+			ColumnExpr("c.experiment_id").
+			ColumnExpr("c.uuid::text AS uuid, c.trial_id, c.steps_completed AS total_batches").
+			ColumnExpr("c.report_time AS end_time, c.resources, c.searcher_metric").
+			Join("JOIN experiments AS e ON e.id = c.experiment_id").
+			Where("c.experiment_id IN (?)", bun.In(ids)).
+			Where("c.state = 'COMPLETED'").
+			Where("c.searcher_metric IS NOT NULL").
+			OrderExpr(`c.experiment_id, c.searcher_metric * (
+				CASE WHEN coalesce((e.config->'searcher'->>'smaller_is_better')::boolean, true)
+				THEN 1 ELSE -1 END) ASC`).
+			Scan(ctx, &rows); err != nil {
+			return nil, fmt.Errorf("getting best checkpoints of experiments: %w", err)
+		}
+		for i := range rows {
+			included[rows[i].ExperimentID].BestCheckpoint = &rows[i].Checkpoint
+		}
+	}
+
+	return included, nil
+}
+
+// Trials loads the owners of trials, keyed by trial ID, in one query. Trials already come with
+// their latest validation and best checkpoint, so the owner is their only relation to load.
+func Trials(ctx context.Context, ids []int, include Set) (map[int]*User, error) {
+	owners := make(map[int]*User, len(ids))
+	if len(ids) == 0 || !include[Owner] {
+		return owners, nil
+	}
+
+	var rows []struct {
+		TrialID int `bun:"trial_id"`
+		User
+	}
+	if err := db.Bun().NewSelect().
+		TableExpr("trials AS t").
+		ColumnExpr("t.id AS trial_id").
+		ColumnExpr("u.id, u.username, u.display_name").
+		Join("JOIN experiments AS e ON e.id = t.experiment_id").
+		Join("JOIN users AS u ON u.id = e.owner_id").
+		Where("t.id IN (?)", bun.In(ids)).
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("getting owners of trials: %w", err)
+	}
+	for i := range rows {
+		owners[rows[i].TrialID] = &rows[i].User
+	}
+	return owners, nil
+}
@@ -0,0 +1,74 @@
+// Package relations loads objects related to experiments and trials, such as their latest
+// validation, best checkpoint and owner, so list endpoints can include them in one response instead
+// of clients fetching each one separately.
+package relations
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Relation is a related object that list endpoints can include in their results.
+type Relation string
+
+const (
+	// LatestValidation is the most recent validation of a trial, or of any trial of an experiment.
+	LatestValidation Relation = "latest_validation"
+	// BestCheckpoint is the completed checkpoint with the best searcher metric.
+	BestCheckpoint Relation = "best_checkpoint"
+	// Owner is the user who owns the experiment.
+	Owner Relation = "owner"
+)
+
+// Set is the set of relations to include.
+type Set map[Relation]bool
+
+// Parse parses the include option of a list request. Each entry may itself be a comma-separated
+// list of relations.
+func Parse(include []string) (Set, error) {
+	set := Set{}
+	for _, r := range strings.Split(strings.Join(include, ","), ",") {
+		switch r := Relation(strings.TrimSpace(r)); r {
+		case "":
+		case LatestValidation, BestCheckpoint, Owner:
+			set[r] = true
+		default:
+			return nil, fmt.Errorf(
+				"unknown relation %q, expected one of %s, %s or %s", r, LatestValidation, BestCheckpoint, Owner)
+		}
+	}
+	return set, nil
+}
+
+// Validation is a validation of a trial.
+type Validation struct {
+	TrialID      int            `bun:"trial_id" json:"trialId"`
+	TotalBatches int            `bun:"total_batches" json:"totalBatches"`
+	EndTime      time.Time      `bun:"end_time" json:"endTime"`
+	Metrics      map[string]any `bun:"metrics" json:"metrics"`
+}
+
+// Checkpoint is a completed checkpoint of a trial.
+type Checkpoint struct {
+	UUID           string           `bun:"uuid" json:"uuid"`
+	TrialID        int              `bun:"trial_id" json:"trialId"`
+	TotalBatches   *int             `bun:"total_batches" json:"totalBatches"`
+	EndTime        time.Time        `bun:"end_time" json:"endTime"`
+	Resources      map[string]int64 `bun:"resources" json:"resources"`
+	SearcherMetric *float64         `bun:"searcher_metric" json:"searcherMetric"`
+}
+
+// User is the public information of a user.
+type User struct {
+	ID          int     `bun:"id" json:"id"`
+	Username    string  `bun:"username" json:"username"`
+	DisplayName *string `bun:"display_name" json:"displayName"`
+}
+
+// Included are the related objects of an experiment. Relations that weren't requested, or that
+// don't exist yet, are nil.
+type Included struct {
+	LatestValidation *Validation
+	BestCheckpoint   *Checkpoint
+}
@@ -0,0 +1,24 @@
+package relations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	set, err := Parse(nil)
+	require.NoError(t, err)
+	require.Empty(t, set)
+
+	set, err = Parse([]string{"owner, best_checkpoint,"})
+	require.NoError(t, err)
+	require.Equal(t, Set{Owner: true, BestCheckpoint: true}, set)
+
+	set, err = Parse([]string{"owner", "latest_validation"})
+	require.NoError(t, err)
+	require.Equal(t, Set{Owner: true, LatestValidation: true}, set)
+
+	_, err = Parse([]string{"owner", "hyperparameters"})
+	require.ErrorContains(t, err, `unknown relation "hyperparameters"`)
+}
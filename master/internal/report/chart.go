@@ -0,0 +1,118 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// Chart is a line chart of a metric of the best trials of an experiment over the batches they
+// trained.
+type Chart struct {
+	Metric string
+	Series []Series
+}
+
+// Series is the values of a metric reported by a trial.
+type Series struct {
+	TrialID int
+	Points  []Point
+}
+
+// Point is the value of a metric after a number of batches.
+type Point struct {
+	Batches int
+	Value   float64
+}
+
+const (
+	chartWidth  = 720
+	chartHeight = 360
+	plotLeft    = 70
+	plotRight   = chartWidth - 140
+	plotTop     = 20
+	plotBottom  = chartHeight - 50
+	chartTicks  = 5
+)
+
+var chartColors = []string{
+	"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+	"#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+}
+
+// SVG draws the chart as a standalone SVG image.
+func (c Chart) SVG() string {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, s := range c.Series {
+		for _, p := range s.Points {
+			minX, maxX = math.Min(minX, float64(p.Batches)), math.Max(maxX, float64(p.Batches))
+			minY, maxY = math.Min(minY, p.Value), math.Max(maxY, p.Value)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" `+
+		`viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`,
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="white"/>`, chartWidth, chartHeight)
+	if math.IsInf(minX, 1) {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">No values of %s</text></svg>`,
+			chartWidth/2, chartHeight/2, html.EscapeString(c.Metric))
+		return b.String()
+	}
+	// A flat line or a single point is drawn in the middle of the plot.
+	if maxX == minX {
+		minX, maxX = minX-1, maxX+1
+	}
+	if maxY == minY {
+		pad := math.Max(math.Abs(minY)*0.1, 1)
+		minY, maxY = minY-pad, maxY+pad
+	}
+	x := func(v float64) float64 {
+		return plotLeft + (v-minX)/(maxX-minX)*(plotRight-plotLeft)
+	}
+	y := func(v float64) float64 {
+		return plotBottom - (v-minY)/(maxY-minY)*(plotBottom-plotTop)
+	}
+
+	fmt.Fprintf(&b, `<g stroke="#888"><line x1="%d" y1="%d" x2="%d" y2="%d"/>`+
+		`<line x1="%d" y1="%d" x2="%d" y2="%d"/></g>`,
+		plotLeft, plotBottom, plotRight, plotBottom, plotLeft, plotTop, plotLeft, plotBottom)
+	for i := 0; i <= chartTicks; i++ {
+		xv := minX + (maxX-minX)*float64(i)/chartTicks
+		yv := minY + (maxY-minY)*float64(i)/chartTicks
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%.0f</text>`,
+			x(xv), plotBottom+16, xv)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%.4g</text>`,
+			plotLeft-6, y(yv)+4, yv)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#eee"/>`,
+			plotLeft+1, y(yv), plotRight, y(yv))
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">Batches</text>`,
+		(plotLeft+plotRight)/2, chartHeight-12)
+	fmt.Fprintf(&b, `<text x="14" y="%d" text-anchor="middle" transform="rotate(-90 14 %d)">%s</text>`,
+		(plotTop+plotBottom)/2, (plotTop+plotBottom)/2, html.EscapeString(c.Metric))
+
+	for i, s := range c.Series {
+		color := chartColors[i%len(chartColors)]
+		points := make([]string, 0, len(s.Points))
+		for _, p := range s.Points {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(float64(p.Batches)), y(p.Value)))
+		}
+		if len(points) == 1 {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`,
+				x(float64(s.Points[0].Batches)), y(s.Points[0].Value), color)
+		} else if len(points) > 1 {
+			fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`,
+				color, strings.Join(points, " "))
+		}
+		legendY := plotTop + 8 + i*16
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2"/>`,
+			plotRight+16, legendY, plotRight+36, legendY, color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">Trial %d</text>`, plotRight+42, legendY+4, s.TrialID)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
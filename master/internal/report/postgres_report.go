@@ -0,0 +1,223 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Build gathers what a report of an experiment shows.
+func Build(ctx context.Context, exp *model.Experiment, opts Options) (*Report, error) {
+	var info struct {
+		Name        string
+		Description string
+		Config      []byte
+		NumTrials   int
+	}
+	if err := db.Bun().NewSelect().
+		TableExpr("experiments AS e").
+		ColumnExpr("e.config->>'name' AS name").
+		ColumnExpr("COALESCE(e.config->>'description', '') AS description").
+		ColumnExpr("e.config::text AS config").
+		ColumnExpr("(SELECT COUNT(*) FROM trials t WHERE t.experiment_id = e.id) AS num_trials").
+		Where("e.id = ?", exp.ID).
+		Scan(ctx, &info); err != nil {
+		return nil, fmt.Errorf("getting experiment %d: %w", exp.ID, err)
+	}
+	config, err := sanitizeConfig(info.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{
+		ExperimentID:    exp.ID,
+		Name:            info.Name,
+		Description:     info.Description,
+		State:           exp.State,
+		Owner:           exp.Username,
+		StartTime:       exp.StartTime,
+		EndTime:         exp.EndTime,
+		Searcher:        exp.Config.Searcher.Name,
+		Metric:          exp.Config.Searcher.Metric,
+		SmallerIsBetter: exp.Config.Searcher.SmallerIsBetter,
+		NumTrials:       info.NumTrials,
+		Config:          config,
+		GeneratedAt:     time.Now(),
+	}
+	if r.Trials, err = bestTrials(ctx, exp, opts.TopTrials); err != nil {
+		return nil, err
+	}
+	r.HParamNames = hparamNames(r.Trials)
+
+	var charted []int
+	for _, t := range r.Trials {
+		if len(charted) < maxChartedTrials {
+			charted = append(charted, t.ID)
+		}
+	}
+	metrics := opts.Metrics
+	if len(metrics) == 0 {
+		metrics = []string{r.Metric}
+	}
+	for _, metric := range metrics {
+		chart, err := validationChart(ctx, metric, charted)
+		if err != nil {
+			return nil, err
+		}
+		r.Charts = append(r.Charts, *chart)
+	}
+	return r, nil
+}
+
+// bestTrials returns the trials of an experiment with the best searcher metric, best first, along
+// with their best checkpoints.
+func bestTrials(ctx context.Context, exp *model.Experiment, limit int) ([]Trial, error) {
+	var rows []struct {
+		ID                  int
+		State               model.State
+		SearcherMetricValue *float64
+		TotalBatches        int
+		HParams             map[string]any
+	}
+	if err := db.Bun().NewSelect().
+		Table("trials").
+		Column("id", "state", "searcher_metric_value", "total_batches", "hparams").
+		Where("experiment_id = ?", exp.ID).
+		Where("searcher_metric_value IS NOT NULL").
+		OrderExpr("searcher_metric_value_signed ASC, id ASC").
+		Limit(limit).
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("getting best trials of experiment %d: %w", exp.ID, err)
+	}
+	if len(rows) == 0 {
+		return []Trial{}, nil
+	}
+
+	trials := make([]Trial, 0, len(rows))
+	ids := make([]int, 0, len(rows))
+	for _, row := range rows {
+		trials = append(trials, Trial{
+			ID:             row.ID,
+			State:          row.State,
+			SearcherMetric: row.SearcherMetricValue,
+			TotalBatches:   row.TotalBatches,
+			HParams:        row.HParams,
+		})
+		ids = append(ids, row.ID)
+	}
+
+	order := "searcher_metric DESC"
+	if exp.Config.Searcher.SmallerIsBetter {
+		order = "searcher_metric ASC"
+	}
+	var checkpoints []struct {
+		UUID           string
+		TrialID        int
+		StepsCompleted int
+	}
+	if err := db.Bun().NewSelect().
+		TableExpr("checkpoints_view").
+		DistinctOn("trial_id").
+		ColumnExpr("uuid::text AS uuid").
+		Column("trial_id", "steps_completed").
+		Where("trial_id IN (?)", bun.In(ids)).
+		Where("state = ?", model.CompletedState).
+		OrderExpr("trial_id, searcher_metric IS NULL, "+order+", steps_completed DESC").
+		Scan(ctx, &checkpoints); err != nil {
+		return nil, fmt.Errorf("getting best checkpoints of experiment %d: %w", exp.ID, err)
+	}
+	byTrial := make(map[int]*Checkpoint, len(checkpoints))
+	for _, c := range checkpoints {
+		byTrial[c.TrialID] = &Checkpoint{
+			UUID:           c.UUID,
+			StepsCompleted: c.StepsCompleted,
+			Location:       checkpointLocation(exp.Config.CheckpointStorage, c.UUID),
+		}
+	}
+	for i := range trials {
+		trials[i].Checkpoint = byTrial[trials[i].ID]
+	}
+	return trials, nil
+}
+
+// validationChart returns a chart of a validation metric of trials.
+func validationChart(ctx context.Context, metric string, trialIDs []int) (*Chart, error) {
+	chart := &Chart{Metric: metric}
+	if len(trialIDs) == 0 {
+		return chart, nil
+	}
+
+	var rows []struct {
+		TrialID      int
+		TotalBatches int
+		Value        *float64
+	}
+	path := model.TrialMetricsJSONPath(true)
+	if err := db.Bun().NewSelect().
+		Table("metrics").
+		Column("trial_id", "total_batches").
+		ColumnExpr(`CASE WHEN jsonb_typeof(metrics->?->?) = 'number'
+			THEN (metrics->?->>?)::float8 END AS value`, path, metric, path, metric).
+		Where("trial_id IN (?)", bun.In(trialIDs)).
+		Where("partition_type = ?", db.ValidationMetric).
+		Where("NOT archived").
+		Order("trial_id", "total_batches").
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("getting validation metric %s: %w", metric, err)
+	}
+
+	series := make(map[int]*Series, len(trialIDs))
+	for _, id := range trialIDs {
+		chart.Series = append(chart.Series, Series{TrialID: id})
+	}
+	for i := range chart.Series {
+		series[chart.Series[i].TrialID] = &chart.Series[i]
+	}
+	for _, row := range rows {
+		if row.Value == nil || math.IsNaN(*row.Value) || math.IsInf(*row.Value, 0) {
+			continue
+		}
+		s := series[row.TrialID]
+		s.Points = append(s.Points, Point{Batches: row.TotalBatches, Value: *row.Value})
+	}
+	return chart, nil
+}
+
+// SaveReport stores a rendered report.
+func SaveReport(ctx context.Context, r *model.ExperimentReport) error {
+	if _, err := db.Bun().NewInsert().Model(r).Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("saving report of experiment %d: %w", r.ExperimentID, err)
+	}
+	return nil
+}
+
+// Reports returns the reports of an experiment, newest first, without their contents.
+func Reports(ctx context.Context, experimentID int) ([]model.ExperimentReport, error) {
+	reports := []model.ExperimentReport{}
+	if err := db.Bun().NewSelect().Model(&reports).
+		ExcludeColumn("content").
+		Where("experiment_id = ?", experimentID).
+		Order("created_at DESC", "id DESC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting reports of experiment %d: %w", experimentID, err)
+	}
+	return reports, nil
+}
+
+// ReportByID returns a report of an experiment, or db.ErrNotFound if it doesn't exist.
+func ReportByID(ctx context.Context, experimentID, id int) (*model.ExperimentReport, error) {
+	var r model.ExperimentReport
+	if err := db.Bun().NewSelect().Model(&r).
+		Where("id = ?", id).
+		Where("experiment_id = ?", experimentID).
+		Scan(ctx); err != nil {
+		return nil, db.MatchSentinelError(err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,312 @@
+// Package report renders reports of the results of experiments as standalone HTML or Markdown
+// files, for sharing results with people who don't use the cluster.
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+const (
+	// DefaultTopTrials is how many of the best trials a report shows by default.
+	DefaultTopTrials = 10
+	// MaxTopTrials caps how many of the best trials a report shows.
+	MaxTopTrials = 100
+	// MaxMetrics caps how many metrics a report charts.
+	MaxMetrics = 10
+	// maxChartedTrials caps how many trials a chart draws, so that it stays readable.
+	maxChartedTrials = 10
+)
+
+// Options are what a report shows.
+type Options struct {
+	Format model.ExperimentReportFormat `json:"format"`
+	// TopTrials is how many of the best trials by the searcher metric the report shows.
+	TopTrials int `json:"top_trials"`
+	// Metrics are the validation metrics charted for the best trials. The searcher metric is
+	// charted if none are given.
+	Metrics []string `json:"metrics"`
+}
+
+// Validate fills in the defaults of o and returns an error wrapping db.ErrInvalidInput if it is
+// invalid.
+func (o *Options) Validate() error {
+	if o.Format == "" {
+		o.Format = model.ExperimentReportFormatHTML
+	}
+	if o.TopTrials == 0 {
+		o.TopTrials = DefaultTopTrials
+	}
+	if !o.Format.Valid() {
+		return fmt.Errorf("%w: format must be html or markdown; got %q", db.ErrInvalidInput, o.Format)
+	}
+	if o.TopTrials < 0 || o.TopTrials > MaxTopTrials {
+		return fmt.Errorf("%w: top_trials must be between 1 and %d; got %d",
+			db.ErrInvalidInput, MaxTopTrials, o.TopTrials)
+	}
+	if len(o.Metrics) > MaxMetrics {
+		return fmt.Errorf("%w: at most %d metrics can be charted; got %d",
+			db.ErrInvalidInput, MaxMetrics, len(o.Metrics))
+	}
+	return nil
+}
+
+// Report is what a report of an experiment shows.
+type Report struct {
+	ExperimentID    int
+	Name            string
+	Description     string
+	State           model.State
+	Owner           string
+	StartTime       time.Time
+	EndTime         *time.Time
+	Searcher        string
+	Metric          string
+	SmallerIsBetter bool
+	NumTrials       int
+	// Config is the experiment's config as YAML, without the settings that may hold secrets.
+	Config      string
+	HParamNames []string
+	Trials      []Trial
+	Charts      []Chart
+	GeneratedAt time.Time
+}
+
+// Trial is a row of the table of the best trials of a report.
+type Trial struct {
+	ID             int
+	State          model.State
+	SearcherMetric *float64
+	TotalBatches   int
+	HParams        map[string]any
+	Checkpoint     *Checkpoint
+}
+
+// HParam returns the value of a hyperparameter of the trial as text.
+func (t Trial) HParam(name string) string {
+	v, ok := t.HParams[name]
+	if !ok {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// Checkpoint is the best checkpoint of a trial.
+type Checkpoint struct {
+	UUID           string
+	StepsCompleted int
+	// Location is where the checkpoint is stored, such as an S3 URL, if it can be told from the
+	// checkpoint storage of the experiment.
+	Location string
+}
+
+// sanitizeConfig returns an experiment config as YAML for a report. The environment and bind
+// mounts, which may hold credentials and paths of the cluster, are left out, and only the type
+// of the checkpoint storage is kept.
+func sanitizeConfig(raw []byte) (string, error) {
+	var config map[string]any
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return "", fmt.Errorf("parsing experiment config: %w", err)
+	}
+	delete(config, "environment")
+	delete(config, "bind_mounts")
+	if storage, ok := config["checkpoint_storage"].(map[string]any); ok {
+		config["checkpoint_storage"] = map[string]any{"type": storage["type"]}
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	y, err := yaml.JSONToYAML(b)
+	if err != nil {
+		return "", fmt.Errorf("converting experiment config to YAML: %w", err)
+	}
+	return string(y), nil
+}
+
+// hparamNames returns the names of the hyperparameters of trials, sorted.
+func hparamNames(trials []Trial) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, t := range trials {
+		for name := range t.HParams {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkpointLocation returns where a checkpoint is stored in the checkpoint storage of its
+// experiment, or an empty string for storage where that can't be told.
+func checkpointLocation(storage expconf.CheckpointStorageConfig, id string) string {
+	prefixed := func(scheme, bucket string, prefix *string) string {
+		p := ""
+		if prefix != nil && *prefix != "" {
+			p = *prefix + "/"
+		}
+		return fmt.Sprintf("%s://%s/%s%s", scheme, bucket, p, id)
+	}
+	switch {
+	case storage.RawS3Config != nil:
+		return prefixed("s3", storage.RawS3Config.Bucket(), storage.RawS3Config.Prefix())
+	case storage.RawGCSConfig != nil:
+		return prefixed("gs", storage.RawGCSConfig.Bucket(), storage.RawGCSConfig.Prefix())
+	case storage.RawSharedFSConfig != nil:
+		path, err := storage.RawSharedFSConfig.PathInHost()
+		if err != nil {
+			return ""
+		}
+		return path + "/" + id
+	case storage.RawSFTPConfig != nil:
+		s := storage.RawSFTPConfig
+		return fmt.Sprintf("sftp://%s:%d%s/%s", s.Host(), s.Port(), s.StoragePath(), id)
+	default:
+		return ""
+	}
+}
+
+// Render renders a report in a format.
+func Render(r *Report, format model.ExperimentReportFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case model.ExperimentReportFormatMarkdown:
+		err = markdownTemplate.Execute(&buf, r)
+	default:
+		err = htmlTemplate.Execute(&buf, r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rendering report of experiment %d: %w", r.ExperimentID, err)
+	}
+	return buf.Bytes(), nil
+}
+
+var templateFuncs = map[string]any{
+	"time": func(t any) string {
+		switch t := t.(type) {
+		case time.Time:
+			return t.UTC().Format(time.RFC3339)
+		case *time.Time:
+			return t.UTC().Format(time.RFC3339)
+		default:
+			return ""
+		}
+	},
+	// cell escapes text for a cell of a Markdown table.
+	"cell": func(s string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+	},
+	"metric": func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.6g", *v)
+	},
+	"svg": func(c Chart) htmltemplate.HTML {
+		// The chart escapes the text it draws.
+		return htmltemplate.HTML(c.SVG()) // nolint: gosec
+	},
+	"svgDataURI": func(c Chart) string {
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(c.SVG()))
+	},
+}
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(templateFuncs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Experiment {{.ExperimentID}}: {{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 960px; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f4f4f4; }
+pre { background: #f8f8f8; padding: 1em; overflow-x: auto; }
+code { font-size: 90%; }
+</style>
+</head>
+<body>
+<h1>Experiment {{.ExperimentID}}: {{.Name}}</h1>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<table>
+<tr><th>State</th><td>{{.State}}</td></tr>
+<tr><th>Owner</th><td>{{.Owner}}</td></tr>
+<tr><th>Started</th><td>{{time .StartTime}}</td></tr>
+{{if .EndTime}}<tr><th>Ended</th><td>{{time .EndTime}}</td></tr>{{end}}
+<tr><th>Searcher</th><td>{{.Searcher}}</td></tr>
+<tr><th>Searcher metric</th><td>{{.Metric}} ({{if .SmallerIsBetter}}smaller{{else}}larger{{end}} is better)</td></tr>
+<tr><th>Trials</th><td>{{.NumTrials}}</td></tr>
+</table>
+<h2>Best trials</h2>
+{{if .Trials}}<table>
+<tr><th>Trial</th><th>State</th><th>{{.Metric}}</th><th>Batches</th>{{range .HParamNames}}<th>{{.}}</th>{{end}}<th>Best checkpoint</th></tr>
+{{range $t := .Trials}}<tr><td>{{$t.ID}}</td><td>{{$t.State}}</td><td>{{metric $t.SearcherMetric}}</td><td>{{$t.TotalBatches}}</td>{{range $.HParamNames}}<td>{{$t.HParam .}}</td>{{end}}<td>{{with $t.Checkpoint}}<code>{{.UUID}}</code> at {{.StepsCompleted}} batches{{if .Location}}<br><code>{{.Location}}</code>{{end}}{{end}}</td></tr>
+{{end}}</table>{{else}}<p>No trial has reported the searcher metric.</p>{{end}}
+{{range .Charts}}<h2>{{.Metric}}</h2>
+{{svg .}}
+{{end}}<h2>Configuration</h2>
+<pre><code>{{.Config}}</code></pre>
+<p><small>Generated at {{time .GeneratedAt}}.</small></p>
+</body>
+</html>
+`))
+
+var markdownTemplate = texttemplate.Must(texttemplate.New("markdown").Funcs(templateFuncs).Parse(
+	`# Experiment {{.ExperimentID}}: {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+| | |
+|---|---|
+| State | {{.State}} |
+| Owner | {{.Owner}} |
+| Started | {{time .StartTime}} |
+{{if .EndTime}}| Ended | {{time .EndTime}} |
+{{end}}| Searcher | {{.Searcher}} |
+| Searcher metric | {{.Metric}} ({{if .SmallerIsBetter}}smaller{{else}}larger{{end}} is better) |
+| Trials | {{.NumTrials}} |
+
+## Best trials
+{{if .Trials}}
+| Trial | State | {{cell .Metric}} | Batches |{{range .HParamNames}} {{cell .}} |{{end}} Best checkpoint |
+|---|---|---|---|{{range .HParamNames}}---|{{end}}---|
+{{range $t := .Trials}}| {{$t.ID}} | {{$t.State}} | {{metric $t.SearcherMetric}} | {{$t.TotalBatches}} |{{range $.HParamNames}} {{cell ($t.HParam .)}} |{{end}} {{with $t.Checkpoint}}` + "`{{.UUID}}`" + ` at {{.StepsCompleted}} batches{{if .Location}}, ` + "`{{.Location}}`" + `{{end}}{{end}} |
+{{end}}{{else}}
+No trial has reported the searcher metric.
+{{end}}{{range .Charts}}
+## {{.Metric}}
+
+![{{.Metric}}]({{svgDataURI .}})
+{{end}}
+## Configuration
+
+` + "```yaml" + `
+{{.Config}}` + "```" + `
+
+_Generated at {{time .GeneratedAt}}._
+`))
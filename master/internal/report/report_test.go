@@ -0,0 +1,128 @@
+package report
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	opts := Options{}
+	require.NoError(t, opts.Validate())
+	require.Equal(t, model.ExperimentReportFormatHTML, opts.Format)
+	require.Equal(t, DefaultTopTrials, opts.TopTrials)
+
+	for _, bad := range []Options{
+		{Format: "pdf"},
+		{TopTrials: -1},
+		{TopTrials: MaxTopTrials + 1},
+		{Metrics: make([]string, MaxMetrics+1)},
+	} {
+		err := bad.Validate()
+		require.True(t, errors.Is(err, db.ErrInvalidInput), "%+v: %v", bad, err)
+	}
+}
+
+func TestSanitizeConfig(t *testing.T) {
+	config, err := sanitizeConfig([]byte(`{
+		"name": "mnist",
+		"environment": {"environment_variables": ["AWS_SECRET_ACCESS_KEY=hunter2"]},
+		"bind_mounts": [{"host_path": "/secret"}],
+		"checkpoint_storage": {"type": "s3", "bucket": "b", "secret_key": "hunter2"}
+	}`))
+	require.NoError(t, err)
+	require.Contains(t, config, "name: mnist")
+	require.Contains(t, config, "type: s3")
+	require.NotContains(t, config, "hunter2")
+	require.NotContains(t, config, "/secret")
+	require.NotContains(t, config, "bucket")
+}
+
+func TestCheckpointLocation(t *testing.T) {
+	s3 := expconf.CheckpointStorageConfig{RawS3Config: &expconf.S3Config{
+		RawBucket: ptrs.Ptr("bucket"),
+		RawPrefix: ptrs.Ptr("runs"),
+	}}
+	require.Equal(t, "s3://bucket/runs/abc", checkpointLocation(s3, "abc"))
+
+	gcs := expconf.CheckpointStorageConfig{RawGCSConfig: &expconf.GCSConfig{
+		RawBucket: ptrs.Ptr("bucket"),
+	}}
+	require.Equal(t, "gs://bucket/abc", checkpointLocation(gcs, "abc"))
+
+	require.Equal(t, "", checkpointLocation(expconf.CheckpointStorageConfig{}, "abc"))
+}
+
+func sampleReport() *Report {
+	end := time.Date(2024, 12, 21, 13, 0, 0, 0, time.UTC)
+	return &Report{
+		ExperimentID: 7,
+		Name:         "mnist <pytorch>",
+		State:        model.CompletedState,
+		Owner:        "admin",
+		StartTime:    time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC),
+		EndTime:      &end,
+		Searcher:     "adaptive_asha",
+		Metric:       "validation_loss",
+		NumTrials:    2,
+		Config:       "name: mnist\n",
+		HParamNames:  []string{"lr"},
+		Trials: []Trial{{
+			ID:             3,
+			State:          model.CompletedState,
+			SearcherMetric: ptrs.Ptr(0.125),
+			TotalBatches:   100,
+			HParams:        map[string]any{"lr": 0.01},
+			Checkpoint: &Checkpoint{
+				UUID:           "abc",
+				StepsCompleted: 100,
+				Location:       "s3://bucket/abc",
+			},
+		}},
+		Charts: []Chart{{
+			Metric: "validation_loss",
+			Series: []Series{{TrialID: 3, Points: []Point{{50, 0.5}, {100, 0.125}}}},
+		}},
+		GeneratedAt: end,
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	b, err := Render(sampleReport(), model.ExperimentReportFormatHTML)
+	require.NoError(t, err)
+	out := string(b)
+	require.Contains(t, out, "mnist &lt;pytorch&gt;")
+	require.Contains(t, out, "<td>0.125</td>")
+	require.Contains(t, out, "<td>0.01</td>")
+	require.Contains(t, out, "s3://bucket/abc")
+	require.Contains(t, out, "<svg")
+	require.Contains(t, out, "<polyline")
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	b, err := Render(sampleReport(), model.ExperimentReportFormatMarkdown)
+	require.NoError(t, err)
+	out := string(b)
+	require.Contains(t, out, "# Experiment 7: mnist <pytorch>")
+	require.Contains(t, out, "| 3 | COMPLETED | 0.125 | 100 | 0.01 | `abc` at 100 batches")
+	require.Contains(t, out, "](data:image/svg+xml;base64,")
+	require.Contains(t, out, "```yaml\nname: mnist\n```")
+}
+
+func TestChartSVG(t *testing.T) {
+	empty := Chart{Metric: "loss"}.SVG()
+	require.Contains(t, empty, "No values of loss")
+
+	single := Chart{Metric: "loss", Series: []Series{{TrialID: 1, Points: []Point{{10, 1}}}}}.SVG()
+	require.Contains(t, single, "<circle")
+	require.NotContains(t, single, "NaN")
+	require.True(t, strings.HasSuffix(single, "</svg>"))
+}
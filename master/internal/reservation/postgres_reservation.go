@@ -0,0 +1,91 @@
+package reservation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// CreateReservation reserves a fraction of a resource pool unless it conflicts with the existing
+// reservations of the pool, returning how it fits with them either way. Reservations of the same
+// pool are created one at a time, so two that conflict can't both be created. It returns
+// db.ErrNotFound if the workspace or user doesn't exist.
+func CreateReservation(
+	ctx context.Context, r *model.ResourcePoolReservation,
+) (*Conflicts, error) {
+	var conflicts *Conflicts
+	err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			"SELECT pg_advisory_xact_lock(hashtext('resource_pool_reservations'), hashtext(?))",
+			r.ResourcePool,
+		); err != nil {
+			return err
+		}
+		var existing []model.ResourcePoolReservation
+		if err := tx.NewSelect().Model(&existing).
+			Where("resource_pool = ?", r.ResourcePool).
+			Where("start_time < ? AND end_time > ?", r.EndTime, r.StartTime).
+			Scan(ctx); err != nil {
+			return err
+		}
+		conflicts = FindConflicts(existing, r.StartTime, r.EndTime, r.SlotFraction)
+		if conflicts.Conflict {
+			return nil
+		}
+		_, err := tx.NewInsert().Model(r).Returning("id, created_at").Exec(ctx)
+		return db.MatchSentinelError(err)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating resource pool reservation: %w", err)
+	}
+	return conflicts, nil
+}
+
+// ReservationByID returns a reservation, or db.ErrNotFound if it doesn't exist.
+func ReservationByID(ctx context.Context, id int) (*model.ResourcePoolReservation, error) {
+	var r model.ResourcePoolReservation
+	if err := db.Bun().NewSelect().Model(&r).Where("id = ?", id).Scan(ctx); err != nil {
+		return nil, db.MatchSentinelError(err)
+	}
+	return &r, nil
+}
+
+// Reservations returns the reservations of a pool, or of all pools if pool is empty, ordered by
+// start time. Reservations that ended before since are left out.
+func Reservations(
+	ctx context.Context, pool string, since time.Time,
+) ([]model.ResourcePoolReservation, error) {
+	reservations := []model.ResourcePoolReservation{}
+	q := db.Bun().NewSelect().Model(&reservations).
+		Where("end_time > ?", since).
+		Order("start_time", "id")
+	if pool != "" {
+		q = q.Where("resource_pool = ?", pool)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting resource pool reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+// DeleteReservation deletes a reservation, releasing its slots early if it is underway. It returns
+// db.ErrNotFound if the reservation doesn't exist.
+func DeleteReservation(ctx context.Context, id int) error {
+	res, err := db.Bun().NewDelete().Model((*model.ResourcePoolReservation)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting resource pool reservation %d: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package reservation manages calendar reservations of fractions of resource pools for workspaces
+// and users, and finds the reservations that conflict with a new one.
+package reservation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// DescriptionMaxLength caps the length of the description of a reservation.
+const DescriptionMaxLength = 1000
+
+// Conflicts is how a proposed reservation fits with the reservations of its pool that overlap it.
+// The reservations conflict if, at some point of the window, they would together reserve more than
+// the whole pool.
+type Conflicts struct {
+	Overlapping      []model.ResourcePoolReservation `json:"overlapping"`
+	PeakSlotFraction float64                         `json:"peak_slot_fraction"`
+	Conflict         bool                            `json:"conflict"`
+}
+
+// Validate returns an error wrapping db.ErrInvalidInput if r is invalid.
+func Validate(r *model.ResourcePoolReservation, now time.Time) error {
+	if r.ResourcePool == "" {
+		return fmt.Errorf("%w: resource pool must be set", db.ErrInvalidInput)
+	}
+	if (r.WorkspaceID == nil) == (r.UserID == nil) {
+		return fmt.Errorf("%w: exactly one of workspace_id and user_id must be set",
+			db.ErrInvalidInput)
+	}
+	if r.SlotFraction <= 0 || r.SlotFraction > 1 {
+		return fmt.Errorf("%w: slot fraction must be greater than 0 and at most 1; got %v",
+			db.ErrInvalidInput, r.SlotFraction)
+	}
+	if !r.EndTime.After(r.StartTime) {
+		return fmt.Errorf("%w: end time must be after start time", db.ErrInvalidInput)
+	}
+	if !r.EndTime.After(now) {
+		return fmt.Errorf("%w: end time must be in the future", db.ErrInvalidInput)
+	}
+	if n := utf8.RuneCountInString(r.Description); n > DescriptionMaxLength {
+		return fmt.Errorf("%w: description must be at most %d characters; got %d",
+			db.ErrInvalidInput, DescriptionMaxLength, n)
+	}
+	return nil
+}
+
+// FindConflicts returns how reserving fraction of a pool between start and end fits with the
+// existing reservations of the pool.
+func FindConflicts(
+	existing []model.ResourcePoolReservation, start, end time.Time, fraction float64,
+) *Conflicts {
+	type event struct {
+		at    time.Time
+		delta float64
+	}
+	c := &Conflicts{Overlapping: []model.ResourcePoolReservation{}}
+	var events []event
+	for _, r := range existing {
+		if !r.Overlaps(start, end) {
+			continue
+		}
+		c.Overlapping = append(c.Overlapping, r)
+		events = append(events, event{at: r.StartTime, delta: r.SlotFraction})
+		events = append(events, event{at: r.EndTime, delta: -r.SlotFraction})
+	}
+	// Reservations end before the ones that start at the same time, so back-to-back reservations
+	// don't add up.
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+
+	reserved := fraction
+	c.PeakSlotFraction = reserved
+	for _, e := range events {
+		reserved += e.delta
+		c.PeakSlotFraction = max(c.PeakSlotFraction, reserved)
+	}
+	// Allow for rounding, so that, say, fractions of 0.7 and 0.3 fill the pool without conflict.
+	c.Conflict = c.PeakSlotFraction > 1+1e-9
+	return c
+}
@@ -0,0 +1,72 @@
+package reservation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+	valid := model.ResourcePoolReservation{
+		ResourcePool: "default",
+		WorkspaceID:  ptrs.Ptr(1),
+		SlotFraction: 0.5,
+		StartTime:    now.Add(time.Hour),
+		EndTime:      now.Add(2 * time.Hour),
+	}
+	require.NoError(t, Validate(&valid, now))
+
+	for _, change := range []func(r *model.ResourcePoolReservation){
+		func(r *model.ResourcePoolReservation) { r.ResourcePool = "" },
+		func(r *model.ResourcePoolReservation) { r.WorkspaceID = nil },
+		func(r *model.ResourcePoolReservation) { r.UserID = ptrs.Ptr(model.UserID(1)) },
+		func(r *model.ResourcePoolReservation) { r.SlotFraction = 0 },
+		func(r *model.ResourcePoolReservation) { r.SlotFraction = 1.5 },
+		func(r *model.ResourcePoolReservation) { r.EndTime = r.StartTime },
+		func(r *model.ResourcePoolReservation) {
+			r.StartTime, r.EndTime = now.Add(-2*time.Hour), now.Add(-time.Hour)
+		},
+	} {
+		r := valid
+		change(&r)
+		require.ErrorIs(t, Validate(&r, now), db.ErrInvalidInput)
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	day := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	reservation := func(id, fromHour, toHour int, fraction float64) model.ResourcePoolReservation {
+		return model.ResourcePoolReservation{
+			ID:           id,
+			SlotFraction: fraction,
+			StartTime:    day.Add(time.Duration(fromHour) * time.Hour),
+			EndTime:      day.Add(time.Duration(toHour) * time.Hour),
+		}
+	}
+	existing := []model.ResourcePoolReservation{
+		reservation(1, 9, 12, 0.5),
+		reservation(2, 12, 17, 0.5),
+		reservation(3, 18, 20, 0.75),
+	}
+
+	// Back-to-back reservations don't add up.
+	c := FindConflicts(existing, day.Add(10*time.Hour), day.Add(14*time.Hour), 0.5)
+	require.False(t, c.Conflict)
+	require.InDelta(t, 1.0, c.PeakSlotFraction, 1e-9)
+	require.Len(t, c.Overlapping, 2)
+
+	c = FindConflicts(existing, day.Add(16*time.Hour), day.Add(19*time.Hour), 0.5)
+	require.True(t, c.Conflict)
+	require.InDelta(t, 1.25, c.PeakSlotFraction, 1e-9)
+	require.Equal(t, []int{2, 3}, []int{c.Overlapping[0].ID, c.Overlapping[1].ID})
+
+	c = FindConflicts(existing, day.Add(20*time.Hour), day.Add(22*time.Hour), 1)
+	require.False(t, c.Conflict)
+	require.Empty(t, c.Overlapping)
+}
@@ -104,6 +104,7 @@ func (m *Master) restoreExperiment(expModel *model.Experiment) error {
 		return errors.Wrapf(err, "retrieving full user on restart")
 	}
 	taskSpec.Owner = owner
+	taskSpec.WorkspaceID = workspaceID
 
 	token, err := user.StartSession(context.Background(), owner)
 	if err != nil {
@@ -225,7 +226,7 @@ func (e *internalExperiment) snapshotAndSave() {
 		e.syslog.WithError(err).Errorf("failed to snapshot experiment, fault tolerance is lost")
 		return
 	}
-	err = e.db.SaveSnapshot(e.ID, experimentSnapshotVersion, es)
+	err = e.snapshots.save(e.db, e.ID, es)
 	if err != nil {
 		e.faultToleranceEnabled = false
 		e.syslog.WithError(err).Errorf("failed to persist experiment snapshot, fault tolerance is lost")
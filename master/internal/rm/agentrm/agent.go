@@ -7,6 +7,7 @@ import (
 	"os"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -18,8 +19,10 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/proxy"
 	"github.com/determined-ai/determined/master/internal/rm/rmevents"
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/versioncompat"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/cproto"
@@ -52,11 +55,35 @@ type (
 		// started tracks if we have received the AgentStarted message.
 		started bool
 		version string
+		// compat is the verdict of the master on the version the agent connected with.
+		compat versioncompat.Verdict
+		// supportsExec is set if the agent can start processes in running containers.
+		supportsExec bool
+		// outboundOnly is set if the agent accepts no connections from the master, so the services
+		// of its containers are proxied through tunnels over its connection.
+		outboundOnly bool
+		// tunnels holds the data the agent sent over the tunnels through it, by ID.
+		tunnels map[string]chan aproto.TunnelData
+		// execSessions holds the output of the exec processes relayed through the agent, by ID.
+		execSessions map[string]chan sproto.ExecOutput
+		// session numbers the state transitions sent to agents that resume sessions after
+		// transient disconnects, so that those the agent didn't receive are replayed. It is nil
+		// for agents too old to resume sessions.
+		session *aproto.Session[aproto.AgentMessage]
+		// resumedStarts are the containers whose start is replayed to an agent that resumed its
+		// session, which the agent can't report as reattached yet.
+		resumedStarts map[cproto.ID]bool
+		// sessionResumed is set while an agent that resumed its session reattaches its containers.
+		sessionResumed bool
 
 		// TODO(ilia): Maybe maxZeroSlotContainers should be an attribute of a resource pool,
 		// and not be copied to agents.
 		maxZeroSlotContainers int
 		agentReconnectWait    time.Duration
+		diskPressureThreshold float64
+		imageGC               *config.ImageGCConfig
+		images                *imageTracker
+		versions              *versioncompat.Registry
 		// awaitingReconnect et al contain reconnect related state. The pattern for
 		// reconnecting agents is
 		//  * They have a small window to reconnect.
@@ -102,6 +129,7 @@ type (
 	// allocateFreeDevices calls agentState.allocateFreeDevices.
 	allocateFreeDevices struct {
 		slots       int
+		scratchGiB  int
 		containerID cproto.ID
 	}
 	// allocateFreeDevicesResponse is a response to allocateFreeDevices.
@@ -117,6 +145,8 @@ type (
 func newAgent(
 	id aproto.ID,
 	agentUpdates *queue.Queue[agentUpdatedEvent],
+	images *imageTracker,
+	versions *versioncompat.Registry,
 	resourcePoolName string,
 	rpConfig *config.ResourcePoolConfig,
 	opts *aproto.MasterSetAgentOptions,
@@ -135,9 +165,15 @@ func newAgent(
 		resourcePoolName:      resourcePoolName,
 		maxZeroSlotContainers: rpConfig.MaxAuxContainersPerAgent,
 		agentReconnectWait:    time.Duration(rpConfig.AgentReconnectWait),
+		diskPressureThreshold: rpConfig.DiskPressureThreshold,
+		imageGC:               rpConfig.ImageGC,
+		images:                images,
+		versions:              versions,
 		opts:                  opts,
 		agentState:            restoredAgentState,
 		unregister:            unregister,
+		execSessions:          map[string]chan sproto.ExecOutput{},
+		tunnels:               map[string]chan aproto.TunnelData{},
 	}
 
 	if restoring := a.agentState != nil; restoring {
@@ -174,6 +210,7 @@ func (a *agent) AllocateFreeDevices(msg allocateFreeDevices) (allocateFreeDevice
 	if err != nil {
 		return allocateFreeDevicesResponse{}, err
 	}
+	a.agentState.reserveScratch(msg.containerID, msg.scratchGiB)
 	return allocateFreeDevicesResponse{devices: devices}, nil
 }
 
@@ -217,13 +254,27 @@ func (a *agent) startTaskContainer(msg sproto.StartTaskContainer) {
 		WithField("slots", len(msg.StartContainer.Container.Devices))
 	log.Infof("starting container")
 
-	a.socket.Outbox <- aproto.AgentMessage{StartContainer: &msg.StartContainer}
+	a.send(aproto.AgentMessage{StartContainer: &msg.StartContainer})
+	a.images.containerStarted(msg.StartContainer.Container.ID,
+		msg.StartContainer.Spec.RunSpec.ContainerConfig.Image, time.Now())
 
 	if err := a.agentState.startContainer(msg); err != nil {
 		log.WithError(err).Error("failed to update agent state")
 	}
 }
 
+// DtrainNetworkInterface returns the network interface the agent's containers should communicate
+// with other agents' containers over, or "" if it isn't known.
+func (a *agent) DtrainNetworkInterface() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.agentState == nil {
+		return ""
+	}
+	return a.agentState.dtrainNetworkInterface()
+}
+
 func (a *agent) KillTaskContainer(msg sproto.KillTaskContainer) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -245,11 +296,17 @@ func (a *agent) killTaskContainer(msg sproto.KillTaskContainer) {
 	killMsg := aproto.SignalContainer{
 		ContainerID: msg.ContainerID, Signal: syscall.SIGKILL,
 	}
-	a.socket.Outbox <- aproto.AgentMessage{SignalContainer: &killMsg}
+	a.send(aproto.AgentMessage{SignalContainer: &killMsg})
 }
 
 func (a *agent) stop(cause error) {
 	defer a.unregister()
+	defer a.versions.Forget(versioncompat.KindAgent, string(a.id))
+	a.endExecSessions(errors.New("agent stopped"))
+	a.closeTunnels(errors.New("agent stopped"))
+	if a.outboundOnly {
+		proxy.DefaultProxy.UnregisterTunnel(tunnelHost(a.id))
+	}
 
 	if cause != nil {
 		a.syslog.WithError(cause).WithFields(logrus.Fields{
@@ -362,6 +419,7 @@ func (a *agent) handleWebsocketConnection(msg webSocketRequest) error {
 
 	a.socket = socket
 	a.version = msg.echoCtx.QueryParam("version")
+	a.compat = a.versions.Report(versioncompat.KindAgent, string(a.id), a.version)
 
 	lastColonIndex := strings.LastIndex(msg.echoCtx.Request().RemoteAddr, ":")
 	if lastColonIndex == -1 {
@@ -372,20 +430,29 @@ func (a *agent) handleWebsocketConnection(msg webSocketRequest) error {
 
 	a.adjustAgentIPAddrIfRunningDevClusterOnHpcUsingAnSSHTunnel(msg)
 
-	var masterSetAgentOptions aproto.AgentMessage
+	received, _ := strconv.ParseUint(msg.echoCtx.QueryParam("received"), 10, 64)
+	sessionResume, replay := a.resumeSession(msg.echoCtx.QueryParam("session"), received)
+
+	optsCopy := *a.opts
+	optsCopy.Session = sessionResume
 	if a.awaitingReconnect {
-		optsCopy := *a.opts
 		optsCopy.ContainersToReattach = a.gatherContainersToReattach()
-		masterSetAgentOptions = aproto.AgentMessage{MasterSetAgentOptions: &optsCopy}
-	} else {
-		masterSetAgentOptions = aproto.AgentMessage{MasterSetAgentOptions: a.opts}
 	}
+	optsCopy.VersionCompatibility = &aproto.VersionCompatibility{
+		Compatible: a.compat.Compatible,
+		Policy:     a.compat.Policy,
+		Reason:     a.compat.Reason,
+	}
+	masterSetAgentOptions := aproto.AgentMessage{MasterSetAgentOptions: &optsCopy}
 
 	if a.awaitingRestore {
 		a.awaitingRestore = false
 	}
 
 	a.socket.Outbox <- masterSetAgentOptions
+	for _, msg := range replay {
+		a.socket.Outbox <- msg
+	}
 
 	if a.awaitingReconnect {
 		a.syslog.Info("agent reconnected")
@@ -601,6 +668,16 @@ func (a *agent) HandleIncomingWebsocketMessage(msg *aproto.MasterMessage) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	if a.session != nil {
+		if !a.session.Receive(msg.Seq, msg.Ack) {
+			a.syslog.Debugf("dropping replayed message %d", msg.Seq)
+			return
+		}
+		if msg.Seq != 0 {
+			defer a.acknowledge()
+		}
+	}
+
 	switch {
 	case msg.AgentStarted != nil:
 		a.syslog.Infof("agent connected ip: %v resource pool: %s slots: %d",
@@ -633,9 +710,21 @@ func (a *agent) HandleIncomingWebsocketMessage(msg *aproto.MasterMessage) {
 				a.stop(resourcePoolErr)
 				return
 			}
+			// Labels, network interfaces and scratch capacity aren't part of the agent snapshot, so
+			// agents restored on master restart only learn them when they reconnect.
+			a.agentState.labels = msg.AgentStarted.Labels
+			a.agentState.networkInterfaces = msg.AgentStarted.NetworkInterfaces
+			a.agentState.scratchCapacityGiB = msg.AgentStarted.ScratchCapacityGiB
 		} else {
 			a.agentStarted(msg.AgentStarted)
 		}
+		a.applyVersionCompatibility()
+		a.recordClockOffset(msg.AgentStarted.Time)
+		a.supportsExec = msg.AgentStarted.SupportsExec
+		a.outboundOnly = msg.AgentStarted.OutboundOnly
+		if a.outboundOnly {
+			proxy.DefaultProxy.RegisterTunnel(tunnelHost(a.id), a.dialTunnel)
+		}
 
 		a.started = true
 
@@ -676,6 +765,14 @@ func (a *agent) HandleIncomingWebsocketMessage(msg *aproto.MasterMessage) {
 				a.syslog.Errorf("error recording task stats %s", err)
 			}
 		}
+	case msg.AgentDiskUsage != nil:
+		a.diskUsageReported(msg.AgentDiskUsage)
+	case msg.ExecOutput != nil:
+		a.execOutput(*msg.ExecOutput)
+	case msg.TunnelData != nil:
+		a.tunnelData(*msg.TunnelData)
+	case msg.Ack != 0:
+		// The message only acknowledges messages, which the session recorded above.
 
 	default:
 		check.Panic(errors.Errorf("error parsing incoming message"))
@@ -700,6 +797,31 @@ func (a *agent) agentStarted(agentStarted *aproto.AgentStarted) {
 	a.notifyListeners()
 }
 
+// applyVersionCompatibility keeps work off agents whose version the master refuses to schedule
+// onto.
+func (a *agent) applyVersionCompatibility() {
+	if refused := a.compat.Refused(); refused != a.agentState.versionRefused {
+		if refused {
+			a.syslog.Warnf("not scheduling onto agent with incompatible version: %s", a.compat.Reason)
+		}
+		a.agentState.versionRefused = refused
+		a.notifyListeners()
+	}
+}
+
+// recordClockOffset records how far the agent's clock is behind the master's, estimated from the
+// time the agent sent a message at, so the timestamps of its logs can be normalized. The estimate
+// includes the time the message took to arrive.
+func (a *agent) recordClockOffset(sent time.Time) {
+	if sent.IsZero() {
+		return
+	}
+	offset := time.Since(sent)
+	if err := db.SetAgentClockOffset(context.TODO(), string(a.id), offset); err != nil {
+		a.syslog.WithError(err).Warn("failed to record agent clock offset")
+	}
+}
+
 func (a *agent) containerStateChanged(sc aproto.ContainerStateChanged) {
 	aID, ok := a.agentState.containerAllocation[sc.Container.ID]
 	if !ok {
@@ -716,7 +838,11 @@ func (a *agent) containerStateChanged(sc aproto.ContainerStateChanged) {
 
 	switch sc.Container.State {
 	case cproto.Running:
-		if sc.ContainerStarted.ProxyAddress == "" {
+		switch {
+		case sc.ContainerStarted.ProxyAddress != "":
+		case a.outboundOnly:
+			sc.ContainerStarted.ProxyAddress = tunnelHost(a.id)
+		default:
 			sc.ContainerStarted.ProxyAddress = a.address
 		}
 	case cproto.Terminated:
@@ -724,12 +850,44 @@ func (a *agent) containerStateChanged(sc aproto.ContainerStateChanged) {
 			WithError(sc.ContainerStopped.Failure).
 			Infof("container %s terminated", sc.Container.ID)
 		delete(a.agentState.containerAllocation, sc.Container.ID)
+		a.images.containerStopped(sc.Container.ID)
 	}
 
 	rmevents.Publish(aID, sproto.FromContainerStateChanged(sc))
 	a.agentState.containerStateChanged(sc)
 }
 
+// diskUsageReported stops scheduling onto the agent while its image partition is fuller than the
+// disk pressure threshold of its resource pool, and removes images from it if image GC is enabled.
+func (a *agent) diskUsageReported(usage *aproto.AgentDiskUsage) {
+	if !a.started {
+		return
+	}
+	a.images.seen(usage.Images, time.Now())
+
+	utilization := diskUtilization(usage)
+	pressure := a.diskPressureThreshold > 0 && utilization > a.diskPressureThreshold
+	if pressure != a.agentState.diskPressure {
+		if pressure {
+			a.syslog.Warnf("agent is under disk pressure with %.0f%% of its image partition used, "+
+				"not scheduling onto it", utilization*100)
+		} else {
+			a.syslog.Infof("agent is no longer under disk pressure")
+		}
+		a.agentState.diskPressure = pressure
+		a.notifyListeners()
+	}
+
+	// Agents with restricted features may not support removing images.
+	if a.imageGC == nil || a.compat.Restricted() {
+		return
+	}
+	if ids := a.images.imagesToRemove(usage, *a.imageGC); len(ids) > 0 {
+		a.syslog.Infof("removing %d unused images to free up disk space", len(ids))
+		a.socket.Outbox <- aproto.AgentMessage{RemoveImages: &aproto.RemoveImages{IDs: ids}}
+	}
+}
+
 func (a *agent) Summarize() model.AgentSummary {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -770,6 +928,9 @@ func (a *agent) gatherContainersToReattach() []aproto.ContainerReattach {
 	result := make([]aproto.ContainerReattach, 0, len(a.agentState.containerAllocation))
 
 	for _, container := range a.agentState.containerState {
+		if a.resumedStarts[container.ID] {
+			continue
+		}
 		result = append(result, aproto.ContainerReattach{Container: *container})
 	}
 	a.syslog.Infof("going to try to reattach containers (%v)", result)
@@ -785,6 +946,14 @@ func (a *agent) handleContainersReattached(agentStarted *aproto.AgentStarted) er
 
 	recovered := map[cproto.ID]aproto.ContainerReattachAck{}
 	doomed := map[cproto.ID]aproto.ContainerReattachAck{}
+	sessionResumed := a.sessionResumed
+	a.sessionResumed = false
+	for cid := range a.resumedStarts {
+		if container, ok := a.agentState.containerState[cid]; ok {
+			recovered[cid] = aproto.ContainerReattachAck{Container: *container}
+		}
+	}
+	a.resumedStarts = nil
 
 	for _, containerRestored := range agentStarted.ContainersReattached {
 		cid := containerRestored.Container.ID
@@ -820,7 +989,15 @@ func (a *agent) handleContainersReattached(agentStarted *aproto.AgentStarted) er
 			continue
 		}
 
-		if a.agentState.containerState[cid].State != containerRestored.Container.State {
+		// The transitions of agents that resumed their session that happened while they were away
+		// follow the reattach.
+		known := a.agentState.containerState[cid].State
+		if sessionResumed && known.Before(containerRestored.Container.State) {
+			recovered[cid] = containerRestored
+			continue
+		}
+
+		if known != containerRestored.Container.State {
 			a.syslog.Warnf(
 				"reattached container %s has changed state: %s to %s",
 				cid, a.agentState.containerState[cid].State,
@@ -873,12 +1050,12 @@ func (a *agent) clearNonReattachedContainers(
 		// To me, this is a hack to make up for an architectural deficiency. I think this problem and others would go
 		// away if we merged task.AllocationService and ResourceManagers into a single entity. There is too much shared
 		// responsibility between them.
-		a.socket.Outbox <- aproto.AgentMessage{
+		a.send(aproto.AgentMessage{
 			SignalContainer: &aproto.SignalContainer{
 				ContainerID: cID,
 				Signal:      syscall.SIGKILL,
 			},
-		}
+		})
 	}
 
 	return a.agentState.clearUnlessRecovered(recovered)
@@ -891,9 +1068,60 @@ func (a *agent) defaultReattachFailureMessage() aproto.ContainerStopped {
 	)
 }
 
+// send sends a message to the agent, numbering it if it is a state transition to an agent that
+// resumes sessions.
+func (a *agent) send(msg aproto.AgentMessage) {
+	if a.session != nil {
+		msg = a.session.Send(msg)
+	}
+	a.socket.Outbox <- msg
+}
+
+// acknowledge acknowledges the messages received from an agent that resumes sessions.
+func (a *agent) acknowledge() {
+	if a.socket != nil {
+		a.socket.Outbox <- aproto.AgentMessage{Ack: a.session.Received()}
+	}
+}
+
+// resumeSession resumes the session an agent connected with if it is the one kept while awaiting
+// the agent's reconnect, and returns the verdict on it and the messages to replay to the agent.
+// Otherwise, it starts a new session. Agents that don't send a session ID don't resume sessions.
+func (a *agent) resumeSession(
+	id string, received uint64,
+) (*aproto.SessionResume, []aproto.AgentMessage) {
+	if id == "" {
+		a.session = nil
+		return nil, nil
+	}
+	if a.awaitingReconnect && a.session != nil && a.session.ID() == id {
+		if replay, ok := a.session.Resume(received); ok {
+			a.syslog.Infof("agent resumed its session, replaying %d messages", len(replay))
+			a.sessionResumed = true
+			a.resumedStarts = map[cproto.ID]bool{}
+			for _, msg := range replay {
+				if msg.StartContainer != nil {
+					a.resumedStarts[msg.StartContainer.Container.ID] = true
+				}
+			}
+			return &aproto.SessionResume{Resumed: true, Received: a.session.Received()}, replay
+		}
+		a.syslog.Warn("agent reconnected with a session that fell too far behind to resume")
+	}
+	if a.session == nil {
+		a.session = aproto.NewSession[aproto.AgentMessage](id)
+	} else {
+		a.session.Reset(id)
+	}
+	return &aproto.SessionResume{}, nil
+}
+
 func (a *agent) socketDisconnected() {
 	a.socket = nil
 	a.awaitingReconnect = true
+	// Exec sessions can't be resumed, since their output while the agent was away is lost.
+	a.endExecSessions(errRecovering)
+	a.closeTunnels(errRecovering)
 
 	timer := time.AfterFunc(a.agentReconnectWait, a.HandleReconnectTimeout)
 	a.reconnectTimers = append(a.reconnectTimers, timer)
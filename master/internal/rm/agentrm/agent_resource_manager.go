@@ -23,10 +23,12 @@ import (
 	"github.com/determined-ai/determined/master/internal/rm/rmevents"
 	"github.com/determined-ai/determined/master/internal/rm/rmutils"
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/versioncompat"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/command"
 	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/master/pkg/syncx/queue"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/jobv1"
@@ -40,9 +42,10 @@ func New(
 	e *echo.Echo,
 	config *config.ResourceManagerWithPoolsConfig,
 	opts *aproto.MasterSetAgentOptions,
+	versions *versioncompat.Registry,
 	cert *tls.Certificate,
 ) (*ResourceManager, error) {
-	agentService, agentUpdates := newAgentService(config.ResourcePools, opts)
+	agentService, agentUpdates := newAgentService(config.ResourcePools, opts, versions)
 
 	e.GET("/agents", func(c echo.Context) error {
 		if !c.IsWebSocket() {
@@ -288,6 +291,41 @@ func (a *ResourceManager) GetJobQ(rpName rm.ResourcePoolName) (map[model.JobID]*
 	return pool.GetJobQ(), nil
 }
 
+// GetWorkloadClasses implements rm.ResourceManager.
+func (a *ResourceManager) GetWorkloadClasses(
+	rpName rm.ResourcePoolName,
+) (*sproto.WorkloadClassesSummary, error) {
+	pool, err := a.poolByName(rpName.String())
+	if err != nil {
+		return nil, err
+	}
+	return pool.WorkloadClasses(), nil
+}
+
+// SetWorkloadClasses implements rm.ResourceManager.
+func (a *ResourceManager) SetWorkloadClasses(
+	rpName rm.ResourcePoolName, cfg *config.WorkloadClassesConfig,
+) error {
+	pool, err := a.poolByName(rpName.String())
+	if err != nil {
+		return err
+	}
+	pool.SetWorkloadClasses(cfg)
+	return nil
+}
+
+// SetReservations implements rm.ResourceManager.
+func (a *ResourceManager) SetReservations(
+	rpName rm.ResourcePoolName, reservations []model.ResourcePoolReservation,
+) error {
+	pool, err := a.poolByName(rpName.String())
+	if err != nil {
+		return err
+	}
+	pool.SetReservations(reservations)
+	return nil
+}
+
 // GetJobQueueStatsRequest implements rm.ResourceManager.
 func (a *ResourceManager) GetJobQueueStatsRequest(
 	msg *apiv1.GetJobQueueStatsRequest,
@@ -484,6 +522,17 @@ func (a *ResourceManager) SetGroupPriority(msg sproto.SetGroupPriority) error {
 	return pool.SetGroupPriority(msg)
 }
 
+// SetPriorityInJob implements rm.ResourceManager.
+func (a *ResourceManager) SetPriorityInJob(msg sproto.SetPriorityInJob) error {
+	pool, err := a.poolByName(msg.ResourcePool)
+	if err != nil {
+		return fmt.Errorf("set priority in job found no resource pool with name %s: %w",
+			msg.ResourcePool, err)
+	}
+	pool.SetPriorityInJob(msg)
+	return nil
+}
+
 // SetGroupWeight implements rm.ResourceManager.
 func (a *ResourceManager) SetGroupWeight(msg sproto.SetGroupWeight) error {
 	pool, err := a.poolByName(msg.ResourcePool)
@@ -528,10 +577,46 @@ func (a *ResourceManager) TaskContainerDefaults(
 func (a *ResourceManager) ValidateResources(
 	msg sproto.ValidateResourcesRequest,
 ) ([]command.LaunchWarning, error) {
+	if msg.Scratch != nil {
+		if msg.Scratch.Backing() != expconf.ScratchBackingLocal {
+			return nil, rmerrors.UnsupportedError(fmt.Sprintf(
+				"%s scratch volumes unsupported in the agent RM", msg.Scratch.Backing()))
+		}
+		pool, err := a.poolByName(msg.ResourcePool)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"validating request for (%s, %d): %w", msg.ResourcePool, msg.Slots, err)
+		}
+		if !pool.hasScratchCapacity(msg.Scratch.SizeGiB()) {
+			return nil, fmt.Errorf(
+				"no agent in resource pool %s has %d GiB of scratch space",
+				msg.ResourcePool, msg.Scratch.SizeGiB())
+		}
+	}
+
 	if msg.Slots == 0 {
 		return nil, nil
 	}
 
+	var warnings []command.LaunchWarning
+	if msg.MinGPUMemoryMiB > 0 {
+		pool, err := a.poolByName(msg.ResourcePool)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"validating request for (%s, %d): %w", msg.ResourcePool, msg.Slots, err)
+		}
+		// Agents may be scaling up, restarting or provisioned on demand, so the task only waits
+		// for one with enough memory unless the pool can never have one.
+		switch possible, current := pool.gpuMemoryAvailability(msg.MinGPUMemoryMiB); {
+		case !possible:
+			return nil, fmt.Errorf(
+				"resource pool %s launches instances without GPUs, which can't have %d MiB of memory",
+				msg.ResourcePool, msg.MinGPUMemoryMiB)
+		case !current:
+			warnings = append(warnings, command.CurrentGPUMemoryUnavailable)
+		}
+	}
+
 	if msg.IsSingleNode {
 		pool, err := a.poolByName(msg.ResourcePool)
 		if err != nil {
@@ -543,16 +628,16 @@ func (a *ResourceManager) ValidateResources(
 		if !resp.Fulfillable {
 			return nil, errors.New("request unfulfillable, please try requesting less slots")
 		}
-		return nil, nil
+		return warnings, nil
 	}
 	switch exceeded, err := a.CheckMaxSlotsExceeded(&msg); {
 	case err != nil:
 		return nil, fmt.Errorf(
 			"validating request for (%s, %d): %w", msg.ResourcePool, msg.Slots, err)
 	case exceeded:
-		return []command.LaunchWarning{command.CurrentSlotsExceeded}, nil
+		return append(warnings, command.CurrentSlotsExceeded), nil
 	default:
-		return nil, nil
+		return warnings, nil
 	}
 }
 
@@ -765,14 +850,13 @@ func (a *ResourceManager) createResourcePoolSummary(
 		resp.MaxAgentStartingPeriod = float32(startingPeriodSecs)
 	}
 	if pool.Scheduler != nil {
-		if pool.Scheduler.FittingPolicy == best {
+		// Packing and spreading are reported as the fitting policies they refine.
+		switch pool.Scheduler.FittingPolicy {
+		case best, pack:
 			resp.SchedulerFittingPolicy = resourcepoolv1.FittingPolicy_FITTING_POLICY_BEST
-		}
-		if pool.Scheduler.FittingPolicy == worst {
+		case worst, spread:
 			resp.SchedulerFittingPolicy = resourcepoolv1.FittingPolicy_FITTING_POLICY_WORST
-		}
-
-		if pool.Scheduler.FittingPolicy != best && pool.Scheduler.FittingPolicy != worst {
+		default:
 			a.syslog.Errorf("unrecognized scheduler fitting policy")
 			return &resourcepoolv1.ResourcePool{}, err
 		}
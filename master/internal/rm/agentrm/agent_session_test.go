@@ -0,0 +1,52 @@
+package agentrm
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/ws"
+)
+
+func TestAgentResumeSession(t *testing.T) {
+	outbox := make(chan aproto.AgentMessage, 16)
+	a := &agent{
+		syslog: logrus.WithField("component", "agent"),
+		id:     "test",
+		socket: &ws.WebSocket[*aproto.MasterMessage, aproto.AgentMessage]{Outbox: outbox},
+	}
+
+	resume, replay := a.resumeSession("s1", 0)
+	require.Equal(t, &aproto.SessionResume{}, resume)
+	require.Empty(t, replay)
+
+	a.send(aproto.AgentMessage{StartContainer: &aproto.StartContainer{
+		Container: cproto.Container{ID: "c1"},
+	}})
+	require.Equal(t, uint64(1), (<-outbox).Seq)
+
+	// Only agents reconnecting within the grace window with the same session resume it, and the
+	// starts replayed to them aren't expected back as reattached.
+	a.awaitingReconnect = true
+	resume, replay = a.resumeSession("s1", 0)
+	require.Equal(t, &aproto.SessionResume{Resumed: true}, resume)
+	require.Len(t, replay, 1)
+	require.True(t, a.resumedStarts["c1"])
+	require.True(t, a.sessionResumed)
+
+	// Acknowledged messages aren't replayed.
+	a.HandleIncomingWebsocketMessage(&aproto.MasterMessage{Ack: 1})
+	_, replay = a.resumeSession("s1", 1)
+	require.Empty(t, replay)
+
+	resume, _ = a.resumeSession("s2", 1)
+	require.False(t, resume.Resumed)
+
+	// Agents that don't send a session don't resume one.
+	resume, _ = a.resumeSession("", 0)
+	require.Nil(t, resume)
+	require.Nil(t, a.session)
+}
@@ -48,10 +48,28 @@ type agentState struct {
 	handler          *agent
 	Devices          map[device.Device]*cproto.ID
 	resourcePoolName string
+	labels           map[string]string
 	enabled          bool
 	draining         bool
 	uuid             uuid.UUID
 
+	// networkInterfaces are the interfaces the agent reported its containers could communicate
+	// with other agents' containers over.
+	networkInterfaces []aproto.NetworkInterface
+
+	// scratchCapacityGiB is the space the agent has for scratch volumes, and containerScratchGiB is
+	// how much of it each container on the agent reserved.
+	scratchCapacityGiB  int
+	containerScratchGiB map[cproto.ID]int
+
+	// diskPressure is set while the partition the agent's images are on is fuller than the disk
+	// pressure threshold of its resource pool.
+	diskPressure bool
+
+	// versionRefused is set while the version of the agent is incompatible with the master and its
+	// policy refuses to schedule onto such agents.
+	versionRefused bool
+
 	maxZeroSlotContainers int
 
 	slotStates          map[device.ID]*slot
@@ -71,6 +89,7 @@ func newAgentState(id aproto.ID, maxZeroSlotContainers int) *agentState {
 		slotStates:            make(map[device.ID]*slot),
 		containerAllocation:   make(map[cproto.ID]model.AllocationID),
 		containerState:        make(map[cproto.ID]*cproto.Container),
+		containerScratchGiB:   make(map[cproto.ID]int),
 		uuid:                  uuid.New(),
 	}
 }
@@ -185,9 +204,26 @@ func (a *agentState) allocateFreeDevices(slots int, cid cproto.ID) ([]device.Dev
 	return devices, nil
 }
 
+// reserveScratch reserves scratch space on the agent for a container.
+func (a *agentState) reserveScratch(cid cproto.ID, gib int) {
+	if gib > 0 {
+		a.containerScratchGiB[cid] = gib
+	}
+}
+
+// freeScratchGiB returns the scratch space on the agent that no container has reserved.
+func (a *agentState) freeScratchGiB() int {
+	free := a.scratchCapacityGiB
+	for _, gib := range a.containerScratchGiB {
+		free -= gib
+	}
+	return free
+}
+
 // deallocateContainer deallocates containers.
 func (a *agentState) deallocateContainer(id cproto.ID) {
 	delete(a.containerState, id)
+	delete(a.containerScratchGiB, id)
 	for d, cid := range a.Devices {
 		if cid != nil && *cid == id {
 			a.Devices[d] = nil
@@ -205,9 +241,14 @@ func (a *agentState) deepCopy() *agentState {
 		enabled:               a.enabled,
 		draining:              a.draining,
 		containerState:        maps.Clone(a.containerState),
+		scratchCapacityGiB:    a.scratchCapacityGiB,
+		containerScratchGiB:   maps.Clone(a.containerScratchGiB),
+		diskPressure:          a.diskPressure,
+		versionRefused:        a.versionRefused,
 		// TODO(ilia): Deepcopy of `slotStates` may be necessary one day.
 		slotStates:       a.slotStates,
 		resourcePoolName: a.resourcePoolName,
+		labels:           a.labels,
 	}
 
 	return copiedAgent
@@ -244,6 +285,9 @@ func (a *agentState) removeDevice(device device.Device) {
 // agentStarted initializes slots from AgentStarted.Devices.
 func (a *agentState) agentStarted(agentStarted *aproto.AgentStarted) {
 	msg := agentStarted
+	a.labels = msg.Labels
+	a.networkInterfaces = msg.NetworkInterfaces
+	a.scratchCapacityGiB = msg.ScratchCapacityGiB
 	for _, d := range msg.Devices {
 		enabled := slotEnabled{
 			agentEnabled: true,
@@ -258,6 +302,21 @@ func (a *agentState) agentStarted(agentStarted *aproto.AgentStarted) {
 	}
 }
 
+// dtrainNetworkInterface picks the network interface the agent's containers should communicate with
+// other agents' containers over: the one the agent reaches the master through or else the only one
+// it reported. It returns "" if no interface the agent reported clearly is.
+func (a *agentState) dtrainNetworkInterface() string {
+	for _, iface := range a.networkInterfaces {
+		if iface.RoutesToMaster {
+			return iface.Name
+		}
+	}
+	if len(a.networkInterfaces) == 1 {
+		return a.networkInterfaces[0].Name
+	}
+	return ""
+}
+
 func (a *agentState) checkAgentStartedDevicesMatch(
 	agentStarted *aproto.AgentStarted,
 ) error {
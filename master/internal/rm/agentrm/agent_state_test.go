@@ -448,3 +448,19 @@ func TestSlotStates(t *testing.T) {
 	state.enable()
 	require.Equal(t, 2, state.numSlots())
 }
+
+func TestDtrainNetworkInterface(t *testing.T) {
+	state := newAgentState(aproto.ID(uuid.NewString()), 64)
+	require.Empty(t, state.dtrainNetworkInterface())
+
+	state.networkInterfaces = []aproto.NetworkInterface{{Name: "ens5", Addresses: []string{"10.0.0.2"}}}
+	require.Equal(t, "ens5", state.dtrainNetworkInterface())
+
+	state.networkInterfaces = append(state.networkInterfaces, aproto.NetworkInterface{
+		Name: "ib0", Addresses: []string{"192.168.0.2"},
+	})
+	require.Empty(t, state.dtrainNetworkInterface(), "ambiguous without a route to the master")
+
+	state.networkInterfaces[1].RoutesToMaster = true
+	require.Equal(t, "ib0", state.dtrainNetworkInterface())
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/versioncompat"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/syncx/queue"
@@ -27,6 +28,8 @@ func TestAgentFastFailAfterFirstConnect2(t *testing.T) {
 	a := newAgent(
 		"test",
 		queue.New[agentUpdatedEvent](),
+		newImageTracker(),
+		versioncompat.NewRegistry("", config.VersionCompatibilityConfig{}),
 		"default",
 		&config.ResourcePoolConfig{},
 		&aproto.MasterSetAgentOptions{
@@ -14,6 +14,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/connsave"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/versioncompat"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/syncx/queue"
@@ -40,6 +41,8 @@ type agents struct {
 
 	agents       *tasklist.Registry[aproto.ID, *agent]
 	agentUpdates *queue.Queue[agentUpdatedEvent]
+	images       *imageTracker
+	versions     *versioncompat.Registry
 	poolConfigs  []config.ResourcePoolConfig
 	opts         *aproto.MasterSetAgentOptions
 }
@@ -47,12 +50,15 @@ type agents struct {
 func newAgentService(
 	poolConfigs []config.ResourcePoolConfig,
 	opts *aproto.MasterSetAgentOptions,
+	versions *versioncompat.Registry,
 ) (*agents, *queue.Queue[agentUpdatedEvent]) {
 	agentUpdates := queue.New[agentUpdatedEvent]()
 	a := &agents{
 		syslog:       logrus.WithField("component", "agents"),
 		agents:       tasklist.NewRegistry[aproto.ID, *agent](),
 		agentUpdates: agentUpdates,
+		images:       newImageTracker(),
+		versions:     versions,
 		poolConfigs:  poolConfigs,
 		opts:         opts,
 	}
@@ -217,6 +223,8 @@ func (a *agents) createAgent(
 	return newAgent(
 		id,
 		a.agentUpdates,
+		a.images,
+		a.versions,
 		resourcePool,
 		poolConfig,
 		opts,
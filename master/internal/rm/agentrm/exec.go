@@ -0,0 +1,115 @@
+package agentrm
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+// execOutputBufferSize is the number of outputs of an exec process buffered for the user. Outputs
+// are relayed while holding the agent's lock, so a session that falls further behind is ended
+// rather than blocking every other message from the agent.
+const execOutputBufferSize = 256
+
+// Exec starts a process in a container on the agent and relays it.
+func (a *agent) Exec(containerID cproto.ID, req sproto.ExecRequest) (*sproto.ExecSession, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case a.awaitingReconnect || a.socket == nil:
+		return nil, errRecovering
+	case !a.supportsExec:
+		return nil, fmt.Errorf("%w: agent %s must be upgraded", sproto.ErrExecUnsupported, a.id)
+	}
+
+	execID := uuid.New().String()
+	output := make(chan sproto.ExecOutput, execOutputBufferSize)
+	a.execSessions[execID] = output
+	a.socket.Outbox <- aproto.AgentMessage{ExecStart: &aproto.ExecStart{
+		ExecID:      execID,
+		ContainerID: containerID,
+		Cmd:         req.Cmd,
+		TTY:         req.TTY,
+		Rows:        req.Rows,
+		Cols:        req.Cols,
+	}}
+	a.syslog.WithField("container-id", containerID).Infof("started exec session %s", execID)
+
+	send := func(in sproto.ExecInput) error {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if _, ok := a.execSessions[execID]; !ok {
+			return errors.New("exec session ended")
+		}
+		msg := &aproto.ExecInput{ExecID: execID, Data: in.Data, CloseInput: in.CloseInput}
+		if in.Resize != nil {
+			msg.Resize = &aproto.ExecResize{Rows: in.Resize.Rows, Cols: in.Resize.Cols}
+		}
+		a.socket.Outbox <- aproto.AgentMessage{ExecInput: msg}
+		return nil
+	}
+	detach := func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.endExecSession(execID, nil)
+	}
+	return sproto.NewExecSession(output, send, detach), nil
+}
+
+// execOutput relays the output of an exec process to its session.
+func (a *agent) execOutput(msg aproto.ExecOutput) {
+	output, ok := a.execSessions[msg.ExecID]
+	if !ok {
+		// The session was closed while the agent was still sending its output.
+		return
+	}
+
+	out := sproto.ExecOutput{Data: msg.Data, ExitCode: msg.ExitCode}
+	if msg.Error != "" {
+		out.Err = errors.New(msg.Error)
+	}
+	select {
+	case output <- out:
+	default:
+		a.endExecSession(msg.ExecID, errors.New("exec session fell behind its output"))
+		return
+	}
+	if out.ExitCode != nil || out.Err != nil {
+		delete(a.execSessions, msg.ExecID)
+		close(output)
+	}
+}
+
+// endExecSession stops relaying an exec process, ending it with err if it is set.
+func (a *agent) endExecSession(execID string, err error) {
+	output, ok := a.execSessions[execID]
+	if !ok {
+		return
+	}
+	delete(a.execSessions, execID)
+	if err != nil {
+		select {
+		case output <- sproto.ExecOutput{Err: err}:
+		default:
+		}
+	}
+	close(output)
+	if a.socket != nil && !a.awaitingReconnect {
+		a.socket.Outbox <- aproto.AgentMessage{ExecInput: &aproto.ExecInput{
+			ExecID: execID, Detach: true,
+		}}
+	}
+}
+
+// endExecSessions ends every exec session relayed through the agent with err.
+func (a *agent) endExecSessions(err error) {
+	for execID := range a.execSessions {
+		a.endExecSession(execID, err)
+	}
+}
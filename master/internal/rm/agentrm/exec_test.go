@@ -0,0 +1,74 @@
+package agentrm
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/ws"
+)
+
+func newExecTestAgent(supportsExec bool) (*agent, chan aproto.AgentMessage) {
+	outbox := make(chan aproto.AgentMessage, 16)
+	return &agent{
+		syslog:       logrus.WithField("component", "agent"),
+		id:           "test",
+		socket:       &ws.WebSocket[*aproto.MasterMessage, aproto.AgentMessage]{Outbox: outbox},
+		supportsExec: supportsExec,
+		execSessions: map[string]chan sproto.ExecOutput{},
+	}, outbox
+}
+
+func TestAgentExec(t *testing.T) {
+	a, outbox := newExecTestAgent(true)
+	session, err := a.Exec("container", sproto.ExecRequest{Cmd: []string{"bash"}, TTY: true})
+	require.NoError(t, err)
+	start := (<-outbox).ExecStart
+	require.NotNil(t, start)
+	require.Equal(t, []string{"bash"}, start.Cmd)
+
+	require.NoError(t, session.Send(sproto.ExecInput{Resize: &sproto.ExecSize{Rows: 24, Cols: 80}}))
+	in := (<-outbox).ExecInput
+	require.Equal(t, start.ExecID, in.ExecID)
+	require.Equal(t, &aproto.ExecResize{Rows: 24, Cols: 80}, in.Resize)
+
+	a.HandleIncomingWebsocketMessage(&aproto.MasterMessage{ExecOutput: &aproto.ExecOutput{
+		ExecID: start.ExecID, Data: []byte("hi"),
+	}})
+	a.HandleIncomingWebsocketMessage(&aproto.MasterMessage{ExecOutput: &aproto.ExecOutput{
+		ExecID: start.ExecID, ExitCode: ptrs.Ptr(3),
+	}})
+	require.Equal(t, []byte("hi"), (<-session.Output).Data)
+	require.Equal(t, ptrs.Ptr(3), (<-session.Output).ExitCode)
+	_, ok := <-session.Output
+	require.False(t, ok)
+
+	// Input to an ended session fails, and closing it doesn't detach from the process again.
+	require.Error(t, session.Send(sproto.ExecInput{Data: []byte("ls\n")}))
+	session.Close()
+	require.Empty(t, outbox)
+}
+
+func TestAgentExecUnsupported(t *testing.T) {
+	a, _ := newExecTestAgent(false)
+	_, err := a.Exec("container", sproto.ExecRequest{Cmd: []string{"bash"}})
+	require.ErrorIs(t, err, sproto.ErrExecUnsupported)
+}
+
+func TestAgentExecSessionsEndOnDisconnect(t *testing.T) {
+	a, outbox := newExecTestAgent(true)
+	session, err := a.Exec("container", sproto.ExecRequest{Cmd: []string{"bash"}})
+	require.NoError(t, err)
+	<-outbox
+
+	a.endExecSessions(errRecovering)
+	out := <-session.Output
+	require.ErrorIs(t, out.Err, errRecovering)
+	_, ok := <-session.Output
+	require.False(t, ok)
+	require.True(t, (<-outbox).ExecInput.Detach)
+}
@@ -214,6 +214,14 @@ func calculateGroupStates(
 		if state.MaxSlots != nil {
 			state.slotDemand = mathx.Min(state.slotDemand, *state.MaxSlots)
 		}
+		// Start the allocations of a group with the highest priority within their job first, and
+		// release the ones with the lowest first.
+		sort.SliceStable(state.pendingReqs, func(i, j int) bool {
+			return tasklist.ComparePriorityInJob(state.pendingReqs[i], state.pendingReqs[j]) > 0
+		})
+		sort.SliceStable(state.allocatedReqs, func(i, j int) bool {
+			return tasklist.ComparePriorityInJob(state.allocatedReqs[i], state.allocatedReqs[j]) < 0
+		})
 	}
 
 	return states
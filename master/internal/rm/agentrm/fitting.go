@@ -120,7 +120,10 @@ func findDedicatedAgentFits(
 	// 2) Multi-agent tasks will receive all the slots on every agent they are scheduled on.
 	agentsByNumSlots := make(map[int][]*agentState)
 	for _, agent := range agentStates {
-		constraints := []HardConstraint{agentSlotUnusedSatisfied, agentPermittedSatisfied}
+		constraints := []HardConstraint{
+			agentSlotUnusedSatisfied, agentPermittedSatisfied, gpuMemorySatisfied,
+			agentConstraintsSatisfied, scratchSatisfied, diskPressureSatisfied, versionSatisfied,
+		}
 		if isViable(req, agent, constraints...) {
 			agentsByNumSlots[agent.numEmptySlots()] = append(
 				agentsByNumSlots[agent.numEmptySlots()],
@@ -224,7 +227,9 @@ func findSharedAgentFit(
 ) *fittingState {
 	var candidates candidateList
 	for _, agent := range agents {
-		if !isViable(req, agent, slotsSatisfied, maxZeroSlotContainersSatisfied, agentPermittedSatisfied) {
+		if !isViable(req, agent, slotsSatisfied, maxZeroSlotContainersSatisfied,
+			agentPermittedSatisfied, gpuMemorySatisfied, agentConstraintsSatisfied,
+			scratchSatisfied, diskPressureSatisfied, versionSatisfied) {
 			continue
 		}
 
@@ -8,8 +8,10 @@ import (
 )
 
 const (
-	best  = "best"
-	worst = "worst"
+	best   = "best"
+	worst  = "worst"
+	pack   = "pack"
+	spread = "spread"
 )
 
 // Hard Constraints.
@@ -28,6 +30,49 @@ func maxZeroSlotContainersSatisfied(req *sproto.AllocateRequest, agent *agentSta
 	return true
 }
 
+// gpuMemorySatisfied checks that every device of the agent has at least the GPU memory the task
+// requires. Devices that don't report their memory never qualify.
+func gpuMemorySatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	minMemory := req.FittingRequirements.MinGPUMemoryMiB
+	if minMemory == 0 || req.SlotsNeeded == 0 {
+		return true
+	}
+	if len(agent.Devices) == 0 {
+		return false
+	}
+	for d := range agent.Devices {
+		if d.MemoryMiB < minMemory {
+			return false
+		}
+	}
+	return true
+}
+
+// agentConstraintsSatisfied checks that the labels of the agent match every agent constraint of the
+// task.
+func agentConstraintsSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	for _, c := range req.FittingRequirements.AgentConstraints {
+		if !c.Matches(agent.labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// scratchSatisfied checks that the agent has room for the scratch volume the task requires.
+func scratchSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	gib := req.FittingRequirements.ScratchGiB
+	return gib == 0 || gib <= agent.freeScratchGiB()
+}
+
+func diskPressureSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool {
+	return !agent.diskPressure
+}
+
+func versionSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool {
+	return !agent.versionRefused
+}
+
 func agentSlotUnusedSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool {
 	return agent.numUsedSlots() == 0
 }
@@ -63,6 +108,34 @@ func WorstFit(req *sproto.AllocateRequest, agent *agentState) float64 {
 	}
 }
 
+// PackFit returns a float affinity score between 0 and 1 for the affinity between the task and
+// the agent. Like BestFit, it fills the most utilized agents first, but it only starts using an
+// agent with no slots in use when no agent in use fits, and then prefers the smallest one. This
+// keeps as many whole agents free as possible for the multi-agent tasks of distributed jobs, which
+// only run on agents with every slot free.
+func PackFit(req *sproto.AllocateRequest, agent *agentState) float64 {
+	switch {
+	case req.SlotsNeeded == 0:
+		return BestFit(req, agent)
+	case agent.numUsedSlots() != 0:
+		return 0.5 + 0.5/(1.0+float64(agent.numEmptySlots()))
+	default:
+		return 0.5 / (1.0 + float64(agent.numSlots()))
+	}
+}
+
+// SpreadFit returns a float affinity score between 0 and 1 for the affinity between the task and
+// the agent. This method attempts to allocate tasks to the agent running the fewest containers,
+// then with the smallest share of its slots in use, so that tasks share agents, and with them
+// host resources such as CPUs, memory and network, as little as possible.
+func SpreadFit(_ *sproto.AllocateRequest, agent *agentState) float64 {
+	used := 0.0
+	if agent.numSlots() != 0 {
+		used = float64(agent.numUsedSlots()) / float64(agent.numSlots())
+	}
+	return 1.0 / (1.0 + float64(len(agent.containerState)) + used)
+}
+
 // MakeFitFunction returns the corresponding fitting function.
 func MakeFitFunction(fittingPolicy string) func(
 	*sproto.AllocateRequest, *agentState) float64 {
@@ -71,6 +144,10 @@ func MakeFitFunction(fittingPolicy string) func(
 		return WorstFit
 	case best:
 		return BestFit
+	case pack:
+		return PackFit
+	case spread:
+		return SpreadFit
 	default:
 		panic(fmt.Sprintf("invalid scheduler fit: %s", fittingPolicy))
 	}
@@ -1,11 +1,14 @@
 package agentrm
 
 import (
+	"fmt"
 	"testing"
 
 	"gotest.tools/assert"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestBestFit(t *testing.T) {
@@ -77,3 +80,105 @@ func TestWorstFit(t *testing.T) {
 		newFakeAgentState(t, "agent8", 10, 5, 100, 0),
 	), 0.5)
 }
+
+func TestPackFit(t *testing.T) {
+	assert.Equal(t, PackFit(
+		&sproto.AllocateRequest{SlotsNeeded: 0},
+		newFakeAgentState(t, "agent1", 0, 0, 2, 1),
+	), 0.5)
+	assert.Equal(t, PackFit(
+		&sproto.AllocateRequest{SlotsNeeded: 1},
+		newFakeAgentState(t, "agent2", 4, 3, 100, 0),
+	), 0.75)
+	assert.Equal(t, PackFit(
+		&sproto.AllocateRequest{SlotsNeeded: 1},
+		newFakeAgentState(t, "agent3", 8, 1, 100, 0),
+	), 0.5625)
+	assert.Equal(t, PackFit(
+		&sproto.AllocateRequest{SlotsNeeded: 1},
+		newFakeAgentState(t, "agent4", 1, 0, 100, 0),
+	), 0.25)
+	assert.Equal(t, PackFit(
+		&sproto.AllocateRequest{SlotsNeeded: 1},
+		newFakeAgentState(t, "agent5", 4, 0, 100, 0),
+	), 0.1)
+}
+
+func TestSpreadFit(t *testing.T) {
+	assert.Equal(t, SpreadFit(
+		&sproto.AllocateRequest{SlotsNeeded: 1},
+		newFakeAgentState(t, "agent1", 4, 0, 100, 0),
+	), 1.0)
+	assert.Equal(t, SpreadFit(
+		&sproto.AllocateRequest{SlotsNeeded: 1},
+		newFakeAgentState(t, "agent2", 4, 2, 100, 0),
+	), 0.4)
+	assert.Equal(t, SpreadFit(
+		&sproto.AllocateRequest{SlotsNeeded: 1},
+		newFakeAgentState(t, "agent3", 4, 0, 100, 1),
+	), 0.5)
+	assert.Equal(t, SpreadFit(
+		&sproto.AllocateRequest{SlotsNeeded: 0},
+		newFakeAgentState(t, "agent4", 0, 0, 100, 3),
+	), 0.25)
+}
+
+// simulatePlacement places tasks needing the given numbers of slots on the agents one after the
+// other, as the scheduler would, and returns how many tasks couldn't be placed.
+func simulatePlacement(
+	agents map[aproto.ID]*agentState, fittingMethod SoftConstraint, slotsNeeded ...int,
+) (unplaced int) {
+	for i, slots := range slotsNeeded {
+		req := &sproto.AllocateRequest{
+			AllocationID: model.AllocationID(fmt.Sprintf("task%d", i)),
+			SlotsNeeded:  slots,
+		}
+		fits := findFits(req, agents, fittingMethod, false)
+		if len(fits) == 0 {
+			unplaced++
+			continue
+		}
+		addTaskToAgents(req, fits)
+	}
+	return unplaced
+}
+
+func TestPackKeepsAgentsFreeForDistributedJobs(t *testing.T) {
+	newAgents := func() map[aproto.ID]*agentState {
+		return map[aproto.ID]*agentState{
+			"big":    newFakeAgentState(t, "big", 8, 5, 100, 0),
+			"small1": newFakeAgentState(t, "small1", 2, 0, 100, 0),
+			"small2": newFakeAgentState(t, "small2", 2, 0, 100, 0),
+		}
+	}
+
+	// A 2-slot task fills a small agent exactly under best fit, so a 4-slot distributed task no
+	// longer finds two free agents. Packing puts it on the agent already in use instead.
+	assert.Equal(t, simulatePlacement(newAgents(), BestFit, 2, 4), 1)
+	assert.Equal(t, simulatePlacement(newAgents(), PackFit, 2, 4), 0)
+}
+
+func TestSpreadMinimizesTasksPerAgent(t *testing.T) {
+	maxContainers := func(agents map[aproto.ID]*agentState) int {
+		most := 0
+		for _, agent := range agents {
+			most = max(most, len(agent.containerState))
+		}
+		return most
+	}
+	newAgents := func() map[aproto.ID]*agentState {
+		return map[aproto.ID]*agentState{
+			"agent1": newFakeAgentState(t, "agent1", 8, 0, 100, 0),
+			"agent2": newFakeAgentState(t, "agent2", 4, 0, 100, 0),
+			"agent3": newFakeAgentState(t, "agent3", 4, 0, 100, 0),
+		}
+	}
+
+	packed := newAgents()
+	assert.Equal(t, simulatePlacement(packed, BestFit, 1, 1, 1, 1, 1, 1), 0)
+	assert.Equal(t, maxContainers(packed), 4)
+
+	spread := newAgents()
+	assert.Equal(t, simulatePlacement(spread, SpreadFit, 1, 1, 1, 1, 1, 1), 0)
+	assert.Equal(t, maxContainers(spread), 2)
+}
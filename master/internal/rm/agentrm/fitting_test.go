@@ -9,6 +9,9 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestIsViable(t *testing.T) {
@@ -22,6 +25,95 @@ func TestIsViable(t *testing.T) {
 		newFakeAgentState(t, "agent4", 1, 0, 100, 0), slotsSatisfied))
 }
 
+func TestGPUMemorySatisfied(t *testing.T) {
+	withMemory := func(agent *agentState, memory ...int) *agentState {
+		agent.Devices = map[device.Device]*cproto.ID{}
+		for i, m := range memory {
+			agent.Devices[device.Device{ID: device.ID(i), MemoryMiB: m}] = nil
+		}
+		return agent
+	}
+	req := &sproto.AllocateRequest{
+		SlotsNeeded:         1,
+		FittingRequirements: sproto.FittingRequirements{MinGPUMemoryMiB: 40000},
+	}
+
+	assert.Assert(t, gpuMemorySatisfied(req,
+		withMemory(newFakeAgentState(t, "agent1", 0, 0, 100, 0), 40960, 81920)))
+	assert.Assert(t, !gpuMemorySatisfied(req,
+		withMemory(newFakeAgentState(t, "agent2", 0, 0, 100, 0), 81920, 16384)))
+	// Devices that don't report their memory don't qualify.
+	assert.Assert(t, !gpuMemorySatisfied(req,
+		newFakeAgentState(t, "agent3", 2, 0, 100, 0)))
+	assert.Assert(t, !gpuMemorySatisfied(req,
+		newFakeAgentState(t, "agent4", 0, 0, 100, 0)))
+
+	req.FittingRequirements.MinGPUMemoryMiB = 0
+	assert.Assert(t, gpuMemorySatisfied(req,
+		newFakeAgentState(t, "agent3", 2, 0, 100, 0)))
+}
+
+func TestScratchSatisfied(t *testing.T) {
+	withScratch := func(agent *agentState, capacity int) *agentState {
+		agent.scratchCapacityGiB = capacity
+		return agent
+	}
+	req := &sproto.AllocateRequest{
+		SlotsNeeded:         1,
+		FittingRequirements: sproto.FittingRequirements{ScratchGiB: 100},
+	}
+
+	agent := withScratch(newFakeAgentState(t, "agent1", 4, 0, 100, 0), 250)
+	assert.Assert(t, scratchSatisfied(req, agent))
+	agent.reserveScratch(cproto.NewID(), 100)
+	assert.Assert(t, scratchSatisfied(req, agent))
+	// Space reserved by other containers is not available.
+	agent.reserveScratch(cproto.NewID(), 100)
+	assert.Assert(t, !scratchSatisfied(req, agent))
+	assert.Assert(t, !scratchSatisfied(req,
+		newFakeAgentState(t, "agent2", 4, 0, 100, 0)))
+
+	req.FittingRequirements.ScratchGiB = 0
+	assert.Assert(t, scratchSatisfied(req,
+		newFakeAgentState(t, "agent2", 4, 0, 100, 0)))
+}
+
+func TestAgentConstraintsSatisfied(t *testing.T) {
+	withLabels := func(agent *agentState, labels map[string]string) *agentState {
+		agent.labels = labels
+		return agent
+	}
+	req := &sproto.AllocateRequest{
+		SlotsNeeded: 1,
+		FittingRequirements: sproto.FittingRequirements{
+			AgentConstraints: []sproto.AgentConstraint{
+				{Key: "gpu", Operator: sproto.AgentConstraintIn, Values: []string{"a100", "h100"}},
+				{Key: "rack", Operator: sproto.AgentConstraintNotIn, Values: []string{"r12"}},
+			},
+		},
+	}
+
+	assert.Assert(t, agentConstraintsSatisfied(req, withLabels(
+		newFakeAgentState(t, "agent1", 4, 0, 100, 0), map[string]string{"gpu": "a100"})))
+	assert.Assert(t, agentConstraintsSatisfied(req, withLabels(
+		newFakeAgentState(t, "agent2", 4, 0, 100, 0), map[string]string{"gpu": "h100", "rack": "r3"})))
+	assert.Assert(t, !agentConstraintsSatisfied(req, withLabels(
+		newFakeAgentState(t, "agent3", 4, 0, 100, 0), map[string]string{"gpu": "a100", "rack": "r12"})))
+	// Agents without a label never match an in constraint on it.
+	assert.Assert(t, !agentConstraintsSatisfied(req,
+		newFakeAgentState(t, "agent4", 4, 0, 100, 0)))
+}
+
+func TestVersionSatisfied(t *testing.T) {
+	req := &sproto.AllocateRequest{SlotsNeeded: 1}
+
+	agent := newFakeAgentState(t, "agent1", 4, 0, 100, 0)
+	assert.Assert(t, versionSatisfied(req, agent))
+	agent.versionRefused = true
+	assert.Assert(t, !versionSatisfied(req, agent))
+	assert.Assert(t, agent.deepCopy().versionRefused)
+}
+
 func TestFindFits(t *testing.T) {
 	type testCase struct {
 		Name          string
@@ -503,6 +595,34 @@ func TestFindFitDisallowedNodes(t *testing.T) {
 	assert.Equal(t, fits[0].Agent, agents[0])
 }
 
+func TestFindFitVersionRefused(t *testing.T) {
+	agents := []*agentState{
+		newFakeAgentState(t, "agent1", 4, 0, 100, 0),
+		newFakeAgentState(t, "agent2", 4, 0, 100, 0),
+	}
+	agents[0].versionRefused = true
+	agentsByHandler, _ := byID(agents...)
+
+	for _, slots := range []int{1, 4} {
+		task := &sproto.AllocateRequest{
+			AllocationID: "a",
+			SlotsNeeded:  slots,
+			TaskID:       model.TaskID(fmt.Sprintf("slots%d", slots)),
+		}
+		fits := findFits(task, agentsByHandler, BestFit, false)
+		assert.Assert(t, len(fits) == 1)
+		assert.Equal(t, fits[0].Agent, agents[1])
+	}
+
+	agents[1].versionRefused = true
+	fits := findFits(&sproto.AllocateRequest{
+		AllocationID: "a",
+		SlotsNeeded:  1,
+		TaskID:       "noAgents",
+	}, agentsByHandler, BestFit, false)
+	assert.Assert(t, len(fits) == 0)
+}
+
 func byID(
 	handlers ...*agentState,
 ) (map[aproto.ID]*agentState, []*agentState) {
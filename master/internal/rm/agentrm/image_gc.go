@@ -0,0 +1,142 @@
+package agentrm
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+// imageTracker tracks the images task containers use across all agents, so that images can be
+// removed from agents that are low on disk in least recently used order across the fleet.
+type imageTracker struct {
+	mu sync.Mutex
+	// lastUsed is when a container was last started from, or last seen using, each image.
+	lastUsed map[string]time.Time
+	// active is the image of each container that is starting or running on any agent.
+	active map[cproto.ID]string
+}
+
+func newImageTracker() *imageTracker {
+	return &imageTracker{
+		lastUsed: make(map[string]time.Time),
+		active:   make(map[cproto.ID]string),
+	}
+}
+
+// normalizeImageRef returns the canonical form of an image reference, so that references to the
+// same image written differently, like with or without the default registry or tag, match.
+func normalizeImageRef(ref string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ref
+	}
+	return reference.TagNameOnly(named).String()
+}
+
+// diskUtilization returns the fraction of the image partition of an agent that is used.
+func diskUtilization(usage *aproto.AgentDiskUsage) float64 {
+	if usage.TotalBytes == 0 {
+		return 0
+	}
+	return float64(usage.UsedBytes) / float64(usage.TotalBytes)
+}
+
+func (t *imageTracker) containerStarted(id cproto.ID, image string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ref := normalizeImageRef(image)
+	t.active[id] = ref
+	t.lastUsed[ref] = now
+}
+
+func (t *imageTracker) containerStopped(id cproto.ID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.active, id)
+}
+
+// seen records that the images containers on an agent were created from are still used.
+func (t *imageTracker) seen(images []aproto.Image, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, img := range images {
+		if !img.InUse {
+			continue
+		}
+		for _, ref := range img.Refs {
+			t.lastUsed[normalizeImageRef(ref)] = now
+		}
+	}
+}
+
+// imagesToRemove picks the images to remove from an agent to bring the utilization of its image
+// partition down to the low threshold, once it reaches the high one. Images that containers on the
+// agent were created from, or that a container on any agent is starting or running from, are
+// spared; the rest are removed least recently used across the fleet first.
+func (t *imageTracker) imagesToRemove(
+	usage *aproto.AgentDiskUsage, gc config.ImageGCConfig,
+) []string {
+	if usage.TotalBytes == 0 || diskUtilization(usage) < gc.HighThreshold {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	referenced := make(map[string]bool, len(t.active))
+	for _, ref := range t.active {
+		referenced[ref] = true
+	}
+
+	type candidate struct {
+		image    aproto.Image
+		lastUsed time.Time
+	}
+	var candidates []candidate
+outer:
+	for _, img := range usage.Images {
+		if img.InUse {
+			continue
+		}
+		c := candidate{image: img}
+		for _, ref := range img.Refs {
+			ref = normalizeImageRef(ref)
+			if referenced[ref] {
+				continue outer
+			}
+			if lastUsed := t.lastUsed[ref]; lastUsed.After(c.lastUsed) {
+				c.lastUsed = lastUsed
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if !candidates[i].lastUsed.Equal(candidates[j].lastUsed) {
+			return candidates[i].lastUsed.Before(candidates[j].lastUsed)
+		}
+		return candidates[i].image.ID < candidates[j].image.ID
+	})
+
+	// Image sizes include layers shared with other images, so this may remove fewer images than
+	// needed; the agent reports its usage again once they are removed.
+	target := uint64(gc.LowThreshold * float64(usage.TotalBytes))
+	used := usage.UsedBytes
+	var ids []string
+	for _, c := range candidates {
+		if used <= target {
+			break
+		}
+		ids = append(ids, c.image.ID)
+		used -= min(used, uint64(c.image.SizeBytes))
+	}
+	return ids
+}
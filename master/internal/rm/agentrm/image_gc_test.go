@@ -0,0 +1,64 @@
+package agentrm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+func TestNormalizeImageRef(t *testing.T) {
+	require.Equal(t, "docker.io/determinedai/pytorch-ngc:latest",
+		normalizeImageRef("determinedai/pytorch-ngc"))
+	require.Equal(t, "docker.io/library/ubuntu:22.04", normalizeImageRef("ubuntu:22.04"))
+	require.Equal(t, "registry.example.com:5000/team/image:v1",
+		normalizeImageRef("registry.example.com:5000/team/image:v1"))
+	require.Equal(t, "<none>:<none>", normalizeImageRef("<none>:<none>"))
+}
+
+func TestImagesToRemove(t *testing.T) {
+	const gib = 1 << 30
+	gc := config.ImageGCConfig{HighThreshold: 0.8, LowThreshold: 0.5}
+	now := time.Now()
+
+	images := newImageTracker()
+	images.containerStarted(cproto.ID("old"), "old:v1", now.Add(-3*time.Hour))
+	images.containerStopped(cproto.ID("old"))
+	images.containerStarted(cproto.ID("recent"), "recent:v1", now.Add(-time.Hour))
+	images.containerStopped(cproto.ID("recent"))
+	images.containerStarted(cproto.ID("elsewhere"), "docker.io/library/active:v1", now)
+
+	usage := &aproto.AgentDiskUsage{
+		TotalBytes: 100 * gib,
+		UsedBytes:  90 * gib,
+		Images: []aproto.Image{
+			{ID: "recent", Refs: []string{"recent:v1"}, SizeBytes: 30 * gib},
+			{ID: "old", Refs: []string{"old:v1"}, SizeBytes: 20 * gib},
+			{ID: "never", Refs: []string{"never:v1"}, SizeBytes: 5 * gib},
+			{ID: "in-use", Refs: []string{"in-use:v1"}, SizeBytes: 50 * gib, InUse: true},
+			// Running from on another agent, under a differently written reference.
+			{ID: "active", Refs: []string{"active:v1"}, SizeBytes: 50 * gib},
+		},
+	}
+	// Images are removed least recently used first until usage is below the low threshold.
+	require.Equal(t, []string{"never", "old", "recent"}, images.imagesToRemove(usage, gc))
+
+	usage.UsedBytes = 70 * gib
+	require.Empty(t, images.imagesToRemove(usage, gc))
+
+	// Images seen in use on an agent count as used.
+	usage.UsedBytes = 90 * gib
+	images.seen([]aproto.Image{{ID: "old", Refs: []string{"old:v1"}, InUse: true}}, now)
+	require.Equal(t, []string{"never", "recent", "old"}, images.imagesToRemove(usage, gc))
+
+	images.containerStopped(cproto.ID("elsewhere"))
+	usage.UsedBytes = 60 * gib
+	usage.Images = []aproto.Image{{ID: "active", Refs: []string{"active:v1"}, SizeBytes: 5 * gib}}
+	require.Empty(t, images.imagesToRemove(usage, gc))
+	usage.UsedBytes = 85 * gib
+	require.Equal(t, []string{"active"}, images.imagesToRemove(usage, gc))
+}
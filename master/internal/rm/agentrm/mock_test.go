@@ -32,6 +32,7 @@ type MockTask struct {
 	// Any test that set this to false is half wrong. It is used as a proxy to oversubscribe agents.
 	ContainerStarted  bool
 	JobSubmissionTime time.Time
+	RequestTime       time.Time
 
 	BlockedNodes []string
 }
@@ -64,6 +65,7 @@ func MockTaskToAllocateRequest(mockTask *MockTask) *sproto.AllocateRequest {
 			Preemptible: !mockTask.NonPreemptible,
 		},
 		JobSubmissionTime: jobSubmissionTime,
+		RequestTime:       mockTask.RequestTime,
 		BlockedNodes:      mockTask.BlockedNodes,
 	}
 	return req
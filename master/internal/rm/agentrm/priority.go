@@ -3,6 +3,7 @@ package agentrm
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -18,6 +19,7 @@ import (
 type priorityScheduler struct {
 	preemptionEnabled      bool
 	allowHeterogeneousFits bool
+	aging                  *priorityAging
 }
 
 // NewPriorityScheduler creates a new scheduler that schedules tasks via priority.
@@ -25,6 +27,7 @@ func NewPriorityScheduler(config *config.SchedulerConfig) Scheduler {
 	return &priorityScheduler{
 		preemptionEnabled:      config.Priority.Preemption,
 		allowHeterogeneousFits: config.AllowHeterogeneousFits,
+		aging:                  newPriorityAging(config.Priority.Aging),
 	}
 }
 
@@ -42,8 +45,15 @@ func (p priorityScheduler) Schedule(rp *resourcePool) (
 }
 
 func (p priorityScheduler) JobQInfo(rp *resourcePool) map[model.JobID]*sproto.RMJobInfo {
-	reqs := tasklist.SortTasksWithPosition(rp.taskList, rp.groups, rp.queuePositions, false)
+	effectivePriorities := p.aging.effectivePriorities(rp.taskList, rp.groups, time.Now())
+	reqs := tasklist.SortTasksWithPosition(
+		rp.taskList, agedGroups(rp.groups, effectivePriorities), rp.queuePositions, false)
 	jobQInfo := tasklist.ReduceToJobQInfo(reqs)
+	for jobID, priority := range effectivePriorities {
+		if info, ok := jobQInfo[jobID]; ok {
+			info.EffectivePriority = &priority
+		}
+	}
 	return jobQInfo
 }
 
@@ -56,6 +66,7 @@ func (p priorityScheduler) prioritySchedule(
 ) ([]*sproto.AllocateRequest, []model.AllocationID) {
 	toAllocate := make([]*sproto.AllocateRequest, 0)
 	toRelease := make([]model.AllocationID, 0)
+	effectivePriorities := p.aging.effectivePriorities(taskList, groups, time.Now())
 
 	// Schedule zero-slot and non-zero-slot tasks independently of each other, e.g., a lower priority
 	// zero-slot task can be started while a higher priority non-zero-slot task is pending, and
@@ -68,6 +79,7 @@ func (p priorityScheduler) prioritySchedule(
 			agents,
 			fittingMethod,
 			taskFilter(zeroSlots),
+			effectivePriorities,
 		)
 		toAllocate = append(toAllocate, allocate...)
 		toRelease = append(toRelease, release...)
@@ -80,6 +92,8 @@ func (p priorityScheduler) prioritySchedule(
 // 1. Schedule pending tasks without preemption.
 // 2. Search if preempting any lower-priority tasks can make space.
 // 3. Back-fill lower-priority pending tasks if there are no tasks to preempt.
+// Pending tasks are scheduled at the effective priority of their job if aging raised it, but only
+// preempt tasks their own priority would.
 func (p priorityScheduler) prioritySchedulerWithFilter(
 	taskList *tasklist.TaskList,
 	groups map[model.JobID]*tasklist.Group,
@@ -87,6 +101,7 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 	agents map[aproto.ID]*agentState,
 	fittingMethod SoftConstraint,
 	filter func(*sproto.AllocateRequest) bool,
+	effectivePriorities map[model.JobID]int,
 ) ([]*sproto.AllocateRequest, []model.AllocationID) {
 	toAllocate := make([]*sproto.AllocateRequest, 0)
 	toRelease := make(map[model.AllocationID]bool)
@@ -100,6 +115,7 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 		jobPositions,
 		filter,
 	)
+	priorityToPendingTasksMap = agePendingTasks(priorityToPendingTasksMap, effectivePriorities)
 
 	localAgentsState := deepCopyAgents(agents)
 
@@ -157,14 +173,14 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 					log.Debugf(
 						"Not preempting tasks for task %s as it will be able to launch "+
 							"once already scheduled preemptions complete", prioritizedAllocation.Name)
-					addTaskToAgents(fits)
+					addTaskToAgents(prioritizedAllocation, fits)
 					continue
 				}
 
 				taskPlaced, updatedLocalAgentState, preemptedTasks := p.trySchedulingTaskViaPreemption(
 					taskList,
 					prioritizedAllocation,
-					priority,
+					*groups[prioritizedAllocation.JobID].Priority,
 					jobPositions,
 					fittingMethod,
 					localAgentsState,
@@ -239,7 +255,7 @@ func (p priorityScheduler) trySchedulingTaskViaPreemption(
 				fittingMethod,
 				p.allowHeterogeneousFits,
 			); len(fits) > 0 {
-				addTaskToAgents(fits)
+				addTaskToAgents(allocationRequest, fits)
 				return true, localAgentsState, preemptedTasks
 			}
 		}
@@ -265,7 +281,7 @@ func (p priorityScheduler) trySchedulingPendingTasksInPriority(
 			unSuccessfulAllocations = append(unSuccessfulAllocations, allocationRequest)
 			continue
 		}
-		addTaskToAgents(fits)
+		addTaskToAgents(allocationRequest, fits)
 		successfulAllocations = append(successfulAllocations, allocationRequest)
 	}
 
@@ -316,11 +332,13 @@ func deepCopyAgents(agents map[aproto.ID]*agentState) map[aproto.ID]*agentState
 	return copiedAgents
 }
 
-func addTaskToAgents(fits []*fittingState) {
+func addTaskToAgents(req *sproto.AllocateRequest, fits []*fittingState) {
 	for _, fit := range fits {
-		if _, err := fit.Agent.allocateFreeDevices(fit.Slots, cproto.NewID()); err != nil {
+		containerID := cproto.NewID()
+		if _, err := fit.Agent.allocateFreeDevices(fit.Slots, containerID); err != nil {
 			panic(errors.Wrap(err, "can't add task to agents"))
 		}
+		fit.Agent.reserveScratch(containerID, req.FittingRequirements.ScratchGiB)
 	}
 }
 
@@ -0,0 +1,128 @@
+package agentrm
+
+import (
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// priorityAging raises the priority of jobs with pending allocations by one every interval they
+// wait, by at most maxBoost and to at most maxPriority. Priorities are lower the higher they are,
+// so raising a priority lowers its value.
+type priorityAging struct {
+	interval    time.Duration
+	maxBoost    int
+	maxPriority int
+}
+
+func newPriorityAging(conf *config.PriorityAgingConfig) *priorityAging {
+	if conf == nil {
+		return nil
+	}
+	maxPriority := model.MinUserSchedulingPriority
+	if conf.MaxPriority != nil {
+		maxPriority = *conf.MaxPriority
+	}
+	return &priorityAging{
+		interval:    time.Duration(conf.Interval),
+		maxBoost:    conf.MaxBoost,
+		maxPriority: maxPriority,
+	}
+}
+
+// effectivePriorities returns the priority each job with pending allocations is scheduled at. A
+// job has waited since the oldest of its pending allocations was requested. Without aging, nil is
+// returned.
+func (a *priorityAging) effectivePriorities(
+	taskList *tasklist.TaskList,
+	groups map[model.JobID]*tasklist.Group,
+	now time.Time,
+) map[model.JobID]int {
+	if a == nil {
+		return nil
+	}
+
+	waitingSince := make(map[model.JobID]time.Time)
+	for it := taskList.Iterator(); it.Next(); {
+		req := it.Value()
+		if taskList.IsScheduled(req.AllocationID) {
+			continue
+		}
+		if since, ok := waitingSince[req.JobID]; !ok || req.RequestTime.Before(since) {
+			waitingSince[req.JobID] = req.RequestTime
+		}
+	}
+
+	priorities := make(map[model.JobID]int, len(waitingSince))
+	for jobID, since := range waitingSince {
+		group, ok := groups[jobID]
+		if !ok || group.Priority == nil {
+			continue
+		}
+		priorities[jobID] = a.effectivePriority(*group.Priority, now.Sub(since))
+	}
+	return priorities
+}
+
+func (a *priorityAging) effectivePriority(priority int, waited time.Duration) int {
+	if priority <= a.maxPriority || waited <= 0 {
+		return priority
+	}
+	boost := int(waited / a.interval)
+	if boost > a.maxBoost {
+		boost = a.maxBoost
+	}
+	if priority-boost < a.maxPriority {
+		return a.maxPriority
+	}
+	return priority - boost
+}
+
+// agePendingTasks moves the pending tasks of each priority to the effective priority of their job.
+// Tasks keep their order, so aged tasks follow the tasks already at the priority they are raised
+// to.
+func agePendingTasks(
+	priorityToPendingTasksMap map[int][]*sproto.AllocateRequest,
+	effectivePriorities map[model.JobID]int,
+) map[int][]*sproto.AllocateRequest {
+	if effectivePriorities == nil {
+		return priorityToPendingTasksMap
+	}
+
+	aged := make(map[int][]*sproto.AllocateRequest, len(priorityToPendingTasksMap))
+	for _, priority := range getOrderedPriorities(priorityToPendingTasksMap) {
+		for _, req := range priorityToPendingTasksMap[priority] {
+			effective, ok := effectivePriorities[req.JobID]
+			if !ok {
+				effective = priority
+			}
+			aged[effective] = append(aged[effective], req)
+		}
+	}
+	return aged
+}
+
+// agedGroups returns a copy of groups with the priority of each job replaced by its effective
+// priority.
+func agedGroups(
+	groups map[model.JobID]*tasklist.Group,
+	effectivePriorities map[model.JobID]int,
+) map[model.JobID]*tasklist.Group {
+	if effectivePriorities == nil {
+		return groups
+	}
+
+	aged := make(map[model.JobID]*tasklist.Group, len(groups))
+	for jobID, group := range groups {
+		if effective, ok := effectivePriorities[jobID]; ok {
+			agedGroup := *group
+			agedGroup.Priority = &effective
+			group = &agedGroup
+		}
+		aged[jobID] = group
+	}
+	return aged
+}
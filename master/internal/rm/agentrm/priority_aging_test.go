@@ -0,0 +1,100 @@
+package agentrm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestPriorityAgingEffectivePriority(t *testing.T) {
+	maxPriority := 20
+	a := newPriorityAging(&config.PriorityAgingConfig{
+		Interval:    model.Duration(10 * time.Minute),
+		MaxBoost:    15,
+		MaxPriority: &maxPriority,
+	})
+
+	assert.Equal(t, a.effectivePriority(50, 0), 50)
+	assert.Equal(t, a.effectivePriority(50, 9*time.Minute), 50)
+	assert.Equal(t, a.effectivePriority(50, 25*time.Minute), 48)
+	// Raised by at most max_boost.
+	assert.Equal(t, a.effectivePriority(50, 24*time.Hour), 35)
+	// Raised to at most max_priority.
+	assert.Equal(t, a.effectivePriority(25, 24*time.Hour), 20)
+	// Never lowered.
+	assert.Equal(t, a.effectivePriority(10, 24*time.Hour), 10)
+}
+
+func TestPrioritySchedulingAging(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &lowerPriority},
+		{ID: "group2", Priority: &higherPriority},
+	}
+	tasks := []*MockTask{
+		{ID: "task1", SlotsNeeded: 4, Group: groups[0], RequestTime: time.Now().Add(-2 * time.Hour)},
+		{ID: "task2", SlotsNeeded: 4, Group: groups[1], RequestTime: time.Now()},
+	}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+
+	p := &priorityScheduler{}
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, []*MockTask{tasks[1]})
+
+	// After two hours, the job of lower priority has been raised above the other.
+	p.aging = newPriorityAging(&config.PriorityAgingConfig{
+		Interval: model.Duration(10 * time.Minute),
+		MaxBoost: 20,
+	})
+	effective := p.aging.effectivePriorities(taskList, groupMap, time.Now())
+	assert.DeepEqual(t, effective, map[model.JobID]int{"group1": 38, "group2": 40})
+	toAllocate, _ = p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, []*MockTask{tasks[0]})
+}
+
+func TestPrioritySchedulingAgingDoesNotPreempt(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &lowerPriority},
+		{ID: "group2", Priority: &higherPriority},
+	}
+	tasks := []*MockTask{
+		{ID: "task1", SlotsNeeded: 4, Group: groups[0], RequestTime: time.Now().Add(-2 * time.Hour)},
+		{
+			ID: "task2", SlotsNeeded: 4, Group: groups[1], RequestTime: time.Now(),
+			AllocatedAgent: agents[0], ContainerStarted: true,
+		},
+	}
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+
+	p := &priorityScheduler{
+		preemptionEnabled: true,
+		aging: newPriorityAging(&config.PriorityAgingConfig{
+			Interval: model.Duration(10 * time.Minute),
+			MaxBoost: 20,
+		}),
+	}
+	toAllocate, toRelease := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+	assertEqualToAllocate(t, toAllocate, []*MockTask{})
+	assertEqualToRelease(t, taskList, toRelease, []*MockTask{})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
 	"github.com/determined-ai/determined/master/pkg/model"
 )
@@ -69,7 +70,8 @@ func New(
 	//    aws_secret_access_key = YOUR_SECRET_ACCESS_KEY
 	//    ```
 	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(config.AWS.Region),
+		Region:     aws.String(config.AWS.Region),
+		HTTPClient: egress.Client(),
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create AWS session")
@@ -13,9 +13,13 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
 
 	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
 	"github.com/determined-ai/determined/master/pkg/model"
 )
@@ -58,8 +62,16 @@ func New(
 	//    ```
 	//    export GOOGLE_APPLICATION_CREDENTIALS="[PATH]"
 	//    ```
-	ctx := context.Background()
-	computeService, err := compute.NewService(ctx)
+	//
+	// Requests, including those for tokens, go through the egress proxy.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, egress.Client())
+	creds, err := google.FindDefaultCredentials(ctx, compute.ComputeScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find GCP credentials")
+	}
+	computeService, err := compute.NewService(
+		ctx, option.WithHTTPClient(oauth2.NewClient(ctx, creds.TokenSource)),
+	)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create GCP compute engine client")
 	}
@@ -0,0 +1,124 @@
+package agentrm
+
+import (
+	"math"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// reservedSlots holds the slots of a resource pool that its active reservations set aside for the
+// tasks of their workspaces and users during a scheduling pass. Other tasks, and tasks that don't
+// fit in their reservations, share the rest of the pool.
+type reservedSlots struct {
+	reservations []model.ResourcePoolReservation
+	slots        []int
+	used         []int
+	free         int
+
+	unreserved     int
+	unreservedUsed int
+
+	placement   map[model.AllocationID]int
+	pendingReqs []*sproto.AllocateRequest
+}
+
+func newReservedSlots(
+	reservations []model.ResourcePoolReservation, total, free int,
+	reqs []*sproto.AllocateRequest, isScheduled func(model.AllocationID) bool,
+) *reservedSlots {
+	s := &reservedSlots{
+		reservations: reservations,
+		slots:        make([]int, len(reservations)),
+		used:         make([]int, len(reservations)),
+		free:         free,
+		unreserved:   total,
+		placement:    make(map[model.AllocationID]int),
+	}
+	for i, r := range reservations {
+		s.slots[i] = int(math.Floor(r.SlotFraction * float64(total)))
+		s.unreserved -= s.slots[i]
+	}
+	s.unreserved = max(0, s.unreserved)
+
+	for _, req := range reqs {
+		if !isScheduled(req.AllocationID) {
+			s.pendingReqs = append(s.pendingReqs, req)
+			continue
+		}
+		i := s.place(req, s.used)
+		s.placement[req.AllocationID] = i
+		if i < 0 {
+			s.unreservedUsed += req.SlotsNeeded
+		}
+	}
+	return s
+}
+
+// place counts the slots of a task against the first reservation that covers it and has room for
+// it, returning the index of the reservation, or -1 if the task must use unreserved slots.
+func (s *reservedSlots) place(req *sproto.AllocateRequest, used []int) int {
+	for i, r := range s.reservations {
+		if r.Covers(req.WorkspaceID, req.OwnerID) && used[i]+req.SlotsNeeded <= s.slots[i] {
+			used[i] += req.SlotsNeeded
+			return i
+		}
+	}
+	return -1
+}
+
+// heldBack returns the pending tasks that can't start without taking reserved slots they aren't
+// covered by. The tasks are given in scheduling order, and tasks later in the order can start if
+// they fit in what is left.
+func (s *reservedSlots) heldBack(reqs []*sproto.AllocateRequest) map[model.AllocationID]bool {
+	held := make(map[model.AllocationID]bool)
+	used := append([]int(nil), s.used...)
+	unreservedUsed := s.unreservedUsed
+	for _, req := range reqs {
+		if req.SlotsNeeded == 0 || s.place(req, used) >= 0 {
+			continue
+		}
+		if unreservedUsed+req.SlotsNeeded > s.unreserved {
+			held[req.AllocationID] = true
+			continue
+		}
+		unreservedUsed += req.SlotsNeeded
+	}
+	return held
+}
+
+// reclaim returns the running tasks to preempt so that the pending tasks of reservations can
+// start, taking back reserved slots from the tasks the reservations don't cover, as happens when a
+// reservation starts. The tasks are given in scheduling order, and tasks are preempted from the
+// end of the order.
+func (s *reservedSlots) reclaim(
+	scheduled []*sproto.AllocateRequest, released map[model.AllocationID]bool,
+) []model.AllocationID {
+	var toRelease []model.AllocationID
+	need := s.neededWithinReservations() - s.free
+	for i := len(scheduled) - 1; i >= 0 && need > 0 && s.unreservedUsed > s.unreserved; i-- {
+		req := scheduled[i]
+		if s.placement[req.AllocationID] >= 0 || released[req.AllocationID] ||
+			!req.Preemption.Preemptible || req.SlotsNeeded == 0 {
+			continue
+		}
+		toRelease = append(toRelease, req.AllocationID)
+		released[req.AllocationID] = true
+		s.unreservedUsed -= req.SlotsNeeded
+		need -= req.SlotsNeeded
+	}
+	return toRelease
+}
+
+// neededWithinReservations returns the slots the pending tasks need from the reservations that
+// cover them, counting only the tasks that fit in what the reservations don't use.
+func (s *reservedSlots) neededWithinReservations() int {
+	used := append([]int(nil), s.used...)
+	needed := 0
+	for _, req := range s.pendingReqs {
+		if s.place(req, used) >= 0 {
+			needed += req.SlotsNeeded
+		}
+	}
+	return needed
+}
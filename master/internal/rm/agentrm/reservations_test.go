@@ -0,0 +1,96 @@
+package agentrm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+type reservationTask struct {
+	workspaceID int
+	slots       int
+	scheduled   bool
+}
+
+func newTestReservedSlots(
+	reservations []model.ResourcePoolReservation, total int, tasks []reservationTask,
+) (*reservedSlots, []*sproto.AllocateRequest) {
+	var reqs []*sproto.AllocateRequest
+	scheduled := make(map[model.AllocationID]bool)
+	used := 0
+	for i, task := range tasks {
+		req := &sproto.AllocateRequest{
+			AllocationID: model.AllocationID(fmt.Sprintf("task%d", i)),
+			WorkspaceID:  task.workspaceID,
+			SlotsNeeded:  task.slots,
+			Preemption:   sproto.PreemptionConfig{Preemptible: true},
+		}
+		reqs = append(reqs, req)
+		if task.scheduled {
+			scheduled[req.AllocationID] = true
+			used += task.slots
+		}
+	}
+	isScheduled := func(id model.AllocationID) bool { return scheduled[id] }
+	return newReservedSlots(reservations, total, total-used, reqs, isScheduled), reqs
+}
+
+func TestReservedSlotsHeldBack(t *testing.T) {
+	reservations := []model.ResourcePoolReservation{
+		{ID: 1, WorkspaceID: ptrs.Ptr(2), SlotFraction: 0.5},
+	}
+	reserved, reqs := newTestReservedSlots(reservations, 8, []reservationTask{
+		{1, 4, true},
+		{1, 2, false},
+		{2, 2, false},
+		{2, 4, false},
+	})
+
+	// Other workspaces can't use the reserved slots even while they are idle, and tasks of the
+	// workspace spill over to the unreserved slots once their reservation is full.
+	require.Equal(t, 4, reserved.unreserved)
+	require.Equal(t, map[model.AllocationID]bool{
+		reqs[1].AllocationID: true,
+		reqs[3].AllocationID: true,
+	}, reserved.heldBack(reqs[1:]))
+
+	// A reservation covers the tasks of its user too.
+	userReservations := []model.ResourcePoolReservation{
+		{ID: 2, UserID: ptrs.Ptr(model.UserID(3)), SlotFraction: 0.25},
+	}
+	reserved, _ = newTestReservedSlots(userReservations, 8, nil)
+	require.Equal(t, 0, reserved.place(&sproto.AllocateRequest{OwnerID: 3, SlotsNeeded: 2},
+		make([]int, 1)))
+	require.Equal(t, -1, reserved.place(&sproto.AllocateRequest{OwnerID: 4, SlotsNeeded: 2},
+		make([]int, 1)))
+}
+
+func TestReservedSlotsReclaim(t *testing.T) {
+	reservations := []model.ResourcePoolReservation{
+		{ID: 1, WorkspaceID: ptrs.Ptr(2), SlotFraction: 0.5},
+	}
+
+	// When the reservation starts, tasks of other workspaces in its slots are preempted for the
+	// tasks of the workspace, from the end of the scheduling order.
+	reserved, reqs := newTestReservedSlots(reservations, 8, []reservationTask{
+		{1, 2, true},
+		{1, 2, true},
+		{1, 2, true},
+		{1, 2, true},
+		{2, 2, false},
+	})
+	require.Equal(t, []model.AllocationID{reqs[3].AllocationID},
+		reserved.reclaim(reqs[:4], map[model.AllocationID]bool{}))
+
+	// Tasks within the unreserved slots aren't preempted.
+	reserved, reqs = newTestReservedSlots(reservations, 8, []reservationTask{
+		{1, 4, true},
+		{2, 2, false},
+	})
+	require.Empty(t, reserved.reclaim(reqs[:1], map[model.AllocationID]bool{}))
+}
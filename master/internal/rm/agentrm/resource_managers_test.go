@@ -13,6 +13,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/internal/versioncompat"
 )
 
 func TestResourceManagerForwardMessage(t *testing.T) {
@@ -34,7 +35,8 @@ func TestResourceManagerForwardMessage(t *testing.T) {
 		},
 	}
 
-	rm, err := New(nil, echo.New(), conf.ResourceManagers()[0], nil, nil)
+	rm, err := New(nil, echo.New(), conf.ResourceManagers()[0], nil,
+		versioncompat.NewRegistry("", config.VersionCompatibilityConfig{}), nil)
 	assert.NilError(t, err, "error initializing resource manager")
 
 	taskSummary, err := rm.GetAllocationSummaries()
@@ -52,7 +54,8 @@ func TestAgentRMHealthCheck(t *testing.T) {
 		},
 	}
 
-	rm, err := New(nil, echo.New(), conf.ResourceManagers()[0], nil, nil)
+	rm, err := New(nil, echo.New(), conf.ResourceManagers()[0], nil,
+		versioncompat.NewRegistry("", config.VersionCompatibilityConfig{}), nil)
 	require.NoError(t, err)
 
 	require.Equal(t, []model.ResourceManagerHealth{
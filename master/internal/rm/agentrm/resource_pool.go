@@ -21,6 +21,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/task/taskmodel"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/set"
 	"github.com/determined-ai/determined/proto/pkg/jobv1"
@@ -37,6 +38,12 @@ type resourcePool struct {
 	scheduler        Scheduler
 	fittingMethod    SoftConstraint
 	slotsPerInstance int
+	workloadClasses  *config.WorkloadClassesConfig
+
+	// reservations are the current and upcoming reservations of the pool, and activeReservations
+	// those that held slots at the last scheduling pass.
+	reservations       []model.ResourcePoolReservation
+	activeReservations []model.ResourcePoolReservation
 
 	provisioner      *provisioner.Provisioner
 	provisionerError error
@@ -76,8 +83,9 @@ func newResourcePool(
 		config: config,
 		cert:   cert,
 
-		scheduler:     scheduler,
-		fittingMethod: fittingMethod,
+		scheduler:       scheduler,
+		fittingMethod:   fittingMethod,
+		workloadClasses: config.Scheduler.WorkloadClasses,
 
 		agentService:   agentService,
 		taskList:       tasklist.New(),
@@ -325,6 +333,22 @@ func (rp *resourcePool) setGroupPriority(msg sproto.SetGroupPriority) error {
 	return nil
 }
 
+// SetPriorityInJob sets the priority of an allocation among the allocations of its job. The
+// allocation keeps its place in the task list, which is ordered by time; the schedulers order the
+// allocations of a job by their priorities when they schedule them.
+func (rp *resourcePool) SetPriorityInJob(msg sproto.SetPriorityInJob) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	req, ok := rp.taskList.TaskByID(msg.AllocationID)
+	if !ok || req.PriorityInJob == msg.PriorityInJob {
+		return
+	}
+	rp.syslog.Infof("setting priority in job of %s to %d", msg.AllocationID, msg.PriorityInJob)
+	req.PriorityInJob = msg.PriorityInJob
+	rp.reschedule = true
+}
+
 func (rp *resourcePool) getOrCreateGroup(jobID model.JobID) *tasklist.Group {
 	if g, ok := rp.groups[jobID]; ok {
 		return g
@@ -357,6 +381,9 @@ func (rp *resourcePool) schedulerTick() {
 			}
 		}
 	}
+	if rp.updateActiveReservations(time.Now()) {
+		rp.reschedule = true
+	}
 	if rp.reschedule {
 		rp.syslog.Trace("scheduling")
 		rp.agentStatesCache = rp.agentService.list(rp.config.PoolName)
@@ -365,7 +392,7 @@ func (rp *resourcePool) schedulerTick() {
 		}()
 
 		rp.pruneTaskList()
-		toAllocate, toRelease := rp.scheduler.Schedule(rp)
+		toAllocate, toRelease := rp.schedule()
 		if len(toAllocate) > 0 || len(toRelease) > 0 {
 			rp.syslog.
 				WithField("toAllocate", len(toAllocate)).
@@ -384,6 +411,158 @@ func (rp *resourcePool) schedulerTick() {
 	rp.rescheduleTimer = time.AfterFunc(actionCoolDown, rp.schedulerTick)
 }
 
+// schedule runs the scheduler of the pool. If the pool shares its slots between workload classes,
+// the scheduler doesn't see the pending tasks that would take slots other classes are entitled
+// to, and tasks of classes over their share are preempted for classes under theirs. Likewise, the
+// scheduler doesn't see the pending tasks that would take slots reserved for other workspaces or
+// users, and tasks using reserved slots are preempted for the tasks the slots are reserved for.
+func (rp *resourcePool) schedule() ([]*sproto.AllocateRequest, []model.AllocationID) {
+	if rp.workloadClasses == nil && len(rp.activeReservations) == 0 {
+		return rp.scheduler.Schedule(rp)
+	}
+
+	reqs := rp.tasksInSchedulingOrder()
+	var pending, scheduled []*sproto.AllocateRequest
+	for _, req := range reqs {
+		if rp.taskList.IsScheduled(req.AllocationID) {
+			scheduled = append(scheduled, req)
+		} else {
+			pending = append(pending, req)
+		}
+	}
+
+	var shares *classShares
+	var reserved *reservedSlots
+	held := make(map[model.AllocationID]bool)
+	if rp.workloadClasses != nil {
+		shares = rp.classShares(reqs)
+		for aID := range shares.heldBack(pending) {
+			held[aID] = true
+		}
+	}
+	if len(rp.activeReservations) > 0 {
+		total, free := rp.slotCounts()
+		reserved = newReservedSlots(rp.activeReservations, total, free, reqs, rp.taskList.IsScheduled)
+		for aID := range reserved.heldBack(pending) {
+			held[aID] = true
+		}
+	}
+
+	taskList := rp.taskList
+	rp.taskList = taskList.Filter(func(req *sproto.AllocateRequest) bool {
+		return !held[req.AllocationID]
+	})
+	toAllocate, toRelease := rp.scheduler.Schedule(rp)
+	rp.taskList = taskList
+
+	if rp.config.Scheduler.GetPreemption() {
+		released := make(map[model.AllocationID]bool, len(toRelease))
+		for _, aID := range toRelease {
+			released[aID] = true
+		}
+		if shares != nil {
+			toRelease = append(toRelease, shares.reclaim(scheduled, released)...)
+		}
+		if reserved != nil {
+			toRelease = append(toRelease, reserved.reclaim(scheduled, released)...)
+		}
+	}
+	return toAllocate, toRelease
+}
+
+// tasksInSchedulingOrder returns the tasks of the pool in the order the scheduler considers them.
+func (rp *resourcePool) tasksInSchedulingOrder() []*sproto.AllocateRequest {
+	if rp.config.Scheduler.Priority != nil {
+		return tasklist.SortTasksWithPosition(rp.taskList, rp.groups, rp.queuePositions, false)
+	}
+	var reqs []*sproto.AllocateRequest
+	for it := rp.taskList.Iterator(); it.Next(); {
+		reqs = append(reqs, it.Value())
+	}
+	return reqs
+}
+
+// classShares computes the shares of the workload classes of the pool. It must be called with
+// the agent states cached.
+func (rp *resourcePool) classShares(reqs []*sproto.AllocateRequest) *classShares {
+	total, free := rp.slotCounts()
+	return newClassShares(*rp.workloadClasses, total, free, reqs, rp.taskList.IsScheduled)
+}
+
+// slotCounts returns the total and free slots of the pool. It must be called with the agent states
+// cached.
+func (rp *resourcePool) slotCounts() (total, free int) {
+	for _, a := range rp.agentStatesCache {
+		total += a.numSlots()
+		free += a.numEmptySlots()
+	}
+	return total, free
+}
+
+// WorkloadClasses returns the configuration and usage of the workload classes of the pool.
+func (rp *resourcePool) WorkloadClasses() *sproto.WorkloadClassesSummary {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	summary := &sproto.WorkloadClassesSummary{Config: rp.workloadClasses}
+	if rp.workloadClasses == nil {
+		return summary
+	}
+
+	rp.agentStatesCache = rp.agentService.list(rp.config.PoolName)
+	defer func() {
+		rp.agentStatesCache = nil
+	}()
+	summary.Usage = rp.classShares(rp.tasksInSchedulingOrder()).summary()
+	return summary
+}
+
+// SetWorkloadClasses replaces how the pool shares its slots between workload classes until the
+// master restarts. A nil config stops sharing slots between classes.
+func (rp *resourcePool) SetWorkloadClasses(cfg *config.WorkloadClassesConfig) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.reschedule = true
+
+	rp.syslog.Infof("workload classes changed to %+v", cfg)
+	rp.workloadClasses = cfg
+}
+
+// SetReservations replaces the current and upcoming reservations of the pool. Each reservation
+// holds its slots from its start to its end time.
+func (rp *resourcePool) SetReservations(reservations []model.ResourcePoolReservation) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.reservations = reservations
+	rp.activeReservations = nil
+	rp.reschedule = true
+}
+
+// updateActiveReservations drops the reservations that ended and finds those active at now,
+// returning whether they changed since the last scheduling pass.
+func (rp *resourcePool) updateActiveReservations(now time.Time) bool {
+	var current, active []model.ResourcePoolReservation
+	for _, r := range rp.reservations {
+		if !now.Before(r.EndTime) {
+			rp.syslog.Infof("reservation %d ended, releasing its slots", r.ID)
+			continue
+		}
+		current = append(current, r)
+		if r.Active(now) {
+			active = append(active, r)
+		}
+	}
+	rp.reservations = current
+
+	changed := len(active) != len(rp.activeReservations)
+	for i := 0; !changed && i < len(active); i++ {
+		changed = active[i].ID != rp.activeReservations[i].ID
+	}
+	rp.activeReservations = active
+	return changed
+}
+
 // allocateResources assigns resources based on a request and notifies the request
 // handler of the assignment. It returns true if it is successfully allocated.
 func (rp *resourcePool) allocateResources(req *sproto.AllocateRequest) bool {
@@ -422,6 +601,7 @@ func (rp *resourcePool) allocateResources(req *sproto.AllocateRequest) bool {
 		containerID := cproto.NewID()
 		resp, err := fit.Agent.handler.AllocateFreeDevices(allocateFreeDevices{
 			slots:       fit.Slots,
+			scratchGiB:  req.FittingRequirements.ScratchGiB,
 			containerID: containerID,
 		})
 		if err != nil {
@@ -592,6 +772,52 @@ func (rp *resourcePool) ValidateResources(
 	return sproto.ValidateResourcesResponse{Fulfillable: fulfillable}
 }
 
+// gpuMemoryAvailability returns whether the pool could ever run a task whose GPUs need at least
+// the given memory, and whether an agent connected now has such GPUs. The pool can't only if its
+// provisioner launches instances without GPUs; agents of other pools may still connect, restart or
+// be launched.
+func (rp *resourcePool) gpuMemoryAvailability(minMemory int) (possible, current bool) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if p := rp.config.Provider; p != nil {
+		switch {
+		case p.AWS != nil && p.AWS.SlotType() != device.CUDA:
+			return false, false
+		case p.GCP != nil && p.GCP.SlotType() != device.CUDA:
+			return false, false
+		}
+	}
+	req := &sproto.AllocateRequest{
+		SlotsNeeded:         1,
+		FittingRequirements: sproto.FittingRequirements{MinGPUMemoryMiB: minMemory},
+	}
+	for _, agent := range rp.agentService.list(rp.config.PoolName) {
+		if gpuMemorySatisfied(req, agent) {
+			return true, true
+		}
+	}
+	return true, false
+}
+
+// hasScratchCapacity returns whether an agent of the pool has room for a scratch volume of the
+// given size once its other containers exit. Pools with a provisioner may launch such agents, so
+// they always qualify.
+func (rp *resourcePool) hasScratchCapacity(gib int) bool {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.provisioner != nil {
+		return true
+	}
+	for _, agent := range rp.agentService.list(rp.config.PoolName) {
+		if agent.scratchCapacityGiB >= gib {
+			return true
+		}
+	}
+	return false
+}
+
 // GetResourceSummary requests a summary of the resources used by the resource pool (agents, slots, cpu containers).
 func (rp *resourcePool) GetResourceSummary() resourceSummary {
 	rp.mu.Lock()
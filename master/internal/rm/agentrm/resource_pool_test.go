@@ -9,6 +9,7 @@ import (
 	"gotest.tools/assert"
 
 	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/aproto"
@@ -266,3 +267,31 @@ func TestGetResourceSummary(t *testing.T) {
 		})
 	}
 }
+
+func TestGPUMemoryAvailability(t *testing.T) {
+	rp := &resourcePool{
+		config:       &config.ResourcePoolConfig{PoolName: "pool"},
+		agentService: &agents{agents: tasklist.NewRegistry[aproto.ID, *agent]()},
+	}
+
+	// With no agent connected, a task may still wait for one to connect.
+	possible, current := rp.gpuMemoryAvailability(16000)
+	require.True(t, possible)
+	require.False(t, current)
+
+	// A provisioner that launches GPU instances may launch one with enough memory.
+	rp.config.Provider = &provconfig.Config{AWS: &provconfig.AWSClusterConfig{
+		InstanceType: "p3.8xlarge",
+	}}
+	possible, _ = rp.gpuMemoryAvailability(16000)
+	require.True(t, possible)
+
+	// One that launches CPU instances never can.
+	rp.config.Provider = &provconfig.Config{AWS: &provconfig.AWSClusterConfig{
+		InstanceType:    "m5.large",
+		CPUSlotsAllowed: true,
+	}}
+	possible, current = rp.gpuMemoryAvailability(16000)
+	require.False(t, possible)
+	require.False(t, current)
+}
@@ -56,6 +56,13 @@ func (c containerResources) Start(
 	spec.ExtraEnvVars[sproto.ResourcesTypeEnvVar] = string(sproto.ResourcesTypeDockerContainer)
 	spec.UseHostMode = rri.IsMultiAgent
 	spec.Devices = c.devices
+	detection := spec.TaskContainerDefaults.DtrainNetworkInterfaceDetection
+	if rri.IsMultiAgent && spec.TaskContainerDefaults.DtrainNetworkInterface == "" &&
+		(detection == nil || *detection) {
+		// Agents name their interfaces differently, so each container gets the interface its own
+		// agent reported rather than one common name.
+		spec.TaskContainerDefaults.DtrainNetworkInterface = c.agent.handler.DtrainNetworkInterface()
+	}
 
 	c.agent.handler.StartTaskContainer(sproto.StartTaskContainer{
 		AllocationID: c.req.AllocationID,
@@ -81,6 +88,13 @@ func (c containerResources) Kill(logCtx logger.Context) {
 	})
 }
 
+// Exec starts a process in the container and relays it.
+func (c containerResources) Exec(
+	_ context.Context, req sproto.ExecRequest,
+) (*sproto.ExecSession, error) {
+	return c.agent.handler.Exec(c.containerID, req)
+}
+
 func (c containerResources) persist() error {
 	summary := c.Summary()
 
@@ -0,0 +1,138 @@
+package agentrm
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+)
+
+const (
+	// tunnelBufferSize is the number of messages from the agent buffered for a tunnel. Data is
+	// relayed while holding the agent's lock, so a tunnel that falls further behind is closed
+	// rather than blocking every other message from the agent.
+	tunnelBufferSize = 256
+	// tunnelChunkSize is the most data sent to the agent in one message.
+	tunnelChunkSize = 32 * 1024
+)
+
+// tunnelHost is the host the proxy reaches the services of containers on an agent that accepts no
+// connections from the master at, through tunnels over its connection.
+func tunnelHost(id aproto.ID) string {
+	return string(id) + ".tunnel"
+}
+
+// dialTunnel connects to a port on the agent's host through a tunnel over its connection.
+func (a *agent) dialTunnel(port int) (net.Conn, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch {
+	case a.awaitingReconnect || a.socket == nil:
+		return nil, errRecovering
+	case !a.outboundOnly:
+		return nil, fmt.Errorf("agent %s accepts connections from the master", a.id)
+	}
+
+	tunnelID := uuid.New().String()
+	local, remote := net.Pipe()
+	in := make(chan aproto.TunnelData, tunnelBufferSize)
+	a.tunnels[tunnelID] = in
+	a.socket.Outbox <- aproto.AgentMessage{TunnelOpen: &aproto.TunnelOpen{
+		TunnelID: tunnelID, Port: port,
+	}}
+	a.syslog.Debugf("opened tunnel %s to port %d", tunnelID, port)
+
+	go a.relayTunnelOutput(tunnelID, remote)
+	go relayTunnelInput(remote, in)
+	return local, nil
+}
+
+// relayTunnelOutput sends what is written to a tunnel to the agent, until either side closes it.
+func (a *agent) relayTunnelOutput(tunnelID string, remote net.Conn) {
+	buf := make([]byte, tunnelChunkSize)
+	for {
+		n, err := remote.Read(buf)
+		a.mu.Lock()
+		if _, ok := a.tunnels[tunnelID]; !ok {
+			a.mu.Unlock()
+			return
+		}
+		if n > 0 {
+			a.socket.Outbox <- aproto.AgentMessage{TunnelData: &aproto.TunnelData{
+				TunnelID: tunnelID, Data: append([]byte(nil), buf[:n]...),
+			}}
+		}
+		if err != nil {
+			a.closeTunnel(tunnelID, nil)
+			a.mu.Unlock()
+			return
+		}
+		a.mu.Unlock()
+	}
+}
+
+// relayTunnelInput writes what the agent sends over a tunnel to it, until either side closes it.
+func relayTunnelInput(remote net.Conn, in chan aproto.TunnelData) {
+	defer remote.Close()
+	for msg := range in {
+		if len(msg.Data) > 0 {
+			if _, err := remote.Write(msg.Data); err != nil {
+				return
+			}
+		}
+		if msg.Close {
+			return
+		}
+	}
+}
+
+// tunnelData relays what the agent sends over a tunnel.
+func (a *agent) tunnelData(msg aproto.TunnelData) {
+	in, ok := a.tunnels[msg.TunnelID]
+	if !ok {
+		// The tunnel was closed while the agent was still sending over it.
+		return
+	}
+	if msg.Error != "" {
+		a.syslog.Debugf("tunnel %s failed: %s", msg.TunnelID, msg.Error)
+	}
+	select {
+	case in <- msg:
+	default:
+		a.closeTunnel(msg.TunnelID, errors.New("tunnel fell behind"))
+		return
+	}
+	if msg.Close {
+		delete(a.tunnels, msg.TunnelID)
+		close(in)
+	}
+}
+
+// closeTunnel closes a tunnel, logging err if it is set, and tells the agent to close it too.
+func (a *agent) closeTunnel(tunnelID string, err error) {
+	in, ok := a.tunnels[tunnelID]
+	if !ok {
+		return
+	}
+	delete(a.tunnels, tunnelID)
+	close(in)
+	if err != nil {
+		a.syslog.Debugf("closing tunnel %s: %s", tunnelID, err)
+	}
+	if a.socket != nil && !a.awaitingReconnect {
+		a.socket.Outbox <- aproto.AgentMessage{TunnelData: &aproto.TunnelData{
+			TunnelID: tunnelID, Close: true,
+		}}
+	}
+}
+
+// closeTunnels closes every tunnel through the agent.
+func (a *agent) closeTunnels(err error) {
+	for tunnelID := range a.tunnels {
+		a.closeTunnel(tunnelID, err)
+	}
+}
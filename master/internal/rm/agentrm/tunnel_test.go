@@ -0,0 +1,68 @@
+package agentrm
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/ws"
+)
+
+func newTunnelTestAgent(outboundOnly bool) (*agent, chan aproto.AgentMessage) {
+	outbox := make(chan aproto.AgentMessage, 16)
+	return &agent{
+		syslog:       logrus.WithField("component", "agent"),
+		id:           "test",
+		socket:       &ws.WebSocket[*aproto.MasterMessage, aproto.AgentMessage]{Outbox: outbox},
+		outboundOnly: outboundOnly,
+		tunnels:      map[string]chan aproto.TunnelData{},
+	}, outbox
+}
+
+func TestAgentTunnel(t *testing.T) {
+	a, outbox := newTunnelTestAgent(true)
+	conn, err := a.dialTunnel(8888)
+	require.NoError(t, err)
+	open := (<-outbox).TunnelOpen
+	require.NotNil(t, open)
+	require.Equal(t, 8888, open.Port)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	data := (<-outbox).TunnelData
+	require.Equal(t, open.TunnelID, data.TunnelID)
+	require.Equal(t, []byte("ping"), data.Data)
+
+	a.HandleIncomingWebsocketMessage(&aproto.MasterMessage{TunnelData: &aproto.TunnelData{
+		TunnelID: open.TunnelID, Data: []byte("pong"),
+	}})
+	a.HandleIncomingWebsocketMessage(&aproto.MasterMessage{TunnelData: &aproto.TunnelData{
+		TunnelID: open.TunnelID, Close: true,
+	}})
+	out, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Equal(t, []byte("pong"), out)
+	require.NoError(t, conn.Close())
+	require.Empty(t, a.tunnels)
+}
+
+func TestAgentTunnelClosedByMaster(t *testing.T) {
+	a, outbox := newTunnelTestAgent(true)
+	conn, err := a.dialTunnel(8888)
+	require.NoError(t, err)
+	open := (<-outbox).TunnelOpen
+
+	require.NoError(t, conn.Close())
+	data := (<-outbox).TunnelData
+	require.Equal(t, open.TunnelID, data.TunnelID)
+	require.True(t, data.Close)
+}
+
+func TestAgentTunnelUnsupported(t *testing.T) {
+	a, _ := newTunnelTestAgent(false)
+	_, err := a.dialTunnel(8888)
+	require.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package agentrm
+
+import (
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+var workloadClasses = []sproto.WorkloadClass{
+	sproto.WorkloadClassExperiment,
+	sproto.WorkloadClassNTSC,
+}
+
+// workloadClassOf returns the workload class of a task. Tasks of other types, such as checkpoint
+// GC and generic tasks, don't belong to a class and aren't limited by the shares of the classes.
+func workloadClassOf(req *sproto.AllocateRequest) (sproto.WorkloadClass, bool) {
+	switch req.TaskType {
+	case model.TaskTypeTrial:
+		return sproto.WorkloadClassExperiment, true
+	case model.TaskTypeNotebook, model.TaskTypeShell, model.TaskTypeCommand,
+		model.TaskTypeTensorboard:
+		return sproto.WorkloadClassNTSC, true
+	default:
+		return "", false
+	}
+}
+
+// classShares holds the shares of the slots of a resource pool between its workload classes
+// during a scheduling pass.
+type classShares struct {
+	config  config.WorkloadClassesConfig
+	total   int
+	free    int
+	used    map[sproto.WorkloadClass]int
+	pending map[sproto.WorkloadClass]int
+
+	pendingReqs map[sproto.WorkloadClass][]*sproto.AllocateRequest
+}
+
+func newClassShares(
+	cfg config.WorkloadClassesConfig, total, free int,
+	reqs []*sproto.AllocateRequest, isScheduled func(model.AllocationID) bool,
+) *classShares {
+	s := &classShares{
+		config:  cfg,
+		total:   total,
+		free:    free,
+		used:    make(map[sproto.WorkloadClass]int),
+		pending: make(map[sproto.WorkloadClass]int),
+
+		pendingReqs: make(map[sproto.WorkloadClass][]*sproto.AllocateRequest),
+	}
+	for _, req := range reqs {
+		class, ok := workloadClassOf(req)
+		if !ok {
+			continue
+		}
+		if isScheduled(req.AllocationID) {
+			s.used[class] += req.SlotsNeeded
+		} else {
+			s.pending[class] += req.SlotsNeeded
+			s.pendingReqs[class] = append(s.pendingReqs[class], req)
+		}
+	}
+	return s
+}
+
+func (s *classShares) classConfig(class sproto.WorkloadClass) config.WorkloadClassConfig {
+	if class == sproto.WorkloadClassNTSC {
+		return s.config.NTSC
+	}
+	return s.config.Experiment
+}
+
+func (s *classShares) active(class sproto.WorkloadClass) bool {
+	return s.used[class]+s.pending[class] > 0
+}
+
+// entitled returns the slots a class is entitled to: its weighted share of the pool among the
+// classes that run or wait for tasks, and at least its reserved slots.
+func (s *classShares) entitled(class sproto.WorkloadClass) int {
+	var weights float64
+	for _, c := range workloadClasses {
+		if s.active(c) {
+			weights += s.classConfig(c).Weight
+		}
+	}
+	cfg := s.classConfig(class)
+	share := 0
+	if s.active(class) && weights > 0 {
+		share = int(float64(s.total) * cfg.Weight / weights)
+	}
+	return max(share, cfg.ReservedSlots)
+}
+
+// claimed returns the slots a class is entitled to but doesn't use that other classes must leave
+// to it: its unused reserved slots, and as much of its unused share as its pending tasks need.
+func (s *classShares) claimed(class sproto.WorkloadClass) int {
+	unusedReservation := s.classConfig(class).ReservedSlots - s.used[class]
+	return max(0, unusedReservation, s.neededWithinEntitlement(class))
+}
+
+// limit returns the slots a class may use without taking the slots other classes claim.
+func (s *classShares) limit(class sproto.WorkloadClass) int {
+	limit := s.total
+	for _, c := range workloadClasses {
+		if c != class {
+			limit -= s.claimed(c)
+		}
+	}
+	return limit
+}
+
+// heldBack returns the pending tasks that can't start without their class exceeding its limit.
+// The tasks are given in scheduling order, and tasks later in the order can start if they fit in
+// what is left of the limit.
+func (s *classShares) heldBack(reqs []*sproto.AllocateRequest) map[model.AllocationID]bool {
+	held := make(map[model.AllocationID]bool)
+	admitted := make(map[sproto.WorkloadClass]int)
+	for _, req := range reqs {
+		class, ok := workloadClassOf(req)
+		if !ok || req.SlotsNeeded == 0 {
+			continue
+		}
+		if s.used[class]+admitted[class]+req.SlotsNeeded > s.limit(class) {
+			held[req.AllocationID] = true
+			continue
+		}
+		admitted[class] += req.SlotsNeeded
+	}
+	return held
+}
+
+// reclaim returns the running tasks to preempt so that the pending tasks of classes under their
+// entitlement can start, taking slots from classes over their entitlement. The tasks are given in
+// scheduling order, and tasks are preempted from the end of the order.
+func (s *classShares) reclaim(
+	scheduled []*sproto.AllocateRequest, released map[model.AllocationID]bool,
+) []model.AllocationID {
+	var toRelease []model.AllocationID
+	for _, class := range workloadClasses {
+		need := s.neededWithinEntitlement(class) - s.free
+		for i := len(scheduled) - 1; i >= 0 && need > 0; i-- {
+			req := scheduled[i]
+			other, ok := workloadClassOf(req)
+			if !ok || other == class || released[req.AllocationID] ||
+				!req.Preemption.Preemptible || req.SlotsNeeded == 0 {
+				continue
+			}
+			if s.used[other]-req.SlotsNeeded < s.entitled(other) {
+				continue
+			}
+			toRelease = append(toRelease, req.AllocationID)
+			released[req.AllocationID] = true
+			s.used[other] -= req.SlotsNeeded
+			need -= req.SlotsNeeded
+		}
+	}
+	return toRelease
+}
+
+// neededWithinEntitlement returns the slots the pending tasks of a class need, counting only the
+// tasks that fit in what the class is entitled to but doesn't use.
+func (s *classShares) neededWithinEntitlement(class sproto.WorkloadClass) int {
+	room := s.entitled(class) - s.used[class]
+	needed := 0
+	for _, req := range s.pendingReqs[class] {
+		if needed+req.SlotsNeeded <= room {
+			needed += req.SlotsNeeded
+		}
+	}
+	return needed
+}
+
+// summary returns the usage of the pool by each class.
+func (s *classShares) summary() []sproto.WorkloadClassUsage {
+	var usage []sproto.WorkloadClassUsage
+	for _, class := range workloadClasses {
+		usage = append(usage, sproto.WorkloadClassUsage{
+			Class:         class,
+			EntitledSlots: s.entitled(class),
+			UsedSlots:     s.used[class],
+			PendingSlots:  s.pending[class],
+		})
+	}
+	return usage
+}
@@ -0,0 +1,114 @@
+package agentrm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+type classTask struct {
+	taskType  model.TaskType
+	slots     int
+	scheduled bool
+}
+
+func newTestClassShares(
+	cfg config.WorkloadClassesConfig, total int, tasks []classTask,
+) (*classShares, []*sproto.AllocateRequest) {
+	var reqs []*sproto.AllocateRequest
+	scheduled := make(map[model.AllocationID]bool)
+	used := 0
+	for i, task := range tasks {
+		req := &sproto.AllocateRequest{
+			AllocationID: model.AllocationID(fmt.Sprintf("task%d", i)),
+			TaskType:     task.taskType,
+			SlotsNeeded:  task.slots,
+			Preemption:   sproto.PreemptionConfig{Preemptible: true},
+		}
+		reqs = append(reqs, req)
+		if task.scheduled {
+			scheduled[req.AllocationID] = true
+			used += task.slots
+		}
+	}
+	isScheduled := func(id model.AllocationID) bool { return scheduled[id] }
+	return newClassShares(cfg, total, total-used, reqs, isScheduled), reqs
+}
+
+func TestWorkloadClassesReservedSlots(t *testing.T) {
+	cfg := config.WorkloadClassesConfig{
+		Experiment: config.WorkloadClassConfig{Weight: 1},
+		NTSC:       config.WorkloadClassConfig{ReservedSlots: 2},
+	}
+	shares, reqs := newTestClassShares(cfg, 8, []classTask{
+		{model.TaskTypeTrial, 6, true},
+		{model.TaskTypeTrial, 2, false},
+		{model.TaskTypeCheckpointGC, 2, false},
+	})
+
+	// The reservation is held even though no notebook waits for it, and tasks without a class
+	// aren't limited.
+	require.Equal(t, 6, shares.limit(sproto.WorkloadClassExperiment))
+	require.Equal(t, map[model.AllocationID]bool{reqs[1].AllocationID: true},
+		shares.heldBack(reqs[1:]))
+}
+
+func TestWorkloadClassesWeights(t *testing.T) {
+	cfg := config.WorkloadClassesConfig{
+		Experiment: config.WorkloadClassConfig{Weight: 2},
+		NTSC:       config.WorkloadClassConfig{Weight: 1},
+	}
+
+	// An idle class doesn't hold its share.
+	shares, _ := newTestClassShares(cfg, 12, []classTask{
+		{model.TaskTypeTrial, 12, false},
+	})
+	require.Equal(t, 12, shares.entitled(sproto.WorkloadClassExperiment))
+	require.Equal(t, 12, shares.limit(sproto.WorkloadClassExperiment))
+
+	shares, reqs := newTestClassShares(cfg, 12, []classTask{
+		{model.TaskTypeTrial, 4, false},
+		{model.TaskTypeTrial, 4, false},
+		{model.TaskTypeTrial, 4, false},
+		{model.TaskTypeNotebook, 1, false},
+		{model.TaskTypeShell, 1, false},
+	})
+	require.Equal(t, 8, shares.entitled(sproto.WorkloadClassExperiment))
+	require.Equal(t, 4, shares.entitled(sproto.WorkloadClassNTSC))
+	// The NTSC class only claims what its pending tasks need.
+	require.Equal(t, 10, shares.limit(sproto.WorkloadClassExperiment))
+	require.Equal(t, map[model.AllocationID]bool{reqs[2].AllocationID: true},
+		shares.heldBack(reqs))
+}
+
+func TestWorkloadClassesReclaim(t *testing.T) {
+	cfg := config.WorkloadClassesConfig{
+		Experiment: config.WorkloadClassConfig{Weight: 1},
+		NTSC:       config.WorkloadClassConfig{Weight: 1},
+	}
+	shares, reqs := newTestClassShares(cfg, 8, []classTask{
+		{model.TaskTypeTrial, 2, true},
+		{model.TaskTypeTrial, 2, true},
+		{model.TaskTypeTrial, 2, true},
+		{model.TaskTypeTrial, 2, true},
+		{model.TaskTypeNotebook, 2, false},
+	})
+
+	released := map[model.AllocationID]bool{}
+	require.Equal(t, []model.AllocationID{reqs[3].AllocationID},
+		shares.reclaim(reqs[:4], released))
+	require.Equal(t, 6, shares.used[sproto.WorkloadClassExperiment])
+
+	// Tasks aren't preempted past the share of their class.
+	shares, reqs = newTestClassShares(cfg, 8, []classTask{
+		{model.TaskTypeTrial, 4, true},
+		{model.TaskTypeTrial, 4, true},
+		{model.TaskTypeNotebook, 8, false},
+	})
+	require.Empty(t, shares.reclaim(reqs[:2], map[model.AllocationID]bool{}))
+}
@@ -555,16 +555,48 @@ func (*DispatcherResourceManager) SetGroupPriority(sproto.SetGroupPriority) erro
 	return rmerrors.UnsupportedError("set group priority unsupported in the dispatcher RM")
 }
 
+// SetPriorityInJob implements rm.ResourceManager.
+func (*DispatcherResourceManager) SetPriorityInJob(sproto.SetPriorityInJob) error {
+	return rmerrors.UnsupportedError("priorities within jobs unsupported in the dispatcher RM")
+}
+
 // SetGroupWeight implements rm.ResourceManager.
 func (*DispatcherResourceManager) SetGroupWeight(sproto.SetGroupWeight) error {
 	// TODO(HAL-2863)
 	return rmerrors.UnsupportedError("set group weight unsupported in the dispatcher RM")
 }
 
+// GetWorkloadClasses implements rm.ResourceManager.
+func (*DispatcherResourceManager) GetWorkloadClasses(
+	rm.ResourcePoolName,
+) (*sproto.WorkloadClassesSummary, error) {
+	return nil, rmerrors.UnsupportedError("workload classes unsupported in the dispatcher RM")
+}
+
+// SetWorkloadClasses implements rm.ResourceManager.
+func (*DispatcherResourceManager) SetWorkloadClasses(
+	rm.ResourcePoolName, *config.WorkloadClassesConfig,
+) error {
+	return rmerrors.UnsupportedError("workload classes unsupported in the dispatcher RM")
+}
+
+// SetReservations implements rm.ResourceManager.
+func (*DispatcherResourceManager) SetReservations(
+	rm.ResourcePoolName, []model.ResourcePoolReservation,
+) error {
+	return rmerrors.UnsupportedError("reservations unsupported in the dispatcher RM")
+}
+
 // ValidateResources implements rm.ResourceManager.
 func (*DispatcherResourceManager) ValidateResources(
 	req sproto.ValidateResourcesRequest,
 ) ([]command.LaunchWarning, error) {
+	if req.MinGPUMemoryMiB > 0 {
+		return nil, rmerrors.UnsupportedError("min_gpu_memory_mib unsupported in the dispatcher RM")
+	}
+	if req.Scratch != nil {
+		return nil, rmerrors.UnsupportedError("scratch volumes unsupported in the dispatcher RM")
+	}
 	// TODO(HAL-2862): Use inferred value here if possible.
 	// fulfillable := m.config.MaxSlotsPerContainer >= msg.Slots
 	return nil, nil
@@ -59,6 +59,10 @@ type exitReason struct {
 	code        int
 	msg         string
 	failureType sproto.FailureType
+	// oomKilled is whether Kubernetes reports that the container was killed for running out of
+	// memory.
+	oomKilled bool
+	reason    aproto.FailureReason
 }
 
 func (r *exitReason) String() string {
@@ -205,6 +209,7 @@ func (j *job) finalize() {
 		j.jobExitCause = &exitReason{
 			failureType: sproto.TaskError,
 			msg:         "job crashed",
+			reason:      aproto.InfraFailureReason,
 		}
 		j.informTaskResourcesStopped()
 	}
@@ -223,10 +228,16 @@ func (j *job) exitCause() *sproto.ResourcesFailedError {
 	if j.jobExitCause.code > 0 {
 		exitCode = (*sproto.ExitCode)(&j.jobExitCause.code)
 	}
+	var diagnostics *aproto.ContainerDiagnostics
+	if j.jobExitCause.oomKilled {
+		diagnostics = &aproto.ContainerDiagnostics{OOMKilled: true}
+	}
 	return &sproto.ResourcesFailedError{
 		FailureType: failureType,
 		ErrMsg:      j.jobExitCause.msg,
 		ExitCode:    exitCode,
+		Diagnostics: diagnostics,
+		Reason:      j.jobExitCause.reason,
 	}
 }
 
@@ -450,6 +461,7 @@ func (j *job) podDeletedCallback(deleted *k8sV1.Pod) {
 		j.jobExitCause = &exitReason{
 			failureType: sproto.TaskError,
 			msg:         fmt.Sprintf("pod %s deleted", deleted.Name),
+			reason:      aproto.InfraFailureReason,
 		}
 	}
 }
@@ -572,7 +584,9 @@ func (j *job) createSpecAndSubmit(spec *tasks.TaskSpec) error {
 		return err
 	}
 
-	j.resourceRequestQueue.createKubernetesResources(jobSpec, configMapSpec, j.makeGatewayComms(spec))
+	j.resourceRequestQueue.createKubernetesResources(
+		jobSpec, configMapSpec, j.makeGatewayComms(spec), j.makeNetworkPolicyRequest(spec),
+	)
 	return nil
 }
 
@@ -769,8 +783,9 @@ func getExitCodeAndMessage(pod *k8sV1.Pod, containerNames set.Set[string]) (*exi
 		terminationStatus := containerStatus.State.Terminated
 		if terminationStatus != nil {
 			return &exitReason{
-				code: int(terminationStatus.ExitCode),
-				msg:  terminationStatus.Message,
+				code:      int(terminationStatus.ExitCode),
+				msg:       terminationStatus.Message,
+				oomKilled: terminationStatus.Reason == "OOMKilled",
 			}, nil
 		}
 	}
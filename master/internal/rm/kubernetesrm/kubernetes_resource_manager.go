@@ -342,6 +342,11 @@ func (k *ResourceManager) SetGroupPriority(msg sproto.SetGroupPriority) error {
 	return rp.SetGroupPriority(msg)
 }
 
+// SetPriorityInJob implements rm.ResourceManager.
+func (k *ResourceManager) SetPriorityInJob(sproto.SetPriorityInJob) error {
+	return rmerrors.UnsupportedError("priorities within jobs unsupported in the kubernetes RM")
+}
+
 // SetGroupWeight implements rm.ResourceManager.
 func (k *ResourceManager) SetGroupWeight(msg sproto.SetGroupWeight) error {
 	rp, err := k.poolByName(msg.ResourcePool)
@@ -352,6 +357,27 @@ func (k *ResourceManager) SetGroupWeight(msg sproto.SetGroupWeight) error {
 	return rp.SetGroupWeight(msg)
 }
 
+// GetWorkloadClasses implements rm.ResourceManager.
+func (k *ResourceManager) GetWorkloadClasses(
+	rm.ResourcePoolName,
+) (*sproto.WorkloadClassesSummary, error) {
+	return nil, rmerrors.UnsupportedError("workload classes unsupported in the kubernetes RM")
+}
+
+// SetWorkloadClasses implements rm.ResourceManager.
+func (k *ResourceManager) SetWorkloadClasses(
+	rm.ResourcePoolName, *config.WorkloadClassesConfig,
+) error {
+	return rmerrors.UnsupportedError("workload classes unsupported in the kubernetes RM")
+}
+
+// SetReservations implements rm.ResourceManager.
+func (k *ResourceManager) SetReservations(
+	rm.ResourcePoolName, []model.ResourcePoolReservation,
+) error {
+	return rmerrors.UnsupportedError("reservations unsupported in the kubernetes RM")
+}
+
 // ValidateResources implements rm.ResourceManager.
 func (k *ResourceManager) ValidateResources(
 	msg sproto.ValidateResourcesRequest,
@@ -359,6 +385,9 @@ func (k *ResourceManager) ValidateResources(
 	if msg.Slots == 0 {
 		return nil, nil
 	}
+	if msg.MinGPUMemoryMiB > 0 {
+		return nil, rmerrors.UnsupportedError("min_gpu_memory_mib unsupported in the kubernetes RM")
+	}
 
 	rp, err := k.poolByName(msg.ResourcePool)
 	if err != nil {
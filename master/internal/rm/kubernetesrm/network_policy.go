@@ -0,0 +1,101 @@
+package kubernetesrm
+
+import (
+	"slices"
+
+	k8sV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	typedNetworkingV1 "k8s.io/client-go/kubernetes/typed/networking/v1"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+)
+
+// namespaceNameLabel is set by Kubernetes on every namespace to its name.
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// networkPolicyRequest is a NetworkPolicy to create alongside a job's other resources.
+type networkPolicyRequest struct {
+	spec     *networkingV1.NetworkPolicy
+	policies typedNetworkingV1.NetworkPolicyInterface
+}
+
+func (j *job) makeNetworkPolicyRequest(spec *tasks.TaskSpec) *networkPolicyRequest {
+	k := spec.TaskContainerDefaults.Kubernetes
+	if k == nil || k.NetworkPolicy == nil || !k.NetworkPolicy.Enabled {
+		return nil
+	}
+	return &networkPolicyRequest{
+		spec:     j.networkPolicySpec(spec.AllocationID, *k.NetworkPolicy),
+		policies: j.clientSet.NetworkingV1().NetworkPolicies(j.namespace),
+	}
+}
+
+// networkPolicySpec restricts an allocation's pods to traffic with each other, the master, the
+// internal task gateway if there is one, DNS and any other allowed addresses.
+func (j *job) networkPolicySpec(
+	allocationID string, cfg model.KubernetesNetworkPolicyConfig,
+) *networkingV1.NetworkPolicy {
+	allocLabels := map[string]string{determinedLabel: allocationID}
+	peers := []networkingV1.NetworkPolicyPeer{
+		{PodSelector: &metaV1.LabelSelector{MatchLabels: allocLabels}},
+	}
+	if len(cfg.MasterPodLabels) > 0 {
+		master := networkingV1.NetworkPolicyPeer{
+			PodSelector: &metaV1.LabelSelector{MatchLabels: cfg.MasterPodLabels},
+		}
+		if cfg.MasterNamespace != "" {
+			master.NamespaceSelector = namespaceSelector(cfg.MasterNamespace)
+		}
+		peers = append(peers, master)
+	}
+	for _, cidr := range cfg.MasterCIDRs {
+		peers = append(peers, networkingV1.NetworkPolicyPeer{
+			IPBlock: &networkingV1.IPBlock{CIDR: cidr},
+		})
+	}
+
+	ingressPeers := slices.Clone(peers)
+	if j.internalTaskGWConfig != nil {
+		ingressPeers = append(ingressPeers, networkingV1.NetworkPolicyPeer{
+			NamespaceSelector: namespaceSelector(j.internalTaskGWConfig.GatewayNamespace),
+		})
+	}
+
+	egress := []networkingV1.NetworkPolicyEgressRule{
+		{To: peers},
+		{Ports: []networkingV1.NetworkPolicyPort{
+			{Protocol: ptrs.Ptr(k8sV1.ProtocolUDP), Port: ptrs.Ptr(intstr.FromInt32(53))},
+			{Protocol: ptrs.Ptr(k8sV1.ProtocolTCP), Port: ptrs.Ptr(intstr.FromInt32(53))},
+		}},
+	}
+	for _, cidr := range cfg.AllowedEgressCIDRs {
+		egress = append(egress, networkingV1.NetworkPolicyEgressRule{
+			To: []networkingV1.NetworkPolicyPeer{{IPBlock: &networkingV1.IPBlock{CIDR: cidr}}},
+		})
+	}
+
+	return &networkingV1.NetworkPolicy{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:        j.jobName,
+			Namespace:   j.namespace,
+			Labels:      allocLabels,
+			Annotations: map[string]string{jobNameAnnotation: j.jobName},
+		},
+		Spec: networkingV1.NetworkPolicySpec{
+			PodSelector: metaV1.LabelSelector{MatchLabels: allocLabels},
+			PolicyTypes: []networkingV1.PolicyType{
+				networkingV1.PolicyTypeIngress, networkingV1.PolicyTypeEgress,
+			},
+			Ingress: []networkingV1.NetworkPolicyIngressRule{{From: ingressPeers}},
+			Egress:  egress,
+		},
+	}
+}
+
+func namespaceSelector(namespace string) *metaV1.LabelSelector {
+	return &metaV1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: namespace}}
+}
@@ -0,0 +1,69 @@
+//nolint:exhaustruct
+package kubernetesrm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+)
+
+func TestMakeNetworkPolicyRequestDisabled(t *testing.T) {
+	j := &job{namespace: "podnamespace", jobName: "sharedName"}
+	spec := &tasks.TaskSpec{AllocationID: "allocID"}
+	require.Nil(t, j.makeNetworkPolicyRequest(spec))
+
+	spec.TaskContainerDefaults.Kubernetes = &model.KubernetesTaskContainerDefaults{
+		NetworkPolicy: &model.KubernetesNetworkPolicyConfig{
+			Enabled:     false,
+			MasterCIDRs: []string{"10.0.0.1/32"},
+		},
+	}
+	require.Nil(t, j.makeNetworkPolicyRequest(spec))
+}
+
+func TestNetworkPolicySpec(t *testing.T) {
+	j := &job{namespace: "podnamespace", jobName: "sharedName"}
+	cfg := model.KubernetesNetworkPolicyConfig{
+		Enabled:            true,
+		MasterPodLabels:    map[string]string{"app": "determined-master"},
+		MasterNamespace:    "default",
+		MasterCIDRs:        []string{"10.0.0.1/32"},
+		AllowedEgressCIDRs: []string{"10.20.0.0/16"},
+	}
+	allocLabels := map[string]string{determinedLabel: "allocID"}
+	peers := []networkingV1.NetworkPolicyPeer{
+		{PodSelector: &metaV1.LabelSelector{MatchLabels: allocLabels}},
+		{
+			PodSelector:       &metaV1.LabelSelector{MatchLabels: cfg.MasterPodLabels},
+			NamespaceSelector: namespaceSelector("default"),
+		},
+		{IPBlock: &networkingV1.IPBlock{CIDR: "10.0.0.1/32"}},
+	}
+
+	policy := j.networkPolicySpec("allocID", cfg)
+	require.Equal(t, "sharedName", policy.Name)
+	require.Equal(t, "podnamespace", policy.Namespace)
+	require.Equal(t, allocLabels, policy.Spec.PodSelector.MatchLabels)
+	require.Equal(t, []networkingV1.PolicyType{
+		networkingV1.PolicyTypeIngress, networkingV1.PolicyTypeEgress,
+	}, policy.Spec.PolicyTypes)
+	require.Equal(t, []networkingV1.NetworkPolicyIngressRule{{From: peers}}, policy.Spec.Ingress)
+	require.Len(t, policy.Spec.Egress, 3)
+	require.Equal(t, peers, policy.Spec.Egress[0].To)
+	require.Len(t, policy.Spec.Egress[1].Ports, 2, "DNS is allowed")
+	require.Equal(t, "10.20.0.0/16", policy.Spec.Egress[2].To[0].IPBlock.CIDR)
+
+	// The internal task gateway proxies connections to tasks.
+	j.internalTaskGWConfig = &config.InternalTaskGatewayConfig{GatewayNamespace: "gatewaynamespace"}
+	policy = j.networkPolicySpec("allocID", cfg)
+	from := policy.Spec.Ingress[0].From
+	require.Len(t, from, len(peers)+1)
+	require.Equal(t, namespaceSelector("gatewaynamespace"), from[len(from)-1].NamespaceSelector)
+	require.Equal(t, peers, policy.Spec.Egress[0].To, "only ingress is allowed from the gateway")
+}
@@ -0,0 +1,97 @@
+package kubernetesrm
+
+import (
+	"slices"
+
+	k8sV1 "k8s.io/api/core/v1"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+// podSecurityOptOuts returns which of a pool's pod security defaults a task's pod spec, as the user
+// specified it, opts out of.
+func podSecurityOptOuts(k *model.KubernetesTaskContainerDefaults, pod *k8sV1.Pod) []string {
+	if k == nil {
+		return nil
+	}
+	return k.PodSecurityOptOuts(pod)
+}
+
+// configurePodSecurity applies a pool's pod security defaults, except those opted out of, to a
+// task's pod and every one of its containers and init containers, the user's included.
+func configurePodSecurity(
+	k *model.KubernetesTaskContainerDefaults, optOuts []string, pod *k8sV1.Pod,
+) {
+	if k == nil {
+		return
+	}
+
+	if k.RuntimeClassName != nil && !slices.Contains(optOuts, model.PodSecurityRuntimeClassName) {
+		pod.Spec.RuntimeClassName = ptrs.Ptr(*k.RuntimeClassName)
+	}
+
+	sc := k.SecurityContext
+	if sc == nil {
+		return
+	}
+	runAsNonRoot := sc.RunAsNonRoot && !slices.Contains(optOuts, model.PodSecurityRunAsNonRoot)
+	profile := sc.K8sSeccompProfile()
+	if slices.Contains(optOuts, model.PodSecuritySeccompProfile) {
+		profile = nil
+	}
+	if runAsNonRoot || profile != nil {
+		pod.Spec.SecurityContext = pod.Spec.SecurityContext.DeepCopy()
+		if pod.Spec.SecurityContext == nil {
+			pod.Spec.SecurityContext = &k8sV1.PodSecurityContext{}
+		}
+	}
+	if profile != nil {
+		pod.Spec.SecurityContext.SeccompProfile = profile
+	}
+	if runAsNonRoot {
+		podSC := pod.Spec.SecurityContext
+		podSC.RunAsNonRoot = ptrs.Ptr(true)
+		podSC.RunAsUser = nonRootID(podSC.RunAsUser, sc.NonRootUID)
+		podSC.RunAsGroup = nonRootID(podSC.RunAsGroup, sc.NonRootGID)
+	}
+	readOnlyRootFilesystem := sc.ReadOnlyRootFilesystem &&
+		!slices.Contains(optOuts, model.PodSecurityReadOnlyRootFilesystem)
+	if !runAsNonRoot && !readOnlyRootFilesystem {
+		return
+	}
+
+	for _, cs := range [][]k8sV1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range cs {
+			c := &cs[i]
+			// The security context may be shared with the user's pod spec.
+			c.SecurityContext = c.SecurityContext.DeepCopy()
+			if c.SecurityContext == nil {
+				c.SecurityContext = &k8sV1.SecurityContext{}
+			}
+			if runAsNonRoot {
+				c.SecurityContext.RunAsNonRoot = ptrs.Ptr(true)
+				// Containers that run as root, like those of tasks whose agent user is root, run
+				// as the non-root user instead.
+				if c.SecurityContext.RunAsUser != nil && *c.SecurityContext.RunAsUser == 0 {
+					c.SecurityContext.RunAsUser = nonRootID(c.SecurityContext.RunAsUser, sc.NonRootUID)
+				}
+				if c.SecurityContext.RunAsGroup != nil && *c.SecurityContext.RunAsGroup == 0 {
+					c.SecurityContext.RunAsGroup = nonRootID(c.SecurityContext.RunAsGroup, sc.NonRootGID)
+				}
+			}
+			if readOnlyRootFilesystem {
+				c.SecurityContext.ReadOnlyRootFilesystem = ptrs.Ptr(true)
+			}
+		}
+	}
+}
+
+// nonRootID returns the non-root ID to run as instead of an unset or root user or group ID, if
+// the pool sets one.
+func nonRootID(id *int64, nonRoot *int) *int64 {
+	if nonRoot != nil && (id == nil || *id == 0) {
+		return ptrs.Ptr(int64(*nonRoot))
+	}
+	return id
+}
@@ -0,0 +1,88 @@
+//nolint:exhaustruct
+package kubernetesrm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8sV1 "k8s.io/api/core/v1"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestConfigurePodSecurity(t *testing.T) {
+	k := &model.KubernetesTaskContainerDefaults{
+		RuntimeClassName: ptrs.Ptr("gvisor"),
+		SecurityContext: &model.KubernetesSecurityContextConfig{
+			RunAsNonRoot:           true,
+			NonRootUID:             ptrs.Ptr(1000),
+			NonRootGID:             ptrs.Ptr(1000),
+			SeccompProfile:         "RuntimeDefault",
+			ReadOnlyRootFilesystem: true,
+		},
+	}
+
+	// Tasks whose agent user is root run as the non-root user instead, and every container, the
+	// user's included, gets the defaults.
+	userSC := &k8sV1.SecurityContext{RunAsUser: ptrs.Ptr(int64(0)), RunAsGroup: ptrs.Ptr(int64(0))}
+	pod := &k8sV1.Pod{Spec: k8sV1.PodSpec{
+		InitContainers: []k8sV1.Container{{Name: "user-init"}, {Name: "determined-init"}},
+		Containers: []k8sV1.Container{
+			{Name: "sidecar"}, {Name: model.DeterminedK8ContainerName, SecurityContext: userSC},
+		},
+	}}
+	configurePodSecurity(k, podSecurityOptOuts(k, pod), pod)
+	require.Equal(t, "gvisor", *pod.Spec.RuntimeClassName)
+	require.Equal(t, &k8sV1.PodSecurityContext{
+		RunAsUser:      ptrs.Ptr(int64(1000)),
+		RunAsGroup:     ptrs.Ptr(int64(1000)),
+		RunAsNonRoot:   ptrs.Ptr(true),
+		SeccompProfile: &k8sV1.SeccompProfile{Type: k8sV1.SeccompProfileTypeRuntimeDefault},
+	}, pod.Spec.SecurityContext)
+	for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers[0]) {
+		require.Equal(t, &k8sV1.SecurityContext{
+			RunAsNonRoot:           ptrs.Ptr(true),
+			ReadOnlyRootFilesystem: ptrs.Ptr(true),
+		}, c.SecurityContext, c.Name)
+	}
+	require.Equal(t, &k8sV1.SecurityContext{
+		RunAsUser:              ptrs.Ptr(int64(1000)),
+		RunAsGroup:             ptrs.Ptr(int64(1000)),
+		RunAsNonRoot:           ptrs.Ptr(true),
+		ReadOnlyRootFilesystem: ptrs.Ptr(true),
+	}, pod.Spec.Containers[1].SecurityContext)
+	require.Equal(t, int64(0), *userSC.RunAsUser, "the user's security context isn't modified")
+
+	// Non-root users are kept.
+	pod = &k8sV1.Pod{Spec: k8sV1.PodSpec{
+		SecurityContext: &k8sV1.PodSecurityContext{RunAsUser: ptrs.Ptr(int64(1002))},
+		Containers: []k8sV1.Container{
+			{SecurityContext: &k8sV1.SecurityContext{RunAsUser: ptrs.Ptr(int64(1001))}},
+		},
+	}}
+	configurePodSecurity(k, nil, pod)
+	require.Equal(t, int64(1002), *pod.Spec.SecurityContext.RunAsUser)
+	require.Equal(t, int64(1001), *pod.Spec.Containers[0].SecurityContext.RunAsUser)
+
+	// A pod spec may opt out of each default, on any of its containers.
+	optOutSC := &k8sV1.SecurityContext{
+		RunAsNonRoot:           ptrs.Ptr(false),
+		ReadOnlyRootFilesystem: ptrs.Ptr(false),
+		SeccompProfile:         &k8sV1.SeccompProfile{Type: k8sV1.SeccompProfileTypeUnconfined},
+	}
+	pod = &k8sV1.Pod{Spec: k8sV1.PodSpec{
+		RuntimeClassName: ptrs.Ptr("runc"),
+		Containers:       []k8sV1.Container{{Name: "sidecar", SecurityContext: optOutSC}, {}},
+	}}
+	configurePodSecurity(k, podSecurityOptOuts(k, pod), pod)
+	require.Equal(t, "runc", *pod.Spec.RuntimeClassName)
+	require.Nil(t, pod.Spec.SecurityContext)
+	require.Equal(t, optOutSC, pod.Spec.Containers[0].SecurityContext)
+	require.Nil(t, pod.Spec.Containers[1].SecurityContext)
+
+	// Nothing is applied without pool defaults.
+	pod = &k8sV1.Pod{Spec: k8sV1.PodSpec{Containers: []k8sV1.Container{{}}}}
+	configurePodSecurity(nil, podSecurityOptOuts(nil, pod), pod)
+	require.Equal(t, &k8sV1.Pod{Spec: k8sV1.PodSpec{Containers: []k8sV1.Container{{}}}}, pod)
+}
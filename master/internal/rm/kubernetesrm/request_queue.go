@@ -27,6 +27,7 @@ type (
 		jobSpec       *batchV1.Job
 		configMapSpec *k8sV1.ConfigMap
 		gw            *gatewayResourceComm
+		networkPolicy *networkPolicyRequest
 	}
 
 	deleteKubernetesResources struct {
@@ -229,11 +230,12 @@ func (r *requestQueue) createKubernetesResources(
 	jobSpec *batchV1.Job,
 	configMapSpec *k8sV1.ConfigMap,
 	gwResources *gatewayResourceComm,
+	networkPolicy *networkPolicyRequest,
 ) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	msg := createKubernetesResources{jobSpec, configMapSpec, gwResources}
+	msg := createKubernetesResources{jobSpec, configMapSpec, gwResources, networkPolicy}
 	ref := keyForCreate(msg)
 
 	if _, requestAlreadyExists := r.pendingResourceCreations[ref]; requestAlreadyExists {
@@ -63,7 +63,7 @@ func (m *mockJob) create() {
 		Name:      m.name,
 		Namespace: "default",
 	}}
-	m.requestQueue.createKubernetesResources(&jobSpec, &cmSpec, nil)
+	m.requestQueue.createKubernetesResources(&jobSpec, &cmSpec, nil, nil)
 }
 
 func (m *mockJob) delete() {
@@ -87,6 +87,26 @@ func (r *requestProcessingWorker) receiveCreateKubernetesResources(
 	}
 	r.syslog.Infof("created configMap %s", configMap.Name)
 
+	if msg.networkPolicy != nil {
+		// The policy is created before the job so its pods are never unrestricted, and is owned by
+		// the configMap so Kubernetes deletes it along with the job's other resources.
+		policySpec := msg.networkPolicy.spec
+		policySpec.OwnerReferences = []metaV1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       configMap.Name,
+			UID:        configMap.UID,
+		}}
+		r.syslog.Debugf("creating network policy %s", policySpec.Name)
+		policy, err := msg.networkPolicy.policies.Create(context.TODO(), policySpec, metaV1.CreateOptions{})
+		if err != nil {
+			r.syslog.WithError(err).Errorf("error creating network policy %s", policySpec.Name)
+			r.failures <- resourceCreationFailed{jobName: msg.jobSpec.Name, err: err}
+			return
+		}
+		r.syslog.Infof("created network policy %s", policy.Name)
+	}
+
 	r.syslog.Debugf("creating job %s", msg.jobSpec.Name)
 	job, err := r.jobInterface[msg.jobSpec.Namespace].Create(
 		context.TODO(), msg.jobSpec, metaV1.CreateOptions{},
@@ -172,6 +172,14 @@ func (j *job) configureEnvVars(
 			},
 		},
 	})
+	envVars = append(envVars, k8sV1.EnvVar{
+		Name: "DET_KUBERNETES_POD_NAME",
+		ValueFrom: &k8sV1.EnvVarSource{
+			FieldRef: &k8sV1.ObjectFieldSelector{
+				FieldPath: "metadata.name",
+			},
+		},
+	})
 	return envVars, nil
 }
 
@@ -321,6 +329,12 @@ func (j *job) configureVolumes(
 	volumeMounts = append(volumeMounts, shmVolumeMount)
 	volumes = append(volumes, shmVolume)
 
+	if taskSpec.Scratch != nil {
+		scratchVolumeMount, scratchVolume := configureScratchVolume(taskSpec.Scratch)
+		volumeMounts = append(volumeMounts, scratchVolumeMount)
+		volumes = append(volumes, scratchVolume)
+	}
+
 	// //nolint:lll // There isn't a great way to break this line that makes it more readable.
 	initContainerVolumeMounts, mainContainerRunArchiveVolumeMounts, runArchiveVolumes := configureAdditionalFilesVolumes(
 		j.configMapName,
@@ -616,6 +630,10 @@ func (j *job) configureJobSpec(
 	addNodeDisabledAffinityToPodSpec(podSpec, clusterIDNodeLabel())
 	addDisallowedNodesToPodSpec(j.req, podSpec)
 
+	// Opt-outs are read from the pod spec as the user specified it, before the Determined
+	// containers replace the one it names.
+	securityOptOuts := podSecurityOptOuts(taskSpec.TaskContainerDefaults.Kubernetes, podSpec)
+
 	nonDeterminedContainers := make([]k8sV1.Container, 0)
 	for idx, container := range podSpec.Spec.Containers {
 		if container.Name != model.DeterminedK8ContainerName {
@@ -653,6 +671,7 @@ func (j *job) configureJobSpec(
 	podSpec.Spec.InitContainers = append(podSpec.Spec.InitContainers, determinedInitContainers)
 	podSpec.Spec.RestartPolicy = k8sV1.RestartPolicyNever
 	podSpec.ObjectMeta.Namespace = j.namespace
+	configurePodSecurity(taskSpec.TaskContainerDefaults.Kubernetes, securityOptOuts, podSpec)
 
 	return &batchV1.Job{
 		ObjectMeta: podSpec.ObjectMeta,
@@ -726,6 +745,13 @@ func (j *job) createSpec(scheduler string, taskSpec *tasks.TaskSpec) (*batchV1.J
 		WorkingDir:   taskSpec.WorkDir,
 		Ports:        containerPorts,
 	}
+	if taskSpec.Scratch != nil && taskSpec.Scratch.Backing() == expconf.ScratchBackingLocal {
+		// Local scratch is on the node's disk, so the pod must be scheduled where there is room
+		// for it, and is evicted rather than fill the disk.
+		size := scratchQuantity(taskSpec.Scratch)
+		container.Resources.Requests[k8sV1.ResourceEphemeralStorage] = size
+		container.Resources.Limits[k8sV1.ResourceEphemeralStorage] = size
+	}
 
 	configMapSpec, err := j.configureConfigMapSpec(taskSpec, runArchives)
 	if err != nil {
@@ -527,3 +527,33 @@ func TestDeterminedLabels(t *testing.T) {
 	require.NotNil(t, spec)
 	require.Equal(t, expectedLabels, spec.ObjectMeta.Labels)
 }
+
+func TestConfigureScratchVolume(t *testing.T) {
+	size := *resource.NewQuantity(10<<30, resource.BinarySI)
+
+	local := &expconf.ScratchConfig{
+		RawSizeGiB:       10,
+		RawBacking:       ptrs.Ptr(expconf.ScratchBackingLocal),
+		RawContainerPath: ptrs.Ptr("/scratch"),
+	}
+	mount, volume := configureScratchVolume(local)
+	require.Equal(t, "/scratch", mount.MountPath)
+	require.Equal(t, volume.Name, mount.Name)
+	require.NotNil(t, volume.EmptyDir)
+	require.Equal(t, size, *volume.EmptyDir.SizeLimit)
+	require.Nil(t, volume.Ephemeral)
+
+	ephemeral := &expconf.ScratchConfig{
+		RawSizeGiB:       10,
+		RawBacking:       ptrs.Ptr(expconf.ScratchBackingEphemeral),
+		RawContainerPath: ptrs.Ptr("/data"),
+		RawStorageClass:  ptrs.Ptr("fast-ssd"),
+	}
+	mount, volume = configureScratchVolume(ephemeral)
+	require.Equal(t, "/data", mount.MountPath)
+	require.Nil(t, volume.EmptyDir)
+	require.NotNil(t, volume.Ephemeral)
+	claim := volume.Ephemeral.VolumeClaimTemplate.Spec
+	require.Equal(t, "fast-ssd", *claim.StorageClassName)
+	require.Equal(t, size, claim.Resources.Requests[k8sV1.ResourceStorage])
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/docker/docker/api/types/mount"
 
 	k8sV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 )
 
 func configureMountPropagation(b *mount.BindOptions) *k8sV1.MountPropagationMode {
@@ -78,6 +80,42 @@ func configureShmVolume(_ int64) (k8sV1.VolumeMount, k8sV1.Volume) {
 	return volumeMount, volume
 }
 
+// configureScratchVolume creates the scratch volume of a pod: an emptyDir capped at its size for
+// local scratch, or a volume provisioned from its storage class that is deleted with the pod for
+// ephemeral scratch.
+func configureScratchVolume(scratch *expconf.ScratchConfig) (k8sV1.VolumeMount, k8sV1.Volume) {
+	volumeName := "det-scratch-volume"
+	size := scratchQuantity(scratch)
+	volumeMount := k8sV1.VolumeMount{
+		Name:      volumeName,
+		ReadOnly:  false,
+		MountPath: scratch.ContainerPath(),
+	}
+	volume := k8sV1.Volume{Name: volumeName}
+	if scratch.Backing() == expconf.ScratchBackingEphemeral {
+		volume.VolumeSource = k8sV1.VolumeSource{Ephemeral: &k8sV1.EphemeralVolumeSource{
+			VolumeClaimTemplate: &k8sV1.PersistentVolumeClaimTemplate{
+				Spec: k8sV1.PersistentVolumeClaimSpec{
+					AccessModes:      []k8sV1.PersistentVolumeAccessMode{k8sV1.ReadWriteOnce},
+					StorageClassName: scratch.StorageClass(),
+					Resources: k8sV1.ResourceRequirements{
+						Requests: k8sV1.ResourceList{k8sV1.ResourceStorage: size},
+					},
+				},
+			},
+		}}
+	} else {
+		volume.VolumeSource = k8sV1.VolumeSource{EmptyDir: &k8sV1.EmptyDirVolumeSource{
+			SizeLimit: &size,
+		}}
+	}
+	return volumeMount, volume
+}
+
+func scratchQuantity(scratch *expconf.ScratchConfig) resource.Quantity {
+	return *resource.NewQuantity(int64(scratch.SizeGiB())<<30, resource.BinarySI)
+}
+
 func configureAdditionalFilesVolumes(
 	configMapName string,
 	runArchives []cproto.RunArchive,
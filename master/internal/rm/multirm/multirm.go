@@ -7,6 +7,7 @@ import (
 	"golang.org/x/exp/maps"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -131,6 +132,52 @@ func (m *MultiRMRouter) SetGroupPriority(req sproto.SetGroupPriority) error {
 	return m.rms[resolvedRMName].SetGroupPriority(req)
 }
 
+// SetPriorityInJob routes a SetPriorityInJob request to a specified resource manager/pool.
+func (m *MultiRMRouter) SetPriorityInJob(req sproto.SetPriorityInJob) error {
+	resolvedRMName, err := m.getRMName(rm.ResourcePoolName(req.ResourcePool))
+	if err != nil {
+		return err
+	}
+
+	return m.rms[resolvedRMName].SetPriorityInJob(req)
+}
+
+// GetWorkloadClasses routes a GetWorkloadClasses request to a specified resource manager/pool.
+func (m *MultiRMRouter) GetWorkloadClasses(
+	rpName rm.ResourcePoolName,
+) (*sproto.WorkloadClassesSummary, error) {
+	resolvedRMName, err := m.getRMName(rpName)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.rms[resolvedRMName].GetWorkloadClasses(rpName)
+}
+
+// SetWorkloadClasses routes a SetWorkloadClasses request to a specified resource manager/pool.
+func (m *MultiRMRouter) SetWorkloadClasses(
+	rpName rm.ResourcePoolName, cfg *config.WorkloadClassesConfig,
+) error {
+	resolvedRMName, err := m.getRMName(rpName)
+	if err != nil {
+		return err
+	}
+
+	return m.rms[resolvedRMName].SetWorkloadClasses(rpName, cfg)
+}
+
+// SetReservations routes a SetReservations request to a specified resource manager/pool.
+func (m *MultiRMRouter) SetReservations(
+	rpName rm.ResourcePoolName, reservations []model.ResourcePoolReservation,
+) error {
+	resolvedRMName, err := m.getRMName(rpName)
+	if err != nil {
+		return err
+	}
+
+	return m.rms[resolvedRMName].SetReservations(rpName, reservations)
+}
+
 // IsReattachableOnlyAfterStarted routes a IsReattachableOnlyAfterStarted call to a specified resource manager/pool.
 func (m *MultiRMRouter) IsReattachableOnlyAfterStarted() bool {
 	resolvedRMName, err := m.getRMName("")
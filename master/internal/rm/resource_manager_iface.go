@@ -3,6 +3,7 @@ package rm
 import (
 	"google.golang.org/protobuf/proto"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/command"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -24,8 +25,12 @@ type ResourceManager interface {
 	SetGroupMaxSlots(sproto.SetGroupMaxSlots)
 	SetGroupWeight(sproto.SetGroupWeight) error
 	SetGroupPriority(sproto.SetGroupPriority) error
+	SetPriorityInJob(sproto.SetPriorityInJob) error
 	IsReattachableOnlyAfterStarted() bool
 	SmallerValueIsHigherPriority() (bool, error)
+	GetWorkloadClasses(ResourcePoolName) (*sproto.WorkloadClassesSummary, error)
+	SetWorkloadClasses(ResourcePoolName, *config.WorkloadClassesConfig) error
+	SetReservations(ResourcePoolName, []model.ResourcePoolReservation) error
 
 	// Resource pool stuff.
 	GetResourcePools() (*apiv1.GetResourcePoolsResponse, error)
@@ -186,7 +186,11 @@ func SortTasksWithPosition(
 // 1 if a is in front of b.
 // 0 if a is equal to b in position.
 // -1 if a is behind b.
+// Allocations of the same job are ordered by their priority within the job first.
 func comparePositions(a, b *sproto.AllocateRequest, jobPositions JobSortState) int {
+	if c := ComparePriorityInJob(a, b); c != 0 {
+		return c
+	}
 	aPosition, aOk := jobPositions[a.JobID]
 	bPosition, bOk := jobPositions[b.JobID]
 	zero := decimal.NewFromInt(0)
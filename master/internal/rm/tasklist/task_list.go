@@ -120,6 +120,23 @@ func (l *TaskList) ForResourcePool(name string) *TaskList {
 	return newTaskList
 }
 
+// Filter returns a new TaskList with the tasks, and their allocations, that satisfy keep.
+func (l *TaskList) Filter(keep func(*sproto.AllocateRequest) bool) *TaskList {
+	newTaskList := New()
+	for it := l.Iterator(); it.Next(); {
+		task := it.Value()
+		if !keep(task) {
+			continue
+		}
+
+		newTaskList.AddTask(task)
+		if assigned, ok := l.allocations[task.AllocationID]; ok {
+			newTaskList.allocations[task.AllocationID] = assigned
+		}
+	}
+	return newTaskList
+}
+
 // TaskSummary returns a summary for an allocation in the TaskList.
 func (l *TaskList) TaskSummary(
 	id model.AllocationID,
@@ -206,6 +223,20 @@ func allocationRequestComparator(a *sproto.AllocateRequest, b *sproto.AllocateRe
 	return 1
 }
 
+// ComparePriorityInJob compares allocations of the same job by their priority within the job. It
+// returns 1 if a goes in front of b, -1 if b goes in front of a, and 0 if they are of different jobs
+// or have the same priority, in which case they keep their order.
+func ComparePriorityInJob(a *sproto.AllocateRequest, b *sproto.AllocateRequest) int {
+	switch {
+	case a.JobID != b.JobID || a.PriorityInJob == b.PriorityInJob:
+		return 0
+	case a.PriorityInJob > b.PriorityInJob:
+		return 1
+	default:
+		return -1
+	}
+}
+
 // registerTimeComparator compares AllocateRequests based on when their Allocate actor was
 // registred.
 func registerTimeComparator(t1 *sproto.AllocateRequest, t2 *sproto.AllocateRequest) int {
@@ -83,3 +83,44 @@ func TestAllocationRequestComparator(t *testing.T) {
 		})
 	}
 }
+
+func TestComparePriorityInJob(t *testing.T) {
+	tests := []struct {
+		name string
+		a    *sproto.AllocateRequest
+		b    *sproto.AllocateRequest
+		want int
+	}{
+		{
+			name: "higher priority first",
+			a:    &sproto.AllocateRequest{JobID: "job1", PriorityInJob: 2},
+			b:    &sproto.AllocateRequest{JobID: "job1", PriorityInJob: 1},
+			want: 1,
+		},
+		{
+			name: "lower priority last",
+			a:    &sproto.AllocateRequest{JobID: "job1"},
+			b:    &sproto.AllocateRequest{JobID: "job1", PriorityInJob: 1},
+			want: -1,
+		},
+		{
+			name: "equal priorities keep their order",
+			a:    &sproto.AllocateRequest{JobID: "job1", PriorityInJob: 1},
+			b:    &sproto.AllocateRequest{JobID: "job1", PriorityInJob: 1},
+			want: 0,
+		},
+		{
+			name: "priorities of different jobs are not compared",
+			a:    &sproto.AllocateRequest{JobID: "job1", PriorityInJob: 2},
+			b:    &sproto.AllocateRequest{JobID: "job2"},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComparePriorityInJob(tt.a, tt.b); got != tt.want {
+				t.Errorf("ComparePriorityInJob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
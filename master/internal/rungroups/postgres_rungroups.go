@@ -0,0 +1,202 @@
+package rungroups
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/trialcompare"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// visibleMembers returns a query of the group_id, experiment_id, name, state, project_id and
+// workspace_id of the members of all run groups that pass the filter.
+func visibleMembers(filter ExperimentFilter) (*bun.SelectQuery, error) {
+	members := db.Bun().NewSelect().
+		TableExpr("run_group_experiments AS rge").
+		Join("JOIN experiments AS e ON e.id = rge.experiment_id").
+		Join("JOIN projects AS p ON p.id = e.project_id").
+		ColumnExpr("rge.group_id, e.id AS experiment_id, e.config->>'name' AS name, e.state").
+		ColumnExpr("e.project_id, p.workspace_id")
+	return filter(db.Bun().NewSelect().TableExpr("(?) AS m", members).ColumnExpr("m.*"))
+}
+
+// groups returns a query of the run groups a user owns or has a visible member in, with how many
+// members are visible.
+func groups(userID model.UserID, filter ExperimentFilter) (*bun.SelectQuery, error) {
+	members, err := visibleMembers(filter)
+	if err != nil {
+		return nil, err
+	}
+	return db.Bun().NewSelect().
+		TableExpr("run_groups AS g").
+		Join("LEFT JOIN (?) AS vm ON vm.group_id = g.id", members).
+		ColumnExpr("g.id, g.name, g.description, g.owner_id, g.created_at").
+		ColumnExpr("COUNT(vm.experiment_id) AS num_experiments").
+		Group("g.id").
+		Having("g.owner_id = ? OR COUNT(vm.experiment_id) > 0", userID), nil
+}
+
+// Groups returns the run groups a user owns or can see a member of, ordered by ID.
+func Groups(ctx context.Context, userID model.UserID, filter ExperimentFilter) ([]Group, error) {
+	q, err := groups(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	res := []Group{}
+	if err := q.Order("g.id").Scan(ctx, &res); err != nil {
+		return nil, fmt.Errorf("getting run groups of user %d: %w", userID, err)
+	}
+	return res, nil
+}
+
+// GroupByID returns a run group if the user owns it or can see a member of it, or
+// db.ErrNotFound.
+func GroupByID(
+	ctx context.Context, id int, userID model.UserID, filter ExperimentFilter,
+) (*Group, error) {
+	q, err := groups(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	var g Group
+	if err := q.Where("g.id = ?", id).Scan(ctx, &g); err != nil {
+		return nil, fmt.Errorf("getting run group %d: %w", id, db.MatchSentinelError(err))
+	}
+	return &g, nil
+}
+
+// Create creates a run group with the given experiments and sets its ID and creation time.
+func Create(ctx context.Context, g *model.RunGroup, experimentIDs []int) error {
+	return db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(g).Returning("id, created_at").Exec(ctx); err != nil {
+			return fmt.Errorf("creating run group %q: %w", g.Name, err)
+		}
+		return addExperiments(ctx, tx, g.ID, experimentIDs)
+	})
+}
+
+// Update updates the name and description of a run group.
+func Update(ctx context.Context, g *model.RunGroup) error {
+	res, err := db.Bun().NewUpdate().
+		Model(g).
+		Column("name", "description").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("updating run group %d: %w", g.ID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("updating run group %d: %w", g.ID, err)
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// Delete deletes a run group. Its experiments are left as they are.
+func Delete(ctx context.Context, id int) error {
+	if _, err := db.Bun().NewDelete().
+		Model((*model.RunGroup)(nil)).
+		Where("id = ?", id).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("deleting run group %d: %w", id, err)
+	}
+	return nil
+}
+
+// AddExperiments adds experiments to a run group. Experiments already in the group stay as they
+// are.
+func AddExperiments(ctx context.Context, groupID int, experimentIDs []int) error {
+	return addExperiments(ctx, db.Bun(), groupID, experimentIDs)
+}
+
+func addExperiments(ctx context.Context, idb bun.IDB, groupID int, experimentIDs []int) error {
+	if len(experimentIDs) == 0 {
+		return nil
+	}
+	members := make([]model.RunGroupExperiment, 0, len(experimentIDs))
+	for _, id := range experimentIDs {
+		members = append(members, model.RunGroupExperiment{GroupID: groupID, ExperimentID: id})
+	}
+	if _, err := idb.NewInsert().
+		Model(&members).
+		On("CONFLICT (group_id, experiment_id) DO NOTHING").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("adding experiments to run group %d: %w", groupID, err)
+	}
+	return nil
+}
+
+// RemoveExperiment removes an experiment from a run group, or returns db.ErrNotFound if it isn't
+// in the group.
+func RemoveExperiment(ctx context.Context, groupID, experimentID int) error {
+	res, err := db.Bun().NewDelete().
+		Model((*model.RunGroupExperiment)(nil)).
+		Where("group_id = ? AND experiment_id = ?", groupID, experimentID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("removing experiment %d from run group %d: %w", experimentID, groupID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("removing experiment %d from run group %d: %w", experimentID, groupID, err)
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// ExperimentIDs returns the IDs of all experiments of a run group, visible or not.
+func ExperimentIDs(ctx context.Context, groupID int) ([]int, error) {
+	ids := []int{}
+	if err := db.Bun().NewSelect().
+		Model((*model.RunGroupExperiment)(nil)).
+		Column("experiment_id").
+		Where("group_id = ?", groupID).
+		Order("experiment_id").
+		Scan(ctx, &ids); err != nil {
+		return nil, fmt.Errorf("getting experiments of run group %d: %w", groupID, err)
+	}
+	return ids, nil
+}
+
+// Members returns the experiments of a run group that pass the filter, ordered by ID.
+func Members(ctx context.Context, groupID int, filter ExperimentFilter) ([]Member, error) {
+	q, err := visibleMembers(filter)
+	if err != nil {
+		return nil, err
+	}
+	members := []Member{}
+	if err := q.Where("m.group_id = ?", groupID).Order("m.experiment_id").
+		Scan(ctx, &members); err != nil {
+		return nil, fmt.Errorf("getting members of run group %d: %w", groupID, err)
+	}
+	return members, nil
+}
+
+// FinalValues returns the value of a validation metric in the latest validation of each trial of
+// the experiments, ordered by trial ID.
+func FinalValues(
+	ctx context.Context, experimentIDs []int, metricName string,
+) ([]trialcompare.TrialValue, error) {
+	values := []trialcompare.TrialValue{}
+	if len(experimentIDs) == 0 {
+		return values, nil
+	}
+	if err := db.Bun().NewSelect().
+		TableExpr("trials AS t").
+		ColumnExpr("t.id AS trial_id").
+		ColumnExpr("t.experiment_id").
+		ColumnExpr(`CASE WHEN jsonb_typeof(v.metrics->'validation_metrics'->?) = 'number'
+			THEN (v.metrics->'validation_metrics'->>?)::float8 END AS value`,
+			metricName, metricName).
+		Join("LEFT JOIN validations AS v ON v.id = t.latest_validation_id").
+		Where("t.experiment_id IN (?)", bun.In(experimentIDs)).
+		Order("t.id").
+		Scan(ctx, &values); err != nil {
+		return nil, fmt.Errorf("getting final %s of trials: %w", metricName, err)
+	}
+	return values, nil
+}
@@ -0,0 +1,118 @@
+//go:build integration
+// +build integration
+
+package rungroups
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func allExperiments(q *bun.SelectQuery) (*bun.SelectQuery, error) {
+	return q, nil
+}
+
+func experimentIDs(members []Member) []int {
+	ids := []int{}
+	for _, m := range members {
+		ids = append(ids, m.ExperimentID)
+	}
+	return ids
+}
+
+func TestRunGroups(t *testing.T) {
+	ctx := context.Background()
+	owner := db.RequireMockUser(t, db.SingleDB())
+	other := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	var expIDs []int
+	for i := 0; i < 3; i++ {
+		exp := db.RequireMockExperimentParams(t, db.SingleDB(), owner, db.MockExperimentParams{},
+			projectID)
+		expIDs = append(expIDs, exp.ID)
+	}
+	otherWorkspaces := func(q *bun.SelectQuery) (*bun.SelectQuery, error) {
+		return q.Where("workspace_id != ?", workspaceID), nil
+	}
+
+	g := &model.RunGroup{Name: "sweep", OwnerID: owner.ID}
+	require.NoError(t, Create(ctx, g, expIDs[:2]))
+	require.NotZero(t, g.ID)
+
+	got, err := GroupByID(ctx, g.ID, other.ID, allExperiments)
+	require.NoError(t, err)
+	require.Equal(t, "sweep", got.Name)
+	require.Equal(t, 2, got.NumExperiments)
+
+	// Groups are hidden from users who can't see any of their experiments, but not their owners.
+	_, err = GroupByID(ctx, g.ID, other.ID, otherWorkspaces)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	got, err = GroupByID(ctx, g.ID, owner.ID, otherWorkspaces)
+	require.NoError(t, err)
+	require.Zero(t, got.NumExperiments)
+	groups, err := Groups(ctx, other.ID, otherWorkspaces)
+	require.NoError(t, err)
+	for _, group := range groups {
+		require.NotEqual(t, g.ID, group.ID)
+	}
+
+	require.NoError(t, AddExperiments(ctx, g.ID, expIDs[1:]))
+	members, err := Members(ctx, g.ID, allExperiments)
+	require.NoError(t, err)
+	require.Equal(t, expIDs, experimentIDs(members))
+	require.Equal(t, workspaceID, members[0].WorkspaceID)
+	members, err = Members(ctx, g.ID, otherWorkspaces)
+	require.NoError(t, err)
+	require.Empty(t, members)
+
+	require.NoError(t, RemoveExperiment(ctx, g.ID, expIDs[0]))
+	require.ErrorIs(t, RemoveExperiment(ctx, g.ID, expIDs[0]), db.ErrNotFound)
+	ids, err := ExperimentIDs(ctx, g.ID)
+	require.NoError(t, err)
+	require.Equal(t, expIDs[1:], ids)
+
+	g.Name, g.Description = "renamed", "one sweep per dataset"
+	require.NoError(t, Update(ctx, g))
+	got, err = GroupByID(ctx, g.ID, owner.ID, allExperiments)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", got.Name)
+	require.Equal(t, "one sweep per dataset", got.Description)
+
+	values, err := FinalValues(ctx, ids, "loss")
+	require.NoError(t, err)
+	require.Empty(t, values)
+
+	require.NoError(t, Delete(ctx, g.ID))
+	_, err = GroupByID(ctx, g.ID, owner.ID, allExperiments)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	require.ErrorIs(t, Update(ctx, g), db.ErrNotFound)
+}
@@ -0,0 +1,205 @@
+// Package rungroups stores run groups, which gather experiments that belong together, like one
+// sweep per dataset, across projects and workspaces, and summarizes the metrics of their trials.
+package rungroups
+
+import (
+	"math"
+	"sort"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/trialcompare"
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// ExperimentFilter narrows a query of experiments, which exposes their "workspace_id" column, to
+// those a user may see.
+type ExperimentFilter func(q *bun.SelectQuery) (*bun.SelectQuery, error)
+
+// Group is a run group and how many of its experiments the user listing it can see.
+type Group struct {
+	model.RunGroup `bun:",extend"`
+	NumExperiments int `bun:"num_experiments" json:"num_experiments"`
+}
+
+// Proto converts the group to its protobuf representation.
+func (g Group) Proto() *experimentv1.RunGroup {
+	return &experimentv1.RunGroup{
+		Id:             int32(g.ID),
+		Name:           g.Name,
+		Description:    g.Description,
+		OwnerId:        int32(g.OwnerID),
+		CreatedAt:      timestamppb.New(g.CreatedAt),
+		NumExperiments: int32(g.NumExperiments),
+	}
+}
+
+// Member is an experiment of a run group.
+type Member struct {
+	ExperimentID int         `bun:"experiment_id" json:"experiment_id"`
+	Name         string      `bun:"name" json:"name"`
+	State        model.State `bun:"state" json:"state"`
+	ProjectID    int         `bun:"project_id" json:"project_id"`
+	WorkspaceID  int         `bun:"workspace_id" json:"workspace_id"`
+}
+
+// Proto converts the member to its protobuf representation.
+func (m Member) Proto() *experimentv1.RunGroupExperiment {
+	return &experimentv1.RunGroupExperiment{
+		ExperimentId: int32(m.ExperimentID),
+		Name:         m.Name,
+		State:        model.StateToProto(m.State),
+		ProjectId:    int32(m.ProjectID),
+		WorkspaceId:  int32(m.WorkspaceID),
+	}
+}
+
+// BestTrial is the trial with the best value of a metric.
+type BestTrial struct {
+	ExperimentID int     `json:"experiment_id"`
+	TrialID      int     `json:"trial_id"`
+	Value        float64 `json:"value"`
+}
+
+// ExperimentBest is the best trial of an experiment of a run group.
+type ExperimentBest struct {
+	ExperimentID int `json:"experiment_id"`
+	// NumTrials is how many trials of the experiment reported the metric.
+	NumTrials int `json:"num_trials"`
+	// Best is unset if no trial of the experiment reported the metric.
+	Best *BestTrial `json:"best"`
+}
+
+// Distribution is the distribution of the final values of a metric across trials.
+type Distribution struct {
+	Count  int      `json:"count"`
+	Mean   *float64 `json:"mean"`
+	StdDev *float64 `json:"std_dev"`
+	Min    *float64 `json:"min"`
+	P25    *float64 `json:"p25"`
+	Median *float64 `json:"median"`
+	P75    *float64 `json:"p75"`
+	Max    *float64 `json:"max"`
+}
+
+// Summary summarizes the final value of a validation metric across the trials of a run group.
+type Summary struct {
+	MetricName      string `json:"metric_name"`
+	SmallerIsBetter bool   `json:"smaller_is_better"`
+	// Best is the best trial of the whole group, if any trial reported the metric.
+	Best         *BestTrial       `json:"best"`
+	Experiments  []ExperimentBest `json:"experiments"`
+	Distribution Distribution     `json:"distribution"`
+}
+
+// Proto converts the best trials and distribution of the summary to their protobuf
+// representation.
+func (s Summary) Proto() *apiv1.GetRunGroupSummaryResponse {
+	bestProto := func(b *BestTrial) *experimentv1.RunGroupBestTrial {
+		if b == nil {
+			return nil
+		}
+		return &experimentv1.RunGroupBestTrial{
+			ExperimentId: int32(b.ExperimentID),
+			TrialId:      int32(b.TrialID),
+			Value:        b.Value,
+		}
+	}
+	resp := &apiv1.GetRunGroupSummaryResponse{
+		MetricName:      s.MetricName,
+		SmallerIsBetter: s.SmallerIsBetter,
+		Best:            bestProto(s.Best),
+		Experiments:     make([]*experimentv1.RunGroupExperimentBest, 0, len(s.Experiments)),
+		Distribution: &experimentv1.RunGroupDistribution{
+			Count:  int32(s.Distribution.Count),
+			Mean:   s.Distribution.Mean,
+			StdDev: s.Distribution.StdDev,
+			Min:    s.Distribution.Min,
+			P25:    s.Distribution.P25,
+			Median: s.Distribution.Median,
+			P75:    s.Distribution.P75,
+			Max:    s.Distribution.Max,
+		},
+	}
+	for _, e := range s.Experiments {
+		resp.Experiments = append(resp.Experiments, &experimentv1.RunGroupExperimentBest{
+			ExperimentId: int32(e.ExperimentID),
+			NumTrials:    int32(e.NumTrials),
+			Best:         bestProto(e.Best),
+		})
+	}
+	return resp
+}
+
+// Summarize summarizes the final values of a metric of the trials of the experiments of a run
+// group. Trials that didn't report the metric are left out.
+func Summarize(
+	metricName string, smallerIsBetter bool, experimentIDs []int, trials []trialcompare.TrialValue,
+) Summary {
+	s := Summary{
+		MetricName:      metricName,
+		SmallerIsBetter: smallerIsBetter,
+		Experiments:     make([]ExperimentBest, 0, len(experimentIDs)),
+	}
+	better := func(a, b float64) bool {
+		if smallerIsBetter {
+			return a < b
+		}
+		return a > b
+	}
+
+	bests := make(map[int]*ExperimentBest, len(experimentIDs))
+	for _, id := range experimentIDs {
+		s.Experiments = append(s.Experiments, ExperimentBest{ExperimentID: id})
+	}
+	for i := range s.Experiments {
+		bests[s.Experiments[i].ExperimentID] = &s.Experiments[i]
+	}
+
+	var values []float64
+	for _, t := range trials {
+		if t.Value == nil || math.IsNaN(*t.Value) || math.IsInf(*t.Value, 0) {
+			continue
+		}
+		values = append(values, *t.Value)
+		trial := BestTrial{ExperimentID: t.ExperimentID, TrialID: t.TrialID, Value: *t.Value}
+		if b, ok := bests[t.ExperimentID]; ok {
+			b.NumTrials++
+			if b.Best == nil || better(trial.Value, b.Best.Value) {
+				b.Best = &trial
+			}
+		}
+		if s.Best == nil || better(trial.Value, s.Best.Value) {
+			s.Best = &trial
+		}
+	}
+	s.Distribution = distribution(values)
+	return s
+}
+
+func distribution(values []float64) Distribution {
+	d := Distribution{Count: len(values)}
+	if len(values) == 0 {
+		return d
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mean := mathx.Mean(sorted)
+	d.Mean = &mean
+	if len(sorted) > 1 {
+		sd := math.Sqrt(mathx.Variance(sorted, mean))
+		d.StdDev = &sd
+	}
+	quantile := func(q float64) *float64 {
+		v := mathx.Quantile(sorted, q)
+		return &v
+	}
+	d.Min, d.P25, d.Median, d.P75, d.Max = quantile(0), quantile(0.25), quantile(0.5),
+		quantile(0.75), quantile(1)
+	return d
+}
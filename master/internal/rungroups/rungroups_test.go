@@ -0,0 +1,75 @@
+package rungroups
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/trialcompare"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestSummarize(t *testing.T) {
+	trials := []trialcompare.TrialValue{
+		{TrialID: 1, ExperimentID: 10, Value: ptrs.Ptr(0.4)},
+		{TrialID: 2, ExperimentID: 10, Value: ptrs.Ptr(0.2)},
+		{TrialID: 3, ExperimentID: 10},
+		{TrialID: 4, ExperimentID: 11, Value: ptrs.Ptr(0.3)},
+		{TrialID: 5, ExperimentID: 11, Value: ptrs.Ptr(0.1)},
+		{TrialID: 6, ExperimentID: 11, Value: ptrs.Ptr(math.NaN())},
+	}
+
+	s := Summarize("loss", true, []int{10, 11, 12}, trials)
+	require.Equal(t, "loss", s.MetricName)
+	require.Equal(t, &BestTrial{ExperimentID: 11, TrialID: 5, Value: 0.1}, s.Best)
+	require.Len(t, s.Experiments, 3)
+	require.Equal(t, 2, s.Experiments[0].NumTrials)
+	require.Equal(t, 2, s.Experiments[0].Best.TrialID)
+	require.Equal(t, 5, s.Experiments[1].Best.TrialID)
+	require.Equal(t, ExperimentBest{ExperimentID: 12}, s.Experiments[2])
+
+	d := s.Distribution
+	require.Equal(t, 4, d.Count)
+	require.InDelta(t, 0.25, *d.Mean, 1e-12)
+	require.InDelta(t, math.Sqrt(0.05/3), *d.StdDev, 1e-12)
+	require.Equal(t, 0.1, *d.Min)
+	require.InDelta(t, 0.175, *d.P25, 1e-12)
+	require.InDelta(t, 0.25, *d.Median, 1e-12)
+	require.InDelta(t, 0.325, *d.P75, 1e-12)
+	require.Equal(t, 0.4, *d.Max)
+
+	s = Summarize("accuracy", false, []int{10, 11}, trials)
+	require.Equal(t, 1, s.Best.TrialID)
+	require.Equal(t, 4, s.Experiments[1].Best.TrialID)
+
+	s = Summarize("accuracy", false, []int{10}, trials[2:3])
+	require.Nil(t, s.Best)
+	require.Nil(t, s.Experiments[0].Best)
+	require.Equal(t, Distribution{}, s.Distribution)
+
+	// A single value has no spread.
+	s = Summarize("accuracy", false, []int{10}, trials[:1])
+	require.Nil(t, s.Distribution.StdDev)
+	require.Equal(t, 0.4, *s.Distribution.P25)
+}
+
+func TestSummaryProto(t *testing.T) {
+	trials := []trialcompare.TrialValue{
+		{TrialID: 1, ExperimentID: 10, Value: ptrs.Ptr(0.4)},
+		{TrialID: 2, ExperimentID: 10, Value: ptrs.Ptr(0.2)},
+	}
+	pb := Summarize("loss", true, []int{10, 11}, trials).Proto()
+	require.Equal(t, "loss", pb.MetricName)
+	require.True(t, pb.SmallerIsBetter)
+	require.Equal(t, int32(2), pb.Best.TrialId)
+	require.Len(t, pb.Experiments, 2)
+	require.Equal(t, int32(2), pb.Experiments[0].NumTrials)
+	require.Nil(t, pb.Experiments[1].Best)
+	require.Equal(t, int32(2), pb.Distribution.Count)
+	require.InDelta(t, 0.3, pb.Distribution.GetMedian(), 1e-12)
+
+	pb = Summarize("loss", true, []int{10}, nil).Proto()
+	require.Nil(t, pb.Best)
+	require.Nil(t, pb.Distribution.Median)
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -12,8 +13,10 @@ import (
 	k8sV1 "k8s.io/api/core/v1"
 
 	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/configpolicy"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/raycluster"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -141,6 +144,7 @@ func getGenericTaskOnAllocationExit(
 	logCtx logger.Context,
 ) func(ae *task.AllocationExited) {
 	return func(ae *task.AllocationExited) {
+		raycluster.Unregister(taskID)
 		syslog := logrus.WithField("component", "genericTask").WithFields(logCtx.Fields())
 		if ae.Err != nil {
 			err := db.SetErrorState(taskID, time.Now().UTC())
@@ -171,3 +175,73 @@ func getGenericTaskOnAllocationExit(
 		}
 	}
 }
+
+// configureGenericTaskAllocation sets up the allocation of a generic task: who it runs for, and
+// whether it runs a Spark job or a Ray cluster. A Ray cluster's dashboard is proxied, and the
+// cluster is killed once it's idle for too long.
+func configureGenericTaskAllocation(req *sproto.AllocateRequest, spec *tasks.GenericTaskSpec) {
+	req.WorkspaceID = spec.WorkspaceID
+	req.OwnerID = spec.Base.OwnerID()
+	if spec.GenericTaskConfig.Spark != nil {
+		req.Name = fmt.Sprintf("Spark Job %s", req.TaskID)
+	}
+	ray := spec.GenericTaskConfig.Ray
+	if ray == nil {
+		return
+	}
+	req.Name = fmt.Sprintf("Ray Cluster %s", req.TaskID)
+	req.ProxyPorts = sproto.NewProxyPortConfig(spec.ProxyPorts(), req.TaskID)
+	if ray.IdleTimeout != nil {
+		req.IdleTimeout = &sproto.IdleTimeoutConfig{
+			ServiceID:       string(req.TaskID),
+			UseProxyState:   true,
+			UseRunnerState:  true,
+			TimeoutDuration: time.Duration(*ray.IdleTimeout),
+			Debug:           spec.GenericTaskConfig.Debug,
+		}
+	}
+}
+
+// registerRayCluster starts tracking a generic task if it runs a Ray cluster. It's called before
+// the task's allocation starts, since the allocation unregisters the cluster when it exits, and
+// callers unregister it if the allocation fails to start.
+func registerRayCluster(taskID model.TaskID, spec *tasks.GenericTaskSpec) {
+	ray := spec.GenericTaskConfig.Ray
+	if ray == nil {
+		return
+	}
+	raycluster.Register(raycluster.Cluster{
+		TaskID:        taskID,
+		WorkspaceID:   spec.WorkspaceID,
+		DashboardPort: ray.DashboardPort,
+		StartTime:     time.Now().UTC(),
+	})
+}
+
+// checkCanOptOutOfPodSecurity returns an error if a task's pod spec opts out of its resource
+// pool's pod security defaults and the user doesn't have permission to in the workspace.
+func (a *apiServer) checkCanOptOutOfPodSecurity(
+	ctx context.Context,
+	curUser model.User,
+	workspaceID int32,
+	taskContainerDefaults model.TaskContainerDefaultsConfig,
+	pod *k8sV1.Pod,
+) error {
+	if taskContainerDefaults.Kubernetes == nil {
+		return nil
+	}
+	optOuts := taskContainerDefaults.Kubernetes.PodSecurityOptOuts(pod)
+	if len(optOuts) == 0 {
+		return nil
+	}
+	w, err := a.GetWorkspaceByID(ctx, workspaceID, curUser, false)
+	if err != nil {
+		return err
+	}
+	if err := configpolicy.AuthZProvider.Get().CanOptOutOfPodSecurity(ctx, curUser, w); err != nil {
+		return status.Errorf(codes.PermissionDenied,
+			"opting out of the resource pool's pod security defaults (%s): %s",
+			strings.Join(optOuts, ", "), err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package sproto
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrExecUnsupported is returned when the resources of an allocation can't run exec processes,
+// such as those of resource managers that don't support it or agents that are too old.
+var ErrExecUnsupported = errors.New("interactive exec is not supported by the task's resources")
+
+// ExecRequest is a request to start a process in the container of running resources.
+type ExecRequest struct {
+	Cmd  []string
+	TTY  bool
+	Rows uint
+	Cols uint
+}
+
+// ExecInput is input to an exec process. Exactly one of its fields is set.
+type ExecInput struct {
+	Data       []byte
+	Resize     *ExecSize
+	CloseInput bool
+}
+
+// ExecSize is the size of the TTY of an exec process.
+type ExecSize struct {
+	Rows uint
+	Cols uint
+}
+
+// ExecOutput is output of an exec process. The last output of a process has its exit code or
+// the error that ended it.
+type ExecOutput struct {
+	Data     []byte
+	ExitCode *int
+	Err      error
+}
+
+// ExecResources is implemented by resources that can start processes in their containers.
+type ExecResources interface {
+	Exec(ctx context.Context, req ExecRequest) (*ExecSession, error)
+}
+
+// ExecSession is a process started in the container of some resources. Its output is closed after
+// the last output of the process, or when the session is closed.
+type ExecSession struct {
+	Output <-chan ExecOutput
+
+	send      func(ExecInput) error
+	detach    func()
+	closeOnce sync.Once
+}
+
+// NewExecSession returns a session that relays input with send and detaches from the process with
+// detach, which must close output.
+func NewExecSession(
+	output <-chan ExecOutput, send func(ExecInput) error, detach func(),
+) *ExecSession {
+	return &ExecSession{Output: output, send: send, detach: detach}
+}
+
+// Send sends input to the process.
+func (s *ExecSession) Send(in ExecInput) error {
+	return s.send(in)
+}
+
+// Close detaches from the process. Processes reading from a TTY exit when it is closed.
+func (s *ExecSession) Close() {
+	s.closeOnce.Do(s.detach)
+}
@@ -32,6 +32,9 @@ type RMJobInfo struct { // rename ?
 	State          SchedulingState
 	RequestedSlots int
 	AllocatedSlots int
+	// EffectivePriority is the priority a queued job is scheduled at once it has been raised by
+	// priority aging, if the resource pool ages priorities.
+	EffectivePriority *int
 }
 
 // DeleteJob instructs the RM to clean up all metadata associated with a job external to
@@ -70,6 +73,12 @@ type (
 		ResourcePool string
 		JobID        model.JobID
 	}
+	// SetPriorityInJob sets the priority of an allocation among the allocations of its job.
+	SetPriorityInJob struct {
+		PriorityInJob int
+		ResourcePool  string
+		AllocationID  model.AllocationID
+	}
 )
 
 // RecoverJobPosition gets sent from the experiment or command actor to the resource pool.
@@ -1,6 +1,19 @@
 package sproto
 
-import "github.com/determined-ai/determined/master/pkg/model"
+import (
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// WorkloadClass is a class of workloads that share the slots of a resource pool.
+type WorkloadClass string
+
+const (
+	// WorkloadClassExperiment is the class of the trials of experiments.
+	WorkloadClassExperiment WorkloadClass = "experiment"
+	// WorkloadClassNTSC is the class of notebooks, shells, commands and TensorBoards.
+	WorkloadClassNTSC WorkloadClass = "ntsc"
+)
 
 type (
 	// CapacityCheck checks the potential available slots in a resource pool.
@@ -13,4 +26,17 @@ type (
 		SlotsAvailable   int
 		CapacityExceeded bool
 	}
+	// WorkloadClassesSummary is the configuration and usage of the workload classes of a resource
+	// pool. Config is nil if the pool doesn't share its slots between classes.
+	WorkloadClassesSummary struct {
+		Config *config.WorkloadClassesConfig `json:"config"`
+		Usage  []WorkloadClassUsage          `json:"usage"`
+	}
+	// WorkloadClassUsage is the usage of a resource pool by a class of workloads.
+	WorkloadClassUsage struct {
+		Class         WorkloadClass `json:"class"`
+		EntitledSlots int           `json:"entitled_slots"`
+		UsedSlots     int           `json:"used_slots"`
+		PendingSlots  int           `json:"pending_slots"`
+	}
 )
@@ -8,6 +8,7 @@ import (
 
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/taskv1"
 )
 
@@ -149,6 +150,8 @@ func FromContainerStopped(cs *aproto.ContainerStopped) *ResourcesStopped {
 			FailureType: FromContainerFailureType(f.FailureType),
 			ErrMsg:      f.ErrMsg,
 			ExitCode:    FromContainerExitCode(f.ExitCode),
+			Diagnostics: f.Diagnostics,
+			Reason:      f.Reason,
 		}
 	}
 	return rs
@@ -185,6 +188,10 @@ type ResourcesFailedError struct {
 	FailureType FailureType
 	ErrMsg      string
 	ExitCode    *ExitCode
+	// Diagnostics is set if the resource manager could tell more about why the resources failed.
+	Diagnostics *aproto.ContainerDiagnostics `json:",omitempty"`
+	// Reason is set if the resource manager knows what caused the failure.
+	Reason aproto.FailureReason `json:",omitempty"`
 }
 
 // Proto returns the proto representation of ResourcesFailure.
@@ -348,25 +355,29 @@ func IsUnrecoverableSystemError(err error) bool {
 	}
 }
 
-// IsTransientSystemError checks if the error is caused by the system and
-// shouldn't count against `max_restarts`.
-func IsTransientSystemError(err error) bool {
+// ClassifyFailure returns the restart budget an allocation's exit error counts against, or "" if
+// the error isn't a failure, like an abort.
+func ClassifyFailure(err error) model.FailureClass {
 	switch err := err.(type) {
 	case ResourcesFailedError:
 		switch err.FailureType {
-		case ResourcesFailed, TaskError:
-			return false
-		// Questionable, could be considered failures, but for now we don't.
-		case AgentError, AgentFailed, RestoreError:
-			return true
 		// Definitely not a failure.
 		case TaskAborted, ResourcesAborted:
-			return true
+			return ""
+		// Lost agents and failures to restore allocations. These only count against a budget when
+		// the experiment sets max_infra_restarts.
+		case AgentError, AgentFailed, RestoreError:
+			return model.InfraFailure
 		default:
-			return false
+			// Errors of the container runtime or lost Kubernetes nodes, which fail the task
+			// without an exit code.
+			if err.Reason == aproto.InfraFailureReason {
+				return model.InfraFailure
+			}
+			return model.UserFailure
 		}
 	default:
-		return false
+		return model.UserFailure
 	}
 }
 
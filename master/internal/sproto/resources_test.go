@@ -0,0 +1,40 @@
+package sproto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected model.FailureClass
+	}{
+		{"aborted", ResourcesFailedError{FailureType: TaskAborted}, ""},
+		{"non-zero exit code", ResourcesFailedError{FailureType: ResourcesFailed}, model.UserFailure},
+		{"agent lost", ResourcesFailedError{FailureType: AgentFailed}, model.InfraFailure},
+		{"task error", ResourcesFailedError{FailureType: TaskError}, model.UserFailure},
+		{"runtime error", ResourcesFailedError{
+			FailureType: TaskError,
+			Reason:      aproto.InfraFailureReason,
+		}, model.InfraFailure},
+		{"other error", errors.New("boom"), model.UserFailure},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, ClassifyFailure(c.err))
+		})
+	}
+}
+
+func TestFromContainerStoppedKeepsReason(t *testing.T) {
+	stop := aproto.ContainerError(aproto.TaskError, errors.New("no such image"))
+	stop.Failure.Reason = aproto.InfraFailureReason
+	require.Equal(t, model.InfraFailure, ClassifyFailure(*FromContainerStopped(&stop).Failure))
+}
@@ -1,7 +1,61 @@
 package sproto
 
+import (
+	"slices"
+
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
 // FittingRequirements allow tasks to specify requirements for their placement.
 type FittingRequirements struct {
 	// SingleAgent specifies that the task must be located within a single agent.
 	SingleAgent bool
+	// MinGPUMemoryMiB specifies the memory each GPU of the task must have, or 0 for any GPU.
+	MinGPUMemoryMiB int
+	// AgentConstraints specify the labels of the agents the task may be located on.
+	AgentConstraints []AgentConstraint
+	// ScratchGiB specifies the local scratch space each container of the task reserves on its
+	// agent, or 0 for none.
+	ScratchGiB int
+}
+
+// AgentConstraintOperator is how an AgentConstraint matches the value of a label.
+type AgentConstraintOperator string
+
+const (
+	// AgentConstraintIn matches agents whose label is one of the values.
+	AgentConstraintIn AgentConstraintOperator = "in"
+	// AgentConstraintNotIn matches agents without the label or whose label is none of the values.
+	AgentConstraintNotIn AgentConstraintOperator = "notin"
+)
+
+// AgentConstraint selects agents by the value of one of their labels, like the node selector
+// requirements of Kubernetes.
+type AgentConstraint struct {
+	Key      string
+	Operator AgentConstraintOperator
+	Values   []string
+}
+
+// Matches returns whether an agent with the given labels satisfies the constraint.
+func (c AgentConstraint) Matches(labels map[string]string) bool {
+	value, ok := labels[c.Key]
+	in := ok && slices.Contains(c.Values, value)
+	if c.Operator == AgentConstraintNotIn {
+		return !in
+	}
+	return in
+}
+
+// NewAgentConstraints converts expconf agent constraints into internal representation.
+func NewAgentConstraints(input expconf.AgentConstraintsConfig) []AgentConstraint {
+	var out []AgentConstraint
+	for _, c := range input {
+		out = append(out, AgentConstraint{
+			Key:      c.Key(),
+			Operator: AgentConstraintOperator(c.Operator()),
+			Values:   c.Values(),
+		})
+	}
+	return out
 }
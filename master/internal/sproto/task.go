@@ -27,6 +27,7 @@ type (
 		AllocationID      model.AllocationID
 		TaskID            model.TaskID
 		JobID             model.JobID
+		TaskType          model.TaskType
 		RequestTime       time.Time
 		JobSubmissionTime time.Time
 		// IsUserVisible determines whether the AllocateRequest should
@@ -34,18 +35,27 @@ type (
 		IsUserVisible bool
 		State         SchedulingState
 		Name          string
+		// WorkspaceID and OwnerID are the workspace and user the allocation runs for, which
+		// decide the resource pool reservations whose slots it may use.
+		WorkspaceID int
+		OwnerID     model.UserID
 
 		// Resource configuration.
 		SlotsNeeded         int
 		ResourcePool        string
 		FittingRequirements FittingRequirements
+		// PriorityInJob orders the allocations of a job, such as the trials of an experiment.
+		// Allocations with higher values are scheduled before other allocations of their job.
+		PriorityInJob int
 
 		// Behavioral configuration.
-		Preemption  PreemptionConfig
-		IdleTimeout *IdleTimeoutConfig
-		ProxyPorts  []*ProxyPortConfig
-		Restore     bool
-		ProxyTLS    bool
+		Preemption         PreemptionConfig
+		IdleTimeout        *IdleTimeoutConfig
+		StragglerDetection *StragglerDetectionConfig
+		Liveness           *LivenessConfig
+		ProxyPorts         []*ProxyPortConfig
+		Restore            bool
+		ProxyTLS           bool
 
 		// Logging context of the allocation actor.
 		LogContext logger.Context
@@ -62,6 +72,21 @@ type (
 		Debug           bool
 	}
 
+	// StragglerDetectionConfig configures how ranks that lag behind their peers are handled.
+	StragglerDetectionConfig struct {
+		SlowdownThreshold float64
+		Patience          int
+		ExcludeNode       bool
+	}
+
+	// LivenessConfig configures how allocations that stopped making progress are handled. A zero
+	// timeout disables its check.
+	LivenessConfig struct {
+		HeartbeatTimeout   time.Duration
+		MaxTimeWithoutStep time.Duration
+		Restart            bool
+	}
+
 	// PreemptionConfig configures task preemption.
 	PreemptionConfig struct {
 		Preemptible     bool
@@ -104,6 +129,10 @@ type (
 		Slots        int
 		IsSingleNode bool
 		TaskID       *model.TaskID
+		// MinGPUMemoryMiB is the memory each GPU of the request must have, or 0 for any GPU.
+		MinGPUMemoryMiB int
+		// Scratch is the scratch volume each container of the request gets, if any.
+		Scratch *expconf.ScratchConfig
 	}
 
 	// ValidateResourcesResponse is the response to ValidateResourcesRequest.
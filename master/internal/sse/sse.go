@@ -0,0 +1,119 @@
+// Package sse writes server-sent event streams, which let clients without gRPC support, such as
+// browsers and curl, tail logs and metrics over plain HTTP.
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// HeartbeatInterval is how often an idle stream sends a heartbeat, so proxies and clients
+	// don't time the connection out.
+	HeartbeatInterval = 15 * time.Second
+	// retryInterval is how long clients wait before reconnecting after the stream drops.
+	retryInterval = 3 * time.Second
+
+	// ResumeTokenParam is the query parameter clients that can't set the Last-Event-ID header use
+	// to resume a stream.
+	ResumeTokenParam = "resume_token"
+
+	// EventEnd is sent when a stream finishes, so clients know not to reconnect.
+	EventEnd = "end"
+	// EventError is sent when a stream fails after it has started.
+	EventError = "error"
+)
+
+// Writer writes events to an HTTP response.
+type Writer struct {
+	w http.ResponseWriter
+	f http.Flusher
+}
+
+// NewWriter starts an event stream on w.
+func NewWriter(w http.ResponseWriter) (*Writer, error) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("response writer %T does not support streaming", w)
+	}
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	// Stop nginx and similar proxies from buffering the stream.
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sw := &Writer{w: w, f: f}
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", retryInterval.Milliseconds()); err != nil {
+		return nil, err
+	}
+	f.Flush()
+	return sw, nil
+}
+
+// Event writes an event with the given ID, which clients send back as a resume token when they
+// reconnect, and type. Data is sent as is if it is a []byte and as JSON otherwise.
+func (s *Writer) Event(id, event string, data any) error {
+	b, ok := data.([]byte)
+	if !ok {
+		var err error
+		if b, err = json.Marshal(data); err != nil {
+			return fmt.Errorf("marshaling %s event: %w", event, err)
+		}
+	}
+
+	var sb strings.Builder
+	if id != "" {
+		fmt.Fprintf(&sb, "id: %s\n", sanitize(id))
+	}
+	if event != "" {
+		fmt.Fprintf(&sb, "event: %s\n", sanitize(event))
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+
+	if _, err := s.w.Write([]byte(sb.String())); err != nil {
+		return err
+	}
+	s.f.Flush()
+	return nil
+}
+
+// Heartbeat writes a comment, which clients ignore but which keeps the connection alive.
+func (s *Writer) Heartbeat() error {
+	if _, err := s.w.Write([]byte(": heartbeat\n\n")); err != nil {
+		return err
+	}
+	s.f.Flush()
+	return nil
+}
+
+// End writes the end event.
+func (s *Writer) End() error {
+	return s.Event("", EventEnd, map[string]any{})
+}
+
+// Error writes an error event.
+func (s *Writer) Error(err error) error {
+	return s.Event("", EventError, map[string]string{"error": err.Error()})
+}
+
+// ResumeToken returns the ID of the last event a reconnecting client received, from the
+// Last-Event-ID header browsers send or from the resume_token query parameter.
+func ResumeToken(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get(ResumeTokenParam)
+}
+
+// sanitize strips newlines, which would end a field early.
+func sanitize(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
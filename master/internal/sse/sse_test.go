@@ -0,0 +1,40 @@
+package sse
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w, err := NewWriter(rec)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Event("7", "log", map[string]string{"log": "hello"}))
+	require.NoError(t, w.Event("", "raw", []byte("a\nb")))
+	require.NoError(t, w.Event("8\n", "log\n", []byte("x")))
+	require.NoError(t, w.Heartbeat())
+	require.NoError(t, w.Error(errors.New("boom")))
+	require.NoError(t, w.End())
+
+	require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	require.Equal(t, "retry: 3000\n\n"+
+		"id: 7\nevent: log\ndata: {\"log\":\"hello\"}\n\n"+
+		"event: raw\ndata: a\ndata: b\n\n"+
+		"id: 8\nevent: log\ndata: x\n\n"+
+		": heartbeat\n\n"+
+		"event: error\ndata: {\"error\":\"boom\"}\n\n"+
+		"event: end\ndata: {}\n\n", rec.Body.String())
+}
+
+func TestResumeToken(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/logs?resume_token=5", nil)
+	require.Equal(t, "5", ResumeToken(r))
+	r.Header.Set("Last-Event-ID", "9")
+	require.Equal(t, "9", ResumeToken(r))
+	require.Equal(t, "", ResumeToken(httptest.NewRequest(http.MethodGet, "/logs", nil)))
+}
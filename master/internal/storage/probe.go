@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/checkpoints"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// ProbeTimeout is how long probing a single checkpoint storage backend may take.
+const ProbeTimeout = time.Minute
+
+// ProbeStatus is the outcome of probing a checkpoint storage backend.
+type ProbeStatus string
+
+const (
+	// ProbeOK means a probe object was written, read back and deleted.
+	ProbeOK ProbeStatus = "ok"
+	// ProbeFailed means one of the steps of the probe failed.
+	ProbeFailed ProbeStatus = "failed"
+	// ProbeSkipped means the master can't access the storage, like Azure and SFTP, so only tasks
+	// find out whether it is configured correctly.
+	ProbeSkipped ProbeStatus = "skipped"
+)
+
+// ProbeResult is the outcome of probing one checkpoint storage backend.
+type ProbeResult struct {
+	// Name is who the storage is configured for: "master" for the cluster default, or
+	// "workspace/<name>" for a workspace that overrides it.
+	Name string `json:"name"`
+	// UsedBy lists the names of everything else that is configured with the same storage, which
+	// is only probed once.
+	UsedBy          []string                        `json:"used_by"`
+	Type            string                          `json:"type"`
+	Config          expconf.CheckpointStorageConfig `json:"config"`
+	Status          ProbeStatus                     `json:"status"`
+	Error           string                          `json:"error,omitempty"`
+	DurationSeconds float64                         `json:"duration_seconds"`
+}
+
+// namedStorage is checkpoint storage along with who it is configured for.
+type namedStorage struct {
+	name   string
+	config expconf.CheckpointStorageConfig
+}
+
+// ProbeConfigured probes the checkpoint storage of the cluster, defaultStorage, and of every
+// unarchived workspace that overrides it. Each distinct backend is probed once.
+func ProbeConfigured(
+	ctx context.Context, defaultStorage expconf.CheckpointStorageConfig,
+) ([]ProbeResult, error) {
+	var workspaces []model.Workspace
+	if err := db.Bun().NewSelect().Model(&workspaces).
+		Column("name", "checkpoint_storage_config").
+		Where("checkpoint_storage_config IS NOT NULL").
+		Where("NOT archived").
+		Order("id").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting checkpoint storage of workspaces: %w", err)
+	}
+
+	storages := []namedStorage{{name: "master", config: schemas.WithDefaults(defaultStorage)}}
+	for _, w := range workspaces {
+		storages = append(storages, namedStorage{
+			name:   "workspace/" + w.Name,
+			config: schemas.WithDefaults(*schemas.Merge(w.CheckpointStorageConfig, &defaultStorage)),
+		})
+	}
+	return probeStorages(ctx, storages)
+}
+
+func probeStorages(ctx context.Context, storages []namedStorage) ([]ProbeResult, error) {
+	var results []ProbeResult
+	byBackend := map[string]int{}
+	for _, s := range storages {
+		// Storage is the same backend if everything but the save_*_best settings matches.
+		key, err := json.Marshal(s.config.GetUnionMember())
+		if err != nil {
+			return nil, fmt.Errorf("comparing checkpoint storage of %s: %w", s.name, err)
+		}
+		if i, ok := byBackend[string(key)]; ok {
+			results[i].UsedBy = append(results[i].UsedBy, s.name)
+			continue
+		}
+		byBackend[string(key)] = len(results)
+		results = append(results, probeStorage(ctx, s))
+	}
+	return results, nil
+}
+
+func probeStorage(ctx context.Context, s namedStorage) ProbeResult {
+	result := ProbeResult{
+		Name:   s.name,
+		UsedBy: []string{},
+		Type:   checkpoints.StorageType(&s.config),
+		Config: s.config.Printable(),
+		Status: ProbeOK,
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+	backend, err := checkpoints.NewBackend(&s.config)
+	if err == nil {
+		err = backend.Probe(ctx)
+	}
+	result.DurationSeconds = time.Since(start).Seconds()
+
+	switch {
+	case errors.Is(err, checkpoints.ErrUnsupportedStorage):
+		result.Status = ProbeSkipped
+		result.Error = err.Error()
+	case err != nil:
+		result.Status = ProbeFailed
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestProbeStorages(t *testing.T) {
+	directory := func(path string) expconf.CheckpointStorageConfig {
+		return expconf.CheckpointStorageConfig{
+			RawDirectoryConfig: &expconf.DirectoryConfig{RawContainerPath: ptrs.Ptr(path)},
+		}
+	}
+	dir := t.TempDir()
+	shared := directory(dir)
+	shared.RawSaveTrialBest = ptrs.Ptr(5)
+
+	results, err := probeStorages(context.Background(), []namedStorage{
+		{name: "master", config: directory(dir)},
+		{name: "workspace/same", config: shared},
+		{name: "workspace/missing", config: directory(filepath.Join(dir, "missing"))},
+		{name: "workspace/azure", config: expconf.CheckpointStorageConfig{
+			RawAzureConfig: &expconf.AzureConfig{RawContainer: ptrs.Ptr("ckpts")},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.Equal(t, "master", results[0].Name)
+	require.Equal(t, []string{"workspace/same"}, results[0].UsedBy)
+	require.Equal(t, "directory", results[0].Type)
+	require.Equal(t, ProbeOK, results[0].Status, results[0].Error)
+
+	require.Equal(t, "workspace/missing", results[1].Name)
+	require.Equal(t, ProbeFailed, results[1].Status)
+	require.Contains(t, results[1].Error, "writing probe object")
+
+	require.Equal(t, "workspace/azure", results[2].Name)
+	require.Equal(t, ProbeSkipped, results[2].Status)
+}
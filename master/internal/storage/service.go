@@ -102,6 +102,10 @@ func getChildBackendRowWheres(backend storageBackend) ([]string, [][]any) {
 		wheres = append(wheres, "? = ?")
 		args = append(args, []any{bun.Safe(colName), v})
 	}
+	addIntWhere := func(colName string, v int) {
+		wheres = append(wheres, "? = ?")
+		args = append(args, []any{bun.Safe(colName), v})
+	}
 	addStringPtrWhere := func(colName string, v *string) {
 		if v != nil {
 			addStringWhere(colName, *v)
@@ -135,6 +139,20 @@ func getChildBackendRowWheres(backend storageBackend) ([]string, [][]any) {
 		addStringPtrWhere("credential", b.Credential)
 	case *storageBackendDirectory:
 		addStringWhere("container_path", b.ContainerPath)
+	case *storageBackendSFTP:
+		addStringWhere("host", b.Host)
+		addIntWhere("port", b.Port)
+		addStringWhere("username", b.Username)
+		addStringPtrWhere("password", b.Password)
+		addStringPtrWhere("private_key", b.PrivateKey)
+		addStringPtrWhere("private_key_passphrase", b.PrivateKeyPassphrase)
+		addStringPtrWhere("known_hosts", b.KnownHosts)
+		addStringPtrWhere("host_key", b.HostKey)
+		addStringWhere("storage_path", b.StoragePath)
+	case *storageBackendHDFS:
+		addStringWhere("url", b.URL)
+		addStringPtrWhere("username", b.Username)
+		addStringWhere("storage_path", b.StoragePath)
 	}
 
 	return wheres, args
@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"math/rand"
 	"os"
 	"reflect"
 	"strings"
@@ -71,10 +72,11 @@ func generateStorageBackendExhaustiveTestCases(t *testing.T) []storageBackendExh
 		subS := subStruct.Elem()
 		subTypeOfS := subS.Type()
 		for i := 0; i < subS.NumField(); i++ {
-			if subTypeOfS.Field(i).Type.Kind() != reflect.Ptr &&
-				subTypeOfS.Field(i).Type.Elem().Kind() != reflect.String {
-				require.Fail(t, "this test only handles *string, you can add logic "+
-					"to skip the non *string field if you add a test case in TestStorageBackend")
+			if subTypeOfS.Field(i).Type.Kind() != reflect.Ptr ||
+				(subTypeOfS.Field(i).Type.Elem().Kind() != reflect.String &&
+					subTypeOfS.Field(i).Type.Elem().Kind() != reflect.Int) {
+				require.Fail(t, "this test only handles *string and *int, you can add logic "+
+					"to skip other fields if you add a test case in TestStorageBackend")
 			}
 
 			jsonTag, _, _ := strings.Cut(subTypeOfS.Field(i).Tag.Get("json"), ",")
@@ -85,6 +87,14 @@ func generateStorageBackendExhaustiveTestCases(t *testing.T) []storageBackendExh
 				testCase["connection_string"] = nil
 				continue // Azure only can set one of these. So skip account_url.
 			}
+			if unionVal == "sftp" && jsonTag == "password" {
+				testCase["password"] = nil
+				continue // SFTP only can set one of password and private_key.
+			}
+			if subTypeOfS.Field(i).Type.Elem().Kind() == reflect.Int {
+				testCase[jsonTag] = 1 + rand.Intn(65535) //nolint:gosec
+				continue
+			}
 
 			testCase[jsonTag] = uuid.New().String()
 		}
@@ -117,6 +127,10 @@ func TestStorageBackend(t *testing.T) {
 		{"azure connection_string", fillUUIDs(`{"type": "azure", "container": "%s", "connection_string": "%s"}`)},
 		{"azure url", fillUUIDs(`{"type": "azure", "container": "%s", "account_url": "%s", "credential": "%s"}`)},
 		{"container minimal", fillUUIDs(`{"type": "directory", "container_path": "%s"}`)},
+		{"sftp minimal", fillUUIDs(`{"type": "sftp", "host": "%s", "username": "%s", "storage_path": "%s"}`)},
+		{"sftp password", fillUUIDs(`{"type": "sftp", "host": "%s", "port": 2222, "username": "%s", "password": "%s", "storage_path": "%s"}`)},
+		{"sftp host key", fillUUIDs(`{"type": "sftp", "host": "%s", "username": "%s", "private_key": "%s", "private_key_passphrase": "%s", "host_key": "%s", "storage_path": "%s"}`)},
+		{"hdfs minimal", fillUUIDs(`{"type": "hdfs", "url": "%s", "storage_path": "%s"}`)},
 	}
 	cases = append(cases, generateStorageBackendExhaustiveTestCases(t)...)
 
@@ -190,6 +204,14 @@ func TestStorageBackendChecks(t *testing.T) {
 			ConnectionString: ptrs.Ptr(uuid.New().String()),
 			AccountURL:       ptrs.Ptr(uuid.New().String()),
 		}},
+		{"sftp password + private_key set", &storageBackendSFTP{
+			Host:        uuid.New().String(),
+			Port:        22,
+			Username:    uuid.New().String(),
+			Password:    ptrs.Ptr(uuid.New().String()),
+			PrivateKey:  ptrs.Ptr(uuid.New().String()),
+			StoragePath: uuid.New().String(),
+		}},
 		{"fs reserved", &storageBackendSharedFS{
 			HostPath:      uuid.New().String(),
 			ContainerPath: ptrs.Ptr(reserved),
@@ -206,6 +228,11 @@ func TestStorageBackendChecks(t *testing.T) {
 			Container:  uuid.New().String(),
 			Credential: ptrs.Ptr(reserved),
 		}},
+		{"hdfs reserved", &storageBackendHDFS{
+			URL:         uuid.New().String(),
+			Username:    ptrs.Ptr(reserved),
+			StoragePath: uuid.New().String(),
+		}},
 	}
 
 	ctx := context.Background()
@@ -264,6 +291,9 @@ func TestStorageBackendQuery(t *testing.T) {
 					continue
 				}
 
+				if f, ok := v.(float64); ok {
+					v = int(f) // JSON numbers decode as float64.
+				}
 				if v == nil {
 					expected = append(expected, clause{
 						Where: "? IS NULL",
@@ -17,6 +17,8 @@ type storageBackendRow struct {
 	GCSID         *int                   `bun:"gcs_id"`
 	AzureID       *int                   `bun:"azure_id"`
 	DirectoryID   *int                   `bun:"directory_id"`
+	SFTPID        *int                   `bun:"sftp_id"`
+	HDFSID        *int                   `bun:"hdfs_id"`
 }
 
 func (p *storageBackendRow) toChildRowOnlyIDPopulated() storageBackend {
@@ -31,6 +33,10 @@ func (p *storageBackendRow) toChildRowOnlyIDPopulated() storageBackend {
 		return &storageBackendAzure{ID: *p.AzureID}
 	case p.DirectoryID != nil:
 		return &storageBackendDirectory{ID: *p.DirectoryID}
+	case p.SFTPID != nil:
+		return &storageBackendSFTP{ID: *p.SFTPID}
+	case p.HDFSID != nil:
+		return &storageBackendHDFS{ID: *p.HDFSID}
 	default:
 		panic(fmt.Sprintf("expected one of p to be nil %+v", p))
 	}
@@ -167,6 +173,66 @@ func (s *storageBackendDirectory) toExpconf() *expconf.CheckpointStorageConfig {
 	}
 }
 
+type storageBackendSFTP struct {
+	bun.BaseModel `bun:"table:storage_backend_sftp"`
+	ID            int `bun:",pk,autoincrement"`
+
+	Host                 string  `bun:"host"`
+	Port                 int     `bun:"port"`
+	Username             string  `bun:"username"`
+	Password             *string `bun:"password"`
+	PrivateKey           *string `bun:"private_key"`
+	PrivateKeyPassphrase *string `bun:"private_key_passphrase"`
+	KnownHosts           *string `bun:"known_hosts"`
+	HostKey              *string `bun:"host_key"`
+	StoragePath          string  `bun:"storage_path"`
+}
+
+func (s *storageBackendSFTP) id() int {
+	return s.ID
+}
+
+//nolint:exhaustruct
+func (s *storageBackendSFTP) toExpconf() *expconf.CheckpointStorageConfig {
+	return &expconf.CheckpointStorageConfig{
+		RawSFTPConfig: &expconf.SFTPConfig{
+			RawHost:                 &s.Host,
+			RawPort:                 &s.Port,
+			RawUsername:             &s.Username,
+			RawPassword:             s.Password,
+			RawPrivateKey:           s.PrivateKey,
+			RawPrivateKeyPassphrase: s.PrivateKeyPassphrase,
+			RawKnownHosts:           s.KnownHosts,
+			RawHostKey:              s.HostKey,
+			RawStoragePath:          &s.StoragePath,
+		},
+	}
+}
+
+type storageBackendHDFS struct {
+	bun.BaseModel `bun:"table:storage_backend_hdfs"`
+	ID            int `bun:",pk,autoincrement"`
+
+	URL         string  `bun:"url"`
+	Username    *string `bun:"username"`
+	StoragePath string  `bun:"storage_path"`
+}
+
+func (s *storageBackendHDFS) id() int {
+	return s.ID
+}
+
+//nolint:exhaustruct
+func (s *storageBackendHDFS) toExpconf() *expconf.CheckpointStorageConfig {
+	return &expconf.CheckpointStorageConfig{
+		RawHDFSConfig: &expconf.HDFSConfig{
+			RawURL:         &s.URL,
+			RawUsername:    s.Username,
+			RawStoragePath: &s.StoragePath,
+		},
+	}
+}
+
 func expconfToStorage(cs *expconf.CheckpointStorageConfig) (storageBackend, string) {
 	switch storage := cs.GetUnionMember().(type) {
 	case expconf.SharedFSConfig:
@@ -202,6 +268,24 @@ func expconfToStorage(cs *expconf.CheckpointStorageConfig) (storageBackend, stri
 		return &storageBackendDirectory{
 			ContainerPath: storage.ContainerPath(),
 		}, "directory_id"
+	case expconf.SFTPConfig:
+		return &storageBackendSFTP{
+			Host:                 storage.Host(),
+			Port:                 storage.Port(),
+			Username:             storage.Username(),
+			Password:             storage.Password(),
+			PrivateKey:           storage.PrivateKey(),
+			PrivateKeyPassphrase: storage.PrivateKeyPassphrase(),
+			KnownHosts:           storage.KnownHosts(),
+			HostKey:              storage.HostKey(),
+			StoragePath:          storage.StoragePath(),
+		}, "sftp_id"
+	case expconf.HDFSConfig:
+		return &storageBackendHDFS{
+			URL:         storage.URL(),
+			Username:    storage.Username(),
+			StoragePath: storage.StoragePath(),
+		}, "hdfs_id"
 	default:
 		panic(fmt.Sprintf("unknown type converting expconf to storage backend %T", storage))
 	}
@@ -0,0 +1,161 @@
+package storageusage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Workspaces returns the storage usage of the given workspaces, or of every workspace if ids is
+// nil. Usage is summed from the checkpoint sizes kept on experiments, which are updated whenever
+// a checkpoint is reported or garbage collected.
+func Workspaces(ctx context.Context, ids []int) ([]WorkspaceUsage, error) {
+	var usages []WorkspaceUsage
+	q := db.Bun().NewSelect().
+		TableExpr("workspaces AS w").
+		ColumnExpr("w.id AS workspace_id").
+		ColumnExpr("w.name").
+		ColumnExpr("COALESCE(SUM(e.checkpoint_size), 0) AS bytes").
+		ColumnExpr("COALESCE(SUM(e.checkpoint_count), 0) AS checkpoint_count").
+		ColumnExpr("q.soft_quota_bytes").
+		ColumnExpr("q.hard_quota_bytes").
+		ColumnExpr("q.override_until").
+		Join("LEFT JOIN projects AS p ON p.workspace_id = w.id").
+		Join("LEFT JOIN experiments AS e ON e.project_id = p.id").
+		Join("LEFT JOIN workspace_storage_quotas AS q ON q.workspace_id = w.id").
+		Group("w.id", "q.workspace_id").
+		Order("w.id")
+	if ids != nil {
+		q = q.Where("w.id IN (?)", bun.In(ids))
+	}
+	if err := q.Scan(ctx, &usages); err != nil {
+		return nil, fmt.Errorf("getting workspace storage usage: %w", err)
+	}
+	return usages, nil
+}
+
+// Projects returns the storage usage of the given projects.
+func Projects(ctx context.Context, ids []int) ([]ProjectUsage, error) {
+	var usages []ProjectUsage
+	if err := db.Bun().NewSelect().
+		TableExpr("projects AS p").
+		ColumnExpr("p.id AS project_id").
+		ColumnExpr("p.name").
+		ColumnExpr("COALESCE(SUM(e.checkpoint_size), 0) AS bytes").
+		ColumnExpr("COALESCE(SUM(e.checkpoint_count), 0) AS checkpoint_count").
+		Join("LEFT JOIN experiments AS e ON e.project_id = p.id").
+		Where("p.id IN (?)", bun.In(ids)).
+		Group("p.id").
+		Order("p.id").
+		Scan(ctx, &usages); err != nil {
+		return nil, fmt.Errorf("getting project storage usage: %w", err)
+	}
+	return usages, nil
+}
+
+// SetQuotas sets the soft and hard storage quotas of a workspace. A nil quota is removed.
+func SetQuotas(ctx context.Context, workspaceID int, softQuotaBytes, hardQuotaBytes *int64) error {
+	if softQuotaBytes == nil && hardQuotaBytes == nil {
+		if _, err := db.Bun().NewDelete().Model((*model.WorkspaceStorageQuota)(nil)).
+			Where("workspace_id = ?", workspaceID).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("removing storage quotas of workspace %d: %w", workspaceID, err)
+		}
+		return nil
+	}
+
+	// Changing the quotas resets the alert, so a workspace still over its new soft quota alerts
+	// again.
+	if _, err := db.Bun().NewInsert().Model(&model.WorkspaceStorageQuota{
+		WorkspaceID:    workspaceID,
+		SoftQuotaBytes: softQuotaBytes,
+		HardQuotaBytes: hardQuotaBytes,
+	}).
+		On("CONFLICT (workspace_id) DO UPDATE").
+		Set("soft_quota_bytes = EXCLUDED.soft_quota_bytes").
+		Set("hard_quota_bytes = EXCLUDED.hard_quota_bytes").
+		Set("exceeded_at = NULL").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("setting storage quotas of workspace %d: %w", workspaceID, err)
+	}
+	return nil
+}
+
+// SetOverride lets checkpoints be written to a workspace over its hard quota until the given
+// time, or clears the override if until is nil. It returns db.ErrNotFound if the workspace has
+// no quotas.
+func SetOverride(ctx context.Context, workspaceID int, until *time.Time) error {
+	res, err := db.Bun().NewUpdate().Model((*model.WorkspaceStorageQuota)(nil)).
+		Set("override_until = ?", until).
+		Where("workspace_id = ?", workspaceID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("setting storage quota override of workspace %d: %w", workspaceID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// markExceeded records whether a workspace is over its soft quota, returning true only when it has
+// just gone over, so each time a workspace exceeds its quota alerts once.
+func markExceeded(ctx context.Context, workspaceID int, over bool) (bool, error) {
+	q := db.Bun().NewUpdate().Model((*model.WorkspaceStorageQuota)(nil)).
+		Where("workspace_id = ?", workspaceID)
+	if over {
+		q = q.Set("exceeded_at = ?", time.Now().UTC()).Where("exceeded_at IS NULL")
+	} else {
+		q = q.Set("exceeded_at = NULL").Where("exceeded_at IS NOT NULL")
+	}
+
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("marking storage quota of workspace %d: %w", workspaceID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return over && n > 0, nil
+}
+
+func workspaceOfExperiment(ctx context.Context, experimentID int) (int, error) {
+	var id int
+	err := db.Bun().NewSelect().
+		TableExpr("experiments AS e").
+		ColumnExpr("p.workspace_id").
+		Join("JOIN projects AS p ON p.id = e.project_id").
+		Where("e.id = ?", experimentID).
+		Scan(ctx, &id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, db.ErrNotFound
+	} else if err != nil {
+		return 0, fmt.Errorf("getting workspace of experiment %d: %w", experimentID, err)
+	}
+	return id, nil
+}
+
+func workspacesOfCheckpoints(ctx context.Context, checkpoints []uuid.UUID) ([]int, error) {
+	var ids []int
+	if err := db.Bun().NewSelect().
+		TableExpr("run_checkpoints AS rc").
+		ColumnExpr("DISTINCT p.workspace_id").
+		Join("JOIN runs AS r ON r.id = rc.run_id").
+		Join("JOIN projects AS p ON p.id = r.project_id").
+		Where("rc.checkpoint_id IN (?)", bun.In(checkpoints)).
+		Scan(ctx, &ids); err != nil {
+		return nil, fmt.Errorf("getting workspaces of checkpoints: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,106 @@
+//go:build integration
+// +build integration
+
+package storageusage
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/webhooks"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestMain(m *testing.M) {
+	pgDB, _, err := db.ResolveTestPostgres()
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = db.MigrateTestPostgres(pgDB, "file://../../static/migrations", "up")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	err = etc.SetRootPath("../../static/srv")
+	if err != nil {
+		log.Panicln(err)
+	}
+
+	webhooks.Init()
+
+	os.Exit(m.Run())
+}
+
+func TestStorageUsageAndQuota(t *testing.T) {
+	ctx := context.Background()
+
+	user := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	exp := db.RequireMockExperimentProject(t, db.SingleDB(), user, projectID)
+	tr, task := db.RequireMockTrial(t, db.SingleDB(), exp)
+	allocation := db.RequireMockAllocation(t, db.SingleDB(), task.TaskID)
+
+	ckpt := db.MockModelCheckpoint(uuid.New(), allocation)
+	ckpt.Resources = map[string]int64{"model.pt": 100, "metadata.json": 20}
+	require.NoError(t, db.AddCheckpointMetadata(ctx, &ckpt, tr.ID))
+
+	usages, err := Workspaces(ctx, []int{workspaceID})
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	require.Equal(t, int64(120), usages[0].Bytes)
+	require.Equal(t, 1, usages[0].CheckpointCount)
+	require.Nil(t, usages[0].SoftQuotaBytes)
+
+	projects, err := Projects(ctx, []int{projectID})
+	require.NoError(t, err)
+	require.Equal(t, []ProjectUsage{
+		{ProjectID: projectID, Name: projects[0].Name, Bytes: 120, CheckpointCount: 1},
+	}, projects)
+
+	require.NoError(t, SetQuotas(ctx, workspaceID, ptrs.Ptr(int64(100)), nil))
+	require.NoError(t, CheckQuotas(ctx, []uuid.UUID{ckpt.UUID}))
+
+	var quota model.WorkspaceStorageQuota
+	require.NoError(t, db.Bun().NewSelect().Model(&quota).
+		Where("workspace_id = ?", workspaceID).Scan(ctx))
+	require.NotNil(t, quota.ExceededAt)
+
+	// A workspace already over its quota doesn't alert again.
+	exceeded, err := markExceeded(ctx, workspaceID, true)
+	require.NoError(t, err)
+	require.False(t, exceeded)
+
+	// Raising the quota clears the alert.
+	require.NoError(t, SetQuotas(ctx, workspaceID, ptrs.Ptr(int64(1000)), nil))
+	require.NoError(t, CheckQuotas(ctx, []uuid.UUID{ckpt.UUID}))
+	usages, err = Workspaces(ctx, []int{workspaceID})
+	require.NoError(t, err)
+	require.False(t, usages[0].OverSoftQuota())
+
+	// Reaching the hard quota blocks writes until an administrator overrides it.
+	require.NoError(t, CheckCanWrite(ctx, exp.ID))
+	require.NoError(t, SetQuotas(ctx, workspaceID, nil, ptrs.Ptr(int64(120))))
+	require.ErrorIs(t, CheckCanWrite(ctx, exp.ID), ErrHardQuotaExceeded)
+	require.NoError(t, SetOverride(ctx, workspaceID, ptrs.Ptr(time.Now().Add(time.Hour))))
+	require.NoError(t, CheckCanWrite(ctx, exp.ID))
+	require.NoError(t, SetOverride(ctx, workspaceID, nil))
+	require.ErrorIs(t, CheckCanWrite(ctx, exp.ID), ErrHardQuotaExceeded)
+
+	require.NoError(t, SetQuotas(ctx, workspaceID, nil, nil))
+	usages, err = Workspaces(ctx, []int{workspaceID})
+	require.NoError(t, err)
+	require.Nil(t, usages[0].SoftQuotaBytes)
+	require.Nil(t, usages[0].HardQuotaBytes)
+	require.ErrorIs(t, SetOverride(ctx, workspaceID, nil), db.ErrNotFound)
+}
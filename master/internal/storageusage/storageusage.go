@@ -0,0 +1,115 @@
+// Package storageusage accounts for the checkpoint storage used by projects and workspaces, alerts
+// when a workspace goes over its soft storage quota and blocks new checkpoints once it reaches
+// its hard storage quota.
+package storageusage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/webhooks"
+)
+
+// ErrHardQuotaExceeded is returned when a checkpoint is written to a workspace that has reached its
+// hard storage quota.
+var ErrHardQuotaExceeded = errors.New("workspace hard storage quota exceeded")
+
+// WorkspaceUsage is the checkpoint storage used by a workspace.
+type WorkspaceUsage struct {
+	WorkspaceID     int        `bun:"workspace_id" json:"workspace_id"`
+	Name            string     `bun:"name" json:"name"`
+	Bytes           int64      `bun:"bytes" json:"bytes"`
+	CheckpointCount int        `bun:"checkpoint_count" json:"checkpoint_count"`
+	SoftQuotaBytes  *int64     `bun:"soft_quota_bytes" json:"soft_quota_bytes"`
+	HardQuotaBytes  *int64     `bun:"hard_quota_bytes" json:"hard_quota_bytes"`
+	OverrideUntil   *time.Time `bun:"override_until" json:"override_until"`
+}
+
+// OverSoftQuota returns whether the workspace uses more storage than its soft quota allows.
+func (u WorkspaceUsage) OverSoftQuota() bool {
+	return u.SoftQuotaBytes != nil && u.Bytes > *u.SoftQuotaBytes
+}
+
+// WritesBlocked returns whether the workspace has reached its hard quota and no administrator
+// override is in effect at now.
+func (u WorkspaceUsage) WritesBlocked(now time.Time) bool {
+	if u.HardQuotaBytes == nil || u.Bytes < *u.HardQuotaBytes {
+		return false
+	}
+	return u.OverrideUntil == nil || !now.Before(*u.OverrideUntil)
+}
+
+// ProjectUsage is the checkpoint storage used by a project.
+type ProjectUsage struct {
+	ProjectID       int    `bun:"project_id" json:"project_id"`
+	Name            string `bun:"name" json:"name"`
+	Bytes           int64  `bun:"bytes" json:"bytes"`
+	CheckpointCount int    `bun:"checkpoint_count" json:"checkpoint_count"`
+}
+
+// CheckCanWrite returns an error wrapping ErrHardQuotaExceeded if the workspace owning an
+// experiment has reached its hard storage quota, so its trials can't save new checkpoints.
+func CheckCanWrite(ctx context.Context, experimentID int) error {
+	workspaceID, err := workspaceOfExperiment(ctx, experimentID)
+	if err != nil {
+		return err
+	}
+	usages, err := Workspaces(ctx, []int{workspaceID})
+	if err != nil {
+		return err
+	}
+	if len(usages) == 0 || !usages[0].WritesBlocked(time.Now()) {
+		return nil
+	}
+
+	u := usages[0]
+	return fmt.Errorf(
+		"%w: workspace %s is using %d bytes of checkpoint storage, at or over its hard quota of %d "+
+			"bytes; delete checkpoints to free space or ask an administrator to raise the quota",
+		ErrHardQuotaExceeded, u.Name, u.Bytes, *u.HardQuotaBytes)
+}
+
+// CheckQuotas compares the usage of the workspaces owning the given checkpoints to their soft
+// quotas, alerting the first time a workspace goes over its quota. It is called after checkpoint
+// sizes change, when checkpoints are reported or garbage collected.
+func CheckQuotas(ctx context.Context, checkpoints []uuid.UUID) error {
+	workspaceIDs, err := workspacesOfCheckpoints(ctx, checkpoints)
+	if err != nil {
+		return err
+	}
+	if len(workspaceIDs) == 0 {
+		return nil
+	}
+
+	usages, err := Workspaces(ctx, workspaceIDs)
+	if err != nil {
+		return err
+	}
+	for _, u := range usages {
+		if u.SoftQuotaBytes == nil {
+			continue
+		}
+		exceeded, err := markExceeded(ctx, u.WorkspaceID, u.OverSoftQuota())
+		if err != nil {
+			return err
+		}
+		if !exceeded {
+			continue
+		}
+
+		log.WithField("workspace-id", u.WorkspaceID).Warnf(
+			"workspace %s is using %d bytes of checkpoint storage, over its soft quota of %d bytes",
+			u.Name, u.Bytes, *u.SoftQuotaBytes)
+		if err := webhooks.ReportWorkspaceStorageQuotaExceeded(
+			ctx, int32(u.WorkspaceID), u.Name, u.Bytes, *u.SoftQuotaBytes,
+		); err != nil {
+			return fmt.Errorf("reporting storage quota exceeded for workspace %d: %w", u.WorkspaceID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package storageusage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestQuotas(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name        string
+		usage       WorkspaceUsage
+		overSoft    bool
+		writesBlock bool
+	}{
+		{name: "no quotas", usage: WorkspaceUsage{Bytes: 100}},
+		{
+			name:     "over soft quota",
+			usage:    WorkspaceUsage{Bytes: 100, SoftQuotaBytes: ptrs.Ptr(int64(50))},
+			overSoft: true,
+		},
+		{
+			name:  "under hard quota",
+			usage: WorkspaceUsage{Bytes: 99, HardQuotaBytes: ptrs.Ptr(int64(100))},
+		},
+		{
+			name:        "at hard quota",
+			usage:       WorkspaceUsage{Bytes: 100, HardQuotaBytes: ptrs.Ptr(int64(100))},
+			writesBlock: true,
+		},
+		{
+			name: "overridden hard quota",
+			usage: WorkspaceUsage{
+				Bytes:          200,
+				HardQuotaBytes: ptrs.Ptr(int64(100)),
+				OverrideUntil:  ptrs.Ptr(now.Add(time.Hour)),
+			},
+		},
+		{
+			name: "expired override",
+			usage: WorkspaceUsage{
+				Bytes:          200,
+				HardQuotaBytes: ptrs.Ptr(int64(100)),
+				OverrideUntil:  ptrs.Ptr(now.Add(-time.Hour)),
+			},
+			writesBlock: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.overSoft, tc.usage.OverSoftQuota())
+			require.Equal(t, tc.writesBlock, tc.usage.WritesBlocked(now))
+		})
+	}
+}
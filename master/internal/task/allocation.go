@@ -13,18 +13,23 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	apiutils "github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/cluster"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/envvarsets"
 	"github.com/determined-ai/determined/master/internal/portregistry"
 	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/proxy"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task/idle"
+	"github.com/determined-ai/determined/master/internal/task/liveness"
 	"github.com/determined-ai/determined/master/internal/task/preemptible"
+	"github.com/determined-ai/determined/master/internal/task/straggler"
 	"github.com/determined-ai/determined/master/internal/task/tasklogger"
 	"github.com/determined-ai/determined/master/internal/task/taskmodel"
 	"github.com/determined-ai/determined/master/internal/telemetry"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	detLogger "github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -129,6 +134,11 @@ type allocation struct {
 	killCooldown *time.Time
 	// tracks if we have finished termination.
 	exited *AllocationExited
+	// Set when liveness detection killed the allocation because it stalled, so that it exits with
+	// an error instead of as if it were killed by a user.
+	stallErr error
+	// The NCCL_* environment variables the task was started with, for diagnosing failures.
+	ncclEnv map[string]string
 
 	// State for specific sub-behaviors of an allocation.
 	// Encapsulates logic of rendezvousing containers of the currently
@@ -321,6 +331,30 @@ func (a *allocation) Signal(sig AllocationSignal, reason string) {
 	}
 }
 
+// Exec starts a process in the container of the allocation's first running resources.
+func (a *allocation) Exec(ctx context.Context, req sproto.ExecRequest) (*sproto.ExecSession, error) {
+	a.mu.Lock()
+	var target *taskmodel.ResourcesWithState
+	for _, r := range a.resources {
+		if r.Started == nil || r.Exited != nil {
+			continue
+		}
+		if target == nil || r.Rank < target.Rank {
+			target = r
+		}
+	}
+	a.mu.Unlock()
+
+	if target == nil {
+		return nil, AllocationUnfulfilledError{Action: "exec"}
+	}
+	r, ok := target.Resources.(sproto.ExecResources)
+	if !ok {
+		return nil, sproto.ErrExecUnsupported
+	}
+	return r.Exec(ctx, req)
+}
+
 // SetProxyAddress sets the proxy address of the allocation and sets up proxies for any services
 // it provides.
 func (a *allocation) SetProxyAddress(ctx context.Context, address string) error {
@@ -433,27 +467,65 @@ func (a *allocation) WatchRendezvous(rID sproto.ResourcesID) (RendezvousWatcher,
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	err := a.validateRendezvous()
-	if err != nil {
+	if err := a.startRendezvous(); err != nil {
 		return RendezvousWatcher{}, err
 	}
+	return a.rendezvous.watch(rID)
+}
+
+// AdvertiseRendezvousAddress sets the address the peers of a resource reach it at, instead of the
+// address of its host.
+func (a *allocation) AdvertiseRendezvousAddress(rID sproto.ResourcesID, addr string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.startRendezvous(); err != nil {
+		return err
+	}
+	return a.rendezvous.advertise(rID, addr)
+}
+
+// ReportRendezvousReachability records the ranks a resource couldn't connect to after the
+// rendezvous, and logs which connections failed.
+func (a *allocation) ReportRendezvousReachability(rID sproto.ResourcesID, unreachable []int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
 	if a.rendezvous == nil {
-		a.rendezvous = newRendezvous(a.model.AllocationID, a.resources, rendezvousTimeoutDuration)
-		a.closers = append(a.closers, a.rendezvous.close)
-		a.wg.Go(func(ctx context.Context) {
-			t := time.NewTimer(rendezvousTimeoutDuration)
-			defer t.Stop()
-
-			select {
-			case <-t.C:
-				a.RendezvousTimeout()
-			case <-ctx.Done():
-			}
-		})
+		return apiutils.AsValidationError("rendezvous has not started: %s", a.model.AllocationID)
 	}
+	msg, err := a.rendezvous.reportUnreachable(rID, unreachable)
+	if err != nil {
+		return err
+	}
+	if msg != "" {
+		a.sendTaskLog(&model.TaskLog{Log: msg, Level: ptrs.Ptr(model.LogLevelWarning)})
+	}
+	return nil
+}
 
-	return a.rendezvous.watch(rID)
+// startRendezvous starts the rendezvous of the allocation, if it hasn't started yet.
+func (a *allocation) startRendezvous() error {
+	if err := a.validateRendezvous(); err != nil {
+		return err
+	}
+	if a.rendezvous != nil {
+		return nil
+	}
+
+	a.rendezvous = newRendezvous(a.model.AllocationID, a.resources, rendezvousTimeoutDuration)
+	a.closers = append(a.closers, a.rendezvous.close)
+	a.wg.Go(func(ctx context.Context) {
+		t := time.NewTimer(rendezvousTimeoutDuration)
+		defer t.Stop()
+
+		select {
+		case <-t.C:
+			a.RendezvousTimeout()
+		case <-ctx.Done():
+		}
+	})
+	return nil
 }
 
 // UnwatchRendezvous removes a rendezvous watcher.
@@ -577,6 +649,9 @@ func (a *allocation) finalize(
 	}
 	a.purgeRestorableResources()
 	a.markResourcesReleased()
+	if exitErr != nil && severity == logrus.ErrorLevel {
+		a.recordDiagnostics(exitReason)
+	}
 
 	a.exited = &AllocationExited{UserRequestedStop: userRequestedStop, Err: exitErr, FinalState: a.state()}
 	a.SetExitStatus(exitReason, exitErr, nil)
@@ -657,6 +732,30 @@ func (a *allocation) resourcesAllocated(msg *sproto.ResourcesAllocated) error {
 		})
 	}
 
+	if cfg := a.req.StragglerDetection; cfg != nil {
+		straggler.Register(a.req.AllocationID.String(), *cfg, func(alert straggler.Alert) {
+			a.handleStraggler(*cfg, alert)
+		})
+		a.closers = append(a.closers, func() {
+			straggler.Unregister(a.req.AllocationID.String())
+		})
+	}
+
+	if cfg := a.req.Liveness; cfg != nil {
+		// The actions run outside the watcher, since closing it under the lock waits for it.
+		liveness.Register(a.req.TaskID, a.req.AllocationID, *cfg, func(reason string) {
+			a.wg.Go(func(context.Context) { a.handleStall(*cfg, reason) })
+		}, func() {
+			a.wg.Go(func(context.Context) { a.handleStallRecovered() })
+		})
+		a.closers = append(a.closers, func() {
+			liveness.Unregister(a.req.TaskID, a.req.AllocationID)
+		})
+		if a.req.Restore {
+			a.restoreStall()
+		}
+	}
+
 	if a.req.Restore {
 		for _, port := range a.model.Ports {
 			portregistry.RestorePort(port)
@@ -705,6 +804,24 @@ func (a *allocation) resourcesAllocated(msg *sproto.ResourcesAllocated) error {
 			spec.ExtraEnvVars[portName] = strconv.Itoa(port)
 		}
 
+		// Sets are resolved as the task starts, so changes to them reach tasks that were queued
+		// or are restarted. Variables set by the master take precedence over the sets'.
+		if sets := spec.Environment.EnvironmentVariableSets(); spec.WorkspaceID != 0 && len(sets) > 0 {
+			envVars, err := envvarsets.Resolve(context.TODO(), spec.WorkspaceID, sets)
+			if err != nil {
+				return fmt.Errorf("resolving environment variable sets: %w", err)
+			}
+			if spec.ExtraEnvVars == nil {
+				spec.ExtraEnvVars = map[string]string{}
+			}
+			for k, v := range envVars {
+				if _, ok := spec.ExtraEnvVars[k]; !ok {
+					spec.ExtraEnvVars[k] = v
+				}
+			}
+		}
+
+		a.ncclEnv = spec.NCCLEnvVars()
 		for cID, r := range a.resources {
 			if err := r.Start(a.logCtx, spec, sproto.ResourcesRuntimeInfo{
 				Token:        token,
@@ -1078,10 +1195,11 @@ func (a *allocation) registerProxies(addresses []cproto.Address) {
 		if a.req.ProxyTLS {
 			urlScheme = "https"
 		}
-		proxy.DefaultProxy.Register(pcfg.ServiceID, &url.URL{
-			Scheme: urlScheme,
-			Host:   fmt.Sprintf("%s:%d", address.HostIP, address.HostPort),
-		}, pcfg.ProxyTCP, pcfg.Unauthenticated)
+		proxy.DefaultProxy.RegisterForAllocation(pcfg.ServiceID, string(a.model.AllocationID),
+			&url.URL{
+				Scheme: urlScheme,
+				Host:   fmt.Sprintf("%s:%d", address.HostIP, address.HostPort),
+			}, pcfg.ProxyTCP, pcfg.Unauthenticated)
 		a.syslog.Debugf("registered proxy id: %s, cfg: %v\n", pcfg.ServiceID, pcfg)
 		a.proxies = append(a.proxies, pcfg.ServiceID)
 	}
@@ -1151,6 +1269,8 @@ func (a *allocation) calculateExitStatus(reason string) (
 	exitErr error,
 ) {
 	switch {
+	case a.stallErr != nil:
+		return fmt.Sprintf("allocation killed after %s", reason), false, logrus.ErrorLevel, a.stallErr
 	case a.killedWhileRunning:
 		return fmt.Sprintf("allocation killed after %s", reason), false, logrus.InfoLevel, nil
 	case a.req.Preemption.Preemptible && preemptible.Acknowledged(a.req.AllocationID.String()):
@@ -1254,6 +1374,112 @@ func (a *allocation) enrichLog(log *model.TaskLog) *model.TaskLog {
 	return log
 }
 
+// recordDiagnostics persists a diagnostics bundle for a failed allocation. Logs aren't copied
+// into it, since they are kept anyway; they are added to the bundle when it is downloaded.
+func (a *allocation) recordDiagnostics(exitReason string) {
+	ncclEnv := a.ncclEnv
+	if ncclEnv == nil {
+		ncclEnv = map[string]string{}
+	}
+	if err := AddAllocationDiagnostics(context.TODO(), &model.AllocationDiagnostics{
+		TaskID:       a.req.TaskID,
+		AllocationID: a.req.AllocationID,
+		ExitReason:   exitReason,
+		Resources:    a.resources.diagnostics(),
+		NCCLEnv:      ncclEnv,
+	}); err != nil {
+		a.syslog.WithError(err).Error("failed to record allocation diagnostics")
+	}
+}
+
+// handleStraggler records a straggler alert and surfaces it in the task logs. If the allocation
+// is configured to exclude the suspect node, the allocation is terminated so that it is restarted
+// elsewhere.
+func (a *allocation) handleStraggler(cfg sproto.StragglerDetectionConfig, alert straggler.Alert) {
+	msg := fmt.Sprintf(
+		"straggler detected: rank %d on agent %s was %.2fx slower than its peers for %d windows",
+		alert.Rank, alert.AgentID, alert.Slowdown, alert.Windows,
+	)
+	a.syslog.Warn(msg)
+
+	if err := AddStragglerAlert(context.TODO(), &model.StragglerAlert{
+		TaskID:       a.req.TaskID,
+		AllocationID: a.req.AllocationID,
+		Rank:         alert.Rank,
+		AgentID:      alert.AgentID,
+		Slowdown:     alert.Slowdown,
+		Windows:      alert.Windows,
+		Excluded:     cfg.ExcludeNode,
+	}); err != nil {
+		a.syslog.WithError(err).Error("failed to record straggler alert")
+	}
+
+	if !cfg.ExcludeNode {
+		a.sendTaskLog(&model.TaskLog{Log: msg, Level: ptrs.Ptr(model.LogLevelWarning)})
+		return
+	}
+	a.sendTaskLog(&model.TaskLog{
+		Log:   msg + fmt.Sprintf(", restarting without agent %s", alert.AgentID),
+		Level: ptrs.Ptr(model.LogLevelWarning),
+	})
+	a.Signal(TerminateAllocation, fmt.Sprintf("straggler detected on agent %s", alert.AgentID))
+}
+
+// handleStall records a stall alert, surfaces it in the task logs and fires the webhooks of the
+// task's workspace. If the allocation is configured to restart, it is killed, since a stalled
+// allocation can't be expected to respond to preemption, and exits with an error that counts
+// against the restarts of its task.
+func (a *allocation) handleStall(cfg sproto.LivenessConfig, reason string) {
+	msg := fmt.Sprintf("%s stalled: %s", a.req.Name, reason)
+	a.syslog.Warn(msg)
+
+	ctx := context.TODO()
+	if err := AddStallAlert(ctx, &model.StallAlert{
+		TaskID:       a.req.TaskID,
+		AllocationID: a.req.AllocationID,
+		Reason:       reason,
+		Restarted:    cfg.Restart,
+	}); err != nil {
+		a.syslog.WithError(err).Error("failed to record stall alert")
+	}
+	if err := webhooks.ReportTrialStalled(ctx, a.req.TaskID, reason, cfg.Restart); err != nil {
+		a.syslog.WithError(err).Error("failed to report stall to webhooks")
+	}
+
+	if !cfg.Restart {
+		a.sendTaskLog(&model.TaskLog{Log: msg, Level: ptrs.Ptr(model.LogLevelWarning)})
+		return
+	}
+	a.sendTaskLog(&model.TaskLog{Log: msg + ", restarting", Level: ptrs.Ptr(model.LogLevelWarning)})
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stallErr = sproto.ResourcesFailedError{FailureType: sproto.TaskError, ErrMsg: msg}
+	a.tryExitOrKill(fmt.Sprintf("stalled: %s", reason))
+}
+
+// restoreStall marks a restored allocation stalled again if it was stalled before the master
+// restarted, since its stall alert is still open.
+func (a *allocation) restoreStall() {
+	alert, err := OpenStallAlert(context.TODO(), a.req.AllocationID)
+	if err != nil {
+		a.syslog.WithError(err).Error("failed to restore stall status")
+		return
+	}
+	if alert != nil {
+		liveness.RestoreStall(a.req.TaskID, a.req.AllocationID, alert.Reason)
+	}
+}
+
+// handleStallRecovered notes that a stalled allocation makes progress again.
+func (a *allocation) handleStallRecovered() {
+	a.syslog.Info("allocation recovered from stall")
+	if err := RecoverStallAlerts(context.TODO(), a.req.AllocationID); err != nil {
+		a.syslog.WithError(err).Error("failed to record stall recovery")
+	}
+	a.sendTaskLog(&model.TaskLog{Log: fmt.Sprintf("%s is making progress again", a.req.Name)})
+}
+
 // sendTaskLog is called without a lock.
 func (a *allocation) sendTaskLog(log *model.TaskLog) {
 	tasklogger.Insert(a.enrichLog(log))
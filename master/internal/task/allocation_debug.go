@@ -0,0 +1,95 @@
+package task
+
+import (
+	"sort"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AllocationDebugState is a dump of the internal state of an allocation, for debugging the master.
+type AllocationDebugState struct {
+	AllocationID model.AllocationID `json:"allocation_id"`
+	TaskID       model.TaskID       `json:"task_id"`
+	Name         string             `json:"name"`
+	ResourcePool string             `json:"resource_pool"`
+	// Locked is set if the allocation was locked for too long to dump the rest of its state, which
+	// usually means it is stuck.
+	Locked bool `json:"locked,omitempty"`
+
+	State              model.AllocationState        `json:"state,omitempty"`
+	Ready              bool                         `json:"ready"`
+	ResourcesStarted   bool                         `json:"resources_started"`
+	Restored           bool                         `json:"restored"`
+	KilledWhileRunning bool                         `json:"killed_while_running"`
+	Exited             bool                         `json:"exited"`
+	ExitErr            string                       `json:"exit_err,omitempty"`
+	Resources          []model.ResourcesDiagnostics `json:"resources,omitempty"`
+	Rendezvous         *RendezvousDebugState        `json:"rendezvous,omitempty"`
+	Proxies            []string                     `json:"proxies,omitempty"`
+}
+
+// RendezvousDebugState is a dump of the internal state of a rendezvous.
+type RendezvousDebugState struct {
+	Watchers          []sproto.ResourcesID          `json:"watchers"`
+	AllReadySucceeded bool                          `json:"all_ready_succeeded"`
+	Advertised        map[sproto.ResourcesID]string `json:"advertised,omitempty"`
+	Unreachable       map[sproto.ResourcesID][]int  `json:"unreachable,omitempty"`
+}
+
+// debugState dumps the state of the allocation without waiting on its lock, so that a stuck
+// allocation can't hang the dump of the rest.
+func (a *allocation) debugState() AllocationDebugState {
+	s := AllocationDebugState{
+		AllocationID: a.req.AllocationID,
+		TaskID:       a.req.TaskID,
+		Name:         a.req.Name,
+		ResourcePool: a.req.ResourcePool,
+	}
+	if !a.mu.TryLock() {
+		s.Locked = true
+		return s
+	}
+	defer a.mu.Unlock()
+
+	s.State = a.getModelState()
+	s.Ready = coalesceBool(a.model.IsReady, false)
+	s.ResourcesStarted = a.resourcesStarted
+	s.Restored = a.restored
+	s.KilledWhileRunning = a.killedWhileRunning
+	s.Exited = a.exited != nil
+	if a.exitErr != nil {
+		s.ExitErr = a.exitErr.Error()
+	}
+	s.Resources = a.resources.diagnostics()
+	s.Proxies = append(s.Proxies, a.proxies...)
+	if r := a.rendezvous; r != nil {
+		watchers := maps.Keys(r.watchers)
+		sort.Slice(watchers, func(i, j int) bool { return watchers[i] < watchers[j] })
+		s.Rendezvous = &RendezvousDebugState{
+			Watchers:          watchers,
+			AllReadySucceeded: r.allReadySucceeded,
+			Advertised:        maps.Clone(r.advertised),
+			Unreachable:       maps.Clone(r.unreachable),
+		}
+	}
+	return s
+}
+
+// DebugState dumps the internal state of every allocation, ordered by ID.
+func (as *allocationService) DebugState() []AllocationDebugState {
+	as.mu.RLock()
+	refs := maps.Values(as.allocations)
+	as.mu.RUnlock()
+
+	states := make([]AllocationDebugState, 0, len(refs))
+	for _, ref := range refs {
+		states = append(states, ref.debugState())
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].AllocationID < states[j].AllocationID
+	})
+	return states
+}
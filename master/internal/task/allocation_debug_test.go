@@ -0,0 +1,46 @@
+package task
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestAllocationServiceDebugState(t *testing.T) {
+	as := newAllocationService()
+	for _, id := range []model.AllocationID{"task-b.1", "task-a.1"} {
+		as.allocations[id] = &allocation{
+			req: sproto.AllocateRequest{
+				AllocationID: id,
+				Name:         string(id),
+				ResourcePool: "default",
+			},
+			resources: resourcesList{},
+		}
+	}
+	as.allocations["task-a.1"].exitErr = errors.New("container failed")
+	as.allocations["task-a.1"].rendezvous = &rendezvous{
+		unreachable: map[sproto.ResourcesID][]int{"r1": {1}},
+	}
+
+	// A stuck allocation is reported as such without holding up the rest.
+	as.allocations["task-b.1"].mu.Lock()
+	defer as.allocations["task-b.1"].mu.Unlock()
+
+	states := as.DebugState()
+	require.Len(t, states, 2)
+
+	require.Equal(t, model.AllocationID("task-a.1"), states[0].AllocationID)
+	require.False(t, states[0].Locked)
+	require.Equal(t, model.AllocationStatePending, states[0].State)
+	require.Equal(t, "container failed", states[0].ExitErr)
+	require.Equal(t, []int{1}, states[0].Rendezvous.Unreachable["r1"])
+
+	require.Equal(t, "task-b.1", states[1].Name)
+	require.True(t, states[1].Locked)
+	require.Empty(t, states[1].State)
+}
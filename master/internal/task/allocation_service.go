@@ -201,6 +201,36 @@ func (as *allocationService) WatchRendezvous(
 	}
 }
 
+// AdvertiseRendezvousAddress sets the address the peers of a resource reach it at, instead of the
+// address of its host. It must be called before the resource watches the rendezvous.
+func (as *allocationService) AdvertiseRendezvousAddress(
+	ctx context.Context,
+	id model.AllocationID,
+	rID sproto.ResourcesID,
+	addr string,
+) error {
+	ref, err := as.waitForRestore(ctx, id)
+	if err != nil {
+		return err
+	}
+	return ref.AdvertiseRendezvousAddress(rID, addr)
+}
+
+// ReportRendezvousReachability records the ranks a resource couldn't connect to after the
+// rendezvous, and logs which connections failed.
+func (as *allocationService) ReportRendezvousReachability(
+	ctx context.Context,
+	id model.AllocationID,
+	rID sproto.ResourcesID,
+	unreachable []int,
+) error {
+	ref, err := as.waitForRestore(ctx, id)
+	if err != nil {
+		return err
+	}
+	return ref.ReportRendezvousReachability(rID, unreachable)
+}
+
 // SetResourcesAsDaemon marks the resources as daemons. If all non-daemon resources exit, the
 // allocation will kill the remaining daemon resources.
 func (as *allocationService) SetResourcesAsDaemon(
@@ -229,6 +259,19 @@ func (as *allocationService) Signal(
 	return nil
 }
 
+// Exec starts a process in a container of the allocation and relays it.
+func (as *allocationService) Exec(
+	ctx context.Context,
+	id model.AllocationID,
+	req sproto.ExecRequest,
+) (*sproto.ExecSession, error) {
+	ref, err := as.waitForRestore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ref.Exec(ctx, req)
+}
+
 // State returns a copy of the current state of the allocation.
 // TODO(DET-9698): Just replace this with DB access, easy to do.
 func (as *allocationService) State(id model.AllocationID) (AllocationState, error) {
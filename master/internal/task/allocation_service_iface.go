@@ -18,6 +18,7 @@ import (
 // AllocationService allows callers to launch, direct and query allocations.
 type AllocationService interface {
 	GetAllAllocationIDs() []model.AllocationID
+	DebugState() []AllocationDebugState
 	StartAllocation(
 		logCtx logger.Context,
 		req sproto.AllocateRequest,
@@ -53,6 +54,18 @@ type AllocationService interface {
 		id model.AllocationID,
 		rID sproto.ResourcesID,
 	) (*trialv1.RendezvousInfo, error)
+	AdvertiseRendezvousAddress(
+		ctx context.Context,
+		id model.AllocationID,
+		rID sproto.ResourcesID,
+		addr string,
+	) error
+	ReportRendezvousReachability(
+		ctx context.Context,
+		id model.AllocationID,
+		rID sproto.ResourcesID,
+		unreachable []int,
+	) error
 	SetResourcesAsDaemon(
 		ctx context.Context,
 		id model.AllocationID,
@@ -73,5 +86,10 @@ type AllocationService interface {
 		log *sproto.ContainerLog,
 	)
 	WaitForRestore(ctx context.Context, id model.AllocationID) error
+	Exec(
+		ctx context.Context,
+		id model.AllocationID,
+		req sproto.ExecRequest,
+	) (*sproto.ExecSession, error)
 	Detach(id model.AllocationID) error
 }
@@ -0,0 +1,68 @@
+package liveness
+
+import (
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/syncx/mapx"
+)
+
+// Watchers are keyed by task, since a task runs one allocation at a time, so that the liveness
+// of a task can be looked up without knowing its current allocation.
+var watchers = mapx.New[model.TaskID, *Watcher]()
+
+// Register a watcher for an allocation to the default service. The actions can trigger until
+// Unregister is called.
+func Register(
+	taskID model.TaskID, allocationID model.AllocationID, cfg sproto.LivenessConfig,
+	onStall StallFn, onRecover RecoverFn,
+) {
+	w := New(allocationID.String(), cfg, onStall, onRecover)
+	if old, ok := watchers.Load(taskID); ok {
+		old.Close()
+	}
+	watchers.Store(taskID, w)
+}
+
+// Unregister removes the watcher of an allocation from the service.
+func Unregister(taskID model.TaskID, allocationID model.AllocationID) {
+	var w *Watcher
+	watchers.WithLock(func(m map[model.TaskID]*Watcher) {
+		if cur, ok := m[taskID]; ok && cur.Status().AllocationID == allocationID.String() {
+			w = cur
+			delete(m, taskID)
+		}
+	})
+	if w != nil {
+		w.Close()
+	}
+}
+
+// RecordHeartbeat records a heartbeat from an allocation. It returns ErrNotWatched if the
+// allocation isn't the one its task is running.
+func RecordHeartbeat(taskID model.TaskID, allocationID model.AllocationID, hb Heartbeat) error {
+	w, ok := watchers.Load(taskID)
+	if !ok || w.Status().AllocationID != allocationID.String() {
+		return ErrNotWatched
+	}
+	w.Heartbeat(time.Now(), hb)
+	return nil
+}
+
+// RestoreStall marks the allocation a task is running stalled, as it was before the master
+// restarted.
+func RestoreStall(taskID model.TaskID, allocationID model.AllocationID, reason string) {
+	if w, ok := watchers.Load(taskID); ok && w.Status().AllocationID == allocationID.String() {
+		w.RestoreStall(reason)
+	}
+}
+
+// GetStatus returns the liveness of the allocation a task is running, if it is watched.
+func GetStatus(taskID model.TaskID) (Status, bool) {
+	w, ok := watchers.Load(taskID)
+	if !ok {
+		return Status{}, false
+	}
+	return w.Status(), true
+}
@@ -0,0 +1,173 @@
+package liveness
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/syncx/waitgroupx"
+)
+
+// ErrNotWatched indicates that an allocation does not have liveness detection enabled or is not
+// running.
+var ErrNotWatched = fmt.Errorf("liveness detection is not enabled for allocation")
+
+// TickInterval is the interval at which watchers check the liveness of their allocations.
+var TickInterval = 5 * time.Second
+
+// State is the liveness of an allocation.
+type State string
+
+const (
+	// StateWaiting means the allocation hasn't sent its first heartbeat yet.
+	StateWaiting State = "WAITING"
+	// StateHealthy means the allocation sends heartbeats and makes progress.
+	StateHealthy State = "HEALTHY"
+	// StateStalled means the allocation stopped sending heartbeats or stopped making progress.
+	StateStalled State = "STALLED"
+)
+
+// Heartbeat is sent periodically by the harness of a running allocation.
+type Heartbeat struct {
+	// StepsCompleted is the step of the last metrics the allocation reported, if it reported any.
+	StepsCompleted *int `json:"steps_completed"`
+}
+
+// Status is the liveness of an allocation as last observed by its watcher.
+type Status struct {
+	AllocationID   string     `json:"allocation_id"`
+	State          State      `json:"state"`
+	Reason         string     `json:"reason,omitempty"`
+	LastHeartbeat  *time.Time `json:"last_heartbeat"`
+	StepsCompleted *int       `json:"steps_completed"`
+	LastStepAt     *time.Time `json:"last_step_at"`
+}
+
+// StallFn is called with the reason an allocation stalled.
+type StallFn func(reason string)
+
+// RecoverFn is called when a stalled allocation makes progress again.
+type RecoverFn func()
+
+// Watcher tracks the heartbeats of an allocation and calls its actions when the allocation stalls
+// or recovers. The clocks start with the first heartbeat, so time spent pulling images or
+// waiting for peers before the harness starts is never considered a stall.
+type Watcher struct {
+	// Configuration.
+	cfg       sproto.LivenessConfig
+	onStall   StallFn
+	onRecover RecoverFn
+
+	// Mutable internal state.
+	mu     sync.Mutex
+	wg     waitgroupx.Group
+	status Status
+}
+
+// New creates a new liveness watcher. The actions can be called until Close is called.
+func New(
+	allocationID string, cfg sproto.LivenessConfig, onStall StallFn, onRecover RecoverFn,
+) *Watcher {
+	w := &Watcher{
+		cfg:       cfg,
+		onStall:   onStall,
+		onRecover: onRecover,
+		wg:        waitgroupx.WithContext(context.Background()),
+		status:    Status{AllocationID: allocationID, State: StateWaiting},
+	}
+	w.wg.Go(w.run)
+	return w
+}
+
+// Heartbeat records a heartbeat received at the given instant.
+func (w *Watcher) Heartbeat(now time.Time, hb Heartbeat) {
+	w.mu.Lock()
+	s := &w.status
+	if s.LastStepAt == nil || hb.StepsCompleted != nil &&
+		(s.StepsCompleted == nil || *hb.StepsCompleted > *s.StepsCompleted) {
+		s.LastStepAt = &now
+	}
+	if hb.StepsCompleted != nil {
+		s.StepsCompleted = hb.StepsCompleted
+	}
+	s.LastHeartbeat = &now
+
+	recovered := s.State == StateStalled && w.stallReason(now) == ""
+	if s.State != StateStalled || recovered {
+		s.State, s.Reason = StateHealthy, ""
+	}
+	w.mu.Unlock()
+
+	if recovered {
+		w.onRecover()
+	}
+}
+
+// RestoreStall marks the allocation stalled for a reason it was found stalled for before the
+// master restarted, so that it recovers once it makes progress again rather than stalling anew.
+func (w *Watcher) RestoreStall(reason string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.State, w.status.Reason = StateStalled, reason
+}
+
+// Status returns the liveness of the allocation.
+func (w *Watcher) Status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+// Close closes the watcher.
+func (w *Watcher) Close() {
+	w.wg.Close()
+}
+
+func (w *Watcher) run(ctx context.Context) {
+	t := time.NewTicker(TickInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+		w.tick(time.Now())
+	}
+}
+
+func (w *Watcher) tick(now time.Time) {
+	w.mu.Lock()
+	if w.status.State != StateHealthy {
+		w.mu.Unlock()
+		return
+	}
+	reason := w.stallReason(now)
+	if reason == "" {
+		w.mu.Unlock()
+		return
+	}
+	w.status.State, w.status.Reason = StateStalled, reason
+	w.mu.Unlock()
+
+	w.onStall(reason)
+}
+
+// stallReason returns why the allocation is stalled at the given instant, or "" if it isn't. It
+// must be called with the lock held, after the first heartbeat.
+func (w *Watcher) stallReason(now time.Time) string {
+	s := w.status
+	if timeout := w.cfg.HeartbeatTimeout; timeout > 0 && now.Sub(*s.LastHeartbeat) > timeout {
+		return fmt.Sprintf("no heartbeat for more than %s", timeout)
+	}
+	if timeout := w.cfg.MaxTimeWithoutStep; timeout > 0 && now.Sub(*s.LastStepAt) > timeout {
+		if s.StepsCompleted == nil {
+			return fmt.Sprintf("no steps reported for more than %s", timeout)
+		}
+		return fmt.Sprintf("no steps reported past step %d for more than %s",
+			*s.StepsCompleted, timeout)
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package liveness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+type recorder struct {
+	stalls     []string
+	recoveries int
+}
+
+func newTestWatcher(t *testing.T, cfg sproto.LivenessConfig) (*Watcher, *recorder) {
+	r := &recorder{}
+	w := New("alloc", cfg, func(reason string) {
+		r.stalls = append(r.stalls, reason)
+	}, func() {
+		r.recoveries++
+	})
+	t.Cleanup(w.Close)
+	return w, r
+}
+
+func TestWatcherHeartbeatTimeout(t *testing.T) {
+	w, r := newTestWatcher(t, sproto.LivenessConfig{HeartbeatTimeout: time.Minute})
+	start := time.Now()
+
+	// The clocks don't start before the first heartbeat.
+	w.tick(start.Add(time.Hour))
+	require.Equal(t, StateWaiting, w.Status().State)
+	require.Empty(t, r.stalls)
+
+	w.Heartbeat(start, Heartbeat{})
+	w.tick(start.Add(30 * time.Second))
+	require.Equal(t, StateHealthy, w.Status().State)
+
+	w.tick(start.Add(2 * time.Minute))
+	require.Equal(t, StateStalled, w.Status().State)
+	require.Equal(t, []string{"no heartbeat for more than 1m0s"}, r.stalls)
+
+	// Stalls are reported once.
+	w.tick(start.Add(3 * time.Minute))
+	require.Len(t, r.stalls, 1)
+
+	w.Heartbeat(start.Add(4*time.Minute), Heartbeat{})
+	require.Equal(t, StateHealthy, w.Status().State)
+	require.Equal(t, 1, r.recoveries)
+}
+
+func TestWatcherMaxTimeWithoutStep(t *testing.T) {
+	w, r := newTestWatcher(t, sproto.LivenessConfig{
+		HeartbeatTimeout:   time.Minute,
+		MaxTimeWithoutStep: 10 * time.Minute,
+	})
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	w.Heartbeat(at(0), Heartbeat{StepsCompleted: ptrs.Ptr(100)})
+	w.Heartbeat(at(5*time.Minute), Heartbeat{StepsCompleted: ptrs.Ptr(200)})
+	w.Heartbeat(at(14*time.Minute), Heartbeat{StepsCompleted: ptrs.Ptr(200)})
+	w.tick(at(14 * time.Minute))
+	require.Equal(t, StateHealthy, w.Status().State)
+
+	// Heartbeats without progress don't keep the allocation alive.
+	w.Heartbeat(at(16*time.Minute), Heartbeat{StepsCompleted: ptrs.Ptr(200)})
+	w.tick(at(16 * time.Minute))
+	require.Equal(t, StateStalled, w.Status().State)
+	require.Equal(t, []string{"no steps reported past step 200 for more than 10m0s"}, r.stalls)
+
+	w.Heartbeat(at(17*time.Minute), Heartbeat{StepsCompleted: ptrs.Ptr(200)})
+	require.Equal(t, StateStalled, w.Status().State)
+	require.Zero(t, r.recoveries)
+
+	w.Heartbeat(at(18*time.Minute), Heartbeat{StepsCompleted: ptrs.Ptr(300)})
+	status := w.Status()
+	require.Equal(t, StateHealthy, status.State)
+	require.Equal(t, 300, *status.StepsCompleted)
+	require.Equal(t, at(18*time.Minute), *status.LastStepAt)
+	require.Equal(t, 1, r.recoveries)
+}
+
+func TestWatcherRestoreStall(t *testing.T) {
+	w, r := newTestWatcher(t, sproto.LivenessConfig{HeartbeatTimeout: time.Minute})
+	start := time.Now()
+
+	w.RestoreStall("no heartbeat for more than 1m0s")
+	w.tick(start.Add(time.Hour))
+	require.Equal(t, StateStalled, w.Status().State)
+	require.Empty(t, r.stalls, "restored stalls aren't reported again")
+
+	w.Heartbeat(start.Add(time.Hour), Heartbeat{})
+	require.Equal(t, StateHealthy, w.Status().State)
+	require.Equal(t, 1, r.recoveries)
+}
+
+func TestServiceIgnoresOtherAllocations(t *testing.T) {
+	taskID := model.TaskID("task")
+	Register(taskID, "task.1", sproto.LivenessConfig{}, func(string) {}, func() {})
+	defer Unregister(taskID, "task.1")
+
+	require.ErrorIs(t, RecordHeartbeat(taskID, "task.0", Heartbeat{}), ErrNotWatched)
+	require.NoError(t, RecordHeartbeat(taskID, "task.1", Heartbeat{}))
+
+	// Unregistering a previous allocation leaves the current one alone.
+	Unregister(taskID, "task.0")
+	status, ok := GetStatus(taskID)
+	require.True(t, ok)
+	require.Equal(t, StateHealthy, status.State)
+}
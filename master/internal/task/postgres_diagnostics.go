@@ -0,0 +1,32 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddAllocationDiagnostics records the diagnostics bundle of a failed allocation.
+func AddAllocationDiagnostics(ctx context.Context, diag *model.AllocationDiagnostics) error {
+	if _, err := db.Bun().NewInsert().Model(diag).Exec(ctx); err != nil {
+		return fmt.Errorf("adding diagnostics for allocation %s: %w", diag.AllocationID, err)
+	}
+	return nil
+}
+
+// AllocationDiagnosticsByTask returns the diagnostics bundles of the failed allocations of a task,
+// oldest first.
+func AllocationDiagnosticsByTask(
+	ctx context.Context, taskID model.TaskID,
+) ([]model.AllocationDiagnostics, error) {
+	diags := []model.AllocationDiagnostics{}
+	if err := db.Bun().NewSelect().Model(&diags).
+		Where("task_id = ?", taskID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting diagnostics for task %s: %w", taskID, err)
+	}
+	return diags, nil
+}
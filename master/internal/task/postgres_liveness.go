@@ -0,0 +1,61 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddStallAlert records an allocation liveness detection found stalled.
+func AddStallAlert(ctx context.Context, alert *model.StallAlert) error {
+	if _, err := db.Bun().NewInsert().Model(alert).Exec(ctx); err != nil {
+		return fmt.Errorf("adding stall alert for allocation %s: %w", alert.AllocationID, err)
+	}
+	return nil
+}
+
+// RecoverStallAlerts marks the open stall alerts of an allocation as recovered.
+func RecoverStallAlerts(ctx context.Context, allocationID model.AllocationID) error {
+	if _, err := db.Bun().NewUpdate().Model((*model.StallAlert)(nil)).
+		Set("recovered_at = NOW()").
+		Where("allocation_id = ?", allocationID).
+		Where("recovered_at IS NULL").
+		Exec(ctx); err != nil {
+		return fmt.Errorf("recovering stall alerts for allocation %s: %w", allocationID, err)
+	}
+	return nil
+}
+
+// OpenStallAlert returns the latest stall alert of an allocation that hasn't recovered, if any.
+func OpenStallAlert(ctx context.Context, allocationID model.AllocationID) (*model.StallAlert, error) {
+	var alert model.StallAlert
+	err := db.Bun().NewSelect().Model(&alert).
+		Where("allocation_id = ?", allocationID).
+		Where("recovered_at IS NULL").
+		Order("id DESC").
+		Limit(1).
+		Scan(ctx)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("getting open stall alert for allocation %s: %w", allocationID, err)
+	}
+	return &alert, nil
+}
+
+// StallAlertsByTask returns the stall alerts raised for a task, oldest first.
+func StallAlertsByTask(ctx context.Context, taskID model.TaskID) ([]model.StallAlert, error) {
+	alerts := []model.StallAlert{}
+	if err := db.Bun().NewSelect().Model(&alerts).
+		Where("task_id = ?", taskID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting stall alerts for task %s: %w", taskID, err)
+	}
+	return alerts, nil
+}
@@ -0,0 +1,31 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// AddStragglerAlert records a rank flagged by straggler detection.
+func AddStragglerAlert(ctx context.Context, alert *model.StragglerAlert) error {
+	if _, err := db.Bun().NewInsert().Model(alert).Exec(ctx); err != nil {
+		return fmt.Errorf("adding straggler alert for allocation %s: %w", alert.AllocationID, err)
+	}
+	return nil
+}
+
+// StragglerAlertsByTask returns the straggler alerts raised for a task, oldest first.
+func StragglerAlertsByTask(
+	ctx context.Context, taskID model.TaskID,
+) ([]model.StragglerAlert, error) {
+	alerts := []model.StragglerAlert{}
+	if err := db.Bun().NewSelect().Model(&alerts).
+		Where("task_id = ?", taskID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting straggler alerts for task %s: %w", taskID, err)
+	}
+	return alerts, nil
+}
@@ -2,7 +2,9 @@ package task
 
 import (
 	"fmt"
+	"net"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -50,6 +52,12 @@ type rendezvous struct {
 	resources         resourcesList
 	lastWatchTime     time.Time
 	allReadySucceeded bool
+
+	// advertised holds the addresses containers asked their peers to reach them at instead of the
+	// address of their host, such as when they are behind NAT.
+	advertised map[sproto.ResourcesID]string
+	// unreachable holds, for each container that checked, the ranks it couldn't connect to.
+	unreachable map[sproto.ResourcesID][]int
 }
 
 // newRendezvous returns a new rendezvous component.
@@ -63,7 +71,97 @@ func newRendezvous(
 		timeout:      timeout,
 		resources:    rs,
 		watchers:     map[sproto.ResourcesID]chan<- RendezvousInfoOrError{},
+		advertised:   map[sproto.ResourcesID]string{},
+		unreachable:  map[sproto.ResourcesID][]int{},
+	}
+}
+
+// advertise sets the address the peers of a container reach it at, relaying it through the master
+// for containers whose host address isn't reachable from their peers. It must be called before the
+// rendezvous completes.
+func (r *rendezvous) advertise(rID sproto.ResourcesID, addr string) error {
+	if _, ok := r.resources[rID]; !ok {
+		err := StaleResourcesError{ID: rID}
+		return apiutils.AsValidationError(err.Error())
+	} else if r.allReadySucceeded {
+		return apiutils.AsValidationError("rendezvous already completed: %s", r.allocationID)
+	}
+	if net.ParseIP(addr) == nil && !isHostname(addr) {
+		return apiutils.AsValidationError("invalid rendezvous address %q: must be an IP or hostname",
+			addr)
+	}
+	r.advertised[rID] = addr
+	return nil
+}
+
+// isHostname returns whether s is a valid DNS hostname, such as the hostname of a container.
+func isHostname(s string) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
 	}
+	return true
+}
+
+// reportUnreachable records the ranks a container couldn't connect to after the rendezvous, and
+// returns a description of the failed connections for the task logs, or "" if there were none.
+func (r *rendezvous) reportUnreachable(rID sproto.ResourcesID, ranks []int) (string, error) {
+	from, ok := r.resources[rID]
+	if !ok {
+		err := StaleResourcesError{ID: rID}
+		return "", apiutils.AsValidationError(err.Error())
+	} else if !r.allReadySucceeded {
+		return "", apiutils.AsValidationError("rendezvous has not completed: %s", r.allocationID)
+	}
+	addresses := map[int]string{}
+	for id, res := range r.resources {
+		addresses[res.Rank] = r.addressOf(id)
+	}
+	for _, rank := range ranks {
+		if _, ok := addresses[rank]; !ok {
+			return "", apiutils.AsValidationError("unknown rank %d", rank)
+		}
+	}
+
+	r.unreachable[rID] = ranks
+	if len(ranks) == 0 {
+		return "", nil
+	}
+	sorted := append([]int(nil), ranks...)
+	sort.Ints(sorted)
+	var peers []string
+	for _, rank := range sorted {
+		peers = append(peers, fmt.Sprintf("rank %d at %s", rank, addresses[rank]))
+	}
+	return fmt.Sprintf("rank %d at %s could not connect to %s; if the containers are behind NAT "+
+		"or on networks that can't reach each other, have them advertise reachable addresses",
+		from.Rank, r.addressOf(rID), strings.Join(peers, ", ")), nil
+}
+
+// addressOf returns the address the peers of a container reach it at, or "" if it isn't known.
+func (r *rendezvous) addressOf(rID sproto.ResourcesID) string {
+	if addr, ok := r.advertised[rID]; ok {
+		return addr
+	}
+	res := r.resources[rID]
+	if res == nil || res.Started == nil {
+		return ""
+	}
+	for _, addr := range res.Started.Addresses {
+		if minLocalRendezvousPort <= addr.ContainerPort && addr.ContainerPort <= maxLocalRendezvousPort {
+			return addr.HostIP
+		}
+	}
+	return ""
 }
 
 func (r *rendezvous) watch(rID sproto.ResourcesID) (RendezvousWatcher, error) {
@@ -158,12 +256,37 @@ func (r *rendezvous) checkTimeout() error {
 				"connect to master; when running on kubernetes this may happen " +
 				"because only some of the pods have been scheduled; it is possible " +
 				"that some pods will never be scheduled without adding compute " +
-				"resources or pausing / killing other experiments in the cluster",
+				"resources or pausing / killing other experiments in the cluster" +
+				r.pendingRanks(),
 		}
 	}
 	return nil
 }
 
+// pendingRanks describes the ranks the rendezvous is waiting on, for diagnosing timeouts.
+func (r *rendezvous) pendingRanks() string {
+	var notStarted, notConnected []int
+	for id, res := range r.resources {
+		if res.Started == nil {
+			notStarted = append(notStarted, res.Rank)
+		}
+		if _, ok := r.watchers[id]; !ok {
+			notConnected = append(notConnected, res.Rank)
+		}
+	}
+	sort.Ints(notStarted)
+	sort.Ints(notConnected)
+
+	var msg string
+	if len(notStarted) > 0 {
+		msg += fmt.Sprintf("; ranks whose containers have not started: %v", notStarted)
+	}
+	if len(notConnected) > 0 {
+		msg += fmt.Sprintf("; ranks that have not connected to the master: %v", notConnected)
+	}
+	return msg
+}
+
 // close closes rendezvous by letting still active watchers know they were terminated.
 func (r *rendezvous) close() {
 	if r == nil {
@@ -229,7 +352,10 @@ func (r *rendezvous) info() ([]cAddress, []string, []int32, error) {
 			}
 		}
 
-		if len(addrs) == 1 {
+		if addr, ok := r.advertised[caddr.id]; ok {
+			raddrs = append(raddrs, addr)
+			slots = append(slots, int32(caddr.slots))
+		} else if len(addrs) == 1 {
 			raddrs = append(raddrs, addrs[0].HostIP)
 			slots = append(slots, int32(caddr.slots))
 		} else {
@@ -180,8 +180,58 @@ func TestRendezvousTimeout(t *testing.T) {
 	r.resources[c1].Started = &sproto.ResourcesStarted{Addresses: addressesFromContainerID(c1)}
 	r.try()
 
-	require.ErrorContains(t, r.checkTimeout(),
-		"some containers are taking a long time")
+	err = r.checkTimeout()
+	require.ErrorContains(t, err, "some containers are taking a long time")
+	require.ErrorContains(t, err, "ranks whose containers have not started: [1]")
+	require.ErrorContains(t, err, "ranks that have not connected to the master: [1]")
+}
+
+func TestRendezvousAdvertisedAddresses(t *testing.T) {
+	res := mocks.NewResources(t)
+	res.On("Summary").Return(sproto.ResourcesSummary{
+		AgentDevices: map[aproto.ID][]device.Device{},
+	})
+	t1 := model.AllocationID(uuid.New().String())
+	c1, c2 := sproto.ResourcesID(cproto.NewID()), sproto.ResourcesID(cproto.NewID())
+	r := newRendezvous(t1, resourcesList{
+		c1: &taskmodel.ResourcesWithState{Resources: res, Rank: 0},
+		c2: &taskmodel.ResourcesWithState{Resources: res, Rank: 1},
+	}, rendezvousTimeoutDuration)
+
+	require.ErrorContains(t, r.advertise(c2, "203.0.113.7:1734"), "invalid rendezvous address")
+	require.ErrorContains(t, r.advertise(sproto.ResourcesID(cproto.NewID()), "203.0.113.7"),
+		"stale resources")
+	require.NoError(t, r.advertise(c2, "203.0.113.7"))
+
+	_, err := r.reportUnreachable(c1, nil)
+	require.ErrorContains(t, err, "rendezvous has not completed")
+
+	var ws []RendezvousWatcher
+	for _, c := range []sproto.ResourcesID{c1, c2} {
+		r.resources[c].Started = &sproto.ResourcesStarted{Addresses: addressesFromContainerID(c)}
+		w, err := r.watch(c)
+		require.NoError(t, err)
+		ws = append(ws, w)
+	}
+	require.True(t, r.ready())
+	for _, w := range ws {
+		resp := <-w.C
+		require.NoError(t, resp.Err)
+		require.Equal(t, []string{fmt.Sprintf("%s.example.com", c1), "203.0.113.7"},
+			resp.Info.Addresses)
+	}
+	require.ErrorContains(t, r.advertise(c2, "203.0.113.8"), "rendezvous already completed")
+
+	msg, err := r.reportUnreachable(c1, []int{1})
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("rank 0 at %s.example.com could not connect to rank 1 at "+
+		"203.0.113.7; if the containers are behind NAT or on networks that can't reach each "+
+		"other, have them advertise reachable addresses", c1), msg)
+	msg, err = r.reportUnreachable(c2, nil)
+	require.NoError(t, err)
+	require.Empty(t, msg)
+	_, err = r.reportUnreachable(c2, []int{5})
+	require.ErrorContains(t, err, "unknown rank 5")
 }
 
 func addressesFromContainerID(rID sproto.ResourcesID) []cproto.Address {
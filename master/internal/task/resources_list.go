@@ -6,6 +6,8 @@ import (
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task/taskmodel"
 	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 )
 
 // resourcesList tracks resourcesList with their state.
@@ -102,3 +104,37 @@ func (rs resourcesList) failed() resourcesList {
 	}
 	return nrs
 }
+
+// diagnostics summarizes how each of the resources exited, in rank order.
+func (rs resourcesList) diagnostics() []model.ResourcesDiagnostics {
+	diags := []model.ResourcesDiagnostics{}
+	for id, r := range rs {
+		diag := model.ResourcesDiagnostics{ResourcesID: string(id), Rank: r.Rank, AgentIDs: []string{}}
+		if r.Resources != nil {
+			summary := r.Summary()
+			for agentID := range summary.AgentDevices {
+				diag.AgentIDs = append(diag.AgentIDs, string(agentID))
+			}
+			sort.Strings(diag.AgentIDs)
+			if summary.ContainerID != nil {
+				diag.ContainerID = string(*summary.ContainerID)
+			}
+		}
+		if r.Exited != nil && r.Exited.Failure != nil {
+			f := r.Exited.Failure
+			diag.Failure = f.Error()
+			if f.ExitCode != nil {
+				diag.ExitCode = ptrs.Ptr(int(*f.ExitCode))
+			}
+			if f.Diagnostics != nil {
+				diag.OOMKilled = f.Diagnostics.OOMKilled
+				diag.KernelLog = f.Diagnostics.KernelLog
+			}
+		}
+		diags = append(diags, diag)
+	}
+	sort.Slice(diags, func(i, j int) bool {
+		return diags[i].Rank < diags[j].Rank
+	})
+	return diags
+}
@@ -0,0 +1,184 @@
+package straggler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/mathx"
+)
+
+// ErrDetectionDisabled indicates that an allocation does not have straggler detection enabled or
+// is not running.
+var ErrDetectionDisabled = fmt.Errorf("straggler detection is not enabled for allocation")
+
+// maxOpenWindows is how many windows behind the newest one reported are kept waiting for the rest
+// of their ranks. A rank that dies or stops reporting leaves its windows incomplete forever.
+const maxOpenWindows = 8
+
+// Timing is the mean step time one rank observed over a reporting window. Since every rank waits
+// for the slowest one in collectives such as the gradient all-reduce, step times include that wait
+// and are the same across ranks; ranks are compared by their step time without the wait.
+type Timing struct {
+	Rank            int     `json:"rank"`
+	AgentID         string  `json:"agent_id"`
+	Window          int     `json:"window"`
+	WorldSize       int     `json:"world_size"`
+	MeanSeconds     float64 `json:"mean_seconds"`
+	MeanWaitSeconds float64 `json:"mean_wait_seconds"`
+}
+
+// computeSeconds is the mean time the rank spent on its own work, outside of collectives.
+func (t Timing) computeSeconds() float64 {
+	return t.MeanSeconds - t.MeanWaitSeconds
+}
+
+// Alert describes a rank that consistently lagged behind its peers.
+type Alert struct {
+	Rank     int
+	AgentID  string
+	Slowdown float64
+	Windows  int
+}
+
+// AlertFn is called when a straggler is detected.
+type AlertFn func(Alert)
+
+// Detector compares per-rank step timings across reporting windows and flags a rank that is
+// slower than the median of its peers by more than the configured threshold for several windows
+// in a row.
+type Detector struct {
+	// Configuration.
+	cfg    sproto.StragglerDetectionConfig
+	action AlertFn
+
+	// Mutable internal state.
+	mu        sync.Mutex
+	windows   map[int]map[int]Timing
+	worldSize int
+	newest    int
+	latest    int
+	suspect   int
+	streak    int
+	fired     bool
+}
+
+// New creates a new straggler detector. The action is called at most once.
+func New(cfg sproto.StragglerDetectionConfig, action AlertFn) *Detector {
+	d := &Detector{cfg: cfg, action: action}
+	d.reset(0)
+	return d
+}
+
+// reset forgets every window, e.g. when an elastic resize changes the world size and ranks start
+// reporting for a new set of workers.
+func (d *Detector) reset(worldSize int) {
+	d.windows = make(map[int]map[int]Timing)
+	d.worldSize = worldSize
+	d.newest, d.latest = -1, -1
+	d.suspect, d.streak = -1, 0
+}
+
+// Report records a timing from one rank. Once every rank has reported for a window, the window
+// is evaluated.
+func (d *Detector) Report(t Timing) error {
+	if t.Rank < 0 || t.Rank >= t.WorldSize {
+		return fmt.Errorf("rank %d is out of range for world size %d", t.Rank, t.WorldSize)
+	}
+	if t.MeanSeconds <= 0 {
+		return fmt.Errorf("mean step time must be positive, got %v", t.MeanSeconds)
+	}
+	if t.MeanWaitSeconds < 0 || t.MeanWaitSeconds >= t.MeanSeconds {
+		return fmt.Errorf("mean wait time must be in [0, %v), got %v", t.MeanSeconds, t.MeanWaitSeconds)
+	}
+
+	alert, ok := d.report(t)
+	if ok {
+		d.action(alert)
+	}
+	return nil
+}
+
+func (d *Detector) report(t Timing) (Alert, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.fired || t.WorldSize < 2 {
+		return Alert{}, false
+	}
+	if t.WorldSize != d.worldSize {
+		d.reset(t.WorldSize)
+	}
+	if t.Window <= d.latest || t.Window <= d.newest-maxOpenWindows {
+		return Alert{}, false
+	}
+	if t.Window > d.newest {
+		d.newest = t.Window
+		for w := range d.windows {
+			if w <= d.newest-maxOpenWindows {
+				delete(d.windows, w)
+			}
+		}
+	}
+
+	ranks, ok := d.windows[t.Window]
+	if !ok {
+		ranks = make(map[int]Timing)
+		d.windows[t.Window] = ranks
+	}
+	ranks[t.Rank] = t
+	if len(ranks) < t.WorldSize {
+		return Alert{}, false
+	}
+
+	// Windows that will never complete (e.g. a rank skipped a report) are dropped.
+	for w := range d.windows {
+		if w <= t.Window {
+			delete(d.windows, w)
+		}
+	}
+	d.latest = t.Window
+
+	slowest, slowdown := evaluate(ranks)
+	switch {
+	case slowdown < d.cfg.SlowdownThreshold:
+		d.suspect, d.streak = -1, 0
+	case slowest.Rank == d.suspect:
+		d.streak++
+	default:
+		d.suspect, d.streak = slowest.Rank, 1
+	}
+	if d.streak < d.cfg.Patience {
+		return Alert{}, false
+	}
+
+	d.fired = true
+	return Alert{
+		Rank:     slowest.Rank,
+		AgentID:  slowest.AgentID,
+		Slowdown: slowdown,
+		Windows:  d.streak,
+	}, true
+}
+
+// evaluate returns the slowest rank in a window and how much slower it was than the median of
+// the other ranks.
+func evaluate(ranks map[int]Timing) (Timing, float64) {
+	var slowest Timing
+	for _, t := range ranks {
+		if t.computeSeconds() > slowest.computeSeconds() ||
+			(t.computeSeconds() == slowest.computeSeconds() && t.Rank < slowest.Rank) {
+			slowest = t
+		}
+	}
+
+	var others []float64
+	for _, t := range ranks {
+		if t.Rank != slowest.Rank {
+			others = append(others, t.computeSeconds())
+		}
+	}
+	sort.Float64s(others)
+	return slowest, slowest.computeSeconds() / mathx.Median(others)
+}
@@ -0,0 +1,153 @@
+package straggler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+)
+
+func reportWindow(t *testing.T, d *Detector, window int, means ...float64) {
+	for rank, mean := range means {
+		require.NoError(t, d.Report(Timing{
+			Rank:        rank,
+			AgentID:     "agent-" + string(rune('a'+rank)),
+			Window:      window,
+			WorldSize:   len(means),
+			MeanSeconds: mean,
+		}))
+	}
+}
+
+func TestDetectorFlagsConsistentStraggler(t *testing.T) {
+	var alerts []Alert
+	d := New(sproto.StragglerDetectionConfig{SlowdownThreshold: 1.5, Patience: 3}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+
+	reportWindow(t, d, 0, 1.0, 1.1, 2.0, 0.9)
+	reportWindow(t, d, 1, 1.0, 1.0, 2.2, 1.0)
+	require.Empty(t, alerts)
+
+	reportWindow(t, d, 2, 1.0, 1.0, 1.8, 1.0)
+	require.Len(t, alerts, 1)
+	require.Equal(t, 2, alerts[0].Rank)
+	require.Equal(t, "agent-c", alerts[0].AgentID)
+	require.Equal(t, 3, alerts[0].Windows)
+	require.InDelta(t, 1.8, alerts[0].Slowdown, 1e-9)
+
+	// The action fires at most once.
+	reportWindow(t, d, 3, 1.0, 1.0, 3.0, 1.0)
+	require.Len(t, alerts, 1)
+}
+
+func TestDetectorResetsOnRecoveryOrNewSuspect(t *testing.T) {
+	var alerts []Alert
+	d := New(sproto.StragglerDetectionConfig{SlowdownThreshold: 1.5, Patience: 2}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+
+	reportWindow(t, d, 0, 1.0, 2.0)
+	reportWindow(t, d, 1, 1.0, 1.1)
+	reportWindow(t, d, 2, 1.0, 2.0)
+	reportWindow(t, d, 3, 2.0, 1.0)
+	require.Empty(t, alerts)
+
+	reportWindow(t, d, 4, 2.0, 1.0)
+	require.Len(t, alerts, 1)
+	require.Equal(t, 0, alerts[0].Rank)
+}
+
+func TestDetectorWaitsForAllRanks(t *testing.T) {
+	var alerts []Alert
+	d := New(sproto.StragglerDetectionConfig{SlowdownThreshold: 1.5, Patience: 1}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+
+	require.NoError(t, d.Report(Timing{Rank: 1, Window: 0, WorldSize: 3, MeanSeconds: 5}))
+	require.NoError(t, d.Report(Timing{Rank: 0, Window: 0, WorldSize: 3, MeanSeconds: 1}))
+	require.Empty(t, alerts)
+
+	// Stale windows are ignored once a later window has been evaluated.
+	reportWindow(t, d, 1, 1.0, 1.0, 1.0)
+	require.NoError(t, d.Report(Timing{Rank: 2, Window: 0, WorldSize: 3, MeanSeconds: 1}))
+	require.Empty(t, alerts)
+}
+
+func TestDetectorBoundsOpenWindows(t *testing.T) {
+	var alerts []Alert
+	d := New(sproto.StragglerDetectionConfig{SlowdownThreshold: 1.5, Patience: 1}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+
+	// Rank 1 stopped reporting, so no window completes.
+	for w := 0; w < 100; w++ {
+		require.NoError(t, d.Report(Timing{Rank: 0, Window: w, WorldSize: 2, MeanSeconds: 1}))
+	}
+	require.Len(t, d.windows, maxOpenWindows)
+
+	// Reports for windows that fell too far behind are ignored.
+	require.NoError(t, d.Report(Timing{Rank: 1, Window: 0, WorldSize: 2, MeanSeconds: 5}))
+	require.Empty(t, alerts)
+	require.NoError(t, d.Report(Timing{Rank: 1, Window: 99, WorldSize: 2, MeanSeconds: 5}))
+	require.Len(t, alerts, 1)
+}
+
+func TestDetectorResetsOnWorldSizeChange(t *testing.T) {
+	var alerts []Alert
+	d := New(sproto.StragglerDetectionConfig{SlowdownThreshold: 1.5, Patience: 2}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+
+	reportWindow(t, d, 0, 1.0, 1.0, 2.0, 1.0)
+	require.NoError(t, d.Report(Timing{Rank: 0, Window: 1, WorldSize: 4, MeanSeconds: 1}))
+	require.NoError(t, d.Report(Timing{Rank: 1, Window: 1, WorldSize: 4, MeanSeconds: 1}))
+
+	// After a resize to two ranks, windows restart and the old streak and reports are dropped.
+	reportWindow(t, d, 0, 1.0, 2.0)
+	require.Empty(t, alerts)
+	require.Len(t, d.windows, 0)
+	reportWindow(t, d, 1, 1.0, 2.0)
+	require.Len(t, alerts, 1)
+	require.Equal(t, 1, alerts[0].Rank)
+	require.Equal(t, 2, alerts[0].Windows)
+}
+
+func TestDetectorComparesTimeOutsideCollectives(t *testing.T) {
+	var alerts []Alert
+	d := New(sproto.StragglerDetectionConfig{SlowdownThreshold: 1.5, Patience: 1}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+
+	// Every rank waits for rank 1 in the all-reduce, so their step times match.
+	for rank, wait := range []float64{1.0, 0.1, 1.1} {
+		require.NoError(t, d.Report(Timing{
+			Rank: rank, WorldSize: 3, MeanSeconds: 2.0, MeanWaitSeconds: wait,
+		}))
+	}
+	require.Len(t, alerts, 1)
+	require.Equal(t, 1, alerts[0].Rank)
+	require.InDelta(t, 1.9/0.95, alerts[0].Slowdown, 1e-9)
+}
+
+func TestDetectorRejectsInvalidTimings(t *testing.T) {
+	d := New(sproto.StragglerDetectionConfig{SlowdownThreshold: 1.5, Patience: 1}, func(Alert) {})
+	require.Error(t, d.Report(Timing{Rank: 2, WorldSize: 2, MeanSeconds: 1}))
+	require.Error(t, d.Report(Timing{Rank: 0, WorldSize: 2, MeanSeconds: 0}))
+	require.Error(t, d.Report(Timing{Rank: 0, WorldSize: 2, MeanSeconds: 1, MeanWaitSeconds: 1}))
+	require.Error(t, d.Report(Timing{Rank: 0, WorldSize: 2, MeanSeconds: 1, MeanWaitSeconds: -1}))
+}
+
+func TestServiceReportRequiresRegistration(t *testing.T) {
+	require.ErrorIs(t, Report("missing", Timing{}), ErrDetectionDisabled)
+
+	var alerts []Alert
+	cfg := sproto.StragglerDetectionConfig{SlowdownThreshold: 1.5, Patience: 1}
+	Register("test", cfg, func(a Alert) { alerts = append(alerts, a) })
+	defer Unregister("test")
+
+	require.NoError(t, Report("test", Timing{Rank: 0, WorldSize: 2, MeanSeconds: 1}))
+	require.NoError(t, Report("test", Timing{Rank: 1, WorldSize: 2, MeanSeconds: 3}))
+	require.Len(t, alerts, 1)
+}
@@ -0,0 +1,31 @@
+package straggler
+
+import (
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/syncx/mapx"
+)
+
+var detectors = mapx.New[string, *Detector]()
+
+// Register a detector to the default service. The action is called at most once, when a
+// straggler is detected, and can trigger until Unregister is called.
+// ID must be a globally unique identifier for the detector.
+func Register(id string, cfg sproto.StragglerDetectionConfig, action AlertFn) {
+	detectors.Store(id, New(cfg, action))
+}
+
+// Unregister removes a detector from the service.
+// ID must be a globally unique identifier for the detector.
+func Unregister(id string) {
+	detectors.Delete(id)
+}
+
+// Report records a step timing for a detector.
+// ID must be a globally unique identifier for the detector.
+func Report(id string, t Timing) error {
+	d, ok := detectors.Load(id)
+	if !ok {
+		return ErrDetectionDisabled
+	}
+	return d.Report(t)
+}
@@ -0,0 +1,140 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/segmentio/analytics-go.v3"
+
+	"github.com/determined-ai/determined/master/internal/egress"
+	"github.com/determined-ai/determined/master/pkg/config"
+)
+
+const (
+	// ExportSchemaVersion is the version of the format of exported records. It changes whenever a
+	// change to the format would break readers of older records.
+	ExportSchemaVersion = 1
+
+	exportQueueSize   = 1000
+	exportPostTimeout = 10 * time.Second
+)
+
+// ExportRecord is a telemetry event as written to a local sink.
+type ExportRecord struct {
+	SchemaVersion int       `json:"schema_version"`
+	Type          string    `json:"type"`
+	Event         string    `json:"event,omitempty"`
+	ClusterID     string    `json:"cluster_id"`
+	Timestamp     time.Time `json:"timestamp"`
+	// Properties are the properties of a track event or the traits of an identify event.
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// exportClient implements the analytics.Client interface by writing records to a local file or
+// endpoint rather than sending them to Segment.
+type exportClient struct {
+	write     func(ExportRecord) error
+	closeSink func() error
+
+	records chan ExportRecord
+	done    chan struct{}
+	close   sync.Once
+}
+
+func newExportClient(conf config.TelemetryExportConfig) (*exportClient, error) {
+	var write func(ExportRecord) error
+	closeSink := func() error { return nil }
+	switch {
+	case conf.Path != "":
+		f, err := os.OpenFile(conf.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("opening telemetry export file: %w", err)
+		}
+		enc := json.NewEncoder(f)
+		write = func(r ExportRecord) error { return enc.Encode(r) }
+		closeSink = f.Close
+	case conf.URL != "":
+		client := &http.Client{Timeout: exportPostTimeout, Transport: egress.Transport()}
+		write = func(r ExportRecord) error { return postExportRecord(client, conf.URL, r) }
+	default:
+		return nil, errors.New("no telemetry export sink is configured")
+	}
+
+	c := &exportClient{
+		write:     write,
+		closeSink: closeSink,
+		records:   make(chan ExportRecord, exportQueueSize),
+		done:      make(chan struct{}),
+	}
+	go c.run()
+	return c, nil
+}
+
+func postExportRecord(client *http.Client, url string, r ExportRecord) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body)) //nolint:noctx
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry export endpoint responded %s", resp.Status)
+	}
+	return nil
+}
+
+func (c *exportClient) run() {
+	defer close(c.done)
+	for r := range c.records {
+		if err := c.write(r); err != nil {
+			syslog.WithError(err).WithField("event", r.Event).Warn("failed to export telemetry")
+		}
+	}
+}
+
+// Enqueue implements the analytics.Client interface. Records are dropped, rather than blocking
+// the caller, while the sink is too far behind.
+func (c *exportClient) Enqueue(msg analytics.Message) error {
+	var r ExportRecord
+	switch m := msg.(type) {
+	case analytics.Identify:
+		r = ExportRecord{Type: "identify", ClusterID: m.UserId, Timestamp: m.Timestamp, Properties: m.Traits}
+	case analytics.Track:
+		r = ExportRecord{
+			Type:       "track",
+			Event:      m.Event,
+			ClusterID:  m.UserId,
+			Timestamp:  m.Timestamp,
+			Properties: m.Properties,
+		}
+	default:
+		return fmt.Errorf("messages of type %T can't be exported", msg)
+	}
+	r.SchemaVersion = ExportSchemaVersion
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+
+	select {
+	case c.records <- r:
+		return nil
+	default:
+		return errors.New("telemetry export queue is full")
+	}
+}
+
+// Close implements the io.Closer interface. It waits for queued records to be written.
+func (c *exportClient) Close() error {
+	c.close.Do(func() { close(c.records) })
+	<-c.done
+	return c.closeSink()
+}
@@ -0,0 +1,80 @@
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/segmentio/analytics-go.v3"
+
+	"github.com/determined-ai/determined/master/pkg/config"
+)
+
+func TestExportToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	client, err := newExportClient(config.TelemetryExportConfig{Path: path})
+	require.NoError(t, err)
+
+	require.NoError(t, client.Enqueue(analytics.Identify{
+		UserId: "cluster",
+		Traits: analytics.Traits{"master_version": "1.0.0"},
+	}))
+	require.NoError(t, client.Enqueue(analytics.Track{
+		UserId:     "cluster",
+		Event:      "user_created",
+		Properties: analytics.Properties{"admin": true},
+	}))
+	require.NoError(t, client.Close())
+
+	f, err := os.Open(path) //nolint:gosec
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []ExportRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r ExportRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, records, 2)
+	for _, r := range records {
+		require.Equal(t, ExportSchemaVersion, r.SchemaVersion)
+		require.Equal(t, "cluster", r.ClusterID)
+		require.False(t, r.Timestamp.IsZero())
+	}
+	require.Equal(t, "identify", records[0].Type)
+	require.Equal(t, "1.0.0", records[0].Properties["master_version"])
+	require.Equal(t, "track", records[1].Type)
+	require.Equal(t, "user_created", records[1].Event)
+	require.Equal(t, true, records[1].Properties["admin"])
+}
+
+func TestExportToEndpoint(t *testing.T) {
+	received := make(chan ExportRecord, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var record ExportRecord
+		require.NoError(t, json.Unmarshal(body, &record))
+		received <- record
+	}))
+	defer server.Close()
+
+	client, err := newExportClient(config.TelemetryExportConfig{URL: server.URL})
+	require.NoError(t, err)
+	require.NoError(t, client.Enqueue(analytics.Track{UserId: "cluster", Event: "master_tick"}))
+	require.NoError(t, client.Close())
+
+	record := <-received
+	require.Equal(t, ExportSchemaVersion, record.SchemaVersion)
+	require.Equal(t, "master_tick", record.Event)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"gopkg.in/segmentio/analytics-go.v3"
 
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/pkg/config"
 )
 
@@ -20,19 +21,33 @@ func Init(clusterID string, conf config.TelemetryConfig) {
 		return
 	}
 
-	if !conf.Enabled || conf.SegmentMasterKey == "" {
+	var client analytics.Client
+	switch {
+	case !conf.Enabled:
 		syslog.Info("telemetry reporting is disabled")
 		return
-	}
-	syslog.Info("telemetry reporting is enabled; run with --telemetry-enabled=false to disable")
-
-	client, err := analytics.NewWithConfig(
-		conf.SegmentMasterKey,
-		analytics.Config{Logger: debugLogger{}},
-	)
-	if err != nil {
-		syslog.WithError(err).Warn("failed to initialize telemetry client")
+	case conf.Export != nil:
+		syslog.Info("telemetry is exported to a local sink rather than reported")
+		c, err := newExportClient(*conf.Export)
+		if err != nil {
+			syslog.WithError(err).Warn("failed to initialize telemetry export")
+			return
+		}
+		client = c
+	case conf.SegmentMasterKey == "":
+		syslog.Info("telemetry reporting is disabled")
 		return
+	default:
+		syslog.Info("telemetry reporting is enabled; run with --telemetry-enabled=false to disable")
+		c, err := analytics.NewWithConfig(
+			conf.SegmentMasterKey,
+			analytics.Config{Logger: debugLogger{}, Transport: egress.Transport()},
+		)
+		if err != nil {
+			syslog.WithError(err).Warn("failed to initialize telemetry client")
+			return
+		}
+		client = c
 	}
 
 	telemeter, err := newTelemeter(client, clusterID)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,12 +17,15 @@ import (
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/logpattern"
+	"github.com/determined-ai/determined/master/internal/metricsexport"
 	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 
 	"github.com/determined-ai/determined/master/internal/task/tasklogger"
+	"github.com/determined-ai/determined/master/pkg/command"
 	"github.com/determined-ai/determined/master/pkg/logger"
 	"github.com/determined-ai/determined/master/pkg/mathx"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -85,13 +89,29 @@ type trial struct {
 	state model.State
 	// searcher encapsulates the searcher state of the trial.
 	searcher experiment.TrialSearcherState
-	// restarts is a failure count, it increments when the trial fails and we retry it.
+	// restarts is a failure count, it increments when the trial's code fails and we retry it.
 	restarts int
+	// infraRestarts is a failure count, it increments when what the trial runs on fails and we
+	// retry it.
+	infraRestarts int
 	// runID is a count of how many times the task container(s) have stopped and restarted, which
 	// could be due to a failure or due to normal pausing and continuing. When TrialID increments,
 	// it effectively invalidates many outstanding messages associated with the previous run.
 	runID int
 
+	// priority is the priority of the trial among the trials of its experiment.
+	priority int
+
+	// slots is the number of slots the trial is allocated with. It only differs from the
+	// configured slots per trial for elastic trials that have been resized.
+	slots int
+	// pendingResize is the resize an elastic trial's current allocation was preempted for.
+	pendingResize *model.TrialResize
+	// stopElasticMonitor stops offering the current allocation of an elastic trial a new size.
+	stopElasticMonitor context.CancelFunc
+	// elasticHysteresis holds off resizing an elastic trial too often.
+	elasticHysteresis elasticHysteresis
+
 	// a ref to the current allocation
 	allocationID *model.AllocationID
 	// a note of the user initated exit reason, if any.
@@ -167,6 +187,10 @@ func newTrial(
 		}
 	}
 
+	if t.slots, err = t.initialSlots(); err != nil {
+		return nil, fmt.Errorf("getting trial slots in prestart: %w", err)
+	}
+
 	t.logCtx = logger.MergeContexts(t.logCtx, logger.Context{
 		"trial-id":     t.id,
 		"trial-run-id": t.runID,
@@ -199,6 +223,7 @@ func (t *trial) exit(reason *model.ExitedReason) {
 
 func (t *trial) close() error {
 	t.wg.Close()
+	t.stopElasticMonitorIfRunning()
 	if !t.idSet {
 		return nil
 	}
@@ -266,6 +291,38 @@ func (t *trial) PatchRP(rp string) {
 	}
 }
 
+// SetPriority sets the priority of the trial among the trials of its experiment and applies it to
+// its current allocation, if it has one.
+func (t *trial) SetPriority(priority int, setBy model.TrialPrioritySetter) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := db.SetTrialPriority(context.TODO(), &model.TrialPriority{
+		TrialID:  t.id,
+		Priority: priority,
+		SetBy:    setBy,
+	}); err != nil {
+		return err
+	}
+	t.priority = priority
+	if t.allocationID == nil {
+		return nil
+	}
+
+	switch err := t.rm.SetPriorityInJob(sproto.SetPriorityInJob{
+		PriorityInJob: priority,
+		ResourcePool:  t.config.Resources().ResourcePool(),
+		AllocationID:  *t.allocationID,
+	}).(type) {
+	case nil:
+	case rmerrors.UnsupportedError:
+		t.syslog.WithError(err).Debug("ignoring unsupported call to set priority in job")
+	default:
+		return fmt.Errorf("setting priority of trial %d: %w", t.id, err)
+	}
+	return nil
+}
+
 func (t *trial) SetUserInitiatedEarlyExit(req experiment.UserInitiatedEarlyTrialExit) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -333,6 +390,18 @@ func (t *trial) recover() error {
 	}
 	t.runID = runID
 	t.restarts = restarts
+	infraRestarts, err := db.TrialInfraRestarts(context.TODO(), t.id)
+	if err != nil {
+		return errors.Wrap(err, "restoring old trial state")
+	}
+	t.infraRestarts = infraRestarts
+	priority, err := db.TrialPriorityByTrialID(context.TODO(), t.id)
+	if err != nil {
+		return errors.Wrap(err, "restoring old trial state")
+	}
+	if priority != nil {
+		t.priority = priority.Priority
+	}
 	return nil
 }
 
@@ -413,20 +482,30 @@ func (t *trial) maybeAllocateTask() error {
 			AllocationID:      restoredAllocation.AllocationID,
 			TaskID:            t.taskID,
 			JobID:             t.jobID,
+			TaskType:          model.TaskTypeTrial,
 			JobSubmissionTime: t.jobSubmissionTime,
 			RequestTime:       time.Now().UTC(),
 			IsUserVisible:     true,
 			Name:              name,
-			SlotsNeeded:       t.config.Resources().SlotsPerTrial(),
+			WorkspaceID:       t.taskSpec.WorkspaceID,
+			OwnerID:           t.taskSpec.OwnerID(),
+			SlotsNeeded:       t.slots,
 			ResourcePool:      t.config.Resources().ResourcePool(),
+			PriorityInJob:     t.priority,
 			FittingRequirements: sproto.FittingRequirements{
-				SingleAgent: isSingleNode,
+				SingleAgent:     isSingleNode,
+				MinGPUMemoryMiB: minGPUMemory(t.config.Resources()),
+				AgentConstraints: sproto.NewAgentConstraints(
+					t.config.Resources().AgentConstraints()),
+				ScratchGiB: localScratchGiB(t.config.Resources()),
 			},
 			Preemption: sproto.PreemptionConfig{
 				Preemptible:     true,
 				TimeoutDuration: time.Duration(preemptionTimeout) * time.Second,
 			},
-			Restore: true,
+			StragglerDetection: t.stragglerDetectionConfig(),
+			Liveness:           t.livenessConfig(),
+			Restore:            true,
 			ProxyPorts: sproto.NewProxyPortConfig(
 				tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
 
@@ -444,6 +523,7 @@ func (t *trial) maybeAllocateTask() error {
 		}
 
 		t.allocationID = &ar.AllocationID
+		t.maybeStartElasticMonitor(ar.AllocationID)
 		return nil
 	}
 
@@ -460,22 +540,32 @@ func (t *trial) maybeAllocateTask() error {
 		AllocationID:      model.AllocationID(fmt.Sprintf("%s.%d", t.taskID, t.runID)),
 		TaskID:            t.taskID,
 		JobID:             t.jobID,
+		TaskType:          model.TaskTypeTrial,
 		RequestTime:       time.Now().UTC(),
 		JobSubmissionTime: t.jobSubmissionTime,
 		IsUserVisible:     true,
 		Name:              name,
+		WorkspaceID:       t.taskSpec.WorkspaceID,
+		OwnerID:           t.taskSpec.OwnerID(),
 
-		SlotsNeeded:  t.config.Resources().SlotsPerTrial(),
-		ResourcePool: t.config.Resources().ResourcePool(),
+		SlotsNeeded:   t.slots,
+		ResourcePool:  t.config.Resources().ResourcePool(),
+		PriorityInJob: t.priority,
 		FittingRequirements: sproto.FittingRequirements{
-			SingleAgent: isSingleNode,
+			SingleAgent:     isSingleNode,
+			MinGPUMemoryMiB: minGPUMemory(t.config.Resources()),
+			AgentConstraints: sproto.NewAgentConstraints(
+				t.config.Resources().AgentConstraints()),
+			ScratchGiB: localScratchGiB(t.config.Resources()),
 		},
 
 		Preemption: sproto.PreemptionConfig{
 			Preemptible:     true,
 			TimeoutDuration: time.Duration(preemptionTimeout) * time.Second,
 		},
-		ProxyPorts: sproto.NewProxyPortConfig(tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
+		StragglerDetection: t.stragglerDetectionConfig(),
+		Liveness:           t.livenessConfig(),
+		ProxyPorts:         sproto.NewProxyPortConfig(tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
 
 		BlockedNodes: blockedNodes,
 	}
@@ -508,6 +598,7 @@ func (t *trial) maybeAllocateTask() error {
 	}
 
 	t.allocationID = &ar.AllocationID
+	t.maybeStartElasticMonitor(ar.AllocationID)
 	return nil
 }
 
@@ -521,6 +612,37 @@ func (t *trial) addTask(ctx context.Context) error {
 	})
 }
 
+// stragglerDetectionConfig returns the straggler detection configuration for the trial's
+// allocations, or nil if it is disabled.
+func (t *trial) stragglerDetectionConfig() *sproto.StragglerDetectionConfig {
+	cfg := t.config.StragglerDetection()
+	if !cfg.Enabled() {
+		return nil
+	}
+	return &sproto.StragglerDetectionConfig{
+		SlowdownThreshold: cfg.SlowdownThreshold(),
+		Patience:          cfg.Patience(),
+		ExcludeNode:       cfg.ExcludeNode(),
+	}
+}
+
+// livenessConfig returns the liveness configuration for the trial's allocations, or nil if it is
+// disabled.
+func (t *trial) livenessConfig() *sproto.LivenessConfig {
+	cfg := t.config.Liveness()
+	if !cfg.Enabled() {
+		return nil
+	}
+	l := &sproto.LivenessConfig{
+		HeartbeatTimeout: time.Duration(cfg.HeartbeatTimeout()) * time.Second,
+		Restart:          cfg.Restart(),
+	}
+	if maxTime := cfg.MaxTimeWithoutStep(); maxTime != nil {
+		l.MaxTimeWithoutStep = time.Duration(*maxTime) * time.Second
+	}
+	return l
+}
+
 func (t *trial) buildTaskSpecifier() (*tasks.TrialSpec, error) {
 	if err := t.db.UpdateTrialFields(t.id, nil, t.runID, 0); err != nil {
 		return nil, errors.Wrap(err, "failed to save trial run ID")
@@ -548,6 +670,7 @@ func (t *trial) buildTaskSpecifier() (*tasks.TrialSpec, error) {
 		TrialSeed:        t.searcher.Create.TrialSeed,
 		StepsCompleted:   stepsCompleted,
 		LatestCheckpoint: latestCheckpoint,
+		Slots:            t.slots,
 
 		Keys: t.generatedKeys,
 	}, nil
@@ -570,7 +693,12 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 	if exit.Err != nil {
 		t.syslog.WithError(exit.Err).Error("trial allocation failed")
 	}
+	allocationID := t.allocationID
 	t.allocationID = nil
+	t.stopElasticMonitorIfRunning()
+	if err := t.applyPendingResize(); err != nil {
+		t.syslog.WithError(err).Error("failed to record elastic trial resize")
+	}
 
 	prom.DisassociateJobExperiment(t.jobID, strconv.Itoa(t.experimentID), t.config.Labels())
 
@@ -618,11 +746,11 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 			InformationalReason: fmt.Sprintf(
 				"trial allocation exited with unrecoverable failure %v", exit.Err),
 		})
-	case exit.Err != nil && sproto.IsTransientSystemError(exit.Err):
+	case exit.Err != nil && sproto.ClassifyFailure(exit.Err) == "":
 		t.syslog.
 			WithError(exit.Err).
 			Errorf("trial encountered transient system error")
-	case exit.Err != nil && !sproto.IsTransientSystemError(exit.Err):
+	case exit.Err != nil:
 		// First check against log_pattern_policies retries.
 		notRetries, err := logpattern.ShouldRetry(context.TODO(), t.taskID)
 		if err != nil {
@@ -649,21 +777,17 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 			})
 		}
 
-		// If we don't have a log_pattern_policy preventing us from retrying go to normal max_restarts.
-		t.syslog.
-			WithError(exit.Err).
-			Errorf("trial failed (restart %d/%d)", t.restarts, t.config.MaxRestarts())
-		t.restarts++
-		if err := t.db.UpdateTrialFields(t.id, nil, 0, t.restarts); err != nil {
+		// If we don't have a log_pattern_policy preventing us from retrying go to the restart budget
+		// of the failure's class.
+		if exceeded, err := t.recordFailure(allocationID, exit.Err); err != nil {
 			return t.transition(model.StateWithReason{
 				State:               model.ErrorState,
 				InformationalReason: err.Error(),
 			})
-		}
-		if t.restarts > t.config.MaxRestarts() {
+		} else if exceeded != "" {
 			return t.transition(model.StateWithReason{
 				State:               model.ErrorState,
-				InformationalReason: "trial exceeded max restarts",
+				InformationalReason: exceeded,
 			})
 		}
 
@@ -707,6 +831,58 @@ func (t *trial) handleAllocationExit(exit *task.AllocationExited) error {
 	return nil
 }
 
+// recordFailure counts a failed allocation against the restart budget of its class, and returns
+// why the trial can't be restarted if the failure exceeds the budget.
+func (t *trial) recordFailure(allocationID *model.AllocationID, exitErr error) (string, error) {
+	failure := model.TrialFailure{
+		TrialID: t.id,
+		Class:   sproto.ClassifyFailure(exitErr),
+		Reason:  exitErr.Error(),
+	}
+	if allocationID != nil {
+		failure.AllocationID = *allocationID
+	}
+	var failed sproto.ResourcesFailedError
+	if errors.As(exitErr, &failed) && failed.ExitCode != nil {
+		failure.ExitCode = ptrs.Ptr(int(*failed.ExitCode))
+	}
+
+	var exceeded string
+	switch failure.Class {
+	case model.InfraFailure:
+		// Infrastructure failures are only limited when the experiment opts into a budget for them.
+		t.infraRestarts++
+		if maxInfraRestarts := t.config.MaxInfraRestarts(); maxInfraRestarts != nil {
+			t.syslog.
+				WithError(exitErr).
+				Errorf("trial failed due to infrastructure (restart %d/%d)",
+					t.infraRestarts-1, *maxInfraRestarts)
+			if t.infraRestarts > *maxInfraRestarts {
+				exceeded = "trial exceeded max infra restarts"
+			}
+		} else {
+			t.syslog.
+				WithError(exitErr).
+				Errorf("trial failed due to infrastructure (restart %d)", t.infraRestarts-1)
+		}
+	default:
+		t.syslog.
+			WithError(exitErr).
+			Errorf("trial failed (restart %d/%d)", t.restarts, t.config.MaxRestarts())
+		t.restarts++
+		if err := t.db.UpdateTrialFields(t.id, nil, 0, t.restarts); err != nil {
+			return "", err
+		}
+		if t.restarts > t.config.MaxRestarts() {
+			exceeded = "trial exceeded max restarts"
+		}
+	}
+	if err := db.AddTrialFailure(context.TODO(), &failure); err != nil {
+		return "", err
+	}
+	return exceeded, nil
+}
+
 // patchState decide if the state patch is valid. If so, we'll transition the trial.
 func (t *trial) patchState(s model.StateWithReason) error {
 	switch {
@@ -737,6 +913,11 @@ func (t *trial) transition(s model.StateWithReason) error {
 			if err := db.UpdateTrial(context.TODO(), t.id, s.State); err != nil {
 				return fmt.Errorf("updating trial with end state (%s, %s): %w", s.State, s.InformationalReason, err)
 			}
+			if model.TerminalStates[s.State] {
+				if err := metricsexport.ReportTrialEnded(context.TODO(), t.id, s.State); err != nil {
+					t.syslog.WithError(err).Error("failed to queue final metrics export")
+				}
+			}
 		}
 		t.state = s.State
 	}
@@ -840,19 +1021,40 @@ func (t *trial) maybeRestoreAllocation() (*model.Allocation, error) {
 	}
 }
 
+// minGPUMemory returns the memory each GPU of a trial must have, or 0 for any GPU.
+func minGPUMemory(resources expconf.ResourcesConfig) int {
+	if resources.MinGPUMemoryMiB() == nil {
+		return 0
+	}
+	return *resources.MinGPUMemoryMiB()
+}
+
+// localScratchGiB returns the space on its agent each container of a trial reserves for its
+// scratch volume, or 0 if it doesn't have one on the agent's local disk.
+func localScratchGiB(resources expconf.ResourcesConfig) int {
+	scratch := resources.Scratch()
+	if scratch == nil || scratch.Backing() != expconf.ScratchBackingLocal {
+		return 0
+	}
+	return scratch.SizeGiB()
+}
+
 func (t *trial) checkResourcePoolRemainingCapacity() error {
 	launchWarnings, err := t.rm.ValidateResources(
 		sproto.ValidateResourcesRequest{
 			ResourcePool: t.config.Resources().ResourcePool(),
-			Slots:        t.config.Resources().SlotsPerTrial(),
+			Slots:        t.slots,
 			IsSingleNode: t.config.Resources().IsSingleNode() != nil && *t.config.Resources().IsSingleNode(),
 			TaskID:       &t.taskID,
+
+			MinGPUMemoryMiB: minGPUMemory(t.config.Resources()),
+			Scratch:         t.config.Resources().Scratch(),
 		},
 	)
 	if err != nil {
 		return fmt.Errorf("checking resource availability: %v", err.Error())
 	}
-	if len(launchWarnings) > 0 {
+	if slices.Contains(launchWarnings, command.CurrentSlotsExceeded) {
 		msg := fmt.Sprintf(
 			"task ID %v slots requested exceeds %v resource pool capacity",
 			t.taskID,
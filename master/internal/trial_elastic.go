@@ -0,0 +1,295 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/internal/task"
+	"github.com/determined-ai/determined/master/internal/task/tasklogger"
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// elasticCheckInterval is how often a running elastic trial checks whether it should resize.
+var elasticCheckInterval = time.Minute
+
+const (
+	// elasticMinResizeInterval is the least time between two resizes of an elastic trial. Each
+	// resize preempts and checkpoints the trial, so resizing more often would spend more time
+	// restarting than training.
+	elasticMinResizeInterval = 10 * time.Minute
+	// elasticStableChecks is how many checks in a row must call for a resize in the same
+	// direction before the trial resizes, so that it doesn't follow short-lived changes in its
+	// pool's load.
+	elasticStableChecks = 3
+)
+
+// elasticAgent is an agent of an elastic trial's resource pool.
+type elasticAgent struct {
+	// free is the number of slots of the agent the trial could be allocated, including those it
+	// already runs on.
+	free int
+	size int
+}
+
+// elasticPool is what an elastic trial's resource pool can offer it.
+type elasticPool struct {
+	agents []elasticAgent
+	// queued are the slots requested by each job other than the trial's queued in the pool, in
+	// queue order.
+	queued []int
+}
+
+// elasticResizeTarget returns the number of slots an elastic trial should run with, and why. The
+// trial gives back the slots that jobs queued in its pool need and could then be scheduled with,
+// and otherwise grows into as many free slots as a new allocation of it could be placed on,
+// staying within its configured bounds.
+func elasticResizeTarget(current, minSlots, maxSlots int, pool elasticPool) (int, string) {
+	free := -current
+	for _, a := range pool.agents {
+		free += a.free
+	}
+	free = mathx.Max(free, 0)
+
+	if len(pool.queued) > 0 {
+		// Queued jobs are only given slots if what the trial can give back, with the free slots,
+		// is enough to schedule them.
+		needed := 0
+		for _, slots := range pool.queued {
+			if needed+slots <= free+current-minSlots {
+				needed += slots
+			}
+		}
+		if needed <= free {
+			return current, ""
+		}
+		target := mathx.Max(current-(needed-free), minSlots)
+		return target, fmt.Sprintf("%d slots are needed by jobs queued in pool", needed-free)
+	}
+
+	for target := mathx.Min(current+free, maxSlots); target > current; target-- {
+		if elasticFits(target, pool.agents) {
+			return target, fmt.Sprintf("%d free slots in pool", free)
+		}
+	}
+	return current, ""
+}
+
+// elasticFits returns whether an allocation of slots could be placed on agents: either on a single
+// agent, or across agents of the same size that it uses all the slots of, like the scheduler
+// places distributed allocations.
+func elasticFits(slots int, agents []elasticAgent) bool {
+	dedicated := map[int]int{}
+	for _, a := range agents {
+		if a.free >= slots {
+			return true
+		}
+		if a.size > 0 && a.free == a.size {
+			dedicated[a.size]++
+		}
+	}
+	for size, count := range dedicated {
+		if slots%size == 0 && slots/size <= count {
+			return true
+		}
+	}
+	return false
+}
+
+// elasticHysteresis holds off resizing an elastic trial until elasticStableChecks checks in a row
+// have called for a resize in the same direction, and elasticMinResizeInterval has passed since
+// its last resize.
+type elasticHysteresis struct {
+	lastResize time.Time
+	// checks counts the checks in a row that called for growing, if positive, or shrinking, if
+	// negative.
+	checks int
+}
+
+// ready records a check that called for resizing from current to target slots, and returns
+// whether the trial should resize now.
+func (h *elasticHysteresis) ready(now time.Time, current, target int) bool {
+	switch {
+	case target > current && h.checks >= 0:
+		h.checks++
+	case target > current:
+		h.checks = 1
+	case target < current && h.checks <= 0:
+		h.checks--
+	case target < current:
+		h.checks = -1
+	default:
+		h.checks = 0
+	}
+	stable := h.checks >= elasticStableChecks || h.checks <= -elasticStableChecks
+	return stable && now.Sub(h.lastResize) >= elasticMinResizeInterval
+}
+
+// resized records a resize.
+func (h *elasticHysteresis) resized(at time.Time) {
+	h.lastResize = at
+	h.checks = 0
+}
+
+// initialSlots returns the number of slots the trial should next be allocated with, which for
+// elastic trials is the size it was last resized to.
+func (t *trial) initialSlots() (int, error) {
+	slots := t.config.Resources().SlotsPerTrial()
+	elastic := t.config.Resources().Elastic()
+	if elastic == nil {
+		return slots, nil
+	}
+	resizes, err := db.TrialResizesByTrialID(context.TODO(), t.id)
+	if err != nil {
+		return 0, err
+	}
+	if len(resizes) > 0 {
+		slots = resizes[len(resizes)-1].ToSlots
+		t.elasticHysteresis.resized(resizes[len(resizes)-1].CreatedAt)
+	}
+	return mathx.Clamp(elastic.MinSlots(), slots, elastic.MaxSlots()), nil
+}
+
+// maybeStartElasticMonitor periodically offers an elastic trial's allocation a new size until
+// the allocation exits. The monitor does not hold up closing the trial; it only acts while the
+// allocation it was started for is the trial's current allocation.
+func (t *trial) maybeStartElasticMonitor(allocationID model.AllocationID) {
+	if t.config.Resources().Elastic() == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.stopElasticMonitor = cancel
+	go func() {
+		ticker := time.NewTicker(elasticCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				t.checkElasticResize(allocationID)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (t *trial) stopElasticMonitorIfRunning() {
+	if t.stopElasticMonitor != nil {
+		t.stopElasticMonitor()
+		t.stopElasticMonitor = nil
+	}
+}
+
+// checkElasticResize compares the trial's size against its pool's free slots and queue and, if
+// it should be resized, preempts the allocation. Preemption acts as the resize barrier: every rank
+// checkpoints and exits, and the trial is rescheduled at the new size from that checkpoint.
+func (t *trial) checkElasticResize(allocationID model.AllocationID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elastic := t.config.Resources().Elastic()
+	if elastic == nil || t.allocationID == nil || *t.allocationID != allocationID ||
+		t.pendingResize != nil || t.state != model.ActiveState {
+		return
+	}
+
+	pool := t.config.Resources().ResourcePool()
+	capacity, err := t.poolElasticCapacity(pool, allocationID)
+	if err != nil {
+		t.syslog.WithError(err).Warn("failed to check pool capacity for elastic trial")
+		return
+	}
+	target, reason := elasticResizeTarget(t.slots, elastic.MinSlots(), elastic.MaxSlots(), capacity)
+	if !t.elasticHysteresis.ready(time.Now(), t.slots, target) {
+		return
+	}
+
+	reason += " " + pool
+	t.pendingResize = &model.TrialResize{
+		TrialID:   t.id,
+		FromSlots: t.slots,
+		ToSlots:   target,
+		Reason:    reason,
+	}
+	msg := fmt.Sprintf("resizing elastic trial from %d to %d slots (%s)", t.slots, target, reason)
+	t.syslog.Info(msg)
+	tasklogger.Insert(tasklogger.CreateLogFromMaster(t.taskID, model.LogLevelInfo, msg))
+	if err := task.DefaultService.Signal(allocationID, task.TerminateAllocation, msg); err != nil {
+		t.syslog.WithError(err).Warn("could not preempt allocation to resize elastic trial")
+		t.pendingResize = nil
+	}
+}
+
+// poolElasticCapacity returns the agents of a resource pool that an elastic trial's allocation
+// could be placed on, with the slots it runs on counted as free, and the jobs queued in the pool.
+func (t *trial) poolElasticCapacity(
+	pool string, allocationID model.AllocationID,
+) (elasticPool, error) {
+	var capacity elasticPool
+	jobs, err := t.rm.GetJobQ(rm.ResourcePoolName(pool))
+	if err != nil {
+		return capacity, fmt.Errorf("getting job queue: %w", err)
+	}
+	queued := make([]*sproto.RMJobInfo, 0, len(jobs))
+	for jobID, info := range jobs {
+		if jobID != t.jobID && info.State == sproto.SchedulingStateQueued {
+			queued = append(queued, info)
+		}
+	}
+	sort.Slice(queued, func(i, j int) bool { return queued[i].JobsAhead < queued[j].JobsAhead })
+	for _, info := range queued {
+		capacity.queued = append(capacity.queued, info.RequestedSlots)
+	}
+
+	allocations, err := t.rm.GetAllocationSummaries()
+	if err != nil {
+		return capacity, fmt.Errorf("getting allocations: %w", err)
+	}
+	own := map[string]int{}
+	for _, r := range allocations[allocationID].Resources {
+		for agentID, devices := range r.AgentDevices {
+			own[string(agentID)] += len(devices)
+		}
+	}
+
+	resp, err := t.rm.GetAgents()
+	if err != nil {
+		return capacity, fmt.Errorf("getting agents: %w", err)
+	}
+	for _, a := range resp.Agents {
+		if !a.Enabled || a.Draining || !slices.Contains(a.ResourcePools, pool) {
+			continue
+		}
+		agent := elasticAgent{free: own[a.Id]}
+		for _, slot := range a.Slots {
+			if !slot.Enabled || slot.Draining {
+				continue
+			}
+			agent.size++
+			if slot.Container == nil {
+				agent.free++
+			}
+		}
+		capacity.agents = append(capacity.agents, agent)
+	}
+	return capacity, nil
+}
+
+// applyPendingResize applies and records a resize decided while the trial's last allocation was
+// running, so that the next allocation is requested with the new size.
+func (t *trial) applyPendingResize() error {
+	if t.pendingResize == nil {
+		return nil
+	}
+	resize := t.pendingResize
+	t.pendingResize = nil
+	t.slots = resize.ToSlots
+	t.elasticHysteresis.resized(time.Now())
+	return db.AddTrialResize(context.TODO(), resize)
+}
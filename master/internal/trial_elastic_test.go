@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticResizeTarget(t *testing.T) {
+	// Four agents of 4 slots; the trial runs on the first two.
+	agents := func(free ...int) []elasticAgent {
+		var out []elasticAgent
+		for _, f := range free {
+			out = append(out, elasticAgent{free: f, size: 4})
+		}
+		return out
+	}
+	cases := []struct {
+		name     string
+		current  int
+		pool     elasticPool
+		expected int
+	}{
+		{"grows into a free agent", 4, elasticPool{agents: agents(4, 4, 4, 0)}, 8},
+		{"grows up to max", 4, elasticPool{agents: agents(4, 4, 4, 4)}, 8},
+		{"stays at max", 8, elasticPool{agents: agents(4, 4, 4, 0)}, 8},
+		{"stays without free slots", 4, elasticPool{agents: agents(4, 0, 0, 0)}, 4},
+		{
+			"doesn't grow into slots it can't be placed on", 4,
+			elasticPool{agents: agents(4, 2, 2, 0)}, 4,
+		},
+		{
+			"shrinks by what queued jobs need", 6,
+			elasticPool{agents: agents(4, 2, 1, 0), queued: []int{2}}, 5,
+		},
+		{
+			"shrinks to min at most", 6,
+			elasticPool{agents: agents(4, 2, 0, 0), queued: []int{4}}, 2,
+		},
+		{
+			"skips queued jobs it can't make room for", 6,
+			elasticPool{agents: agents(4, 2, 0, 0), queued: []int{16, 1}}, 5,
+		},
+		{
+			"doesn't shrink for queued jobs that fit already", 4,
+			elasticPool{agents: agents(4, 0, 2, 0), queued: []int{2}}, 4,
+		},
+		{
+			"doesn't grow while jobs are queued", 4,
+			elasticPool{agents: agents(4, 4, 4, 0), queued: []int{16}}, 4,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			target, _ := elasticResizeTarget(tc.current, 2, 8, tc.pool)
+			require.Equal(t, tc.expected, target)
+		})
+	}
+}
+
+func TestElasticFits(t *testing.T) {
+	agents := []elasticAgent{{free: 3, size: 4}, {free: 4, size: 4}, {free: 4, size: 4}}
+	require.True(t, elasticFits(3, agents))
+	require.True(t, elasticFits(8, agents))
+	require.False(t, elasticFits(6, agents), "6 slots span agents without using all of them")
+	require.False(t, elasticFits(12, agents))
+}
+
+func TestElasticHysteresis(t *testing.T) {
+	start := time.Now()
+	h := elasticHysteresis{}
+	h.resized(start)
+
+	// A resize needs elasticStableChecks checks in a row calling for it.
+	now := start.Add(elasticMinResizeInterval)
+	require.False(t, h.ready(now, 4, 8))
+	require.False(t, h.ready(now, 4, 8))
+	require.False(t, h.ready(now, 4, 2), "a change of direction starts over")
+	require.False(t, h.ready(now, 4, 4), "so does a check calling for no resize")
+	for i := 0; i < elasticStableChecks-1; i++ {
+		require.False(t, h.ready(now, 4, 8))
+	}
+	require.True(t, h.ready(now, 4, 8))
+
+	// And at least elasticMinResizeInterval since the last one.
+	h.resized(now)
+	for i := 0; i < elasticStableChecks; i++ {
+		require.False(t, h.ready(now.Add(time.Minute), 8, 4))
+	}
+	require.True(t, h.ready(now.Add(elasticMinResizeInterval), 8, 4))
+}
@@ -21,6 +21,7 @@ import (
 	internaldb "github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/mocks/allocationmocks"
+	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/pkg/etc"
 	detLogger "github.com/determined-ai/determined/master/pkg/logger"
@@ -105,6 +106,83 @@ func TestTrialRestarts(t *testing.T) {
 	require.True(t, model.TerminalStates[tr.state])
 }
 
+func TestTrialInfraRestarts(t *testing.T) {
+	_, tr, _, done := setup(t)
+	tr.config.SetMaxInfraRestarts(ptrs.Ptr(2))
+	require.NoError(t, tr.PatchState(
+		model.StateWithReason{State: model.ActiveState}))
+	require.NoError(t, tr.PatchSearcherState(experiment.TrialSearcherState{
+		Create:                 searcher.Create{},
+		EarlyStoppedBySearcher: false,
+		EarlyExitedByUserCode:  false,
+	}))
+
+	// Aborts aren't failures.
+	tr.AllocationExitedCallback(&task.AllocationExited{
+		Err: sproto.ResourcesFailedError{FailureType: sproto.ResourcesAborted},
+	})
+	require.Equal(t, 0, tr.infraRestarts)
+
+	// Failures of the trial's code and of what it runs on have separate budgets.
+	tr.AllocationExitedCallback(&task.AllocationExited{
+		Err: sproto.ResourcesFailedError{
+			FailureType: sproto.ResourcesFailed,
+			ExitCode:    ptrs.Ptr(sproto.ExitCode(1)),
+		},
+	})
+	require.Equal(t, 1, tr.restarts)
+	maxInfraRestarts := *tr.config.MaxInfraRestarts()
+	for i := 0; i <= maxInfraRestarts; i++ {
+		require.NotNil(t, tr.allocationID)
+		require.Equal(t, i, tr.infraRestarts)
+		tr.AllocationExitedCallback(&task.AllocationExited{
+			Err: sproto.ResourcesFailedError{FailureType: sproto.AgentFailed},
+		})
+	}
+	require.Equal(t, 1, tr.restarts)
+	select {
+	case <-done: // success
+	case <-time.After(5 * time.Second):
+		require.Error(t, fmt.Errorf("timed out waiting for trial to terminate"))
+	}
+	require.Equal(t, model.ErrorState, tr.state)
+
+	failures, err := internaldb.TrialFailuresByTrialID(context.TODO(), tr.id)
+	require.NoError(t, err)
+	require.Len(t, failures, maxInfraRestarts+2)
+	require.Equal(t, model.UserFailure, failures[0].Class)
+	require.Equal(t, 1, *failures[0].ExitCode)
+	require.Equal(t, model.InfraFailure, failures[1].Class)
+	require.Nil(t, failures[1].ExitCode)
+
+	infraRestarts, err := internaldb.TrialInfraRestarts(context.TODO(), tr.id)
+	require.NoError(t, err)
+	require.Equal(t, maxInfraRestarts+1, infraRestarts)
+}
+
+func TestTrialInfraRestartsUnlimitedByDefault(t *testing.T) {
+	_, tr, _, _ := setup(t)
+	require.Nil(t, tr.config.MaxInfraRestarts())
+	require.NoError(t, tr.PatchState(
+		model.StateWithReason{State: model.ActiveState}))
+	require.NoError(t, tr.PatchSearcherState(experiment.TrialSearcherState{
+		Create:                 searcher.Create{},
+		EarlyStoppedBySearcher: false,
+		EarlyExitedByUserCode:  false,
+	}))
+
+	// Lost agents and restore failures never fail a trial without a budget for them.
+	for i := 0; i < 20; i++ {
+		require.NotNil(t, tr.allocationID)
+		tr.AllocationExitedCallback(&task.AllocationExited{
+			Err: sproto.ResourcesFailedError{FailureType: sproto.AgentFailed},
+		})
+	}
+	require.Equal(t, 20, tr.infraRestarts)
+	require.Equal(t, 0, tr.restarts)
+	require.Equal(t, model.ActiveState, tr.state)
+}
+
 func setup(t *testing.T) (
 	*internaldb.PgDB,
 	*trial,
@@ -0,0 +1,30 @@
+package trialcompare
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// FinalValues returns the value of a validation metric in the latest validation of each of the
+// trials, ordered by trial ID. Trials that don't exist are left out.
+func FinalValues(ctx context.Context, metricName string, trialIDs []int) ([]TrialValue, error) {
+	values := []TrialValue{}
+	if err := db.Bun().NewSelect().
+		TableExpr("trials AS t").
+		ColumnExpr("t.id AS trial_id").
+		ColumnExpr("t.experiment_id").
+		ColumnExpr(`CASE WHEN jsonb_typeof(v.metrics->'validation_metrics'->?) = 'number'
+			THEN (v.metrics->'validation_metrics'->>?)::float8 END AS value`,
+			metricName, metricName).
+		Join("LEFT JOIN validations AS v ON v.id = t.latest_validation_id").
+		Where("t.id IN (?)", bun.In(trialIDs)).
+		Order("t.id").
+		Scan(ctx, &values); err != nil {
+		return nil, fmt.Errorf("getting final %s of trials: %w", metricName, err)
+	}
+	return values, nil
+}
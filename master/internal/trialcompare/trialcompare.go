@@ -0,0 +1,231 @@
+// Package trialcompare compares the final validation metrics of two groups of trials with
+// summary statistics and significance tests, so teams can judge whether a change to their
+// experiment configuration actually made a difference.
+package trialcompare
+
+import (
+	"math"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+)
+
+// MaxTrials is the most trials a group may have.
+const MaxTrials = 10000
+
+// TrialValue is the final value of the compared metric of a trial, if it reported one.
+type TrialValue struct {
+	TrialID      int      `bun:"trial_id"`
+	ExperimentID int      `bun:"experiment_id"`
+	Value        *float64 `bun:"value"`
+}
+
+// Summary summarizes the metric values of a group of trials.
+type Summary struct {
+	TrialIDs []int `json:"trial_ids"`
+	// MissingTrialIDs are the trials of the group that haven't reported the metric, which are left
+	// out of the comparison.
+	MissingTrialIDs []int    `json:"missing_trial_ids"`
+	Count           int      `json:"count"`
+	Mean            *float64 `json:"mean"`
+	// StdDev is the sample standard deviation; it is unset for fewer than two values.
+	StdDev *float64 `json:"std_dev"`
+	Min    *float64 `json:"min"`
+	Median *float64 `json:"median"`
+	Max    *float64 `json:"max"`
+}
+
+// TTest is the result of Welch's t-test, which doesn't assume the groups have equal variances.
+type TTest struct {
+	Statistic        float64 `json:"statistic"`
+	DegreesOfFreedom float64 `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+}
+
+// MannWhitneyU is the result of the Mann-Whitney U test, which compares the ranks of the values
+// rather than assuming they are normally distributed. The p-value uses the normal approximation,
+// corrected for ties.
+type MannWhitneyU struct {
+	// Statistic is U for the first group.
+	Statistic float64 `json:"statistic"`
+	Z         float64 `json:"z"`
+	PValue    float64 `json:"p_value"`
+}
+
+// Comparison compares a metric between two groups of trials. The tests are two-sided, and unset
+// when a group has too few values for them.
+type Comparison struct {
+	MetricName string  `json:"metric_name"`
+	GroupA     Summary `json:"group_a"`
+	GroupB     Summary `json:"group_b"`
+	// MeanDifference is the mean of group B minus the mean of group A.
+	MeanDifference *float64      `json:"mean_difference"`
+	TTest          *TTest        `json:"t_test"`
+	MannWhitneyU   *MannWhitneyU `json:"mann_whitney_u"`
+}
+
+// Compare compares the values of the metric of two groups of trials.
+func Compare(metricName string, groupA, groupB []TrialValue) Comparison {
+	a, sa := summarize(groupA)
+	b, sb := summarize(groupB)
+	c := Comparison{MetricName: metricName, GroupA: sa, GroupB: sb}
+	if len(a) > 0 && len(b) > 0 {
+		diff := *sb.Mean - *sa.Mean
+		c.MeanDifference = &diff
+		c.MannWhitneyU = mannWhitneyU(a, b)
+	}
+	if len(a) > 1 && len(b) > 1 {
+		c.TTest = welchTTest(a, b)
+	}
+	return c
+}
+
+func summarize(trials []TrialValue) ([]float64, Summary) {
+	s := Summary{TrialIDs: []int{}, MissingTrialIDs: []int{}}
+	var values []float64
+	for _, t := range trials {
+		if t.Value == nil || math.IsNaN(*t.Value) || math.IsInf(*t.Value, 0) {
+			s.MissingTrialIDs = append(s.MissingTrialIDs, t.TrialID)
+			continue
+		}
+		s.TrialIDs = append(s.TrialIDs, t.TrialID)
+		values = append(values, *t.Value)
+	}
+	s.Count = len(values)
+	if len(values) == 0 {
+		return values, s
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	m := mathx.Mean(values)
+	median := mathx.Median(sorted)
+	s.Mean, s.Min, s.Median, s.Max = &m, &sorted[0], &median, &sorted[len(sorted)-1]
+	if len(values) > 1 {
+		sd := math.Sqrt(mathx.Variance(values, m))
+		s.StdDev = &sd
+	}
+	return values, s
+}
+
+// welchTTest returns the result of Welch's t-test, or nil if both groups have no variance.
+func welchTTest(a, b []float64) *TTest {
+	na, nb := float64(len(a)), float64(len(b))
+	ma, mb := mathx.Mean(a), mathx.Mean(b)
+	qa, qb := mathx.Variance(a, ma)/na, mathx.Variance(b, mb)/nb
+	if qa+qb == 0 {
+		return nil
+	}
+	t := (ma - mb) / math.Sqrt(qa+qb)
+	df := (qa + qb) * (qa + qb) / (qa*qa/(na-1) + qb*qb/(nb-1))
+	return &TTest{
+		Statistic:        t,
+		DegreesOfFreedom: df,
+		PValue:           regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5),
+	}
+}
+
+// mannWhitneyU returns the result of the Mann-Whitney U test.
+func mannWhitneyU(a, b []float64) *MannWhitneyU {
+	type ranked struct {
+		value  float64
+		groupA bool
+	}
+	all := make([]ranked, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, ranked{v, true})
+	}
+	for _, v := range b {
+		all = append(all, ranked{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Tied values share the average of their ranks.
+	var rankSumA, tieTerm float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].groupA {
+				rankSumA += rank
+			}
+		}
+		ties := float64(j - i)
+		tieTerm += ties*ties*ties - ties
+		i = j
+	}
+
+	na, nb, n := float64(len(a)), float64(len(b)), float64(len(all))
+	u := rankSumA - na*(na+1)/2
+	res := &MannWhitneyU{Statistic: u, PValue: 1}
+	if n < 2 {
+		return res
+	}
+	sigma := math.Sqrt(na * nb / 12 * ((n + 1) - tieTerm/(n*(n-1))))
+	if sigma == 0 {
+		return res
+	}
+	// The continuity correction moves U half a step towards its mean.
+	d := u - na*nb/2
+	d -= math.Copysign(math.Min(0.5, math.Abs(d)), d)
+	res.Z = d / sigma
+	res.PValue = math.Erfc(math.Abs(res.Z) / math.Sqrt2)
+	return res
+}
+
+// regularizedIncompleteBeta returns I_x(a, b), evaluated with the continued fraction of Numerical
+// Recipes, 6.4.
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly for x below the mean of the distribution.
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+	clampTiny := func(v float64) float64 {
+		if math.Abs(v) < tiny {
+			return tiny
+		}
+		return v
+	}
+	c, d := 1.0, 1/clampTiny(1-(a+b)*x/(a+1))
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		// Even step.
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 / clampTiny(1+num*d)
+		c = clampTiny(1 + num/c)
+		h *= d * c
+		// Odd step.
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 / clampTiny(1+num*d)
+		c = clampTiny(1 + num/c)
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,92 @@
+package trialcompare
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func trialValues(firstID int, values ...float64) []TrialValue {
+	var trials []TrialValue
+	for i, v := range values {
+		trials = append(trials, TrialValue{TrialID: firstID + i, Value: ptrs.Ptr(v)})
+	}
+	return trials
+}
+
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	for _, x := range []float64{0.1, 0.5, 0.9} {
+		require.InDelta(t, x, regularizedIncompleteBeta(x, 1, 1), 1e-12)
+		require.InDelta(t, math.Pow(x, 3), regularizedIncompleteBeta(x, 3, 1), 1e-12)
+	}
+	// The two-sided p-value of the 0.05 critical value of Student's t with 10 degrees of freedom.
+	const tCrit, df = 2.228139, 10.0
+	require.InDelta(t, 0.05, regularizedIncompleteBeta(df/(df+tCrit*tCrit), df/2, 0.5), 1e-6)
+}
+
+func TestWelchTTest(t *testing.T) {
+	// The first example of https://en.wikipedia.org/wiki/Welch%27s_t-test.
+	a := []float64{
+		27.5, 21.0, 19.0, 23.6, 17.0, 17.9, 16.9, 20.1, 21.9, 22.6, 23.1, 19.6, 19.0, 21.7, 21.4,
+	}
+	b := []float64{
+		27.1, 22.0, 20.8, 23.4, 23.4, 23.5, 25.8, 22.0, 24.8, 20.2, 21.9, 22.1, 22.9, 20.5, 24.4,
+	}
+	res := welchTTest(a, b)
+	require.InDelta(t, -2.46, res.Statistic, 0.005)
+	require.InDelta(t, 24.99, res.DegreesOfFreedom, 0.005)
+	require.InDelta(t, 0.021, res.PValue, 0.0005)
+
+	require.Nil(t, welchTTest([]float64{1, 1}, []float64{2, 2}))
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	res := mannWhitneyU([]float64{1, 2, 3}, []float64{4, 5, 6})
+	require.InDelta(t, 0, res.Statistic, 1e-12)
+	require.InDelta(t, -1.745743, res.Z, 1e-6)
+	require.InDelta(t, 0.080856, res.PValue, 1e-6)
+
+	// Ties share ranks and shrink the variance.
+	res = mannWhitneyU([]float64{1, 2, 2}, []float64{2, 3, 4})
+	require.InDelta(t, 1, res.Statistic, 1e-12)
+	require.InDelta(t, -1.391217, res.Z, 1e-6)
+	require.InDelta(t, 0.164160, res.PValue, 1e-6)
+
+	res = mannWhitneyU([]float64{1, 1}, []float64{1})
+	require.Equal(t, 1.0, res.PValue)
+}
+
+func TestCompare(t *testing.T) {
+	groupA := append(trialValues(1, 0.70, 0.72, 0.71, 0.73),
+		TrialValue{TrialID: 5}, TrialValue{TrialID: 6, Value: ptrs.Ptr(math.NaN())})
+	groupB := trialValues(10, 0.75, 0.77, 0.76)
+
+	c := Compare("accuracy", groupA, groupB)
+	require.Equal(t, "accuracy", c.MetricName)
+	require.Equal(t, []int{1, 2, 3, 4}, c.GroupA.TrialIDs)
+	require.Equal(t, []int{5, 6}, c.GroupA.MissingTrialIDs)
+	require.Equal(t, 4, c.GroupA.Count)
+	require.InDelta(t, 0.715, *c.GroupA.Mean, 1e-12)
+	require.InDelta(t, 0.715, *c.GroupA.Median, 1e-12)
+	require.Equal(t, 0.70, *c.GroupA.Min)
+	require.Equal(t, 0.73, *c.GroupA.Max)
+	require.InDelta(t, math.Sqrt(0.0005/3), *c.GroupA.StdDev, 1e-12)
+	require.InDelta(t, 0.045, *c.MeanDifference, 1e-12)
+	require.NotNil(t, c.TTest)
+	require.Less(t, c.TTest.PValue, 0.01)
+	require.NotNil(t, c.MannWhitneyU)
+
+	// With a single value, there is no variance to test against.
+	c = Compare("accuracy", trialValues(1, 0.7), groupB)
+	require.Nil(t, c.GroupA.StdDev)
+	require.Nil(t, c.TTest)
+	require.NotNil(t, c.MannWhitneyU)
+
+	c = Compare("accuracy", []TrialValue{{TrialID: 1}}, groupB)
+	require.Nil(t, c.GroupA.Mean)
+	require.Nil(t, c.MeanDifference)
+	require.Nil(t, c.MannWhitneyU)
+}
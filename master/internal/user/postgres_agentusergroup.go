@@ -30,6 +30,24 @@ func getAgentUserGroupFromUser(
 	}
 }
 
+// mappedAgentUserGroup returns the agent user the master config maps a user who isn't linked to
+// one to, or nil if it doesn't map them.
+func mappedAgentUserGroup(ctx context.Context, userID model.UserID) (*model.AgentUserGroup, error) {
+	mapping := config.GetMasterConfig().Security.AgentUserGroupMapping
+	if len(mapping.Users) == 0 && !mapping.LookupHostUsers {
+		return nil, nil
+	}
+	u, err := ByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user %d to map to an agent user: %w", userID, err)
+	}
+	aug, err := mapping.AgentUserGroup(u.Username)
+	if err != nil {
+		return nil, fmt.Errorf("mapping user %s to an agent user: %w", u.Username, err)
+	}
+	return aug, nil
+}
+
 type optionalAgentUserGroup struct {
 	User *string
 	UID  *int
@@ -70,6 +88,11 @@ func GetAgentUserGroup(
 		return nil, fmt.Errorf("failed to get agent user group from user: %w", err)
 	}
 
+	if userAug == nil {
+		if userAug, err = mappedAgentUserGroup(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
 	if userAug == nil {
 		userAug = &config.GetMasterConfig().Security.DefaultTask
 	}
@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -25,6 +26,16 @@ const (
 	SessionDuration = 7 * 24 * time.Hour
 	// PersonalGroupPostfix is the system postfix appended to the username of all personal groups.
 	PersonalGroupPostfix = "DeterminedPersonalGroup"
+
+	// MaxSettingKeyLength is the length limit of the key and the storage path of a user setting.
+	MaxSettingKeyLength = 256
+	// MaxSettingValueBytes is the size limit of the value of a user setting.
+	MaxSettingValueBytes = 256 << 10
+	// MaxSettings is how many settings a user can have. The WebUI saves settings for each page of
+	// experiments and trials a user opens, so this is much more than one page needs.
+	MaxSettings = 10000
+	// MaxSettingsTotalBytes is the limit of the total size of the values of a user's settings.
+	MaxSettingsTotalBytes = 16 << 20
 )
 
 // ErrRemoteUserTokenExpired notifies that the remote user's token has expired.
@@ -320,28 +331,164 @@ func UpdateUsername(ctx context.Context, userID *model.UserID, newUsername strin
 	return err
 }
 
-// UpdateUserSetting updates user setting.
+// ValidateUserSetting returns an InvalidArgument error if a setting can't be saved as it is. An
+// empty value is valid and deletes the setting.
+func ValidateUserSetting(setting *model.UserWebSetting) error {
+	switch {
+	case setting.Key == "":
+		return status.Error(codes.InvalidArgument, "setting key must be set")
+	case len(setting.Key) > MaxSettingKeyLength || len(setting.StoragePath) > MaxSettingKeyLength:
+		return status.Errorf(codes.InvalidArgument,
+			"setting key and storage path must be at most %d characters", MaxSettingKeyLength)
+	case len(setting.Value) > MaxSettingValueBytes:
+		return status.Errorf(codes.InvalidArgument,
+			"value of setting %s is %d bytes, more than the limit of %d bytes",
+			setting.Key, len(setting.Value), MaxSettingValueBytes)
+	case setting.Value != "" && !json.Valid([]byte(setting.Value)):
+		return status.Errorf(codes.InvalidArgument, "value of setting %s isn't valid JSON", setting.Key)
+	}
+	return nil
+}
+
+// UpdateUserSetting updates user setting. It returns a ResourceExhausted error, without saving any
+// settings, if a user would have more or larger settings than the limits allow.
 func UpdateUserSetting(ctx context.Context, settings []*model.UserWebSetting) error {
 	return db.Bun().RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
-		var err error
+		userIDs := make([]model.UserID, 0, 1)
 		for _, setting := range settings {
-			if len(setting.Value) == 0 {
-				_, err = tx.NewDelete().
-					Model(setting).
-					Where("user_id = ?", setting.UserID).
-					Where("storage_path = ?", setting.StoragePath).
-					Where("key = ?", setting.Key).Exec(ctx)
-			} else {
-				_, err = tx.NewInsert().
-					Model(setting).
-					On("CONFLICT (user_id, key, storage_path) DO UPDATE").
-					Set("value = EXCLUDED.value").Exec(ctx)
+			if !slices.Contains(userIDs, setting.UserID) {
+				userIDs = append(userIDs, setting.UserID)
 			}
 		}
-		return err
+		slices.Sort(userIDs)
+
+		before := make([]userSettingUsage, len(userIDs))
+		for i, userID := range userIDs {
+			usage, err := lockUserSettings(ctx, tx, userID)
+			if err != nil {
+				return err
+			}
+			before[i] = usage
+		}
+		if err := upsertUserSettings(ctx, tx, settings); err != nil {
+			return err
+		}
+		for i, userID := range userIDs {
+			if err := checkUserSettingLimits(ctx, tx, userID, before[i]); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
+// ImportUserSetting saves imported settings for a user. If replace is set, it deletes the user's
+// settings that aren't imported. Like UpdateUserSetting, it changes nothing if the user would
+// exceed the limits.
+func ImportUserSetting(
+	ctx context.Context, userID model.UserID, settings []*model.UserWebSetting, replace bool,
+) error {
+	return db.Bun().RunInTx(ctx, &sql.TxOptions{}, func(ctx context.Context, tx bun.Tx) error {
+		before, err := lockUserSettings(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if replace {
+			if _, err = tx.NewDelete().
+				Model(&model.UserWebSetting{}).
+				Where("user_id = ?", userID).
+				Exec(ctx); err != nil {
+				return fmt.Errorf("deleting settings of user %d: %w", userID, err)
+			}
+		}
+		for _, setting := range settings {
+			setting.UserID = userID
+		}
+		if err = upsertUserSettings(ctx, tx, settings); err != nil {
+			return err
+		}
+		return checkUserSettingLimits(ctx, tx, userID, before)
+	})
+}
+
+// userSettingUsage is how many settings a user has and the total size of their values.
+type userSettingUsage struct {
+	Count      int `bun:"count"`
+	TotalBytes int `bun:"total_bytes"`
+}
+
+func getUserSettingUsage(
+	ctx context.Context, tx bun.IDB, userID model.UserID,
+) (userSettingUsage, error) {
+	var usage userSettingUsage
+	if err := tx.NewSelect().
+		Model(&model.UserWebSetting{}).
+		ColumnExpr("COUNT(*) AS count").
+		ColumnExpr("COALESCE(SUM(OCTET_LENGTH(value::text)), 0) AS total_bytes").
+		Where("user_id = ?", userID).
+		Scan(ctx, &usage); err != nil {
+		return usage, fmt.Errorf("getting size of settings of user %d: %w", userID, err)
+	}
+	return usage, nil
+}
+
+// lockUserSettings returns the usage of a user's settings, keeping other transactions from
+// changing them until tx ends so limits are checked against what is saved.
+func lockUserSettings(
+	ctx context.Context, tx bun.Tx, userID model.UserID,
+) (userSettingUsage, error) {
+	if _, err := tx.NewSelect().
+		Table("users").
+		Column("id").
+		Where("id = ?", userID).
+		For("UPDATE").
+		Exec(ctx); err != nil {
+		return userSettingUsage{}, fmt.Errorf("locking settings of user %d: %w", userID, err)
+	}
+	return getUserSettingUsage(ctx, tx, userID)
+}
+
+// checkUserSettingLimits returns a ResourceExhausted error if a user's settings exceed the limits
+// and have grown since before. Users already over the limits can still delete or shrink settings.
+func checkUserSettingLimits(
+	ctx context.Context, tx bun.Tx, userID model.UserID, before userSettingUsage,
+) error {
+	after, err := getUserSettingUsage(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	if (after.Count > MaxSettings && after.Count > before.Count) ||
+		(after.TotalBytes > MaxSettingsTotalBytes && after.TotalBytes > before.TotalBytes) {
+		return status.Errorf(codes.ResourceExhausted,
+			"users can have at most %d settings of at most %d bytes in total, "+
+				"saving would make %d settings of %d bytes",
+			MaxSettings, MaxSettingsTotalBytes, after.Count, after.TotalBytes)
+	}
+	return nil
+}
+
+func upsertUserSettings(ctx context.Context, tx bun.Tx, settings []*model.UserWebSetting) error {
+	for _, setting := range settings {
+		var err error
+		if len(setting.Value) == 0 {
+			_, err = tx.NewDelete().
+				Model(setting).
+				Where("user_id = ?", setting.UserID).
+				Where("storage_path = ?", setting.StoragePath).
+				Where("key = ?", setting.Key).Exec(ctx)
+		} else {
+			_, err = tx.NewInsert().
+				Model(setting).
+				On("CONFLICT (user_id, key, storage_path) DO UPDATE").
+				Set("value = EXCLUDED.value").Exec(ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetUserSetting gets user setting.
 func GetUserSetting(ctx context.Context, userID model.UserID) ([]*model.UserWebSetting, error) {
 	var setting []*model.UserWebSetting
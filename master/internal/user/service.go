@@ -23,6 +23,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/config"
 	detContext "github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/internal/telemetry"
 	"github.com/determined-ai/determined/master/pkg/model"
 )
@@ -53,6 +54,7 @@ var unauthenticatedPointsList = []string{
 	"/docs/.*",
 	"/info",
 	"/task-logs",
+	"/task-logs/v2",
 	"/agents",
 	"/det",
 	"/det/.*",
@@ -67,6 +69,7 @@ var unauthenticatedPointsList = []string{
 	"/oauth2/authorize(/.*)?",
 	"/oauth2/token(/.*)?",
 	"/scim/v2/.*",
+	"/workspace-api/v1/.*",
 }
 
 var unauthenticatedPointsPattern = regexp.MustCompile("^" +
@@ -115,7 +118,7 @@ func InitService(db *db.PgDB, extConfig *model.ExternalSessions) {
 				log.WithError(err).Errorf("failed to read the master TLS certificate")
 				return
 			}
-			userService.extConfig.StartInvalidationPoll(cert)
+			userService.extConfig.StartInvalidationPoll(cert, egress.Proxy)
 		}
 	})
 }
@@ -0,0 +1,229 @@
+// Package versioncompat judges the versions agents and harnesses report when they connect against
+// the compatibility policies of the master, so the master can be upgraded ahead of them, and
+// summarizes the version skew of the fleet.
+package versioncompat
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+// Kind is a kind of component that reports its version to the master.
+type Kind string
+
+const (
+	// KindAgent is an agent, identified by its agent ID.
+	KindAgent Kind = "agent"
+	// KindHarness is the harness of a task container, identified by its allocation ID.
+	KindHarness Kind = "harness"
+)
+
+// Verdict is whether a component's version is compatible with the master, and the policy for it
+// if it isn't.
+type Verdict struct {
+	Compatible bool   `json:"compatible"`
+	Policy     string `json:"policy"`
+	// Reason is why the version isn't compatible.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Refused returns whether the master refuses to schedule onto or run the component.
+func (v Verdict) Refused() bool {
+	return !v.Compatible && v.Policy == config.VersionPolicyRefuseScheduling
+}
+
+// Restricted returns whether the master turns off features that depend on the component.
+func (v Verdict) Restricted() bool {
+	return !v.Compatible && v.Policy == config.VersionPolicyRestrictFeatures
+}
+
+// Check judges the version of a component against the version of the master.
+func Check(masterVersion, version string, p config.ComponentVersionPolicy) Verdict {
+	v := Verdict{Compatible: true, Policy: policy(p)}
+	// Development builds of the master have no release version and work with anything.
+	master, err := semver.NewVersion(masterVersion)
+	if err != nil {
+		return v
+	}
+	c, err := semver.NewVersion(version)
+	switch {
+	case err != nil:
+		v.Reason = fmt.Sprintf("version %q is not a release version", version)
+	case c.Major() != master.Major():
+		v.Reason = fmt.Sprintf("version %s is on another major version than the master's %s",
+			c, master)
+	case c.Minor() > master.Minor():
+		v.Reason = fmt.Sprintf("version %s is newer than the master's %s", c, master)
+	case master.Minor()-c.Minor() > uint64(p.AllowedMinorSkew):
+		v.Reason = fmt.Sprintf("version %s is %d minor versions behind the master's %s, "+
+			"more than the %d allowed", c, master.Minor()-c.Minor(), master, p.AllowedMinorSkew)
+	case p.MinimumVersion != "":
+		// Prereleases of the minimum version count as the minimum version.
+		release, _ := c.SetPrerelease("")
+		if minimum, err := semver.NewVersion(p.MinimumVersion); err == nil &&
+			release.LessThan(minimum) {
+			v.Reason = fmt.Sprintf("version %s is older than the minimum version %s", c, minimum)
+		}
+	}
+	v.Compatible = v.Reason == ""
+	return v
+}
+
+// policy returns the policy of a component, which defaults to warn.
+func policy(p config.ComponentVersionPolicy) string {
+	if p.Policy == "" {
+		return config.VersionPolicyWarn
+	}
+	return p.Policy
+}
+
+// Component is a connected component and the verdict on its version.
+type Component struct {
+	Kind       Kind      `json:"kind"`
+	ID         string    `json:"id"`
+	Version    string    `json:"version"`
+	Verdict    Verdict   `json:"verdict"`
+	ReportedAt time.Time `json:"reported_at"`
+}
+
+// VersionCount is how many components of a kind run a version.
+type VersionCount struct {
+	Version    string `json:"version"`
+	Count      int    `json:"count"`
+	Compatible bool   `json:"compatible"`
+}
+
+// KindSkew summarizes the versions of the connected components of a kind.
+type KindSkew struct {
+	Kind         Kind   `json:"kind"`
+	Policy       string `json:"policy"`
+	Total        int    `json:"total"`
+	Incompatible int    `json:"incompatible"`
+	// Versions are ordered from newest to oldest, with versions that aren't release versions last.
+	Versions []VersionCount `json:"versions"`
+	// IncompatibleComponents are the components that aren't compatible, ordered by ID.
+	IncompatibleComponents []Component `json:"incompatible_components"`
+}
+
+// Skew summarizes the version skew between the master and the components connected to it.
+type Skew struct {
+	MasterVersion string     `json:"master_version"`
+	Kinds         []KindSkew `json:"kinds"`
+}
+
+// Registry tracks the versions of connected components.
+type Registry struct {
+	masterVersion string
+	policies      map[Kind]config.ComponentVersionPolicy
+
+	mu         sync.Mutex
+	components map[Kind]map[string]Component
+}
+
+// NewRegistry returns a registry that judges components against the version of the master.
+func NewRegistry(masterVersion string, conf config.VersionCompatibilityConfig) *Registry {
+	return &Registry{
+		masterVersion: masterVersion,
+		policies: map[Kind]config.ComponentVersionPolicy{
+			KindAgent:   conf.Agent,
+			KindHarness: conf.Harness,
+		},
+		components: map[Kind]map[string]Component{KindAgent: {}, KindHarness: {}},
+	}
+}
+
+// Report records the version a component reported and returns the verdict on it.
+func (r *Registry) Report(kind Kind, id, version string) Verdict {
+	v := Check(r.masterVersion, version, r.policies[kind])
+	if !v.Compatible && v.Policy != config.VersionPolicyIgnore {
+		log.WithFields(log.Fields{"kind": kind, "id": id, "policy": v.Policy}).
+			Warnf("%s %s is not compatible with the master: %s", kind, id, v.Reason)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.components[kind][id] = Component{
+		Kind: kind, ID: id, Version: version, Verdict: v, ReportedAt: time.Now(),
+	}
+	return v
+}
+
+// Verdict returns the verdict on the version of a component, if it reported one.
+func (r *Registry) Verdict(kind Kind, id string) (Verdict, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.components[kind][id]
+	return c.Verdict, ok
+}
+
+// Forget stops tracking a component that went away.
+func (r *Registry) Forget(kind Kind, id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.components[kind], id)
+}
+
+// Skew summarizes the versions of the components connected to the master.
+func (r *Registry) Skew() Skew {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := Skew{MasterVersion: r.masterVersion}
+	for _, kind := range []Kind{KindAgent, KindHarness} {
+		ks := KindSkew{
+			Kind:                   kind,
+			Policy:                 policy(r.policies[kind]),
+			Versions:               []VersionCount{},
+			IncompatibleComponents: []Component{},
+		}
+		counts := map[string]*VersionCount{}
+		for _, c := range r.components[kind] {
+			ks.Total++
+			if !c.Verdict.Compatible {
+				ks.Incompatible++
+				ks.IncompatibleComponents = append(ks.IncompatibleComponents, c)
+			}
+			if vc, ok := counts[c.Version]; ok {
+				vc.Count++
+			} else {
+				counts[c.Version] = &VersionCount{
+					Version: c.Version, Count: 1, Compatible: c.Verdict.Compatible,
+				}
+			}
+		}
+		for _, vc := range counts {
+			ks.Versions = append(ks.Versions, *vc)
+		}
+		sort.Slice(ks.Versions, func(i, j int) bool {
+			return newer(ks.Versions[i].Version, ks.Versions[j].Version)
+		})
+		sort.Slice(ks.IncompatibleComponents, func(i, j int) bool {
+			return ks.IncompatibleComponents[i].ID < ks.IncompatibleComponents[j].ID
+		})
+		s.Kinds = append(s.Kinds, ks)
+	}
+	return s
+}
+
+// newer orders versions from newest to oldest, with versions that don't parse last.
+func newer(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	switch {
+	case errA != nil && errB != nil:
+		return a < b
+	case errA != nil || errB != nil:
+		return errB != nil
+	case va.Equal(vb):
+		return a < b
+	default:
+		return va.GreaterThan(vb)
+	}
+}
@@ -0,0 +1,98 @@
+package versioncompat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config"
+)
+
+func TestCheck(t *testing.T) {
+	canary := config.ComponentVersionPolicy{AllowedMinorSkew: 1, MinimumVersion: "0.36.1"}
+	tests := []struct {
+		name       string
+		master     string
+		version    string
+		policy     config.ComponentVersionPolicy
+		compatible bool
+	}{
+		{name: "same", master: "0.37.0", version: "0.37.2", compatible: true},
+		{name: "dev master", master: "0.37.0-dev0+abc", version: "0.37.0", compatible: true},
+		{name: "unreleased master", master: "dev", version: "anything", compatible: true},
+		{name: "behind", master: "0.37.0", version: "0.36.4"},
+		{
+			name: "behind within skew", master: "0.37.0", version: "0.36.4", policy: canary,
+			compatible: true,
+		},
+		{name: "too far behind", master: "0.38.0", version: "0.36.4", policy: canary},
+		{name: "below minimum", master: "0.37.0", version: "0.36.0", policy: canary},
+		{
+			name: "prerelease of minimum", master: "0.37.0", version: "0.36.1-rc1", policy: canary,
+			compatible: true,
+		},
+		{name: "ahead", master: "0.37.0", version: "0.38.0", policy: canary},
+		{name: "other major", master: "1.0.0", version: "0.37.0", policy: canary},
+		{name: "unparsable", master: "0.37.0", version: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := Check(tt.master, tt.version, tt.policy)
+			require.Equal(t, tt.compatible, v.Compatible, v.Reason)
+			require.Equal(t, tt.compatible, v.Reason == "")
+			require.Equal(t, config.VersionPolicyWarn, v.Policy)
+		})
+	}
+}
+
+func TestVerdict(t *testing.T) {
+	refuse := config.ComponentVersionPolicy{Policy: config.VersionPolicyRefuseScheduling}
+	require.True(t, Check("0.37.0", "0.36.0", refuse).Refused())
+	require.False(t, Check("0.37.0", "0.37.0", refuse).Refused())
+
+	restrict := config.ComponentVersionPolicy{Policy: config.VersionPolicyRestrictFeatures}
+	require.True(t, Check("0.37.0", "0.36.0", restrict).Restricted())
+	require.False(t, Check("0.37.0", "0.36.0", restrict).Refused())
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry("0.37.0", config.VersionCompatibilityConfig{
+		Agent: config.ComponentVersionPolicy{
+			Policy: config.VersionPolicyRefuseScheduling, AllowedMinorSkew: 1,
+		},
+	})
+	require.True(t, r.Report(KindAgent, "a", "0.37.0").Compatible)
+	require.True(t, r.Report(KindAgent, "b", "0.36.2").Compatible)
+	require.True(t, r.Report(KindAgent, "c", "0.35.0").Refused())
+	r.Report(KindAgent, "d", "0.37.0")
+	r.Report(KindAgent, "e", "dev")
+	require.False(t, r.Report(KindHarness, "task.1", "0.36.0").Compatible)
+
+	v, ok := r.Verdict(KindAgent, "c")
+	require.True(t, ok)
+	require.True(t, v.Refused())
+	_, ok = r.Verdict(KindHarness, "a")
+	require.False(t, ok)
+
+	r.Forget(KindAgent, "d")
+	s := r.Skew()
+	require.Equal(t, "0.37.0", s.MasterVersion)
+	require.Len(t, s.Kinds, 2)
+	agents := s.Kinds[0]
+	require.Equal(t, KindAgent, agents.Kind)
+	require.Equal(t, config.VersionPolicyRefuseScheduling, agents.Policy)
+	require.Equal(t, 4, agents.Total)
+	require.Equal(t, 2, agents.Incompatible)
+	require.Equal(t, []VersionCount{
+		{Version: "0.37.0", Count: 1, Compatible: true},
+		{Version: "0.36.2", Count: 1, Compatible: true},
+		{Version: "0.35.0", Count: 1},
+		{Version: "dev", Count: 1},
+	}, agents.Versions)
+	require.Equal(t, "c", agents.IncompatibleComponents[0].ID)
+	require.Equal(t, "e", agents.IncompatibleComponents[1].ID)
+
+	harnesses := s.Kinds[1]
+	require.Equal(t, config.VersionPolicyWarn, harnesses.Policy)
+	require.Equal(t, 1, harnesses.Incompatible)
+}
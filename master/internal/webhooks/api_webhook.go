@@ -11,13 +11,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/hashicorp/go-cleanhttp"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/ptrs"
@@ -216,7 +216,7 @@ func (a *WebhooksAPIServer) TestWebhook(
 	}
 
 	log.Infof("creating webhook request for event %v", eventID)
-	c := cleanhttp.DefaultClient()
+	c := egress.Client()
 	resp, err := c.Do(tReq)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument,
@@ -516,6 +516,131 @@ func ReportExperimentStateChanged(
 	return nil
 }
 
+// ReportWorkspaceStorageQuotaExceeded adds events to the queue for the custom triggers of webhooks
+// in a workspace, or of global webhooks, when the workspace goes over its storage quota.
+func ReportWorkspaceStorageQuotaExceeded(
+	ctx context.Context, workspaceID int32, workspaceName string, usedBytes, quotaBytes int64,
+) error {
+	err := reportWorkspaceCustomEvent(ctx, workspaceID, CustomTriggerData{
+		Title: "Workspace storage quota exceeded",
+		Description: fmt.Sprintf(
+			"Workspace %s is using %d bytes of checkpoint storage, over its quota of %d bytes.",
+			workspaceName, usedBytes, quotaBytes),
+		Level: "warn",
+	})
+	if err != nil {
+		return fmt.Errorf("report storage quota exceeded: %w", err)
+	}
+	return nil
+}
+
+// ReportModelEvaluationRegressed adds events to the queue for the custom triggers of webhooks in
+// a model's workspace, or of global webhooks, when an evaluation of a version of the model was
+// worse than the last one.
+func ReportModelEvaluationRegressed(
+	ctx context.Context, workspaceID int32, modelName string, version int, policyName string,
+	metric string, value, baseline float64,
+) error {
+	err := reportWorkspaceCustomEvent(ctx, workspaceID, CustomTriggerData{
+		Title: "Model evaluation regressed",
+		Description: fmt.Sprintf(
+			"Evaluation %s of version %d of model %s reported %s of %g, worse than the %g of the "+
+				"last evaluation.",
+			policyName, version, modelName, metric, value, baseline),
+		Level: "warn",
+	})
+	if err != nil {
+		return fmt.Errorf("report model evaluation regressed: %w", err)
+	}
+	return nil
+}
+
+// ReportTrialStalled adds events to the queue for the custom triggers of webhooks in a trial's
+// workspace, or of global webhooks, when liveness detection found the trial stalled.
+func ReportTrialStalled(ctx context.Context, taskID model.TaskID, reason string, restarted bool) error {
+	t, err := db.TrialByTaskID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("report trial stalled: %w", err)
+	}
+	e, err := db.ExperimentByID(ctx, t.ExperimentID)
+	if err != nil {
+		return fmt.Errorf("report trial stalled: %w", err)
+	}
+	workspaceID, err := experiment.GetWorkspaceFromExperiment(ctx, e)
+	if err != nil {
+		return fmt.Errorf("report trial stalled: %w", err)
+	}
+
+	description := fmt.Sprintf("Trial %d of experiment %d stalled: %s.", t.ID, e.ID, reason)
+	if restarted {
+		description += " It is being restarted."
+	}
+	err = reportWorkspaceCustomEvent(ctx, workspaceID, CustomTriggerData{
+		Title:       "Trial stalled",
+		Description: description,
+		Level:       "warn",
+	})
+	if err != nil {
+		return fmt.Errorf("report trial stalled: %w", err)
+	}
+	return nil
+}
+
+// reportWorkspaceCustomEvent adds events to the queue for the custom triggers of webhooks in a
+// workspace, or of global webhooks.
+func reportWorkspaceCustomEvent(ctx context.Context, workspaceID int32, data CustomTriggerData) error {
+	var ts []Trigger
+	switch err := db.Bun().NewSelect().Model(&ts).Relation("Webhook").
+		Where("trigger_type = ?", TriggerTypeCustom).
+		Where("webhook.workspace_id = ? OR webhook.workspace_id IS NULL", workspaceID).
+		Scan(ctx); {
+	case err != nil:
+		return err
+	case len(ts) == 0:
+		return nil
+	}
+
+	var es []Event
+	for _, t := range ts {
+		var p []byte
+		var err error
+		switch t.Webhook.WebhookType {
+		case WebhookTypeDefault:
+			p, err = json.Marshal(EventPayload{
+				ID:        uuid.New(),
+				Type:      TriggerTypeCustom,
+				Timestamp: time.Now().Unix(),
+				Data:      EventData{CustomData: &data},
+			})
+		case WebhookTypeSlack:
+			p, err = json.Marshal(SlackMessageBody{
+				Blocks: []SlackBlock{
+					{
+						Type: "section",
+						Text: SlackField{
+							Type: "mrkdwn",
+							Text: fmt.Sprintf("*%s*\n%s", data.Title, data.Description),
+						},
+					},
+				},
+			})
+		default:
+			err = fmt.Errorf("unknown webhook type %+v", t.Webhook.WebhookType)
+		}
+		if err != nil {
+			return fmt.Errorf("generating payload: %w", err)
+		}
+		es = append(es, Event{Payload: p, URL: t.Webhook.URL})
+	}
+
+	if _, err := db.Bun().NewInsert().Model(&es).Exec(ctx); err != nil {
+		return fmt.Errorf("inserting event trigger: %w", err)
+	}
+
+	singletonShipper.Wake()
+	return nil
+}
+
 func addTaskLogEvent(ctx context.Context,
 	taskID model.TaskID, nodeName, triggeringLog string, trigger *Trigger,
 ) error {
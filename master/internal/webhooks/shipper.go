@@ -14,10 +14,10 @@ import (
 	"time"
 
 	back "github.com/cenkalti/backoff/v4"
-	"github.com/hashicorp/go-cleanhttp"
 	log "github.com/sirupsen/logrus"
 
 	conf "github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/egress"
 )
 
 const (
@@ -95,7 +95,7 @@ func (s *shipper) Close() {
 func newWorker(id int) *worker {
 	return &worker{
 		log: log.WithFields(log.Fields{"component": "webhook-shipper-worker", "id": id}),
-		cl:  cleanhttp.DefaultClient(),
+		cl:  egress.Client(),
 	}
 }
 
@@ -151,6 +151,16 @@ func (a *WorkspaceAuthZBasic) CanSetWorkspacesCheckpointStorageConfig(
 	return nil
 }
 
+// CanSetWorkspacesSettings returns an error if the user is not an admin or owner of the workspace.
+func (a *WorkspaceAuthZBasic) CanSetWorkspacesSettings(
+	ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
+) error {
+	if !curUser.Admin && curUser.ID != model.UserID(workspace.UserId) {
+		return fmt.Errorf("only admins may change the settings of other user's workspaces")
+	}
+	return nil
+}
+
 // CanCreateWorkspaceWithCheckpointStorageConfig returns an nil error.
 func (a *WorkspaceAuthZBasic) CanCreateWorkspaceWithCheckpointStorageConfig(
 	ctx context.Context, curUser model.User,
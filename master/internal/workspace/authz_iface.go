@@ -60,6 +60,9 @@ type WorkspaceAuthZ interface {
 	CanSetWorkspacesDefaultPools(
 		ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
 	) error
+	CanSetWorkspacesSettings(
+		ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
+	) error
 	// TODO: we should consider userID as an arg instead of model.User
 
 	// DELETE /api/v1/workspaces/:workspace_id
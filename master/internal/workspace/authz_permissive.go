@@ -139,6 +139,14 @@ func (p *WorkspaceAuthZPermissive) CanSetWorkspacesCheckpointStorageConfig(
 	return (&WorkspaceAuthZBasic{}).CanSetWorkspacesCheckpointStorageConfig(ctx, curUser, workspace)
 }
 
+// CanSetWorkspacesSettings calls RBAC authz but enforces basic authz.
+func (p *WorkspaceAuthZPermissive) CanSetWorkspacesSettings(
+	ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
+) error {
+	_ = (&WorkspaceAuthZRBAC{}).CanSetWorkspacesSettings(ctx, curUser, workspace)
+	return (&WorkspaceAuthZBasic{}).CanSetWorkspacesSettings(ctx, curUser, workspace)
+}
+
 // CanCreateWorkspaceWithCheckpointStorageConfig calls RBAC authz but enforces basic authz.
 func (p *WorkspaceAuthZPermissive) CanCreateWorkspaceWithCheckpointStorageConfig(
 	ctx context.Context, curUser model.User,
@@ -335,6 +335,23 @@ func (r *WorkspaceAuthZRBAC) CanSetWorkspacesCheckpointStorageConfig(
 		rbacv1.PermissionType_PERMISSION_TYPE_SET_WORKSPACE_CHECKPOINT_STORAGE_CONFIG)
 }
 
+// CanSetWorkspacesSettings determines if a user can change the settings of a workspace's
+// features, such as notebook sync, duplicate experiment detection, metrics exports and
+// environment variable sets.
+func (r *WorkspaceAuthZRBAC) CanSetWorkspacesSettings(
+	ctx context.Context, curUser model.User, workspace *workspacev1.Workspace,
+) (err error) {
+	fields := audit.ExtractLogFields(ctx)
+	addWorkspaceInfo(curUser, workspace, fields,
+		rbacv1.PermissionType_PERMISSION_TYPE_SET_WORKSPACE_SETTINGS)
+	defer func() {
+		audit.LogFromErr(fields, err)
+	}()
+
+	return db.DoesPermissionMatch(ctx, curUser.ID, &workspace.Id,
+		rbacv1.PermissionType_PERMISSION_TYPE_SET_WORKSPACE_SETTINGS)
+}
+
 // CanCreateWorkspaceWithCheckpointStorageConfig determines if a user can set
 // checkpoint storage access on a new workspace.
 func (r *WorkspaceAuthZRBAC) CanCreateWorkspaceWithCheckpointStorageConfig(
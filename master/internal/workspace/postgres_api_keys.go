@@ -0,0 +1,96 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// CreateAPIKey creates a read-only API key for a workspace, returning the key itself, which can't
+// be retrieved again, along with its record.
+func CreateAPIKey(
+	ctx context.Context, workspaceID int, name string, createdBy model.UserID, expiresAt *time.Time,
+) (string, *model.WorkspaceAPIKey, error) {
+	key, prefix, hash, err := model.NewWorkspaceAPIKey()
+	if err != nil {
+		return "", nil, err
+	}
+	k := &model.WorkspaceAPIKey{
+		WorkspaceID: workspaceID,
+		Name:        name,
+		KeyPrefix:   prefix,
+		KeyHash:     hash,
+		CreatedBy:   createdBy,
+		ExpiresAt:   expiresAt,
+	}
+	if _, err := db.Bun().NewInsert().Model(k).Returning("id, created_at").Exec(ctx); err != nil {
+		return "", nil, fmt.Errorf("creating API key %q for workspace %d: %w", name, workspaceID, err)
+	}
+	return key, k, nil
+}
+
+// APIKeys returns the API keys of a workspace, including revoked and expired ones.
+func APIKeys(ctx context.Context, workspaceID int) ([]model.WorkspaceAPIKey, error) {
+	keys := []model.WorkspaceAPIKey{}
+	if err := db.Bun().NewSelect().Model(&keys).
+		Where("workspace_id = ?", workspaceID).
+		Order("id ASC").
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting API keys of workspace %d: %w", workspaceID, err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes an API key of a workspace, returning db.ErrNotFound if the workspace has no
+// such active key.
+func RevokeAPIKey(ctx context.Context, workspaceID, keyID int) error {
+	res, err := db.Bun().NewUpdate().Model((*model.WorkspaceAPIKey)(nil)).
+		Set("revoked_at = ?", time.Now().UTC()).
+		Where("id = ?", keyID).
+		Where("workspace_id = ?", workspaceID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("revoking API key %d of workspace %d: %w", keyID, workspaceID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return db.ErrNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns the active workspace API key matching key, or db.ErrNotFound, and
+// records that it was used.
+func AuthenticateAPIKey(ctx context.Context, key string) (*model.WorkspaceAPIKey, error) {
+	var k model.WorkspaceAPIKey
+	err := db.Bun().NewSelect().Model(&k).
+		Where("key_hash = ?", model.HashWorkspaceAPIKey(key)).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("getting workspace API key: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if !k.Active(now) {
+		return nil, db.ErrNotFound
+	}
+	// Dashboards poll often, so only record use about once a minute.
+	if _, err := db.Bun().NewUpdate().Model(&k).
+		Set("last_used_at = ?", now).
+		WherePK().
+		Where("last_used_at IS NULL OR last_used_at < ?", now.Add(-time.Minute)).
+		Exec(ctx); err != nil {
+		return nil, fmt.Errorf("recording use of workspace API key %d: %w", k.ID, err)
+	}
+	k.LastUsedAt = &now
+	return &k, nil
+}
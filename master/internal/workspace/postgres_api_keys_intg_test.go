@@ -0,0 +1,61 @@
+//go:build integration
+// +build integration
+
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+func TestWorkspaceAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	user := db.RequireMockUser(t, db.SingleDB())
+	workspaceID, _ := db.RequireMockWorkspaceID(t, db.SingleDB(), "")
+	projectID, _ := db.RequireMockProjectID(t, db.SingleDB(), workspaceID, false)
+	exp := db.RequireMockExperimentProject(t, db.SingleDB(), user, projectID)
+	tr, _ := db.RequireMockTrial(t, db.SingleDB(), exp)
+
+	key, k, err := CreateAPIKey(ctx, workspaceID, "grafana", user.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, key[:len(k.KeyPrefix)], k.KeyPrefix)
+	_, _, err = CreateAPIKey(ctx, workspaceID, "grafana", user.ID, nil)
+	require.Error(t, err, "key names are unique within a workspace")
+
+	authed, err := AuthenticateAPIKey(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, workspaceID, authed.WorkspaceID)
+	require.NotNil(t, authed.LastUsedAt)
+	_, err = AuthenticateAPIKey(ctx, key+"x")
+	require.ErrorIs(t, err, db.ErrNotFound)
+
+	summaries, err := ExperimentSummaries(ctx, workspaceID, ExperimentSummaryFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	require.Equal(t, exp.ID, summaries[0].ID)
+	require.Equal(t, 1, summaries[0].NumTrials)
+
+	trialWorkspaceID, err := WorkspaceIDOfTrial(ctx, tr.ID)
+	require.NoError(t, err)
+	require.Equal(t, workspaceID, trialWorkspaceID)
+
+	require.NoError(t, RevokeAPIKey(ctx, workspaceID, k.ID))
+	require.ErrorIs(t, RevokeAPIKey(ctx, workspaceID, k.ID), db.ErrNotFound)
+	_, err = AuthenticateAPIKey(ctx, key)
+	require.ErrorIs(t, err, db.ErrNotFound)
+
+	past := time.Now().Add(-time.Hour)
+	expired, _, err := CreateAPIKey(ctx, workspaceID, "expired", user.ID, &past)
+	require.NoError(t, err)
+	_, err = AuthenticateAPIKey(ctx, expired)
+	require.ErrorIs(t, err, db.ErrNotFound)
+
+	keys, err := APIKeys(ctx, workspaceID)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+}
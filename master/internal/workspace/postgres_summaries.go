@@ -0,0 +1,92 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+)
+
+// ExperimentSummary is a brief description of an experiment, for external dashboards.
+type ExperimentSummary struct {
+	ID                      int        `bun:"id" json:"id"`
+	Name                    string     `bun:"name" json:"name"`
+	State                   string     `bun:"state" json:"state"`
+	ProjectID               int        `bun:"project_id" json:"project_id"`
+	ProjectName             string     `bun:"project_name" json:"project_name"`
+	Owner                   string     `bun:"owner" json:"owner"`
+	StartTime               time.Time  `bun:"start_time" json:"start_time"`
+	EndTime                 *time.Time `bun:"end_time" json:"end_time"`
+	Progress                *float64   `bun:"progress" json:"progress"`
+	NumTrials               int        `bun:"num_trials" json:"num_trials"`
+	SearcherMetric          string     `bun:"searcher_metric" json:"searcher_metric"`
+	BestSearcherMetricValue *float64   `bun:"best_searcher_metric_value" json:"best_searcher_metric_value"`
+}
+
+// ExperimentSummaryFilter narrows down the experiments ExperimentSummaries returns.
+type ExperimentSummaryFilter struct {
+	ProjectID *int
+	State     *string
+	Archived  bool
+	Limit     int
+	Offset    int
+}
+
+// ExperimentSummaries returns summaries of the experiments in a workspace, newest first.
+func ExperimentSummaries(
+	ctx context.Context, workspaceID int, f ExperimentSummaryFilter,
+) ([]ExperimentSummary, error) {
+	summaries := []ExperimentSummary{}
+	q := db.Bun().NewSelect().
+		TableExpr("experiments AS e").
+		ColumnExpr("e.id").
+		ColumnExpr("e.config->>'name' AS name").
+		ColumnExpr("e.state").
+		ColumnExpr("e.project_id").
+		ColumnExpr("p.name AS project_name").
+		ColumnExpr("u.username AS owner").
+		ColumnExpr("e.start_time, e.end_time, e.progress").
+		ColumnExpr("(SELECT COUNT(*) FROM trials t WHERE t.experiment_id = e.id) AS num_trials").
+		ColumnExpr("e.config->'searcher'->>'metric' AS searcher_metric").
+		ColumnExpr(`(
+			SELECT t.searcher_metric_value FROM trials t WHERE t.id = e.best_trial_id
+		) AS best_searcher_metric_value`).
+		Join("JOIN projects AS p ON p.id = e.project_id").
+		Join("JOIN users AS u ON u.id = e.owner_id").
+		Where("p.workspace_id = ?", workspaceID).
+		Where("e.archived = ?", f.Archived).
+		Order("e.id DESC").
+		Limit(f.Limit).
+		Offset(f.Offset)
+	if f.ProjectID != nil {
+		q = q.Where("e.project_id = ?", *f.ProjectID)
+	}
+	if f.State != nil {
+		q = q.Where("e.state = ?", *f.State)
+	}
+	if err := q.Scan(ctx, &summaries); err != nil {
+		return nil, fmt.Errorf("getting experiment summaries of workspace %d: %w", workspaceID, err)
+	}
+	return summaries, nil
+}
+
+// WorkspaceIDOfTrial returns the ID of the workspace a trial belongs to, or db.ErrNotFound.
+func WorkspaceIDOfTrial(ctx context.Context, trialID int) (int, error) {
+	var id int
+	err := db.Bun().NewSelect().
+		TableExpr("trials AS t").
+		ColumnExpr("p.workspace_id").
+		Join("JOIN experiments AS e ON e.id = t.experiment_id").
+		Join("JOIN projects AS p ON p.id = e.project_id").
+		Where("t.id = ?", trialID).
+		Scan(ctx, &id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, db.ErrNotFound
+	} else if err != nil {
+		return 0, fmt.Errorf("getting workspace of trial %d: %w", trialID, err)
+	}
+	return id, nil
+}
@@ -21,6 +21,18 @@ type AgentMessage struct {
 	StartContainer        *StartContainer
 	SignalContainer       *SignalContainer
 	AgentShutdown         *AgentShutdown
+	RemoveImages          *RemoveImages
+	ExecStart             *ExecStart
+	ExecInput             *ExecInput
+	TunnelOpen            *TunnelOpen
+	TunnelData            *TunnelData
+
+	// Seq numbers the state transitions sent to agents that resume sessions; see Session. Other
+	// messages leave it 0.
+	Seq uint64
+	// Ack is the Seq of the last message received from the agent. Messages that only acknowledge
+	// have nothing else set.
+	Ack uint64
 }
 
 // MasterSetAgentOptions is the first message sent to an agent by the master. It lets
@@ -31,6 +43,19 @@ type MasterSetAgentOptions struct {
 	MasterInfo           MasterInfo
 	LoggingOptions       model.LoggingConfig
 	ContainersToReattach []ContainerReattach
+	// VersionCompatibility is the verdict of the master on the version the agent connected with.
+	VersionCompatibility *VersionCompatibility
+	// Session is the verdict of the master on the session the agent connected with, for agents
+	// that resume sessions.
+	Session *SessionResume
+}
+
+// VersionCompatibility is whether the version of an agent is compatible with the master, and what
+// the master does about it if it isn't.
+type VersionCompatibility struct {
+	Compatible bool
+	Policy     string
+	Reason     string
 }
 
 // StartContainer notifies the agent to start a container with the provided spec.
@@ -45,5 +70,56 @@ type SignalContainer struct {
 	Signal      syscall.Signal
 }
 
+// RemoveImages notifies the agent to remove the images with the given IDs to free up disk space.
+type RemoveImages struct {
+	IDs []string
+}
+
+// ExecStart notifies the agent to start a process in a running container, for an interactive
+// session the master relays to a user. The agent sends the output of the process back as
+// ExecOutput messages with the same ExecID.
+type ExecStart struct {
+	ExecID      string
+	ContainerID cproto.ID
+	Cmd         []string
+	TTY         bool
+	Rows        uint
+	Cols        uint
+}
+
+// ExecInput is input to a process the agent started for an ExecStart.
+type ExecInput struct {
+	ExecID string
+	Data   []byte
+	// Resize, if set, is the new size of the process's TTY.
+	Resize *ExecResize
+	// CloseInput closes the standard input of the process.
+	CloseInput bool
+	// Detach stops relaying the process. Processes reading from a TTY exit when it is closed.
+	Detach bool
+}
+
+// ExecResize is the size of the TTY of an exec process.
+type ExecResize struct {
+	Rows uint
+	Cols uint
+}
+
+// TunnelOpen notifies an agent that accepts no connections from the master to connect to a port
+// on its host, and relay the connection with TunnelData messages with the same TunnelID.
+type TunnelOpen struct {
+	TunnelID string
+	Port     int
+}
+
+// TunnelData is data sent over a tunnel through an agent's connection to the master, in either
+// direction. The last message each side sends has Close set, and Error if the tunnel failed.
+type TunnelData struct {
+	TunnelID string
+	Data     []byte
+	Close    bool
+	Error    string
+}
+
 // ErrAgentMustReconnect is the error returned by the master when the agent must exit and reconnect.
 var ErrAgentMustReconnect = errors.New("agent is past reconnect period, it must restart")
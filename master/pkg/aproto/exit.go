@@ -11,6 +11,26 @@ type ContainerFailureError struct {
 	FailureType FailureType
 	ErrMsg      string
 	ExitCode    *ExitCode
+	// Diagnostics is collected on the agent when the container exits with a non-zero exit code.
+	Diagnostics *ContainerDiagnostics `json:",omitempty"`
+	// Reason tells what caused the failure, where the FailureType doesn't.
+	Reason FailureReason `json:",omitempty"`
+}
+
+// FailureReason is what caused a container to fail, beyond its FailureType.
+type FailureReason string
+
+// InfraFailureReason denotes that the container failed because of what it ran on, like an error
+// of the container runtime or the loss of its node, rather than because of its task.
+const InfraFailureReason FailureReason = "infra"
+
+// ContainerDiagnostics holds what the agent knows about why a container failed, beyond its exit
+// code, so that failures can be looked into without access to the agent.
+type ContainerDiagnostics struct {
+	// OOMKilled is whether the kernel killed a process of the container for running out of memory.
+	OOMKilled bool
+	// KernelLog holds the most recent out of memory records in the kernel log of the agent.
+	KernelLog []string `json:",omitempty"`
 }
 
 func (c ContainerFailureError) Error() string {
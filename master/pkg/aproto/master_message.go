@@ -50,6 +50,16 @@ type MasterMessage struct {
 	ContainerStateChanged *ContainerStateChanged
 	ContainerLog          *ContainerLog
 	ContainerStatsRecord  *ContainerStatsRecord
+	AgentDiskUsage        *AgentDiskUsage
+	ExecOutput            *ExecOutput
+	TunnelData            *TunnelData
+
+	// Seq numbers the state transitions agents that resume sessions send; see Session. Other
+	// messages leave it 0.
+	Seq uint64
+	// Ack is the Seq of the last message received from the master. Messages that only acknowledge
+	// have nothing else set.
+	Ack uint64
 }
 
 // ContainerReattach is a struct describing containers that can be reattached.
@@ -81,6 +91,37 @@ type AgentStarted struct {
 	Devices              []device.Device
 	ContainersReattached []ContainerReattachAck
 	ResourcePoolName     string
+	Labels               map[string]string
+	NetworkInterfaces    []NetworkInterface
+	// ScratchCapacityGiB is the space for scratch volumes on the agent's scratch disk, or 0 if it
+	// has none.
+	ScratchCapacityGiB int
+	// Time is the agent's clock when it sent the message, from which the master estimates how far
+	// the timestamps of the agent's logs are off its own clock. Older agents leave it unset.
+	Time time.Time
+	// SupportsExec is set by agents that can start processes in running containers for ExecStart.
+	SupportsExec bool
+	// OutboundOnly is set by agents that accept no connections from the master, which reaches the
+	// services of their containers through tunnels opened with TunnelOpen instead.
+	OutboundOnly bool
+}
+
+// ExecOutput is output of a process the agent started for an ExecStart. The last message of a
+// process has its exit code or the error that ended it.
+type ExecOutput struct {
+	ExecID   string
+	Data     []byte
+	ExitCode *int
+	Error    string
+}
+
+// NetworkInterface is a network interface of an agent's host that containers could communicate
+// with each other over.
+type NetworkInterface struct {
+	Name      string
+	Addresses []string
+	// RoutesToMaster is set on the interface the agent reaches the master through.
+	RoutesToMaster bool
 }
 
 // ContainerStateChanged notifies the master that the agent transitioned the container state.
@@ -105,7 +146,9 @@ func (c ContainerStarted) String() string {
 // started information.
 func (c ContainerStarted) Addresses() []cproto.Address {
 	proxy := c.ProxyAddress
-	proxyIsIPv4 := net.ParseIP(proxy).To4() != nil
+	// Hostnames, such as those of tunnels to agents, are reached through the IPv4 bindings.
+	proxyIP := net.ParseIP(proxy)
+	proxyIsIPv4 := proxyIP == nil || proxyIP.To4() != nil
 
 	info := c.ContainerInfo
 
@@ -206,3 +249,21 @@ type ContainerStatsRecord struct {
 	Stats    *model.TaskStats
 	TaskType model.TaskType
 }
+
+// AgentDiskUsage notifies the master of the utilization of the partition the agent's images are
+// stored on, and of the images on it.
+type AgentDiskUsage struct {
+	TotalBytes uint64
+	UsedBytes  uint64
+	Images     []Image
+}
+
+// Image is a Docker image on an agent.
+type Image struct {
+	ID string
+	// Refs are the tags and digests the image is known by.
+	Refs      []string
+	SizeBytes int64
+	// InUse is set if any container on the agent, running or not, was created from the image.
+	InUse bool
+}
@@ -0,0 +1,126 @@
+package aproto
+
+// maxUnacked is the number of messages a session buffers until they are acknowledged. Sessions
+// that fall further behind can no longer be resumed.
+const maxUnacked = 1024
+
+// SessionResume is the verdict of the master on the session an agent reconnected with.
+type SessionResume struct {
+	// Resumed is set if the master kept the session the agent reconnected with, in which case both
+	// replay the messages the other hasn't received. Otherwise, both start a new session.
+	Resumed bool
+	// Received is the Seq of the last message the master received from the agent.
+	Received uint64
+}
+
+// sequenced is implemented by the messages sessions number.
+type sequenced[T any] interface {
+	// withSeq returns the message with its Seq and Ack set.
+	withSeq(seq, ack uint64) T
+	// reliable is whether the message is a state transition that must not be lost.
+	reliable() bool
+}
+
+func (m AgentMessage) withSeq(seq, ack uint64) AgentMessage {
+	m.Seq, m.Ack = seq, ack
+	return m
+}
+
+func (m AgentMessage) reliable() bool {
+	return m.StartContainer != nil || m.SignalContainer != nil
+}
+
+func (m *MasterMessage) withSeq(seq, ack uint64) *MasterMessage {
+	c := *m
+	c.Seq, c.Ack = seq, ack
+	return &c
+}
+
+func (m *MasterMessage) reliable() bool {
+	return m.ContainerStateChanged != nil
+}
+
+// Session numbers the state transitions one side of an agent's connection to the master sends,
+// and tracks those the other side received, so that the transitions sent but not acknowledged
+// before the connection breaks can be replayed when the agent reconnects and resumes it. Other
+// messages, such as logs, aren't numbered and are lost with the connection. Sessions aren't safe
+// for concurrent use.
+type Session[T sequenced[T]] struct {
+	id       string
+	sent     uint64
+	received uint64
+	// unacked are the reliable messages sent and not acknowledged, in order.
+	unacked []T
+	seqs    []uint64
+	// dropped is the Seq of the last message dropped before it was acknowledged.
+	dropped uint64
+}
+
+// NewSession returns a new session with the given ID.
+func NewSession[T sequenced[T]](id string) *Session[T] {
+	return &Session[T]{id: id}
+}
+
+// ID returns the ID of the session.
+func (s *Session[T]) ID() string {
+	return s.id
+}
+
+// Received returns the Seq of the last message received.
+func (s *Session[T]) Received() uint64 {
+	return s.received
+}
+
+// Send numbers msg if it is reliable, buffers it until it is acknowledged, and acknowledges the
+// messages received so far with it.
+func (s *Session[T]) Send(msg T) T {
+	if !msg.reliable() {
+		return msg.withSeq(0, s.received)
+	}
+	s.sent++
+	msg = msg.withSeq(s.sent, s.received)
+	if len(s.unacked) == maxUnacked {
+		s.dropped = s.seqs[0]
+		s.unacked, s.seqs = s.unacked[1:], s.seqs[1:]
+	}
+	s.unacked = append(s.unacked, msg)
+	s.seqs = append(s.seqs, s.sent)
+	return msg
+}
+
+// Receive records the Seq and Ack of a message received and returns whether it should be handled.
+// Messages replayed after they were received are not.
+func (s *Session[T]) Receive(seq, ack uint64) bool {
+	s.acknowledge(ack)
+	if seq == 0 {
+		return true
+	}
+	if seq <= s.received {
+		return false
+	}
+	s.received = seq
+	return true
+}
+
+func (s *Session[T]) acknowledge(ack uint64) {
+	i := 0
+	for i < len(s.seqs) && s.seqs[i] <= ack {
+		i++
+	}
+	s.unacked, s.seqs = s.unacked[i:], s.seqs[i:]
+}
+
+// Resume returns the messages to replay to the other side, which received up to the message with
+// the Seq received, or false if some of them were dropped and the session can't be resumed.
+func (s *Session[T]) Resume(received uint64) ([]T, bool) {
+	if received < s.dropped || received > s.sent {
+		return nil, false
+	}
+	s.acknowledge(received)
+	return append([]T(nil), s.unacked...), true
+}
+
+// Reset starts a new session with the given ID, forgetting the messages of the last one.
+func (s *Session[T]) Reset(id string) {
+	*s = Session[T]{id: id}
+}
@@ -0,0 +1,64 @@
+package aproto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+func startContainer(id cproto.ID) AgentMessage {
+	return AgentMessage{StartContainer: &StartContainer{Container: cproto.Container{ID: id}}}
+}
+
+func TestSessionReplaysUnacknowledged(t *testing.T) {
+	s := NewSession[AgentMessage]("session")
+
+	first := s.Send(startContainer("a"))
+	require.Equal(t, uint64(1), first.Seq)
+	// Messages that aren't state transitions aren't numbered.
+	require.Zero(t, s.Send(AgentMessage{RemoveImages: &RemoveImages{}}).Seq)
+	require.Equal(t, uint64(2), s.Send(startContainer("b")).Seq)
+	require.Equal(t, uint64(3), s.Send(startContainer("c")).Seq)
+
+	require.True(t, s.Receive(0, 1))
+	replay, ok := s.Resume(2)
+	require.True(t, ok)
+	require.Len(t, replay, 1)
+	require.Equal(t, cproto.ID("c"), replay[0].StartContainer.Container.ID)
+
+	// The other side can't have received messages that weren't sent.
+	_, ok = s.Resume(4)
+	require.False(t, ok)
+}
+
+func TestSessionDropsReplayedMessages(t *testing.T) {
+	s := NewSession[*MasterMessage]("session")
+	require.True(t, s.Receive(1, 0))
+	require.True(t, s.Receive(0, 0))
+	require.False(t, s.Receive(1, 0))
+	require.True(t, s.Receive(2, 0))
+	require.Equal(t, uint64(2), s.Received())
+
+	msg := &MasterMessage{ContainerStateChanged: &ContainerStateChanged{}}
+	sent := s.Send(msg)
+	require.Equal(t, uint64(2), sent.Ack)
+	require.Zero(t, msg.Seq, "the message sent is a copy")
+}
+
+func TestSessionCannotResumeAfterDropping(t *testing.T) {
+	s := NewSession[AgentMessage]("session")
+	for i := 0; i < maxUnacked+1; i++ {
+		s.Send(startContainer("a"))
+	}
+	_, ok := s.Resume(0)
+	require.False(t, ok)
+	replay, ok := s.Resume(1)
+	require.True(t, ok)
+	require.Len(t, replay, maxUnacked)
+
+	s.Reset("new")
+	require.Equal(t, "new", s.ID())
+	require.Equal(t, uint64(1), s.Send(startContainer("a")).Seq)
+}
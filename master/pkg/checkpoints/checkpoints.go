@@ -2,12 +2,15 @@ package checkpoints
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/gcs"
+	"github.com/determined-ai/determined/master/pkg/checkpoints/hdfs"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/local"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/s3"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
@@ -18,6 +21,51 @@ type CheckpointDownloader interface {
 	Download(context.Context) error
 	Close() error
 	ListFiles(context.Context) ([]archive.FileEntry, error)
+	// ReadRange reads up to length bytes of a file in the checkpoint, starting at offset, without
+	// writing them to the archive. Fewer bytes are returned if the file ends first.
+	ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error)
+}
+
+// Backend is checkpoint storage that the master can read checkpoints from.
+type Backend interface {
+	// NewDownloader returns a CheckpointDownloader for the checkpoint with the UUID id, which
+	// writes to aw.
+	NewDownloader(ctx context.Context, aw archive.ArchiveWriter, id string) (
+		CheckpointDownloader, error)
+	// Probe writes an object to the storage, reads it back and deletes it, returning the first
+	// step that failed.
+	Probe(ctx context.Context) error
+}
+
+// ErrUnsupportedStorage is returned by NewBackend for storage that the master can't access.
+var ErrUnsupportedStorage = errors.New("checkpoint download via master is not supported")
+
+// NewBackend returns the Backend for storageConfig. Checkpoints in storage that the master can't
+// read, such as Azure and SFTP, can only be downloaded by clients.
+func NewBackend(storageConfig *expconf.CheckpointStorageConfig) (Backend, error) {
+	switch storage := storageConfig.GetUnionMember().(type) {
+	case expconf.S3Config:
+		return s3Backend(storage), nil
+
+	case expconf.GCSConfig:
+		return gcsBackend(storage), nil
+
+	case expconf.SharedFSConfig:
+		pathPrefix, err := storage.PathInContainerOrHost()
+		if err != nil {
+			return nil, err
+		}
+		return localBackend(pathPrefix), nil
+
+	case expconf.DirectoryConfig:
+		return localBackend(storage.ContainerPath()), nil
+
+	case expconf.HDFSConfig:
+		return hdfsBackend(storage), nil
+
+	default:
+		return nil, fmt.Errorf("%w for %s", ErrUnsupportedStorage, storageConfig2Str(storage))
+	}
 }
 
 // NewDownloader returns a new CheckpointDownloader that writes to w.
@@ -34,44 +82,107 @@ func NewDownloader(
 	storageConfig *expconf.CheckpointStorageConfig,
 	aw archive.ArchiveWriter,
 ) (CheckpointDownloader, error) {
-	idPrefix := func(prefix string) string {
-		prefix = strings.TrimRight(prefix, "/")
-		return prefix + "/" + id
+	backend, err := NewBackend(storageConfig)
+	if err != nil {
+		return nil, err
 	}
-	idPrefixRef := func(prefixRef *string) string {
-		prefix := ""
-		if prefixRef != nil {
-			prefix = *prefixRef
-		}
-		return idPrefix(prefix)
+	return backend.NewDownloader(ctx, aw, id)
+}
+
+func idPrefix(prefix, id string) string {
+	prefix = strings.TrimRight(prefix, "/")
+	return prefix + "/" + id
+}
+
+func idPrefixRef(prefixRef *string, id string) string {
+	prefix := ""
+	if prefixRef != nil {
+		prefix = *prefixRef
 	}
+	return idPrefix(prefix, id)
+}
 
-	switch storage := storageConfig.GetUnionMember().(type) {
-	case expconf.S3Config:
-		prefix := idPrefixRef(storage.Prefix())
-		return s3.NewS3Downloader(ctx, aw, storage.Bucket(), prefix, storage.EndpointURL())
+type s3Backend expconf.S3Config
 
-	case expconf.GCSConfig:
-		prefix := idPrefixRef(storage.Prefix())
-		return gcs.NewGCSDownloader(ctx, aw, storage.Bucket(), prefix)
+func (b s3Backend) NewDownloader(
+	ctx context.Context, aw archive.ArchiveWriter, id string,
+) (CheckpointDownloader, error) {
+	storage := expconf.S3Config(b)
+	prefix := idPrefixRef(storage.Prefix(), id)
+	return s3.NewS3Downloader(ctx, aw, storage.Bucket(), prefix, storage.EndpointURL())
+}
 
-	case expconf.SharedFSConfig:
-		pathPrefix, err := storage.PathInContainerOrHost()
-		if err != nil {
-			return nil, err
-		}
-		prefix := idPrefix(pathPrefix)
-		return local.NewLocalDownloader(aw, prefix)
+func (b s3Backend) Probe(ctx context.Context) error {
+	storage := expconf.S3Config(b)
+	prefix := ""
+	if storage.Prefix() != nil {
+		prefix = *storage.Prefix()
+	}
+	store, err := s3.NewS3Store(ctx, storage.Bucket(), prefix, storage.EndpointURL(),
+		storage.AccessKey(), storage.SecretKey())
+	if err != nil {
+		return err
+	}
+	return probe(ctx, store)
+}
 
-	case expconf.DirectoryConfig:
-		prefix := idPrefix(storage.ContainerPath())
-		return local.NewLocalDownloader(aw, prefix)
+type gcsBackend expconf.GCSConfig
 
-	default:
-		return nil,
-			fmt.Errorf("checkpoint download via master is not supported for %s",
-				storageConfig2Str(storage))
+func (b gcsBackend) NewDownloader(
+	ctx context.Context, aw archive.ArchiveWriter, id string,
+) (CheckpointDownloader, error) {
+	storage := expconf.GCSConfig(b)
+	prefix := idPrefixRef(storage.Prefix(), id)
+	return gcs.NewGCSDownloader(ctx, aw, storage.Bucket(), prefix)
+}
+
+func (b gcsBackend) Probe(ctx context.Context) error {
+	storage := expconf.GCSConfig(b)
+	prefix := ""
+	if storage.Prefix() != nil {
+		prefix = *storage.Prefix()
+	}
+	store, err := gcs.NewGCSStore(ctx, storage.Bucket(), prefix)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		_ = store.Close()
+	}()
+	return probe(ctx, store)
+}
+
+// localBackend is a directory of checkpoints that is mounted on the master.
+type localBackend string
+
+func (b localBackend) NewDownloader(
+	_ context.Context, aw archive.ArchiveWriter, id string,
+) (CheckpointDownloader, error) {
+	return local.NewLocalDownloader(aw, idPrefix(string(b), id))
+}
+
+func (b localBackend) Probe(ctx context.Context) error {
+	return probe(ctx, local.NewLocalStore(string(b)))
+}
+
+type hdfsBackend expconf.HDFSConfig
+
+func (b hdfsBackend) NewDownloader(
+	_ context.Context, aw archive.ArchiveWriter, id string,
+) (CheckpointDownloader, error) {
+	storage := expconf.HDFSConfig(b)
+	prefix := idPrefix(storage.StoragePath(), id)
+	return hdfs.NewHDFSDownloader(aw, egress.Client(), storage.URL(), storage.Username(), prefix)
+}
+
+func (b hdfsBackend) Probe(ctx context.Context) error {
+	storage := expconf.HDFSConfig(b)
+	store, err := hdfs.NewHDFSStore(egress.Client(), storage.URL(), storage.Username(),
+		storage.StoragePath())
+	if err != nil {
+		return err
+	}
+	return probe(ctx, store)
 }
 
 func storageConfig2Str(config any) string {
@@ -86,7 +197,16 @@ func storageConfig2Str(config any) string {
 		return "shared_fs"
 	case expconf.DirectoryConfig:
 		return "directory"
+	case expconf.SFTPConfig:
+		return "sftp"
+	case expconf.HDFSConfig:
+		return "hdfs"
 	default:
 		return "unknown"
 	}
 }
+
+// StorageType returns the type of storageConfig, like "s3".
+func StorageType(storageConfig *expconf.CheckpointStorageConfig) string {
+	return storageConfig2Str(storageConfig.GetUnionMember())
+}
@@ -2,15 +2,30 @@ package gcs
 
 import (
 	"context"
+	"io"
+	"net/http"
 	"strings"
 
 	"cloud.google.com/go/storage"
 	"github.com/docker/go-units"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
 
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
 )
 
+// newClient returns a client whose requests to GCS go through the egress proxy.
+func newClient(ctx context.Context) (*storage.Client, error) {
+	t, err := htransport.NewTransport(ctx, egress.Transport(),
+		option.WithScopes(storage.ScopeFullControl))
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewClient(ctx, option.WithHTTPClient(&http.Client{Transport: t})) //nolint:forbidigo
+}
+
 // GCSDownloader implements downloading a checkpoint from GCS
 // and sends it to the client in an archive file.
 type GCSDownloader struct {
@@ -107,6 +122,20 @@ func (d *GCSDownloader) ListFiles(ctx context.Context) ([]archive.FileEntry, err
 	return d.files, nil
 }
 
+// ReadRange reads part of a file in the checkpoint.
+func (d *GCSDownloader) ReadRange(
+	ctx context.Context, path string, offset, length int64,
+) ([]byte, error) {
+	r, err := d.bucket.Object(d.prefix+path).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	return io.ReadAll(r)
+}
+
 // NewGCSDownloader returns a new GCSDownloader.
 func NewGCSDownloader(
 	ctx context.Context,
@@ -119,7 +148,7 @@ func NewGCSDownloader(
 		prefix += "/"
 	}
 
-	client, err := storage.NewClient(ctx)
+	client, err := newClient(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -131,3 +160,56 @@ func NewGCSDownloader(
 		buffer: make([]byte, DefaultDownloadPartSize),
 	}, nil
 }
+
+// GCSStore reads and writes single objects in GCS.
+type GCSStore struct {
+	client *storage.Client
+	bucket *storage.BucketHandle
+	prefix string
+}
+
+// Put writes data to the object key, replacing it if it exists.
+func (s *GCSStore) Put(ctx context.Context, key string, data []byte) error {
+	w := s.bucket.Object(s.prefix + key).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Get reads the object key.
+func (s *GCSStore) Get(ctx context.Context, key string) ([]byte, error) {
+	r, err := s.bucket.Object(s.prefix + key).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	return io.ReadAll(r)
+}
+
+// Delete deletes the object key.
+func (s *GCSStore) Delete(ctx context.Context, key string) error {
+	return s.bucket.Object(s.prefix + key).Delete(ctx)
+}
+
+// Close closes the underlying client.
+func (s *GCSStore) Close() error {
+	return s.client.Close()
+}
+
+// NewGCSStore returns a new GCSStore for the objects under prefix.
+func NewGCSStore(ctx context.Context, bucket string, prefix string) (*GCSStore, error) {
+	prefix = strings.TrimLeft(prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	client, err := newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &GCSStore{client: client, bucket: client.Bucket(bucket), prefix: prefix}, nil
+}
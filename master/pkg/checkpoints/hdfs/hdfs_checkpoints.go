@@ -0,0 +1,296 @@
+package hdfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+)
+
+// HDFSDownloader implements downloading a checkpoint from HDFS, through its WebHDFS REST API,
+// and sends it to the client in an archive file.
+type HDFSDownloader struct {
+	webHDFS
+	aw     archive.ArchiveWriter
+	prefix string
+	files  []archive.FileEntry
+}
+
+// webHDFS makes requests to the WebHDFS REST API of a namenode.
+type webHDFS struct {
+	client  *http.Client
+	baseURL string
+	user    *string
+}
+
+// fileStatus is the status of a file or directory in a WebHDFS LISTSTATUS response.
+type fileStatus struct {
+	PathSuffix string `json:"pathSuffix"`
+	Type       string `json:"type"`
+	Length     int64  `json:"length"`
+}
+
+// remoteException is the body of WebHDFS error responses.
+type remoteException struct {
+	RemoteException struct {
+		Exception string `json:"exception"`
+		Message   string `json:"message"`
+	} `json:"RemoteException"`
+}
+
+func (w webHDFS) request(
+	ctx context.Context, method, filePath, op string, params url.Values, body io.Reader,
+) (*http.Response, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("op", op)
+	if w.user != nil {
+		params.Set("user.name", *w.user)
+	}
+	u := w.baseURL + "/webhdfs/v1" + (&url.URL{Path: filePath}).EscapedPath() + "?" +
+		params.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	// OPEN redirects to a datanode, which the client follows.
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", op, filePath, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		var e remoteException
+		if err := json.NewDecoder(resp.Body).Decode(&e); err == nil &&
+			e.RemoteException.Message != "" {
+			return nil, fmt.Errorf("%s %s: %s: %s", op, filePath,
+				e.RemoteException.Exception, e.RemoteException.Message)
+		}
+		return nil, fmt.Errorf("%s %s: namenode responded %s", op, filePath, resp.Status)
+	}
+	return resp, nil
+}
+
+func (d *HDFSDownloader) listStatus(ctx context.Context, dir string) ([]fileStatus, error) {
+	resp, err := d.request(ctx, http.MethodGet, dir, "LISTSTATUS", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		FileStatuses struct {
+			FileStatus []fileStatus `json:"FileStatus"`
+		} `json:"FileStatuses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding LISTSTATUS %s: %w", dir, err)
+	}
+	return body.FileStatuses.FileStatus, nil
+}
+
+func (d *HDFSDownloader) archiveDownload(ctx context.Context, filePath string, size int64) error {
+	if err := d.aw.WriteHeader(filePath, size); err != nil {
+		return err
+	}
+	resp, err := d.request(ctx, http.MethodGet, d.prefix+filePath, "OPEN", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	n, err := io.Copy(d.aw, resp.Body)
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("downloading %s: got %d of %d bytes", filePath, n, size)
+	}
+	return nil
+}
+
+// Download downloads the checkpoint.
+func (d *HDFSDownloader) Download(ctx context.Context) error {
+	files, err := d.ListFiles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := d.archiveDownload(ctx, file.Path, file.Size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying ArchiveWriter.
+func (d *HDFSDownloader) Close() error {
+	return d.aw.Close()
+}
+
+// ListFiles lists the files in the checkpoint.
+func (d *HDFSDownloader) ListFiles(ctx context.Context) ([]archive.FileEntry, error) {
+	if d.files != nil {
+		return d.files, nil
+	}
+	files := make([]archive.FileEntry, 0)
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+		statuses, err := d.listStatus(ctx, d.prefix+dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range statuses {
+			switch p := dir + s.PathSuffix; s.Type {
+			case "DIRECTORY":
+				dirs = append(dirs, p+"/")
+			case "FILE":
+				files = append(files, archive.FileEntry{Path: p, Size: s.Length})
+			}
+		}
+	}
+	d.files = files
+	return d.files, nil
+}
+
+// ReadRange reads part of a file in the checkpoint.
+func (d *HDFSDownloader) ReadRange(
+	ctx context.Context, filePath string, offset, length int64,
+) ([]byte, error) {
+	if length <= 0 {
+		return nil, nil
+	}
+	resp, err := d.request(ctx, http.MethodGet, d.prefix+filePath, "OPEN", url.Values{
+		"offset": {strconv.FormatInt(offset, 10)},
+		"length": {strconv.FormatInt(length, 10)},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(io.LimitReader(resp.Body, length))
+}
+
+// NewHDFSDownloader returns a new HDFSDownloader. baseURL is the WebHDFS address of the
+// namenode, like http://namenode:9870, and user is who to access HDFS as, if not the default.
+func NewHDFSDownloader(
+	aw archive.ArchiveWriter,
+	client *http.Client,
+	baseURL string,
+	user *string,
+	prefix string,
+) (*HDFSDownloader, error) {
+	prefix = path.Clean("/" + prefix)
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	w, err := newWebHDFS(client, baseURL, user)
+	if err != nil {
+		return nil, err
+	}
+	return &HDFSDownloader{
+		webHDFS: w,
+		aw:      aw,
+		prefix:  prefix,
+	}, nil
+}
+
+func newWebHDFS(client *http.Client, baseURL string, user *string) (webHDFS, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return webHDFS{}, fmt.Errorf("invalid WebHDFS url %q", baseURL)
+	}
+	return webHDFS{client: client, baseURL: strings.TrimRight(baseURL, "/"), user: user}, nil
+}
+
+// HDFSStore reads and writes single files in HDFS, through its WebHDFS REST API.
+type HDFSStore struct {
+	webHDFS
+	prefix string
+}
+
+// Put writes data to the file key, replacing it if it exists.
+func (s *HDFSStore) Put(ctx context.Context, key string, data []byte) error {
+	// Creating a file is two steps: the namenode redirects to the datanode to write it to, and the
+	// data is sent there.
+	noRedirect := *s.client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	create := webHDFS{client: &noRedirect, baseURL: s.baseURL, user: s.user}
+	filePath := s.prefix + key
+	params := url.Values{"overwrite": {"true"}}
+	resp, err := create.request(ctx, http.MethodPut, filePath, "CREATE", params, nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	location := resp.Header.Get("Location")
+	if location == "" {
+		// Some gateways, like HttpFS, take the data on the first request.
+		params.Set("data", "true")
+		resp, err = s.request(ctx, http.MethodPut, filePath, "CREATE", params, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, location, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp, err = s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("CREATE %s: %w", filePath, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("CREATE %s: datanode responded %s", filePath, resp.Status)
+	}
+	return nil
+}
+
+// Get reads the file key.
+func (s *HDFSStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.request(ctx, http.MethodGet, s.prefix+key, "OPEN", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// Delete deletes the file key.
+func (s *HDFSStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.request(ctx, http.MethodDelete, s.prefix+key, "DELETE", nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// NewHDFSStore returns a new HDFSStore for the files under prefix, with the same arguments as
+// NewHDFSDownloader.
+func NewHDFSStore(
+	client *http.Client, baseURL string, user *string, prefix string,
+) (*HDFSStore, error) {
+	w, err := newWebHDFS(client, baseURL, user)
+	if err != nil {
+		return nil, err
+	}
+	prefix = path.Clean("/" + prefix)
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &HDFSStore{webHDFS: w, prefix: prefix}, nil
+}
@@ -0,0 +1,172 @@
+package hdfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+// newFakeWebHDFS serves files from a map of paths to their contents, like a namenode that
+// redirects reads and writes to a datanode.
+func newFakeWebHDFS(t *testing.T, files map[string]string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "determined", r.URL.Query().Get("user.name"))
+		if strings.HasPrefix(r.URL.Path, "/datanode") {
+			if r.Method == http.MethodPut {
+				content, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				files[strings.TrimPrefix(r.URL.Path, "/datanode")] = string(content)
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			content := files[strings.TrimPrefix(r.URL.Path, "/datanode")]
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			length, err := strconv.Atoi(r.URL.Query().Get("length"))
+			if err != nil {
+				length = len(content)
+			}
+			_, _ = io.WriteString(w, content[offset:min(offset+length, len(content))])
+			return
+		}
+
+		p := strings.TrimPrefix(r.URL.Path, "/webhdfs/v1")
+		switch r.URL.Query().Get("op") {
+		case "OPEN":
+			if _, ok := files[p]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			http.Redirect(w, r, server.URL+"/datanode"+p+"?"+r.URL.RawQuery,
+				http.StatusTemporaryRedirect)
+		case "CREATE":
+			require.Equal(t, http.MethodPut, r.Method)
+			http.Redirect(w, r, server.URL+"/datanode"+p+"?"+r.URL.RawQuery,
+				http.StatusTemporaryRedirect)
+		case "DELETE":
+			require.Equal(t, http.MethodDelete, r.Method)
+			_, ok := files[p]
+			delete(files, p)
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]bool{"boolean": ok}))
+		case "LISTSTATUS":
+			statuses := []fileStatus{}
+			dirs := map[string]bool{}
+			for name, content := range files {
+				rel, ok := strings.CutPrefix(name, strings.TrimSuffix(p, "/")+"/")
+				if !ok {
+					continue
+				}
+				if child, _, isDir := strings.Cut(rel, "/"); isDir {
+					if !dirs[child] {
+						dirs[child] = true
+						statuses = append(statuses, fileStatus{PathSuffix: child, Type: "DIRECTORY"})
+					}
+				} else {
+					statuses = append(statuses, fileStatus{
+						PathSuffix: rel, Type: "FILE", Length: int64(len(content)),
+					})
+				}
+			}
+			if len(statuses) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `{"RemoteException": {"exception": "FileNotFoundException",`+
+					`"message": "File `+p+` does not exist."}}`)
+				return
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"FileStatuses": map[string]any{"FileStatus": statuses},
+			}))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHDFSDownloader(t *testing.T) {
+	ctx := context.Background()
+	files := map[string]string{
+		"/ckpts/abc/metadata.json":      `{"steps_completed": 10}`,
+		"/ckpts/abc/state/model.pt":     "weights",
+		"/ckpts/abcdef/not-this-one.pt": "other",
+	}
+	server := newFakeWebHDFS(t, files)
+
+	var buf bytes.Buffer
+	aw, err := archive.NewArchiveWriter(&buf, archive.ArchiveTar)
+	require.NoError(t, err)
+	d, err := NewHDFSDownloader(aw, server.Client(), server.URL, ptrs.Ptr("determined"),
+		"ckpts/abc")
+	require.NoError(t, err)
+
+	entries, err := d.ListFiles(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []archive.FileEntry{
+		{Path: "metadata.json", Size: 23},
+		{Path: "state/model.pt", Size: 7},
+	}, entries)
+
+	b, err := d.ReadRange(ctx, "state/model.pt", 2, 3)
+	require.NoError(t, err)
+	require.Equal(t, "igh", string(b))
+
+	require.NoError(t, d.Download(ctx))
+	require.NoError(t, d.Close())
+	tr := tar.NewReader(&buf)
+	got := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		got[hdr.Name] = string(content)
+	}
+	require.Equal(t, map[string]string{
+		"metadata.json":  files["/ckpts/abc/metadata.json"],
+		"state/model.pt": files["/ckpts/abc/state/model.pt"],
+	}, got)
+
+	missing, err := NewHDFSDownloader(aw, server.Client(), server.URL, ptrs.Ptr("determined"),
+		path.Join("ckpts", "missing"))
+	require.NoError(t, err)
+	_, err = missing.ListFiles(ctx)
+	require.ErrorContains(t, err, "FileNotFoundException")
+}
+
+func TestHDFSStore(t *testing.T) {
+	ctx := context.Background()
+	files := map[string]string{}
+	server := newFakeWebHDFS(t, files)
+
+	s, err := NewHDFSStore(server.Client(), server.URL, ptrs.Ptr("determined"), "ckpts")
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(ctx, "probe", []byte("written")))
+	require.Equal(t, map[string]string{"/ckpts/probe": "written"}, files)
+
+	b, err := s.Get(ctx, "probe")
+	require.NoError(t, err)
+	require.Equal(t, "written", string(b))
+
+	require.NoError(t, s.Delete(ctx, "probe"))
+	require.Empty(t, files)
+	_, err = s.Get(ctx, "probe")
+	require.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package checkpoints
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/determined-ai/determined/master/pkg/checkpoints/inspect"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// checksumChunkSize is how much of a file is read at a time to checksum it.
+const checksumChunkSize = 8 << 20
+
+// ParseImportPath parses the path of a checkpoint in external storage, like
+// s3://bucket/prefix/<uuid>, into its UUID, checking that it is in the checkpoint storage it is
+// imported from. Checkpoints are found in their storage by their UUIDs, so the directory of the
+// checkpoint must be named by one, and must be directly under the prefix of the storage.
+func ParseImportPath(importPath string, storage expconf.CheckpointStorageConfig) (uuid.UUID, error) {
+	if storage.RawS3Config == nil {
+		return uuid.Nil, fmt.Errorf("only checkpoints in S3 checkpoint storage can be imported")
+	}
+	u, err := url.Parse(importPath)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parsing checkpoint path %s: %w", importPath, err)
+	}
+	if u.Scheme != "s3" {
+		return uuid.Nil, fmt.Errorf(
+			"checkpoint path %s is not an s3:// path; only checkpoints in S3 can be imported",
+			importPath)
+	}
+	if u.Host == "" {
+		return uuid.Nil, fmt.Errorf("checkpoint path %s has no bucket", importPath)
+	}
+
+	dir, name := path.Split(strings.Trim(u.Path, "/"))
+	id, err := uuid.Parse(name)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf(
+			"the directory of checkpoint path %s must be named by the UUID of the checkpoint: %w",
+			importPath, err)
+	}
+
+	bucket := storage.RawS3Config.Bucket()
+	prefix := ""
+	if p := storage.RawS3Config.Prefix(); p != nil {
+		prefix = strings.Trim(*p, "/")
+	}
+	if u.Host != bucket || strings.TrimSuffix(dir, "/") != prefix {
+		want := "s3://" + path.Join(bucket, prefix, "<checkpoint uuid>")
+		return uuid.Nil, fmt.Errorf(
+			"checkpoint path %s is not in the checkpoint storage of the workspace: it must be %s",
+			importPath, want)
+	}
+	return id, nil
+}
+
+// Checksum returns the SHA-256 checksum of a file of a checkpoint, in the form sha256:<hex>.
+func Checksum(ctx context.Context, r inspect.Reader, filePath string, size int64) (string, error) {
+	h := sha256.New()
+	for offset := int64(0); offset < size; {
+		chunk, err := r.ReadRange(ctx, filePath, offset, min(checksumChunkSize, size-offset))
+		if err != nil {
+			return "", fmt.Errorf("reading %s: %w", filePath, err)
+		}
+		if len(chunk) == 0 {
+			return "", fmt.Errorf("reading %s: file ended at %d of %d bytes", filePath, offset, size)
+		}
+		h.Write(chunk)
+		offset += int64(len(chunk))
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
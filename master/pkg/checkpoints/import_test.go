@@ -0,0 +1,75 @@
+package checkpoints
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestParseImportPath(t *testing.T) {
+	id := uuid.New()
+	//nolint:exhaustruct
+	storage := expconf.CheckpointStorageConfig{RawS3Config: &expconf.S3Config{
+		RawBucket: ptrs.Ptr("bucket"),
+		RawPrefix: ptrs.Ptr("/models/bert/"),
+	}}
+
+	got, err := ParseImportPath("s3://bucket/models/bert/"+id.String()+"/", storage)
+	require.NoError(t, err)
+	require.Equal(t, id, got)
+
+	_, err = ParseImportPath("s3://other-bucket/models/bert/"+id.String(), storage)
+	require.ErrorContains(t, err, "not in the checkpoint storage of the workspace")
+	_, err = ParseImportPath("s3://bucket/"+id.String(), storage)
+	require.ErrorContains(t, err, "not in the checkpoint storage of the workspace")
+	_, err = ParseImportPath("s3://bucket/models/bert/nested/"+id.String(), storage)
+	require.ErrorContains(t, err, "not in the checkpoint storage of the workspace")
+
+	storage.RawS3Config.RawPrefix = nil
+	got, err = ParseImportPath("s3://bucket/"+id.String(), storage)
+	require.NoError(t, err)
+	require.Equal(t, id, got)
+
+	_, err = ParseImportPath("gs://bucket/"+id.String(), storage)
+	require.ErrorContains(t, err, "not an s3:// path")
+	_, err = ParseImportPath("s3:///"+id.String(), storage)
+	require.ErrorContains(t, err, "has no bucket")
+	_, err = ParseImportPath("s3://bucket/models/bert", storage)
+	require.ErrorContains(t, err, "must be named by the UUID")
+
+	//nolint:exhaustruct
+	_, err = ParseImportPath("s3://bucket/"+id.String(), expconf.CheckpointStorageConfig{
+		RawSharedFSConfig: &expconf.SharedFSConfig{},
+	})
+	require.ErrorContains(t, err, "only checkpoints in S3 checkpoint storage")
+}
+
+// shortReader reads files of a checkpoint at most a few bytes at a time.
+type shortReader map[string][]byte
+
+func (r shortReader) ReadRange(
+	_ context.Context, path string, offset, length int64,
+) ([]byte, error) {
+	b := r[path][offset:]
+	return b[:min(length, 3, int64(len(b)))], nil
+}
+
+func TestChecksum(t *testing.T) {
+	contents := []byte("the weights of a model")
+	sum := sha256.Sum256(contents)
+	r := shortReader{"model.pt": contents}
+
+	checksum, err := Checksum(context.Background(), r, "model.pt", int64(len(contents)))
+	require.NoError(t, err)
+	require.Equal(t, "sha256:"+hex.EncodeToString(sum[:]), checksum)
+
+	_, err = Checksum(context.Background(), r, "model.pt", int64(len(contents))+1)
+	require.ErrorContains(t, err, "file ended")
+}
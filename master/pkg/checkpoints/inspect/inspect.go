@@ -0,0 +1,305 @@
+// Package inspect summarizes a checkpoint from its file list and the parts of its files that
+// describe it, so it can be checked without downloading it.
+package inspect
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+)
+
+const (
+	// MaxMetadataFileSize is the largest metadata file whose contents are included in a summary.
+	MaxMetadataFileSize = 1 << 20
+	// MaxSafetensorsHeaderSize is the largest safetensors header that is read.
+	MaxSafetensorsHeaderSize = 16 << 20
+	// MaxSafetensorsFiles is the most safetensors files whose headers are read.
+	MaxSafetensorsFiles = 64
+	// MaxTensors is the most tensors listed in a model summary. Parameter counts include all
+	// tensors.
+	MaxTensors = 1000
+)
+
+// metadataFiles are the files, by base name, that describe a checkpoint and are small enough to
+// include in its summary: Determined's own metadata, and Hugging Face model and generation
+// configs.
+var metadataFiles = map[string]bool{
+	"metadata.json":          true,
+	"load_data.json":         true,
+	"config.json":            true,
+	"generation_config.json": true,
+}
+
+// frameworkFiles detect the framework a checkpoint was saved with from its file names.
+var frameworkFiles = []struct {
+	framework string
+	match     func(name string) bool
+}{
+	{"pytorch", func(name string) bool {
+		return hasExt(name, ".pt", ".pth") || name == "pytorch_model.bin" ||
+			(strings.HasPrefix(name, "pytorch_model-") && hasExt(name, ".bin"))
+	}},
+	{"tensorflow", func(name string) bool { return name == "saved_model.pb" || hasExt(name, ".index") }},
+	{"keras", func(name string) bool { return hasExt(name, ".h5", ".keras") }},
+	{"safetensors", func(name string) bool { return hasExt(name, ".safetensors") }},
+	{"onnx", func(name string) bool { return hasExt(name, ".onnx") }},
+	{"deepspeed", func(name string) bool { return strings.HasSuffix(name, "_optim_states.pt") }},
+}
+
+func hasExt(name string, exts ...string) bool {
+	for _, ext := range exts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reader reads parts of a checkpoint's files.
+type Reader interface {
+	ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error)
+}
+
+// File is a file in a checkpoint.
+type File struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Tensor is a tensor stored in a checkpoint.
+type Tensor struct {
+	Name       string  `json:"name"`
+	DType      string  `json:"dtype"`
+	Shape      []int64 `json:"shape"`
+	Parameters int64   `json:"parameters"`
+}
+
+// Model summarizes the tensors stored in a model file.
+type Model struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+	// Tensors are the first MaxTensors tensors, by name.
+	Tensors     []Tensor `json:"tensors"`
+	TensorCount int      `json:"tensor_count"`
+	// Parameters counts the parameters of all tensors, in total and by dtype.
+	Parameters        int64            `json:"parameters"`
+	ParametersByDType map[string]int64 `json:"parameters_by_dtype"`
+	// Metadata is the free-form metadata stored with the tensors.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Summary summarizes a checkpoint.
+type Summary struct {
+	Files     []File `json:"files"`
+	TotalSize int64  `json:"total_size"`
+	// Frameworks are the frameworks the checkpoint's files look like they were saved with.
+	Frameworks []string `json:"frameworks"`
+	Models     []Model  `json:"models"`
+	// Metadata holds the contents of the checkpoint's metadata files, by path.
+	Metadata map[string]json.RawMessage `json:"metadata"`
+	// Warnings explain what couldn't be inspected.
+	Warnings []string `json:"warnings"`
+}
+
+// Proto returns the summary as a protobuf message.
+func (s *Summary) Proto() *checkpointv1.CheckpointSummary {
+	pb := &checkpointv1.CheckpointSummary{
+		TotalSize:  s.TotalSize,
+		Frameworks: append([]string{}, s.Frameworks...),
+		Metadata:   protoutils.ToStruct(s.Metadata),
+		Warnings:   append([]string{}, s.Warnings...),
+	}
+	for _, f := range s.Files {
+		pb.Files = append(pb.Files, &checkpointv1.CheckpointFile{Path: f.Path, Size: f.Size})
+	}
+	for _, m := range s.Models {
+		model := &checkpointv1.CheckpointModel{
+			Path:              m.Path,
+			Format:            m.Format,
+			TensorCount:       int32(m.TensorCount),
+			Parameters:        m.Parameters,
+			ParametersByDtype: m.ParametersByDType,
+			Metadata:          m.Metadata,
+		}
+		for _, t := range m.Tensors {
+			model.Tensors = append(model.Tensors, &checkpointv1.CheckpointTensor{
+				Name:       t.Name,
+				Dtype:      t.DType,
+				Shape:      t.Shape,
+				Parameters: t.Parameters,
+			})
+		}
+		pb.Models = append(pb.Models, model)
+	}
+	return pb
+}
+
+// Inspect summarizes a checkpoint with the given files. If r is nil, the summary is based on file
+// names and sizes alone.
+func Inspect(ctx context.Context, r Reader, files []archive.FileEntry) (*Summary, error) {
+	s := &Summary{
+		Files:      make([]File, 0, len(files)),
+		Frameworks: []string{},
+		Models:     []Model{},
+		Metadata:   map[string]json.RawMessage{},
+		Warnings:   []string{},
+	}
+	frameworks := map[string]bool{}
+	for _, f := range files {
+		s.Files = append(s.Files, File{Path: f.Path, Size: f.Size})
+		s.TotalSize += f.Size
+		name := path.Base(f.Path)
+		for _, ff := range frameworkFiles {
+			if ff.match(name) {
+				frameworks[ff.framework] = true
+			}
+		}
+	}
+	sort.Slice(s.Files, func(i, j int) bool { return s.Files[i].Path < s.Files[j].Path })
+
+	if r == nil {
+		s.Warnings = append(s.Warnings, "the checkpoint's storage can't be read by the master, so "+
+			"only its file names and sizes were inspected")
+	} else {
+		safetensors := 0
+		for _, f := range s.Files {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			name := path.Base(f.Path)
+			switch {
+			case metadataFiles[name]:
+				inspectMetadataFile(ctx, r, f, s)
+			case hasExt(name, ".safetensors"):
+				if safetensors++; safetensors > MaxSafetensorsFiles {
+					continue
+				}
+				inspectSafetensorsFile(ctx, r, f, s)
+			}
+		}
+		if safetensors > MaxSafetensorsFiles {
+			s.Warnings = append(s.Warnings, fmt.Sprintf("only the first %d of %d safetensors files "+
+				"were inspected", MaxSafetensorsFiles, safetensors))
+		}
+	}
+
+	// Hugging Face configs name the model's architecture.
+	for p, m := range s.Metadata {
+		if path.Base(p) != "config.json" {
+			continue
+		}
+		var config struct {
+			ModelType     string   `json:"model_type"`
+			Architectures []string `json:"architectures"`
+		}
+		if json.Unmarshal(m, &config) == nil && (config.ModelType != "" || len(config.Architectures) > 0) {
+			frameworks["transformers"] = true
+		}
+	}
+	for f := range frameworks {
+		s.Frameworks = append(s.Frameworks, f)
+	}
+	sort.Strings(s.Frameworks)
+	return s, nil
+}
+
+func inspectMetadataFile(ctx context.Context, r Reader, f File, s *Summary) {
+	if f.Size > MaxMetadataFileSize {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("%s is too large to inspect", f.Path))
+		return
+	}
+	b, err := r.ReadRange(ctx, f.Path, 0, f.Size)
+	if err != nil {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("reading %s: %s", f.Path, err))
+		return
+	}
+	if !json.Valid(b) {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("%s isn't valid JSON", f.Path))
+		return
+	}
+	s.Metadata[f.Path] = b
+}
+
+func inspectSafetensorsFile(ctx context.Context, r Reader, f File, s *Summary) {
+	m, err := readSafetensorsHeader(ctx, r, f)
+	if err != nil {
+		s.Warnings = append(s.Warnings, fmt.Sprintf("reading tensors of %s: %s", f.Path, err))
+		return
+	}
+	s.Models = append(s.Models, *m)
+}
+
+// readSafetensorsHeader reads the tensors of a safetensors file from its header, which is a JSON
+// object that follows its length, as a little-endian uint64, at the start of the file.
+func readSafetensorsHeader(ctx context.Context, r Reader, f File) (*Model, error) {
+	if f.Size < 8 {
+		return nil, fmt.Errorf("file is too short")
+	}
+	b, err := r.ReadRange(ctx, f.Path, 0, 8)
+	if err != nil {
+		return nil, err
+	} else if len(b) < 8 {
+		return nil, fmt.Errorf("file is too short")
+	}
+	size := binary.LittleEndian.Uint64(b)
+	if size > MaxSafetensorsHeaderSize || int64(size) > f.Size-8 {
+		return nil, fmt.Errorf("header of %d bytes is too large", size)
+	}
+	header, err := r.ReadRange(ctx, f.Path, 8, int64(size))
+	if err != nil {
+		return nil, err
+	}
+	return parseSafetensorsHeader(f.Path, header)
+}
+
+func parseSafetensorsHeader(p string, header []byte) (*Model, error) {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(header, &entries); err != nil {
+		return nil, fmt.Errorf("parsing header: %w", err)
+	}
+
+	m := &Model{
+		Path:              p,
+		Format:            "safetensors",
+		Tensors:           []Tensor{},
+		ParametersByDType: map[string]int64{},
+	}
+	for name, raw := range entries {
+		if name == "__metadata__" {
+			if err := json.Unmarshal(raw, &m.Metadata); err != nil {
+				return nil, fmt.Errorf("parsing metadata: %w", err)
+			}
+			continue
+		}
+		var t struct {
+			DType string  `json:"dtype"`
+			Shape []int64 `json:"shape"`
+		}
+		if err := json.Unmarshal(raw, &t); err != nil {
+			return nil, fmt.Errorf("parsing tensor %s: %w", name, err)
+		}
+		params := int64(1)
+		for _, dim := range t.Shape {
+			params *= dim
+		}
+		m.TensorCount++
+		m.Parameters += params
+		m.ParametersByDType[t.DType] += params
+		m.Tensors = append(m.Tensors, Tensor{
+			Name: name, DType: t.DType, Shape: t.Shape, Parameters: params,
+		})
+	}
+	sort.Slice(m.Tensors, func(i, j int) bool { return m.Tensors[i].Name < m.Tensors[j].Name })
+	if len(m.Tensors) > MaxTensors {
+		m.Tensors = m.Tensors[:MaxTensors]
+	}
+	return m, nil
+}
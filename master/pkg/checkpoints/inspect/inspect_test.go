@@ -0,0 +1,124 @@
+package inspect
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+)
+
+type memReader map[string][]byte
+
+func (r memReader) ReadRange(_ context.Context, path string, offset, length int64) ([]byte, error) {
+	b := r[path]
+	end := offset + length
+	if end > int64(len(b)) {
+		end = int64(len(b))
+	}
+	return b[offset:end], nil
+}
+
+func (r memReader) files() []archive.FileEntry {
+	var files []archive.FileEntry
+	for p, b := range r {
+		files = append(files, archive.FileEntry{Path: p, Size: int64(len(b))})
+	}
+	return files
+}
+
+func safetensors(t *testing.T, header map[string]interface{}) []byte {
+	h, err := json.Marshal(header)
+	require.NoError(t, err)
+	b := binary.LittleEndian.AppendUint64(nil, uint64(len(h)))
+	b = append(b, h...)
+	return append(b, make([]byte, 64)...)
+}
+
+func TestInspect(t *testing.T) {
+	r := memReader{
+		"model.safetensors": safetensors(t, map[string]interface{}{
+			"__metadata__": map[string]string{"format": "pt"},
+			"lm_head.weight": map[string]interface{}{
+				"dtype": "BF16", "shape": []int{4, 8}, "data_offsets": []int{0, 64},
+			},
+			"embed.weight": map[string]interface{}{
+				"dtype": "F32", "shape": []int{2, 3}, "data_offsets": []int{64, 88},
+			},
+		}),
+		"config.json":    []byte(`{"model_type": "llama", "architectures": ["LlamaForCausalLM"]}`),
+		"metadata.json":  []byte(`{"steps_completed": 100}`),
+		"state_dict.pth": make([]byte, 10),
+		"notes.txt":      []byte("not inspected"),
+	}
+
+	s, err := Inspect(context.Background(), r, r.files())
+	require.NoError(t, err)
+	require.Empty(t, s.Warnings)
+	require.Len(t, s.Files, 5)
+	require.Equal(t, "config.json", s.Files[0].Path)
+	require.Equal(t, []string{"pytorch", "safetensors", "transformers"}, s.Frameworks)
+	require.JSONEq(t, `{"steps_completed": 100}`, string(s.Metadata["metadata.json"]))
+	require.NotContains(t, s.Metadata, "notes.txt")
+
+	require.Len(t, s.Models, 1)
+	m := s.Models[0]
+	require.Equal(t, 2, m.TensorCount)
+	require.Equal(t, int64(38), m.Parameters)
+	require.Equal(t, map[string]int64{"BF16": 32, "F32": 6}, m.ParametersByDType)
+	require.Equal(t, "embed.weight", m.Tensors[0].Name)
+	require.Equal(t, []int64{2, 3}, m.Tensors[0].Shape)
+	require.Equal(t, "pt", m.Metadata["format"])
+}
+
+func TestInspectWithoutReader(t *testing.T) {
+	files := []archive.FileEntry{{Path: "saved_model.pb", Size: 3}, {Path: "variables/variables.index", Size: 2}}
+	s, err := Inspect(context.Background(), nil, files)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), s.TotalSize)
+	require.Equal(t, []string{"tensorflow"}, s.Frameworks)
+	require.Empty(t, s.Models)
+	require.Len(t, s.Warnings, 1)
+}
+
+func TestInspectBadSafetensors(t *testing.T) {
+	r := memReader{
+		"short.safetensors": []byte{1, 2},
+		"huge.safetensors":  binary.LittleEndian.AppendUint64(nil, 1<<40),
+	}
+	s, err := Inspect(context.Background(), r, r.files())
+	require.NoError(t, err)
+	require.Empty(t, s.Models)
+	require.Len(t, s.Warnings, 2)
+}
+
+func TestSummaryProto(t *testing.T) {
+	s := &Summary{
+		Files:      []File{{Path: "model.safetensors", Size: 120}},
+		TotalSize:  120,
+		Frameworks: []string{"safetensors"},
+		Models: []Model{{
+			Path:              "model.safetensors",
+			Format:            "safetensors",
+			Tensors:           []Tensor{{Name: "w", DType: "F32", Shape: []int64{2, 3}, Parameters: 6}},
+			TensorCount:       1,
+			Parameters:        6,
+			ParametersByDType: map[string]int64{"F32": 6},
+		}},
+		Metadata: map[string]json.RawMessage{"metadata.json": []byte(`{"steps_completed": 100}`)},
+		Warnings: []string{},
+	}
+
+	pb := s.Proto()
+	require.Equal(t, "model.safetensors", pb.Files[0].Path)
+	require.Equal(t, int64(120), pb.TotalSize)
+	require.Equal(t, int32(1), pb.Models[0].TensorCount)
+	require.Equal(t, []int64{2, 3}, pb.Models[0].Tensors[0].Shape)
+	require.Equal(t, map[string]int64{"F32": 6}, pb.Models[0].ParametersByDtype)
+	require.Equal(t, float64(100), pb.Metadata.Fields["metadata.json"].GetStructValue().
+		Fields["steps_completed"].GetNumberValue())
+	require.NotNil(t, pb.Warnings)
+}
@@ -2,6 +2,7 @@ package local
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -110,6 +111,25 @@ func (d *LocalDownloader) ListFiles(ctx context.Context) ([]archive.FileEntry, e
 	return d.files, nil
 }
 
+// ReadRange reads part of a file in the checkpoint.
+func (d *LocalDownloader) ReadRange(
+	ctx context.Context, path string, offset, length int64,
+) ([]byte, error) {
+	f, err := os.Open(filepath.Clean(d.prefix + path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	b := make([]byte, length)
+	n, err := f.ReadAt(b, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return b[:n], nil
+}
+
 // NewLocalDownloader returns a new LocalDownloader.
 func NewLocalDownloader(aw archive.ArchiveWriter, prefix string) (*LocalDownloader, error) {
 	if !strings.HasPrefix(prefix, "/") {
@@ -125,3 +145,28 @@ func NewLocalDownloader(aw archive.ArchiveWriter, prefix string) (*LocalDownload
 		buffer: make([]byte, DefaultDownloadPartSize),
 	}, nil
 }
+
+// LocalStore reads and writes single files on the local filesystem.
+type LocalStore struct {
+	prefix string
+}
+
+// Put writes data to the file key, replacing it if it exists.
+func (s *LocalStore) Put(_ context.Context, key string, data []byte) error {
+	return os.WriteFile(filepath.Join(s.prefix, key), data, 0o600)
+}
+
+// Get reads the file key.
+func (s *LocalStore) Get(_ context.Context, key string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.prefix, key))
+}
+
+// Delete deletes the file key.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	return os.Remove(filepath.Join(s.prefix, key))
+}
+
+// NewLocalStore returns a new LocalStore for the files in the directory prefix.
+func NewLocalStore(prefix string) *LocalStore {
+	return &LocalStore{prefix: filepath.Clean(prefix)}
+}
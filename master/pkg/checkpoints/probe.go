@@ -0,0 +1,46 @@
+package checkpoints
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// probePrefix names the probe objects, which are written next to checkpoints, so that leftovers
+// of a failed probe can be told apart from them.
+const probePrefix = ".determined-probe-"
+
+// objectStore is storage that single objects can be written to, read back from and deleted
+// from, by key.
+type objectStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// probe writes a probe object to store, reads it back and deletes it, which checks end to end
+// that the configuration and credentials of the storage allow checkpoints to be saved to it.
+func probe(ctx context.Context, store objectStore) error {
+	key := probePrefix + uuid.New().String()
+	data := []byte(fmt.Sprintf("determined checkpoint storage probe %s\n",
+		time.Now().UTC().Format(time.RFC3339)))
+
+	if err := store.Put(ctx, key, data); err != nil {
+		return fmt.Errorf("writing probe object %s: %w", key, err)
+	}
+	read, err := store.Get(ctx, key)
+	if err != nil {
+		err = fmt.Errorf("reading probe object %s: %w", key, err)
+	} else if !bytes.Equal(read, data) {
+		err = fmt.Errorf("reading probe object %s: got %d bytes that differ from the %d written",
+			key, len(read), len(data))
+	}
+	if delErr := store.Delete(ctx, key); delErr != nil {
+		err = errors.Join(err, fmt.Errorf("deleting probe object %s: %w", key, delErr))
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package checkpoints
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// corruptingStore is an objectStore that keeps its objects in memory, reading them back with
+// their first byte changed.
+type corruptingStore map[string][]byte
+
+func (s corruptingStore) Put(_ context.Context, key string, data []byte) error {
+	s[key] = append([]byte{}, data...)
+	return nil
+}
+
+func (s corruptingStore) Get(_ context.Context, key string) ([]byte, error) {
+	data, ok := s[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	corrupted := append([]byte{}, data...)
+	corrupted[0]++
+	return corrupted, nil
+}
+
+func (s corruptingStore) Delete(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func TestProbe(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	require.NoError(t, localBackend(dir).Probe(ctx))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries, "the probe object should be deleted")
+
+	err = localBackend(filepath.Join(dir, "missing")).Probe(ctx)
+	require.ErrorContains(t, err, "writing probe object")
+
+	store := corruptingStore{}
+	err = probe(ctx, store)
+	require.ErrorContains(t, err, "that differ from")
+	require.Empty(t, store, "the probe object should be deleted even if it was not read back")
+}
@@ -1,6 +1,7 @@
 package s3
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -8,10 +9,12 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 
+	"github.com/determined-ai/determined/master/internal/egress"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
 	"github.com/determined-ai/determined/master/pkg/ptrs"
 )
@@ -96,6 +99,27 @@ func (d *S3Downloader) ListFiles(ctx context.Context) ([]archive.FileEntry, erro
 	return d.files, nil
 }
 
+// ReadRange reads part of a file in the checkpoint.
+func (d *S3Downloader) ReadRange(
+	ctx context.Context, path string, offset, length int64,
+) ([]byte, error) {
+	if length <= 0 {
+		return nil, nil
+	}
+	out, err := d.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: &d.bucket,
+		Key:    ptrs.Ptr(d.prefix + path),
+		Range:  ptrs.Ptr(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = out.Body.Close()
+	}()
+	return io.ReadAll(io.LimitReader(out.Body, length))
+}
+
 // NewS3Downloader returns a new S3Downloader.
 func NewS3Downloader(
 	ctx context.Context,
@@ -111,6 +135,27 @@ func NewS3Downloader(
 
 	// We do not pass in credentials explicitly. Instead, we reply on
 	// the existing AWS credentials.
+	sess, err := newSession(ctx, bucket, endpointURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Downloader{
+		aw:     aw,
+		client: s3.New(sess),
+		downloader: s3manager.NewDownloader(sess, func(d *s3manager.Downloader) {
+			d.Concurrency = 1 // Setting concurrency to 1 to use seqWriterAt
+		}),
+		bucket: bucket,
+		prefix: prefix,
+	}, nil
+}
+
+// newSession returns a session for the region of bucket, with creds if they are set, or else
+// the existing AWS credentials.
+func newSession(
+	ctx context.Context, bucket string, endpointURL *string, creds *credentials.Credentials,
+) (*session.Session, error) {
 	var endpointFormat *string
 	if endpointURL != nil {
 		format := fmt.Sprint(*endpointURL, "/%s")
@@ -123,7 +168,11 @@ func NewS3Downloader(
 		return nil, err
 	}
 
-	awsConfig := &aws.Config{Region: &region}
+	awsConfig := &aws.Config{
+		Region:      &region,
+		Credentials: creds,
+		HTTPClient:  &http.Client{Transport: egress.Transport()}, //nolint:forbidigo
+	}
 
 	// configure for non-aws S3 providers
 	if endpointURL != nil {
@@ -132,20 +181,74 @@ func NewS3Downloader(
 		awsConfig.S3ForcePathStyle = aws.Bool(true)
 	}
 
-	sess, err := session.NewSession(awsConfig)
+	return session.NewSession(awsConfig)
+}
+
+// S3Store reads and writes single objects in S3.
+type S3Store struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+// Put writes data to the object key, replacing it if it exists.
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: &s.bucket,
+		Key:    ptrs.Ptr(s.prefix + key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+// Get reads the object key.
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    ptrs.Ptr(s.prefix + key),
+	})
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		_ = out.Body.Close()
+	}()
+	return io.ReadAll(out.Body)
+}
 
-	return &S3Downloader{
-		aw:     aw,
-		client: s3.New(sess),
-		downloader: s3manager.NewDownloader(sess, func(d *s3manager.Downloader) {
-			d.Concurrency = 1 // Setting concurrency to 1 to use seqWriterAt
-		}),
-		bucket: bucket,
-		prefix: prefix,
-	}, nil
+// Delete deletes the object key.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.bucket,
+		Key:    ptrs.Ptr(s.prefix + key),
+	})
+	return err
+}
+
+// NewS3Store returns a new S3Store for the objects under prefix. Unlike NewS3Downloader, it uses
+// the access key and secret key if they are both set, which is how tasks access the bucket.
+func NewS3Store(
+	ctx context.Context,
+	bucket string,
+	prefix string,
+	endpointURL *string,
+	accessKey *string,
+	secretKey *string,
+) (*S3Store, error) {
+	prefix = strings.TrimLeft(prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	var creds *credentials.Credentials
+	if accessKey != nil && secretKey != nil {
+		creds = credentials.NewStaticCredentials(*accessKey, *secretKey, "")
+	}
+	sess, err := newSession(ctx, bucket, endpointURL, creds)
+	if err != nil {
+		return nil, err
+	}
+	return &S3Store{client: s3.New(sess), bucket: bucket, prefix: prefix}, nil
 }
 
 // GetS3BucketRegion returns the region name of the specified bucket.
@@ -169,7 +272,7 @@ func GetS3BucketRegion(ctx context.Context, bucket string, endpointURL *string)
 		return "", fmt.Errorf("making request to get region of s3 bucket at url %s: %w", url, err)
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := egress.Client().Do(req)
 	if err != nil {
 		return "", fmt.Errorf("getting region of s3 bucket at url %s: %w", url, err)
 	}
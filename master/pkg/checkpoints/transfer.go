@@ -0,0 +1,155 @@
+package checkpoints
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrRangeNotSatisfiable is returned by ParseRange when the range lies outside the content.
+var ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// ByteRange is an inclusive range of bytes of a download.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+// Length returns the number of bytes in the range.
+func (r ByteRange) Length() int64 {
+	return r.End - r.Start + 1
+}
+
+// ContentRange returns the value of the Content-Range header for the range.
+func (r ByteRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End, size)
+}
+
+// ParseRange parses the value of a Range header against content of the given size. Only a
+// single range is supported, since that is all resuming a download needs. A nil range and nil
+// error mean the header should be ignored and the whole content sent.
+func ParseRange(header string, size int64) (*ByteRange, error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+	startStr, endStr, ok := strings.Cut(spec, "-")
+	if !ok {
+		return nil, nil
+	}
+
+	var r ByteRange
+	switch {
+	case startStr == "":
+		// A suffix range, e.g. "bytes=-500" for the last 500 bytes.
+		n, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || n < 0 {
+			return nil, nil
+		}
+		if n == 0 {
+			return nil, ErrRangeNotSatisfiable
+		}
+		r = ByteRange{Start: max(size-n, 0), End: size - 1}
+	default:
+		start, err := strconv.ParseInt(startStr, 10, 64)
+		if err != nil || start < 0 {
+			return nil, nil
+		}
+		end := size - 1
+		if endStr != "" {
+			if end, err = strconv.ParseInt(endStr, 10, 64); err != nil || end < start {
+				return nil, nil
+			}
+		}
+		r = ByteRange{Start: start, End: min(end, size-1)}
+	}
+
+	if r.Start >= size {
+		return nil, ErrRangeNotSatisfiable
+	}
+	return &r, nil
+}
+
+// RangeWriter passes through only the bytes of a stream that fall in a ByteRange and discards
+// the rest. Once the range has been written, done is called so the producer can stop early.
+type RangeWriter struct {
+	next   io.Writer
+	r      ByteRange
+	offset int64
+	done   func()
+}
+
+// NewRangeWriter returns a RangeWriter that writes the bytes of r to w.
+func NewRangeWriter(w io.Writer, r ByteRange, done func()) *RangeWriter {
+	return &RangeWriter{next: w, r: r, done: done}
+}
+
+// Write implements io.Writer. It always reports the full length of p as written so producers
+// keep going until the range is satisfied.
+func (w *RangeWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	start, end := w.offset, w.offset+int64(n)
+	w.offset = end
+	if end <= w.r.Start || start > w.r.End {
+		return n, nil
+	}
+
+	lo := max(w.r.Start-start, 0)
+	hi := min(w.r.End+1-start, int64(n))
+	if _, err := w.next.Write(p[lo:hi]); err != nil {
+		return 0, err
+	}
+	if w.Satisfied() && w.done != nil {
+		w.done()
+	}
+	return n, nil
+}
+
+// Satisfied returns whether every byte of the range has been written.
+func (w *RangeWriter) Satisfied() bool {
+	return w.offset > w.r.End
+}
+
+// throttledWriter limits the rate of bytes written to the next writer.
+type throttledWriter struct {
+	ctx     context.Context
+	next    io.Writer
+	limiter *rate.Limiter
+}
+
+// NewThrottledWriter returns a writer that writes to w at no more than bytesPerSec bytes per
+// second. A non-positive limit returns w unchanged.
+func NewThrottledWriter(ctx context.Context, w io.Writer, bytesPerSec int64) io.Writer {
+	if bytesPerSec <= 0 {
+		return w
+	}
+	burst := int(min(bytesPerSec, 1<<20))
+	return &throttledWriter{
+		ctx:     ctx,
+		next:    w,
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSec), burst),
+	}
+}
+
+// Write implements io.Writer, writing p in chunks no larger than the limiter's burst.
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), w.limiter.Burst())]
+		if err := w.limiter.WaitN(w.ctx, len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := w.next.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
@@ -0,0 +1,67 @@
+package checkpoints
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	cases := []struct {
+		header  string
+		want    *ByteRange
+		wantErr error
+	}{
+		{header: "", want: nil},
+		{header: "items=0-5", want: nil},
+		{header: "bytes=0-1,4-5", want: nil},
+		{header: "bytes=5-2", want: nil},
+		{header: "bytes=0-9", want: &ByteRange{Start: 0, End: 9}},
+		{header: "bytes=10-", want: &ByteRange{Start: 10, End: 99}},
+		{header: "bytes=90-200", want: &ByteRange{Start: 90, End: 99}},
+		{header: "bytes=-10", want: &ByteRange{Start: 90, End: 99}},
+		{header: "bytes=-200", want: &ByteRange{Start: 0, End: 99}},
+		{header: "bytes=100-", wantErr: ErrRangeNotSatisfiable},
+		{header: "bytes=-0", wantErr: ErrRangeNotSatisfiable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.header, func(t *testing.T) {
+			got, err := ParseRange(tc.header, 100)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRangeWriter(t *testing.T) {
+	var buf bytes.Buffer
+	done := 0
+	w := NewRangeWriter(&buf, ByteRange{Start: 3, End: 7}, func() { done++ })
+
+	for _, chunk := range []string{"01", "234", "5", "6789", "abc"} {
+		n, err := w.Write([]byte(chunk))
+		require.NoError(t, err)
+		require.Equal(t, len(chunk), n)
+	}
+	require.Equal(t, "34567", buf.String())
+	require.True(t, w.Satisfied())
+	require.Equal(t, 1, done)
+}
+
+func TestThrottledWriter(t *testing.T) {
+	var buf bytes.Buffer
+	require.Equal(t, &buf, NewThrottledWriter(context.Background(), &buf, 0))
+
+	w := NewThrottledWriter(context.Background(), &buf, 4)
+	n, err := w.Write([]byte("012345"))
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+	require.Equal(t, "012345", buf.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewThrottledWriter(ctx, &buf, 4).Write([]byte("01234567"))
+	require.Error(t, err)
+}
@@ -12,12 +12,22 @@ type LaunchWarning int
 const (
 	// CurrentSlotsExceeded represents a resource pool having insufficient slots.
 	CurrentSlotsExceeded LaunchWarning = 1
+	// DuplicateExperiment represents a workspace already having experiments identical to a
+	// created one.
+	DuplicateExperiment LaunchWarning = 2
+	// CurrentGPUMemoryUnavailable represents a resource pool having no agent whose GPUs have the
+	// memory requested.
+	CurrentGPUMemoryUnavailable LaunchWarning = 3
 )
 
 func toProtoEnum(l LaunchWarning) apiv1.LaunchWarning {
 	switch l {
 	case CurrentSlotsExceeded:
 		return apiv1.LaunchWarning_LAUNCH_WARNING_CURRENT_SLOTS_EXCEEDED
+	case DuplicateExperiment:
+		return apiv1.LaunchWarning_LAUNCH_WARNING_DUPLICATE_EXPERIMENT
+	case CurrentGPUMemoryUnavailable:
+		return apiv1.LaunchWarning_LAUNCH_WARNING_CURRENT_GPU_MEMORY_UNAVAILABLE
 	default:
 		panic(fmt.Sprintf("Unknown LaunchWarning value %v", l))
 	}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"errors"
+	"net/url"
+)
+
 // TelemetryConfig is the configuration for telemetry.
 type TelemetryConfig struct {
 	Enabled                  bool   `json:"enabled"`
@@ -8,4 +13,39 @@ type TelemetryConfig struct {
 	OtelExportedOtlpEndpoint string `json:"otel_endpoint"`
 	SegmentWebUIKey          string `json:"segment_webui_key"`
 	ClusterID                string `json:"cluster_id"`
+	// Export, if set, replaces reporting to Segment with writing to a local sink.
+	Export *TelemetryExportConfig `json:"export"`
+}
+
+// SegmentEnabled returns whether the master and tasks report telemetry directly to Segment.
+func (c TelemetryConfig) SegmentEnabled() bool {
+	return c.Enabled && c.Export == nil && c.SegmentMasterKey != ""
+}
+
+// WebUISegmentEnabled returns whether the WebUI reports telemetry directly to Segment.
+func (c TelemetryConfig) WebUISegmentEnabled() bool {
+	return c.Enabled && c.Export == nil && c.SegmentWebUIKey != ""
+}
+
+// TelemetryExportConfig configures a local sink for telemetry, for clusters that can't reach
+// Segment. Its records can be reviewed and forwarded manually. Exactly one of Path and URL must be
+// set.
+type TelemetryExportConfig struct {
+	// Path is a file that records are appended to as JSON lines.
+	Path string `json:"path"`
+	// URL is an endpoint that records are POSTed to as JSON.
+	URL string `json:"url"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *TelemetryExportConfig) Validate() []error {
+	switch {
+	case (c.Path == "") == (c.URL == ""):
+		return []error{errors.New("exactly one of telemetry.export.path and url must be set")}
+	case c.URL != "":
+		if u, err := url.Parse(c.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return []error{errors.New("telemetry.export.url must be a URL")}
+		}
+	}
+	return nil
 }
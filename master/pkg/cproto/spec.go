@@ -32,6 +32,15 @@ type RunSpec struct {
 	Archives   []RunArchive
 	DeviceType device.Type
 	Registry   *registry.AuthConfig
+	// Scratch is the scratch volume the agent provisions for the container, if any.
+	Scratch *ScratchSpec
+}
+
+// ScratchSpec describes a scratch volume an agent makes on its local disk for a container, and
+// removes when the container exits.
+type ScratchSpec struct {
+	ContainerPath string
+	SizeGiB       int
 }
 
 // ChecksConfig describes the configuration for multiple readiness checks.
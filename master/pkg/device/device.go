@@ -45,6 +45,8 @@ type Device struct {
 	Brand string `json:"brand"`
 	UUID  string `json:"uuid"`
 	Type  Type   `json:"type"`
+	// MemoryMiB is the total memory of a GPU, or 0 if it is unknown.
+	MemoryMiB int `json:"memory_mib,omitempty"`
 }
 
 func (d *Device) String() string {
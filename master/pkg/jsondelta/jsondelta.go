@@ -0,0 +1,130 @@
+// Package jsondelta computes and applies deltas between JSON objects, so that a large document that
+// changes a little at a time can be stored as a base and a series of small deltas.
+//
+// A delta is a JSON object that maps each key that changed to one of:
+//
+//	{"set": <value>}      the key was added or its value replaced
+//	{"delete": true}      the key was removed
+//	{"patch": <delta>}    the value is an object, and the delta applies to it
+//
+// Unlike a JSON merge patch, a delta can set values to null, which Go marshals nil pointers, maps
+// and slices to.
+package jsondelta
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+type op struct {
+	Set    json.RawMessage `json:"set,omitempty"`
+	Delete bool            `json:"delete,omitempty"`
+	Patch  map[string]op   `json:"patch,omitempty"`
+}
+
+func decodeObject(doc []byte) (map[string]any, error) {
+	d := json.NewDecoder(bytes.NewReader(doc))
+	// Numbers are kept as written so that large integers, like random number generator state,
+	// survive.
+	d.UseNumber()
+	var obj map[string]any
+	if err := d.Decode(&obj); err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+	return obj, nil
+}
+
+// Diff returns the delta from the JSON object from to the JSON object to, or nil if they are equal.
+func Diff(from, to []byte) ([]byte, error) {
+	fromObj, err := decodeObject(from)
+	if err != nil {
+		return nil, fmt.Errorf("decoding original: %w", err)
+	}
+	toObj, err := decodeObject(to)
+	if err != nil {
+		return nil, fmt.Errorf("decoding modified: %w", err)
+	}
+	delta, err := diff(fromObj, toObj)
+	if err != nil || len(delta) == 0 {
+		return nil, err
+	}
+	return json.Marshal(delta)
+}
+
+func diff(from, to map[string]any) (map[string]op, error) {
+	delta := map[string]op{}
+	for k := range from {
+		if _, ok := to[k]; !ok {
+			delta[k] = op{Delete: true}
+		}
+	}
+	for k, v := range to {
+		old, ok := from[k]
+		if ok && reflect.DeepEqual(old, v) {
+			continue
+		}
+		oldObj, oldIsObj := old.(map[string]any)
+		newObj, newIsObj := v.(map[string]any)
+		if ok && oldIsObj && newIsObj {
+			patch, err := diff(oldObj, newObj)
+			if err != nil {
+				return nil, err
+			}
+			delta[k] = op{Patch: patch}
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		delta[k] = op{Set: b}
+	}
+	return delta, nil
+}
+
+// Apply returns the JSON object doc with the delta applied.
+func Apply(doc, delta []byte) ([]byte, error) {
+	obj, err := decodeObject(doc)
+	if err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+	var ops map[string]op
+	if err := json.Unmarshal(delta, &ops); err != nil {
+		return nil, fmt.Errorf("decoding delta: %w", err)
+	}
+	if err := apply(obj, ops); err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+func apply(obj map[string]any, ops map[string]op) error {
+	for k, o := range ops {
+		switch {
+		case o.Delete:
+			delete(obj, k)
+		case o.Set != nil:
+			var v any
+			d := json.NewDecoder(bytes.NewReader(o.Set))
+			d.UseNumber()
+			if err := d.Decode(&v); err != nil {
+				return fmt.Errorf("decoding value of %q: %w", k, err)
+			}
+			obj[k] = v
+		default:
+			child, ok := obj[k].(map[string]any)
+			if !ok {
+				return fmt.Errorf("cannot patch %q, which is not an object", k)
+			}
+			if err := apply(child, o.Patch); err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package jsondelta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffApply(t *testing.T) {
+	from := `{
+		"rand": {"seed": 18446744073709551615},
+		"trials": {"a": {"hparams": {"lr": 0.1}, "closed": false}, "b": {"closed": true}},
+		"removed": [1, 2],
+		"unchanged": {"x": null}
+	}`
+	to := `{
+		"rand": {"seed": 18446744073709551614},
+		"trials": {"a": {"hparams": {"lr": 0.1}, "closed": true}, "c": {"checkpoint": null}},
+		"unchanged": {"x": null},
+		"progress": null
+	}`
+
+	delta, err := Diff([]byte(from), []byte(to))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"rand": {"patch": {"seed": {"set": 18446744073709551614}}},
+		"trials": {"patch": {
+			"a": {"patch": {"closed": {"set": true}}},
+			"b": {"delete": true},
+			"c": {"set": {"checkpoint": null}}
+		}},
+		"removed": {"delete": true},
+		"progress": {"set": null}
+	}`, string(delta))
+
+	applied, err := Apply([]byte(from), delta)
+	require.NoError(t, err)
+	require.JSONEq(t, to, string(applied))
+
+	delta, err = Diff([]byte(to), []byte(to))
+	require.NoError(t, err)
+	require.Nil(t, delta)
+
+	_, err = Diff([]byte(`[]`), []byte(to))
+	require.Error(t, err)
+	_, err = Apply([]byte(`{"a": 1}`), []byte(`{"a": {"patch": {"b": {"set": 1}}}}`))
+	require.ErrorContains(t, err, "not an object")
+}
@@ -35,3 +35,42 @@ func Clamp[T constraints.Ordered](min, val, max T) T {
 	}
 	return Min(Max(min, val), max)
 }
+
+// Quantile returns the q-th quantile, for q between 0 and 1, of a non-empty slice sorted in
+// ascending order, interpolating linearly between samples.
+func Quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i] + frac*(sorted[i+1]-sorted[i])
+}
+
+// Median returns the median of a non-empty slice sorted in ascending order.
+func Median(sorted []float64) float64 {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// Mean returns the arithmetic mean of a non-empty slice.
+func Mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Variance returns the sample variance of a slice of at least two values with the given mean.
+func Variance(values []float64, mean float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(values)-1)
+}
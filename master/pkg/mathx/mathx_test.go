@@ -25,3 +25,24 @@ func TestClamp(t *testing.T) {
 	require.Equal(t, 1, Clamp(1, 0, 3))
 	require.Panics(t, func() { Clamp(3, 0, 1) })
 }
+
+func TestQuantile(t *testing.T) {
+	require.Equal(t, 7.0, Quantile([]float64{7}, 0.9))
+	sorted := []float64{1, 2, 3, 4}
+	require.Equal(t, 1.0, Quantile(sorted, 0))
+	require.Equal(t, 2.5, Quantile(sorted, 0.5))
+	require.Equal(t, 1.75, Quantile(sorted, 0.25))
+	require.Equal(t, 4.0, Quantile(sorted, 1))
+}
+
+func TestMedian(t *testing.T) {
+	require.Equal(t, 7.0, Median([]float64{7}))
+	require.Equal(t, 2.0, Median([]float64{1, 2, 3}))
+	require.Equal(t, 2.5, Median([]float64{1, 2, 3, 4}))
+}
+
+func TestMeanAndVariance(t *testing.T) {
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	require.Equal(t, 5.0, Mean(values))
+	require.InDelta(t, 32.0/7, Variance(values, 5), 1e-12)
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// AllocationDiagnostics is the bun model of the diagnostics bundle collected when an allocation
+// fails, so that its failure can be looked into without access to the nodes it ran on.
+type AllocationDiagnostics struct {
+	bun.BaseModel `bun:"table:allocation_diagnostics"`
+	ID            int                    `bun:"id,pk,autoincrement" json:"id"`
+	TaskID        TaskID                 `bun:"task_id" json:"task_id"`
+	AllocationID  AllocationID           `bun:"allocation_id" json:"allocation_id"`
+	ExitReason    string                 `bun:"exit_reason" json:"exit_reason"`
+	Resources     []ResourcesDiagnostics `bun:"resources,type:jsonb" json:"resources"`
+	// NCCLEnv holds the NCCL_* environment variables the containers of the allocation ran with.
+	NCCLEnv   map[string]string `bun:"nccl_env,type:jsonb" json:"nccl_env"`
+	CreatedAt time.Time         `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// ResourcesDiagnostics is how a single container, or other unit of resources, of a failed
+// allocation exited.
+type ResourcesDiagnostics struct {
+	ResourcesID string   `json:"resources_id"`
+	Rank        int      `json:"rank"`
+	AgentIDs    []string `json:"agent_ids"`
+	ContainerID string   `json:"container_id,omitempty"`
+	// ExitCode and Failure are unset for resources that exited successfully, or that were still
+	// running when the allocation failed.
+	ExitCode  *int     `json:"exit_code,omitempty"`
+	Failure   string   `json:"failure,omitempty"`
+	OOMKilled bool     `json:"oom_killed"`
+	KernelLog []string `json:"kernel_log,omitempty"`
+}
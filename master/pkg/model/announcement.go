@@ -0,0 +1,128 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+// AnnouncementSeverity is how prominently an announcement is shown to users.
+type AnnouncementSeverity string
+
+const (
+	// AnnouncementSeverityInfo is for routine notices.
+	AnnouncementSeverityInfo AnnouncementSeverity = "info"
+	// AnnouncementSeverityWarning is for notices users should act on.
+	AnnouncementSeverityWarning AnnouncementSeverity = "warning"
+	// AnnouncementSeverityCritical is for outages and other urgent notices.
+	AnnouncementSeverityCritical AnnouncementSeverity = "critical"
+)
+
+// Valid returns whether s is a known severity.
+func (s AnnouncementSeverity) Valid() bool {
+	switch s {
+	case AnnouncementSeverityInfo, AnnouncementSeverityWarning, AnnouncementSeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+// AnnouncementSeverityFromProto converts a protobuf severity. Unspecified severities convert to
+// the empty severity, which announcements default from, and unknown ones to invalid severities.
+func AnnouncementSeverityFromProto(s masterv1.AnnouncementSeverity) AnnouncementSeverity {
+	switch s {
+	case masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_UNSPECIFIED:
+		return ""
+	case masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_INFO:
+		return AnnouncementSeverityInfo
+	case masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_WARNING:
+		return AnnouncementSeverityWarning
+	case masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_CRITICAL:
+		return AnnouncementSeverityCritical
+	default:
+		return AnnouncementSeverity(s.String())
+	}
+}
+
+// Proto converts a severity to its protobuf representation.
+func (s AnnouncementSeverity) Proto() masterv1.AnnouncementSeverity {
+	switch s {
+	case AnnouncementSeverityInfo:
+		return masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_INFO
+	case AnnouncementSeverityWarning:
+		return masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_WARNING
+	case AnnouncementSeverityCritical:
+		return masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_CRITICAL
+	default:
+		return masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_UNSPECIFIED
+	}
+}
+
+// Announcement is the bun model of a cluster-wide announcement shown to users between its start
+// and end times. An announcement with no end time is shown until it is deleted.
+type Announcement struct {
+	bun.BaseModel `bun:"table:announcements"`
+	ID            int                  `bun:"id,pk,autoincrement" json:"id"`
+	Message       string               `bun:"message" json:"message"`
+	Severity      AnnouncementSeverity `bun:"severity" json:"severity"`
+	StartTime     time.Time            `bun:"start_time" json:"start_time"`
+	EndTime       *time.Time           `bun:"end_time" json:"end_time"`
+	CreatedBy     UserID               `bun:"created_by" json:"created_by"`
+	CreatedAt     time.Time            `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Active returns whether the announcement is shown at now.
+func (a Announcement) Active(now time.Time) bool {
+	return !now.Before(a.StartTime) && (a.EndTime == nil || now.Before(*a.EndTime))
+}
+
+// Proto converts an announcement to its protobuf representation.
+func (a Announcement) Proto() *masterv1.Announcement {
+	pb := &masterv1.Announcement{
+		Id:        int32(a.ID),
+		Message:   a.Message,
+		Severity:  a.Severity.Proto(),
+		StartTime: timestamppb.New(a.StartTime),
+		CreatedBy: int32(a.CreatedBy),
+		CreatedAt: timestamppb.New(a.CreatedAt),
+	}
+	if a.EndTime != nil {
+		pb.EndTime = timestamppb.New(*a.EndTime)
+	}
+	return pb
+}
+
+// MaintenanceWindow is the bun model of a scheduled cluster maintenance. During the window, new
+// experiments and tasks can't be submitted if BlockSubmissions is set.
+type MaintenanceWindow struct {
+	bun.BaseModel    `bun:"table:maintenance_windows"`
+	ID               int       `bun:"id,pk,autoincrement" json:"id"`
+	Message          string    `bun:"message" json:"message"`
+	StartTime        time.Time `bun:"start_time" json:"start_time"`
+	EndTime          time.Time `bun:"end_time" json:"end_time"`
+	BlockSubmissions bool      `bun:"block_submissions" json:"block_submissions"`
+	CreatedBy        UserID    `bun:"created_by" json:"created_by"`
+	CreatedAt        time.Time `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Active returns whether the maintenance is underway at now.
+func (w MaintenanceWindow) Active(now time.Time) bool {
+	return !now.Before(w.StartTime) && now.Before(w.EndTime)
+}
+
+// Proto converts a maintenance window to its protobuf representation.
+func (w MaintenanceWindow) Proto() *masterv1.MaintenanceWindow {
+	return &masterv1.MaintenanceWindow{
+		Id:               int32(w.ID),
+		Message:          w.Message,
+		StartTime:        timestamppb.New(w.StartTime),
+		EndTime:          timestamppb.New(w.EndTime),
+		BlockSubmissions: w.BlockSubmissions,
+		CreatedBy:        int32(w.CreatedBy),
+		CreatedAt:        timestamppb.New(w.CreatedAt),
+	}
+}
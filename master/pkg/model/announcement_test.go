@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+func TestAnnouncementSeverityProto(t *testing.T) {
+	for _, s := range []AnnouncementSeverity{
+		AnnouncementSeverityInfo, AnnouncementSeverityWarning, AnnouncementSeverityCritical,
+	} {
+		require.Equal(t, s, AnnouncementSeverityFromProto(s.Proto()))
+	}
+	require.Equal(t, AnnouncementSeverity(""), AnnouncementSeverityFromProto(
+		masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_UNSPECIFIED))
+	require.False(t, AnnouncementSeverityFromProto(masterv1.AnnouncementSeverity(10)).Valid())
+}
+
+func TestAnnouncementProto(t *testing.T) {
+	now := time.Now()
+	pb := Announcement{ID: 2, Message: "m", Severity: AnnouncementSeverityWarning}.Proto()
+	require.Equal(t, masterv1.AnnouncementSeverity_ANNOUNCEMENT_SEVERITY_WARNING, pb.Severity)
+	require.Nil(t, pb.EndTime)
+
+	pb = Announcement{ID: 2, Message: "m", EndTime: &now}.Proto()
+	require.Equal(t, now.Unix(), pb.EndTime.AsTime().Unix())
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// BatchSizeProbe is the bun model of a short run of the trials of an experiment at one batch size,
+// to tell whether that batch size fits in memory. Fits is nil until the probe exits.
+type BatchSizeProbe struct {
+	bun.BaseModel `bun:"table:batch_size_probes"`
+	TaskID        TaskID    `bun:"task_id,pk" json:"task_id"`
+	ExperimentID  int       `bun:"experiment_id" json:"experiment_id"`
+	BatchSize     int       `bun:"batch_size" json:"batch_size"`
+	Fits          *bool     `bun:"fits" json:"fits"`
+	CreatedAt     time.Time `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// TunedBatchSize is the bun model of the batch size that batch size tuning chose for an experiment.
+type TunedBatchSize struct {
+	bun.BaseModel `bun:"table:tuned_batch_sizes"`
+	ExperimentID  int       `bun:"experiment_id,pk" json:"experiment_id"`
+	BatchSize     int       `bun:"batch_size" json:"batch_size"`
+	TunedAt       time.Time `bun:"tuned_at,scanonly" json:"tuned_at"`
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// BoostRequestKind is what a boost request asks to temporarily raise for an experiment.
+type BoostRequestKind string
+
+const (
+	// BoostRequestKindPriority asks for a higher scheduling priority.
+	BoostRequestKindPriority BoostRequestKind = "priority"
+	// BoostRequestKindMaxSlots asks for an exception to the experiment's max slots.
+	BoostRequestKindMaxSlots BoostRequestKind = "max_slots"
+)
+
+// BoostRequestKindFromProto converts a protobuf boost request kind, returning an error for unknown
+// kinds.
+func BoostRequestKindFromProto(k experimentv1.BoostRequestKind) (BoostRequestKind, error) {
+	switch k {
+	case experimentv1.BoostRequestKind_BOOST_REQUEST_KIND_PRIORITY:
+		return BoostRequestKindPriority, nil
+	case experimentv1.BoostRequestKind_BOOST_REQUEST_KIND_MAX_SLOTS:
+		return BoostRequestKindMaxSlots, nil
+	default:
+		return "", fmt.Errorf("invalid boost request kind %s, must be one of %s or %s",
+			k, experimentv1.BoostRequestKind_BOOST_REQUEST_KIND_PRIORITY,
+			experimentv1.BoostRequestKind_BOOST_REQUEST_KIND_MAX_SLOTS)
+	}
+}
+
+// Proto converts a boost request kind to its protobuf representation.
+func (k BoostRequestKind) Proto() experimentv1.BoostRequestKind {
+	switch k {
+	case BoostRequestKindPriority:
+		return experimentv1.BoostRequestKind_BOOST_REQUEST_KIND_PRIORITY
+	case BoostRequestKindMaxSlots:
+		return experimentv1.BoostRequestKind_BOOST_REQUEST_KIND_MAX_SLOTS
+	default:
+		return experimentv1.BoostRequestKind_BOOST_REQUEST_KIND_UNSPECIFIED
+	}
+}
+
+// BoostRequestState is where a boost request is in its approval workflow.
+type BoostRequestState string
+
+const (
+	// BoostRequestStatePending is a request waiting for an admin to review it.
+	BoostRequestStatePending BoostRequestState = "pending"
+	// BoostRequestStateApproved is a request whose boost is applied until it expires.
+	BoostRequestStateApproved BoostRequestState = "approved"
+	// BoostRequestStateDenied is a request an admin turned down.
+	BoostRequestStateDenied BoostRequestState = "denied"
+	// BoostRequestStateExpired is an approved request whose boost has been reverted.
+	BoostRequestStateExpired BoostRequestState = "expired"
+)
+
+// Valid returns whether s is a known state.
+func (s BoostRequestState) Valid() bool {
+	switch s {
+	case BoostRequestStatePending, BoostRequestStateApproved, BoostRequestStateDenied,
+		BoostRequestStateExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// BoostRequestStateFromProto converts a protobuf boost request state, returning an error for
+// unknown states.
+func BoostRequestStateFromProto(s experimentv1.BoostRequestState) (BoostRequestState, error) {
+	switch s {
+	case experimentv1.BoostRequestState_BOOST_REQUEST_STATE_PENDING:
+		return BoostRequestStatePending, nil
+	case experimentv1.BoostRequestState_BOOST_REQUEST_STATE_APPROVED:
+		return BoostRequestStateApproved, nil
+	case experimentv1.BoostRequestState_BOOST_REQUEST_STATE_DENIED:
+		return BoostRequestStateDenied, nil
+	case experimentv1.BoostRequestState_BOOST_REQUEST_STATE_EXPIRED:
+		return BoostRequestStateExpired, nil
+	default:
+		return "", fmt.Errorf("invalid boost request state %s", s)
+	}
+}
+
+// Proto converts a boost request state to its protobuf representation.
+func (s BoostRequestState) Proto() experimentv1.BoostRequestState {
+	switch s {
+	case BoostRequestStatePending:
+		return experimentv1.BoostRequestState_BOOST_REQUEST_STATE_PENDING
+	case BoostRequestStateApproved:
+		return experimentv1.BoostRequestState_BOOST_REQUEST_STATE_APPROVED
+	case BoostRequestStateDenied:
+		return experimentv1.BoostRequestState_BOOST_REQUEST_STATE_DENIED
+	case BoostRequestStateExpired:
+		return experimentv1.BoostRequestState_BOOST_REQUEST_STATE_EXPIRED
+	default:
+		return experimentv1.BoostRequestState_BOOST_REQUEST_STATE_UNSPECIFIED
+	}
+}
+
+// BoostRequest is the bun model of a user's request to temporarily raise an experiment's priority
+// or max slots. Once an admin approves it, the boost is applied until ExpiresAt, after which the
+// experiment is reverted to OriginalValue.
+type BoostRequest struct {
+	bun.BaseModel   `bun:"table:boost_requests"`
+	ID              int               `bun:"id,pk,autoincrement" json:"id"`
+	ExperimentID    int               `bun:"experiment_id" json:"experiment_id"`
+	Kind            BoostRequestKind  `bun:"kind" json:"kind"`
+	Value           int               `bun:"value" json:"value"`
+	DurationSeconds int               `bun:"duration_seconds" json:"duration_seconds"`
+	Reason          string            `bun:"reason" json:"reason"`
+	State           BoostRequestState `bun:"state" json:"state"`
+	RequestedBy     UserID            `bun:"requested_by" json:"requested_by"`
+	CreatedAt       time.Time         `bun:"created_at,scanonly" json:"created_at"`
+	ReviewedBy      *UserID           `bun:"reviewed_by" json:"reviewed_by"`
+	ReviewedAt      *time.Time        `bun:"reviewed_at" json:"reviewed_at"`
+	ReviewComment   *string           `bun:"review_comment" json:"review_comment"`
+	ExpiresAt       *time.Time        `bun:"expires_at" json:"expires_at"`
+	// OriginalValue is the priority or max slots the boost replaced. An unset original max slots
+	// means the experiment had no limit.
+	OriginalValue *int `bun:"original_value" json:"original_value"`
+}
+
+// Duration returns how long the boost is applied for once approved.
+func (r BoostRequest) Duration() time.Duration {
+	return time.Duration(r.DurationSeconds) * time.Second
+}
+
+// Proto converts a boost request to its protobuf representation.
+func (r BoostRequest) Proto() *experimentv1.BoostRequest {
+	pb := &experimentv1.BoostRequest{
+		Id:              int32(r.ID),
+		ExperimentId:    int32(r.ExperimentID),
+		Kind:            r.Kind.Proto(),
+		Value:           int32(r.Value),
+		DurationSeconds: int32(r.DurationSeconds),
+		Reason:          r.Reason,
+		State:           r.State.Proto(),
+		RequestedBy:     int32(r.RequestedBy),
+		CreatedAt:       timestamppb.New(r.CreatedAt),
+		ReviewComment:   r.ReviewComment,
+	}
+	if r.ReviewedBy != nil {
+		pb.ReviewedBy = ptrs.Ptr(int32(*r.ReviewedBy))
+	}
+	if r.ReviewedAt != nil {
+		pb.ReviewedAt = timestamppb.New(*r.ReviewedAt)
+	}
+	if r.ExpiresAt != nil {
+		pb.ExpiresAt = timestamppb.New(*r.ExpiresAt)
+	}
+	if r.OriginalValue != nil {
+		pb.OriginalValue = ptrs.Ptr(int32(*r.OriginalValue))
+	}
+	return pb
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+func TestBoostRequestEnumsProto(t *testing.T) {
+	for _, k := range []BoostRequestKind{BoostRequestKindPriority, BoostRequestKindMaxSlots} {
+		converted, err := BoostRequestKindFromProto(k.Proto())
+		require.NoError(t, err)
+		require.Equal(t, k, converted)
+	}
+	_, err := BoostRequestKindFromProto(experimentv1.BoostRequestKind_BOOST_REQUEST_KIND_UNSPECIFIED)
+	require.Error(t, err)
+
+	for _, s := range []BoostRequestState{
+		BoostRequestStatePending, BoostRequestStateApproved, BoostRequestStateDenied,
+		BoostRequestStateExpired,
+	} {
+		converted, err := BoostRequestStateFromProto(s.Proto())
+		require.NoError(t, err)
+		require.Equal(t, s, converted)
+	}
+	_, err = BoostRequestStateFromProto(experimentv1.BoostRequestState_BOOST_REQUEST_STATE_UNSPECIFIED)
+	require.Error(t, err)
+}
+
+func TestBoostRequestProto(t *testing.T) {
+	pb := BoostRequest{ID: 1, Kind: BoostRequestKindMaxSlots, State: BoostRequestStatePending}.Proto()
+	require.Nil(t, pb.ReviewedBy)
+	require.Nil(t, pb.ExpiresAt)
+	require.Nil(t, pb.OriginalValue)
+
+	reviewer := UserID(3)
+	expires := time.Now()
+	pb = BoostRequest{
+		ID:            1,
+		State:         BoostRequestStateApproved,
+		ReviewedBy:    &reviewer,
+		ExpiresAt:     &expires,
+		OriginalValue: ptrs.Ptr(8),
+	}.Proto()
+	require.Equal(t, int32(3), *pb.ReviewedBy)
+	require.Equal(t, expires.Unix(), pb.ExpiresAt.AsTime().Unix())
+	require.Equal(t, int32(8), *pb.OriginalValue)
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/proto/pkg/checkpointv1"
+)
+
+// CheckpointMetadataChange is the bun model of a change made to the metadata of a checkpoint after
+// it was reported.
+type CheckpointMetadataChange struct {
+	bun.BaseModel  `bun:"table:checkpoint_metadata_changes"`
+	ID             int                    `bun:"id,pk,autoincrement" json:"id"`
+	CheckpointUUID uuid.UUID              `bun:"checkpoint_uuid,type:uuid" json:"checkpoint_uuid"`
+	ChangedBy      *UserID                `bun:"changed_by" json:"changed_by"`
+	Previous       map[string]interface{} `bun:"previous,type:jsonb" json:"previous"`
+	Metadata       map[string]interface{} `bun:"metadata,type:jsonb" json:"metadata"`
+	ChangedAt      time.Time              `bun:"changed_at,nullzero,default:now()" json:"changed_at"`
+}
+
+// Proto converts a change made to the metadata of a checkpoint to its protobuf representation.
+func (c CheckpointMetadataChange) Proto() *checkpointv1.CheckpointMetadataChange {
+	pc := &checkpointv1.CheckpointMetadataChange{
+		Id:             int32(c.ID),
+		CheckpointUuid: c.CheckpointUUID.String(),
+		Previous:       protoutils.ToStruct(c.Previous),
+		Metadata:       protoutils.ToStruct(c.Metadata),
+		ChangedAt:      timestamppb.New(c.ChangedAt),
+	}
+	if c.ChangedBy != nil {
+		changedBy := int32(*c.ChangedBy)
+		pc.ChangedBy = &changedBy
+	}
+	return pc
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointMetadataChangeProto(t *testing.T) {
+	changedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	changedBy := UserID(7)
+	c := CheckpointMetadataChange{
+		ID:             2,
+		CheckpointUUID: uuid.New(),
+		ChangedBy:      &changedBy,
+		Previous:       map[string]interface{}{},
+		Metadata:       map[string]interface{}{"eval": map[string]interface{}{"mmlu": 0.61}},
+		ChangedAt:      changedAt,
+	}
+	pb := c.Proto()
+	require.Equal(t, int32(2), pb.Id)
+	require.Equal(t, c.CheckpointUUID.String(), pb.CheckpointUuid)
+	require.Equal(t, int32(7), pb.GetChangedBy())
+	require.Empty(t, pb.Previous.AsMap())
+	require.Equal(t, c.Metadata, pb.Metadata.AsMap())
+	require.Equal(t, changedAt, pb.ChangedAt.AsTime())
+
+	c.ChangedBy = nil
+	require.Nil(t, c.Proto().ChangedBy)
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"path/filepath"
+	"strings"
+
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 )
@@ -52,6 +55,7 @@ type CommandConfig struct {
 	Debug            bool                `json:"debug"`
 	Pbs              expconf.PbsConfig   `json:"pbs,omitempty"`
 	Slurm            expconf.SlurmConfig `json:"slurm,omitempty"`
+	Outputs          []string            `json:"outputs,omitempty"`
 }
 
 // Validate implements the check.Validatable interface.
@@ -69,5 +73,26 @@ func (c *CommandConfig) Validate() []error {
 			"invalid notebook idle type",
 		),
 		check.True(c.Resources.IsSingleNode == nil, "resources.is_single_node cannot be set for NTSCs"),
+		c.validateOutputs(),
+	}
+}
+
+// validateOutputs checks that declared output paths are distinct, non-empty, and do not escape
+// the command's working directory when relative.
+func (c *CommandConfig) validateOutputs() error {
+	seen := make(map[string]bool, len(c.Outputs))
+	for _, p := range c.Outputs {
+		if p == "" {
+			return check.True(false, "outputs must not contain empty paths")
+		}
+		clean := filepath.Clean(p)
+		if !filepath.IsAbs(clean) && (clean == ".." || strings.HasPrefix(clean, "../")) {
+			return check.True(false, "output path %q must not escape the working directory", p)
+		}
+		if seen[clean] {
+			return check.True(false, "output path %q is declared more than once", p)
+		}
+		seen[clean] = true
 	}
+	return nil
 }
@@ -14,6 +14,7 @@ func TestConfigValidate(t *testing.T) {
 		Resources        ResourcesConfig
 		Entrypoint       []string
 		NotebookIdleType string
+		Outputs          []string
 	}
 	type testCase struct {
 		name    string
@@ -58,6 +59,38 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid-outputs",
+			fields: fields{
+				Resources:        resources,
+				Environment:      environment,
+				Entrypoint:       []string{"test"},
+				NotebookIdleType: NotebookIdleTypeActivity,
+				Outputs:          []string{"results", "/tmp/model.pt", "./logs/../metrics.json"},
+			},
+		},
+		{
+			name: "escaping-output",
+			fields: fields{
+				Resources:        resources,
+				Environment:      environment,
+				Entrypoint:       []string{"test"},
+				NotebookIdleType: NotebookIdleTypeActivity,
+				Outputs:          []string{"results/../../etc"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate-output",
+			fields: fields{
+				Resources:        resources,
+				Environment:      environment,
+				Entrypoint:       []string{"test"},
+				NotebookIdleType: NotebookIdleTypeActivity,
+				Outputs:          []string{"results", "./results/"},
+			},
+			wantErr: true,
+		},
 	}
 	runTestCase := func(t *testing.T, tc testCase) {
 		t.Run(tc.name, func(t *testing.T) {
@@ -68,6 +101,7 @@ func TestConfigValidate(t *testing.T) {
 				Resources:        tc.fields.Resources,
 				Entrypoint:       tc.fields.Entrypoint,
 				NotebookIdleType: tc.fields.NotebookIdleType,
+				Outputs:          tc.fields.Outputs,
 			}
 			if err := check.Validate(c); (err != nil) != tc.wantErr {
 				t.Errorf("config.Validate() error = %v, wantErr %v", err, tc.wantErr)
@@ -118,14 +118,15 @@ func (e Environment) ToExpconf() expconf.EnvironmentConfig {
 	proxyConf := e.ProxyPorts.ToExpconf()
 
 	return schemas.WithDefaults(expconf.EnvironmentConfig{
-		RawImage:                &image,
-		RawEnvironmentVariables: &vars,
-		RawProxyPorts:           &proxyConf,
-		RawPorts:                e.Ports,
-		RawRegistryAuth:         e.RegistryAuth,
-		RawForcePullImage:       ptrs.Ptr(e.ForcePullImage),
-		RawPodSpec:              (*expconf.PodSpec)(e.PodSpec),
-		RawAddCapabilities:      e.AddCapabilities,
-		RawDropCapabilities:     e.DropCapabilities,
+		RawImage:                   &image,
+		RawEnvironmentVariables:    &vars,
+		RawProxyPorts:              &proxyConf,
+		RawEnvironmentVariableSets: e.EnvironmentVariableSets,
+		RawPorts:                   e.Ports,
+		RawRegistryAuth:            e.RegistryAuth,
+		RawForcePullImage:          ptrs.Ptr(e.ForcePullImage),
+		RawPodSpec:                 (*expconf.PodSpec)(e.PodSpec),
+		RawAddCapabilities:         e.AddCapabilities,
+		RawDropCapabilities:        e.DropCapabilities,
 	})
 }
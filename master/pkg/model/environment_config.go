@@ -29,6 +29,9 @@ type Environment struct {
 	Image                RuntimeItem      `json:"image"`
 	EnvironmentVariables RuntimeItems     `json:"environment_variables,omitempty"`
 	ProxyPorts           ProxyPortsConfig `json:"proxy_ports"`
+	// EnvironmentVariableSets are the names of sets of environment variables of the task's
+	// workspace.
+	EnvironmentVariableSets []string `json:"environment_variable_sets,omitempty"`
 
 	Ports          map[string]int       `json:"ports"`
 	RegistryAuth   *registry.AuthConfig `json:"registry_auth,omitempty"`
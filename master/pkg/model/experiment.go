@@ -196,13 +196,17 @@ var ExperimentTransitions = map[State]map[State]bool{
 		ActiveState: true,
 		ErrorState:  true,
 	},
+	// Continuing an experiment moves it from a terminal state back to PAUSED.
 	CanceledState: {
+		PausedState:   true,
 		DeletingState: true,
 	},
 	CompletedState: {
+		PausedState:   true,
 		DeletingState: true,
 	},
 	ErrorState: {
+		PausedState:   true,
 		DeletingState: true,
 	},
 	DeletingState: {
@@ -341,6 +345,17 @@ type Experiment struct {
 	// experiments which ran some time in the past, which is exactly what LegacyConfig is for.
 	Config         expconf.LegacyConfig `db:"config"`
 	OriginalConfig string               `db:"original_config"`
+	// ConfigHash is the ExperimentConfigHash of the experiment's effective config when it was
+	// created. It is nil for experiments created before config hashes were introduced.
+	ConfigHash *string `db:"config_hash"`
+	// ModelDefinitionHash is the ModelDefinitionHash of the experiment's model definition when it
+	// was created. It is nil for experiments without a model definition and for experiments created
+	// before model definition hashes were introduced.
+	ModelDefinitionHash *string `db:"model_definition_hash"`
+	// GitCommit and GitRemote describe the git checkout the experiment's model definition was
+	// submitted from, if the client that submitted it reported one.
+	GitCommit *string `db:"git_commit"`
+	GitRemote *string `db:"git_remote"`
 
 	StartTime            time.Time  `db:"start_time"`
 	EndTime              *time.Time `db:"end_time"`
@@ -410,11 +425,17 @@ func NewExperiment(
 	projectID int,
 	unmanaged bool,
 ) (*Experiment, error) {
+	configHash, err := ExperimentConfigHash(config, originalConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Experiment{
 		State:          PausedState,
 		JobID:          NewJobID(),
 		Config:         config.AsLegacy(),
 		OriginalConfig: originalConfig,
+		ConfigHash:     &configHash,
 		StartTime:      time.Now().UTC(),
 		ParentID:       parentID,
 		Archived:       archived,
@@ -422,6 +443,18 @@ func NewExperiment(
 	}, nil
 }
 
+// IllegalTransitionError is returned when changing the state of an experiment to a state that
+// ExperimentTransitions doesn't allow from its current state.
+type IllegalTransitionError struct {
+	ID   int
+	From State
+	To   State
+}
+
+func (e IllegalTransitionError) Error() string {
+	return fmt.Sprintf("illegal transition %v -> %v for experiment %v", e.From, e.To, e.ID)
+}
+
 // Transition changes the state of the experiment to the new state. If the state was not modified
 // the first return value returns false. If the state transition is illegal, an error is returned.
 func (e *Experiment) Transition(state State) (bool, error) {
@@ -429,8 +462,7 @@ func (e *Experiment) Transition(state State) (bool, error) {
 		return false, nil
 	}
 	if !ExperimentTransitions[e.State][state] {
-		return false, errors.Errorf("illegal transition %v -> %v for experiment %v",
-			e.State, state, e.ID)
+		return false, IllegalTransitionError{ID: e.ID, From: e.State, To: state}
 	}
 	e.State = state
 	if TerminalStates[state] {
@@ -440,6 +472,32 @@ func (e *Experiment) Transition(state State) (bool, error) {
 	return true, nil
 }
 
+// ExperimentStateTransition is the bun model of a change of the state of an experiment.
+type ExperimentStateTransition struct {
+	bun.BaseModel  `bun:"table:experiment_state_history"`
+	ID             int       `bun:"id,pk,autoincrement" json:"id"`
+	ExperimentID   int       `bun:"experiment_id" json:"experiment_id"`
+	FromState      *State    `bun:"from_state" json:"from_state"`
+	ToState        State     `bun:"to_state" json:"to_state"`
+	Reason         string    `bun:"reason" json:"reason"`
+	TransitionedAt time.Time `bun:"transitioned_at" json:"transitioned_at"`
+}
+
+// Proto converts an ExperimentStateTransition to its protobuf representation.
+func (t ExperimentStateTransition) Proto() *experimentv1.ExperimentStateTransition {
+	pb := &experimentv1.ExperimentStateTransition{
+		Id:             int32(t.ID),
+		ExperimentId:   int32(t.ExperimentID),
+		ToState:        StateToProto(t.ToState),
+		Reason:         t.Reason,
+		TransitionedAt: timestamppb.New(t.TransitionedAt),
+	}
+	if t.FromState != nil {
+		pb.FromState = ptrs.Ptr(StateToProto(*t.FromState))
+	}
+	return pb
+}
+
 // Trial represents a row from the `trials` table.
 type Trial struct {
 	bun.BaseModel `bun:"table:trials"`
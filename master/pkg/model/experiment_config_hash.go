@@ -0,0 +1,132 @@
+package model
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// configHashExcludedFields are the top-level fields of an experiment config that describe the
+// experiment, rather than what it runs, and so don't affect its config hash.
+var configHashExcludedFields = []string{"name", "description", "labels", "project", "workspace"}
+
+// ExperimentConfigHash returns the hash of the canonical form of an experiment's effective config,
+// so that experiments that run the same config have the same hash. The canonical form is the
+// config as JSON, with object keys sorted, null values dropped, and the fields that only describe
+// the experiment removed. originalConfig is the config as it was submitted: an experiment seed it
+// doesn't set was picked at random, which doesn't make the experiment different from one that is
+// otherwise the same, so it isn't part of the hash.
+func ExperimentConfigHash(config expconf.ExperimentConfig, originalConfig string) (string, error) {
+	if !setsExperimentSeed(originalConfig) {
+		config.RawReproducibility = nil
+	}
+	canonical, err := canonicalExperimentConfig(config)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// setsExperimentSeed returns whether a submitted config sets the experiment seed.
+func setsExperimentSeed(originalConfig string) bool {
+	var submitted struct {
+		Reproducibility *struct {
+			ExperimentSeed *json.Number `json:"experiment_seed"`
+		} `json:"reproducibility"`
+	}
+	// Don't throw errors; validation should happen elsewhere.
+	_ = yaml.Unmarshal([]byte(originalConfig), &submitted)
+	return submitted.Reproducibility != nil && submitted.Reproducibility.ExperimentSeed != nil
+}
+
+func canonicalExperimentConfig(config expconf.ExperimentConfig) ([]byte, error) {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling experiment config")
+	}
+
+	// Numbers are kept as they were written, rather than read as floats, so they're not rounded.
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return nil, errors.Wrap(err, "reading experiment config")
+	}
+	for _, f := range configHashExcludedFields {
+		delete(fields, f)
+	}
+
+	// Maps are marshaled with their keys sorted.
+	canonical, err := json.Marshal(dropNulls(fields))
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling canonical experiment config")
+	}
+	return canonical, nil
+}
+
+// dropNulls removes null values from JSON objects, so that a field that is unset hashes the same as
+// a field that doesn't exist, e.g. in an older version of Determined.
+func dropNulls(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if child == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = dropNulls(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = dropNulls(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// ModelDefinitionHash returns the hash of the files in a gzipped tar of an experiment's model
+// definition, so that experiments that run the same code have the same hash. Only the paths and
+// contents of files and symlinks are hashed; their modes, owners and modification times, which
+// differ between checkouts of the same code, aren't. It returns nil for an empty model definition.
+func ModelDefinitionHash(modelDefinition []byte) (*string, error) {
+	if len(modelDefinition) == 0 {
+		return nil, nil
+	}
+	items, err := archive.FromTarGz(modelDefinition)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading model definition")
+	}
+
+	var files []archive.Item
+	for _, item := range items {
+		if item.Type == tar.TypeReg || item.Type == tar.TypeSymlink {
+			files = append(files, item)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	h := sha256.New()
+	for _, f := range files {
+		// Each field is length-prefixed so that no two different sets of files hash the same.
+		for _, field := range [][]byte{{f.Type}, []byte(f.Path), f.Content} {
+			h.Write([]byte(strconv.Itoa(len(field)) + ":"))
+			h.Write(field)
+		}
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	return &sum, nil
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"archive/tar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestExperimentConfigHash(t *testing.T) {
+	config := func(name string, maxRestarts int) expconf.ExperimentConfig {
+		//nolint:exhaustruct
+		return expconf.ExperimentConfig{
+			RawName:        expconf.Name{RawString: ptrs.Ptr(name)},
+			RawDescription: ptrs.Ptr("description of " + name),
+			RawLabels:      expconf.Labels{name: true},
+			RawMaxRestarts: ptrs.Ptr(maxRestarts),
+			RawHyperparameters: expconf.Hyperparameters{
+				"lr": expconf.Hyperparameter{
+					RawConstHyperparameter: &expconf.ConstHyperparameter{RawVal: 0.1},
+				},
+			},
+		}
+	}
+
+	hash, err := ExperimentConfigHash(config("a", 5), "")
+	require.NoError(t, err)
+	require.Len(t, hash, 64)
+
+	// What describes the experiment doesn't change its hash.
+	same, err := ExperimentConfigHash(config("b", 5), "")
+	require.NoError(t, err)
+	require.Equal(t, hash, same)
+
+	// What it runs does.
+	different, err := ExperimentConfigHash(config("a", 6), "")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, different)
+
+	// The seed is only part of it if the submitted config sets it, rather than it being picked at
+	// random.
+	seeded := config("a", 5)
+	seeded.RawReproducibility = &expconf.ReproducibilityConfig{RawExperimentSeed: ptrs.Ptr(uint32(7))}
+	random, err := ExperimentConfigHash(seeded, "name: a\n")
+	require.NoError(t, err)
+	require.Equal(t, hash, random)
+	set, err := ExperimentConfigHash(seeded, "name: a\nreproducibility:\n  experiment_seed: 7\n")
+	require.NoError(t, err)
+	require.NotEqual(t, hash, set)
+
+	// Unset fields hash the same as missing ones.
+	canonical, err := canonicalExperimentConfig(config("a", 5))
+	require.NoError(t, err)
+	require.NotContains(t, string(canonical), "null")
+	require.NotContains(t, string(canonical), `"name"`)
+}
+
+func TestModelDefinitionHash(t *testing.T) {
+	modelDef := func(mtime time.Time, train string) []byte {
+		files := archive.Archive{
+			archive.RootItem("model", nil, 0o755, tar.TypeDir),
+			archive.RootItem("model/train.py", []byte(train), 0o644, tar.TypeReg),
+			archive.RootItem("model/const.yaml", []byte("name: a"), 0o644, tar.TypeReg),
+		}
+		for i := range files {
+			files[i].ModifiedTime = archive.UnixTime{Time: mtime}
+		}
+		b, err := archive.ToTarGz(files)
+		require.NoError(t, err)
+		return b
+	}
+
+	hash, err := ModelDefinitionHash(modelDef(time.Unix(0, 0), "print(1)"))
+	require.NoError(t, err)
+	require.NotNil(t, hash)
+	require.Len(t, *hash, 64)
+
+	// When the files were checked out doesn't change the hash.
+	same, err := ModelDefinitionHash(modelDef(time.Now(), "print(1)"))
+	require.NoError(t, err)
+	require.Equal(t, *hash, *same)
+
+	// What's in them does.
+	different, err := ModelDefinitionHash(modelDef(time.Unix(0, 0), "print(2)"))
+	require.NoError(t, err)
+	require.NotEqual(t, *hash, *different)
+
+	none, err := ModelDefinitionHash(nil)
+	require.NoError(t, err)
+	require.Nil(t, none)
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// ExperimentReportFormat is the file format of an experiment report.
+type ExperimentReportFormat string
+
+const (
+	// ExperimentReportFormatHTML is a single HTML page with its charts inlined.
+	ExperimentReportFormatHTML ExperimentReportFormat = "html"
+	// ExperimentReportFormatMarkdown is a Markdown document with its charts as data URIs.
+	ExperimentReportFormatMarkdown ExperimentReportFormat = "markdown"
+)
+
+// Valid returns whether f is a known format.
+func (f ExperimentReportFormat) Valid() bool {
+	return f == ExperimentReportFormatHTML || f == ExperimentReportFormatMarkdown
+}
+
+// ContentType returns the MIME type of reports of the format.
+func (f ExperimentReportFormat) ContentType() string {
+	if f == ExperimentReportFormatMarkdown {
+		return "text/markdown; charset=utf-8"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// Extension returns the file name extension of reports of the format.
+func (f ExperimentReportFormat) Extension() string {
+	if f == ExperimentReportFormatMarkdown {
+		return "md"
+	}
+	return "html"
+}
+
+// ExperimentReport is the bun model of a report of the results of an experiment that was rendered
+// and stored by the master, so it can be shared as a file.
+type ExperimentReport struct {
+	bun.BaseModel `bun:"table:experiment_reports"`
+	ID            int                    `bun:"id,pk,autoincrement" json:"id"`
+	ExperimentID  int                    `bun:"experiment_id" json:"experiment_id"`
+	Format        ExperimentReportFormat `bun:"format" json:"format"`
+	Content       []byte                 `bun:"content" json:"-"`
+	CreatedBy     UserID                 `bun:"created_by" json:"created_by"`
+	CreatedAt     time.Time              `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts a report to its protobuf representation. The content is served separately.
+func (r ExperimentReport) Proto() *experimentv1.ExperimentReport {
+	return &experimentv1.ExperimentReport{
+		Id:           int32(r.ID),
+		ExperimentId: int32(r.ExperimentID),
+		Format:       string(r.Format),
+		CreatedBy:    int32(r.CreatedBy),
+		CreatedAt:    timestamppb.New(r.CreatedAt),
+	}
+}
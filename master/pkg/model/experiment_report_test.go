@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExperimentReportProto(t *testing.T) {
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	pb := ExperimentReport{
+		ID:           1,
+		ExperimentID: 7,
+		Format:       ExperimentReportFormatMarkdown,
+		Content:      []byte("# Report"),
+		CreatedBy:    2,
+		CreatedAt:    createdAt,
+	}.Proto()
+	require.Equal(t, int32(1), pb.Id)
+	require.Equal(t, int32(7), pb.ExperimentId)
+	require.Equal(t, "markdown", pb.Format)
+	require.Equal(t, int32(2), pb.CreatedBy)
+	require.Equal(t, createdAt, pb.CreatedAt.AsTime())
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
 )
 
 func TestToRun(t *testing.T) {
@@ -51,3 +52,45 @@ func TestToRun(t *testing.T) {
 	require.Equal(t, expectedRun, actualRun)
 	require.Equal(t, expectedTrial, actualTrial)
 }
+
+func TestExperimentTransition(t *testing.T) {
+	e := &Experiment{ID: 7, State: ActiveState}
+	changed, err := e.Transition(ActiveState)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	_, err = e.Transition(CompletedState)
+	require.Equal(t, IllegalTransitionError{ID: 7, From: ActiveState, To: CompletedState}, err)
+	require.EqualError(t, err, "illegal transition ACTIVE -> COMPLETED for experiment 7")
+
+	changed, err = e.Transition(StoppingCompletedState)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Nil(t, e.EndTime)
+	changed, err = e.Transition(CompletedState)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.NotNil(t, e.EndTime)
+}
+
+func TestExperimentStateTransitionProto(t *testing.T) {
+	transitionedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	pb := ExperimentStateTransition{
+		ID:             3,
+		ExperimentID:   7,
+		FromState:      ptrs.Ptr(ActiveState),
+		ToState:        PausedState,
+		Reason:         "paused by user",
+		TransitionedAt: transitionedAt,
+	}.Proto()
+	require.Equal(t, int32(3), pb.Id)
+	require.Equal(t, int32(7), pb.ExperimentId)
+	require.Equal(t, experimentv1.State_STATE_ACTIVE, pb.GetFromState())
+	require.Equal(t, experimentv1.State_STATE_PAUSED, pb.ToState)
+	require.Equal(t, "paused by user", pb.Reason)
+	require.Equal(t, transitionedAt, pb.TransitionedAt.AsTime())
+
+	// The state an experiment was created in has no state before it.
+	pb = ExperimentStateTransition{ToState: ActiveState}.Proto()
+	require.Nil(t, pb.FromState)
+}
@@ -45,6 +45,12 @@ type GenericTaskConfig struct {
 	WorkDir     *string                 `json:"work_dir"`
 	Debug       bool                    `json:"debug"`
 
+	// Ray runs a Ray cluster in the task's containers. The entrypoint, if any, is run as the Ray
+	// driver on the head node once the cluster is up.
+	Ray *RayClusterConfig `json:"ray,omitempty"`
+	// Spark submits a Spark application whose driver the task runs in place of an entrypoint.
+	Spark *SparkJobConfig `json:"spark,omitempty"`
+
 	Pbs               expconf.PbsConfig   `json:"pbs,omitempty"`
 	Slurm             expconf.SlurmConfig `json:"slurm,omitempty"`
 	PreemptionTimeout int                 `json:"preemption_timeout,omitempty"`
@@ -52,9 +58,18 @@ type GenericTaskConfig struct {
 
 // Validate implements the check.Validatable interface.
 func (c *GenericTaskConfig) Validate() []error {
-	return []error{
+	errs := []error{
 		check.GreaterThanOrEqualTo(c.Resources.Slots(), 0,
 			"resources.slots must be >= 0"),
-		check.GreaterThan(len(c.Entrypoint), 0, "entrypoint must be non-empty"),
 	}
+	switch {
+	case c.Spark != nil:
+		errs = append(errs,
+			check.True(c.Ray == nil, "ray and spark can't both be set"),
+			check.Equal(len(c.Entrypoint), 0, "entrypoint can't be set for a spark job"),
+		)
+	case c.Ray == nil:
+		errs = append(errs, check.GreaterThan(len(c.Entrypoint), 0, "entrypoint must be non-empty"))
+	}
+	return errs
 }
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// ExperimentImagePin is the bun model of the image an experiment runs on a type of device,
+// resolved from its tag to its digest when the experiment was created, continued or forked.
+type ExperimentImagePin struct {
+	bun.BaseModel `bun:"table:experiment_image_pins"`
+	ExperimentID  int         `bun:"experiment_id,pk" json:"experiment_id"`
+	DeviceType    device.Type `bun:"device_type,pk" json:"device_type"`
+	Image         string      `bun:"image" json:"image"`
+	PinnedImage   string      `bun:"pinned_image" json:"pinned_image"`
+	PinnedAt      time.Time   `bun:"pinned_at,scanonly" json:"pinned_at"`
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// MetricDirection is whether a metric improves as it gets smaller or larger.
+type MetricDirection string
+
+const (
+	// MetricDirectionMinimize is a metric that improves as it gets smaller, such as a loss.
+	MetricDirectionMinimize MetricDirection = "minimize"
+	// MetricDirectionMaximize is a metric that improves as it gets larger, such as an accuracy.
+	MetricDirectionMaximize MetricDirection = "maximize"
+)
+
+// MetricScale is the scale clients should plot a metric on.
+type MetricScale string
+
+const (
+	// MetricScaleLinear plots a metric on a linear scale.
+	MetricScaleLinear MetricScale = "linear"
+	// MetricScaleLog plots a metric on a logarithmic scale.
+	MetricScaleLog MetricScale = "log"
+)
+
+// MetricMetadata describes how clients should display a metric of an experiment, so that they
+// don't each have to guess, e.g., whether "loss" should be minimized. Empty fields are undeclared.
+type MetricMetadata struct {
+	bun.BaseModel `bun:"table:metric_metadata"`
+	ExperimentID  int             `bun:"experiment_id,pk" json:"-"`
+	Name          string          `bun:"name,pk" json:"name"`
+	Unit          string          `bun:"unit" json:"unit,omitempty"`
+	Direction     MetricDirection `bun:"direction" json:"direction,omitempty"`
+	DisplayName   string          `bun:"display_name" json:"display_name,omitempty"`
+	Scale         MetricScale     `bun:"scale" json:"scale,omitempty"`
+	UpdatedAt     time.Time       `bun:"updated_at" json:"-"`
+}
+
+// Merge returns m with its undeclared fields taken from other.
+func (m MetricMetadata) Merge(other MetricMetadata) MetricMetadata {
+	if m.Unit == "" {
+		m.Unit = other.Unit
+	}
+	if m.Direction == "" {
+		m.Direction = other.Direction
+	}
+	if m.DisplayName == "" {
+		m.DisplayName = other.DisplayName
+	}
+	if m.Scale == "" {
+		m.Scale = other.Scale
+	}
+	return m
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+// MetricsExportType is the kind of external system trial metrics are mirrored to.
+type MetricsExportType string
+
+const (
+	// MetricsExportMLflow mirrors metrics to an MLflow tracking server.
+	MetricsExportMLflow MetricsExportType = "mlflow"
+	// MetricsExportWandB mirrors metrics to Weights & Biases.
+	MetricsExportWandB MetricsExportType = "wandb"
+)
+
+// MetricsExportTypeFromProto converts a protobuf export type, returning an error for unknown types.
+func MetricsExportTypeFromProto(t workspacev1.MetricsExportType) (MetricsExportType, error) {
+	switch t {
+	case workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_MLFLOW:
+		return MetricsExportMLflow, nil
+	case workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_WANDB:
+		return MetricsExportWandB, nil
+	default:
+		return "", fmt.Errorf("invalid metrics export type %s, must be one of %s or %s",
+			t, workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_MLFLOW,
+			workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_WANDB)
+	}
+}
+
+// Proto converts an export type to its protobuf representation.
+func (t MetricsExportType) Proto() workspacev1.MetricsExportType {
+	switch t {
+	case MetricsExportMLflow:
+		return workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_MLFLOW
+	case MetricsExportWandB:
+		return workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_WANDB
+	default:
+		return workspacev1.MetricsExportType_METRICS_EXPORT_TYPE_UNSPECIFIED
+	}
+}
+
+// MetricsExportDestination is the bun model of an external system that the trial metrics of a
+// workspace are mirrored to.
+type MetricsExportDestination struct {
+	bun.BaseModel `bun:"table:metrics_export_destinations"`
+	ID            int               `bun:"id,pk,autoincrement" json:"id"`
+	WorkspaceID   int               `bun:"workspace_id" json:"workspace_id"`
+	Type          MetricsExportType `bun:"type" json:"type"`
+	Name          string            `bun:"name" json:"name"`
+	URL           string            `bun:"url" json:"url"`
+	APIKey        string            `bun:"api_key" json:"-"`
+	Project       string            `bun:"project" json:"project"`
+	Entity        string            `bun:"entity" json:"entity"`
+	CreatedAt     time.Time         `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts a destination to its protobuf representation, without its API key.
+func (d MetricsExportDestination) Proto() *workspacev1.MetricsExportDestination {
+	return &workspacev1.MetricsExportDestination{
+		Id:          int32(d.ID),
+		WorkspaceId: int32(d.WorkspaceID),
+		Type:        d.Type.Proto(),
+		Name:        d.Name,
+		Url:         d.URL,
+		Project:     d.Project,
+		Entity:      d.Entity,
+		CreatedAt:   timestamppb.New(d.CreatedAt),
+	}
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+// ModelEvaluationTrigger is what started a model evaluation.
+type ModelEvaluationTrigger string
+
+const (
+	// ModelEvaluationTriggerRegister evaluates a model version when it is registered.
+	ModelEvaluationTriggerRegister ModelEvaluationTrigger = "register"
+	// ModelEvaluationTriggerSchedule evaluates a model's latest version on a schedule.
+	ModelEvaluationTriggerSchedule ModelEvaluationTrigger = "schedule"
+	// ModelEvaluationTriggerManual evaluates a model version when a user asks to.
+	ModelEvaluationTriggerManual ModelEvaluationTrigger = "manual"
+)
+
+// Proto converts a trigger to its protobuf representation.
+func (t ModelEvaluationTrigger) Proto() modelv1.ModelEvaluationTrigger {
+	switch t {
+	case ModelEvaluationTriggerRegister:
+		return modelv1.ModelEvaluationTrigger_MODEL_EVALUATION_TRIGGER_REGISTER
+	case ModelEvaluationTriggerSchedule:
+		return modelv1.ModelEvaluationTrigger_MODEL_EVALUATION_TRIGGER_SCHEDULE
+	case ModelEvaluationTriggerManual:
+		return modelv1.ModelEvaluationTrigger_MODEL_EVALUATION_TRIGGER_MANUAL
+	default:
+		return modelv1.ModelEvaluationTrigger_MODEL_EVALUATION_TRIGGER_UNSPECIFIED
+	}
+}
+
+// ModelEvaluationState is the state of a model evaluation.
+type ModelEvaluationState string
+
+const (
+	// ModelEvaluationLaunching is an evaluation whose experiment is being created.
+	ModelEvaluationLaunching ModelEvaluationState = "launching"
+	// ModelEvaluationRunning is an evaluation whose experiment hasn't ended.
+	ModelEvaluationRunning ModelEvaluationState = "running"
+	// ModelEvaluationCompleted is an evaluation whose experiment completed.
+	ModelEvaluationCompleted ModelEvaluationState = "completed"
+	// ModelEvaluationFailed is an evaluation whose experiment couldn't be created or didn't
+	// complete.
+	ModelEvaluationFailed ModelEvaluationState = "failed"
+)
+
+// Proto converts a state to its protobuf representation.
+func (s ModelEvaluationState) Proto() modelv1.ModelEvaluationState {
+	switch s {
+	case ModelEvaluationLaunching:
+		return modelv1.ModelEvaluationState_MODEL_EVALUATION_STATE_LAUNCHING
+	case ModelEvaluationRunning:
+		return modelv1.ModelEvaluationState_MODEL_EVALUATION_STATE_RUNNING
+	case ModelEvaluationCompleted:
+		return modelv1.ModelEvaluationState_MODEL_EVALUATION_STATE_COMPLETED
+	case ModelEvaluationFailed:
+		return modelv1.ModelEvaluationState_MODEL_EVALUATION_STATE_FAILED
+	default:
+		return modelv1.ModelEvaluationState_MODEL_EVALUATION_STATE_UNSPECIFIED
+	}
+}
+
+// ModelEvaluationPolicy is the bun model of an evaluation experiment that runs automatically
+// against a registry model's versions.
+type ModelEvaluationPolicy struct {
+	bun.BaseModel `bun:"table:model_evaluation_policies"`
+	ID            int    `bun:"id,pk,autoincrement" json:"id"`
+	ModelID       int    `bun:"model_id" json:"model_id"`
+	Name          string `bun:"name" json:"name"`
+	// Config is the config of the evaluation experiment, as YAML.
+	Config string `bun:"config" json:"config"`
+	// ModelDefinition is the gzipped tar of the evaluation experiment's model definition.
+	ModelDefinition []byte `bun:"model_definition" json:"-"`
+	ProjectID       int    `bun:"project_id" json:"project_id"`
+	// OnRegister evaluates each new version when it is registered.
+	OnRegister bool `bun:"on_register" json:"on_register"`
+	// ScheduleInterval, a duration like 24h, evaluates the model's latest version, or its latest
+	// version with ScheduleLabel, that often.
+	ScheduleInterval *string `bun:"schedule_interval" json:"schedule_interval"`
+	ScheduleLabel    *string `bun:"schedule_label" json:"schedule_label"`
+	// Metric is the validation metric that is compared between evaluations.
+	Metric          string `bun:"metric" json:"metric"`
+	SmallerIsBetter bool   `bun:"smaller_is_better" json:"smaller_is_better"`
+	// RegressionTolerance is how much worse than the last evaluation, relative to it, an
+	// evaluation can be without counting as a regression.
+	RegressionTolerance float64    `bun:"regression_tolerance" json:"regression_tolerance"`
+	OwnerID             UserID     `bun:"owner_id" json:"owner_id"`
+	CreatedAt           time.Time  `bun:"created_at,scanonly" json:"created_at"`
+	LastScheduledAt     *time.Time `bun:"last_scheduled_at" json:"last_scheduled_at"`
+}
+
+// Proto converts a policy to its protobuf representation.
+func (p ModelEvaluationPolicy) Proto() *modelv1.ModelEvaluationPolicy {
+	pb := &modelv1.ModelEvaluationPolicy{
+		Id:                  int32(p.ID),
+		ModelId:             int32(p.ModelID),
+		Name:                p.Name,
+		Config:              p.Config,
+		ProjectId:           int32(p.ProjectID),
+		OnRegister:          p.OnRegister,
+		ScheduleInterval:    p.ScheduleInterval,
+		ScheduleLabel:       p.ScheduleLabel,
+		Metric:              p.Metric,
+		SmallerIsBetter:     p.SmallerIsBetter,
+		RegressionTolerance: p.RegressionTolerance,
+		OwnerId:             int32(p.OwnerID),
+		CreatedAt:           timestamppb.New(p.CreatedAt),
+	}
+	if p.LastScheduledAt != nil {
+		pb.LastScheduledAt = timestamppb.New(*p.LastScheduledAt)
+	}
+	return pb
+}
+
+// ModelEvaluation is the bun model of an evaluation of a model version by a policy.
+type ModelEvaluation struct {
+	bun.BaseModel  `bun:"table:model_evaluations"`
+	ID             int                    `bun:"id,pk,autoincrement" json:"id"`
+	PolicyID       int                    `bun:"policy_id" json:"policy_id"`
+	ModelVersionID int                    `bun:"model_version_id" json:"model_version_id"`
+	ExperimentID   *int                   `bun:"experiment_id" json:"experiment_id"`
+	Trigger        ModelEvaluationTrigger `bun:"trigger" json:"trigger"`
+	State          ModelEvaluationState   `bun:"state" json:"state"`
+	Error          *string                `bun:"error" json:"error"`
+	// Metrics are the latest validation metrics the evaluation experiment reported.
+	Metrics     map[string]any `bun:"metrics,type:jsonb" json:"metrics"`
+	MetricValue *float64       `bun:"metric_value" json:"metric_value"`
+	// BaselineID is the evaluation the metric was compared with, and Regressed whether it was
+	// worse than that evaluation by more than the policy tolerates.
+	BaselineID *int       `bun:"baseline_id" json:"baseline_id"`
+	Regressed  *bool      `bun:"regressed" json:"regressed"`
+	CreatedAt  time.Time  `bun:"created_at,scanonly" json:"created_at"`
+	EndedAt    *time.Time `bun:"ended_at" json:"ended_at"`
+
+	// ModelVersion is the version number of the evaluated model version.
+	ModelVersion int `bun:"model_version,scanonly" json:"model_version"`
+}
+
+// Proto converts an evaluation to its protobuf representation.
+func (e ModelEvaluation) Proto() *modelv1.ModelEvaluation {
+	pb := &modelv1.ModelEvaluation{
+		Id:             int32(e.ID),
+		PolicyId:       int32(e.PolicyID),
+		ModelVersionId: int32(e.ModelVersionID),
+		ModelVersion:   int32(e.ModelVersion),
+		Trigger:        e.Trigger.Proto(),
+		State:          e.State.Proto(),
+		Error:          e.Error,
+		MetricValue:    e.MetricValue,
+		Regressed:      e.Regressed,
+		CreatedAt:      timestamppb.New(e.CreatedAt),
+	}
+	if e.ExperimentID != nil {
+		pb.ExperimentId = ptrs.Ptr(int32(*e.ExperimentID))
+	}
+	if e.Metrics != nil {
+		pb.Metrics = protoutils.ToStruct(e.Metrics)
+	}
+	if e.BaselineID != nil {
+		pb.BaselineId = ptrs.Ptr(int32(*e.BaselineID))
+	}
+	if e.EndedAt != nil {
+		pb.EndedAt = timestamppb.New(*e.EndedAt)
+	}
+	return pb
+}
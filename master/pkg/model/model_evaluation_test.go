@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+func TestModelEvaluationPolicyProto(t *testing.T) {
+	pb := ModelEvaluationPolicy{
+		ID:               1,
+		ModelID:          2,
+		Name:             "nightly",
+		ScheduleInterval: ptrs.Ptr("24h"),
+		SmallerIsBetter:  true,
+	}.Proto()
+	require.Equal(t, int32(2), pb.ModelId)
+	require.Equal(t, "24h", pb.GetScheduleInterval())
+	require.Nil(t, pb.ScheduleLabel)
+	require.Nil(t, pb.LastScheduledAt)
+}
+
+func TestModelEvaluationProto(t *testing.T) {
+	experimentID := 7
+	ended := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	pb := ModelEvaluation{
+		ID:           3,
+		ExperimentID: &experimentID,
+		Trigger:      ModelEvaluationTriggerSchedule,
+		State:        ModelEvaluationCompleted,
+		Metrics:      map[string]any{"loss": 0.5},
+		MetricValue:  ptrs.Ptr(0.5),
+		EndedAt:      &ended,
+		ModelVersion: 4,
+	}.Proto()
+	require.Equal(t, int32(7), pb.GetExperimentId())
+	require.Equal(t, modelv1.ModelEvaluationTrigger_MODEL_EVALUATION_TRIGGER_SCHEDULE, pb.Trigger)
+	require.Equal(t, modelv1.ModelEvaluationState_MODEL_EVALUATION_STATE_COMPLETED, pb.State)
+	require.Equal(t, 0.5, pb.Metrics.AsMap()["loss"])
+	require.Equal(t, ended, pb.EndedAt.AsTime())
+	require.Nil(t, pb.BaselineId)
+	require.Equal(t, int32(4), pb.ModelVersion)
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+// ModelPromotionGate is the bun model of a metric threshold that versions of a registry model must
+// pass, in their latest evaluation by an evaluation policy, to be promoted to production.
+type ModelPromotionGate struct {
+	bun.BaseModel `bun:"table:model_promotion_gates"`
+	ID            int    `bun:"id,pk,autoincrement" json:"id"`
+	ModelID       int    `bun:"model_id" json:"model_id"`
+	PolicyID      int    `bun:"policy_id" json:"policy_id"`
+	Metric        string `bun:"metric" json:"metric"`
+	// Comparison is how the metric is compared with the threshold, one of >=, >, <= and <.
+	Comparison string    `bun:"comparison" json:"comparison"`
+	Threshold  float64   `bun:"threshold" json:"threshold"`
+	CreatedBy  UserID    `bun:"created_by" json:"created_by"`
+	CreatedAt  time.Time `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts a gate to its protobuf representation.
+func (g ModelPromotionGate) Proto() *modelv1.ModelPromotionGate {
+	return &modelv1.ModelPromotionGate{
+		Id:         int32(g.ID),
+		ModelId:    int32(g.ModelID),
+		PolicyId:   int32(g.PolicyID),
+		Metric:     g.Metric,
+		Comparison: g.Comparison,
+		Threshold:  g.Threshold,
+		CreatedBy:  int32(g.CreatedBy),
+		CreatedAt:  timestamppb.New(g.CreatedAt),
+	}
+}
+
+// ModelPromotionOverride is the bun model of the audit entry of a model version that was promoted
+// to production without passing its model's gates.
+type ModelPromotionOverride struct {
+	bun.BaseModel  `bun:"table:model_promotion_overrides"`
+	ID             int    `bun:"id,pk,autoincrement" json:"id"`
+	ModelVersionID int    `bun:"model_version_id" json:"model_version_id"`
+	UserID         UserID `bun:"user_id" json:"user_id"`
+	Reason         string `bun:"reason" json:"reason"`
+	// UnmetGates are the gates the version didn't pass, and why, when it was promoted.
+	UnmetGates []map[string]any `bun:"unmet_gates,type:jsonb" json:"unmet_gates"`
+	CreatedAt  time.Time        `bun:"created_at,scanonly" json:"created_at"`
+
+	// ModelVersion is the version number of the promoted model version.
+	ModelVersion int `bun:"model_version,scanonly" json:"model_version"`
+}
+
+// Proto converts an override to its protobuf representation.
+func (o ModelPromotionOverride) Proto() *modelv1.ModelPromotionOverride {
+	unmet := make([]*structpb.Struct, 0, len(o.UnmetGates))
+	for _, g := range o.UnmetGates {
+		unmet = append(unmet, protoutils.ToStruct(g))
+	}
+	return &modelv1.ModelPromotionOverride{
+		Id:             int32(o.ID),
+		ModelVersionId: int32(o.ModelVersionID),
+		ModelVersion:   int32(o.ModelVersion),
+		UserId:         int32(o.UserID),
+		Reason:         o.Reason,
+		UnmetGates:     unmet,
+		CreatedAt:      timestamppb.New(o.CreatedAt),
+	}
+}
+
+// ModelPromotionGateAction is a change to a model's promotion gates.
+type ModelPromotionGateAction string
+
+const (
+	// ModelPromotionGateAdded is a gate that was added.
+	ModelPromotionGateAdded ModelPromotionGateAction = "added"
+	// ModelPromotionGateRemoved is a gate that was removed, by itself or along with its
+	// evaluation policy.
+	ModelPromotionGateRemoved ModelPromotionGateAction = "removed"
+)
+
+// Proto converts an action to its protobuf representation.
+func (a ModelPromotionGateAction) Proto() modelv1.ModelPromotionGateAction {
+	switch a {
+	case ModelPromotionGateAdded:
+		return modelv1.ModelPromotionGateAction_MODEL_PROMOTION_GATE_ACTION_ADDED
+	case ModelPromotionGateRemoved:
+		return modelv1.ModelPromotionGateAction_MODEL_PROMOTION_GATE_ACTION_REMOVED
+	default:
+		return modelv1.ModelPromotionGateAction_MODEL_PROMOTION_GATE_ACTION_UNSPECIFIED
+	}
+}
+
+// ModelPromotionGateChange is the bun model of the audit entry of a gate that was added to or
+// removed from a model. It keeps the gate as it was, since removed gates are deleted.
+type ModelPromotionGateChange struct {
+	bun.BaseModel `bun:"table:model_promotion_gate_changes"`
+	ID            int                      `bun:"id,pk,autoincrement" json:"id"`
+	ModelID       int                      `bun:"model_id" json:"model_id"`
+	GateID        int                      `bun:"gate_id" json:"gate_id"`
+	Action        ModelPromotionGateAction `bun:"action" json:"action"`
+	PolicyID      int                      `bun:"policy_id" json:"policy_id"`
+	Metric        string                   `bun:"metric" json:"metric"`
+	Comparison    string                   `bun:"comparison" json:"comparison"`
+	Threshold     float64                  `bun:"threshold" json:"threshold"`
+	UserID        UserID                   `bun:"user_id" json:"user_id"`
+	CreatedAt     time.Time                `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts a change to its protobuf representation.
+func (c ModelPromotionGateChange) Proto() *modelv1.ModelPromotionGateChange {
+	return &modelv1.ModelPromotionGateChange{
+		Id:         int32(c.ID),
+		ModelId:    int32(c.ModelID),
+		GateId:     int32(c.GateID),
+		Action:     c.Action.Proto(),
+		PolicyId:   int32(c.PolicyID),
+		Metric:     c.Metric,
+		Comparison: c.Comparison,
+		Threshold:  c.Threshold,
+		UserId:     int32(c.UserID),
+		CreatedAt:  timestamppb.New(c.CreatedAt),
+	}
+}
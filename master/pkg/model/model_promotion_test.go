@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/modelv1"
+)
+
+func TestModelPromotionGateProto(t *testing.T) {
+	pb := ModelPromotionGate{
+		ID:         1,
+		PolicyID:   2,
+		Metric:     "accuracy",
+		Comparison: ">=",
+		Threshold:  0.92,
+		CreatedBy:  3,
+	}.Proto()
+	require.Equal(t, int32(2), pb.PolicyId)
+	require.Equal(t, 0.92, pb.Threshold)
+	require.Equal(t, int32(3), pb.CreatedBy)
+}
+
+func TestModelPromotionOverrideProto(t *testing.T) {
+	pb := ModelPromotionOverride{
+		ID:           1,
+		ModelVersion: 3,
+		Reason:       "hotfix",
+		UnmetGates:   []map[string]any{{"passed": false, "reason": "too low"}},
+	}.Proto()
+	require.Equal(t, int32(3), pb.ModelVersion)
+	require.Len(t, pb.UnmetGates, 1)
+	require.Equal(t, "too low", pb.UnmetGates[0].AsMap()["reason"])
+
+	require.NotNil(t, ModelPromotionOverride{}.Proto().UnmetGates)
+}
+
+func TestModelPromotionGateChangeProto(t *testing.T) {
+	pb := ModelPromotionGateChange{GateID: 4, Action: ModelPromotionGateRemoved}.Proto()
+	require.Equal(t, int32(4), pb.GateId)
+	require.Equal(t, modelv1.ModelPromotionGateAction_MODEL_PROMOTION_GATE_ACTION_REMOVED,
+		pb.Action)
+	require.Equal(t, modelv1.ModelPromotionGateAction_MODEL_PROMOTION_GATE_ACTION_UNSPECIFIED,
+		ModelPromotionGateAction("").Proto())
+}
@@ -1,11 +1,13 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/uptrace/bun"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/determined-ai/determined/master/pkg/protoutils"
 	"github.com/determined-ai/determined/proto/pkg/projectv1"
 )
 
@@ -70,3 +72,100 @@ func ProjectsToProto(ps []*Project) []*projectv1.Project {
 	}
 	return out
 }
+
+// ProjectPin is the bun model of a project a user has pinned.
+type ProjectPin struct {
+	bun.BaseModel `bun:"table:project_pins"`
+	ID            int    `bun:"id,pk,autoincrement"`
+	ProjectID     int    `bun:"project_id"`
+	UserID        UserID `bun:"user_id"`
+	// Position orders a user's project pins, like WorkspacePin.Position.
+	Position  int       `bun:"position,nullzero"`
+	CreatedAt time.Time `bun:"created_at,scanonly"`
+}
+
+// ProjectExperimentNaming is the bun model of how experiments in a project are named.
+type ProjectExperimentNaming struct {
+	bun.BaseModel `bun:"table:project_experiment_naming"`
+	ProjectID     int `bun:"project_id,pk" json:"project_id"`
+	// UniqueNames requires each experiment in the project to have a different name.
+	UniqueNames bool `bun:"unique_names" json:"unique_names"`
+	// NameTemplate names experiments whose config omits a name.
+	NameTemplate *string `bun:"name_template" json:"name_template"`
+	// NextSeq is the number the template's {seq} placeholder is replaced with next.
+	NextSeq int `bun:"next_seq,scanonly" json:"next_seq"`
+}
+
+// Proto converts how experiments in a project are named to its protobuf representation.
+func (n ProjectExperimentNaming) Proto() *projectv1.ExperimentNaming {
+	return &projectv1.ExperimentNaming{
+		ProjectId:    int32(n.ProjectID),
+		UniqueNames:  n.UniqueNames,
+		NameTemplate: n.NameTemplate,
+		NextSeq:      int32(n.NextSeq),
+	}
+}
+
+// ProjectExperimentDefaults is the bun model of the defaults experiments created in a project get
+// when their configs omit them.
+type ProjectExperimentDefaults struct {
+	bun.BaseModel `bun:"table:project_experiment_defaults"`
+	ProjectID     int     `bun:"project_id,pk" json:"project_id"`
+	ResourcePool  *string `bun:"resource_pool" json:"resource_pool"`
+	// ForceResourcePool runs every experiment in the project in ResourcePool, even if its config
+	// sets another pool.
+	ForceResourcePool bool    `bun:"force_resource_pool" json:"force_resource_pool"`
+	Priority          *int    `bun:"priority" json:"priority"`
+	CheckpointPolicy  *string `bun:"checkpoint_policy" json:"checkpoint_policy"`
+}
+
+// Proto converts the experiment defaults of a project to its protobuf representation.
+func (d ProjectExperimentDefaults) Proto() *projectv1.ExperimentDefaults {
+	pb := &projectv1.ExperimentDefaults{
+		ProjectId:         int32(d.ProjectID),
+		ResourcePool:      d.ResourcePool,
+		ForceResourcePool: d.ForceResourcePool,
+		CheckpointPolicy:  d.CheckpointPolicy,
+	}
+	if d.Priority != nil {
+		priority := int32(*d.Priority)
+		pb.Priority = &priority
+	}
+	return pb
+}
+
+// ProjectExperimentMetadataSchema is the bun model of the JSON schema the metadata of experiments
+// in a project must follow.
+type ProjectExperimentMetadataSchema struct {
+	bun.BaseModel `bun:"table:project_experiment_metadata_schemas"`
+	ProjectID     int             `bun:"project_id,pk" json:"project_id"`
+	Schema        json.RawMessage `bun:"schema,type:jsonb" json:"schema"`
+	UpdatedAt     time.Time       `bun:"updated_at,nullzero,default:now()" json:"updated_at"`
+}
+
+// Proto converts the experiment metadata schema of a project to its protobuf representation.
+func (s ProjectExperimentMetadataSchema) Proto() *projectv1.ExperimentMetadataSchema {
+	return &projectv1.ExperimentMetadataSchema{
+		ProjectId: int32(s.ProjectID),
+		Schema:    protoutils.ToStruct(s.Schema),
+		UpdatedAt: timestamppb.New(s.UpdatedAt),
+	}
+}
+
+// ProjectCheckpointMetadataSchema is the bun model of the JSON schema the metadata of the
+// checkpoints of experiments in a project must follow.
+type ProjectCheckpointMetadataSchema struct {
+	bun.BaseModel `bun:"table:project_checkpoint_metadata_schemas"`
+	ProjectID     int             `bun:"project_id,pk" json:"project_id"`
+	Schema        json.RawMessage `bun:"schema,type:jsonb" json:"schema"`
+	UpdatedAt     time.Time       `bun:"updated_at,nullzero,default:now()" json:"updated_at"`
+}
+
+// Proto converts the checkpoint metadata schema of a project to its protobuf representation.
+func (s ProjectCheckpointMetadataSchema) Proto() *projectv1.CheckpointMetadataSchema {
+	return &projectv1.CheckpointMetadataSchema{
+		ProjectId: int32(s.ProjectID),
+		Schema:    protoutils.ToStruct(s.Schema),
+		UpdatedAt: timestamppb.New(s.UpdatedAt),
+	}
+}
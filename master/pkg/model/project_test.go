@@ -0,0 +1,51 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestProjectExperimentDefaultsProto(t *testing.T) {
+	pb := ProjectExperimentDefaults{
+		ProjectID:         3,
+		ResourcePool:      ptrs.Ptr("a100"),
+		ForceResourcePool: true,
+		Priority:          ptrs.Ptr(10),
+	}.Proto()
+	require.Equal(t, int32(3), pb.ProjectId)
+	require.Equal(t, "a100", pb.GetResourcePool())
+	require.True(t, pb.ForceResourcePool)
+	require.Equal(t, int32(10), pb.GetPriority())
+	require.Nil(t, pb.CheckpointPolicy)
+
+	require.Nil(t, ProjectExperimentDefaults{}.Proto().Priority)
+}
+
+func TestProjectExperimentMetadataSchemaProto(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	pb := ProjectExperimentMetadataSchema{
+		ProjectID: 3,
+		Schema:    json.RawMessage(`{"type": "object"}`),
+		UpdatedAt: updatedAt,
+	}.Proto()
+	require.Equal(t, int32(3), pb.ProjectId)
+	require.Equal(t, map[string]interface{}{"type": "object"}, pb.Schema.AsMap())
+	require.Equal(t, updatedAt, pb.UpdatedAt.AsTime())
+}
+
+func TestProjectCheckpointMetadataSchemaProto(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	pb := ProjectCheckpointMetadataSchema{
+		ProjectID: 3,
+		Schema:    json.RawMessage(`{"type": "object"}`),
+		UpdatedAt: updatedAt,
+	}.Proto()
+	require.Equal(t, int32(3), pb.ProjectId)
+	require.Equal(t, map[string]interface{}{"type": "object"}, pb.Schema.AsMap())
+	require.Equal(t, updatedAt, pb.UpdatedAt.AsTime())
+}
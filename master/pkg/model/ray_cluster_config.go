@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+const (
+	// DefaultRayHeadPort is the port of the Ray head node's GCS server.
+	DefaultRayHeadPort = 6379
+	// DefaultRayDashboardPort is the port of the Ray dashboard.
+	DefaultRayDashboardPort = 8265
+
+	// The rendezvous ports are reserved for communication across a task's containers.
+	minRendezvousPort = 1734
+	maxRendezvousPort = 1765
+)
+
+// RayClusterConfig configures a generic task that runs a Ray cluster. The first container runs the
+// Ray head node and every other container runs a worker node connected to it.
+type RayClusterConfig struct {
+	HeadPort      int       `json:"head_port"`
+	DashboardPort int       `json:"dashboard_port"`
+	IdleTimeout   *Duration `json:"idle_timeout,omitempty"`
+	StartArgs     []string  `json:"start_args,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *RayClusterConfig) UnmarshalJSON(data []byte) error {
+	type DefaultParser RayClusterConfig
+	parsed := DefaultParser{HeadPort: DefaultRayHeadPort, DashboardPort: DefaultRayDashboardPort}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return errors.Wrap(err, "failed to parse ray cluster config")
+	}
+	*c = RayClusterConfig(parsed)
+	return nil
+}
+
+// Validate implements the check.Validatable interface.
+func (c *RayClusterConfig) Validate() []error {
+	errs := []error{
+		check.True(c.HeadPort != c.DashboardPort,
+			"ray.head_port and ray.dashboard_port must differ"),
+	}
+	for _, p := range []struct {
+		name string
+		port int
+	}{{"ray.head_port", c.HeadPort}, {"ray.dashboard_port", c.DashboardPort}} {
+		errs = append(errs,
+			check.BetweenInclusive(p.port, 1, 65535, "%s must be a valid port", p.name),
+			check.False(minRendezvousPort <= p.port && p.port <= maxRendezvousPort,
+				"%s must not be in the range %d-%d reserved for rendezvous",
+				p.name, minRendezvousPort, maxRendezvousPort),
+		)
+	}
+	if c.IdleTimeout != nil {
+		errs = append(errs, check.GreaterThan(int64(*c.IdleTimeout), int64(0),
+			"ray.idle_timeout must be positive"))
+	}
+	return errs
+}
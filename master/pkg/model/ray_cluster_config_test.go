@@ -0,0 +1,40 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestRayClusterConfig(t *testing.T) {
+	var c RayClusterConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"idle_timeout": "30m"}`), &c))
+	require.Equal(t, RayClusterConfig{
+		HeadPort:      DefaultRayHeadPort,
+		DashboardPort: DefaultRayDashboardPort,
+		IdleTimeout:   ptrs.Ptr(Duration(30 * time.Minute)),
+	}, c)
+	require.NoError(t, check.Validate(&c))
+
+	for name, invalid := range map[string]RayClusterConfig{
+		"same ports":       {HeadPort: 6379, DashboardPort: 6379},
+		"invalid port":     {HeadPort: 70000, DashboardPort: 8265},
+		"rendezvous port":  {HeadPort: 6379, DashboardPort: 1740},
+		"negative timeout": {HeadPort: 6379, DashboardPort: 8265, IdleTimeout: ptrs.Ptr(Duration(-time.Second))},
+	} {
+		require.Error(t, check.Validate(&invalid), name)
+	}
+}
+
+func TestGenericTaskConfigRayEntrypoint(t *testing.T) {
+	c := DefaultConfigGenericTaskConfig(nil)
+	require.Error(t, check.Validate(&c), "an entrypoint is required")
+
+	c.Ray = &RayClusterConfig{HeadPort: DefaultRayHeadPort, DashboardPort: DefaultRayDashboardPort}
+	require.NoError(t, check.Validate(&c), "a ray cluster runs without a driver")
+}
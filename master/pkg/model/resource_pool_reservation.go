@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/resourcepoolv1"
+)
+
+// ResourcePoolReservation is the bun model of a reservation of a fraction of the slots of a
+// resource pool for the tasks of a workspace or of a user between its start and end times. Exactly
+// one of WorkspaceID and UserID is set.
+type ResourcePoolReservation struct {
+	bun.BaseModel `bun:"table:resource_pool_reservations"`
+	ID            int       `bun:"id,pk,autoincrement" json:"id"`
+	ResourcePool  string    `bun:"resource_pool" json:"resource_pool"`
+	WorkspaceID   *int      `bun:"workspace_id" json:"workspace_id"`
+	UserID        *UserID   `bun:"user_id" json:"user_id"`
+	SlotFraction  float64   `bun:"slot_fraction" json:"slot_fraction"`
+	StartTime     time.Time `bun:"start_time" json:"start_time"`
+	EndTime       time.Time `bun:"end_time" json:"end_time"`
+	Description   string    `bun:"description" json:"description"`
+	CreatedBy     UserID    `bun:"created_by" json:"created_by"`
+	CreatedAt     time.Time `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Active returns whether the reservation holds slots at now.
+func (r ResourcePoolReservation) Active(now time.Time) bool {
+	return !now.Before(r.StartTime) && now.Before(r.EndTime)
+}
+
+// Overlaps returns whether the reservation holds slots at any time between start and end.
+func (r ResourcePoolReservation) Overlaps(start, end time.Time) bool {
+	return r.StartTime.Before(end) && start.Before(r.EndTime)
+}
+
+// Covers returns whether a task of the workspace and owner may use the reserved slots.
+func (r ResourcePoolReservation) Covers(workspaceID int, ownerID UserID) bool {
+	if r.WorkspaceID != nil {
+		return *r.WorkspaceID == workspaceID
+	}
+	return r.UserID != nil && *r.UserID == ownerID
+}
+
+// Proto converts a reservation to its protobuf representation.
+func (r ResourcePoolReservation) Proto() *resourcepoolv1.ResourcePoolReservation {
+	pb := &resourcepoolv1.ResourcePoolReservation{
+		Id:           int32(r.ID),
+		ResourcePool: r.ResourcePool,
+		SlotFraction: r.SlotFraction,
+		StartTime:    timestamppb.New(r.StartTime),
+		EndTime:      timestamppb.New(r.EndTime),
+		Description:  r.Description,
+		CreatedBy:    int32(r.CreatedBy),
+		CreatedAt:    timestamppb.New(r.CreatedAt),
+	}
+	if r.WorkspaceID != nil {
+		workspaceID := int32(*r.WorkspaceID)
+		pb.WorkspaceId = &workspaceID
+	}
+	if r.UserID != nil {
+		userID := int32(*r.UserID)
+		pb.UserId = &userID
+	}
+	return pb
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestResourcePoolReservationProto(t *testing.T) {
+	start := time.Date(2024, 12, 20, 9, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+	pb := ResourcePoolReservation{
+		ID:           1,
+		ResourcePool: "default",
+		WorkspaceID:  ptrs.Ptr(3),
+		SlotFraction: 0.5,
+		StartTime:    start,
+		EndTime:      end,
+		Description:  "Demo day",
+		CreatedBy:    2,
+	}.Proto()
+	require.Equal(t, int32(1), pb.Id)
+	require.Equal(t, "default", pb.ResourcePool)
+	require.Equal(t, int32(3), pb.GetWorkspaceId())
+	require.Nil(t, pb.UserId)
+	require.Equal(t, 0.5, pb.SlotFraction)
+	require.Equal(t, start, pb.StartTime.AsTime())
+	require.Equal(t, end, pb.EndTime.AsTime())
+	require.Equal(t, "Demo day", pb.Description)
+	require.Equal(t, int32(2), pb.CreatedBy)
+
+	pb = ResourcePoolReservation{UserID: ptrs.Ptr(UserID(4))}.Proto()
+	require.Nil(t, pb.WorkspaceId)
+	require.Equal(t, int32(4), pb.GetUserId())
+}
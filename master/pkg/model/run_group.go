@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// RunGroup is the bun model of a group of experiments, like the experiments of a sweep, that can
+// span projects and workspaces. Who can see a group and its members follows from who can see its
+// member experiments.
+type RunGroup struct {
+	bun.BaseModel `bun:"table:run_groups"`
+	ID            int       `bun:"id,pk,autoincrement" json:"id"`
+	Name          string    `bun:"name" json:"name"`
+	Description   string    `bun:"description" json:"description"`
+	OwnerID       UserID    `bun:"owner_id" json:"owner_id"`
+	CreatedAt     time.Time `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// RunGroupExperiment is the bun model of the membership of an experiment in a run group.
+type RunGroupExperiment struct {
+	bun.BaseModel `bun:"table:run_group_experiments"`
+	GroupID       int       `bun:"group_id,pk" json:"group_id"`
+	ExperimentID  int       `bun:"experiment_id,pk" json:"experiment_id"`
+	AddedAt       time.Time `bun:"added_at,scanonly" json:"added_at"`
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+const sparkKubernetesMasterPrefix = "k8s://"
+
+// sparkReservedConf are the Spark properties jobs can't set, since they choose where a job is
+// submitted to, or what its pods run as and run. The administrator's defaults may set them.
+var sparkReservedConf = map[string]bool{
+	"spark.master":                                       true,
+	"spark.submit.deployMode":                            true,
+	"spark.kubernetes.namespace":                         true,
+	"spark.kubernetes.driver.pod.name":                   true,
+	"spark.kubernetes.container.image":                   true,
+	"spark.kubernetes.driver.container.image":            true,
+	"spark.kubernetes.executor.container.image":          true,
+	"spark.kubernetes.driver.podTemplateFile":            true,
+	"spark.kubernetes.executor.podTemplateFile":          true,
+	"spark.kubernetes.driver.podTemplateContainerName":   true,
+	"spark.kubernetes.executor.podTemplateContainerName": true,
+}
+
+// sparkReservedConfPrefix are prefixes of reserved properties, like those of the service account
+// and credentials that pods are created with.
+var sparkReservedConfPrefix = []string{"spark.kubernetes.authenticate."}
+
+func isSparkReservedConf(key string) bool {
+	if sparkReservedConf[key] {
+		return true
+	}
+	for _, prefix := range sparkReservedConfPrefix {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SparkBackendConfig configures the Spark cluster, or Kubernetes cluster, that Spark jobs are
+// submitted to.
+type SparkBackendConfig struct {
+	// Master is passed to spark-submit as --master, e.g. k8s://https://kubernetes.default.svc.
+	Master string `json:"master"`
+	// Namespace is the Kubernetes namespace executors run in.
+	Namespace string `json:"namespace"`
+	// ExecutorImage is the image of executor pods on Kubernetes.
+	ExecutorImage string `json:"executor_image"`
+	// Conf are default Spark properties, which jobs may override.
+	Conf map[string]string `json:"conf"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *SparkBackendConfig) Validate() []error {
+	return []error{
+		check.NotEmpty(c.Master, "integrations.spark.master must be set"),
+	}
+}
+
+// IsKubernetes returns whether executors run as pods on Kubernetes.
+func (c SparkBackendConfig) IsKubernetes() bool {
+	return strings.HasPrefix(c.Master, sparkKubernetesMasterPrefix)
+}
+
+// SparkJobConfig configures a generic task that submits a Spark application. The task runs the
+// application's driver, so its state and logs are those of the driver.
+type SparkJobConfig struct {
+	// Application is the path or URI of the application's jar or Python file.
+	Application string            `json:"application"`
+	MainClass   string            `json:"main_class,omitempty"`
+	Args        []string          `json:"args,omitempty"`
+	Conf        map[string]string `json:"conf,omitempty"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c *SparkJobConfig) Validate() []error {
+	errs := []error{
+		check.NotEmpty(c.Application, "spark.application must be set"),
+	}
+	keys := make([]string, 0, len(c.Conf))
+	for k := range c.Conf {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		errs = append(errs, check.False(isSparkReservedConf(k), "spark.conf can't set %s", k))
+	}
+	return errs
+}
+
+// SparkSubmitCommand templates the spark-submit command that runs a job's driver in the task's
+// container against a backend. Properties that are only known once the container runs, like the
+// name of the driver's pod, are added by the container's entrypoint.
+func SparkSubmitCommand(backend SparkBackendConfig, job SparkJobConfig) []string {
+	conf := map[string]string{}
+	for k, v := range backend.Conf {
+		conf[k] = v
+	}
+	if backend.IsKubernetes() && backend.ExecutorImage != "" {
+		conf["spark.kubernetes.container.image"] = backend.ExecutorImage
+	}
+	for k, v := range job.Conf {
+		if !isSparkReservedConf(k) {
+			conf[k] = v
+		}
+	}
+	delete(conf, "spark.master")
+	delete(conf, "spark.submit.deployMode")
+	delete(conf, "spark.kubernetes.driver.pod.name")
+	if backend.IsKubernetes() && backend.Namespace != "" {
+		conf["spark.kubernetes.namespace"] = backend.Namespace
+	}
+
+	keys := make([]string, 0, len(conf))
+	for k := range conf {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	cmd := []string{"spark-submit", "--master", backend.Master, "--deploy-mode", "client"}
+	for _, k := range keys {
+		cmd = append(cmd, "--conf", fmt.Sprintf("%s=%s", k, conf[k]))
+	}
+	if job.MainClass != "" {
+		cmd = append(cmd, "--class", job.MainClass)
+	}
+	cmd = append(cmd, job.Application)
+	return append(cmd, job.Args...)
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+func TestSparkSubmitCommand(t *testing.T) {
+	backend := SparkBackendConfig{
+		Master:        "k8s://https://kubernetes.default.svc",
+		Namespace:     "spark",
+		ExecutorImage: "apache/spark:3.5.0",
+		Conf:          map[string]string{"spark.executor.instances": "2", "spark.ui.enabled": "false"},
+	}
+	job := SparkJobConfig{
+		Application: "local:///opt/app.jar",
+		MainClass:   "org.example.App",
+		Args:        []string{"--input", "s3://bucket/data"},
+		Conf: map[string]string{
+			"spark.executor.instances":   "4",
+			"spark.master":               "local[*]",
+			"spark.kubernetes.namespace": "other",
+			"spark.kubernetes.authenticate.driver.serviceAccountName": "admin",
+			"spark.kubernetes.container.image":                        "evil/spark",
+			"spark.kubernetes.driver.pod.name":                        "other-pod",
+		},
+	}
+	require.Equal(t, []string{
+		"spark-submit", "--master", "k8s://https://kubernetes.default.svc", "--deploy-mode", "client",
+		"--conf", "spark.executor.instances=4",
+		"--conf", "spark.kubernetes.container.image=apache/spark:3.5.0",
+		"--conf", "spark.kubernetes.namespace=spark",
+		"--conf", "spark.ui.enabled=false",
+		"--class", "org.example.App",
+		"local:///opt/app.jar", "--input", "s3://bucket/data",
+	}, SparkSubmitCommand(backend, job))
+
+	// Kubernetes properties are only set for Kubernetes backends.
+	backend = SparkBackendConfig{
+		Master:        "spark://spark-master:7077",
+		ExecutorImage: "apache/spark:3.5.0",
+		Conf:          map[string]string{"spark.kubernetes.namespace": "other"},
+	}
+	require.Equal(t, []string{
+		"spark-submit", "--master", "spark://spark-master:7077", "--deploy-mode", "client",
+		"--conf", "spark.kubernetes.namespace=other",
+		"app.py",
+	}, SparkSubmitCommand(backend, SparkJobConfig{Application: "app.py"}))
+}
+
+func TestSparkJobConfigReservedConf(t *testing.T) {
+	c := SparkJobConfig{
+		Application: "app.py",
+		Conf:        map[string]string{"spark.executor.memory": "4g"},
+	}
+	require.NoError(t, check.Validate(c))
+
+	for _, k := range []string{
+		"spark.master",
+		"spark.kubernetes.authenticate.driver.serviceAccountName",
+		"spark.kubernetes.executor.container.image",
+		"spark.kubernetes.driver.podTemplateFile",
+	} {
+		c.Conf = map[string]string{k: "x"}
+		require.ErrorContains(t, check.Validate(c), k)
+	}
+}
+
+func TestGenericTaskConfigSpark(t *testing.T) {
+	c := DefaultConfigGenericTaskConfig(nil)
+	c.Spark = &SparkJobConfig{Application: "app.py"}
+	require.NoError(t, check.Validate(c))
+
+	c.Entrypoint = []string{"python", "app.py"}
+	require.Error(t, check.Validate(c), "a spark job has no entrypoint")
+
+	c.Entrypoint = nil
+	c.Ray = &RayClusterConfig{HeadPort: DefaultRayHeadPort, DashboardPort: DefaultRayDashboardPort}
+	require.Error(t, check.Validate(c), "a task can't be both a spark job and a ray cluster")
+
+	c.Ray, c.Spark = nil, &SparkJobConfig{}
+	require.Error(t, check.Validate(c), "a spark job needs an application")
+}
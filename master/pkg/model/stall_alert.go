@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// StallAlert is the bun model of a time liveness detection found an allocation stalled, because it
+// stopped sending heartbeats or stopped reporting steps.
+type StallAlert struct {
+	bun.BaseModel `bun:"table:stall_alerts"`
+	ID            int          `bun:"id,pk,autoincrement" json:"id"`
+	TaskID        TaskID       `bun:"task_id" json:"task_id"`
+	AllocationID  AllocationID `bun:"allocation_id" json:"allocation_id"`
+	Reason        string       `bun:"reason" json:"reason"`
+	Restarted     bool         `bun:"restarted" json:"restarted"`
+	CreatedAt     time.Time    `bun:"created_at,scanonly" json:"created_at"`
+	RecoveredAt   *time.Time   `bun:"recovered_at" json:"recovered_at"`
+}
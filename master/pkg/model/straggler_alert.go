@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+// StragglerAlert is the bun model of a rank straggler detection found consistently lagging behind
+// its peers in a distributed allocation.
+type StragglerAlert struct {
+	bun.BaseModel `bun:"table:straggler_alerts"`
+	ID            int          `bun:"id,pk,autoincrement" json:"id"`
+	TaskID        TaskID       `bun:"task_id" json:"task_id"`
+	AllocationID  AllocationID `bun:"allocation_id" json:"allocation_id"`
+	Rank          int          `bun:"rank" json:"rank"`
+	AgentID       string       `bun:"agent_id" json:"agent_id"`
+	Slowdown      float64      `bun:"slowdown" json:"slowdown"`
+	Windows       int          `bun:"windows" json:"windows"`
+	Excluded      bool         `bun:"excluded" json:"excluded"`
+	CreatedAt     time.Time    `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts a straggler alert to its protobuf representation.
+func (a StragglerAlert) Proto() *taskv1.StragglerAlert {
+	return &taskv1.StragglerAlert{
+		Id:           int32(a.ID),
+		TaskId:       string(a.TaskID),
+		AllocationId: string(a.AllocationID),
+		Rank:         int32(a.Rank),
+		AgentId:      a.AgentID,
+		Slowdown:     a.Slowdown,
+		Windows:      int32(a.Windows),
+		Excluded:     a.Excluded,
+		CreatedAt:    timestamppb.New(a.CreatedAt),
+	}
+}
@@ -1,6 +1,9 @@
 package model
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -39,6 +42,73 @@ type ResourceConstraints struct {
 // Submitted workloads whose config's respective field(s) exceed defined constraints within a given
 // scope are rejected.
 type Constraints struct {
-	ResourceConstraints *ResourceConstraints `json:"resources"`
-	PriorityLimit       *int                 `json:"priority_limit"`
+	ResourceConstraints *ResourceConstraints  `json:"resources"`
+	PriorityLimit       *int                  `json:"priority_limit"`
+	BindMounts          *BindMountConstraints `json:"bind_mounts"`
+}
+
+// BindMountConstraints restrict which host paths workloads may bind mount. Each path covers
+// itself and everything under it, so "/data" covers "/data/shared" but not "/database".
+type BindMountConstraints struct {
+	// AllowedHostPaths, if set, are the only host paths that may be bind mounted.
+	AllowedHostPaths []string `json:"allowed_host_paths"`
+	// DeniedHostPaths may never be bind mounted, even if they're under an allowed host path.
+	DeniedHostPaths []string `json:"denied_host_paths"`
+	// ReadOnlyHostPaths may only be bind mounted read-only.
+	ReadOnlyHostPaths []string `json:"read_only_host_paths"`
+}
+
+// Validate implements the check.Validatable interface.
+func (b BindMountConstraints) Validate() []error {
+	var errs []error
+	for _, paths := range [][]string{b.AllowedHostPaths, b.DeniedHostPaths, b.ReadOnlyHostPaths} {
+		for _, p := range paths {
+			if !filepath.IsAbs(p) {
+				errs = append(errs, fmt.Errorf("bind mount host path %q must be absolute", p))
+			}
+		}
+	}
+	return errs
+}
+
+// CheckHostPath returns an error if bind mounting hostPath violates the constraints. Mounting a
+// parent of a denied or read-only path is treated as mounting that path, since it exposes it.
+func (b BindMountConstraints) CheckHostPath(hostPath string, readOnly bool) error {
+	if len(b.AllowedHostPaths) == 0 && len(b.DeniedHostPaths) == 0 && len(b.ReadOnlyHostPaths) == 0 {
+		return nil
+	}
+	if !filepath.IsAbs(hostPath) {
+		return fmt.Errorf("bind mount host path %q must be absolute", hostPath)
+	}
+	hostPath = filepath.Clean(hostPath)
+	for _, p := range b.DeniedHostPaths {
+		if hostPathUnder(hostPath, p) || hostPathUnder(p, hostPath) {
+			return fmt.Errorf("bind mounting host path %q is denied because it exposes %q", hostPath, p)
+		}
+	}
+	if len(b.AllowedHostPaths) > 0 {
+		allowed := false
+		for _, p := range b.AllowedHostPaths {
+			allowed = allowed || hostPathUnder(hostPath, p)
+		}
+		if !allowed {
+			return fmt.Errorf("bind mounting host path %q is denied because it isn't under any of "+
+				"the allowed host paths %v", hostPath, b.AllowedHostPaths)
+		}
+	}
+	if !readOnly {
+		for _, p := range b.ReadOnlyHostPaths {
+			if hostPathUnder(hostPath, p) || hostPathUnder(p, hostPath) {
+				return fmt.Errorf("bind mount of host path %q must be read-only because it exposes %q",
+					hostPath, p)
+			}
+		}
+	}
+	return nil
+}
+
+// hostPathUnder returns true if path is prefix or is under it.
+func hostPathUnder(path, prefix string) bool {
+	path, prefix = filepath.Clean(path), filepath.Clean(prefix)
+	return path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
 }
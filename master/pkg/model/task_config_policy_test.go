@@ -0,0 +1,55 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindMountConstraintsCheckHostPath(t *testing.T) {
+	b := BindMountConstraints{
+		AllowedHostPaths:  []string{"/data", "/scratch/"},
+		DeniedHostPaths:   []string{"/data/secrets"},
+		ReadOnlyHostPaths: []string{"/data/datasets"},
+	}
+	cases := []struct {
+		hostPath string
+		readOnly bool
+		allowed  bool
+	}{
+		{"/data", true, false}, // Exposes /data/secrets.
+		{"/data/shared", false, true},
+		{"/scratch/user/", false, true},
+		{"/database", true, false},
+		{"/etc", true, false},
+		{"relative/path", true, false},
+		{"/data/secrets/key", true, false},
+		{"/data/../data/secrets", true, false},
+		{"/data/datasets/mnist", true, true},
+		{"/data/datasets/mnist", false, false},
+		{"/data", false, false},
+	}
+	for _, c := range cases {
+		err := b.CheckHostPath(c.hostPath, c.readOnly)
+		if c.allowed {
+			require.NoError(t, err, c.hostPath)
+		} else {
+			require.Error(t, err, c.hostPath)
+		}
+	}
+
+	// A parent of a denied path exposes it, even without an allow list.
+	b = BindMountConstraints{DeniedHostPaths: []string{"/etc", "/var/run/docker.sock"}}
+	require.Error(t, b.CheckHostPath("/", true))
+	require.Error(t, b.CheckHostPath("/var/run", true))
+	require.NoError(t, b.CheckHostPath("/var/log", false))
+	require.NoError(t, BindMountConstraints{}.CheckHostPath("/etc", false))
+}
+
+func TestBindMountConstraintsValidate(t *testing.T) {
+	require.Empty(t, BindMountConstraints{DeniedHostPaths: []string{"/etc"}}.Validate())
+	require.Len(t, BindMountConstraints{
+		AllowedHostPaths:  []string{"data"},
+		ReadOnlyHostPaths: []string{"~/datasets"},
+	}.Validate(), 2)
+}
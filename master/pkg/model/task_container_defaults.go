@@ -3,6 +3,9 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"net"
+	"reflect"
 	"slices"
 	"strings"
 
@@ -29,6 +32,10 @@ type TaskContainerDefaultsConfig struct {
 	GLOOPortRange          string                `json:"gloo_port_range,omitempty"`
 	ShmSizeBytes           int64                 `json:"shm_size_bytes,omitempty"`
 	NetworkMode            container.NetworkMode `json:"network_mode,omitempty"`
+	// DtrainNetworkInterfaceDetection is whether multi-agent tasks communicate over the interface
+	// each agent reports when DtrainNetworkInterface is unset. It defaults to true.
+	DtrainNetworkInterfaceDetection *bool `json:"dtrain_network_interface_detection,omitempty"`
+
 	// TODO(DET-9855) we should move these over to KubernetesTaskContainerDefaults.
 	CPUPodSpec           *k8sV1.Pod           `json:"cpu_pod_spec"`
 	GPUPodSpec           *k8sV1.Pod           `json:"gpu_pod_spec"`
@@ -38,9 +45,10 @@ type TaskContainerDefaultsConfig struct {
 	ForcePullImage       bool                 `json:"force_pull_image,omitempty"`
 	EnvironmentVariables *RuntimeItems        `json:"environment_variables,omitempty"`
 
-	AddCapabilities  []string      `json:"add_capabilities"`
-	DropCapabilities []string      `json:"drop_capabilities"`
-	Devices          DevicesConfig `json:"devices"`
+	AddCapabilities  []string       `json:"add_capabilities"`
+	DropCapabilities []string       `json:"drop_capabilities"`
+	Devices          DevicesConfig  `json:"devices"`
+	Ulimits          []UlimitConfig `json:"ulimits,omitempty"`
 
 	BindMounts BindMountsConfig      `json:"bind_mounts"`
 	WorkDir    *string               `json:"work_dir"`
@@ -98,6 +106,7 @@ func (c *TaskContainerDefaultsConfig) Validate() []error {
 		check.NotEmpty(string(c.NetworkMode), "network_mode must be set"),
 	}
 
+	errs = append(errs, validateUlimits(c.Ulimits)...)
 	errs = append(errs, validatePodSpec(c.CPUPodSpec)...)
 	errs = append(errs, validatePodSpec(c.GPUPodSpec)...)
 	errs = append(errs, validatePodSpec(c.CheckpointGCPodSpec)...)
@@ -105,9 +114,189 @@ func (c *TaskContainerDefaultsConfig) Validate() []error {
 	return errs
 }
 
+// UlimitConfig configures a resource limit of task containers, such as memlock for InfiniBand.
+// A hard limit of -1 means unlimited.
+type UlimitConfig struct {
+	Name string `json:"name"`
+	Soft int64  `json:"soft"`
+	Hard int64  `json:"hard"`
+}
+
+func validateUlimits(ulimits []UlimitConfig) []error {
+	var errs []error
+	names := set.New[string]()
+	for _, u := range ulimits {
+		switch {
+		case u.Name == "":
+			errs = append(errs, errors.New("ulimits must have a name"))
+		case names.Contains(u.Name):
+			errs = append(errs, fmt.Errorf("ulimit %s is set more than once", u.Name))
+		case u.Hard >= 0 && (u.Soft < 0 || u.Soft > u.Hard):
+			errs = append(errs, fmt.Errorf("ulimit %s soft limit must not exceed its hard limit", u.Name))
+		}
+		names.Insert(u.Name)
+	}
+	return errs
+}
+
 // KubernetesTaskContainerDefaults is task container defaults specific to Kubernetes.
 type KubernetesTaskContainerDefaults struct {
-	MaxSlotsPerPod *int `json:"max_slots_per_pod"`
+	MaxSlotsPerPod *int                           `json:"max_slots_per_pod"`
+	NetworkPolicy  *KubernetesNetworkPolicyConfig `json:"network_policy"`
+	// SecurityContext and RuntimeClassName are applied to every task pod unless its pod spec opts
+	// out of them, which takes permission to opt out of pod security in the workspace.
+	SecurityContext  *KubernetesSecurityContextConfig `json:"security_context"`
+	RuntimeClassName *string                          `json:"runtime_class_name"`
+}
+
+// Settings of KubernetesTaskContainerDefaults a task's pod spec may opt out of.
+const (
+	PodSecurityRuntimeClassName       = "runtime_class_name"
+	PodSecurityRunAsNonRoot           = "run_as_non_root"
+	PodSecuritySeccompProfile         = "seccomp_profile"
+	PodSecurityReadOnlyRootFilesystem = "read_only_root_filesystem"
+)
+
+// PodSecurityOptOuts returns which pod security defaults a task's pod spec opts out of, by setting
+// them to something else on the pod or any of its containers or init containers.
+func (k KubernetesTaskContainerDefaults) PodSecurityOptOuts(pod *k8sV1.Pod) []string {
+	if pod == nil {
+		return nil
+	}
+	var optOuts []string
+	if k.RuntimeClassName != nil && pod.Spec.RuntimeClassName != nil &&
+		*pod.Spec.RuntimeClassName != *k.RuntimeClassName {
+		optOuts = append(optOuts, PodSecurityRuntimeClassName)
+	}
+	sc := k.SecurityContext
+	if sc == nil {
+		return optOuts
+	}
+
+	podSC := pod.Spec.SecurityContext
+	if podSC == nil {
+		podSC = &k8sV1.PodSecurityContext{}
+	}
+	var containerSCs []*k8sV1.SecurityContext
+	for _, cs := range [][]k8sV1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range cs {
+			if c.SecurityContext != nil {
+				containerSCs = append(containerSCs, c.SecurityContext)
+			}
+		}
+	}
+
+	if sc.RunAsNonRoot {
+		optOut := podSC.RunAsNonRoot != nil && !*podSC.RunAsNonRoot
+		for _, c := range containerSCs {
+			optOut = optOut || (c.RunAsNonRoot != nil && !*c.RunAsNonRoot)
+		}
+		if optOut {
+			optOuts = append(optOuts, PodSecurityRunAsNonRoot)
+		}
+	}
+	if profile := sc.K8sSeccompProfile(); profile != nil {
+		profiles := []*k8sV1.SeccompProfile{podSC.SeccompProfile}
+		for _, c := range containerSCs {
+			profiles = append(profiles, c.SeccompProfile)
+		}
+		for _, p := range profiles {
+			if p != nil && !reflect.DeepEqual(p, profile) {
+				optOuts = append(optOuts, PodSecuritySeccompProfile)
+				break
+			}
+		}
+	}
+	if sc.ReadOnlyRootFilesystem {
+		for _, c := range containerSCs {
+			if c.ReadOnlyRootFilesystem != nil && !*c.ReadOnlyRootFilesystem {
+				optOuts = append(optOuts, PodSecurityReadOnlyRootFilesystem)
+				break
+			}
+		}
+	}
+	return optOuts
+}
+
+// KubernetesSecurityContextConfig is the security context of task pods and all of their
+// containers.
+type KubernetesSecurityContextConfig struct {
+	// RunAsNonRoot refuses to start containers as root. Tasks whose agent user is root run as
+	// NonRootUID and NonRootGID instead, if they're set.
+	RunAsNonRoot bool `json:"run_as_non_root"`
+	NonRootUID   *int `json:"non_root_uid"`
+	NonRootGID   *int `json:"non_root_gid"`
+	// SeccompProfile is "RuntimeDefault" or "localhost/<path>" for a profile on the node.
+	SeccompProfile         string `json:"seccomp_profile"`
+	ReadOnlyRootFilesystem bool   `json:"read_only_root_filesystem"`
+}
+
+const localhostSeccompProfilePrefix = "localhost/"
+
+// Validate implements the check.Validatable interface.
+func (s KubernetesSecurityContextConfig) Validate() []error {
+	var errs []error
+	if s.NonRootUID != nil && *s.NonRootUID <= 0 {
+		errs = append(errs, errors.New("security_context.non_root_uid must be greater than 0"))
+	}
+	if s.NonRootGID != nil && *s.NonRootGID < 0 {
+		errs = append(errs, errors.New("security_context.non_root_gid must not be negative"))
+	}
+	if s.SeccompProfile != "" && s.SeccompProfile != string(k8sV1.SeccompProfileTypeRuntimeDefault) &&
+		(!strings.HasPrefix(s.SeccompProfile, localhostSeccompProfilePrefix) ||
+			s.SeccompProfile == localhostSeccompProfilePrefix) {
+		errs = append(errs, fmt.Errorf(
+			"security_context.seccomp_profile must be RuntimeDefault or localhost/<path>, not %q",
+			s.SeccompProfile))
+	}
+	return errs
+}
+
+// K8sSeccompProfile returns the SeccompProfile, or nil if it's unset.
+func (s KubernetesSecurityContextConfig) K8sSeccompProfile() *k8sV1.SeccompProfile {
+	switch {
+	case s.SeccompProfile == "":
+		return nil
+	case strings.HasPrefix(s.SeccompProfile, localhostSeccompProfilePrefix):
+		return &k8sV1.SeccompProfile{
+			Type:             k8sV1.SeccompProfileTypeLocalhost,
+			LocalhostProfile: ptrs.Ptr(strings.TrimPrefix(s.SeccompProfile, localhostSeccompProfilePrefix)),
+		}
+	default:
+		return &k8sV1.SeccompProfile{Type: k8sV1.SeccompProfileType(s.SeccompProfile)}
+	}
+}
+
+// KubernetesNetworkPolicyConfig configures a NetworkPolicy created for each allocation, which
+// restricts its pods to traffic with each other and the master, so users' jobs are isolated.
+type KubernetesNetworkPolicyConfig struct {
+	Enabled bool `json:"enabled"`
+	// MasterPodLabels select the master's pods, in MasterNamespace if it's set or else in the
+	// namespace of the allocation.
+	MasterPodLabels map[string]string `json:"master_pod_labels"`
+	MasterNamespace string            `json:"master_namespace"`
+	// MasterCIDRs are the addresses of the master, for masters outside the cluster.
+	MasterCIDRs []string `json:"master_cidrs"`
+	// AllowedEgressCIDRs are other addresses pods may connect to, such as checkpoint storage.
+	AllowedEgressCIDRs []string `json:"allowed_egress_cidrs"`
+}
+
+// Validate implements the check.Validatable interface.
+func (n KubernetesNetworkPolicyConfig) Validate() []error {
+	if !n.Enabled {
+		return nil
+	}
+	var errs []error
+	if len(n.MasterPodLabels) == 0 && len(n.MasterCIDRs) == 0 {
+		errs = append(errs, errors.New(
+			"network_policy must set master_pod_labels or master_cidrs so tasks can reach the master"))
+	}
+	for _, cidr := range append(slices.Clone(n.MasterCIDRs), n.AllowedEgressCIDRs...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fmt.Errorf("network_policy has invalid CIDR %q: %w", cidr, err))
+		}
+	}
+	return errs
 }
 
 // MergeIntoExpConfig sets any unset ExperimentConfig values from TaskContainerDefaults.
@@ -193,6 +382,10 @@ func (c TaskContainerDefaultsConfig) Merge(
 		res.DtrainNetworkInterface = other.DtrainNetworkInterface
 	}
 
+	if other.DtrainNetworkInterfaceDetection != nil {
+		res.DtrainNetworkInterfaceDetection = ptrs.Ptr(*other.DtrainNetworkInterfaceDetection)
+	}
+
 	if other.NCCLPortRange != "" {
 		res.NCCLPortRange = other.NCCLPortRange
 	}
@@ -275,6 +468,22 @@ func (c TaskContainerDefaultsConfig) Merge(
 		}
 	}
 
+	if other.Ulimits != nil {
+		tmp := res.Ulimits
+		res.Ulimits = slices.Clone(other.Ulimits)
+
+		names := set.New[string]()
+		for _, u := range res.Ulimits {
+			names.Insert(u.Name)
+		}
+		for _, u := range tmp {
+			if names.Contains(u.Name) {
+				continue
+			}
+			res.Ulimits = append(res.Ulimits, u)
+		}
+	}
+
 	if other.StartupHook != "" {
 		res.StartupHook = other.StartupHook
 	}
@@ -331,6 +540,32 @@ func (c TaskContainerDefaultsConfig) Merge(
 		res.PreemptionTimeout = other.PreemptionTimeout
 	}
 
+	if other.Kubernetes != nil && other.Kubernetes.NetworkPolicy != nil {
+		if res.Kubernetes == nil {
+			res.Kubernetes = &KubernetesTaskContainerDefaults{}
+		}
+		np := *other.Kubernetes.NetworkPolicy
+		np.MasterPodLabels = maps.Clone(np.MasterPodLabels)
+		np.MasterCIDRs = slices.Clone(np.MasterCIDRs)
+		np.AllowedEgressCIDRs = slices.Clone(np.AllowedEgressCIDRs)
+		res.Kubernetes.NetworkPolicy = &np
+	}
+
+	if other.Kubernetes != nil && other.Kubernetes.SecurityContext != nil {
+		if res.Kubernetes == nil {
+			res.Kubernetes = &KubernetesTaskContainerDefaults{}
+		}
+		sc := *other.Kubernetes.SecurityContext
+		res.Kubernetes.SecurityContext = &sc
+	}
+
+	if other.Kubernetes != nil && other.Kubernetes.RuntimeClassName != nil {
+		if res.Kubernetes == nil {
+			res.Kubernetes = &KubernetesTaskContainerDefaults{}
+		}
+		res.Kubernetes.RuntimeClassName = ptrs.Ptr(*other.Kubernetes.RuntimeClassName)
+	}
+
 	return res, nil
 }
 
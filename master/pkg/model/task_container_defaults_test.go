@@ -418,6 +418,25 @@ func TestTaskContainerDefaultsConfigMerging(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "merge ulimits",
+			self: TaskContainerDefaultsConfig{
+				Ulimits: []UlimitConfig{
+					{Name: "memlock", Soft: 1024, Hard: 1024},
+					{Name: "nofile", Soft: 1024, Hard: 4096},
+				},
+			},
+			other: TaskContainerDefaultsConfig{
+				Ulimits: []UlimitConfig{{Name: "memlock", Soft: -1, Hard: -1}},
+			},
+			want: TaskContainerDefaultsConfig{
+				Ulimits: []UlimitConfig{
+					{Name: "memlock", Soft: -1, Hard: -1},
+					{Name: "nofile", Soft: 1024, Hard: 4096},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -528,3 +547,130 @@ func TestPodSpecsDefaultMerging(t *testing.T) {
 		require.Equal(t, expected, conf.RawEnvironment.RawPodSpec)
 	}
 }
+
+func TestKubernetesNetworkPolicyConfigValidate(t *testing.T) {
+	require.Empty(t, KubernetesNetworkPolicyConfig{}.Validate())
+	require.Empty(t, KubernetesNetworkPolicyConfig{
+		Enabled:            true,
+		MasterPodLabels:    map[string]string{"app": "determined-master"},
+		AllowedEgressCIDRs: []string{"10.20.0.0/16"},
+	}.Validate())
+	require.Len(t, KubernetesNetworkPolicyConfig{Enabled: true}.Validate(), 1)
+	require.Len(t, KubernetesNetworkPolicyConfig{
+		Enabled:            true,
+		MasterCIDRs:        []string{"10.0.0.1"},
+		AllowedEgressCIDRs: []string{"not a cidr"},
+	}.Validate(), 2)
+}
+
+func TestMergeKubernetesNetworkPolicy(t *testing.T) {
+	base := TaskContainerDefaultsConfig{
+		Kubernetes: &KubernetesTaskContainerDefaults{MaxSlotsPerPod: ptrs.Ptr(4)},
+	}
+	pool := TaskContainerDefaultsConfig{
+		Kubernetes: &KubernetesTaskContainerDefaults{
+			NetworkPolicy: &KubernetesNetworkPolicyConfig{
+				Enabled:     true,
+				MasterCIDRs: []string{"10.0.0.1/32"},
+			},
+		},
+	}
+	merged, err := base.Merge(pool)
+	require.NoError(t, err)
+	require.Equal(t, 4, *merged.Kubernetes.MaxSlotsPerPod)
+	require.Equal(t, *pool.Kubernetes.NetworkPolicy, *merged.Kubernetes.NetworkPolicy)
+	require.Nil(t, base.Kubernetes.NetworkPolicy)
+
+	merged.Kubernetes.NetworkPolicy.MasterCIDRs[0] = "10.0.0.2/32"
+	require.Equal(t, "10.0.0.1/32", pool.Kubernetes.NetworkPolicy.MasterCIDRs[0])
+}
+
+func TestKubernetesSecurityContextConfigValidate(t *testing.T) {
+	require.Empty(t, KubernetesSecurityContextConfig{}.Validate())
+	require.Empty(t, KubernetesSecurityContextConfig{
+		RunAsNonRoot:   true,
+		NonRootUID:     ptrs.Ptr(1000),
+		NonRootGID:     ptrs.Ptr(0),
+		SeccompProfile: "localhost/profiles/det.json",
+	}.Validate())
+	require.Len(t, KubernetesSecurityContextConfig{
+		NonRootUID:     ptrs.Ptr(0),
+		SeccompProfile: "Unconfined",
+	}.Validate(), 2)
+	require.Len(t, KubernetesSecurityContextConfig{SeccompProfile: "localhost/"}.Validate(), 1)
+}
+
+func TestPodSecurityOptOuts(t *testing.T) {
+	k := KubernetesTaskContainerDefaults{
+		RuntimeClassName: ptrs.Ptr("gvisor"),
+		SecurityContext: &KubernetesSecurityContextConfig{
+			RunAsNonRoot:           true,
+			SeccompProfile:         "RuntimeDefault",
+			ReadOnlyRootFilesystem: true,
+		},
+	}
+	require.Empty(t, k.PodSecurityOptOuts(nil))
+
+	// Setting what the pool already sets isn't opting out.
+	pod := &k8sV1.Pod{Spec: k8sV1.PodSpec{
+		RuntimeClassName: ptrs.Ptr("gvisor"),
+		SecurityContext: &k8sV1.PodSecurityContext{
+			SeccompProfile: &k8sV1.SeccompProfile{Type: k8sV1.SeccompProfileTypeRuntimeDefault},
+		},
+		Containers: []k8sV1.Container{{
+			Name:            DeterminedK8ContainerName,
+			SecurityContext: &k8sV1.SecurityContext{RunAsNonRoot: ptrs.Ptr(true)},
+		}},
+	}}
+	require.Empty(t, k.PodSecurityOptOuts(pod))
+
+	// Every container and init container is checked, not just the Determined container.
+	sidecar := pod.DeepCopy()
+	sidecar.Spec.Containers = append(sidecar.Spec.Containers, k8sV1.Container{
+		Name:            "sidecar",
+		SecurityContext: &k8sV1.SecurityContext{ReadOnlyRootFilesystem: ptrs.Ptr(false)},
+	})
+	sidecar.Spec.InitContainers = []k8sV1.Container{{
+		Name:            "init",
+		SecurityContext: &k8sV1.SecurityContext{RunAsNonRoot: ptrs.Ptr(false)},
+	}}
+	require.Equal(t, []string{PodSecurityRunAsNonRoot, PodSecurityReadOnlyRootFilesystem},
+		k.PodSecurityOptOuts(sidecar))
+
+	pod.Spec.RuntimeClassName = ptrs.Ptr("runc")
+	pod.Spec.Containers[0].SecurityContext = &k8sV1.SecurityContext{
+		RunAsNonRoot:           ptrs.Ptr(false),
+		ReadOnlyRootFilesystem: ptrs.Ptr(false),
+		SeccompProfile:         &k8sV1.SeccompProfile{Type: k8sV1.SeccompProfileTypeUnconfined},
+	}
+	require.Equal(t, []string{
+		PodSecurityRuntimeClassName,
+		PodSecurityRunAsNonRoot,
+		PodSecuritySeccompProfile,
+		PodSecurityReadOnlyRootFilesystem,
+	}, k.PodSecurityOptOuts(pod))
+
+	// Nothing is opted out of if the pool doesn't set it.
+	require.Empty(t, KubernetesTaskContainerDefaults{}.PodSecurityOptOuts(pod))
+}
+
+func TestMergeKubernetesPodSecurity(t *testing.T) {
+	base := TaskContainerDefaultsConfig{
+		Kubernetes: &KubernetesTaskContainerDefaults{RuntimeClassName: ptrs.Ptr("kata")},
+	}
+	pool := TaskContainerDefaultsConfig{
+		Kubernetes: &KubernetesTaskContainerDefaults{
+			SecurityContext: &KubernetesSecurityContextConfig{RunAsNonRoot: true},
+		},
+	}
+	merged, err := base.Merge(pool)
+	require.NoError(t, err)
+	require.Equal(t, "kata", *merged.Kubernetes.RuntimeClassName)
+	require.Equal(t, *pool.Kubernetes.SecurityContext, *merged.Kubernetes.SecurityContext)
+	require.Nil(t, base.Kubernetes.SecurityContext)
+
+	pool.Kubernetes.RuntimeClassName = ptrs.Ptr("gvisor")
+	merged, err = base.Merge(pool)
+	require.NoError(t, err)
+	require.Equal(t, "gvisor", *merged.Kubernetes.RuntimeClassName)
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+const (
+	// CommandOutputsEnvVar is the environment variable listing, as a JSON array, the paths a
+	// command container uploads to storage when the command exits.
+	CommandOutputsEnvVar = "DET_COMMAND_OUTPUTS"
+	// CommandOutputsStorageConfigEnvVar is the environment variable holding the path to the
+	// storage config file used to upload command outputs.
+	CommandOutputsStorageConfigEnvVar = "DET_COMMAND_OUTPUTS_STORAGE_CONFIG"
+	// CommandOutputsStorageConfigPath is where the outputs storage config is written inside the
+	// command container.
+	CommandOutputsStorageConfigPath = "/run/determined/outputs-storage.json"
+)
+
+// TaskOutput is the bun model of a file or directory a task uploaded to storage when it exited.
+type TaskOutput struct {
+	bun.BaseModel `bun:"table:task_outputs"`
+	ID            int       `bun:"id,pk,autoincrement" json:"id"`
+	TaskID        TaskID    `bun:"task_id" json:"task_id"`
+	Path          string    `bun:"path" json:"path"`
+	StoragePath   string    `bun:"storage_path" json:"storage_path"`
+	SizeBytes     int64     `bun:"size_bytes" json:"size_bytes"`
+	CreatedAt     time.Time `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts a task output to its protobuf representation.
+func (o TaskOutput) Proto() *taskv1.TaskOutput {
+	return &taskv1.TaskOutput{
+		Id:          int32(o.ID),
+		TaskId:      string(o.TaskID),
+		Path:        o.Path,
+		StoragePath: o.StoragePath,
+		SizeBytes:   o.SizeBytes,
+		CreatedAt:   timestamppb.New(o.CreatedAt),
+	}
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+// ProxyShareAccess is the level of proxied access a task owner grants another user.
+type ProxyShareAccess string
+
+const (
+	// ProxyShareAccessReadOnly allows viewing a task's proxied service without modifying it.
+	ProxyShareAccessReadOnly ProxyShareAccess = "READ_ONLY"
+	// ProxyShareAccessFull allows the same proxied access as the task owner.
+	ProxyShareAccessFull ProxyShareAccess = "FULL"
+)
+
+// ProxyShareAccessFromProto converts a protobuf access level, returning an error for unknown
+// levels.
+func ProxyShareAccessFromProto(a taskv1.ProxyShareAccess) (ProxyShareAccess, error) {
+	switch a {
+	case taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_READ_ONLY:
+		return ProxyShareAccessReadOnly, nil
+	case taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_FULL:
+		return ProxyShareAccessFull, nil
+	default:
+		return "", fmt.Errorf("invalid proxy share access %s, must be one of %s or %s",
+			a, taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_READ_ONLY,
+			taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_FULL)
+	}
+}
+
+// Proto converts an access level to its protobuf representation.
+func (a ProxyShareAccess) Proto() taskv1.ProxyShareAccess {
+	switch a {
+	case ProxyShareAccessReadOnly:
+		return taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_READ_ONLY
+	case ProxyShareAccessFull:
+		return taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_FULL
+	default:
+		return taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_UNSPECIFIED
+	}
+}
+
+// AllowsRequest returns whether a proxied request is permitted at this access level. Read-only
+// access is limited to safe methods and cannot open websockets, which is how notebook kernels and
+// terminals execute code.
+func (a ProxyShareAccess) AllowsRequest(r *http.Request, isWebSocket bool) bool {
+	switch a {
+	case ProxyShareAccessFull:
+		return true
+	case ProxyShareAccessReadOnly:
+		if isWebSocket {
+			return false
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return true
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// TaskProxyShare is the bun model of a grant of proxied access to a task for another user.
+type TaskProxyShare struct {
+	bun.BaseModel `bun:"table:task_proxy_shares"`
+	ID            int              `bun:"id,pk,autoincrement" json:"id"`
+	TaskID        TaskID           `bun:"task_id" json:"task_id"`
+	UserID        UserID           `bun:"user_id" json:"user_id"`
+	Access        ProxyShareAccess `bun:"access" json:"access"`
+	GrantedBy     UserID           `bun:"granted_by" json:"granted_by"`
+	CreatedAt     time.Time        `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts a share to its protobuf representation.
+func (s TaskProxyShare) Proto() *taskv1.TaskProxyShare {
+	return &taskv1.TaskProxyShare{
+		Id:        int32(s.ID),
+		TaskId:    string(s.TaskID),
+		UserId:    int32(s.UserID),
+		Access:    s.Access.Proto(),
+		GrantedBy: int32(s.GrantedBy),
+		CreatedAt: timestamppb.New(s.CreatedAt),
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/taskv1"
+)
+
+func TestProxyShareAccessFromProto(t *testing.T) {
+	for _, a := range []ProxyShareAccess{ProxyShareAccessReadOnly, ProxyShareAccessFull} {
+		parsed, err := ProxyShareAccessFromProto(a.Proto())
+		require.NoError(t, err)
+		require.Equal(t, a, parsed)
+	}
+
+	_, err := ProxyShareAccessFromProto(taskv1.ProxyShareAccess_PROXY_SHARE_ACCESS_UNSPECIFIED)
+	require.ErrorContains(t, err, "invalid proxy share access")
+}
+
+func TestProxyShareAccessAllowsRequest(t *testing.T) {
+	cases := []struct {
+		access    ProxyShareAccess
+		method    string
+		websocket bool
+		allowed   bool
+	}{
+		{ProxyShareAccessFull, http.MethodPut, false, true},
+		{ProxyShareAccessFull, http.MethodGet, true, true},
+		{ProxyShareAccessReadOnly, http.MethodGet, false, true},
+		{ProxyShareAccessReadOnly, http.MethodHead, false, true},
+		{ProxyShareAccessReadOnly, http.MethodGet, true, false},
+		{ProxyShareAccessReadOnly, http.MethodPost, false, false},
+		{ProxyShareAccessReadOnly, http.MethodDelete, false, false},
+		{ProxyShareAccess(""), http.MethodGet, false, false},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(tc.method, "/proxy/task/", nil)
+		require.Equal(t, tc.allowed, tc.access.AllowsRequest(r, tc.websocket),
+			"%s %s websocket=%v", tc.access, tc.method, tc.websocket)
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// FailureClass is what caused an allocation of a trial to fail, which decides the restart budget
+// the failure counts against.
+type FailureClass string
+
+const (
+	// UserFailure is a failure of the trial's code, such as a non-zero exit code. It counts
+	// against max_restarts.
+	UserFailure FailureClass = "user"
+	// InfraFailure is a failure of what the trial runs on, such as a lost node or an agent failing
+	// to launch a container. It counts against max_infra_restarts, if set.
+	InfraFailure FailureClass = "infra"
+)
+
+// TrialFailure is the bun model of a failed allocation of a trial.
+type TrialFailure struct {
+	bun.BaseModel `bun:"table:trial_failures"`
+	ID            int          `bun:"id,pk,autoincrement" json:"id"`
+	TrialID       int          `bun:"trial_id" json:"trial_id"`
+	AllocationID  AllocationID `bun:"allocation_id" json:"allocation_id"`
+	Class         FailureClass `bun:"class" json:"class"`
+	Reason        string       `bun:"reason" json:"reason"`
+	ExitCode      *int         `bun:"exit_code" json:"exit_code"`
+	CreatedAt     time.Time    `bun:"created_at,scanonly" json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// TrialPin is the bun model of a trial pinned within its experiment.
+type TrialPin struct {
+	bun.BaseModel `bun:"table:trial_pins"`
+	TrialID       int `bun:"trial_id,pk"`
+	ExperimentID  int `bun:"experiment_id"`
+	// Position orders the pinned trials of an experiment, like ProjectPin.Position.
+	Position  int       `bun:"position"`
+	PinnedBy  *UserID   `bun:"pinned_by"`
+	CreatedAt time.Time `bun:"created_at,scanonly"`
+}
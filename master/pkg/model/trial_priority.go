@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// TrialPrioritySetter is what set the priority of a trial.
+type TrialPrioritySetter string
+
+const (
+	// TrialPrioritySetBySearcher is a priority the searcher set, such as for a trial it promoted.
+	TrialPrioritySetBySearcher TrialPrioritySetter = "searcher"
+	// TrialPrioritySetByUser is a priority a user set through the API.
+	TrialPrioritySetByUser TrialPrioritySetter = "user"
+)
+
+// TrialPriority is the bun model of the priority of a trial among the trials of its experiment.
+// When slots are scarce, the allocations of trials with higher priorities are scheduled before the
+// other allocations of the experiment.
+type TrialPriority struct {
+	bun.BaseModel `bun:"table:trial_priorities"`
+	TrialID       int                 `bun:"trial_id,pk" json:"trial_id"`
+	Priority      int                 `bun:"priority" json:"priority"`
+	SetBy         TrialPrioritySetter `bun:"set_by" json:"set_by,omitempty"`
+	UpdatedAt     *time.Time          `bun:"updated_at" json:"updated_at,omitempty"`
+}
+
+// Proto converts a priority to its protobuf representation.
+func (p TrialPriority) Proto() *trialv1.TrialPriority {
+	pb := &trialv1.TrialPriority{
+		TrialId:  int32(p.TrialID),
+		Priority: int32(p.Priority),
+	}
+	if p.SetBy != "" {
+		setBy := string(p.SetBy)
+		pb.SetBy = &setBy
+	}
+	if p.UpdatedAt != nil {
+		pb.UpdatedAt = timestamppb.New(*p.UpdatedAt)
+	}
+	return pb
+}
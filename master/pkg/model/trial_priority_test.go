@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrialPriorityProto(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	pb := TrialPriority{
+		TrialID:   12,
+		Priority:  10,
+		SetBy:     TrialPrioritySetByUser,
+		UpdatedAt: &updatedAt,
+	}.Proto()
+	require.Equal(t, int32(12), pb.TrialId)
+	require.Equal(t, int32(10), pb.Priority)
+	require.Equal(t, "user", pb.GetSetBy())
+	require.Equal(t, updatedAt, pb.UpdatedAt.AsTime())
+
+	pb = TrialPriority{TrialID: 12}.Proto()
+	require.Equal(t, int32(0), pb.Priority)
+	require.Nil(t, pb.SetBy)
+	require.Nil(t, pb.UpdatedAt)
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
+)
+
+// TrialResize is the bun model of a change in the number of slots an elastic trial runs with.
+type TrialResize struct {
+	bun.BaseModel `bun:"table:trial_resizes"`
+	ID            int       `bun:"id,pk,autoincrement" json:"id"`
+	TrialID       int       `bun:"trial_id" json:"trial_id"`
+	FromSlots     int       `bun:"from_slots" json:"from_slots"`
+	ToSlots       int       `bun:"to_slots" json:"to_slots"`
+	Reason        string    `bun:"reason" json:"reason"`
+	CreatedAt     time.Time `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts a resize to its protobuf representation.
+func (r TrialResize) Proto() *trialv1.TrialResize {
+	return &trialv1.TrialResize{
+		Id:        int32(r.ID),
+		TrialId:   int32(r.TrialID),
+		FromSlots: int32(r.FromSlots),
+		ToSlots:   int32(r.ToSlots),
+		Reason:    r.Reason,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+	}
+}
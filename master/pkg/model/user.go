@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -252,8 +253,11 @@ func (e *ExternalSessions) Validate(claims *JWT) error {
 	return nil
 }
 
-func (e *ExternalSessions) fetchInvalidations(cert *tls.Certificate) {
+func (e *ExternalSessions) fetchInvalidations(
+	cert *tls.Certificate, proxy func(*http.Request) (*url.URL, error),
+) {
 	transport := cleanhttp.DefaultTransport()
+	transport.Proxy = proxy
 	transport.TLSClientConfig = &tls.Config{
 		Certificates: []tls.Certificate{*cert},
 		MinVersion:   tls.VersionTLS12,
@@ -299,12 +303,14 @@ func (e *ExternalSessions) fetchInvalidations(cert *tls.Certificate) {
 	}()
 }
 
-// StartInvalidationPoll polls for new invalidations every minute.
-func (e *ExternalSessions) StartInvalidationPoll(cert *tls.Certificate) {
+// StartInvalidationPoll polls for new invalidations every minute, through proxy.
+func (e *ExternalSessions) StartInvalidationPoll(
+	cert *tls.Certificate, proxy func(*http.Request) (*url.URL, error),
+) {
 	t := time.NewTicker(1 * time.Minute)
 	go func() {
 		for range t.C {
-			e.fetchInvalidations(cert)
+			e.fetchInvalidations(cert, proxy)
 		}
 	}()
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/uptrace/bun"
 
 	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/proto/pkg/userv1"
@@ -39,6 +40,7 @@ type Workspace struct {
 	DefaultComputePool       string                           `bun:"default_compute_pool"`
 	DefaultAuxPool           string                           `bun:"default_aux_pool"`
 	AutoCreatedNamespaceName *string                          `bun:"auto_created_namespace_name"`
+	NotebookWorkDirSync      bool                             `bun:"notebook_workdir_sync"`
 }
 
 // ToProto converts a bun model of a workspace to a proto object.
@@ -108,6 +110,8 @@ type WorkspacePin struct {
 	bun.BaseModel `bun:"table:workspace_pins"`
 	WorkspaceID   int    `bun:"workspace_id"`
 	UserID        UserID `bun:"user_id"`
+	// Position orders a user's pins. Pins inserted without one go after the user's other pins.
+	Position int `bun:"position,nullzero"`
 }
 
 // WorkspaceNamespace is the bun model of a workspace-namespace binding.
@@ -128,3 +132,46 @@ func (wn WorkspaceNamespace) ToProto() *workspacev1.WorkspaceNamespaceBinding {
 		AutoCreateNamespace: wn.AutoCreateNamespace,
 	}
 }
+
+// NotebookWorkDirSync is the bun model of a user's synced notebook working directory within a
+// workspace.
+type NotebookWorkDirSync struct {
+	bun.BaseModel  `bun:"table:notebook_workdir_syncs"`
+	ID             int        `bun:"id,pk,autoincrement" json:"id"`
+	WorkspaceID    int        `bun:"workspace_id" json:"workspace_id"`
+	UserID         UserID     `bun:"user_id" json:"user_id"`
+	StoragePrefix  string     `bun:"storage_prefix" json:"storage_prefix"`
+	LastTaskID     *TaskID    `bun:"last_task_id" json:"last_task_id"`
+	LastLaunchedAt time.Time  `bun:"last_launched_at" json:"last_launched_at"`
+	LastSyncedAt   *time.Time `bun:"last_synced_at" json:"last_synced_at"`
+}
+
+// Proto converts a notebook sync record to its protobuf representation.
+func (s NotebookWorkDirSync) Proto() *workspacev1.NotebookWorkDirSync {
+	out := &workspacev1.NotebookWorkDirSync{
+		Id:             int32(s.ID),
+		WorkspaceId:    int32(s.WorkspaceID),
+		UserId:         int32(s.UserID),
+		StoragePrefix:  s.StoragePrefix,
+		LastLaunchedAt: timestamppb.New(s.LastLaunchedAt),
+	}
+	if s.LastTaskID != nil {
+		out.LastTaskId = (*string)(s.LastTaskID)
+	}
+	if s.LastSyncedAt != nil {
+		out.LastSyncedAt = timestamppb.New(*s.LastSyncedAt)
+	}
+	return out
+}
+
+// WorkspaceStorageQuota is the bun model of the checkpoint storage quotas of a workspace.
+// Going over the soft quota alerts, and ExceededAt is set while usage is over it. Reaching the hard
+// quota blocks new checkpoints until OverrideUntil, if an administrator has set it.
+type WorkspaceStorageQuota struct {
+	bun.BaseModel  `bun:"table:workspace_storage_quotas"`
+	WorkspaceID    int        `bun:"workspace_id,pk" json:"workspace_id"`
+	SoftQuotaBytes *int64     `bun:"soft_quota_bytes" json:"soft_quota_bytes"`
+	HardQuotaBytes *int64     `bun:"hard_quota_bytes" json:"hard_quota_bytes"`
+	ExceededAt     *time.Time `bun:"exceeded_at" json:"exceeded_at"`
+	OverrideUntil  *time.Time `bun:"override_until" json:"override_until"`
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+// WorkspaceAPIKeyPrefix starts every workspace API key, so they are easy to tell apart from user
+// tokens and to find in leaked secrets.
+const WorkspaceAPIKeyPrefix = "detwk_"
+
+// workspaceAPIKeyDisplayLen is how much of a key is kept in the clear to identify it in listings.
+const workspaceAPIKeyDisplayLen = len(WorkspaceAPIKeyPrefix) + 6
+
+// WorkspaceAPIKey is the bun model of a read-only API key scoped to a workspace, for external
+// systems such as dashboards. Only a hash of the key is stored.
+type WorkspaceAPIKey struct {
+	bun.BaseModel `bun:"table:workspace_api_keys"`
+	ID            int        `bun:"id,pk,autoincrement" json:"id"`
+	WorkspaceID   int        `bun:"workspace_id" json:"workspace_id"`
+	Name          string     `bun:"name" json:"name"`
+	KeyPrefix     string     `bun:"key_prefix" json:"key_prefix"`
+	KeyHash       []byte     `bun:"key_hash" json:"-"`
+	CreatedBy     UserID     `bun:"created_by" json:"created_by"`
+	CreatedAt     time.Time  `bun:"created_at,scanonly" json:"created_at"`
+	ExpiresAt     *time.Time `bun:"expires_at" json:"expires_at"`
+	LastUsedAt    *time.Time `bun:"last_used_at" json:"last_used_at"`
+	RevokedAt     *time.Time `bun:"revoked_at" json:"revoked_at"`
+}
+
+// Active returns whether the key can be used at now.
+func (k WorkspaceAPIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// Proto converts a key to its protobuf representation, without its hash.
+func (k WorkspaceAPIKey) Proto() *workspacev1.WorkspaceAPIKey {
+	pb := &workspacev1.WorkspaceAPIKey{
+		Id:          int32(k.ID),
+		WorkspaceId: int32(k.WorkspaceID),
+		Name:        k.Name,
+		KeyPrefix:   k.KeyPrefix,
+		CreatedBy:   int32(k.CreatedBy),
+		CreatedAt:   timestamppb.New(k.CreatedAt),
+	}
+	if k.ExpiresAt != nil {
+		pb.ExpiresAt = timestamppb.New(*k.ExpiresAt)
+	}
+	if k.LastUsedAt != nil {
+		pb.LastUsedAt = timestamppb.New(*k.LastUsedAt)
+	}
+	if k.RevokedAt != nil {
+		pb.RevokedAt = timestamppb.New(*k.RevokedAt)
+	}
+	return pb
+}
+
+// NewWorkspaceAPIKey generates a random workspace API key, returning the key, which is only ever
+// shown to its creator, and the prefix and hash to store.
+func NewWorkspaceAPIKey() (key, prefix string, hash []byte, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", nil, fmt.Errorf("generating workspace API key: %w", err)
+	}
+	key = WorkspaceAPIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:workspaceAPIKeyDisplayLen], HashWorkspaceAPIKey(key), nil
+}
+
+// HashWorkspaceAPIKey returns the hash a workspace API key is stored and looked up by. Keys are
+// long and random, so a fast hash is enough.
+func HashWorkspaceAPIKey(key string) []byte {
+	h := sha256.Sum256([]byte(key))
+	return h[:]
+}
+
+// IsWorkspaceAPIKey returns whether a bearer token is a workspace API key rather than a user
+// token.
+func IsWorkspaceAPIKey(token string) bool {
+	return strings.HasPrefix(token, WorkspaceAPIKeyPrefix)
+}
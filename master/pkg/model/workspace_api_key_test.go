@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWorkspaceAPIKey(t *testing.T) {
+	key, prefix, hash, err := NewWorkspaceAPIKey()
+	require.NoError(t, err)
+	require.True(t, IsWorkspaceAPIKey(key))
+	require.False(t, IsWorkspaceAPIKey("v2.public.token"))
+	require.True(t, len(key) > len(prefix))
+	require.Equal(t, key[:len(prefix)], prefix)
+	require.Equal(t, HashWorkspaceAPIKey(key), hash)
+
+	other, _, otherHash, err := NewWorkspaceAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+	require.NotEqual(t, hash, otherHash)
+}
+
+func TestWorkspaceAPIKeyActive(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	require.True(t, WorkspaceAPIKey{}.Active(now))
+	require.True(t, WorkspaceAPIKey{ExpiresAt: &future}.Active(now))
+	require.False(t, WorkspaceAPIKey{ExpiresAt: &past}.Active(now))
+	require.False(t, WorkspaceAPIKey{RevokedAt: &past}.Active(now))
+}
+
+func TestWorkspaceAPIKeyProto(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	pb := WorkspaceAPIKey{ID: 3, WorkspaceID: 2, Name: "grafana", RevokedAt: &past}.Proto()
+	require.Equal(t, int32(3), pb.Id)
+	require.Equal(t, int32(2), pb.WorkspaceId)
+	require.Equal(t, "grafana", pb.Name)
+	require.Nil(t, pb.ExpiresAt)
+	require.Equal(t, past.Unix(), pb.RevokedAt.AsTime().Unix())
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+// WorkspaceEnvVarSet is the bun model of a named set of environment variables of a workspace,
+// such as proxy settings or dataset endpoints, that task configs in the workspace refer to by name
+// in environment.environment_variable_sets. They aren't secret.
+type WorkspaceEnvVarSet struct {
+	bun.BaseModel `bun:"table:workspace_env_var_sets"`
+	ID            int               `bun:"id,pk,autoincrement" json:"id"`
+	WorkspaceID   int               `bun:"workspace_id" json:"workspace_id"`
+	Name          string            `bun:"name" json:"name"`
+	Variables     map[string]string `bun:"variables,type:jsonb" json:"variables"`
+	Description   string            `bun:"description" json:"description"`
+	UpdatedBy     UserID            `bun:"updated_by" json:"updated_by"`
+	UpdatedAt     time.Time         `bun:"updated_at" json:"updated_at"`
+}
+
+// Proto converts the set to its protobuf representation.
+func (s WorkspaceEnvVarSet) Proto() *workspacev1.EnvVarSet {
+	variables := s.Variables
+	if variables == nil {
+		variables = map[string]string{}
+	}
+	return &workspacev1.EnvVarSet{
+		Id:          int32(s.ID),
+		WorkspaceId: int32(s.WorkspaceID),
+		Name:        s.Name,
+		Variables:   variables,
+		Description: s.Description,
+		UpdatedBy:   int32(s.UpdatedBy),
+		UpdatedAt:   timestamppb.New(s.UpdatedAt),
+	}
+}
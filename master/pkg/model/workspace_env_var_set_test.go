@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaceEnvVarSetProto(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	pb := WorkspaceEnvVarSet{
+		ID:          2,
+		WorkspaceID: 1,
+		Name:        "proxy",
+		Variables:   map[string]string{"HTTPS_PROXY": "http://proxy:3128"},
+		Description: "Proxy settings",
+		UpdatedBy:   3,
+		UpdatedAt:   updatedAt,
+	}.Proto()
+	require.Equal(t, int32(2), pb.Id)
+	require.Equal(t, int32(1), pb.WorkspaceId)
+	require.Equal(t, "proxy", pb.Name)
+	require.Equal(t, map[string]string{"HTTPS_PROXY": "http://proxy:3128"}, pb.Variables)
+	require.Equal(t, "Proxy settings", pb.Description)
+	require.Equal(t, int32(3), pb.UpdatedBy)
+	require.Equal(t, updatedAt, pb.UpdatedAt.AsTime())
+
+	require.NotNil(t, WorkspaceEnvVarSet{}.Proto().Variables)
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+// WorkspaceEventKind is the kind of notable event recorded in a workspace's activity feed.
+type WorkspaceEventKind string
+
+const (
+	// WorkspaceEventExperimentCreated is recorded when an experiment is created.
+	WorkspaceEventExperimentCreated WorkspaceEventKind = "experiment_created"
+	// WorkspaceEventExperimentCompleted is recorded when an experiment completes.
+	WorkspaceEventExperimentCompleted WorkspaceEventKind = "experiment_completed"
+	// WorkspaceEventExperimentFailed is recorded when an experiment ends in an error.
+	WorkspaceEventExperimentFailed WorkspaceEventKind = "experiment_failed"
+	// WorkspaceEventModelRegistered is recorded when a model is added to the registry.
+	WorkspaceEventModelRegistered WorkspaceEventKind = "model_registered"
+	// WorkspaceEventMemberAdded is recorded when a user or group is assigned a role in a workspace.
+	WorkspaceEventMemberAdded WorkspaceEventKind = "member_added"
+)
+
+// Valid returns whether k is a known kind.
+func (k WorkspaceEventKind) Valid() bool {
+	switch k {
+	case WorkspaceEventExperimentCreated, WorkspaceEventExperimentCompleted,
+		WorkspaceEventExperimentFailed, WorkspaceEventModelRegistered, WorkspaceEventMemberAdded:
+		return true
+	default:
+		return false
+	}
+}
+
+// WorkspaceEventKindFromProto converts a protobuf kind of event. Unknown kinds convert to invalid
+// kinds.
+func WorkspaceEventKindFromProto(k workspacev1.WorkspaceEventKind) WorkspaceEventKind {
+	switch k {
+	case workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_EXPERIMENT_CREATED:
+		return WorkspaceEventExperimentCreated
+	case workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_EXPERIMENT_COMPLETED:
+		return WorkspaceEventExperimentCompleted
+	case workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_EXPERIMENT_FAILED:
+		return WorkspaceEventExperimentFailed
+	case workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_MODEL_REGISTERED:
+		return WorkspaceEventModelRegistered
+	case workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_MEMBER_ADDED:
+		return WorkspaceEventMemberAdded
+	default:
+		return WorkspaceEventKind(k.String())
+	}
+}
+
+// Proto converts a kind of event to its protobuf representation.
+func (k WorkspaceEventKind) Proto() workspacev1.WorkspaceEventKind {
+	switch k {
+	case WorkspaceEventExperimentCreated:
+		return workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_EXPERIMENT_CREATED
+	case WorkspaceEventExperimentCompleted:
+		return workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_EXPERIMENT_COMPLETED
+	case WorkspaceEventExperimentFailed:
+		return workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_EXPERIMENT_FAILED
+	case WorkspaceEventModelRegistered:
+		return workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_MODEL_REGISTERED
+	case WorkspaceEventMemberAdded:
+		return workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_MEMBER_ADDED
+	default:
+		return workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_UNSPECIFIED
+	}
+}
+
+// WorkspaceEvent is the bun model of an entry in a workspace's activity feed.
+type WorkspaceEvent struct {
+	bun.BaseModel `bun:"table:workspace_events"`
+	ID            int64              `bun:"id,pk,autoincrement" json:"id"`
+	WorkspaceID   int                `bun:"workspace_id" json:"workspace_id"`
+	Kind          WorkspaceEventKind `bun:"kind" json:"kind"`
+	ActorID       *UserID            `bun:"actor_id" json:"actor_id"`
+	ActorUsername *string            `bun:"actor_username,scanonly" json:"actor_username"`
+	ExperimentID  *int               `bun:"experiment_id" json:"experiment_id,omitempty"`
+	ModelID       *int               `bun:"model_id" json:"model_id,omitempty"`
+	// Details describe the event, like the name of the experiment or the role a member was given.
+	Details   map[string]any `bun:"details,type:jsonb" json:"details"`
+	CreatedAt time.Time      `bun:"created_at,scanonly" json:"created_at"`
+}
+
+// Proto converts an event to its protobuf representation.
+func (e WorkspaceEvent) Proto() *workspacev1.WorkspaceEvent {
+	pb := &workspacev1.WorkspaceEvent{
+		Id:            e.ID,
+		WorkspaceId:   int32(e.WorkspaceID),
+		Kind:          e.Kind.Proto(),
+		ActorUsername: e.ActorUsername,
+		Details:       protoutils.ToStruct(e.Details),
+		CreatedAt:     timestamppb.New(e.CreatedAt),
+	}
+	if e.ActorID != nil {
+		pb.ActorId = ptrs.Ptr(int32(*e.ActorID))
+	}
+	if e.ExperimentID != nil {
+		pb.ExperimentId = ptrs.Ptr(int32(*e.ExperimentID))
+	}
+	if e.ModelID != nil {
+		pb.ModelId = ptrs.Ptr(int32(*e.ModelID))
+	}
+	return pb
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/workspacev1"
+)
+
+func TestWorkspaceEventKindProto(t *testing.T) {
+	for _, k := range []WorkspaceEventKind{
+		WorkspaceEventExperimentCreated, WorkspaceEventExperimentCompleted,
+		WorkspaceEventExperimentFailed, WorkspaceEventModelRegistered, WorkspaceEventMemberAdded,
+	} {
+		require.Equal(t, k, WorkspaceEventKindFromProto(k.Proto()))
+	}
+	require.False(t, WorkspaceEventKindFromProto(
+		workspacev1.WorkspaceEventKind_WORKSPACE_EVENT_KIND_UNSPECIFIED).Valid())
+}
+
+func TestWorkspaceEventProto(t *testing.T) {
+	experimentID := 3
+	pb := WorkspaceEvent{
+		ID:           2,
+		WorkspaceID:  1,
+		Kind:         WorkspaceEventExperimentCreated,
+		ExperimentID: &experimentID,
+		Details:      map[string]any{"name": "exp"},
+	}.Proto()
+	require.Equal(t, int32(3), pb.GetExperimentId())
+	require.Nil(t, pb.ActorId)
+	require.Nil(t, pb.ModelId)
+	require.Equal(t, "exp", pb.Details.AsMap()["name"])
+}
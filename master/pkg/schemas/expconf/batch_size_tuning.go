@@ -0,0 +1,12 @@
+package expconf
+
+// BatchSizeTuningConfigV0 configures the search for the largest batch size that fits in the
+// memory of the slots of a trial, which the master runs before an experiment starts its trials.
+//
+//go:generate ../gen.sh
+type BatchSizeTuningConfigV0 struct {
+	RawHyperparameter *string `json:"hyperparameter"`
+	RawMinBatchSize   *int    `json:"min_batch_size"`
+	RawMaxBatchSize   int     `json:"max_batch_size"`
+	RawProbeSteps     *int    `json:"probe_steps"`
+}
@@ -0,0 +1,22 @@
+package expconf
+
+// CompletionHookConfigV0 configures a command the master launches after the experiment ends, for
+// post-processing such as packaging the best checkpoint or writing a report.
+//
+//go:generate ../gen.sh
+type CompletionHookConfigV0 struct {
+	RawCommand []string `json:"command"`
+	RawStates  []string `json:"states"`
+	RawSlots   *int     `json:"slots"`
+}
+
+// RunsOn returns whether the hook runs when the experiment ends in the given terminal state. The
+// schema ensures the states are names of terminal states.
+func (c CompletionHookConfigV0) RunsOn(state string) bool {
+	for _, s := range c.States() {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
@@ -55,6 +55,16 @@ func (p PodSpec) WithDefaults() PodSpec {
 	return PodSpec(*pod.DeepCopy())
 }
 
+// Image pinning modes of an environment.
+const (
+	// PinImages resolves image tags to digests, keeping the images experiments already pinned.
+	PinImages = "pin"
+	// RepinImages resolves the tags of images experiments already pinned to digests again.
+	RepinImages = "repin"
+	// NoImagePinning runs images by their tags.
+	NoImagePinning = "off"
+)
+
 // EnvironmentConfigV0 configures the environment of a Determined command or experiment.
 //
 //go:generate ../gen.sh --import github.com/docker/docker/api/types/registry
@@ -62,11 +72,17 @@ type EnvironmentConfigV0 struct {
 	RawImage                *EnvironmentImageMapV0     `json:"image"`
 	RawEnvironmentVariables *EnvironmentVariablesMapV0 `json:"environment_variables"`
 	RawProxyPorts           *ProxyPortsConfigV0        `json:"proxy_ports"`
+	// RawEnvironmentVariableSets are the names of sets of environment variables of the task's
+	// workspace, which are set in the task's container when it starts.
+	RawEnvironmentVariableSets []string `json:"environment_variable_sets"`
 
 	RawPorts          map[string]int       `json:"ports"`
 	RawRegistryAuth   *registry.AuthConfig `json:"registry_auth"`
 	RawForcePullImage *bool                `json:"force_pull_image"`
-	RawPodSpec        *PodSpec             `json:"pod_spec"`
+	// RawImagePinning is whether experiments resolve the tags of their images to digests when they
+	// are created, so that continuing or forking them runs the same images.
+	RawImagePinning *string  `json:"image_pinning"`
+	RawPodSpec      *PodSpec `json:"pod_spec"`
 
 	RawAddCapabilities  []string `json:"add_capabilities"`
 	RawDropCapabilities []string `json:"drop_capabilities"`
@@ -18,38 +18,50 @@ import (
 //
 //go:generate ../gen.sh
 type ExperimentConfigV0 struct {
-	RawBindMounts               BindMountsConfigV0          `json:"bind_mounts"`
-	RawCheckpointPolicy         *string                     `json:"checkpoint_policy"`
-	RawCheckpointStorage        *CheckpointStorageConfigV0  `json:"checkpoint_storage"`
-	RawData                     map[string]interface{}      `json:"data"`
-	RawIntegrations             *IntegrationsConfigV0       `json:"integrations"`
-	RawDebug                    *bool                       `json:"debug"`
-	RawDescription              *string                     `json:"description"`
-	RawEntrypoint               *EntrypointV0               `json:"entrypoint"`
-	RawEnvironment              *EnvironmentConfigV0        `json:"environment"`
-	RawHyperparameters          HyperparametersV0           `json:"hyperparameters"`
-	RawLabels                   LabelsV0                    `json:"labels"`
-	RawLogPolicies              LogPoliciesConfigV0         `json:"log_policies"`
-	RawRetentionPolicy          *RetentionPolicyConfigV0    `json:"retention_policy,omitempty"`
-	RawMaxRestarts              *int                        `json:"max_restarts"`
-	RawMinCheckpointPeriod      *LengthV0                   `json:"min_checkpoint_period"`
-	RawMinValidationPeriod      *LengthV0                   `json:"min_validation_period"`
-	RawName                     Name                        `json:"name"`
-	RawOptimizations            *OptimizationsConfigV0      `json:"optimizations"`
-	RawPerformInitialValidation *bool                       `json:"perform_initial_validation"`
-	RawProfiling                *ProfilingConfigV0          `json:"profiling"`
-	RawProject                  *string                     `json:"project"`
-	RawRecordsPerEpoch          *int                        `json:"records_per_epoch"`
-	RawReproducibility          *ReproducibilityConfigV0    `json:"reproducibility"`
-	RawResources                *ResourcesConfigV0          `json:"resources"`
-	RawSchedulingUnit           *int                        `json:"scheduling_unit"`
-	RawSearcher                 *SearcherConfigV0           `json:"searcher"`
-	RawSecurity                 *SecurityConfigV0           `json:"security,omitempty"`
-	RawTensorboardStorage       *TensorboardStorageConfigV0 `json:"tensorboard_storage,omitempty"`
-	RawWorkspace                *string                     `json:"workspace"`
-	RawSlurmConfig              *SlurmConfigV0              `json:"slurm,omitempty"`
-	RawPbsConfig                *PbsConfigV0                `json:"pbs,omitempty"`
-	RawPreemptionTimeout        *int                        `json:"preemption_timeout"`
+	RawBatchSizeTuning            *BatchSizeTuningConfigV0    `json:"batch_size_tuning"`
+	RawBindMounts                 BindMountsConfigV0          `json:"bind_mounts"`
+	RawCheckpointPolicy           *string                     `json:"checkpoint_policy"`
+	RawCheckpointStorage          *CheckpointStorageConfigV0  `json:"checkpoint_storage"`
+	RawCompletionHook             *CompletionHookConfigV0     `json:"completion_hook"`
+	RawData                       map[string]interface{}      `json:"data"`
+	RawIntegrations               *IntegrationsConfigV0       `json:"integrations"`
+	RawDebug                      *bool                       `json:"debug"`
+	RawDescription                *string                     `json:"description"`
+	RawEntrypoint                 *EntrypointV0               `json:"entrypoint"`
+	RawEnvironment                *EnvironmentConfigV0        `json:"environment"`
+	RawHarnessFree                *bool                       `json:"harness_free"`
+	RawHyperparameters            HyperparametersV0           `json:"hyperparameters"`
+	RawLabels                     LabelsV0                    `json:"labels"`
+	RawLaunchLayer                *LaunchLayerConfigV0        `json:"launch_layer"`
+	RawLiveness                   *LivenessConfigV0           `json:"liveness"`
+	RawLogMetrics                 []LogMetricRuleV0           `json:"log_metrics"`
+	RawLogPolicies                LogPoliciesConfigV0         `json:"log_policies"`
+	RawRetentionPolicy            *RetentionPolicyConfigV0    `json:"retention_policy,omitempty"`
+	RawMaxDuration                *int                        `json:"max_duration"`
+	RawMaxRestarts                *int                        `json:"max_restarts"`
+	RawMaxInfraRestarts           *int                        `json:"max_infra_restarts"`
+	RawMaxStepsWithoutImprovement *int                        `json:"max_steps_without_improvement"`
+	RawMetadata                   map[string]interface{}      `json:"metadata"`
+	RawMetricMetadata             MetricMetadataConfigV0      `json:"metric_metadata"`
+	RawMinCheckpointPeriod        *LengthV0                   `json:"min_checkpoint_period"`
+	RawMinValidationPeriod        *LengthV0                   `json:"min_validation_period"`
+	RawName                       Name                        `json:"name"`
+	RawOptimizations              *OptimizationsConfigV0      `json:"optimizations"`
+	RawPerformInitialValidation   *bool                       `json:"perform_initial_validation"`
+	RawProfiling                  *ProfilingConfigV0          `json:"profiling"`
+	RawProject                    *string                     `json:"project"`
+	RawRecordsPerEpoch            *int                        `json:"records_per_epoch"`
+	RawReproducibility            *ReproducibilityConfigV0    `json:"reproducibility"`
+	RawResources                  *ResourcesConfigV0          `json:"resources"`
+	RawSchedulingUnit             *int                        `json:"scheduling_unit"`
+	RawSearcher                   *SearcherConfigV0           `json:"searcher"`
+	RawSecurity                   *SecurityConfigV0           `json:"security,omitempty"`
+	RawStragglerDetection         *StragglerDetectionConfigV0 `json:"straggler_detection"`
+	RawTensorboardStorage         *TensorboardStorageConfigV0 `json:"tensorboard_storage,omitempty"`
+	RawWorkspace                  *string                     `json:"workspace"`
+	RawSlurmConfig                *SlurmConfigV0              `json:"slurm,omitempty"`
+	RawPbsConfig                  *PbsConfigV0                `json:"pbs,omitempty"`
+	RawPreemptionTimeout          *int                        `json:"preemption_timeout"`
 }
 
 // Value implements the driver.Valuer interface.
@@ -204,16 +216,49 @@ type ResourcesConfigV0 struct {
 	// Slots is used by commands while trials use SlotsPerTrial.
 	RawSlots *int `json:"slots,omitempty"`
 
-	RawMaxSlots       *int     `json:"max_slots"`
-	RawSlotsPerTrial  *int     `json:"slots_per_trial"`
-	RawWeight         *float64 `json:"weight"`
-	RawNativeParallel *bool    `json:"native_parallel,omitempty"`
-	RawShmSize        *int     `json:"shm_size"`
-	RawResourcePool   *string  `json:"resource_pool"`
-	RawPriority       *int     `json:"priority"`
-	RawIsSingleNode   *bool    `json:"is_single_node"`
+	RawMaxSlots        *int     `json:"max_slots"`
+	RawSlotsPerTrial   *int     `json:"slots_per_trial"`
+	RawWeight          *float64 `json:"weight"`
+	RawNativeParallel  *bool    `json:"native_parallel,omitempty"`
+	RawShmSize         *int     `json:"shm_size"`
+	RawResourcePool    *string  `json:"resource_pool"`
+	RawPriority        *int     `json:"priority"`
+	RawIsSingleNode    *bool    `json:"is_single_node"`
+	RawMinGPUMemoryMiB *int     `json:"min_gpu_memory_mib"`
+
+	RawDevices          DevicesConfigV0          `json:"devices"`
+	RawElastic          *ElasticConfigV0         `json:"elastic,omitempty"`
+	RawAgentConstraints AgentConstraintsConfigV0 `json:"agent_constraints"`
+	RawScratch          *ScratchConfigV0         `json:"scratch,omitempty"`
+}
+
+// Scratch volume backings.
+const (
+	// ScratchBackingLocal is a directory on the local disk of the node, removed when the task's
+	// container exits.
+	ScratchBackingLocal = "local"
+	// ScratchBackingEphemeral is a Kubernetes generic ephemeral volume, provisioned from a storage
+	// class and deleted along with the pod.
+	ScratchBackingEphemeral = "ephemeral"
+)
+
+// ScratchConfigV0 configures the scratch volume each container of a trial gets for the lifetime of
+// its allocation.
+//
+//go:generate ../gen.sh
+type ScratchConfigV0 struct {
+	RawSizeGiB       int     `json:"size_gib"`
+	RawBacking       *string `json:"backing"`
+	RawContainerPath *string `json:"container_path"`
+	RawStorageClass  *string `json:"storage_class"`
+}
 
-	RawDevices DevicesConfigV0 `json:"devices"`
+// ElasticConfigV0 configures a trial whose slot count may change while it runs.
+//
+//go:generate ../gen.sh
+type ElasticConfigV0 struct {
+	RawMinSlots int `json:"min_slots"`
+	RawMaxSlots int `json:"max_slots"`
 }
 
 // OptimizationsConfigV0 is a legacy config value.
@@ -286,6 +331,20 @@ type BindMountV0 struct {
 	RawPropagation   *string `json:"propagation"`
 }
 
+// AgentConstraintV0 restricts trials to agents whose label is, or is not, one of a set of values.
+//
+//go:generate ../gen.sh
+type AgentConstraintV0 struct {
+	RawKey      string   `json:"key"`
+	RawOperator string   `json:"operator"`
+	RawValues   []string `json:"values"`
+}
+
+// AgentConstraintsConfigV0 is the configuration for agent constraints.
+//
+//go:generate ../gen.sh
+type AgentConstraintsConfigV0 []AgentConstraintV0
+
 // DevicesConfigV0 is the configuration for devices.
 //
 //go:generate ../gen.sh
@@ -116,3 +116,60 @@ func TestName(t *testing.T) {
 
 	assert.DeepEqual(t, newConfig.Name().String(), "my_name")
 }
+
+func TestLaunchLayerCommand(t *testing.T) {
+	script := EntrypointV0{RawEntrypoint: []interface{}{"python3", "train.py"}}
+	cases := []struct {
+		name       string
+		layer      LaunchLayerConfigV0
+		entrypoint EntrypointV0
+		expected   []string
+	}{
+		{
+			name:       "torch_distributed without args",
+			layer:      LaunchLayerConfigV0{RawType: TorchDistributedLaunchLayer},
+			entrypoint: script,
+			expected: []string{
+				"python3", "-m", "determined.launch.torch_distributed", "python3", "train.py",
+			},
+		},
+		{
+			name: "torch_distributed with templated args",
+			layer: LaunchLayerConfigV0{
+				RawType: TorchDistributedLaunchLayer,
+				RawArgs: []string{"--nproc_per_node={slots_per_node}", "--max_restarts={slots_per_trial}"},
+			},
+			entrypoint: EntrypointV0{RawEntrypoint: "python3 train.py --epochs 3"},
+			expected: []string{
+				"python3", "-m", "determined.launch.torch_distributed",
+				"--nproc_per_node={slots_per_node}", "--max_restarts=8", "--",
+				"sh", "-c", "python3 train.py --epochs 3",
+			},
+		},
+		{
+			name:       "deepspeed with a legacy trial class",
+			layer:      LaunchLayerConfigV0{RawType: DeepSpeedLaunchLayer},
+			entrypoint: EntrypointV0{RawEntrypoint: "model_def:MyTrial"},
+			expected: []string{
+				"python3", "-m", "determined.launch.deepspeed", "--trial", "model_def:MyTrial",
+			},
+		},
+		{
+			name: "horovod over mpi",
+			layer: LaunchLayerConfigV0{
+				RawType: HorovodMPILaunchLayer,
+				RawArgs: []string{"--mpi-args=--bind-to none"},
+			},
+			entrypoint: script,
+			expected: []string{
+				"python3", "-m", "determined.launch.horovod",
+				"--mpi", "--mpi-args=--bind-to none", "--", "python3", "train.py",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.layer.Command(tc.entrypoint, 8))
+		})
+	}
+}
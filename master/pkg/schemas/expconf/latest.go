@@ -4,33 +4,44 @@ package expconf
 
 type (
 	AdaptiveASHAConfig        = AdaptiveASHAConfigV0
+	AgentConstraint           = AgentConstraintV0
+	AgentConstraintsConfig    = AgentConstraintsConfigV0
 	AsyncHalvingConfig        = AsyncHalvingConfigV0
 	AzureConfig               = AzureConfigV0
+	BatchSizeTuningConfig     = BatchSizeTuningConfigV0
 	BindMount                 = BindMountV0
 	BindMountsConfig          = BindMountsConfigV0
 	CategoricalHyperparameter = CategoricalHyperparameterV0
 	CheckpointStorageConfig   = CheckpointStorageConfigV0
+	CompletionHookConfig      = CompletionHookConfigV0
 	ConstHyperparameter       = ConstHyperparameterV0
 	Device                    = DeviceV0
 	DevicesConfig             = DevicesConfigV0
 	DirectoryConfig           = DirectoryConfigV0
 	DoubleHyperparameter      = DoubleHyperparameterV0
 	Entrypoint                = EntrypointV0
+	ElasticConfig             = ElasticConfigV0
 	EnvironmentConfig         = EnvironmentConfigV0
 	EnvironmentImageMap       = EnvironmentImageMapV0
 	EnvironmentVariablesMap   = EnvironmentVariablesMapV0
 	ExperimentConfig          = ExperimentConfigV0
 	GCSConfig                 = GCSConfigV0
 	GridConfig                = GridConfigV0
+	HDFSConfig                = HDFSConfigV0
 	Hyperparameter            = HyperparameterV0
 	Hyperparameters           = HyperparametersV0
 	IntHyperparameter         = IntHyperparameterV0
 	Labels                    = LabelsV0
+	LaunchLayerConfig         = LaunchLayerConfigV0
 	Length                    = LengthV0
+	LivenessConfig            = LivenessConfigV0
+	LogMetricRule             = LogMetricRuleV0
 	LogPoliciesConfig         = LogPoliciesConfigV0
 	LogPolicy                 = LogPolicyV0
 	LogAction                 = LogActionV0
 	LogHyperparameter         = LogHyperparameterV0
+	MetricMetadata            = MetricMetadataV0
+	MetricMetadataConfig      = MetricMetadataConfigV0
 	OptimizationsConfig       = OptimizationsConfigV0
 	PbsConfig                 = PbsConfigV0
 	ProfilingConfig           = ProfilingConfigV0
@@ -41,10 +52,13 @@ type (
 	ResourcesConfig           = ResourcesConfigV0
 	RetentionPolicy           = RetentionPolicyConfigV0
 	S3Config                  = S3ConfigV0
+	ScratchConfig             = ScratchConfigV0
 	SearcherConfig            = SearcherConfigV0
+	SFTPConfig                = SFTPConfigV0
 	SharedFSConfig            = SharedFSConfigV0
 	SingleConfig              = SingleConfigV0
 	SlurmConfig               = SlurmConfigV0
+	StragglerDetectionConfig  = StragglerDetectionConfigV0
 	IntegrationsConfig        = IntegrationsConfigV0
 	PachydermConfig           = PachydermConfigV0
 	PachydermPachdConfig      = PachydermPachdConfigV0
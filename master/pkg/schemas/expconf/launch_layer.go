@@ -0,0 +1,83 @@
+package expconf
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Launch layers a trial's entrypoint can be launched under.
+const (
+	TorchDistributedLaunchLayer = "torch_distributed"
+	DeepSpeedLaunchLayer        = "deepspeed"
+	HorovodLaunchLayer          = "horovod"
+	HorovodMPILaunchLayer       = "horovod_mpi"
+	HorovodGlooLaunchLayer      = "horovod_gloo"
+)
+
+// SlotsPerTrialPlaceholder is the placeholder of launch layer arguments the master fills in, with
+// the slots the trial is allocated with, which differ from slots_per_trial once an elastic trial
+// is resized. The other placeholders, {num_nodes}, {slots_per_node}, {node_rank} and {chief_ip}, depend on where
+// the containers of a trial run, so the harness fills them in.
+const SlotsPerTrialPlaceholder = "{slots_per_trial}"
+
+// launchLayerModules are the harness modules that implement the launch layers.
+var launchLayerModules = map[string]string{
+	TorchDistributedLaunchLayer: "determined.launch.torch_distributed",
+	DeepSpeedLaunchLayer:        "determined.launch.deepspeed",
+	HorovodLaunchLayer:          "determined.launch.horovod",
+	HorovodMPILaunchLayer:       "determined.launch.horovod",
+	HorovodGlooLaunchLayer:      "determined.launch.horovod",
+}
+
+// legacyTrialClassRegex matches legacy entrypoints of the form module.submodule:ClassName, like
+// the harness does.
+var legacyTrialClassRegex = regexp.MustCompile(`^[a-zA-Z0-9_.]+:[a-zA-Z0-9_]+$`)
+
+// LaunchLayerConfigV0 configures the launcher that starts the workers of a distributed trial
+// around its entrypoint.
+//
+//go:generate ../gen.sh
+type LaunchLayerConfigV0 struct {
+	RawType string   `json:"type"`
+	RawArgs []string `json:"args"`
+}
+
+// Command returns the command that launches entrypoint under the launch layer, with the
+// placeholders of its arguments that the master knows filled in for a trial allocated slots
+// slots. The schema ensures the type of the launch layer and the entrypoint are valid.
+func (l LaunchLayerConfigV0) Command(entrypoint EntrypointV0, slots int) []string {
+	var script []string
+	switch e := entrypoint.RawEntrypoint.(type) {
+	case string:
+		if legacyTrialClassRegex.MatchString(e) {
+			script = []string{"--trial", e}
+		} else {
+			script = []string{"sh", "-c", e}
+		}
+	case []interface{}:
+		for _, arg := range e {
+			script = append(script, fmt.Sprint(arg))
+		}
+	}
+
+	var overrides []string
+	switch l.RawType {
+	case HorovodMPILaunchLayer:
+		overrides = append(overrides, "--mpi")
+	case HorovodGlooLaunchLayer:
+		overrides = append(overrides, "--gloo")
+	}
+	for _, arg := range l.RawArgs {
+		overrides = append(overrides,
+			strings.ReplaceAll(arg, SlotsPerTrialPlaceholder, strconv.Itoa(slots)))
+	}
+
+	cmd := []string{"python3", "-m", launchLayerModules[l.RawType]}
+	if len(overrides) > 0 {
+		// The launchers pass the arguments before a "--" through to the underlying launcher.
+		cmd = append(append(cmd, overrides...), "--")
+	}
+	return append(cmd, script...)
+}
@@ -114,11 +114,13 @@ func TestLegacyConfig(t *testing.T) {
 						RawCUDA: ptrs.Ptr("determinedai/environments:cuda-10.0-pytorch-1.4-tf-1.15-gpu-aaa3750"),
 						RawROCM: ptrs.Ptr("determinedai/environments:rocm-5.0-pytorch-1.10-tf-2.7-rocm-622d512"),
 					},
-					RawPorts:            map[string]int{},
-					RawProxyPorts:       &ProxyPortsConfigV0{},
-					RawForcePullImage:   ptrs.Ptr(false),
-					RawAddCapabilities:  []string{},
-					RawDropCapabilities: []string{},
+					RawPorts:                   map[string]int{},
+					RawProxyPorts:              &ProxyPortsConfigV0{},
+					RawForcePullImage:          ptrs.Ptr(false),
+					RawImagePinning:            ptrs.Ptr("pin"),
+					RawAddCapabilities:         []string{},
+					RawDropCapabilities:        []string{},
+					RawEnvironmentVariableSets: []string{},
 				},
 				Hyperparameters: Hyperparameters{
 					"global_batch_size": {
@@ -282,11 +284,13 @@ func TestLegacyConfig(t *testing.T) {
 						},
 						Status: k8sV1.PodStatus{},
 					},
-					RawPorts:            map[string]int{},
-					RawProxyPorts:       &ProxyPortsConfigV0{},
-					RawForcePullImage:   ptrs.Ptr(false),
-					RawAddCapabilities:  []string{},
-					RawDropCapabilities: []string{},
+					RawPorts:                   map[string]int{},
+					RawProxyPorts:              &ProxyPortsConfigV0{},
+					RawForcePullImage:          ptrs.Ptr(false),
+					RawImagePinning:            ptrs.Ptr("pin"),
+					RawAddCapabilities:         []string{},
+					RawDropCapabilities:        []string{},
+					RawEnvironmentVariableSets: []string{},
 				},
 				Hyperparameters: Hyperparameters{
 					"global_batch_size": {
@@ -417,11 +421,13 @@ func TestLegacyConfig(t *testing.T) {
 						RawCUDA: ptrs.Ptr("determinedai/environments:cuda-10.2-pytorch-1.7-tf-1.15-gpu-6eceaca"),
 						RawROCM: ptrs.Ptr("determinedai/environments:rocm-5.0-pytorch-1.10-tf-2.7-rocm-622d512"),
 					},
-					RawPorts:            map[string]int{},
-					RawProxyPorts:       &ProxyPortsConfigV0{},
-					RawForcePullImage:   ptrs.Ptr(false),
-					RawAddCapabilities:  []string{},
-					RawDropCapabilities: []string{},
+					RawPorts:                   map[string]int{},
+					RawProxyPorts:              &ProxyPortsConfigV0{},
+					RawForcePullImage:          ptrs.Ptr(false),
+					RawImagePinning:            ptrs.Ptr("pin"),
+					RawAddCapabilities:         []string{},
+					RawDropCapabilities:        []string{},
+					RawEnvironmentVariableSets: []string{},
 				},
 				Hyperparameters: Hyperparameters{
 					"global_batch_size": {
@@ -499,11 +505,13 @@ func TestLegacyConfig(t *testing.T) {
 						RawCUDA: ptrs.Ptr("determinedai/environments:cuda-10.2-pytorch-1.7-tf-1.15-gpu-6eceaca"),
 						RawROCM: ptrs.Ptr("determinedai/environments:rocm-5.0-pytorch-1.10-tf-2.7-rocm-622d512"),
 					},
-					RawAddCapabilities:  []string{},
-					RawDropCapabilities: []string{},
-					RawForcePullImage:   ptrs.Ptr(false),
-					RawPorts:            map[string]int{},
-					RawProxyPorts:       &ProxyPortsConfigV0{},
+					RawAddCapabilities:         []string{},
+					RawDropCapabilities:        []string{},
+					RawEnvironmentVariableSets: []string{},
+					RawForcePullImage:          ptrs.Ptr(false),
+					RawImagePinning:            ptrs.Ptr("pin"),
+					RawPorts:                   map[string]int{},
+					RawProxyPorts:              &ProxyPortsConfigV0{},
 				},
 			},
 		},
@@ -0,0 +1,12 @@
+package expconf
+
+// LivenessConfigV0 configures detection of trials that stopped making progress.
+//
+//go:generate ../gen.sh
+type LivenessConfigV0 struct {
+	RawEnabled            *bool `json:"enabled"`
+	RawHeartbeatInterval  *int  `json:"heartbeat_interval"`
+	RawHeartbeatTimeout   *int  `json:"heartbeat_timeout"`
+	RawMaxTimeWithoutStep *int  `json:"max_time_without_step"`
+	RawRestart            *bool `json:"restart"`
+}
@@ -0,0 +1,13 @@
+package expconf
+
+// LogMetricRuleV0 derives metrics from the logs of the trials of the experiment, for training code
+// that can't report metrics through the harness. A rule either matches lines against a regular
+// expression, whose named groups are metrics, or reads fields of lines that are JSON objects.
+//
+//go:generate ../gen.sh
+type LogMetricRuleV0 struct {
+	RawRegex *string  `json:"regex"`
+	RawJSON  []string `json:"json"`
+	// RawSteps names the group or field with the steps completed when the line was logged.
+	RawSteps *string `json:"steps"`
+}
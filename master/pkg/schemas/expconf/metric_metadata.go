@@ -0,0 +1,17 @@
+package expconf
+
+// MetricMetadataConfigV0 declares how clients should display the metrics of the experiment, by
+// metric name.
+//
+//go:generate ../gen.sh
+type MetricMetadataConfigV0 map[string]MetricMetadataV0
+
+// MetricMetadataV0 declares how clients should display a metric.
+//
+//go:generate ../gen.sh
+type MetricMetadataV0 struct {
+	RawUnit        *string `json:"unit"`
+	RawDirection   *string `json:"direction"`
+	RawDisplayName *string `json:"display_name"`
+	RawScale       *string `json:"scale"`
+}
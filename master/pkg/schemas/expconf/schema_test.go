@@ -133,6 +133,10 @@ func objectForURL(url string) interface{} {
 	switch url {
 	case "http://determined.ai/schemas/expconf/v0/experiment.json":
 		return &ExperimentConfigV0{}
+	case "http://determined.ai/schemas/expconf/v0/agent-constraint.json":
+		return &AgentConstraintV0{}
+	case "http://determined.ai/schemas/expconf/v0/agent-constraints.json":
+		return &AgentConstraintsConfigV0{}
 	case "http://determined.ai/schemas/expconf/v0/bind-mount.json":
 		return &BindMountV0{}
 	case "http://determined.ai/schemas/expconf/v0/bind-mounts.json":
@@ -143,6 +147,8 @@ func objectForURL(url string) interface{} {
 		return &EnvironmentConfigV0{}
 	case "http://determined.ai/schemas/expconf/v0/resources.json":
 		return &ResourcesConfigV0{}
+	case "http://determined.ai/schemas/expconf/v0/scratch.json":
+		return &ScratchConfigV0{}
 	// For union member schemas, just return the union type.
 	case "http://determined.ai/schemas/expconf/v0/searcher.json",
 		"http://determined.ai/schemas/expconf/v0/searcher-adaptive-asha.json",
@@ -28,6 +28,8 @@ type CheckpointStorageConfigV0 struct {
 	RawGCSConfig       *GCSConfigV0       `union:"type,gcs" json:"-"`
 	RawAzureConfig     *AzureConfigV0     `union:"type,azure" json:"-"`
 	RawDirectoryConfig *DirectoryConfigV0 `union:"type,directory" json:"-"`
+	RawSFTPConfig      *SFTPConfigV0      `union:"type,sftp" json:"-"`
+	RawHDFSConfig      *HDFSConfigV0      `union:"type,hdfs" json:"-"`
 
 	RawSaveExperimentBest *int `json:"save_experiment_best"`
 	RawSaveTrialBest      *int `json:"save_trial_best"`
@@ -65,6 +67,14 @@ func (c CheckpointStorageConfigV0) Printable() CheckpointStorageConfigV0 {
 			out.RawS3Config.RawSecretKey = &hiddenValue
 		}
 	}
+	if out.RawSFTPConfig != nil {
+		if out.RawSFTPConfig.RawPassword != nil {
+			out.RawSFTPConfig.RawPassword = &hiddenValue
+		}
+		if out.RawSFTPConfig.RawPrivateKeyPassphrase != nil {
+			out.RawSFTPConfig.RawPrivateKeyPassphrase = &hiddenValue
+		}
+	}
 	return out
 }
 
@@ -267,3 +277,32 @@ func (c DirectoryConfigV0) Validate() []error {
 	}
 	return errs
 }
+
+// SFTPConfigV0 configures storing checkpoints on an SFTP server.
+//
+//go:generate ../gen.sh
+type SFTPConfigV0 struct {
+	RawHost     *string `json:"host"`
+	RawPort     *int    `json:"port"`
+	RawUsername *string `json:"username"`
+	RawPassword *string `json:"password,omitempty"`
+	// RawPrivateKey is the path of the private key to authenticate with, in the task container.
+	RawPrivateKey           *string `json:"private_key,omitempty"`
+	RawPrivateKeyPassphrase *string `json:"private_key_passphrase,omitempty"`
+	// RawKnownHosts is the path of a known_hosts file to check the server's host key against, in
+	// the task container, in addition to the system's known hosts.
+	RawKnownHosts *string `json:"known_hosts,omitempty"`
+	// RawHostKey is the server's public host key, like "ssh-ed25519 AAAA...".
+	RawHostKey     *string `json:"host_key,omitempty"`
+	RawStoragePath *string `json:"storage_path"`
+}
+
+// HDFSConfigV0 configures storing checkpoints on HDFS, through its WebHDFS REST API.
+//
+//go:generate ../gen.sh
+type HDFSConfigV0 struct {
+	// RawURL is the WebHDFS address of the namenode, like http://namenode:9870.
+	RawURL         *string `json:"url"`
+	RawUsername    *string `json:"username"`
+	RawStoragePath *string `json:"storage_path"`
+}
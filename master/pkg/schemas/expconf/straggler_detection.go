@@ -0,0 +1,12 @@
+package expconf
+
+// StragglerDetectionConfigV0 configures detection of slow ranks in distributed training.
+//
+//go:generate ../gen.sh
+type StragglerDetectionConfigV0 struct {
+	RawEnabled           *bool    `json:"enabled"`
+	RawSlowdownThreshold *float64 `json:"slowdown_threshold"`
+	RawWindow            *int     `json:"window"`
+	RawPatience          *int     `json:"patience"`
+	RawExcludeNode       *bool    `json:"exclude_node"`
+}
@@ -8,6 +8,48 @@ import (
 )
 
 var (
+	textAgentConstraintV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/agent-constraint.json",
+    "title": "AgentConstraint",
+    "additionalProperties": false,
+    "required": [
+        "key",
+        "operator",
+        "values"
+    ],
+    "type": "object",
+    "properties": {
+        "key": {
+            "type": "string",
+            "minLength": 1
+        },
+        "operator": {
+            "enum": [
+                "in",
+                "notin"
+            ]
+        },
+        "values": {
+            "type": "array",
+            "minItems": 1,
+            "items": {
+                "type": "string"
+            }
+        }
+    }
+}
+`)
+	textAgentConstraintsConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/agent-constraints.json",
+    "title": "AgentConstraintsConfig",
+    "type": "array",
+    "items": {
+        "$ref": "http://determined.ai/schemas/expconf/v0/agent-constraint.json"
+    }
+}
+`)
 	textAzureConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
     "$id": "http://determined.ai/schemas/expconf/v0/azure.json",
@@ -114,6 +156,51 @@ var (
         }
     }
 }
+`)
+	textBatchSizeTuningConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/batch-size-tuning.json",
+    "title": "BatchSizeTuningConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "max_batch_size"
+    ],
+    "properties": {
+        "hyperparameter": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": "global_batch_size"
+        },
+        "min_batch_size": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 1,
+            "minimum": 1
+        },
+        "max_batch_size": {
+            "type": "integer",
+            "minimum": 1
+        },
+        "probe_steps": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 10,
+            "minimum": 1
+        }
+    },
+    "compareProperties": {
+        "type": "a<=b",
+        "a": "min_batch_size",
+        "b": "max_batch_size"
+    }
+}
 `)
 	textBindMountV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -273,7 +360,7 @@ var (
     },
     "then": {
         "union": {
-            "defaultMessage": "is not an object where object[\"type\"] is one of 'shared_fs', 'directory', 's3', 'gcs', or 'azure'",
+            "defaultMessage": "is not an object where object[\"type\"] is one of 'shared_fs', 'directory', 's3', 'gcs', 'azure', 'sftp', or 'hdfs'",
             "items": [
                 {
                     "unionKey": "const:type=shared_fs",
@@ -294,6 +381,14 @@ var (
                 {
                     "unionKey": "const:type=azure",
                     "$ref": "http://determined.ai/schemas/expconf/v0/azure.json"
+                },
+                {
+                    "unionKey": "const:type=sftp",
+                    "$ref": "http://determined.ai/schemas/expconf/v0/sftp.json"
+                },
+                {
+                    "unionKey": "const:type=hdfs",
+                    "$ref": "http://determined.ai/schemas/expconf/v0/hdfs.json"
                 }
             ]
         }
@@ -313,13 +408,18 @@ var (
         "credential": true,
         "endpoint_url": true,
         "prefix": true,
+        "host": true,
         "host_path": true,
+        "password": true,
+        "port": true,
+        "private_key": true,
         "propagation": true,
         "secret_key": true,
         "storage_path": true,
         "tensorboard_path": true,
         "type": true,
-        "user": true,
+        "url": true,
+        "username": true,
         "save_experiment_best": {
             "type": [
                 "integer",
@@ -346,6 +446,52 @@ var (
         }
     }
 }
+`)
+	textCompletionHookConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/completion-hook.json",
+    "title": "CompletionHookConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "command"
+    ],
+    "properties": {
+        "command": {
+            "type": "array",
+            "minItems": 1,
+            "items": {
+                "type": "string"
+            }
+        },
+        "states": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [
+                "COMPLETED"
+            ],
+            "minItems": 1,
+            "uniqueItems": true,
+            "items": {
+                "enum": [
+                    "COMPLETED",
+                    "CANCELED",
+                    "ERROR"
+                ]
+            }
+        },
+        "slots": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 0,
+            "minimum": 0
+        }
+    }
+}
 `)
 	textDeviceV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -447,6 +593,33 @@ var (
         }
     }
 }
+`)
+	textElasticConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/elastic.json",
+    "title": "ElasticConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "min_slots",
+        "max_slots"
+    ],
+    "properties": {
+        "min_slots": {
+            "type": "integer",
+            "minimum": 1
+        },
+        "max_slots": {
+            "type": "integer",
+            "minimum": 1
+        }
+    },
+    "compareProperties": {
+        "type": "a<=b",
+        "a": "min_slots",
+        "b": "max_slots"
+    }
+}
 `)
 	textEnvironmentImageMapV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -612,6 +785,16 @@ var (
             "default": [],
             "optionalRef": "http://determined.ai/schemas/expconf/v0/environment-variables.json"
         },
+        "environment_variable_sets": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "items": {
+                "type": "string"
+            }
+        },
         "proxy_ports": {
             "type": [
                 "array",
@@ -637,6 +820,15 @@ var (
             ],
             "default": false
         },
+        "image_pinning": {
+            "enum": [
+                null,
+                "pin",
+                "repin",
+                "off"
+            ],
+            "default": "pin"
+        },
         "registry_auth": {
             "type": [
                 "object",
@@ -739,6 +931,14 @@ var (
         "searcher"
     ],
     "properties": {
+        "batch_size_tuning": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/batch-size-tuning.json"
+        },
         "bind_mounts": {
             "type": [
                 "array",
@@ -764,6 +964,14 @@ var (
             "default": null,
             "optionalRef": "http://determined.ai/schemas/expconf/v0/checkpoint-storage.json"
         },
+        "completion_hook": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/completion-hook.json"
+        },
         "data": {
             "type": [
                 "object",
@@ -820,6 +1028,13 @@ var (
             "default": {},
             "optionalRef": "http://determined.ai/schemas/expconf/v0/environment.json"
         },
+        "harness_free": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": false
+        },
         "hyperparameters": {
             "type": [
                 "object",
@@ -843,6 +1058,32 @@ var (
                 "type": "string"
             }
         },
+        "launch_layer": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/launch-layer.json"
+        },
+        "liveness": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": {},
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/liveness.json"
+        },
+        "log_metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/log-metric-rule.json"
+            }
+        },
         "log_policies": {
             "type": [
                 "array",
@@ -860,6 +1101,14 @@ var (
             "default": null,
             "optionalRef": "http://determined.ai/schemas/expconf/v0/retention-policy.json"
         },
+        "max_duration": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
         "max_restarts": {
             "type": [
                 "integer",
@@ -868,6 +1117,44 @@ var (
             "minimum": 0,
             "default": 5
         },
+        "max_infra_restarts": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 0,
+            "default": null
+        },
+        "max_steps_without_improvement": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "metadata": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": null,
+            "additionalProperties": {
+                "type": [
+                    "string",
+                    "number",
+                    "boolean"
+                ]
+            }
+        },
+        "metric_metadata": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": {},
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/metric-metadata.json"
+        },
         "min_checkpoint_period": {
             "type": [
                 "object",
@@ -995,6 +1282,14 @@ var (
             "default": {},
             "optionalRef": "http://determined.ai/schemas/expconf/v0/hpc-cluster-slurm.json"
         },
+        "straggler_detection": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": {},
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/straggler-detection.json"
+        },
         "tensorboard_storage": {
             "type": [
                 "object",
@@ -1034,6 +1329,58 @@ var (
                     }
                 }
             }
+        },
+        {
+            "if": {
+                "$comment": "harness-free trials run their entrypoint directly, so they need one",
+                "properties": {
+                    "harness_free": {
+                        "const": true
+                    }
+                },
+                "required": [
+                    "harness_free"
+                ]
+            },
+            "then": {
+                "required": [
+                    "entrypoint"
+                ],
+                "properties": {
+                    "entrypoint": {
+                        "type": [
+                            "string",
+                            "array"
+                        ]
+                    }
+                }
+            }
+        },
+        {
+            "if": {
+                "$comment": "launch layers launch the entrypoint through the harness",
+                "properties": {
+                    "launch_layer": {
+                        "type": "object"
+                    }
+                },
+                "required": [
+                    "launch_layer"
+                ]
+            },
+            "then": {
+                "required": [
+                    "entrypoint"
+                ],
+                "properties": {
+                    "harness_free": {
+                        "enum": [
+                            null,
+                            false
+                        ]
+                    }
+                }
+            }
         }
     ]
 }
@@ -1118,6 +1465,71 @@ var (
         }
     }
 }
+`)
+	textHDFSConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/hdfs.json",
+    "title": "HDFSConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "type"
+    ],
+    "eventuallyRequired": [
+        "url",
+        "storage_path"
+    ],
+    "properties": {
+        "type": {
+            "const": "hdfs"
+        },
+        "url": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "username": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "storage_path": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "save_experiment_best": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 0,
+            "minimum": 0
+        },
+        "save_trial_best": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 1,
+            "minimum": 0
+        },
+        "save_trial_latest": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 1,
+            "minimum": 0
+        }
+    }
+}
 `)
 	textPbsConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -1441,6 +1853,59 @@ var (
         }
     }
 }
+`)
+	textLaunchLayerConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/launch-layer.json",
+    "title": "LaunchLayerConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "type"
+    ],
+    "properties": {
+        "type": {
+            "enum": [
+                "torch_distributed",
+                "deepspeed",
+                "horovod",
+                "horovod_mpi",
+                "horovod_gloo"
+            ]
+        },
+        "args": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "items": {
+                "type": "string",
+                "$comment": "braces may only appear around the placeholders launch commands support",
+                "pattern": "^([^{}]|\\{(slots_per_trial|num_nodes|slots_per_node|node_rank|chief_ip)\\})*$"
+            }
+        }
+    },
+    "allOf": [
+        {
+            "if": {
+                "$comment": "the deepspeed launch layer doesn't take launcher arguments",
+                "properties": {
+                    "type": {
+                        "const": "deepspeed"
+                    }
+                }
+            },
+            "then": {
+                "properties": {
+                    "args": {
+                        "maxItems": 0
+                    }
+                }
+            }
+        }
+    ]
+}
 `)
 	textLengthV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -1494,6 +1959,60 @@ var (
         ]
     }
 }
+`)
+	textLivenessConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/liveness.json",
+    "title": "LivenessConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [],
+    "properties": {
+        "enabled": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": false
+        },
+        "heartbeat_interval": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 30,
+            "minimum": 1
+        },
+        "heartbeat_timeout": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 300,
+            "minimum": 1
+        },
+        "max_time_without_step": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "restart": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": false
+        }
+    },
+    "compareProperties": {
+        "type": "a<b",
+        "a": "heartbeat_interval",
+        "b": "heartbeat_timeout"
+    }
+}
 `)
 	textLogActionV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -1555,6 +2074,90 @@ var (
         }
     }
 }
+`)
+	textLogMetricRuleV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/log-metric-rule.json",
+    "title": "LogMetricRule",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [],
+    "properties": {
+        "regex": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null,
+            "minLength": 1
+        },
+        "json": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": null,
+            "minItems": 1,
+            "uniqueItems": true,
+            "items": {
+                "type": "string",
+                "minLength": 1
+            }
+        },
+        "steps": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null,
+            "minLength": 1
+        }
+    },
+    "checks": {
+        "one of \"regex\" or \"json\" must be set": {
+            "anyOf": [
+                {
+                    "required": [
+                        "regex"
+                    ],
+                    "properties": {
+                        "regex": {
+                            "type": "string"
+                        }
+                    }
+                },
+                {
+                    "required": [
+                        "json"
+                    ],
+                    "properties": {
+                        "json": {
+                            "type": "array"
+                        }
+                    }
+                }
+            ]
+        },
+        "\"regex\" and \"json\" cannot both be set": {
+            "anyOf": [
+                {
+                    "properties": {
+                        "regex": {
+                            "type": "null"
+                        }
+                    }
+                },
+                {
+                    "properties": {
+                        "json": {
+                            "type": "null"
+                        }
+                    }
+                }
+            ]
+        }
+    }
+}
 `)
 	textLogPoliciesConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -1619,6 +2222,59 @@ var (
         }
     }
 }
+`)
+	textMetricMetadataV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/metric-metadata-entry.json",
+    "title": "MetricMetadata",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [],
+    "properties": {
+        "unit": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null,
+            "maxLength": 64
+        },
+        "direction": {
+            "enum": [
+                null,
+                "minimize",
+                "maximize"
+            ],
+            "default": null
+        },
+        "display_name": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null,
+            "maxLength": 128
+        },
+        "scale": {
+            "enum": [
+                null,
+                "linear",
+                "log"
+            ],
+            "default": null
+        }
+    }
+}
+`)
+	textMetricMetadataConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/metric-metadata.json",
+    "title": "MetricMetadataConfig",
+    "type": "object",
+    "additionalProperties": {
+        "$ref": "http://determined.ai/schemas/expconf/v0/metric-metadata-entry.json"
+    }
+}
 `)
 	textOptimizationsConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -2025,6 +2681,14 @@ var (
             ],
             "default": null
         },
+        "agent_constraints": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": [],
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/agent-constraints.json"
+        },
         "devices": {
             "type": [
                 "array",
@@ -2033,6 +2697,14 @@ var (
             "default": [],
             "optionalRef": "http://determined.ai/schemas/expconf/v0/devices.json"
         },
+        "elastic": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/elastic.json"
+        },
         "is_single_node": {
             "type": [
                 "boolean",
@@ -2047,6 +2719,14 @@ var (
             ],
             "default": null
         },
+        "min_gpu_memory_mib": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": null
+        },
         "native_parallel": {
             "type": [
                 "boolean",
@@ -2070,6 +2750,14 @@ var (
             ],
             "default": ""
         },
+        "scratch": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/scratch.json"
+        },
         "shm_size": {
             "type": [
                 "integer",
@@ -2231,6 +2919,64 @@ var (
         }
     }
 }
+`)
+	textScratchConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/scratch.json",
+    "title": "ScratchConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "size_gib"
+    ],
+    "properties": {
+        "size_gib": {
+            "type": "integer",
+            "minimum": 1
+        },
+        "backing": {
+            "enum": [
+                "local",
+                "ephemeral",
+                null
+            ],
+            "default": "local"
+        },
+        "container_path": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": "/scratch"
+        },
+        "storage_class": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        }
+    },
+    "checks": {
+        "storage_class may only be set for ephemeral scratch volumes": {
+            "not": {
+                "required": [
+                    "storage_class"
+                ],
+                "properties": {
+                    "backing": {
+                        "not": {
+                            "const": "ephemeral"
+                        }
+                    },
+                    "storage_class": {
+                        "type": "string"
+                    }
+                }
+            }
+        }
+    }
+}
 `)
 	textAdaptiveASHAConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -3103,41 +3849,169 @@ var (
             ],
             "default": true
         },
-        "source_trial_id": {
+        "source_trial_id": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null
+        },
+        "source_checkpoint_uuid": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "budget": true,
+        "train_stragglers": true,
+        "unit": true
+    }
+}
+`)
+	textSecurityConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/security.json",
+    "title": "SecurityConfig",
+    "$comment": "SecurityConfig has not been used in a very long time",
+    "type": "object",
+    "additionalProperties": false,
+    "properties": {
+        "kerberos": {
+            "type": [
+                "object",
+                "null"
+            ],
+            "default": null,
+            "optionalRef": "http://determined.ai/schemas/expconf/v0/kerberos.json"
+        }
+    }
+}
+`)
+	textSFTPConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/sftp.json",
+    "title": "SFTPConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "type"
+    ],
+    "eventuallyRequired": [
+        "host",
+        "username",
+        "storage_path"
+    ],
+    "checks": {
+        "password and private_key must not both be set": {
+            "not": {
+                "required": [
+                    "password",
+                    "private_key"
+                ],
+                "properties": {
+                    "password": {
+                        "type": "string"
+                    },
+                    "private_key": {
+                        "type": "string"
+                    }
+                }
+            }
+        }
+    },
+    "properties": {
+        "type": {
+            "const": "sftp"
+        },
+        "host": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "port": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "maximum": 65535,
+            "default": 22
+        },
+        "username": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "password": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "private_key": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "private_key_passphrase": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "known_hosts": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "host_key": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "storage_path": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "save_experiment_best": {
             "type": [
                 "integer",
                 "null"
             ],
-            "default": null
+            "default": 0,
+            "minimum": 0
         },
-        "source_checkpoint_uuid": {
+        "save_trial_best": {
             "type": [
-                "string",
+                "integer",
                 "null"
             ],
-            "default": null
+            "default": 1,
+            "minimum": 0
         },
-        "budget": true,
-        "train_stragglers": true,
-        "unit": true
-    }
-}
-`)
-	textSecurityConfigV0 = []byte(`{
-    "$schema": "http://json-schema.org/draft-07/schema#",
-    "$id": "http://determined.ai/schemas/expconf/v0/security.json",
-    "title": "SecurityConfig",
-    "$comment": "SecurityConfig has not been used in a very long time",
-    "type": "object",
-    "additionalProperties": false,
-    "properties": {
-        "kerberos": {
+        "save_trial_latest": {
             "type": [
-                "object",
+                "integer",
                 "null"
             ],
-            "default": null,
-            "optionalRef": "http://determined.ai/schemas/expconf/v0/kerberos.json"
+            "default": 1,
+            "minimum": 0
         }
     }
 }
@@ -3235,6 +4109,55 @@ var (
         }
     }
 }
+`)
+	textStragglerDetectionConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/straggler-detection.json",
+    "title": "StragglerDetectionConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [],
+    "properties": {
+        "enabled": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": false
+        },
+        "slowdown_threshold": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "default": 1.5,
+            "minimum": 1
+        },
+        "window": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 20,
+            "minimum": 1
+        },
+        "patience": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": 3,
+            "minimum": 1
+        },
+        "exclude_node": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": false
+        }
+    }
+}
 `)
 	textTensorboardStorageConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -3453,8 +4376,14 @@ var (
     }
 }
 `)
+	schemaAgentConstraintV0 interface{}
+
+	schemaAgentConstraintsConfigV0 interface{}
+
 	schemaAzureConfigV0 interface{}
 
+	schemaBatchSizeTuningConfigV0 interface{}
+
 	schemaBindMountV0 interface{}
 
 	schemaBindMountsConfigV0 interface{}
@@ -3465,12 +4394,16 @@ var (
 
 	schemaCheckpointStorageConfigV0 interface{}
 
+	schemaCompletionHookConfigV0 interface{}
+
 	schemaDeviceV0 interface{}
 
 	schemaDevicesConfigV0 interface{}
 
 	schemaDirectoryConfigV0 interface{}
 
+	schemaElasticConfigV0 interface{}
+
 	schemaEnvironmentImageMapV0 interface{}
 
 	schemaEnvironmentImageV0 interface{}
@@ -3485,6 +4418,8 @@ var (
 
 	schemaGCSConfigV0 interface{}
 
+	schemaHDFSConfigV0 interface{}
+
 	schemaPbsConfigV0 interface{}
 
 	schemaSlurmConfigV0 interface{}
@@ -3507,18 +4442,28 @@ var (
 
 	schemaKerberosConfigV0 interface{}
 
+	schemaLaunchLayerConfigV0 interface{}
+
 	schemaLengthV0 interface{}
 
+	schemaLivenessConfigV0 interface{}
+
 	schemaLogActionV0 interface{}
 
 	schemaLogLegacyActionCancelRetriesV0 interface{}
 
 	schemaLogLegacyActionExcludeNodeV0 interface{}
 
+	schemaLogMetricRuleV0 interface{}
+
 	schemaLogPoliciesConfigV0 interface{}
 
 	schemaLogPolicyV0 interface{}
 
+	schemaMetricMetadataV0 interface{}
+
+	schemaMetricMetadataConfigV0 interface{}
+
 	schemaOptimizationsConfigV0 interface{}
 
 	schemaPachydermDatasetConfigV0 interface{}
@@ -3545,6 +4490,8 @@ var (
 
 	schemaS3ConfigV0 interface{}
 
+	schemaScratchConfigV0 interface{}
+
 	schemaAdaptiveASHAConfigV0 interface{}
 
 	schemaAdaptiveSimpleConfigV0 interface{}
@@ -3569,8 +4516,12 @@ var (
 
 	schemaSecurityConfigV0 interface{}
 
+	schemaSFTPConfigV0 interface{}
+
 	schemaSharedFSConfigV0 interface{}
 
+	schemaStragglerDetectionConfigV0 interface{}
+
 	schemaTensorboardStorageConfigV0 interface{}
 
 	schemaTestRootV0 interface{}
@@ -3592,6 +4543,46 @@ var (
 	cachedSchemaBytesMap map[string][]byte
 )
 
+func ParsedAgentConstraintV0() interface{} {
+	cacheLock.RLock()
+	if schemaAgentConstraintV0 != nil {
+		cacheLock.RUnlock()
+		return schemaAgentConstraintV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaAgentConstraintV0 != nil {
+		return schemaAgentConstraintV0
+	}
+	err := json.Unmarshal(textAgentConstraintV0, &schemaAgentConstraintV0)
+	if err != nil {
+		panic("invalid embedded json for AgentConstraintV0")
+	}
+	return schemaAgentConstraintV0
+}
+
+func ParsedAgentConstraintsConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaAgentConstraintsConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaAgentConstraintsConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaAgentConstraintsConfigV0 != nil {
+		return schemaAgentConstraintsConfigV0
+	}
+	err := json.Unmarshal(textAgentConstraintsConfigV0, &schemaAgentConstraintsConfigV0)
+	if err != nil {
+		panic("invalid embedded json for AgentConstraintsConfigV0")
+	}
+	return schemaAgentConstraintsConfigV0
+}
+
 func ParsedAzureConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaAzureConfigV0 != nil {
@@ -3612,6 +4603,26 @@ func ParsedAzureConfigV0() interface{} {
 	return schemaAzureConfigV0
 }
 
+func ParsedBatchSizeTuningConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaBatchSizeTuningConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaBatchSizeTuningConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaBatchSizeTuningConfigV0 != nil {
+		return schemaBatchSizeTuningConfigV0
+	}
+	err := json.Unmarshal(textBatchSizeTuningConfigV0, &schemaBatchSizeTuningConfigV0)
+	if err != nil {
+		panic("invalid embedded json for BatchSizeTuningConfigV0")
+	}
+	return schemaBatchSizeTuningConfigV0
+}
+
 func ParsedBindMountV0() interface{} {
 	cacheLock.RLock()
 	if schemaBindMountV0 != nil {
@@ -3712,6 +4723,26 @@ func ParsedCheckpointStorageConfigV0() interface{} {
 	return schemaCheckpointStorageConfigV0
 }
 
+func ParsedCompletionHookConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaCompletionHookConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaCompletionHookConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaCompletionHookConfigV0 != nil {
+		return schemaCompletionHookConfigV0
+	}
+	err := json.Unmarshal(textCompletionHookConfigV0, &schemaCompletionHookConfigV0)
+	if err != nil {
+		panic("invalid embedded json for CompletionHookConfigV0")
+	}
+	return schemaCompletionHookConfigV0
+}
+
 func ParsedDeviceV0() interface{} {
 	cacheLock.RLock()
 	if schemaDeviceV0 != nil {
@@ -3772,6 +4803,26 @@ func ParsedDirectoryConfigV0() interface{} {
 	return schemaDirectoryConfigV0
 }
 
+func ParsedElasticConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaElasticConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaElasticConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaElasticConfigV0 != nil {
+		return schemaElasticConfigV0
+	}
+	err := json.Unmarshal(textElasticConfigV0, &schemaElasticConfigV0)
+	if err != nil {
+		panic("invalid embedded json for ElasticConfigV0")
+	}
+	return schemaElasticConfigV0
+}
+
 func ParsedEnvironmentImageMapV0() interface{} {
 	cacheLock.RLock()
 	if schemaEnvironmentImageMapV0 != nil {
@@ -3912,6 +4963,26 @@ func ParsedGCSConfigV0() interface{} {
 	return schemaGCSConfigV0
 }
 
+func ParsedHDFSConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaHDFSConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaHDFSConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaHDFSConfigV0 != nil {
+		return schemaHDFSConfigV0
+	}
+	err := json.Unmarshal(textHDFSConfigV0, &schemaHDFSConfigV0)
+	if err != nil {
+		panic("invalid embedded json for HDFSConfigV0")
+	}
+	return schemaHDFSConfigV0
+}
+
 func ParsedPbsConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaPbsConfigV0 != nil {
@@ -4132,6 +5203,26 @@ func ParsedKerberosConfigV0() interface{} {
 	return schemaKerberosConfigV0
 }
 
+func ParsedLaunchLayerConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaLaunchLayerConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaLaunchLayerConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaLaunchLayerConfigV0 != nil {
+		return schemaLaunchLayerConfigV0
+	}
+	err := json.Unmarshal(textLaunchLayerConfigV0, &schemaLaunchLayerConfigV0)
+	if err != nil {
+		panic("invalid embedded json for LaunchLayerConfigV0")
+	}
+	return schemaLaunchLayerConfigV0
+}
+
 func ParsedLengthV0() interface{} {
 	cacheLock.RLock()
 	if schemaLengthV0 != nil {
@@ -4152,6 +5243,26 @@ func ParsedLengthV0() interface{} {
 	return schemaLengthV0
 }
 
+func ParsedLivenessConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaLivenessConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaLivenessConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaLivenessConfigV0 != nil {
+		return schemaLivenessConfigV0
+	}
+	err := json.Unmarshal(textLivenessConfigV0, &schemaLivenessConfigV0)
+	if err != nil {
+		panic("invalid embedded json for LivenessConfigV0")
+	}
+	return schemaLivenessConfigV0
+}
+
 func ParsedLogActionV0() interface{} {
 	cacheLock.RLock()
 	if schemaLogActionV0 != nil {
@@ -4212,6 +5323,26 @@ func ParsedLogLegacyActionExcludeNodeV0() interface{} {
 	return schemaLogLegacyActionExcludeNodeV0
 }
 
+func ParsedLogMetricRuleV0() interface{} {
+	cacheLock.RLock()
+	if schemaLogMetricRuleV0 != nil {
+		cacheLock.RUnlock()
+		return schemaLogMetricRuleV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaLogMetricRuleV0 != nil {
+		return schemaLogMetricRuleV0
+	}
+	err := json.Unmarshal(textLogMetricRuleV0, &schemaLogMetricRuleV0)
+	if err != nil {
+		panic("invalid embedded json for LogMetricRuleV0")
+	}
+	return schemaLogMetricRuleV0
+}
+
 func ParsedLogPoliciesConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaLogPoliciesConfigV0 != nil {
@@ -4252,6 +5383,46 @@ func ParsedLogPolicyV0() interface{} {
 	return schemaLogPolicyV0
 }
 
+func ParsedMetricMetadataV0() interface{} {
+	cacheLock.RLock()
+	if schemaMetricMetadataV0 != nil {
+		cacheLock.RUnlock()
+		return schemaMetricMetadataV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaMetricMetadataV0 != nil {
+		return schemaMetricMetadataV0
+	}
+	err := json.Unmarshal(textMetricMetadataV0, &schemaMetricMetadataV0)
+	if err != nil {
+		panic("invalid embedded json for MetricMetadataV0")
+	}
+	return schemaMetricMetadataV0
+}
+
+func ParsedMetricMetadataConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaMetricMetadataConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaMetricMetadataConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaMetricMetadataConfigV0 != nil {
+		return schemaMetricMetadataConfigV0
+	}
+	err := json.Unmarshal(textMetricMetadataConfigV0, &schemaMetricMetadataConfigV0)
+	if err != nil {
+		panic("invalid embedded json for MetricMetadataConfigV0")
+	}
+	return schemaMetricMetadataConfigV0
+}
+
 func ParsedOptimizationsConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaOptimizationsConfigV0 != nil {
@@ -4512,6 +5683,26 @@ func ParsedS3ConfigV0() interface{} {
 	return schemaS3ConfigV0
 }
 
+func ParsedScratchConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaScratchConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaScratchConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaScratchConfigV0 != nil {
+		return schemaScratchConfigV0
+	}
+	err := json.Unmarshal(textScratchConfigV0, &schemaScratchConfigV0)
+	if err != nil {
+		panic("invalid embedded json for ScratchConfigV0")
+	}
+	return schemaScratchConfigV0
+}
+
 func ParsedAdaptiveASHAConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaAdaptiveASHAConfigV0 != nil {
@@ -4752,6 +5943,26 @@ func ParsedSecurityConfigV0() interface{} {
 	return schemaSecurityConfigV0
 }
 
+func ParsedSFTPConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaSFTPConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaSFTPConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaSFTPConfigV0 != nil {
+		return schemaSFTPConfigV0
+	}
+	err := json.Unmarshal(textSFTPConfigV0, &schemaSFTPConfigV0)
+	if err != nil {
+		panic("invalid embedded json for SFTPConfigV0")
+	}
+	return schemaSFTPConfigV0
+}
+
 func ParsedSharedFSConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaSharedFSConfigV0 != nil {
@@ -4772,6 +5983,26 @@ func ParsedSharedFSConfigV0() interface{} {
 	return schemaSharedFSConfigV0
 }
 
+func ParsedStragglerDetectionConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaStragglerDetectionConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaStragglerDetectionConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaStragglerDetectionConfigV0 != nil {
+		return schemaStragglerDetectionConfigV0
+	}
+	err := json.Unmarshal(textStragglerDetectionConfigV0, &schemaStragglerDetectionConfigV0)
+	if err != nil {
+		panic("invalid embedded json for StragglerDetectionConfigV0")
+	}
+	return schemaStragglerDetectionConfigV0
+}
+
 func ParsedTensorboardStorageConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaTensorboardStorageConfigV0 != nil {
@@ -4927,8 +6158,14 @@ func schemaBytesMap() map[string][]byte {
 	}
 	var url string
 	cachedSchemaBytesMap = map[string][]byte{}
+	url = "http://determined.ai/schemas/expconf/v0/agent-constraint.json"
+	cachedSchemaBytesMap[url] = textAgentConstraintV0
+	url = "http://determined.ai/schemas/expconf/v0/agent-constraints.json"
+	cachedSchemaBytesMap[url] = textAgentConstraintsConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/azure.json"
 	cachedSchemaBytesMap[url] = textAzureConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/batch-size-tuning.json"
+	cachedSchemaBytesMap[url] = textBatchSizeTuningConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/bind-mount.json"
 	cachedSchemaBytesMap[url] = textBindMountV0
 	url = "http://determined.ai/schemas/expconf/v0/bind-mounts.json"
@@ -4939,12 +6176,16 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textCheckPositiveLengthV0
 	url = "http://determined.ai/schemas/expconf/v0/checkpoint-storage.json"
 	cachedSchemaBytesMap[url] = textCheckpointStorageConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/completion-hook.json"
+	cachedSchemaBytesMap[url] = textCompletionHookConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/device.json"
 	cachedSchemaBytesMap[url] = textDeviceV0
 	url = "http://determined.ai/schemas/expconf/v0/devices.json"
 	cachedSchemaBytesMap[url] = textDevicesConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/directory.json"
 	cachedSchemaBytesMap[url] = textDirectoryConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/elastic.json"
+	cachedSchemaBytesMap[url] = textElasticConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/environment-image-map.json"
 	cachedSchemaBytesMap[url] = textEnvironmentImageMapV0
 	url = "http://determined.ai/schemas/expconf/v0/environment-image.json"
@@ -4959,6 +6200,8 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textExperimentConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/gcs.json"
 	cachedSchemaBytesMap[url] = textGCSConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/hdfs.json"
+	cachedSchemaBytesMap[url] = textHDFSConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/hpc-cluster-pbs.json"
 	cachedSchemaBytesMap[url] = textPbsConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/hpc-cluster-slurm.json"
@@ -4981,18 +6224,28 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textIntegrationsConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/kerberos.json"
 	cachedSchemaBytesMap[url] = textKerberosConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/launch-layer.json"
+	cachedSchemaBytesMap[url] = textLaunchLayerConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/length.json"
 	cachedSchemaBytesMap[url] = textLengthV0
+	url = "http://determined.ai/schemas/expconf/v0/liveness.json"
+	cachedSchemaBytesMap[url] = textLivenessConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/log-action.json"
 	cachedSchemaBytesMap[url] = textLogActionV0
 	url = "http://determined.ai/schemas/expconf/v0/log-legacy-action-cancel-retries.json"
 	cachedSchemaBytesMap[url] = textLogLegacyActionCancelRetriesV0
 	url = "http://determined.ai/schemas/expconf/v0/log-legacy-action-exclude-node.json"
 	cachedSchemaBytesMap[url] = textLogLegacyActionExcludeNodeV0
+	url = "http://determined.ai/schemas/expconf/v0/log-metric-rule.json"
+	cachedSchemaBytesMap[url] = textLogMetricRuleV0
 	url = "http://determined.ai/schemas/expconf/v0/log-policies.json"
 	cachedSchemaBytesMap[url] = textLogPoliciesConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/log-policy.json"
 	cachedSchemaBytesMap[url] = textLogPolicyV0
+	url = "http://determined.ai/schemas/expconf/v0/metric-metadata-entry.json"
+	cachedSchemaBytesMap[url] = textMetricMetadataV0
+	url = "http://determined.ai/schemas/expconf/v0/metric-metadata.json"
+	cachedSchemaBytesMap[url] = textMetricMetadataConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/optimizations.json"
 	cachedSchemaBytesMap[url] = textOptimizationsConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/pachyderm-dataset.json"
@@ -5019,6 +6272,8 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textRetentionPolicyConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/s3.json"
 	cachedSchemaBytesMap[url] = textS3ConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/scratch.json"
+	cachedSchemaBytesMap[url] = textScratchConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-adaptive-asha.json"
 	cachedSchemaBytesMap[url] = textAdaptiveASHAConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-adaptive-simple.json"
@@ -5043,8 +6298,12 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textSearcherConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/security.json"
 	cachedSchemaBytesMap[url] = textSecurityConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/sftp.json"
+	cachedSchemaBytesMap[url] = textSFTPConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/shared-fs.json"
 	cachedSchemaBytesMap[url] = textSharedFSConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/straggler-detection.json"
+	cachedSchemaBytesMap[url] = textStragglerDetectionConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/tensorboard-storage.json"
 	cachedSchemaBytesMap[url] = textTensorboardStorageConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/test-root.json"
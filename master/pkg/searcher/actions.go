@@ -59,6 +59,26 @@ func (action Stop) String() string {
 	return fmt.Sprintf("Stop{RequestID: %d}", action.RequestID)
 }
 
+// Prioritize is a directive from the searcher to change the priority of a run among the runs of
+// the experiment, such as for a run it promoted to a higher rung. Runs with higher priorities are
+// scheduled first when slots are scarce.
+type Prioritize struct {
+	RequestID model.RequestID `json:"request_id"`
+	Priority  int             `json:"priority"`
+}
+
+// SearcherAction (Prioritize) implements SearcherAction.
+func (Prioritize) searcherAction() {}
+
+// NewPrioritize initializes a new Prioritize action with the given Run ID and priority.
+func NewPrioritize(requestID model.RequestID, priority int) Prioritize {
+	return Prioritize{RequestID: requestID, Priority: priority}
+}
+
+func (action Prioritize) String() string {
+	return fmt.Sprintf("Prioritize{RequestID: %d, Priority: %d}", action.RequestID, action.Priority)
+}
+
 // Shutdown marks the searcher as completed.
 type Shutdown struct {
 	Cancel  bool
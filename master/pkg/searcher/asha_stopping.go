@@ -33,6 +33,13 @@ type (
 		TrialsCompleted  int                      `json:"trials_completed"`
 		InvalidTrials    int                      `json:"invalid_trials"`
 		SearchMethodType SearchMethodType         `json:"search_method_type"`
+		// Decisions records what happened to trials at the rungs they reached, to explain the
+		// search. Only the most recent maxRungDecisions are kept.
+		Decisions []rungDecision `json:"decisions"`
+		// RungPromoted and RungStopped count, by rung, the trials promoted and stopped over the
+		// whole search. Trials that exited early count as stopped.
+		RungPromoted []int `json:"rung_promoted"`
+		RungStopped  []int `json:"rung_stopped"`
 	}
 
 	runMetric struct {
@@ -51,6 +58,9 @@ func (r *rung) String() string {
 
 const ashaExitedMetricValue = math.MaxFloat64
 
+// maxRungDecisions caps the decisions an ASHA searcher keeps, so they don't bloat its snapshots.
+const maxRungDecisions = 1000
+
 func makeRungs(numRungs int, divisor float64, maxLength uint64) []*rung {
 	rungs := make([]*rung, 0, numRungs)
 	for i := 0; i < numRungs; i++ {
@@ -162,12 +172,18 @@ func (s *asyncHalvingStoppingSearch) validationCompleted(
 		return nil, err
 	}
 
+	rungBefore := s.TrialRungs[requestID]
 	ops := s.doEarlyStopping(requestID, *timeStep, *value)
 	allTrials := len(s.TrialRungs) - s.InvalidTrials
 	if len(ops) > 0 && allTrials < s.MaxTrials() {
 		create := NewCreate(ctx.rand, sampleAll(ctx.hparams, ctx.rand))
 		ops = append(ops, create)
 	}
+	// Runs that were promoted continue training the best configurations so far, so they go ahead
+	// of the exploratory runs of lower rungs.
+	if len(ops) == 0 && s.TrialRungs[requestID] > rungBefore {
+		ops = append(ops, NewPrioritize(requestID, s.TrialRungs[requestID]))
+	}
 	return ops, nil
 }
 
@@ -216,6 +232,7 @@ func (s *asyncHalvingStoppingSearch) doEarlyStopping(
 
 		// If this is the top rung, close the run and exit.
 		if r == s.NumRungs()-1 {
+			s.decide(rungDecision{RequestID: requestID, Rung: r, Outcome: RungOutcomeCompleted})
 			actions = append(actions, NewStop(requestID))
 			return actions
 		}
@@ -224,28 +241,44 @@ func (s *asyncHalvingStoppingSearch) doEarlyStopping(
 		// If trials < divisor, continue only if this is the best performing run so far.
 		numContinue := mathx.Max(int(float64(len(rung.Metrics))/s.Divisor()), 1)
 
+		decision := rungDecision{
+			RequestID:   requestID,
+			Rung:        r,
+			Outcome:     RungOutcomePromoted,
+			Rank:        insertIndex + 1,
+			RungSize:    len(rung.Metrics),
+			NumContinue: numContinue,
+		}
 		if insertIndex >= numContinue {
+			decision.Outcome = RungOutcomeStopped
+			s.decide(decision)
 			actions = append(actions, NewStop(requestID))
 			return actions
 		}
+		s.decide(decision)
 
 		// Continue to next rung.
 	}
 	return actions
 }
 
-func (s *asyncHalvingStoppingSearch) progress(
-	map[model.RequestID]float64, map[model.RequestID]bool,
-) float64 {
-	allTrials := len(s.Rungs[0].Metrics)
-	// Give ourselves an overhead of 20% of max trials when calculating progress.
-	progress := float64(allTrials) / (1.2 * float64(s.MaxTrials()))
-	if allTrials == s.MaxTrials() {
-		numValidTrials := float64(s.TrialsCompleted) - float64(s.InvalidTrials)
-		progressNoOverhead := numValidTrials / float64(s.MaxTrials())
-		progress = math.Max(progressNoOverhead, progress)
+// decide records a decision, counting it against its rung and dropping the oldest decisions past
+// maxRungDecisions.
+func (s *asyncHalvingStoppingSearch) decide(d rungDecision) {
+	if len(s.RungPromoted) < len(s.Rungs) {
+		s.RungPromoted = append(s.RungPromoted, make([]int, len(s.Rungs)-len(s.RungPromoted))...)
+		s.RungStopped = append(s.RungStopped, make([]int, len(s.Rungs)-len(s.RungStopped))...)
+	}
+	switch d.Outcome {
+	case RungOutcomePromoted:
+		s.RungPromoted[d.Rung]++
+	case RungOutcomeStopped, RungOutcomeExited:
+		s.RungStopped[d.Rung]++
+	}
+	s.Decisions = append(s.Decisions, d)
+	if n := len(s.Decisions) - maxRungDecisions; n > 0 {
+		s.Decisions = append(s.Decisions[:0], s.Decisions[n:]...)
 	}
-	return progress
 }
 
 func (s *asyncHalvingStoppingSearch) trialExitedEarly(
@@ -279,6 +312,7 @@ func (s *asyncHalvingStoppingSearch) trialExitedEarly(
 	rung := s.Rungs[rungIndex]
 
 	rung.insertMetric(requestID, ashaExitedMetricValue)
+	s.decide(rungDecision{RequestID: requestID, Rung: rungIndex, Outcome: RungOutcomeExited})
 
 	allTrials := len(s.TrialRungs) - s.InvalidTrials
 	if allTrials < s.MaxTrials() {
@@ -449,3 +449,40 @@ func TestASHAStoppingSearchMethod(t *testing.T) {
 	require.Equal(t, 1, stoppedAt900)
 	require.Equal(t, 9, stoppedAt100)
 }
+
+func TestASHAStoppingPrioritizesPromotedTrials(t *testing.T) {
+	config := expconf.AsyncHalvingConfig{
+		RawMaxTime:    ptrs.Ptr(900),
+		RawDivisor:    ptrs.Ptr(3.0),
+		RawNumRungs:   ptrs.Ptr(3),
+		RawMaxTrials:  ptrs.Ptr(10),
+		RawTimeMetric: ptrs.Ptr("batches"),
+	}
+	searcherConfig := expconf.SearcherConfig{
+		RawAsyncHalvingConfig: &config,
+		RawSmallerIsBetter:    ptrs.Ptr(true),
+		RawMetric:             ptrs.Ptr("val_loss"),
+	}
+	searcherConfig = schemas.WithDefaults(searcherConfig)
+	hparams := expconf.Hyperparameters{
+		"x": expconf.Hyperparameter{RawConstHyperparameter: &expconf.ConstHyperparameter{RawVal: 1}},
+	}
+	search := NewTestSearchRunner(t, searcherConfig, hparams).method.(*asyncHalvingStoppingSearch)
+	search.TrialRungs = map[model.RequestID]int{mockRequestID(1): 0}
+
+	// The first validation of a trial promotes it past the first rung.
+	ops, err := search.validationCompleted(context{}, mockRequestID(1), map[string]interface{}{
+		"val_loss": 0.5,
+		"batches":  100.0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Action{NewPrioritize(mockRequestID(1), 1)}, ops)
+
+	// Validations that don't promote the trial don't change its priority.
+	ops, err = search.validationCompleted(context{}, mockRequestID(1), map[string]interface{}{
+		"val_loss": 0.4,
+		"batches":  150.0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, ops)
+}
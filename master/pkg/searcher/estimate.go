@@ -0,0 +1,280 @@
+package searcher
+
+import (
+	"math"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+const (
+	// progressConfidence is the probability the intervals of a ProgressEstimate are meant to
+	// contain the true value.
+	progressConfidence = 0.9
+	// progressZ is the z-score of a two-sided interval with progressConfidence.
+	progressZ = 1.645
+	// continuePriorWeight is the number of pseudo-decisions, made at the rate ASHA is designed
+	// to continue trials at, that the observed continue rate of a rung starts from. It keeps the
+	// estimate sensible before a rung has made many decisions.
+	continuePriorWeight = 2.0
+)
+
+type (
+	// Interval is an estimated value and the interval the value likely lies in.
+	Interval struct {
+		Value float64 `json:"value"`
+		Low   float64 `json:"low"`
+		High  float64 `json:"high"`
+	}
+
+	// ProgressEstimate is the estimated progress of a search and the work left in it. Searches
+	// that stop trials early, like ASHA, can't know how much training is left, so it is estimated
+	// from the rates trials have been stopped at so far.
+	ProgressEstimate struct {
+		// Progress is the fraction of the search's training done, between 0.0 and 1.0.
+		Progress        Interval `json:"progress"`
+		RemainingTrials Interval `json:"remaining_trials"`
+		// RemainingLength is the training left, in trial lengths: one is the training of a trial
+		// that runs to completion.
+		RemainingLength Interval `json:"remaining_length"`
+		// RemainingUnits is the training left, in the unit of the searcher's length, for search
+		// methods that have one.
+		RemainingUnits *Interval `json:"remaining_units,omitempty"`
+		Unit           string    `json:"unit,omitempty"`
+		// Confidence is the probability each interval is meant to contain the true value.
+		Confidence float64 `json:"confidence"`
+	}
+
+	// workEstimate is the training done and left in a search, in trial lengths.
+	workEstimate struct {
+		done            float64
+		remainingTrials Interval
+		remainingLength Interval
+		// length is the length of a trial, if the search method has one.
+		length *expconf.Length
+	}
+)
+
+func exactInterval(v float64) Interval {
+	return Interval{Value: v, Low: v, High: v}
+}
+
+// Proto converts the Interval to its protobuf representation.
+func (e Interval) Proto() *experimentv1.SearcherInterval {
+	return &experimentv1.SearcherInterval{Value: e.Value, Low: e.Low, High: e.High}
+}
+
+// Proto converts the ProgressEstimate to its protobuf representation.
+func (p ProgressEstimate) Proto() *experimentv1.SearcherProgressEstimate {
+	out := &experimentv1.SearcherProgressEstimate{
+		Progress:        p.Progress.Proto(),
+		RemainingTrials: p.RemainingTrials.Proto(),
+		RemainingLength: p.RemainingLength.Proto(),
+		Confidence:      p.Confidence,
+	}
+	if p.RemainingUnits != nil {
+		out.RemainingUnits = p.RemainingUnits.Proto()
+	}
+	if p.Unit != "" {
+		out.Unit = &p.Unit
+	}
+	return out
+}
+
+func (e Interval) add(o Interval) Interval {
+	return Interval{Value: e.Value + o.Value, Low: e.Low + o.Low, High: e.High + o.High}
+}
+
+func (e Interval) scale(f float64) Interval {
+	return Interval{Value: e.Value * f, Low: e.Low * f, High: e.High * f}
+}
+
+// EstimateProgress returns the estimated progress of the search and the work left in it.
+func (s *Searcher) EstimateProgress() ProgressEstimate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.estimateProgress()
+}
+
+func (s *Searcher) estimateProgress() ProgressEstimate {
+	w := s.method.estimate(s.state.TrialProgress, s.state.TrialsClosed)
+	fraction := func(remaining float64) float64 {
+		if w.done+remaining <= 0 {
+			return 0
+		}
+		return mathx.Clamp(0, w.done/(w.done+remaining), 1)
+	}
+	out := ProgressEstimate{
+		// Less training left means more progress, so the bounds swap.
+		Progress: Interval{
+			Value: fraction(w.remainingLength.Value),
+			Low:   fraction(w.remainingLength.High),
+			High:  fraction(w.remainingLength.Low),
+		},
+		RemainingTrials: w.remainingTrials,
+		RemainingLength: w.remainingLength,
+		Confidence:      progressConfidence,
+	}
+	if w.length != nil {
+		units := w.remainingLength.scale(float64(w.length.Units))
+		out.RemainingUnits = &units
+		out.Unit = string(w.length.Unit)
+	}
+	return out
+}
+
+// estimateFixedTrials estimates the work of a search that trains each of its trials to
+// completion, such as random and grid searches, given the trials it has yet to create.
+func estimateFixedTrials(
+	trialProgress map[model.RequestID]float64,
+	trialsClosed map[model.RequestID]bool,
+	uncreated int,
+) workEstimate {
+	w := workEstimate{}
+	open := 0
+	remaining := float64(uncreated)
+	for requestID, p := range trialProgress {
+		if trialsClosed[requestID] {
+			w.done++
+			continue
+		}
+		open++
+		w.done += p
+		remaining += math.Max(1-p, 0)
+	}
+	w.remainingTrials = exactInterval(float64(open + uncreated))
+	w.remainingLength = exactInterval(remaining)
+	return w
+}
+
+func (s *randomSearch) estimate(
+	trialProgress map[model.RequestID]float64,
+	trialsClosed map[model.RequestID]bool,
+) workEstimate {
+	// Trials with invalid hyperparameters have no progress and are replaced, so the search still
+	// creates max_trials trials that have some.
+	return estimateFixedTrials(trialProgress, trialsClosed,
+		mathx.Max(s.MaxTrials()-len(trialProgress), 0))
+}
+
+func (s *gridSearch) estimate(
+	trialProgress map[model.RequestID]float64,
+	trialsClosed map[model.RequestID]bool,
+) workEstimate {
+	open := 0
+	for requestID := range trialProgress {
+		if !trialsClosed[requestID] {
+			open++
+		}
+	}
+	// Pending trials that aren't open yet were requested but not created.
+	uncreated := len(s.RemainingTrials) + mathx.Max(s.PendingTrials-open, 0)
+	return estimateFixedTrials(trialProgress, trialsClosed, uncreated)
+}
+
+// continueRates returns, for each rung but the top one, the estimated rate trials that reach it
+// continue to the next one at, and the bounds of that rate. Each rate is the mean of a beta
+// distribution updated with the decisions made at the rung, starting from the rate the search is
+// designed to continue trials at, 1/divisor.
+func (s *asyncHalvingStoppingSearch) continueRates() (mean, low, high []float64) {
+	n := len(s.Rungs) - 1
+	designed := 1 / s.Divisor()
+	for r := 0; r < n; r++ {
+		var promoted, stopped float64
+		if r < len(s.RungPromoted) {
+			promoted, stopped = float64(s.RungPromoted[r]), float64(s.RungStopped[r])
+		}
+		a := promoted + continuePriorWeight*designed
+		b := stopped + continuePriorWeight*(1-designed)
+		m := a / (a + b)
+		sd := math.Sqrt(a * b / ((a + b) * (a + b) * (a + b + 1)))
+		mean = append(mean, m)
+		low = append(low, mathx.Clamp(0, m-progressZ*sd, 1))
+		high = append(high, mathx.Clamp(0, m+progressZ*sd, 1))
+	}
+	return mean, low, high
+}
+
+// remainingLength returns the expected training left, in trial lengths, for a trial that is
+// waiting to be decided on at a rung and has trained trained trial lengths, given the rates trials
+// continue from each rung.
+func (s *asyncHalvingStoppingSearch) remainingLength(
+	rungIndex int, trained float64, rates []float64,
+) float64 {
+	maxUnits := float64(s.Length().Units)
+	at := func(r int) float64 { return float64(s.Rungs[r].UnitsNeeded) / maxUnits }
+
+	remaining := math.Max(at(rungIndex)-trained, 0)
+	reach := 1.0
+	for r := rungIndex + 1; r < len(s.Rungs); r++ {
+		reach *= rates[r-1]
+		remaining += reach * math.Max(at(r)-math.Max(at(r-1), trained), 0)
+	}
+	return remaining
+}
+
+func (s *asyncHalvingStoppingSearch) estimate(
+	trialProgress map[model.RequestID]float64,
+	trialsClosed map[model.RequestID]bool,
+) workEstimate {
+	length := s.Length()
+	w := workEstimate{length: &length}
+	mean, low, high := s.continueRates()
+
+	open := 0
+	for requestID, p := range trialProgress {
+		w.done += p
+		if trialsClosed[requestID] || s.EarlyExitTrials[requestID] {
+			continue
+		}
+		open++
+		rungIndex := s.TrialRungs[requestID]
+		w.remainingLength = w.remainingLength.add(Interval{
+			Value: s.remainingLength(rungIndex, p, mean),
+			Low:   s.remainingLength(rungIndex, p, low),
+			High:  s.remainingLength(rungIndex, p, high),
+		})
+	}
+	// Trials with invalid hyperparameters are replaced, so the search trains max_trials trials
+	// with valid ones.
+	uncreated := mathx.Max(s.MaxTrials()-(len(s.TrialRungs)-s.InvalidTrials), 0)
+	w.remainingLength = w.remainingLength.add(Interval{
+		Value: s.remainingLength(0, 0, mean),
+		Low:   s.remainingLength(0, 0, low),
+		High:  s.remainingLength(0, 0, high),
+	}.scale(float64(uncreated)))
+	w.remainingTrials = exactInterval(float64(open + uncreated))
+	return w
+}
+
+func (s *tournamentSearch) estimate(
+	trialProgress map[model.RequestID]float64,
+	trialsClosed map[model.RequestID]bool,
+) workEstimate {
+	w := workEstimate{}
+	for subSearchID, subSearch := range s.subSearches {
+		subSearchTrialProgress := map[model.RequestID]float64{}
+		for rID, p := range trialProgress {
+			if subSearchID == s.TrialTable[rID] {
+				subSearchTrialProgress[rID] = p
+			}
+		}
+		subSearchTrialsClosed := map[model.RequestID]bool{}
+		for rID, closed := range trialsClosed {
+			if subSearchID == s.TrialTable[rID] {
+				subSearchTrialsClosed[rID] = closed
+			}
+		}
+		sub := subSearch.estimate(subSearchTrialProgress, subSearchTrialsClosed)
+		w.done += sub.done
+		w.remainingTrials = w.remainingTrials.add(sub.remainingTrials)
+		w.remainingLength = w.remainingLength.add(sub.remainingLength)
+		if w.length == nil {
+			w.length = sub.length
+		}
+	}
+	return w
+}
@@ -0,0 +1,150 @@
+//nolint:exhaustruct
+package searcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func requireInterval(t *testing.T, expected float64, actual Interval) {
+	require.InDelta(t, expected, actual.Value, 1e-9)
+	require.LessOrEqual(t, actual.Low, actual.Value)
+	require.GreaterOrEqual(t, actual.High, actual.Value)
+}
+
+func TestEstimateProgressRandom(t *testing.T) {
+	conf := expconf.SearcherConfig{
+		RawMetric: ptrs.Ptr("loss"),
+		RawRandomConfig: &expconf.RandomConfig{
+			RawMaxTrials:           ptrs.Ptr(4),
+			RawMaxConcurrentTrials: ptrs.Ptr(2),
+		},
+	}
+	sr := NewTestSearchRunner(t, conf, expconf.Hyperparameters{})
+	sr.initialRuns()
+	require.Len(t, sr.trials, 2)
+
+	// Trials that are yet to be created count as left to do, rather than being ignored.
+	sr.searcher.SetTrialProgress(sr.trials[0].requestID, 0.5)
+	estimate := sr.searcher.EstimateProgress()
+	require.Equal(t, exactInterval(4), estimate.RemainingTrials)
+	require.Equal(t, exactInterval(3.5), estimate.RemainingLength)
+	require.Equal(t, exactInterval(0.125), estimate.Progress)
+	require.Nil(t, estimate.RemainingUnits)
+	require.InDelta(t, 0.125, sr.searcher.Progress(), 1e-9)
+
+	sr.closeRun(sr.trials[0].requestID)
+	require.Len(t, sr.trials, 3)
+	estimate = sr.searcher.EstimateProgress()
+	require.Equal(t, exactInterval(3), estimate.RemainingTrials)
+	require.Equal(t, exactInterval(3), estimate.RemainingLength)
+	require.Equal(t, exactInterval(0.25), estimate.Progress)
+}
+
+func TestEstimateProgressASHA(t *testing.T) {
+	config := expconf.AsyncHalvingConfig{
+		RawMaxTime:    ptrs.Ptr(900),
+		RawDivisor:    ptrs.Ptr(3.0),
+		RawNumRungs:   ptrs.Ptr(3),
+		RawMaxTrials:  ptrs.Ptr(10),
+		RawTimeMetric: ptrs.Ptr("batches"),
+	}
+	searcherConfig := expconf.SearcherConfig{
+		RawAsyncHalvingConfig: &config,
+		RawMetric:             ptrs.Ptr("loss"),
+		RawSmallerIsBetter:    ptrs.Ptr(true),
+	}
+	searcherConfig = schemas.WithDefaults(searcherConfig)
+	intHparam := &expconf.IntHyperparameter{RawMaxval: 10, RawCount: ptrs.Ptr(3)}
+	hparams := expconf.Hyperparameters{
+		"x": expconf.Hyperparameter{RawIntHyperparameter: intHparam},
+	}
+	sr := NewTestSearchRunner(t, searcherConfig, hparams)
+	sr.initialRuns()
+
+	// Before any decisions, trials are expected to continue from each rung at 1/divisor: each
+	// trains to 100 batches, a third of them to 300 and a ninth of them to 900.
+	perTrial := (100 + 200.0/3 + 600.0/9) / 900
+	estimate := sr.searcher.EstimateProgress()
+	require.Equal(t, exactInterval(10), estimate.RemainingTrials)
+	requireInterval(t, 10*perTrial, estimate.RemainingLength)
+	require.Less(t, estimate.RemainingLength.Low, estimate.RemainingLength.High)
+	require.NotNil(t, estimate.RemainingUnits)
+	requireInterval(t, 10*perTrial*900, *estimate.RemainingUnits)
+	require.Equal(t, "batches", estimate.Unit)
+	require.Equal(t, 0.9, estimate.Confidence)
+
+	// Trials get worse as the search goes on, so nearly all are stopped at the first rung and
+	// the rate trials are expected to continue from it at drops.
+	sr.run(900, 100, true)
+	method := sr.method.(*asyncHalvingStoppingSearch)
+	mean, low, high := method.continueRates()
+	require.Less(t, mean[0], 1.0/3)
+	require.LessOrEqual(t, low[0], mean[0])
+	require.GreaterOrEqual(t, high[0], mean[0])
+
+	estimate = sr.searcher.EstimateProgress()
+	require.Equal(t, exactInterval(0), estimate.RemainingTrials)
+	require.Equal(t, exactInterval(0), estimate.RemainingLength)
+}
+
+func TestEstimateProgressAdaptiveASHA(t *testing.T) {
+	maxConcurrentTrials := 5
+	maxTrials := 10
+	divisor := 3.0
+	maxTime := 900
+	config := expconf.SearcherConfig{
+		RawAdaptiveASHAConfig: &expconf.AdaptiveASHAConfig{
+			RawMaxTime:             &maxTime,
+			RawMaxTrials:           &maxTrials,
+			RawMaxConcurrentTrials: &maxConcurrentTrials,
+			RawDivisor:             &divisor,
+			RawMode:                ptrs.Ptr(expconf.StandardMode),
+			RawTimeMetric:          ptrs.Ptr("batches"),
+			RawMaxRungs:            ptrs.Ptr(3),
+		},
+		RawMetric:          ptrs.Ptr("loss"),
+		RawSmallerIsBetter: ptrs.Ptr(true),
+	}
+	config = schemas.WithDefaults(config)
+	sr := NewTestSearchRunner(t, config, expconf.Hyperparameters{})
+	sr.initialRuns()
+
+	// The brackets' trials add up to max_trials.
+	estimate := sr.searcher.EstimateProgress()
+	require.Equal(t, exactInterval(float64(maxTrials)), estimate.RemainingTrials)
+	require.Greater(t, estimate.RemainingLength.Value, 0.0)
+	require.Equal(t, exactInterval(0), estimate.Progress)
+
+	sr.run(900, 100, true)
+	estimate = sr.searcher.EstimateProgress()
+	require.Equal(t, exactInterval(0), estimate.RemainingTrials)
+	require.Equal(t, exactInterval(0), estimate.RemainingLength)
+}
+
+func TestProgressEstimateProto(t *testing.T) {
+	estimate := ProgressEstimate{
+		Progress:        Interval{Value: 0.5, Low: 0.4, High: 0.6},
+		RemainingTrials: exactInterval(3),
+		RemainingLength: Interval{Value: 1.5, Low: 1, High: 2},
+		Confidence:      progressConfidence,
+	}
+	pb := estimate.Proto()
+	require.Equal(t, 0.4, pb.Progress.Low)
+	require.Equal(t, 3.0, pb.RemainingTrials.Value)
+	require.Equal(t, 2.0, pb.RemainingLength.High)
+	require.Equal(t, progressConfidence, pb.Confidence)
+	require.Nil(t, pb.RemainingUnits)
+	require.Nil(t, pb.Unit)
+
+	estimate.RemainingUnits = &Interval{Value: 150, Low: 100, High: 200}
+	estimate.Unit = "batches"
+	pb = estimate.Proto()
+	require.Equal(t, 150.0, pb.RemainingUnits.Value)
+	require.Equal(t, "batches", pb.GetUnit())
+}
@@ -59,31 +59,6 @@ func (s *gridSearch) initialTrials(ctx context) ([]Action, error) {
 	return actions, nil
 }
 
-func (s *gridSearch) progress(
-	trialProgress map[model.RequestID]float64,
-	trialsClosed map[model.RequestID]bool,
-) float64 {
-	if s.MaxConcurrentTrials() > 0 && s.PendingTrials > s.MaxConcurrentTrials() {
-		panic("pending trials is greater than max_concurrent_trials")
-	}
-	// Progress is calculated as follows:
-	//   - InvalidHP trials contribute max_length units since they represent one config within the grid
-	//     and are not replaced with a new config as with random search
-	//   - Other early-exit trials contribute max_length units
-	//   - In progress trials contribute units trained
-	trialProgresses := 0.
-
-	for k, v := range trialProgress {
-		if trialsClosed[k] {
-			trialProgresses += 1.0
-		} else {
-			trialProgresses += v
-		}
-	}
-
-	return trialProgresses / float64(len(trialProgress))
-}
-
 // trialExitedEarly does nothing since grid does not take actions based on
 // search status or progress.
 func (s *gridSearch) trialExitedEarly(
@@ -0,0 +1,274 @@
+package searcher
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// RungOutcome is what an ASHA searcher decided for a trial when it reached a rung.
+type RungOutcome string
+
+const (
+	// RungOutcomePromoted means the trial ranked well enough to continue to the next rung.
+	RungOutcomePromoted RungOutcome = "PROMOTED"
+	// RungOutcomeStopped means the trial was stopped early for ranking too low in the rung.
+	RungOutcomeStopped RungOutcome = "STOPPED"
+	// RungOutcomeCompleted means the trial reached the top rung and trained to completion.
+	RungOutcomeCompleted RungOutcome = "COMPLETED"
+	// RungOutcomeExited means the trial exited on its own before the searcher decided.
+	RungOutcomeExited RungOutcome = "EXITED"
+)
+
+type (
+	// Introspection is a view of the internal state of a running search, for understanding why
+	// the searcher made the decisions it did.
+	Introspection struct {
+		SearchMethod    SearchMethodType       `json:"search_method"`
+		TrialsRequested int                    `json:"trials_requested"`
+		PendingTrials   []IntrospectedTrial    `json:"pending_trials"`
+		ClosedTrials    []IntrospectedTrial    `json:"closed_trials"`
+		Brackets        []BracketIntrospection `json:"brackets,omitempty"`
+	}
+
+	// IntrospectedTrial identifies a trial by searcher request ID and, once created, trial ID.
+	IntrospectedTrial struct {
+		RequestID model.RequestID `json:"request_id"`
+		TrialID   int             `json:"trial_id,omitempty"`
+	}
+
+	// BracketIntrospection is the state of one ASHA bracket. Adaptive ASHA runs several. Only
+	// its most recent decisions are kept.
+	BracketIntrospection struct {
+		MaxTrials int                 `json:"max_trials"`
+		Rungs     []RungIntrospection `json:"rungs"`
+		Decisions []RungDecision      `json:"decisions"`
+	}
+
+	// RungIntrospection is the population of a rung, best first.
+	RungIntrospection struct {
+		UnitsNeeded uint64       `json:"units_needed"`
+		Population  []RungMember `json:"population"`
+	}
+
+	// RungMember is a trial that reached a rung and the searcher metric it reported there. Trials
+	// that exited early have no metric.
+	RungMember struct {
+		IntrospectedTrial
+		Metric *float64 `json:"metric,omitempty"`
+	}
+
+	// RungDecision is a decision the searcher made for a trial when it reached a rung.
+	RungDecision struct {
+		IntrospectedTrial
+		Rung    int         `json:"rung"`
+		Outcome RungOutcome `json:"outcome"`
+		Reason  string      `json:"reason"`
+	}
+)
+
+// Proto converts a rung outcome to its protobuf representation.
+func (o RungOutcome) Proto() experimentv1.RungOutcome {
+	switch o {
+	case RungOutcomePromoted:
+		return experimentv1.RungOutcome_RUNG_OUTCOME_PROMOTED
+	case RungOutcomeStopped:
+		return experimentv1.RungOutcome_RUNG_OUTCOME_STOPPED
+	case RungOutcomeCompleted:
+		return experimentv1.RungOutcome_RUNG_OUTCOME_COMPLETED
+	case RungOutcomeExited:
+		return experimentv1.RungOutcome_RUNG_OUTCOME_EXITED
+	default:
+		return experimentv1.RungOutcome_RUNG_OUTCOME_UNSPECIFIED
+	}
+}
+
+// Proto converts the Introspection to its protobuf representation.
+func (i Introspection) Proto() *experimentv1.SearcherIntrospection {
+	out := &experimentv1.SearcherIntrospection{
+		SearchMethod:    string(i.SearchMethod),
+		TrialsRequested: int32(i.TrialsRequested),
+		PendingTrials:   make([]*experimentv1.IntrospectedTrial, 0, len(i.PendingTrials)),
+		ClosedTrials:    make([]*experimentv1.IntrospectedTrial, 0, len(i.ClosedTrials)),
+		Brackets:        make([]*experimentv1.BracketIntrospection, 0, len(i.Brackets)),
+	}
+	for _, t := range i.PendingTrials {
+		out.PendingTrials = append(out.PendingTrials, t.Proto())
+	}
+	for _, t := range i.ClosedTrials {
+		out.ClosedTrials = append(out.ClosedTrials, t.Proto())
+	}
+	for _, b := range i.Brackets {
+		out.Brackets = append(out.Brackets, b.Proto())
+	}
+	return out
+}
+
+// Proto converts the IntrospectedTrial to its protobuf representation.
+func (t IntrospectedTrial) Proto() *experimentv1.IntrospectedTrial {
+	out := &experimentv1.IntrospectedTrial{RequestId: t.RequestID.String()}
+	if t.TrialID != 0 {
+		out.TrialId = ptrs.Ptr(int32(t.TrialID))
+	}
+	return out
+}
+
+// Proto converts the BracketIntrospection to its protobuf representation.
+func (b BracketIntrospection) Proto() *experimentv1.BracketIntrospection {
+	out := &experimentv1.BracketIntrospection{
+		MaxTrials: int32(b.MaxTrials),
+		Rungs:     make([]*experimentv1.RungIntrospection, 0, len(b.Rungs)),
+		Decisions: make([]*experimentv1.RungDecision, 0, len(b.Decisions)),
+	}
+	for _, r := range b.Rungs {
+		rung := &experimentv1.RungIntrospection{
+			UnitsNeeded: r.UnitsNeeded,
+			Population:  make([]*experimentv1.RungMember, 0, len(r.Population)),
+		}
+		for _, m := range r.Population {
+			rung.Population = append(rung.Population, &experimentv1.RungMember{
+				Trial:  m.IntrospectedTrial.Proto(),
+				Metric: m.Metric,
+			})
+		}
+		out.Rungs = append(out.Rungs, rung)
+	}
+	for _, d := range b.Decisions {
+		out.Decisions = append(out.Decisions, &experimentv1.RungDecision{
+			Trial:   d.IntrospectedTrial.Proto(),
+			Rung:    int32(d.Rung),
+			Outcome: d.Outcome.Proto(),
+			Reason:  d.Reason,
+		})
+	}
+	return out
+}
+
+// introspector is implemented by search methods with internal state worth explaining.
+type introspector interface {
+	introspect() []BracketIntrospection
+}
+
+// Introspect returns a view of the searcher's internal state. trialIDs maps request IDs to the
+// IDs of the trials created for them.
+func (s *Searcher) Introspect(trialIDs map[model.RequestID]int) Introspection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := Introspection{
+		SearchMethod:    s.method.Type(),
+		TrialsRequested: s.state.TrialsRequested,
+		PendingTrials:   []IntrospectedTrial{},
+		ClosedTrials:    []IntrospectedTrial{},
+	}
+	for requestID := range s.state.TrialsCreated {
+		t := IntrospectedTrial{RequestID: requestID, TrialID: trialIDs[requestID]}
+		if s.state.TrialsClosed[requestID] {
+			out.ClosedTrials = append(out.ClosedTrials, t)
+		} else {
+			out.PendingTrials = append(out.PendingTrials, t)
+		}
+	}
+	byTrialID := func(ts []IntrospectedTrial) func(i, j int) bool {
+		return func(i, j int) bool {
+			if ts[i].TrialID != ts[j].TrialID {
+				return ts[i].TrialID < ts[j].TrialID
+			}
+			return ts[i].RequestID.Before(ts[j].RequestID)
+		}
+	}
+	sort.Slice(out.PendingTrials, byTrialID(out.PendingTrials))
+	sort.Slice(out.ClosedTrials, byTrialID(out.ClosedTrials))
+
+	if i, ok := s.method.(introspector); ok {
+		out.Brackets = i.introspect()
+		for _, b := range out.Brackets {
+			for _, r := range b.Rungs {
+				for j := range r.Population {
+					r.Population[j].TrialID = trialIDs[r.Population[j].RequestID]
+				}
+			}
+			for j := range b.Decisions {
+				b.Decisions[j].TrialID = trialIDs[b.Decisions[j].RequestID]
+			}
+		}
+	}
+	return out
+}
+
+// rungDecision is the persisted record of a decision made by an ASHA searcher, from which a
+// RungDecision is rendered.
+type rungDecision struct {
+	RequestID   model.RequestID `json:"request_id"`
+	Rung        int             `json:"rung"`
+	Outcome     RungOutcome     `json:"outcome"`
+	Rank        int             `json:"rank"`
+	RungSize    int             `json:"rung_size"`
+	NumContinue int             `json:"num_continue"`
+}
+
+func (d rungDecision) reason() string {
+	switch d.Outcome {
+	case RungOutcomePromoted:
+		return fmt.Sprintf("ranked %d of %d in rung %d, within the top %d that continue",
+			d.Rank, d.RungSize, d.Rung, d.NumContinue)
+	case RungOutcomeStopped:
+		return fmt.Sprintf("ranked %d of %d in rung %d, outside the top %d that continue",
+			d.Rank, d.RungSize, d.Rung, d.NumContinue)
+	case RungOutcomeCompleted:
+		return fmt.Sprintf("reached the top rung (%d)", d.Rung)
+	case RungOutcomeExited:
+		return fmt.Sprintf("exited early in rung %d and is ranked last in it", d.Rung)
+	default:
+		return ""
+	}
+}
+
+func (s *asyncHalvingStoppingSearch) introspect() []BracketIntrospection {
+	b := BracketIntrospection{
+		MaxTrials: s.MaxTrials(),
+		Rungs:     make([]RungIntrospection, 0, len(s.Rungs)),
+		Decisions: make([]RungDecision, 0, len(s.Decisions)),
+	}
+	for _, r := range s.Rungs {
+		ri := RungIntrospection{
+			UnitsNeeded: r.UnitsNeeded,
+			Population:  make([]RungMember, 0, len(r.Metrics)),
+		}
+		for _, m := range r.Metrics {
+			member := RungMember{IntrospectedTrial: IntrospectedTrial{RequestID: m.RequestID}}
+			if float64(m.Metric) != ashaExitedMetricValue {
+				// Metrics are negated when larger is better so that rungs sort best first.
+				metric := float64(m.Metric)
+				if !s.SmallerIsBetter {
+					metric *= -1
+				}
+				member.Metric = &metric
+			}
+			ri.Population = append(ri.Population, member)
+		}
+		b.Rungs = append(b.Rungs, ri)
+	}
+	for _, d := range s.Decisions {
+		b.Decisions = append(b.Decisions, RungDecision{
+			IntrospectedTrial: IntrospectedTrial{RequestID: d.RequestID},
+			Rung:              d.Rung,
+			Outcome:           d.Outcome,
+			Reason:            d.reason(),
+		})
+	}
+	return []BracketIntrospection{b}
+}
+
+func (s *tournamentSearch) introspect() []BracketIntrospection {
+	var out []BracketIntrospection
+	for _, sub := range s.subSearches {
+		if i, ok := sub.(introspector); ok {
+			out = append(out, i.introspect()...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+//nolint:exhaustruct
+package searcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+func TestASHAIntrospectDecisions(t *testing.T) {
+	s := &asyncHalvingStoppingSearch{SmallerIsBetter: true}
+	s.RawDivisor = ptrs.Ptr(2.0)
+	s.RawNumRungs = ptrs.Ptr(2)
+	s.RawMaxTrials = ptrs.Ptr(3)
+	s.Rungs = []*rung{{UnitsNeeded: 1}, {UnitsNeeded: 2}}
+	s.TrialRungs = map[model.RequestID]int{}
+
+	// The first trial is the best so far in rung 0 and is promoted; the second is worse and is
+	// stopped; the first then reaches the top rung.
+	require.Empty(t, s.doEarlyStopping(mockRequestID(1), 1, 0.1))
+	require.Equal(t, []Action{Stop{RequestID: mockRequestID(2)}},
+		s.doEarlyStopping(mockRequestID(2), 1, 0.5))
+	require.Equal(t, []Action{Stop{RequestID: mockRequestID(1)}},
+		s.doEarlyStopping(mockRequestID(1), 2, 0.05))
+
+	brackets := s.introspect()
+	require.Len(t, brackets, 1)
+	b := brackets[0]
+	require.Equal(t, 3, b.MaxTrials)
+
+	require.Len(t, b.Rungs, 2)
+	require.Equal(t, []RungMember{
+		{IntrospectedTrial: IntrospectedTrial{RequestID: mockRequestID(1)}, Metric: ptrs.Ptr(0.1)},
+		{IntrospectedTrial: IntrospectedTrial{RequestID: mockRequestID(2)}, Metric: ptrs.Ptr(0.5)},
+	}, b.Rungs[0].Population)
+
+	require.Len(t, b.Decisions, 3)
+	require.Equal(t, RungOutcomePromoted, b.Decisions[0].Outcome)
+	require.Equal(t, "ranked 1 of 1 in rung 0, within the top 1 that continue",
+		b.Decisions[0].Reason)
+	require.Equal(t, RungOutcomeStopped, b.Decisions[1].Outcome)
+	require.Equal(t, "ranked 2 of 2 in rung 0, outside the top 1 that continue",
+		b.Decisions[1].Reason)
+	require.Equal(t, RungOutcomeCompleted, b.Decisions[2].Outcome)
+	require.Equal(t, 1, b.Decisions[2].Rung)
+}
+
+func TestASHADecisionsCapped(t *testing.T) {
+	s := &asyncHalvingStoppingSearch{}
+	s.Rungs = []*rung{{UnitsNeeded: 1}, {UnitsNeeded: 2}}
+	for i := 0; i < maxRungDecisions+10; i++ {
+		outcome := RungOutcomeStopped
+		if i%2 == 0 {
+			outcome = RungOutcomePromoted
+		}
+		s.decide(rungDecision{RequestID: mockRequestID(i), Outcome: outcome})
+	}
+
+	// Only the most recent decisions are kept, but every decision is counted against its rung.
+	require.Len(t, s.Decisions, maxRungDecisions)
+	require.Equal(t, mockRequestID(10), s.Decisions[0].RequestID)
+	require.Equal(t, []int{(maxRungDecisions + 10) / 2, 0}, s.RungPromoted)
+	require.Equal(t, []int{(maxRungDecisions + 10) / 2, 0}, s.RungStopped)
+}
+
+func TestIntrospectionProto(t *testing.T) {
+	in := Introspection{
+		SearchMethod:    ASHASearch,
+		TrialsRequested: 2,
+		PendingTrials:   []IntrospectedTrial{{RequestID: mockRequestID(1), TrialID: 7}},
+		ClosedTrials:    []IntrospectedTrial{{RequestID: mockRequestID(2)}},
+		Brackets: []BracketIntrospection{{
+			MaxTrials: 2,
+			Rungs: []RungIntrospection{{
+				UnitsNeeded: 1,
+				Population: []RungMember{
+					{IntrospectedTrial: IntrospectedTrial{RequestID: mockRequestID(1), TrialID: 7}},
+				},
+			}},
+			Decisions: []RungDecision{{
+				IntrospectedTrial: IntrospectedTrial{RequestID: mockRequestID(2)},
+				Outcome:           RungOutcomeExited,
+			}},
+		}},
+	}
+
+	out := in.Proto()
+	require.Equal(t, string(ASHASearch), out.SearchMethod)
+	require.Equal(t, int32(7), out.PendingTrials[0].GetTrialId())
+	// Trials that haven't been created yet have no trial ID.
+	require.Nil(t, out.ClosedTrials[0].TrialId)
+	require.Nil(t, out.Brackets[0].Rungs[0].Population[0].Metric)
+	require.Equal(t, experimentv1.RungOutcome_RUNG_OUTCOME_EXITED,
+		out.Brackets[0].Decisions[0].Outcome)
+}
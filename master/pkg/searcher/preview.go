@@ -0,0 +1,92 @@
+package searcher
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// SampledTrial is the hyperparameter configuration and seed of a trial the searcher would create.
+type SampledTrial struct {
+	TrialSeed uint32       `json:"trial_seed"`
+	Hparams   HParamSample `json:"hparams"`
+}
+
+// SearchEstimate is an estimate of the total size of a search.
+type SearchEstimate struct {
+	// TrialCount is the number of trials the search will create, not counting trials replaced
+	// after exiting with an invalid hyperparameter configuration.
+	TrialCount int `json:"trial_count"`
+	// Units is the total training length over all trials, in Unit, when the searcher determines
+	// it. It is unset for searchers whose trials all train to the length set in the training code.
+	Units *int64  `json:"units,omitempty"`
+	Unit  *string `json:"unit,omitempty"`
+	// SlotUnits is Units multiplied by the slots each trial uses.
+	SlotUnits *int64 `json:"slot_units,omitempty"`
+}
+
+// Sample returns the first n trials the searcher would create for an experiment with the given
+// seed, in the order it would create them. Sampling consumes the seeded random state the same way
+// the searcher does, so the samples match what an experiment with that seed runs.
+func Sample(
+	conf expconf.SearcherConfig, hparams expconf.Hyperparameters, seed uint32, n int,
+) ([]SampledTrial, error) {
+	rand := nprand.New(seed)
+	samples := []SampledTrial{}
+	add := func(hps HParamSample) {
+		create := NewCreate(rand, hps)
+		samples = append(samples, SampledTrial{TrialSeed: create.TrialSeed, Hparams: create.Hparams})
+	}
+
+	switch {
+	case conf.RawSingleConfig != nil:
+		n = mathx.Min(n, 1)
+	case conf.RawRandomConfig != nil:
+		n = mathx.Min(n, conf.RawRandomConfig.MaxTrials())
+	case conf.RawAsyncHalvingConfig != nil:
+		n = mathx.Min(n, conf.RawAsyncHalvingConfig.MaxTrials())
+	case conf.RawAdaptiveASHAConfig != nil:
+		n = mathx.Min(n, conf.RawAdaptiveASHAConfig.MaxTrials())
+	case conf.RawGridConfig != nil:
+		// The grid searcher hands out grid points starting from the end of the grid.
+		grid := newHyperparameterGrid(hparams)
+		for i := len(grid) - 1; i >= 0 && len(samples) < n; i-- {
+			add(grid[i])
+		}
+		return samples, nil
+	default:
+		return nil, errors.New("invalid searcher configuration")
+	}
+
+	for len(samples) < n {
+		add(sampleAll(hparams, rand))
+	}
+	return samples, nil
+}
+
+// Estimate returns the estimated total size of a simulated search whose trials each use
+// slotsPerTrial slots.
+func Estimate(summary SearchSummary, slotsPerTrial int) SearchEstimate {
+	var estimate SearchEstimate
+	var units int64
+	lengthKnown := true
+	for _, t := range summary.Trials {
+		estimate.TrialCount += t.Count
+		if t.Unit.MaxLength || t.Unit.Value == nil {
+			lengthKnown = false
+			continue
+		}
+		units += int64(t.Count) * int64(*t.Unit.Value)
+		estimate.Unit = t.Unit.Name
+	}
+	if lengthKnown && estimate.TrialCount > 0 {
+		slotUnits := units * int64(slotsPerTrial)
+		estimate.Units = &units
+		estimate.SlotUnits = &slotUnits
+	} else {
+		estimate.Unit = nil
+	}
+	return estimate
+}
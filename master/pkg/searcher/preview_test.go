@@ -0,0 +1,90 @@
+package searcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func initialCreates(
+	t *testing.T, conf expconf.SearcherConfig, hparams expconf.Hyperparameters, seed uint32,
+) []SampledTrial {
+	s := NewSearcher(seed, NewSearchMethod(conf), hparams)
+	actions, err := s.InitialTrials()
+	require.NoError(t, err)
+	var out []SampledTrial
+	for _, a := range actions {
+		c, ok := a.(Create)
+		require.True(t, ok)
+		out = append(out, SampledTrial{TrialSeed: c.TrialSeed, Hparams: c.Hparams})
+	}
+	return out
+}
+
+func TestSampleMatchesSearcher(t *testing.T) {
+	hparams := expconf.Hyperparameters{
+		"x": expconf.Hyperparameter{
+			RawIntHyperparameter: &expconf.IntHyperparameter{
+				RawMinval: 0, RawMaxval: 10, RawCount: ptrs.Ptr(3),
+			},
+		},
+		"y": expconf.Hyperparameter{
+			RawCategoricalHyperparameter: &expconf.CategoricalHyperparameter{
+				RawVals: []interface{}{"a", "b"},
+			},
+		},
+	}
+	cases := map[string]expconf.SearcherConfig{
+		"random": {
+			RawMetric: ptrs.Ptr("loss"),
+			RawRandomConfig: &expconf.RandomConfig{
+				RawMaxTrials:           ptrs.Ptr(5),
+				RawMaxConcurrentTrials: ptrs.Ptr(5),
+			},
+		},
+		"grid": {
+			RawMetric: ptrs.Ptr("loss"),
+			RawGridConfig: &expconf.GridConfig{
+				RawMaxConcurrentTrials: ptrs.Ptr(6),
+			},
+		},
+	}
+	for name, conf := range cases {
+		t.Run(name, func(t *testing.T) {
+			conf = schemas.WithDefaults(conf)
+			expected := initialCreates(t, conf, hparams, 7)
+
+			samples, err := Sample(conf, hparams, 7, 100)
+			require.NoError(t, err)
+			require.Equal(t, expected, samples)
+
+			samples, err = Sample(conf, hparams, 7, 2)
+			require.NoError(t, err)
+			require.Equal(t, expected[:2], samples)
+		})
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	unit := ptrs.Ptr("batches")
+	summary := SearchSummary{Trials: []TrialSummary{
+		{Count: 5, Unit: SearchUnit{Name: unit, Value: ptrs.Ptr(int32(100))}},
+		{Count: 3, Unit: SearchUnit{Name: unit, Value: ptrs.Ptr(int32(300))}},
+		{Count: 2, Unit: SearchUnit{Name: unit, Value: ptrs.Ptr(int32(900))}},
+	}}
+	require.Equal(t, SearchEstimate{
+		TrialCount: 10,
+		Units:      ptrs.Ptr(int64(3200)),
+		Unit:       unit,
+		SlotUnits:  ptrs.Ptr(int64(6400)),
+	}, Estimate(summary, 2))
+
+	summary = SearchSummary{Trials: []TrialSummary{
+		{Count: 4, Unit: SearchUnit{MaxLength: true}},
+	}}
+	require.Equal(t, SearchEstimate{TrialCount: 4}, Estimate(summary, 2))
+}
@@ -67,32 +67,6 @@ func (s *randomSearch) initialTrials(ctx context) ([]Action, error) {
 	return actions, nil
 }
 
-func (s *randomSearch) progress(
-	trialProgress map[model.RequestID]float64,
-	trialsClosed map[model.RequestID]bool,
-) float64 {
-	if s.MaxConcurrentTrials() > 0 && s.PendingTrials > s.MaxConcurrentTrials() {
-		panic("pending trials is greater than max_concurrent_trials")
-	}
-	// Progress is calculated as follows:
-	//   - InvalidHP trials contribute 0 since we do not count them against max_trials budget and are
-	//     replaced with another randomly sampled config
-	//   - Other early-exit trials contribute max_length units
-	//   - In progress trials contribute units trained
-	// trialsProgress records units trained for all trials except for InvalidHP trials.
-	trialProgresses := 0.
-
-	for k, v := range trialProgress {
-		if trialsClosed[k] {
-			trialProgresses += 1.0
-		} else {
-			trialProgresses += v
-		}
-	}
-
-	return trialProgresses / float64(len(trialProgress))
-}
-
 // trialExitedEarly creates a new trial upon receiving an InvalidHP workload.
 // Otherwise, it does nothing since actions are not taken based on search status.
 func (s *randomSearch) trialExitedEarly(
@@ -27,8 +27,8 @@ type SearchMethod interface {
 		metrics map[string]interface{}) ([]Action, error)
 	// trialExited informs the searcher that the trial has exited.
 	trialExited(ctx context, requestID model.RequestID) ([]Action, error)
-	// progress returns search progress as a float between 0.0 and 1.0.
-	progress(map[model.RequestID]float64, map[model.RequestID]bool) float64
+	// estimate returns the training done and left in the search.
+	estimate(map[model.RequestID]float64, map[model.RequestID]bool) workEstimate
 	// trialExitedEarly informs the searcher that the trial has exited earlier than expected.
 	trialExitedEarly(
 		ctx context, requestID model.RequestID, exitedReason model.ExitedReason,
@@ -194,12 +194,14 @@ func (s *Searcher) TrialIsClosed(requestID model.RequestID) bool {
 	return s.state.TrialsClosed[requestID]
 }
 
-// Progress returns experiment progress as a float between 0.0 and 1.0.
+// Progress returns experiment progress as a float between 0.0 and 1.0. It is the expected value of
+// EstimateProgress, so it accounts for trials yet to be created and, for searches that stop trials
+// early, for the training they are expected to skip.
 func (s *Searcher) Progress() float64 {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	progress := s.method.progress(s.state.TrialProgress, s.state.TrialsClosed)
+	progress := s.estimateProgress().Progress.Value
 	if math.IsNaN(progress) || math.IsInf(progress, 0) {
 		return 0.0
 	}
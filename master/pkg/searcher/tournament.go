@@ -107,30 +107,6 @@ func (s *tournamentSearch) trialExitedEarly(
 	return s.markCreates(subSearchID, ops), err
 }
 
-// progress returns experiment progress as a float between 0.0 and 1.0.
-func (s *tournamentSearch) progress(
-	trialProgress map[model.RequestID]float64,
-	trialsClosed map[model.RequestID]bool,
-) float64 {
-	sum := 0.0
-	for subSearchID, subSearch := range s.subSearches {
-		subSearchTrialProgress := map[model.RequestID]float64{}
-		for rID, p := range trialProgress {
-			if subSearchID == s.TrialTable[rID] {
-				subSearchTrialProgress[rID] = p
-			}
-		}
-		subSearchTrialsClosed := map[model.RequestID]bool{}
-		for rID, closed := range trialsClosed {
-			if subSearchID == s.TrialTable[rID] {
-				subSearchTrialsClosed[rID] = closed
-			}
-		}
-		sum += subSearch.progress(subSearchTrialProgress, subSearchTrialsClosed)
-	}
-	return sum / float64(len(s.subSearches))
-}
-
 func (s *tournamentSearch) markCreates(subSearchID int, actions []Action) []Action {
 	for _, action := range actions {
 		if _, ok := action.(Create); ok {
@@ -10,6 +10,7 @@ import (
 
 	docker "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-units"
 	"github.com/jinzhu/copier"
 
 	"github.com/determined-ai/determined/master/internal/config"
@@ -66,6 +67,10 @@ type TaskSpec struct {
 	LoggingFields map[string]string
 	// LogRetentionDays is the number of days to retain logs for.
 	LogRetentionDays *int16
+	// TaskLogLimits limits the logs the log shipper ships.
+	TaskLogLimits config.TaskLogLimitsConfig
+	// TaskLogShipping configures how the log shipper ships logs.
+	TaskLogShipping config.TaskLogShippingConfig
 
 	// Fields that are set on the cluster level.
 	ClusterID   string
@@ -94,6 +99,8 @@ type TaskSpec struct {
 	// This is used by Docker only.
 	UseHostMode bool
 	ShmSize     int64
+	// Scratch is the scratch volume each container of the task gets, if any.
+	Scratch *expconf.ScratchConfig
 
 	// The parent task of an allocation.
 	TaskID string
@@ -112,9 +119,10 @@ type TaskSpec struct {
 
 	ExtraProxyPorts expconf.ProxyPortsConfig
 
-	Workspace string
-	Project   string
-	Labels    []string
+	Workspace   string
+	WorkspaceID int
+	Project     string
+	Labels      []string
 	// Ports required by trial or commands and their respective base port values.
 	UniqueExposedPortRequests map[string]int
 
@@ -147,6 +155,14 @@ func (t *TaskSpec) ResolveWorkDir() {
 	t.WorkDir = strings.ReplaceAll(workDir, "$DET_USER", detUser)
 }
 
+// OwnerID returns the ID of the user the task runs for, or 0 if it has no owner.
+func (t *TaskSpec) OwnerID() model.UserID {
+	if t.Owner == nil {
+		return 0
+	}
+	return t.Owner.ID
+}
+
 // Archives returns all the archives.
 func (t *TaskSpec) Archives() ([]cproto.RunArchive, []cproto.RunArchive) {
 	res := []cproto.RunArchive{
@@ -216,6 +232,10 @@ func (t TaskSpec) EnvVars() map[string]string {
 	networkInterface := t.TaskContainerDefaults.DtrainNetworkInterface
 	if networkInterface != "" {
 		e["DET_INTER_NODE_NETWORK_INTERFACE"] = networkInterface
+		// NCCL and Gloo otherwise pick an interface on their own, often one that only routes on
+		// the local host, and hang waiting for their peers.
+		e["NCCL_SOCKET_IFNAME"] = networkInterface
+		e["GLOO_SOCKET_IFNAME"] = networkInterface
 	}
 
 	if len(t.MasterCert) != 0 {
@@ -239,12 +259,42 @@ func (t TaskSpec) EnvVars() map[string]string {
 		e["DET_TASK_LOGGING_METADATA"] = string(j)
 	}
 
+	if t.TaskLogLimits.Dedup {
+		e["DET_LOG_DEDUP"] = "true"
+	}
+	if t.TaskLogLimits.LinesPerSecond > 0 {
+		e["DET_LOG_RATE_LIMIT"] = strconv.Itoa(t.TaskLogLimits.LinesPerSecond)
+		if t.TaskLogLimits.Burst > 0 {
+			e["DET_LOG_RATE_LIMIT_BURST"] = strconv.Itoa(t.TaskLogLimits.Burst)
+		}
+	}
+	e["DET_LOG_SPOOL_MAX_BYTES"] = strconv.FormatInt(t.TaskLogShipping.SpoolMaxBytes, 10)
+
 	for k, v := range t.ExtraEnvVars {
 		e[k] = v
 	}
 	return e
 }
 
+// NCCLEnvVars returns the NCCL_* environment variables containers of the task run with on CUDA
+// devices. Like in ToDockerSpec, those of the environment configuration take precedence.
+func (t TaskSpec) NCCLEnvVars() map[string]string {
+	e := map[string]string{}
+	for k, v := range t.EnvVars() {
+		if strings.HasPrefix(k, "NCCL_") {
+			e[k] = v
+		}
+	}
+	if t.Environment.RawEnvironmentVariables != nil {
+		for _, kv := range t.Environment.EnvironmentVariables().For(device.CUDA) {
+			if k, v, _ := strings.Cut(kv, "="); strings.HasPrefix(k, "NCCL_") {
+				e[k] = v
+			}
+		}
+	}
+	return e
+}
+
 // LogShipperWrappedEntrypoint returns the configured Entrypoint wrapped with ship_logs.py.
 func (t *TaskSpec) LogShipperWrappedEntrypoint() []string {
 	if t.DontShipLogs {
@@ -291,6 +341,11 @@ func (t *TaskSpec) ToDockerSpec() cproto.Spec {
 		})
 	}
 
+	var ulimits []*units.Ulimit
+	for _, u := range t.TaskContainerDefaults.Ulimits {
+		ulimits = append(ulimits, &units.Ulimit{Name: u.Name, Soft: u.Soft, Hard: u.Hard})
+	}
+
 	runArchives, rootArchives := t.Archives()
 	spec := cproto.Spec{
 		TaskType: string(t.TaskType),
@@ -317,6 +372,7 @@ func (t *TaskSpec) ToDockerSpec() cproto.Spec {
 
 				Resources: docker.Resources{
 					Devices: devices,
+					Ulimits: ulimits,
 				},
 			},
 			Archives:   append(runArchives, rootArchives...),
@@ -324,6 +380,12 @@ func (t *TaskSpec) ToDockerSpec() cproto.Spec {
 			Registry:   env.RegistryAuth(),
 		},
 	}
+	if t.Scratch != nil {
+		spec.RunSpec.Scratch = &cproto.ScratchSpec{
+			ContainerPath: t.Scratch.ContainerPath(),
+			SizeGiB:       t.Scratch.SizeGiB(),
+		}
+	}
 
 	return spec
 }
@@ -2,6 +2,9 @@ package tasks
 
 import (
 	"archive/tar"
+	"maps"
+	"strconv"
+	"strings"
 	"time"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -10,9 +13,15 @@ import (
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/etc"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/proto/pkg/jobv1"
 )
 
+// rayRendezvousPort is exposed by each container of a Ray cluster for rendezvous, through which
+// the workers find the head node.
+const rayRendezvousPort = 1734
+
 // GenericTaskSpec is the generic task spec.
 type GenericTaskSpec struct {
 	Base           TaskSpec
@@ -22,6 +31,8 @@ type GenericTaskSpec struct {
 	JobID          model.JobID
 
 	GenericTaskConfig model.GenericTaskConfig
+	// SparkBackend is where the task's Spark job, if it has one, is submitted to.
+	SparkBackend *model.SparkBackendConfig
 }
 
 // ToTaskSpec converts the generic task spec to the common task spec.
@@ -29,12 +40,23 @@ func (s GenericTaskSpec) ToTaskSpec() TaskSpec {
 	res := s.Base
 
 	commandEntrypoint := "/run/determined/generic-task-entrypoint.sh"
+	entrypointFile := "generic-task-entrypoint.sh"
+	entrypoint := s.GenericTaskConfig.Entrypoint
+	switch {
+	case s.GenericTaskConfig.Ray != nil:
+		commandEntrypoint = "/run/determined/ray-entrypoint.sh"
+		entrypointFile = "ray-entrypoint.sh"
+	case s.GenericTaskConfig.Spark != nil && s.SparkBackend != nil:
+		commandEntrypoint = "/run/determined/spark-entrypoint.sh"
+		entrypointFile = "spark-entrypoint.sh"
+		entrypoint = model.SparkSubmitCommand(*s.SparkBackend, *s.GenericTaskConfig.Spark)
+	}
 	res.Entrypoint = []string{commandEntrypoint}
-	res.Entrypoint = append(res.Entrypoint, s.GenericTaskConfig.Entrypoint...)
+	res.Entrypoint = append(res.Entrypoint, entrypoint...)
 	commandEntryArchive := wrapArchive(archive.Archive{
 		res.AgentUserGroup.OwnedArchiveItem(
 			commandEntrypoint,
-			etc.MustStaticFile("generic-task-entrypoint.sh"),
+			etc.MustStaticFile(entrypointFile),
 			0o700,
 			tar.TypeReg,
 		),
@@ -56,6 +78,37 @@ func (s GenericTaskSpec) ToTaskSpec() TaskSpec {
 	res.ResourcesConfig = s.GenericTaskConfig.Resources
 
 	res.Description = "generic-task"
+	if ray := s.GenericTaskConfig.Ray; ray != nil {
+		res.Description = "ray-cluster"
+
+		ports := maps.Clone(res.Environment.Ports())
+		if ports == nil {
+			ports = map[string]int{}
+		}
+		ports["rendezvous"] = rayRendezvousPort
+		ports["ray-head"] = ray.HeadPort
+		ports["ray-dashboard"] = ray.DashboardPort
+		res.Environment.SetPorts(ports)
+
+		res.ExtraEnvVars = cloneEnvVars(res.ExtraEnvVars)
+		res.ExtraEnvVars["DET_RAY_HEAD_PORT"] = strconv.Itoa(ray.HeadPort)
+		res.ExtraEnvVars["DET_RAY_DASHBOARD_PORT"] = strconv.Itoa(ray.DashboardPort)
+		if len(ray.StartArgs) > 0 {
+			res.ExtraEnvVars["DET_RAY_START_ARGS"] = strings.Join(ray.StartArgs, " ")
+		}
+	}
+	if s.GenericTaskConfig.Spark != nil {
+		res.Description = "spark-job"
+		if s.SparkBackend != nil && s.SparkBackend.IsKubernetes() {
+			// The driver streams the logs of the executor pods into the task's logs.
+			namespace := s.SparkBackend.Namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			res.ExtraEnvVars = cloneEnvVars(res.ExtraEnvVars)
+			res.ExtraEnvVars["DET_SPARK_EXECUTOR_NAMESPACE"] = namespace
+		}
+	}
 
 	res.Mounts = ToDockerMounts(s.GenericTaskConfig.BindMounts.ToExpconf(), res.WorkDir)
 
@@ -68,6 +121,28 @@ func (s GenericTaskSpec) ToTaskSpec() TaskSpec {
 	return res
 }
 
+func cloneEnvVars(vars map[string]string) map[string]string {
+	out := maps.Clone(vars)
+	if out == nil {
+		out = map[string]string{}
+	}
+	return out
+}
+
+// ProxyPorts returns the ports of the task's services that the master proxies.
+func (s GenericTaskSpec) ProxyPorts() expconf.ProxyPortsConfig {
+	ray := s.GenericTaskConfig.Ray
+	if ray == nil {
+		return nil
+	}
+	return expconf.ProxyPortsConfig{{
+		RawProxyPort:        ray.DashboardPort,
+		RawProxyTCP:         ptrs.Ptr(false),
+		RawUnauthenticated:  ptrs.Ptr(false),
+		RawDefaultServiceID: ptrs.Ptr(true),
+	}}
+}
+
 // TODO(aaron.amanuel): fill in job information. These should probably be on a different struct.
 // not right on the generic task spec.
 
@@ -32,6 +32,16 @@ func TestToTaskSpec(t *testing.T) {
 				GenericTaskConfig: model.DefaultConfigGenericTaskConfig(&model.TaskContainerDefaultsConfig{WorkDir: ptrs.Ptr("/")}),
 			},
 		},
+		"rayTestCase": {
+			expectedDescription:  "ray-cluster",
+			expectedSlots:        1,
+			expectedIsSingleNode: true,
+			expectedEntrypoint:   "/run/determined/ray-entrypoint.sh",
+			expectedType:         model.TaskTypeGeneric,
+			taskSpec: GenericTaskSpec{
+				GenericTaskConfig: rayTaskConfig(),
+			},
+		},
 	}
 
 	for testCase, testVars := range tests {
@@ -47,3 +57,55 @@ func TestToTaskSpec(t *testing.T) {
 		})
 	}
 }
+
+func rayTaskConfig() model.GenericTaskConfig {
+	config := model.DefaultConfigGenericTaskConfig(&model.TaskContainerDefaultsConfig{WorkDir: ptrs.Ptr("/")})
+	config.Ray = &model.RayClusterConfig{
+		HeadPort:      model.DefaultRayHeadPort,
+		DashboardPort: model.DefaultRayDashboardPort,
+	}
+	return config
+}
+
+func TestRayClusterTaskSpec(t *testing.T) {
+	require.NoError(t, etc.SetRootPath("../../static/srv/"))
+	spec := GenericTaskSpec{GenericTaskConfig: rayTaskConfig()}
+	spec.GenericTaskConfig.Environment.Ports = map[string]int{"user": 8080}
+
+	res := spec.ToTaskSpec()
+	require.Equal(t, map[string]int{
+		"user":          8080,
+		"rendezvous":    1734,
+		"ray-head":      model.DefaultRayHeadPort,
+		"ray-dashboard": model.DefaultRayDashboardPort,
+	}, res.Environment.Ports())
+	require.Equal(t, map[string]int{"user": 8080}, spec.GenericTaskConfig.Environment.Ports,
+		"the task's config isn't modified")
+	require.Equal(t, "8265", res.ExtraEnvVars["DET_RAY_DASHBOARD_PORT"])
+
+	proxyPorts := spec.ProxyPorts()
+	require.Len(t, proxyPorts, 1)
+	require.Equal(t, model.DefaultRayDashboardPort, proxyPorts[0].ProxyPort())
+	require.True(t, proxyPorts[0].DefaultServiceID())
+
+	require.Nil(t, GenericTaskSpec{}.ProxyPorts())
+}
+
+func TestSparkJobTaskSpec(t *testing.T) {
+	require.NoError(t, etc.SetRootPath("../../static/srv/"))
+	config := model.DefaultConfigGenericTaskConfig(&model.TaskContainerDefaultsConfig{WorkDir: ptrs.Ptr("/")})
+	config.Spark = &model.SparkJobConfig{Application: "app.py"}
+	spec := GenericTaskSpec{
+		GenericTaskConfig: config,
+		SparkBackend:      &model.SparkBackendConfig{Master: "k8s://https://kubernetes.default.svc"},
+	}
+
+	res := spec.ToTaskSpec()
+	require.Equal(t, "spark-job", res.Description)
+	require.Equal(t, []string{
+		"/run/determined/spark-entrypoint.sh",
+		"spark-submit", "--master", "k8s://https://kubernetes.default.svc", "--deploy-mode", "client",
+		"app.py",
+	}, res.Entrypoint)
+	require.Equal(t, "default", res.ExtraEnvVars["DET_SPARK_EXECUTOR_NAMESPACE"])
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	k8sV1 "k8s.io/api/core/v1"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/pkg/archive"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/etc"
@@ -36,6 +37,59 @@ func TestTaskSpecClone(t *testing.T) {
 	require.Equal(t, map[string]string{"a": "true"}, cloned.ExtraEnvVars)
 }
 
+func TestTaskLogLimitsEnvVars(t *testing.T) {
+	//nolint:exhaustruct
+	spec := TaskSpec{}
+	env := spec.EnvVars()
+	require.NotContains(t, env, "DET_LOG_DEDUP")
+	require.NotContains(t, env, "DET_LOG_RATE_LIMIT")
+
+	spec.TaskLogLimits = config.TaskLogLimitsConfig{Dedup: true, LinesPerSecond: 100, Burst: 500}
+	env = spec.EnvVars()
+	require.Equal(t, "true", env["DET_LOG_DEDUP"])
+	require.Equal(t, "100", env["DET_LOG_RATE_LIMIT"])
+	require.Equal(t, "500", env["DET_LOG_RATE_LIMIT_BURST"])
+}
+
+func TestTaskLogShippingEnvVars(t *testing.T) {
+	//nolint:exhaustruct
+	spec := TaskSpec{}
+	require.Equal(t, "0", spec.EnvVars()["DET_LOG_SPOOL_MAX_BYTES"])
+
+	spec.TaskLogShipping = config.TaskLogShippingConfig{SpoolMaxBytes: 1 << 20}
+	require.Equal(t, "1048576", spec.EnvVars()["DET_LOG_SPOOL_MAX_BYTES"])
+}
+
+func TestDtrainNetworkInterfaceEnvVars(t *testing.T) {
+	//nolint:exhaustruct
+	spec := TaskSpec{}
+	env := spec.EnvVars()
+	require.NotContains(t, env, "NCCL_SOCKET_IFNAME")
+	require.NotContains(t, env, "GLOO_SOCKET_IFNAME")
+
+	spec.TaskContainerDefaults.DtrainNetworkInterface = "ens5"
+	env = spec.EnvVars()
+	require.Equal(t, "ens5", env["DET_INTER_NODE_NETWORK_INTERFACE"])
+	require.Equal(t, "ens5", env["NCCL_SOCKET_IFNAME"])
+	require.Equal(t, "ens5", env["GLOO_SOCKET_IFNAME"])
+}
+
+func TestNCCLEnvVars(t *testing.T) {
+	//nolint:exhaustruct
+	spec := TaskSpec{}
+	require.Empty(t, spec.NCCLEnvVars())
+
+	spec.TaskContainerDefaults.DtrainNetworkInterface = "ens5"
+	spec.Environment.RawEnvironmentVariables = &expconf.EnvironmentVariablesMapV0{
+		RawCPU:  []string{"NCCL_DEBUG=WARN"},
+		RawCUDA: []string{"NCCL_DEBUG=INFO", "NCCL_SOCKET_IFNAME=ib0", "OMP_NUM_THREADS=4"},
+	}
+	require.Equal(t, map[string]string{
+		"NCCL_DEBUG":         "INFO",
+		"NCCL_SOCKET_IFNAME": "ib0",
+	}, spec.NCCLEnvVars())
+}
+
 // finds the first startup hook.
 func findFirstStartupHook(runArchives []cproto.RunArchive) *archive.Item {
 	for _, runArchive := range runArchives {
@@ -69,3 +123,13 @@ func TestTCDStartupHook(t *testing.T) {
 	require.NotNil(t, hook, "TCD with startup hook should generate a startup hook file")
 	require.Contains(t, string(hook.Content), "echo hi")
 }
+
+func TestHarnessFreeEntrypoint(t *testing.T) {
+	require.Nil(t, harnessFreeEntrypoint(nil))
+	require.Equal(t, []string{"sh", "-c", "./train --epochs 3"},
+		harnessFreeEntrypoint(&expconf.EntrypointV0{RawEntrypoint: "./train --epochs 3"}))
+	require.Equal(t, []string{"./train", "--epochs", "3"},
+		harnessFreeEntrypoint(&expconf.EntrypointV0{
+			RawEntrypoint: []interface{}{"./train", "--epochs", 3},
+		}))
+}
@@ -28,8 +28,15 @@ type TrialSpec struct {
 	TrialSeed        uint32
 	LatestCheckpoint *model.Checkpoint
 	StepsCompleted   int
+	// Slots is the number of slots the trial is allocated with, which differs from the
+	// config's slots_per_trial once an elastic trial has been resized.
+	Slots int
 
 	Keys ssh.PrivateAndPublicKeys
+
+	// BatchSizeProbeSteps, if set, makes the trial a probe of whether its batch size fits in
+	// memory, which trains for that many steps without reporting to the master.
+	BatchSizeProbeSteps int
 }
 
 // ToTaskSpec generates a TaskSpec.
@@ -51,13 +58,14 @@ func (s TrialSpec) ToTaskSpec() TaskSpec {
 
 	res.WorkDir = DefaultWorkDir
 
-	additionalFiles := archive.Archive{
-		s.Base.AgentUserGroup.OwnedArchiveItem(
+	var additionalFiles archive.Archive
+	if !s.ExperimentConfig.HarnessFree() {
+		additionalFiles = append(additionalFiles, s.Base.AgentUserGroup.OwnedArchiveItem(
 			trialEntrypointFile,
 			etc.MustStaticFile(etc.TrialEntrypointScriptResource),
 			trialEntrypointMode,
 			tar.TypeReg,
-		),
+		))
 	}
 
 	additionalSSHFiles := archive.Archive{
@@ -109,7 +117,11 @@ func (s TrialSpec) ToTaskSpec() TaskSpec {
 		s.TrialRunID,
 	)
 
-	res.Entrypoint = []string{"/run/determined/train/entrypoint.sh"}
+	if s.ExperimentConfig.HarnessFree() {
+		res.Entrypoint = harnessFreeEntrypoint(s.ExperimentConfig.Entrypoint())
+	} else {
+		res.Entrypoint = []string{"/run/determined/train/entrypoint.sh"}
+	}
 
 	envVars := map[string]string{
 		"DET_EXPERIMENT_ID":     strconv.Itoa(s.ExperimentID),
@@ -121,6 +133,16 @@ func (s TrialSpec) ToTaskSpec() TaskSpec {
 		"DET_STEPS_COMPLETED":   strconv.Itoa(s.StepsCompleted),
 		"DET_TASK_TYPE":         string(model.TaskTypeTrial),
 	}
+	if s.ExperimentConfig.HarnessFree() {
+		envVars["DET_HARNESS_FREE"] = "true"
+	}
+	if l := s.ExperimentConfig.LaunchLayer(); l != nil && s.ExperimentConfig.Entrypoint() != nil {
+		envVars["DET_LAUNCH_COMMAND"] = jsonify(
+			l.Command(*s.ExperimentConfig.Entrypoint(), s.Slots))
+	}
+	if s.BatchSizeProbeSteps > 0 {
+		envVars["DET_BATCH_SIZE_PROBE_STEPS"] = strconv.Itoa(s.BatchSizeProbeSteps)
+	}
 	if s.LatestCheckpoint != nil && s.LatestCheckpoint.UUID != nil {
 		envVars["DET_LATEST_CHECKPOINT"] = s.LatestCheckpoint.UUID.String()
 	}
@@ -142,6 +164,7 @@ func (s TrialSpec) ToTaskSpec() TaskSpec {
 	if shm := s.ExperimentConfig.Resources().ShmSize(); shm != nil {
 		res.ShmSize = int64(*shm)
 	}
+	res.Scratch = s.ExperimentConfig.Resources().Scratch()
 
 	mounts := ToDockerMounts(s.ExperimentConfig.BindMounts(), res.WorkDir)
 	addMount := func(source, target string, bindOpts *mount.BindOptions) {
@@ -162,6 +185,26 @@ func (s TrialSpec) ToTaskSpec() TaskSpec {
 	return res
 }
 
+// harnessFreeEntrypoint returns the command a harness-free trial runs: its entrypoint as is if it
+// is a list, or run by the shell if it is a string.
+func harnessFreeEntrypoint(e *expconf.EntrypointV0) []string {
+	if e == nil {
+		return nil
+	}
+	switch raw := e.RawEntrypoint.(type) {
+	case string:
+		return []string{"sh", "-c", raw}
+	case []interface{}:
+		args := make([]string, 0, len(raw))
+		for _, arg := range raw {
+			args = append(args, fmt.Sprint(arg))
+		}
+		return args
+	default:
+		return nil
+	}
+}
+
 // MakeEnvPorts fills in `Environment.Ports` i.e. exposed ports for container config.
 func (s *TrialSpec) MakeEnvPorts() expconf.EnvironmentConfigV0 {
 	ppc := s.ProxyPorts()
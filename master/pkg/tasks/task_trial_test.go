@@ -0,0 +1,47 @@
+package tasks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func TestTrialSpecLaunchCommandSlots(t *testing.T) {
+	require.NoError(t, etc.SetRootPath("../../static/srv/"))
+	//nolint:exhaustruct
+	config := schemas.WithDefaults(expconf.ExperimentConfig{
+		RawEntrypoint: &expconf.EntrypointV0{RawEntrypoint: "python3 train.py"},
+		RawLaunchLayer: &expconf.LaunchLayerConfigV0{
+			RawType: expconf.TorchDistributedLaunchLayer,
+			RawArgs: []string{"--nproc-per-node={slots_per_trial}"},
+		},
+		RawResources: &expconf.ResourcesConfigV0{RawSlotsPerTrial: ptrs.Ptr(8)},
+		RawCheckpointStorage: &expconf.CheckpointStorageConfigV0{
+			RawSharedFSConfig: &expconf.SharedFSConfigV0{
+				RawHostPath: ptrs.Ptr("/tmp"),
+			},
+		},
+	})
+	// An elastic trial resized to fewer slots than its config's slots_per_trial launches with
+	// the slots it is allocated.
+	//nolint:exhaustruct
+	spec := TrialSpec{
+		Base:             TaskSpec{AgentUserGroup: &model.AgentUserGroup{}},
+		ExperimentConfig: config,
+		Slots:            2,
+	}
+
+	var cmd []string
+	require.NoError(t, json.Unmarshal([]byte(spec.ToTaskSpec().ExtraEnvVars["DET_LAUNCH_COMMAND"]), &cmd))
+	require.Equal(t, []string{
+		"python3", "-m", "determined.launch.torch_distributed", "--nproc-per-node=2", "--",
+		"sh", "-c", "python3 train.py",
+	}, cmd)
+}
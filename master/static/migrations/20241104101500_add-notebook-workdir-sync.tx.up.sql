@@ -0,0 +1,15 @@
+ALTER TABLE workspaces
+    ADD COLUMN notebook_workdir_sync boolean NOT NULL DEFAULT false;
+
+CREATE TABLE notebook_workdir_syncs (
+  id SERIAL PRIMARY KEY,
+  workspace_id INT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  storage_prefix TEXT NOT NULL,
+  last_task_id TEXT REFERENCES tasks(task_id) ON DELETE SET NULL,
+  last_launched_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  last_synced_at TIMESTAMP with time zone DEFAULT NULL,
+  UNIQUE (user_id, workspace_id)
+);
+
+CREATE INDEX ix_notebook_workdir_syncs_task ON public.notebook_workdir_syncs USING btree (last_task_id);
@@ -0,0 +1,11 @@
+CREATE TYPE proxy_share_access AS ENUM ('READ_ONLY', 'FULL');
+
+CREATE TABLE task_proxy_shares (
+  id SERIAL PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+  user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  access proxy_share_access NOT NULL,
+  granted_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  UNIQUE (task_id, user_id)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE task_outputs (
+  id SERIAL PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+  path TEXT NOT NULL,
+  storage_path TEXT NOT NULL,
+  size_bytes BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  UNIQUE (task_id, path)
+);
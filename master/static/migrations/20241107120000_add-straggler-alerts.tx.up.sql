@@ -0,0 +1,13 @@
+CREATE TABLE straggler_alerts (
+  id SERIAL PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+  allocation_id TEXT NOT NULL,
+  rank INT NOT NULL,
+  agent_id TEXT NOT NULL,
+  slowdown DOUBLE PRECISION NOT NULL,
+  windows INT NOT NULL,
+  excluded BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_straggler_alerts_task_id ON straggler_alerts(task_id);
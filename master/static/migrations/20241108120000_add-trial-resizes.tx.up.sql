@@ -0,0 +1,10 @@
+CREATE TABLE trial_resizes (
+  id SERIAL PRIMARY KEY,
+  trial_id INT NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+  from_slots INT NOT NULL,
+  to_slots INT NOT NULL,
+  reason TEXT NOT NULL,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_trial_resizes_trial_id ON trial_resizes(trial_id);
@@ -0,0 +1,5 @@
+CREATE TABLE workspace_storage_quotas (
+  workspace_id INT PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+  quota_bytes BIGINT NOT NULL,
+  exceeded_at TIMESTAMP with time zone DEFAULT NULL
+);
@@ -0,0 +1,5 @@
+ALTER TABLE workspace_storage_quotas RENAME COLUMN quota_bytes TO soft_quota_bytes;
+ALTER TABLE workspace_storage_quotas
+    ALTER COLUMN soft_quota_bytes DROP NOT NULL,
+    ADD COLUMN hard_quota_bytes BIGINT DEFAULT NULL,
+    ADD COLUMN override_until TIMESTAMP with time zone DEFAULT NULL;
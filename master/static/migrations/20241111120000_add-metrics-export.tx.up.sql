@@ -0,0 +1,36 @@
+CREATE TYPE metrics_export_type AS ENUM ('mlflow', 'wandb');
+
+CREATE TABLE metrics_export_destinations (
+  id SERIAL PRIMARY KEY,
+  workspace_id INT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+  type metrics_export_type NOT NULL,
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  api_key TEXT NOT NULL DEFAULT '',
+  project TEXT NOT NULL DEFAULT '',
+  entity TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  UNIQUE (workspace_id, name)
+);
+
+CREATE TABLE metrics_export_queue (
+  id BIGSERIAL PRIMARY KEY,
+  destination_id INT NOT NULL REFERENCES metrics_export_destinations(id) ON DELETE CASCADE,
+  trial_id INT NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  last_error TEXT
+);
+
+CREATE INDEX ix_metrics_export_queue_next_attempt_at ON metrics_export_queue(next_attempt_at);
+CREATE INDEX ix_metrics_export_queue_destination_id ON metrics_export_queue(destination_id);
+
+CREATE TABLE metrics_export_runs (
+  destination_id INT NOT NULL REFERENCES metrics_export_destinations(id) ON DELETE CASCADE,
+  trial_id INT NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+  external_run_id TEXT NOT NULL,
+  history_offset INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (destination_id, trial_id)
+);
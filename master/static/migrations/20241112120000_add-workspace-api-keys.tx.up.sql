@@ -0,0 +1,13 @@
+CREATE TABLE workspace_api_keys (
+  id SERIAL PRIMARY KEY,
+  workspace_id INT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  key_prefix TEXT NOT NULL,
+  key_hash BYTEA NOT NULL UNIQUE,
+  created_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP with time zone,
+  last_used_at TIMESTAMP with time zone,
+  revoked_at TIMESTAMP with time zone,
+  UNIQUE (workspace_id, name)
+);
@@ -0,0 +1,20 @@
+CREATE TABLE announcements (
+  id SERIAL PRIMARY KEY,
+  message TEXT NOT NULL,
+  severity TEXT NOT NULL DEFAULT 'info' CHECK (severity IN ('info', 'warning', 'critical')),
+  start_time TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  end_time TIMESTAMP with time zone,
+  created_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE maintenance_windows (
+  id SERIAL PRIMARY KEY,
+  message TEXT NOT NULL,
+  start_time TIMESTAMP with time zone NOT NULL,
+  end_time TIMESTAMP with time zone NOT NULL,
+  block_submissions BOOLEAN NOT NULL DEFAULT false,
+  created_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  CHECK (end_time > start_time)
+);
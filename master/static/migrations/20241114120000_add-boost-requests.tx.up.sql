@@ -0,0 +1,24 @@
+CREATE TABLE boost_requests (
+  id SERIAL PRIMARY KEY,
+  experiment_id INT NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('priority', 'max_slots')),
+  value INT NOT NULL,
+  duration_seconds INT NOT NULL CHECK (duration_seconds > 0),
+  reason TEXT NOT NULL,
+  state TEXT NOT NULL DEFAULT 'pending'
+    CHECK (state IN ('pending', 'approved', 'denied', 'expired')),
+  requested_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  reviewed_by INT REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMP with time zone,
+  review_comment TEXT,
+  expires_at TIMESTAMP with time zone,
+  original_value INT
+);
+
+CREATE INDEX ix_boost_requests_state ON boost_requests (state);
+
+-- An experiment can only have one boost of each kind applied at a time, so that each one can be
+-- reverted to the value it replaced.
+CREATE UNIQUE INDEX ix_boost_requests_approved ON boost_requests (experiment_id, kind)
+  WHERE state = 'approved';
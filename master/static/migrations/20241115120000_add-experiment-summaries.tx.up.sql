@@ -0,0 +1,33 @@
+-- Summaries of experiments and of the experiments in each project, kept up to date by triggers in
+-- views_and_triggers/up/experiments.sql so list endpoints and project cards don't have to aggregate
+-- trials and experiments on every read.
+CREATE TABLE experiment_summaries (
+    experiment_id integer PRIMARY KEY REFERENCES experiments(id) ON DELETE CASCADE,
+    num_trials integer NOT NULL DEFAULT 0,
+    best_trial_searcher_metric double precision
+);
+
+CREATE TABLE project_experiment_summaries (
+    project_id integer PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    num_experiments integer NOT NULL DEFAULT 0,
+    num_active_experiments integer NOT NULL DEFAULT 0,
+    last_experiment_started_at timestamptz
+);
+
+INSERT INTO experiment_summaries (experiment_id, num_trials, best_trial_searcher_metric)
+SELECT
+    e.id,
+    (SELECT COUNT(*) FROM runs AS r WHERE r.experiment_id = e.id),
+    (SELECT r.searcher_metric_value FROM runs AS r WHERE r.id = e.best_trial_id)
+FROM experiments AS e;
+
+INSERT INTO project_experiment_summaries (
+    project_id, num_experiments, num_active_experiments, last_experiment_started_at
+)
+SELECT
+    project_id,
+    COUNT(*),
+    COUNT(*) FILTER (WHERE state = 'ACTIVE'),
+    MAX(start_time)
+FROM experiments
+GROUP BY project_id;
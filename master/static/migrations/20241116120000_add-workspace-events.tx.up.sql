@@ -0,0 +1,16 @@
+CREATE TABLE workspace_events (
+    id bigserial PRIMARY KEY,
+    workspace_id integer NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    kind text NOT NULL CHECK (kind IN (
+        'experiment_created', 'experiment_completed', 'experiment_failed', 'model_registered',
+        'member_added'
+    )),
+    actor_id integer REFERENCES users(id) ON DELETE SET NULL,
+    -- Events outlive the experiments and models they are about, so these aren't foreign keys.
+    experiment_id integer,
+    model_id integer,
+    details jsonb NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_workspace_events_workspace_id_id ON workspace_events (workspace_id, id DESC);
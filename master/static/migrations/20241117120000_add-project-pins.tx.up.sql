@@ -0,0 +1,23 @@
+-- Users order their pinned workspaces and projects, and pin projects as well as workspaces.
+ALTER TABLE workspace_pins ADD COLUMN position integer;
+
+-- Keep the order pinned workspaces were listed in before, newest first.
+UPDATE workspace_pins AS p SET position = o.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC, id DESC) AS position
+    FROM workspace_pins
+) AS o
+WHERE p.id = o.id;
+
+ALTER TABLE workspace_pins ALTER COLUMN position SET NOT NULL;
+
+CREATE TABLE project_pins (
+    id serial PRIMARY KEY,
+    project_id integer NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, project_id)
+);
+
+CREATE INDEX ix_project_pins_project_id ON project_pins USING btree (project_id);
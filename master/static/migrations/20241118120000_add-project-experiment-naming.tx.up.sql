@@ -0,0 +1,10 @@
+-- How experiments in each project are named: a template for experiments whose config omits a name,
+-- and whether names must be unique in the project.
+CREATE TABLE project_experiment_naming (
+    project_id integer PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    unique_names boolean NOT NULL DEFAULT false,
+    name_template text,
+    next_seq integer NOT NULL DEFAULT 1
+);
+
+CREATE INDEX ix_experiments_project_id_name ON experiments USING btree (project_id, (config->>'name'));
@@ -0,0 +1,5 @@
+-- The hash of each experiment's canonical effective config, computed by the master when the
+-- experiment is created. Experiments created before this migration have no hash.
+ALTER TABLE experiments ADD COLUMN config_hash text;
+
+CREATE INDEX ix_experiments_config_hash ON experiments USING btree (config_hash);
@@ -0,0 +1,11 @@
+-- The hash of each experiment's model definition, computed by the master when the experiment is
+-- created. Together with the config hash it identifies experiments that run the same thing.
+ALTER TABLE experiments ADD COLUMN model_definition_hash text;
+
+CREATE INDEX ix_experiments_config_hash_model_definition_hash
+    ON experiments USING btree (config_hash, model_definition_hash);
+
+-- What happens when an experiment is submitted to a workspace that already has an identical one.
+-- NULL turns detection off.
+ALTER TABLE workspaces ADD COLUMN duplicate_experiment_policy text
+    CHECK (duplicate_experiment_policy IN ('warn', 'block'));
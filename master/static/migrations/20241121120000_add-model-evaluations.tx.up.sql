@@ -0,0 +1,52 @@
+-- Evaluation experiments that run automatically against a registry model's versions, and the
+-- evaluations they ran.
+CREATE TABLE model_evaluation_policies (
+    id serial PRIMARY KEY,
+    model_id integer NOT NULL REFERENCES models(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    -- The config of the evaluation experiment, as YAML.
+    config text NOT NULL,
+    -- The gzipped tar of the evaluation experiment's model definition, if it has one.
+    model_definition bytea,
+    -- The project evaluation experiments are created in.
+    project_id integer NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    -- Evaluate each new version when it is registered.
+    on_register boolean NOT NULL DEFAULT true,
+    -- Evaluate the latest version, or the latest with schedule_label, this often, as a Go
+    -- duration like 24h.
+    schedule_interval text,
+    schedule_label text,
+    -- The validation metric that is compared between evaluations.
+    metric text NOT NULL,
+    smaller_is_better boolean NOT NULL DEFAULT true,
+    -- How much worse, relative to the last evaluation, counts as a regression.
+    regression_tolerance double precision NOT NULL DEFAULT 0,
+    owner_id integer NOT NULL REFERENCES users(id),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    last_scheduled_at timestamptz,
+    UNIQUE (model_id, name)
+);
+
+CREATE TABLE model_evaluations (
+    id serial PRIMARY KEY,
+    policy_id integer NOT NULL REFERENCES model_evaluation_policies(id) ON DELETE CASCADE,
+    model_version_id integer NOT NULL REFERENCES model_versions(id) ON DELETE CASCADE,
+    experiment_id integer REFERENCES experiments(id) ON DELETE SET NULL,
+    trigger text NOT NULL CHECK (trigger IN ('register', 'schedule', 'manual')),
+    state text NOT NULL CHECK (state IN ('running', 'completed', 'failed')),
+    error text,
+    -- The latest validation metrics the evaluation experiment reported.
+    metrics jsonb,
+    metric_value double precision,
+    -- The evaluation the metric was compared with, and whether it regressed from it.
+    baseline_id integer REFERENCES model_evaluations(id) ON DELETE SET NULL,
+    regressed boolean,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    ended_at timestamptz
+);
+
+CREATE INDEX ix_model_evaluations_policy_id ON model_evaluations USING btree (policy_id);
+CREATE INDEX ix_model_evaluations_model_version_id
+    ON model_evaluations USING btree (model_version_id);
+CREATE UNIQUE INDEX ix_model_evaluations_experiment_id
+    ON model_evaluations USING btree (experiment_id);
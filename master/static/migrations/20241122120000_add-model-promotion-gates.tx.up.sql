@@ -0,0 +1,27 @@
+-- Metric gates that versions of a registry model must pass, in their latest evaluation by an
+-- evaluation policy, to be promoted to production, and the promotions that overrode them.
+CREATE TABLE model_promotion_gates (
+    id serial PRIMARY KEY,
+    model_id integer NOT NULL REFERENCES models(id) ON DELETE CASCADE,
+    policy_id integer NOT NULL REFERENCES model_evaluation_policies(id) ON DELETE CASCADE,
+    metric text NOT NULL,
+    comparison text NOT NULL CHECK (comparison IN ('>=', '>', '<=', '<')),
+    threshold double precision NOT NULL,
+    created_by integer NOT NULL REFERENCES users(id),
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ix_model_promotion_gates_model_id ON model_promotion_gates USING btree (model_id);
+
+CREATE TABLE model_promotion_overrides (
+    id serial PRIMARY KEY,
+    model_version_id integer NOT NULL REFERENCES model_versions(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id),
+    reason text NOT NULL,
+    -- The gates the version didn't pass, and why, when it was promoted.
+    unmet_gates jsonb NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ix_model_promotion_overrides_model_version_id
+    ON model_promotion_overrides USING btree (model_version_id);
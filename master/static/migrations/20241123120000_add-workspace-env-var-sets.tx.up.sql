@@ -0,0 +1,10 @@
+CREATE TABLE workspace_env_var_sets (
+  id SERIAL PRIMARY KEY,
+  workspace_id INT NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  variables JSONB NOT NULL DEFAULT '{}',
+  description TEXT NOT NULL DEFAULT '',
+  updated_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  updated_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  UNIQUE (workspace_id, name)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE trial_failures (
+  id SERIAL PRIMARY KEY,
+  trial_id INT NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+  allocation_id TEXT NOT NULL,
+  class TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  exit_code INT NULL,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_trial_failures_trial_id ON trial_failures(trial_id);
@@ -0,0 +1,11 @@
+CREATE TABLE stall_alerts (
+  id SERIAL PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+  allocation_id TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  restarted BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  recovered_at TIMESTAMP with time zone NULL
+);
+
+CREATE INDEX ix_stall_alerts_task_id ON stall_alerts(task_id);
@@ -0,0 +1,15 @@
+CREATE TABLE batch_size_probes (
+  task_id TEXT PRIMARY KEY REFERENCES tasks(task_id) ON DELETE CASCADE,
+  experiment_id INTEGER NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+  batch_size INTEGER NOT NULL,
+  fits BOOLEAN NULL,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_batch_size_probes_experiment_id ON batch_size_probes(experiment_id);
+
+CREATE TABLE tuned_batch_sizes (
+  experiment_id INTEGER PRIMARY KEY REFERENCES experiments(id) ON DELETE CASCADE,
+  batch_size INTEGER NOT NULL,
+  tuned_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
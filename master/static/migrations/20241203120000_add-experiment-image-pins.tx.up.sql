@@ -0,0 +1,8 @@
+CREATE TABLE experiment_image_pins (
+  experiment_id INTEGER NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+  device_type TEXT NOT NULL,
+  image TEXT NOT NULL,
+  pinned_image TEXT NOT NULL,
+  pinned_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (experiment_id, device_type)
+);
@@ -0,0 +1,56 @@
+CREATE TABLE storage_backend_sftp (
+  id integer PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+  host         TEXT NOT NULL,
+  port         integer NOT NULL,
+  username     TEXT NOT NULL,
+  password     TEXT,
+  private_key  TEXT,
+  storage_path TEXT NOT NULL,
+  CONSTRAINT sftp_dont_give_all CHECK (
+    NOT (password IS NOT NULL AND private_key IS NOT NULL)
+  ),
+  CONSTRAINT sftp_reserved_value CHECK (
+    password != 'DeterminedReservedNullUniqueValue' AND
+    private_key != 'DeterminedReservedNullUniqueValue'
+  )
+);
+
+CREATE UNIQUE INDEX ix_storage_backend_unique_sftp ON storage_backend_sftp (
+  host,
+  port,
+  username,
+  COALESCE(password, 'DeterminedReservedNullUniqueValue'),
+  COALESCE(private_key, 'DeterminedReservedNullUniqueValue'),
+  storage_path
+);
+
+CREATE TABLE storage_backend_hdfs (
+  id integer PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
+  url          TEXT NOT NULL,
+  username     TEXT,
+  storage_path TEXT NOT NULL,
+  CONSTRAINT hdfs_reserved_value CHECK (
+    username != 'DeterminedReservedNullUniqueValue'
+  )
+);
+
+CREATE UNIQUE INDEX ix_storage_backend_unique_hdfs ON storage_backend_hdfs (
+  url,
+  COALESCE(username, 'DeterminedReservedNullUniqueValue'),
+  storage_path
+);
+
+ALTER TABLE storage_backend
+  ADD COLUMN sftp_id integer UNIQUE REFERENCES storage_backend_sftp(id) ON DELETE CASCADE,
+  ADD COLUMN hdfs_id integer UNIQUE REFERENCES storage_backend_hdfs(id) ON DELETE CASCADE,
+  DROP CONSTRAINT check_one_not_null,
+  ADD CONSTRAINT check_one_not_null
+    CHECK (
+      (shared_fs_id IS NOT NULL)::integer +
+      (s3_id IS NOT NULL)::integer +
+      (gcs_id IS NOT NULL)::integer +
+      (azure_id IS NOT NULL)::integer +
+      (directory_id IS NOT NULL)::integer +
+      (sftp_id IS NOT NULL)::integer +
+      (hdfs_id IS NOT NULL)::integer = 1
+    );
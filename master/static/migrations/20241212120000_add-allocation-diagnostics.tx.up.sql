@@ -0,0 +1,11 @@
+CREATE TABLE allocation_diagnostics (
+  id SERIAL PRIMARY KEY,
+  task_id TEXT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+  allocation_id TEXT NOT NULL,
+  exit_reason TEXT NOT NULL,
+  resources JSONB NOT NULL DEFAULT '[]',
+  nccl_env JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_allocation_diagnostics_task_id ON allocation_diagnostics(task_id);
@@ -0,0 +1,10 @@
+CREATE TABLE experiment_snapshot_deltas (
+  id SERIAL PRIMARY KEY,
+  experiment_id integer NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+  content JSONB NOT NULL,
+  version integer NOT NULL,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_experiment_snapshot_deltas_experiment_id
+  ON experiment_snapshot_deltas(experiment_id, id);
@@ -0,0 +1,19 @@
+CREATE TABLE allocation_summaries (
+  day DATE NOT NULL,
+  resource_pool TEXT NOT NULL,
+  task_type TEXT NOT NULL,
+  allocations INT NOT NULL,
+  slot_seconds DOUBLE PRECISION NOT NULL,
+  PRIMARY KEY (day, resource_pool, task_type)
+);
+
+CREATE TABLE task_stats_summaries (
+  day DATE NOT NULL,
+  event_type stats_type NOT NULL,
+  events INT NOT NULL,
+  total_seconds DOUBLE PRECISION NOT NULL,
+  max_seconds DOUBLE PRECISION NOT NULL,
+  PRIMARY KEY (day, event_type)
+);
+
+CREATE INDEX ix_allocations_end_time ON allocations(end_time);
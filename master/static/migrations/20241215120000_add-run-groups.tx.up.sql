@@ -0,0 +1,16 @@
+CREATE TABLE run_groups (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  owner_id INT NOT NULL REFERENCES users(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE run_group_experiments (
+  group_id INT NOT NULL REFERENCES run_groups(id) ON DELETE CASCADE,
+  experiment_id INT NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+  added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (group_id, experiment_id)
+);
+
+CREATE INDEX ix_run_group_experiments_experiment_id ON run_group_experiments(experiment_id);
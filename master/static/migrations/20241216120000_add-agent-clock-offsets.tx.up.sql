@@ -0,0 +1,5 @@
+CREATE TABLE agent_clock_offsets (
+  agent_id TEXT PRIMARY KEY,
+  offset_ms BIGINT NOT NULL,
+  measured_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,8 @@
+-- Preferences that clients such as the WebUI save for users, so they follow users across devices.
+CREATE TABLE user_preferences (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key text NOT NULL,
+    value jsonb NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key)
+);
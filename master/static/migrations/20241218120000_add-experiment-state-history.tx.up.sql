@@ -0,0 +1,13 @@
+-- Every change of an experiment's state, whichever code path made it. The master sets
+-- determined.experiment_state_reason in the transaction of a transition to record why.
+CREATE TABLE experiment_state_history (
+    id bigserial PRIMARY KEY,
+    experiment_id integer NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    from_state public.experiment_state,
+    to_state public.experiment_state NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    transitioned_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_experiment_state_history_experiment_id
+    ON experiment_state_history USING btree (experiment_id, id);
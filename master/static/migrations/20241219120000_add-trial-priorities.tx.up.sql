@@ -0,0 +1,7 @@
+-- The priority of a trial among the trials of its experiment. Trials without a row have priority 0.
+CREATE TABLE trial_priorities (
+  trial_id INT PRIMARY KEY REFERENCES runs(id) ON DELETE CASCADE,
+  priority INT NOT NULL,
+  set_by TEXT NOT NULL,
+  updated_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,17 @@
+CREATE TABLE resource_pool_reservations (
+  id SERIAL PRIMARY KEY,
+  resource_pool TEXT NOT NULL,
+  workspace_id INT REFERENCES workspaces(id) ON DELETE CASCADE,
+  user_id INT REFERENCES users(id) ON DELETE CASCADE,
+  slot_fraction DOUBLE PRECISION NOT NULL CHECK (slot_fraction > 0 AND slot_fraction <= 1),
+  start_time TIMESTAMP with time zone NOT NULL,
+  end_time TIMESTAMP with time zone NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  created_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  CHECK ((workspace_id IS NULL) != (user_id IS NULL)),
+  CHECK (end_time > start_time)
+);
+
+CREATE INDEX ix_resource_pool_reservations_pool_end_time
+  ON resource_pool_reservations (resource_pool, end_time);
@@ -0,0 +1,10 @@
+CREATE TABLE experiment_reports (
+  id SERIAL PRIMARY KEY,
+  experiment_id INT NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+  format TEXT NOT NULL CHECK (format IN ('html', 'markdown')),
+  content BYTEA NOT NULL,
+  created_by INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_experiment_reports_experiment_id ON experiment_reports (experiment_id);
@@ -0,0 +1,10 @@
+CREATE TABLE metric_metadata (
+  experiment_id INT NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  unit TEXT NOT NULL DEFAULT '',
+  direction TEXT NOT NULL DEFAULT '' CHECK (direction IN ('', 'minimize', 'maximize')),
+  display_name TEXT NOT NULL DEFAULT '',
+  scale TEXT NOT NULL DEFAULT '' CHECK (scale IN ('', 'linear', 'log')),
+  updated_at TIMESTAMP with time zone NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (experiment_id, name)
+);
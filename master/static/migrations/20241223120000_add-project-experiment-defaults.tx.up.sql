@@ -0,0 +1,10 @@
+-- Defaults that experiments created in each project get when their configs omit them, and a
+-- resource pool the project's experiments are forced to run in.
+CREATE TABLE project_experiment_defaults (
+    project_id integer PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    resource_pool text,
+    force_resource_pool boolean NOT NULL DEFAULT false,
+    priority integer CHECK (priority BETWEEN 1 AND 99),
+    checkpoint_policy text CHECK (checkpoint_policy IN ('best', 'all', 'none')),
+    CHECK (NOT force_resource_pool OR resource_pool IS NOT NULL)
+);
@@ -0,0 +1,11 @@
+-- Trials pinned within their experiments, which are listed before the experiments' other trials.
+-- Pins are shared by everyone who can view the experiment.
+CREATE TABLE trial_pins (
+    trial_id integer PRIMARY KEY REFERENCES runs(id) ON DELETE CASCADE,
+    experiment_id integer NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    pinned_by integer REFERENCES users(id) ON DELETE SET NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ix_trial_pins_experiment_id ON trial_pins USING btree (experiment_id, position);
@@ -0,0 +1,7 @@
+-- JSON schemas the metadata of experiments in a project must follow. Experiments keep their
+-- metadata in the metadata key of their config.
+CREATE TABLE project_experiment_metadata_schemas (
+    project_id integer PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    schema jsonb NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT NOW()
+);
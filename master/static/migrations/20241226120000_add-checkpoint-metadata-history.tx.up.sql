@@ -0,0 +1,18 @@
+-- JSON schemas the metadata of the checkpoints of experiments in a project must follow.
+CREATE TABLE project_checkpoint_metadata_schemas (
+    project_id integer PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    schema jsonb NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+-- Every change made to the metadata of a checkpoint after it was reported.
+CREATE TABLE checkpoint_metadata_changes (
+    id serial PRIMARY KEY,
+    checkpoint_uuid uuid NOT NULL REFERENCES checkpoints_v2(uuid) ON DELETE CASCADE,
+    changed_by integer REFERENCES users(id) ON DELETE SET NULL,
+    previous jsonb NOT NULL,
+    metadata jsonb NOT NULL,
+    changed_at timestamptz NOT NULL DEFAULT NOW()
+);
+CREATE INDEX ix_checkpoint_metadata_changes_checkpoint_uuid
+    ON checkpoint_metadata_changes(checkpoint_uuid, id);
@@ -0,0 +1,13 @@
+-- Imports of MLflow experiments, which run in the background after they are requested.
+CREATE TABLE mlflow_imports (
+    id serial PRIMARY KEY,
+    state text NOT NULL,
+    server_url text NOT NULL,
+    mlflow_experiment text NOT NULL,
+    experiment_id integer REFERENCES experiments(id) ON DELETE SET NULL,
+    trials integer NOT NULL DEFAULT 0,
+    error text,
+    created_by integer REFERENCES users(id) ON DELETE SET NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    end_time timestamptz
+);
@@ -0,0 +1,7 @@
+/* Add an RBAC permission for viewing the experiments and jobs of federation peers. */
+INSERT into permissions(id, name, global_only) VALUES
+    (8008, 'view federation', true);
+
+-- ClusterAdmin
+INSERT INTO permission_assignments(permission_id, role_id) VALUES
+    (8008, 1);
@@ -0,0 +1,8 @@
+-- The CORS and Content Security Policy admins set through the API, which replaces the one in the
+-- master config until it is deleted.
+CREATE TABLE http_policy (
+    id boolean PRIMARY KEY DEFAULT true CHECK (id),
+    policy jsonb NOT NULL,
+    updated_by integer REFERENCES users(id) ON DELETE SET NULL,
+    updated_at timestamptz NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+-- Evaluations are recorded before their experiments are created, so that ones that fail to launch
+-- are reported.
+ALTER TABLE model_evaluations DROP CONSTRAINT model_evaluations_state_check;
+ALTER TABLE model_evaluations ADD CONSTRAINT model_evaluations_state_check
+    CHECK (state IN ('launching', 'running', 'completed', 'failed'));
@@ -0,0 +1,16 @@
+-- Who added and removed the promotion gates of registry models, and when.
+CREATE TABLE model_promotion_gate_changes (
+    id serial PRIMARY KEY,
+    model_id integer NOT NULL REFERENCES models(id) ON DELETE CASCADE,
+    gate_id integer NOT NULL,
+    action text NOT NULL CHECK (action IN ('added', 'removed')),
+    policy_id integer NOT NULL,
+    metric text NOT NULL,
+    comparison text NOT NULL,
+    threshold double precision NOT NULL,
+    user_id integer NOT NULL REFERENCES users(id),
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ix_model_promotion_gate_changes_model_id
+    ON model_promotion_gate_changes USING btree (model_id);
@@ -0,0 +1,7 @@
+/* Add an RBAC permission for opting out of the pod security defaults of resource pools. */
+INSERT into permissions(id, name, global_only) VALUES
+    (11008, 'opt out of pod security', false);
+
+-- ClusterAdmin
+INSERT INTO permission_assignments(permission_id, role_id) VALUES
+    (11008, 1);
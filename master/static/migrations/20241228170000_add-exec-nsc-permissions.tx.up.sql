@@ -0,0 +1,13 @@
+/* Add RBAC permissions for exec into notebooks, shells, and commands. */
+INSERT into permissions(id, name, global_only) VALUES
+    (3004, 'exec into notebooks/shells/commands', false),
+    (3005, 'exec into other users'' notebooks/shells/commands', false);
+
+-- Roles that could exec through the update permission keep exec into their own tasks.
+INSERT INTO permission_assignments(permission_id, role_id)
+    SELECT 3004, role_id FROM permission_assignments WHERE permission_id = 3003;
+
+-- ClusterAdmin, WorkspaceAdmin
+INSERT INTO permission_assignments(permission_id, role_id) VALUES
+    (3005, 1),
+    (3005, 2);
@@ -0,0 +1,8 @@
+/* Add an RBAC permission for changing the settings of a workspace's features. */
+INSERT into permissions(id, name, global_only) VALUES
+    (4008, 'set workspace settings', false);
+
+-- ClusterAdmin, WorkspaceAdmin
+INSERT INTO permission_assignments(permission_id, role_id) VALUES
+    (4008, 1),
+    (4008, 2);
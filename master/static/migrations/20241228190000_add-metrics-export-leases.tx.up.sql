@@ -0,0 +1,2 @@
+/* Items are leased while they are sent, so no transaction is held open across requests. */
+ALTER TABLE metrics_export_queue ADD COLUMN leased_until TIMESTAMP with time zone;
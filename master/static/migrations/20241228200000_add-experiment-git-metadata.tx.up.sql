@@ -0,0 +1,4 @@
+-- The git checkout an experiment's model definition was submitted from, as reported by the client
+-- that submitted it. Duplicate experiment detection only matches experiments with the same commit.
+ALTER TABLE experiments ADD COLUMN git_commit text;
+ALTER TABLE experiments ADD COLUMN git_remote text;
@@ -0,0 +1,8 @@
+/* Add an RBAC permission for setting the experiment defaults of projects. */
+INSERT into permissions(id, name, global_only) VALUES
+    (5005, 'set project experiment defaults', false);
+
+-- ClusterAdmin, WorkspaceAdmin
+INSERT INTO permission_assignments(permission_id, role_id) VALUES
+    (5005, 1),
+    (5005, 2);
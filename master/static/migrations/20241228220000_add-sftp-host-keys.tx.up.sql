@@ -0,0 +1,22 @@
+ALTER TABLE storage_backend_sftp
+  ADD COLUMN private_key_passphrase TEXT,
+  ADD COLUMN known_hosts TEXT,
+  ADD COLUMN host_key TEXT,
+  ADD CONSTRAINT sftp_host_key_reserved_value CHECK (
+    private_key_passphrase != 'DeterminedReservedNullUniqueValue' AND
+    known_hosts != 'DeterminedReservedNullUniqueValue' AND
+    host_key != 'DeterminedReservedNullUniqueValue'
+  );
+
+DROP INDEX ix_storage_backend_unique_sftp;
+CREATE UNIQUE INDEX ix_storage_backend_unique_sftp ON storage_backend_sftp (
+  host,
+  port,
+  username,
+  COALESCE(password, 'DeterminedReservedNullUniqueValue'),
+  COALESCE(private_key, 'DeterminedReservedNullUniqueValue'),
+  COALESCE(private_key_passphrase, 'DeterminedReservedNullUniqueValue'),
+  COALESCE(known_hosts, 'DeterminedReservedNullUniqueValue'),
+  COALESCE(host_key, 'DeterminedReservedNullUniqueValue'),
+  storage_path
+);
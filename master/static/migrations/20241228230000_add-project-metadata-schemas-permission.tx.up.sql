@@ -0,0 +1,11 @@
+/* Add an RBAC permission for setting the metadata schemas of projects. */
+INSERT into permissions(id, name, global_only) VALUES
+    (5006, 'set project metadata schemas', false);
+
+-- ClusterAdmin, WorkspaceAdmin, Editor, EditorRestricted: the roles that could update projects,
+-- which setting metadata schemas required before.
+INSERT INTO permission_assignments(permission_id, role_id) VALUES
+    (5006, 1),
+    (5006, 2),
+    (5006, 5),
+    (5006, 7);
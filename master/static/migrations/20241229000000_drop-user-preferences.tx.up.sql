@@ -0,0 +1,6 @@
+-- User preferences are saved as user web settings instead.
+INSERT INTO user_web_settings (user_id, key, storage_path, value)
+SELECT user_id, key, '', value FROM user_preferences
+ON CONFLICT DO NOTHING;
+
+DROP TABLE user_preferences;
@@ -2,7 +2,14 @@ SELECT
     t.task_id AS task,
     BOOL_OR(CASE WHEN a.state = 'PULLING' THEN true ELSE false END) AS pulling,
     BOOL_OR(CASE WHEN a.state = 'STARTING' THEN true ELSE false END) AS starting,
-    BOOL_OR(CASE WHEN a.state = 'RUNNING' THEN true ELSE false END) AS running
+    BOOL_OR(CASE WHEN a.state = 'RUNNING' THEN true ELSE false END) AS running,
+    (
+        SELECT s.reason FROM stall_alerts s
+        JOIN allocations sa ON sa.allocation_id = s.allocation_id
+        WHERE s.task_id = t.task_id AND s.recovered_at IS NULL AND sa.end_time IS NULL
+        ORDER BY s.id DESC
+        LIMIT 1
+    ) AS stall_reason
 FROM tasks t
 JOIN allocations a ON a.task_id = t.task_id
 WHERE t.task_id IN (SELECT UNNEST(STRING_TO_ARRAY($1, ',')))
@@ -16,8 +16,30 @@ test -f "${TCD_STARTUP_HOOK}" && source "${TCD_STARTUP_HOOK}"
 test -f "${STARTUP_HOOK}" && source "${STARTUP_HOOK}"
 set +x
 
+if [ -z "$DET_COMMAND_OUTPUTS" ]; then
+    if [ "$#" -eq 1 ]; then
+        exec /bin/sh -c "$@"
+    else
+        exec "$@"
+    fi
+fi
+
+# Declared outputs are uploaded once the command exits, so run it as a child instead of
+# replacing this shell, and exit with the command's status regardless of the upload's.
+set +e
 if [ "$#" -eq 1 ]; then
-    exec /bin/sh -c "$@"
+    /bin/sh -c "$@" &
 else
-    exec "$@"
+    "$@" &
+fi
+child=$!
+trap 'kill -TERM $child 2>/dev/null' TERM INT
+wait $child
+exit_code=$?
+# The first wait returns early when a signal is trapped; wait again for the command to exit.
+if kill -0 $child 2>/dev/null; then
+    wait $child
+    exit_code=$?
 fi
+"$DET_PYTHON_EXECUTABLE" -m determined.exec.upload_outputs
+exit $exit_code
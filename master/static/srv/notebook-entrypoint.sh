@@ -21,6 +21,14 @@ set +x
 
 "$DET_PYTHON_EXECUTABLE" /run/determined/jupyter/check_idle.py &
 
+# Restore the working directory from the last notebook in this workspace and keep syncing it back,
+# so work survives the notebook being killed.
+if [ -n "$DET_NOTEBOOK_SYNC_PREFIX" ]; then
+    "$DET_PYTHON_EXECUTABLE" -m determined.exec.notebook_sync restore
+    "$DET_PYTHON_EXECUTABLE" -m determined.exec.notebook_sync watch &
+    trap '"$DET_PYTHON_EXECUTABLE" -m determined.exec.notebook_sync upload' EXIT
+fi
+
 JUPYTER_LAB_LOG_FORMAT="%(levelname)s: [%(name)s] %(message)s"
 READINESS_REGEX='^.*Jupyter Server .* is running.*$'
 
@@ -23,6 +23,7 @@ WITH searcher_info AS (
         t.checkpoint_size,
         coalesce(t.end_time, now()) - t.start_time AS duration,
         t.total_batches AS total_batches_processed,
+        (SELECT p.position FROM trial_pins p WHERE p.trial_id = t.id) AS pin_position,
         (
            CASE WHEN t.best_validation_id IS NOT NULL THEN
                 (SELECT searcher_info.sign * (v.metrics->'validation_metrics'->>searcher_info.metric_name)::float8
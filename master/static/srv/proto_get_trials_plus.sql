@@ -142,9 +142,12 @@ SELECT
   -- `restart` count is incremented before `restart <= max_restarts` stop restart check,
   -- so trials in terminal state have restarts = max + 1
   LEAST(t.restarts, max_restarts) as restarts,
-  t.metadata as metadata
+  t.metadata as metadata,
+  tp.trial_id IS NOT NULL AS pinned,
+  tp.position AS pin_position
 FROM searcher_info
   INNER JOIN trials t ON t.id = searcher_info.trial_id
+  LEFT JOIN trial_pins tp ON tp.trial_id = t.id
   LEFT JOIN best_validation bv ON bv.trial_id = searcher_info.trial_id
   LEFT JOIN latest_validation lv ON lv.trial_id = searcher_info.trial_id
   LEFT JOIN best_checkpoint bc ON bc.trial_id = searcher_info.trial_id
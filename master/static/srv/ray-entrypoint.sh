@@ -0,0 +1,23 @@
+#!/usr/bin/env bash
+
+## ray cluster entry point
+
+source /run/determined/task-setup.sh
+
+set -e
+
+STARTUP_HOOK="startup-hook.sh"
+
+# Ray itself must be installed in the image, in addition to the "determined*.whl" that contains
+# the "determined/exec/ray_cluster.py" script that starts the Ray nodes.
+"$DET_PYTHON_EXECUTABLE" -m determined.exec.prep_container --proxy --download_context_directory
+
+set -x
+test -f "${TCD_STARTUP_HOOK}" && source "${TCD_STARTUP_HOOK}"
+test -f "${STARTUP_HOOK}" && source "${STARTUP_HOOK}"
+set +x
+
+# Do rendezvous last, so the workers learn the address of the head node.
+"$DET_PYTHON_EXECUTABLE" -m determined.exec.prep_container --rendezvous
+
+exec "$DET_PYTHON_EXECUTABLE" -m determined.exec.ray_cluster "$@"
@@ -18,4 +18,11 @@ fi
 ship_logs="$1"
 shift
 
+# Harness-free images may not have python at all. Their output then goes only to the container's
+# own logs, and the task is expected to report whatever it needs to through the master's API.
+if [ -n "$DET_HARNESS_FREE" ] && ! "$DET_PYTHON_EXECUTABLE" --version >/dev/null 2>&1; then
+    echo "warning: $DET_PYTHON_EXECUTABLE not found, task logs will not be shipped to the master" >&2
+    exec "$@"
+fi
+
 exec "$DET_PYTHON_EXECUTABLE" "$ship_logs" "$@"
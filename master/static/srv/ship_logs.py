@@ -22,7 +22,9 @@ ship_logs.py will read environment variables set by the master to obtain its con
 isn't intended to be useful in any non-managed environments.
 """
 
+import collections
 import datetime
+import gzip
 import io
 import json
 import logging
@@ -34,12 +36,25 @@ import socket
 import ssl
 import subprocess
 import sys
+import tempfile
 import threading
 import time
 import traceback
 import urllib.error
 import urllib.request
-from typing import Any, Dict, Iterator, List, NamedTuple, Optional, cast
+from typing import (
+    Any,
+    Callable,
+    Deque,
+    Dict,
+    Iterator,
+    List,
+    NamedTuple,
+    Optional,
+    Tuple,
+    Union,
+    cast,
+)
 
 # Duplicated from determined/__init__.py.  It's nice to keep them in sync.
 LOG_FORMAT = "%(levelname)s: [%(process)s] %(name)s: %(message)s"
@@ -70,9 +85,24 @@ SHIPPER_FLUSH_INTERVAL = 1  # How often to make API calls
 # Full jitter time on encountering an API exception.
 SHIPPER_FAILURE_BACKOFF_SECONDS = 1
 
-# Max size of the log buffer before forcing a flush.
+# Max size of the log buffer before forcing a flush, until the master grants credit for a size.
 LOG_BATCH_MAX_SIZE = 1000
 
+# Path of the master endpoint that takes compressed, sequenced batches of logs.
+LOGS_PATH = "/task-logs/v2"
+
+# Timeout of each API call, so a master that stopped responding counts as unreachable.
+SHIPPER_REQUEST_TIMEOUT = 60
+
+# Delays before retrying spooled batches after consecutive failures; the last one repeats.
+SHIPPER_RETRY_BACKOFFS = [1, 5, 10, 15, 15, 15, 60]
+
+# How long the shipper tries to reach the master while its spool is full, before giving up.
+SHIPPER_GIVE_UP_SECONDS = 600
+
+# Default max size of the batches spooled to disk while the master can't take them.
+SPOOL_MAX_BYTES = 256 << 20
+
 # Max size of the shipping queue before we start to apply backpressure by blocking sends. We would
 # only hit this if we got underwater by three full batches while trying to ship a batch.
 SHIP_QUEUE_MAX_SIZE = 3 * LOG_BATCH_MAX_SIZE
@@ -223,6 +253,176 @@ class Collector(threading.Thread):
             self.logq.put(log)
 
 
+class LogLimiter:
+    """
+    LogLimiter protects the master's log backend from runaway print loops.
+
+    When dedup is set, it collapses identical consecutive lines into the first line followed by a
+    marker counting its repeats.  When lines_per_second is set, it limits the rate lines are
+    shipped at with a token bucket of size burst, and replaces dropped lines with a truncation
+    marker.  Truncation markers themselves are never dropped.
+    """
+
+    def __init__(self, dedup: bool = False, lines_per_second: int = 0, burst: int = 0) -> None:
+        self.dedup = dedup
+        self.lines_per_second = lines_per_second
+        self.burst = max(burst, lines_per_second)
+        self.tokens = float(self.burst)
+        self.refilled_at = time.time()
+
+        # The last line shipped, and how many times it has repeated since.
+        self.last: Optional[Dict[str, Any]] = None
+        self.repeats = 0
+        # The last line dropped, and how many lines have been dropped since the last marker.
+        self.last_dropped: Optional[Dict[str, Any]] = None
+        self.dropped = 0
+
+    def add(self, log: Dict[str, Any]) -> List[Dict[str, Any]]:
+        """
+        Add a collected log, returning the logs that are ready to ship.
+        """
+        if self.dedup and self.last is not None and self._same(self.last, log):
+            self.repeats += 1
+            return []
+
+        out = self._repeats_marker()
+        shipped = self._limit(log)
+        if self.dedup:
+            # Repeats of a dropped line are rate limited like any other line.
+            self.last = log if shipped else None
+        return out + shipped
+
+    def flush(self) -> List[Dict[str, Any]]:
+        """
+        Return markers for lines repeated or dropped since the last call, before shipping a batch.
+        """
+        return self._repeats_marker() + self._dropped_marker()
+
+    @staticmethod
+    def _same(a: Dict[str, Any], b: Dict[str, Any]) -> bool:
+        return all(a.get(k) == b.get(k) for k in ("log", "stdtype", "rank_id", "level"))
+
+    def _limit(self, log: Dict[str, Any], count: int = 1) -> List[Dict[str, Any]]:
+        """
+        Return log if the rate limit allows it; count is how many lines it stands for if dropped.
+        """
+        if not self.lines_per_second:
+            return [log]
+
+        now = time.time()
+        self.tokens = min(
+            float(self.burst), self.tokens + (now - self.refilled_at) * self.lines_per_second
+        )
+        self.refilled_at = now
+        if self.tokens < 1:
+            self.last_dropped = log
+            self.dropped += count
+            return []
+
+        self.tokens -= 1
+        return self._dropped_marker() + [log]
+
+    def _repeats_marker(self) -> List[Dict[str, Any]]:
+        if not self.repeats:
+            return []
+        assert self.last is not None
+        repeats, self.repeats = self.repeats, 0
+        marker = self._marker(self.last, f"[previous line repeated {repeats} times]\n")
+        # Lines collapsed into a marker still count against the rate limit.
+        return self._limit(marker, count=repeats)
+
+    def _dropped_marker(self) -> List[Dict[str, Any]]:
+        if not self.dropped:
+            return []
+        assert self.last_dropped is not None
+        marker = self._marker(
+            self.last_dropped,
+            f"[{self.dropped} lines dropped by the log rate limit of {self.lines_per_second} "
+            "lines per second]\n",
+        )
+        marker["level"] = "LOG_LEVEL_WARNING"
+        self.dropped = 0
+        self.last_dropped = None
+        return [marker]
+
+    @staticmethod
+    def _marker(log: Dict[str, Any], msg: str) -> Dict[str, Any]:
+        now = datetime.datetime.now(datetime.timezone.utc).isoformat()
+        return {**log, "timestamp": now, "log": msg}
+
+
+class MasterBusy(Exception):
+    """
+    MasterBusy is raised when the master can't take a batch yet, and asks to retry it later.
+    """
+
+    def __init__(self, retry_after: int) -> None:
+        super().__init__(f"the master asked to retry after {retry_after} seconds")
+        self.retry_after = retry_after
+
+
+class Spool:
+    """
+    Spool holds the batches the master couldn't take yet, in the order they were made.
+
+    While the master is unreachable the shipper spools batches to files in dirpath and keeps
+    reading logs, and ships the spooled batches in order once the master is back.  The spool holds
+    at most max_bytes of batches; a max_bytes of zero disables spooling to disk.  If dirpath isn't
+    writable, batches are held in memory instead.
+    """
+
+    def __init__(self, dirpath: str, max_bytes: int) -> None:
+        self.dirpath = dirpath
+        self.max_bytes = max_bytes
+        self.size = 0
+        self.count = 0
+        # Each entry is the path of a spooled batch, or the batch itself, and the batch's size.
+        self.entries: Deque[Tuple[Union[str, bytes], int]] = collections.deque()
+        self.warned = False
+
+    def __len__(self) -> int:
+        return len(self.entries)
+
+    def full(self) -> bool:
+        return self.size >= self.max_bytes
+
+    def push(self, batch: bytes) -> None:
+        entry: Union[str, bytes] = batch
+        if self.max_bytes > 0:
+            path = os.path.join(self.dirpath, f"{self.count:020d}.json.gz")
+            try:
+                os.makedirs(self.dirpath, exist_ok=True)
+                with open(path, "wb") as f:
+                    f.write(batch)
+                entry = path
+            except OSError:
+                if not self.warned:
+                    logging.warning(
+                        f"failed to spool logs to {self.dirpath}, holding them in memory",
+                        exc_info=True,
+                    )
+                    self.warned = True
+        self.count += 1
+        self.size += len(batch)
+        self.entries.append((entry, len(batch)))
+
+    def peek(self) -> bytes:
+        entry, _ = self.entries[0]
+        if isinstance(entry, bytes):
+            return entry
+        with open(entry, "rb") as f:
+            return f.read()
+
+    def pop(self) -> None:
+        entry, size = self.entries.popleft()
+        self.size -= size
+        if isinstance(entry, str):
+            try:
+                os.remove(entry)
+            except OSError:
+                pass
+
+
 def override_verify_name(ctx: ssl.SSLContext, verify_name: str) -> ssl.SSLContext:
     class VerifyNameOverride:
         def __getattr__(self, name: str, default: Any = None) -> Any:
@@ -239,6 +439,10 @@ class Shipper(threading.Thread):
     """
     Shipper reads structured logs from logq and ships them to the determined-master.
 
+    Logs are shipped in gzipped batches with a stream ID and sequence number, so the master can
+    acknowledge batches and ignore retries of batches it already has.  Each acknowledgement grants
+    credit for the size of the next batch.  Batches the master can't take are spooled until it can.
+
     It will send a message on doneq when it finishes.
     """
 
@@ -251,10 +455,22 @@ class Shipper(threading.Thread):
         logq: queue.Queue,
         doneq: queue.Queue,
         daemon: bool,
+        limiter: Optional[LogLimiter] = None,
+        spool: Optional[Spool] = None,
     ) -> None:
         super().__init__(daemon=daemon)
         self.logq = logq
         self.doneq = doneq
+        self.limiter = limiter or LogLimiter()
+        self.spool = spool if spool is not None else Spool(default_spool_dir(), SPOOL_MAX_BYTES)
+
+        self.stream_id = os.urandom(16).hex()
+        self.seq = 0
+        self.credit = LOG_BATCH_MAX_SIZE
+        # When to retry shipping after a failure, and since when shipping has been failing.
+        self.retry_at = 0.0
+        self.failures = 0
+        self.failing_since: Optional[float] = None
 
         # TODO(rb): Switch to DET_USER_TOKEN when the user token passed into a container isn't
         # limited to expire in 7 days, and then set `Authorization: Bearer $token` here instead.
@@ -272,7 +488,7 @@ class Shipper(threading.Thread):
                 logging.error("failed to read DET_MASTER_CERT_FILE ({cert_file})", exc_info=True)
 
         self.base_url = master_url.rstrip("/")
-        self.logs_url = f"{self.base_url}/api/v1/task/logs"
+        self.logs_url = f"{self.base_url}{LOGS_PATH}"
 
         self.context = None
         if master_url.startswith("https://"):
@@ -307,7 +523,7 @@ class Shipper(threading.Thread):
             logs: List[Dict[str, Any]] = []
             deadline = time.time() + SHIPPER_FLUSH_INTERVAL
             # Pop logs until both collectors close, or we fill up a batch, or we hit the deadline.
-            while eofs < 2 and len(logs) < LOG_BATCH_MAX_SIZE:
+            while eofs < 2 and len(logs) < self.credit:
                 now = time.time()
                 timeout = deadline - now
                 if timeout <= 0:
@@ -324,51 +540,120 @@ class Shipper(threading.Thread):
                     eofs += 1
                     continue
 
-                logs.append(log)
+                logs.extend(self.limiter.add(log))
 
-            if not logs:
-                continue
+            logs.extend(self.limiter.flush())
 
-            data = json.dumps({"logs": logs}).encode("utf8")
+            if logs:
+                self.send(self.encode(logs))
+            else:
+                self.drain()
 
-            # Try to ship for about ten minutes.
-            backoffs = [0, 1, 5, 10, 15, 15, 15, 15, 15, 15, 15, 60, 60, 60, 60, 60, 60, 60, 60, 60]
+        # Both collectors are done; ship whatever is still spooled.
+        self.drain_until(lambda: not self.spool)
 
-            self.ship(data, backoffs)
+    def encode(self, logs: List[Dict[str, Any]]) -> bytes:
+        self.seq += 1
+        batch = {"stream_id": self.stream_id, "seq": self.seq, "logs": logs}
+        return gzip.compress(json.dumps(batch).encode("utf8"))
 
-    def ship(self, data: bytes, backoffs: List[int]) -> None:
-        for delay in backoffs:
-            time.sleep(delay)
+    def send(self, batch: bytes) -> None:
+        """
+        Ship a batch, or spool it behind earlier batches if the master can't take it now.
+        """
+        if not self.spool and time.time() >= self.retry_at:
             try:
-                req = urllib.request.Request(self.logs_url, data, self.headers, method="POST")
-                try:
-                    with urllib.request.urlopen(req, context=self.context) as resp:
-                        respbody = resp.read()
-                except urllib.error.URLError as e:
-                    # urllib stacktraces are awful, so see if we can interpret what happened.
-                    # Note that we've already connected successfully to the master so failures here
-                    # are likely related to master crashing or the network breaking or something to
-                    # that effect.
-                    if isinstance(e, urllib.error.HTTPError):
-                        raise RuntimeError(
-                            f"POST logs returned status code: {e.code} and reason: {e.reason}, "
-                            "is the master healthy?"
-                        ) from None
-                    elif isinstance(e.reason, ConnectionRefusedError):
-                        raise RuntimeError(
-                            f"The connection to {self.master_url} was refused, is master down?"
-                        )
-                    raise
-
-                if resp.getcode() != 200:
-                    raise RuntimeError(
-                        f"ship logs failed: status code {resp.get_code()}, body:\n---\n"
-                        + respbody.decode("utf8")
-                    )
+                self.post(batch)
+                return
+            except Exception as e:
+                self.failed(e)
+
+        self.spool.push(batch)
+        self.drain()
+        if self.spool.full():
+            # Stop reading logs until the master takes some of the spool.
+            self.drain_until(lambda: not self.spool.full())
 
-                # Shipped successfully
+    def drain(self) -> None:
+        """
+        Ship spooled batches in order, until the spool is empty or the master can't take one.
+        """
+        while self.spool and time.time() >= self.retry_at:
+            try:
+                self.post(self.spool.peek())
+            except Exception as e:
+                self.failed(e)
                 return
+            self.spool.pop()
+
+    def drain_until(self, done: Callable[[], bool]) -> None:
+        while not done():
+            time.sleep(max(0.0, self.retry_at - time.time()))
+            self.drain()
+            if (
+                not done()
+                and self.failing_since is not None
+                and time.time() - self.failing_since > SHIPPER_GIVE_UP_SECONDS
+            ):
+                raise RuntimeError("failed to connect to master for too long, giving up")
+
+    def failed(self, e: Exception) -> None:
+        now = time.time()
+        if isinstance(e, MasterBusy):
+            # The master is applying backpressure, which isn't a failure to reach it.
+            self.retry_at = now + e.retry_after
+            return
+        logging.error("failed to ship logs to master", exc_info=e)
+        if self.failing_since is None:
+            self.failing_since = now
+        self.retry_at = now + SHIPPER_RETRY_BACKOFFS[
+            min(self.failures, len(SHIPPER_RETRY_BACKOFFS) - 1)
+        ]
+        self.failures += 1
+
+    def post(self, batch: bytes) -> None:
+        """
+        Make one attempt at shipping a batch, and take the credit the master grants for the next.
+        """
+        headers = {
+            **self.headers,
+            "Content-Type": "application/json",
+            "Content-Encoding": "gzip",
+        }
+        req = urllib.request.Request(self.logs_url, batch, headers, method="POST")
+        try:
+            with urllib.request.urlopen(
+                req, context=self.context, timeout=SHIPPER_REQUEST_TIMEOUT
+            ) as resp:
+                respbody = resp.read()
+        except urllib.error.URLError as e:
+            # urllib stacktraces are awful, so see if we can interpret what happened.  Note that
+            # we've already connected successfully to the master so failures here are likely
+            # related to master crashing or the network breaking or something to that effect.
+            if isinstance(e, urllib.error.HTTPError):
+                if e.code == 429:
+                    raise MasterBusy(int(e.headers.get("Retry-After") or 1)) from None
+                raise RuntimeError(
+                    f"POST logs returned status code: {e.code} and reason: {e.reason}, "
+                    "is the master healthy?"
+                ) from None
+            elif isinstance(e.reason, ConnectionRefusedError):
+                raise RuntimeError(
+                    f"The connection to {self.master_url} was refused, is master down?"
+                ) from None
+            raise
+
+        ack = json.loads(respbody)
+        self.credit = max(1, int(ack["credit"]))
+        self.failures = 0
+        self.failing_since = None
 
+    def ship(self, batch: bytes, backoffs: List[int]) -> None:
+        for delay in backoffs:
+            time.sleep(delay)
+            try:
+                self.post(batch)
+                return
             except Exception:
                 logging.error("failed to ship logs to master", exc_info=True)
 
@@ -390,17 +675,15 @@ class Shipper(threading.Thread):
                 {
                     "timestamp": now,
                     "log": line,
-                    "level": "ERROR",
+                    "level": "LOG_LEVEL_ERROR",
                     "stdtype": "stderr",
                     **metadata,
                 }
             )
 
-        data = json.dumps({"logs": logs}).encode("utf8")
-
         # Try to ship for about 30 seconds.
         backoffs = [0, 1, 5, 10, 15]
-        self.ship(data, backoffs)
+        self.ship(self.encode(logs), backoffs)
 
     def assert_master_is_reachable(self):
         """
@@ -479,6 +762,10 @@ class Shipper(threading.Thread):
             return False
 
 
+def default_spool_dir() -> str:
+    return os.path.join(tempfile.gettempdir(), f"det-log-spool-{os.getpid()}")
+
+
 class Waiter(threading.Thread):
     """
     Waiter calls p.wait() on a process, that's it.
@@ -506,6 +793,8 @@ def main(
     emit_stdout_logs: bool,
     cmd: List[str],
     log_wait_time: int,
+    limiter: Optional[LogLimiter] = None,
+    spool: Optional[Spool] = None,
 ) -> int:
     logq: queue.Queue = queue.Queue()
     doneq: queue.Queue = queue.Queue()
@@ -525,7 +814,17 @@ def main(
     #
     # So as an easy workaround, we set daemon=True and just exit the process if it's not done on
     # time.
-    shipper = Shipper(master_url, token, cert_name, cert_file, logq, doneq, daemon=True)
+    shipper = Shipper(
+        master_url,
+        token,
+        cert_name,
+        cert_file,
+        logq,
+        doneq,
+        daemon=True,
+        limiter=limiter,
+        spool=spool,
+    )
     shipper_timed_out = False
 
     shipper.assert_master_is_reachable()
@@ -779,6 +1078,26 @@ if __name__ == "__main__":
 
         emit_stdout_logs = bool(os.environ.get("DET_SHIPPER_EMIT_STDOUT_LOGS"))
 
+        raw_rate_limit = os.environ.get("DET_LOG_RATE_LIMIT", "0")
+        raw_rate_limit_burst = os.environ.get("DET_LOG_RATE_LIMIT_BURST", "0")
+        try:
+            limiter = LogLimiter(
+                dedup=bool(os.environ.get("DET_LOG_DEDUP")),
+                lines_per_second=int(raw_rate_limit),
+                burst=int(raw_rate_limit_burst),
+            )
+        except Exception:
+            raise ValueError(
+                f"invalid DET_LOG_RATE_LIMIT or DET_LOG_RATE_LIMIT_BURST: '{raw_rate_limit}', "
+                f"'{raw_rate_limit_burst}'"
+            ) from None
+
+        raw_spool_max_bytes = os.environ.get("DET_LOG_SPOOL_MAX_BYTES", str(SPOOL_MAX_BYTES))
+        try:
+            spool = Spool(default_spool_dir(), int(raw_spool_max_bytes))
+        except Exception:
+            raise ValueError(f"invalid DET_LOG_SPOOL_MAX_BYTES: '{raw_spool_max_bytes}'") from None
+
         metadata["source"] = "task"
 
         exit_code = main(
@@ -790,6 +1109,8 @@ if __name__ == "__main__":
             emit_stdout_logs,
             cmd=sys.argv[1:],
             log_wait_time=log_wait_time,
+            limiter=limiter,
+            spool=spool,
         )
     except Exception:
         logging.error("ship_logs.py crashed!", exc_info=True)
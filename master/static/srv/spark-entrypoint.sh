@@ -0,0 +1,20 @@
+#!/usr/bin/env bash
+
+## spark job entry point
+
+source /run/determined/task-setup.sh
+
+set -e
+
+STARTUP_HOOK="startup-hook.sh"
+
+# spark-submit must be installed in the image, in addition to the "determined*.whl" that contains
+# the "determined/exec/spark_submit.py" script that runs it.
+"$DET_PYTHON_EXECUTABLE" -m determined.exec.prep_container --download_context_directory
+
+set -x
+test -f "${TCD_STARTUP_HOOK}" && source "${TCD_STARTUP_HOOK}"
+test -f "${STARTUP_HOOK}" && source "${STARTUP_HOOK}"
+set +x
+
+exec "$DET_PYTHON_EXECUTABLE" -m determined.exec.spark_submit "$@"
@@ -51,6 +51,11 @@ if [ "$HOME" = "/" ]; then
         set -o pipefail
         getent passwd "$UID" | cut -d: -f6
     )" || HOME="$PWD"
+    # Users mapped to agent users from LDAP or the master config often have a home directory that
+    # doesn't exist in the image, so use the working directory, which they own, instead.
+    if [ ! -d "$HOME" ] || [ ! -w "$HOME" ]; then
+        HOME="$PWD"
+    fi
     export HOME
 fi
 
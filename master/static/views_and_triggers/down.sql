@@ -8,16 +8,23 @@ DROP VIEW IF EXISTS validations;
 DROP VIEW IF EXISTS validation_metrics;
 
 DROP FUNCTION IF EXISTS abort_checkpoint_delete CASCADE;
+DROP FUNCTION IF EXISTS add_project_experiment_summary CASCADE;
 DROP FUNCTION IF EXISTS autoupdate_exp_best_trial_metrics CASCADE;
 DROP FUNCTION IF EXISTS autoupdate_exp_best_trial_metrics_on_delete CASCADE;
 DROP FUNCTION IF EXISTS autoupdate_user_image_deleted CASCADE;
 DROP FUNCTION IF EXISTS autoupdate_user_image_modified CASCADE;
+DROP FUNCTION IF EXISTS check_experiment_name_unique CASCADE;
+DROP FUNCTION IF EXISTS experiment_summaries_on_experiment CASCADE;
+DROP FUNCTION IF EXISTS experiment_summaries_on_run CASCADE;
 DROP FUNCTION IF EXISTS get_raw_metric CASCADE;
 DROP FUNCTION IF EXISTS get_signed_metric CASCADE;
 DROP FUNCTION IF EXISTS page_info CASCADE;
 DROP FUNCTION IF EXISTS proto_time CASCADE;
+DROP FUNCTION IF EXISTS record_experiment_state_change CASCADE;
+DROP FUNCTION IF EXISTS remove_project_experiment_summary CASCADE;
 DROP FUNCTION IF EXISTS retention_timestamp CASCADE;
 DROP FUNCTION IF EXISTS set_modified_time CASCADE;
+DROP FUNCTION IF EXISTS set_pin_position CASCADE;
 DROP FUNCTION IF EXISTS stream_model_change CASCADE;
 DROP FUNCTION IF EXISTS stream_model_notify CASCADE;
 DROP FUNCTION IF EXISTS stream_model_seq_modify CASCADE;
@@ -0,0 +1,149 @@
+-- Counts are adjusted by deltas rather than recounted, so concurrent transactions serialize on the
+-- summary row instead of each counting rows the other can't see yet.
+CREATE OR REPLACE FUNCTION add_project_experiment_summary(
+    project integer, experiments integer, active integer, started timestamptz
+) RETURNS void AS $$
+BEGIN
+    INSERT INTO project_experiment_summaries AS s (
+        project_id, num_experiments, num_active_experiments, last_experiment_started_at
+    ) VALUES (project, experiments, active, started)
+    ON CONFLICT (project_id) DO UPDATE SET
+        num_experiments = s.num_experiments + experiments,
+        num_active_experiments = s.num_active_experiments + active,
+        last_experiment_started_at = GREATEST(s.last_experiment_started_at, started);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION remove_project_experiment_summary(
+    project integer, active integer, started timestamptz
+) RETURNS void AS $$
+BEGIN
+    UPDATE project_experiment_summaries SET
+        num_experiments = num_experiments - 1,
+        num_active_experiments = num_active_experiments - active,
+        last_experiment_started_at = CASE
+            WHEN last_experiment_started_at > started THEN last_experiment_started_at
+            ELSE (SELECT MAX(start_time) FROM experiments WHERE project_id = project)
+        END
+    WHERE project_id = project;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION experiment_summaries_on_experiment() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO experiment_summaries (experiment_id) VALUES (NEW.id);
+        PERFORM add_project_experiment_summary(
+            NEW.project_id, 1, (NEW.state = 'ACTIVE')::integer, NEW.start_time
+        );
+    ELSIF TG_OP = 'DELETE' THEN
+        PERFORM remove_project_experiment_summary(
+            OLD.project_id, (OLD.state = 'ACTIVE')::integer, OLD.start_time
+        );
+    ELSE
+        IF NEW.project_id <> OLD.project_id THEN
+            PERFORM remove_project_experiment_summary(
+                OLD.project_id, (OLD.state = 'ACTIVE')::integer, OLD.start_time
+            );
+            PERFORM add_project_experiment_summary(
+                NEW.project_id, 1, (NEW.state = 'ACTIVE')::integer, NEW.start_time
+            );
+        ELSIF (NEW.state = 'ACTIVE') IS DISTINCT FROM (OLD.state = 'ACTIVE') THEN
+            PERFORM add_project_experiment_summary(
+                NEW.project_id, 0,
+                (NEW.state = 'ACTIVE')::integer - (OLD.state = 'ACTIVE')::integer,
+                NEW.start_time
+            );
+        END IF;
+        IF NEW.best_trial_id IS DISTINCT FROM OLD.best_trial_id THEN
+            UPDATE experiment_summaries SET best_trial_searcher_metric = (
+                SELECT searcher_metric_value FROM runs WHERE id = NEW.best_trial_id
+            ) WHERE experiment_id = NEW.id;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER experiment_summaries_on_experiment
+    AFTER INSERT OR DELETE OR UPDATE OF project_id, state, best_trial_id ON experiments
+    FOR EACH ROW EXECUTE PROCEDURE experiment_summaries_on_experiment();
+
+-- Summaries are only updated, never inserted, for runs, since a run's experiment may already have
+-- been deleted when the run is deleted with it.
+CREATE OR REPLACE FUNCTION experiment_summaries_on_run() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE experiment_summaries SET num_trials = num_trials + 1
+        WHERE experiment_id = NEW.experiment_id;
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE experiment_summaries SET num_trials = num_trials - 1
+        WHERE experiment_id = OLD.experiment_id;
+    ELSIF NEW.searcher_metric_value IS DISTINCT FROM OLD.searcher_metric_value THEN
+        UPDATE experiment_summaries AS s SET best_trial_searcher_metric = NEW.searcher_metric_value
+        FROM experiments AS e
+        WHERE e.id = NEW.experiment_id AND e.best_trial_id = NEW.id AND s.experiment_id = e.id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER experiment_summaries_on_run
+    AFTER INSERT OR DELETE OR UPDATE OF searcher_metric_value ON runs
+    FOR EACH ROW EXECUTE PROCEDURE experiment_summaries_on_run();
+
+CREATE OR REPLACE FUNCTION check_experiment_name_unique() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.project_id = OLD.project_id
+        AND NEW.config->>'name' IS NOT DISTINCT FROM OLD.config->>'name' THEN
+        RETURN NEW;
+    END IF;
+    -- Locking the project's naming row serializes experiments being named in the project, so two
+    -- transactions can't both take the same name.
+    PERFORM 1 FROM project_experiment_naming
+    WHERE project_id = NEW.project_id AND unique_names
+    FOR UPDATE;
+    IF FOUND AND EXISTS (
+        SELECT 1 FROM experiments AS e
+        WHERE e.project_id = NEW.project_id
+            AND e.config->>'name' = NEW.config->>'name'
+            AND e.id <> NEW.id
+            -- Unmanaged experiments are upserted by their external ID.
+            AND (
+                NEW.external_experiment_id IS NULL
+                OR e.external_experiment_id IS DISTINCT FROM NEW.external_experiment_id
+            )
+    ) THEN
+        RAISE EXCEPTION 'experiment name "%" is already used in project %',
+            NEW.config->>'name', NEW.project_id
+            USING ERRCODE = 'unique_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER check_experiment_name_unique
+    BEFORE INSERT OR UPDATE OF config, project_id ON experiments
+    FOR EACH ROW EXECUTE PROCEDURE check_experiment_name_unique();
+
+CREATE OR REPLACE FUNCTION record_experiment_state_change() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO experiment_state_history (experiment_id, from_state, to_state, reason)
+    VALUES (
+        NEW.id,
+        CASE WHEN TG_OP = 'UPDATE' THEN OLD.state END,
+        NEW.state,
+        COALESCE(current_setting('determined.experiment_state_reason', true), '')
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_experiment_state_insert
+    AFTER INSERT ON experiments
+    FOR EACH ROW EXECUTE PROCEDURE record_experiment_state_change();
+
+CREATE TRIGGER record_experiment_state_update
+    AFTER UPDATE OF state ON experiments
+    FOR EACH ROW WHEN (OLD.state IS DISTINCT FROM NEW.state)
+    EXECUTE PROCEDURE record_experiment_state_change();
@@ -0,0 +1,19 @@
+-- New pins go after the user's existing pins unless they are given a position.
+CREATE OR REPLACE FUNCTION set_pin_position() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.position IS NULL THEN
+        EXECUTE format(
+            'SELECT COALESCE(MAX(position), 0) + 1 FROM %I WHERE user_id = $1', TG_TABLE_NAME
+        ) INTO NEW.position USING NEW.user_id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_workspace_pin_position
+    BEFORE INSERT ON workspace_pins
+    FOR EACH ROW EXECUTE PROCEDURE set_pin_position();
+
+CREATE TRIGGER set_project_pin_position
+    BEFORE INSERT ON project_pins
+    FOR EACH ROW EXECUTE PROCEDURE set_pin_position();
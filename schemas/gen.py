@@ -268,11 +268,13 @@ def get_defaulted_type(schema: Schema, tag: str, type: str) -> Tuple[str, str, b
     eventuallyRequired = required or tag in schema.schema.get("eventuallyRequired", [])
 
     KNOWN_MAP_OR_SLICE_ALIAS_TYPES = [
+        "AgentConstraintsConfigV0",
         "BindMountsConfigV0",
         "DevicesConfigV0",
         "HyperparametersV0",
         "LabelsV0",
         "LogPoliciesConfigV0",
+        "MetricMetadataConfigV0",
         # Technically Name is a struct containing a string pointer, which exists only to
         # handle the semantics of runtime defaultables.  But it has the same mechanics as a map or
         # slice alias, so we include it here.
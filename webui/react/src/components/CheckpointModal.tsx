@@ -69,6 +69,12 @@ const getStorageLocation = (
         }${containerPath}`;
       }
       break;
+    case CheckpointStorageType.SFTP:
+      location = `sftp://${config.checkpointStorage.host || ''}${storagePath || ''}`;
+      break;
+    case CheckpointStorageType.HDFS:
+      location = `hdfs://${storagePath || ''}`;
+      break;
     case CheckpointStorageType.AZURE:
       // type from api doesn't have azure-specific props
       break;
@@ -45,6 +45,11 @@ const TrialHeaderLeft: React.FC<Props> = ({ experiment, trial }: Props) => {
               />
             </Tooltip>
           ))}
+        {trial.stalled && (
+          <Tooltip content={trial.stallReason}>
+            <Badge backgroundColor={hex2hsl(labelColor)} text="Stalled" />
+          </Tooltip>
+        )}
       </div>
     </div>
   );
@@ -684,6 +684,8 @@ export const decodeV1TrialToTrialItem = (data: Sdk.Trialv1Trial): types.TrialIte
     logRetentionDays: data.logRetentionDays,
     metadata: data.metadata,
     searcherMetricsVal: data.searcherMetricValue,
+    stallReason: data.stallReason,
+    stalled: data.stalled,
     startTime: data.startTime as unknown as string,
     state: decodeExperimentState(data.state),
     summaryMetrics: data.summaryMetrics && decodeSummaryMetrics(data.summaryMetrics),
@@ -332,7 +332,9 @@ export const CheckpointStorageType = {
   AZURE: 'azure',
   DIRECTORY: 'directory',
   GCS: 'gcs',
+  HDFS: 'hdfs',
   S3: 's3',
+  SFTP: 'sftp',
   SharedFS: 'shared_fs',
 } as const;
 
@@ -342,9 +344,11 @@ export const CheckpointStorage = t.intersection([
   t.partial({
     bucket: t.string,
     containerPath: t.string,
+    host: t.string,
     hostPath: t.string,
     storagePath: t.string,
     type: valueof(CheckpointStorageType),
+    url: t.string,
   }),
   t.type({
     saveExperimentBest: t.number,
@@ -707,6 +711,8 @@ export interface TrialItem extends StartEndTimes {
   taskId?: string;
   metadata?: JsonObject;
   logPolicyMatched?: string;
+  stalled?: boolean;
+  stallReason?: string;
 }
 
 export interface TrialDetails extends TrialItem {